@@ -0,0 +1,70 @@
+package gopdf
+
+import (
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// pageAnnotation はページに配置される注釈の内部表現
+// 各注釈はWriteTo時に独立した間接オブジェクトとして出力される
+type pageAnnotation interface {
+	// annotationDict は注釈辞書を生成する（pageRefは注釈が属するページへの参照）
+	annotationDict(pageRef *core.Reference) core.Dictionary
+}
+
+// formFieldAnnotation はAcroFormのフィールドを兼ねる注釈（ウィジェット）
+type formFieldAnnotation interface {
+	pageAnnotation
+	// hasCalculation は計算アクションを持つか（AcroFormの/COに登録する必要があるか）を返す
+	hasCalculation() bool
+}
+
+// formFieldRef は出力済みフォームフィールドへの参照
+type formFieldRef struct {
+	ref         *core.Reference
+	calculation bool
+	signature   bool
+}
+
+// writeAnnotations はページの注釈を出力し、/Annots配列とフォームフィールドの参照を返す
+// 構造ツリーから参照される注釈（taggedがnilでない場合）は、予約済みの番号に/StructParentを付けて出力する
+func writeAnnotations(w *writer.Writer, annotations []pageAnnotation, pageRef *core.Reference, tagged *taggedPDF) (core.Array, []formFieldRef, error) {
+	annots := make(core.Array, 0, len(annotations))
+	var fieldRefs []formFieldRef
+
+	for _, annot := range annotations {
+		dict := annot.annotationDict(pageRef)
+		var ref *core.Reference
+		if tagged != nil && tagged.annotRefs[annot] != nil {
+			ref = tagged.annotRefs[annot]
+			dict[core.Name("StructParent")] = core.Integer(tagged.annotParents[annot])
+			if err := w.WriteObject(ref.ObjectNumber, dict); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			num, err := w.AddObject(dict)
+			if err != nil {
+				return nil, nil, err
+			}
+			ref = &core.Reference{ObjectNumber: num, GenerationNumber: 0}
+		}
+		annots = append(annots, ref)
+
+		if field, ok := annot.(formFieldAnnotation); ok {
+			_, isSignature := annot.(*signatureFieldAnnotation)
+			fieldRefs = append(fieldRefs, formFieldRef{ref: ref, calculation: field.hasCalculation(), signature: isSignature})
+		}
+	}
+
+	return annots, fieldRefs, nil
+}
+
+// rectArray は左下座標と幅・高さからPDFの矩形配列 [llx lly urx ury] を生成する
+func rectArray(x, y, width, height float64) core.Array {
+	return core.Array{
+		core.Real(x),
+		core.Real(y),
+		core.Real(x + width),
+		core.Real(y + height),
+	}
+}
@@ -0,0 +1,176 @@
+package gopdf
+
+import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// AFRelationship は埋め込みファイルと文書の関係（PDF/A-3 と PDF 2.0 の /AFRelationship）
+type AFRelationship string
+
+const (
+	// AFRelationshipSource は文書の元になったファイル（例: 変換前の原稿）
+	AFRelationshipSource AFRelationship = "Source"
+	// AFRelationshipData は文書の内容（表やグラフ）のもとになったデータ
+	AFRelationshipData AFRelationship = "Data"
+	// AFRelationshipAlternative は文書と同じ内容の別表現（例: 電子請求書のXML）
+	AFRelationshipAlternative AFRelationship = "Alternative"
+	// AFRelationshipSupplement は文書を補足するファイル
+	AFRelationshipSupplement AFRelationship = "Supplement"
+	// AFRelationshipUnspecified は関係が不明、または上記に当てはまらない
+	AFRelationshipUnspecified AFRelationship = "Unspecified"
+)
+
+// FileAttachment はPDFに埋め込むファイル
+type FileAttachment struct {
+	Name         string         // ファイル名（名前ツリーのキー。文書内で一意）
+	Data         []byte         // ファイルの内容
+	MIMEType     string         // MIMEタイプ（例: "text/xml"）。PDF/A-3では必須
+	Description  string         // 説明（/Desc）
+	ModDate      time.Time      // 更新日時（ゼロ値の場合はAttachFileを呼んだ時刻）
+	Relationship AFRelationship // 文書との関係。空でなければカタログの/AFに関連ファイルとして登録する
+}
+
+// AttachFile はファイルを文書に埋め込む
+// 埋め込んだファイルはカタログの/Names/EmbeddedFilesに登録され、ビューアの添付ファイル一覧に表示される
+// 同じ名前で再度追加した場合は上書きされる
+func (d *Document) AttachFile(a FileAttachment) error {
+	a, err := normalizeAttachment(a)
+	if err != nil {
+		return err
+	}
+	for i, existing := range d.attachments {
+		if existing.Name == a.Name {
+			d.attachments[i] = a
+			return nil
+		}
+	}
+	d.attachments = append(d.attachments, a)
+	return nil
+}
+
+// normalizeAttachment は埋め込むファイルを検証し、更新日時を補ってDataを複製したものを返す
+func normalizeAttachment(a FileAttachment) (FileAttachment, error) {
+	if a.Name == "" {
+		return a, fmt.Errorf("attachment name is required")
+	}
+	switch a.Relationship {
+	case "", AFRelationshipSource, AFRelationshipData, AFRelationshipAlternative,
+		AFRelationshipSupplement, AFRelationshipUnspecified:
+	default:
+		return a, fmt.Errorf("unsupported AFRelationship: %q", a.Relationship)
+	}
+	if a.ModDate.IsZero() {
+		a.ModDate = time.Now()
+	}
+	a.Data = append([]byte(nil), a.Data...)
+	return a, nil
+}
+
+// Attachments は埋め込むファイルの一覧を返す
+func (d *Document) Attachments() []FileAttachment {
+	return append([]FileAttachment(nil), d.attachments...)
+}
+
+// writeAttachments は埋め込みファイルとファイル指定辞書を出力し、
+// カタログの/Names/EmbeddedFilesと/AF（関連ファイル）を設定する
+func (d *Document) writeAttachments(w *writer.Writer, catalog core.Dictionary) error {
+	if len(d.attachments) == 0 {
+		return nil
+	}
+
+	// 名前ツリーのキーは昇順である必要がある
+	attachments := make([]FileAttachment, len(d.attachments))
+	copy(attachments, d.attachments)
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].Name < attachments[j].Name
+	})
+
+	names := make(core.Array, 0, len(attachments)*2)
+	var associated core.Array
+	for _, a := range attachments {
+		if a.Relationship == "" && d.conformance == PDFA3B {
+			a.Relationship = AFRelationshipUnspecified
+		}
+		specRef, err := writeFileSpec(w, a)
+		if err != nil {
+			return err
+		}
+		names = append(names, textString(a.Name), specRef)
+		if a.Relationship != "" {
+			associated = append(associated, specRef)
+		}
+	}
+
+	namesDictionary(catalog)[core.Name("EmbeddedFiles")] = core.Dictionary{
+		core.Name("Names"): names,
+	}
+	if len(associated) > 0 {
+		catalog[core.Name("AF")] = associated
+	}
+	return nil
+}
+
+// writeFileSpec は埋め込みファイルストリームと、それを参照するファイル指定辞書を出力する
+func writeFileSpec(w *writer.Writer, a FileAttachment) (*core.Reference, error) {
+	fileRef, err := writeEmbeddedFile(w, a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed %s: %w", a.Name, err)
+	}
+
+	spec := core.Dictionary{
+		core.Name("Type"): core.Name("Filespec"),
+		core.Name("F"):    textString(a.Name),
+		core.Name("UF"):   textString(a.Name),
+		core.Name("EF"): core.Dictionary{
+			core.Name("F"):  fileRef,
+			core.Name("UF"): fileRef,
+		},
+	}
+	if a.Description != "" {
+		spec[core.Name("Desc")] = textString(a.Description)
+	}
+	if a.Relationship != "" {
+		spec[core.Name("AFRelationship")] = core.Name(a.Relationship)
+	}
+
+	specNum, err := w.AddObject(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Reference{ObjectNumber: specNum}, nil
+}
+
+// writeEmbeddedFile は埋め込みファイルストリームを出力する
+func writeEmbeddedFile(w *writer.Writer, a FileAttachment) (*core.Reference, error) {
+	compressed, err := compressWithZlib(a.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	checksum := md5.Sum(a.Data)
+	dict := core.Dictionary{
+		core.Name("Type"):   core.Name("EmbeddedFile"),
+		core.Name("Filter"): core.Name("FlateDecode"),
+		core.Name("Length"): core.Integer(len(compressed)),
+		core.Name("Params"): core.Dictionary{
+			core.Name("Size"):     core.Integer(len(a.Data)),
+			core.Name("ModDate"):  core.String(formatPDFDate(a.ModDate)),
+			core.Name("CheckSum"): core.String(checksum[:]),
+		},
+	}
+	if a.MIMEType != "" {
+		dict[core.Name("Subtype")] = core.Name(a.MIMEType)
+	}
+
+	num, err := w.AddObject(&core.Stream{Dict: dict, Data: compressed})
+	if err != nil {
+		return nil, err
+	}
+	return &core.Reference{ObjectNumber: num}, nil
+}
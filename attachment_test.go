@@ -0,0 +1,227 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// readAttachments は出力したPDFの/Names/EmbeddedFilesをファイル名 -> ファイル指定辞書で返す
+func readAttachments(t *testing.T, pdf []byte) (*PDFReader, core.Dictionary, map[string]core.Dictionary) {
+	t.Helper()
+	r, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, ok := r.r.Resolve(catalog[core.Name("Names")]).(core.Dictionary)
+	if !ok {
+		t.Fatal("catalog has no /Names")
+	}
+	specs := map[string]core.Dictionary{}
+	err = r.r.WalkNameTree(names[core.Name("EmbeddedFiles")], func(key string, value core.Object) error {
+		spec, ok := r.r.Resolve(value).(core.Dictionary)
+		if !ok {
+			return errors.New("file specification is not a dictionary")
+		}
+		specs[key] = spec
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, catalog, specs
+}
+
+func TestDocumentAttachFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		attachment FileAttachment
+		wantErr    bool
+	}{
+		{"basic", FileAttachment{Name: "data.csv", Data: []byte("a,b\n")}, false},
+		{"with relationship", FileAttachment{Name: "data.csv", Relationship: AFRelationshipSource}, false},
+		{"missing name", FileAttachment{Data: []byte("x")}, true},
+		{"unknown relationship", FileAttachment{Name: "data.csv", Relationship: "Other"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			err := doc.AttachFile(tt.attachment)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AttachFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			attachments := doc.Attachments()
+			if len(attachments) != 1 || attachments[0].Name != tt.attachment.Name {
+				t.Fatalf("Attachments() = %+v", attachments)
+			}
+			if attachments[0].ModDate.IsZero() {
+				t.Error("ModDate was not set")
+			}
+		})
+	}
+
+	t.Run("same name replaces", func(t *testing.T) {
+		doc := New()
+		doc.AttachFile(FileAttachment{Name: "a.txt", Data: []byte("old")})
+		doc.AttachFile(FileAttachment{Name: "a.txt", Data: []byte("new")})
+		attachments := doc.Attachments()
+		if len(attachments) != 1 || string(attachments[0].Data) != "new" {
+			t.Errorf("Attachments() = %+v, want one attachment with new data", attachments)
+		}
+	})
+}
+
+func TestDocumentWriteTo_Attachments(t *testing.T) {
+	modDate := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	doc.AddJavaScript("init", "var x = 1;")
+	if err := doc.AttachFile(FileAttachment{
+		Name:         "report.csv",
+		Data:         []byte("month,total\n1,100\n"),
+		MIMEType:     "text/csv",
+		Description:  "月次集計",
+		ModDate:      modDate,
+		Relationship: AFRelationshipData,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.AttachFile(FileAttachment{Name: "notes.txt", Data: []byte("memo"), ModDate: modDate}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	r, catalog, specs := readAttachments(t, buf.Bytes())
+	if len(specs) != 2 {
+		t.Fatalf("embedded files = %d, want 2", len(specs))
+	}
+
+	// JavaScriptの名前ツリーと共存すること
+	names := r.r.Resolve(catalog[core.Name("Names")]).(core.Dictionary)
+	if _, ok := names[core.Name("JavaScript")]; !ok {
+		t.Error("/Names/JavaScript was dropped")
+	}
+
+	spec := specs["report.csv"]
+	if got := rawTextString(spec[core.Name("UF")]); got != "report.csv" {
+		t.Errorf("/UF = %q, want report.csv", got)
+	}
+	if got := rawTextString(spec[core.Name("Desc")]); got != "月次集計" {
+		t.Errorf("/Desc = %q, want 月次集計", got)
+	}
+	if got := spec[core.Name("AFRelationship")]; got != core.Name("Data") {
+		t.Errorf("/AFRelationship = %v, want Data", got)
+	}
+
+	ef := r.r.Resolve(spec[core.Name("EF")]).(core.Dictionary)
+	stream, ok := r.r.Resolve(ef[core.Name("F")]).(*core.Stream)
+	if !ok {
+		t.Fatal("/EF /F is not a stream")
+	}
+	if got := stream.Dict[core.Name("Subtype")]; got != core.Name("text/csv") {
+		t.Errorf("/Subtype = %v, want text/csv", got)
+	}
+	data, err := r.r.DecodeStream(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "month,total\n1,100\n" {
+		t.Errorf("embedded data = %q", data)
+	}
+	params := r.r.Resolve(stream.Dict[core.Name("Params")]).(core.Dictionary)
+	if got := params[core.Name("Size")]; got != core.Integer(len(data)) {
+		t.Errorf("/Params /Size = %v, want %d", got, len(data))
+	}
+	if got := rawTextString(params[core.Name("ModDate")]); got != formatPDFDate(modDate) {
+		t.Errorf("/Params /ModDate = %q, want %q", got, formatPDFDate(modDate))
+	}
+
+	// 関係を指定したファイルだけが関連ファイル（/AF）になる
+	af, ok := r.r.Resolve(catalog[core.Name("AF")]).(core.Array)
+	if !ok || len(af) != 1 {
+		t.Fatalf("catalog /AF = %v, want one entry", catalog[core.Name("AF")])
+	}
+	if _, ok := specs["notes.txt"][core.Name("AFRelationship")]; ok {
+		t.Error("notes.txt has /AFRelationship outside PDF/A-3")
+	}
+}
+
+func TestDocumentWriteTo_PDFA3BAttachment(t *testing.T) {
+	doc := New()
+	if err := doc.SetConformance(PDFA3B); err != nil {
+		t.Fatal(err)
+	}
+	doc.AddPage(PageSizeA4, Portrait)
+	if err := doc.AttachFile(FileAttachment{Name: "source.md", Data: []byte("# Doc"), MIMEType: "text/markdown"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	r, catalog, specs := readAttachments(t, buf.Bytes())
+	if got := specs["source.md"][core.Name("AFRelationship")]; got != core.Name("Unspecified") {
+		t.Errorf("/AFRelationship = %v, want Unspecified", got)
+	}
+	if af, ok := r.r.Resolve(catalog[core.Name("AF")]).(core.Array); !ok || len(af) != 1 {
+		t.Errorf("catalog /AF = %v, want one entry", catalog[core.Name("AF")])
+	}
+
+	packet, err := r.RawXMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(packet), "<pdfaid:part>3</pdfaid:part>") {
+		t.Error("XMP does not declare pdfaid:part 3")
+	}
+}
+
+func TestDocumentWriteTo_AttachmentViolations(t *testing.T) {
+	tests := []struct {
+		name        string
+		conformance Conformance
+		attachment  FileAttachment
+		want        string
+	}{
+		{"PDF/A-2b forbids attachments", PDFA2B, FileAttachment{Name: "a.xml", MIMEType: "text/xml"}, "not allowed in PDF/A-2"},
+		{"PDF/A-3b requires MIME type", PDFA3B, FileAttachment{Name: "a.bin"}, "no MIME type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.SetConformance(tt.conformance)
+			doc.AddPage(PageSizeA4, Portrait)
+			doc.AttachFile(tt.attachment)
+
+			err := doc.WriteTo(&bytes.Buffer{})
+			var confErr *ConformanceError
+			if !errors.As(err, &confErr) {
+				t.Fatalf("WriteTo() error = %v, want *ConformanceError", err)
+			}
+			if len(confErr.Violations) != 1 || !strings.Contains(confErr.Violations[0], tt.want) {
+				t.Errorf("Violations = %q, want one containing %q", confErr.Violations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// BookletOptions は中綴じの小冊子に面付けするときの設定
+type BookletOptions struct {
+	// SheetSize は用紙の大きさ（横長にして使う）
+	// ゼロ値の場合は、元の1ページ目を2つ横に並べた大きさ
+	SheetSize PageSize
+	// Margin は用紙の端と、2つのページの間の余白の半分（ポイント）
+	Margin float64
+	// RightToLeft は右綴じにする（縦書きの日本語の冊子など）
+	// 省略した場合は左綴じで、1ページ目が表紙の右側に来る
+	RightToLeft bool
+}
+
+// Booklet は中綴じの小冊子に面付けしたPDFを書き出す
+// 元のページを (n, 1)、(2, n-1)、(n-2, 3)、(4, n-3)、... の組にして、横長の用紙の左右に並べる
+// 出力の各ページは用紙の表と裏が交互に並ぶので、両面印刷（短辺綴じ）して重ねたまま半分に折ると冊子になる
+// ページ数が4の倍数でない場合は、最後に白紙のページを補う
+// 各ページは用紙の半分（余白の内側）に収まるよう縦横比を保って拡大縮小し、中央に置く。注釈は含まない
+// 設計書: docs/booklet_design.md
+func (r *PDFReader) Booklet(out io.Writer, opts BookletOptions) error {
+	pageCount := r.PageCount()
+	if pageCount == 0 {
+		return fmt.Errorf("PDF has no pages")
+	}
+	if opts.Margin < 0 {
+		return fmt.Errorf("invalid margin: %v", opts.Margin)
+	}
+
+	doc := New()
+	pages := make([]*ImportedPage, pageCount)
+	for i := range pages {
+		tpl, err := doc.ImportPage(r, i)
+		if err != nil {
+			return err
+		}
+		pages[i] = tpl
+	}
+
+	sheet := opts.SheetSize
+	if sheet.Width == 0 || sheet.Height == 0 {
+		sheet = PageSize{Width: 2 * pages[0].Width(), Height: pages[0].Height()}
+	}
+	// 用紙は横長にする
+	sheet = PageSize{Width: math.Max(sheet.Width, sheet.Height), Height: math.Min(sheet.Width, sheet.Height)}
+	slotW := sheet.Width/2 - 2*opts.Margin
+	slotH := sheet.Height - 2*opts.Margin
+	if slotW <= 0 || slotH <= 0 {
+		return fmt.Errorf("margin %v is too large for the sheet %vx%v", opts.Margin, sheet.Width, sheet.Height)
+	}
+
+	for _, side := range bookletOrder(pageCount) {
+		left, right := side[0], side[1]
+		if opts.RightToLeft {
+			left, right = right, left
+		}
+
+		page := doc.AddPage(sheet, Portrait)
+		for slot, pageNum := range []int{left, right} {
+			if pageNum >= pageCount {
+				continue // 補った白紙のページ
+			}
+			tpl := pages[pageNum]
+			if tpl.Width() <= 0 || tpl.Height() <= 0 {
+				continue
+			}
+			scale := math.Min(slotW/tpl.Width(), slotH/tpl.Height())
+			width, height := tpl.Width()*scale, tpl.Height()*scale
+			x := float64(slot)*sheet.Width/2 + opts.Margin + (slotW-width)/2
+			y := opts.Margin + (slotH-height)/2
+			if err := page.DrawImportedPage(tpl, x, y, width, height); err != nil {
+				return err
+			}
+		}
+	}
+
+	if info, err := r.r.GetInfo(); err == nil && len(info) > 0 {
+		doc.SetMetadata(parseInfoDict(info))
+	}
+	return doc.WriteTo(out)
+}
+
+// bookletOrder は中綴じの各面（用紙の表、裏の順）に左右に並べるページ番号（0-indexed、左綴じ）を返す
+// ページ数は4の倍数に切り上げ、pageCount以上の番号は白紙のページを表す
+func bookletOrder(pageCount int) [][2]int {
+	n := (pageCount + 3) / 4 * 4
+	sides := make([][2]int, 0, n/2)
+	for i := 0; i < n/2; i += 2 {
+		sides = append(sides,
+			[2]int{n - 1 - i, i},     // 表: 後ろのページが左、前のページが右
+			[2]int{i + 1, n - 2 - i}, // 裏: 前のページが左、後ろのページが右
+		)
+	}
+	return sides
+}
@@ -0,0 +1,170 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// bookletSourcePDF は "P1", "P2", ... と書いた100×150ポイントのページをpageCountページ持つPDFを作成する
+func bookletSourcePDF(t *testing.T, pageCount int) *PDFReader {
+	t.Helper()
+	kids := make([]string, pageCount)
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", ""}
+	for i := 0; i < pageCount; i++ {
+		pageNum, contentsNum := 3+2*i, 4+2*i
+		kids[i] = fmt.Sprintf("%d 0 R", pageNum)
+		data := fmt.Sprintf("BT /F1 12 Tf 10 10 Td (P%d) Tj ET", i+1)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", contentsNum),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 100 150] >>", strings.Join(kids, " "), pageCount)
+
+	reader, err := OpenReader(bytes.NewReader(buildRawPDF(objects)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+// bookletSides は面付けしたページの左右に描かれた元のページの文字列（白紙は空）を返す
+func bookletSides(t *testing.T, reader *PDFReader, pageNum int, sheetWidth float64) [2]string {
+	t.Helper()
+	page, err := reader.r.GetPage(pageNum)
+	if err != nil {
+		t.Fatalf("GetPage(%d) failed: %v", pageNum, err)
+	}
+	data, err := reader.r.GetPageContents(page)
+	if err != nil {
+		t.Fatalf("GetPageContents(%d) failed: %v", pageNum, err)
+	}
+	operations, err := content.NewStreamParser(data).ParseOperations()
+	if err != nil {
+		t.Fatalf("failed to parse contents: %v", err)
+	}
+	resources, _ := reader.r.Resolve(page[core.Name("Resources")]).(core.Dictionary)
+	xobjects, _ := reader.r.Resolve(resources[core.Name("XObject")]).(core.Dictionary)
+	label := regexp.MustCompile(`\((P\d+)\)`)
+
+	var sides [2]string
+	var x float64
+	for _, op := range operations {
+		switch op.Operator {
+		case "cm":
+			x = numberValue(op.Operands[4])
+		case "Do":
+			form, ok := reader.r.Resolve(xobjects[op.Operands[0].(core.Name)]).(*core.Stream)
+			if !ok {
+				t.Fatalf("page %d: %v is not a form", pageNum, op.Operands[0])
+			}
+			formData, err := reader.r.DecodeStream(form)
+			if err != nil {
+				t.Fatalf("failed to decode form: %v", err)
+			}
+			slot := 0
+			if x >= sheetWidth/2 {
+				slot = 1
+			}
+			if m := label.FindSubmatch(formData); m != nil {
+				sides[slot] = string(m[1])
+			}
+		}
+	}
+	return sides
+}
+
+// numberValue は数値のオペランドをfloat64にする
+func numberValue(obj core.Object) float64 {
+	switch v := obj.(type) {
+	case core.Integer:
+		return float64(v)
+	case core.Real:
+		return float64(v)
+	}
+	return 0
+}
+
+func TestPDFReader_Booklet(t *testing.T) {
+	tests := []struct {
+		name      string
+		pageCount int
+		opts      BookletOptions
+		wantSize  PageSize
+		want      [][2]string // 出力の各ページの左右
+	}{
+		{
+			name:      "four pages",
+			pageCount: 4,
+			wantSize:  PageSize{Width: 200, Height: 150},
+			want:      [][2]string{{"P4", "P1"}, {"P2", "P3"}},
+		},
+		{
+			name:      "padded with blank pages",
+			pageCount: 5,
+			wantSize:  PageSize{Width: 200, Height: 150},
+			want:      [][2]string{{"", "P1"}, {"P2", ""}, {"", "P3"}, {"P4", "P5"}},
+		},
+		{
+			name:      "eight pages right to left",
+			pageCount: 8,
+			opts:      BookletOptions{RightToLeft: true},
+			wantSize:  PageSize{Width: 200, Height: 150},
+			want:      [][2]string{{"P1", "P8"}, {"P7", "P2"}, {"P3", "P6"}, {"P5", "P4"}},
+		},
+		{
+			name:      "sheet size is made landscape",
+			pageCount: 2,
+			opts:      BookletOptions{SheetSize: PageSizeA4, Margin: 10},
+			wantSize:  PageSize{Width: 842, Height: 595},
+			want:      [][2]string{{"", "P1"}, {"P2", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := bookletSourcePDF(t, tt.pageCount)
+			var buf bytes.Buffer
+			if err := source.Booklet(&buf, tt.opts); err != nil {
+				t.Fatalf("Booklet failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+
+			if got := reader.PageCount(); got != len(tt.want) {
+				t.Fatalf("PageCount = %d, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				layout, err := reader.ExtractPageLayout(i)
+				if err != nil {
+					t.Fatalf("ExtractPageLayout(%d) failed: %v", i, err)
+				}
+				if layout.Width != tt.wantSize.Width || layout.Height != tt.wantSize.Height {
+					t.Errorf("page %d size = %vx%v, want %vx%v", i, layout.Width, layout.Height, tt.wantSize.Width, tt.wantSize.Height)
+				}
+				if got := bookletSides(t, reader, i, tt.wantSize.Width); got != want {
+					t.Errorf("page %d sides = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBookletOrder(t *testing.T) {
+	got := bookletOrder(8)
+	want := [][2]int{{7, 0}, {1, 6}, {5, 2}, {3, 4}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("bookletOrder(8) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,313 @@
+package gopdf
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// CompareOptions は2つのPDFを比較するときの設定
+type CompareOptions struct {
+	Visual    bool    // ページを画像に描画して比較する（RenderPageを使う）
+	DPI       float64 // 画像で比較するときの解像度（0の場合はDefaultRenderDPI）
+	Tolerance uint8   // 差とみなさない色の差（RGBの各成分の差の最大値、0〜255）
+}
+
+// TextDiffOp はテキストの差分の行の種類
+type TextDiffOp int
+
+const (
+	TextDiffEqual  TextDiffOp = iota // 両方にある行
+	TextDiffDelete                   // aにだけある行
+	TextDiffInsert                   // bにだけある行
+)
+
+// TextDiffLine はテキストの差分の1行
+type TextDiffLine struct {
+	Op   TextDiffOp
+	Text string
+}
+
+// PageComparison は1ページの比較結果
+type PageComparison struct {
+	Page int  // ページ番号（0-indexed）
+	InA  bool // aにこのページがあるか
+	InB  bool // bにこのページがあるか
+
+	TextChanged bool           // テキストに差があるか
+	TextDiff    []TextDiffLine // 行ごとの差分（変わらない行も含む、片方にしかないページはその行がすべて追加・削除）
+
+	// 以下はCompareOptions.Visualの場合のみ
+	DiffPixels int         // 色の差がToleranceを超えたピクセルの数
+	DiffRatio  float64     // DiffPixelsの全ピクセルに対する割合（0〜1）
+	Heatmap    *image.RGBA // 差のあるピクセルを赤く示した画像（差がなければnil）
+}
+
+// Changed はページに差があるかを返す
+func (p PageComparison) Changed() bool {
+	return !p.InA || !p.InB || p.TextChanged || p.DiffPixels > 0
+}
+
+// TextDiffString はテキストの差分を、行の先頭に "  "（変わらない）、"- "（aのみ）、"+ "（bのみ）を付けた文字列にする
+func (p PageComparison) TextDiffString() string {
+	var sb strings.Builder
+	for _, line := range p.TextDiff {
+		switch line.Op {
+		case TextDiffDelete:
+			sb.WriteString("- ")
+		case TextDiffInsert:
+			sb.WriteString("+ ")
+		default:
+			sb.WriteString("  ")
+		}
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ComparisonResult は2つのPDFの比較結果
+type ComparisonResult struct {
+	PageCountA int
+	PageCountB int
+	Pages      []PageComparison // ページ数の多い方に合わせた、ページ順の結果
+}
+
+// Equal は2つのPDFに差がないかを返す
+func (c *ComparisonResult) Equal() bool {
+	return len(c.ChangedPages()) == 0
+}
+
+// ChangedPages は差のあるページの番号を返す
+func (c *ComparisonResult) ChangedPages() []int {
+	var pages []int
+	for _, p := range c.Pages {
+		if p.Changed() {
+			pages = append(pages, p.Page)
+		}
+	}
+	return pages
+}
+
+// Compare は2つのPDFをページごとに比較する
+// テキストはExtractTextInRectと同じく行ごとにまとめて行単位で比較し、
+// CompareOptions.Visualの場合はページを描画してピクセルの差も求める
+// 設計書: docs/compare_design.md
+func Compare(a, b *PDFReader, opts CompareOptions) (*ComparisonResult, error) {
+	result := &ComparisonResult{
+		PageCountA: a.PageCount(),
+		PageCountB: b.PageCount(),
+	}
+
+	pageCount := max(result.PageCountA, result.PageCountB)
+	for i := 0; i < pageCount; i++ {
+		page := PageComparison{
+			Page: i,
+			InA:  i < result.PageCountA,
+			InB:  i < result.PageCountB,
+		}
+
+		linesA, err := comparePageLines(a, i, page.InA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d of a: %w", i, err)
+		}
+		linesB, err := comparePageLines(b, i, page.InB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d of b: %w", i, err)
+		}
+		page.TextDiff = diffLines(linesA, linesB)
+		for _, line := range page.TextDiff {
+			if line.Op != TextDiffEqual {
+				page.TextChanged = true
+				break
+			}
+		}
+
+		if opts.Visual {
+			if err := comparePageImages(a, b, &page, opts); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Pages = append(result.Pages, page)
+	}
+
+	return result, nil
+}
+
+// comparePageLines はページのテキストを行に分けて返す（ページがなければnil）
+func comparePageLines(r *PDFReader, pageNum int, exists bool) ([]string, error) {
+	if !exists {
+		return nil, nil
+	}
+	glyphs, err := r.extractPageGlyphs(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	text := glyphRunsText(selectGlyphRuns(glyphs, func(layout.TextElement) bool { return true }))
+	if text == "" {
+		return nil, nil
+	}
+	return strings.Split(text, "\n"), nil
+}
+
+// comparePageImages はページを描画してピクセルの差を求める
+// 片方にしかないページは、もう片方を同じ大きさの白い画像として比較する
+func comparePageImages(a, b *PDFReader, page *PageComparison, opts CompareOptions) error {
+	render := func(r *PDFReader, exists bool, name string) (image.Image, error) {
+		if !exists {
+			return nil, nil
+		}
+		img, err := r.RenderPage(page.Page, RenderOptions{DPI: opts.DPI})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render page %d of %s: %w", page.Page, name, err)
+		}
+		return img, nil
+	}
+	imgA, err := render(a, page.InA, "a")
+	if err != nil {
+		return err
+	}
+	imgB, err := render(b, page.InB, "b")
+	if err != nil {
+		return err
+	}
+
+	heatmap, diffPixels := diffImages(imgA, imgB, opts.Tolerance)
+	page.DiffPixels = diffPixels
+	if total := heatmap.Bounds().Dx() * heatmap.Bounds().Dy(); total > 0 {
+		page.DiffRatio = float64(diffPixels) / float64(total)
+	}
+	if diffPixels > 0 {
+		page.Heatmap = heatmap
+	}
+	return nil
+}
+
+// diffImages は2つの画像のピクセルの差を求め、差のあるピクセルの数とヒートマップを返す
+// ヒートマップはaを薄くした画像の上に、差のあるピクセルを差の大きさに応じた濃さの赤で重ねたもの
+// 大きさの違う画像は左上を揃え、範囲の外は白として比較する（nilの画像はすべて白）
+func diffImages(a, b image.Image, tolerance uint8) (*image.RGBA, int) {
+	var bounds image.Rectangle
+	for _, img := range []image.Image{a, b} {
+		if img != nil {
+			size := img.Bounds().Size()
+			bounds = bounds.Union(image.Rect(0, 0, size.X, size.Y))
+		}
+	}
+
+	at := func(img image.Image, x, y int) color.RGBA {
+		if img == nil {
+			return color.RGBA{255, 255, 255, 255}
+		}
+		p := image.Point{X: x, Y: y}.Add(img.Bounds().Min)
+		if !p.In(img.Bounds()) {
+			return color.RGBA{255, 255, 255, 255}
+		}
+		return color.RGBAModel.Convert(img.At(p.X, p.Y)).(color.RGBA)
+	}
+	channelDiff := func(c1, c2 uint8) uint8 {
+		if c1 > c2 {
+			return c1 - c2
+		}
+		return c2 - c1
+	}
+
+	heatmap := image.NewRGBA(bounds)
+	diffPixels := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			ca, cb := at(a, x, y), at(b, x, y)
+			d := max(channelDiff(ca.R, cb.R), channelDiff(ca.G, cb.G), channelDiff(ca.B, cb.B))
+
+			// aの明るさを1/4の濃さにした背景
+			gray := uint8((uint32(ca.R)*299 + uint32(ca.G)*587 + uint32(ca.B)*114) / 1000)
+			faded := 255 - (255-gray)/4
+			c := color.RGBA{faded, faded, faded, 255}
+			if d > tolerance {
+				diffPixels++
+				// 差が小さくても見えるように、濃さは1/3から始める
+				strength := 85 + uint32(d)*170/255
+				c.G = uint8(uint32(c.G) * (255 - strength) / 255)
+				c.B = uint8(uint32(c.B) * (255 - strength) / 255)
+				c.R = 255
+			}
+			heatmap.SetRGBA(x, y, c)
+		}
+	}
+	return heatmap, diffPixels
+}
+
+// maxDiffCells は行単位の差分で、最長共通部分列の表を作る大きさの上限
+// これを超える範囲は、aの行をすべて削除してbの行をすべて追加したものとする
+const maxDiffCells = 4 << 20
+
+// diffLines は2つの行の並びの差分を返す
+// 先頭と末尾の共通する行を除いた範囲で最長共通部分列を求める
+func diffLines(a, b []string) []TextDiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result []TextDiffLine
+	for _, line := range a[:prefix] {
+		result = append(result, TextDiffLine{Op: TextDiffEqual, Text: line})
+	}
+	result = append(result, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, TextDiffLine{Op: TextDiffEqual, Text: line})
+	}
+	return result
+}
+
+// diffMiddle は最長共通部分列で差分を求める（削除を追加より先に並べる）
+func diffMiddle(a, b []string) []TextDiffLine {
+	var result []TextDiffLine
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			result = append(result, TextDiffLine{Op: TextDiffDelete, Text: line})
+		}
+		for _, line := range b {
+			result = append(result, TextDiffLine{Op: TextDiffInsert, Text: line})
+		}
+		return result
+	}
+
+	// lcs[i][j] は a[i:] と b[j:] の最長共通部分列の長さ
+	width := len(b) + 1
+	lcs := make([]int, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			result = append(result, TextDiffLine{Op: TextDiffEqual, Text: a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[(i+1)*width+j] >= lcs[i*width+j+1]):
+			result = append(result, TextDiffLine{Op: TextDiffDelete, Text: a[i]})
+			i++
+		default:
+			result = append(result, TextDiffLine{Op: TextDiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	return result
+}
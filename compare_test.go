@@ -0,0 +1,164 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// compareTestPDF はページごとのコンテンツストリームからPDFを作成する
+func compareTestPDF(t *testing.T, pages ...string) *PDFReader {
+	t.Helper()
+	kids := ""
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", "", "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"}
+	for _, contents := range pages {
+		pageNum := len(objects) + 1
+		kids += fmt.Sprintf("%d 0 R ", pageNum)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 100] /Contents %d 0 R /Resources << /Font << /F1 3 0 R >> >> >>", pageNum+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(pages))
+
+	reader, err := OpenReader(bytes.NewReader(buildRawPDF(objects)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+func TestCompare(t *testing.T) {
+	const (
+		report  = "BT /F1 10 Tf 10 80 Td (Total: 100) Tj 0 -14 Td (Status: ok) Tj ET"
+		changed = "BT /F1 10 Tf 10 80 Td (Total: 120) Tj 0 -14 Td (Status: ok) Tj ET"
+		redBox  = "1 0 0 rg 150 10 40 40 re f"
+		blueBox = "0 0 1 rg 150 10 40 40 re f"
+	)
+
+	tests := []struct {
+		name        string
+		a, b        []string
+		opts        CompareOptions
+		wantEqual   bool
+		wantChanged []int
+		check       func(t *testing.T, result *ComparisonResult)
+	}{
+		{
+			name:      "identical",
+			a:         []string{report, redBox},
+			b:         []string{report, redBox},
+			opts:      CompareOptions{Visual: true},
+			wantEqual: true,
+		},
+		{
+			name:        "text changed",
+			a:           []string{report},
+			b:           []string{changed},
+			wantChanged: []int{0},
+			check: func(t *testing.T, result *ComparisonResult) {
+				want := []TextDiffLine{
+					{Op: TextDiffDelete, Text: "Total: 100"},
+					{Op: TextDiffInsert, Text: "Total: 120"},
+					{Op: TextDiffEqual, Text: "Status: ok"},
+				}
+				if got := result.Pages[0].TextDiff; !reflect.DeepEqual(got, want) {
+					t.Errorf("TextDiff = %+v, want %+v", got, want)
+				}
+				if got, want := result.Pages[0].TextDiffString(), "- Total: 100\n+ Total: 120\n  Status: ok\n"; got != want {
+					t.Errorf("TextDiffString = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name:        "page added",
+			a:           []string{report},
+			b:           []string{report, report},
+			wantChanged: []int{1},
+			check: func(t *testing.T, result *ComparisonResult) {
+				page := result.Pages[1]
+				if page.InA || !page.InB {
+					t.Errorf("InA = %v, InB = %v, want false, true", page.InA, page.InB)
+				}
+				if len(page.TextDiff) != 2 || page.TextDiff[0].Op != TextDiffInsert {
+					t.Errorf("TextDiff = %+v, want all lines inserted", page.TextDiff)
+				}
+			},
+		},
+		{
+			name:        "color changed",
+			a:           []string{redBox},
+			b:           []string{blueBox},
+			opts:        CompareOptions{Visual: true},
+			wantChanged: []int{0},
+			check: func(t *testing.T, result *ComparisonResult) {
+				page := result.Pages[0]
+				if page.TextChanged {
+					t.Error("TextChanged = true, want false")
+				}
+				if page.DiffPixels != 40*40 {
+					t.Errorf("DiffPixels = %d, want %d", page.DiffPixels, 40*40)
+				}
+				if page.Heatmap == nil || page.Heatmap.Bounds().Dx() != 200 {
+					t.Fatalf("Heatmap = %v, want 200px wide image", page.Heatmap)
+				}
+				if c := page.Heatmap.RGBAAt(170, 70); c.R != 255 || c.G > 100 {
+					t.Errorf("heatmap pixel = %v, want red", c)
+				}
+			},
+		},
+		{
+			name:      "color change without visual",
+			a:         []string{redBox},
+			b:         []string{blueBox},
+			wantEqual: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(compareTestPDF(t, tt.a...), compareTestPDF(t, tt.b...), tt.opts)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if got := result.Equal(); got != tt.wantEqual {
+				t.Errorf("Equal() = %v, want %v", got, tt.wantEqual)
+			}
+			if got := result.ChangedPages(); !reflect.DeepEqual(got, tt.wantChanged) {
+				t.Errorf("ChangedPages() = %v, want %v", got, tt.wantChanged)
+			}
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want string
+	}{
+		{name: "empty", want: ""},
+		{name: "same", a: []string{"x", "y"}, b: []string{"x", "y"}, want: "=x =y"},
+		{name: "insert in middle", a: []string{"a", "c"}, b: []string{"a", "b", "c"}, want: "=a +b =c"},
+		{name: "delete", a: []string{"a", "b", "c"}, b: []string{"a", "c"}, want: "=a -b =c"},
+		{name: "replace", a: []string{"a", "b", "c"}, b: []string{"a", "x", "c"}, want: "=a -b +x =c"},
+		{name: "moved line", a: []string{"a", "b", "c", "d"}, b: []string{"b", "c", "a", "d"}, want: "-a =b =c +a =d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, line := range diffLines(tt.a, tt.b) {
+				got = append(got, string("=-+"[line.Op])+line.Text)
+			}
+			if s := fmt.Sprint(got); s != "["+tt.want+"]" {
+				t.Errorf("diffLines = %s, want [%s]", s, tt.want)
+			}
+		})
+	}
+}
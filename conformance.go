@@ -0,0 +1,250 @@
+package gopdf
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// Conformance は出力するPDFが準拠する規格
+type Conformance int
+
+const (
+	// ConformanceNone は規格への準拠を強制しない（デフォルト）
+	ConformanceNone Conformance = iota
+	// PDFA2B は PDF/A-2b（ISO 19005-2 レベルB：見た目の長期保存）
+	PDFA2B
+	// PDFUA1 は PDF/UA-1（ISO 14289-1：アクセシビリティ）
+	PDFUA1
+	// PDFA3B は PDF/A-3b（ISO 19005-3 レベルB：任意形式のファイルを埋め込めるPDF/A-2b）
+	PDFA3B
+)
+
+// String は規格の名前を返す
+func (c Conformance) String() string {
+	switch c {
+	case ConformanceNone:
+		return "none"
+	case PDFA2B:
+		return "PDF/A-2b"
+	case PDFUA1:
+		return "PDF/UA-1"
+	case PDFA3B:
+		return "PDF/A-3b"
+	default:
+		return fmt.Sprintf("Conformance(%d)", int(c))
+	}
+}
+
+// pdfaID はXMPのPDF/A識別情報（pdfaid:part と pdfaid:conformance）を返す
+func (c Conformance) pdfaID() (part int, level string, ok bool) {
+	switch c {
+	case PDFA2B:
+		return 2, "B", true
+	case PDFA3B:
+		return 3, "B", true
+	default:
+		return 0, "", false
+	}
+}
+
+// pdfuaID はXMPのPDF/UA識別情報（pdfuaid:part）を返す
+func (c Conformance) pdfuaID() (part int, ok bool) {
+	if c == PDFUA1 {
+		return 1, true
+	}
+	return 0, false
+}
+
+// requiresXMP はXMPメタデータの出力が必須かを返す
+func (c Conformance) requiresXMP() bool {
+	_, _, pdfa := c.pdfaID()
+	_, pdfua := c.pdfuaID()
+	return pdfa || pdfua
+}
+
+// SetConformance は出力するPDFが準拠する規格を設定する
+// PDF/Aを指定すると、WriteTo時に要件（フォントの埋め込み、出力インテント、
+// XMPの識別情報など）を満たすよう出力し、満たせない内容があれば *ConformanceError を返す
+// PDF/UAを指定すると、構造タグ・言語・タイトルなどアクセシビリティの要件を検査する
+func (d *Document) SetConformance(c Conformance) error {
+	switch c {
+	case ConformanceNone, PDFA2B, PDFA3B, PDFUA1:
+	default:
+		return fmt.Errorf("unsupported conformance level: %s", c)
+	}
+	d.conformance = c
+	return nil
+}
+
+// Conformance は設定されている準拠規格を返す
+func (d *Document) Conformance() Conformance {
+	return d.conformance
+}
+
+// ConformanceError は規格に準拠できない内容の一覧
+type ConformanceError struct {
+	Conformance Conformance
+	Violations  []string // 違反内容と対処方法
+}
+
+func (e *ConformanceError) Error() string {
+	return fmt.Sprintf("document does not conform to %s: %s", e.Conformance, strings.Join(e.Violations, "; "))
+}
+
+// checkConformance は設定された規格に違反する内容をすべて集めて返す
+func (d *Document) checkConformance() error {
+	var violations []string
+	switch d.conformance {
+	case PDFA2B, PDFA3B:
+		violations = d.pdfaViolations()
+	case PDFUA1:
+		violations = d.pdfuaViolations()
+	}
+	// PDF/A-2、PDF/A-3、PDF/UA-1はいずれもPDF 1.7（ISO 32000-1）に基づく
+	if d.conformance != ConformanceNone && d.PDFVersion() > PDFVersion17 {
+		violations = append(violations, fmt.Sprintf("PDF %s is not allowed (use SetPDFVersion with PDFVersion17 or earlier)", d.PDFVersion()))
+	}
+
+	if len(violations) > 0 {
+		return &ConformanceError{Conformance: d.conformance, Violations: violations}
+	}
+	return nil
+}
+
+// pdfaViolations はPDF/Aの要件に違反する内容を返す
+func (d *Document) pdfaViolations() []string {
+	var violations []string
+	intent := d.effectiveOutputIntent()
+	if d.encryption != nil {
+		violations = append(violations, "encryption is not allowed (remove SetEncryption)")
+	}
+	if len(d.javaScripts) > 0 || d.openJavaScript != "" {
+		violations = append(violations, "JavaScript is not allowed (remove AddJavaScript and SetOpenJavaScript)")
+	}
+	if d.signature != nil && d.signature.Appearance != nil {
+		violations = append(violations, "visible signature appearances use a non-embedded font (sign without Appearance)")
+	}
+	violations = append(violations, d.attachmentViolations()...)
+
+	for i, page := range d.pages {
+		violations = append(violations, page.standardFontViolations(i)...)
+
+		for _, img := range page.images {
+			if !outputIntentDescribes(intent, img.ColorSpace) {
+				violations = append(violations, fmt.Sprintf("page %d has a %s image that the %d-component output intent cannot describe (convert it or use SetOutputIntent with a matching profile)", i+1, img.ColorSpace, intent.components))
+			}
+		}
+		// 色はコンテンツストリームを解析せず、Page APIで設定した色空間の記録で判定する
+		if page.colorSpaces["DeviceRGB"] && !outputIntentDescribes(intent, "DeviceRGB") {
+			violations = append(violations, fmt.Sprintf("page %d uses DeviceRGB colors that the %d-component output intent cannot describe (use an RGB output intent)", i+1, intent.components))
+		}
+
+		// 不透明度1未満のテキストレイヤーは未定義のグラフィックス状態を参照する
+		if page.usesGS1 {
+			violations = append(violations, fmt.Sprintf("page %d has a text layer with Opacity below 1, which references an undefined graphics state (use Opacity 1 with an invisible RenderMode)", i+1))
+		}
+
+		for _, annot := range page.annotations {
+			if field, ok := annot.(*textFieldAnnotation); ok {
+				violations = append(violations, fmt.Sprintf("page %d: form field %q has no appearance stream (remove the field)", i+1, field.field.Name))
+			}
+		}
+	}
+	return violations
+}
+
+// attachmentViolations は埋め込みファイルに関する違反を返す
+// PDF/A-2はPDF/Aファイル以外の埋め込みを認めず、PDF/A-3は埋め込みファイルにMIMEタイプを求める
+func (d *Document) attachmentViolations() []string {
+	var violations []string
+	for _, a := range d.attachments {
+		switch {
+		case d.conformance == PDFA2B:
+			violations = append(violations, fmt.Sprintf("embedded file %q is not allowed in PDF/A-2 (use PDFA3B)", a.Name))
+		case a.MIMEType == "":
+			violations = append(violations, fmt.Sprintf("embedded file %q has no MIME type (set FileAttachment.MIMEType)", a.Name))
+		}
+	}
+	return violations
+}
+
+// outputIntentDescribes はデバイス色空間の色を出力インテントで解釈できるかを返す
+// DeviceGrayはどの出力インテントでも解釈でき、DeviceRGBとDeviceCMYKは成分数が一致する必要がある
+func outputIntentDescribes(intent *outputIntent, colorSpace string) bool {
+	switch colorSpace {
+	case "DeviceRGB":
+		return intent.components == 3
+	case "DeviceCMYK":
+		return intent.components == 4
+	default:
+		return true
+	}
+}
+
+// pdfuaViolations はPDF/UAの要件に違反する内容を返す
+func (d *Document) pdfuaViolations() []string {
+	var violations []string
+	if d.structure == nil {
+		violations = append(violations, "document has no structure tags (mark content with Page.BeginTag and EndTag)")
+	} else if n := d.structure.figuresWithoutAlt(); n > 0 {
+		violations = append(violations, fmt.Sprintf("%d figure(s) have no alternative text (use ImageOptions.AltText or Page.SetAltText)", n))
+	}
+	if d.language == "" {
+		violations = append(violations, "document language is not set (use SetLanguage)")
+	}
+	if d.metadata == nil || d.metadata.Title == "" {
+		violations = append(violations, "document title is not set (set Metadata.Title)")
+	}
+	if d.encryption != nil && !d.encryption.Permissions.ExtractContent {
+		violations = append(violations, "encryption must allow content extraction for assistive technology (set Permissions.ExtractContent)")
+	}
+	if d.signature != nil && d.signature.Appearance != nil {
+		violations = append(violations, "visible signature widgets are not tagged (sign without Appearance)")
+	}
+
+	for i, page := range d.pages {
+		violations = append(violations, page.standardFontViolations(i)...)
+
+		for _, annot := range page.annotations {
+			if field, ok := annot.(*textFieldAnnotation); ok {
+				violations = append(violations, fmt.Sprintf("page %d: form field %q is not tagged (remove the field)", i+1, field.field.Name))
+			}
+			if link, ok := annot.(*linkAnnotation); ok && !link.tagged {
+				target := fmt.Sprintf("%q", link.link.URI)
+				if link.link.Page != nil {
+					target = "a page in the document"
+				}
+				violations = append(violations, fmt.Sprintf("page %d: link to %s is not tagged (call AddLink inside a structure element such as StructLink)", i+1, target))
+			}
+		}
+	}
+	return violations
+}
+
+// standardFontViolations は埋め込まれない標準14フォントの使用を報告する（indexは0始まりのページ番号）
+func (p *Page) standardFontViolations(index int) []string {
+	names := make([]string, 0, len(p.fonts))
+	for _, f := range p.fonts {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	violations := make([]string, 0, len(names))
+	for _, name := range names {
+		violations = append(violations, fmt.Sprintf("page %d uses non-embedded standard font %s (use SetTTFFont with an embeddable TrueType font)", index+1, name))
+	}
+	return violations
+}
+
+// newFileID はトレーラーの/IDに使うファイル識別子を生成する
+func newFileID() (core.Array, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate file ID: %w", err)
+	}
+	return core.Array{core.String(id), core.String(id)}, nil
+}
@@ -0,0 +1,304 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocumentSetConformance(t *testing.T) {
+	tests := []struct {
+		name        string
+		conformance Conformance
+		wantErr     bool
+	}{
+		{"none", ConformanceNone, false},
+		{"PDF/A-2b", PDFA2B, false},
+		{"PDF/A-3b", PDFA3B, false},
+		{"unknown", Conformance(99), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			err := doc.SetConformance(tt.conformance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetConformance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && doc.Conformance() != tt.conformance {
+				t.Errorf("Conformance() = %v, want %v", doc.Conformance(), tt.conformance)
+			}
+		})
+	}
+}
+
+func TestDocumentWriteTo_PDFA2B(t *testing.T) {
+	doc := New()
+	if err := doc.SetConformance(PDFA2B); err != nil {
+		t.Fatal(err)
+	}
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetFillColor(Color{R: 0.2, G: 0.4, B: 0.8})
+	page.FillRectangle(50, 50, 200, 100)
+	doc.SetMetadata(Metadata{
+		Title:  "Archive",
+		Custom: map[string]string{"Department": "Sales"},
+		XMP: []XMPProperty{
+			{Namespace: "http://example.com/ns/invoice/1.0/", Prefix: "inv", Name: "Number", Value: "INV-001"},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	pdf := buf.Bytes()
+
+	// ヘッダー直後に4バイト以上のバイナリコメントがあること
+	lines := bytes.SplitN(pdf, []byte("\n"), 3)
+	if len(lines[1]) < 5 || lines[1][0] != '%' || lines[1][1] < 128 {
+		t.Errorf("second line = %q, want a binary comment", lines[1])
+	}
+
+	for _, want := range []string{"/OutputIntents", "/GTS_PDFA1", "/DestOutputProfile", "/ID"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("output does not contain %s", want)
+		}
+	}
+
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	packet, err := reader.RawXMP()
+	if err != nil {
+		t.Fatalf("RawXMP() failed: %v", err)
+	}
+	if err := checkWellFormed(packet); err != nil {
+		t.Fatalf("XMP is not well-formed: %v", err)
+	}
+	for _, want := range []string{
+		"<pdfaid:part>2</pdfaid:part>",
+		"<pdfaid:conformance>B</pdfaid:conformance>",
+		"<pdfaSchema:namespaceURI>http://ns.adobe.com/pdfx/1.3/</pdfaSchema:namespaceURI>",
+		"<pdfaProperty:name>Department</pdfaProperty:name>",
+		"<pdfaSchema:prefix>inv</pdfaSchema:prefix>",
+		"<pdfaProperty:name>Number</pdfaProperty:name>",
+	} {
+		if !bytes.Contains(packet, []byte(want)) {
+			t.Errorf("XMP does not contain %s", want)
+		}
+	}
+
+	// 識別情報や拡張スキーマはユーザーのXMPプロパティとして読み出されない
+	got, err := reader.XMPMetadata()
+	if err != nil {
+		t.Fatalf("XMPMetadata() failed: %v", err)
+	}
+	if len(got.XMP) != 1 || got.XMP[0].Name != "Number" {
+		t.Errorf("XMP = %+v, want only inv:Number", got.XMP)
+	}
+	if got.Custom["Department"] != "Sales" {
+		t.Errorf("Custom[Department] = %q, want %q", got.Custom["Department"], "Sales")
+	}
+}
+
+func TestDocumentWriteTo_PDFA2BDefaults(t *testing.T) {
+	doc := New()
+	if err := doc.SetConformance(PDFA2B); err != nil {
+		t.Fatal(err)
+	}
+	doc.AddPage(PageSizeA4, Portrait)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	// メタデータ未設定でもXMPとInfo辞書が出力される
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	packet, err := reader.RawXMP()
+	if err != nil {
+		t.Fatalf("RawXMP() failed: %v", err)
+	}
+	if !bytes.Contains(packet, []byte("<pdfaid:part>2</pdfaid:part>")) {
+		t.Error("XMP does not contain the PDF/A identification")
+	}
+	if bytes.Contains(packet, []byte("pdfaExtension")) {
+		t.Error("XMP contains extension schemas without custom properties")
+	}
+	if info := reader.Info(); info.Producer != "gopdf" || info.CreationDate.IsZero() {
+		t.Errorf("Info() = %+v, want default Producer and CreationDate", info)
+	}
+}
+
+func TestDocumentWriteTo_PDFA2BWithTTF(t *testing.T) {
+	fontPath := getTestTTFPath()
+	if fontPath == "" {
+		t.Skip("No test font available on this system")
+	}
+	ttf, err := LoadTTF(fontPath)
+	if err != nil {
+		t.Fatalf("LoadTTF failed: %v", err)
+	}
+
+	doc := New()
+	if err := doc.SetConformance(PDFA2B); err != nil {
+		t.Fatal(err)
+	}
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetTTFFont(ttf, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.DrawTextUTF8("Archived text", 50, 750); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/FontFile2")) {
+		t.Error("font is not embedded")
+	}
+}
+
+func TestDocumentWriteTo_PDFA2BSigned(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+
+	doc := New()
+	if err := doc.SetConformance(PDFA2B); err != nil {
+		t.Fatal(err)
+	}
+	doc.AddPage(PageSizeA4, Portrait)
+	if err := doc.Sign(SignatureOptions{Signer: key, Certificate: cert}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	results, err := reader.VerifySignatures(roots)
+	if err != nil {
+		t.Fatalf("VerifySignatures() failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Valid() {
+		t.Fatalf("VerifySignatures() = %+v, want one valid signature", results)
+	}
+}
+
+func TestDocumentWriteTo_PDFA2BViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, doc *Document, page *Page)
+		want  string
+	}{
+		{
+			name: "standard font",
+			setup: func(t *testing.T, doc *Document, page *Page) {
+				if err := page.SetFont(FontHelvetica, 12); err != nil {
+					t.Fatal(err)
+				}
+				if err := page.DrawText("Hello", 50, 750); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "non-embedded standard font Helvetica",
+		},
+		{
+			name: "encryption",
+			setup: func(t *testing.T, doc *Document, page *Page) {
+				if err := doc.SetEncryption(EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "encryption is not allowed",
+		},
+		{
+			name: "JavaScript",
+			setup: func(t *testing.T, doc *Document, page *Page) {
+				doc.SetOpenJavaScript("app.alert('hi');")
+			},
+			want: "JavaScript is not allowed",
+		},
+		{
+			name: "form field",
+			setup: func(t *testing.T, doc *Document, page *Page) {
+				if err := page.AddTextField(TextField{Name: "name", X: 50, Y: 50, Width: 200, Height: 20}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: `form field "name" has no appearance stream`,
+		},
+		{
+			name: "CMYK image",
+			setup: func(t *testing.T, doc *Document, page *Page) {
+				img := &Image{Width: 1, Height: 1, ColorSpace: "DeviceCMYK", BitsPerComponent: 8, Filter: "DCTDecode", Data: []byte{0}}
+				if err := page.DrawImage(img, 0, 0, 10, 10); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "DeviceCMYK image",
+		},
+		{
+			name: "transparent text layer",
+			setup: func(t *testing.T, doc *Document, page *Page) {
+				layer := NewTextLayer([]TextLayerWord{{Text: "hidden", Bounds: Rectangle{X: 10, Y: 10, Width: 50, Height: 12}}})
+				layer.Opacity = 0.5
+				if err := page.AddTextLayer(layer); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "Opacity below 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			if err := doc.SetConformance(PDFA2B); err != nil {
+				t.Fatal(err)
+			}
+			page := doc.AddPage(PageSizeA4, Portrait)
+			tt.setup(t, doc, page)
+
+			err := doc.WriteTo(&bytes.Buffer{})
+			var confErr *ConformanceError
+			if !errors.As(err, &confErr) {
+				t.Fatalf("WriteTo() error = %v, want *ConformanceError", err)
+			}
+			if confErr.Conformance != PDFA2B {
+				t.Errorf("Conformance = %v, want %v", confErr.Conformance, PDFA2B)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
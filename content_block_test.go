@@ -205,3 +205,65 @@ func TestPageLayoutSortedContentBlocks(t *testing.T) {
 		t.Errorf("blocks[2] position = (%f, %f), want (100, 500)", x3, y3)
 	}
 }
+
+// TestPageLayout_VectorBlocks はパスのVectorBlockへのまとめ方のテスト
+func TestPageLayout_VectorBlocks(t *testing.T) {
+	line := func(x1, y1, x2, y2 float64) PathBlock {
+		return PathBlock{Stroke: true, Rect: Rectangle{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}}
+	}
+
+	tests := []struct {
+		name  string
+		paths []PathBlock
+		want  []Rectangle // まとめたブロックの矩形
+		sizes []int       // 各ブロックのパスの数
+	}{
+		{
+			name: "grid is one block",
+			paths: []PathBlock{
+				line(100, 500, 300, 500), line(100, 550, 300, 550), line(100, 600, 300, 600),
+				line(100, 500, 100, 600), line(300, 500, 300, 600),
+			},
+			want:  []Rectangle{{X: 100, Y: 500, Width: 200, Height: 100}},
+			sizes: []int{5},
+		},
+		{
+			name:  "separate rules",
+			paths: []PathBlock{line(50, 700, 500, 700), line(50, 100, 500, 100)},
+			want:  []Rectangle{{X: 50, Y: 700, Width: 450}, {X: 50, Y: 100, Width: 450}},
+			sizes: []int{1, 1},
+		},
+		{
+			// 後のパスが、離れていた2つのまとまりをつなぐ
+			name:  "bridged",
+			paths: []PathBlock{line(100, 400, 200, 400), line(300, 400, 400, 400), line(200, 400, 300, 400)},
+			want:  []Rectangle{{X: 100, Y: 400, Width: 300}},
+			sizes: []int{3},
+		},
+		{
+			name: "background and invisible paths are skipped",
+			paths: []PathBlock{
+				{Fill: true, Rect: Rectangle{Width: 595, Height: 842}},
+				{Rect: Rectangle{X: 10, Y: 10, Width: 100, Height: 100}},
+				line(50, 700, 500, 700),
+			},
+			want:  []Rectangle{{X: 50, Y: 700, Width: 450}},
+			sizes: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &PageLayout{Width: 595, Height: 842, Paths: tt.paths}
+			blocks := pl.VectorBlocks()
+			if len(blocks) != len(tt.want) {
+				t.Fatalf("VectorBlocks() = %+v, want %d blocks", blocks, len(tt.want))
+			}
+			for i, block := range blocks {
+				if block.Rect != tt.want[i] || len(block.Paths) != tt.sizes[i] {
+					t.Errorf("block %d = %+v with %d paths, want %+v with %d paths", i, block.Rect, len(block.Paths), tt.want[i], tt.sizes[i])
+				}
+			}
+		})
+	}
+}
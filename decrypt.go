@@ -0,0 +1,37 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// Decrypt は暗号化されたPDFをパスワードで開き、暗号化を解除したPDFとして書き出す
+// password にはユーザーパスワードまたはオーナーパスワードを指定する
+// ページ内容・メタデータ・文書構造はそのまま保持される
+// 入力が暗号化されていない場合は、そのまま複製を書き出す
+func Decrypt(in io.ReadSeeker, out io.Writer, password string) error {
+	r, err := openAuthenticated(in, password)
+	if err != nil {
+		return err
+	}
+
+	return rewritePDF(r, out, nil)
+}
+
+// openAuthenticated はPDFを開き、暗号化されている場合はパスワードで認証する
+func openAuthenticated(in io.ReadSeeker, password string) (*reader.Reader, error) {
+	r, err := reader.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	if r.IsEncrypted() {
+		if err := r.AuthenticateWithPassword(password); err != nil {
+			return nil, fmt.Errorf("failed to decrypt PDF: %w", err)
+		}
+	}
+
+	return r, nil
+}
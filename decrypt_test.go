@@ -0,0 +1,145 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// buildEncryptedPDF はテキストとメタデータを含む暗号化PDFを生成する
+func buildEncryptedPDF(t *testing.T, opts *EncryptionOptions) []byte {
+	t.Helper()
+
+	doc := New()
+	for i, text := range []string{"First page", "Second page"} {
+		page := doc.AddPage(PageSizeA4, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatal(err)
+		}
+		if err := page.DrawText(text, 100, 700); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if err := page.AddTextField(TextField{Name: "name", X: 100, Y: 600, Width: 200, Height: 20, Value: "初期値"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	doc.SetMetadata(Metadata{Title: "秘密の文書", Author: "gopdf"})
+	if opts != nil {
+		if err := doc.SetEncryption(*opts); err != nil {
+			t.Fatalf("SetEncryption() failed: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *EncryptionOptions
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "RC4 40-bit with user password",
+			opts:     &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 40},
+			password: "user",
+		},
+		{
+			name:     "RC4 128-bit with owner password",
+			opts:     &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: RestrictedPermissions(), KeyLength: 128},
+			password: "owner",
+		},
+		{
+			name:     "AES-128",
+			opts:     &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128, Algorithm: EncryptionAES},
+			password: "user",
+		},
+		{
+			name:     "AES-256",
+			opts:     &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 256, Algorithm: EncryptionAES},
+			password: "owner",
+		},
+		{
+			name:     "empty user password",
+			opts:     &EncryptionOptions{OwnerPassword: "owner", Permissions: PrintOnlyPermissions(), KeyLength: 128},
+			password: "",
+		},
+		{
+			name:     "not encrypted",
+			opts:     nil,
+			password: "",
+		},
+		{
+			name:     "wrong password",
+			opts:     &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128},
+			password: "wrong",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildEncryptedPDF(t, tt.opts)
+
+			var out bytes.Buffer
+			err := Decrypt(bytes.NewReader(data), &out, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			defer reader.Close()
+
+			if reader.IsEncrypted() {
+				t.Fatal("decrypted PDF should not be encrypted")
+			}
+			if got := reader.PageCount(); got != 2 {
+				t.Errorf("PageCount() = %d, want 2", got)
+			}
+			for i, want := range []string{"First page", "Second page"} {
+				text, err := reader.ExtractPageText(i)
+				if err != nil {
+					t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+				}
+				if !strings.Contains(text, want) {
+					t.Errorf("page %d text = %q, want to contain %q", i, text, want)
+				}
+			}
+			if info := reader.Info(); info.Title != "秘密の文書" || info.Author != "gopdf" {
+				t.Errorf("Info() = %+v", info)
+			}
+
+			catalog, err := reader.r.GetCatalog()
+			if err != nil {
+				t.Fatal(err)
+			}
+			acroForm, ok := reader.r.Resolve(catalog["AcroForm"]).(core.Dictionary)
+			if !ok {
+				t.Fatal("AcroForm was not preserved")
+			}
+			fields, _ := reader.r.Resolve(acroForm["Fields"]).(core.Array)
+			if len(fields) != 1 {
+				t.Fatalf("got %d fields, want 1", len(fields))
+			}
+			field, _ := reader.r.Resolve(fields[0]).(core.Dictionary)
+			if got := rawTextString(field["V"]); got != "初期値" {
+				t.Errorf("field value = %q, want %q", got, "初期値")
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package gopdf
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/ryomak/gopdf/internal/core"
 	"github.com/ryomak/gopdf/internal/writer"
@@ -10,9 +12,22 @@ import (
 
 // Document represents a PDF document.
 type Document struct {
-	pages      []*Page
-	encryption *EncryptionOptions
-	metadata   *Metadata
+	pages          []*Page
+	encryption     *EncryptionOptions
+	metadata       *Metadata
+	javaScripts    []namedJavaScript // document-level scripts (Names/JavaScript)
+	openJavaScript string            // script run when the document is opened
+	signature      *SignatureOptions // digital signature applied on WriteTo
+	conformance    Conformance       // standard enforced on WriteTo (e.g. PDF/A-2b)
+	structure      *structureTree    // logical structure for tagged PDF (nil = untagged)
+	language       string            // natural language of the document (/Lang)
+	outputIntent   *outputIntent     // target output condition (/OutputIntents)
+	attachments    []FileAttachment  // embedded files (Names/EmbeddedFiles)
+	invoice        *facturXInvoice   // Factur-X / ZUGFeRD invoice metadata for XMP
+	version        PDFVersion        // output PDF version (0 = 1.7)
+	outlines       []*OutlineItem    // top-level bookmarks (Outlines)
+
+	templateImports map[*PDFReader]*pdfImport // sources of ImportPage, shared per reader
 }
 
 // New creates a new PDF document.
@@ -28,33 +43,74 @@ func (d *Document) AddPage(size PageSize, orientation Orientation) *Page {
 	page := &Page{
 		width:  actualSize.Width,
 		height: actualSize.Height,
+		doc:    d,
 	}
 	d.pages = append(d.pages, page)
+	if d.structure != nil {
+		d.structure.startPage(page)
+	}
 	return page
 }
 
 // WriteTo writes the PDF document to the given writer.
 func (d *Document) WriteTo(w io.Writer) error {
+	if err := d.checkConformance(); err != nil {
+		return err
+	}
+	if err := d.checkVersion(); err != nil {
+		return err
+	}
+
+	if d.signature == nil {
+		return d.writeDocument(w, nil)
+	}
+
+	if d.encryption != nil {
+		return fmt.Errorf("signing encrypted documents is not supported")
+	}
+
+	// 署名する場合は出力全体をバッファし、最後に/ByteRangeと/Contentsを埋める
+	sig := *d.signature
+	if sig.SigningTime.IsZero() {
+		sig.SigningTime = time.Now()
+	}
+
+	var buf bytes.Buffer
+	if err := d.writeDocument(&buf, &sig); err != nil {
+		return err
+	}
+	pdf := buf.Bytes()
+	if err := sig.sign(pdf); err != nil {
+		return err
+	}
+	_, err := w.Write(pdf)
+	return err
+}
+
+// writeDocument writes the PDF document, reserving space for sig if it is not nil.
+func (d *Document) writeDocument(w io.Writer, sig *SignatureOptions) error {
 	pdfWriter := writer.NewWriter(w)
 
 	// 暗号化が設定されている場合、暗号化情報をセットアップ
 	if d.encryption != nil {
-		encryptionInfo, err := writer.SetupEncryption(
-			d.encryption.UserPassword,
-			d.encryption.OwnerPassword,
-			d.encryption.Permissions.toInternal(),
-			d.encryption.KeyLength,
-		)
+		encryptionInfo, err := d.encryption.writerEncryption()
 		if err != nil {
-			return fmt.Errorf("failed to setup encryption: %w", err)
+			return err
 		}
 		pdfWriter.SetEncryption(encryptionInfo)
 	}
 
 	// ヘッダーを書く
+	pdfWriter.SetVersion(d.PDFVersion().String())
 	if err := pdfWriter.WriteHeader(); err != nil {
 		return err
 	}
+	_, _, pdfa := d.conformance.pdfaID()
+	if pdfa {
+		if err := pdfWriter.WriteBinaryComment(); err != nil {
+			return err
+		}
+	}
 
 	// まず、全ページで使用されているフォント（StandardFont）を収集
 	allFonts := make(map[string]*core.Reference)
@@ -96,25 +152,13 @@ func (d *Document) WriteTo(w io.Writer) error {
 	ttfEmbedder := writer.NewTTFFontEmbedder(pdfWriter)
 	for fontKey, ttfFont := range allTTFFonts {
 		// Copy usedGlyphs map to avoid concurrent access issues
-		ttfFont.glyphsMutex.Lock()
-		usedGlyphs := make(map[uint16]rune, len(ttfFont.usedGlyphs))
-		for k, v := range ttfFont.usedGlyphs {
-			usedGlyphs[k] = v
-		}
-		ttfFont.glyphsMutex.Unlock()
-
-		fontRef, err := ttfEmbedder.EmbedTTFFont(ttfFont.internal, usedGlyphs)
+		fontRef, err := ttfEmbedder.EmbedTTFFont(ttfFont.internal, ttfFont.usedGlyphsSnapshot())
 		if err != nil {
 			return fmt.Errorf("failed to embed TTF font %s: %w", fontKey, err)
 		}
 		ttfFontRefs[fontKey] = fontRef
 	}
 
-	// Pagesオブジェクトの番号を計算
-	// Font(フォント数) + TTFFont(TTFフォント数*5) + Image(画像数) + Content(1) + Page(1) のペアが len(d.pages) 個
-	// 次のオブジェクト番号 = 1 + フォント数 + TTFフォント数*5 + 画像数 + len(d.pages)*2
-	pagesObjNum := 1 + len(allFonts) + len(allTTFFonts)*5 + len(allImages) + len(d.pages)*2
-
 	// 標準フォントオブジェクトを作成
 	for fontKey := range allFonts {
 		// フォント名を取得
@@ -145,74 +189,90 @@ func (d *Document) WriteTo(w io.Writer) error {
 
 	// 画像XObjectを作成
 	for _, img := range imageOrder {
-		// SMask（アルファチャンネル）がある場合は先に処理
-		var smaskRef *core.Reference
-		if img.SMask != nil {
-			smaskDict := core.Dictionary{
-				core.Name("Type"):             core.Name("XObject"),
-				core.Name("Subtype"):          core.Name("Image"),
-				core.Name("Width"):            core.Integer(img.SMask.Width),
-				core.Name("Height"):           core.Integer(img.SMask.Height),
-				core.Name("ColorSpace"):       core.Name(img.SMask.ColorSpace),
-				core.Name("BitsPerComponent"): core.Integer(img.SMask.BitsPerComponent),
-				core.Name("Filter"):           core.Name(img.SMask.Filter),
-				core.Name("Length"):           core.Integer(len(img.SMask.Data)),
-			}
-
-			smaskStream := &core.Stream{
-				Dict: smaskDict,
-				Data: img.SMask.Data,
-			}
-
-			smaskNum, err := pdfWriter.AddObject(smaskStream)
-			if err != nil {
-				return err
-			}
-
-			smaskRef = &core.Reference{
-				ObjectNumber:     smaskNum,
-				GenerationNumber: 0,
-			}
+		imgRef, err := writeImageXObject(pdfWriter, img)
+		if err != nil {
+			return err
 		}
+		allImages[img] = imgRef
+	}
 
-		// メイン画像のDictionary作成
-		imageDict := core.Dictionary{
-			core.Name("Type"):             core.Name("XObject"),
-			core.Name("Subtype"):          core.Name("Image"),
-			core.Name("Width"):            core.Integer(img.Width),
-			core.Name("Height"):           core.Integer(img.Height),
-			core.Name("ColorSpace"):       core.Name(img.ColorSpace),
-			core.Name("BitsPerComponent"): core.Integer(img.BitsPerComponent),
-			core.Name("Filter"):           core.Name(img.Filter),
-			core.Name("Length"):           core.Integer(len(img.Data)),
-		}
+	// Pagesオブジェクトと各Pageオブジェクトの番号を先に予約する
+	// （注釈やアウトラインなど、ページへの前方参照を可能にするため）
+	pagesNum := pdfWriter.ReserveObject()
+	pageRefs := make([]*core.Reference, 0, len(d.pages))
+	for range d.pages {
+		pageRefs = append(pageRefs, &core.Reference{
+			ObjectNumber:     pdfWriter.ReserveObject(),
+			GenerationNumber: 0,
+		})
+	}
 
-		// SMaskがある場合は参照を追加
-		if smaskRef != nil {
-			imageDict[core.Name("SMask")] = smaskRef
-		}
+	if err := d.resolveLinkDestinations(pageRefs); err != nil {
+		return err
+	}
 
-		imageStream := &core.Stream{
-			Dict: imageDict,
-			Data: img.Data,
+	// 構造ツリーを出力（ページの/StructParentsを決めるため、ページより先に出力する）
+	var tagged *taggedPDF
+	if d.structure != nil {
+		var err error
+		tagged, err = d.writeStructureTree(pdfWriter, pageRefs)
+		if err != nil {
+			return err
 		}
+	}
 
-		imgNum, err := pdfWriter.AddObject(imageStream)
+	// 署名フィールドを準備（署名辞書の番号を予約し、可視署名の外観を出力する）
+	var sigField *signatureFieldAnnotation
+	if sig != nil {
+		var err error
+		sigField, err = sig.prepareField(pdfWriter, len(d.pages))
 		if err != nil {
 			return err
 		}
+	}
 
-		allImages[img] = &core.Reference{
-			ObjectNumber:     imgNum,
-			GenerationNumber: 0,
+	// 既存のPDFから追加したページは、元のPDFごとにオブジェクトを複製する
+	imports, copiers, err := d.newImportCopiers(pdfWriter, pageRefs)
+	if err != nil {
+		return err
+	}
+
+	// 取り込んだページ（ImportPage）をフォームXObjectとして出力
+	templateRefs := make(map[*ImportedPage]*core.Reference)
+	for _, page := range d.pages {
+		for _, tpl := range page.templates {
+			if _, exists := templateRefs[tpl]; exists {
+				continue
+			}
+			ref, err := writeTemplate(pdfWriter, copiers[tpl.source.pdf], tpl)
+			if err != nil {
+				return fmt.Errorf("failed to write imported page: %w", err)
+			}
+			templateRefs[tpl] = ref
 		}
 	}
 
 	// 各ページのコンテンツストリームとPageオブジェクトを作成
-	pageRefs := make([]*core.Reference, 0, len(d.pages))
-	for _, page := range d.pages {
+	var fieldRefs []formFieldRef
+	for i, page := range d.pages {
+		if page.source != nil {
+			var annots core.Array
+			if sigField != nil && sigField.page == i {
+				var fields []formFieldRef
+				annots, fields, err = writeAnnotations(pdfWriter, []pageAnnotation{sigField}, pageRefs[i], nil)
+				if err != nil {
+					return err
+				}
+				fieldRefs = append(fieldRefs, fields...)
+			}
+			if err := writeImportedPage(pdfWriter, copiers[page.source.pdf], page.source, pageRefs[i], &core.Reference{ObjectNumber: pagesNum}, annots); err != nil {
+				return fmt.Errorf("failed to write page %d: %w", i, err)
+			}
+			continue
+		}
+
 		// コンテンツストリームの作成
-		contentData := page.content.Bytes()
+		contentData := page.contentBytes()
 		contentDict := core.Dictionary{
 			core.Name("Length"): core.Integer(len(contentData)),
 		}
@@ -245,12 +305,15 @@ func (d *Document) WriteTo(w io.Writer) error {
 		}
 
 		// このページで使用されている画像をResourcesに追加
-		if len(page.images) > 0 {
+		if len(page.images) > 0 || len(page.templates) > 0 {
 			xobjectResources := core.Dictionary{}
-			for i, img := range page.images {
-				imageKey := fmt.Sprintf("Im%d", i+1)
+			for j, img := range page.images {
+				imageKey := fmt.Sprintf("Im%d", j+1)
 				xobjectResources[core.Name(imageKey)] = allImages[img]
 			}
+			for j, tpl := range page.templates {
+				xobjectResources[core.Name(templateKey(j))] = templateRefs[tpl]
+			}
 			resourcesDict[core.Name("XObject")] = xobjectResources
 		}
 
@@ -258,7 +321,7 @@ func (d *Document) WriteTo(w io.Writer) error {
 		pageDict := core.Dictionary{
 			core.Name("Type"): core.Name("Page"),
 			core.Name("Parent"): &core.Reference{
-				ObjectNumber:     pagesObjNum,
+				ObjectNumber:     pagesNum,
 				GenerationNumber: 0,
 			},
 			core.Name("MediaBox"): core.Array{
@@ -274,16 +337,49 @@ func (d *Document) WriteTo(w io.Writer) error {
 			core.Name("Resources"): resourcesDict,
 		}
 
-		// Pageオブジェクトを追加
-		pageNum, err := pdfWriter.AddObject(pageDict)
-		if err != nil {
+		// タグ付きPDFでは親ツリーのキーとタブ順序（構造順）を設定
+		if tagged != nil {
+			if key, ok := tagged.structParents[page]; ok {
+				pageDict[core.Name("StructParents")] = core.Integer(key)
+			}
+		}
+
+		// 注釈（フォームフィールドと署名のウィジェットを含む）を出力
+		annotations := page.annotations
+		if sigField != nil && sigField.page == i {
+			annotations = append(annotations[:len(annotations):len(annotations)], sigField)
+		}
+		if len(annotations) > 0 {
+			if tagged != nil {
+				pageDict[core.Name("Tabs")] = core.Name("S")
+			}
+			annots, fields, err := writeAnnotations(pdfWriter, annotations, pageRefs[i], tagged)
+			if err != nil {
+				return err
+			}
+			pageDict[core.Name("Annots")] = annots
+			fieldRefs = append(fieldRefs, fields...)
+		}
+
+		// Pageオブジェクトを出力
+		if err := pdfWriter.WriteObject(pageRefs[i].ObjectNumber, pageDict); err != nil {
 			return err
 		}
+	}
 
-		pageRefs = append(pageRefs, &core.Reference{
-			ObjectNumber:     pageNum,
-			GenerationNumber: 0,
-		})
+	// 追加したページが参照するオブジェクトを出力し、複製したフォームフィールドを登録する
+	for _, imp := range imports {
+		if err := copiers[imp].flush(); err != nil {
+			return err
+		}
+		fieldRefs = append(fieldRefs, importedFieldRefs(copiers[imp])...)
+	}
+
+	// 署名辞書を出力（/ByteRangeと/Contentsは出力後に埋める）
+	if sigField != nil {
+		if err := pdfWriter.WriteObject(sigField.sigRef.ObjectNumber, sig.signatureDict()); err != nil {
+			return err
+		}
 	}
 
 	// Pagesオブジェクトを作成
@@ -298,8 +394,7 @@ func (d *Document) WriteTo(w io.Writer) error {
 		core.Name("Count"): core.Integer(len(d.pages)),
 	}
 
-	pagesNum, err := pdfWriter.AddObject(pagesDict)
-	if err != nil {
+	if err := pdfWriter.WriteObject(pagesNum, pagesDict); err != nil {
 		return err
 	}
 
@@ -312,6 +407,76 @@ func (d *Document) WriteTo(w io.Writer) error {
 		},
 	}
 
+	// ドキュメントレベルのJavaScriptとフォームを追加
+	if err := d.addJavaScriptToCatalog(pdfWriter, catalogDict); err != nil {
+		return err
+	}
+	if len(fieldRefs) > 0 {
+		catalogDict[core.Name("AcroForm")] = createAcroFormDict(fieldRefs)
+	}
+
+	// 埋め込みファイル（添付ファイル）
+	if err := d.writeAttachments(pdfWriter, catalogDict); err != nil {
+		return err
+	}
+
+	// しおり（開いたときにしおりの一覧を表示する）
+	if len(d.outlines) > 0 {
+		outlinesRef, err := d.writeOutlines(pdfWriter, pageRefs)
+		if err != nil {
+			return err
+		}
+		catalogDict[core.Name("Outlines")] = outlinesRef
+		catalogDict[core.Name("PageMode")] = core.Name("UseOutlines")
+	}
+
+	// 文書の言語と論理構造（タグ付きPDF）
+	if d.language != "" {
+		catalogDict[core.Name("Lang")] = textString(d.language)
+	}
+	if tagged != nil {
+		catalogDict[core.Name("StructTreeRoot")] = tagged.structTreeRoot
+		catalogDict[core.Name("MarkInfo")] = core.Dictionary{core.Name("Marked"): core.Boolean(true)}
+		// ファイル名ではなく文書タイトルをウィンドウに表示する
+		if d.metadata != nil && d.metadata.Title != "" {
+			catalogDict[core.Name("ViewerPreferences")] = core.Dictionary{core.Name("DisplayDocTitle"): core.Boolean(true)}
+		}
+	}
+
+	// XMPメタデータ（Info辞書と同じ内容）をカタログの/Metadataに設定
+	// PDF/AとPDF/UA、Factur-XではXMPが必須なので、メタデータ未設定でも既定値で出力する
+	metadata := d.metadata
+	if metadata == nil && (d.conformance.requiresXMP() || d.invoice != nil) {
+		metadata = &Metadata{}
+	}
+	metadata = metadata.withDefaults()
+	if metadata != nil {
+		metadata = d.invoice.withXMP(metadata)
+		xmpStream, err := createXMPStream(metadata, d.conformance)
+		if err != nil {
+			return fmt.Errorf("failed to create XMP metadata: %w", err)
+		}
+		xmpNum, err := pdfWriter.AddObject(xmpStream)
+		if err != nil {
+			return err
+		}
+		catalogDict[core.Name("Metadata")] = &core.Reference{ObjectNumber: xmpNum}
+	}
+
+	// 出力インテント（PDF/Aでは色を解釈するためのICCプロファイルが必須）
+	intents, err := d.writeOutputIntents(pdfWriter)
+	if err != nil {
+		return err
+	}
+	if len(intents) > 0 {
+		catalogDict[core.Name("OutputIntents")] = intents
+	}
+
+	// AES-256はPDF 2.0の機能なので、1.7ヘッダーではAdobe拡張レベル8を宣言する
+	if d.encryption != nil && d.encryption.GetRevision() == 6 && d.PDFVersion() < PDFVersion20 {
+		addAES256Extension(catalogDict)
+	}
+
 	catalogNum, err := pdfWriter.AddObject(catalogDict)
 	if err != nil {
 		return err
@@ -319,8 +484,8 @@ func (d *Document) WriteTo(w io.Writer) error {
 
 	// Info辞書を作成（メタデータが設定されている場合）
 	var infoNum int
-	if d.metadata != nil {
-		infoDict := createInfoDict(d.metadata)
+	if metadata != nil {
+		infoDict := createInfoDict(metadata)
 		if len(infoDict) > 0 {
 			infoNum, err = pdfWriter.AddObject(infoDict)
 			if err != nil {
@@ -329,15 +494,8 @@ func (d *Document) WriteTo(w io.Writer) error {
 		}
 	}
 
-	// Trailerを書く
-	// ここで全オブジェクト数を計算: Catalog + Pages + (Content + Page) * ページ数 + Info(0 or 1) + 1(offset 0)
-	totalObjects := 1 + 1 + len(d.pages)*2 + 1
-	if infoNum > 0 {
-		totalObjects++
-	}
-
+	// Trailerを書く（SizeはWriteTrailerで設定される）
 	trailer := core.Dictionary{
-		core.Name("Size"): core.Integer(totalObjects),
 		core.Name("Root"): &core.Reference{
 			ObjectNumber:     catalogNum,
 			GenerationNumber: 0,
@@ -352,6 +510,15 @@ func (d *Document) WriteTo(w io.Writer) error {
 		}
 	}
 
+	// PDF/AとPDF 2.0ではファイル識別子が必須（暗号化時はWriterが設定する）
+	if (pdfa || d.PDFVersion() >= PDFVersion20) && d.encryption == nil {
+		id, err := newFileID()
+		if err != nil {
+			return err
+		}
+		trailer[core.Name("ID")] = id
+	}
+
 	return pdfWriter.WriteTrailer(trailer)
 }
 
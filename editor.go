@@ -0,0 +1,681 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// Editor は既存のPDFを開いたまま編集し、書き直して保存する
+// テキストを抽出して描き直すのではなく、PDFのオブジェクトをそのまま複製するため、
+// しおり・フォーム・論理構造・メタデータなど文書全体の情報は保存後も残る
+// 設計書: docs/editor_design.md
+type Editor struct {
+	r      *PDFReader
+	pages  []*editorPage       // 保存するページ（この順に出力する）
+	images map[int]core.Object // 縮小した画像XObject（元のオブジェクト番号 -> 代わりに出力する画像）
+
+	attachments []FileAttachment // AttachFileで埋め込むファイル
+}
+
+// editorPage は保存するページと、元のPDFでのページ
+type editorPage struct {
+	ref       *core.Reference // 元のPageオブジェクト
+	rotation  *int            // 変更した/Rotate（nil = 元のまま）
+	mediaBox  *Rectangle      // 変更した/MediaBox（nil = 元のまま）
+	cropBox   *Rectangle      // 変更した/CropBox（nil = 元のまま）
+	transform *[6]float64     // コンテンツと注釈に適用する変換（nil = 変換しない）
+	contents  []byte          // テキストや画像を置き換えたコンテンツストリーム（nil = 元のまま）
+
+	removedImages map[core.Name]bool     // リソースから除く画像XObjectの名前（RemoveImagesで削除したもの）
+	attachments   []attachmentAnnotation // 加える添付ファイル注釈
+}
+
+// NewEditor は読み込んだPDFを編集するEditorを作成する
+// 暗号化されたPDFは、AuthenticateWithPasswordで認証してから渡す（保存したPDFは暗号化されない）
+// 保存はSaveで行うため、rはSaveが終わるまで閉じないこと
+func NewEditor(r *PDFReader) (*Editor, error) {
+	if r.r.IsEncrypted() && !r.r.IsAuthenticated() {
+		return nil, fmt.Errorf("PDF is encrypted: authenticate with a password before editing it")
+	}
+
+	refs, err := r.r.GetPageReferences()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pages: %w", err)
+	}
+
+	e := &Editor{r: r}
+	for _, ref := range refs {
+		e.pages = append(e.pages, &editorPage{ref: ref})
+	}
+	return e, nil
+}
+
+// PageCount は現在のページ数を返す
+func (e *Editor) PageCount() int {
+	return len(e.pages)
+}
+
+// checkPage はページ番号（0-indexed）が現在のページの範囲内かを確認する
+func (e *Editor) checkPage(pageNum int) error {
+	if pageNum < 0 || pageNum >= len(e.pages) {
+		return fmt.Errorf("page number %d out of range [0, %d)", pageNum, len(e.pages))
+	}
+	return nil
+}
+
+// DeletePages は指定したページ（現在のページ番号、0-indexed）を削除する
+// すべてのページを削除することはできない
+func (e *Editor) DeletePages(pageNums ...int) error {
+	deleted := make(map[int]bool)
+	for _, pageNum := range pageNums {
+		if err := e.checkPage(pageNum); err != nil {
+			return err
+		}
+		deleted[pageNum] = true
+	}
+
+	order := make([]int, 0, len(e.pages))
+	for i := range e.pages {
+		if !deleted[i] {
+			order = append(order, i)
+		}
+	}
+	return e.ReorderPages(order)
+}
+
+// DuplicatePage はページを複製し、元のページの直後に挿入する
+// 複製したページの注釈は新しい注釈として複製する（フォームのウィジェットを除く）
+func (e *Editor) DuplicatePage(pageNum int) error {
+	if err := e.checkPage(pageNum); err != nil {
+		return err
+	}
+	order := make([]int, 0, len(e.pages)+1)
+	for i := range e.pages {
+		order = append(order, i)
+		if i == pageNum {
+			order = append(order, i)
+		}
+	}
+	return e.ReorderPages(order)
+}
+
+// MovePage はページをfromからtoの位置（移動後のページ番号）に移動する
+func (e *Editor) MovePage(from, to int) error {
+	if err := e.checkPage(from); err != nil {
+		return err
+	}
+	if err := e.checkPage(to); err != nil {
+		return err
+	}
+
+	order := make([]int, 0, len(e.pages))
+	for i := range e.pages {
+		if i != from {
+			order = append(order, i)
+		}
+	}
+	order = append(order[:to], append([]int{from}, order[to:]...)...)
+	return e.ReorderPages(order)
+}
+
+// ReorderPages はページを並べ替える
+// orderは新しい順に並べた現在のページ番号で、含めないページは削除され、複数回含めたページは複製される
+func (e *Editor) ReorderPages(order []int) error {
+	if len(order) == 0 {
+		return fmt.Errorf("cannot remove all pages")
+	}
+	pages := make([]*editorPage, 0, len(order))
+	for _, pageNum := range order {
+		if err := e.checkPage(pageNum); err != nil {
+			return err
+		}
+		page := *e.pages[pageNum]
+		pages = append(pages, &page)
+	}
+	e.pages = pages
+	return nil
+}
+
+// PageRotation はページの表示の回転（時計回りの角度、0, 90, 180, 270）を返す
+func (e *Editor) PageRotation(pageNum int) (int, error) {
+	if err := e.checkPage(pageNum); err != nil {
+		return 0, err
+	}
+	return e.pageRotation(e.pages[pageNum])
+}
+
+// pageRotation はページの現在の/Rotateを返す
+func (e *Editor) pageRotation(page *editorPage) (int, error) {
+	if page.rotation != nil {
+		return *page.rotation, nil
+	}
+	dict, err := e.r.r.GetPageByReference(page.ref)
+	if err != nil {
+		return 0, err
+	}
+	return e.r.pageRotation(dict), nil
+}
+
+// SetPageRotation はページの表示の回転（/Rotate）をrotation度にする
+// rotationは90の倍数（負の値や360以上は0〜270に正規化する）。pageNumsを省略した場合はすべてのページ
+func (e *Editor) SetPageRotation(rotation int, pageNums ...int) error {
+	return e.rotatePages(pageNums, func(int) int { return rotation }, rotation)
+}
+
+// RotatePages はページを時計回りにdegrees度回転する（現在の/Rotateに加える）
+// 横向きにスキャンされたページを直す場合などに使う。degreesは90の倍数で、負の値は反時計回り
+// pageNumsを省略した場合はすべてのページ
+func (e *Editor) RotatePages(degrees int, pageNums ...int) error {
+	return e.rotatePages(pageNums, func(current int) int { return current + degrees }, degrees)
+}
+
+// rotatePages は各ページの/Rotateを、現在の回転から求めたrotateの値にする
+// degreesは検証のための角度（90の倍数でなければエラー）
+func (e *Editor) rotatePages(pageNums []int, rotate func(current int) int, degrees int) error {
+	if degrees%90 != 0 {
+		return fmt.Errorf("rotation must be a multiple of 90, got %d", degrees)
+	}
+	pageNums, err := e.targetPages(pageNums)
+	if err != nil {
+		return err
+	}
+
+	rotations := make(map[int]int, len(pageNums))
+	for _, pageNum := range pageNums {
+		if _, done := rotations[pageNum]; done {
+			continue
+		}
+		current, err := e.pageRotation(e.pages[pageNum])
+		if err != nil {
+			return fmt.Errorf("failed to read page %d: %w", pageNum, err)
+		}
+		rotations[pageNum] = (rotate(current)%360 + 360) % 360
+	}
+	for pageNum, rotation := range rotations {
+		rotation := rotation
+		e.pages[pageNum].rotation = &rotation
+	}
+	return nil
+}
+
+// pageOverlay はページのコンテンツの上または下に描くフォームXObject
+// フォームは/Matrixでページの座標系に合わせてあり、ページの座標系の原点にそのまま描く
+type pageOverlay struct {
+	form     *core.Reference // 出力済みのフォームXObject
+	underlay bool            // 元のコンテンツの下に描く
+}
+
+// overlayFunc は保存するページ（pageNumは保存後のページ番号、pageは継承した属性を補った元のPage辞書）に
+// 重ねるフォームを出力して返す
+type overlayFunc func(w *writer.Writer, pageNum int, page core.Dictionary) ([]pageOverlay, error)
+
+// Save は編集したPDFを書き出す
+// ページツリーは現在のページ順で作り直し、削除したページへの参照（しおりの移動先など）はnullになる
+func (e *Editor) Save(out io.Writer) error {
+	return e.save(out, nil, false)
+}
+
+// SaveIncremental は元のPDFをそのまま書き出し、その後に変更したオブジェクトだけを増分更新として追記する
+// 元のバイト列が変わらないため、既存の電子署名は改ざんされていないと検証できる（署名後の追記としては検出される）
+// ページ順を変えていない場合は、編集したページだけを書き換える
+// 暗号化されたPDFと、xrefを修復して読み込んだPDFには使えない
+func (e *Editor) SaveIncremental(out io.Writer) error {
+	return e.save(out, nil, true)
+}
+
+// save は編集したPDFを書き出す。overlayがnilでなければ、各ページに返されたフォームを重ねる
+// incrementalの場合は、元のPDFの後に増分更新として追記する
+func (e *Editor) save(out io.Writer, overlay overlayFunc, incremental bool) error {
+	src := e.r.r
+	var w *writer.Writer
+	var c *objectCopier
+	if incremental {
+		var err error
+		if w, err = e.appendWriter(out); err != nil {
+			return err
+		}
+		c = newObjectCopier(src, w)
+		c.inPlace = true
+	} else {
+		w = writer.NewWriter(out)
+		if err := w.WriteHeader(); err != nil {
+			return err
+		}
+		c = newObjectCopier(src, w)
+		c.skip = make(map[int]bool)
+		if err := skipPageTree(src, c.skip); err != nil {
+			return err
+		}
+	}
+	// 縮小した画像は元のオブジェクトの代わりに出力する
+	if len(e.images) > 0 {
+		c.replace = make(map[int]core.Object, len(e.images))
+		for objNum, obj := range e.images {
+			c.replace[objNum] = obj
+		}
+	}
+	// 埋め込むファイルは、ページの注釈から参照できるよう先に出力する
+	specs, err := e.writeAttachments(w)
+	if err != nil {
+		return err
+	}
+
+	// ページ順を変えていない増分更新では、元のページツリーをそのまま使う
+	keepTree := false
+	if incremental {
+		refs, err := src.GetPageReferences()
+		keepTree = err == nil && len(refs) == len(e.pages)
+		for i := 0; keepTree && i < len(refs); i++ {
+			keepTree = refs[i].ObjectNumber == e.pages[i].ref.ObjectNumber
+		}
+	}
+
+	// 各ページの番号を予約する。元のページへの参照は、最初に現れたページに対応付ける
+	// 増分更新では、最初に現れたページは元のページを同じ番号で書き換える
+	var pagesNum int
+	if !keepTree {
+		pagesNum = w.ReserveObject()
+	}
+	pageRefs := make([]*core.Reference, len(e.pages))
+	duplicate := make([]bool, len(e.pages))
+	seen := make(map[int]bool, len(e.pages))
+	for i, page := range e.pages {
+		srcNum := page.ref.ObjectNumber
+		duplicate[i] = seen[srcNum]
+		seen[srcNum] = true
+		if incremental && !duplicate[i] {
+			pageRefs[i] = page.ref
+			continue
+		}
+		pageRefs[i] = &core.Reference{ObjectNumber: w.ReserveObject()}
+		if !duplicate[i] {
+			delete(c.skip, srcNum)
+			c.mapping[srcNum] = pageRefs[i].ObjectNumber
+		}
+	}
+
+	srcTrailer := src.GetTrailer()
+	root, ok := srcTrailer[core.Name("Root")].(*core.Reference)
+	if !ok {
+		return fmt.Errorf("trailer /Root is missing or not a reference")
+	}
+	catalog, err := src.GetCatalog()
+	if err != nil {
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+	rootRef := root
+	if !incremental {
+		rootRef = &core.Reference{ObjectNumber: w.ReserveObject()}
+		c.mapping[root.ObjectNumber] = rootRef.ObjectNumber
+	}
+
+	var parent *core.Reference
+	if !keepTree {
+		parent = &core.Reference{ObjectNumber: pagesNum}
+	}
+	kids := make(core.Array, len(e.pages))
+	for i, page := range e.pages {
+		kids[i] = pageRefs[i]
+		var overlays []pageOverlay
+		if overlay != nil {
+			dict, err := e.pageDict(page)
+			if err != nil {
+				return fmt.Errorf("failed to read page %d: %w", i, err)
+			}
+			overlays, err = overlay(w, i, dict)
+			if err != nil {
+				return fmt.Errorf("failed to write overlays of page %d: %w", i, err)
+			}
+		}
+		if keepTree && len(overlays) == 0 && !page.edited() {
+			continue
+		}
+		added, err := writeAttachmentAnnotations(w, page, pageRefs[i], specs, e.attachments)
+		if err != nil {
+			return fmt.Errorf("failed to write attachment annotations of page %d: %w", i, err)
+		}
+		if err := e.writePage(c, page, pageRefs[i], parent, duplicate[i], overlays, added); err != nil {
+			return fmt.Errorf("failed to write page %d: %w", i, err)
+		}
+	}
+
+	if !keepTree {
+		if err := w.WriteObject(pagesNum, core.Dictionary{
+			core.Name("Type"):  core.Name("Pages"),
+			core.Name("Kids"):  kids,
+			core.Name("Count"): core.Integer(len(e.pages)),
+		}); err != nil {
+			return err
+		}
+	}
+	// ページツリーをそのまま使う増分更新でも、ファイルを埋め込んだ場合はカタログを書き換える
+	if !keepTree || len(specs) > 0 {
+		catalogDict := make(core.Dictionary, len(catalog))
+		for k, v := range catalog {
+			catalogDict[k] = v
+		}
+		if !keepTree {
+			delete(catalogDict, core.Name("Pages"))
+		}
+		catalogCopy, ok := c.copyObject(catalogDict).(core.Dictionary)
+		if !ok {
+			return fmt.Errorf("catalog is not a dictionary")
+		}
+		if !keepTree {
+			catalogCopy[core.Name("Pages")] = parent
+		}
+		if err := e.addAttachments(c, catalog, catalogCopy, specs); err != nil {
+			return err
+		}
+		if err := w.WriteObject(rootRef.ObjectNumber, catalogCopy); err != nil {
+			return fmt.Errorf("failed to write catalog: %w", err)
+		}
+	}
+
+	trailer := core.Dictionary{
+		core.Name("Root"): rootRef,
+	}
+	if info, ok := srcTrailer[core.Name("Info")]; ok {
+		trailer[core.Name("Info")] = c.copyObject(info)
+	}
+	if id, ok := srcTrailer[core.Name("ID")]; ok {
+		trailer[core.Name("ID")] = id
+		// 増分更新では、IDの1つ目は元のまま、2つ目を新しくする
+		if ids, ok := id.(core.Array); ok && incremental && len(ids) == 2 {
+			fileID, err := writer.GenerateFileID()
+			if err != nil {
+				return err
+			}
+			trailer[core.Name("ID")] = core.Array{ids[0], core.String(fileID)}
+		}
+	}
+
+	if err := c.flush(); err != nil {
+		return err
+	}
+	return w.WriteTrailer(trailer)
+}
+
+// appendWriter は元のPDFのバイト列をoutに書き出し、その後に増分更新を追記するWriterを返す
+func (e *Editor) appendWriter(out io.Writer) (*writer.Writer, error) {
+	src := e.r.r
+	if src.IsEncrypted() {
+		return nil, fmt.Errorf("incremental update of an encrypted PDF is not supported")
+	}
+	prev := src.XrefOffset()
+	if prev < 0 {
+		return nil, fmt.Errorf("cannot append to a PDF whose cross-reference table was repaired")
+	}
+	size, ok := src.GetTrailer()[core.Name("Size")].(core.Integer)
+	if !ok || size <= 0 {
+		return nil, fmt.Errorf("trailer /Size is missing")
+	}
+
+	fileSize, err := src.Size()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file size: %w", err)
+	}
+	data, err := src.ReadRange(0, fileSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	// 追記するセクションは新しい行から始める
+	if n := len(data); n > 0 && data[n-1] != '\n' && data[n-1] != '\r' {
+		data = append(data, '\n')
+	}
+	if _, err := out.Write(data); err != nil {
+		return nil, err
+	}
+	return writer.NewIncrementalWriter(out, writer.IncrementalBase{
+		FileSize:   int64(len(data)),
+		Size:       int(size),
+		PrevXref:   prev,
+		Generation: src.Generation,
+	}), nil
+}
+
+// pageDict は継承した属性を補い、編集（回転・ボックス）を反映した元のPage辞書を返す
+// 大きさを変えたページは、元の座標の/BleedBox・/TrimBox・/ArtBoxを除く
+func (e *Editor) pageDict(page *editorPage) (core.Dictionary, error) {
+	dict, err := e.r.r.GetPageByReference(page.ref)
+	if err != nil {
+		return nil, err
+	}
+	if page.rotation != nil {
+		if *page.rotation == 0 {
+			delete(dict, core.Name("Rotate"))
+		} else {
+			dict[core.Name("Rotate")] = core.Integer(*page.rotation)
+		}
+	}
+	if page.mediaBox != nil {
+		box := page.mediaBox
+		dict[core.Name("MediaBox")] = rectArray(box.X, box.Y, box.Width, box.Height)
+	}
+	if page.cropBox != nil {
+		box := page.cropBox
+		dict[core.Name("CropBox")] = rectArray(box.X, box.Y, box.Width, box.Height)
+	}
+	if page.transform != nil {
+		delete(dict, core.Name("BleedBox"))
+		delete(dict, core.Name("TrimBox"))
+		delete(dict, core.Name("ArtBox"))
+	}
+	return dict, nil
+}
+
+// edited はページの属性やコンテンツを編集したかを返す
+func (p *editorPage) edited() bool {
+	return p.rotation != nil || p.mediaBox != nil || p.cropBox != nil || p.transform != nil || p.contents != nil ||
+		len(p.removedImages) > 0 || len(p.attachments) > 0
+}
+
+// writePage はページを、親をparentに置き換えて出力する（parentがnilの場合は元の親のまま）
+// 複製したページ（duplicate）は論理構造に属さず、注釈は新しいオブジェクトとして複製する
+// overlaysがある場合や大きさを変えた場合は、元のコンテンツをq/Qで囲み、その前後でフォームを描く
+// addedは/Annotsの後に加える出力済みの注釈
+func (e *Editor) writePage(c *objectCopier, page *editorPage, pageRef, parent *core.Reference, duplicate bool, overlays []pageOverlay, added core.Array) error {
+	src := c.src
+	dict, err := e.pageDict(page)
+	if err != nil {
+		return err
+	}
+	if parent != nil {
+		delete(dict, core.Name("Parent"))
+	}
+
+	// 重ねるフォームを登録したり削除した画像を除いたりできるよう、/Resourcesと/XObjectをこのページだけの辞書にする
+	if len(overlays) > 0 || len(page.removedImages) > 0 {
+		resources := core.Dictionary{}
+		if shared, ok := src.Resolve(dict[core.Name("Resources")]).(core.Dictionary); ok {
+			for k, v := range shared {
+				resources[k] = v
+			}
+		}
+		xobjects := core.Dictionary{}
+		if shared, ok := src.Resolve(resources[core.Name("XObject")]).(core.Dictionary); ok {
+			for k, v := range shared {
+				xobjects[k] = v
+			}
+		}
+		for name := range page.removedImages {
+			delete(xobjects, name)
+		}
+		resources[core.Name("XObject")] = xobjects
+		dict[core.Name("Resources")] = resources
+	}
+	// テキストを置き換えたページは、元のコンテンツの代わりに置き換えたストリームを出力する
+	if page.contents != nil {
+		delete(dict, core.Name("Contents"))
+	}
+	// 元のコンテンツの前後にストリームを加えられるよう、/Contentsを配列にする
+	if len(overlays) > 0 || page.transform != nil {
+		switch contents := src.Resolve(dict[core.Name("Contents")]).(type) {
+		case core.Array:
+			dict[core.Name("Contents")] = contents
+		case nil:
+			dict[core.Name("Contents")] = core.Array{}
+		default:
+			dict[core.Name("Contents")] = core.Array{dict[core.Name("Contents")]}
+		}
+	}
+
+	var annots core.Array
+	if page.transform != nil && !duplicate {
+		transformAnnotations(c, dict, *page.transform)
+	}
+	if duplicate {
+		delete(dict, core.Name("StructParents"))
+		delete(dict, core.Name("B"))
+		annots, err = duplicateAnnotations(c, dict[core.Name("Annots")], pageRef, page.transform)
+		if err != nil {
+			return err
+		}
+		delete(dict, core.Name("Annots"))
+	} else if len(added) > 0 {
+		// 注釈を加えられるよう、/Annotsを直接の配列にする
+		items, _ := src.Resolve(dict[core.Name("Annots")]).(core.Array)
+		dict[core.Name("Annots")] = append(core.Array{}, items...)
+	}
+
+	pageDict, ok := c.copyObject(dict).(core.Dictionary)
+	if !ok {
+		return fmt.Errorf("page is not a dictionary")
+	}
+	if parent != nil {
+		pageDict[core.Name("Parent")] = parent
+	}
+	if len(added) > 0 {
+		if items, ok := pageDict[core.Name("Annots")].(core.Array); ok {
+			annots = append(items, annots...)
+		}
+		annots = append(annots, added...)
+	}
+	if len(annots) > 0 {
+		pageDict[core.Name("Annots")] = annots
+	}
+	if page.contents != nil {
+		ref, err := addContentStream(c.w, page.contents)
+		if err != nil {
+			return err
+		}
+		if contents, ok := pageDict[core.Name("Contents")].(core.Array); ok {
+			pageDict[core.Name("Contents")] = append(contents, ref)
+		} else {
+			pageDict[core.Name("Contents")] = ref
+		}
+	}
+	if len(overlays) > 0 || page.transform != nil {
+		if err := wrapContents(c.w, pageDict, overlays, page.transform); err != nil {
+			return err
+		}
+	}
+	return c.w.WriteObject(pageRef.ObjectNumber, pageDict)
+}
+
+// wrapContents は複製したPage辞書の/Contentsの前後に、フォームを描くコンテンツストリームを加える
+// transformがnilでなければ、元のコンテンツにだけ変換を適用する
+// /Contentsは配列で、overlaysがある場合は/Resourcesと/XObjectが直接の辞書であること（writePageで置き換えたもの）
+func wrapContents(w *writer.Writer, pageDict core.Dictionary, overlays []pageOverlay, transform *[6]float64) error {
+	resources, _ := pageDict[core.Name("Resources")].(core.Dictionary)
+	xobjects, _ := resources[core.Name("XObject")].(core.Dictionary)
+	contents, _ := pageDict[core.Name("Contents")].(core.Array)
+	if len(overlays) > 0 && xobjects == nil {
+		return fmt.Errorf("page resources are not a dictionary")
+	}
+
+	// 元のコンテンツはq/Qで囲み、変更したグラフィックス状態（座標系や色）を上に描くフォームに持ち越さない
+	var before, after bytes.Buffer
+	after.WriteString("Q\n")
+	index := 0
+	for _, overlay := range overlays {
+		// 元のリソースと重ならない名前を付ける
+		var name core.Name
+		for {
+			index++
+			name = core.Name(fmt.Sprintf("Overlay%d", index))
+			if _, exists := xobjects[name]; !exists {
+				break
+			}
+		}
+		xobjects[name] = overlay.form
+
+		if overlay.underlay {
+			fmt.Fprintf(&before, "/%s Do\n", name)
+		} else {
+			fmt.Fprintf(&after, "/%s Do\n", name)
+		}
+	}
+	before.WriteString("q\n")
+	if transform != nil {
+		m := *transform
+		fmt.Fprintf(&before, "%.4f %.4f %.4f %.4f %.4f %.4f cm\n", m[0], m[1], m[2], m[3], m[4], m[5])
+	}
+
+	addStream := func(data []byte) (*core.Reference, error) {
+		num, err := w.AddObject(&core.Stream{
+			Dict: core.Dictionary{core.Name("Length"): core.Integer(len(data))},
+			Data: data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &core.Reference{ObjectNumber: num}, nil
+	}
+	beforeRef, err := addStream(before.Bytes())
+	if err != nil {
+		return err
+	}
+	afterRef, err := addStream(after.Bytes())
+	if err != nil {
+		return err
+	}
+
+	refs := make(core.Array, 0, len(contents)+2)
+	refs = append(refs, beforeRef)
+	refs = append(refs, contents...)
+	refs = append(refs, afterRef)
+	pageDict[core.Name("Contents")] = refs
+	return nil
+}
+
+// duplicateAnnotations は注釈を/PをpageRefにした新しいオブジェクトとして出力し、その参照を返す
+// transformがnilでなければ、注釈の位置を変換する
+// フォームのウィジェット（フィールドの/Kidsに登録できない）とポップアップ（元の注釈に属する）は複製しない
+func duplicateAnnotations(c *objectCopier, annots core.Object, pageRef *core.Reference, transform *[6]float64) (core.Array, error) {
+	items, _ := c.src.Resolve(annots).(core.Array)
+	var refs core.Array
+	for _, item := range items {
+		annot, ok := c.src.Resolve(item).(core.Dictionary)
+		if !ok {
+			continue
+		}
+		if subtype, _ := annot[core.Name("Subtype")].(core.Name); subtype == "Widget" || subtype == "Popup" {
+			continue
+		}
+
+		dict := make(core.Dictionary, len(annot))
+		for k, v := range annot {
+			dict[k] = v
+		}
+		if transform != nil {
+			dict = transformAnnotation(c.src, dict, *transform)
+		}
+		delete(dict, core.Name("Popup"))
+		delete(dict, core.Name("StructParent"))
+		delete(dict, core.Name("P"))
+
+		annotCopy := c.copyObject(dict).(core.Dictionary)
+		annotCopy[core.Name("P")] = pageRef
+		num, err := c.w.AddObject(annotCopy)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, &core.Reference{ObjectNumber: num})
+	}
+	return refs, nil
+}
@@ -0,0 +1,197 @@
+package gopdf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// AttachmentAnnotation はページに置く添付ファイル注釈（ファイルを開けるアイコン）
+type AttachmentAnnotation struct {
+	Rect     Rectangle // アイコンの位置（保存後のページの座標）
+	Icon     string    // アイコン名（/Name。PushPin、Paperclip、Graph、Tag。空の場合はPushPin）
+	Contents string    // 注釈のテキスト（空の場合はファイルの説明、説明もなければファイル名）
+}
+
+// attachmentAnnotation はページに加える添付ファイル注釈と、注釈から開くファイルの名前
+type attachmentAnnotation struct {
+	name  string
+	annot AttachmentAnnotation
+}
+
+// attachmentIcons は添付ファイル注釈の標準のアイコン名
+var attachmentIcons = map[string]bool{
+	"PushPin":   true,
+	"Paperclip": true,
+	"Graph":     true,
+	"Tag":       true,
+}
+
+// AttachFile はファイルを文書に埋め込む
+// 保存時にカタログの/Names/EmbeddedFilesに登録され、ビューアの添付ファイル一覧に表示される
+// 元のPDFやこのEditorに同じ名前の埋め込みファイルがある場合は置き換える
+// Relationshipを指定した場合は、カタログの/AFにも関連ファイルとして登録する
+func (e *Editor) AttachFile(a FileAttachment) error {
+	a, err := normalizeAttachment(a)
+	if err != nil {
+		return err
+	}
+	for i, existing := range e.attachments {
+		if existing.Name == a.Name {
+			e.attachments[i] = a
+			return nil
+		}
+	}
+	e.attachments = append(e.attachments, a)
+	return nil
+}
+
+// AddAttachmentAnnotation はAttachFileで埋め込んだファイルnameを開く添付ファイル注釈をページに加える
+// 注釈は添付ファイル一覧と同じファイル指定辞書を参照する（ファイルは1つだけ埋め込まれる）
+// 注釈を加えた後にページを複製した場合は、複製したページにも同じファイルの注釈を加える
+func (e *Editor) AddAttachmentAnnotation(pageNum int, name string, annot AttachmentAnnotation) error {
+	if err := e.checkPage(pageNum); err != nil {
+		return err
+	}
+	found := false
+	for _, a := range e.attachments {
+		found = found || a.Name == name
+	}
+	if !found {
+		return fmt.Errorf("attachment %q is not attached: call AttachFile first", name)
+	}
+	if annot.Rect.Width <= 0 || annot.Rect.Height <= 0 {
+		return fmt.Errorf("invalid annotation rect: %vx%v", annot.Rect.Width, annot.Rect.Height)
+	}
+	if annot.Icon != "" && !attachmentIcons[annot.Icon] {
+		return fmt.Errorf("unsupported attachment icon: %q", annot.Icon)
+	}
+
+	// 複製したページと配列を共有しないよう、新しい配列にする
+	page := e.pages[pageNum]
+	annots := make([]attachmentAnnotation, 0, len(page.attachments)+1)
+	annots = append(annots, page.attachments...)
+	page.attachments = append(annots, attachmentAnnotation{name: name, annot: annot})
+	return nil
+}
+
+// writeAttachments はAttachFileで埋め込んだファイルを出力し、ファイル名 -> ファイル指定辞書の参照を返す
+func (e *Editor) writeAttachments(w *writer.Writer) (map[string]*core.Reference, error) {
+	specs := make(map[string]*core.Reference, len(e.attachments))
+	for _, a := range e.attachments {
+		ref, err := writeFileSpec(w, a)
+		if err != nil {
+			return nil, err
+		}
+		specs[a.Name] = ref
+	}
+	return specs, nil
+}
+
+// addAttachments は複製したカタログの/Names/EmbeddedFilesを、元の名前ツリーにspecsを加えたものにする
+// 同じ名前の元のエントリは置き換え、置き換えたファイル指定辞書は/AFからも除く
+// catalogは元のカタログ、catalogCopyはcで複製したカタログ
+func (e *Editor) addAttachments(c *objectCopier, catalog, catalogCopy core.Dictionary, specs map[string]*core.Reference) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	src := c.src
+
+	// 名前ツリーのキーは（エンコードした文字列の）昇順である必要がある
+	entries := make(map[string]core.Object)
+	replaced := make(map[int]bool)
+	var associated core.Array
+	for _, a := range e.attachments {
+		entries[string(textString(a.Name))] = specs[a.Name]
+		if a.Relationship != "" {
+			associated = append(associated, specs[a.Name])
+		}
+	}
+	names, _ := src.Resolve(catalog[core.Name("Names")]).(core.Dictionary)
+	err := src.WalkNameTree(names[core.Name("EmbeddedFiles")], func(key string, value core.Object) error {
+		if _, exists := entries[key]; exists {
+			if ref, ok := value.(*core.Reference); ok {
+				replaced[ref.ObjectNumber] = true
+			}
+			return nil
+		}
+		entries[key] = c.copyObject(value)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read embedded files: %w", err)
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tree := make(core.Array, 0, len(keys)*2)
+	for _, key := range keys {
+		tree = append(tree, core.String(key), entries[key])
+	}
+
+	// 元の/Namesの他の名前ツリー（/Destsなど）はそのまま残す
+	namesCopy := core.Dictionary{}
+	for k, v := range names {
+		if k != core.Name("EmbeddedFiles") {
+			namesCopy[k] = c.copyObject(v)
+		}
+	}
+	namesCopy[core.Name("EmbeddedFiles")] = core.Dictionary{core.Name("Names"): tree}
+	catalogCopy[core.Name("Names")] = namesCopy
+
+	var af core.Array
+	if items, ok := src.Resolve(catalog[core.Name("AF")]).(core.Array); ok {
+		for _, item := range items {
+			if ref, ok := item.(*core.Reference); ok && replaced[ref.ObjectNumber] {
+				continue
+			}
+			af = append(af, c.copyObject(item))
+		}
+	}
+	af = append(af, associated...)
+	if len(af) > 0 {
+		catalogCopy[core.Name("AF")] = af
+	}
+	return nil
+}
+
+// writeAttachmentAnnotations はページに加える添付ファイル注釈を出力し、その参照を返す
+func writeAttachmentAnnotations(w *writer.Writer, page *editorPage, pageRef *core.Reference, specs map[string]*core.Reference, attachments []FileAttachment) (core.Array, error) {
+	var refs core.Array
+	for _, item := range page.attachments {
+		annot := item.annot
+		icon := annot.Icon
+		if icon == "" {
+			icon = "PushPin"
+		}
+		contents := annot.Contents
+		for _, a := range attachments {
+			if contents == "" && a.Name == item.name {
+				contents = a.Description
+			}
+		}
+		if contents == "" {
+			contents = item.name
+		}
+		rect := annot.Rect
+		num, err := w.AddObject(core.Dictionary{
+			core.Name("Type"):     core.Name("Annot"),
+			core.Name("Subtype"):  core.Name("FileAttachment"),
+			core.Name("Rect"):     rectArray(rect.X, rect.Y, rect.Width, rect.Height),
+			core.Name("FS"):       specs[item.name],
+			core.Name("Name"):     core.Name(icon),
+			core.Name("Contents"): textString(contents),
+			core.Name("F"):        core.Integer(annotFlagPrint),
+			core.Name("P"):        pageRef,
+		})
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, &core.Reference{ObjectNumber: num})
+	}
+	return refs, nil
+}
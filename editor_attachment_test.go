@@ -0,0 +1,174 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// editorAttachmentSourcePDF は埋め込みファイル（old.txtとreplace.txt）と付箋の注釈を持つPDFを作成する
+// replace.txtはカタログの/AFにも登録されている
+func editorAttachmentSourcePDF() []byte {
+	stream := func(dict, data string) string {
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+	}
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R /Names 5 0 R /AF [8 0 R] >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 300 200] >>",
+		"<< /Type /Page /Parent 2 0 R /Annots [10 0 R] >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+		"<< /EmbeddedFiles << /Kids [11 0 R] >> /Dests << /Names [(top) [3 0 R /Fit]] >> >>",
+		"<< /Type /Filespec /F (old.txt) /EF << /F 7 0 R >> >>",
+		stream("/Type /EmbeddedFile", "old"),
+		"<< /Type /Filespec /F (replace.txt) /EF << /F 9 0 R >> /AFRelationship /Data >>",
+		stream("/Type /EmbeddedFile", "before"),
+		"<< /Type /Annot /Subtype /Text /Rect [10 10 30 30] /Contents (note) >>",
+		"<< /Names [(old.txt) 6 0 R (replace.txt) 8 0 R] /Limits [(old.txt) (replace.txt)] >>",
+	})
+}
+
+func TestEditor_AttachFile(t *testing.T) {
+	for _, incremental := range []bool{false, true} {
+		t.Run(fmt.Sprintf("incremental=%v", incremental), func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(editorAttachmentSourcePDF()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+			editor, err := NewEditor(source)
+			if err != nil {
+				t.Fatalf("NewEditor failed: %v", err)
+			}
+
+			attachments := []FileAttachment{
+				{Name: "data.csv", Data: []byte("a,b\n1,2\n"), MIMEType: "text/csv", Description: "Source data", Relationship: AFRelationshipSource},
+				{Name: "replace.txt", Data: []byte("after")},
+			}
+			for _, a := range attachments {
+				if err := editor.AttachFile(a); err != nil {
+					t.Fatalf("AttachFile(%s) failed: %v", a.Name, err)
+				}
+			}
+			if err := editor.AddAttachmentAnnotation(0, "data.csv", AttachmentAnnotation{Rect: Rectangle{X: 250, Y: 150, Width: 20, Height: 20}}); err != nil {
+				t.Fatalf("AddAttachmentAnnotation failed: %v", err)
+			}
+
+			output := saveEditor(t, editor, incremental)
+			reader, err := OpenReader(bytes.NewReader(output))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+
+			got, err := reader.ExtractAttachments()
+			if err != nil {
+				t.Fatalf("ExtractAttachments failed: %v", err)
+			}
+			want := map[string]string{"data.csv": "a,b\n1,2\n", "old.txt": "old", "replace.txt": "after"}
+			if len(got) != len(want) {
+				t.Fatalf("got %d attachments, want %d: %+v", len(got), len(want), got)
+			}
+			for i, name := range []string{"data.csv", "old.txt", "replace.txt"} {
+				if got[i].Name != name || string(got[i].Data) != want[name] {
+					t.Errorf("attachment %d = %s %q, want %s %q", i, got[i].Name, got[i].Data, name, want[name])
+				}
+			}
+			if got[0].MIMEType != "text/csv" || got[0].Relationship != AFRelationshipSource {
+				t.Errorf("data.csv = %+v, want its MIME type and relationship", got[0])
+			}
+
+			// /AFは置き換えたreplace.txtを除き、data.csvを加える
+			catalog, err := reader.r.GetCatalog()
+			if err != nil {
+				t.Fatalf("GetCatalog failed: %v", err)
+			}
+			af, _ := reader.r.Resolve(catalog[core.Name("AF")]).(core.Array)
+			if len(af) != 1 {
+				t.Fatalf("/AF = %v, want only data.csv", af)
+			}
+			spec, _ := reader.r.Resolve(af[0]).(core.Dictionary)
+			if name := reader.fileSpecName(spec); name != "data.csv" {
+				t.Errorf("/AF file = %q, want %q", name, "data.csv")
+			}
+			// /Namesの他の名前ツリーは残る
+			names, _ := reader.r.Resolve(catalog[core.Name("Names")]).(core.Dictionary)
+			if _, ok := reader.r.LookupNameTree(names[core.Name("Dests")], "top"); !ok {
+				t.Errorf("named destination %q was lost", "top")
+			}
+
+			annotations, err := reader.ExtractPageAnnotations(0)
+			if err != nil {
+				t.Fatalf("ExtractPageAnnotations failed: %v", err)
+			}
+			if len(annotations) != 2 || annotations[0].Type != AnnotationTypeText {
+				t.Fatalf("annotations = %+v, want the original note and the attachment", annotations)
+			}
+			annot := annotations[1]
+			if annot.Type != AnnotationTypeFileAttachment || annot.Contents != "Source data" ||
+				annot.Rect != (Rectangle{X: 250, Y: 150, Width: 20, Height: 20}) {
+				t.Errorf("attachment annotation = %+v", annot)
+			}
+			if others, _ := reader.ExtractPageAnnotations(1); len(others) != 0 {
+				t.Errorf("page 1 annotations = %+v, want none", others)
+			}
+
+			// 注釈は名前ツリーと同じファイル指定辞書を参照する
+			page, err := reader.r.GetPage(0)
+			if err != nil {
+				t.Fatalf("GetPage failed: %v", err)
+			}
+			annots, _ := reader.r.Resolve(page[core.Name("Annots")]).(core.Array)
+			dict, _ := reader.r.Resolve(annots[1]).(core.Dictionary)
+			fs, ok := dict[core.Name("FS")].(*core.Reference)
+			treeSpec, _ := reader.r.LookupNameTree(names[core.Name("EmbeddedFiles")], "data.csv")
+			if treeRef, isRef := treeSpec.(*core.Reference); !ok || !isRef || fs.ObjectNumber != treeRef.ObjectNumber {
+				t.Errorf("annotation /FS = %v, want the file specification of the name tree %v", dict[core.Name("FS")], treeSpec)
+			}
+
+			if incremental && !bytes.HasPrefix(output, editorAttachmentSourcePDF()) {
+				t.Errorf("incremental save changed the original bytes")
+			}
+		})
+	}
+}
+
+func TestEditor_AddAttachmentAnnotation_Errors(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorAttachmentSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+	if err := editor.AttachFile(FileAttachment{Name: "data.csv", Data: []byte("a")}); err != nil {
+		t.Fatalf("AttachFile failed: %v", err)
+	}
+	rect := Rectangle{X: 10, Y: 10, Width: 20, Height: 20}
+
+	tests := []struct {
+		name    string
+		pageNum int
+		file    string
+		annot   AttachmentAnnotation
+	}{
+		{"page out of range", 2, "data.csv", AttachmentAnnotation{Rect: rect}},
+		{"not attached", 0, "old.txt", AttachmentAnnotation{Rect: rect}},
+		{"empty rect", 0, "data.csv", AttachmentAnnotation{}},
+		{"unknown icon", 0, "data.csv", AttachmentAnnotation{Rect: rect, Icon: "Star"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := editor.AddAttachmentAnnotation(tt.pageNum, tt.file, tt.annot); err == nil {
+				t.Error("AddAttachmentAnnotation should fail")
+			}
+		})
+	}
+
+	if err := editor.AttachFile(FileAttachment{Data: []byte("a")}); err == nil {
+		t.Error("AttachFile should fail without a name")
+	}
+}
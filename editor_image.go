@@ -0,0 +1,412 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+
+	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/internal/core"
+	xdraw "golang.org/x/image/draw"
+)
+
+// PageImage はページのコンテンツストリームで描かれた画像（RemoveImagesで削除する画像を選ぶために使う）
+// 同じ画像を複数回描いた場合は、描いた回数だけ返す
+type PageImage struct {
+	Name             string    // リソース名（例: "Im1"）
+	Width            int       // 画像の幅（ピクセル）
+	Height           int       // 画像の高さ（ピクセル）
+	ColorSpace       string    // 色空間（名前で指定されていない場合は元のPDFでの値）
+	BitsPerComponent int       // ビット深度
+	Filter           string    // 圧縮フィルター
+	Size             int       // 圧縮された画像データのバイト数
+	Rect             Rectangle // 描かれた領域（/Rotateのあるページでは表示される向きの座標）
+	DPI              float64   // 描かれた大きさでの解像度（縦と横の低い方）
+}
+
+// DownsampleOptions はDownsampleImagesの設定
+type DownsampleOptions struct {
+	MaxDPI  float64 // この解像度より高い画像を縮小する（0の場合は150）
+	Quality int     // JPEGの品質 1〜100（0の場合は75）
+}
+
+// pageImage は描かれた画像と、画像XObjectのストリーム
+type pageImage struct {
+	PageImage
+	objNum    int          // 画像XObjectの元のオブジェクト番号
+	operation int          // 画像を描いたDoオペレーション
+	stream    *core.Stream // 画像XObject（縮小した場合は置き換えたもの）
+}
+
+// PageImages はページ（現在のページ番号）のコンテンツストリームで描かれた画像を返す
+// フォームXObjectの中で描かれた画像は含まない。DownsampleImagesで縮小した画像は縮小後の値を返す
+func (e *Editor) PageImages(pageNum int) ([]PageImage, error) {
+	if err := e.checkPage(pageNum); err != nil {
+		return nil, err
+	}
+	images, _, _, err := e.pageImages(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PageImage, len(images))
+	for i, img := range images {
+		result[i] = img.PageImage
+	}
+	return result, nil
+}
+
+// RemoveImages はページ（現在のページ番号）で描かれた画像のうち、selectedがtrueを返したものを削除する
+// 画像を描くオペレーションをコンテンツストリームから除き、ページで描かれなくなった画像はページのリソースからも除く
+// 他のページで描かれている画像は、そのページには残る。pageNumsを省略した場合はすべてのページ
+// SaveIncrementalでは元のバイト列が残るため、ファイルは小さくならない
+// 設計書: docs/editor_design.md
+func (e *Editor) RemoveImages(selected func(PageImage) bool, pageNums ...int) error {
+	pageNums, err := e.targetPages(pageNums)
+	if err != nil {
+		return err
+	}
+
+	done := make(map[int]bool, len(pageNums))
+	for _, pageNum := range pageNums {
+		if done[pageNum] {
+			continue
+		}
+		done[pageNum] = true
+
+		images, data, operations, err := e.pageImages(pageNum)
+		if err != nil {
+			return err
+		}
+		removed := make(map[int][]byte)
+		drawn := make(map[string]bool)
+		for _, img := range images {
+			if selected(img.PageImage) {
+				removed[img.operation] = nil
+			} else {
+				drawn[img.Name] = true
+			}
+		}
+		if len(removed) == 0 {
+			continue
+		}
+
+		rewritten, err := content.RewriteOperations(data, operations, removed)
+		if err != nil {
+			return fmt.Errorf("failed to remove images on page %d: %w", pageNum, err)
+		}
+		page := e.pages[pageNum]
+		page.contents = rewritten
+
+		// 並べ替えで複製したページと共有しないよう、変更するたびに作り直す
+		removedImages := make(map[core.Name]bool, len(page.removedImages)+len(images))
+		for name := range page.removedImages {
+			removedImages[name] = true
+		}
+		for _, img := range images {
+			if !drawn[img.Name] {
+				removedImages[core.Name(img.Name)] = true
+			}
+		}
+		page.removedImages = removedImages
+	}
+	return nil
+}
+
+// DownsampleImages はページ（現在のページ番号）で描かれた画像のうち、opts.MaxDPIより高い解像度で描かれたものを縮小し、
+// JPEGで圧縮し直す（大きなスキャン画像のPDFを小さくする場合など）。pageNumsを省略した場合はすべてのページ
+// 解像度は画像を描いた大きさから求め、複数回描かれた画像は最も低い解像度で判定する
+// 画像XObjectそのものを置き換えるため、指定していないページで同じ画像を描いている場合はそのページの画像も縮小される
+// 縮小してもデータが小さくならない画像、ステンシルマスク（/ImageMask）、透明な部分や展開できない形式（JPXDecodeなど）の画像は変更しない
+func (e *Editor) DownsampleImages(opts DownsampleOptions, pageNums ...int) error {
+	maxDPI := opts.MaxDPI
+	if maxDPI == 0 {
+		maxDPI = 150
+	}
+	quality := opts.Quality
+	if quality == 0 {
+		quality = 75
+	}
+	if maxDPI < 0 {
+		return fmt.Errorf("invalid max DPI: %v", opts.MaxDPI)
+	}
+	if quality < 1 || quality > 100 {
+		return fmt.Errorf("invalid JPEG quality: %d", opts.Quality)
+	}
+	pageNums, err := e.targetPages(pageNums)
+	if err != nil {
+		return err
+	}
+
+	// 画像XObjectごとに、描かれた最も低い解像度と、色空間を解決するためのページのリソースを集める
+	type target struct {
+		image     pageImage
+		resources core.Dictionary
+	}
+	targets := make(map[int]*target)
+	var order []int
+	done := make(map[int]bool, len(pageNums))
+	for _, pageNum := range pageNums {
+		if done[pageNum] {
+			continue
+		}
+		done[pageNum] = true
+
+		images, _, _, err := e.pageImages(pageNum)
+		if err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			continue
+		}
+		dict, err := e.r.r.GetPageByReference(e.pages[pageNum].ref)
+		if err != nil {
+			return fmt.Errorf("failed to read page %d: %w", pageNum, err)
+		}
+		resources, err := e.r.r.GetPageResources(dict)
+		if err != nil {
+			return fmt.Errorf("failed to read resources of page %d: %w", pageNum, err)
+		}
+		for _, img := range images {
+			if t, ok := targets[img.objNum]; ok {
+				if img.DPI < t.image.DPI {
+					t.image = img
+				}
+				continue
+			}
+			targets[img.objNum] = &target{image: img, resources: resources}
+			order = append(order, img.objNum)
+		}
+	}
+
+	for _, objNum := range order {
+		t := targets[objNum]
+		if t.image.DPI <= maxDPI {
+			continue
+		}
+		replaced, err := e.downsampleImage(t.image, t.resources, maxDPI/t.image.DPI, quality)
+		if err != nil {
+			return fmt.Errorf("failed to downsample image %s: %w", t.image.Name, err)
+		}
+		if replaced == nil {
+			continue
+		}
+		if e.images == nil {
+			e.images = make(map[int]core.Object)
+		}
+		for num, obj := range replaced {
+			e.images[num] = obj
+		}
+	}
+	return nil
+}
+
+// downsampleImage は画像をscale倍の大きさに縮小した画像XObjectを、元のオブジェクト番号 -> 置き換える画像で返す
+// /SMaskがある場合は、マスクも同じ大きさに縮小する。変更しない画像の場合はnilを返す
+func (e *Editor) downsampleImage(img pageImage, resources core.Dictionary, scale float64, quality int) (map[int]core.Object, error) {
+	src := e.r.r
+	width := max(int(math.Round(float64(img.Width)*scale)), 1)
+	height := max(int(math.Round(float64(img.Height)*scale)), 1)
+	if width >= img.Width && height >= img.Height {
+		return nil, nil
+	}
+
+	decoded, err := content.DecodeImage(src, img.stream, resources)
+	if err != nil {
+		// ステンシルマスクや展開できない形式の画像は縮小しない
+		return nil, nil
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), decoded, decoded.Bounds(), xdraw.Src, nil)
+
+	gray := true
+	for i := 0; i < len(dst.Pix); i += 4 {
+		// 色空間がRGBに変換できなかった部分は透明になり、JPEGでは表せない
+		if dst.Pix[i+3] != 0xff {
+			return nil, nil
+		}
+		if dst.Pix[i] != dst.Pix[i+1] || dst.Pix[i] != dst.Pix[i+2] {
+			gray = false
+		}
+	}
+	var encoded bytes.Buffer
+	colorSpace := core.Name("DeviceRGB")
+	var out image.Image = dst
+	if gray {
+		colorSpace = core.Name("DeviceGray")
+		out = grayImage(dst)
+	}
+	if err := jpeg.Encode(&encoded, out, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	if encoded.Len() >= len(img.stream.Data) {
+		return nil, nil
+	}
+
+	replaced := make(map[int]core.Object)
+	dict := resampledImageDict(img.stream.Dict, width, height)
+	dict[core.Name("ColorSpace")] = colorSpace
+	dict[core.Name("BitsPerComponent")] = core.Integer(8)
+	dict[core.Name("Filter")] = core.Name("DCTDecode")
+	// 色のキーによるマスク（/Maskの配列）と/Decodeは元のサンプルの値に対するものなので除く
+	if _, ok := src.Resolve(dict[core.Name("Mask")]).(core.Array); ok {
+		delete(dict, core.Name("Mask"))
+	}
+	delete(dict, core.Name("Decode"))
+	replaced[img.objNum] = &core.Stream{Dict: dict, Data: encoded.Bytes()}
+
+	if ref, ok := dict[core.Name("SMask")].(*core.Reference); ok {
+		smask, err := e.downsampleSoftMask(ref.ObjectNumber, resources, width, height)
+		if err != nil {
+			return nil, err
+		}
+		if smask == nil {
+			return nil, nil
+		}
+		replaced[ref.ObjectNumber] = smask
+	}
+	return replaced, nil
+}
+
+// downsampleSoftMask はソフトマスク（/SMaskの画像）をwidth×heightに縮小し、Flateで圧縮した画像を返す
+// /Matteのあるマスクは元の画像と同じ大きさである必要があり、縮小できないためnilを返す
+func (e *Editor) downsampleSoftMask(objNum int, resources core.Dictionary, width, height int) (*core.Stream, error) {
+	stream, ok := e.imageObject(objNum).(*core.Stream)
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := stream.Dict[core.Name("Matte")]; ok {
+		return nil, nil
+	}
+	decoded, err := content.DecodeImage(e.r.r, stream, resources)
+	if err != nil {
+		return nil, nil
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), decoded, decoded.Bounds(), xdraw.Src, nil)
+
+	compressed, err := compressWithZlib(grayImage(dst).Pix)
+	if err != nil {
+		return nil, err
+	}
+	dict := resampledImageDict(stream.Dict, width, height)
+	dict[core.Name("ColorSpace")] = core.Name("DeviceGray")
+	dict[core.Name("BitsPerComponent")] = core.Integer(8)
+	dict[core.Name("Filter")] = core.Name("FlateDecode")
+	delete(dict, core.Name("Decode"))
+	return &core.Stream{Dict: dict, Data: compressed}, nil
+}
+
+// resampledImageDict は画像XObjectの辞書を、大きさを変えて圧縮し直す画像のために複製する
+func resampledImageDict(src core.Dictionary, width, height int) core.Dictionary {
+	dict := make(core.Dictionary, len(src))
+	for k, v := range src {
+		dict[k] = v
+	}
+	delete(dict, core.Name("DecodeParms"))
+	delete(dict, core.Name("Length"))
+	dict[core.Name("Width")] = core.Integer(width)
+	dict[core.Name("Height")] = core.Integer(height)
+	return dict
+}
+
+// grayImage はRGBの画像の赤の値をグレーの画像にする（R、G、Bが等しい画像に使う）
+func grayImage(img *image.NRGBA) *image.Gray {
+	gray := image.NewGray(img.Bounds())
+	for i := range gray.Pix {
+		gray.Pix[i] = img.Pix[i*4]
+	}
+	return gray
+}
+
+// imageObject は元のオブジェクト番号の画像XObjectを返す（縮小した場合は置き換えたもの）
+func (e *Editor) imageObject(objNum int) core.Object {
+	if obj, ok := e.images[objNum]; ok {
+		return obj
+	}
+	obj, err := e.r.r.GetObject(objNum)
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// pageImages はページのコンテンツストリームで描かれた画像を、コンテンツストリームとそのオペレーションとともに返す
+// テキストや画像を置き換えたページでは、置き換えた後のコンテンツストリームを使う
+func (e *Editor) pageImages(pageNum int) ([]pageImage, []byte, []content.Operation, error) {
+	page := e.pages[pageNum]
+	dict, err := e.r.r.GetPageByReference(page.ref)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read page %d: %w", pageNum, err)
+	}
+	data := page.contents
+	if data == nil {
+		if data, err = e.r.r.GetPageContents(dict); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read contents of page %d: %w", pageNum, err)
+		}
+	}
+	operations, err := content.NewStreamParser(data).ParseOperations()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse contents of page %d: %w", pageNum, err)
+	}
+	blocks, err := content.NewImageExtractor(e.r.r).ExtractImagesWithPosition(dict, operations)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to extract images of page %d: %w", pageNum, err)
+	}
+
+	rotation := e.r.pageRotation(dict)
+	width, height := e.r.getPageSize(dict)
+	images := make([]pageImage, 0, len(blocks))
+	for _, block := range blocks {
+		stream, ok := e.imageObject(block.ObjectNumber).(*core.Stream)
+		if !ok {
+			continue
+		}
+		img := pageImage{
+			PageImage: PageImage{
+				Name:             block.Name,
+				Width:            block.Width,
+				Height:           block.Height,
+				ColorSpace:       block.ColorSpace,
+				BitsPerComponent: block.BitsPerComp,
+				Filter:           block.Filter,
+				Size:             len(stream.Data),
+				Rect:             Rectangle{X: block.X, Y: block.Y, Width: block.PlacedWidth, Height: block.PlacedHeight},
+			},
+			objNum:    block.ObjectNumber,
+			operation: block.Operation,
+			stream:    stream,
+		}
+		// 縮小した画像は置き換えた画像の値にする
+		if _, ok := e.images[block.ObjectNumber]; ok {
+			img.Width = intValue(stream.Dict[core.Name("Width")])
+			img.Height = intValue(stream.Dict[core.Name("Height")])
+			img.ColorSpace = nameValue(stream.Dict[core.Name("ColorSpace")])
+			img.BitsPerComponent = intValue(stream.Dict[core.Name("BitsPerComponent")])
+			img.Filter = nameValue(stream.Dict[core.Name("Filter")])
+		}
+		// 画像の1ピクセルの大きさは、単位正方形の辺をCTMで変換した長さから求める（大きさのない画像は0）
+		m := block.Transform
+		if sx, sy := math.Hypot(m.A, m.B), math.Hypot(m.C, m.D); sx > 0 && sy > 0 {
+			img.DPI = math.Min(float64(img.Width)/(sx/72), float64(img.Height)/(sy/72))
+		}
+		if rotation != 0 {
+			img.Rect = rotateRect(img.Rect, rotation, width, height)
+		}
+		images = append(images, img)
+	}
+	return images, data, operations, nil
+}
+
+// intValue は整数のオブジェクトの値を返す（整数でない場合は0）
+func intValue(obj core.Object) int {
+	v, _ := obj.(core.Integer)
+	return int(v)
+}
+
+// nameValue は名前のオブジェクトの値を返す（名前でない場合は空文字列）
+func nameValue(obj core.Object) string {
+	v, _ := obj.(core.Name)
+	return string(v)
+}
@@ -0,0 +1,347 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// editorImageSourcePDF は画像を描くページを持つPDFを作成する
+// Im1は400×400のRGBの画像（/SMaskあり）で、1ページ目と2ページ目に100ptの大きさで描く（288dpi）
+// Im2は10×10のグレーの画像で、1ページ目に100ptの大きさで描く（7.2dpi）。2ページ目は/Rotate 90
+func editorImageSourcePDF() []byte {
+	const size = 400
+	rgb := make([]byte, 0, size*size*3)
+	alpha := make([]byte, 0, size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			rgb = append(rgb, byte(x*255/size), byte(y*255/size), 128)
+			alpha = append(alpha, byte((x+y)*255/(2*size)))
+		}
+	}
+	gray := strings.Repeat("\x80", 10*10)
+
+	stream := func(dict, data string) string {
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+	}
+	image := func(width, height int, colorSpace, extra, data string) string {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 %s", width, height, colorSpace, extra)
+		return stream(dict, data)
+	}
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 300 200] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R /Resources << /XObject << /Im1 7 0 R /Im2 8 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R /Resources << /XObject << /Im1 7 0 R >> >> /Rotate 90 >>",
+		stream("", "q 100 0 0 100 10 20 cm /Im1 Do Q q 100 0 0 100 150 20 cm /Im2 Do Q"),
+		stream("", "q 100 0 0 100 10 20 cm /Im1 Do Q"),
+		image(size, size, "DeviceRGB", "/SMask 9 0 R /Interpolate true", string(rgb)),
+		image(10, 10, "DeviceGray", "", gray),
+		image(size, size, "DeviceGray", "", string(alpha)),
+	})
+}
+
+func TestEditor_PageImages(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorImageSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		pageNum int
+		want    []PageImage
+	}{
+		{
+			name:    "two images",
+			pageNum: 0,
+			want: []PageImage{
+				{Name: "Im1", Width: 400, Height: 400, Rect: Rectangle{X: 10, Y: 20, Width: 100, Height: 100}, DPI: 288},
+				{Name: "Im2", Width: 10, Height: 10, Rect: Rectangle{X: 150, Y: 20, Width: 100, Height: 100}, DPI: 7.2},
+			},
+		},
+		{
+			name:    "rotated page",
+			pageNum: 1,
+			want: []PageImage{
+				{Name: "Im1", Width: 400, Height: 400, Rect: Rectangle{X: 20, Y: 190, Width: 100, Height: 100}, DPI: 288},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := editor.PageImages(tt.pageNum)
+			if err != nil {
+				t.Fatalf("PageImages failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("PageImages() returned %d images, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				img := got[i]
+				if img.Name != want.Name || img.Width != want.Width || img.Height != want.Height {
+					t.Errorf("image %d = %s %dx%d, want %s %dx%d", i, img.Name, img.Width, img.Height, want.Name, want.Width, want.Height)
+				}
+				if !closeTo(img.Rect.X, want.Rect.X) || !closeTo(img.Rect.Y, want.Rect.Y) ||
+					!closeTo(img.Rect.Width, want.Rect.Width) || !closeTo(img.Rect.Height, want.Rect.Height) {
+					t.Errorf("image %d rect = %+v, want %+v", i, img.Rect, want.Rect)
+				}
+				if !closeTo(img.DPI, want.DPI) {
+					t.Errorf("image %d DPI = %v, want %v", i, img.DPI, want.DPI)
+				}
+			}
+		})
+	}
+
+	if _, err := editor.PageImages(2); err == nil {
+		t.Error("PageImages should fail for a page out of range")
+	}
+}
+
+func TestEditor_RemoveImages(t *testing.T) {
+	byName := func(name string) func(PageImage) bool {
+		return func(img PageImage) bool { return img.Name == name }
+	}
+
+	tests := []struct {
+		name string
+		edit func(e *Editor) error
+		// 保存後のページ -> 描かれている画像の名前
+		want map[int][]string
+	}{
+		{
+			name: "one image on all pages",
+			edit: func(e *Editor) error { return e.RemoveImages(byName("Im1")) },
+			want: map[int][]string{0: {"Im2"}, 1: nil},
+		},
+		{
+			name: "shared image on one page",
+			edit: func(e *Editor) error { return e.RemoveImages(byName("Im1"), 0) },
+			want: map[int][]string{0: {"Im2"}, 1: {"Im1"}},
+		},
+		{
+			name: "by resolution",
+			edit: func(e *Editor) error {
+				return e.RemoveImages(func(img PageImage) bool { return img.DPI < 10 })
+			},
+			want: map[int][]string{0: {"Im1"}, 1: {"Im1"}},
+		},
+		{
+			name: "duplicated page keeps its images",
+			edit: func(e *Editor) error {
+				if err := e.DuplicatePage(0); err != nil {
+					return err
+				}
+				return e.RemoveImages(byName("Im2"), 1)
+			},
+			want: map[int][]string{0: {"Im1", "Im2"}, 1: {"Im1"}, 2: {"Im1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, incremental := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/incremental=%v", tt.name, incremental), func(t *testing.T) {
+				source, err := OpenReader(bytes.NewReader(editorImageSourcePDF()))
+				if err != nil {
+					t.Fatalf("Failed to open PDF: %v", err)
+				}
+				defer source.Close()
+				editor, err := NewEditor(source)
+				if err != nil {
+					t.Fatalf("NewEditor failed: %v", err)
+				}
+				if err := tt.edit(editor); err != nil {
+					t.Fatalf("edit failed: %v", err)
+				}
+
+				saved := saveEditor(t, editor, incremental)
+				reader, err := OpenReader(bytes.NewReader(saved))
+				if err != nil {
+					t.Fatalf("Failed to open saved PDF: %v", err)
+				}
+				defer reader.Close()
+				for pageNum, want := range tt.want {
+					if got := imageNames(t, reader, pageNum); strings.Join(got, ",") != strings.Join(want, ",") {
+						t.Errorf("page %d images = %v, want %v", pageNum, got, want)
+					}
+					// 描かれなくなった画像はページのリソースからも除く
+					page, err := reader.r.GetPage(pageNum)
+					if err != nil {
+						t.Fatalf("GetPage(%d) failed: %v", pageNum, err)
+					}
+					resources, _ := reader.r.GetPageResources(page)
+					xobjects, _ := reader.r.Resolve(resources[core.Name("XObject")]).(core.Dictionary)
+					if len(xobjects) != len(want) {
+						t.Errorf("page %d has %d XObjects in its resources, want %d", pageNum, len(xobjects), len(want))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestEditor_DownsampleImages(t *testing.T) {
+	original := editorImageSourcePDF()
+
+	tests := []struct {
+		name     string
+		opts     DownsampleOptions
+		pageNums []int
+		// 画像の名前 -> 縮小後の幅
+		want map[string]int
+	}{
+		{name: "default resolution", opts: DownsampleOptions{}, want: map[string]int{"Im1": 208, "Im2": 10}},
+		{name: "72 dpi", opts: DownsampleOptions{MaxDPI: 72, Quality: 50}, want: map[string]int{"Im1": 100, "Im2": 10}},
+		{name: "high limit", opts: DownsampleOptions{MaxDPI: 300}, want: map[string]int{"Im1": 400, "Im2": 10}},
+		{name: "shared image on a selected page", opts: DownsampleOptions{MaxDPI: 72}, pageNums: []int{1}, want: map[string]int{"Im1": 100, "Im2": 10}},
+	}
+
+	for _, tt := range tests {
+		for _, incremental := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/incremental=%v", tt.name, incremental), func(t *testing.T) {
+				source, err := OpenReader(bytes.NewReader(original))
+				if err != nil {
+					t.Fatalf("Failed to open PDF: %v", err)
+				}
+				defer source.Close()
+				editor, err := NewEditor(source)
+				if err != nil {
+					t.Fatalf("NewEditor failed: %v", err)
+				}
+				if err := editor.DownsampleImages(tt.opts, tt.pageNums...); err != nil {
+					t.Fatalf("DownsampleImages failed: %v", err)
+				}
+
+				saved := saveEditor(t, editor, incremental)
+				reader, err := OpenReader(bytes.NewReader(saved))
+				if err != nil {
+					t.Fatalf("Failed to open saved PDF: %v", err)
+				}
+				defer reader.Close()
+				savedEditor, err := NewEditor(reader)
+				if err != nil {
+					t.Fatalf("NewEditor failed: %v", err)
+				}
+
+				downsampled := false
+				for pageNum := 0; pageNum < 2; pageNum++ {
+					images, err := savedEditor.PageImages(pageNum)
+					if err != nil {
+						t.Fatalf("PageImages(%d) failed: %v", pageNum, err)
+					}
+					for _, img := range images {
+						if img.Width != tt.want[img.Name] {
+							t.Errorf("page %d image %s width = %d, want %d", pageNum, img.Name, img.Width, tt.want[img.Name])
+						}
+						if img.Width == 400 {
+							continue
+						}
+						if img.Name == "Im1" {
+							downsampled = true
+							if img.Filter != "DCTDecode" || img.ColorSpace != "DeviceRGB" {
+								t.Errorf("image %s = %s %s, want DCTDecode DeviceRGB", img.Name, img.Filter, img.ColorSpace)
+							}
+						}
+					}
+				}
+				if !downsampled {
+					return
+				}
+
+				// /SMaskも同じ大きさに縮小し、他の属性は残す
+				page, err := reader.r.GetPage(0)
+				if err != nil {
+					t.Fatalf("GetPage failed: %v", err)
+				}
+				resources, _ := reader.r.GetPageResources(page)
+				xobjects, _ := reader.r.Resolve(resources[core.Name("XObject")]).(core.Dictionary)
+				im1, _ := reader.r.Resolve(xobjects[core.Name("Im1")]).(*core.Stream)
+				if im1 == nil {
+					t.Fatal("Im1 is missing")
+				}
+				if im1.Dict[core.Name("Interpolate")] != core.Boolean(true) {
+					t.Error("/Interpolate was not kept")
+				}
+				smask, _ := reader.r.Resolve(im1.Dict[core.Name("SMask")]).(*core.Stream)
+				if smask == nil || smask.Dict[core.Name("Width")] != im1.Dict[core.Name("Width")] {
+					t.Errorf("/SMask was not downsampled with the image")
+				}
+				if !incremental && len(saved) >= len(original)/4 {
+					t.Errorf("saved PDF is %d bytes, original is %d bytes", len(saved), len(original))
+				}
+			})
+		}
+	}
+}
+
+func TestEditor_DownsampleImages_Errors(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorImageSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     DownsampleOptions
+		pageNums []int
+	}{
+		{name: "negative resolution", opts: DownsampleOptions{MaxDPI: -1}},
+		{name: "quality too high", opts: DownsampleOptions{Quality: 101}},
+		{name: "page out of range", pageNums: []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := editor.DownsampleImages(tt.opts, tt.pageNums...); err == nil {
+				t.Error("DownsampleImages should fail")
+			}
+		})
+	}
+}
+
+// saveEditor はEditorで編集したPDFを保存したバイト列を返す
+func saveEditor(t *testing.T, editor *Editor, incremental bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	if incremental {
+		err = editor.SaveIncremental(&buf)
+	} else {
+		err = editor.Save(&buf)
+	}
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// imageNames はページで描かれている画像の名前を返す
+func imageNames(t *testing.T, reader *PDFReader, pageNum int) []string {
+	t.Helper()
+	editor, err := NewEditor(reader)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+	images, err := editor.PageImages(pageNum)
+	if err != nil {
+		t.Fatalf("PageImages(%d) failed: %v", pageNum, err)
+	}
+	var names []string
+	for _, img := range images {
+		names = append(names, img.Name)
+	}
+	return names
+}
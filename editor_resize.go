@@ -0,0 +1,261 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// PageBox はページの表示される範囲（/CropBoxと/MediaBoxの重なり）を、ページの座標（/Rotateで回す前）で返す
+// ResizePagesで大きさを変えた後は、変更後の座標で返す
+func (e *Editor) PageBox(pageNum int) (Rectangle, error) {
+	if err := e.checkPage(pageNum); err != nil {
+		return Rectangle{}, err
+	}
+	dict, err := e.pageDict(e.pages[pageNum])
+	if err != nil {
+		return Rectangle{}, err
+	}
+	return e.r.pageBoxes(dict).Visible, nil
+}
+
+// CropPages はページの/CropBoxをboxにして、表示・印刷される範囲を切り抜く（スキャンの黒い縁を除くなど）
+// boxはPageBoxと同じページの座標で指定する。コンテンツは変更せず、boxの外側は隠れるだけで残る
+// pageNumsを省略した場合はすべてのページ
+func (e *Editor) CropPages(box Rectangle, pageNums ...int) error {
+	if box.Width <= 0 || box.Height <= 0 {
+		return fmt.Errorf("invalid crop box: %vx%v", box.Width, box.Height)
+	}
+	pageNums, err := e.targetPages(pageNums)
+	if err != nil {
+		return err
+	}
+	for _, pageNum := range pageNums {
+		box := box
+		e.pages[pageNum].cropBox = &box
+	}
+	return nil
+}
+
+// ResizePages はページの用紙の大きさをsizeにし、表示される範囲のコンテンツを縦横比を保って拡大縮小して中央に置く
+// （A4のページをLetterにする場合など）。sizeは表示される向きの大きさで、/Rotateで回したページは縦横を入れ替えて適用する
+// 注釈の位置も合わせて移動する。pageNumsを省略した場合はすべてのページ
+func (e *Editor) ResizePages(size PageSize, pageNums ...int) error {
+	if size.Width <= 0 || size.Height <= 0 {
+		return fmt.Errorf("invalid page size: %vx%v", size.Width, size.Height)
+	}
+	return e.TransformPages(PageTransform{Size: size}, pageNums...)
+}
+
+// PageTransform はTransformPagesでページに適用する変換
+// 大きさ・余白・移動量は表示される向き（/Rotateで回した後）で指定する
+type PageTransform struct {
+	Size PageSize // 新しい用紙の大きさ（ゼロ値の場合は表示される範囲の大きさのまま）
+
+	// 内容を置く範囲の、用紙の端からの余白
+	MarginTop, MarginRight, MarginBottom, MarginLeft float64
+
+	Scale            float64 // 内容の拡大率（0の場合は余白の内側に収まるように縦横比を保って拡大縮小する）
+	OffsetX, OffsetY float64 // 余白の内側の中央に置いた内容を動かす量（右・上が正）
+}
+
+// TransformPages はページの用紙の大きさを変え、表示される範囲のコンテンツを拡大縮小して余白の内側の中央に置き、さらに動かす
+// 用紙の大きさの変換、綴じ代の追加、印刷位置の調整などに使う。注釈の位置も合わせて移動する
+// 変換後の用紙の外に出た内容は表示されない。pageNumsを省略した場合はすべてのページ
+func (e *Editor) TransformPages(t PageTransform, pageNums ...int) error {
+	if t.Size != (PageSize{}) && (t.Size.Width <= 0 || t.Size.Height <= 0) {
+		return fmt.Errorf("invalid page size: %vx%v", t.Size.Width, t.Size.Height)
+	}
+	if t.MarginTop < 0 || t.MarginRight < 0 || t.MarginBottom < 0 || t.MarginLeft < 0 {
+		return fmt.Errorf("margins must not be negative")
+	}
+	if t.Scale < 0 {
+		return fmt.Errorf("invalid scale: %v", t.Scale)
+	}
+	pageNums, err := e.targetPages(pageNums)
+	if err != nil {
+		return err
+	}
+
+	// 確認を終えてからページを変更する
+	done := make(map[int]bool, len(pageNums))
+	transforms := make(map[int][6]float64, len(pageNums))
+	boxes := make(map[int]Rectangle, len(pageNums))
+	for _, pageNum := range pageNums {
+		if done[pageNum] {
+			continue
+		}
+		done[pageNum] = true
+		dict, err := e.pageDict(e.pages[pageNum])
+		if err != nil {
+			return fmt.Errorf("failed to read page %d: %w", pageNum, err)
+		}
+		visible := e.r.pageBoxes(dict).Visible
+		if visible.Width <= 0 || visible.Height <= 0 {
+			return fmt.Errorf("page %d has an empty page box", pageNum)
+		}
+		m, box, err := t.matrix(e.r.pageRotation(dict), visible)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", pageNum, err)
+		}
+		transforms[pageNum] = m
+		boxes[pageNum] = box
+	}
+
+	for pageNum, m := range transforms {
+		page := e.pages[pageNum]
+		if page.transform != nil {
+			m = multiplyMatrix(*page.transform, m)
+		}
+		box := boxes[pageNum]
+		page.transform = &m
+		page.mediaBox = &box
+		page.cropBox = &box
+	}
+	return nil
+}
+
+// matrix は表示される範囲visibleを変換後の用紙 (0, 0)-(width, height) に置く変換行列と、変換後の用紙をページの座標で返す
+// rotationはページの/Rotateで、表示される向きの余白と移動量をページの座標の向きに直して使う
+func (t PageTransform) matrix(rotation int, visible Rectangle) ([6]float64, Rectangle, error) {
+	width, height := visible.Width, visible.Height
+	if t.Size != (PageSize{}) {
+		width, height = rotatePageSize(rotation, t.Size.Width, t.Size.Height)
+	}
+
+	// ページの座標の左・下・右・上の余白と、右・上への移動量
+	left, bottom, right, top := t.MarginLeft, t.MarginBottom, t.MarginRight, t.MarginTop
+	dx, dy := t.OffsetX, t.OffsetY
+	switch rotation {
+	case 90: // 表示される向きの右がページの上、上がページの左
+		left, bottom, right, top = t.MarginTop, t.MarginLeft, t.MarginBottom, t.MarginRight
+		dx, dy = -t.OffsetY, t.OffsetX
+	case 180:
+		left, bottom, right, top = t.MarginRight, t.MarginTop, t.MarginLeft, t.MarginBottom
+		dx, dy = -t.OffsetX, -t.OffsetY
+	case 270: // 表示される向きの右がページの下、上がページの右
+		left, bottom, right, top = t.MarginBottom, t.MarginRight, t.MarginTop, t.MarginLeft
+		dx, dy = t.OffsetY, -t.OffsetX
+	}
+	areaWidth, areaHeight := width-left-right, height-bottom-top
+	if areaWidth <= 0 || areaHeight <= 0 {
+		return [6]float64{}, Rectangle{}, fmt.Errorf("margins leave no space for the content on a %vx%v page", width, height)
+	}
+
+	scale := t.Scale
+	if scale == 0 {
+		scale = math.Min(areaWidth/visible.Width, areaHeight/visible.Height)
+	}
+	m := [6]float64{
+		scale, 0, 0, scale,
+		left + (areaWidth-visible.Width*scale)/2 - visible.X*scale + dx,
+		bottom + (areaHeight-visible.Height*scale)/2 - visible.Y*scale + dy,
+	}
+	return m, Rectangle{Width: width, Height: height}, nil
+}
+
+// targetPages はページ番号を確認して返す。省略した場合はすべてのページ
+func (e *Editor) targetPages(pageNums []int) ([]int, error) {
+	if len(pageNums) == 0 {
+		for i := range e.pages {
+			pageNums = append(pageNums, i)
+		}
+	}
+	for _, pageNum := range pageNums {
+		if err := e.checkPage(pageNum); err != nil {
+			return nil, err
+		}
+	}
+	return pageNums, nil
+}
+
+// multiplyMatrix はmを適用してからnを適用する変換行列を返す
+func multiplyMatrix(m, n [6]float64) [6]float64 {
+	return [6]float64{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// transformAnnotations はページの注釈の位置をmで変換する
+// 直接の注釈辞書は変換した配列に置き換え、参照している注釈は出力するときに変換した辞書を使う（オブジェクト番号は変えない）
+func transformAnnotations(c *objectCopier, dict core.Dictionary, m [6]float64) {
+	items, ok := c.src.Resolve(dict[core.Name("Annots")]).(core.Array)
+	if !ok {
+		return
+	}
+	annots := make(core.Array, len(items))
+	for i, item := range items {
+		annots[i] = item
+		annot, ok := c.src.Resolve(item).(core.Dictionary)
+		if !ok {
+			continue
+		}
+		if ref, isRef := item.(*core.Reference); isRef {
+			if c.replace == nil {
+				c.replace = make(map[int]core.Object)
+			}
+			c.replace[ref.ObjectNumber] = transformAnnotation(c.src, annot, m)
+		} else {
+			annots[i] = transformAnnotation(c.src, annot, m)
+		}
+	}
+	dict[core.Name("Annots")] = annots
+}
+
+// transformAnnotation は注釈の/Rectと座標の配列（/QuadPoints、/L、/Vertices、/InkList）をmで変換した複製を返す
+// 外観ストリームは/Rectに合わせて描かれるため、変換しない
+func transformAnnotation(src *reader.Reader, annot core.Dictionary, m [6]float64) core.Dictionary {
+	point := func(x, y float64) (float64, float64) {
+		return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+	}
+	points := func(obj core.Object) (core.Array, bool) {
+		arr, ok := src.Resolve(obj).(core.Array)
+		if !ok {
+			return nil, false
+		}
+		out := make(core.Array, len(arr))
+		for i := 0; i+1 < len(arr); i += 2 {
+			x, y := point(toFloat64(src.Resolve(arr[i])), toFloat64(src.Resolve(arr[i+1])))
+			out[i], out[i+1] = core.Real(x), core.Real(y)
+		}
+		if len(arr)%2 == 1 {
+			out[len(arr)-1] = arr[len(arr)-1]
+		}
+		return out, true
+	}
+
+	out := make(core.Dictionary, len(annot))
+	for k, v := range annot {
+		out[k] = v
+	}
+	if rect, ok := src.Resolve(annot[core.Name("Rect")]).(core.Array); ok && len(rect) >= 4 {
+		x1, y1 := point(toFloat64(src.Resolve(rect[0])), toFloat64(src.Resolve(rect[1])))
+		x2, y2 := point(toFloat64(src.Resolve(rect[2])), toFloat64(src.Resolve(rect[3])))
+		out[core.Name("Rect")] = core.Array{
+			core.Real(math.Min(x1, x2)), core.Real(math.Min(y1, y2)),
+			core.Real(math.Max(x1, x2)), core.Real(math.Max(y1, y2)),
+		}
+	}
+	for _, key := range []string{"QuadPoints", "L", "Vertices"} {
+		if arr, ok := points(annot[core.Name(key)]); ok {
+			out[core.Name(key)] = arr
+		}
+	}
+	if ink, ok := src.Resolve(annot[core.Name("InkList")]).(core.Array); ok {
+		paths := make(core.Array, 0, len(ink))
+		for _, path := range ink {
+			if arr, ok := points(path); ok {
+				paths = append(paths, arr)
+			}
+		}
+		out[core.Name("InkList")] = paths
+	}
+	return out
+}
@@ -0,0 +1,285 @@
+package gopdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEditor_CropAndResizePages(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(e *Editor) error
+		// 保存後の各ページの表示される大きさ
+		wantSizes []PageSize
+		// 保存後のページ -> テキストの位置と大きさ（表示される向きの座標）
+		wantText map[int][3]float64
+		// 保存後のページ -> リンク注釈の/Rect
+		wantLinks map[int]Rectangle
+	}{
+		{
+			name:      "unchanged",
+			edit:      func(e *Editor) error { return nil },
+			wantSizes: []PageSize{{300, 200}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{0: {20, 150, 12}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "crop",
+			edit: func(e *Editor) error {
+				return e.CropPages(Rectangle{X: 10, Y: 100, Width: 150, Height: 80}, 0, 1)
+			},
+			wantSizes: []PageSize{{150, 80}, {150, 80}, {200, 300}},
+			wantText:  map[int][3]float64{0: {20, 150, 12}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "resize",
+			edit: func(e *Editor) error { return e.ResizePages(PageSize{Width: 600, Height: 900}) },
+			// 300x200のページは2倍にして上下の中央に置き、回転したページは縦横を入れ替えて3倍にする
+			wantSizes: []PageSize{{600, 900}, {600, 900}, {600, 900}},
+			wantText:  map[int][3]float64{0: {40, 550, 24}, 2: {450, 840, 36}},
+			wantLinks: map[int]Rectangle{1: {X: 40, Y: 530, Width: 120, Height: 50}},
+		},
+		{
+			name: "crop then resize",
+			edit: func(e *Editor) error {
+				if err := e.CropPages(Rectangle{Y: 100, Width: 150, Height: 100}, 0); err != nil {
+					return err
+				}
+				return e.ResizePages(PageSize{Width: 300, Height: 200}, 0)
+			},
+			wantSizes: []PageSize{{300, 200}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{0: {40, 100, 24}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "resize twice",
+			edit: func(e *Editor) error {
+				if err := e.ResizePages(PageSize{Width: 600, Height: 400}, 0); err != nil {
+					return err
+				}
+				return e.ResizePages(PageSize{Width: 150, Height: 100}, 0)
+			},
+			wantSizes: []PageSize{{150, 100}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{0: {10, 75, 6}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "margins",
+			edit: func(e *Editor) error {
+				return e.TransformPages(PageTransform{MarginTop: 20, MarginRight: 20, MarginBottom: 20, MarginLeft: 20}, 0, 1)
+			},
+			// 260x160の範囲に収まるよう0.8倍にして、左右の中央に置く
+			wantSizes: []PageSize{{300, 200}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{0: {46, 140, 9.6}},
+			wantLinks: map[int]Rectangle{1: {X: 46, Y: 132, Width: 48, Height: 20}},
+		},
+		{
+			name: "shift",
+			edit: func(e *Editor) error {
+				return e.TransformPages(PageTransform{Scale: 1, OffsetX: 10, OffsetY: -5}, 0, 2)
+			},
+			// 回転したページも表示される向きで右に10、下に5動かす
+			wantSizes: []PageSize{{300, 200}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{0: {30, 145, 12}, 2: {160, 275, 12}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "new size with a binding margin",
+			edit: func(e *Editor) error {
+				return e.TransformPages(PageTransform{Size: PageSize{Width: 600, Height: 900}, MarginLeft: 100, Scale: 1}, 0)
+			},
+			wantSizes: []PageSize{{600, 900}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{0: {220, 500, 12}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "margin on a rotated page",
+			edit: func(e *Editor) error { return e.TransformPages(PageTransform{MarginTop: 50}, 2) },
+			// 表示される向きの上の余白はページの座標の左の余白になり、250x200の範囲に収まるよう5/6倍にする
+			wantSizes: []PageSize{{300, 200}, {300, 200}, {200, 300}},
+			wantText:  map[int][3]float64{2: {425.0 / 3, 700.0 / 3, 10}},
+			wantLinks: map[int]Rectangle{1: {X: 20, Y: 140, Width: 60, Height: 25}},
+		},
+		{
+			name: "resize only the duplicate",
+			edit: func(e *Editor) error {
+				if err := e.DuplicatePage(1); err != nil {
+					return err
+				}
+				return e.ResizePages(PageSize{Width: 600, Height: 400}, 2)
+			},
+			wantSizes: []PageSize{{300, 200}, {300, 200}, {600, 400}, {200, 300}},
+			wantLinks: map[int]Rectangle{
+				1: {X: 20, Y: 140, Width: 60, Height: 25},
+				2: {X: 40, Y: 280, Width: 120, Height: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+
+			editor, err := NewEditor(source)
+			if err != nil {
+				t.Fatalf("NewEditor failed: %v", err)
+			}
+			if err := tt.edit(editor); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := editor.Save(&buf); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+
+			if got := reader.PageCount(); got != len(tt.wantSizes) {
+				t.Fatalf("saved PageCount = %d, want %d", got, len(tt.wantSizes))
+			}
+			for i, want := range tt.wantSizes {
+				layout, err := reader.ExtractPageLayout(i)
+				if err != nil {
+					t.Fatalf("ExtractPageLayout(%d) failed: %v", i, err)
+				}
+				visible := layout.Boxes.Visible
+				if !closeTo(visible.Width, want.Width) || !closeTo(visible.Height, want.Height) {
+					t.Errorf("page %d size = %vx%v, want %vx%v", i, visible.Width, visible.Height, want.Width, want.Height)
+				}
+			}
+
+			for i, want := range tt.wantText {
+				elements, err := reader.ExtractPageTextElements(i)
+				if err != nil {
+					t.Fatalf("ExtractPageTextElements(%d) failed: %v", i, err)
+				}
+				if len(elements) != 1 {
+					t.Fatalf("page %d text elements = %d, want 1", i, len(elements))
+				}
+				got := [3]float64{elements[0].X, elements[0].Y, elements[0].Size}
+				if !closeTo(got[0], want[0]) || !closeTo(got[1], want[1]) || !closeTo(got[2], want[2]) {
+					t.Errorf("page %d text (x, y, size) = %v, want %v", i, got, want)
+				}
+			}
+
+			for i, want := range tt.wantLinks {
+				annotations, err := reader.ExtractPageAnnotations(i)
+				if err != nil {
+					t.Fatalf("ExtractPageAnnotations(%d) failed: %v", i, err)
+				}
+				found := false
+				for _, a := range annotations {
+					if a.Type != AnnotationTypeLink {
+						continue
+					}
+					found = true
+					if !closeTo(a.Rect.X, want.X) || !closeTo(a.Rect.Y, want.Y) ||
+						!closeTo(a.Rect.Width, want.Width) || !closeTo(a.Rect.Height, want.Height) {
+						t.Errorf("page %d link rect = %+v, want %+v", i, a.Rect, want)
+					}
+				}
+				if !found {
+					t.Errorf("page %d has no link", i)
+				}
+			}
+		})
+	}
+}
+
+func TestEditor_PageBox(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+
+	steps := []struct {
+		name string
+		edit func() error
+		want Rectangle
+	}{
+		{name: "original", edit: func() error { return nil }, want: Rectangle{Width: 300, Height: 200}},
+		{
+			name: "crop",
+			edit: func() error { return editor.CropPages(Rectangle{X: 10, Y: 20, Width: 100, Height: 50}, 0) },
+			want: Rectangle{X: 10, Y: 20, Width: 100, Height: 50},
+		},
+		{
+			name: "crop outside the media box",
+			edit: func() error { return editor.CropPages(Rectangle{X: 250, Y: 150, Width: 100, Height: 100}, 0) },
+			want: Rectangle{X: 250, Y: 150, Width: 50, Height: 50},
+		},
+		{
+			name: "resize",
+			edit: func() error { return editor.ResizePages(PageSize{Width: 612, Height: 792}, 0) },
+			want: Rectangle{Width: 612, Height: 792},
+		},
+		{
+			name: "crop after resize",
+			edit: func() error { return editor.CropPages(Rectangle{X: 36, Y: 36, Width: 540, Height: 720}) },
+			want: Rectangle{X: 36, Y: 36, Width: 540, Height: 720},
+		},
+	}
+	for _, step := range steps {
+		if err := step.edit(); err != nil {
+			t.Fatalf("%s: edit failed: %v", step.name, err)
+		}
+		if got, err := editor.PageBox(0); err != nil || got != step.want {
+			t.Errorf("%s: PageBox(0) = %+v, %v, want %+v", step.name, got, err, step.want)
+		}
+	}
+}
+
+func TestEditor_TransformPages_Errors(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		transform PageTransform
+		pageNums  []int
+	}{
+		{"invalid size", PageTransform{Size: PageSize{Width: 600}}, nil},
+		{"negative margin", PageTransform{MarginLeft: -10}, nil},
+		{"negative scale", PageTransform{Scale: -1}, nil},
+		{"page out of range", PageTransform{Scale: 1}, []int{3}},
+		// 1ページ目には収まるが、回転した3ページ目は左の余白が用紙の幅（200）を超える
+		{"margins larger than a page", PageTransform{MarginLeft: 250}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := editor.TransformPages(tt.transform, tt.pageNums...); err == nil {
+				t.Error("TransformPages should fail")
+			}
+			// エラーの場合はどのページも変更しない
+			if box, err := editor.PageBox(0); err != nil || box != (Rectangle{Width: 300, Height: 200}) || editor.pages[0].transform != nil {
+				t.Errorf("page 0 was changed: %+v, %v", box, err)
+			}
+		})
+	}
+}
+
+// closeTo は座標の比較で丸め誤差を許す
+func closeTo(got, want float64) bool {
+	return got > want-0.01 && got < want+0.01
+}
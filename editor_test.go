@@ -0,0 +1,442 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// editorSourcePDF は "One", "Two", "Three" の3ページと添付ファイル、タイトルを持つPDFを作成する
+// 2ページ目には3ページ目へのリンク注釈とテキスト注釈があり、3ページ目は90度回転している
+func editorSourcePDF() []byte {
+	stream := func(data string) string {
+		return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+	}
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R /Names << /EmbeddedFiles << /Names [(data.txt) 12 0 R] >> >> >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 /MediaBox [0 0 300 200] /Resources << /Font << /F1 6 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 7 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 8 0 R /Annots [10 0 R 11 0 R] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 9 0 R /Rotate 90 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		stream("BT /F1 12 Tf 20 150 Td (One) Tj ET"),
+		stream("BT /F1 12 Tf 20 150 Td (Two) Tj ET"),
+		stream("BT /F1 12 Tf 20 150 Td (Three) Tj ET"),
+		"<< /Type /Annot /Subtype /Link /Rect [20 140 80 165] /P 4 0 R /Dest [5 0 R /Fit] >>",
+		"<< /Type /Annot /Subtype /Text /Rect [100 100 120 120] /P 4 0 R /Contents (Note) >>",
+		"<< /Type /Filespec /F (data.txt) /UF (data.txt) /EF << /F 13 0 R >> >>",
+		"<< /Type /EmbeddedFile /Length 5 >>\nstream\nhello\nendstream",
+		"<< /Title (Report) >>",
+	})
+	// Info辞書を参照するようにトレーラーを書き換える
+	return bytes.Replace(pdf, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 14 0 R"), 1)
+}
+
+func TestEditor(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      func(e *Editor) error
+		wantTexts []string
+		// テキスト注釈のあるページ -> リンクの移動先ページ（-1は移動先が削除されたもの）
+		wantLinks map[int]int
+	}{
+		{
+			name:      "unchanged",
+			edit:      func(e *Editor) error { return nil },
+			wantTexts: []string{"One", "Two", "Three"},
+			wantLinks: map[int]int{1: 2},
+		},
+		{
+			name:      "delete first page",
+			edit:      func(e *Editor) error { return e.DeletePages(0) },
+			wantTexts: []string{"Two", "Three"},
+			wantLinks: map[int]int{0: 1},
+		},
+		{
+			name:      "delete link target",
+			edit:      func(e *Editor) error { return e.DeletePages(2) },
+			wantTexts: []string{"One", "Two"},
+			wantLinks: map[int]int{1: -1},
+		},
+		{
+			name:      "duplicate",
+			edit:      func(e *Editor) error { return e.DuplicatePage(1) },
+			wantTexts: []string{"One", "Two", "Two", "Three"},
+			wantLinks: map[int]int{1: 3, 2: 3},
+		},
+		{
+			name:      "move last to first",
+			edit:      func(e *Editor) error { return e.MovePage(2, 0) },
+			wantTexts: []string{"Three", "One", "Two"},
+			wantLinks: map[int]int{2: 0},
+		},
+		{
+			name:      "move first to last",
+			edit:      func(e *Editor) error { return e.MovePage(0, 2) },
+			wantTexts: []string{"Two", "Three", "One"},
+			wantLinks: map[int]int{0: 1},
+		},
+		{
+			name:      "reorder",
+			edit:      func(e *Editor) error { return e.ReorderPages([]int{2, 1}) },
+			wantTexts: []string{"Three", "Two"},
+			wantLinks: map[int]int{1: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+
+			editor, err := NewEditor(source)
+			if err != nil {
+				t.Fatalf("NewEditor failed: %v", err)
+			}
+			if err := tt.edit(editor); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			if got := editor.PageCount(); got != len(tt.wantTexts) {
+				t.Errorf("PageCount = %d, want %d", got, len(tt.wantTexts))
+			}
+			var buf bytes.Buffer
+			if err := editor.Save(&buf); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+
+			if got := reader.PageCount(); got != len(tt.wantTexts) {
+				t.Fatalf("saved PageCount = %d, want %d", got, len(tt.wantTexts))
+			}
+			for i, want := range tt.wantTexts {
+				text, err := reader.ExtractPageText(i)
+				if err != nil {
+					t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+				}
+				if !strings.Contains(text, want) {
+					t.Errorf("page %d text = %q, want to contain %q", i, text, want)
+				}
+
+				annotations, err := reader.ExtractPageAnnotations(i)
+				if err != nil {
+					t.Fatalf("ExtractPageAnnotations(%d) failed: %v", i, err)
+				}
+				wantDest, hasLink := tt.wantLinks[i]
+				if !hasLink {
+					if len(annotations) != 0 {
+						t.Errorf("page %d annotations = %+v, want none", i, annotations)
+					}
+					continue
+				}
+				if len(annotations) != 2 {
+					t.Fatalf("page %d annotations = %d, want 2", i, len(annotations))
+				}
+				for _, a := range annotations {
+					if a.Type != AnnotationTypeLink {
+						continue
+					}
+					if a.Destination == nil || a.Destination.PageNum != wantDest {
+						t.Errorf("page %d link destination = %+v, want page %d", i, a.Destination, wantDest)
+					}
+				}
+			}
+
+			// 文書全体の情報（メタデータと添付ファイル）は残る
+			if got := reader.Info().Title; got != "Report" {
+				t.Errorf("Title = %q, want %q", got, "Report")
+			}
+			attachments, err := reader.ExtractAttachments()
+			if err != nil {
+				t.Fatalf("ExtractAttachments failed: %v", err)
+			}
+			if len(attachments) != 1 || string(attachments[0].Data) != "hello" {
+				t.Errorf("attachments = %+v, want data.txt", attachments)
+			}
+		})
+	}
+}
+
+func TestEditor_RotatePages(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(e *Editor) error
+		want []int
+	}{
+		{
+			name: "unchanged",
+			edit: func(e *Editor) error { return nil },
+			want: []int{0, 0, 90},
+		},
+		{
+			name: "rotate all pages",
+			edit: func(e *Editor) error { return e.RotatePages(90) },
+			want: []int{90, 90, 180},
+		},
+		{
+			name: "rotate counterclockwise",
+			edit: func(e *Editor) error { return e.RotatePages(-90, 0, 2) },
+			want: []int{270, 0, 0},
+		},
+		{
+			name: "page listed twice is rotated once",
+			edit: func(e *Editor) error { return e.RotatePages(180, 1, 1) },
+			want: []int{0, 180, 90},
+		},
+		{
+			name: "set rotation",
+			edit: func(e *Editor) error { return e.SetPageRotation(450, 0, 2) },
+			want: []int{90, 0, 90},
+		},
+		{
+			name: "reset rotation",
+			edit: func(e *Editor) error { return e.SetPageRotation(0) },
+			want: []int{0, 0, 0},
+		},
+		{
+			name: "rotate only the duplicate",
+			edit: func(e *Editor) error {
+				if err := e.DuplicatePage(2); err != nil {
+					return err
+				}
+				return e.RotatePages(90, 3)
+			},
+			want: []int{0, 0, 90, 180},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+
+			editor, err := NewEditor(source)
+			if err != nil {
+				t.Fatalf("NewEditor failed: %v", err)
+			}
+			if err := tt.edit(editor); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			for i, want := range tt.want {
+				if got, err := editor.PageRotation(i); err != nil || got != want {
+					t.Errorf("PageRotation(%d) = %d, %v, want %d", i, got, err, want)
+				}
+			}
+			var buf bytes.Buffer
+			if err := editor.Save(&buf); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+			refs, err := reader.r.GetPageReferences()
+			if err != nil {
+				t.Fatalf("Failed to get pages: %v", err)
+			}
+			if len(refs) != len(tt.want) {
+				t.Fatalf("saved pages = %d, want %d", len(refs), len(tt.want))
+			}
+			for i, want := range tt.want {
+				dict, err := reader.r.GetPageByReference(refs[i])
+				if err != nil {
+					t.Fatalf("Failed to read page %d: %v", i, err)
+				}
+				if got := reader.pageRotation(dict); got != want {
+					t.Errorf("saved page %d rotation = %d, want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEditor_SaveIncremental(t *testing.T) {
+	tests := []struct {
+		name      string
+		edit      func(e *Editor) error
+		wantTexts []string
+		wantLinks map[int]int // リンク注釈のあるページ -> 移動先のページ
+		// 追記したオブジェクトの数（-1は確認しない）
+		wantObjects int
+	}{
+		{
+			name:        "unchanged",
+			edit:        func(e *Editor) error { return nil },
+			wantTexts:   []string{"One", "Two", "Three"},
+			wantLinks:   map[int]int{1: 2},
+			wantObjects: 0,
+		},
+		{
+			name:      "rotate one page",
+			edit:      func(e *Editor) error { return e.RotatePages(90, 0) },
+			wantTexts: []string{"One", "Two", "Three"},
+			wantLinks: map[int]int{1: 2},
+			// ページ順が変わらないため、回転したページだけを書き換える
+			wantObjects: 1,
+		},
+		{
+			name:        "move last to first",
+			edit:        func(e *Editor) error { return e.MovePage(2, 0) },
+			wantTexts:   []string{"Three", "One", "Two"},
+			wantLinks:   map[int]int{2: 0},
+			wantObjects: -1,
+		},
+		{
+			name:        "duplicate",
+			edit:        func(e *Editor) error { return e.DuplicatePage(1) },
+			wantTexts:   []string{"One", "Two", "Two", "Three"},
+			wantLinks:   map[int]int{1: 3, 2: 3},
+			wantObjects: -1,
+		},
+		{
+			name:      "resize page with annotations",
+			edit:      func(e *Editor) error { return e.ResizePages(PageSize{Width: 600, Height: 400}, 1) },
+			wantTexts: []string{"One", "Two", "Three"},
+			wantLinks: map[int]int{1: 2},
+			// ページ、注釈2つ、コンテンツの前後のストリーム
+			wantObjects: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := editorSourcePDF()
+			source, err := OpenReader(bytes.NewReader(original))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+
+			editor, err := NewEditor(source)
+			if err != nil {
+				t.Fatalf("NewEditor failed: %v", err)
+			}
+			if err := tt.edit(editor); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := editor.SaveIncremental(&buf); err != nil {
+				t.Fatalf("SaveIncremental failed: %v", err)
+			}
+
+			if !bytes.HasPrefix(buf.Bytes(), original) {
+				t.Fatal("the original bytes were changed")
+			}
+			update := buf.Bytes()[len(original):]
+			if !bytes.Contains(update, []byte("/Prev ")) {
+				t.Error("update has no /Prev")
+			}
+			if got := bytes.Count(update, []byte(" obj\n")); tt.wantObjects >= 0 && got != tt.wantObjects {
+				t.Errorf("appended objects = %d, want %d", got, tt.wantObjects)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+			if reader.Repaired() {
+				t.Error("saved PDF needed repair")
+			}
+
+			if got := reader.PageCount(); got != len(tt.wantTexts) {
+				t.Fatalf("saved PageCount = %d, want %d", got, len(tt.wantTexts))
+			}
+			for i, want := range tt.wantTexts {
+				text, err := reader.ExtractPageText(i)
+				if err != nil {
+					t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+				}
+				if !strings.Contains(text, want) {
+					t.Errorf("page %d text = %q, want to contain %q", i, text, want)
+				}
+			}
+			for i, wantDest := range tt.wantLinks {
+				annotations, err := reader.ExtractPageAnnotations(i)
+				if err != nil {
+					t.Fatalf("ExtractPageAnnotations(%d) failed: %v", i, err)
+				}
+				for _, a := range annotations {
+					if a.Type == AnnotationTypeLink && (a.Destination == nil || a.Destination.PageNum != wantDest) {
+						t.Errorf("page %d link destination = %+v, want page %d", i, a.Destination, wantDest)
+					}
+				}
+			}
+			if got := reader.Info().Title; got != "Report" {
+				t.Errorf("Title = %q, want %q", got, "Report")
+			}
+		})
+	}
+}
+
+func TestEditor_SaveIncremental_Encrypted(t *testing.T) {
+	data := buildEncryptedPDF(t, &EncryptionOptions{UserPassword: "", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128})
+	source, err := OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	if err := source.AuthenticateWithPassword(""); err != nil {
+		t.Fatalf("AuthenticateWithPassword failed: %v", err)
+	}
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+	if err := editor.SaveIncremental(&bytes.Buffer{}); err == nil {
+		t.Error("expected error for an encrypted PDF")
+	}
+}
+
+func TestEditor_Errors(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		edit func() error
+	}{
+		{name: "delete out of range", edit: func() error { return editor.DeletePages(3) }},
+		{name: "delete all pages", edit: func() error { return editor.DeletePages(0, 1, 2) }},
+		{name: "duplicate negative", edit: func() error { return editor.DuplicatePage(-1) }},
+		{name: "move out of range", edit: func() error { return editor.MovePage(0, 3) }},
+		{name: "empty order", edit: func() error { return editor.ReorderPages(nil) }},
+		{name: "rotate by 45 degrees", edit: func() error { return editor.RotatePages(45) }},
+		{name: "rotate out of range", edit: func() error { return editor.RotatePages(90, 0, 3) }},
+		{name: "set rotation out of range", edit: func() error { return editor.SetPageRotation(90, -1) }},
+		{name: "empty crop box", edit: func() error { return editor.CropPages(Rectangle{Width: 100}) }},
+		{name: "crop out of range", edit: func() error { return editor.CropPages(Rectangle{Width: 10, Height: 10}, 3) }},
+		{name: "resize to zero", edit: func() error { return editor.ResizePages(PageSize{Width: 100}) }},
+		{name: "resize out of range", edit: func() error { return editor.ResizePages(PageSizeA4, 0, 5) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.edit(); err == nil {
+				t.Error("expected error")
+			}
+			if got := editor.PageCount(); got != 3 {
+				t.Errorf("PageCount = %d after failed edit, want 3", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+	"github.com/ryomak/gopdf/layout"
+)
+
+// ReplaceSearchResult は検索結果（PDFReader.Search、SearchPage）の文字列を、コンテンツストリームの中でtextに置き換える
+// result.PageはEditorのページ番号として扱い、位置は元のPDF（NewEditorに渡したPDFReader）で検索したものとする
+// 置き換えた文字列は元のフォントで符号化するため、フォントに含まれない文字（サブセットのフォントで使われていない文字など）は使えない
+// 設計書: docs/editor_design.md
+func (e *Editor) ReplaceSearchResult(result SearchResult, text string) error {
+	if len(result.Quads) == 0 {
+		return fmt.Errorf("search result has no position")
+	}
+	return e.replaceGlyphs(result.Page, result.Text, text, func(glyph layout.TextElement) bool {
+		for _, quad := range result.Quads {
+			if containsGlyph(quad.Bounds(), glyph) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ReplaceTextBlock はテキストブロック（PDFReader.ExtractPageLayout）の文字列を、コンテンツストリームの中でtextに置き換える
+// textはブロックの最初のテキスト要素の位置に表示し、ブロックの他の文字は削除する（行の折り返しはしない）
+// 位置は元のPDFで抽出したものとし、フォントに含まれない文字が使えないのはReplaceSearchResultと同じ
+func (e *Editor) ReplaceTextBlock(pageNum int, block TextBlock, text string) error {
+	return e.replaceGlyphs(pageNum, "", text, func(glyph layout.TextElement) bool {
+		return containsGlyph(block.Rect, glyph)
+	})
+}
+
+// replaceGlyphs はページのコンテンツストリームで、selectedを満たす文字をtextに置き換える
+// 選んだ文字のうちコンテンツストリームで最初の文字の位置にtextを表示し、残りの文字は削除する
+// wantが空でなければ、選んだ文字が（空白を除いて）wantと一致することを確かめる
+func (e *Editor) replaceGlyphs(pageNum int, want, text string, selected func(layout.TextElement) bool) error {
+	if err := e.checkPage(pageNum); err != nil {
+		return err
+	}
+	page := e.pages[pageNum]
+	dict, err := e.r.r.GetPageByReference(page.ref)
+	if err != nil {
+		return fmt.Errorf("failed to read page %d: %w", pageNum, err)
+	}
+
+	data := page.contents
+	if data == nil {
+		if data, err = e.r.r.GetPageContents(dict); err != nil {
+			return fmt.Errorf("failed to read contents of page %d: %w", pageNum, err)
+		}
+	}
+	operations, err := content.NewStreamParser(data).ParseOperations()
+	if err != nil {
+		return fmt.Errorf("failed to parse contents of page %d: %w", pageNum, err)
+	}
+	elements, err := content.NewTextExtractor(operations, e.r.r, dict).Extract()
+	if err != nil {
+		return fmt.Errorf("failed to extract text of page %d: %w", pageNum, err)
+	}
+
+	// 検索やレイアウトの抽出と同じく、/Rotateのあるページでは表示される向きの座標で判定する
+	glyphs := convertGlyphs(elements)
+	if rotation := e.r.pageRotation(dict); rotation != 0 {
+		width, height := e.r.getPageSize(dict)
+		for _, elemGlyphs := range glyphs {
+			rotateTextElements(elemGlyphs, rotation, width, height)
+		}
+	}
+
+	var edits []content.TextEdit
+	var found strings.Builder
+	for i, elemGlyphs := range glyphs {
+		for j, glyph := range elemGlyphs {
+			if !selected(glyph) {
+				continue
+			}
+			found.WriteString(glyph.Text)
+			if n := len(edits); n > 0 && edits[n-1].Element == i && edits[n-1].End == j {
+				edits[n-1].End++
+				continue
+			}
+			edits = append(edits, content.TextEdit{Element: i, Start: j, End: j + 1})
+		}
+	}
+	if len(edits) == 0 {
+		return fmt.Errorf("text not found on page %d", pageNum)
+	}
+	if want != "" && removeSpaces(found.String()) != removeSpaces(want) {
+		return fmt.Errorf("text on page %d is %q, not %q", pageNum, found.String(), want)
+	}
+	edits[0].Text = text
+
+	replaced, err := content.ReplaceText(data, operations, elements, edits)
+	if err != nil {
+		return fmt.Errorf("failed to replace text on page %d: %w", pageNum, err)
+	}
+	page.contents = replaced
+	return nil
+}
+
+// containsGlyph は文字の領域の中心が矩形の内側にあるかを返す
+// 隣の文字や行にかかる程度のずれでは、選んだ文字の外側の文字を含めない
+func containsGlyph(rect Rectangle, glyph layout.TextElement) bool {
+	bounds := glyph.Bounds()
+	x, y := bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2
+	return x > rect.X && x < rect.X+rect.Width && y > rect.Y && y < rect.Y+rect.Height
+}
+
+// removeSpaces は空白と改行を除いた文字列を返す
+func removeSpaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// addContentStream は置き換えたコンテンツストリームを圧縮して出力する
+func addContentStream(w *writer.Writer, data []byte) (*core.Reference, error) {
+	compressed, err := compressWithZlib(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress contents: %w", err)
+	}
+	num, err := w.AddObject(&core.Stream{
+		Dict: core.Dictionary{
+			core.Name("Filter"): core.Name("FlateDecode"),
+			core.Name("Length"): core.Integer(len(compressed)),
+		},
+		Data: compressed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &core.Reference{ObjectNumber: num}, nil
+}
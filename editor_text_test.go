@@ -0,0 +1,203 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// editorTextSourcePDF はテキストを置き換えるページを持つPDFを作成する
+// 1ページ目はTjとTJ、2ページ目はToUnicodeを持つIdentity-Hの複合フォント（"abcde"のみ）、3ページ目は/Rotate 90
+func editorTextSourcePDF() []byte {
+	stream := func(data string) string {
+		return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+	}
+	toUnicode := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"1 beginbfrange <0001> <0005> <0061> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 /MediaBox [0 0 300 200] /Resources << /Font << /F1 6 0 R /F2 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 9 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 10 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 11 0 R /Rotate 90 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Sample /Encoding /Identity-H /DescendantFonts [8 0 R] /ToUnicode 12 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /Sample /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /DW 500 >>",
+		stream("BT /F1 12 Tf 20 150 Td (Hello World) Tj 0 -20 Td [(Sec) -10 (ond line)] TJ ET"),
+		stream("BT /F2 12 Tf 20 150 Td <000100020003> Tj ET"),
+		stream("BT /F1 12 Tf 20 150 Td (Rotated) Tj ET"),
+		stream(toUnicode),
+	})
+}
+
+func TestEditor_ReplaceText(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(t *testing.T, source *PDFReader, e *Editor) error
+		// 保存後のページ -> テキスト
+		want map[int]string
+	}{
+		{
+			name: "search result in Tj",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				return e.ReplaceSearchResult(searchOne(t, source, 0, "World"), "Earth")
+			},
+			want: map[int]string{0: "Hello Earth Second line"},
+		},
+		{
+			name: "search result across strings of TJ",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				return e.ReplaceSearchResult(searchOne(t, source, 0, "Second"), "2nd")
+			},
+			want: map[int]string{0: "Hello World 2nd line"},
+		},
+		{
+			name: "two results on the same page",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				if err := e.ReplaceSearchResult(searchOne(t, source, 0, "Hello"), "Hi"); err != nil {
+					return err
+				}
+				return e.ReplaceSearchResult(searchOne(t, source, 0, "line"), "row")
+			},
+			want: map[int]string{0: "Hi World Second row"},
+		},
+		{
+			name: "composite font",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				return e.ReplaceSearchResult(searchOne(t, source, 1, "bc"), "ed")
+			},
+			want: map[int]string{1: "aed"},
+		},
+		{
+			name: "rotated page",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				return e.ReplaceSearchResult(searchOne(t, source, 2, "Rotated"), "Turned")
+			},
+			want: map[int]string{2: "Turned"},
+		},
+		{
+			name: "text block",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				layout, err := source.ExtractPageLayout(0)
+				if err != nil {
+					return err
+				}
+				for _, block := range layout.TextBlocks {
+					if strings.Contains(block.Text, "Hello") {
+						return e.ReplaceTextBlock(0, block, "Replaced")
+					}
+				}
+				return fmt.Errorf("no text block")
+			},
+			want: map[int]string{0: "Replaced"},
+		},
+		{
+			name: "after reordering pages",
+			edit: func(t *testing.T, source *PDFReader, e *Editor) error {
+				result := searchOne(t, source, 1, "abc")
+				if err := e.MovePage(1, 0); err != nil {
+					return err
+				}
+				result.Page = 0
+				return e.ReplaceSearchResult(result, "cab")
+			},
+			want: map[int]string{0: "cab", 1: "Hello World Second line"},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, incremental := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/incremental=%v", tt.name, incremental), func(t *testing.T) {
+				source, err := OpenReader(bytes.NewReader(editorTextSourcePDF()))
+				if err != nil {
+					t.Fatalf("Failed to open PDF: %v", err)
+				}
+				defer source.Close()
+
+				editor, err := NewEditor(source)
+				if err != nil {
+					t.Fatalf("NewEditor failed: %v", err)
+				}
+				if err := tt.edit(t, source, editor); err != nil {
+					t.Fatalf("edit failed: %v", err)
+				}
+				var buf bytes.Buffer
+				if incremental {
+					err = editor.SaveIncremental(&buf)
+				} else {
+					err = editor.Save(&buf)
+				}
+				if err != nil {
+					t.Fatalf("save failed: %v", err)
+				}
+
+				reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+				if err != nil {
+					t.Fatalf("Failed to open saved PDF: %v", err)
+				}
+				defer reader.Close()
+				for pageNum, want := range tt.want {
+					got, err := reader.ExtractPageText(pageNum)
+					if err != nil {
+						t.Fatalf("ExtractPageText(%d) failed: %v", pageNum, err)
+					}
+					if strings.TrimSpace(got) != want {
+						t.Errorf("page %d text = %q, want %q", pageNum, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestEditor_ReplaceText_Errors(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(editorTextSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+	editor, err := NewEditor(source)
+	if err != nil {
+		t.Fatalf("NewEditor failed: %v", err)
+	}
+
+	stale := searchOne(t, source, 0, "World")
+	stale.Text = "Earth"
+	moved := searchOne(t, source, 0, "World")
+	moved.Page = 1
+
+	tests := []struct {
+		name string
+		edit func() error
+	}{
+		{name: "character not in the font", edit: func() error { return editor.ReplaceSearchResult(searchOne(t, source, 1, "abc"), "xyz") }},
+		{name: "character not in a single-byte font", edit: func() error { return editor.ReplaceSearchResult(searchOne(t, source, 0, "World"), "世界") }},
+		{name: "text does not match", edit: func() error { return editor.ReplaceSearchResult(stale, "Mars") }},
+		{name: "no text at the position", edit: func() error { return editor.ReplaceSearchResult(moved, "Mars") }},
+		{name: "no position", edit: func() error { return editor.ReplaceSearchResult(SearchResult{Text: "World"}, "Mars") }},
+		{name: "page out of range", edit: func() error { return editor.ReplaceTextBlock(3, TextBlock{}, "Mars") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.edit(); err == nil {
+				t.Error("edit should fail")
+			}
+		})
+	}
+}
+
+// searchOne はページでqueryを検索し、1か所だけ一致したものを返す
+func searchOne(t *testing.T, reader *PDFReader, pageNum int, query string) SearchResult {
+	t.Helper()
+	results, err := reader.SearchPage(pageNum, query, SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchPage(%d, %q) failed: %v", pageNum, query, err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("SearchPage(%d, %q) found %d results, want 1", pageNum, query, len(results))
+	}
+	return results[0]
+}
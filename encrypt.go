@@ -0,0 +1,27 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+)
+
+// Encrypt は既存のPDFを開き、新しい暗号化設定で書き出す
+// password は入力が暗号化されている場合のユーザーパスワードまたはオーナーパスワード（暗号化されていなければ無視される）
+// ページ内容・メタデータ・文書構造はそのまま保持されるため、パスワードや権限の変更に利用できる
+func Encrypt(in io.ReadSeeker, out io.Writer, password string, opts EncryptionOptions) error {
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid encryption options: %w", err)
+	}
+
+	r, err := openAuthenticated(in, password)
+	if err != nil {
+		return err
+	}
+
+	encryption, err := opts.writerEncryption()
+	if err != nil {
+		return err
+	}
+
+	return rewritePDF(r, out, encryption)
+}
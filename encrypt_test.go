@@ -0,0 +1,123 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+func TestEncrypt(t *testing.T) {
+	oldOpts := &EncryptionOptions{UserPassword: "old-user", OwnerPassword: "old-owner", Permissions: DefaultPermissions(), KeyLength: 128}
+
+	tests := []struct {
+		name       string
+		source     *EncryptionOptions
+		password   string
+		opts       EncryptionOptions
+		wantMethod string
+		wantErr    bool
+	}{
+		{
+			name:       "encrypt unencrypted PDF",
+			source:     nil,
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128},
+			wantMethod: "V2",
+		},
+		{
+			name:       "rotate passwords",
+			source:     oldOpts,
+			password:   "old-owner",
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: PrintOnlyPermissions(), KeyLength: 128, Algorithm: EncryptionAES},
+			wantMethod: "AESV2",
+		},
+		{
+			name:       "upgrade to AES-256",
+			source:     &EncryptionOptions{UserPassword: "old-user", OwnerPassword: "old-owner", Permissions: DefaultPermissions(), KeyLength: 40},
+			password:   "old-user",
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: RestrictedPermissions(), KeyLength: 256},
+			wantMethod: "AESV3",
+		},
+		{
+			name:     "wrong password",
+			source:   oldOpts,
+			password: "wrong",
+			opts:     EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", KeyLength: 128},
+			wantErr:  true,
+		},
+		{
+			name:    "invalid options",
+			source:  nil,
+			opts:    EncryptionOptions{UserPassword: "user", KeyLength: 64},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildEncryptedPDF(t, tt.source)
+
+			var out bytes.Buffer
+			err := Encrypt(bytes.NewReader(data), &out, tt.password, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tt.source != nil {
+				old, err := OpenReader(bytes.NewReader(out.Bytes()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := old.AuthenticateWithPassword(tt.source.UserPassword); err == nil {
+					t.Error("old user password should no longer work")
+				}
+			}
+
+			reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			defer reader.Close()
+
+			if !reader.IsEncrypted() {
+				t.Fatal("output should be encrypted")
+			}
+			if err := reader.AuthenticateWithPassword(tt.opts.UserPassword); err != nil {
+				t.Fatalf("AuthenticateWithPassword() failed: %v", err)
+			}
+			if got := reader.GetEncryptionInfo().Method; got != tt.wantMethod {
+				t.Errorf("Method = %q, want %q", got, tt.wantMethod)
+			}
+			if got := reader.Permissions(); got != tt.opts.Permissions {
+				t.Errorf("Permissions() = %+v, want %+v", got, tt.opts.Permissions)
+			}
+
+			if got := reader.PageCount(); got != 2 {
+				t.Errorf("PageCount() = %d, want 2", got)
+			}
+			text, err := reader.ExtractPageText(1)
+			if err != nil {
+				t.Fatalf("ExtractPageText() failed: %v", err)
+			}
+			if !strings.Contains(text, "Second page") {
+				t.Errorf("page text = %q, want to contain %q", text, "Second page")
+			}
+			if info := reader.Info(); info.Title != "秘密の文書" {
+				t.Errorf("Title = %q, want %q", info.Title, "秘密の文書")
+			}
+
+			catalog, err := reader.r.GetCatalog()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, hasExtensions := catalog[core.Name("Extensions")]
+			if wantExtensions := tt.wantMethod == "AESV3"; hasExtensions != wantExtensions {
+				t.Errorf("catalog /Extensions present = %v, want %v", hasExtensions, wantExtensions)
+			}
+		})
+	}
+}
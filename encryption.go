@@ -3,15 +3,40 @@ package gopdf
 import (
 	"fmt"
 
+	"github.com/ryomak/gopdf/internal/core"
 	"github.com/ryomak/gopdf/internal/security"
+	"github.com/ryomak/gopdf/internal/writer"
 )
 
+// EncryptionAlgorithm は暗号化アルゴリズム
+type EncryptionAlgorithm int
+
+const (
+	// EncryptionRC4 はRC4による暗号化（KeyLength 40 / 128）
+	EncryptionRC4 EncryptionAlgorithm = iota
+	// EncryptionAES はAESによる暗号化（KeyLength 128 = AESV2, 256 = AESV3）
+	EncryptionAES
+)
+
+// String はアルゴリズム名を返す
+func (a EncryptionAlgorithm) String() string {
+	switch a {
+	case EncryptionRC4:
+		return "RC4"
+	case EncryptionAES:
+		return "AES"
+	default:
+		return fmt.Sprintf("EncryptionAlgorithm(%d)", int(a))
+	}
+}
+
 // EncryptionOptions はPDF暗号化のオプション
 type EncryptionOptions struct {
-	UserPassword  string      // ユーザーパスワード（PDFを開くために必要）
-	OwnerPassword string      // オーナーパスワード（すべての権限）
-	Permissions   Permissions // アクセス権限
-	KeyLength     int         // 暗号鍵の長さ（40 or 128 bits）
+	UserPassword  string              // ユーザーパスワード（PDFを開くために必要）
+	OwnerPassword string              // オーナーパスワード（すべての権限）
+	Permissions   Permissions         // アクセス権限
+	KeyLength     int                 // 暗号鍵の長さ（40, 128 or 256 bits。256はAES-256）
+	Algorithm     EncryptionAlgorithm // 暗号化アルゴリズム（デフォルトはRC4）
 }
 
 // Permissions はPDFのアクセス権限
@@ -82,6 +107,48 @@ func (p Permissions) toInternal() security.Permissions {
 	}
 }
 
+// permissionsFromInternal converts security.Permissions to gopdf.Permissions
+func permissionsFromInternal(p security.Permissions) Permissions {
+	return Permissions{
+		Print:            p.Print,
+		Modify:           p.Modify,
+		Copy:             p.Copy,
+		Annotate:         p.Annotate,
+		FillForms:        p.FillForms,
+		ExtractContent:   p.ExtractContent,
+		Assemble:         p.Assemble,
+		PrintHighQuality: p.PrintHighQuality,
+	}
+}
+
+// writerEncryption derives the writer's encryption info (keys, O/U values) from the options
+func (opts EncryptionOptions) writerEncryption() (*writer.EncryptionInfo, error) {
+	info, err := writer.SetupEncryptionWithAlgorithm(
+		opts.UserPassword,
+		opts.OwnerPassword,
+		opts.Permissions.toInternal(),
+		opts.KeyLength,
+		opts.securityAlgorithm(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup encryption: %w", err)
+	}
+	return info, nil
+}
+
+// addAES256Extension adds the Adobe extension level 8 (required for AES-256 in a 1.7 file) to the catalog
+func addAES256Extension(catalog core.Dictionary) {
+	extensions, ok := catalog[core.Name("Extensions")].(core.Dictionary)
+	if !ok {
+		extensions = core.Dictionary{}
+		catalog[core.Name("Extensions")] = extensions
+	}
+	extensions[core.Name("ADBE")] = core.Dictionary{
+		core.Name("BaseVersion"):    core.Name("1.7"),
+		core.Name("ExtensionLevel"): core.Integer(8),
+	}
+}
+
 // Validate validates the encryption options
 func (opts EncryptionOptions) Validate() error {
 	// At least one password must be set
@@ -89,22 +156,56 @@ func (opts EncryptionOptions) Validate() error {
 		return fmt.Errorf("at least one password must be set")
 	}
 
-	// Key length must be 40 or 128
-	if opts.KeyLength != 40 && opts.KeyLength != 128 {
-		return fmt.Errorf("key length must be 40 or 128 bits, got %d", opts.KeyLength)
+	// 256-bitはAES-256（PDF 2.0, R6）のみ。Algorithm の指定に関わらずAESを使用する
+	if opts.KeyLength == 256 {
+		if opts.Algorithm != EncryptionRC4 && opts.Algorithm != EncryptionAES {
+			return fmt.Errorf("unsupported encryption algorithm: %v", opts.Algorithm)
+		}
+		return nil
+	}
+
+	switch opts.Algorithm {
+	case EncryptionRC4:
+		// Key length must be 40 or 128
+		if opts.KeyLength != 40 && opts.KeyLength != 128 {
+			return fmt.Errorf("key length must be 40 or 128 bits, got %d", opts.KeyLength)
+		}
+	case EncryptionAES:
+		if opts.KeyLength != 128 {
+			return fmt.Errorf("AES key length must be 128 or 256 bits, got %d", opts.KeyLength)
+		}
+	default:
+		return fmt.Errorf("unsupported encryption algorithm: %v", opts.Algorithm)
 	}
 
 	return nil
 }
 
-// GetRevision returns the PDF encryption revision number based on key length
+// GetRevision returns the PDF encryption revision number based on algorithm and key length
 func (opts EncryptionOptions) GetRevision() int {
+	if opts.KeyLength == 256 {
+		return 6 // Revision 6 for AES-256 (AESV3)
+	}
+	if opts.Algorithm == EncryptionAES {
+		return 4 // Revision 4 for AES-128 (AESV2)
+	}
 	if opts.KeyLength == 40 {
 		return 2 // Revision 2 for 40-bit
 	}
 	return 3 // Revision 3 for 128-bit
 }
 
+// securityAlgorithm returns the internal cipher used for strings and streams
+func (opts EncryptionOptions) securityAlgorithm() security.Algorithm {
+	if opts.KeyLength == 256 {
+		return security.AlgorithmAESV3
+	}
+	if opts.Algorithm == EncryptionAES {
+		return security.AlgorithmAESV2
+	}
+	return security.AlgorithmRC4
+}
+
 // GetKeyLengthBytes returns the key length in bytes
 func (opts EncryptionOptions) GetKeyLengthBytes() int {
 	return opts.KeyLength / 8
@@ -57,6 +57,26 @@ func TestEncryptionOptionsValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid: AES-128 encryption",
+			opts: EncryptionOptions{
+				UserPassword: "user",
+				Permissions:  DefaultPermissions(),
+				KeyLength:    128,
+				Algorithm:    EncryptionAES,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid: AES with 40-bit key",
+			opts: EncryptionOptions{
+				UserPassword: "user",
+				Permissions:  DefaultPermissions(),
+				KeyLength:    40,
+				Algorithm:    EncryptionAES,
+			},
+			wantErr: true,
+		},
 		{
 			name: "Valid: 128-bit encryption",
 			opts: EncryptionOptions{
@@ -66,6 +86,25 @@ func TestEncryptionOptionsValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid: AES-256 encryption",
+			opts: EncryptionOptions{
+				UserPassword: "user",
+				Permissions:  DefaultPermissions(),
+				KeyLength:    256,
+				Algorithm:    EncryptionAES,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid: 256-bit key implies AES",
+			opts: EncryptionOptions{
+				UserPassword: "user",
+				Permissions:  DefaultPermissions(),
+				KeyLength:    256,
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -82,15 +121,19 @@ func TestEncryptionOptionsGetRevision(t *testing.T) {
 	tests := []struct {
 		name      string
 		keyLength int
+		algorithm EncryptionAlgorithm
 		want      int
 	}{
-		{"40-bit", 40, 2},
-		{"128-bit", 128, 3},
+		{"40-bit", 40, EncryptionRC4, 2},
+		{"128-bit", 128, EncryptionRC4, 3},
+		{"AES-128", 128, EncryptionAES, 4},
+		{"AES-256", 256, EncryptionAES, 6},
+		{"256-bit without algorithm", 256, EncryptionRC4, 6},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			opts := EncryptionOptions{KeyLength: tt.keyLength}
+			opts := EncryptionOptions{KeyLength: tt.keyLength, Algorithm: tt.algorithm}
 			if got := opts.GetRevision(); got != tt.want {
 				t.Errorf("GetRevision() = %d, want %d", got, tt.want)
 			}
@@ -106,6 +149,7 @@ func TestEncryptionOptionsGetKeyLengthBytes(t *testing.T) {
 	}{
 		{"40-bit", 40, 5},
 		{"128-bit", 128, 16},
+		{"256-bit", 256, 32},
 	}
 
 	for _, tt := range tests {
@@ -318,3 +362,183 @@ func TestDocumentWithoutEncryption(t *testing.T) {
 		t.Error("Non-encrypted PDF should not contain /Encrypt")
 	}
 }
+
+func TestEncryptionRoundtrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       EncryptionOptions
+		wantMethod string
+	}{
+		{
+			name:       "RC4 40-bit",
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 40},
+			wantMethod: "V2",
+		},
+		{
+			name:       "RC4 128-bit",
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128},
+			wantMethod: "V2",
+		},
+		{
+			name:       "AES-128",
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128, Algorithm: EncryptionAES},
+			wantMethod: "AESV2",
+		},
+		{
+			name:       "AES-256",
+			opts:       EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: PrintOnlyPermissions(), KeyLength: 256, Algorithm: EncryptionAES},
+			wantMethod: "AESV3",
+		},
+		{
+			name:       "AES-256 non-ASCII password",
+			opts:       EncryptionOptions{UserPassword: "ユーザー", OwnerPassword: "オーナー", Permissions: DefaultPermissions(), KeyLength: 256},
+			wantMethod: "AESV3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			page := doc.AddPage(PageSizeA4, Portrait)
+			if err := page.SetFont(FontHelvetica, 12); err != nil {
+				t.Fatal(err)
+			}
+			if err := page.DrawText("Secret text", 100, 700); err != nil {
+				t.Fatal(err)
+			}
+			doc.SetMetadata(Metadata{Title: "Encrypted Title"})
+			if err := doc.SetEncryption(tt.opts); err != nil {
+				t.Fatalf("SetEncryption() failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+
+			for _, password := range []string{tt.opts.UserPassword, tt.opts.OwnerPassword} {
+				reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+				if err != nil {
+					t.Fatalf("OpenReader() failed: %v", err)
+				}
+				if err := reader.AuthenticateWithPassword(password); err != nil {
+					t.Fatalf("AuthenticateWithPassword(%q) failed: %v", password, err)
+				}
+
+				info := reader.GetEncryptionInfo()
+				if info.Method != tt.wantMethod {
+					t.Errorf("Method = %q, want %q", info.Method, tt.wantMethod)
+				}
+				if wantOwner := password == tt.opts.OwnerPassword; info.IsOwner != wantOwner {
+					t.Errorf("IsOwner = %v, want %v", info.IsOwner, wantOwner)
+				}
+
+				text, err := reader.ExtractPageText(0)
+				if err != nil {
+					t.Fatalf("ExtractPageText() failed: %v", err)
+				}
+				if !strings.Contains(text, "Secret text") {
+					t.Errorf("ExtractPageText() = %q, want to contain %q", text, "Secret text")
+				}
+				if got := reader.Info().Title; got != "Encrypted Title" {
+					t.Errorf("Title = %q, want %q", got, "Encrypted Title")
+				}
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			if err := reader.AuthenticateWithPassword("wrong"); err == nil {
+				t.Error("AuthenticateWithPassword() with wrong password should fail")
+			}
+		})
+	}
+}
+
+func TestPDFReaderPermissions(t *testing.T) {
+	restricted := Permissions{Print: true, FillForms: true}
+
+	tests := []struct {
+		name      string
+		opts      *EncryptionOptions
+		password  string
+		wantPerms Permissions
+		wantPrint bool
+		wantCopy  bool
+		wantFill  bool
+	}{
+		{
+			name:      "not encrypted",
+			wantPerms: DefaultPermissions(),
+			wantPrint: true,
+			wantCopy:  true,
+			wantFill:  true,
+		},
+		{
+			name:      "user password",
+			opts:      &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: restricted, KeyLength: 128},
+			password:  "user",
+			wantPerms: restricted,
+			wantPrint: true,
+			wantFill:  true,
+		},
+		{
+			name:      "owner password",
+			opts:      &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: restricted, KeyLength: 256},
+			password:  "owner",
+			wantPerms: restricted,
+			wantPrint: true,
+			wantCopy:  true,
+			wantFill:  true,
+		},
+		{
+			// リビジョン2では拡張ビットは基本の権限（Annotate, Print など）に従う
+			name:      "revision 2",
+			opts:      &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: restricted, KeyLength: 40},
+			password:  "user",
+			wantPerms: Permissions{Print: true, PrintHighQuality: true},
+			wantPrint: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			if tt.opts != nil {
+				if err := doc.SetEncryption(*tt.opts); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reader.Close()
+			if tt.password != "" {
+				if err := reader.AuthenticateWithPassword(tt.password); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := reader.Permissions(); got != tt.wantPerms {
+				t.Errorf("Permissions() = %+v, want %+v", got, tt.wantPerms)
+			}
+			if got := reader.CanPrint(); got != tt.wantPrint {
+				t.Errorf("CanPrint() = %v, want %v", got, tt.wantPrint)
+			}
+			if got := reader.CanCopy(); got != tt.wantCopy {
+				t.Errorf("CanCopy() = %v, want %v", got, tt.wantCopy)
+			}
+			if got := reader.CanFillForms(); got != tt.wantFill {
+				t.Errorf("CanFillForms() = %v, want %v", got, tt.wantFill)
+			}
+		})
+	}
+}
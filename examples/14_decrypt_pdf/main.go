@@ -65,16 +65,21 @@ func main() {
 		}[encInfo.IsOwner])
 
 		// Display permissions
-		fmt.Println("\n🔐 Permissions:")
-		perms := encInfo.P
-		displayPermission("Print", (perms & (1 << 2)) != 0)
-		displayPermission("Modify", (perms & (1 << 3)) != 0)
-		displayPermission("Copy/Extract", (perms & (1 << 4)) != 0)
-		displayPermission("Annotate", (perms & (1 << 5)) != 0)
-		displayPermission("Fill Forms", (perms & (1 << 8)) != 0)
-		displayPermission("Extract for Accessibility", (perms & (1 << 9)) != 0)
-		displayPermission("Assemble Document", (perms & (1 << 10)) != 0)
-		displayPermission("Print High Quality", (perms & (1 << 11)) != 0)
+		fmt.Println("\n🔐 Permissions (as recorded in the document):")
+		perms := r.Permissions()
+		displayPermission("Print", perms.Print)
+		displayPermission("Modify", perms.Modify)
+		displayPermission("Copy/Extract", perms.Copy)
+		displayPermission("Annotate", perms.Annotate)
+		displayPermission("Fill Forms", perms.FillForms)
+		displayPermission("Extract for Accessibility", perms.ExtractContent)
+		displayPermission("Assemble Document", perms.Assemble)
+		displayPermission("Print High Quality", perms.PrintHighQuality)
+
+		// Effective permissions also take owner authentication into account
+		fmt.Println("\n✅ Effective Access:")
+		displayPermission("Print", r.CanPrint())
+		displayPermission("Copy", r.CanCopy())
 	}
 
 	// Display PDF information
@@ -0,0 +1,127 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// FacturXProfile はFactur-X / ZUGFeRD 2 の請求書データのプロファイル（XMPの fx:ConformanceLevel）
+type FacturXProfile string
+
+const (
+	FacturXMinimum   FacturXProfile = "MINIMUM"
+	FacturXBasicWL   FacturXProfile = "BASIC WL"
+	FacturXBasic     FacturXProfile = "BASIC"
+	FacturXEN16931   FacturXProfile = "EN 16931"
+	FacturXExtended  FacturXProfile = "EXTENDED"
+	FacturXXRechnung FacturXProfile = "XRECHNUNG"
+)
+
+// nsFacturX はFactur-X / ZUGFeRD 2 のXMP拡張スキーマの名前空間
+const nsFacturX = "urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#"
+
+// facturXInvoice は埋め込んだ請求書をXMPで宣言するための情報
+type facturXInvoice struct {
+	profile  FacturXProfile
+	fileName string
+}
+
+// fileName はプロファイルごとに規定された埋め込みファイル名を返す
+func (p FacturXProfile) fileName() string {
+	if p == FacturXXRechnung {
+		return "xrechnung.xml"
+	}
+	return "factur-x.xml"
+}
+
+// relationship は請求書XMLと文書の関係を返す
+// MINIMUMとBASIC WLは請求書として必要な明細を持たないため、PDFが請求書の本体でXMLはそのデータとなる
+func (p FacturXProfile) relationship() AFRelationship {
+	if p == FacturXMinimum || p == FacturXBasicWL {
+		return AFRelationshipData
+	}
+	return AFRelationshipAlternative
+}
+
+// AttachFacturX はFactur-X / ZUGFeRD 2 の電子請求書（CII形式のXML）を埋め込む
+// 文書はPDF/A-3bとして出力され（SetConformance(PDFA3B)）、XMLはプロファイルに応じたファイル名・
+// AFRelationshipで関連ファイルとして登録される。XMPには請求書の種類・ファイル名・プロファイルを記録する
+// XMLは整形式であることのみ検査し、スキーマ（EN 16931など）に対する検証は行わない
+func (d *Document) AttachFacturX(invoiceXML []byte, profile FacturXProfile) error {
+	switch profile {
+	case FacturXMinimum, FacturXBasicWL, FacturXBasic, FacturXEN16931, FacturXExtended, FacturXXRechnung:
+	default:
+		return fmt.Errorf("unsupported Factur-X profile: %q", profile)
+	}
+	if d.conformance != ConformanceNone && d.conformance != PDFA3B {
+		return fmt.Errorf("Factur-X requires PDF/A-3, but the document is set to %s", d.conformance)
+	}
+	if err := checkWellFormedXML(invoiceXML); err != nil {
+		return fmt.Errorf("invalid invoice XML: %w", err)
+	}
+
+	invoice := &facturXInvoice{profile: profile, fileName: profile.fileName()}
+	err := d.AttachFile(FileAttachment{
+		Name:         invoice.fileName,
+		Data:         invoiceXML,
+		MIMEType:     "text/xml",
+		Description:  "Factur-X/ZUGFeRD invoice",
+		Relationship: profile.relationship(),
+	})
+	if err != nil {
+		return err
+	}
+	d.conformance = PDFA3B
+	d.invoice = invoice
+	return nil
+}
+
+// withXMP はFactur-Xの拡張スキーマのプロパティを加えたメタデータを返す
+func (inv *facturXInvoice) withXMP(metadata *Metadata) *Metadata {
+	if inv == nil {
+		return metadata
+	}
+	out := *metadata
+	out.XMP = slices.Clone(metadata.XMP)
+	for _, p := range [][2]string{
+		{"DocumentType", "INVOICE"},
+		{"DocumentFileName", inv.fileName},
+		{"Version", "1.0"},
+		{"ConformanceLevel", string(inv.profile)},
+	} {
+		out.XMP = append(out.XMP, XMPProperty{Namespace: nsFacturX, Prefix: "fx", Name: p[0], Value: p[1]})
+	}
+	return &out
+}
+
+// checkWellFormedXML はデータが1つのルート要素を持つ整形式のXMLかを検査する
+func checkWellFormedXML(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth, roots := 0, 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if roots != 1 {
+		return fmt.Errorf("expected a single root element, found %d", roots)
+	}
+	return nil
+}
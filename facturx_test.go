@@ -0,0 +1,114 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+const testInvoiceXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rsm:CrossIndustryInvoice xmlns:rsm="urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100">
+  <rsm:ExchangedDocument/>
+</rsm:CrossIndustryInvoice>`
+
+func TestDocumentAttachFacturX(t *testing.T) {
+	tests := []struct {
+		name             string
+		profile          FacturXProfile
+		wantFile         string
+		wantRelationship core.Name
+	}{
+		{"minimum", FacturXMinimum, "factur-x.xml", "Data"},
+		{"basic WL", FacturXBasicWL, "factur-x.xml", "Data"},
+		{"EN 16931", FacturXEN16931, "factur-x.xml", "Alternative"},
+		{"XRechnung", FacturXXRechnung, "xrechnung.xml", "Alternative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fontPath := getTestTTFPath()
+			if fontPath == "" {
+				t.Skip("No test font available on this system")
+			}
+			font, err := LoadTTF(fontPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc := New()
+			doc.SetMetadata(Metadata{Title: "Invoice INV-001"})
+			page := doc.AddPage(PageSizeA4, Portrait)
+			if err := page.SetTTFFont(font, 12); err != nil {
+				t.Fatal(err)
+			}
+			page.DrawTextUTF8("Invoice INV-001", 50, 750)
+			if err := doc.AttachFacturX([]byte(testInvoiceXML), tt.profile); err != nil {
+				t.Fatalf("AttachFacturX() failed: %v", err)
+			}
+			if doc.Conformance() != PDFA3B {
+				t.Errorf("Conformance() = %v, want PDF/A-3b", doc.Conformance())
+			}
+
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+
+			r, _, specs := readAttachments(t, buf.Bytes())
+			spec, ok := specs[tt.wantFile]
+			if !ok {
+				t.Fatalf("embedded files = %v, want %s", specs, tt.wantFile)
+			}
+			if got := spec[core.Name("AFRelationship")]; got != tt.wantRelationship {
+				t.Errorf("/AFRelationship = %v, want %v", got, tt.wantRelationship)
+			}
+
+			packet, err := r.RawXMP()
+			if err != nil {
+				t.Fatal(err)
+			}
+			xmp := string(packet)
+			for _, want := range []string{
+				"<pdfaid:part>3</pdfaid:part>",
+				`xmlns:fx="` + nsFacturX + `"`,
+				"<fx:DocumentType>INVOICE</fx:DocumentType>",
+				"<fx:DocumentFileName>" + tt.wantFile + "</fx:DocumentFileName>",
+				"<fx:Version>1.0</fx:Version>",
+				"<fx:ConformanceLevel>" + string(tt.profile) + "</fx:ConformanceLevel>",
+				"<pdfaSchema:prefix>fx</pdfaSchema:prefix>",
+			} {
+				if !strings.Contains(xmp, want) {
+					t.Errorf("XMP does not contain %s", want)
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentAttachFacturXErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		conformance Conformance
+		xml         string
+		profile     FacturXProfile
+	}{
+		{"unknown profile", ConformanceNone, testInvoiceXML, "COMFORT"},
+		{"malformed XML", ConformanceNone, "<Invoice>", FacturXBasic},
+		{"no root element", ConformanceNone, "", FacturXBasic},
+		{"PDF/A-2b document", PDFA2B, testInvoiceXML, FacturXBasic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.SetConformance(tt.conformance)
+			if err := doc.AttachFacturX([]byte(tt.xml), tt.profile); err == nil {
+				t.Fatal("AttachFacturX() succeeded, want error")
+			}
+			if len(doc.Attachments()) != 0 {
+				t.Error("invoice was attached despite the error")
+			}
+		})
+	}
+}
@@ -52,3 +52,9 @@ const (
 func (f StandardFont) Name() string {
 	return string(f)
 }
+
+// TextWidth estimates the width of a text string at a given font size.
+// Standard fonts carry no metrics in this package, so the width is an approximation.
+func (f StandardFont) TextWidth(text string, fontSize float64) (float64, error) {
+	return estimateTextWidth(text, fontSize, string(f)), nil
+}
@@ -0,0 +1,191 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Script は翻訳先の言語の文字の種類（用字系）
+type Script string
+
+const (
+	ScriptLatin    Script = "latin"    // ラテン文字（英語・フランス語・ベトナム語など）
+	ScriptCJK      Script = "cjk"      // 漢字・かな・ハングル（日本語・中国語・韓国語）
+	ScriptCyrillic Script = "cyrillic" // キリル文字（ロシア語・ウクライナ語など）
+	ScriptThai     Script = "thai"     // タイ文字
+	ScriptArabic   Script = "arabic"   // アラビア文字（アラビア語・ペルシア語・ウルドゥー語）
+	ScriptHebrew   Script = "hebrew"   // ヘブライ文字（ヘブライ語・イディッシュ語）
+)
+
+// languageScripts は言語コード（BCP 47の最初の部分）の用字系（ない言語はラテン文字）
+var languageScripts = map[string]Script{
+	"ja": ScriptCJK, "zh": ScriptCJK, "ko": ScriptCJK,
+	"ru": ScriptCyrillic, "uk": ScriptCyrillic, "be": ScriptCyrillic, "bg": ScriptCyrillic,
+	"sr": ScriptCyrillic, "mk": ScriptCyrillic, "kk": ScriptCyrillic, "ky": ScriptCyrillic, "mn": ScriptCyrillic,
+	"th": ScriptThai,
+	"ar": ScriptArabic, "fa": ScriptArabic, "ur": ScriptArabic,
+	"he": ScriptHebrew, "iw": ScriptHebrew, "yi": ScriptHebrew,
+}
+
+// scriptSamples はフォントが用字系を描けるかを確かめる文字
+var scriptSamples = map[Script]string{
+	ScriptLatin:    "Aaé",
+	ScriptCJK:      "中文",
+	ScriptCyrillic: "Яя",
+	ScriptThai:     "กข",
+	ScriptArabic:   "عب",
+	ScriptHebrew:   "אב",
+}
+
+// languageSamples は同じ用字系でも描ける文字が違う言語の、確かめる文字
+var languageSamples = map[string]string{
+	"ja": "あア中",
+	"ko": "한글",
+}
+
+// LanguageScript は言語コード（"ja"、"zh-Hans"、"ru"、"pt_BR" など）の用字系を返す
+// 知らない言語はScriptLatin
+func LanguageScript(language string) Script {
+	if script, ok := languageScripts[primaryLanguage(language)]; ok {
+		return script
+	}
+	return ScriptLatin
+}
+
+// IsRightToLeft は言語が右から左に書く言語（アラビア文字・ヘブライ文字）かを返す
+func IsRightToLeft(language string) bool {
+	script := LanguageScript(language)
+	return script == ScriptArabic || script == ScriptHebrew
+}
+
+// primaryLanguage は言語コードの最初の部分を小文字で返す（"zh-Hans" -> "zh"）
+func primaryLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
+
+var (
+	defaultLatinFont     *TTFFont
+	defaultLatinFontOnce sync.Once
+	defaultLatinFontErr  error
+)
+
+// defaultLatinTTFFont はラテン文字・キリル文字・ギリシア文字を描ける、埋め込まれたGoフォントを返す
+func defaultLatinTTFFont() (*TTFFont, error) {
+	defaultLatinFontOnce.Do(func() {
+		defaultLatinFont, defaultLatinFontErr = LoadTTFFromBytes(goregular.TTF)
+	})
+	return defaultLatinFont, defaultLatinFontErr
+}
+
+// FontResolver は翻訳先の言語の文字を描けるフォントを選ぶ
+// 登録したフォントを登録順に、次に埋め込まれたフォント（Goフォント: ラテン・キリル・ギリシア文字、Koruri: 日本語・漢字）を試し、
+// 確かめる文字をすべて描けるものを使う。タイ文字・アラビア文字・ヘブライ文字・ハングルのフォントは埋め込んでいないため、Registerで登録する
+// 並行に使える
+// 設計書: docs/font_resolver_design.md
+type FontResolver struct {
+	mu    sync.RWMutex
+	fonts []registeredFont
+}
+
+// registeredFont は登録したフォント
+type registeredFont struct {
+	script Script
+	font   *TTFFont
+}
+
+// NewFontResolver は埋め込まれたフォントだけを使うFontResolverを作成する
+func NewFontResolver() *FontResolver {
+	return &FontResolver{}
+}
+
+// Register はscriptの文字を描くフォントを登録する（同じ用字系では先に登録したものを優先する）
+func (r *FontResolver) Register(script Script, font *TTFFont) error {
+	if font == nil {
+		return fmt.Errorf("font cannot be nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fonts = append(r.fonts, registeredFont{script: script, font: font})
+	return nil
+}
+
+// FontForLanguage は言語（"ja"、"ru"、"th" など）の文字を描けるフォントを返す
+// 言語の用字系で登録したフォント、埋め込まれたフォントの順に探す
+func (r *FontResolver) FontForLanguage(language string) (*TTFFont, error) {
+	script := LanguageScript(language)
+	sample, ok := languageSamples[primaryLanguage(language)]
+	if !ok {
+		sample = scriptSamples[script]
+	}
+
+	candidates, err := r.candidates(script)
+	if err != nil {
+		return nil, err
+	}
+	for _, font := range candidates {
+		if font.covers(sample) {
+			return font, nil
+		}
+	}
+	return nil, fmt.Errorf("no font for language %q (%s script): register one with FontResolver.Register", language, script)
+}
+
+// FontForText はtextのすべての文字（空白を除く）を描けるフォントを返す
+// 登録したフォント（用字系によらず登録順）、埋め込まれたフォントの順に探す
+func (r *FontResolver) FontForText(text string) (*TTFFont, error) {
+	candidates, err := r.candidates("")
+	if err != nil {
+		return nil, err
+	}
+	for _, font := range candidates {
+		if font.covers(text) {
+			return font, nil
+		}
+	}
+	return nil, fmt.Errorf("no font covers all characters of the text: register one with FontResolver.Register")
+}
+
+// candidates は試すフォントを順に返す（scriptが空の場合は登録したすべてのフォント）
+func (r *FontResolver) candidates(script Script) ([]*TTFFont, error) {
+	var fonts []*TTFFont
+	if r != nil {
+		r.mu.RLock()
+		for _, f := range r.fonts {
+			if script == "" || f.script == script {
+				fonts = append(fonts, f.font)
+			}
+		}
+		r.mu.RUnlock()
+	}
+
+	latin, err := defaultLatinTTFFont()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the embedded font: %w", err)
+	}
+	japanese, err := DefaultJapaneseFont()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the embedded font: %w", err)
+	}
+	return append(fonts, latin, japanese), nil
+}
+
+// covers はtextのすべての文字（空白と制御文字を除く）のグリフがあるかを返す
+func (f *TTFFont) covers(text string) bool {
+	for _, r := range text {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			continue
+		}
+		if index, err := f.internal.GetGlyphIndex(r); err != nil || index == 0 {
+			return false
+		}
+	}
+	return true
+}
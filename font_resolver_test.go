@@ -0,0 +1,140 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLanguageScript(t *testing.T) {
+	tests := []struct {
+		language string
+		want     Script
+	}{
+		{"ja", ScriptCJK},
+		{"zh-Hans", ScriptCJK},
+		{"ru", ScriptCyrillic},
+		{"uk_UA", ScriptCyrillic},
+		{"th", ScriptThai},
+		{"AR", ScriptArabic},
+		{"he", ScriptHebrew},
+		{"pt-BR", ScriptLatin},
+		{"", ScriptLatin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			if got := LanguageScript(tt.language); got != tt.want {
+				t.Errorf("LanguageScript(%q) = %q, want %q", tt.language, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRightToLeft(t *testing.T) {
+	for language, want := range map[string]bool{"ar": true, "fa-IR": true, "he": true, "en": false, "ja": false} {
+		if got := IsRightToLeft(language); got != want {
+			t.Errorf("IsRightToLeft(%q) = %v, want %v", language, got, want)
+		}
+	}
+}
+
+func TestFontResolver(t *testing.T) {
+	japanese, err := DefaultJapaneseFont()
+	if err != nil {
+		t.Fatalf("DefaultJapaneseFont failed: %v", err)
+	}
+	latin, err := defaultLatinTTFFont()
+	if err != nil {
+		t.Fatalf("defaultLatinTTFFont failed: %v", err)
+	}
+
+	var resolver *FontResolver // nilは埋め込まれたフォントだけを使う
+	for _, tt := range []struct {
+		language string
+		want     *TTFFont
+	}{
+		{"en", latin},
+		{"ru", latin},
+		{"ja", japanese},
+		{"zh-Hant", japanese},
+		{"th", nil},
+		{"ar", nil},
+		{"ko", nil}, // Koruriにハングルはない
+	} {
+		got, err := resolver.FontForLanguage(tt.language)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("FontForLanguage(%q) = %s, want an error", tt.language, got.Name())
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("FontForLanguage(%q) = %v, %v, want %s", tt.language, got, err, tt.want.Name())
+		}
+	}
+
+	for _, tt := range []struct {
+		text string
+		want *TTFFont
+	}{
+		{"Привет, мир", latin},
+		{"こんにちは 世界", japanese},
+		{"สวัสดี", nil},
+	} {
+		got, err := resolver.FontForText(tt.text)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("FontForText(%q) = %s, want an error", tt.text, got.Name())
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("FontForText(%q) = %v, %v, want %s", tt.text, got, err, tt.want.Name())
+		}
+	}
+
+	// 登録したフォントを埋め込まれたフォントより優先する
+	resolver = NewFontResolver()
+	if err := resolver.Register(ScriptLatin, japanese); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := resolver.FontForLanguage("fr"); err != nil || got != japanese {
+		t.Errorf("FontForLanguage(fr) = %v, %v, want the registered font", got, err)
+	}
+	if got, err := resolver.FontForLanguage("ru"); err != nil || got != latin {
+		t.Errorf("FontForLanguage(ru) = %v, %v, want the embedded font", got, err)
+	}
+	if err := resolver.Register(ScriptThai, nil); err == nil {
+		t.Error("Register should fail for a nil font")
+	}
+}
+
+func TestTranslatePDFToWriter_TargetLanguage(t *testing.T) {
+	opts := DefaultPDFTranslatorOptions(nil, "")
+	opts.TargetLanguage = "ru"
+	opts.Translator = TranslateFunc(func(text string) (string, error) {
+		return "Привет", nil
+	})
+
+	var out bytes.Buffer
+	if err := TranslatePDFToWriter(bytes.NewReader(translatorTestPDF(t, "Hello")), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriter failed: %v", err)
+	}
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	text, err := reader.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText failed: %v", err)
+	}
+	if !strings.Contains(text, "Привет") {
+		t.Errorf("translated text = %q, want Привет", text)
+	}
+
+	opts.TargetLanguage = "th"
+	if err := TranslatePDFToWriter(bytes.NewReader(translatorTestPDF(t, "Hello")), &out, opts); err == nil {
+		t.Error("TranslatePDFToWriter should fail without a Thai font")
+	}
+}
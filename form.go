@@ -0,0 +1,223 @@
+package gopdf
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// フィールドフラグ（/Ff）のビット
+const (
+	fieldFlagReadOnly  = 1 << 0
+	fieldFlagRequired  = 1 << 1
+	fieldFlagMultiline = 1 << 12
+)
+
+// FieldActions はフォームフィールドに設定するJavaScriptアクション
+// 各スクリプトはフィールドの追加アクション（/AA）として出力される。
+type FieldActions struct {
+	Keystroke string // 入力中に実行される（/K）
+	Format    string // 表示値の整形時に実行される（/F）
+	Validate  string // 値の確定時に実行される（/V）
+	Calculate string // 他のフィールドの変更時に再計算される（/C）
+}
+
+// IsEmpty はアクションが1つも設定されていないかを返す
+func (a FieldActions) IsEmpty() bool {
+	return a.Keystroke == "" && a.Format == "" && a.Validate == "" && a.Calculate == ""
+}
+
+// DateFieldActions は日付入力用のアクションを返す
+// formatはAcrobatの日付書式（例: "yyyy/mm/dd", "mm/dd/yyyy"）
+func DateFieldActions(format string) FieldActions {
+	quoted := strconv.Quote(format)
+	return FieldActions{
+		Keystroke: fmt.Sprintf("AFDate_KeystrokeEx(%s);", quoted),
+		Format:    fmt.Sprintf("AFDate_FormatEx(%s);", quoted),
+	}
+}
+
+// NumberFieldActions は数値入力用のアクションを返す
+// decimalsは小数点以下の桁数
+func NumberFieldActions(decimals int) FieldActions {
+	return FieldActions{
+		Keystroke: fmt.Sprintf("AFNumber_Keystroke(%d, 0, 0, 0, \"\", true);", decimals),
+		Format:    fmt.Sprintf("AFNumber_Format(%d, 0, 0, 0, \"\", true);", decimals),
+	}
+}
+
+// RangeFieldActions は数値の範囲を検証するアクションを返す
+// NumberFieldActionsと組み合わせて使用する。
+func RangeFieldActions(decimals int, min, max float64) FieldActions {
+	actions := NumberFieldActions(decimals)
+	actions.Validate = fmt.Sprintf("AFRange_Validate(true, %s, true, %s);",
+		strconv.FormatFloat(min, 'f', -1, 64),
+		strconv.FormatFloat(max, 'f', -1, 64))
+	return actions
+}
+
+// TextField はテキスト入力フォームフィールド
+type TextField struct {
+	Name      string  // フィールド名（必須、ドキュメント内で一意）
+	X, Y      float64 // 左下座標（ポイント）
+	Width     float64 // 幅（ポイント）
+	Height    float64 // 高さ（ポイント）
+	Value     string  // 初期値
+	FontSize  float64 // フォントサイズ（0 = 自動）
+	Tooltip   string  // ツールチップ（/TU）
+	MaxLength int     // 最大文字数（0 = 無制限）
+	Required  bool    // 入力必須
+	ReadOnly  bool    // 読み取り専用
+	Multiline bool    // 複数行入力
+	Actions   FieldActions
+}
+
+// AddTextField はページにテキスト入力フィールドを追加する
+// フィールドはドキュメントのAcroFormに登録され、
+// 設定されたJavaScriptアクションはビューア上で実行される。
+func (p *Page) AddTextField(field TextField) error {
+	if field.Name == "" {
+		return fmt.Errorf("field name is required")
+	}
+	if field.Width <= 0 || field.Height <= 0 {
+		return fmt.Errorf("field %q has invalid size: %.2fx%.2f", field.Name, field.Width, field.Height)
+	}
+	// フィールド名はAcroForm全体で一意にする（アクションは名前でフィールドを参照する）
+	pages := []*Page{p}
+	if p.doc != nil {
+		pages = p.doc.pages
+	}
+	for _, page := range pages {
+		for _, annot := range page.annotations {
+			if f, ok := annot.(*textFieldAnnotation); ok && f.field.Name == field.Name {
+				return fmt.Errorf("field %q already exists in the document", field.Name)
+			}
+		}
+	}
+
+	p.annotations = append(p.annotations, &textFieldAnnotation{field: field})
+	return nil
+}
+
+// textFieldAnnotation はテキストフィールドとそのウィジェット注釈を兼ねる辞書を生成する
+type textFieldAnnotation struct {
+	field TextField
+}
+
+func (a *textFieldAnnotation) hasCalculation() bool {
+	return a.field.Actions.Calculate != ""
+}
+
+func (a *textFieldAnnotation) annotationDict(pageRef *core.Reference) core.Dictionary {
+	f := a.field
+
+	flags := 0
+	if f.ReadOnly {
+		flags |= fieldFlagReadOnly
+	}
+	if f.Required {
+		flags |= fieldFlagRequired
+	}
+	if f.Multiline {
+		flags |= fieldFlagMultiline
+	}
+
+	dict := core.Dictionary{
+		core.Name("Type"):    core.Name("Annot"),
+		core.Name("Subtype"): core.Name("Widget"),
+		core.Name("FT"):      core.Name("Tx"),
+		core.Name("T"):       textString(f.Name),
+		core.Name("Rect"):    rectArray(f.X, f.Y, f.Width, f.Height),
+		core.Name("F"):       core.Integer(4), // Print
+		core.Name("P"):       pageRef,
+		core.Name("DA"):      core.String(fmt.Sprintf("/Helv %s Tf 0 g", strconv.FormatFloat(f.FontSize, 'f', -1, 64))),
+	}
+
+	if flags != 0 {
+		dict[core.Name("Ff")] = core.Integer(flags)
+	}
+	if f.Value != "" {
+		dict[core.Name("V")] = textString(f.Value)
+	}
+	if f.Tooltip != "" {
+		dict[core.Name("TU")] = textString(f.Tooltip)
+	}
+	if f.MaxLength > 0 {
+		dict[core.Name("MaxLen")] = core.Integer(f.MaxLength)
+	}
+	if aa := fieldActionsDict(f.Actions); len(aa) > 0 {
+		dict[core.Name("AA")] = aa
+	}
+
+	return dict
+}
+
+// fieldActionsDict はFieldActionsから追加アクション辞書（/AA）を生成する
+func fieldActionsDict(actions FieldActions) core.Dictionary {
+	aa := core.Dictionary{}
+	if actions.Keystroke != "" {
+		aa[core.Name("K")] = javaScriptAction(actions.Keystroke)
+	}
+	if actions.Format != "" {
+		aa[core.Name("F")] = javaScriptAction(actions.Format)
+	}
+	if actions.Validate != "" {
+		aa[core.Name("V")] = javaScriptAction(actions.Validate)
+	}
+	if actions.Calculate != "" {
+		aa[core.Name("C")] = javaScriptAction(actions.Calculate)
+	}
+	return aa
+}
+
+// createAcroFormDict はCatalogに設定するAcroForm辞書を生成する
+func createAcroFormDict(fieldRefs []formFieldRef) core.Dictionary {
+	fields := make(core.Array, 0, len(fieldRefs))
+	calculationOrder := core.Array{}
+	hasSignature := false
+	needAppearances := false
+	for _, f := range fieldRefs {
+		fields = append(fields, f.ref)
+		if f.calculation {
+			calculationOrder = append(calculationOrder, f.ref)
+		}
+		if f.signature {
+			hasSignature = true
+		} else {
+			needAppearances = true
+		}
+	}
+
+	acroForm := core.Dictionary{
+		core.Name("Fields"): fields,
+		core.Name("DA"):     core.String("/Helv 0 Tf 0 g"),
+		core.Name("DR"): core.Dictionary{
+			core.Name("Font"): core.Dictionary{
+				core.Name("Helv"): core.Dictionary{
+					core.Name("Type"):     core.Name("Font"),
+					core.Name("Subtype"):  core.Name("Type1"),
+					core.Name("BaseFont"): core.Name("Helvetica"),
+					core.Name("Encoding"): core.Name("WinAnsiEncoding"),
+				},
+			},
+		},
+	}
+
+	// 外観ストリームを持たないテキストフィールドはビューアに外観を生成させる
+	if needAppearances {
+		acroForm[core.Name("NeedAppearances")] = core.Boolean(true)
+	}
+
+	// 署名フィールドがある場合は署名の存在と追記専用であることを示す
+	if hasSignature {
+		acroForm[core.Name("SigFlags")] = core.Integer(sigFlagsSignaturesExist | sigFlagsAppendOnly)
+	}
+
+	// 計算アクションを持つフィールドは計算順序（/CO）に登録する
+	if len(calculationOrder) > 0 {
+		acroForm[core.Name("CO")] = calculationOrder
+	}
+
+	return acroForm
+}
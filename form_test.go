@@ -0,0 +1,189 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+func TestPage_AddTextField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   TextField
+		wantErr bool
+	}{
+		{
+			name:  "valid field",
+			field: TextField{Name: "name", X: 100, Y: 700, Width: 200, Height: 20},
+		},
+		{
+			name:    "missing name",
+			field:   TextField{X: 100, Y: 700, Width: 200, Height: 20},
+			wantErr: true,
+		},
+		{
+			name:    "invalid size",
+			field:   TextField{Name: "name", X: 100, Y: 700, Width: 0, Height: 20},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			page := doc.AddPage(PageSizeA4, Portrait)
+			err := page.AddTextField(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddTextField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPage_AddTextField_Duplicate(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	field := TextField{Name: "date", X: 100, Y: 700, Width: 200, Height: 20}
+
+	if err := page.AddTextField(field); err != nil {
+		t.Fatalf("AddTextField() failed: %v", err)
+	}
+	if err := page.AddTextField(field); err == nil {
+		t.Error("AddTextField() with duplicate name should fail")
+	}
+
+	// 別のページでも同じ名前のフィールドは追加できない
+	page2 := doc.AddPage(PageSizeA4, Portrait)
+	if err := page2.AddTextField(field); err == nil {
+		t.Error("AddTextField() with a name used on another page should fail")
+	}
+	field.Name = "amount"
+	if err := page2.AddTextField(field); err != nil {
+		t.Errorf("AddTextField() with a new name failed: %v", err)
+	}
+}
+
+func TestFieldActionHelpers(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions FieldActions
+		want    []string
+	}{
+		{
+			name:    "date",
+			actions: DateFieldActions("yyyy/mm/dd"),
+			want:    []string{`AFDate_KeystrokeEx("yyyy/mm/dd");`, `AFDate_FormatEx("yyyy/mm/dd");`},
+		},
+		{
+			name:    "number",
+			actions: NumberFieldActions(2),
+			want:    []string{"AFNumber_Keystroke(2,", "AFNumber_Format(2,"},
+		},
+		{
+			name:    "range",
+			actions: RangeFieldActions(0, 1, 99.5),
+			want:    []string{"AFRange_Validate(true, 1, true, 99.5);"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := tt.actions.Keystroke + tt.actions.Format + tt.actions.Validate + tt.actions.Calculate
+			for _, w := range tt.want {
+				if !strings.Contains(all, w) {
+					t.Errorf("actions %+v do not contain %q", tt.actions, w)
+				}
+			}
+			if tt.actions.IsEmpty() {
+				t.Error("IsEmpty() = true, want false")
+			}
+		})
+	}
+}
+
+func TestTextField_Roundtrip(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+
+	if err := page.AddTextField(TextField{
+		Name:     "birthday",
+		X:        100,
+		Y:        700,
+		Width:    150,
+		Height:   20,
+		Required: true,
+		Actions:  DateFieldActions("yyyy/mm/dd"),
+	}); err != nil {
+		t.Fatalf("AddTextField() failed: %v", err)
+	}
+	if err := page.AddTextField(TextField{
+		Name:    "total",
+		X:       100,
+		Y:       650,
+		Width:   150,
+		Height:  20,
+		Actions: FieldActions{Calculate: "event.value = 1 + 1;"},
+	}); err != nil {
+		t.Fatalf("AddTextField() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	defer reader.Close()
+
+	catalog, err := reader.r.GetCatalog()
+	if err != nil {
+		t.Fatalf("GetCatalog() failed: %v", err)
+	}
+
+	acroForm, ok := catalog[core.Name("AcroForm")].(core.Dictionary)
+	if !ok {
+		t.Fatal("Catalog has no AcroForm dictionary")
+	}
+	fields, ok := acroForm[core.Name("Fields")].(core.Array)
+	if !ok || len(fields) != 2 {
+		t.Fatalf("AcroForm Fields = %v, want 2 fields", acroForm[core.Name("Fields")])
+	}
+	co, ok := acroForm[core.Name("CO")].(core.Array)
+	if !ok || len(co) != 1 {
+		t.Errorf("AcroForm CO = %v, want 1 field", acroForm[core.Name("CO")])
+	}
+
+	pageDict, err := reader.r.GetPage(0)
+	if err != nil {
+		t.Fatalf("GetPage() failed: %v", err)
+	}
+	annots, ok := pageDict[core.Name("Annots")].(core.Array)
+	if !ok || len(annots) != 2 {
+		t.Fatalf("Page Annots = %v, want 2 annotations", pageDict[core.Name("Annots")])
+	}
+
+	widget, err := reader.r.ResolveReference(annots[0].(*core.Reference))
+	if err != nil {
+		t.Fatalf("ResolveReference() failed: %v", err)
+	}
+	widgetDict := widget.(core.Dictionary)
+	if got := widgetDict[core.Name("Ff")]; got != core.Integer(fieldFlagRequired) {
+		t.Errorf("Ff = %v, want %d", got, fieldFlagRequired)
+	}
+	aa, ok := widgetDict[core.Name("AA")].(core.Dictionary)
+	if !ok {
+		t.Fatal("widget has no AA dictionary")
+	}
+	format, ok := aa[core.Name("F")].(core.Dictionary)
+	if !ok {
+		t.Fatal("AA has no format action")
+	}
+	if js := string(format[core.Name("JS")].(core.String)); !strings.Contains(js, "AFDate_FormatEx") {
+		t.Errorf("format JS = %q, want AFDate_FormatEx", js)
+	}
+}
@@ -7,6 +7,5 @@ toolchain go1.24.9
 require (
 	github.com/gomarkdown/markdown v0.0.0-20250810172220-2e2c11897d1a
 	golang.org/x/image v0.32.0
+	golang.org/x/text v0.30.0
 )
-
-require golang.org/x/text v0.30.0 // indirect
@@ -7,8 +7,10 @@ import (
 	"io"
 	"os"
 
+	"github.com/ryomak/gopdf/internal/core"
 	"github.com/ryomak/gopdf/internal/image/jpeg"
 	"github.com/ryomak/gopdf/internal/image/png"
+	"github.com/ryomak/gopdf/internal/writer"
 )
 
 // Image represents an image that can be embedded in a PDF
@@ -164,3 +166,32 @@ func (r *bytesReader) Read(p []byte) (int, error) {
 	r.pos += n
 	return n, nil
 }
+
+// writeImageXObject は画像をXObjectとして出力し、その参照を返す
+// SMask（アルファチャンネル）がある場合は先に出力し、/SMaskで参照する
+func writeImageXObject(w *writer.Writer, img *Image) (*core.Reference, error) {
+	imageDict := core.Dictionary{
+		core.Name("Type"):             core.Name("XObject"),
+		core.Name("Subtype"):          core.Name("Image"),
+		core.Name("Width"):            core.Integer(img.Width),
+		core.Name("Height"):           core.Integer(img.Height),
+		core.Name("ColorSpace"):       core.Name(img.ColorSpace),
+		core.Name("BitsPerComponent"): core.Integer(img.BitsPerComponent),
+		core.Name("Filter"):           core.Name(img.Filter),
+		core.Name("Length"):           core.Integer(len(img.Data)),
+	}
+
+	if img.SMask != nil {
+		smaskRef, err := writeImageXObject(w, img.SMask)
+		if err != nil {
+			return nil, err
+		}
+		imageDict[core.Name("SMask")] = smaskRef
+	}
+
+	imgNum, err := w.AddObject(&core.Stream{Dict: imageDict, Data: img.Data})
+	if err != nil {
+		return nil, err
+	}
+	return &core.Reference{ObjectNumber: imgNum}, nil
+}
@@ -0,0 +1,195 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// ImportedPage は既存のPDFから取り込んだページ
+// ページの内容はフォームXObjectとして出力され、DrawImportedPageで何度でも描画できる
+// （レターヘッドや帳票の背景の上に、PageのAPIで描き足す用途）
+type ImportedPage struct {
+	source   *sourcePage
+	box      Rectangle // 表示される領域（CropBoxとMediaBoxの重なり）
+	rotation int       // ページの/Rotate
+	width    float64   // 表示される向きの幅
+	height   float64   // 表示される向きの高さ
+}
+
+// Width は表示される向きのページの幅（ポイント）を返す
+func (t *ImportedPage) Width() float64 {
+	return t.width
+}
+
+// Height は表示される向きのページの高さ（ポイント）を返す
+func (t *ImportedPage) Height() float64 {
+	return t.height
+}
+
+// ImportPage は読み込んだPDFのページ（0-indexed）を、描画に使えるように取り込む
+// 取り込むのは表示される領域の内容で、/Rotateは適用済み（表示される向きで描画される）。注釈は含まない
+// 同じPDFから取り込んだページが共有するフォントや画像は、一度だけ出力する
+// 複製はWriteToで行うため、rはWriteToが終わるまで閉じないこと
+// 設計書: docs/import_page_design.md
+func (d *Document) ImportPage(r *PDFReader, pageNum int) (*ImportedPage, error) {
+	imp, ok := d.templateImports[r]
+	if !ok {
+		imp = &pdfImport{src: r.r, prune: true}
+	}
+	tpl, err := newImportedPage(r, imp, pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.templateImports == nil {
+		d.templateImports = make(map[*PDFReader]*pdfImport)
+	}
+	d.templateImports[r] = imp
+	return tpl, nil
+}
+
+// newImportedPage は読み込んだPDFのページ（0-indexed）を、impから追加したページとして取り込む
+func newImportedPage(r *PDFReader, imp *pdfImport, pageNum int) (*ImportedPage, error) {
+	if r.r.IsEncrypted() && !r.r.IsAuthenticated() {
+		return nil, fmt.Errorf("PDF is encrypted: authenticate with a password before importing its pages")
+	}
+
+	refs, err := r.r.GetPageReferences()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pages: %w", err)
+	}
+	if pageNum < 0 || pageNum >= len(refs) {
+		return nil, fmt.Errorf("page number %d out of range [0, %d)", pageNum, len(refs))
+	}
+	dict, err := r.r.GetPageByReference(refs[pageNum])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page %d: %w", pageNum, err)
+	}
+
+	box := r.pageBoxes(dict).Visible
+	rotation := r.pageRotation(dict)
+	width, height := rotatePageSize(rotation, box.Width, box.Height)
+	return &ImportedPage{
+		source:   &sourcePage{pdf: imp, ref: refs[pageNum], dict: dict},
+		box:      box,
+		rotation: rotation,
+		width:    width,
+		height:   height,
+	}, nil
+}
+
+// DrawImportedPage は取り込んだページを、左下(x, y)から幅width・高さheightの範囲に描画する
+// widthとheightが両方0の場合は元の大きさ、片方だけ0の場合は縦横比を保って描画する
+func (p *Page) DrawImportedPage(tpl *ImportedPage, x, y, width, height float64) error {
+	if tpl == nil {
+		return fmt.Errorf("imported page cannot be nil")
+	}
+	if tpl.width <= 0 || tpl.height <= 0 {
+		return fmt.Errorf("imported page has an empty visible area")
+	}
+	switch {
+	case width == 0 && height == 0:
+		width, height = tpl.width, tpl.height
+	case width == 0:
+		width = tpl.width * height / tpl.height
+	case height == 0:
+		height = tpl.height * width / tpl.width
+	}
+
+	index := len(p.templates)
+	for i, t := range p.templates {
+		if t == tpl {
+			index = i
+			break
+		}
+	}
+	if index == len(p.templates) {
+		p.templates = append(p.templates, tpl)
+	}
+
+	// フォームの座標系は表示される向きの (0, 0)-(Width, Height) なので、拡大して移動する
+	fmt.Fprintf(&p.content, "q\n")
+	fmt.Fprintf(&p.content, "%.4f 0 0 %.4f %.2f %.2f cm\n", width/tpl.width, height/tpl.height, x, y)
+	fmt.Fprintf(&p.content, "/%s Do\n", templateKey(index))
+	fmt.Fprintf(&p.content, "Q\n")
+	return nil
+}
+
+// templateKey はページのリソースでの取り込んだページの名前（Tpl1, Tpl2, ...）を返す
+func templateKey(index int) string {
+	return fmt.Sprintf("Tpl%d", index+1)
+}
+
+// formMatrix は元のページの座標を、表示される向きの (0, 0)-(Width, Height) に移すフォームの/Matrixを返す
+func (t *ImportedPage) formMatrix() core.Array {
+	return matrixArray(displayMatrix(t.box, t.rotation))
+}
+
+// displayMatrix はページの座標を、表示される向きで表示される領域の左下を原点とする座標に移す行列を返す
+// 表示される領域の左下を原点に移してから、rotatePointと同じく時計回りに回転する
+func displayMatrix(box Rectangle, rotation int) [6]float64 {
+	llx, lly := box.X, box.Y
+	w, h := box.Width, box.Height
+	switch rotation {
+	case 90:
+		return [6]float64{0, -1, 1, 0, -lly, w + llx}
+	case 180:
+		return [6]float64{-1, 0, 0, -1, w + llx, h + lly}
+	case 270:
+		return [6]float64{0, 1, -1, 0, h + lly, -llx}
+	default:
+		return [6]float64{1, 0, 0, 1, -llx, -lly}
+	}
+}
+
+// invertMatrix は変換行列 [a b c d e f] の逆行列を返す（回転と拡大縮小、移動のみを想定）
+func invertMatrix(m [6]float64) [6]float64 {
+	det := m[0]*m[3] - m[1]*m[2]
+	a, b, c, d := m[3]/det, -m[1]/det, -m[2]/det, m[0]/det
+	return [6]float64{a, b, c, d, -(m[4]*a + m[5]*c), -(m[4]*b + m[5]*d)}
+}
+
+// matrixArray は変換行列をPDFの配列にする
+func matrixArray(m [6]float64) core.Array {
+	matrix := make(core.Array, len(m))
+	for i, v := range m {
+		matrix[i] = core.Real(v)
+	}
+	return matrix
+}
+
+// writeTemplate は取り込んだページをフォームXObjectとして出力する
+// リソースはcで複製する（参照先のオブジェクトはflushで出力される）
+func writeTemplate(w *writer.Writer, c *objectCopier, tpl *ImportedPage) (*core.Reference, error) {
+	page := tpl.source.dict
+	data, err := c.src.GetPageContents(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page contents: %w", err)
+	}
+
+	resources := page[core.Name("Resources")]
+	if tpl.source.pdf.prune {
+		if pruned, ok := prunePageResources(c.src, page); ok {
+			resources = pruned
+		}
+	}
+
+	dict := core.Dictionary{
+		core.Name("Type"):    core.Name("XObject"),
+		core.Name("Subtype"): core.Name("Form"),
+		core.Name("BBox"):    rectArray(tpl.box.X, tpl.box.Y, tpl.box.Width, tpl.box.Height),
+		core.Name("Matrix"):  tpl.formMatrix(),
+		core.Name("Length"):  core.Integer(len(data)),
+	}
+	if resources != nil {
+		dict[core.Name("Resources")] = c.copyObject(resources)
+	}
+
+	num, err := w.AddObject(&core.Stream{Dict: dict, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return &core.Reference{ObjectNumber: num}, nil
+}
@@ -0,0 +1,184 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// importSourcePDF は左下に赤、その右に青の50×50ポイントの矩形を描いた200×100ポイントのページを作成する
+func importSourcePDF(t *testing.T, pageExtra string) *PDFReader {
+	t.Helper()
+	contents := "1 0 0 rg 0 0 50 50 re f 0 0 1 rg 50 0 50 50 re f"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 100] /Contents 4 0 R " + pageExtra + " >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+func TestPage_DrawImportedPage(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	tests := []struct {
+		name       string
+		pageExtra  string
+		draw       [4]float64 // x, y, width, height
+		wantSize   [2]float64 // 取り込んだページの幅と高さ
+		wantPixels map[image.Point]color.RGBA
+	}{
+		{
+			name:     "natural size",
+			wantSize: [2]float64{200, 100},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 25, Y: 175}:  red, // ページの(25, 25)
+				{X: 75, Y: 175}:  blue,
+				{X: 150, Y: 175}: white,
+			},
+		},
+		{
+			name:     "scaled and moved",
+			draw:     [4]float64{100, 100, 100, 0},
+			wantSize: [2]float64{200, 100},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 110, Y: 90}: red, // ページの(110, 110)
+				{X: 135, Y: 90}: blue,
+				{X: 25, Y: 175}: white,
+			},
+		},
+		{
+			name:      "crop box",
+			pageExtra: "/CropBox [50 0 200 100]",
+			wantSize:  [2]float64{150, 100},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 25, Y: 175}: blue,
+				{X: 75, Y: 175}: white,
+			},
+		},
+		{
+			name:      "rotate 90",
+			pageExtra: "/Rotate 90",
+			wantSize:  [2]float64{100, 200},
+			wantPixels: map[image.Point]color.RGBA{
+				// 時計回りに90度回転すると、元のページの左下は左上、その右は上から2番目になる
+				{X: 25, Y: 25}:  red,
+				{X: 25, Y: 75}:  blue,
+				{X: 75, Y: 175}: white,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			tpl, err := doc.ImportPage(importSourcePDF(t, tt.pageExtra), 0)
+			if err != nil {
+				t.Fatalf("ImportPage failed: %v", err)
+			}
+			if tpl.Width() != tt.wantSize[0] || tpl.Height() != tt.wantSize[1] {
+				t.Errorf("size = %vx%v, want %vx%v", tpl.Width(), tpl.Height(), tt.wantSize[0], tt.wantSize[1])
+			}
+
+			page := doc.AddPage(PageSize{Width: 200, Height: 200}, Portrait)
+			if err := page.DrawImportedPage(tpl, tt.draw[0], tt.draw[1], tt.draw[2], tt.draw[3]); err != nil {
+				t.Fatalf("DrawImportedPage failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+			img, err := reader.RenderPage(0, RenderOptions{})
+			if err != nil {
+				t.Fatalf("RenderPage failed: %v", err)
+			}
+			for p, want := range tt.wantPixels {
+				if got := color.RGBAModel.Convert(img.At(p.X, p.Y)); got != want {
+					t.Errorf("pixel %v = %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_ImportPage_SharedForm(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(splitSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+
+	doc := New()
+	letterhead, err := doc.ImportPage(source, 0)
+	if err != nil {
+		t.Fatalf("ImportPage failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		page := doc.AddPage(PageSizeA4, Portrait)
+		if err := page.DrawImportedPage(letterhead, 0, 0, 0, 0); err != nil {
+			t.Fatalf("DrawImportedPage failed: %v", err)
+		}
+		if err := page.DrawImportedPage(letterhead, 0, 400, 0, 0); err != nil {
+			t.Fatalf("DrawImportedPage failed: %v", err)
+		}
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatalf("SetFont failed: %v", err)
+		}
+		if err := page.DrawText("Body", 50, 300); err != nil {
+			t.Fatalf("DrawText failed: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	output := buf.String()
+
+	// 取り込んだページは1つのフォームとして出力され、使わないフォント（Courier）は含まれない
+	if n := strings.Count(output, "/Subtype /Form"); n != 1 {
+		t.Errorf("form XObjects = %d, want 1", n)
+	}
+	if strings.Contains(output, "/Courier") {
+		t.Error("output contains the unused font")
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+	for i := 0; i < 2; i++ {
+		if got := pageResourceNames(t, reader, i); strings.Join(got, ",") != "Font:F1,XObject:Tpl1" {
+			t.Errorf("page %d resources = %v, want [Font:F1 XObject:Tpl1]", i, got)
+		}
+	}
+}
+
+func TestDocument_ImportPage_Errors(t *testing.T) {
+	doc := New()
+	if _, err := doc.ImportPage(importSourcePDF(t, ""), 1); err == nil {
+		t.Error("expected error for out of range page")
+	}
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.DrawImportedPage(nil, 0, 0, 0, 0); err == nil {
+		t.Error("expected error for nil imported page")
+	}
+}
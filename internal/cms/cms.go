@@ -0,0 +1,264 @@
+// Package cms builds CMS (PKCS#7) SignedData structures used for PDF signatures.
+// Only the subset required by detached PDF signatures (RFC 5652) is implemented.
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Object identifiers used in SignedData
+var (
+	OIDData           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	OIDSignedData     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	OIDContentType    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	OIDMessageDigest  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	OIDSigningTime    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	OIDTSTInfo        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	OIDTimeStampToken = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+
+	OIDSHA256          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	OIDRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	OIDECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+// SignOptions holds the inputs for creating a detached signature
+type SignOptions struct {
+	Signer      crypto.Signer       // Private key (may be backed by an HSM or KMS)
+	Certificate *x509.Certificate   // Signer certificate
+	Chain       []*x509.Certificate // Intermediate certificates to embed
+	SigningTime time.Time           // Value of the signingTime attribute (zero = omitted)
+
+	// Timestamp, if set, is called with the signature value and returns an RFC 3161
+	// timestamp token that is added as the id-aa-timeStampToken unsigned attribute.
+	Timestamp func(signature []byte) ([]byte, error)
+}
+
+// algorithmIdentifier is the ASN.1 AlgorithmIdentifier structure
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// issuerAndSerialNumber identifies the signer certificate
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// attribute is a CMS attribute (a type and a SET OF values)
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// signerInfo is the SignerInfo structure
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue // IssuerAndSerialNumber or [0] SubjectKeyIdentifier
+	DigestAlgorithm    algorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm algorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// encapsulatedContentInfo has no eContent for detached signatures
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"optional,explicit,tag:0"`
+}
+
+// signedData is the SignedData structure
+type signedData struct {
+	Version          int
+	DigestAlgorithms []algorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// contentInfo is the outer ContentInfo wrapper
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0"` // [0] EXPLICIT SignedData
+
+}
+
+// SignDetached creates a DER-encoded detached SignedData over content whose
+// SHA-256 digest is messageDigest.
+func SignDetached(messageDigest []byte, opts SignOptions) ([]byte, error) {
+	return sign(messageDigest, nil, OIDData, opts)
+}
+
+// SignAttached creates a DER-encoded SignedData that encapsulates content of the given type
+func SignAttached(content []byte, contentType asn1.ObjectIdentifier, opts SignOptions) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	return sign(digest[:], content, contentType, opts)
+}
+
+// sign builds SignedData; content is nil for detached signatures
+func sign(messageDigest, content []byte, contentType asn1.ObjectIdentifier, opts SignOptions) ([]byte, error) {
+	if opts.Signer == nil {
+		return nil, fmt.Errorf("signer is required")
+	}
+	if opts.Certificate == nil {
+		return nil, fmt.Errorf("certificate is required")
+	}
+
+	sigAlg, err := signatureAlgorithm(opts.Signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	// 署名対象の属性（DERのSET OFとして署名し、埋め込み時は[0] IMPLICITにする）
+	attrs := []attributeValue{
+		{OIDContentType, contentType},
+		{OIDMessageDigest, messageDigest},
+	}
+	if !opts.SigningTime.IsZero() {
+		attrs = append(attrs, attributeValue{OIDSigningTime, opts.SigningTime.UTC()})
+	}
+	signedAttrs, err := marshalAttributes(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := crypto.SHA256.New()
+	digest.Write(signedAttrs)
+	signature, err := opts.Signer.Sign(rand.Reader, digest.Sum(nil), crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	var unsignedAttrs asn1.RawValue
+	if opts.Timestamp != nil {
+		token, err := opts.Timestamp(signature)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get timestamp: %w", err)
+		}
+		attrs, err := marshalAttributes([]attributeValue{{OIDTimeStampToken, asn1.RawValue{FullBytes: token}}})
+		if err != nil {
+			return nil, err
+		}
+		unsignedAttrs = implicitSet(attrs, 1)
+	}
+
+	encap := encapsulatedContentInfo{EContentType: contentType}
+	if content != nil {
+		octets, err := asn1.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		encap.EContent = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: octets}
+	}
+
+	sid, err := asn1.Marshal(issuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: opts.Certificate.RawIssuer},
+		SerialNumber: opts.Certificate.SerialNumber,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signer identifier: %w", err)
+	}
+
+	certs := make([]byte, 0, len(opts.Certificate.Raw))
+	certs = append(certs, opts.Certificate.Raw...)
+	for _, c := range opts.Chain {
+		certs = append(certs, c.Raw...)
+	}
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []algorithmIdentifier{{Algorithm: OIDSHA256}},
+		EncapContentInfo: encap,
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    algorithmIdentifier{Algorithm: OIDSHA256},
+			SignedAttrs:        implicitSet(signedAttrs, 0),
+			SignatureAlgorithm: sigAlg,
+			Signature:          signature,
+			UnsignedAttrs:      unsignedAttrs,
+		}},
+	}
+
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SignedData: %w", err)
+	}
+
+	return asn1.Marshal(contentInfo{
+		ContentType: OIDSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// signatureAlgorithm returns the SignerInfo signature algorithm for the public key
+func signatureAlgorithm(pub crypto.PublicKey) (algorithmIdentifier, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return algorithmIdentifier{Algorithm: OIDRSAEncryption, Parameters: asn1.NullRawValue}, nil
+	case *ecdsa.PublicKey:
+		return algorithmIdentifier{Algorithm: OIDECDSAWithSHA256}, nil
+	default:
+		return algorithmIdentifier{}, fmt.Errorf("unsupported signer key type: %T", pub)
+	}
+}
+
+// attributeValue is a single-valued attribute before encoding
+type attributeValue struct {
+	oid   asn1.ObjectIdentifier
+	value any
+}
+
+// marshalAttributes encodes attributes as a DER SET OF Attribute.
+// DER requires the elements of a SET OF to be sorted by their encoding.
+func marshalAttributes(attrs []attributeValue) ([]byte, error) {
+	encoded := make([][]byte, 0, len(attrs))
+	for _, a := range attrs {
+		value, err := asn1.Marshal(a.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal attribute %v: %w", a.oid, err)
+		}
+		der, err := asn1.Marshal(attribute{
+			Type:   a.oid,
+			Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal attribute %v: %w", a.oid, err)
+		}
+		encoded = append(encoded, der)
+	}
+
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+
+	return asn1.Marshal(asn1.RawValue{
+		Class:      asn1.ClassUniversal,
+		Tag:        asn1.TagSet,
+		IsCompound: true,
+		Bytes:      bytes.Join(encoded, nil),
+	})
+}
+
+// implicitSet re-tags a DER SET as a context-specific [tag] IMPLICIT value
+func implicitSet(set []byte, tag int) asn1.RawValue {
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(set, &raw); err != nil {
+		return asn1.RawValue{}
+	}
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: tag, IsCompound: true, Bytes: raw.Bytes}
+}
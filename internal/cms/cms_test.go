@@ -0,0 +1,173 @@
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, key crypto.Signer) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cms test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestSignDetached(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+	digest := sha256.Sum256([]byte("content"))
+
+	der, err := SignDetached(digest[:], SignOptions{Signer: key, Certificate: cert, SigningTime: time.Now()})
+	if err != nil {
+		t.Fatalf("SignDetached() failed: %v", err)
+	}
+
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil || len(rest) != 0 {
+		t.Fatalf("failed to parse ContentInfo: %v", err)
+	}
+	if !ci.ContentType.Equal(OIDSignedData) {
+		t.Errorf("ContentType = %v, want %v", ci.ContentType, OIDSignedData)
+	}
+	if ci.Content.Class != asn1.ClassContextSpecific || ci.Content.Tag != 0 {
+		t.Errorf("content is not tagged [0]: class %d tag %d", ci.Content.Class, ci.Content.Tag)
+	}
+	if !bytes.Contains(der, cert.Raw) {
+		t.Error("certificate is not embedded")
+	}
+	if !bytes.Contains(der, digest[:]) {
+		t.Error("message digest is not embedded")
+	}
+}
+
+func TestSignDetached_Errors(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts SignOptions
+	}{
+		{"missing signer", SignOptions{Certificate: newTestCertificate(t, ecKey)}},
+		{"missing certificate", SignOptions{Signer: ecKey}},
+		{"unsupported key", SignOptions{Signer: edKey, Certificate: newTestCertificate(t, edKey)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SignDetached(make([]byte, 32), tt.opts); err == nil {
+				t.Error("SignDetached() should fail")
+			}
+		})
+	}
+}
+
+func TestMarshalAttributes_Sorted(t *testing.T) {
+	der, err := marshalAttributes([]attributeValue{
+		{OIDSigningTime, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{OIDMessageDigest, []byte{1, 2, 3}},
+		{OIDContentType, OIDData},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var set asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Tag != asn1.TagSet {
+		t.Fatalf("tag = %d, want SET", set.Tag)
+	}
+
+	var prev []byte
+	for rest := set.Bytes; len(rest) > 0; {
+		var elem asn1.RawValue
+		var err error
+		rest, err = asn1.Unmarshal(rest, &elem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != nil && bytes.Compare(prev, elem.FullBytes) > 0 {
+			t.Error("attributes are not in DER order")
+		}
+		prev = elem.FullBytes
+	}
+}
+
+func TestParseAndVerifyDetached(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+	content := []byte("signed content")
+	digest := sha256.Sum256(content)
+	signingTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	der, err := SignDetached(digest[:], SignOptions{Signer: key, Certificate: cert, SigningTime: signingTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// PDFの/Contentsと同様に末尾のゼロ埋めを付けてもパースできる
+	sd, err := Parse(append(der, make([]byte, 64)...))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if !sd.Signer.Equal(cert) {
+		t.Error("Signer does not match the certificate")
+	}
+	if !sd.SigningTime.Equal(signingTime) {
+		t.Errorf("SigningTime = %v, want %v", sd.SigningTime, signingTime)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr bool
+	}{
+		{"original content", content, false},
+		{"modified content", []byte("signed c0ntent"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sd.VerifyDetached(bytes.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyDetached() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
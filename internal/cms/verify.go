@@ -0,0 +1,263 @@
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Additional object identifiers accepted when verifying
+var (
+	OIDSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	OIDSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	OIDSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidSHA1WithRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidECPublicKey     = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidECDSAWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+)
+
+// SignedData is a parsed CMS SignedData with a single signer
+type SignedData struct {
+	Certificates []*x509.Certificate // All embedded certificates
+	Signer       *x509.Certificate   // Certificate matching the signer identifier
+	SigningTime  time.Time           // signingTime attribute (zero if absent)
+	Content      []byte              // Encapsulated content (nil for detached signatures)
+
+	info signerInfo
+}
+
+// Parse parses a DER-encoded ContentInfo containing SignedData.
+// Trailing bytes (such as the zero padding of a PDF /Contents string) are ignored.
+func Parse(der []byte) (*SignedData, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, fmt.Errorf("failed to parse ContentInfo: %w", err)
+	}
+	if !ci.ContentType.Equal(OIDSignedData) {
+		return nil, fmt.Errorf("content type %v is not SignedData", ci.ContentType)
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, fmt.Errorf("failed to parse SignedData: %w", err)
+	}
+	if len(sd.SignerInfos) == 0 {
+		return nil, fmt.Errorf("SignedData has no signers")
+	}
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificates: %w", err)
+	}
+
+	result := &SignedData{
+		Certificates: certs,
+		info:         sd.SignerInfos[0],
+	}
+
+	if len(sd.EncapContentInfo.EContent.Bytes) > 0 {
+		var content []byte
+		if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &content); err != nil {
+			return nil, fmt.Errorf("failed to parse encapsulated content: %w", err)
+		}
+		result.Content = content
+	}
+
+	result.Signer, err = findSigner(certs, result.info.SID)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, ok := result.signedAttribute(OIDSigningTime); ok {
+		var t time.Time
+		if _, err := asn1.Unmarshal(raw, &t); err == nil {
+			result.SigningTime = t
+		}
+	}
+
+	return result, nil
+}
+
+// findSigner finds the certificate identified by a SignerIdentifier
+func findSigner(certs []*x509.Certificate, sid asn1.RawValue) (*x509.Certificate, error) {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		// subjectKeyIdentifier [0]
+		for _, c := range certs {
+			if bytes.Equal(c.SubjectKeyId, sid.Bytes) {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("signer certificate not found")
+	}
+
+	var ias issuerAndSerialNumber
+	if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+		return nil, fmt.Errorf("failed to parse signer identifier: %w", err)
+	}
+	for _, c := range certs {
+		if c.SerialNumber.Cmp(ias.SerialNumber) == 0 && bytes.Equal(c.RawIssuer, ias.Issuer.FullBytes) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("signer certificate not found")
+}
+
+// DigestAlgorithm returns the hash function used by the signer
+func (sd *SignedData) DigestAlgorithm() (crypto.Hash, error) {
+	return HashForOID(sd.info.DigestAlgorithm.Algorithm)
+}
+
+// Signature returns the raw signature value of the signer
+func (sd *SignedData) Signature() []byte {
+	return sd.info.Signature
+}
+
+// UnsignedAttribute returns the first value of an unsigned attribute
+func (sd *SignedData) UnsignedAttribute(oid asn1.ObjectIdentifier) ([]byte, bool) {
+	return findAttribute(sd.info.UnsignedAttrs.Bytes, oid)
+}
+
+// signedAttribute returns the first value of a signed attribute
+func (sd *SignedData) signedAttribute(oid asn1.ObjectIdentifier) ([]byte, bool) {
+	return findAttribute(sd.info.SignedAttrs.Bytes, oid)
+}
+
+// VerifyDetached checks that the signature covers the data read from content
+func (sd *SignedData) VerifyDetached(content io.Reader) error {
+	hash, err := sd.DigestAlgorithm()
+	if err != nil {
+		return err
+	}
+
+	h := hash.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return fmt.Errorf("failed to read signed content: %w", err)
+	}
+	return sd.VerifyDigest(h.Sum(nil))
+}
+
+// VerifyDigest checks the signature against a precomputed digest of the signed content
+func (sd *SignedData) VerifyDigest(digest []byte) error {
+	alg, err := x509SignatureAlgorithm(sd.info.DigestAlgorithm.Algorithm, sd.info.SignatureAlgorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	if len(sd.info.SignedAttrs.Bytes) == 0 {
+		return fmt.Errorf("signatures without signed attributes are not supported")
+	}
+
+	raw, ok := sd.signedAttribute(OIDMessageDigest)
+	if !ok {
+		return fmt.Errorf("messageDigest attribute is missing")
+	}
+	var messageDigest []byte
+	if _, err := asn1.Unmarshal(raw, &messageDigest); err != nil {
+		return fmt.Errorf("failed to parse messageDigest: %w", err)
+	}
+	if !bytes.Equal(messageDigest, digest) {
+		return fmt.Errorf("message digest does not match the signed content")
+	}
+
+	// 署名対象は[0] IMPLICITではなくDERのSET OFとしてエンコードした属性
+	signed, err := asn1.Marshal(asn1.RawValue{
+		Class:      asn1.ClassUniversal,
+		Tag:        asn1.TagSet,
+		IsCompound: true,
+		Bytes:      sd.info.SignedAttrs.Bytes,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sd.Signer.CheckSignature(alg, signed, sd.info.Signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+	return nil
+}
+
+// findAttribute returns the DER of the first value of attribute oid in a SET OF Attribute body
+func findAttribute(set []byte, oid asn1.ObjectIdentifier) ([]byte, bool) {
+	for rest := set; len(rest) > 0; {
+		var attr attribute
+		var err error
+		rest, err = asn1.Unmarshal(rest, &attr)
+		if err != nil {
+			return nil, false
+		}
+		if !attr.Type.Equal(oid) {
+			continue
+		}
+		var value asn1.RawValue
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+			return nil, false
+		}
+		return value.FullBytes, true
+	}
+	return nil, false
+}
+
+// HashForOID maps a digest algorithm OID to a crypto.Hash
+func HashForOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(OIDSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(OIDSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(OIDSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(OIDSHA512):
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported digest algorithm: %v", oid)
+	}
+}
+
+// x509SignatureAlgorithm combines the digest and signature algorithm OIDs
+func x509SignatureAlgorithm(digestOID, sigOID asn1.ObjectIdentifier) (x509.SignatureAlgorithm, error) {
+	hash, err := HashForOID(digestOID)
+	if err != nil {
+		return x509.UnknownSignatureAlgorithm, err
+	}
+
+	isRSA := sigOID.Equal(OIDRSAEncryption) || sigOID.Equal(oidSHA1WithRSA) || sigOID.Equal(oidSHA256WithRSA) ||
+		sigOID.Equal(oidSHA384WithRSA) || sigOID.Equal(oidSHA512WithRSA)
+	isECDSA := sigOID.Equal(oidECPublicKey) || sigOID.Equal(oidECDSAWithSHA1) || sigOID.Equal(OIDECDSAWithSHA256) ||
+		sigOID.Equal(oidECDSAWithSHA384) || sigOID.Equal(oidECDSAWithSHA512)
+
+	switch {
+	case isRSA:
+		switch hash {
+		case crypto.SHA1:
+			return x509.SHA1WithRSA, nil
+		case crypto.SHA256:
+			return x509.SHA256WithRSA, nil
+		case crypto.SHA384:
+			return x509.SHA384WithRSA, nil
+		case crypto.SHA512:
+			return x509.SHA512WithRSA, nil
+		}
+	case isECDSA:
+		switch hash {
+		case crypto.SHA1:
+			return x509.ECDSAWithSHA1, nil
+		case crypto.SHA256:
+			return x509.ECDSAWithSHA256, nil
+		case crypto.SHA384:
+			return x509.ECDSAWithSHA384, nil
+		case crypto.SHA512:
+			return x509.ECDSAWithSHA512, nil
+		}
+	}
+	return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported signature algorithm: %v", sigOID)
+}
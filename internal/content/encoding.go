@@ -0,0 +1,137 @@
+package content
+
+import (
+	"strings"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// SimpleEncoding は単純フォント（1バイトの文字コード）の文字コード→Unicodeの対応
+// 合字のグリフは複数の文字になるため、文字列で持つ（""は対応なし）
+type SimpleEncoding [256]string
+
+// winAnsiHigh はWinAnsiEncodingの0x80〜0x9Fの文字（0は未定義）
+var winAnsiHigh = [32]rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// macRomanHigh はMacRomanEncodingの0x80〜0xFFの文字（0xCAはノーブレークスペース、0xF0は未定義）
+const macRomanHigh = "ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü" +
+	"†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø" +
+	"¿¡¬√ƒ≈∆«»…\u00A0ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄¤‹›ﬁﬂ" +
+	"‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔ\x00ÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ"
+
+// standardHigh はStandardEncodingでASCIIと異なる文字
+var standardHigh = map[byte]rune{
+	0x27: 0x2019, 0x60: 0x2018,
+	0xA1: 0x00A1, 0xA2: 0x00A2, 0xA3: 0x00A3, 0xA4: 0x2044, 0xA5: 0x00A5, 0xA6: 0x0192, 0xA7: 0x00A7,
+	0xA8: 0x00A4, 0xA9: 0x0027, 0xAA: 0x201C, 0xAB: 0x00AB, 0xAC: 0x2039, 0xAD: 0x203A, 0xAE: 0xFB01, 0xAF: 0xFB02,
+	0xB1: 0x2013, 0xB2: 0x2020, 0xB3: 0x2021, 0xB4: 0x00B7, 0xB6: 0x00B6, 0xB7: 0x2022,
+	0xB8: 0x201A, 0xB9: 0x201E, 0xBA: 0x201D, 0xBB: 0x00BB, 0xBC: 0x2026, 0xBD: 0x2030, 0xBF: 0x00BF,
+	0xC1: 0x0060, 0xC2: 0x00B4, 0xC3: 0x02C6, 0xC4: 0x02DC, 0xC5: 0x00AF, 0xC6: 0x02D8, 0xC7: 0x02D9,
+	0xC8: 0x00A8, 0xCA: 0x02DA, 0xCB: 0x00B8, 0xCD: 0x02DD, 0xCE: 0x02DB, 0xCF: 0x02C7,
+	0xD0: 0x2014, 0xE1: 0x00C6, 0xE3: 0x00AA, 0xE8: 0x0141, 0xE9: 0x00D8, 0xEA: 0x0152, 0xEB: 0x00BA,
+	0xF1: 0x00E6, 0xF5: 0x0131, 0xF8: 0x0142, 0xF9: 0x00F8, 0xFA: 0x0153, 0xFB: 0x00DF,
+}
+
+// baseEncoding は/BaseEncodingなどで指定される定義済みのエンコーディングを返す（未知の名前はnil）
+func baseEncoding(name string) *SimpleEncoding {
+	var enc SimpleEncoding
+	for c := 0x20; c < 0x7F; c++ {
+		enc[c] = string(rune(c))
+	}
+
+	switch name {
+	case "StandardEncoding":
+		for c, r := range standardHigh {
+			enc[c] = string(r)
+		}
+	case "WinAnsiEncoding":
+		for i, r := range winAnsiHigh {
+			if r != 0 {
+				enc[0x80+i] = string(r)
+			}
+		}
+		for c := 0xA0; c <= 0xFF; c++ {
+			enc[c] = string(rune(c))
+		}
+	case "MacRomanEncoding":
+		c := 0x80
+		for _, r := range macRomanHigh {
+			if r != 0 {
+				enc[c] = string(r)
+			}
+			c++
+		}
+	default:
+		return nil
+	}
+	return &enc
+}
+
+// loadSimpleEncoding はフォント辞書の/Encodingから文字コード→Unicodeの対応を作る
+// /Encoding がない、または複合フォント（Type0）の場合はnilを返す
+// 辞書の場合は/BaseEncoding（省略時はStandardEncoding）を/Differencesで置き換える
+func loadSimpleEncoding(r *reader.Reader, fontDict core.Dictionary) *SimpleEncoding {
+	if fontDict[core.Name("Subtype")] == core.Name("Type0") {
+		return nil
+	}
+
+	switch v := r.Resolve(fontDict[core.Name("Encoding")]).(type) {
+	case core.Name:
+		return baseEncoding(string(v))
+	case core.Dictionary:
+		base, _ := r.Resolve(v[core.Name("BaseEncoding")]).(core.Name)
+		enc := baseEncoding(string(base))
+		if enc == nil {
+			enc = baseEncoding("StandardEncoding")
+		}
+		if differences, ok := r.Resolve(v[core.Name("Differences")]).(core.Array); ok {
+			applyDifferences(enc, differences)
+		}
+		return enc
+	default:
+		return nil
+	}
+}
+
+// applyDifferences は/Differences（[コード 名前 名前 ... コード 名前 ...]）をencに適用する
+// Unicodeに変換できないグリフ名（"g12" など）のコードは対応なしにする
+func applyDifferences(enc *SimpleEncoding, differences core.Array) {
+	code := -1
+	for _, item := range differences {
+		switch v := item.(type) {
+		case core.Integer:
+			code = int(v)
+		case core.Name:
+			if code >= 0 && code < len(enc) {
+				enc[code] = glyphNameToUnicode(string(v))
+			}
+			code++
+		}
+	}
+}
+
+// Decode は文字コードの列をUnicode文字列に変換する（対応のないコードは除く）
+func (enc *SimpleEncoding) Decode(data []byte) string {
+	var sb strings.Builder
+	for _, b := range data {
+		sb.WriteString(enc[b])
+	}
+	return sb.String()
+}
+
+// Code はUnicodeの文字（合字は複数の文字）に対応する文字コードを返す（Decodeの逆）
+// 同じ文字に複数のコードが対応する場合は、最も小さいコードを返す
+func (enc *SimpleEncoding) Code(text string) (byte, bool) {
+	for c, mapped := range enc {
+		if mapped != "" && mapped == text {
+			return byte(c), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,195 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// TestGlyphNameToUnicode はグリフ名からUnicodeへの変換をテストする
+func TestGlyphNameToUnicode(t *testing.T) {
+	tests := []struct {
+		name  string
+		glyph string
+		want  string
+	}{
+		{"ASCII letter", "A", "A"},
+		{"ASCII symbol", "ampersand", "&"},
+		{"Latin-1", "eacute", "é"},
+		{"Latin Extended-A", "Scaron", "Š"},
+		{"punctuation", "quotedblleft", "“"},
+		{"ligature glyph", "fi", "ﬁ"},
+		{"Greek", "Omegagreek", "Ω"},
+		{"Cyrillic", "afii10017", "А"},
+		{"Hebrew", "afii57664", "א"},
+		{"Arabic", "afii57409", "ء"},
+		{"Cyrillic ligature", "afii10017_afii10018", "АБ"},
+		{"suffix", "a.sc", "a"},
+		{"underscore ligature", "f_f_i", "ffi"},
+		{"uni", "uni20AC", "€"},
+		{"uni sequence", "uni00660069", "fi"},
+		{"u", "u1F600", "😀"},
+		{"lower case hex", "uni20ac", ""},
+		{"surrogate", "uniD800", ""},
+		{"unknown", "g123", ""},
+		{"notdef", ".notdef", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := glyphNameToUnicode(tt.glyph); got != tt.want {
+				t.Errorf("glyphNameToUnicode(%q) = %q, want %q", tt.glyph, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseGlyphList はAdobe Glyph Listの形式の解析をテストする
+func TestParseGlyphList(t *testing.T) {
+	list := parseGlyphList("# comment;0041\nA;0041\nA;0061\ndalethatafpatah;05D3 05B2\nbroken;XYZ\nempty;\n")
+	want := map[string]string{"A": "A", "dalethatafpatah": "\u05D3\u05B2"}
+	if len(list) != len(want) {
+		t.Errorf("parseGlyphList() = %q, want %q", list, want)
+	}
+	for name, s := range want {
+		if list[name] != s {
+			t.Errorf("parseGlyphList()[%q] = %q, want %q", name, list[name], s)
+		}
+	}
+}
+
+// TestBaseEncoding は定義済みのエンコーディングをテストする
+func TestBaseEncoding(t *testing.T) {
+	tests := []struct {
+		encoding string
+		code     byte
+		want     string
+	}{
+		{"StandardEncoding", 'A', "A"},
+		{"StandardEncoding", 0x27, "’"},
+		{"StandardEncoding", 0xAE, "ﬁ"},
+		{"StandardEncoding", 0xE8, "Ł"},
+		{"StandardEncoding", 0xE9, "Ø"},
+		{"WinAnsiEncoding", 0x80, "€"},
+		{"WinAnsiEncoding", 0x93, "“"},
+		{"WinAnsiEncoding", 0xE9, "é"},
+		{"WinAnsiEncoding", 0x81, ""},
+		{"MacRomanEncoding", 0x80, "Ä"},
+		{"MacRomanEncoding", 0x8E, "é"},
+		{"MacRomanEncoding", 0xD2, "“"},
+		{"MacRomanEncoding", 0xCA, "\u00A0"},
+		{"MacRomanEncoding", 0xDE, "ﬁ"},
+		{"MacRomanEncoding", 0xF0, ""},
+		{"MacRomanEncoding", 0xFF, "ˇ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			enc := baseEncoding(tt.encoding)
+			if enc == nil {
+				t.Fatalf("baseEncoding(%q) = nil", tt.encoding)
+			}
+			if got := enc[tt.code]; got != tt.want {
+				t.Errorf("%s[0x%02X] = %q, want %q", tt.encoding, tt.code, got, tt.want)
+			}
+		})
+	}
+
+	if baseEncoding("Identity-H") != nil {
+		t.Error("baseEncoding(Identity-H) should be nil")
+	}
+}
+
+// TestLoadSimpleEncoding はフォント辞書の/Encodingの読み込みをテストする
+func TestLoadSimpleEncoding(t *testing.T) {
+	differences := core.Dictionary{
+		core.Name("Type"):         core.Name("Encoding"),
+		core.Name("BaseEncoding"): core.Name("WinAnsiEncoding"),
+		core.Name("Differences"): core.Array{
+			core.Integer(1), core.Name("H"), core.Name("e"), core.Name("l"), core.Name("o"),
+			core.Integer(0x41), core.Name("f_i"), core.Name("g7"),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fontDict core.Dictionary
+		data     []byte
+		want     string
+		wantNil  bool
+	}{
+		{
+			name:     "named encoding",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type1"), core.Name("Encoding"): core.Name("WinAnsiEncoding")},
+			data:     []byte("caf\xe9 \x93q\x94"),
+			want:     "café “q”",
+		},
+		{
+			name:     "differences over base encoding",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("TrueType"), core.Name("Encoding"): differences},
+			data:     []byte("\x01\x02\x03\x03\x04 \x41\x42\xe9"),
+			want:     "Hello fié",
+		},
+		{
+			name: "differences without base encoding",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type1"), core.Name("Encoding"): core.Dictionary{
+				core.Name("Differences"): core.Array{core.Integer(0x27), core.Name("quotesingle")},
+			}},
+			data: []byte("it's \x60x\xae"),
+			want: "it's ‘xﬁ",
+		},
+		{
+			name:     "no encoding",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type1")},
+			wantNil:  true,
+		},
+		{
+			name:     "composite font",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type0"), core.Name("Encoding"): core.Name("Identity-H")},
+			wantNil:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := loadSimpleEncoding(nil, tt.fontDict)
+			if tt.wantNil {
+				if enc != nil {
+					t.Error("loadSimpleEncoding() should return nil")
+				}
+				return
+			}
+			if enc == nil {
+				t.Fatal("loadSimpleEncoding() = nil")
+			}
+			if got := enc.Decode(tt.data); got != tt.want {
+				t.Errorf("Decode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTextExtractor_Encoding はフォントの/Encodingを使ったテキストの抽出をテストする
+func TestTextExtractor_Encoding(t *testing.T) {
+	enc := baseEncoding("StandardEncoding")
+	applyDifferences(enc, core.Array{core.Integer(1), core.Name("T"), core.Name("e"), core.Name("x"), core.Name("t")})
+
+	tests := []struct {
+		name string
+		info *FontInfo
+		want string
+	}{
+		{"with encoding", &FontInfo{Name: "F1", Encoding: enc}, "Text"},
+		{"without encoding", &FontInfo{Name: "F1"}, "\x01\x02\x03\x04"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewTextExtractor(nil, nil, nil)
+			e.currentFontInfo = tt.info
+			if got := e.getTextString(core.String("\x01\x02\x03\x04")); got != tt.want {
+				t.Errorf("getTextString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
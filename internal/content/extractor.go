@@ -1,6 +1,7 @@
 package content
 
 import (
+	"math"
 	"unicode/utf16"
 	"unicode/utf8"
 
@@ -11,11 +12,53 @@ import (
 
 // TextElement はテキスト要素
 type TextElement struct {
-	Text string  // テキスト内容
-	X    float64 // X座標
-	Y    float64 // Y座標
+	Text  string  // テキスト内容
+	X     float64 // X座標
+	Y     float64 // Y座標
+	Width float64 // 表示したときの幅（文字幅、Tc、Tw、Tz、TJの位置調整を適用した移動量）
 	Font string  // フォント名
 	Size float64 // フォントサイズ
+	MCID int     // 属するマーク付きコンテンツのID（BDCの/MCID、なければ-1）
+
+	Color             [3]float64 // 塗りつぶし色（RGB）
+	RenderMode        int        // テキストレンダリングモード（Tr、3は不可視）
+	HorizontalScaling float64    // 水平スケーリング（Tz、%）
+
+	Angle float64 // ベースラインの向き（度、反時計回り、-180〜180。0は左から右へ書く通常のテキスト）
+
+	Glyphs []GlyphBox // 表示した文字ごとの位置（表示した順）
+
+	Source TextSource // 表示したオペレーション（文字列の置き換えに使う）
+}
+
+// TextSource はテキスト要素を表示したオペレーションと、各文字の文字コード
+type TextSource struct {
+	Operation int       // オペレーションの位置（NewTextExtractorに渡したoperationsの番号）
+	Font      *FontInfo // 表示したフォント
+	Strings   []int     // 各文字（Glyphs）を含む文字列のオペランド（TJは配列の位置、それ以外は0）
+	Codes     [][]byte  // 各文字の文字コード（文字コードの長さが決まっていないフォントではnil）
+}
+
+// addGlyphs は文字列のオペランドitemで表示したcount文字を記録する
+func (s *TextSource) addGlyphs(item int, data []byte, count int) {
+	n := s.Font.codeLength()
+	for i := 0; i < count; i++ {
+		var code []byte
+		if n > 0 && (i+1)*n <= len(data) {
+			code = data[i*n : (i+1)*n]
+		}
+		s.Strings = append(s.Strings, item)
+		s.Codes = append(s.Codes, code)
+	}
+}
+
+// GlyphBox は表示された1文字の位置
+// 高さと向きは属するテキスト要素のSizeとAngleと同じ
+type GlyphBox struct {
+	Text  string  // 文字（合字などでは複数の文字、対応する文字がなければ空）
+	X     float64 // ベースラインの始点のX座標
+	Y     float64 // ベースラインの始点のY座標
+	Width float64 // 表示したときの幅（文字幅、Tc、Tw、Tzを適用した移動量）
 }
 
 // TextExtractor はテキストを抽出する
@@ -41,6 +84,11 @@ type TextExtractor struct {
 	charSpacing float64
 	wordSpacing float64
 	leading     float64
+
+	// マーク付きコンテンツ（BMC/BDC〜EMC）ごとのMCIDのスタック
+	markedContent []int
+
+	operation int // 処理中のオペレーションの位置
 }
 
 // NewTextExtractor は新しいTextExtractorを作成する
@@ -65,8 +113,10 @@ func (e *TextExtractor) Extract() ([]TextElement, error) {
 
 	// 初期化
 	e.resetTextState()
+	e.markedContent = nil
 
-	for _, op := range e.operations {
+	for i, op := range e.operations {
+		e.operation = i
 		switch op.Operator {
 		case "q": // Save graphics state
 			e.graphicsStateStack = append(e.graphicsStateStack, e.graphicsState.Clone())
@@ -159,31 +209,20 @@ func (e *TextExtractor) Extract() ([]TextElement, error) {
 
 		case "Tj": // Show text
 			if len(op.Operands) >= 1 {
-				text := e.getTextString(op.Operands[0])
-				elem := e.createTextElement(text)
-				elements = append(elements, elem)
+				elements = append(elements, e.showText(op.Operands[0]))
 			}
 
 		case "TJ": // Show text with positioning
 			if len(op.Operands) >= 1 {
 				if array, ok := utils.ExtractAs[core.Array](op.Operands[0]); ok {
-					for _, item := range array {
-						if str, ok := utils.ExtractAs[core.String](item); ok {
-							text := e.getTextString(core.String(str))
-							elem := e.createTextElement(text)
-							elements = append(elements, elem)
-						}
-						// 数値の場合は位置調整（今は無視）
-					}
+					elements = append(elements, e.showTextArray(array)...)
 				}
 			}
 
 		case "'": // Move to next line and show text
 			e.moveText(0, -e.leading)
 			if len(op.Operands) >= 1 {
-				text := e.getTextString(op.Operands[0])
-				elem := e.createTextElement(text)
-				elements = append(elements, elem)
+				elements = append(elements, e.showText(op.Operands[0]))
 			}
 
 		case "\"": // Set word/char spacing, move to next line, show text
@@ -191,9 +230,7 @@ func (e *TextExtractor) Extract() ([]TextElement, error) {
 				e.wordSpacing = getNumber(op.Operands[0])
 				e.charSpacing = getNumber(op.Operands[1])
 				e.moveText(0, -e.leading)
-				text := e.getTextString(op.Operands[2])
-				elem := e.createTextElement(text)
-				elements = append(elements, elem)
+				elements = append(elements, e.showText(op.Operands[2]))
 			}
 
 		case "Tc": // Set character spacing
@@ -206,10 +243,39 @@ func (e *TextExtractor) Extract() ([]TextElement, error) {
 				e.wordSpacing = getNumber(op.Operands[0])
 			}
 
+		case "BMC": // Begin marked content
+			e.markedContent = append(e.markedContent, e.currentMCID())
+
+		case "BDC": // Begin marked content with properties
+			e.markedContent = append(e.markedContent, e.markedContentID(op.Operands))
+
+		case "EMC": // End marked content
+			if len(e.markedContent) > 0 {
+				e.markedContent = e.markedContent[:len(e.markedContent)-1]
+			}
+
 		case "TL": // Set text leading
 			if len(op.Operands) >= 1 {
 				e.leading = getNumber(op.Operands[0])
 			}
+
+		case "Tr": // Set text rendering mode
+			if len(op.Operands) >= 1 {
+				e.graphicsState.TextRenderMode = int(getNumber(op.Operands[0]))
+			}
+
+		case "Tz": // Set horizontal scaling
+			if len(op.Operands) >= 1 {
+				e.graphicsState.HorizontalScaling = getNumber(op.Operands[0])
+			}
+
+		case "cs": // Set fill color space (resets the fill color to black)
+			e.graphicsState.FillColor = [3]float64{0, 0, 0}
+
+		case "g", "rg", "k", "sc", "scn": // Set fill color
+			if c, ok := deviceColor(numericOperands(op.Operands)); ok {
+				e.graphicsState.FillColor = c
+			}
 		}
 	}
 
@@ -256,27 +322,166 @@ func (e *TextExtractor) setTextMatrix(operands []core.Object) {
 	e.lineMatrix = e.textMatrix
 }
 
+// tjSplitThreshold はTJの位置調整（1/1000 em）でテキスト要素を分ける大きさ
+// カーニング程度の調整は同じ要素に含め、語間などの大きな移動で分ける（空白を入れるかはグループ化で決める）
+const tjSplitThreshold = 200.0
+
+// showText は文字列を表示し、テキストマトリックスを表示した幅だけ進める
+func (e *TextExtractor) showText(obj core.Object) TextElement {
+	elem := e.createTextElement(e.getTextString(obj))
+	if str, ok := obj.(core.String); ok {
+		elem.Glyphs, elem.Width = e.showGlyphs([]byte(str))
+		elem.Source.addGlyphs(0, []byte(str), len(elem.Glyphs))
+	}
+	return elem
+}
+
+// showTextArray はTJの配列を表示する
+// 文字列は文字幅、数値は位置調整（-数値/1000 em）だけテキストマトリックスを進める
+func (e *TextExtractor) showTextArray(array core.Array) []TextElement {
+	var elements []TextElement
+	var current *TextElement
+	for i, item := range array {
+		switch v := item.(type) {
+		case core.String:
+			if current == nil {
+				elem := e.createTextElement("")
+				current = &elem
+			}
+			current.Text += e.getTextString(v)
+			glyphs, width := e.showGlyphs([]byte(v))
+			current.Glyphs = append(current.Glyphs, glyphs...)
+			current.Width += width
+			current.Source.addGlyphs(i, []byte(v), len(glyphs))
+
+		case core.Integer, core.Real:
+			adjustment := getNumber(v)
+			if current != nil && math.Abs(adjustment) >= tjSplitThreshold {
+				elements = append(elements, *current)
+				current = nil
+			}
+			moved := e.advance(-adjustment / 1000 * e.fontSize * e.graphicsState.HorizontalScaling / 100)
+			if current != nil {
+				current.Width += moved
+			}
+		}
+	}
+	if current != nil {
+		elements = append(elements, *current)
+	}
+	return elements
+}
+
+// showGlyphs は文字列を1文字ずつ表示してテキストマトリックスを進め、
+// 各文字の位置とページの座標系での移動量の合計を返す
+// 1文字の移動量 tx = (w0 × Tfs + Tc + Tw) × Th（Twは1バイトの文字コード32のみ）
+func (e *TextExtractor) showGlyphs(data []byte) ([]GlyphBox, float64) {
+	glyphs := e.currentFontInfo.glyphs(data)
+	texts := e.currentFontInfo.glyphTexts(data, len(glyphs))
+	scale := e.graphicsState.HorizontalScaling / 100
+
+	boxes := make([]GlyphBox, len(glyphs))
+	var total float64
+	for i, g := range glyphs {
+		w := g.width/1000*e.fontSize + e.charSpacing
+		if g.space {
+			w += e.wordSpacing
+		}
+
+		trm := e.textRenderingMatrix()
+		moved := e.advance(w * scale)
+		boxes[i] = GlyphBox{Text: texts[i], X: trm.E, Y: trm.F, Width: moved}
+		total += moved
+	}
+	return boxes, total
+}
+
+// advance はテキストマトリックスをテキスト空間でtxだけ進め（Tm = [1 0 0 1 tx 0] × Tm）、
+// ページの座標系（CTM適用後）での移動量を返す
+func (e *TextExtractor) advance(tx float64) float64 {
+	dx, dy := tx*e.textMatrix[0], tx*e.textMatrix[1]
+	e.textMatrix[4] += dx
+	e.textMatrix[5] += dy
+
+	ctm := e.graphicsState.CTM
+	return math.Hypot(ctm.A*dx+ctm.C*dy, ctm.B*dx+ctm.D*dy)
+}
+
 // createTextElement はテキスト要素を作成する
+// 位置・向き・フォントサイズは、テキストマトリックスとCTMを合わせた行列（Tm × CTM）で
+// テキスト空間をページの座標系に写したものとする
 func (e *TextExtractor) createTextElement(text string) TextElement {
-	// テキストマトリックスから座標を取得
-	x := e.textMatrix[4] // e
-	y := e.textMatrix[5] // f
+	trm := e.textRenderingMatrix()
 
-	// Note: CTMの扱いは複雑です。
-	// 一部のPDFでは、Tmの座標が既に変換後の空間にあるため、
-	// CTMを適用すると二重変換になってしまいます。
-	// 現時点では、Tmの座標をそのまま使用します。
-	// 将来的には、より正確なCTM処理が必要かもしれません。
+	// テキスト空間の原点がベースラインの始点、x軸がベースラインの向き、y軸が文字の高さの向き
+	x, y := trm.E, trm.F
+	angle := math.Atan2(trm.B, trm.A) * 180 / math.Pi
+	size := e.fontSize * math.Hypot(trm.C, trm.D)
 
 	return TextElement{
 		Text: text,
 		X:    x,
 		Y:    y,
 		Font: e.currentFont,
-		Size: e.fontSize,
+		Size: size,
+		MCID: e.currentMCID(),
+
+		Color:             e.graphicsState.FillColor,
+		RenderMode:        e.graphicsState.TextRenderMode,
+		HorizontalScaling: e.graphicsState.HorizontalScaling,
+
+		Angle: angle,
+
+		Source: TextSource{Operation: e.operation, Font: e.currentFontInfo},
 	}
 }
 
+// textRenderingMatrix は現在のテキストマトリックスとCTMを合わせた行列（Tm × CTM）を返す
+// フォントサイズと水平スケーリングは含めない
+func (e *TextExtractor) textRenderingMatrix() Matrix {
+	tm := Matrix{
+		A: e.textMatrix[0], B: e.textMatrix[1],
+		C: e.textMatrix[2], D: e.textMatrix[3],
+		E: e.textMatrix[4], F: e.textMatrix[5],
+	}
+	return tm.Multiply(e.graphicsState.CTM)
+}
+
+// currentMCID は現在のマーク付きコンテンツのMCIDを返す（なければ-1）
+// MCIDを持たない内側のマーク付きコンテンツは、外側のMCIDに属する
+func (e *TextExtractor) currentMCID() int {
+	if len(e.markedContent) == 0 {
+		return -1
+	}
+	return e.markedContent[len(e.markedContent)-1]
+}
+
+// markedContentID はBDCのプロパティ（インライン辞書、またはリソースの/Propertiesの名前）からMCIDを取得する
+func (e *TextExtractor) markedContentID(operands []core.Object) int {
+	if len(operands) < 2 {
+		return e.currentMCID()
+	}
+
+	var props core.Dictionary
+	switch v := operands[1].(type) {
+	case core.Dictionary:
+		props = v
+	case core.Name:
+		if e.reader != nil && e.page != nil {
+			if resources, err := e.reader.GetPageResources(e.page); err == nil {
+				if properties, ok := e.reader.Resolve(resources[core.Name("Properties")]).(core.Dictionary); ok {
+					props, _ = e.reader.Resolve(properties[v]).(core.Dictionary)
+				}
+			}
+		}
+	}
+
+	if mcid, ok := props[core.Name("MCID")].(core.Integer); ok {
+		return int(mcid)
+	}
+	return e.currentMCID()
+}
+
 // getNumber はオブジェクトから数値を取得する
 func getNumber(obj core.Object) float64 {
 	switch v := obj.(type) {
@@ -303,23 +508,10 @@ func getString(obj core.Object) string {
 }
 
 // getTextString はテキスト表示用の文字列を取得する
-// ToUnicode CMapがあればそれを使用し、なければ通常のエンコーディングを使用
 func (e *TextExtractor) getTextString(obj core.Object) string {
 	switch v := obj.(type) {
 	case core.String:
-		data := []byte(v)
-
-		// ToUnicode CMapがあれば優先的に使用
-		if e.currentFontInfo != nil && e.currentFontInfo.ToUnicodeCMap != nil {
-			result := e.currentFontInfo.ToUnicodeCMap.LookupString(data)
-			if result != "" {
-				return result
-			}
-		}
-
-		// ToUnicode がない、または失敗した場合は通常のデコード
-		return decodePDFString(data)
-
+		return e.currentFontInfo.decode([]byte(v))
 	case core.Name:
 		return string(v)
 	default:
@@ -1,6 +1,7 @@
 package content
 
 import (
+	"math"
 	"testing"
 
 	"github.com/ryomak/gopdf/internal/core"
@@ -81,34 +82,97 @@ func TestTextExtractor_MultipleTexts(t *testing.T) {
 	}
 }
 
-// TestTextExtractor_TJ はTJオペレーターをテストする
+// TestTextExtractor_TJ はTJオペレーターの位置調整をテストする
+// フォント情報がない場合の文字幅は600/1000 em（12ptで7.2）
 func TestTextExtractor_TJ(t *testing.T) {
+	type want struct {
+		text  string
+		x     float64
+		width float64
+	}
+	tests := []struct {
+		name       string
+		adjustment core.Object
+		want       []want
+	}{
+		{
+			name:       "kerning stays in one element",
+			adjustment: core.Integer(-50),
+			want:       []want{{"HelloWorld", 100, 72 + 0.6}},
+		},
+		{
+			name:       "word gap splits elements",
+			adjustment: core.Integer(-300),
+			want:       []want{{"Hello", 100, 36}, {"World", 100 + 36 + 3.6, 36}},
+		},
+		{
+			name:       "positive adjustment moves left",
+			adjustment: core.Real(250),
+			want:       []want{{"Hello", 100, 36}, {"World", 100 + 36 - 3, 36}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operations := []Operation{
+				{Operator: "BT"},
+				{Operator: "Tf", Operands: []core.Object{core.Name("F1"), core.Real(12)}},
+				{Operator: "Td", Operands: []core.Object{core.Real(100), core.Real(700)}},
+				{Operator: "TJ", Operands: []core.Object{
+					core.Array{core.String("Hello"), tt.adjustment, core.String("World")},
+				}},
+				{Operator: "ET"},
+			}
+
+			elements, err := NewTextExtractor(operations, nil, nil).Extract()
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(elements) != len(tt.want) {
+				t.Fatalf("Expected %d elements, got %d: %+v", len(tt.want), len(elements), elements)
+			}
+			for i, w := range tt.want {
+				elem := elements[i]
+				if elem.Text != w.text || math.Abs(elem.X-w.x) > 1e-9 || math.Abs(elem.Width-w.width) > 1e-9 {
+					t.Errorf("element %d = {%q X:%v Width:%v}, want {%q X:%v Width:%v}",
+						i, elem.Text, elem.X, elem.Width, w.text, w.x, w.width)
+				}
+			}
+		})
+	}
+}
+
+// TestTextExtractor_Advance は文字間隔・単語間隔・水平スケーリングとTmの拡大によるテキスト位置の移動をテストする
+func TestTextExtractor_Advance(t *testing.T) {
 	operations := []Operation{
 		{Operator: "BT"},
-		{Operator: "Tf", Operands: []core.Object{core.Name("F1"), core.Real(12)}},
-		{Operator: "Td", Operands: []core.Object{core.Real(100), core.Real(700)}},
-		{Operator: "TJ", Operands: []core.Object{
-			core.Array{core.String("Hello"), core.Integer(-50), core.String("World")},
+		{Operator: "Tf", Operands: []core.Object{core.Name("F1"), core.Real(10)}},
+		{Operator: "Tm", Operands: []core.Object{
+			core.Real(2), core.Real(0), core.Real(0), core.Real(2), core.Real(50), core.Real(500),
 		}},
+		{Operator: "Tc", Operands: []core.Object{core.Real(1)}},
+		{Operator: "Tw", Operands: []core.Object{core.Real(2)}},
+		{Operator: "Tz", Operands: []core.Object{core.Integer(50)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("a b")}},
+		{Operator: "Tj", Operands: []core.Object{core.String("c")}},
 		{Operator: "ET"},
 	}
 
-	extractor := NewTextExtractor(operations, nil, nil)
-	elements, err := extractor.Extract()
-
+	elements, err := NewTextExtractor(operations, nil, nil).Extract()
 	if err != nil {
 		t.Fatalf("Extract failed: %v", err)
 	}
-
 	if len(elements) != 2 {
 		t.Fatalf("Expected 2 elements, got %d", len(elements))
 	}
 
-	if elements[0].Text != "Hello" {
-		t.Errorf("First text = %q, want %q", elements[0].Text, "Hello")
+	// "a b": (3文字 × (6 + Tc 1) + Tw 2) × Th 0.5 = 11.5（テキスト空間）、Tmで2倍して23
+	if math.Abs(elements[0].Width-23) > 1e-9 {
+		t.Errorf("Width = %v, want 23", elements[0].Width)
 	}
-	if elements[1].Text != "World" {
-		t.Errorf("Second text = %q, want %q", elements[1].Text, "World")
+	// 2つ目のTjは1つ目の幅だけ右から始まる
+	if math.Abs(elements[1].X-73) > 1e-9 || elements[1].Y != 500 {
+		t.Errorf("second element at (%v, %v), want (73, 500)", elements[1].X, elements[1].Y)
 	}
 }
 
@@ -262,3 +326,228 @@ ET`
 		t.Errorf("First text = %q, want %q", elements[0].Text, "Title")
 	}
 }
+
+// TestTextExtractor_MarkedContent はマーク付きコンテンツのMCIDの割り当てをテストする
+func TestTextExtractor_MarkedContent(t *testing.T) {
+	mcid := func(n int) core.Dictionary {
+		return core.Dictionary{core.Name("MCID"): core.Integer(n)}
+	}
+	operations := []Operation{
+		{Operator: "BT"},
+		{Operator: "Tf", Operands: []core.Object{core.Name("F1"), core.Real(12)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("none")}},
+		{Operator: "BDC", Operands: []core.Object{core.Name("P"), mcid(0)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("zero")}},
+		{Operator: "BMC", Operands: []core.Object{core.Name("Span")}},
+		{Operator: "Tj", Operands: []core.Object{core.String("inherited")}},
+		{Operator: "EMC"},
+		{Operator: "EMC"},
+		{Operator: "BDC", Operands: []core.Object{core.Name("H1"), mcid(3)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("three")}},
+		{Operator: "EMC"},
+		{Operator: "BMC", Operands: []core.Object{core.Name("Artifact")}},
+		{Operator: "Tj", Operands: []core.Object{core.String("artifact")}},
+		{Operator: "EMC"},
+		{Operator: "ET"},
+	}
+
+	elements, err := NewTextExtractor(operations, nil, nil).Extract()
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	want := map[string]int{"none": -1, "zero": 0, "inherited": 0, "three": 3, "artifact": -1}
+	if len(elements) != len(want) {
+		t.Fatalf("Expected %d elements, got %d", len(want), len(elements))
+	}
+	for _, elem := range elements {
+		if elem.MCID != want[elem.Text] {
+			t.Errorf("%q: MCID = %d, want %d", elem.Text, elem.MCID, want[elem.Text])
+		}
+	}
+}
+
+// TestTextExtractor_TextStyle はテキストの色・レンダリングモード・水平スケーリングの抽出をテストする
+func TestTextExtractor_TextStyle(t *testing.T) {
+	operations := []Operation{
+		{Operator: "BT"},
+		{Operator: "Tf", Operands: []core.Object{core.Name("F1"), core.Real(12)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("default")}},
+		{Operator: "ET"},
+		{Operator: "q"},
+		{Operator: "rg", Operands: []core.Object{core.Real(1), core.Real(0), core.Real(0)}},
+		{Operator: "BT"},
+		{Operator: "Tr", Operands: []core.Object{core.Integer(3)}},
+		{Operator: "Tz", Operands: []core.Object{core.Integer(50)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("styled")}},
+		{Operator: "ET"},
+		{Operator: "Q"},
+		{Operator: "BT"},
+		{Operator: "Tj", Operands: []core.Object{core.String("restored")}},
+		{Operator: "k", Operands: []core.Object{core.Integer(0), core.Integer(0), core.Integer(1), core.Integer(0)}},
+		{Operator: "Tj", Operands: []core.Object{core.String("cmyk")}},
+		{Operator: "g", Operands: []core.Object{core.Real(0.5)}},
+		{Operator: "cs", Operands: []core.Object{core.Name("DeviceRGB")}},
+		{Operator: "Tj", Operands: []core.Object{core.String("colorspace")}},
+		{Operator: "ET"},
+	}
+
+	elements, err := NewTextExtractor(operations, nil, nil).Extract()
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	tests := []struct {
+		text              string
+		color             [3]float64
+		renderMode        int
+		horizontalScaling float64
+	}{
+		{"default", [3]float64{0, 0, 0}, 0, 100},
+		{"styled", [3]float64{1, 0, 0}, 3, 50},
+		{"restored", [3]float64{0, 0, 0}, 0, 100},
+		{"cmyk", [3]float64{1, 1, 0}, 0, 100},
+		{"colorspace", [3]float64{0, 0, 0}, 0, 100},
+	}
+	if len(elements) != len(tests) {
+		t.Fatalf("Expected %d elements, got %d", len(tests), len(elements))
+	}
+	for i, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			elem := elements[i]
+			if elem.Text != tt.text {
+				t.Fatalf("Text = %q, want %q", elem.Text, tt.text)
+			}
+			if elem.Color != tt.color {
+				t.Errorf("Color = %v, want %v", elem.Color, tt.color)
+			}
+			if elem.RenderMode != tt.renderMode {
+				t.Errorf("RenderMode = %d, want %d", elem.RenderMode, tt.renderMode)
+			}
+			if elem.HorizontalScaling != tt.horizontalScaling {
+				t.Errorf("HorizontalScaling = %v, want %v", elem.HorizontalScaling, tt.horizontalScaling)
+			}
+		})
+	}
+}
+
+// TestTextExtractor_TextRenderingMatrix はTmとCTMを合わせた位置・向き・サイズをテストする
+func TestTextExtractor_TextRenderingMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		x, y   float64
+		angle  float64
+		size   float64
+		width  float64 // 幅のない標準フォントは1文字0.6 em
+	}{
+		{
+			name:   "scaled and translated CTM",
+			stream: "2 0 0 2 10 20 cm BT /F1 6 Tf 5 5 Td (Hi) Tj ET",
+			x:      20, y: 30, angle: 0, size: 12, width: 14.4,
+		},
+		{
+			name:   "flipped page CTM",
+			stream: "1 0 0 -1 0 792 cm BT /F1 12 Tf 1 0 0 -1 50 742 Tm (Hi) Tj ET",
+			x:      50, y: 50, angle: 0, size: 12, width: 14.4,
+		},
+		{
+			name:   "vertical axis label",
+			stream: "BT /F1 10 Tf 0 1 -1 0 40 300 Tm (Hi) Tj ET",
+			x:      40, y: 300, angle: 90, size: 10, width: 12,
+		},
+		{
+			name:   "rotated stamp inside q",
+			stream: "q 0.70710678 0.70710678 -0.70710678 0.70710678 100 100 cm BT /F1 20 Tf (Hi) Tj ET Q",
+			x:      100, y: 100, angle: 45, size: 20, width: 24,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operations, err := NewStreamParser([]byte(tt.stream)).ParseOperations()
+			if err != nil {
+				t.Fatalf("ParseOperations failed: %v", err)
+			}
+			elements, err := NewTextExtractor(operations, nil, nil).Extract()
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(elements) != 1 {
+				t.Fatalf("Expected 1 element, got %d", len(elements))
+			}
+
+			elem := elements[0]
+			got := []float64{elem.X, elem.Y, elem.Angle, elem.Size, elem.Width}
+			want := []float64{tt.x, tt.y, tt.angle, tt.size, tt.width}
+			for i, name := range []string{"X", "Y", "Angle", "Size", "Width"} {
+				if math.Abs(got[i]-want[i]) > 1e-6 {
+					t.Errorf("%s = %v, want %v", name, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestTextExtractor_Glyphs は文字ごとの位置の記録をテストする
+func TestTextExtractor_Glyphs(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []GlyphBox // 幅のない標準フォントは1文字0.6 em
+	}{
+		{
+			name:   "kerning and character spacing",
+			stream: "BT /F1 10 Tf 1 Tc 100 700 Td [(AB) -100 (C)] TJ ET",
+			want: []GlyphBox{
+				{Text: "A", X: 100, Y: 700, Width: 7},
+				{Text: "B", X: 107, Y: 700, Width: 7},
+				{Text: "C", X: 115, Y: 700, Width: 7},
+			},
+		},
+		{
+			name:   "vertical axis label",
+			stream: "BT /F1 10 Tf 0 1 -1 0 40 300 Tm (Hi) Tj ET",
+			want: []GlyphBox{
+				{Text: "H", X: 40, Y: 300, Width: 6},
+				{Text: "i", X: 40, Y: 306, Width: 6},
+			},
+		},
+		{
+			name:   "multi-byte string without font",
+			stream: "BT /F1 10 Tf 100 700 Td (\xe3\x81\x82) Tj ET",
+			want: []GlyphBox{
+				{Text: "あ", X: 100, Y: 700, Width: 6},
+				{Text: "", X: 106, Y: 700, Width: 6},
+				{Text: "", X: 112, Y: 700, Width: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operations, err := NewStreamParser([]byte(tt.stream)).ParseOperations()
+			if err != nil {
+				t.Fatalf("ParseOperations failed: %v", err)
+			}
+			elements, err := NewTextExtractor(operations, nil, nil).Extract()
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(elements) != 1 {
+				t.Fatalf("Expected 1 element, got %d", len(elements))
+			}
+
+			got := elements[0].Glyphs
+			if len(got) != len(tt.want) {
+				t.Fatalf("Glyphs = %+v, want %+v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				g := got[i]
+				if g.Text != want.Text || math.Abs(g.X-want.X) > 1e-9 || math.Abs(g.Y-want.Y) > 1e-9 || math.Abs(g.Width-want.Width) > 1e-9 {
+					t.Errorf("Glyphs[%d] = %+v, want %+v", i, g, want)
+				}
+			}
+		})
+	}
+}
@@ -10,7 +10,111 @@ import (
 // FontInfo はフォント情報を保持する
 type FontInfo struct {
 	Name          string
-	ToUnicodeCMap *ToUnicodeCMap // nilの場合は通常のエンコーディングを使用
+	BaseFont      string          // /BaseFont（太字・斜体の判定に使う。サブセットの接頭辞を含む）
+	ToUnicodeCMap *ToUnicodeCMap  // nilの場合は通常のエンコーディングを使用
+	Encoding      *SimpleEncoding // 単純フォントの/Encoding（nilの場合は文字列のエンコーディングを推測する）
+	CMap          *PredefinedCMap // 複合フォントの定義済みCMap（nilの場合は未対応のCMap）
+	Widths        *FontWidths     // 文字幅（nilの場合は幅が分からない）
+}
+
+// decode は文字列をUnicodeのテキストに変換する
+// ToUnicode CMap、フォントの/Encoding（単純フォント）または定義済みCMap（複合フォント）の順に使用し、
+// どれもなければ通常のエンコーディングを使用
+func (f *FontInfo) decode(data []byte) string {
+	// ToUnicode CMapがあれば優先的に使用
+	if f != nil && f.ToUnicodeCMap != nil {
+		result := f.ToUnicodeCMap.LookupString(data)
+		if result != "" {
+			return result
+		}
+	}
+
+	// 次に単純フォントの/Encoding、複合フォントの定義済みCMapを使用
+	if f != nil && f.Encoding != nil {
+		return f.Encoding.Decode(data)
+	}
+	if f != nil && f.CMap != nil {
+		return f.CMap.Decode(data)
+	}
+
+	// ToUnicode も /Encoding もない、または失敗した場合は通常のデコード
+	return decodePDFString(data)
+}
+
+// Encode はテキストをフォントの文字コードの列に変換する（decodeの逆）
+// decodeと同じくToUnicode CMap、/Encoding、定義済みCMapの順に使い、どれもなければ1バイトの文字コード（ASCIIとLatin-1）にする
+// フォントに含まれない文字（サブセットのフォントで使われていない文字など）があればエラーを返す
+func (f *FontInfo) Encode(text string) ([]byte, error) {
+	var data []byte
+	for _, r := range text {
+		code, ok := f.encodeRune(r)
+		if !ok {
+			return nil, fmt.Errorf("font %s cannot encode %q", fontName(f), r)
+		}
+		data = append(data, code...)
+	}
+	return data, nil
+}
+
+// encodeRune は1文字の文字コードを返す
+func (f *FontInfo) encodeRune(r rune) ([]byte, bool) {
+	switch {
+	case f != nil && f.ToUnicodeCMap != nil:
+		cid, ok := f.ToUnicodeCMap.Code(r)
+		if !ok {
+			return nil, false
+		}
+		if f.codeLength() == 1 {
+			return []byte{byte(cid)}, cid <= 0xFF
+		}
+		return []byte{byte(cid >> 8), byte(cid)}, true
+
+	case f != nil && f.Encoding != nil:
+		code, ok := f.Encoding.Code(string(r))
+		return []byte{code}, ok
+
+	case f != nil && f.CMap != nil:
+		code, err := f.CMap.Encode(string(r))
+		return code, err == nil && len(code) > 0
+
+	case f.codeLength() == 1:
+		// decodePDFStringで同じ文字に戻るASCIIとLatin-1の範囲
+		if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+			return []byte{byte(r)}, true
+		}
+	}
+	return nil, false
+}
+
+// hasDecoder はフォントが文字コードをUnicodeに変換する情報（ToUnicode、/Encoding、定義済みCMap）を持つかを返す
+func (f *FontInfo) hasDecoder() bool {
+	return f != nil && (f.ToUnicodeCMap != nil || f.Encoding != nil || f.CMap != nil)
+}
+
+// glyphTexts は文字列をcount個の文字に分けたときの、それぞれのテキストを返す
+// 文字コードの長さが決まっていて変換の情報があるフォントは文字コードごとに変換し、
+// そうでなければ文字列全体を変換した結果を文字の数で等分する
+func (f *FontInfo) glyphTexts(data []byte, count int) []string {
+	texts := make([]string, count)
+	if count == 0 {
+		return texts
+	}
+
+	if n := f.codeLength(); n > 0 && f.hasDecoder() && len(data) == n*count {
+		for i := range texts {
+			texts[i] = f.decode(data[i*n : (i+1)*n])
+		}
+		return texts
+	}
+
+	// 端数は前の文字に寄せる（UTF-8の1文字が複数の文字に分かれたときは先頭の文字になる）
+	runes := []rune(f.decode(data))
+	for i := range texts {
+		start := (i*len(runes) + count - 1) / count
+		end := ((i+1)*len(runes) + count - 1) / count
+		texts[i] = string(runes[start:end])
+	}
+	return texts
 }
 
 // FontManager はページ内のフォント情報を管理する
@@ -59,6 +163,15 @@ func (fm *FontManager) loadFontInfo(fontName string, pageResources core.Dictiona
 		return info, nil
 	}
 
+	if baseFont, ok := fontDict[core.Name("BaseFont")].(core.Name); ok {
+		info.BaseFont = string(baseFont)
+	}
+
+	// /Encoding（/BaseEncoding と /Differences、または定義済みCMap）を読み込む
+	info.Encoding = loadSimpleEncoding(fm.reader, fontDict)
+	info.CMap = loadPredefinedCMap(fm.reader, fontDict)
+	info.Widths = loadFontWidths(fm.reader, fontDict)
+
 	// ToUnicode CMap を抽出
 	toUnicodeCMap, err := fm.extractToUnicodeCMap(fontDict)
 	if err != nil {
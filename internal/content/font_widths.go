@@ -0,0 +1,194 @@
+package content
+
+import (
+	"unicode/utf8"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// unknownGlyphWidth は幅の分からないフォントの文字幅（1/1000 em）
+// /Widthsを持たない標準14フォントなど。英数字の平均的な幅として扱う
+const unknownGlyphWidth = 600.0
+
+// FontWidths はフォントの文字幅（1/1000 em）
+type FontWidths struct {
+	composite    bool            // 複合フォント（Type0）か
+	identity     bool            // 文字コードがそのままCIDか（Identity-H/V）
+	widths       map[int]float64 // 文字コード（単純フォント）またはCID -> 幅
+	defaultWidth float64         // 表にない文字の幅（/MissingWidthまたは/DW）
+	declared     bool            // フォントが幅を宣言しているか（/Widthsまたは複合フォント）
+}
+
+// glyph は表示される1文字
+type glyph struct {
+	width float64 // 幅（1/1000 em）
+	space bool    // 1バイトの文字コード32か（語間隔Twを適用する）
+}
+
+// loadFontWidths はフォント辞書から文字幅を読み込む
+// 単純フォントは/FirstCharと/Widths（表にない文字は/FontDescriptorの/MissingWidth）、
+// 複合フォントは子孫のCIDフォントの/Wと/DW（省略時は1000）を使う
+func loadFontWidths(r *reader.Reader, fontDict core.Dictionary) *FontWidths {
+	fw := &FontWidths{widths: make(map[int]float64)}
+
+	if fontDict[core.Name("Subtype")] == core.Name("Type0") {
+		fw.composite = true
+		fw.declared = true
+		fw.defaultWidth = 1000
+		switch v := r.Resolve(fontDict[core.Name("Encoding")]).(type) {
+		case core.Name:
+			fw.identity = v == "Identity-H" || v == "Identity-V"
+		case *core.Stream:
+			name, _ := v.Dict[core.Name("CMapName")].(core.Name)
+			fw.identity = name == "Identity-H" || name == "Identity-V"
+		}
+
+		descendants, _ := r.Resolve(fontDict[core.Name("DescendantFonts")]).(core.Array)
+		if len(descendants) == 0 {
+			return fw
+		}
+		cidFont, ok := r.Resolve(descendants[0]).(core.Dictionary)
+		if !ok {
+			return fw
+		}
+		if dw := r.Resolve(cidFont[core.Name("DW")]); isNumber(dw) {
+			fw.defaultWidth = getNumber(dw)
+		}
+		if w, ok := r.Resolve(cidFont[core.Name("W")]).(core.Array); ok {
+			parseCIDWidths(r, w, fw.widths)
+		}
+		return fw
+	}
+
+	// Type3フォントの幅はグリフ空間の値なので、/FontMatrixで1/1000 emに換算する
+	scale := 1.0
+	if fontDict[core.Name("Subtype")] == core.Name("Type3") {
+		if m, ok := r.Resolve(fontDict[core.Name("FontMatrix")]).(core.Array); ok && len(m) == 6 {
+			scale = getNumber(r.Resolve(m[0])) * 1000
+		}
+	}
+
+	if descriptor, ok := r.Resolve(fontDict[core.Name("FontDescriptor")]).(core.Dictionary); ok {
+		fw.defaultWidth = getNumber(r.Resolve(descriptor[core.Name("MissingWidth")])) * scale
+	}
+	widths, ok := r.Resolve(fontDict[core.Name("Widths")]).(core.Array)
+	if !ok {
+		return fw
+	}
+	fw.declared = true
+	firstChar := int(getNumber(r.Resolve(fontDict[core.Name("FirstChar")])))
+	for i, w := range widths {
+		fw.widths[firstChar+i] = getNumber(r.Resolve(w)) * scale
+	}
+	return fw
+}
+
+// parseCIDWidths は/W配列（[c [w1 w2 ...]] または [cfirst clast w] の並び）を読む
+func parseCIDWidths(r *reader.Reader, w core.Array, widths map[int]float64) {
+	for i := 0; i < len(w); {
+		first := r.Resolve(w[i])
+		if i+1 >= len(w) || !isNumber(first) {
+			return
+		}
+		start := int(getNumber(first))
+
+		if list, ok := r.Resolve(w[i+1]).(core.Array); ok {
+			for j, width := range list {
+				widths[start+j] = getNumber(r.Resolve(width))
+			}
+			i += 2
+			continue
+		}
+
+		if i+2 >= len(w) {
+			return
+		}
+		end := int(getNumber(r.Resolve(w[i+1])))
+		width := getNumber(r.Resolve(w[i+2]))
+		// 壊れた/Wで巨大な範囲を作らないように上限を設ける（CIDは16ビット）
+		for cid := start; cid <= end && cid <= 0xFFFF; cid++ {
+			widths[cid] = width
+		}
+		i += 3
+	}
+}
+
+// glyphs は文字列を表示される文字に分け、それぞれの幅を返す
+// 文字コードとCIDの対応が分からない複合フォント（Identity以外のCMap）は、
+// 変換後の文字数だけ/DWの幅の文字があるものとして扱う
+func (f *FontInfo) glyphs(data []byte) []glyph {
+	var fw *FontWidths
+	if f != nil {
+		fw = f.Widths
+	}
+
+	switch {
+	case fw == nil || !fw.declared:
+		result := make([]glyph, len(data))
+		for i, b := range data {
+			result[i] = glyph{width: unknownGlyphWidth, space: b == ' '}
+		}
+		return result
+
+	case fw.composite && fw.identity:
+		result := make([]glyph, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			result = append(result, glyph{width: fw.width(int(data[i])<<8 | int(data[i+1]))})
+		}
+		return result
+
+	case fw.composite:
+		count := len(data) / 2
+		if f.CMap != nil {
+			count = utf8.RuneCountInString(f.CMap.Decode(data))
+		}
+		result := make([]glyph, count)
+		for i := range result {
+			result[i] = glyph{width: fw.defaultWidth}
+		}
+		return result
+
+	default:
+		result := make([]glyph, len(data))
+		for i, b := range data {
+			result[i] = glyph{width: fw.width(int(b)), space: b == ' '}
+		}
+		return result
+	}
+}
+
+// codeLength は1文字の文字コードのバイト数を返す
+// 単純フォント（幅の分からないフォントを含む）は1、Identity-H/Vの複合フォントは2、それ以外の複合フォントは0
+func (f *FontInfo) codeLength() int {
+	var fw *FontWidths
+	if f != nil {
+		fw = f.Widths
+	}
+
+	switch {
+	case fw == nil || !fw.composite:
+		return 1
+	case fw.identity:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// width は文字コードまたはCIDの幅を返す
+func (fw *FontWidths) width(code int) float64 {
+	if w, ok := fw.widths[code]; ok {
+		return w
+	}
+	return fw.defaultWidth
+}
+
+// isNumber はオブジェクトが数値かを返す
+func isNumber(obj core.Object) bool {
+	switch obj.(type) {
+	case core.Integer, core.Real:
+		return true
+	}
+	return false
+}
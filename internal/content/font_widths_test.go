@@ -0,0 +1,74 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+func TestFontInfo_glyphs(t *testing.T) {
+	simple := core.Dictionary{
+		core.Name("Subtype"):        core.Name("TrueType"),
+		core.Name("FirstChar"):      core.Integer(32),
+		core.Name("Widths"):         core.Array{core.Integer(250), core.Integer(0), core.Integer(0), core.Integer(500)},
+		core.Name("FontDescriptor"): core.Dictionary{core.Name("MissingWidth"): core.Integer(300)},
+	}
+	type3 := core.Dictionary{
+		core.Name("Subtype"):    core.Name("Type3"),
+		core.Name("FontMatrix"): core.Array{core.Real(0.01), core.Integer(0), core.Integer(0), core.Real(0.01), core.Integer(0), core.Integer(0)},
+		core.Name("FirstChar"):  core.Integer(65),
+		core.Name("Widths"):     core.Array{core.Integer(50)},
+	}
+	identity := core.Dictionary{
+		core.Name("Subtype"):  core.Name("Type0"),
+		core.Name("Encoding"): core.Name("Identity-H"),
+		core.Name("DescendantFonts"): core.Array{core.Dictionary{
+			core.Name("DW"): core.Integer(900),
+			// CID 1〜2は個別の幅、CID 10〜12は同じ幅
+			core.Name("W"): core.Array{
+				core.Integer(1), core.Array{core.Integer(100), core.Integer(200)},
+				core.Integer(10), core.Integer(12), core.Integer(500),
+			},
+		}},
+	}
+	uniJIS := core.Dictionary{
+		core.Name("Subtype"):         core.Name("Type0"),
+		core.Name("Encoding"):        core.Name("UniJIS-UTF16-H"),
+		core.Name("DescendantFonts"): core.Array{core.Dictionary{}},
+	}
+
+	tests := []struct {
+		name string
+		font core.Dictionary // nilはフォント情報なし
+		data []byte
+		want []glyph
+	}{
+		{"no font info", nil, []byte("a b"), []glyph{{600, false}, {600, true}, {600, false}}},
+		{"no widths", core.Dictionary{core.Name("Subtype"): core.Name("Type1")}, []byte("a"), []glyph{{600, false}}},
+		{"simple font", simple, []byte(" #x"), []glyph{{250, true}, {500, false}, {300, false}}},
+		{"type3 font matrix", type3, []byte("A"), []glyph{{500, false}}},
+		{"identity", identity, []byte{0, 1, 0, 2, 0, 11, 0, 13}, []glyph{{100, false}, {200, false}, {500, false}, {900, false}}},
+		{"non-identity cmap", uniJIS, []byte{0x30, 0x42, 0xD8, 0x3D, 0xDE, 0x00}, []glyph{{1000, false}, {1000, false}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info *FontInfo
+			if tt.font != nil {
+				info = &FontInfo{
+					Widths: loadFontWidths(nil, tt.font),
+					CMap:   loadPredefinedCMap(nil, tt.font),
+				}
+			}
+			got := info.glyphs(tt.data)
+			if len(got) != len(tt.want) {
+				t.Fatalf("glyphs() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("glyphs()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package content
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+)
+
+// glyphListData はAdobe Glyph Listの形式のグリフ名とUnicodeの対応（glyphlist.txtを参照）
+//
+//go:embed glyphlist.txt
+var glyphListData string
+
+// glyphList はグリフ名→Unicode文字列の対応（glyphListDataから作る）
+var glyphList = parseGlyphList(glyphListData)
+
+// parseGlyphList はAdobe Glyph Listの形式（「グリフ名;XXXX」または「グリフ名;XXXX XXXX ...」）を解析する
+// "#"で始まる行と解析できない行は無視し、同じグリフ名は最初の行を使う
+func parseGlyphList(data string) map[string]string {
+	list := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		name, codes, ok := strings.Cut(strings.TrimSpace(line), ";")
+		if !ok || name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if _, exists := list[name]; exists {
+			continue
+		}
+		var sb strings.Builder
+		for _, code := range strings.Fields(codes) {
+			r, ok := parseGlyphCodePoint(code)
+			if !ok {
+				sb.Reset()
+				break
+			}
+			sb.WriteRune(r)
+		}
+		if sb.Len() > 0 {
+			list[name] = sb.String()
+		}
+	}
+	return list
+}
+
+// glyphNameToUnicode はグリフ名をUnicode文字列に変換する（変換できない場合は""）
+// Adobe Glyph Listの規則に従い、".sc" などの接尾辞を除き、"_" で区切られた合字は各成分を連結し、
+// "uniXXXX"（4桁ごとに1文字）と "uXXXX"〜"uXXXXXX" は16進数のコードポイントとして扱う
+func glyphNameToUnicode(name string) string {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return ""
+	}
+
+	if strings.Contains(name, "_") {
+		var sb strings.Builder
+		for _, part := range strings.Split(name, "_") {
+			s := glyphComponentToUnicode(part)
+			if s == "" {
+				return ""
+			}
+			sb.WriteString(s)
+		}
+		return sb.String()
+	}
+	return glyphComponentToUnicode(name)
+}
+
+// glyphComponentToUnicode は合字の成分（または合字でないグリフ名）をUnicode文字列に変換する
+func glyphComponentToUnicode(name string) string {
+	if s, ok := glyphList[name]; ok {
+		return s
+	}
+
+	// uniXXXX（XXXXが続けば複数の文字）
+	if hex, ok := strings.CutPrefix(name, "uni"); ok && len(hex) >= 4 && len(hex)%4 == 0 {
+		var sb strings.Builder
+		for i := 0; i < len(hex); i += 4 {
+			r, ok := parseGlyphCodePoint(hex[i : i+4])
+			if !ok {
+				return ""
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	}
+
+	// uXXXX〜uXXXXXX
+	if hex, ok := strings.CutPrefix(name, "u"); ok && len(hex) >= 4 && len(hex) <= 6 {
+		if r, ok := parseGlyphCodePoint(hex); ok {
+			return string(r)
+		}
+	}
+	return ""
+}
+
+// parseGlyphCodePoint は大文字の16進数をコードポイントとして解析する（サロゲートは無効）
+func parseGlyphCodePoint(hex string) (rune, bool) {
+	if strings.ToUpper(hex) != hex {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || (v >= 0xD800 && v <= 0xDFFF) || v > 0x10FFFF {
+		return 0, false
+	}
+	return rune(v), true
+}
@@ -74,6 +74,10 @@ type GraphicsState struct {
 	StrokeColor [3]float64  // 線の色（RGB）
 	FillColor   [3]float64  // 塗りつぶし色（RGB）
 	LineWidth   float64     // 線幅
+
+	// テキスト状態のうちq/Qで保存・復元されるもの
+	TextRenderMode    int     // テキストレンダリングモード（Tr）
+	HorizontalScaling float64 // 水平スケーリング（Tz、%）
 }
 
 // NewGraphicsState は新しいGraphicsStateを作成する
@@ -84,6 +88,9 @@ func NewGraphicsState() GraphicsState {
 		StrokeColor: [3]float64{0, 0, 0},
 		FillColor:   [3]float64{0, 0, 0},
 		LineWidth:   1.0,
+
+		TextRenderMode:    0,
+		HorizontalScaling: 100,
 	}
 }
 
@@ -35,6 +35,8 @@ type ImageBlock struct {
 	PlacedWidth  float64 // 配置された幅
 	PlacedHeight float64 // 配置された高さ
 	Transform Matrix     // 変換行列
+	Operation int        // 画像を描いたDoオペレーション（operationsの位置）
+	ObjectNumber int     // 画像XObjectのオブジェクト番号
 }
 
 // ImageExtractor は画像を抽出する
@@ -139,7 +141,7 @@ func (e *ImageExtractor) ExtractImagesWithPosition(page core.Dictionary, operati
 	var images []ImageBlock
 
 	// コンテンツストリームを解析
-	for _, op := range operations {
+	for i, op := range operations {
 		switch op.Operator {
 		case "cm": // 変換行列の変更
 			if len(op.Operands) == 6 {
@@ -238,6 +240,8 @@ func (e *ImageExtractor) ExtractImagesWithPosition(page core.Dictionary, operati
 					PlacedWidth:  width,
 					PlacedHeight: height,
 					Transform:    currentCTM,
+					Operation:    i,
+					ObjectNumber: xobjRef.ObjectNumber,
 				})
 			}
 
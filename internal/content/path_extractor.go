@@ -0,0 +1,305 @@
+package content
+
+import (
+	"math"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// PathKind はパスの形状の分類
+type PathKind string
+
+const (
+	PathKindLine    PathKind = "line"    // 直線（サブパスがすべて1本の線分）
+	PathKindRect    PathKind = "rect"    // 矩形（サブパスがすべて軸に平行な矩形）
+	PathKindCurve   PathKind = "curve"   // ベジェ曲線を含む
+	PathKindPolygon PathKind = "polygon" // それ以外の折れ線・多角形
+)
+
+// PathOp はパスセグメントの種類
+type PathOp string
+
+const (
+	PathOpMoveTo    PathOp = "m" // 新しいサブパスの開始（Points: 始点）
+	PathOpLineTo    PathOp = "l" // 直線（Points: 終点）
+	PathOpCurveTo   PathOp = "c" // 3次ベジェ曲線（Points: 制御点2つと終点。v, yも制御点を補ってcにする）
+	PathOpClosePath PathOp = "h" // サブパスを閉じる（Pointsなし）
+)
+
+// Point は2次元座標
+type Point struct {
+	X, Y float64
+}
+
+// PathSegment はパスの構成要素（座標はCTM適用後）
+type PathSegment struct {
+	Op     PathOp
+	Points []Point
+}
+
+// PathBlock は描画されたパスの情報（位置情報付き）
+type PathBlock struct {
+	Kind        PathKind      // 形状の分類
+	Segments    []PathSegment // 構成要素（reはm, l, l, l, hに展開する）
+	Stroke      bool          // 線を描くか（S, s, B, B*, b, b*）
+	Fill        bool          // 塗りつぶすか（f, F, f*, B, B*, b, b*）
+	EvenOdd     bool          // 塗りつぶしが奇偶規則か（f*, B*, b*）
+	StrokeColor [3]float64    // 線の色（RGB）
+	FillColor   [3]float64    // 塗りつぶし色（RGB）
+	LineWidth   float64       // 線幅（CTMの拡大率を適用後）
+	Transform   Matrix        // 描画時のCTM
+	X           float64       // 外接矩形の左下X座標（ベジェ曲線は制御点を含む）
+	Y           float64       // 外接矩形の左下Y座標
+	Width       float64       // 外接矩形の幅
+	Height      float64       // 外接矩形の高さ
+}
+
+// ExtractPaths はコンテンツストリームから描画されたパスを抽出する
+// パスは描画オペレータ（S, f, Bなど）ごとに1つのPathBlockになり、nで捨てられたパス（クリッピングのみ）は含めない
+// 色はDeviceGray/DeviceRGB/DeviceCMYKとしてRGBに変換する（sc/scnはオペランドの数で色空間を判断する）
+func ExtractPaths(operations []Operation) []PathBlock {
+	gsStack := []GraphicsState{NewGraphicsState()}
+	var paths []PathBlock
+	var segments []PathSegment
+	var current, subpathStart Point
+
+	for _, op := range operations {
+		gs := &gsStack[len(gsStack)-1]
+		nums := numericOperands(op.Operands)
+
+		switch op.Operator {
+		case "q": // グラフィックス状態の保存
+			gsStack = append(gsStack, gs.Clone())
+
+		case "Q": // グラフィックス状態の復元
+			if len(gsStack) > 1 {
+				gsStack = gsStack[:len(gsStack)-1]
+			}
+
+		case "cm": // 変換行列の変更
+			if len(nums) == 6 {
+				gs.CTM = gs.CTM.Multiply(Matrix{A: nums[0], B: nums[1], C: nums[2], D: nums[3], E: nums[4], F: nums[5]})
+			}
+
+		case "w": // 線幅
+			if len(nums) == 1 {
+				gs.LineWidth = nums[0]
+			}
+
+		case "CS": // 線の色空間（色は黒に戻る）
+			gs.StrokeColor = [3]float64{0, 0, 0}
+		case "cs": // 塗りつぶしの色空間（色は黒に戻る）
+			gs.FillColor = [3]float64{0, 0, 0}
+
+		case "G", "RG", "K", "SC", "SCN":
+			if c, ok := deviceColor(nums); ok {
+				gs.StrokeColor = c
+			}
+		case "g", "rg", "k", "sc", "scn":
+			if c, ok := deviceColor(nums); ok {
+				gs.FillColor = c
+			}
+
+		case "m":
+			if len(nums) == 2 {
+				current = transformPoint(gs.CTM, nums[0], nums[1])
+				subpathStart = current
+				segments = append(segments, PathSegment{Op: PathOpMoveTo, Points: []Point{current}})
+			}
+
+		case "l":
+			if len(nums) == 2 {
+				current = transformPoint(gs.CTM, nums[0], nums[1])
+				segments = append(segments, PathSegment{Op: PathOpLineTo, Points: []Point{current}})
+			}
+
+		case "c", "v", "y":
+			var x1, y1, x2, y2, x3, y3 float64
+			switch {
+			case op.Operator == "c" && len(nums) == 6:
+				x1, y1, x2, y2, x3, y3 = nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]
+			case op.Operator != "c" && len(nums) == 4:
+				x2, y2, x3, y3 = nums[0], nums[1], nums[2], nums[3]
+				if op.Operator == "y" {
+					// y: 2つ目の制御点が終点と一致する
+					x1, y1, x2, y2 = x2, y2, x3, y3
+				}
+			default:
+				continue
+			}
+			p1 := transformPoint(gs.CTM, x1, y1)
+			if op.Operator == "v" {
+				// v: 1つ目の制御点が現在の点と一致する
+				p1 = current
+			}
+			p2 := transformPoint(gs.CTM, x2, y2)
+			current = transformPoint(gs.CTM, x3, y3)
+			segments = append(segments, PathSegment{Op: PathOpCurveTo, Points: []Point{p1, p2, current}})
+
+		case "h":
+			if len(segments) > 0 {
+				segments = append(segments, PathSegment{Op: PathOpClosePath})
+				current = subpathStart
+			}
+
+		case "re":
+			if len(nums) == 4 {
+				x, y, w, h := nums[0], nums[1], nums[2], nums[3]
+				corners := []Point{
+					transformPoint(gs.CTM, x, y),
+					transformPoint(gs.CTM, x+w, y),
+					transformPoint(gs.CTM, x+w, y+h),
+					transformPoint(gs.CTM, x, y+h),
+				}
+				segments = append(segments,
+					PathSegment{Op: PathOpMoveTo, Points: corners[0:1]},
+					PathSegment{Op: PathOpLineTo, Points: corners[1:2]},
+					PathSegment{Op: PathOpLineTo, Points: corners[2:3]},
+					PathSegment{Op: PathOpLineTo, Points: corners[3:4]},
+					PathSegment{Op: PathOpClosePath},
+				)
+				current, subpathStart = corners[0], corners[0]
+			}
+
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*":
+			if op.Operator == "s" || op.Operator == "b" || op.Operator == "b*" {
+				// s, b, b*: 描画の前にサブパスを閉じる
+				segments = append(segments, PathSegment{Op: PathOpClosePath})
+			}
+			if block, ok := newPathBlock(segments, op.Operator, *gs); ok {
+				paths = append(paths, block)
+			}
+			segments = nil
+
+		case "n": // 描画せずにパスを終える（クリッピングパスのみ）
+			segments = nil
+		}
+	}
+
+	return paths
+}
+
+// newPathBlock は描画オペレータとグラフィックス状態からPathBlockを作る
+func newPathBlock(segments []PathSegment, operator string, gs GraphicsState) (PathBlock, bool) {
+	var points []Point
+	for _, seg := range segments {
+		points = append(points, seg.Points...)
+	}
+	if len(points) == 0 {
+		return PathBlock{}, false
+	}
+
+	block := PathBlock{
+		Kind:        classifyPath(segments),
+		Segments:    segments,
+		Stroke:      operator != "f" && operator != "F" && operator != "f*",
+		Fill:        operator != "S" && operator != "s",
+		EvenOdd:     operator == "f*" || operator == "B*" || operator == "b*",
+		StrokeColor: gs.StrokeColor,
+		FillColor:   gs.FillColor,
+		// 線幅はユーザー空間の値なので、CTMの面積の拡大率の平方根を掛けてページ上の太さにする
+		LineWidth: gs.LineWidth * math.Sqrt(math.Abs(gs.CTM.A*gs.CTM.D-gs.CTM.B*gs.CTM.C)),
+		Transform: gs.CTM,
+	}
+
+	minX, minY, maxX, maxY := points[0].X, points[0].Y, points[0].X, points[0].Y
+	for _, p := range points[1:] {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	block.X, block.Y = minX, minY
+	block.Width, block.Height = maxX-minX, maxY-minY
+	return block, true
+}
+
+// classifyPath はパスの形状を分類する
+func classifyPath(segments []PathSegment) PathKind {
+	var subpaths [][]PathSegment
+	for _, seg := range segments {
+		if seg.Op == PathOpCurveTo {
+			return PathKindCurve
+		}
+		if seg.Op == PathOpMoveTo || len(subpaths) == 0 {
+			subpaths = append(subpaths, nil)
+		}
+		subpaths[len(subpaths)-1] = append(subpaths[len(subpaths)-1], seg)
+	}
+
+	allLines, allRects := true, true
+	for _, sub := range subpaths {
+		var points []Point
+		closed := false
+		for _, seg := range sub {
+			points = append(points, seg.Points...)
+			if seg.Op == PathOpClosePath {
+				closed = true
+			}
+		}
+		if len(points) != 2 || closed {
+			allLines = false
+		}
+		if !isAxisAlignedRect(points) {
+			allRects = false
+		}
+	}
+
+	switch {
+	case allLines:
+		return PathKindLine
+	case allRects:
+		return PathKindRect
+	default:
+		return PathKindPolygon
+	}
+}
+
+// isAxisAlignedRect は点列が軸に平行な矩形の4隅（始点に戻る5点目があってもよい）かを返す
+func isAxisAlignedRect(points []Point) bool {
+	if len(points) == 5 && points[4] == points[0] {
+		points = points[:4]
+	}
+	if len(points) != 4 {
+		return false
+	}
+	for i := range points {
+		p, q := points[i], points[(i+1)%4]
+		if p.X != q.X && p.Y != q.Y {
+			return false
+		}
+	}
+	// 隣り合う辺が交互に縦・横になっていること（4点が同一直線上にないこと）
+	return points[0].X != points[2].X && points[0].Y != points[2].Y
+}
+
+// deviceColor はDeviceGray（1成分）、DeviceRGB（3成分）、DeviceCMYK（4成分）の色をRGBに変換する
+func deviceColor(components []float64) ([3]float64, bool) {
+	switch len(components) {
+	case 1:
+		return [3]float64{components[0], components[0], components[0]}, true
+	case 3:
+		return [3]float64{components[0], components[1], components[2]}, true
+	case 4:
+		c, m, y, k := components[0], components[1], components[2], components[3]
+		return [3]float64{(1 - c) * (1 - k), (1 - m) * (1 - k), (1 - y) * (1 - k)}, true
+	}
+	return [3]float64{}, false
+}
+
+// numericOperands は数値のオペランドを取り出す（scnのパターン名などは除く）
+func numericOperands(operands []core.Object) []float64 {
+	var nums []float64
+	for _, operand := range operands {
+		switch v := operand.(type) {
+		case core.Integer:
+			nums = append(nums, float64(v))
+		case core.Real:
+			nums = append(nums, float64(v))
+		}
+	}
+	return nums
+}
+
+func transformPoint(m Matrix, x, y float64) Point {
+	tx, ty := m.TransformPoint(x, y)
+	return Point{X: tx, Y: ty}
+}
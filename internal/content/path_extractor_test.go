@@ -0,0 +1,167 @@
+package content
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// realOperands は数値をcore.Realのオペランドに変換する
+func realOperands(values ...float64) []core.Object {
+	objs := make([]core.Object, len(values))
+	for i, v := range values {
+		objs[i] = core.Real(v)
+	}
+	return objs
+}
+
+func TestExtractPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []Operation
+		want       []PathBlock // SegmentsはTestExtractPaths_Segmentsで確認する
+	}{
+		{
+			name: "stroked line",
+			operations: []Operation{
+				{Operator: "w", Operands: realOperands(2)},
+				{Operator: "RG", Operands: realOperands(1, 0, 0)},
+				{Operator: "m", Operands: realOperands(10, 100)},
+				{Operator: "l", Operands: realOperands(200, 100)},
+				{Operator: "S"},
+			},
+			want: []PathBlock{{
+				Kind: PathKindLine, Stroke: true, StrokeColor: [3]float64{1, 0, 0}, LineWidth: 2,
+				X: 10, Y: 100, Width: 190, Height: 0,
+			}},
+		},
+		{
+			name: "filled rect with cm and gray",
+			operations: []Operation{
+				{Operator: "q"},
+				{Operator: "cm", Operands: realOperands(2, 0, 0, 2, 50, 50)},
+				{Operator: "g", Operands: realOperands(0.5)},
+				{Operator: "re", Operands: realOperands(0, 0, 10, 20)},
+				{Operator: "f"},
+				{Operator: "Q"},
+			},
+			want: []PathBlock{{
+				Kind: PathKindRect, Fill: true, FillColor: [3]float64{0.5, 0.5, 0.5}, LineWidth: 2,
+				X: 50, Y: 50, Width: 20, Height: 40,
+			}},
+		},
+		{
+			name: "curve with cmyk fill and stroke",
+			operations: []Operation{
+				{Operator: "k", Operands: realOperands(0, 1, 1, 0)},
+				{Operator: "m", Operands: realOperands(0, 0)},
+				{Operator: "c", Operands: realOperands(0, 10, 10, 10, 10, 0)},
+				{Operator: "b*"},
+			},
+			want: []PathBlock{{
+				Kind: PathKindCurve, Stroke: true, Fill: true, EvenOdd: true,
+				FillColor: [3]float64{1, 0, 0}, LineWidth: 1,
+				X: 0, Y: 0, Width: 10, Height: 10,
+			}},
+		},
+		{
+			name: "closed polygon",
+			operations: []Operation{
+				{Operator: "m", Operands: realOperands(0, 0)},
+				{Operator: "l", Operands: realOperands(10, 0)},
+				{Operator: "l", Operands: realOperands(5, 10)},
+				{Operator: "s"},
+			},
+			want: []PathBlock{{
+				Kind: PathKindPolygon, Stroke: true, LineWidth: 1,
+				X: 0, Y: 0, Width: 10, Height: 10,
+			}},
+		},
+		{
+			name: "state restored after Q",
+			operations: []Operation{
+				{Operator: "q"},
+				{Operator: "RG", Operands: realOperands(0, 0, 1)},
+				{Operator: "w", Operands: realOperands(3)},
+				{Operator: "Q"},
+				{Operator: "m", Operands: realOperands(0, 0)},
+				{Operator: "l", Operands: realOperands(0, 50)},
+				{Operator: "S"},
+			},
+			want: []PathBlock{{
+				Kind: PathKindLine, Stroke: true, LineWidth: 1,
+				X: 0, Y: 0, Width: 0, Height: 50,
+			}},
+		},
+		{
+			name: "clipping path discarded",
+			operations: []Operation{
+				{Operator: "re", Operands: realOperands(0, 0, 100, 100)},
+				{Operator: "W"},
+				{Operator: "n"},
+				{Operator: "S"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractPaths(tt.operations)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractPaths() returned %d paths, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Kind != w.Kind || g.Stroke != w.Stroke || g.Fill != w.Fill || g.EvenOdd != w.EvenOdd {
+					t.Errorf("path %d = {Kind:%s Stroke:%v Fill:%v EvenOdd:%v}, want {Kind:%s Stroke:%v Fill:%v EvenOdd:%v}",
+						i, g.Kind, g.Stroke, g.Fill, g.EvenOdd, w.Kind, w.Stroke, w.Fill, w.EvenOdd)
+				}
+				if g.StrokeColor != w.StrokeColor || g.FillColor != w.FillColor {
+					t.Errorf("path %d colors = %v/%v, want %v/%v", i, g.StrokeColor, g.FillColor, w.StrokeColor, w.FillColor)
+				}
+				if math.Abs(g.LineWidth-w.LineWidth) > 1e-9 {
+					t.Errorf("path %d LineWidth = %v, want %v", i, g.LineWidth, w.LineWidth)
+				}
+				if g.X != w.X || g.Y != w.Y || g.Width != w.Width || g.Height != w.Height {
+					t.Errorf("path %d bounds = (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+						i, g.X, g.Y, g.Width, g.Height, w.X, w.Y, w.Width, w.Height)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractPaths_Segments(t *testing.T) {
+	paths := ExtractPaths([]Operation{
+		{Operator: "cm", Operands: realOperands(1, 0, 0, 1, 100, 0)},
+		{Operator: "m", Operands: realOperands(0, 0)},
+		{Operator: "v", Operands: realOperands(5, 5, 10, 0)},
+		{Operator: "y", Operands: realOperands(15, 5, 20, 0)},
+		{Operator: "S"},
+	})
+	if len(paths) != 1 {
+		t.Fatalf("ExtractPaths() returned %d paths, want 1", len(paths))
+	}
+
+	want := []PathSegment{
+		{Op: PathOpMoveTo, Points: []Point{{100, 0}}},
+		{Op: PathOpCurveTo, Points: []Point{{100, 0}, {105, 5}, {110, 0}}},
+		{Op: PathOpCurveTo, Points: []Point{{115, 5}, {120, 0}, {120, 0}}},
+	}
+	got := paths[0].Segments
+	if len(got) != len(want) {
+		t.Fatalf("Segments = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Op != want[i].Op || len(got[i].Points) != len(want[i].Points) {
+			t.Fatalf("Segments[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+		for j := range want[i].Points {
+			if got[i].Points[j] != want[i].Points[j] {
+				t.Errorf("Segments[%d].Points[%d] = %v, want %v", i, j, got[i].Points[j], want[i].Points[j])
+			}
+		}
+	}
+}
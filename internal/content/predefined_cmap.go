@@ -0,0 +1,169 @@
+package content
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// PredefinedCMap は複合フォント（Type0）の/Encodingに指定される定義済みCMap
+// ToUnicodeがない場合に、文字コードからUnicodeへの変換に使う
+type PredefinedCMap struct {
+	Name   string
+	decode func(data []byte) string
+	encode func(text string) ([]byte, error)
+}
+
+// Decode は文字コードの列をUnicode文字列に変換する
+func (c *PredefinedCMap) Decode(data []byte) string {
+	return c.decode(data)
+}
+
+// Encode はUnicode文字列を文字コードの列に変換する（Decodeの逆）
+// CMapの文字コード体系で表せない文字があればエラーを返す
+func (c *PredefinedCMap) Encode(text string) ([]byte, error) {
+	return c.encode(text)
+}
+
+// legacyCMapCharsets は文字コードが既存の文字コード体系と一致する定義済みCMap（-H/-V を除いた名前）
+var legacyCMapCharsets = map[string]encoding.Encoding{
+	// Adobe-Japan1
+	"83pv-RKSJ":  japanese.ShiftJIS,
+	"90ms-RKSJ":  japanese.ShiftJIS,
+	"90msp-RKSJ": japanese.ShiftJIS,
+	"90pv-RKSJ":  japanese.ShiftJIS,
+	"78-RKSJ":    japanese.ShiftJIS,
+	"Ext-RKSJ":   japanese.ShiftJIS,
+	"EUC":        japanese.EUCJP,
+	"78-EUC":     japanese.EUCJP,
+
+	// Adobe-GB1
+	"GB-EUC":   simplifiedchinese.GBK,
+	"GBpc-EUC": simplifiedchinese.GBK,
+	"GBK-EUC":  simplifiedchinese.GBK,
+	"GBKp-EUC": simplifiedchinese.GBK,
+	"GBK2K":    simplifiedchinese.GB18030,
+
+	// Adobe-CNS1
+	"B5pc":      traditionalchinese.Big5,
+	"ETen-B5":   traditionalchinese.Big5,
+	"ETenms-B5": traditionalchinese.Big5,
+	"HKscs-B5":  traditionalchinese.Big5,
+
+	// Adobe-Korea1
+	"KSC-EUC":   korean.EUCKR,
+	"KSCpc-EUC": korean.EUCKR,
+	"KSCms-UHC": korean.EUCKR,
+}
+
+// lookupPredefinedCMap は定義済みCMapの名前から変換を返す（対応していない名前はnil）
+//
+// Unicode系のCMap（UniJIS-UCS2-H、UniGB-UTF16-Hなど）は文字コードがそのままUnicodeの符号化なので、
+// UCS-2/UTF-16BE、UTF-8、UTF-32BEとして変換する。Shift-JIS、EUC、GBK、Big5などの文字コードを
+// 使うCMapは、その文字コード体系の変換表で変換する。
+// Identity-H/V はCIDそのものなので、CIDとUnicodeの対応表なしには変換できない（nil）。
+func lookupPredefinedCMap(name string) *PredefinedCMap {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, "-H"), "-V")
+
+	if strings.HasPrefix(base, "Uni") {
+		switch {
+		case strings.HasSuffix(base, "-UCS2"), strings.HasSuffix(base, "-UTF16"), strings.HasSuffix(base, "-UCS2-HW"):
+			return &PredefinedCMap{Name: name, decode: decodeUTF16BE, encode: encodeUTF16BE}
+		case strings.HasSuffix(base, "-UTF8"):
+			return &PredefinedCMap{Name: name, decode: decodeCMapUTF8, encode: encodeCMapUTF8}
+		case strings.HasSuffix(base, "-UTF32"):
+			return &PredefinedCMap{Name: name, decode: decodeUTF32BE, encode: encodeUTF32BE}
+		}
+		return nil
+	}
+
+	// 半角の形（-HW）は同じ文字コード体系
+	charset, ok := legacyCMapCharsets[strings.TrimSuffix(base, "-HW")]
+	if !ok {
+		return nil
+	}
+	return &PredefinedCMap{
+		Name: name,
+		decode: func(data []byte) string {
+			decoded, err := charset.NewDecoder().Bytes(data)
+			if err != nil {
+				return ""
+			}
+			return string(decoded)
+		},
+		encode: func(text string) ([]byte, error) {
+			return charset.NewEncoder().Bytes([]byte(text))
+		},
+	}
+}
+
+// loadPredefinedCMap は複合フォント（Type0）の/Encodingから定義済みCMapを探す
+// 埋め込みCMap（ストリーム）の場合は/CMapNameで探す
+func loadPredefinedCMap(r *reader.Reader, fontDict core.Dictionary) *PredefinedCMap {
+	if fontDict[core.Name("Subtype")] != core.Name("Type0") {
+		return nil
+	}
+
+	switch v := r.Resolve(fontDict[core.Name("Encoding")]).(type) {
+	case core.Name:
+		return lookupPredefinedCMap(string(v))
+	case *core.Stream:
+		if name, ok := v.Dict[core.Name("CMapName")].(core.Name); ok {
+			return lookupPredefinedCMap(string(name))
+		}
+	}
+	return nil
+}
+
+// decodeCMapUTF8 はUTF-8の文字コードを変換する（不正なバイトは除く）
+func decodeCMapUTF8(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return strings.ToValidUTF8(string(data), "")
+}
+
+// decodeUTF32BE はUTF-32BEを変換する
+func decodeUTF32BE(data []byte) string {
+	var sb strings.Builder
+	for i := 0; i+4 <= len(data); i += 4 {
+		r := rune(data[i])<<24 | rune(data[i+1])<<16 | rune(data[i+2])<<8 | rune(data[i+3])
+		if utf8.ValidRune(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// encodeUTF16BE はUTF-16BEに変換する（UCS-2/UTF-16のCMap）
+func encodeUTF16BE(text string) ([]byte, error) {
+	units := utf16.Encode([]rune(text))
+	data := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		data = append(data, byte(u>>8), byte(u))
+	}
+	return data, nil
+}
+
+// encodeCMapUTF8 はUTF-8の文字コードに変換する
+func encodeCMapUTF8(text string) ([]byte, error) {
+	return []byte(text), nil
+}
+
+// encodeUTF32BE はUTF-32BEに変換する
+func encodeUTF32BE(text string) ([]byte, error) {
+	data := make([]byte, 0, 4*len(text))
+	for _, r := range text {
+		data = append(data, byte(r>>24), byte(r>>16), byte(r>>8), byte(r))
+	}
+	return data, nil
+}
@@ -0,0 +1,102 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// TestLookupPredefinedCMap は定義済みCMapによる文字コードの変換をテストする
+func TestLookupPredefinedCMap(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"UniJIS-UCS2-H", "\x30\x42\x00A", "あA"},
+		{"UniJIS-UCS2-HW-V", "\x30\x42", "あ"},
+		{"UniGB-UTF16-H", "\x4e\x2d\xd8\x40\xdc\x0b", "中𠀋"},
+		{"UniKS-UTF8-H", "한국", "한국"},
+		{"UniJIS-UTF32-H", "\x00\x02\x00\x0b\x00\x00\x65\xe5", "𠀋日"},
+		{"90ms-RKSJ-H", "\x93\xfa\x96\x7bA", "日本A"},
+		{"90msp-RKSJ-V", "\x93\xfa", "日"},
+		{"EUC-H", "\xc6\xfc\xcb\xdc", "日本"},
+		{"GBK-EUC-H", "\xd6\xd0\xce\xc4", "中文"},
+		{"GBK2K-H", "\xd6\xd0", "中"},
+		{"ETen-B5-H", "\xa4\xa4\xa4\xe5", "中文"},
+		{"KSCms-UHC-HW-H", "\xc7\xd1\xb1\xb9", "한국"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmap := lookupPredefinedCMap(tt.name)
+			if cmap == nil {
+				t.Fatalf("lookupPredefinedCMap(%q) = nil", tt.name)
+			}
+			if got := cmap.Decode([]byte(tt.data)); got != tt.want {
+				t.Errorf("Decode() = %q, want %q", got, tt.want)
+			}
+			if got, err := cmap.Encode(tt.want); err != nil || string(got) != tt.data {
+				t.Errorf("Encode() = %q, %v, want %q", got, err, tt.data)
+			}
+		})
+	}
+
+	for _, name := range []string{"Identity-H", "Identity-V", "UniJIS-X-H", "Unknown-H"} {
+		if lookupPredefinedCMap(name) != nil {
+			t.Errorf("lookupPredefinedCMap(%q) should be nil", name)
+		}
+	}
+}
+
+// TestLoadPredefinedCMap はフォント辞書からの定義済みCMapの読み込みをテストする
+func TestLoadPredefinedCMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		fontDict core.Dictionary
+		want     string
+	}{
+		{
+			name:     "named CMap",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type0"), core.Name("Encoding"): core.Name("90ms-RKSJ-H")},
+			want:     "90ms-RKSJ-H",
+		},
+		{
+			name: "embedded CMap",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type0"), core.Name("Encoding"): &core.Stream{
+				Dict: core.Dictionary{core.Name("Type"): core.Name("CMap"), core.Name("CMapName"): core.Name("UniJIS-UCS2-H")},
+			}},
+			want: "UniJIS-UCS2-H",
+		},
+		{
+			name:     "Identity",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type0"), core.Name("Encoding"): core.Name("Identity-H")},
+		},
+		{
+			name:     "simple font",
+			fontDict: core.Dictionary{core.Name("Subtype"): core.Name("Type1"), core.Name("Encoding"): core.Name("90ms-RKSJ-H")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmap := loadPredefinedCMap(nil, tt.fontDict)
+			got := ""
+			if cmap != nil {
+				got = cmap.Name
+			}
+			if got != tt.want {
+				t.Errorf("loadPredefinedCMap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTextExtractor_PredefinedCMap はToUnicodeのない複合フォントのテキストの抽出をテストする
+func TestTextExtractor_PredefinedCMap(t *testing.T) {
+	e := NewTextExtractor(nil, nil, nil)
+	e.currentFontInfo = &FontInfo{Name: "F1", CMap: lookupPredefinedCMap("90ms-RKSJ-H")}
+	if got := e.getTextString(core.String("\x82\xa0\x82\xa2")); got != "あい" {
+		t.Errorf("getTextString() = %q, want %q", got, "あい")
+	}
+}
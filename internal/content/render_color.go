@@ -0,0 +1,184 @@
+package content
+
+import (
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// colorSpaceKind は描画で扱う色空間の種類
+type colorSpaceKind int
+
+const (
+	colorSpaceGray       colorSpaceKind = iota // DeviceGray, CalGray
+	colorSpaceRGB                              // DeviceRGB, CalRGB
+	colorSpaceCMYK                             // DeviceCMYK
+	colorSpaceLab                              // Lab（明度だけを使う）
+	colorSpaceIndexed                          // Indexed
+	colorSpaceSeparation                       // Separation, DeviceN（色材の量を濃さとして扱う）
+	colorSpacePattern                          // Pattern（描画しない）
+)
+
+// renderColorSpace は描画用の色空間
+type renderColorSpace struct {
+	kind       colorSpaceKind
+	components int // 1色の成分の数
+
+	// Indexed
+	base   *renderColorSpace
+	hival  int
+	lookup []byte
+}
+
+var deviceGraySpace = renderColorSpace{kind: colorSpaceGray, components: 1}
+
+// rgb は色の成分（0〜1、Indexedは番号）をRGBに変換する
+// Patternの場合はfalseを返す
+func (cs renderColorSpace) rgb(components []float64) ([3]float64, bool) {
+	switch cs.kind {
+	case colorSpaceIndexed:
+		if len(components) == 0 || cs.base == nil {
+			return [3]float64{}, false
+		}
+		index := int(components[0])
+		if index < 0 || index > cs.hival {
+			return [3]float64{}, false
+		}
+		n := cs.base.components
+		start := index * n
+		if start+n > len(cs.lookup) {
+			return [3]float64{}, false
+		}
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = float64(cs.lookup[start+i]) / 255
+		}
+		return cs.base.rgb(values)
+
+	case colorSpaceSeparation:
+		if len(components) == 0 {
+			return [3]float64{}, false
+		}
+		var tint float64
+		for _, c := range components {
+			tint = max(tint, c)
+		}
+		return [3]float64{1 - tint, 1 - tint, 1 - tint}, true
+
+	case colorSpaceLab:
+		if len(components) == 0 {
+			return [3]float64{}, false
+		}
+		l := components[0] / 100
+		return [3]float64{l, l, l}, true
+
+	case colorSpacePattern:
+		return [3]float64{}, false
+	}
+
+	return deviceColor(components)
+}
+
+// initialColor は色空間を設定したときの初期の色を返す
+// Indexedは番号0、それ以外は黒（DeviceCMYKのKとSeparationの色材の量が1）
+func (cs renderColorSpace) initialColor() [3]float64 {
+	if cs.kind == colorSpaceIndexed {
+		c, _ := cs.rgb([]float64{0})
+		return c
+	}
+	return [3]float64{0, 0, 0}
+}
+
+// parseColorSpace は色空間（名前または配列）を描画用の色空間にする
+// 名前がデバイス色空間でなければresourcesの/ColorSpaceから探す
+func parseColorSpace(r *reader.Reader, obj core.Object, resources core.Dictionary) renderColorSpace {
+	obj = resolve(r, obj)
+
+	if name, ok := obj.(core.Name); ok {
+		switch name {
+		case "DeviceGray", "G", "CalGray":
+			return deviceGraySpace
+		case "DeviceRGB", "RGB", "CalRGB":
+			return renderColorSpace{kind: colorSpaceRGB, components: 3}
+		case "DeviceCMYK", "CMYK":
+			return renderColorSpace{kind: colorSpaceCMYK, components: 4}
+		case "Pattern":
+			return renderColorSpace{kind: colorSpacePattern}
+		}
+		spaces, _ := resolve(r, resources[core.Name("ColorSpace")]).(core.Dictionary)
+		if named, ok := spaces[name]; ok {
+			return parseColorSpace(r, named, nil)
+		}
+		return deviceGraySpace
+	}
+
+	arr, ok := obj.(core.Array)
+	if !ok || len(arr) == 0 {
+		return deviceGraySpace
+	}
+	family, _ := resolve(r, arr[0]).(core.Name)
+	switch family {
+	case "CalGray", "CalRGB", "DeviceGray", "DeviceRGB", "DeviceCMYK":
+		return parseColorSpace(r, family, nil)
+
+	case "ICCBased":
+		if len(arr) > 1 {
+			if stream, ok := resolve(r, arr[1]).(*core.Stream); ok {
+				switch int(getNumber(resolve(r, stream.Dict[core.Name("N")]))) {
+				case 1:
+					return deviceGraySpace
+				case 4:
+					return renderColorSpace{kind: colorSpaceCMYK, components: 4}
+				}
+			}
+		}
+		return renderColorSpace{kind: colorSpaceRGB, components: 3}
+
+	case "Lab":
+		return renderColorSpace{kind: colorSpaceLab, components: 3}
+
+	case "Indexed", "I":
+		if len(arr) < 4 {
+			return deviceGraySpace
+		}
+		base := parseColorSpace(r, arr[1], resources)
+		cs := renderColorSpace{
+			kind:       colorSpaceIndexed,
+			components: 1,
+			base:       &base,
+			hival:      int(getNumber(resolve(r, arr[2]))),
+		}
+		switch lookup := resolve(r, arr[3]).(type) {
+		case core.String:
+			cs.lookup = []byte(lookup)
+		case *core.Stream:
+			if r != nil {
+				cs.lookup, _ = r.DecodeStream(lookup)
+			}
+		}
+		return cs
+
+	case "Separation":
+		return renderColorSpace{kind: colorSpaceSeparation, components: 1}
+
+	case "DeviceN":
+		n := 1
+		if len(arr) > 1 {
+			if names, ok := resolve(r, arr[1]).(core.Array); ok {
+				n = len(names)
+			}
+		}
+		return renderColorSpace{kind: colorSpaceSeparation, components: n}
+
+	case "Pattern":
+		return renderColorSpace{kind: colorSpacePattern}
+	}
+	return deviceGraySpace
+}
+
+// resolve は間接参照を解決する（リーダーがなければそのまま返す）
+func resolve(r *reader.Reader, obj core.Object) core.Object {
+	if r == nil {
+		return obj
+	}
+	return r.Resolve(obj)
+}
@@ -0,0 +1,341 @@
+package content
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/font/embedded"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// glyphOutlineScale はアウトラインを取り出すときの1 emの大きさ（1/1000 em単位で取り出す）
+const glyphOutlineScale = 1000
+
+// renderFont は文字の形（アウトライン）を取り出すフォント
+// 埋め込まれたTrueType・OpenTypeフォントがあればそれを使い、
+// なければ（または文字が見つからなければ）フォント名に近い代替フォントで描く
+type renderFont struct {
+	embedded  *sfnt.Font   // 埋め込まれたフォント（なければnil）
+	composite bool         // 複合フォント（Type0）か
+	cidToGID  []byte       // CIDToGIDMapのストリーム（nilはIdentity）
+	fallback  []*sfnt.Font // 代替フォント（先頭から探す）
+
+	buf      sfnt.Buffer
+	outlines map[glyphKey]glyphOutline
+}
+
+// glyphKey は文字のアウトラインのキャッシュのキー
+type glyphKey struct {
+	code int    // 文字コード（分からなければ-1）
+	text string // 文字
+}
+
+// glyphOutline は1文字のアウトライン
+type glyphOutline struct {
+	path    devicePath // テキスト空間のアウトライン（1 em = 1、y軸は上向き）
+	advance float64    // 代替フォントで描く場合の送り幅（em、埋め込まれたフォントは0）
+}
+
+// loadRenderFont はフォント辞書から描画用のフォントを読み込む
+// 埋め込まれたフォントのうち、FontFile2（TrueType）と/Subtype /OpenTypeのFontFile3だけを使う
+// （Type1・CFF単体・Type3フォントは代替フォントで描く）
+func loadRenderFont(r *reader.Reader, fontDict core.Dictionary) *renderFont {
+	f := &renderFont{outlines: make(map[glyphKey]glyphOutline)}
+
+	descendant := fontDict
+	if fontDict[core.Name("Subtype")] == core.Name("Type0") {
+		f.composite = true
+		descendant = nil
+		if descendants, ok := resolve(r, fontDict[core.Name("DescendantFonts")]).(core.Array); ok && len(descendants) > 0 {
+			descendant, _ = resolve(r, descendants[0]).(core.Dictionary)
+		}
+		if stream, ok := resolve(r, descendant[core.Name("CIDToGIDMap")]).(*core.Stream); ok && r != nil {
+			f.cidToGID, _ = r.DecodeStream(stream)
+		}
+	}
+
+	baseFont, _ := resolve(r, fontDict[core.Name("BaseFont")]).(core.Name)
+	f.fallback = fallbackFonts(string(baseFont))
+
+	descriptor, _ := resolve(r, descendant[core.Name("FontDescriptor")]).(core.Dictionary)
+	if data := embeddedFontProgram(r, descriptor); data != nil {
+		if font, err := sfnt.Parse(normalizeSFNT(data)); err == nil {
+			f.embedded = font
+		}
+	}
+	return f
+}
+
+// embeddedFontProgram は/FontDescriptorから描画できる形式の埋め込みフォントを取り出す
+func embeddedFontProgram(r *reader.Reader, descriptor core.Dictionary) []byte {
+	if r == nil || descriptor == nil {
+		return nil
+	}
+	if stream, ok := resolve(r, descriptor[core.Name("FontFile2")]).(*core.Stream); ok {
+		data, _ := r.DecodeStream(stream)
+		return data
+	}
+	if stream, ok := resolve(r, descriptor[core.Name("FontFile3")]).(*core.Stream); ok {
+		if stream.Dict[core.Name("Subtype")] == core.Name("OpenType") {
+			data, _ := r.DecodeStream(stream)
+			return data
+		}
+	}
+	return nil
+}
+
+// outline は文字コードcode（分からなければ-1）、文字textの文字のアウトラインを返す
+// 埋め込まれたフォントで見つからなければ代替フォントで探し、どれにもなければ空のアウトラインを返す
+func (f *renderFont) outline(code int, text string) glyphOutline {
+	key := glyphKey{code: code, text: text}
+	if o, ok := f.outlines[key]; ok {
+		return o
+	}
+
+	var o glyphOutline
+	if gid := f.embeddedGlyph(code, text); gid != 0 {
+		o.path = f.loadOutline(f.embedded, gid)
+	} else if r, ok := singleRune(text); ok {
+		for _, font := range f.fallback {
+			gid, err := font.GlyphIndex(&f.buf, r)
+			if err != nil || gid == 0 {
+				continue
+			}
+			o.path = f.loadOutline(font, gid)
+			if advance, err := font.GlyphAdvance(&f.buf, gid, fixed.I(glyphOutlineScale), 0); err == nil {
+				o.advance = float64(advance) / 64 / glyphOutlineScale
+			}
+			break
+		}
+	}
+
+	f.outlines[key] = o
+	return o
+}
+
+// embeddedGlyph は埋め込まれたフォントのグリフ番号を返す（見つからなければ0）
+// 複合フォントは文字コードをCIDとし、CIDToGIDMapでグリフ番号にする
+// 単純フォントはフォントのcmapで、文字、文字コード、シンボルフォントの文字コード（0xF000 + 文字コード）の順に探す
+func (f *renderFont) embeddedGlyph(code int, text string) sfnt.GlyphIndex {
+	if f.embedded == nil {
+		return 0
+	}
+
+	if f.composite {
+		if code < 0 {
+			return 0
+		}
+		gid := code
+		if f.cidToGID != nil {
+			if 2*code+1 >= len(f.cidToGID) {
+				return 0
+			}
+			gid = int(binary.BigEndian.Uint16(f.cidToGID[2*code:]))
+		}
+		if gid >= f.embedded.NumGlyphs() {
+			return 0
+		}
+		return sfnt.GlyphIndex(gid)
+	}
+
+	var candidates []rune
+	if r, ok := singleRune(text); ok {
+		candidates = append(candidates, r)
+	}
+	if code >= 0 {
+		candidates = append(candidates, rune(code), 0xF000+rune(code))
+	}
+	for _, r := range candidates {
+		if gid, err := f.embedded.GlyphIndex(&f.buf, r); err == nil && gid != 0 {
+			return gid
+		}
+	}
+	return 0
+}
+
+// loadOutline はグリフのアウトラインをテキスト空間（1 em = 1、y軸は上向き）のパスにする
+func (f *renderFont) loadOutline(font *sfnt.Font, gid sfnt.GlyphIndex) devicePath {
+	segments, err := font.LoadGlyph(&f.buf, gid, fixed.I(glyphOutlineScale), nil)
+	if err != nil {
+		return nil
+	}
+
+	// sfntの座標はy軸が下向き
+	pt := func(p fixed.Point26_6) Point {
+		return Point{X: float64(p.X) / 64 / glyphOutlineScale, Y: -float64(p.Y) / 64 / glyphOutlineScale}
+	}
+	path := make(devicePath, 0, len(segments))
+	for _, seg := range segments {
+		cmd := pathCmd{}
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			cmd.op = pathMoveTo
+		case sfnt.SegmentOpLineTo:
+			cmd.op = pathLineTo
+		case sfnt.SegmentOpQuadTo:
+			cmd.op = pathQuadTo
+		case sfnt.SegmentOpCubeTo:
+			cmd.op = pathCubeTo
+		}
+		for i := 0; i < cmd.op.pointCount(); i++ {
+			cmd.pts[i] = pt(seg.Args[i])
+		}
+		path = append(path, cmd)
+	}
+	return path
+}
+
+// singleRune は文字列が1文字であればその文字を返す
+func singleRune(text string) (rune, bool) {
+	runes := []rune(text)
+	if len(runes) != 1 {
+		return 0, false
+	}
+	return runes[0], true
+}
+
+var (
+	fallbackOnce   sync.Once
+	fallbackByName map[string]*sfnt.Font
+)
+
+// fallbackFonts はフォント名（/BaseFont）に近いGoフォントと、日本語用のKoruriを返す
+// 等幅（Courier、Mono）、太字（Bold）、斜体（Italic、Oblique）をフォント名から判断する
+func fallbackFonts(baseFont string) []*sfnt.Font {
+	fallbackOnce.Do(func() {
+		fallbackByName = make(map[string]*sfnt.Font)
+		sources := map[string][]byte{
+			"regular":        goregular.TTF,
+			"bold":           gobold.TTF,
+			"italic":         goitalic.TTF,
+			"bolditalic":     gobolditalic.TTF,
+			"mono":           gomono.TTF,
+			"monobold":       gomonobold.TTF,
+			"monoitalic":     gomonoitalic.TTF,
+			"monobolditalic": gomonobolditalic.TTF,
+			"japanese":       embedded.KoruriRegular,
+		}
+		for name, data := range sources {
+			if font, err := sfnt.Parse(data); err == nil {
+				fallbackByName[name] = font
+			}
+		}
+	})
+
+	name := strings.ToLower(baseFont)
+	style := ""
+	if strings.Contains(name, "courier") || strings.Contains(name, "mono") {
+		style = "mono"
+	}
+	bold := strings.Contains(name, "bold") || strings.Contains(name, "black") || strings.Contains(name, "heavy")
+	italic := strings.Contains(name, "italic") || strings.Contains(name, "oblique")
+	switch {
+	case bold && italic:
+		style += "bolditalic"
+	case bold:
+		style += "bold"
+	case italic:
+		style += "italic"
+	case style == "":
+		style = "regular"
+	}
+
+	var fonts []*sfnt.Font
+	for _, key := range []string{style, "japanese"} {
+		if font, ok := fallbackByName[key]; ok {
+			fonts = append(fonts, font)
+		}
+	}
+	return fonts
+}
+
+// normalizeSFNT はPDFに埋め込まれたTrueTypeフォントを、sfntパッケージで読める形に整える
+// サブセット化されたフォントはcmapテーブルを持たないことや、テーブルがタグ順に並んでいないことがあるため、
+// テーブルをタグ順に並べ直し、cmapがなければ何も対応付けない空のcmapを加える
+func normalizeSFNT(data []byte) []byte {
+	if len(data) < 12 {
+		return data
+	}
+	version := binary.BigEndian.Uint32(data)
+	if version != 0x00010000 && version != 0x4F54544F && version != 0x74727565 { // 1.0, 'OTTO', 'true'
+		return data
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*numTables {
+		return data
+	}
+
+	type table struct {
+		tag  uint32
+		data []byte
+	}
+	tables := make([]table, 0, numTables+1)
+	hasCmap := false
+	for i := 0; i < numTables; i++ {
+		entry := data[12+16*i:]
+		tag := binary.BigEndian.Uint32(entry)
+		offset, length := binary.BigEndian.Uint32(entry[8:]), binary.BigEndian.Uint32(entry[12:])
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return data
+		}
+		tables = append(tables, table{tag: tag, data: data[offset : offset+length]})
+		hasCmap = hasCmap || tag == 0x636D6170 // 'cmap'
+	}
+	sorted := sort.SliceIsSorted(tables, func(i, j int) bool { return tables[i].tag < tables[j].tag })
+	if sorted && hasCmap {
+		return data
+	}
+	if !hasCmap {
+		tables = append(tables, table{tag: 0x636D6170, data: emptyCmap()})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].tag < tables[j].tag })
+
+	// テーブルディレクトリを作り直し、テーブルを4バイト境界に並べる
+	offset := 12 + 16*len(tables)
+	header := make([]byte, offset)
+	binary.BigEndian.PutUint32(header, version)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tables)))
+	var body []byte
+	for i, t := range tables {
+		entry := header[12+16*i:]
+		binary.BigEndian.PutUint32(entry, t.tag)
+		binary.BigEndian.PutUint32(entry[8:], uint32(offset+len(body)))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(t.data)))
+		body = append(body, t.data...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	return append(header, body...)
+}
+
+// emptyCmap はどの文字もグリフに対応付けないcmapテーブル（Windows Unicode、フォーマット4）を返す
+func emptyCmap() []byte {
+	cmap := []uint16{
+		0, 1, // version, numTables
+		3, 1, 0, 12, // platformID, encodingID, offset（32ビット）
+		4, 24, 0, // format, length, language
+		2, 2, 0, 0, // segCountX2, searchRange, entrySelector, rangeShift
+		0xFFFF, 0, // endCode, reservedPad
+		0xFFFF, 1, 0, // startCode, idDelta, idRangeOffset
+	}
+	out := make([]byte, 2*len(cmap))
+	for i, v := range cmap {
+		binary.BigEndian.PutUint16(out[2*i:], v)
+	}
+	return out
+}
@@ -0,0 +1,257 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	"golang.org/x/image/ccitt"
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// maxImagePixels は描画する画像のピクセル数の上限（壊れた/Width、/Heightで巨大な画像を作らないため）
+const maxImagePixels = 1 << 26
+
+// loadImage は画像XObjectを描画できる画像にする
+// DCTDecode（JPEG）、CCITTFaxDecode、フィルターで展開できるサンプルに対応し、JPXDecode（JPEG 2000）には対応しない
+// /ImageMaskはfillの色のステンシル、/SMaskは透明度として扱う
+func loadImage(r *reader.Reader, stream *core.Stream, resources core.Dictionary, fill [3]float64) (*image.NRGBA, error) {
+	img, err := decodeImage(r, stream, resources, fill)
+	if err != nil {
+		return nil, err
+	}
+	if smask, ok := resolve(r, stream.Dict[core.Name("SMask")]).(*core.Stream); ok {
+		applySoftMask(r, img, smask)
+	}
+	return img, nil
+}
+
+// DecodeImage は画像XObjectのサンプルをRGBの画像にする（/SMaskと/Maskは適用しない）
+// 画像を縮小して置き換えるときに使う。/ImageMaskの画像（ステンシル）は色を持たないためエラーにする
+func DecodeImage(r *reader.Reader, stream *core.Stream, resources core.Dictionary) (*image.NRGBA, error) {
+	if resolve(r, stream.Dict[core.Name("ImageMask")]) == core.Boolean(true) {
+		return nil, fmt.Errorf("image masks cannot be decoded as images")
+	}
+	// JPEGの画像は展開時に/Decodeを適用しないため、元の色にならない
+	if filter, _ := lastFilter(r, stream.Dict); (filter == "DCTDecode" || filter == "DCT") && stream.Dict[core.Name("Decode")] != nil {
+		return nil, fmt.Errorf("/Decode of JPEG images is not supported")
+	}
+	return decodeImage(r, stream, resources, [3]float64{})
+}
+
+// decodeImage は画像XObjectのサンプルを展開して画像にする（/SMaskは適用しない）
+func decodeImage(r *reader.Reader, stream *core.Stream, resources core.Dictionary, fill [3]float64) (*image.NRGBA, error) {
+	dict := stream.Dict
+	width := int(getNumber(resolve(r, dict[core.Name("Width")])))
+	height := int(getNumber(resolve(r, dict[core.Name("Height")])))
+	if width <= 0 || height <= 0 || width*height > maxImagePixels {
+		return nil, fmt.Errorf("invalid image size %dx%d", width, height)
+	}
+
+	data, err := r.DecodeStream(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image stream: %w", err)
+	}
+
+	var img *image.NRGBA
+	switch filter, params := lastFilter(r, dict); filter {
+	case "DCTDecode", "DCT":
+		decoded, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode JPEG image: %w", err)
+		}
+		img = image.NewNRGBA(decoded.Bounds())
+		xdraw.Draw(img, img.Bounds(), decoded, decoded.Bounds().Min, xdraw.Src)
+
+	case "JPXDecode":
+		return nil, fmt.Errorf("JPXDecode images are not supported")
+
+	case "CCITTFaxDecode", "CCF":
+		data, err = decodeCCITT(r, data, params, width, height)
+		if err != nil {
+			return nil, err
+		}
+		fallthrough
+
+	default:
+		img, err = decodeSamples(r, dict, data, width, height, resources, fill)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+// lastFilter は最後に適用されるフィルター（画像の形式を表すもの）とそのパラメーターを返す
+func lastFilter(r *reader.Reader, dict core.Dictionary) (string, core.Dictionary) {
+	params := resolve(r, dict[core.Name("DecodeParms")])
+	switch filter := resolve(r, dict[core.Name("Filter")]).(type) {
+	case core.Name:
+		p, _ := params.(core.Dictionary)
+		return string(filter), p
+	case core.Array:
+		if len(filter) == 0 {
+			return "", nil
+		}
+		name, _ := resolve(r, filter[len(filter)-1]).(core.Name)
+		var p core.Dictionary
+		if arr, ok := params.(core.Array); ok && len(arr) == len(filter) {
+			p, _ = resolve(r, arr[len(arr)-1]).(core.Dictionary)
+		}
+		return string(name), p
+	}
+	return "", nil
+}
+
+// decodeCCITT はCCITTファクス符号化されたデータを1ビットのサンプル（1が白）に展開する
+func decodeCCITT(r *reader.Reader, data []byte, params core.Dictionary, width, height int) ([]byte, error) {
+	k := int(getNumber(resolve(r, params[core.Name("K")])))
+	if columns := resolve(r, params[core.Name("Columns")]); isNumber(columns) {
+		width = int(getNumber(columns))
+	}
+
+	var format ccitt.SubFormat
+	switch {
+	case k < 0:
+		format = ccitt.Group4
+	case k == 0:
+		format = ccitt.Group3
+	default:
+		return nil, fmt.Errorf("mixed 1D/2D CCITT encoding (K > 0) is not supported")
+	}
+	opts := &ccitt.Options{
+		Align:  resolve(r, params[core.Name("EncodedByteAlign")]) == core.Boolean(true),
+		Invert: resolve(r, params[core.Name("BlackIs1")]) == core.Boolean(true),
+	}
+
+	out, err := io.ReadAll(ccitt.NewReader(bytes.NewReader(data), ccitt.MSB, format, width, height, opts))
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to decode CCITT image: %w", err)
+	}
+	return out, nil
+}
+
+// decodeSamples は展開したサンプルを色空間と/Decodeに従ってRGBにする
+// /ImageMaskの画像は、塗る部分をfillの色、それ以外を透明にする
+func decodeSamples(r *reader.Reader, dict core.Dictionary, data []byte, width, height int, resources core.Dictionary, fill [3]float64) (*image.NRGBA, error) {
+	imageMask := resolve(r, dict[core.Name("ImageMask")]) == core.Boolean(true)
+
+	bpc := int(getNumber(resolve(r, dict[core.Name("BitsPerComponent")])))
+	cs := deviceGraySpace
+	if imageMask {
+		bpc = 1
+	} else {
+		cs = parseColorSpace(r, dict[core.Name("ColorSpace")], resources)
+	}
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		bpc = 8
+	}
+	n := cs.components
+	if n == 0 {
+		return nil, fmt.Errorf("unsupported image color space")
+	}
+
+	// /Decodeは成分ごとの[最小 最大]（省略時は[0 1]、Indexedは[0 2^bpc-1]）
+	maxValue := float64(int(1)<<bpc - 1)
+	decode := make([]float64, 2*n)
+	for i := 0; i < n; i++ {
+		decode[2*i], decode[2*i+1] = 0, 1
+		if cs.kind == colorSpaceIndexed {
+			decode[2*i+1] = maxValue
+		}
+	}
+	if arr, ok := resolve(r, dict[core.Name("Decode")]).(core.Array); ok && len(arr) >= 2*n {
+		for i := range decode {
+			decode[i] = getNumber(resolve(r, arr[i]))
+		}
+	}
+
+	rowBytes := (width*n*bpc + 7) / 8
+	if len(data) < rowBytes*height {
+		// 途中で切れたデータは、読めた行までを描く
+		height = len(data) / rowBytes
+		if height == 0 {
+			return nil, fmt.Errorf("image data is too short")
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	values := make([]float64, n)
+	for y := 0; y < height; y++ {
+		row := data[y*rowBytes : (y+1)*rowBytes]
+		for x := 0; x < width; x++ {
+			for i := 0; i < n; i++ {
+				sample := float64(readSample(row, x*n+i, bpc))
+				values[i] = decode[2*i] + sample*(decode[2*i+1]-decode[2*i])/maxValue
+			}
+
+			var c color.NRGBA
+			if imageMask {
+				// 変換後の値が0のサンプルを塗る
+				if values[0] < 0.5 {
+					c = color.NRGBA{R: toByte(fill[0]), G: toByte(fill[1]), B: toByte(fill[2]), A: 255}
+				}
+			} else if rgb, ok := cs.rgb(values); ok {
+				c = color.NRGBA{R: toByte(rgb[0]), G: toByte(rgb[1]), B: toByte(rgb[2]), A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img, nil
+}
+
+// readSample は行のindex番目のサンプル（bpcビット）を読む
+func readSample(row []byte, index, bpc int) int {
+	switch bpc {
+	case 8:
+		return int(row[index])
+	case 16:
+		return int(row[2*index])<<8 | int(row[2*index+1])
+	}
+	bit := index * bpc
+	shift := 8 - bpc - bit%8
+	return int(row[bit/8]>>shift) & (1<<bpc - 1)
+}
+
+// applySoftMask は/SMask（DeviceGrayの画像）を透明度として画像に適用する
+// 大きさの違うマスクは画像の大きさに合わせて拡大・縮小する
+func applySoftMask(r *reader.Reader, img *image.NRGBA, smask *core.Stream) {
+	mask, err := loadImage(r, smask, nil, [3]float64{})
+	if err != nil {
+		return
+	}
+
+	b := img.Bounds()
+	scaled := mask
+	if mask.Bounds().Size() != b.Size() {
+		scaled = image.NewNRGBA(b)
+		xdraw.ApproxBiLinear.Scale(scaled, b, mask, mask.Bounds(), xdraw.Src, nil)
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			// マスクはグレーなので、R成分を透明度として使う
+			alpha := scaled.Pix[scaled.PixOffset(x, y)]
+			img.Pix[i+3] = uint8(uint32(img.Pix[i+3]) * uint32(alpha) / 255)
+		}
+	}
+}
+
+// toByte は0〜1の値を0〜255にする
+func toByte(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 1:
+		return 255
+	}
+	return uint8(v*255 + 0.5)
+}
@@ -0,0 +1,400 @@
+package content
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/vector"
+)
+
+// pathOp はデバイス座標のパスの命令
+type pathOp int
+
+const (
+	pathMoveTo pathOp = iota // 新しいサブパスの開始（pts[0]）
+	pathLineTo               // 直線（pts[0]）
+	pathQuadTo               // 2次ベジェ曲線（pts[0]: 制御点、pts[1]: 終点）
+	pathCubeTo               // 3次ベジェ曲線（pts[0], pts[1]: 制御点、pts[2]: 終点）
+	pathClose                // サブパスを閉じる
+)
+
+// pathCmd はデバイス座標（画像のピクセル座標）のパスの構成要素
+type pathCmd struct {
+	op  pathOp
+	pts [3]Point
+}
+
+// devicePath はデバイス座標のパス
+type devicePath []pathCmd
+
+// transform はパスの座標を行列で変換したものを返す
+func (p devicePath) transform(m Matrix) devicePath {
+	result := make(devicePath, len(p))
+	for i, cmd := range p {
+		result[i].op = cmd.op
+		for j := range cmd.pts {
+			result[i].pts[j] = transformPoint(m, cmd.pts[j].X, cmd.pts[j].Y)
+		}
+	}
+	return result
+}
+
+// bounds はパスの制御点を含む範囲を、ピクセル単位に広げて返す
+func (p devicePath) bounds() image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, cmd := range p {
+		for i := 0; i < cmd.op.pointCount(); i++ {
+			pt := cmd.pts[i]
+			minX, minY = math.Min(minX, pt.X), math.Min(minY, pt.Y)
+			maxX, maxY = math.Max(maxX, pt.X), math.Max(maxY, pt.Y)
+		}
+	}
+	if minX > maxX {
+		return image.Rectangle{}
+	}
+	// 画像より極端に大きいパスでも整数があふれないようにする
+	clamp := func(v float64) int { return int(math.Max(-1<<24, math.Min(1<<24, v))) }
+	return image.Rect(clamp(math.Floor(minX)), clamp(math.Floor(minY)), clamp(math.Ceil(maxX))+1, clamp(math.Ceil(maxY))+1)
+}
+
+// subpaths はパスをサブパスごとに分ける
+func (p devicePath) subpaths() []devicePath {
+	var result []devicePath
+	for _, cmd := range p {
+		if cmd.op == pathMoveTo || len(result) == 0 {
+			result = append(result, nil)
+		}
+		result[len(result)-1] = append(result[len(result)-1], cmd)
+	}
+	return result
+}
+
+// pointCount は命令が使う点の数を返す
+func (op pathOp) pointCount() int {
+	switch op {
+	case pathMoveTo, pathLineTo:
+		return 1
+	case pathQuadTo:
+		return 2
+	case pathCubeTo:
+		return 3
+	}
+	return 0
+}
+
+// rasterizePath はパスを非ゼロ回転規則で塗りつぶしたときの被覆率を、範囲boundsのマスクとして返す
+// サブパスはすべて閉じたものとして扱う
+func rasterizePath(p devicePath, bounds image.Rectangle) *image.Alpha {
+	mask := image.NewAlpha(bounds)
+	if bounds.Empty() {
+		return mask
+	}
+
+	z := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+	ox, oy := float64(bounds.Min.X), float64(bounds.Min.Y)
+	pt := func(p Point) (float32, float32) { return float32(p.X - ox), float32(p.Y - oy) }
+
+	open := false
+	for _, cmd := range p {
+		switch cmd.op {
+		case pathMoveTo:
+			if open {
+				z.ClosePath()
+			}
+			z.MoveTo(pt(cmd.pts[0]))
+			open = true
+		case pathLineTo:
+			z.LineTo(pt(cmd.pts[0]))
+		case pathQuadTo:
+			bx, by := pt(cmd.pts[0])
+			cx, cy := pt(cmd.pts[1])
+			z.QuadTo(bx, by, cx, cy)
+		case pathCubeTo:
+			bx, by := pt(cmd.pts[0])
+			cx, cy := pt(cmd.pts[1])
+			dx, dy := pt(cmd.pts[2])
+			z.CubeTo(bx, by, cx, cy, dx, dy)
+		case pathClose:
+			z.ClosePath()
+		}
+	}
+	if open {
+		z.ClosePath()
+	}
+
+	z.Draw(mask, bounds, image.Opaque, image.Point{})
+	return mask
+}
+
+// coverage はパスを塗りつぶしたときの被覆率をboundsの範囲で返す
+// 奇偶規則は、サブパスごとの被覆率の排他的論理和（a + b - 2ab）で近似する
+// （自己交差のないサブパスが重なる場合は奇偶規則と一致する）
+func coverage(p devicePath, bounds image.Rectangle, evenOdd bool) *image.Alpha {
+	subpaths := p.subpaths()
+	if !evenOdd || len(subpaths) < 2 {
+		return rasterizePath(p, bounds)
+	}
+
+	mask := image.NewAlpha(bounds)
+	for _, sub := range subpaths {
+		m := rasterizePath(sub, bounds)
+		for i, b := range m.Pix {
+			a := uint32(mask.Pix[i])
+			mask.Pix[i] = uint8((a*255 + uint32(b)*255 - 2*a*uint32(b)) / 255)
+		}
+	}
+	return mask
+}
+
+// flattenCurveSegments は長さlengthの曲線を折れ線にするときの分割数を返す（1ピクセル程度の細かさ）
+func flattenCurveSegments(length float64) int {
+	return int(math.Max(1, math.Min(64, math.Ceil(length/2))))
+}
+
+// polyline は折れ線
+type polyline struct {
+	pts    []Point
+	closed bool
+}
+
+// flatten はパスを折れ線に変換する
+func (p devicePath) flatten() []polyline {
+	var lines []polyline
+	var current *polyline
+	var last Point
+
+	add := func(pt Point) {
+		if current == nil {
+			lines = append(lines, polyline{pts: []Point{last}})
+			current = &lines[len(lines)-1]
+		}
+		current.pts = append(current.pts, pt)
+		last = pt
+	}
+
+	for _, cmd := range p {
+		switch cmd.op {
+		case pathMoveTo:
+			current = nil
+			last = cmd.pts[0]
+			lines = append(lines, polyline{pts: []Point{last}})
+			current = &lines[len(lines)-1]
+		case pathLineTo:
+			add(cmd.pts[0])
+		case pathQuadTo:
+			p0, p1, p2 := last, cmd.pts[0], cmd.pts[1]
+			n := flattenCurveSegments(distance(p0, p1) + distance(p1, p2))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				add(Point{
+					X: u*u*p0.X + 2*u*t*p1.X + t*t*p2.X,
+					Y: u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y,
+				})
+			}
+		case pathCubeTo:
+			p0, p1, p2, p3 := last, cmd.pts[0], cmd.pts[1], cmd.pts[2]
+			n := flattenCurveSegments(distance(p0, p1) + distance(p1, p2) + distance(p2, p3))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				add(Point{
+					X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+					Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+				})
+			}
+		case pathClose:
+			if current != nil {
+				current.closed = true
+				last = current.pts[0]
+				current = nil
+			}
+		}
+	}
+	return lines
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// 線端の形（J）
+const (
+	lineCapButt   = 0
+	lineCapRound  = 1
+	lineCapSquare = 2
+)
+
+// strokeStyle は線を描くときの設定（デバイス座標の値）
+type strokeStyle struct {
+	width     float64
+	cap       int
+	dash      []float64
+	dashPhase float64
+}
+
+// strokeOutline は線を描いたときの領域を、塗りつぶすパスとして返す
+// 線分ごとの矩形と、頂点の円（丸い線の結合）を同じ向きで重ねるため、非ゼロ回転規則で塗りつぶす
+// 線の結合（j）とマイター限界（M）は扱わず、常に丸い結合にする
+func strokeOutline(p devicePath, style strokeStyle) devicePath {
+	half := style.width / 2
+	var outline devicePath
+
+	for _, line := range applyDash(p.flatten(), style.dash, style.dashPhase) {
+		pts := line.pts
+		if line.closed && len(pts) > 1 && pts[0] != pts[len(pts)-1] {
+			pts = append(pts[:len(pts):len(pts)], pts[0])
+		}
+		if len(pts) < 2 {
+			// mだけのサブパスは何も描かない
+			continue
+		}
+
+		if isDegenerate(pts) {
+			// 長さのない線は丸い線端のときだけ点を描く
+			if style.cap == lineCapRound {
+				outline = appendCircle(outline, pts[0], half)
+			}
+			continue
+		}
+
+		for i := 0; i+1 < len(pts); i++ {
+			a, b := pts[i], pts[i+1]
+			if a == b {
+				continue
+			}
+			if !line.closed && style.cap == lineCapSquare {
+				// 四角い線端は両端を線幅の半分だけ延ばす
+				dx, dy := (b.X-a.X)/distance(a, b)*half, (b.Y-a.Y)/distance(a, b)*half
+				if i == 0 {
+					a = Point{X: a.X - dx, Y: a.Y - dy}
+				}
+				if i+2 == len(pts) {
+					b = Point{X: b.X + dx, Y: b.Y + dy}
+				}
+			}
+			outline = appendSegmentRect(outline, a, b, half)
+		}
+
+		// 頂点（閉じたパスは始点も含む）と、丸い線端の両端に円を置く
+		for i, pt := range pts {
+			end := i == 0 || i == len(pts)-1
+			if !end || line.closed || style.cap == lineCapRound {
+				outline = appendCircle(outline, pt, half)
+			}
+		}
+	}
+	return outline
+}
+
+// isDegenerate は折れ線の点がすべて同じかを返す
+func isDegenerate(pts []Point) bool {
+	for _, pt := range pts[1:] {
+		if pt != pts[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// appendSegmentRect は線分a-bを中心とする幅2×halfの矩形を追加する
+func appendSegmentRect(p devicePath, a, b Point, half float64) devicePath {
+	length := distance(a, b)
+	nx, ny := -(b.Y-a.Y)/length*half, (b.X-a.X)/length*half
+	return append(p,
+		pathCmd{op: pathMoveTo, pts: [3]Point{{X: a.X + nx, Y: a.Y + ny}}},
+		pathCmd{op: pathLineTo, pts: [3]Point{{X: b.X + nx, Y: b.Y + ny}}},
+		pathCmd{op: pathLineTo, pts: [3]Point{{X: b.X - nx, Y: b.Y - ny}}},
+		pathCmd{op: pathLineTo, pts: [3]Point{{X: a.X - nx, Y: a.Y - ny}}},
+		pathCmd{op: pathClose},
+	)
+}
+
+// appendCircle は中心c、半径rの円（多角形）を、appendSegmentRectの矩形と同じ向きで追加する
+func appendCircle(p devicePath, c Point, r float64) devicePath {
+	n := int(math.Max(8, math.Min(64, math.Ceil(r*4))))
+	for i := 0; i < n; i++ {
+		angle := -2 * math.Pi * float64(i) / float64(n)
+		op := pathLineTo
+		if i == 0 {
+			op = pathMoveTo
+		}
+		p = append(p, pathCmd{op: op, pts: [3]Point{{X: c.X + r*math.Cos(angle), Y: c.Y + r*math.Sin(angle)}}})
+	}
+	return append(p, pathCmd{op: pathClose})
+}
+
+// applyDash は破線のパターン（d）で折れ線を分ける
+// パターンが空、またはすべて0の場合は実線のまま返す
+func applyDash(lines []polyline, dash []float64, phase float64) []polyline {
+	var total float64
+	for _, d := range dash {
+		if d < 0 {
+			return lines
+		}
+		total += d
+	}
+	if total == 0 {
+		return lines
+	}
+	// 奇数個のパターンは2回繰り返したものとして扱う
+	if len(dash)%2 == 1 {
+		dash = append(dash[:len(dash):len(dash)], dash...)
+		total *= 2
+	}
+
+	var result []polyline
+	for _, line := range lines {
+		pts := line.pts
+		if line.closed && len(pts) > 0 {
+			pts = append(pts[:len(pts):len(pts)], pts[0])
+		}
+
+		// 位相からパターンの位置を決める（各サブパスの始点でパターンをやり直す）
+		index, remaining := 0, dash[0]
+		offset := math.Mod(phase, total)
+		for offset > 0 {
+			if offset < remaining {
+				remaining -= offset
+				break
+			}
+			offset -= remaining
+			index = (index + 1) % len(dash)
+			remaining = dash[index]
+		}
+
+		var current []Point
+		on := index%2 == 0
+		if on && len(pts) > 0 {
+			current = []Point{pts[0]}
+		}
+		for i := 0; i+1 < len(pts); i++ {
+			a, b := pts[i], pts[i+1]
+			length := distance(a, b)
+			pos := 0.0
+			for length-pos > remaining {
+				pos += remaining
+				t := pos / length
+				pt := Point{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+				if on {
+					result = append(result, polyline{pts: append(current, pt)})
+					current = nil
+				} else {
+					current = []Point{pt}
+				}
+				on = !on
+				index = (index + 1) % len(dash)
+				remaining = dash[index]
+			}
+			remaining -= length - pos
+			if on {
+				current = append(current, b)
+			}
+		}
+		if on && len(current) > 1 {
+			result = append(result, polyline{pts: current})
+		}
+	}
+	return result
+}
@@ -0,0 +1,758 @@
+package content
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// maxFormDepth はフォームXObjectを入れ子で描画する深さの上限（循環参照への備え）
+const maxFormDepth = 16
+
+// Renderer はページのコンテンツを画像に描画する
+// 描画するもの: パス（塗りつぶし・線・クリップ）、テキスト、画像XObject、フォームXObject
+// 描画しないもの: シェーディング（sh）、パターン、インライン画像、注釈
+type Renderer struct {
+	reader *reader.Reader
+	dst    *image.RGBA
+	device Matrix // ページの座標系からdstのピクセル座標への変換
+
+	fonts map[int]*renderFont // フォント辞書のオブジェクト番号 -> 描画用のフォント
+}
+
+// NewRenderer は新しいRendererを作成する
+// deviceはページの座標系（デフォルトのユーザー空間）をdstのピクセル座標に写す行列
+func NewRenderer(r *reader.Reader, dst *image.RGBA, device Matrix) *Renderer {
+	return &Renderer{
+		reader: r,
+		dst:    dst,
+		device: device,
+		fonts:  make(map[int]*renderFont),
+	}
+}
+
+// renderScope はコンテンツストリームが参照するリソース（ページまたはフォームXObjectの/Resources）
+type renderScope struct {
+	resources   core.Dictionary
+	fontManager *FontManager
+	fonts       map[string]*renderFont // 間接参照でないフォント辞書の、リソース名 -> 描画用のフォント
+}
+
+func newRenderScope(r *reader.Reader, resources core.Dictionary) *renderScope {
+	scope := &renderScope{
+		resources: resources,
+		fonts:     make(map[string]*renderFont),
+	}
+	if r != nil {
+		scope.fontManager = NewFontManager(r)
+	}
+	return scope
+}
+
+// renderState は描画で使うグラフィックス状態（q/Qで保存・復元する）
+type renderState struct {
+	ctm  Matrix
+	clip *image.Alpha // クリップ領域の被覆率（nilはクリップなし）
+
+	fillSpace, strokeSpace renderColorSpace
+	fill, stroke           [3]float64 // RGB
+	fillPaint, strokePaint bool       // 色で塗れるか（Patternの場合はfalse）
+	fillAlpha, strokeAlpha float64    // ca, CA
+
+	lineWidth float64
+	lineCap   int
+	dash      []float64
+	dashPhase float64
+
+	// テキスト状態
+	font        *FontInfo
+	glyphFont   *renderFont
+	fontSize    float64
+	charSpacing float64 // Tc
+	wordSpacing float64 // Tw
+	leading     float64 // TL
+	scale       float64 // Tz（%）
+	rise        float64 // Ts
+	renderMode  int     // Tr
+}
+
+// initialState はページの描画を始めるときのグラフィックス状態を返す
+func (rd *Renderer) initialState() renderState {
+	return renderState{
+		ctm:         rd.device,
+		fillSpace:   deviceGraySpace,
+		strokeSpace: deviceGraySpace,
+		fillPaint:   true,
+		strokePaint: true,
+		fillAlpha:   1,
+		strokeAlpha: 1,
+		lineWidth:   1,
+		scale:       100,
+	}
+}
+
+// RenderPage はページのコンテンツストリームをdstに描画する
+func (rd *Renderer) RenderPage(page core.Dictionary) error {
+	data, err := rd.reader.GetPageContents(page)
+	if err != nil {
+		return fmt.Errorf("failed to get page contents: %w", err)
+	}
+	operations, err := NewStreamParser(data).ParseOperations()
+	if err != nil {
+		return fmt.Errorf("failed to parse page contents: %w", err)
+	}
+	resources, err := rd.reader.GetPageResources(page)
+	if err != nil {
+		return fmt.Errorf("failed to get page resources: %w", err)
+	}
+
+	rd.Render(operations, resources)
+	return nil
+}
+
+// Render はコンテンツストリームの操作をdstに描画する
+func (rd *Renderer) Render(operations []Operation, resources core.Dictionary) {
+	rd.run(operations, newRenderScope(rd.reader, resources), rd.initialState(), 0)
+}
+
+// run はコンテンツストリームの操作をstateの状態から描画する
+func (rd *Renderer) run(operations []Operation, scope *renderScope, state renderState, depth int) {
+	var stack []renderState
+
+	// パス（デバイス座標）
+	var path devicePath
+	var current, start Point
+	clipPending, clipEvenOdd := false, false
+
+	// テキスト
+	tm, tlm := Identity(), Identity()
+	var textClip devicePath
+	textClipUsed := false
+
+	pt := func(x, y float64) Point { return transformPoint(state.ctm, x, y) }
+
+	for _, op := range operations {
+		nums := numericOperands(op.Operands)
+
+		switch op.Operator {
+		case "q": // Save graphics state
+			stack = append(stack, state)
+
+		case "Q": // Restore graphics state
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+
+		case "cm": // Modify current transformation matrix
+			if len(nums) >= 6 {
+				state.ctm = Matrix{A: nums[0], B: nums[1], C: nums[2], D: nums[3], E: nums[4], F: nums[5]}.Multiply(state.ctm)
+			}
+
+		case "w": // Set line width
+			if len(nums) >= 1 {
+				state.lineWidth = nums[0]
+			}
+
+		case "J": // Set line cap
+			if len(nums) >= 1 {
+				state.lineCap = int(nums[0])
+			}
+
+		case "d": // Set dash pattern
+			if len(op.Operands) >= 2 {
+				state.dash, state.dashPhase = parseDash(rd.reader, op.Operands[0], op.Operands[1])
+			}
+
+		case "gs": // Set parameters from graphics state dictionary
+			if len(op.Operands) >= 1 {
+				rd.applyExtGState(op.Operands[0], scope, &state)
+			}
+
+		case "CS": // Set stroke color space
+			if len(op.Operands) >= 1 {
+				state.strokeSpace = parseColorSpace(rd.reader, op.Operands[0], scope.resources)
+				state.stroke = state.strokeSpace.initialColor()
+				state.strokePaint = state.strokeSpace.kind != colorSpacePattern
+			}
+
+		case "cs": // Set fill color space
+			if len(op.Operands) >= 1 {
+				state.fillSpace = parseColorSpace(rd.reader, op.Operands[0], scope.resources)
+				state.fill = state.fillSpace.initialColor()
+				state.fillPaint = state.fillSpace.kind != colorSpacePattern
+			}
+
+		case "SC", "SCN": // Set stroke color
+			state.stroke, state.strokePaint = setColor(state.strokeSpace, nums, state.stroke)
+
+		case "sc", "scn": // Set fill color
+			state.fill, state.fillPaint = setColor(state.fillSpace, nums, state.fill)
+
+		case "G", "RG", "K": // Set stroke color in a device color space
+			state.strokeSpace = deviceSpace(len(nums))
+			state.stroke, state.strokePaint = setColor(state.strokeSpace, nums, state.stroke)
+
+		case "g", "rg", "k": // Set fill color in a device color space
+			state.fillSpace = deviceSpace(len(nums))
+			state.fill, state.fillPaint = setColor(state.fillSpace, nums, state.fill)
+
+		case "m": // Begin new subpath
+			if len(nums) >= 2 {
+				current = pt(nums[0], nums[1])
+				start = current
+				path = append(path, pathCmd{op: pathMoveTo, pts: [3]Point{current}})
+			}
+
+		case "l": // Append straight line segment
+			if len(nums) >= 2 {
+				current = pt(nums[0], nums[1])
+				path = append(path, pathCmd{op: pathLineTo, pts: [3]Point{current}})
+			}
+
+		case "c": // Append cubic Bezier curve
+			if len(nums) >= 6 {
+				p1, p2, p3 := pt(nums[0], nums[1]), pt(nums[2], nums[3]), pt(nums[4], nums[5])
+				path = append(path, pathCmd{op: pathCubeTo, pts: [3]Point{p1, p2, p3}})
+				current = p3
+			}
+
+		case "v": // Append curve (initial point replicated)
+			if len(nums) >= 4 {
+				p2, p3 := pt(nums[0], nums[1]), pt(nums[2], nums[3])
+				path = append(path, pathCmd{op: pathCubeTo, pts: [3]Point{current, p2, p3}})
+				current = p3
+			}
+
+		case "y": // Append curve (final point replicated)
+			if len(nums) >= 4 {
+				p1, p3 := pt(nums[0], nums[1]), pt(nums[2], nums[3])
+				path = append(path, pathCmd{op: pathCubeTo, pts: [3]Point{p1, p3, p3}})
+				current = p3
+			}
+
+		case "h": // Close subpath
+			path = append(path, pathCmd{op: pathClose})
+			current = start
+
+		case "re": // Append rectangle
+			if len(nums) >= 4 {
+				x, y, w, h := nums[0], nums[1], nums[2], nums[3]
+				current = pt(x, y)
+				start = current
+				path = append(path,
+					pathCmd{op: pathMoveTo, pts: [3]Point{current}},
+					pathCmd{op: pathLineTo, pts: [3]Point{pt(x+w, y)}},
+					pathCmd{op: pathLineTo, pts: [3]Point{pt(x+w, y+h)}},
+					pathCmd{op: pathLineTo, pts: [3]Point{pt(x, y+h)}},
+					pathCmd{op: pathClose},
+				)
+			}
+
+		case "W", "W*": // Set clipping path (applied by the next painting operator)
+			clipPending, clipEvenOdd = true, op.Operator == "W*"
+
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n": // Paint path
+			if op.Operator == "s" || op.Operator == "b" || op.Operator == "b*" {
+				path = append(path, pathCmd{op: pathClose})
+			}
+			evenOdd := op.Operator == "f*" || op.Operator == "B*" || op.Operator == "b*"
+			switch op.Operator {
+			case "f", "F", "f*":
+				rd.fillPath(path, evenOdd, &state)
+			case "S", "s":
+				rd.strokePath(path, &state)
+			case "B", "B*", "b", "b*":
+				rd.fillPath(path, evenOdd, &state)
+				rd.strokePath(path, &state)
+			}
+			if clipPending {
+				state.clip = rd.intersectClip(state.clip, path, clipEvenOdd)
+			}
+			path, clipPending = nil, false
+
+		case "BT": // Begin text
+			tm, tlm = Identity(), Identity()
+			textClip, textClipUsed = nil, false
+
+		case "ET": // End text (applies the clipping path of modes 4-7)
+			if textClipUsed {
+				state.clip = rd.intersectClip(state.clip, textClip, false)
+			}
+			textClip, textClipUsed = nil, false
+
+		case "Tf": // Set font
+			if len(op.Operands) >= 2 {
+				name := getString(op.Operands[0])
+				state.fontSize = getNumber(op.Operands[1])
+				state.font, state.glyphFont = rd.loadFont(name, scope)
+			}
+
+		case "Td": // Move text position
+			if len(nums) >= 2 {
+				tlm = Matrix{A: 1, D: 1, E: nums[0], F: nums[1]}.Multiply(tlm)
+				tm = tlm
+			}
+
+		case "TD": // Move text position and set leading
+			if len(nums) >= 2 {
+				state.leading = -nums[1]
+				tlm = Matrix{A: 1, D: 1, E: nums[0], F: nums[1]}.Multiply(tlm)
+				tm = tlm
+			}
+
+		case "Tm": // Set text matrix
+			if len(nums) >= 6 {
+				tlm = Matrix{A: nums[0], B: nums[1], C: nums[2], D: nums[3], E: nums[4], F: nums[5]}
+				tm = tlm
+			}
+
+		case "T*": // Move to next line
+			tlm = Matrix{A: 1, D: 1, F: -state.leading}.Multiply(tlm)
+			tm = tlm
+
+		case "Tj": // Show text
+			if len(op.Operands) >= 1 {
+				if str, ok := op.Operands[0].(core.String); ok {
+					textClipUsed = rd.showText([]byte(str), &state, &tm, &textClip) || textClipUsed
+				}
+			}
+
+		case "TJ": // Show text with positioning
+			if len(op.Operands) >= 1 {
+				array, _ := op.Operands[0].(core.Array)
+				for _, item := range array {
+					switch v := item.(type) {
+					case core.String:
+						textClipUsed = rd.showText([]byte(v), &state, &tm, &textClip) || textClipUsed
+					case core.Integer, core.Real:
+						tx := -getNumber(v) / 1000 * state.fontSize * state.scale / 100
+						tm = Matrix{A: 1, D: 1, E: tx}.Multiply(tm)
+					}
+				}
+			}
+
+		case "'": // Move to next line and show text
+			tlm = Matrix{A: 1, D: 1, F: -state.leading}.Multiply(tlm)
+			tm = tlm
+			if len(op.Operands) >= 1 {
+				if str, ok := op.Operands[0].(core.String); ok {
+					textClipUsed = rd.showText([]byte(str), &state, &tm, &textClip) || textClipUsed
+				}
+			}
+
+		case "\"": // Set word/char spacing, move to next line, show text
+			if len(op.Operands) >= 3 {
+				state.wordSpacing = getNumber(op.Operands[0])
+				state.charSpacing = getNumber(op.Operands[1])
+				tlm = Matrix{A: 1, D: 1, F: -state.leading}.Multiply(tlm)
+				tm = tlm
+				if str, ok := op.Operands[2].(core.String); ok {
+					textClipUsed = rd.showText([]byte(str), &state, &tm, &textClip) || textClipUsed
+				}
+			}
+
+		case "Tc": // Set character spacing
+			if len(nums) >= 1 {
+				state.charSpacing = nums[0]
+			}
+
+		case "Tw": // Set word spacing
+			if len(nums) >= 1 {
+				state.wordSpacing = nums[0]
+			}
+
+		case "TL": // Set text leading
+			if len(nums) >= 1 {
+				state.leading = nums[0]
+			}
+
+		case "Tz": // Set horizontal scaling
+			if len(nums) >= 1 {
+				state.scale = nums[0]
+			}
+
+		case "Tr": // Set text rendering mode
+			if len(nums) >= 1 {
+				state.renderMode = int(nums[0])
+			}
+
+		case "Ts": // Set text rise
+			if len(nums) >= 1 {
+				state.rise = nums[0]
+			}
+
+		case "Do": // Paint XObject
+			if len(op.Operands) >= 1 {
+				if name, ok := op.Operands[0].(core.Name); ok {
+					rd.drawXObject(name, scope, state, depth)
+				}
+			}
+		}
+	}
+}
+
+// deviceSpace は成分の数に対応するデバイス色空間（G/g、RG/rg、K/k）を返す
+func deviceSpace(components int) renderColorSpace {
+	switch components {
+	case 3:
+		return renderColorSpace{kind: colorSpaceRGB, components: 3}
+	case 4:
+		return renderColorSpace{kind: colorSpaceCMYK, components: 4}
+	}
+	return deviceGraySpace
+}
+
+// setColor は色空間spaceの色を設定し、色と色で塗れるかを返す
+// 変換できない色（成分の数が合わないなど）は直前の色のままにする
+func setColor(space renderColorSpace, components []float64, previous [3]float64) ([3]float64, bool) {
+	if space.kind == colorSpacePattern {
+		return previous, false
+	}
+	if c, ok := space.rgb(components); ok {
+		return c, true
+	}
+	return previous, true
+}
+
+// parseDash は破線のパターン（配列と位相）を読む
+func parseDash(r *reader.Reader, array, phase core.Object) ([]float64, float64) {
+	arr, _ := resolve(r, array).(core.Array)
+	dash := make([]float64, 0, len(arr))
+	for _, v := range arr {
+		dash = append(dash, getNumber(resolve(r, v)))
+	}
+	return dash, getNumber(resolve(r, phase))
+}
+
+// applyExtGState はリソースの/ExtGStateのうち、線幅・線端・破線・透明度（LW, LC, D, CA, ca）を適用する
+func (rd *Renderer) applyExtGState(name core.Object, scope *renderScope, state *renderState) {
+	states, _ := resolve(rd.reader, scope.resources[core.Name("ExtGState")]).(core.Dictionary)
+	key, _ := name.(core.Name)
+	gs, ok := resolve(rd.reader, states[key]).(core.Dictionary)
+	if !ok {
+		return
+	}
+
+	for k, v := range gs {
+		v = resolve(rd.reader, v)
+		switch k {
+		case "LW":
+			state.lineWidth = getNumber(v)
+		case "LC":
+			state.lineCap = int(getNumber(v))
+		case "D":
+			if arr, ok := v.(core.Array); ok && len(arr) >= 2 {
+				state.dash, state.dashPhase = parseDash(rd.reader, arr[0], arr[1])
+			}
+		case "CA":
+			state.strokeAlpha = getNumber(v)
+		case "ca":
+			state.fillAlpha = getNumber(v)
+		}
+	}
+}
+
+// loadFont はリソース名nameのフォントの、文字コードの情報と描画用のフォントを読み込む
+func (rd *Renderer) loadFont(name string, scope *renderScope) (*FontInfo, *renderFont) {
+	var info *FontInfo
+	if scope.fontManager != nil && scope.resources != nil {
+		info, _ = scope.fontManager.GetFont(name, scope.resources)
+	}
+
+	fonts, _ := resolve(rd.reader, scope.resources[core.Name("Font")]).(core.Dictionary)
+	obj := fonts[core.Name(name)]
+	ref, isRef := obj.(*core.Reference)
+	switch {
+	case isRef:
+		if f, ok := rd.fonts[ref.ObjectNumber]; ok {
+			return info, f
+		}
+	default:
+		if f, ok := scope.fonts[name]; ok {
+			return info, f
+		}
+	}
+
+	fontDict, ok := resolve(rd.reader, obj).(core.Dictionary)
+	if !ok {
+		return info, nil
+	}
+	f := loadRenderFont(rd.reader, fontDict)
+	if isRef {
+		rd.fonts[ref.ObjectNumber] = f
+	} else {
+		scope.fonts[name] = f
+	}
+	return info, f
+}
+
+// showText は文字列を表示し、テキストマトリックスを表示した幅だけ進める
+// 文字の位置は Trm = [Tfs×Th 0 0 Tfs 0 Trise] × Tm × CTM で決め、幅はPDFの文字幅を使う
+// テキストレンダリングモードが4〜7（クリップに加える）の場合はtrueを返す
+func (rd *Renderer) showText(data []byte, state *renderState, tm *Matrix, textClip *devicePath) bool {
+	glyphs := state.font.glyphs(data)
+	texts := state.font.glyphTexts(data, len(glyphs))
+	codes := glyphCodes(state.font, data, len(glyphs))
+	th := state.scale / 100
+
+	var outline devicePath
+	for i, g := range glyphs {
+		if state.glyphFont != nil {
+			o := state.glyphFont.outline(codes[i], texts[i])
+			glyphPath := o.path
+			if o.advance > 0 && g.width > 0 {
+				// 代替フォントの文字は、PDFの文字幅に合わせて横に伸縮する
+				sx := math.Max(0.5, math.Min(1.5, g.width/1000/o.advance))
+				glyphPath = glyphPath.transform(Matrix{A: sx, D: 1})
+			}
+			trm := Matrix{A: state.fontSize * th, D: state.fontSize, F: state.rise}.Multiply(*tm).Multiply(state.ctm)
+			outline = append(outline, glyphPath.transform(trm)...)
+		}
+
+		w := g.width/1000*state.fontSize + state.charSpacing
+		if g.space {
+			w += state.wordSpacing
+		}
+		*tm = Matrix{A: 1, D: 1, E: w * th}.Multiply(*tm)
+	}
+
+	switch state.renderMode {
+	case 0, 4: // fill
+		rd.fillPath(outline, false, state)
+	case 1, 5: // stroke
+		rd.strokePath(outline, state)
+	case 2, 6: // fill and stroke
+		rd.fillPath(outline, false, state)
+		rd.strokePath(outline, state)
+	}
+	if state.renderMode >= 4 && state.renderMode <= 7 {
+		*textClip = append(*textClip, outline...)
+		return true
+	}
+	return false
+}
+
+// glyphCodes は文字列を表示される文字に分けたときの、それぞれの文字コードを返す
+// 文字コードの長さが分からない場合は-1にする
+func glyphCodes(f *FontInfo, data []byte, count int) []int {
+	codes := make([]int, count)
+	n := f.codeLength()
+	if n == 0 || len(data) != n*count {
+		for i := range codes {
+			codes[i] = -1
+		}
+		return codes
+	}
+
+	for i := range codes {
+		for _, b := range data[i*n : (i+1)*n] {
+			codes[i] = codes[i]<<8 | int(b)
+		}
+	}
+	return codes
+}
+
+// drawXObject はリソース名nameのXObject（画像またはフォーム）を描画する
+func (rd *Renderer) drawXObject(name core.Name, scope *renderScope, state renderState, depth int) {
+	if rd.reader == nil {
+		return
+	}
+	xobjects, _ := resolve(rd.reader, scope.resources[core.Name("XObject")]).(core.Dictionary)
+	stream, ok := resolve(rd.reader, xobjects[name]).(*core.Stream)
+	if !ok {
+		return
+	}
+
+	switch stream.Dict[core.Name("Subtype")] {
+	case core.Name("Image"):
+		rd.drawImage(stream, scope, state)
+	case core.Name("Form"):
+		rd.drawForm(stream, scope, state, depth)
+	}
+}
+
+// drawImage は画像を単位正方形（CTMで配置される）に描画する
+// 描画できない形式の画像は何も描かない
+func (rd *Renderer) drawImage(stream *core.Stream, scope *renderScope, state renderState) {
+	img, err := loadImage(rd.reader, stream, scope.resources, state.fill)
+	if err != nil {
+		return
+	}
+
+	ctm := state.ctm
+	if det := ctm.A*ctm.D - ctm.B*ctm.C; math.Abs(det) < 1e-9 {
+		return
+	}
+	minX, minY, maxX, maxY := ctm.TransformRect(0, 0, 1, 1)
+	bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Intersect(rd.dst.Bounds())
+	if bounds.Empty() {
+		return
+	}
+
+	// 画像の1行目が単位正方形の上端になる: 画像のピクセル座標 -> 単位正方形 -> デバイス座標
+	w, h := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	m := Matrix{A: 1 / w, D: -1 / h, F: 1}.Multiply(ctm)
+
+	// 拡大する画像は/Interpolateがなければ補間しない（ピクセルの境界をぼかさない）
+	var interpolator xdraw.Interpolator = xdraw.NearestNeighbor
+	enlarged := math.Hypot(m.A, m.B) >= 1 && math.Hypot(m.C, m.D) >= 1
+	if !enlarged || resolve(rd.reader, stream.Dict[core.Name("Interpolate")]) == core.Boolean(true) {
+		interpolator = xdraw.ApproxBiLinear
+	}
+
+	layer := image.NewRGBA(bounds)
+	interpolator.Transform(layer, f64.Aff3{m.A, m.C, m.E, m.B, m.D, m.F}, img, img.Bounds(), xdraw.Src, nil)
+	rd.compositeLayer(layer, state.fillAlpha, state.clip)
+}
+
+// drawForm はフォームXObjectを、/Matrixを適用し/BBoxでクリップして描画する
+// /Resourcesがなければ呼び出し元のリソースを使う
+func (rd *Renderer) drawForm(stream *core.Stream, scope *renderScope, state renderState, depth int) {
+	if depth >= maxFormDepth {
+		return
+	}
+	data, err := rd.reader.DecodeStream(stream)
+	if err != nil {
+		return
+	}
+	operations, err := NewStreamParser(data).ParseOperations()
+	if err != nil {
+		return
+	}
+
+	dict := stream.Dict
+	if m, ok := resolve(rd.reader, dict[core.Name("Matrix")]).(core.Array); ok && len(m) == 6 {
+		v := make([]float64, 6)
+		for i := range v {
+			v[i] = getNumber(resolve(rd.reader, m[i]))
+		}
+		state.ctm = Matrix{A: v[0], B: v[1], C: v[2], D: v[3], E: v[4], F: v[5]}.Multiply(state.ctm)
+	}
+	if bbox, ok := resolve(rd.reader, dict[core.Name("BBox")]).(core.Array); ok && len(bbox) == 4 {
+		x1, y1 := getNumber(resolve(rd.reader, bbox[0])), getNumber(resolve(rd.reader, bbox[1]))
+		x2, y2 := getNumber(resolve(rd.reader, bbox[2])), getNumber(resolve(rd.reader, bbox[3]))
+		box := devicePath{
+			{op: pathMoveTo, pts: [3]Point{{X: x1, Y: y1}}},
+			{op: pathLineTo, pts: [3]Point{{X: x2, Y: y1}}},
+			{op: pathLineTo, pts: [3]Point{{X: x2, Y: y2}}},
+			{op: pathLineTo, pts: [3]Point{{X: x1, Y: y2}}},
+			{op: pathClose},
+		}
+		state.clip = rd.intersectClip(state.clip, box.transform(state.ctm), false)
+	}
+
+	formScope := scope
+	if resources, ok := resolve(rd.reader, dict[core.Name("Resources")]).(core.Dictionary); ok {
+		formScope = newRenderScope(rd.reader, resources)
+	}
+	rd.run(operations, formScope, state, depth+1)
+}
+
+// fillPath はパスを塗りつぶし色で塗りつぶす
+func (rd *Renderer) fillPath(path devicePath, evenOdd bool, state *renderState) {
+	if !state.fillPaint || len(path) == 0 {
+		return
+	}
+	bounds := path.bounds().Intersect(rd.dst.Bounds())
+	if bounds.Empty() {
+		return
+	}
+	rd.composite(coverage(path, bounds, evenOdd), state.fill, state.fillAlpha, state.clip)
+}
+
+// strokePath はパスを線の色で描く
+// 線幅と破線はCTMの拡大率（行列式の平方根）で換算し、1ピクセルより細い線は1ピクセルで描く
+func (rd *Renderer) strokePath(path devicePath, state *renderState) {
+	if !state.strokePaint || len(path) == 0 {
+		return
+	}
+	scale := math.Sqrt(math.Abs(state.ctm.A*state.ctm.D - state.ctm.B*state.ctm.C))
+	dash := make([]float64, len(state.dash))
+	for i, d := range state.dash {
+		dash[i] = d * scale
+	}
+	outline := strokeOutline(path, strokeStyle{
+		width:     math.Max(state.lineWidth*scale, 1),
+		cap:       state.lineCap,
+		dash:      dash,
+		dashPhase: state.dashPhase * scale,
+	})
+
+	bounds := outline.bounds().Intersect(rd.dst.Bounds())
+	if bounds.Empty() {
+		return
+	}
+	rd.composite(rasterizePath(outline, bounds), state.stroke, state.strokeAlpha, state.clip)
+}
+
+// intersectClip はクリップ領域とパスの内側の共通部分を返す
+func (rd *Renderer) intersectClip(clip *image.Alpha, path devicePath, evenOdd bool) *image.Alpha {
+	result := image.NewAlpha(rd.dst.Bounds())
+	bounds := path.bounds().Intersect(rd.dst.Bounds())
+	if bounds.Empty() {
+		return result
+	}
+
+	mask := coverage(path, bounds, evenOdd)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			a := uint32(mask.Pix[mask.PixOffset(x, y)])
+			if clip != nil {
+				a = a * uint32(clip.Pix[clip.PixOffset(x, y)]) / 255
+			}
+			result.Pix[result.PixOffset(x, y)] = uint8(a)
+		}
+	}
+	return result
+}
+
+// composite はcの色を、被覆率mask×透明度alpha×クリップの割合でdstに重ねる
+func (rd *Renderer) composite(mask *image.Alpha, c [3]float64, alpha float64, clip *image.Alpha) {
+	src := [4]uint32{uint32(toByte(c[0])), uint32(toByte(c[1])), uint32(toByte(c[2])), 255}
+	opacity := uint32(toByte(alpha))
+
+	b := mask.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			rd.blend(x, y, src, uint32(mask.Pix[mask.PixOffset(x, y)])*opacity/255, clip)
+		}
+	}
+}
+
+// compositeLayer は描画した画像（アルファ乗算済み）を、透明度alpha×クリップの割合でdstに重ねる
+func (rd *Renderer) compositeLayer(layer *image.RGBA, alpha float64, clip *image.Alpha) {
+	opacity := uint32(toByte(alpha))
+
+	b := layer.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := layer.PixOffset(x, y)
+			src := [4]uint32{uint32(layer.Pix[i]), uint32(layer.Pix[i+1]), uint32(layer.Pix[i+2]), uint32(layer.Pix[i+3])}
+			rd.blend(x, y, src, opacity, clip)
+		}
+	}
+}
+
+// blend はdstの(x, y)にアルファ乗算済みの色srcを、割合coverage（0〜255）とクリップで重ねる
+func (rd *Renderer) blend(x, y int, src [4]uint32, coverage uint32, clip *image.Alpha) {
+	if clip != nil {
+		coverage = coverage * uint32(clip.Pix[clip.PixOffset(x, y)]) / 255
+	}
+	if coverage == 0 {
+		return
+	}
+
+	i := rd.dst.PixOffset(x, y)
+	sa := src[3] * coverage / 255
+	for k := 0; k < 4; k++ {
+		s := src[k] * coverage / 255
+		rd.dst.Pix[i+k] = uint8(s + uint32(rd.dst.Pix[i+k])*(255-sa)/255)
+	}
+}
@@ -0,0 +1,182 @@
+package content
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// renderOperations は100×100ポイントのページを1ポイント1ピクセルで白地に描画する
+func renderOperations(operations []Operation) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	// ページの座標系（y軸が上向き）を画像の座標系（y軸が下向き）にする
+	NewRenderer(nil, dst, Matrix{A: 1, D: -1, F: 100}).Render(operations, nil)
+	return dst
+}
+
+func TestRenderer_Render(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	tests := []struct {
+		name       string
+		operations []Operation
+		want       map[image.Point]color.RGBA // 画像の座標 -> 色
+	}{
+		{
+			name: "filled rect",
+			operations: []Operation{
+				{Operator: "rg", Operands: realOperands(1, 0, 0)},
+				{Operator: "re", Operands: realOperands(10, 10, 30, 20)},
+				{Operator: "f"},
+			},
+			want: map[image.Point]color.RGBA{
+				{X: 20, Y: 80}: red, // ページの(20, 20)
+				{X: 5, Y: 80}:  white,
+				{X: 20, Y: 60}: white, // ページの(20, 40)
+			},
+		},
+		{
+			name: "cm and cmyk",
+			operations: []Operation{
+				{Operator: "q"},
+				{Operator: "cm", Operands: realOperands(2, 0, 0, 2, 50, 50)},
+				{Operator: "k", Operands: realOperands(1, 1, 0, 0)},
+				{Operator: "re", Operands: realOperands(0, 0, 10, 10)},
+				{Operator: "f"},
+				{Operator: "Q"},
+				{Operator: "re", Operands: realOperands(0, 0, 10, 10)},
+				{Operator: "f"},
+			},
+			want: map[image.Point]color.RGBA{
+				{X: 65, Y: 35}: blue,           // ページの(65, 65)
+				{X: 45, Y: 35}: white,          // 拡大前の範囲の外
+				{X: 5, Y: 95}:  {0, 0, 0, 255}, // Qで色は黒に戻る
+				{X: 75, Y: 25}: white,          // ページの(75, 75)は拡大した範囲の外
+			},
+		},
+		{
+			name: "stroked line",
+			operations: []Operation{
+				{Operator: "RG", Operands: realOperands(0, 0, 1)},
+				{Operator: "w", Operands: realOperands(4)},
+				{Operator: "m", Operands: realOperands(10, 50)},
+				{Operator: "l", Operands: realOperands(90, 50)},
+				{Operator: "S"},
+			},
+			want: map[image.Point]color.RGBA{
+				{X: 50, Y: 50}: blue,
+				{X: 50, Y: 48}: blue,
+				{X: 50, Y: 45}: white,
+				{X: 5, Y: 50}:  white, // 線端はbutt
+			},
+		},
+		{
+			name: "clip",
+			operations: []Operation{
+				{Operator: "re", Operands: realOperands(0, 0, 50, 100)},
+				{Operator: "W"},
+				{Operator: "n"},
+				{Operator: "rg", Operands: realOperands(1, 0, 0)},
+				{Operator: "re", Operands: realOperands(0, 0, 100, 100)},
+				{Operator: "f"},
+			},
+			want: map[image.Point]color.RGBA{
+				{X: 25, Y: 50}: red,
+				{X: 75, Y: 50}: white,
+			},
+		},
+		{
+			name: "even-odd fill",
+			operations: []Operation{
+				{Operator: "rg", Operands: realOperands(1, 0, 0)},
+				{Operator: "re", Operands: realOperands(10, 10, 80, 80)},
+				{Operator: "re", Operands: realOperands(30, 30, 40, 40)},
+				{Operator: "f*"},
+			},
+			want: map[image.Point]color.RGBA{
+				{X: 20, Y: 50}: red,
+				{X: 50, Y: 50}: white,
+			},
+		},
+		{
+			name: "pattern fill is not painted",
+			operations: []Operation{
+				{Operator: "cs", Operands: []core.Object{core.Name("Pattern")}},
+				{Operator: "scn", Operands: []core.Object{core.Name("P0")}},
+				{Operator: "re", Operands: realOperands(0, 0, 100, 100)},
+				{Operator: "f"},
+			},
+			want: map[image.Point]color.RGBA{
+				{X: 50, Y: 50}: white,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := renderOperations(tt.operations)
+			for p, want := range tt.want {
+				if got := img.RGBAAt(p.X, p.Y); got != want {
+					t.Errorf("pixel %v = %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderer_FillAlpha(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+
+	resources := core.Dictionary{
+		core.Name("ExtGState"): core.Dictionary{
+			core.Name("GS0"): core.Dictionary{core.Name("ca"): core.Real(0.5)},
+		},
+	}
+	NewRenderer(nil, dst, Matrix{A: 1, D: -1, F: 10}).Render([]Operation{
+		{Operator: "gs", Operands: []core.Object{core.Name("GS0")}},
+		{Operator: "g", Operands: realOperands(0)},
+		{Operator: "re", Operands: realOperands(0, 0, 10, 10)},
+		{Operator: "f"},
+	}, resources)
+
+	got := dst.RGBAAt(5, 5)
+	if got.R < 120 || got.R > 135 || got.A != 255 {
+		t.Errorf("pixel = %v, want half gray", got)
+	}
+}
+
+func TestNormalizeSFNT(t *testing.T) {
+	// cmapのない、タグ順に並んでいない2つのテーブル
+	font := []byte{
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0, 0, 0, 0, 0, 0,
+		'l', 'o', 'c', 'a', 0, 0, 0, 0, 0, 0, 0, 44, 0, 0, 0, 2,
+		'h', 'e', 'a', 'd', 0, 0, 0, 0, 0, 0, 0, 48, 0, 0, 0, 4,
+		1, 2, 0, 0,
+		3, 4, 5, 6,
+	}
+
+	got := normalizeSFNT(font)
+	if n := int(got[4])<<8 | int(got[5]); n != 3 {
+		t.Fatalf("numTables = %d, want 3", n)
+	}
+	var tags []string
+	for i := 0; i < 3; i++ {
+		tags = append(tags, string(got[12+16*i:12+16*i+4]))
+	}
+	if want := []string{"cmap", "head", "loca"}; tags[0] != want[0] || tags[1] != want[1] || tags[2] != want[2] {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+	// headの内容が引き継がれる
+	entry := got[12+16:]
+	offset := int(entry[8])<<24 | int(entry[9])<<16 | int(entry[10])<<8 | int(entry[11])
+	if string(got[offset:offset+4]) != string([]byte{3, 4, 5, 6}) {
+		t.Errorf("head data = %v", got[offset:offset+4])
+	}
+}
@@ -12,6 +12,9 @@ import (
 type Operation struct {
 	Operator string        // オペレーター名（例: "Tj", "Td"）
 	Operands []core.Object // オペランド
+
+	// コンテンツストリームの中で、最初のオペランドからオペレーターの末尾までの範囲（バイト位置）
+	Start, End int
 }
 
 // StreamParser はコンテンツストリームをパースする
@@ -30,6 +33,7 @@ func NewStreamParser(data []byte) *StreamParser {
 func (p *StreamParser) ParseOperations() ([]Operation, error) {
 	var operations []Operation
 	var operands []core.Object
+	var start int
 
 	for {
 		token, err := p.lexer.NextToken()
@@ -45,11 +49,17 @@ func (p *StreamParser) ParseOperations() ([]Operation, error) {
 			break
 		}
 
+		if len(operands) == 0 {
+			start = int(p.lexer.TokenStart())
+		}
+
 		// キーワード（オペレーター）の場合
 		if token.Type == reader.TokenKeyword {
 			op := Operation{
 				Operator: token.Value.(string),
 				Operands: operands,
+				Start:    start,
+				End:      int(p.lexer.Offset()),
 			}
 			operations = append(operations, op)
 			operands = nil
@@ -227,3 +227,62 @@ func TestStreamParser_EmptyStream(t *testing.T) {
 		t.Errorf("Expected 0 operations, got %d", len(operations))
 	}
 }
+
+// TestStreamParser_OperationRange はオペレーションのコンテンツストリームの中での範囲をテストする
+func TestStreamParser_OperationRange(t *testing.T) {
+	stream := "BT\n/F1 12 Tf % comment\n[(a\\)b) -20 <4142>] TJ\n  (x) Tj ET"
+
+	operations, err := NewStreamParser([]byte(stream)).ParseOperations()
+	if err != nil {
+		t.Fatalf("ParseOperations failed: %v", err)
+	}
+
+	want := []string{"BT", "/F1 12 Tf", "[(a\\)b) -20 <4142>] TJ", "(x) Tj", "ET"}
+	if len(operations) != len(want) {
+		t.Fatalf("Expected %d operations, got %d", len(want), len(operations))
+	}
+	for i, op := range operations {
+		if got := stream[op.Start:op.End]; got != want[i] {
+			t.Errorf("operation %d (%s) range = %q, want %q", i, op.Operator, got, want[i])
+		}
+	}
+}
+
+// TestRewriteOperations はオペレーションの書き換えと削除をテストする
+func TestRewriteOperations(t *testing.T) {
+	stream := "q 10 0 0 10 0 0 cm /Im1 Do Q % comment\nq /Im2 Do Q"
+
+	tests := []struct {
+		name         string
+		replacements map[int][]byte
+		want         string
+		wantErr      bool
+	}{
+		{name: "no replacements", replacements: nil, want: stream},
+		{name: "remove", replacements: map[int][]byte{2: nil, 5: {}}, want: "q 10 0 0 10 0 0 cm  Q % comment\nq  Q"},
+		{name: "replace", replacements: map[int][]byte{2: []byte("/Im3 Do")}, want: "q 10 0 0 10 0 0 cm /Im3 Do Q % comment\nq /Im2 Do Q"},
+		{name: "out of range", replacements: map[int][]byte{7: nil}, wantErr: true},
+	}
+
+	operations, err := NewStreamParser([]byte(stream)).ParseOperations()
+	if err != nil {
+		t.Fatalf("ParseOperations failed: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteOperations([]byte(stream), operations, tt.replacements)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("RewriteOperations should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RewriteOperations failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("RewriteOperations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
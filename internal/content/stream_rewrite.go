@@ -0,0 +1,35 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// RewriteOperations はコンテンツストリームdataの一部のオペレーションを書き換えたストリームを返す
+// operationsはdataをParseOperationsした結果、replacementsはオペレーションの位置 -> 代わりに書くバイト列
+// 空のバイト列に置き換えたオペレーションは削除される。それ以外の部分（コメントや空白を含む）は元のまま残す
+func RewriteOperations(data []byte, operations []Operation, replacements map[int][]byte) ([]byte, error) {
+	opIndexes := make([]int, 0, len(replacements))
+	for opIndex := range replacements {
+		if opIndex < 0 || opIndex >= len(operations) {
+			return nil, fmt.Errorf("operation %d out of range", opIndex)
+		}
+		opIndexes = append(opIndexes, opIndex)
+	}
+	sort.Ints(opIndexes)
+
+	var out bytes.Buffer
+	last := 0
+	for _, opIndex := range opIndexes {
+		op := operations[opIndex]
+		if op.Start < last || op.End > len(data) || op.Start > op.End {
+			return nil, fmt.Errorf("invalid range of operation %d", opIndex)
+		}
+		out.Write(data[last:op.Start])
+		out.Write(replacements[opIndex])
+		last = op.End
+	}
+	out.Write(data[last:])
+	return out.Bytes(), nil
+}
@@ -0,0 +1,171 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// TextEdit はテキスト要素の文字の範囲の置き換え
+type TextEdit struct {
+	Element    int    // 置き換えるテキスト要素（Extractの結果の位置）
+	Start, End int    // 置き換える文字の範囲（Glyphsの位置、Endは含まない）
+	Text       string // 置き換え後のテキスト（空の場合は文字を削除する）
+}
+
+// ReplaceText はコンテンツストリームdataのテキストを置き換えたストリームを返す
+// operationsはdataをParseOperationsした結果、elementsはそれをExtractした結果
+// 置き換え後のテキストは要素のフォントで符号化し、変更した文字列を表示するオペレーション（Tj、TJ、'、"）だけを書き直す
+// 文字の位置の調整（TJの数値など）は変えないため、文字数が変わると後ろの文字との間隔が変わる
+func ReplaceText(data []byte, operations []Operation, elements []TextElement, edits []TextEdit) ([]byte, error) {
+	// オペレーションごとに、文字列のオペランド -> 書き直した文字コードの列
+	rewritten := make(map[int]map[int][]byte)
+	for _, edit := range edits {
+		if edit.Element < 0 || edit.Element >= len(elements) {
+			return nil, fmt.Errorf("text element %d out of range", edit.Element)
+		}
+		elem := elements[edit.Element]
+		if edit.Start < 0 || edit.End > len(elem.Glyphs) || edit.Start >= edit.End {
+			return nil, fmt.Errorf("invalid glyph range %d-%d of text element %d", edit.Start, edit.End, edit.Element)
+		}
+		if op := elem.Source.Operation; op < 0 || op >= len(operations) {
+			return nil, fmt.Errorf("operation %d out of range", op)
+		}
+		if rewritten[elem.Source.Operation] == nil {
+			rewritten[elem.Source.Operation] = make(map[int][]byte)
+		}
+	}
+
+	for opIndex, items := range rewritten {
+		// 同じオペレーションで表示した要素の文字を、文字列のオペランドごとに組み立て直す
+		for i, elem := range elements {
+			if elem.Source.Operation != opIndex {
+				continue
+			}
+			if err := rewriteElement(items, i, elem, edits); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	replacements := make(map[int][]byte, len(rewritten))
+	for opIndex, items := range rewritten {
+		operands, err := replaceStrings(operations[opIndex], items)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := writeOperation(&buf, operations[opIndex].Operator, operands); err != nil {
+			return nil, err
+		}
+		replacements[opIndex] = buf.Bytes()
+	}
+	return RewriteOperations(data, operations, replacements)
+}
+
+// rewriteElement はテキスト要素elemの文字のうち、editsで置き換える文字を含む文字列のオペランドを組み立て直してitemsに加える
+// 文字コードの長さが決まっていないフォントでは、文字列の一部だけを置き換えることはできない
+func rewriteElement(items map[int][]byte, index int, elem TextElement, edits []TextEdit) error {
+	source := elem.Source
+	replaced := make([]bool, len(elem.Glyphs))
+	inserted := make(map[int][]byte)
+	touched := make(map[int]bool)
+	for _, edit := range edits {
+		if edit.Element != index {
+			continue
+		}
+		code, err := source.Font.Encode(edit.Text)
+		if err != nil {
+			return fmt.Errorf("failed to encode %q: %w", edit.Text, err)
+		}
+		inserted[edit.Start] = append(inserted[edit.Start], code...)
+		for g := edit.Start; g < edit.End; g++ {
+			replaced[g] = true
+			touched[source.Strings[g]] = true
+		}
+	}
+
+	for g := range elem.Glyphs {
+		item := source.Strings[g]
+		if !touched[item] {
+			continue
+		}
+		if _, ok := items[item]; !ok {
+			items[item] = []byte{}
+		}
+		items[item] = append(items[item], inserted[g]...)
+		if replaced[g] {
+			continue
+		}
+		if source.Codes[g] == nil {
+			return fmt.Errorf("cannot replace part of a string shown with font %s", fontName(source.Font))
+		}
+		items[item] = append(items[item], source.Codes[g]...)
+	}
+	return nil
+}
+
+// replaceStrings はオペレーションの文字列のオペランド（TJは配列の要素）をitemsで置き換えたオペランドを返す
+func replaceStrings(op Operation, items map[int][]byte) ([]core.Object, error) {
+	operands := append([]core.Object(nil), op.Operands...)
+	switch op.Operator {
+	case "Tj", "'":
+		if len(operands) < 1 {
+			return nil, fmt.Errorf("%s has no operands", op.Operator)
+		}
+		if code, ok := items[0]; ok {
+			operands[0] = core.String(code)
+		}
+	case "\"":
+		if len(operands) < 3 {
+			return nil, fmt.Errorf("%s has too few operands", op.Operator)
+		}
+		if code, ok := items[0]; ok {
+			operands[2] = core.String(code)
+		}
+	case "TJ":
+		if len(operands) < 1 {
+			return nil, fmt.Errorf("%s has no operands", op.Operator)
+		}
+		array, ok := operands[0].(core.Array)
+		if !ok {
+			return nil, fmt.Errorf("TJ operand is not an array")
+		}
+		array = append(core.Array(nil), array...)
+		for i, code := range items {
+			if i < len(array) {
+				array[i] = core.String(code)
+			}
+		}
+		operands[0] = array
+	default:
+		return nil, fmt.Errorf("operator %s does not show text", op.Operator)
+	}
+	return operands, nil
+}
+
+// writeOperation はオペランドとオペレーターをコンテンツストリームの形式で書く
+func writeOperation(out *bytes.Buffer, operator string, operands []core.Object) error {
+	s := writer.NewSerializer(out)
+	for _, operand := range operands {
+		if operand == nil {
+			operand = core.Null{}
+		}
+		if err := s.Serialize(operand); err != nil {
+			return err
+		}
+		out.WriteByte(' ')
+	}
+	out.WriteString(operator)
+	return nil
+}
+
+// fontName はエラーメッセージに使うフォント名を返す
+func fontName(f *FontInfo) string {
+	if f == nil {
+		return "(unknown)"
+	}
+	return f.Name
+}
@@ -0,0 +1,185 @@
+package content
+
+import (
+	"testing"
+)
+
+// TestReplaceText はコンテンツストリームの文字列の置き換えをテストする
+func TestReplaceText(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		edits  []TextEdit
+		want   string
+	}{
+		{
+			name:   "whole Tj string",
+			stream: "BT /F1 12 Tf 10 20 Td (Hello) Tj ET",
+			edits:  []TextEdit{{Element: 0, Start: 0, End: 5, Text: "World"}},
+			want:   "BT /F1 12 Tf 10 20 Td (World) Tj ET",
+		},
+		{
+			name:   "part of a Tj string",
+			stream: "BT /F1 12 Tf (Hello) Tj ET",
+			edits:  []TextEdit{{Element: 0, Start: 1, End: 3, Text: "ipp"}},
+			want:   "BT /F1 12 Tf (Hipplo) Tj ET",
+		},
+		{
+			name:   "delete characters",
+			stream: "BT /F1 12 Tf (Hello) Tj ET",
+			edits:  []TextEdit{{Element: 0, Start: 0, End: 5}},
+			want:   "BT /F1 12 Tf () Tj ET",
+		},
+		{
+			name:   "across strings of TJ",
+			stream: "BT /F1 12 Tf [(Hel) -20 (lo)] TJ ET",
+			edits:  []TextEdit{{Element: 0, Start: 2, End: 4, Text: "LL"}},
+			want:   "BT /F1 12 Tf [(HeLL) -20 (o)] TJ ET",
+		},
+		{
+			name:   "second element of TJ",
+			stream: "BT /F1 12 Tf [(A) -300 (B)] TJ ET",
+			edits:  []TextEdit{{Element: 1, Start: 0, End: 1, Text: "C"}},
+			want:   "BT /F1 12 Tf [(A) -300 (C)] TJ ET",
+		},
+		{
+			name:   "quote operators",
+			stream: "BT /F1 12 Tf 14 TL (one) ' 1 0.5 (two) \" ET",
+			edits: []TextEdit{
+				{Element: 0, Start: 0, End: 3, Text: "1"},
+				{Element: 1, Start: 0, End: 3, Text: "2"},
+			},
+			want: "BT /F1 12 Tf 14 TL (1) ' 1 0.5 (2) \" ET",
+		},
+		{
+			name:   "other operations are kept as written",
+			stream: "q 1 0 0 1 0 0 cm % comment\nBT /F1 12 Tf (a(b)c) Tj\n(d) Tj ET Q",
+			edits:  []TextEdit{{Element: 1, Start: 0, End: 1, Text: "e)"}},
+			want:   "q 1 0 0 1 0 0 cm % comment\nBT /F1 12 Tf (a(b)c) Tj\n<6529> Tj ET Q",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(tt.stream)
+			operations, err := NewStreamParser(data).ParseOperations()
+			if err != nil {
+				t.Fatalf("ParseOperations failed: %v", err)
+			}
+			elements, err := NewTextExtractor(operations, nil, nil).Extract()
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			got, err := ReplaceText(data, operations, elements, tt.edits)
+			if err != nil {
+				t.Fatalf("ReplaceText failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReplaceText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReplaceText_Errors は置き換えられない編集をテストする
+func TestReplaceText_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		font   *FontInfo
+		edit   TextEdit
+	}{
+		{
+			name:   "element out of range",
+			stream: "BT (Hello) Tj ET",
+			edit:   TextEdit{Element: 1, Start: 0, End: 1},
+		},
+		{
+			name:   "empty glyph range",
+			stream: "BT (Hello) Tj ET",
+			edit:   TextEdit{Element: 0, Start: 2, End: 2},
+		},
+		{
+			name:   "character not in the font",
+			stream: "BT (Hello) Tj ET",
+			edit:   TextEdit{Element: 0, Start: 0, End: 1, Text: "あ"},
+		},
+		{
+			name:   "part of a string with variable-length codes",
+			stream: "BT /F1 12 Tf <30423044> Tj ET",
+			font: &FontInfo{
+				Name:   "F1",
+				CMap:   lookupPredefinedCMap("UniJIS-UTF8-H"),
+				Widths: &FontWidths{composite: true, declared: true, defaultWidth: 1000},
+			},
+			edit: TextEdit{Element: 0, Start: 0, End: 1, Text: "A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(tt.stream)
+			operations, err := NewStreamParser(data).ParseOperations()
+			if err != nil {
+				t.Fatalf("ParseOperations failed: %v", err)
+			}
+			extractor := NewTextExtractor(operations, nil, nil)
+			extractor.currentFontInfo = tt.font
+			elements, err := extractor.Extract()
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if _, err := ReplaceText(data, operations, elements, []TextEdit{tt.edit}); err == nil {
+				t.Error("ReplaceText should fail")
+			}
+		})
+	}
+}
+
+// TestFontInfo_Encode はテキストからフォントの文字コードへの変換をテストする
+func TestFontInfo_Encode(t *testing.T) {
+	identity := &FontWidths{composite: true, identity: true, declared: true, defaultWidth: 1000}
+	toUnicode := &ToUnicodeCMap{
+		charMap: map[uint16]rune{0x0102: 'あ', 0x0005: 'あ'},
+		ranges:  []CIDRange{{StartCID: 0x0010, EndCID: 0x0019, StartChar: '0'}},
+	}
+	winAnsi := baseEncoding("WinAnsiEncoding")
+
+	tests := []struct {
+		name    string
+		font    *FontInfo
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "no font", text: "Abc é", want: "Abc \xe9"},
+		{name: "no font, not Latin-1", text: "あ", wantErr: true},
+		{name: "ToUnicode, two-byte codes", font: &FontInfo{ToUnicodeCMap: toUnicode, Widths: identity}, text: "あ09", want: "\x00\x05\x00\x10\x00\x19"},
+		{name: "ToUnicode, missing character", font: &FontInfo{ToUnicodeCMap: toUnicode, Widths: identity}, text: "A", wantErr: true},
+		{name: "ToUnicode, one-byte codes", font: &FontInfo{ToUnicodeCMap: toUnicode}, text: "5", want: "\x15"},
+		{name: "simple encoding", font: &FontInfo{Encoding: winAnsi}, text: "€A", want: "\x80A"},
+		{name: "simple encoding, missing character", font: &FontInfo{Encoding: winAnsi}, text: "あ", wantErr: true},
+		{name: "predefined CMap", font: &FontInfo{CMap: lookupPredefinedCMap("90ms-RKSJ-H")}, text: "日本A", want: "\x93\xfa\x96\x7bA"},
+		{name: "identity without ToUnicode", font: &FontInfo{Widths: identity}, text: "A", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.font.Encode(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Encode(%q) = %q, want error", tt.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode(%q) failed: %v", tt.text, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Encode(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
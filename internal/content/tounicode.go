@@ -74,6 +74,32 @@ func (cm *ToUnicodeCMap) Lookup(cid uint16) (rune, bool) {
 	return 0, false
 }
 
+// Code はUnicodeの文字に対応するCIDを返す（Lookupの逆）
+// 同じ文字に複数のCIDが対応する場合は、最も小さいCIDを返す
+func (cm *ToUnicodeCMap) Code(r rune) (uint16, bool) {
+	if cm == nil {
+		return 0, false
+	}
+
+	var code uint16
+	found := false
+	for cid, mapped := range cm.charMap {
+		if mapped == r && (!found || cid < code) {
+			code, found = cid, true
+		}
+	}
+	for _, rang := range cm.ranges {
+		if r < rang.StartChar || r > rang.StartChar+rune(rang.EndCID-rang.StartCID) {
+			continue
+		}
+		cid := rang.StartCID + uint16(r-rang.StartChar)
+		if !found || cid < code {
+			code, found = cid, true
+		}
+	}
+	return code, found
+}
+
 // LookupString はCIDバイト列をUnicode文字列に変換
 func (cm *ToUnicodeCMap) LookupString(data []byte) string {
 	if cm == nil || len(data) == 0 {
@@ -0,0 +1,193 @@
+// Package icc builds the ICC color profiles embedded in PDF output intents.
+package icc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// D50 white point (the ICC profile connection space illuminant)
+var d50 = [3]float64{0.9642, 1.0, 0.8249}
+
+// sRGB primaries chromatically adapted to D50 (IEC 61966-2-1, Bradford)
+var srgbPrimaries = [3][3]float64{
+	{0.4361, 0.2225, 0.0139}, // red
+	{0.3851, 0.7169, 0.0971}, // green
+	{0.1431, 0.0606, 0.7141}, // blue
+}
+
+// SRGBDescription is the profile description and output condition identifier of SRGB
+const SRGBDescription = "sRGB IEC61966-2.1"
+
+// SRGB returns an ICC version 2 display profile for the sRGB color space.
+// The tone curves sample the sRGB transfer function at 1024 points.
+func SRGB() []byte {
+	trc := srgbCurve(1024)
+
+	tags := []tag{
+		{"desc", textDescription(SRGBDescription)},
+		{"cprt", text("No copyright, use freely")},
+		{"wtpt", xyz(d50)},
+		{"rXYZ", xyz(srgbPrimaries[0])},
+		{"gXYZ", xyz(srgbPrimaries[1])},
+		{"bXYZ", xyz(srgbPrimaries[2])},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+	return buildProfile("mntr", "RGB ", tags)
+}
+
+// tag is a tagged element of a profile
+type tag struct {
+	signature string
+	data      []byte
+}
+
+// buildProfile assembles the header, tag table and tag data.
+// Tags with identical data share a single copy.
+func buildProfile(class, colorSpace string, tags []tag) []byte {
+	const headerSize = 128
+	tableSize := 4 + 12*len(tags)
+
+	var data bytes.Buffer
+	table := make([]byte, 0, tableSize)
+	table = binary.BigEndian.AppendUint32(table, uint32(len(tags)))
+	offsets := map[string]int{}
+	for _, t := range tags {
+		offset, ok := offsets[string(t.data)]
+		if !ok {
+			offset = headerSize + tableSize + data.Len()
+			offsets[string(t.data)] = offset
+			data.Write(t.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+		table = append(table, t.signature...)
+		table = binary.BigEndian.AppendUint32(table, uint32(offset))
+		table = binary.BigEndian.AppendUint32(table, uint32(len(t.data)))
+	}
+
+	size := headerSize + tableSize + data.Len()
+	header := make([]byte, headerSize)
+	binary.BigEndian.PutUint32(header[0:], uint32(size))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // version 2.1
+	copy(header[12:], class)
+	copy(header[16:], colorSpace)
+	copy(header[20:], "XYZ ")
+	// 作成日時は出力を再現可能にするため固定値とする
+	for i, v := range []uint16{2024, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(header[24+2*i:], v)
+	}
+	copy(header[36:], "acsp")
+	binary.BigEndian.PutUint32(header[64:], 0) // perceptual rendering intent
+	copy(header[68:], xyzNumbers(d50))
+
+	profile := make([]byte, 0, size)
+	profile = append(profile, header...)
+	profile = append(profile, table...)
+	profile = append(profile, data.Bytes()...)
+	return profile
+}
+
+// s15Fixed16 encodes a signed 15.16 fixed-point number
+func s15Fixed16(v float64) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(int32(math.Round(v*65536))))
+}
+
+func xyzNumbers(v [3]float64) []byte {
+	out := make([]byte, 0, 12)
+	for _, c := range v {
+		out = append(out, s15Fixed16(c)...)
+	}
+	return out
+}
+
+// xyz encodes an XYZType element
+func xyz(v [3]float64) []byte {
+	out := append([]byte("XYZ "), 0, 0, 0, 0)
+	return append(out, xyzNumbers(v)...)
+}
+
+// text encodes a textType element
+func text(s string) []byte {
+	out := append([]byte("text"), 0, 0, 0, 0)
+	out = append(out, s...)
+	return append(out, 0)
+}
+
+// textDescription encodes a version 2 textDescriptionType element (ASCII only)
+func textDescription(s string) []byte {
+	out := append([]byte("desc"), 0, 0, 0, 0)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(s)+1))
+	out = append(out, s...)
+	out = append(out, 0)
+	out = append(out, make([]byte, 4+4)...)    // Unicode language code and count
+	out = append(out, make([]byte, 2+1+67)...) // ScriptCode code, count and string
+	return out
+}
+
+// srgbCurve encodes a curveType element sampling the sRGB transfer function
+func srgbCurve(points int) []byte {
+	out := append([]byte("curv"), 0, 0, 0, 0)
+	out = binary.BigEndian.AppendUint32(out, uint32(points))
+	for i := 0; i < points; i++ {
+		v := float64(i) / float64(points-1)
+		var linear float64
+		if v <= 0.04045 {
+			linear = v / 12.92
+		} else {
+			linear = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		out = binary.BigEndian.AppendUint16(out, uint16(math.Round(linear*65535)))
+	}
+	return out
+}
+
+// Header is the parsed fixed-size header of an ICC profile.
+type Header struct {
+	Size       uint32 // profile size in bytes
+	Version    uint32 // profile version (e.g. 0x02100000 for 2.1)
+	Class      string // device class ("mntr", "prtr", "scnr", ...)
+	ColorSpace string // data color space ("RGB ", "CMYK", "GRAY", ...)
+	PCS        string // profile connection space ("XYZ " or "Lab ")
+}
+
+// Components returns the number of color components of the data color space,
+// or 0 if the color space cannot be used in a PDF ICCBased color space.
+func (h *Header) Components() int {
+	switch h.ColorSpace {
+	case "GRAY":
+		return 1
+	case "RGB ", "Lab ":
+		return 3
+	case "CMYK":
+		return 4
+	default:
+		return 0
+	}
+}
+
+// ParseHeader validates the header of an ICC profile and returns its fields.
+func ParseHeader(profile []byte) (*Header, error) {
+	if len(profile) < 132 {
+		return nil, fmt.Errorf("ICC profile is too short (%d bytes)", len(profile))
+	}
+	if string(profile[36:40]) != "acsp" {
+		return nil, fmt.Errorf("not an ICC profile: missing 'acsp' signature")
+	}
+	h := &Header{
+		Size:       binary.BigEndian.Uint32(profile[0:4]),
+		Version:    binary.BigEndian.Uint32(profile[8:12]),
+		Class:      string(profile[12:16]),
+		ColorSpace: string(profile[16:20]),
+		PCS:        string(profile[20:24]),
+	}
+	if int(h.Size) != len(profile) {
+		return nil, fmt.Errorf("ICC profile size %d does not match the data length %d", h.Size, len(profile))
+	}
+	return h, nil
+}
@@ -0,0 +1,81 @@
+package icc
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestSRGB(t *testing.T) {
+	profile := SRGB()
+
+	if got := binary.BigEndian.Uint32(profile[0:4]); int(got) != len(profile) {
+		t.Errorf("header size = %d, want %d", got, len(profile))
+	}
+	if string(profile[36:40]) != "acsp" {
+		t.Errorf("signature = %q, want %q", profile[36:40], "acsp")
+	}
+	if string(profile[12:16]) != "mntr" || string(profile[16:20]) != "RGB " || string(profile[20:24]) != "XYZ " {
+		t.Errorf("class/color space/PCS = %q/%q/%q", profile[12:16], profile[16:20], profile[20:24])
+	}
+
+	// タグテーブルの各要素がプロファイル内に収まり、4バイト境界から始まること
+	count := int(binary.BigEndian.Uint32(profile[128:132]))
+	want := map[string]string{
+		"desc": "desc", "cprt": "text", "wtpt": "XYZ ",
+		"rXYZ": "XYZ ", "gXYZ": "XYZ ", "bXYZ": "XYZ ",
+		"rTRC": "curv", "gTRC": "curv", "bTRC": "curv",
+	}
+	if count != len(want) {
+		t.Fatalf("tag count = %d, want %d", count, len(want))
+	}
+	for i := 0; i < count; i++ {
+		entry := profile[132+12*i:]
+		sig := string(entry[0:4])
+		offset := int(binary.BigEndian.Uint32(entry[4:8]))
+		size := int(binary.BigEndian.Uint32(entry[8:12]))
+		if offset%4 != 0 || offset+size > len(profile) {
+			t.Errorf("tag %s: offset %d size %d out of range", sig, offset, size)
+			continue
+		}
+		if typ, ok := want[sig]; !ok || string(profile[offset:offset+4]) != typ {
+			t.Errorf("tag %s has type %q, want %q", sig, profile[offset:offset+4], want[sig])
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	srgb := SRGB()
+	truncated := append([]byte(nil), srgb[:200]...)
+	notICC := append([]byte(nil), srgb...)
+	copy(notICC[36:40], "xxxx")
+
+	tests := []struct {
+		name           string
+		profile        []byte
+		wantErr        bool
+		wantComponents int
+	}{
+		{"sRGB", srgb, false, 3},
+		{"too short", srgb[:64], true, 0},
+		{"size mismatch", truncated, true, 0},
+		{"missing signature", notICC, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHeader(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if h.Components() != tt.wantComponents {
+				t.Errorf("Components() = %d, want %d", h.Components(), tt.wantComponents)
+			}
+			if h.Class != "mntr" || h.Version != 0x02100000 {
+				t.Errorf("Class = %q, Version = %#x", h.Class, h.Version)
+			}
+		})
+	}
+}
@@ -14,7 +14,7 @@ type Parser struct {
 // NewParser creates a new Markdown parser with CommonMark and GFM extensions.
 func NewParser() *Parser {
 	// Enable CommonMark extensions and GitHub Flavored Markdown
-	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
+	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock | parser.OrderedListStart
 	p := parser.NewWithExtensions(extensions)
 
 	return &Parser{
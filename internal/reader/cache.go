@@ -0,0 +1,79 @@
+package reader
+
+import "container/list"
+
+// lruCache はオブジェクト番号をキーに、最近使った順に最大limit個まで値を保持するキャッシュ
+// limitが0以下の場合は無制限に保持する
+type lruCache[V any] struct {
+	limit int
+	items map[int]*list.Element
+	order *list.List // 先頭が最近使ったもの
+}
+
+type lruEntry[V any] struct {
+	key   int
+	value V
+}
+
+func newLRUCache[V any](limit int) *lruCache[V] {
+	return &lruCache[V]{
+		limit: limit,
+		items: make(map[int]*list.Element),
+		order: list.New(),
+	}
+}
+
+// get はキーの値を返し、最近使ったものにする
+func (c *lruCache[V]) get(key int) (V, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[V]).value, true
+}
+
+// put は値を保持し、上限を超えた場合は最も長く使っていないものから捨てる
+func (c *lruCache[V]) put(key int, value V) {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry[V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value})
+	c.evict()
+}
+
+// remove はキーの値を捨てる
+func (c *lruCache[V]) remove(key int) {
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+// clear はすべての値を捨てる
+func (c *lruCache[V]) clear() {
+	c.items = make(map[int]*list.Element)
+	c.order.Init()
+}
+
+// len は保持している値の数を返す
+func (c *lruCache[V]) len() int {
+	return len(c.items)
+}
+
+// setLimit は上限を変更し、超えている分を捨てる
+func (c *lruCache[V]) setLimit(limit int) {
+	c.limit = limit
+	c.evict()
+}
+
+func (c *lruCache[V]) evict() {
+	for c.limit > 0 && c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[V]).key)
+	}
+}
@@ -0,0 +1,94 @@
+package reader
+
+import "testing"
+
+// TestLRUCache は上限付きキャッシュの追加・取得・破棄をテストする
+func TestLRUCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		ops   func(c *lruCache[string])
+		want  []int // 残っているキー
+	}{
+		{
+			name:  "unlimited",
+			limit: 0,
+			ops: func(c *lruCache[string]) {
+				for i := 1; i <= 5; i++ {
+					c.put(i, "v")
+				}
+			},
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:  "evicts least recently used",
+			limit: 2,
+			ops: func(c *lruCache[string]) {
+				c.put(1, "a")
+				c.put(2, "b")
+				c.get(1)
+				c.put(3, "c")
+			},
+			want: []int{1, 3},
+		},
+		{
+			name:  "put refreshes existing key",
+			limit: 2,
+			ops: func(c *lruCache[string]) {
+				c.put(1, "a")
+				c.put(2, "b")
+				c.put(1, "a2")
+				c.put(3, "c")
+			},
+			want: []int{1, 3},
+		},
+		{
+			name:  "shrink limit",
+			limit: 0,
+			ops: func(c *lruCache[string]) {
+				for i := 1; i <= 4; i++ {
+					c.put(i, "v")
+				}
+				c.setLimit(1)
+			},
+			want: []int{4},
+		},
+		{
+			name:  "remove and clear",
+			limit: 0,
+			ops: func(c *lruCache[string]) {
+				c.put(1, "a")
+				c.put(2, "b")
+				c.remove(1)
+				c.clear()
+				c.put(3, "c")
+			},
+			want: []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newLRUCache[string](tt.limit)
+			tt.ops(c)
+			if c.len() != len(tt.want) {
+				t.Errorf("len() = %d, want %d", c.len(), len(tt.want))
+			}
+			for _, key := range tt.want {
+				if _, ok := c.get(key); !ok {
+					t.Errorf("get(%d) not found", key)
+				}
+			}
+		})
+	}
+
+	c := newLRUCache[string](0)
+	c.put(1, "a")
+	c.put(1, "b")
+	if v, _ := c.get(1); v != "b" {
+		t.Errorf("get(1) = %q, want %q", v, "b")
+	}
+	if v, ok := c.get(2); ok || v != "" {
+		t.Errorf("get(2) = %q, %v, want zero value", v, ok)
+	}
+}
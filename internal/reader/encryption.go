@@ -9,18 +9,24 @@ import (
 
 // EncryptionInfo holds decryption information for reading encrypted PDFs
 type EncryptionInfo struct {
-	Filter         string   // Should be "Standard"
-	V              int      // Version (1 or 2)
-	R              int      // Revision (2 or 3)
-	O              []byte   // Owner password string
-	U              []byte   // User password string
-	P              int32    // Permission flags
-	Length         int      // Key length in bits (40 or 128)
-	FileID         []byte   // File ID from trailer
-	EncryptionKey  []byte   // Computed encryption key
-	KeyLengthBytes int      // Key length in bytes
-	Authenticated  bool     // Whether password was successfully authenticated
-	IsOwner        bool     // Whether authenticated as owner
+	Filter         string // Should be "Standard"
+	V              int    // Version (1, 2, 4 or 5)
+	R              int    // Revision (2, 3, 4, 5 or 6)
+	O              []byte // Owner password string
+	U              []byte // User password string
+	OE             []byte // Owner-encrypted file key (R5/R6)
+	UE             []byte // User-encrypted file key (R5/R6)
+	Perms          []byte // Encrypted permissions (R5/R6)
+	P              int32  // Permission flags
+	Length         int    // Key length in bits (40, 128 or 256)
+	FileID         []byte // File ID from trailer
+	EncryptionKey  []byte // Computed encryption key
+	KeyLengthBytes int    // Key length in bytes
+	Authenticated  bool   // Whether password was successfully authenticated
+	IsOwner        bool   // Whether authenticated as owner
+
+	StreamAlgorithm security.Algorithm // Cipher for streams (from /StmF for V4/V5)
+	StringAlgorithm security.Algorithm // Cipher for strings (from /StrF for V4/V5)
 }
 
 // parseEncryptDict parses the Encrypt dictionary from the PDF
@@ -82,13 +88,88 @@ func parseEncryptDict(encryptDict core.Dictionary, fileID []byte) (*EncryptionIn
 		info.Length = 40
 	}
 
+	// OE, UE and Perms (required for R5/R6) - encrypted file keys and permissions
+	if info.R >= 5 {
+		for _, entry := range []struct {
+			key  string
+			dest *[]byte
+		}{
+			{"OE", &info.OE},
+			{"UE", &info.UE},
+			{"Perms", &info.Perms},
+		} {
+			value, ok := encryptDict[core.Name(entry.key)].(core.String)
+			if !ok {
+				return nil, fmt.Errorf("missing %s in Encrypt dictionary", entry.key)
+			}
+			*entry.dest = []byte(value)
+		}
+	}
+
+	// V4 and V5 use crypt filters to select the cipher for streams and strings
+	info.StreamAlgorithm = security.AlgorithmRC4
+	info.StringAlgorithm = security.AlgorithmRC4
+	if info.V == 4 || info.V == 5 {
+		cf, _ := encryptDict[core.Name("CF")].(core.Dictionary)
+
+		var err error
+		info.StreamAlgorithm, err = cryptFilterAlgorithm(cf, encryptDict[core.Name("StmF")])
+		if err != nil {
+			return nil, err
+		}
+		info.StringAlgorithm, err = cryptFilterAlgorithm(cf, encryptDict[core.Name("StrF")])
+		if err != nil {
+			return nil, err
+		}
+
+		// V4 uses a 128-bit key unless /Length says otherwise; V5 always uses 256 bits
+		if _, ok := encryptDict[core.Name("Length")]; !ok {
+			info.Length = 128
+		}
+		if info.V == 5 {
+			info.Length = 256
+		}
+	}
+
 	info.KeyLengthBytes = info.Length / 8
 
 	return info, nil
 }
 
+// cryptFilterAlgorithm resolves a crypt filter name (/StmF or /StrF) to its cipher
+func cryptFilterAlgorithm(cf core.Dictionary, filterName core.Object) (security.Algorithm, error) {
+	name, ok := filterName.(core.Name)
+	if !ok || name == "Identity" {
+		// The default crypt filter is Identity (no encryption)
+		return security.AlgorithmIdentity, nil
+	}
+
+	filter, ok := cf[name].(core.Dictionary)
+	if !ok {
+		return 0, fmt.Errorf("crypt filter %s not found in /CF", name)
+	}
+
+	cfm, _ := filter[core.Name("CFM")].(core.Name)
+	switch cfm {
+	case "V2":
+		return security.AlgorithmRC4, nil
+	case "AESV2":
+		return security.AlgorithmAESV2, nil
+	case "AESV3":
+		return security.AlgorithmAESV3, nil
+	case "None", "":
+		return security.AlgorithmIdentity, nil
+	default:
+		return 0, fmt.Errorf("unsupported crypt filter method: %s", cfm)
+	}
+}
+
 // Authenticate attempts to authenticate with the given password
 func (ei *EncryptionInfo) Authenticate(password string) error {
+	if ei.R >= 5 {
+		return ei.authenticateAES256(password)
+	}
+
 	// Try as user password first
 	if security.AuthenticateUserPassword(
 		password,
@@ -149,13 +230,35 @@ func (ei *EncryptionInfo) Authenticate(password string) error {
 	return fmt.Errorf("password authentication failed")
 }
 
+// authenticateAES256 authenticates against revision 5/6 (AES-256) values
+func (ei *EncryptionInfo) authenticateAES256(password string) error {
+	fileKey, isOwner, ok := security.AuthenticateAES256(password, ei.R, ei.O, ei.U, ei.OE, ei.UE)
+	if !ok {
+		return fmt.Errorf("password authentication failed")
+	}
+
+	// Perms guards the P entry against tampering
+	if ei.R >= 6 && !security.VerifyPerms(fileKey, ei.Perms, ei.P) {
+		return fmt.Errorf("permissions do not match the encrypted Perms value")
+	}
+
+	ei.EncryptionKey = fileKey
+	ei.Authenticated = true
+	ei.IsOwner = isOwner
+	return nil
+}
+
 // DecryptStream decrypts a stream object
 func (ei *EncryptionInfo) DecryptStream(data []byte, objectNumber, generationNumber int) []byte {
 	if !ei.Authenticated {
 		return data // Return as-is if not authenticated
 	}
 
-	return security.DecryptStream(data, ei.EncryptionKey, objectNumber, generationNumber, ei.KeyLengthBytes)
+	decrypted, err := security.DecryptData(ei.StreamAlgorithm, data, ei.EncryptionKey, objectNumber, generationNumber, ei.KeyLengthBytes)
+	if err != nil {
+		return data
+	}
+	return decrypted
 }
 
 // DecryptString decrypts a string object
@@ -164,5 +267,9 @@ func (ei *EncryptionInfo) DecryptString(data []byte, objectNumber, generationNum
 		return string(data) // Return as-is if not authenticated
 	}
 
-	return security.DecryptString(data, ei.EncryptionKey, objectNumber, generationNumber, ei.KeyLengthBytes)
+	decrypted, err := security.DecryptData(ei.StringAlgorithm, data, ei.EncryptionKey, objectNumber, generationNumber, ei.KeyLengthBytes)
+	if err != nil {
+		return string(data)
+	}
+	return string(decrypted)
 }
@@ -0,0 +1,143 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// asciiHexDecode はASCIIHexDecodeフィルターを元に戻す
+// 空白は無視し、'>' をデータの終わりとする。桁数が奇数の場合は最後に0を補う
+func asciiHexDecode(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data)/2)
+	var hi byte
+	odd := false
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if isWhitespace(c) {
+			continue
+		}
+		v, ok := hexValue(c)
+		if !ok {
+			return nil, fmt.Errorf("invalid character in ASCIIHexDecode data: %q", c)
+		}
+		if odd {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+	if odd {
+		out = append(out, hi<<4)
+	}
+	return out, nil
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// ascii85Decode はASCII85Decodeフィルターを元に戻す
+// 5文字（'!'〜'u'）で4バイトを表し、'z' は4バイトの0、"~>" をデータの終わりとする
+// 最後の組がn文字（2〜4）の場合は 'u' を補ってn-1バイトを取り出す
+func ascii85Decode(data []byte) ([]byte, error) {
+	// 先頭の "<~" は省略可能
+	if len(data) >= 2 && data[0] == '<' && data[1] == '~' {
+		data = data[2:]
+	}
+
+	out := make([]byte, 0, len(data)*4/5)
+	var group [5]byte
+	n := 0
+	for _, c := range data {
+		if c == '~' {
+			break
+		}
+		if isWhitespace(c) {
+			continue
+		}
+		if c == 'z' && n == 0 {
+			out = append(out, 0, 0, 0, 0)
+			continue
+		}
+		if c < '!' || c > 'u' {
+			return nil, fmt.Errorf("invalid character in ASCII85Decode data: %q", c)
+		}
+		group[n] = c - '!'
+		n++
+		if n == 5 {
+			b, err := decodeASCII85Group(group)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b[:]...)
+			n = 0
+		}
+	}
+
+	switch n {
+	case 0:
+	case 1:
+		return nil, fmt.Errorf("ASCII85Decode data has an incomplete final group")
+	default:
+		for i := n; i < 5; i++ {
+			group[i] = 'u' - '!'
+		}
+		b, err := decodeASCII85Group(group)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b[:n-1]...)
+	}
+	return out, nil
+}
+
+// decodeASCII85Group は5桁の85進数を4バイトに変換する
+func decodeASCII85Group(group [5]byte) ([4]byte, error) {
+	var v uint64
+	for _, d := range group {
+		v = v*85 + uint64(d)
+	}
+	if v > 0xFFFFFFFF {
+		return [4]byte{}, fmt.Errorf("ASCII85Decode group is out of range")
+	}
+	return [4]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}, nil
+}
+
+// runLengthDecode はRunLengthDecodeフィルターを元に戻す
+// 長さバイトが0〜127なら続く長さ+1バイトをそのまま、129〜255なら次の1バイトを257-長さ回繰り返す
+// 128はデータの終わりを表す
+func runLengthDecode(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		length := int(data[i])
+		i++
+		switch {
+		case length == 128:
+			return out, nil
+		case length < 128:
+			if i+length+1 > len(data) {
+				return nil, fmt.Errorf("RunLengthDecode data is truncated")
+			}
+			out = append(out, data[i:i+length+1]...)
+			i += length + 1
+		default:
+			if i >= len(data) {
+				return nil, fmt.Errorf("RunLengthDecode data is truncated")
+			}
+			out = append(out, bytes.Repeat(data[i:i+1], 257-length)...)
+			i++
+		}
+	}
+	return out, nil
+}
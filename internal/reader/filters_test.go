@@ -0,0 +1,165 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"fmt"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// TestASCIIHexDecode はASCIIHexDecodeフィルターをテストする
+func TestASCIIHexDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []byte
+		wantErr bool
+	}{
+		{"simple", "48656c6c6f>", []byte("Hello"), false},
+		{"upper case and whitespace", "48 65\n6C\t6C 6F >", []byte("Hello"), false},
+		{"odd digits", "414>", []byte{0x41, 0x40}, false},
+		{"no end marker", "4142", []byte("AB"), false},
+		{"data after end marker", "41>42", []byte("A"), false},
+		{"empty", ">", []byte{}, false},
+		{"invalid character", "4G>", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := asciiHexDecode([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("asciiHexDecode() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("asciiHexDecode() failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("asciiHexDecode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestASCII85Decode はASCII85Decodeフィルターをテストする
+func TestASCII85Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []byte
+		wantErr bool
+	}{
+		{"full group", "87cURD]i,\"Ebo80~>", []byte("Hello World!"), false},
+		{"partial group", "87cURDZ~>", []byte("Hello"), false},
+		{"leading marker and whitespace", "<~87cUR\nDZ~>", []byte("Hello"), false},
+		{"z for zeros", "z!!~>", []byte{0, 0, 0, 0, 0}, false},
+		{"empty", "~>", []byte{}, false},
+		{"single character group", "87cURD~>", nil, true},
+		{"out of range", "uuuuu~>", nil, true},
+		{"invalid character", "87c{U~>", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ascii85Decode([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ascii85Decode() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ascii85Decode() failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ascii85Decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunLengthDecode はRunLengthDecodeフィルターをテストする
+func TestRunLengthDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"literal run", []byte{2, 'a', 'b', 'c', 128}, []byte("abc"), false},
+		{"repeated run", []byte{253, 'x', 128}, []byte("xxxx"), false},
+		{"mixed runs", []byte{0, 'a', 255, 'b', 1, 'c', 'd', 128}, []byte("abbcd"), false},
+		{"no end marker", []byte{1, 'a', 'b'}, []byte("ab"), false},
+		{"data after end marker", []byte{0, 'a', 128, 0, 'b'}, []byte("a"), false},
+		{"truncated literal run", []byte{3, 'a', 'b'}, nil, true},
+		{"truncated repeated run", []byte{250}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runLengthDecode(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("runLengthDecode() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runLengthDecode() failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("runLengthDecode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// encodeASCII85 はデータをASCII85で符号化し、終わりの "~>" を付ける
+func encodeASCII85(data []byte) []byte {
+	buf := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	return append(buf[:ascii85.Encode(buf, data)], "~>"...)
+}
+
+// encodeRunLength はデータを1つのリテラルの組（128バイト以下）として符号化する
+func encodeRunLength(data []byte) []byte {
+	out := append([]byte{byte(len(data) - 1)}, data...)
+	return append(out, 128)
+}
+
+// TestDecodeStream_Filters はフィルターとその連鎖をテストする
+func TestDecodeStream_Filters(t *testing.T) {
+	content := []byte("BT /F1 12 Tf (Hello) Tj ET")
+	compressed := zlibCompress(t, content)
+
+	tests := []struct {
+		name   string
+		filter core.Object
+		data   []byte
+	}{
+		{"ASCIIHexDecode", core.Name("ASCIIHexDecode"), []byte(fmt.Sprintf("%x>", content))},
+		{"abbreviated AHx", core.Name("AHx"), []byte(fmt.Sprintf("%X>", content))},
+		{"ASCII85Decode", core.Name("ASCII85Decode"), encodeASCII85(content)},
+		{"ASCIIHex then Flate", core.Array{core.Name("ASCIIHexDecode"), core.Name("FlateDecode")}, []byte(fmt.Sprintf("%x>", compressed))},
+		{"ASCII85 then Flate", core.Array{core.Name("A85"), core.Name("FlateDecode")}, encodeASCII85(compressed)},
+		{"RunLengthDecode", core.Name("RunLengthDecode"), encodeRunLength(content)},
+		{"Flate then RunLength", core.Array{core.Name("FlateDecode"), core.Name("RL")}, zlibCompress(t, encodeRunLength(content))},
+	}
+
+	r := &Reader{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &core.Stream{Dict: core.Dictionary{core.Name("Filter"): tt.filter}, Data: tt.data}
+			got, err := r.decodeStream(stream)
+			if err != nil {
+				t.Fatalf("decodeStream() failed: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("decodeStream() = %q, want %q", got, content)
+			}
+		})
+	}
+}
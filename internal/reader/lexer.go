@@ -37,6 +37,9 @@ type Token struct {
 type Lexer struct {
 	r      *bufio.Reader
 	peeked []byte // 先読みバッファ
+
+	offset     int64 // 読み終えたバイト数（先読みしたバイトは含まない）
+	tokenStart int64 // 最後に読んだトークンの先頭の位置
 }
 
 // NewLexer は新しいLexerを作成する
@@ -56,6 +59,7 @@ func (l *Lexer) NextToken() (Token, error) {
 		}
 		return Token{}, err
 	}
+	l.tokenStart = l.offset
 
 	// 次の文字を先読み
 	b, err := l.peekByte()
@@ -92,6 +96,17 @@ func (l *Lexer) NextToken() (Token, error) {
 	}
 }
 
+// Offset は入力の先頭から読み終えたバイト数を返す
+// キーワードを読んだ直後は、キーワードの末尾の位置になる
+func (l *Lexer) Offset() int64 {
+	return l.offset
+}
+
+// TokenStart は最後にNextTokenで読んだトークンの先頭の位置を返す（空白とコメントは含まない）
+func (l *Lexer) TokenStart() int64 {
+	return l.tokenStart
+}
+
 // skipWhitespaceAndComments は空白文字とコメントをスキップする
 func (l *Lexer) skipWhitespaceAndComments() error {
 	for {
@@ -354,9 +369,14 @@ func (l *Lexer) readByte() (byte, error) {
 	if len(l.peeked) > 0 {
 		b := l.peeked[0]
 		l.peeked = l.peeked[1:]
+		l.offset++
 		return b, nil
 	}
-	return l.r.ReadByte()
+	b, err := l.r.ReadByte()
+	if err == nil {
+		l.offset++
+	}
+	return b, err
 }
 
 // peekByte は次のバイトを先読みする（消費しない）
@@ -383,6 +403,7 @@ func (l *Lexer) ReadBytes(n int) ([]byte, error) {
 		if len(l.peeked) >= n {
 			result = l.peeked[:n]
 			l.peeked = l.peeked[n:]
+			l.offset += int64(n)
 			return result, nil
 		}
 		result = l.peeked
@@ -394,6 +415,7 @@ func (l *Lexer) ReadBytes(n int) ([]byte, error) {
 	buf := make([]byte, n)
 	bytesRead, err := io.ReadFull(l.r, buf)
 	result = append(result, buf[:bytesRead]...)
+	l.offset += int64(len(result))
 	return result, err
 }
 
@@ -0,0 +1,243 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// linearizationSearchSize はリニアライズ辞書を探す範囲（先頭からのバイト数）
+// リニアライズ辞書はファイルの最初の1024バイト以内に置かれる
+const linearizationSearchSize = 1024
+
+// pageOffsetHintHeaderSize はページオフセットヒントテーブルのヘッダーのバイト数
+const pageOffsetHintHeaderSize = 36
+
+// Linearization はリニアライズ（Web表示用に最適化）されたPDFのリニアライズ辞書
+type Linearization struct {
+	FileLength      int64 // /L ファイル全体のバイト数
+	FirstPageObject int   // /O 最初のページのページオブジェクト番号
+	FirstPageEnd    int64 // /E 最初のページの終わりのオフセット
+	PageCount       int   // /N ページ数
+	MainXrefOffset  int64 // /T メインのxrefテーブルのオフセット
+	HintOffset      int64 // /H ヒントストリームのオフセット
+	HintLength      int64 // /H ヒントストリームのバイト数
+}
+
+// Linearization はリニアライズ辞書を返す（リニアライズされていない場合はnil）
+func (r *Reader) Linearization() *Linearization {
+	return r.linearization
+}
+
+// detectLinearization は先頭の間接オブジェクトがリニアライズ辞書かを調べる
+// /L がファイルのバイト数と一致しない（リニアライズ後に追記された）場合は、
+// ページの位置が変わっている可能性があるためリニアライズされていないとみなす
+func (r *Reader) detectLinearization() {
+	size, err := r.Size()
+	if err != nil {
+		return
+	}
+	head, err := r.ReadRange(0, min(size, linearizationSearchSize))
+	if err != nil {
+		return
+	}
+	loc := objHeaderPattern.FindIndex(head)
+	if loc == nil {
+		return
+	}
+	if _, err := r.r.Seek(int64(loc[0]), io.SeekStart); err != nil {
+		return
+	}
+	_, _, obj, err := NewParser(r.r).ParseIndirectObject()
+	if err != nil {
+		return
+	}
+	dict, ok := obj.(core.Dictionary)
+	if !ok {
+		return
+	}
+	if _, ok := dict[core.Name("Linearized")]; !ok {
+		return
+	}
+
+	lin := &Linearization{
+		FileLength:      int64(toInteger(dict[core.Name("L")])),
+		FirstPageObject: toInteger(dict[core.Name("O")]),
+		FirstPageEnd:    int64(toInteger(dict[core.Name("E")])),
+		PageCount:       toInteger(dict[core.Name("N")]),
+		MainXrefOffset:  int64(toInteger(dict[core.Name("T")])),
+	}
+	if hint, ok := dict[core.Name("H")].(core.Array); ok && len(hint) >= 2 {
+		lin.HintOffset = int64(toInteger(hint[0]))
+		lin.HintLength = int64(toInteger(hint[1]))
+	}
+	if lin.FileLength != size || lin.FirstPageObject <= 0 || lin.PageCount <= 0 {
+		return
+	}
+	// /Nはヒントテーブルから読むページ数になるため、ページツリーの/Countとオブジェクトの数を超える値は信用しない
+	count, err := r.GetPageCount()
+	if err != nil || lin.PageCount > count || lin.PageCount > len(r.xref) {
+		return
+	}
+	// ヒントテーブルが壊れている場合は、リニアライズされていないものとして通常どおりページツリーを辿る
+	offsets, err := r.loadPageOffsets(lin)
+	if err != nil {
+		return
+	}
+	r.linearization = lin
+	r.pageHints = offsets
+}
+
+// toInteger は整数オブジェクトの値を返す（整数でなければ0）
+func toInteger(obj core.Object) int {
+	n, _ := obj.(core.Integer)
+	return int(n)
+}
+
+// linearizedPage はリニアライズ情報を使い、ページツリーを辿らずにページを取得する
+// 最初のページは/O、それ以外はページオフセットヒントテーブルのオフセットから探す
+// 見つからない、またはページオブジェクトでない場合はnilを返す（呼び出し側はページツリーを辿る）
+func (r *Reader) linearizedPage(pageNum int) core.Dictionary {
+	lin := r.linearization
+	if lin == nil || pageNum < 0 || pageNum >= lin.PageCount {
+		return nil
+	}
+
+	objNum := lin.FirstPageObject
+	if pageNum > 0 {
+		objNum = r.objectNumberAt(r.pageHints[pageNum])
+		if objNum == 0 {
+			return nil
+		}
+	}
+
+	page, ok := r.resolve(&core.Reference{ObjectNumber: objNum}).(core.Dictionary)
+	if !ok || page[core.Name("Type")] != core.Name("Page") {
+		return nil
+	}
+	return page
+}
+
+// loadPageOffsets はヒントストリームから各ページのページオブジェクトのオフセットを読む
+func (r *Reader) loadPageOffsets(lin *Linearization) ([]int64, error) {
+	objNum := r.objectNumberAt(lin.HintOffset)
+	if objNum == 0 {
+		return nil, fmt.Errorf("hint stream not found at offset %d", lin.HintOffset)
+	}
+	stream, ok := r.resolve(&core.Reference{ObjectNumber: objNum}).(*core.Stream)
+	if !ok {
+		return nil, fmt.Errorf("hint object %d is not a stream", objNum)
+	}
+	data, err := r.decodeStream(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode hint stream: %w", err)
+	}
+	offsets, err := parsePageOffsetHints(data, lin.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// ヒントテーブルのオフセットはヒントストリームがないものとして計算されている
+	for i, offset := range offsets {
+		if offset >= lin.HintOffset {
+			offsets[i] = offset + lin.HintLength
+		}
+	}
+	return offsets, nil
+}
+
+// objectNumberAt はoffset位置から始まる間接オブジェクトの番号を返す
+// xrefの同じ番号のエントリがその位置を指していない場合は0を返す
+func (r *Reader) objectNumberAt(offset int64) int {
+	if _, err := r.r.Seek(offset, io.SeekStart); err != nil {
+		return 0
+	}
+	buf := make([]byte, 64)
+	n, _ := io.ReadFull(r.r, buf)
+	loc := objHeaderPattern.FindSubmatchIndex(buf[:n])
+	if loc == nil || loc[0] != 0 {
+		return 0
+	}
+	objNum, err := strconv.Atoi(string(buf[loc[2]:loc[3]]))
+	if err != nil {
+		return 0
+	}
+	if entry, ok := r.xref[objNum]; !ok || !entry.inUse || entry.compressed || entry.offset != offset {
+		return 0
+	}
+	return objNum
+}
+
+// parsePageOffsetHints はページオフセットヒントテーブルから各ページのページオブジェクトのオフセットを求める
+//
+// ヘッダーの項目2が最初のページのオフセット、項目4と5がページの長さの最小値と差分のビット数。
+// ヘッダーに続いて、ページごとのオブジェクト数の差分、ページの長さの差分が全ページ分ずつ
+// （それぞれバイト境界から）並ぶ。各ページは前のページの直後から始まる。
+func parsePageOffsetHints(data []byte, pageCount int) ([]int64, error) {
+	if len(data) < pageOffsetHintHeaderSize {
+		return nil, fmt.Errorf("page offset hint table is too short")
+	}
+	br := &bitReader{data: data}
+	br.readBits(32) // ページ内のオブジェクト数の最小値
+	firstPage := br.readBits(32)
+	objectsBits := br.readBits(16)
+	leastLength := br.readBits(32)
+	lengthBits := br.readBits(16)
+	if objectsBits > 32 || lengthBits > 32 {
+		return nil, fmt.Errorf("page offset hint table has invalid bit widths")
+	}
+	br.pos = pageOffsetHintHeaderSize * 8
+	// 項目がヒントテーブルに収まらないページ数は、オフセットを確保する前に拒否する
+	if pageCount < 0 || uint64(pageCount)*(objectsBits+lengthBits) > uint64(len(data)-pageOffsetHintHeaderSize)*8 {
+		return nil, fmt.Errorf("page offset hint table is too short for %d pages", pageCount)
+	}
+
+	// ページごとのオブジェクト数の差分（使わない）
+	for i := 0; i < pageCount; i++ {
+		br.readBits(int(objectsBits))
+	}
+	br.alignToByte()
+
+	offsets := make([]int64, pageCount)
+	offset := int64(firstPage)
+	for i := 0; i < pageCount; i++ {
+		offsets[i] = offset
+		offset += int64(leastLength + br.readBits(int(lengthBits)))
+	}
+	if br.err != nil {
+		return nil, fmt.Errorf("page offset hint table is truncated: %w", br.err)
+	}
+	return offsets, nil
+}
+
+// bitReader はヒントテーブルのビット列を上位ビットから読む
+type bitReader struct {
+	data []byte
+	pos  int // 読み込み位置（ビット）
+	err  error
+}
+
+// readBits はnビットを読む。データが足りない場合は0を返し、errを設定する
+func (br *bitReader) readBits(n int) uint64 {
+	if br.err != nil {
+		return 0
+	}
+	if br.pos+n > len(br.data)*8 {
+		br.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := br.data[br.pos/8] >> (7 - br.pos%8) & 1
+		v = v<<1 | uint64(bit)
+		br.pos++
+	}
+	return v
+}
+
+// alignToByte は読み込み位置を次のバイト境界に進める
+func (br *bitReader) alignToByte() {
+	br.pos = (br.pos + 7) / 8 * 8
+}
@@ -0,0 +1,215 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// bitWriter はヒントテーブルのビット列を上位ビットから書く
+type bitWriter struct {
+	data []byte
+	pos  int
+}
+
+func (bw *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if bw.pos%8 == 0 {
+			bw.data = append(bw.data, 0)
+		}
+		if v>>i&1 == 1 {
+			bw.data[len(bw.data)-1] |= 1 << (7 - bw.pos%8)
+		}
+		bw.pos++
+	}
+}
+
+func (bw *bitWriter) align() {
+	bw.pos = (bw.pos + 7) / 8 * 8
+}
+
+// encodePageOffsetHints はページオフセットヒントテーブルを作る
+func encodePageOffsetHints(firstPage int64, lengths []int64) []byte {
+	least := lengths[0]
+	for _, l := range lengths {
+		least = min(least, l)
+	}
+	bw := &bitWriter{}
+	bw.writeBits(2, 32)                 // ページ内のオブジェクト数の最小値
+	bw.writeBits(uint64(firstPage), 32) // 最初のページのオフセット
+	bw.writeBits(8, 16)                 // オブジェクト数の差分のビット数
+	bw.writeBits(uint64(least), 32)     // ページの長さの最小値
+	bw.writeBits(16, 16)                // ページの長さの差分のビット数
+	// コンテンツストリームと共有オブジェクトの項目（使わない）
+	for _, n := range []int{32, 16, 32, 16, 16, 16, 16, 16} {
+		bw.writeBits(0, n)
+	}
+	for range lengths {
+		bw.writeBits(0, 8)
+	}
+	bw.align()
+	for _, l := range lengths {
+		bw.writeBits(uint64(l-least), 16)
+	}
+	return bw.data
+}
+
+// buildLinearizedPDF は3ページのリニアライズされたPDFを作る
+// オブジェクトの並び: リニアライズ辞書(9)、ヒントストリーム(10)、Catalog(1)、Pages(2)、
+// ページ(3, 5, 7)とそのコンテンツ(4, 6, 8)、xref
+func buildLinearizedPDF(kids string) []byte {
+	const pageCount = 3
+	build := func(lin string, hints []byte) ([]byte, map[int]int64, int64, int64) {
+		var buf bytes.Buffer
+		offsets := make(map[int]int64)
+		writeObj := func(num int, body string) {
+			offsets[num] = int64(buf.Len())
+			fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", num, body)
+		}
+
+		buf.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+		writeObj(9, lin)
+		writeObj(10, fmt.Sprintf("<< /Length %d /S %d >>\nstream\n%s\nendstream", len(hints), len(hints), hints))
+		hintLength := int64(buf.Len()) - offsets[10]
+		writeObj(1, "<< /Type /Catalog /Pages 2 0 R >>")
+		writeObj(2, fmt.Sprintf("<< /Type /Pages /Kids %s /Count %d /MediaBox [0 0 200 200] >>", kids, pageCount))
+		for i := 0; i < pageCount; i++ {
+			writeObj(3+2*i, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", 4+2*i))
+			content := fmt.Sprintf("BT /F1 12 Tf (Page %d) Tj ET", i+1)
+			writeObj(4+2*i, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+		}
+
+		xrefStart := int64(buf.Len())
+		fmt.Fprintf(&buf, "xref\n0 11\n0000000000 65535 f \n")
+		for num := 1; num <= 10; num++ {
+			fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[num])
+		}
+		fmt.Fprintf(&buf, "trailer\n<< /Size 11 /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", xrefStart)
+		offsets[0] = xrefStart
+		return buf.Bytes(), offsets, hintLength, int64(buf.Len())
+	}
+
+	// 数値は固定幅なので、1回目で求めたオフセットは2回目でも変わらない
+	linDict := func(length, hintOffset, hintLength, firstPageEnd, mainXref int64) string {
+		return fmt.Sprintf("<< /Linearized 1 /L %010d /H [%010d %010d] /O 3 /E %010d /N %d /T %010d >>",
+			length, hintOffset, hintLength, firstPageEnd, pageCount, mainXref)
+	}
+	emptyHints := encodePageOffsetHints(0, make([]int64, pageCount))
+	_, offsets, hintLength, size := build(linDict(0, 0, 0, 0, 0), emptyHints)
+
+	// ヒントテーブルのオフセットはヒントストリームがないものとして計算する
+	pageStarts := []int64{offsets[3], offsets[5], offsets[7], offsets[0]}
+	lengths := make([]int64, pageCount)
+	for i := range lengths {
+		lengths[i] = pageStarts[i+1] - pageStarts[i]
+	}
+	hints := encodePageOffsetHints(offsets[3]-hintLength, lengths)
+	pdf, _, _, _ := build(linDict(size, offsets[10], hintLength, offsets[5], offsets[0]), hints)
+	return pdf
+}
+
+// TestReader_Linearized はリニアライズされたPDFのページの取得をテストする
+func TestReader_Linearized(t *testing.T) {
+	tests := []struct {
+		name       string
+		pdf        []byte
+		linearized bool
+	}{
+		{"linearized", buildLinearizedPDF("[3 0 R 5 0 R 7 0 R]"), true},
+		// ページツリーが壊れていても、/Oとヒントテーブルからページを探せる
+		{"damaged page tree", buildLinearizedPDF("[3 0 R 5 0 R 99 0 R]"), true},
+		// リニアライズ後に追記されたファイルは通常どおりページツリーを辿る
+		{"updated after linearization", append(buildLinearizedPDF("[3 0 R 5 0 R 7 0 R]"), "% appended\n"...), false},
+		// ページツリーの/Countより大きい/Nは信用せず、ページツリーを辿る
+		{"page count larger than the page tree", bytes.Replace(buildLinearizedPDF("[3 0 R 5 0 R 7 0 R]"), []byte("/N 3 "), []byte("/N 9 "), 1), false},
+		// ヒントテーブルが壊れている場合も、ページツリーを辿る
+		{"damaged hint table", damageHintTable(buildLinearizedPDF("[3 0 R 5 0 R 7 0 R]")), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.pdf))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lin := r.Linearization()
+			if (lin != nil) != tt.linearized {
+				t.Fatalf("Linearization() = %+v, want linearized = %v", lin, tt.linearized)
+			}
+			if lin != nil && (lin.FirstPageObject != 3 || lin.PageCount != 3) {
+				t.Errorf("Linearization() = %+v", lin)
+			}
+
+			for i := 0; i < 3; i++ {
+				page, err := r.GetPage(i)
+				if err != nil {
+					t.Fatalf("GetPage(%d) error = %v", i, err)
+				}
+				contents, _ := page[core.Name("Contents")].(*core.Reference)
+				if contents == nil || contents.ObjectNumber != 4+2*i {
+					t.Errorf("GetPage(%d) /Contents = %v, want %d 0 R", i, page[core.Name("Contents")], 4+2*i)
+				}
+				if _, ok := page[core.Name("MediaBox")]; !ok {
+					t.Errorf("GetPage(%d) has no inherited /MediaBox", i)
+				}
+			}
+		})
+	}
+
+	t.Run("not linearized", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(buildPDF([]string{
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+			"<< /Type /Page /Parent 2 0 R >>",
+		})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lin := r.Linearization(); lin != nil {
+			t.Errorf("Linearization() = %+v, want nil", lin)
+		}
+	})
+}
+
+// damageHintTable はヒントテーブルのオブジェクト数の差分のビット数を不正な値（255）にする
+func damageHintTable(pdf []byte) []byte {
+	start := bytes.Index(pdf, []byte("10 0 obj"))
+	start += bytes.Index(pdf[start:], []byte("stream\n")) + len("stream\n")
+	damaged := bytes.Clone(pdf)
+	damaged[start+8], damaged[start+9] = 0, 255
+	return damaged
+}
+
+// TestParsePageOffsetHints はページオフセットヒントテーブルの解析をテストする
+func TestParsePageOffsetHints(t *testing.T) {
+	hints := encodePageOffsetHints(100, []int64{50, 30, 45})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		pages   int
+		want    []int64
+		wantErr bool
+	}{
+		{"valid", hints, 3, []int64{100, 150, 180}, false},
+		{"truncated items", hints[:len(hints)-2], 3, nil, true},
+		{"truncated header", hints[:10], 3, nil, true},
+		// 項目が入りきらないページ数は、オフセットを確保せずにエラーにする
+		{"too many pages", hints, 2000000000, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePageOffsetHints(tt.data, tt.pages)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePageOffsetHints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parsePageOffsetHints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
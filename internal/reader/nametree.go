@@ -0,0 +1,212 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// maxTreeDepth は名前ツリー・ページツリーを辿る際の最大深さ（循環参照対策）
+const maxTreeDepth = 64
+
+// GetPageReferences はページツリーを辿り、全ページへの参照を文書順に返す
+func (r *Reader) GetPageReferences() ([]*core.Reference, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	pagesRef, ok := catalog[core.Name("Pages")].(*core.Reference)
+	if !ok {
+		return nil, fmt.Errorf("catalog /Pages is not a reference")
+	}
+
+	var refs []*core.Reference
+	if err := r.collectPageRefs(pagesRef, 0, &refs); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// collectPageRefs はページツリーのノードを再帰的に辿って葉（Page）の参照を収集する
+func (r *Reader) collectPageRefs(ref *core.Reference, depth int, refs *[]*core.Reference) error {
+	if depth > maxTreeDepth {
+		return fmt.Errorf("page tree is too deep")
+	}
+
+	obj, err := r.GetObject(ref.ObjectNumber)
+	if err != nil {
+		return err
+	}
+	node, ok := obj.(core.Dictionary)
+	if !ok {
+		return fmt.Errorf("page tree node %d is not a dictionary", ref.ObjectNumber)
+	}
+
+	kids, hasKids := node[core.Name("Kids")]
+	if typ, _ := node[core.Name("Type")].(core.Name); typ == "Page" || !hasKids {
+		*refs = append(*refs, ref)
+		return nil
+	}
+
+	kidsArray, ok := r.resolve(kids).(core.Array)
+	if !ok {
+		return fmt.Errorf("page tree node %d has invalid /Kids", ref.ObjectNumber)
+	}
+	for _, kid := range kidsArray {
+		kidRef, ok := kid.(*core.Reference)
+		if !ok {
+			continue
+		}
+		if err := r.collectPageRefs(kidRef, depth+1, refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findPageRef はページツリーを各Pagesノードの/Countを使って降り、pageNum番目のページの参照を返す
+// 目的のページに至る枝のノードとその兄弟だけを読み、ほかのページは読まない
+// /Countがない、またはページ数と合わない場合はエラーを返す
+func (r *Reader) findPageRef(pageNum int) (*core.Reference, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	ref, ok := catalog[core.Name("Pages")].(*core.Reference)
+	if !ok {
+		return nil, fmt.Errorf("catalog /Pages is not a reference")
+	}
+
+	remaining := pageNum
+	for depth := 0; depth <= maxTreeDepth; depth++ {
+		node, ok := r.resolve(ref).(core.Dictionary)
+		if !ok {
+			return nil, fmt.Errorf("page tree node %d is not a dictionary", ref.ObjectNumber)
+		}
+		kidsObj, hasKids := node[core.Name("Kids")]
+		if typ, _ := node[core.Name("Type")].(core.Name); typ == "Page" || !hasKids {
+			if remaining != 0 {
+				return nil, fmt.Errorf("page number %d out of range", pageNum)
+			}
+			return ref, nil
+		}
+		kids, ok := r.resolve(kidsObj).(core.Array)
+		if !ok {
+			return nil, fmt.Errorf("page tree node %d has invalid /Kids", ref.ObjectNumber)
+		}
+
+		var next *core.Reference
+		for _, kid := range kids {
+			kidRef, ok := kid.(*core.Reference)
+			if !ok {
+				continue
+			}
+			count, err := r.pageTreeNodeCount(kidRef)
+			if err != nil {
+				return nil, err
+			}
+			if remaining < count {
+				next = kidRef
+				break
+			}
+			remaining -= count
+		}
+		if next == nil {
+			return nil, fmt.Errorf("page number %d out of range", pageNum)
+		}
+		ref = next
+	}
+	return nil, fmt.Errorf("page tree is too deep")
+}
+
+// pageTreeNodeCount はページツリーのノード以下のページ数を返す（Pageは1、Pagesは/Count）
+func (r *Reader) pageTreeNodeCount(ref *core.Reference) (int, error) {
+	node, ok := r.resolve(ref).(core.Dictionary)
+	if !ok {
+		return 0, fmt.Errorf("page tree node %d is not a dictionary", ref.ObjectNumber)
+	}
+	if _, hasKids := node[core.Name("Kids")]; node[core.Name("Type")] == core.Name("Page") || !hasKids {
+		return 1, nil
+	}
+	count, ok := r.resolve(node[core.Name("Count")]).(core.Integer)
+	if !ok || count < 0 {
+		return 0, fmt.Errorf("page tree node %d has invalid /Count", ref.ObjectNumber)
+	}
+	return int(count), nil
+}
+
+// WalkNameTree は名前ツリー（/Names と /Kids で構成される）を辿り、
+// 各エントリについてfnを呼び出す。fnがエラーを返した場合は走査を中断する。
+func (r *Reader) WalkNameTree(node core.Object, fn func(key string, value core.Object) error) error {
+	return r.walkNameTree(node, fn, 0)
+}
+
+func (r *Reader) walkNameTree(node core.Object, fn func(key string, value core.Object) error, depth int) error {
+	if depth > maxTreeDepth {
+		return fmt.Errorf("name tree is too deep")
+	}
+
+	dict, ok := r.resolve(node).(core.Dictionary)
+	if !ok {
+		return nil
+	}
+
+	if names, ok := r.resolve(dict[core.Name("Names")]).(core.Array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			key, ok := names[i].(core.String)
+			if !ok {
+				continue
+			}
+			if err := fn(string(key), names[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if kids, ok := r.resolve(dict[core.Name("Kids")]).(core.Array); ok {
+		for _, kid := range kids {
+			if err := r.walkNameTree(kid, fn, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LookupNameTree は名前ツリーからキーに対応する値を検索する
+func (r *Reader) LookupNameTree(node core.Object, key string) (core.Object, bool) {
+	var found core.Object
+	errFound := fmt.Errorf("found")
+
+	err := r.WalkNameTree(node, func(k string, v core.Object) error {
+		if k == key {
+			found = v
+			return errFound
+		}
+		return nil
+	})
+	if err == errFound {
+		return found, true
+	}
+	return nil, false
+}
+
+// Resolve は参照であれば解決したオブジェクトを返し、そうでなければそのまま返す
+// 解決に失敗した場合はnilを返す
+func (r *Reader) Resolve(obj core.Object) core.Object {
+	return r.resolve(obj)
+}
+
+func (r *Reader) resolve(obj core.Object) core.Object {
+	ref, ok := obj.(*core.Reference)
+	if !ok {
+		return obj
+	}
+	resolved, err := r.GetObject(ref.ObjectNumber)
+	if err != nil {
+		return nil
+	}
+	return resolved
+}
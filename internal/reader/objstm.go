@@ -0,0 +1,97 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// objectStream は展開したオブジェクトストリーム（/Type /ObjStm）
+type objectStream struct {
+	data    []byte // デコードしたストリームデータ
+	numbers []int  // 格納順のオブジェクト番号
+	offsets []int  // 格納順のオブジェクトの開始位置（data内）
+}
+
+// getCompressedObject はオブジェクトストリームに格納されたオブジェクトを取得する
+func (r *Reader) getCompressedObject(objNum int, entry xrefEntry) (core.Object, error) {
+	if entry.streamNum == objNum {
+		return nil, fmt.Errorf("object stream %d contains itself", objNum)
+	}
+	objStm, err := r.loadObjectStream(entry.streamNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load object stream %d for object %d: %w", entry.streamNum, objNum, err)
+	}
+
+	// xrefの番号で見つからない場合は、オブジェクト番号で探す
+	i := entry.index
+	if i < 0 || i >= len(objStm.numbers) || objStm.numbers[i] != objNum {
+		i = -1
+		for k, num := range objStm.numbers {
+			if num == objNum {
+				i = k
+				break
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("object %d not found in object stream %d", objNum, entry.streamNum)
+		}
+	}
+
+	obj, err := NewParser(bytes.NewReader(objStm.data[objStm.offsets[i]:])).ParseObject()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse object %d in object stream %d: %w", objNum, entry.streamNum, err)
+	}
+	return obj, nil
+}
+
+// loadObjectStream はオブジェクトストリームをデコードして見出し（番号と位置の組）を解析する
+func (r *Reader) loadObjectStream(streamNum int) (*objectStream, error) {
+	if objStm, ok := r.objStreams.get(streamNum); ok {
+		return objStm, nil
+	}
+
+	obj, err := r.GetObject(streamNum)
+	if err != nil {
+		return nil, err
+	}
+	stream, ok := obj.(*core.Stream)
+	if !ok || stream.Dict[core.Name("Type")] != core.Name("ObjStm") {
+		return nil, fmt.Errorf("object %d is not an object stream", streamNum)
+	}
+	n, ok1 := stream.Dict[core.Name("N")].(core.Integer)
+	first, ok2 := stream.Dict[core.Name("First")].(core.Integer)
+	if !ok1 || !ok2 || n < 0 || first < 0 {
+		return nil, fmt.Errorf("object stream %d has invalid /N or /First", streamNum)
+	}
+
+	data, err := r.decodeStream(stream)
+	if err != nil {
+		return nil, err
+	}
+	if int(first) > len(data) {
+		return nil, fmt.Errorf("object stream %d: /First is beyond the data", streamNum)
+	}
+
+	// 見出しは「オブジェクト番号 相対位置」の組がN個並ぶ
+	objStm := &objectStream{data: data}
+	lexer := NewLexer(bytes.NewReader(data[:first]))
+	for i := 0; i < int(n); i++ {
+		num, err1 := lexer.NextToken()
+		off, err2 := lexer.NextToken()
+		if err1 != nil || err2 != nil || num.Type != TokenInteger || off.Type != TokenInteger {
+			return nil, fmt.Errorf("object stream %d has an invalid header", streamNum)
+		}
+		// 負の相対位置や桁あふれした位置は見出しやデータの外を指す
+		offset := int(first) + off.Value.(int)
+		if off.Value.(int) < 0 || offset < int(first) || offset > len(data) {
+			return nil, fmt.Errorf("object stream %d: offset of object %d is outside the data", streamNum, num.Value.(int))
+		}
+		objStm.numbers = append(objStm.numbers, num.Value.(int))
+		objStm.offsets = append(objStm.offsets, offset)
+	}
+
+	r.objStreams.put(streamNum, objStm)
+	return objStm, nil
+}
@@ -0,0 +1,115 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// applyPredictor はFlateDecodeの/DecodeParmsで指定された予測子を元に戻す
+// /Predictor 2 はTIFF、10〜15 はPNG（各行の先頭バイトがフィルターの種類）
+func applyPredictor(data []byte, params core.Dictionary) ([]byte, error) {
+	predictor := intParam(params, "Predictor", 1)
+	if predictor <= 1 {
+		return data, nil
+	}
+
+	colors := intParam(params, "Colors", 1)
+	bpc := intParam(params, "BitsPerComponent", 8)
+	columns := intParam(params, "Columns", 1)
+	if colors < 1 || bpc < 1 || columns < 1 {
+		return nil, fmt.Errorf("invalid predictor parameters")
+	}
+	bpp := (colors*bpc + 7) / 8             // 1ピクセルのバイト数（1未満は1）
+	rowSize := (colors*bpc*columns + 7) / 8 // 1行のバイト数
+
+	switch {
+	case predictor == 2:
+		return tiffPredictor(data, rowSize, bpp, bpc)
+	case predictor >= 10:
+		return pngPredictor(data, rowSize, bpp)
+	default:
+		return nil, fmt.Errorf("unsupported predictor: %d", predictor)
+	}
+}
+
+// pngPredictor はPNGの行フィルター（None, Sub, Up, Average, Paeth）を元に戻す
+func pngPredictor(data []byte, rowSize, bpp int) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowSize)
+	for pos := 0; pos < len(data); pos += rowSize + 1 {
+		filter := data[pos]
+		row := make([]byte, rowSize)
+		copy(row, data[pos+1:min(pos+1+rowSize, len(data))])
+
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 0:
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("invalid PNG predictor filter type: %d", filter)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+// paeth はPNGのPaeth予測値を返す
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// tiffPredictor はTIFFの水平差分予測を元に戻す（8ビットの成分のみ対応）
+func tiffPredictor(data []byte, rowSize, bpp, bpc int) ([]byte, error) {
+	if bpc != 8 {
+		return nil, fmt.Errorf("TIFF predictor with %d bits per component is not supported", bpc)
+	}
+	out := make([]byte, len(data))
+	copy(out, data)
+	for start := 0; start < len(out); start += rowSize {
+		end := min(start+rowSize, len(out))
+		for i := start + bpp; i < end; i++ {
+			out[i] += out[i-bpp]
+		}
+	}
+	return out, nil
+}
+
+// intParam は/DecodeParmsの整数値を返す（未指定の場合はdef）
+func intParam(params core.Dictionary, key string, def int) int {
+	if v, ok := params[core.Name(key)].(core.Integer); ok {
+		return int(v)
+	}
+	return def
+}
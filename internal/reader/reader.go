@@ -6,6 +6,7 @@ import (
 	"compress/zlib"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -18,23 +19,33 @@ type xrefEntry struct {
 	offset     int64 // ファイル内バイトオフセット
 	generation int   // 世代番号
 	inUse      bool  // 使用中かどうか
+	compressed bool  // オブジェクトストリームに格納されているか（xrefストリームのタイプ2）
+	streamNum  int   // 格納しているオブジェクトストリームの番号（compressedの場合）
+	index      int   // オブジェクトストリーム内の番号（compressedの場合）
 }
 
 // Reader はPDFファイルを読み込み、解析する
 type Reader struct {
-	r          io.ReadSeeker       // ファイルのシーク可能なリーダー
-	xref       map[int]xrefEntry   // オブジェクト番号 -> xrefエントリ
-	trailer    core.Dictionary     // Trailer辞書
-	objCache   map[int]core.Object // オブジェクトキャッシュ
-	encryption *EncryptionInfo     // 暗号化情報（nil = 暗号化なし）
+	r          io.ReadSeeker            // ファイルのシーク可能なリーダー
+	xref       map[int]xrefEntry        // オブジェクト番号 -> xrefエントリ
+	trailer    core.Dictionary          // Trailer辞書
+	objCache   *lruCache[core.Object]   // オブジェクトキャッシュ
+	objStreams *lruCache[*objectStream] // 展開済みのオブジェクトストリーム
+	encryption *EncryptionInfo          // 暗号化情報（nil = 暗号化なし）
+	repaired   bool                     // xrefを修復したか（修復は1回だけ行う）
+	xrefOffset int64                    // 最新のxrefセクションの位置（startxrefの値）
+
+	linearization *Linearization // リニアライズ辞書（nil = リニアライズされていない）
+	pageHints     []int64        // ヒントテーブルから求めたページオブジェクトのオフセット
 }
 
 // NewReader は新しいReaderを作成する
 func NewReader(r io.ReadSeeker) (*Reader, error) {
 	reader := &Reader{
-		r:        r,
-		xref:     make(map[int]xrefEntry),
-		objCache: make(map[int]core.Object),
+		r:          r,
+		xref:       make(map[int]xrefEntry),
+		objCache:   newLRUCache[core.Object](0),
+		objStreams: newLRUCache[*objectStream](0),
 	}
 
 	// ファイルの解析
@@ -47,6 +58,26 @@ func NewReader(r io.ReadSeeker) (*Reader, error) {
 
 // parse はPDFファイルを解析する
 func (r *Reader) parse() error {
+	// xrefとtrailerを解析し、壊れている場合はファイルを走査して作り直す
+	if err := r.parseXref(); err != nil {
+		if repairErr := r.repair(); repairErr != nil {
+			return fmt.Errorf("%w (repair failed: %v)", err, repairErr)
+		}
+	}
+
+	// 暗号化情報を検出
+	if err := r.detectEncryption(); err != nil {
+		return fmt.Errorf("failed to detect encryption: %w", err)
+	}
+
+	// リニアライズされたPDFは、ページツリーを辿らずにページを探せる
+	r.detectLinearization()
+
+	return nil
+}
+
+// parseXref はstartxrefが示すxrefとtrailerを解析する
+func (r *Reader) parseXref() error {
 	// startxrefのオフセットを取得
 	xrefOffset, err := r.findStartXref()
 	if err != nil {
@@ -57,13 +88,34 @@ func (r *Reader) parse() error {
 	if err := r.parseXrefAndTrailer(xrefOffset); err != nil {
 		return fmt.Errorf("failed to parse xref and trailer: %w", err)
 	}
+	r.xrefOffset = xrefOffset
+	return nil
+}
 
-	// 暗号化情報を検出
-	if err := r.detectEncryption(); err != nil {
-		return fmt.Errorf("failed to detect encryption: %w", err)
+// XrefOffset は最新のxrefセクションの位置（startxrefの値）を返す
+// xrefを修復して読み込んだ場合は、有効な位置がないため-1を返す
+func (r *Reader) XrefOffset() int64 {
+	if r.repaired {
+		return -1
 	}
+	return r.xrefOffset
+}
 
-	return nil
+// Generation はオブジェクトの世代番号を返す（xrefにないオブジェクトは0）
+func (r *Reader) Generation(objNum int) int {
+	return r.xref[objNum].generation
+}
+
+// ObjectNumbers はxrefで使用中のオブジェクトの番号を昇順で返す
+func (r *Reader) ObjectNumbers() []int {
+	nums := make([]int, 0, len(r.xref))
+	for objNum, entry := range r.xref {
+		if entry.inUse && objNum > 0 {
+			nums = append(nums, objNum)
+		}
+	}
+	sort.Ints(nums)
+	return nums
 }
 
 // detectEncryption はPDFの暗号化情報を検出する
@@ -165,22 +217,66 @@ func (r *Reader) findStartXref() (int64, error) {
 	return offset, nil
 }
 
-// parseXrefAndTrailer はxrefテーブルとtrailerを解析する
+// parseXrefAndTrailer はxrefとtrailerを解析する
+// 増分更新されたPDFでは、startxrefが示す最新のxrefセクションから/Prevを辿って以前のセクションも読む
+// 同じオブジェクト番号は新しいセクションのエントリ（空きを含む）を優先する
 func (r *Reader) parseXrefAndTrailer(offset int64) error {
+	visited := make(map[int64]bool)
+	for !visited[offset] {
+		visited[offset] = true
+
+		entries := make(map[int]xrefEntry)
+		trailer, err := r.parseXrefSection(offset, entries)
+		if err != nil {
+			if len(visited) > 1 {
+				return fmt.Errorf("failed to parse previous xref section at offset %d: %w", offset, err)
+			}
+			return err
+		}
+
+		for objNum, entry := range entries {
+			if _, ok := r.xref[objNum]; !ok {
+				r.xref[objNum] = entry
+			}
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		} else {
+			// 最新のtrailerにないキーは以前のtrailerから補う
+			mergeTrailer(r.trailer, trailer)
+		}
+
+		prev, ok := trailer[core.Name("Prev")].(core.Integer)
+		if !ok {
+			break
+		}
+		offset = int64(prev)
+	}
+	return nil
+}
+
+// parseXrefSection はoffset位置の1つのxrefセクション（テーブルまたはxrefストリーム）を解析し、
+// エントリをentriesに追加してtrailerを返す
+func (r *Reader) parseXrefSection(offset int64, entries map[int]xrefEntry) (core.Dictionary, error) {
 	// xrefオフセット位置にシーク
 	if _, err := r.r.Seek(offset, io.SeekStart); err != nil {
-		return fmt.Errorf("failed to seek to xref: %w", err)
+		return nil, fmt.Errorf("failed to seek to xref: %w", err)
 	}
 
 	// "xref" キーワードを確認
 	reader := bufio.NewReader(r.r)
 	line, err := reader.ReadString('\n')
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !strings.HasPrefix(strings.TrimSpace(line), "xref") {
-		return fmt.Errorf("expected 'xref' keyword, got %q", line)
+		// PDF 1.5以降のxrefストリーム（"N 0 obj <</Type /XRef ...>> stream"）
+		trailer, err := r.parseXrefStream(offset, entries)
+		if err != nil {
+			return nil, fmt.Errorf("expected 'xref' keyword or cross-reference stream: %w", err)
+		}
+		return trailer, nil
 	}
 
 	// xrefサブセクションを読む
@@ -188,7 +284,7 @@ func (r *Reader) parseXrefAndTrailer(offset int64) error {
 		// 次の行を読む
 		line, err := reader.ReadString('\n')
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		line = strings.TrimSpace(line)
@@ -201,46 +297,46 @@ func (r *Reader) parseXrefAndTrailer(offset int64) error {
 		// サブセクションヘッダーをパース: "startNum count"
 		parts := strings.Fields(line)
 		if len(parts) != 2 {
-			return fmt.Errorf("invalid xref subsection header: %q", line)
+			return nil, fmt.Errorf("invalid xref subsection header: %q", line)
 		}
 
 		startNum, err := strconv.Atoi(parts[0])
 		if err != nil {
-			return fmt.Errorf("invalid xref start number: %w", err)
+			return nil, fmt.Errorf("invalid xref start number: %w", err)
 		}
 
 		count, err := strconv.Atoi(parts[1])
 		if err != nil {
-			return fmt.Errorf("invalid xref count: %w", err)
+			return nil, fmt.Errorf("invalid xref count: %w", err)
 		}
 
 		// エントリを読む
 		for i := 0; i < count; i++ {
 			entryLine, err := reader.ReadString('\n')
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			// エントリをパース: "offset generation n/f"
 			entryParts := strings.Fields(entryLine)
 			if len(entryParts) != 3 {
-				return fmt.Errorf("invalid xref entry: %q", entryLine)
+				return nil, fmt.Errorf("invalid xref entry: %q", entryLine)
 			}
 
 			offset, err := strconv.ParseInt(entryParts[0], 10, 64)
 			if err != nil {
-				return fmt.Errorf("invalid xref offset: %w", err)
+				return nil, fmt.Errorf("invalid xref offset: %w", err)
 			}
 
 			generation, err := strconv.Atoi(entryParts[1])
 			if err != nil {
-				return fmt.Errorf("invalid xref generation: %w", err)
+				return nil, fmt.Errorf("invalid xref generation: %w", err)
 			}
 
 			inUse := entryParts[2] == "n"
 
 			objNum := startNum + i
-			r.xref[objNum] = xrefEntry{
+			entries[objNum] = xrefEntry{
 				offset:     offset,
 				generation: generation,
 				inUse:      inUse,
@@ -254,23 +350,28 @@ func (r *Reader) parseXrefAndTrailer(offset int64) error {
 
 	trailerObj, err := parser.ParseObject()
 	if err != nil {
-		return fmt.Errorf("failed to parse trailer: %w", err)
+		return nil, fmt.Errorf("failed to parse trailer: %w", err)
 	}
 
 	trailer, err := utils.MustExtractAs[core.Dictionary](trailerObj, "trailer")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	r.trailer = trailer
+	// ハイブリッド形式では、オブジェクトストリーム内のオブジェクトを/XRefStmのxrefストリームで示す
+	if xrefStm, ok := trailer[core.Name("XRefStm")].(core.Integer); ok {
+		if _, err := r.parseXrefStream(int64(xrefStm), entries); err != nil {
+			return nil, fmt.Errorf("failed to parse XRefStm: %w", err)
+		}
+	}
 
-	return nil
+	return trailer, nil
 }
 
 // GetObject はオブジェクト番号からオブジェクトを取得する
 func (r *Reader) GetObject(objNum int) (core.Object, error) {
 	// キャッシュをチェック
-	if obj, ok := r.objCache[objNum]; ok {
+	if obj, ok := r.objCache.get(objNum); ok {
 		return obj, nil
 	}
 
@@ -284,36 +385,68 @@ func (r *Reader) GetObject(objNum int) (core.Object, error) {
 		return nil, fmt.Errorf("object %d is not in use", objNum)
 	}
 
+	// オブジェクトストリームに格納されたオブジェクト
+	// ストリーム全体が復号されるため、個々のオブジェクトは復号しない
+	if entry.compressed {
+		obj, err := r.getCompressedObject(objNum, entry)
+		if err != nil {
+			return nil, err
+		}
+		r.objCache.put(objNum, obj)
+		return obj, nil
+	}
+
+	obj, gen, err := r.readObjectAt(objNum, entry)
+	if err != nil {
+		// xrefのオフセットがずれている（改行コードの変換など）場合は、xrefを作り直して再試行する
+		if r.repairXref() {
+			return r.GetObject(objNum)
+		}
+		return nil, err
+	}
+
+	// 暗号化されている場合は復号化
+	// ただし、Encrypt辞書自体は暗号化されていないのでスキップ
+	if r.encryption != nil && r.encryption.Authenticated && !r.isEncryptObject(objNum) {
+		obj = r.decryptObject(obj, objNum, gen)
+	}
+
+	// キャッシュに保存
+	r.objCache.put(objNum, obj)
+
+	return obj, nil
+}
+
+// readObjectAt はxrefエントリのオフセット位置にある間接オブジェクトを読む
+func (r *Reader) readObjectAt(objNum int, entry xrefEntry) (core.Object, int, error) {
 	// オフセット位置にシーク
 	if _, err := r.r.Seek(entry.offset, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("failed to seek to object: %w", err)
+		return nil, 0, fmt.Errorf("failed to seek to object: %w", err)
 	}
 
 	// 間接オブジェクトをパース
 	parser := NewParser(r.r)
 	num, gen, obj, err := parser.ParseIndirectObject()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse object %d: %w", objNum, err)
+		return nil, 0, fmt.Errorf("failed to parse object %d: %w", objNum, err)
 	}
 
 	// オブジェクト番号と世代番号の確認
 	if num != objNum {
-		return nil, fmt.Errorf("object number mismatch: expected %d, got %d", objNum, num)
+		return nil, 0, fmt.Errorf("object number mismatch: expected %d, got %d", objNum, num)
 	}
 	if gen != entry.generation {
-		return nil, fmt.Errorf("generation number mismatch for object %d: expected %d, got %d", objNum, entry.generation, gen)
+		return nil, 0, fmt.Errorf("generation number mismatch for object %d: expected %d, got %d", objNum, entry.generation, gen)
 	}
+	return obj, gen, nil
+}
 
-	// 暗号化されている場合は復号化
-	// ただし、Encrypt辞書自体は暗号化されていないのでスキップ
-	if r.encryption != nil && r.encryption.Authenticated && !r.isEncryptObject(objNum) {
-		obj = r.decryptObject(obj, objNum, gen)
-	}
-
-	// キャッシュに保存
-	r.objCache[objNum] = obj
-
-	return obj, nil
+// SetCacheSize は読み込んだオブジェクトを保持する数の上限を設定する
+// 上限を超えると最も長く使っていないものから捨て、必要になったときにファイルから読み直す
+// 0以下は無制限（デフォルト）。展開済みのオブジェクトストリームも同じ数まで保持する
+func (r *Reader) SetCacheSize(n int) {
+	r.objCache.setLimit(n)
+	r.objStreams.setLimit(n)
 }
 
 // ResolveReference は参照を解決してオブジェクトを取得する
@@ -381,62 +514,83 @@ func (r *Reader) GetPageCount() (int, error) {
 	return int(count), nil
 }
 
+// inheritablePageKeys は親のPagesノードから継承されるページの属性
+var inheritablePageKeys = []core.Name{"Resources", "MediaBox", "CropBox", "Rotate"}
+
 // GetPage は指定されたページ番号のPageオブジェクトを返す（0-indexed）
+// ページツリーは各ノードの/Countを使って目的のページに至る枝だけを辿る
+// （リニアライズされたPDFは/Oとヒントテーブルから直接探す）。Page自身にない継承可能な属性
+// （/Resources, /MediaBox, /CropBox, /Rotate）は祖先のPagesノードから補った辞書（コピー）を返す
 func (r *Reader) GetPage(pageNum int) (core.Dictionary, error) {
-	// Catalogを取得
-	catalog, err := r.GetCatalog()
-	if err != nil {
-		return nil, err
+	if page := r.linearizedPage(pageNum); page != nil {
+		return r.inheritPageAttributes(page), nil
 	}
 
-	// /Pagesを取得
-	pagesRef, err := utils.MustExtractAs[*core.Reference](catalog[core.Name("Pages")], "catalog /Pages")
+	// /Countが正しくない場合は、ページツリー全体を辿る
+	ref, err := r.findPageRef(pageNum)
 	if err != nil {
-		return nil, err
+		refs, err := r.GetPageReferences()
+		if err != nil {
+			return nil, err
+		}
+		if pageNum < 0 || pageNum >= len(refs) {
+			return nil, fmt.Errorf("page number %d out of range [0, %d)", pageNum, len(refs))
+		}
+		ref = refs[pageNum]
 	}
 
-	pagesObj, err := r.GetObject(pagesRef.ObjectNumber)
+	pageObj, err := r.GetObject(ref.ObjectNumber)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pages: %w", err)
+		return nil, fmt.Errorf("failed to get page %d: %w", pageNum, err)
 	}
 
-	pages, err := utils.MustExtractAs[core.Dictionary](pagesObj, "pages")
+	page, err := utils.MustExtractAs[core.Dictionary](pageObj, "page")
 	if err != nil {
 		return nil, err
 	}
 
-	// /Kidsから指定されたページを取得
-	kidsObj, ok := pages[core.Name("Kids")]
-	if !ok {
-		return nil, fmt.Errorf("pages dictionary has no /Kids")
-	}
+	return r.inheritPageAttributes(page), nil
+}
 
-	kids, err := utils.MustExtractAs[core.Array](kidsObj, "pages /Kids")
+// GetPageByReference は参照先のPageオブジェクトを、GetPageと同じく継承可能な属性を補った辞書（コピー）で返す
+func (r *Reader) GetPageByReference(ref *core.Reference) (core.Dictionary, error) {
+	pageObj, err := r.GetObject(ref.ObjectNumber)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get page object %d: %w", ref.ObjectNumber, err)
 	}
 
-	if pageNum < 0 || pageNum >= len(kids) {
-		return nil, fmt.Errorf("page number %d out of range [0, %d)", pageNum, len(kids))
-	}
-
-	// ページ参照を取得
-	pageRef, err := utils.MustExtractAs[*core.Reference](kids[pageNum], "page reference")
+	page, err := utils.MustExtractAs[core.Dictionary](pageObj, "page")
 	if err != nil {
 		return nil, err
 	}
 
-	pageObj, err := r.GetObject(pageRef.ObjectNumber)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get page %d: %w", pageNum, err)
-	}
+	return r.inheritPageAttributes(page), nil
+}
 
-	page, err := utils.MustExtractAs[core.Dictionary](pageObj, "page")
-	if err != nil {
-		return nil, err
+// inheritPageAttributes は/Parentを辿り、pageにない継承可能な属性を補った辞書を返す
+func (r *Reader) inheritPageAttributes(page core.Dictionary) core.Dictionary {
+	result := make(core.Dictionary, len(page)+len(inheritablePageKeys))
+	for k, v := range page {
+		result[k] = v
 	}
 
-	return page, nil
+	node := page
+	for depth := 0; depth < maxTreeDepth; depth++ {
+		parent, ok := r.resolve(node[core.Name("Parent")]).(core.Dictionary)
+		if !ok {
+			break
+		}
+		for _, key := range inheritablePageKeys {
+			if _, ok := result[key]; ok {
+				continue
+			}
+			if v, ok := parent[key]; ok {
+				result[key] = v
+			}
+		}
+		node = parent
+	}
+	return result
 }
 
 // GetInfo はInfo辞書（メタデータ）を返す
@@ -462,6 +616,117 @@ func (r *Reader) GetInfo() (core.Dictionary, error) {
 	return info, nil
 }
 
+// GetTrailer はTrailer辞書を返す
+func (r *Reader) GetTrailer() core.Dictionary {
+	return r.trailer
+}
+
+// Size はファイル全体のバイト数を返す
+func (r *Reader) Size() (int64, error) {
+	return r.r.Seek(0, io.SeekEnd)
+}
+
+// ReadRange はファイルの指定範囲の生のバイト列を返す
+func (r *Reader) ReadRange(offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, length %d", offset, length)
+	}
+	if _, err := r.r.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek to %d: %w", offset, err)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, fmt.Errorf("failed to read %d bytes at %d: %w", length, offset, err)
+	}
+	return data, nil
+}
+
+// HexStringRange は間接オブジェクトobjNumの辞書にあるkeyの値（16進文字列）の、ファイル内の範囲を返す
+// 範囲は先頭の'<'から末尾の'>'の次まで。署名の/ByteRangeが/Contentsの値だけを除いているかの確認に使う
+// オブジェクトストリームに格納されたオブジェクトは、ファイル内の位置がないためエラーになる
+func (r *Reader) HexStringRange(objNum int, key string) (start, end int64, err error) {
+	entry, ok := r.xref[objNum]
+	if !ok || !entry.inUse || entry.compressed {
+		return 0, 0, fmt.Errorf("object %d is not stored directly in the file", objNum)
+	}
+	if _, err := r.r.Seek(entry.offset, io.SeekStart); err != nil {
+		return 0, 0, fmt.Errorf("failed to seek to object: %w", err)
+	}
+
+	// N M obj <<
+	lexer := NewLexer(r.r)
+	for _, want := range []TokenType{TokenInteger, TokenInteger, TokenKeyword, TokenDictStart} {
+		token, err := lexer.NextToken()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse object %d: %w", objNum, err)
+		}
+		if token.Type != want {
+			return 0, 0, fmt.Errorf("object %d is not a dictionary", objNum)
+		}
+	}
+
+	for {
+		// 参照の値（N M R）の残りはキーの位置に現れるので読み飛ばす
+		token, err := lexer.NextToken()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse object %d: %w", objNum, err)
+		}
+		switch token.Type {
+		case TokenInteger, TokenRef:
+			continue
+		case TokenName:
+		default:
+			return 0, 0, fmt.Errorf("object %d has no /%s", objNum, key)
+		}
+
+		value, err := lexer.NextToken()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse object %d: %w", objNum, err)
+		}
+		if token.Value.(string) == key {
+			start, end := entry.offset+lexer.TokenStart(), entry.offset+lexer.Offset()
+			if value.Type != TokenString {
+				return 0, 0, fmt.Errorf("object %d: /%s is not a string", objNum, key)
+			}
+			// リテラル文字列は16進文字列ではない
+			head, err := r.ReadRange(start, 1)
+			if err != nil {
+				return 0, 0, err
+			}
+			if head[0] != '<' {
+				return 0, 0, fmt.Errorf("object %d: /%s is not a hex string", objNum, key)
+			}
+			return start, end, nil
+		}
+		if err := skipNestedValue(lexer, value); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse object %d: %w", objNum, err)
+		}
+	}
+}
+
+// skipNestedValue は値の先頭のトークンが辞書や配列の始まりなら、対応する終わりまで読み飛ばす
+func skipNestedValue(lexer *Lexer, first Token) error {
+	depth := 0
+	token := first
+	for {
+		switch token.Type {
+		case TokenDictStart, TokenArrayStart:
+			depth++
+		case TokenDictEnd, TokenArrayEnd:
+			depth--
+		case TokenEOF:
+			return fmt.Errorf("unexpected end of object")
+		}
+		if depth <= 0 {
+			return nil
+		}
+		var err error
+		if token, err = lexer.NextToken(); err != nil {
+			return err
+		}
+	}
+}
+
 // GetPageResources はページのResourcesを取得する
 func (r *Reader) GetPageResources(page core.Dictionary) (core.Dictionary, error) {
 	resourcesObj, ok := page[core.Name("Resources")]
@@ -625,20 +890,29 @@ func (r *Reader) decodeStream(stream *core.Stream) ([]byte, error) {
 		filterObj = obj
 	}
 
+	// /DecodeParms はフィルターが1つなら辞書、複数なら同じ長さの配列
+	paramsObj := r.resolve(stream.Dict[core.Name("DecodeParms")])
+
 	// Filterが名前の場合
 	if filterName, ok := utils.ExtractAs[core.Name](filterObj); ok {
-		return r.applyFilter(data, string(filterName))
+		params, _ := paramsObj.(core.Dictionary)
+		return r.applyFilter(data, string(filterName), params)
 	}
 
 	// Filterが配列の場合（複数のフィルター）
 	if filterArray, ok := utils.ExtractAs[core.Array](filterObj); ok {
-		for _, f := range filterArray {
+		paramsArray, _ := paramsObj.(core.Array)
+		for i, f := range filterArray {
 			filterName, ok := utils.ExtractAs[core.Name](f)
 			if !ok {
 				continue
 			}
+			var params core.Dictionary
+			if i < len(paramsArray) {
+				params, _ = r.resolve(paramsArray[i]).(core.Dictionary)
+			}
 			var err error
-			data, err = r.applyFilter(data, string(filterName))
+			data, err = r.applyFilter(data, string(filterName), params)
 			if err != nil {
 				return nil, err
 			}
@@ -650,7 +924,7 @@ func (r *Reader) decodeStream(stream *core.Stream) ([]byte, error) {
 }
 
 // applyFilter はフィルターを適用する
-func (r *Reader) applyFilter(data []byte, filterName string) ([]byte, error) {
+func (r *Reader) applyFilter(data []byte, filterName string, params core.Dictionary) ([]byte, error) {
 	switch filterName {
 	case "FlateDecode":
 		// zlibで解凍
@@ -666,7 +940,16 @@ func (r *Reader) applyFilter(data []byte, filterName string) ([]byte, error) {
 			return nil, fmt.Errorf("failed to decompress stream: %w", err)
 		}
 
-		return buf.Bytes(), nil
+		return applyPredictor(buf.Bytes(), params)
+
+	case "ASCIIHexDecode", "AHx":
+		return asciiHexDecode(data)
+
+	case "ASCII85Decode", "A85":
+		return ascii85Decode(data)
+
+	case "RunLengthDecode", "RL":
+		return runLengthDecode(data)
 
 	default:
 		// サポートしていないフィルターの場合はそのまま返す
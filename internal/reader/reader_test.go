@@ -349,3 +349,332 @@ func TestReader_GetPage_OutOfRange(t *testing.T) {
 		t.Error("Expected error for negative page number, but got none")
 	}
 }
+
+// buildPDF は番号1から順にobjectsを並べたPDFを作成する（/Root は1番）
+func buildPDF(objects []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xrefStart := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, xrefStart)
+	return buf.Bytes()
+}
+
+// TestReader_GetPage_Inherited はPagesノードから継承される属性をテストする
+func TestReader_GetPage_Inherited(t *testing.T) {
+	pdf := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 5 0 R] /Count 3 /MediaBox [0 0 612 792] /Resources << /Font << /F1 6 0 R >> >> /Rotate 90 >>",
+		"<< /Type /Pages /Parent 2 0 R /Kids [4 0 R] /Count 1 /CropBox [10 10 600 780] /Rotate 180 >>",
+		"<< /Type /Page /Parent 3 0 R >>",
+		"<< /Type /Pages /Parent 2 0 R /Kids [7 0 R 8 0 R] /Count 2 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 5 0 R /MediaBox [0 0 100 100] /Rotate 0 >>",
+		"<< /Type /Page /Parent 5 0 R /Resources << >> >>",
+	})
+	r, err := NewReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		page       int
+		mediaBox   core.Object
+		cropBox    core.Object
+		rotate     core.Object
+		hasFontRes bool
+	}{
+		{
+			name:       "nested node overrides root",
+			page:       0,
+			mediaBox:   core.Array{core.Integer(0), core.Integer(0), core.Integer(612), core.Integer(792)},
+			cropBox:    core.Array{core.Integer(10), core.Integer(10), core.Integer(600), core.Integer(780)},
+			rotate:     core.Integer(180),
+			hasFontRes: true,
+		},
+		{
+			name:       "page overrides ancestors",
+			page:       1,
+			mediaBox:   core.Array{core.Integer(0), core.Integer(0), core.Integer(100), core.Integer(100)},
+			rotate:     core.Integer(0),
+			hasFontRes: true,
+		},
+		{
+			name:     "own empty resources",
+			page:     2,
+			mediaBox: core.Array{core.Integer(0), core.Integer(0), core.Integer(612), core.Integer(792)},
+			rotate:   core.Integer(90),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := r.GetPage(tt.page)
+			if err != nil {
+				t.Fatalf("GetPage(%d) failed: %v", tt.page, err)
+			}
+			if got := fmt.Sprint(page[core.Name("MediaBox")]); got != fmt.Sprint(tt.mediaBox) {
+				t.Errorf("/MediaBox = %s, want %v", got, tt.mediaBox)
+			}
+			if got := fmt.Sprint(page[core.Name("CropBox")]); got != fmt.Sprint(tt.cropBox) {
+				t.Errorf("/CropBox = %s, want %v", got, tt.cropBox)
+			}
+			if page[core.Name("Rotate")] != tt.rotate {
+				t.Errorf("/Rotate = %v, want %v", page[core.Name("Rotate")], tt.rotate)
+			}
+
+			resources, err := r.GetPageResources(page)
+			if err != nil {
+				t.Fatalf("GetPageResources() failed: %v", err)
+			}
+			_, hasFont := resources[core.Name("Font")]
+			if hasFont != tt.hasFontRes {
+				t.Errorf("resources have /Font = %v, want %v", hasFont, tt.hasFontRes)
+			}
+		})
+	}
+
+	// 継承した属性はキャッシュされたPageオブジェクトには書き込まない
+	obj, _ := r.GetObject(4)
+	if _, ok := obj.(core.Dictionary)[core.Name("MediaBox")]; ok {
+		t.Error("GetPage() modified the cached page object")
+	}
+}
+
+// appendUpdate はpdfに増分更新を追加する
+// objectsのオブジェクトを書き、deletedのオブジェクトを空きとするxrefセクションと/Prevを持つtrailerを追加する
+func appendUpdate(pdf []byte, objects map[int]string, deleted []int, trailerExtra string) []byte {
+	prev := bytes.LastIndex(pdf, []byte("startxref"))
+	prevOffset := string(bytes.Fields(pdf[prev+len("startxref"):])[0])
+
+	buf := bytes.NewBuffer(append([]byte{}, pdf...))
+	buf.WriteString("\n")
+	offsets := make(map[int]int)
+	for num := 1; num <= 20; num++ {
+		if body, ok := objects[num]; ok {
+			offsets[num] = buf.Len()
+			fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", num, body)
+		}
+	}
+
+	xrefStart := buf.Len()
+	buf.WriteString("xref\n0 1\n0000000000 65535 f \n")
+	for num := 1; num <= 20; num++ {
+		if offset, ok := offsets[num]; ok {
+			fmt.Fprintf(buf, "%d 1\n%010d 00000 n \n", num, offset)
+		}
+	}
+	for _, num := range deleted {
+		fmt.Fprintf(buf, "%d 1\n0000000000 00001 f \n", num)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size 21 %s /Prev %s >>\nstartxref\n%d\n%%%%EOF", trailerExtra, prevOffset, xrefStart)
+	return buf.Bytes()
+}
+
+// TestReader_IncrementalUpdate は/Prevで繋がった増分更新のxrefの読み込みをテストする
+func TestReader_IncrementalUpdate(t *testing.T) {
+	const updated = "BT\n/F1 12 Tf\n100 700 Td\n(Updated!) Tj\nET\n"
+	contents := fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(updated), updated)
+
+	once := appendUpdate(createMinimalPDF(), map[int]string{4: contents, 6: "<< /Title (Edited) >>"}, nil, "/Root 1 0 R /Info 6 0 R")
+	// 2回目の更新では/Rootを省略し、フォントを削除する
+	twice := appendUpdate(once, nil, []int{5}, "")
+
+	tests := []struct {
+		name      string
+		pdf       []byte
+		wantText  string
+		wantFont  bool
+		wantTitle bool
+	}{
+		{"original", createMinimalPDF(), "(Hello, World!) Tj", true, false},
+		{"one update", once, "(Updated!) Tj", true, true},
+		{"two updates", twice, "(Updated!) Tj", false, true},
+		{"xref stream update", appendXrefStreamUpdate(t, createXrefStreamPDF(t)), "(Hello, ObjStm!) Tj", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.pdf))
+			if err != nil {
+				t.Fatalf("NewReader() failed: %v", err)
+			}
+			if r.Repaired() {
+				t.Error("Repaired() = true, want false")
+			}
+
+			page, err := r.GetPage(0)
+			if err != nil {
+				t.Fatalf("GetPage(0) failed: %v", err)
+			}
+			data, err := r.GetPageContents(page)
+			if err != nil {
+				t.Fatalf("GetPageContents() failed: %v", err)
+			}
+			if !bytes.Contains(data, []byte(tt.wantText)) {
+				t.Errorf("contents = %q, want %q", data, tt.wantText)
+			}
+
+			if _, err := r.GetObject(5); (err == nil) != tt.wantFont {
+				t.Errorf("GetObject(5) error = %v, want font present = %v", err, tt.wantFont)
+			}
+
+			info, err := r.GetInfo()
+			if err != nil {
+				t.Fatalf("GetInfo() failed: %v", err)
+			}
+			if _, ok := info[core.Name("Title")]; ok != tt.wantTitle {
+				t.Errorf("info = %v, want title present = %v", info, tt.wantTitle)
+			}
+		})
+	}
+}
+
+// appendXrefStreamUpdate はxrefストリームを使うPDFに、xrefテーブルによる増分更新（/Infoの追加）を追加する
+func appendXrefStreamUpdate(t *testing.T, pdf []byte) []byte {
+	t.Helper()
+	return appendUpdate(pdf, map[int]string{8: "<< /Title (Edited) >>"}, nil, "/Root 1 0 R /Info 8 0 R")
+}
+
+// TestReader_PrevLoop は/Prevが循環していても読み込めることをテストする
+func TestReader_PrevLoop(t *testing.T) {
+	pdf := createMinimalPDF()
+	xrefStart := bytes.LastIndex(pdf, []byte("xref\n"))
+	pdf = bytes.Replace(pdf, []byte("/Root 1 0 R >>"), []byte(fmt.Sprintf("/Root 1 0 R /Prev %d >>", xrefStart)), 1)
+
+	r, err := NewReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+	if _, err := r.GetPage(0); err != nil {
+		t.Errorf("GetPage(0) failed: %v", err)
+	}
+}
+
+// TestReader_GetPage_Lazy はページツリーのうち目的のページに至る枝だけを読むことをテストする
+func TestReader_GetPage_Lazy(t *testing.T) {
+	build := func(firstCount string) []byte {
+		objects := []string{
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 6 >>",
+			"<< /Type /Pages /Parent 2 0 R /Kids [5 0 R 6 0 R 7 0 R] " + firstCount + " >>",
+			"<< /Type /Pages /Parent 2 0 R /Kids [8 0 R 9 0 R 10 0 R] /Count 3 >>",
+		}
+		for i := 0; i < 6; i++ {
+			parent := 3 + i/3
+			objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Index %d >>", parent, i))
+		}
+		return buildPDF(objects)
+	}
+
+	tests := []struct {
+		name       string
+		firstCount string
+		page       int
+		notLoaded  []int // 読まれないはずのオブジェクト
+	}{
+		{"first branch", "/Count 3", 1, []int{4, 8, 9, 10}},
+		{"second branch", "/Count 3", 4, []int{5, 6, 7, 10}},
+		// /Countがない場合はページツリー全体を辿る
+		{"missing count", "", 4, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(build(tt.firstCount)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			page, err := r.GetPage(tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := page[core.Name("Index")]; got != core.Integer(tt.page) {
+				t.Errorf("GetPage(%d) /Index = %v", tt.page, got)
+			}
+			for _, num := range tt.notLoaded {
+				if _, ok := r.objCache.get(num); ok {
+					t.Errorf("object %d was loaded", num)
+				}
+			}
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(build("/Count 3")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range []int{-1, 6} {
+			if _, err := r.GetPage(n); err == nil {
+				t.Errorf("GetPage(%d) succeeded, want error", n)
+			}
+		}
+	})
+
+	t.Run("cache size", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(build("/Count 3")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.SetCacheSize(3)
+		for i := 0; i < 6; i++ {
+			page, err := r.GetPage(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := page[core.Name("Index")]; got != core.Integer(i) {
+				t.Errorf("GetPage(%d) /Index = %v", i, got)
+			}
+			if n := r.objCache.len(); n > 3 {
+				t.Errorf("cached %d objects, want at most 3", n)
+			}
+		}
+	})
+}
+
+// TestReader_HexStringRange は辞書の16進文字列の値のファイル内の位置をテストする
+func TestReader_HexStringRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"after nested values", "<< /A 1 0 R /B << /Contents <00> >> /C [<11> (x)] /Contents <0A 0B> >>", "<0A 0B>", false},
+		{"key used as a value", "<< /Type /Contents /Contents <FF> >>", "<FF>", false},
+		{"literal string", "<< /Contents (abc) >>", "", true},
+		{"missing", "<< /B << /Contents <00> >> >>", "", true},
+		{"not a dictionary", "[/Contents <00>]", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf := buildPDF([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [] /Count 0 >>",
+				tt.body,
+			})
+			r, err := NewReader(bytes.NewReader(pdf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			start, end, err := r.HexStringRange(3, "Contents")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HexStringRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(pdf[start:end]) != tt.want {
+				t.Errorf("HexStringRange() = %q, want %q", pdf[start:end], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// objHeaderPattern は間接オブジェクトの開始（"N G obj"）
+var objHeaderPattern = regexp.MustCompile(`(\d+)[\x00\t\n\f\r ]+(\d+)[\x00\t\n\f\r ]+obj\b`)
+
+// trailerKeys は修復時にtrailerとして引き継ぐキー
+var trailerKeys = []core.Name{"Root", "Info", "ID", "Encrypt"}
+
+// Repaired はxrefを修復して読み込んだかを返す
+func (r *Reader) Repaired() bool {
+	return r.repaired
+}
+
+// repair はファイル全体から "N G obj" を探してxrefとtrailerを作り直す
+// startxrefやxrefテーブルが壊れている、またはファイルが途中で切れている場合に使う
+func (r *Reader) repair() error {
+	r.repaired = true
+	data, err := r.readAll()
+	if err != nil {
+		return err
+	}
+
+	entries := scanObjectHeaders(data)
+	if len(entries) == 0 {
+		return fmt.Errorf("no objects found")
+	}
+	r.xref = entries
+	r.objCache.clear()
+	r.objStreams.clear()
+
+	trailer := scanTrailers(data)
+
+	// オブジェクトストリームの中身を登録し、xrefストリームの辞書をtrailerとして使う
+	catalogNum := 0
+	for _, num := range sortedObjectNumbers(r.xref) {
+		switch v := r.Resolve(&core.Reference{ObjectNumber: num}).(type) {
+		case *core.Stream:
+			switch v.Dict[core.Name("Type")] {
+			case core.Name("ObjStm"):
+				objStm, err := r.loadObjectStream(num)
+				if err != nil {
+					continue
+				}
+				for i, n := range objStm.numbers {
+					if _, ok := r.xref[n]; !ok {
+						r.xref[n] = xrefEntry{inUse: true, compressed: true, streamNum: num, index: i}
+					}
+				}
+			case core.Name("XRef"):
+				mergeTrailer(trailer, v.Dict)
+			}
+		case core.Dictionary:
+			if v[core.Name("Type")] == core.Name("Catalog") {
+				catalogNum = num
+			}
+		}
+	}
+
+	// trailerが見つからない、または/Rootが読めない場合は、Catalogを探して/Rootにする
+	if _, ok := r.Resolve(trailer[core.Name("Root")]).(core.Dictionary); !ok {
+		if catalogNum == 0 {
+			catalogNum = r.findCatalog()
+		}
+		if catalogNum == 0 {
+			return fmt.Errorf("document catalog not found")
+		}
+		trailer[core.Name("Root")] = &core.Reference{ObjectNumber: catalogNum}
+	}
+	trailer[core.Name("Size")] = core.Integer(maxObjectNumber(r.xref) + 1)
+	r.trailer = trailer
+
+	// 暗号化の検出前に読んだオブジェクトは復号されていないので捨てる
+	r.objCache.clear()
+	r.objStreams.clear()
+	return nil
+}
+
+// repairXref はxrefのオフセットが壊れている場合に、trailerを残したままxrefだけを作り直す
+// 修復は1回だけ行い、作り直した場合はtrueを返す
+func (r *Reader) repairXref() bool {
+	if r.repaired {
+		return false
+	}
+	r.repaired = true
+	data, err := r.readAll()
+	if err != nil {
+		return false
+	}
+	entries := scanObjectHeaders(data)
+	if len(entries) == 0 {
+		return false
+	}
+
+	// オブジェクトストリームに格納されたオブジェクトのエントリは残す
+	for num, entry := range r.xref {
+		if _, ok := entries[num]; !ok && entry.compressed {
+			entries[num] = entry
+		}
+	}
+	r.xref = entries
+	r.objCache.clear()
+	r.objStreams.clear()
+	return true
+}
+
+// findCatalog はオブジェクトストリーム内も含めてCatalogを探す（見つからなければ0）
+func (r *Reader) findCatalog() int {
+	catalogNum := 0
+	for _, num := range sortedObjectNumbers(r.xref) {
+		if dict, ok := r.Resolve(&core.Reference{ObjectNumber: num}).(core.Dictionary); ok && dict[core.Name("Type")] == core.Name("Catalog") {
+			catalogNum = num
+		}
+	}
+	return catalogNum
+}
+
+// readAll はファイル全体を読む
+func (r *Reader) readAll() ([]byte, error) {
+	if _, err := r.r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek: %w", err)
+	}
+	data, err := io.ReadAll(r.r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// scanObjectHeaders はデータ中の "N G obj" の位置からxrefを作る
+// 増分更新では後のオブジェクトが前のものを置き換えるので、同じ番号は後に現れたものを使う
+func scanObjectHeaders(data []byte) map[int]xrefEntry {
+	entries := make(map[int]xrefEntry)
+	for _, m := range objHeaderPattern.FindAllSubmatchIndex(data, -1) {
+		num, err1 := strconv.Atoi(string(data[m[2]:m[3]]))
+		gen, err2 := strconv.Atoi(string(data[m[4]:m[5]]))
+		if err1 != nil || err2 != nil || num <= 0 {
+			continue
+		}
+		entries[num] = xrefEntry{offset: int64(m[0]), generation: gen, inUse: true}
+	}
+	return entries
+}
+
+// scanTrailers はすべての "trailer" 辞書を後ろから読み、必要なキーを集める
+func scanTrailers(data []byte) core.Dictionary {
+	trailer := core.Dictionary{}
+	end := len(data)
+	for {
+		idx := bytes.LastIndex(data[:end], []byte("trailer"))
+		if idx < 0 {
+			break
+		}
+		end = idx
+		obj, err := NewParser(bytes.NewReader(data[idx+len("trailer"):])).ParseObject()
+		if err != nil {
+			continue
+		}
+		if dict, ok := obj.(core.Dictionary); ok {
+			mergeTrailer(trailer, dict)
+		}
+	}
+	return trailer
+}
+
+// mergeTrailer はsrcのtrailerのキーのうち、dstにないものをコピーする
+func mergeTrailer(dst, src core.Dictionary) {
+	for _, key := range trailerKeys {
+		if _, ok := dst[key]; ok {
+			continue
+		}
+		if v, ok := src[key]; ok {
+			dst[key] = v
+		}
+	}
+}
+
+func sortedObjectNumbers(xref map[int]xrefEntry) []int {
+	nums := make([]int, 0, len(xref))
+	for num := range xref {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+func maxObjectNumber(xref map[int]xrefEntry) int {
+	maxNum := 0
+	for num := range xref {
+		maxNum = max(maxNum, num)
+	}
+	return maxNum
+}
@@ -0,0 +1,128 @@
+package reader
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// TestReader_Repair は壊れたPDFからのxrefとtrailerの再構築をテストする
+func TestReader_Repair(t *testing.T) {
+	pdf := createMinimalPDF()
+	xrefStart := bytes.Index(pdf, []byte("xref\n"))
+
+	tests := []struct {
+		name string
+		pdf  []byte
+	}{
+		{
+			// ファイルがxrefの途中で切れている
+			name: "truncated xref",
+			pdf:  pdf[:xrefStart+20],
+		},
+		{
+			// xref以降がまったくない
+			name: "no xref and trailer",
+			pdf:  pdf[:xrefStart],
+		},
+		{
+			name: "wrong startxref",
+			pdf:  bytes.Replace(pdf, []byte("startxref\n"), []byte("startxref\n9"), 1),
+		},
+		{
+			// 改行コードがCRLFに変換され、xrefのオフセットがずれている
+			name: "shifted offsets",
+			pdf:  shiftObjects(pdf),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.pdf))
+			if err != nil {
+				t.Fatalf("NewReader() failed: %v", err)
+			}
+
+			count, err := r.GetPageCount()
+			if err != nil || count != 1 {
+				t.Fatalf("GetPageCount() = %d, %v, want 1", count, err)
+			}
+			page, err := r.GetPage(0)
+			if err != nil {
+				t.Fatalf("GetPage(0) failed: %v", err)
+			}
+			contents, err := r.GetPageContents(page)
+			if err != nil {
+				t.Fatalf("GetPageContents() failed: %v", err)
+			}
+			if !strings.Contains(string(contents), "(Hello, World!) Tj") {
+				t.Errorf("contents = %q, want the page text", contents)
+			}
+			if !r.Repaired() {
+				t.Error("Repaired() = false, want true")
+			}
+		})
+	}
+}
+
+// shiftObjects はヘッダーの後に空行を挿入して、すべてのオブジェクトの位置をずらす
+func shiftObjects(pdf []byte) []byte {
+	return bytes.Replace(pdf, []byte("%PDF-1.7\n"), []byte("%PDF-1.7\r\n\r\n"), 1)
+}
+
+// TestReader_RepairObjectStreams はオブジェクトストリームを使うPDFの再構築をテストする
+func TestReader_RepairObjectStreams(t *testing.T) {
+	pdf := createXrefStreamPDF(t)
+	pdf = pdf[:bytes.Index(pdf, []byte("startxref"))]
+
+	r, err := NewReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		t.Fatalf("GetCatalog() failed: %v", err)
+	}
+	if catalog[core.Name("Type")] != core.Name("Catalog") {
+		t.Errorf("catalog /Type = %v, want Catalog", catalog[core.Name("Type")])
+	}
+	font, ok := r.Resolve(&core.Reference{ObjectNumber: 5}).(core.Dictionary)
+	if !ok || font[core.Name("BaseFont")] != core.Name("Helvetica") {
+		t.Errorf("object 5 = %v, want the Helvetica font", font)
+	}
+}
+
+// TestReader_RepairFails は修復できないデータでエラーになることをテストする
+func TestReader_RepairFails(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no objects", "%PDF-1.7\nthis is not a PDF\n"},
+		{"no catalog", "%PDF-1.7\n1 0 obj\n<< /Type /Pages /Kids [] /Count 0 >>\nendobj\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReader(strings.NewReader(tt.data)); err == nil {
+				t.Error("NewReader() succeeded, want error")
+			}
+		})
+	}
+}
+
+// TestReader_NotRepaired は正常なPDFでは修復しないことをテストする
+func TestReader_NotRepaired(t *testing.T) {
+	r, err := NewReader(bytes.NewReader(createMinimalPDF()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetCatalog(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Repaired() {
+		t.Error("Repaired() = true, want false")
+	}
+}
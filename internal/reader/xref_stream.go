@@ -0,0 +1,105 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// parseXrefStream はoffset位置のxrefストリーム（PDF 1.5以降）を解析し、
+// エントリをentriesに追加してストリーム辞書（トレーラーを兼ねる）を返す
+// すでに使用中のエントリがあるオブジェクト番号は上書きしない
+func (r *Reader) parseXrefStream(offset int64, entries map[int]xrefEntry) (core.Dictionary, error) {
+	if _, err := r.r.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek to xref stream: %w", err)
+	}
+	_, _, obj, err := NewParser(r.r).ParseIndirectObject()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse xref stream: %w", err)
+	}
+	stream, ok := obj.(*core.Stream)
+	if !ok || stream.Dict[core.Name("Type")] != core.Name("XRef") {
+		return nil, fmt.Errorf("object at offset %d is not a cross-reference stream", offset)
+	}
+
+	data, err := r.decodeStream(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode xref stream: %w", err)
+	}
+
+	// /W はエントリの各フィールドのバイト数（種類、フィールド2、フィールド3）
+	w, ok := stream.Dict[core.Name("W")].(core.Array)
+	if !ok || len(w) != 3 {
+		return nil, fmt.Errorf("xref stream has invalid /W")
+	}
+	var widths [3]int
+	entrySize := 0
+	for i, v := range w {
+		n, ok := v.(core.Integer)
+		if !ok || n < 0 || n > 8 {
+			return nil, fmt.Errorf("xref stream has invalid /W")
+		}
+		widths[i] = int(n)
+		entrySize += int(n)
+	}
+	if entrySize == 0 {
+		return nil, fmt.Errorf("xref stream has invalid /W")
+	}
+
+	// /Index は [開始番号 個数 ...]。省略時は [0 Size]
+	size, _ := stream.Dict[core.Name("Size")].(core.Integer)
+	index := core.Array{core.Integer(0), size}
+	if arr, ok := stream.Dict[core.Name("Index")].(core.Array); ok {
+		index = arr
+	}
+
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, ok1 := index[i].(core.Integer)
+		count, ok2 := index[i+1].(core.Integer)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("xref stream has invalid /Index")
+		}
+		for j := 0; j < int(count); j++ {
+			if pos+entrySize > len(data) {
+				return nil, fmt.Errorf("xref stream data is truncated")
+			}
+			var fields [3]int64
+			for k, width := range widths {
+				fields[k] = readBigEndian(data[pos : pos+width])
+				pos += width
+			}
+			// 種類のフィールドを省略した場合はタイプ1
+			if widths[0] == 0 {
+				fields[0] = 1
+			}
+
+			objNum := int(start) + j
+			if existing, ok := entries[objNum]; ok && existing.inUse {
+				continue
+			}
+			switch fields[0] {
+			case 0:
+				entries[objNum] = xrefEntry{generation: int(fields[2]), inUse: false}
+			case 1:
+				entries[objNum] = xrefEntry{offset: fields[1], generation: int(fields[2]), inUse: true}
+			case 2:
+				entries[objNum] = xrefEntry{inUse: true, compressed: true, streamNum: int(fields[1]), index: int(fields[2])}
+			default:
+				// 未知の種類は null オブジェクトへの参照として扱う
+			}
+		}
+	}
+
+	return stream.Dict, nil
+}
+
+// readBigEndian はビッグエンディアンの符号なし整数を読む
+func readBigEndian(b []byte) int64 {
+	var v int64
+	for _, c := range b {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
@@ -0,0 +1,298 @@
+package reader
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// objStmObjects はテスト用PDFでオブジェクトストリームに格納するオブジェクト
+var objStmObjects = []struct {
+	num  int
+	body string
+}{
+	{1, "<< /Type /Catalog /Pages 2 0 R >>"},
+	{2, "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"},
+	{3, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>"},
+	{5, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"},
+}
+
+const objStmContent = "BT\n/F1 12 Tf\n100 700 Td\n(Hello, ObjStm!) Tj\nET\n"
+
+func zlibCompress(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+// pngUpEncode はデータを各行にPNGのUpフィルターをかけて符号化する
+func pngUpEncode(data []byte, columns int) []byte {
+	var out []byte
+	prev := make([]byte, columns)
+	for pos := 0; pos < len(data); pos += columns {
+		row := data[pos : pos+columns]
+		out = append(out, 2)
+		for i, b := range row {
+			out = append(out, b-prev[i])
+		}
+		prev = row
+	}
+	return out
+}
+
+// writeObjStm はobjStmObjectsを格納したオブジェクトストリーム（オブジェクト6）を書く
+func writeObjStm(t *testing.T, buf *bytes.Buffer) {
+	t.Helper()
+	var header, body strings.Builder
+	for _, obj := range objStmObjects {
+		fmt.Fprintf(&header, "%d %d ", obj.num, body.Len())
+		body.WriteString(obj.body + "\n")
+	}
+	data := zlibCompress(t, []byte(header.String()+body.String()))
+	fmt.Fprintf(buf, "6 0 obj\n<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n", len(objStmObjects), header.Len(), len(data))
+	buf.Write(data)
+	buf.WriteString("\nendstream\nendobj\n")
+}
+
+// xrefStreamEntry はxrefストリームの1エントリ（W = [1 4 2]）
+func xrefStreamEntry(typ byte, field2 int, field3 int) []byte {
+	return []byte{typ, byte(field2 >> 24), byte(field2 >> 16), byte(field2 >> 8), byte(field2), byte(field3 >> 8), byte(field3)}
+}
+
+// writeXrefStream はxrefストリーム（オブジェクト7）を書く
+func writeXrefStream(t *testing.T, buf *bytes.Buffer, entries []byte, extra string) {
+	t.Helper()
+	data := zlibCompress(t, pngUpEncode(entries, 7))
+	fmt.Fprintf(buf, "7 0 obj\n<< /Type /XRef /W [1 4 2] %s /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 7 >> /Length %d >>\nstream\n", extra, len(data))
+	buf.Write(data)
+	buf.WriteString("\nendstream\nendobj\n")
+}
+
+// writeContents はページのコンテンツストリーム（オブジェクト4）を書く
+func writeContents(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "4 0 obj\n<< /Length %d >>\nstream\n%sendstream\nendobj\n", len(objStmContent), objStmContent)
+}
+
+// createXrefStreamPDF はxrefストリームとオブジェクトストリームを使うPDF 1.5形式のPDFを作成する
+func createXrefStreamPDF(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	offset4 := buf.Len()
+	writeContents(&buf)
+	offset6 := buf.Len()
+	writeObjStm(t, &buf)
+	offset7 := buf.Len()
+
+	var entries []byte
+	entries = append(entries, xrefStreamEntry(0, 0, 0xFFFF)...)
+	entries = append(entries, xrefStreamEntry(2, 6, 0)...) // 1: Catalog
+	entries = append(entries, xrefStreamEntry(2, 6, 1)...) // 2: Pages
+	entries = append(entries, xrefStreamEntry(2, 6, 2)...) // 3: Page
+	entries = append(entries, xrefStreamEntry(1, offset4, 0)...)
+	entries = append(entries, xrefStreamEntry(2, 6, 3)...) // 5: Font
+	entries = append(entries, xrefStreamEntry(1, offset6, 0)...)
+	entries = append(entries, xrefStreamEntry(1, offset7, 0)...)
+	writeXrefStream(t, &buf, entries, "/Size 8 /Root 1 0 R")
+
+	fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF", offset7)
+	return buf.Bytes()
+}
+
+// createHybridPDF はxrefテーブルと/XRefStmを併用するハイブリッド形式のPDFを作成する
+func createHybridPDF(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	offset4 := buf.Len()
+	writeContents(&buf)
+	offset6 := buf.Len()
+	writeObjStm(t, &buf)
+	offset7 := buf.Len()
+
+	// xrefストリームはオブジェクトストリーム内のオブジェクトだけを示す
+	var entries []byte
+	entries = append(entries, xrefStreamEntry(2, 6, 0)...)
+	entries = append(entries, xrefStreamEntry(2, 6, 1)...)
+	entries = append(entries, xrefStreamEntry(2, 6, 2)...)
+	entries = append(entries, xrefStreamEntry(2, 6, 3)...)
+	writeXrefStream(t, &buf, entries, "/Size 8 /Index [1 3 5 1]")
+
+	// 1.4以前のリーダー向けのテーブルでは、格納されたオブジェクトを空きとして示す
+	xrefStart := buf.Len()
+	buf.WriteString("xref\n0 8\n0000000000 65535 f \n")
+	buf.WriteString("0000000000 00000 f \n0000000000 00000 f \n0000000000 00000 f \n")
+	fmt.Fprintf(&buf, "%010d 00000 n \n", offset4)
+	buf.WriteString("0000000000 00000 f \n")
+	fmt.Fprintf(&buf, "%010d 00000 n \n", offset6)
+	fmt.Fprintf(&buf, "%010d 00000 n \n", offset7)
+	fmt.Fprintf(&buf, "trailer\n<< /Size 8 /Root 1 0 R /XRefStm %d >>\nstartxref\n%d\n%%%%EOF", offset7, xrefStart)
+	return buf.Bytes()
+}
+
+// TestReader_ObjectStreams はオブジェクトストリームに格納されたオブジェクトの読み込みをテストする
+func TestReader_ObjectStreams(t *testing.T) {
+	tests := []struct {
+		name string
+		pdf  []byte
+	}{
+		{"xref stream", createXrefStreamPDF(t)},
+		{"hybrid", createHybridPDF(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.pdf))
+			if err != nil {
+				t.Fatalf("NewReader() failed: %v", err)
+			}
+
+			catalog, err := r.GetCatalog()
+			if err != nil {
+				t.Fatalf("GetCatalog() failed: %v", err)
+			}
+			if catalog[core.Name("Type")] != core.Name("Catalog") {
+				t.Errorf("catalog /Type = %v, want Catalog", catalog[core.Name("Type")])
+			}
+
+			count, err := r.GetPageCount()
+			if err != nil || count != 1 {
+				t.Fatalf("GetPageCount() = %d, %v, want 1", count, err)
+			}
+			page, err := r.GetPage(0)
+			if err != nil {
+				t.Fatalf("GetPage(0) failed: %v", err)
+			}
+			contents, err := r.GetPageContents(page)
+			if err != nil {
+				t.Fatalf("GetPageContents() failed: %v", err)
+			}
+			if string(contents) != objStmContent {
+				t.Errorf("contents = %q, want %q", contents, objStmContent)
+			}
+
+			font, ok := r.Resolve(&core.Reference{ObjectNumber: 5}).(core.Dictionary)
+			if !ok || font[core.Name("BaseFont")] != core.Name("Helvetica") {
+				t.Errorf("object 5 = %v, want the Helvetica font", font)
+			}
+		})
+	}
+}
+
+// TestReader_ObjectStreamErrors は壊れたオブジェクトストリームの扱いをテストする
+func TestReader_ObjectStreamErrors(t *testing.T) {
+	r, err := NewReader(bytes.NewReader(createXrefStreamPDF(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		entry xrefEntry
+	}{
+		{"not an object stream", xrefEntry{inUse: true, compressed: true, streamNum: 4}},
+		{"missing from the stream", xrefEntry{inUse: true, compressed: true, streamNum: 6, index: 9}},
+		{"contains itself", xrefEntry{inUse: true, compressed: true, streamNum: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.xref[9] = tt.entry
+			r.objCache.remove(9)
+			if _, err := r.GetObject(9); err == nil {
+				t.Error("GetObject() succeeded, want error")
+			}
+		})
+	}
+}
+
+// TestReader_ObjectStreamMalformedHeader は見出しの相対位置が壊れたオブジェクトストリームをテストする
+func TestReader_ObjectStreamMalformedHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"negative offset", "5 -50 "},
+		{"offset beyond the data", "5 500 "},
+		{"overflowing offset", "5 9223372036854775807 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.header + "<< /Type /Font >>"
+			pdf := buildPDF([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [] /Count 0 >>",
+				fmt.Sprintf("<< /Type /ObjStm /N 1 /First %d /Length %d >>\nstream\n%s\nendstream", len(tt.header), len(body), body),
+			})
+			r, err := NewReader(bytes.NewReader(pdf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.xref[5] = xrefEntry{inUse: true, compressed: true, streamNum: 3}
+			if _, err := r.GetObject(5); err == nil {
+				t.Error("GetObject() succeeded, want error")
+			}
+		})
+	}
+}
+
+// TestApplyPredictor はFlateDecodeの予測子の復元をテストする
+func TestApplyPredictor(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		params core.Dictionary
+		want   []byte
+	}{
+		{
+			name:   "no predictor",
+			data:   []byte{1, 2, 3},
+			params: nil,
+			want:   []byte{1, 2, 3},
+		},
+		{
+			name:   "PNG None and Sub",
+			data:   []byte{0, 1, 2, 3, 1, 5, 1, 1},
+			params: core.Dictionary{core.Name("Predictor"): core.Integer(12), core.Name("Columns"): core.Integer(3)},
+			want:   []byte{1, 2, 3, 5, 6, 7},
+		},
+		{
+			name:   "PNG Up",
+			data:   []byte{2, 1, 2, 2, 1, 1},
+			params: core.Dictionary{core.Name("Predictor"): core.Integer(12), core.Name("Columns"): core.Integer(2)},
+			want:   []byte{1, 2, 2, 3},
+		},
+		{
+			name:   "PNG Average and Paeth",
+			data:   []byte{0, 10, 20, 3, 5, 5, 4, 1, 2},
+			params: core.Dictionary{core.Name("Predictor"): core.Integer(15), core.Name("Columns"): core.Integer(2)},
+			want:   []byte{10, 20, 10, 20, 11, 22},
+		},
+		{
+			name:   "TIFF",
+			data:   []byte{1, 1, 1, 5, 1, 1},
+			params: core.Dictionary{core.Name("Predictor"): core.Integer(2), core.Name("Columns"): core.Integer(3)},
+			want:   []byte{1, 2, 3, 5, 6, 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyPredictor(tt.data, tt.params)
+			if err != nil {
+				t.Fatalf("applyPredictor() failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("applyPredictor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"fmt"
+)
+
+// Algorithm identifies the cipher used to encrypt strings and streams
+type Algorithm int
+
+const (
+	// AlgorithmRC4 is the RC4 stream cipher (V1/V2, or the V2 crypt filter method)
+	AlgorithmRC4 Algorithm = iota
+	// AlgorithmAESV2 is AES-128 in CBC mode with per-object keys (V4, crypt filter method AESV2)
+	AlgorithmAESV2
+	// AlgorithmAESV3 is AES-256 in CBC mode using the file key directly (V5, crypt filter method AESV3)
+	AlgorithmAESV3
+	// AlgorithmIdentity leaves data unencrypted (the Identity crypt filter)
+	AlgorithmIdentity
+)
+
+// String returns the crypt filter method name of the algorithm
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmRC4:
+		return "V2"
+	case AlgorithmAESV2:
+		return "AESV2"
+	case AlgorithmAESV3:
+		return "AESV3"
+	case AlgorithmIdentity:
+		return "None"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+}
+
+// aesSalt is appended to the object key input for AES (Algorithm 1, step b)
+var aesSalt = []byte{0x73, 0x41, 0x6C, 0x54} // "sAlT"
+
+// ComputeAESObjectKey computes the per-object key for AESV2 encryption
+func ComputeAESObjectKey(encryptionKey []byte, objectNumber, generationNumber int) []byte {
+	data := make([]byte, 0, len(encryptionKey)+9)
+	data = append(data, encryptionKey...)
+	data = append(data,
+		byte(objectNumber), byte(objectNumber>>8), byte(objectNumber>>16),
+		byte(generationNumber), byte(generationNumber>>8),
+	)
+	data = append(data, aesSalt...)
+
+	hash := md5.Sum(data)
+
+	resultLength := len(encryptionKey) + 5
+	if resultLength > 16 {
+		resultLength = 16
+	}
+	return hash[:resultLength]
+}
+
+// EncryptAES encrypts data with AES-CBC using a random IV.
+// The IV is prepended to the result and PKCS#7 padding is applied, as required by PDF.
+func EncryptAES(data, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	padLen := aes.BlockSize - len(data)%aes.BlockSize
+	padded := make([]byte, len(data)+padLen)
+	copy(padded, data)
+	for i := len(data); i < len(padded); i++ {
+		padded[i] = byte(padLen)
+	}
+
+	out := make([]byte, aes.BlockSize+len(padded))
+	iv := out[:aes.BlockSize]
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[aes.BlockSize:], padded)
+	return out, nil
+}
+
+// DecryptAES decrypts data produced by EncryptAES (IV followed by the padded ciphertext)
+func DecryptAES(data, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	if len(data) == 0 {
+		return data, nil
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid AES ciphertext length: %d", len(data))
+	}
+
+	iv := data[:aes.BlockSize]
+	plain := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data[aes.BlockSize:])
+
+	padLen := int(plain[len(plain)-1])
+	if padLen == 0 || padLen > aes.BlockSize || padLen > len(plain) {
+		return nil, fmt.Errorf("invalid AES padding")
+	}
+	return plain[:len(plain)-padLen], nil
+}
+
+// EncryptData encrypts string or stream data of an object with the given algorithm.
+// keyLength is the length of the document encryption key in bytes.
+func EncryptData(alg Algorithm, data, encryptionKey []byte, objectNumber, generationNumber int, keyLength int) ([]byte, error) {
+	switch alg {
+	case AlgorithmRC4:
+		return EncryptStream(data, encryptionKey, objectNumber, generationNumber, keyLength), nil
+	case AlgorithmAESV2:
+		return EncryptAES(data, ComputeAESObjectKey(encryptionKey, objectNumber, generationNumber))
+	case AlgorithmAESV3:
+		return EncryptAES(data, encryptionKey)
+	case AlgorithmIdentity:
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm: %v", alg)
+	}
+}
+
+// DecryptData decrypts string or stream data of an object with the given algorithm
+func DecryptData(alg Algorithm, data, encryptionKey []byte, objectNumber, generationNumber int, keyLength int) ([]byte, error) {
+	switch alg {
+	case AlgorithmRC4:
+		return DecryptStream(data, encryptionKey, objectNumber, generationNumber, keyLength), nil
+	case AlgorithmAESV2:
+		return DecryptAES(data, ComputeAESObjectKey(encryptionKey, objectNumber, generationNumber))
+	case AlgorithmAESV3:
+		return DecryptAES(data, encryptionKey)
+	case AlgorithmIdentity:
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm: %v", alg)
+	}
+}
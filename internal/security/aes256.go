@@ -0,0 +1,232 @@
+package security
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// AES256Values holds the Encrypt dictionary values for AES-256 (V5/R6) encryption
+type AES256Values struct {
+	O     []byte // 48 bytes: hash + validation salt + key salt
+	U     []byte // 48 bytes: hash + validation salt + key salt
+	OE    []byte // 32 bytes: file key encrypted with the owner key
+	UE    []byte // 32 bytes: file key encrypted with the user key
+	Perms []byte // 16 bytes: permissions encrypted with the file key
+}
+
+// preparePasswordR6 converts a password to the byte form used by revision 6.
+// Passwords are UTF-8 encoded and truncated to 127 bytes.
+// (SASLprep normalization is not applied.)
+func preparePasswordR6(password string) []byte {
+	b := []byte(password)
+	if len(b) > 127 {
+		b = b[:127]
+	}
+	return b
+}
+
+// computeHashR6 implements Algorithm 2.B (ISO 32000-2) used by revision 6.
+// userKey is the 48-byte U value when computing owner hashes, nil otherwise.
+func computeHashR6(password, salt, userKey []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(userKey)
+	k := h.Sum(nil)
+
+	for round := 0; ; round++ {
+		// K1 = (password + K + userKey) repeated 64 times
+		seq := make([]byte, 0, len(password)+len(k)+len(userKey))
+		seq = append(seq, password...)
+		seq = append(seq, k...)
+		seq = append(seq, userKey...)
+		k1 := bytes.Repeat(seq, 64)
+
+		// E = AES-128-CBC(K1), key = K[0:16], iv = K[16:32], no padding
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// 先頭16バイトの合計 mod 3 でハッシュ関数を選択
+		sum := 0
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(e)
+		k = next.Sum(nil)
+
+		if round >= 63 && int(e[len(e)-1]) <= round-31 {
+			break
+		}
+	}
+
+	return k[:32]
+}
+
+// computeHashR5 is the hash used by the deprecated revision 5 (plain SHA-256)
+func computeHashR5(password, salt, userKey []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(userKey)
+	return h.Sum(nil)
+}
+
+// hashForRevision selects the password hash for revision 5 or 6
+func hashForRevision(revision int) func(password, salt, userKey []byte) []byte {
+	if revision == 5 {
+		return computeHashR5
+	}
+	return computeHashR6
+}
+
+// aes256NoPadding encrypts or decrypts 32 bytes with AES-256-CBC and a zero IV
+func aes256NoPadding(key, data []byte, decrypt bool) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid data length: %d", len(data))
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	out := make([]byte, len(data))
+	if decrypt {
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	} else {
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+	}
+	return out, nil
+}
+
+// ComputeAES256Values generates a random file key and the O, U, OE, UE, and Perms values
+// for revision 6 (Algorithms 8, 9 and 10 of ISO 32000-2).
+func ComputeAES256Values(userPassword, ownerPassword string, permissions int32) (fileKey []byte, values *AES256Values, err error) {
+	random := make([]byte, 32+8*4+4)
+	if _, err := rand.Read(random); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate random data: %w", err)
+	}
+	fileKey = random[:32]
+	userValidationSalt := random[32:40]
+	userKeySalt := random[40:48]
+	ownerValidationSalt := random[48:56]
+	ownerKeySalt := random[56:64]
+	permsRandom := random[64:68]
+
+	if ownerPassword == "" {
+		ownerPassword = userPassword
+	}
+	user := preparePasswordR6(userPassword)
+	owner := preparePasswordR6(ownerPassword)
+
+	// Algorithm 8: U and UE
+	u := make([]byte, 0, 48)
+	u = append(u, computeHashR6(user, userValidationSalt, nil)...)
+	u = append(u, userValidationSalt...)
+	u = append(u, userKeySalt...)
+
+	ue, err := aes256NoPadding(computeHashR6(user, userKeySalt, nil), fileKey, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Algorithm 9: O and OE
+	o := make([]byte, 0, 48)
+	o = append(o, computeHashR6(owner, ownerValidationSalt, u)...)
+	o = append(o, ownerValidationSalt...)
+	o = append(o, ownerKeySalt...)
+
+	oe, err := aes256NoPadding(computeHashR6(owner, ownerKeySalt, u), fileKey, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Algorithm 10: Perms
+	perms, err := encryptPerms(fileKey, permissions, permsRandom)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fileKey, &AES256Values{O: o, U: u, OE: oe, UE: ue, Perms: perms}, nil
+}
+
+// encryptPerms builds the 16-byte Perms value and encrypts it with AES-256-ECB
+func encryptPerms(fileKey []byte, permissions int32, random []byte) ([]byte, error) {
+	plain := make([]byte, 16)
+	binary.LittleEndian.PutUint32(plain[0:4], uint32(permissions))
+	copy(plain[4:8], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	plain[8] = 'T' // EncryptMetadata = true
+	copy(plain[9:12], "adb")
+	copy(plain[12:16], random)
+
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 16)
+	block.Encrypt(out, plain)
+	return out, nil
+}
+
+// AuthenticateAES256 authenticates a password against revision 5/6 values.
+// On success it returns the file encryption key and whether the owner password matched.
+func AuthenticateAES256(password string, revision int, o, u, oe, ue []byte) (fileKey []byte, isOwner bool, ok bool) {
+	if len(o) < 48 || len(u) < 48 {
+		return nil, false, false
+	}
+	pw := preparePasswordR6(password)
+	hashFn := hashForRevision(revision)
+
+	// ユーザーパスワードとして検証（Algorithm 11）
+	if bytes.Equal(hashFn(pw, u[32:40], nil), u[:32]) {
+		key, err := aes256NoPadding(hashFn(pw, u[40:48], nil), ue, true)
+		if err == nil {
+			return key, false, true
+		}
+	}
+
+	// オーナーパスワードとして検証（Algorithm 12）
+	if bytes.Equal(hashFn(pw, o[32:40], u[:48]), o[:32]) {
+		key, err := aes256NoPadding(hashFn(pw, o[40:48], u[:48]), oe, true)
+		if err == nil {
+			return key, true, true
+		}
+	}
+
+	return nil, false, false
+}
+
+// VerifyPerms decrypts the Perms value and checks it against the P entry (Algorithm 13)
+func VerifyPerms(fileKey, perms []byte, permissions int32) bool {
+	if len(perms) < 16 {
+		return false
+	}
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return false
+	}
+	plain := make([]byte, 16)
+	block.Decrypt(plain, perms[:16])
+
+	if string(plain[9:12]) != "adb" {
+		return false
+	}
+	return binary.LittleEndian.Uint32(plain[0:4]) == uint32(permissions)
+}
@@ -0,0 +1,115 @@
+package security
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAuthenticateAES256(t *testing.T) {
+	perms := DefaultPermissions().ToInt32()
+	fileKey, values, err := ComputeAES256Values("user", "owner", perms)
+	if err != nil {
+		t.Fatalf("ComputeAES256Values failed: %v", err)
+	}
+	if len(fileKey) != 32 {
+		t.Fatalf("fileKey length = %d, want 32", len(fileKey))
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		wantOK    bool
+		wantOwner bool
+	}{
+		{"user password", "user", true, false},
+		{"owner password", "owner", true, true},
+		{"wrong password", "wrong", false, false},
+		{"empty password", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, isOwner, ok := AuthenticateAES256(tt.password, 6, values.O, values.U, values.OE, values.UE)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if isOwner != tt.wantOwner {
+				t.Errorf("isOwner = %v, want %v", isOwner, tt.wantOwner)
+			}
+			if !bytes.Equal(key, fileKey) {
+				t.Error("recovered file key does not match")
+			}
+		})
+	}
+}
+
+func TestComputeAES256Values_EmptyOwnerPassword(t *testing.T) {
+	fileKey, values, err := ComputeAES256Values("user", "", 0)
+	if err != nil {
+		t.Fatalf("ComputeAES256Values failed: %v", err)
+	}
+
+	// オーナーパスワードが空の場合はユーザーパスワードで代用される
+	key, _, ok := AuthenticateAES256("user", 6, values.O, values.U, values.OE, values.UE)
+	if !ok || !bytes.Equal(key, fileKey) {
+		t.Error("user password should authenticate")
+	}
+	if _, _, ok := AuthenticateAES256("", 6, values.O, values.U, values.OE, values.UE); ok {
+		t.Error("empty password should not authenticate")
+	}
+}
+
+func TestPreparePasswordR6(t *testing.T) {
+	long := strings.Repeat("a", 200)
+	if got := preparePasswordR6(long); len(got) != 127 {
+		t.Errorf("len = %d, want 127", len(got))
+	}
+	if got := preparePasswordR6("パス"); string(got) != "パス" {
+		t.Errorf("got %q, want UTF-8 bytes", got)
+	}
+}
+
+func TestVerifyPerms(t *testing.T) {
+	perms := PrintOnlyPermissions().ToInt32()
+	fileKey, values, err := ComputeAES256Values("user", "owner", perms)
+	if err != nil {
+		t.Fatalf("ComputeAES256Values failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		key   []byte
+		p     int32
+		perms []byte
+		want  bool
+	}{
+		{"matching permissions", fileKey, perms, values.Perms, true},
+		{"tampered permissions", fileKey, DefaultPermissions().ToInt32(), values.Perms, false},
+		{"wrong key", bytes.Repeat([]byte{1}, 32), perms, values.Perms, false},
+		{"short value", fileKey, perms, values.Perms[:8], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPerms(tt.key, tt.perms, tt.p); got != tt.want {
+				t.Errorf("VerifyPerms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeHashR6_Deterministic(t *testing.T) {
+	salt := []byte("12345678")
+	a := computeHashR6([]byte("password"), salt, nil)
+	b := computeHashR6([]byte("password"), salt, nil)
+	if len(a) != 32 || !bytes.Equal(a, b) {
+		t.Errorf("hash should be a deterministic 32-byte value")
+	}
+	if bytes.Equal(a, computeHashR5([]byte("password"), salt, nil)) {
+		t.Error("R6 hash should differ from the plain SHA-256 R5 hash")
+	}
+}
@@ -0,0 +1,102 @@
+package security
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptDecryptAES(t *testing.T) {
+	key := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"short", []byte("hello")},
+		{"block aligned", bytes.Repeat([]byte("a"), 16)},
+		{"multiple blocks", bytes.Repeat([]byte("PDF"), 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := EncryptAES(tt.data, key)
+			if err != nil {
+				t.Fatalf("EncryptAES() failed: %v", err)
+			}
+			if len(encrypted)%16 != 0 || len(encrypted) < 32 {
+				t.Errorf("encrypted length = %d, want IV + padded blocks", len(encrypted))
+			}
+
+			decrypted, err := DecryptAES(encrypted, key)
+			if err != nil {
+				t.Fatalf("DecryptAES() failed: %v", err)
+			}
+			if !bytes.Equal(decrypted, tt.data) {
+				t.Errorf("DecryptAES() = %q, want %q", decrypted, tt.data)
+			}
+		})
+	}
+}
+
+func TestDecryptAES_Invalid(t *testing.T) {
+	key := make([]byte, 16)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"too short", make([]byte, 16)},
+		{"not block aligned", make([]byte, 33)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecryptAES(tt.data, key); err == nil {
+				t.Error("DecryptAES() should fail")
+			}
+		})
+	}
+}
+
+func TestComputeAESObjectKey(t *testing.T) {
+	key := make([]byte, 16)
+
+	k1 := ComputeAESObjectKey(key, 1, 0)
+	k2 := ComputeAESObjectKey(key, 2, 0)
+	if len(k1) != 16 {
+		t.Errorf("object key length = %d, want 16", len(k1))
+	}
+	if bytes.Equal(k1, k2) {
+		t.Error("different objects should produce different keys")
+	}
+
+	// AESの鍵はRC4の鍵とは異なる（sAlTが付加される）
+	if bytes.Equal(k1, ComputeObjectKey(key, 1, 0, 16)) {
+		t.Error("AES object key should differ from RC4 object key")
+	}
+}
+
+func TestEncryptDecryptData(t *testing.T) {
+	key := make([]byte, 16)
+	data := []byte("stream content")
+
+	for _, alg := range []Algorithm{AlgorithmRC4, AlgorithmAESV2, AlgorithmIdentity} {
+		t.Run(alg.String(), func(t *testing.T) {
+			encrypted, err := EncryptData(alg, data, key, 5, 0, 16)
+			if err != nil {
+				t.Fatalf("EncryptData() failed: %v", err)
+			}
+			decrypted, err := DecryptData(alg, encrypted, key, 5, 0, 16)
+			if err != nil {
+				t.Fatalf("DecryptData() failed: %v", err)
+			}
+			if !bytes.Equal(decrypted, data) {
+				t.Errorf("roundtrip = %q, want %q", decrypted, data)
+			}
+		})
+	}
+}
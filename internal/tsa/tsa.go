@@ -0,0 +1,254 @@
+// Package tsa implements an RFC 3161 Time-Stamp Protocol client.
+package tsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/cms"
+)
+
+// maxResponseSize limits the size of a TSA response
+const maxResponseSize = 1 << 20
+
+// messageImprint is the hash of the timestamped data
+type messageImprint struct {
+	HashAlgorithm pkixAlgorithm
+	HashedMessage []byte
+}
+
+// pkixAlgorithm is an AlgorithmIdentifier
+type pkixAlgorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// timeStampReq is the TimeStampReq structure
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprint
+	Nonce          *big.Int `asn1:"optional"`
+	CertReq        bool     `asn1:"optional,default:false"`
+}
+
+// pkiStatusInfo is the PKIStatusInfo structure
+type pkiStatusInfo struct {
+	Status       int
+	StatusString []asn1.RawValue `asn1:"optional"`
+	FailInfo     asn1.BitString  `asn1:"optional"`
+}
+
+// timeStampResp is the TimeStampResp structure
+type timeStampResp struct {
+	Status         pkiStatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// accuracy is the Accuracy structure of TSTInfo
+type accuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"optional,tag:0"`
+	Micros  int `asn1:"optional,tag:1"`
+}
+
+// tstInfo is the TSTInfo structure (the signed content of a timestamp token)
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time     `asn1:"generalized"`
+	Accuracy       accuracy      `asn1:"optional"`
+	Ordering       bool          `asn1:"optional,default:false"`
+	Nonce          *big.Int      `asn1:"optional"`
+	TSA            asn1.RawValue `asn1:"optional,explicit,tag:0"`
+	Extensions     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// Token is a parsed timestamp token
+type Token struct {
+	GenTime       time.Time         // Time at which the TSA created the token
+	Hash          crypto.Hash       // Hash algorithm of the message imprint
+	HashedMessage []byte            // Hash of the timestamped data
+	Nonce         *big.Int          // Nonce echoed from the request (may be nil)
+	Certificate   *x509.Certificate // TSA signing certificate
+	Certificates  []*x509.Certificate
+
+	signedData *cms.SignedData
+}
+
+// Client requests timestamp tokens from a Time Stamping Authority
+type Client struct {
+	URL        string       // TSA endpoint
+	HTTPClient *http.Client // HTTP client (nil = http.DefaultClient)
+	Username   string       // Optional basic authentication
+	Password   string
+}
+
+// Timestamp requests a token for data whose digest (computed with hash) is given
+func (c *Client) Timestamp(digest []byte, hash crypto.Hash) ([]byte, error) {
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	req, err := NewRequest(digest, hash, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewReader(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TSA request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/timestamp-query")
+	if c.Username != "" {
+		httpReq.SetBasicAuth(c.Username, c.Password)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("TSA request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("TSA returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TSA response: %w", err)
+	}
+
+	token, err := ParseResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 応答が要求したデータとナンスに対するものかを確認する
+	parsed, err := ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsed.VerifyDigest(digest); err != nil {
+		return nil, err
+	}
+	if parsed.Nonce == nil || parsed.Nonce.Cmp(nonce) != 0 {
+		return nil, fmt.Errorf("TSA response nonce does not match the request")
+	}
+
+	return token, nil
+}
+
+// NewRequest builds a DER-encoded TimeStampReq asking for the TSA certificate
+func NewRequest(digest []byte, hash crypto.Hash, nonce *big.Int) ([]byte, error) {
+	oid, err := oidForHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(timeStampReq{
+		Version: 1,
+		MessageImprint: messageImprint{
+			HashAlgorithm: pkixAlgorithm{Algorithm: oid},
+			HashedMessage: digest,
+		},
+		Nonce:   nonce,
+		CertReq: true,
+	})
+}
+
+// ParseResponse extracts the timestamp token from a DER-encoded TimeStampResp
+func ParseResponse(der []byte) ([]byte, error) {
+	var resp timeStampResp
+	if _, err := asn1.Unmarshal(der, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse TSA response: %w", err)
+	}
+
+	// 0 = granted, 1 = grantedWithMods
+	if resp.Status.Status != 0 && resp.Status.Status != 1 {
+		return nil, fmt.Errorf("TSA rejected the request (status %d)", resp.Status.Status)
+	}
+	if len(resp.TimeStampToken.FullBytes) == 0 {
+		return nil, fmt.Errorf("TSA response has no timestamp token")
+	}
+	return resp.TimeStampToken.FullBytes, nil
+}
+
+// ParseToken parses a DER-encoded timestamp token (a SignedData containing TSTInfo)
+func ParseToken(der []byte) (*Token, error) {
+	sd, err := cms.Parse(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp token: %w", err)
+	}
+	if sd.Content == nil {
+		return nil, fmt.Errorf("timestamp token has no TSTInfo")
+	}
+
+	var info tstInfo
+	if _, err := asn1.Unmarshal(sd.Content, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse TSTInfo: %w", err)
+	}
+	hash, err := cms.HashForOID(info.MessageImprint.HashAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		GenTime:       info.GenTime,
+		Hash:          hash,
+		HashedMessage: info.MessageImprint.HashedMessage,
+		Nonce:         info.Nonce,
+		Certificate:   sd.Signer,
+		Certificates:  sd.Certificates,
+		signedData:    sd,
+	}, nil
+}
+
+// VerifyDigest checks that the token is correctly signed and covers the given digest.
+// It does not verify the TSA certificate chain.
+func (t *Token) VerifyDigest(digest []byte) error {
+	if !bytes.Equal(t.HashedMessage, digest) {
+		return fmt.Errorf("timestamp does not match the data")
+	}
+	if err := t.signedData.VerifyDetached(bytes.NewReader(t.signedData.Content)); err != nil {
+		return fmt.Errorf("invalid timestamp token signature: %w", err)
+	}
+	return nil
+}
+
+// VerifyData hashes data with the token's algorithm and checks it with VerifyDigest
+func (t *Token) VerifyData(data io.Reader) error {
+	h := t.Hash.New()
+	if _, err := io.Copy(h, data); err != nil {
+		return fmt.Errorf("failed to read timestamped data: %w", err)
+	}
+	return t.VerifyDigest(h.Sum(nil))
+}
+
+// oidForHash returns the digest algorithm OID for a hash
+func oidForHash(hash crypto.Hash) (asn1.ObjectIdentifier, error) {
+	switch hash {
+	case crypto.SHA1:
+		return cms.OIDSHA1, nil
+	case crypto.SHA256:
+		return cms.OIDSHA256, nil
+	case crypto.SHA384:
+		return cms.OIDSHA384, nil
+	case crypto.SHA512:
+		return cms.OIDSHA512, nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %v", hash)
+	}
+}
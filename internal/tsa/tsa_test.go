@@ -0,0 +1,205 @@
+package tsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/cms"
+)
+
+// testServer is a minimal TSA used by the tests
+type testServer struct {
+	key      crypto.Signer
+	cert     *x509.Certificate
+	genTime  time.Time
+	status   int  // PKIStatus to return
+	badNonce bool // echo a different nonce
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "Test TSA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testServer{key: key, cert: cert, genTime: time.Now().UTC().Truncate(time.Second)}
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req timeStampReq
+	if _, err := asn1.Unmarshal(body, &req); err != nil || r.Header.Get("Content-Type") != "application/timestamp-query" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	resp := timeStampResp{Status: pkiStatusInfo{Status: s.status}}
+	if s.status == 0 {
+		nonce := req.Nonce
+		if s.badNonce {
+			nonce = new(big.Int).Add(nonce, big.NewInt(1))
+		}
+		info, _ := asn1.Marshal(tstInfo{
+			Version:        1,
+			Policy:         asn1.ObjectIdentifier{1, 2, 3, 4},
+			MessageImprint: req.MessageImprint,
+			SerialNumber:   big.NewInt(1),
+			GenTime:        s.genTime,
+			Nonce:          nonce,
+		})
+		token, err := cms.SignAttached(info, cms.OIDTSTInfo, cms.SignOptions{Signer: s.key, Certificate: s.cert})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.TimeStampToken = asn1.RawValue{FullBytes: token}
+	}
+
+	der, _ := asn1.Marshal(resp)
+	w.Header().Set("Content-Type", "application/timestamp-reply")
+	w.Write(der)
+}
+
+func TestClientTimestamp(t *testing.T) {
+	digest := sha256.Sum256([]byte("signature value"))
+
+	tests := []struct {
+		name    string
+		setup   func(s *testServer)
+		handler func(s *testServer) http.Handler
+		wantErr bool
+	}{
+		{
+			name: "granted",
+		},
+		{
+			name:    "rejected",
+			setup:   func(s *testServer) { s.status = 2 },
+			wantErr: true,
+		},
+		{
+			name:    "nonce mismatch",
+			setup:   func(s *testServer) { s.badNonce = true },
+			wantErr: true,
+		},
+		{
+			name: "http error",
+			handler: func(s *testServer) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+			var handler http.Handler = s
+			if tt.handler != nil {
+				handler = tt.handler(s)
+			}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			client := &Client{URL: server.URL}
+			token, err := client.Timestamp(digest[:], crypto.SHA256)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Timestamp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			parsed, err := ParseToken(token)
+			if err != nil {
+				t.Fatalf("ParseToken() failed: %v", err)
+			}
+			if !parsed.GenTime.Equal(s.genTime) {
+				t.Errorf("GenTime = %v, want %v", parsed.GenTime, s.genTime)
+			}
+			if parsed.Hash != crypto.SHA256 {
+				t.Errorf("Hash = %v, want SHA-256", parsed.Hash)
+			}
+			if !parsed.Certificate.Equal(s.cert) {
+				t.Error("Certificate is not the TSA certificate")
+			}
+			if err := parsed.VerifyData(bytes.NewReader([]byte("signature value"))); err != nil {
+				t.Errorf("VerifyData() failed: %v", err)
+			}
+			if err := parsed.VerifyData(bytes.NewReader([]byte("other value"))); err == nil {
+				t.Error("VerifyData() succeeded for different data")
+			}
+		})
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	digest := sha256.Sum256([]byte("data"))
+
+	tests := []struct {
+		name    string
+		hash    crypto.Hash
+		wantErr bool
+	}{
+		{name: "SHA-256", hash: crypto.SHA256},
+		{name: "SHA-512", hash: crypto.SHA512},
+		{name: "unsupported", hash: crypto.MD5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := NewRequest(digest[:], tt.hash, big.NewInt(99))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var req timeStampReq
+			if _, err := asn1.Unmarshal(der, &req); err != nil {
+				t.Fatalf("failed to parse request: %v", err)
+			}
+			if req.Version != 1 || !req.CertReq || req.Nonce.Int64() != 99 {
+				t.Errorf("request = %+v", req)
+			}
+			if !bytes.Equal(req.MessageImprint.HashedMessage, digest[:]) {
+				t.Error("hashed message mismatch")
+			}
+		})
+	}
+}
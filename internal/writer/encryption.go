@@ -13,11 +13,16 @@ type EncryptionInfo struct {
 	UserPassword  string
 	OwnerPassword string
 	Permissions   security.Permissions
-	KeyLength     int // 40 or 128 bits
+	KeyLength     int // 40, 128 or 256 bits
+	Algorithm     security.Algorithm
+	Revision      int // 2 (RC4 40-bit), 3 (RC4 128-bit), 4 (AES-128) or 6 (AES-256)
 	FileID        []byte
 	EncryptionKey []byte
 	OValue        []byte // Owner password string
 	UValue        []byte // User password string
+	OEValue       []byte // Encrypted file key for the owner (AES-256 only)
+	UEValue       []byte // Encrypted file key for the user (AES-256 only)
+	PermsValue    []byte // Encrypted permissions (AES-256 only)
 }
 
 // GenerateFileID generates a random 16-byte file ID
@@ -30,11 +35,37 @@ func GenerateFileID() ([]byte, error) {
 	return fileID, nil
 }
 
-// SetupEncryption initializes encryption parameters and computes O, U values
+// SetupEncryption initializes RC4 encryption parameters and computes O, U values
 func SetupEncryption(userPassword, ownerPassword string, permissions security.Permissions, keyLength int) (*EncryptionInfo, error) {
-	// Validate key length
-	if keyLength != 40 && keyLength != 128 {
-		return nil, fmt.Errorf("key length must be 40 or 128 bits, got %d", keyLength)
+	return SetupEncryptionWithAlgorithm(userPassword, ownerPassword, permissions, keyLength, security.AlgorithmRC4)
+}
+
+// SetupEncryptionWithAlgorithm initializes encryption parameters for the given algorithm.
+// RC4 supports 40 and 128-bit keys; AESV2 requires a 128-bit key and AESV3 a 256-bit key.
+func SetupEncryptionWithAlgorithm(userPassword, ownerPassword string, permissions security.Permissions, keyLength int, alg security.Algorithm) (*EncryptionInfo, error) {
+	// Determine revision based on algorithm and key length
+	var revision int
+	switch alg {
+	case security.AlgorithmRC4:
+		if keyLength != 40 && keyLength != 128 {
+			return nil, fmt.Errorf("key length must be 40 or 128 bits, got %d", keyLength)
+		}
+		revision = 2
+		if keyLength == 128 {
+			revision = 3
+		}
+	case security.AlgorithmAESV2:
+		if keyLength != 128 {
+			return nil, fmt.Errorf("AESV2 requires a 128-bit key, got %d", keyLength)
+		}
+		revision = 4
+	case security.AlgorithmAESV3:
+		if keyLength != 256 {
+			return nil, fmt.Errorf("AESV3 requires a 256-bit key, got %d", keyLength)
+		}
+		return setupAES256(userPassword, ownerPassword, permissions)
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm: %v", alg)
 	}
 
 	// Generate file ID
@@ -43,11 +74,6 @@ func SetupEncryption(userPassword, ownerPassword string, permissions security.Pe
 		return nil, err
 	}
 
-	// Determine revision based on key length
-	revision := 2
-	if keyLength == 128 {
-		revision = 3
-	}
 	keyLengthBytes := keyLength / 8
 
 	// Compute O value (owner password string)
@@ -65,6 +91,8 @@ func SetupEncryption(userPassword, ownerPassword string, permissions security.Pe
 		OwnerPassword: ownerPassword,
 		Permissions:   permissions,
 		KeyLength:     keyLength,
+		Algorithm:     alg,
+		Revision:      revision,
 		FileID:        fileID,
 		EncryptionKey: encryptionKey,
 		OValue:        oValue,
@@ -72,12 +100,49 @@ func SetupEncryption(userPassword, ownerPassword string, permissions security.Pe
 	}, nil
 }
 
+// setupAES256 initializes revision 6 (AES-256) encryption parameters
+func setupAES256(userPassword, ownerPassword string, permissions security.Permissions) (*EncryptionInfo, error) {
+	// The file ID is not part of the key derivation for R6, but the trailer still needs one
+	fileID, err := GenerateFileID()
+	if err != nil {
+		return nil, err
+	}
+
+	fileKey, values, err := security.ComputeAES256Values(userPassword, ownerPassword, permissions.ToInt32())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute AES-256 values: %w", err)
+	}
+
+	return &EncryptionInfo{
+		UserPassword:  userPassword,
+		OwnerPassword: ownerPassword,
+		Permissions:   permissions,
+		KeyLength:     256,
+		Algorithm:     security.AlgorithmAESV3,
+		Revision:      6,
+		FileID:        fileID,
+		EncryptionKey: fileKey,
+		OValue:        values.O,
+		UValue:        values.U,
+		OEValue:       values.OE,
+		UEValue:       values.UE,
+		PermsValue:    values.Perms,
+	}, nil
+}
+
 // CreateEncryptDictionary creates the Encrypt dictionary for the PDF
 func (ei *EncryptionInfo) CreateEncryptDictionary() core.Dictionary {
-	// Determine V and R based on key length
+	// Determine V and R based on algorithm and key length
 	v := 1
 	r := 2
-	if ei.KeyLength == 128 {
+	switch {
+	case ei.Algorithm == security.AlgorithmAESV3:
+		v = 5
+		r = 6
+	case ei.Algorithm == security.AlgorithmAESV2:
+		v = 4
+		r = 4
+	case ei.KeyLength == 128:
 		v = 2
 		r = 3
 	}
@@ -96,6 +161,27 @@ func (ei *EncryptionInfo) CreateEncryptDictionary() core.Dictionary {
 		encryptDict[core.Name("Length")] = core.Integer(ei.KeyLength)
 	}
 
+	// V4 and V5 use crypt filters: strings and streams share the standard filter
+	if v >= 4 {
+		encryptDict[core.Name("CF")] = core.Dictionary{
+			core.Name("StdCF"): core.Dictionary{
+				core.Name("Type"):      core.Name("CryptFilter"),
+				core.Name("CFM"):       core.Name(ei.Algorithm.String()),
+				core.Name("AuthEvent"): core.Name("DocOpen"),
+				core.Name("Length"):    core.Integer(ei.KeyLength / 8),
+			},
+		}
+		encryptDict[core.Name("StmF")] = core.Name("StdCF")
+		encryptDict[core.Name("StrF")] = core.Name("StdCF")
+	}
+
+	// V5 additionally stores the encrypted file keys and permissions
+	if v == 5 {
+		encryptDict[core.Name("OE")] = core.String(ei.OEValue)
+		encryptDict[core.Name("UE")] = core.String(ei.UEValue)
+		encryptDict[core.Name("Perms")] = core.String(ei.PermsValue)
+	}
+
 	return encryptDict
 }
 
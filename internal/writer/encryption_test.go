@@ -3,6 +3,7 @@ package writer
 import (
 	"testing"
 
+	"github.com/ryomak/gopdf/internal/core"
 	"github.com/ryomak/gopdf/internal/security"
 )
 
@@ -183,6 +184,63 @@ func TestCreateEncryptDictionary(t *testing.T) {
 	}
 }
 
+func TestCreateEncryptDictionaryAESV2(t *testing.T) {
+	info, err := SetupEncryptionWithAlgorithm("user", "owner", security.DefaultPermissions(), 128, security.AlgorithmAESV2)
+	if err != nil {
+		t.Fatalf("SetupEncryptionWithAlgorithm failed: %v", err)
+	}
+
+	dict := info.CreateEncryptDictionary()
+	if dict["V"] != core.Integer(4) || dict["R"] != core.Integer(4) {
+		t.Errorf("V/R = %v/%v, want 4/4", dict["V"], dict["R"])
+	}
+	if dict["StmF"] != core.Name("StdCF") || dict["StrF"] != core.Name("StdCF") {
+		t.Errorf("StmF/StrF = %v/%v, want StdCF", dict["StmF"], dict["StrF"])
+	}
+
+	cf, ok := dict["CF"].(core.Dictionary)
+	if !ok {
+		t.Fatal("CF not set in Encrypt dictionary")
+	}
+	stdCF, ok := cf["StdCF"].(core.Dictionary)
+	if !ok || stdCF["CFM"] != core.Name("AESV2") {
+		t.Errorf("StdCF = %v, want CFM AESV2", cf["StdCF"])
+	}
+
+	if _, err := SetupEncryptionWithAlgorithm("user", "owner", security.DefaultPermissions(), 40, security.AlgorithmAESV2); err == nil {
+		t.Error("AESV2 with 40-bit key should fail")
+	}
+}
+
+func TestCreateEncryptDictionaryAESV3(t *testing.T) {
+	info, err := SetupEncryptionWithAlgorithm("user", "owner", security.DefaultPermissions(), 256, security.AlgorithmAESV3)
+	if err != nil {
+		t.Fatalf("SetupEncryptionWithAlgorithm failed: %v", err)
+	}
+	if len(info.EncryptionKey) != 32 {
+		t.Errorf("EncryptionKey length = %d, want 32", len(info.EncryptionKey))
+	}
+
+	dict := info.CreateEncryptDictionary()
+	if dict["V"] != core.Integer(5) || dict["R"] != core.Integer(6) || dict["Length"] != core.Integer(256) {
+		t.Errorf("V/R/Length = %v/%v/%v, want 5/6/256", dict["V"], dict["R"], dict["Length"])
+	}
+	for key, wantLen := range map[core.Name]int{"O": 48, "U": 48, "OE": 32, "UE": 32, "Perms": 16} {
+		if s, ok := dict[key].(core.String); !ok || len(s) != wantLen {
+			t.Errorf("%s length = %d, want %d", key, len(s), wantLen)
+		}
+	}
+	cf, _ := dict["CF"].(core.Dictionary)
+	stdCF, ok := cf["StdCF"].(core.Dictionary)
+	if !ok || stdCF["CFM"] != core.Name("AESV3") || stdCF["Length"] != core.Integer(32) {
+		t.Errorf("StdCF = %v, want CFM AESV3 with Length 32", cf["StdCF"])
+	}
+
+	if _, err := SetupEncryptionWithAlgorithm("user", "owner", security.DefaultPermissions(), 128, security.AlgorithmAESV3); err == nil {
+		t.Error("AESV3 with 128-bit key should fail")
+	}
+}
+
 func TestCreateFileIDArray(t *testing.T) {
 	info, err := SetupEncryption("user", "owner", security.DefaultPermissions(), 40)
 	if err != nil {
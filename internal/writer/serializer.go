@@ -6,6 +6,7 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/ryomak/gopdf/internal/core"
 )
@@ -90,12 +91,14 @@ func (s *Serializer) needsHexEncoding(str string) bool {
 
 // toHexString converts string to hex string format <AABBCC...>
 func (s *Serializer) toHexString(str string) string {
-	result := "<"
-	for _, b := range []byte(str) {
-		result += fmt.Sprintf("%02X", b)
-	}
-	result += ">"
-	return result
+	var b strings.Builder
+	b.Grow(len(str)*2 + 2)
+	b.WriteByte('<')
+	for i := 0; i < len(str); i++ {
+		fmt.Fprintf(&b, "%02X", str[i])
+	}
+	b.WriteByte('>')
+	return b.String()
 }
 
 // escapeString escapes special characters in PDF literal strings
@@ -123,8 +126,18 @@ func (s *Serializer) escapeString(str string) string {
 }
 
 func (s *Serializer) serializeName(v string) error {
-	// TODO: 特殊文字のエスケープ処理
-	return s.writeString("/" + v)
+	// 区切り文字・空白・'#'・非ASCII文字は #XX（16進数）でエスケープする
+	var b strings.Builder
+	b.WriteByte('/')
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c < 0x21 || c > 0x7E || c == '#' || strings.IndexByte("()<>[]{}/%", c) >= 0 {
+			fmt.Fprintf(&b, "#%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return s.writeString(b.String())
 }
 
 func (s *Serializer) serializeArray(arr core.Array) error {
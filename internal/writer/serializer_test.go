@@ -155,6 +155,9 @@ func TestSerializeName(t *testing.T) {
 		{"simple", core.Name("Type"), "/Type"},
 		{"with number", core.Name("F1"), "/F1"},
 		{"camelCase", core.Name("MediaBox"), "/MediaBox"},
+		{"slash", core.Name("text/xml"), "/text#2Fxml"},
+		{"space and hash", core.Name("A B#1"), "/A#20B#231"},
+		{"subset prefix", core.Name("ABCDEF+DejaVuSans"), "/ABCDEF+DejaVuSans"},
 	}
 
 	for _, tt := range tests {
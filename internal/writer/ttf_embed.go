@@ -3,6 +3,7 @@ package writer
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/ryomak/gopdf/internal/core"
@@ -34,7 +35,7 @@ func (e *TTFFontEmbedder) EmbedTTFFont(ttfFont *font.TTFFont, usedGlyphs map[uin
 	}
 
 	// 3. Create CIDFont (DescendantFont)
-	cidFontRef, err := e.createCIDFont(ttfFont, fontDescriptorRef)
+	cidFontRef, err := e.createCIDFont(ttfFont, fontDescriptorRef, usedGlyphs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CIDFont: %w", err)
 	}
@@ -122,7 +123,7 @@ func (e *TTFFontEmbedder) createCIDSystemInfo() core.Dictionary {
 }
 
 // createCIDFont creates a CIDFont (Type 0 descendant font) dictionary
-func (e *TTFFontEmbedder) createCIDFont(ttfFont *font.TTFFont, fontDescriptorRef *core.Reference) (*core.Reference, error) {
+func (e *TTFFontEmbedder) createCIDFont(ttfFont *font.TTFFont, fontDescriptorRef *core.Reference, usedGlyphs map[uint16]rune) (*core.Reference, error) {
 	cidFont := core.Dictionary{
 		core.Name("Type"):           core.Name("Font"),
 		core.Name("Subtype"):        core.Name("CIDFontType2"),
@@ -133,8 +134,11 @@ func (e *TTFFontEmbedder) createCIDFont(ttfFont *font.TTFFont, fontDescriptorRef
 		core.Name("DW"): core.Integer(1000),
 		// CIDToGIDMap is Identity (CID = GID for TrueType-based fonts)
 		core.Name("CIDToGIDMap"): core.Name("Identity"),
-		// W array for character widths - simplified for now
-		// In production, this should contain actual glyph widths
+	}
+	// W array with the widths of the used glyphs, so that proportional glyphs
+	// (such as the Latin letters of a CJK font) are not spaced at the default width
+	if widths := glyphWidths(ttfFont, usedGlyphs); len(widths) > 0 {
+		cidFont[core.Name("W")] = widths
 	}
 
 	objNum, err := e.writer.AddObject(cidFont)
@@ -148,6 +152,26 @@ func (e *TTFFontEmbedder) createCIDFont(ttfFont *font.TTFFont, fontDescriptorRef
 	}, nil
 }
 
+// glyphWidths returns the W array entries (gid [width]) of the used glyphs in glyph space units,
+// sorted by glyph ID.
+func glyphWidths(ttfFont *font.TTFFont, usedGlyphs map[uint16]rune) core.Array {
+	gids := make([]int, 0, len(usedGlyphs))
+	for gid := range usedGlyphs {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var widths core.Array
+	for _, gid := range gids {
+		width, err := ttfFont.GlyphWidth(usedGlyphs[uint16(gid)], 1000)
+		if err != nil {
+			continue
+		}
+		widths = append(widths, core.Integer(gid), core.Array{core.Integer(math.Round(width))})
+	}
+	return widths
+}
+
 // createToUnicodeCMap creates a ToUnicode CMap stream
 func (e *TTFFontEmbedder) createToUnicodeCMap(ttfFont *font.TTFFont, usedGlyphs map[uint16]rune) (*core.Reference, error) {
 	// Create a ToUnicode CMap with glyph-based mapping
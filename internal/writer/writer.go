@@ -3,6 +3,8 @@ package writer
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/ryomak/gopdf/internal/core"
 	"github.com/ryomak/gopdf/internal/security"
@@ -12,10 +14,21 @@ import (
 type Writer struct {
 	w            io.Writer
 	serializer   *Serializer
-	offsets      map[int]int64 // オブジェクト番号 -> ファイル内オフセット
-	nextObjNum   int           // 次のオブジェクト番号
-	bytesWritten int64         // 書き込まれた総バイト数
-	encryption   *EncryptionInfo // 暗号化情報（nil = 暗号化なし）
+	offsets      map[int]int64    // オブジェクト番号 -> ファイル内オフセット
+	nextObjNum   int              // 次のオブジェクト番号
+	bytesWritten int64            // 書き込まれた総バイト数
+	encryption   *EncryptionInfo  // 暗号化情報（nil = 暗号化なし）
+	version      string           // ヘッダーのバージョン（例: "1.7"）
+	base         *IncrementalBase // 追記する既存のファイル（nil = 新しいファイル）
+}
+
+// IncrementalBase describes the existing file that an incremental update is appended to.
+type IncrementalBase struct {
+	FileSize int64 // 既存のファイルのバイト数（追記するセクションの開始位置）
+	Size     int   // 既存のtrailerの/Size（新しいオブジェクトはこの番号から振る）
+	PrevXref int64 // 既存の最新のxrefセクションの位置（trailerの/Prev）
+	// Generation は書き換える既存のオブジェクトの世代番号を返す（nilの場合はすべて0）
+	Generation func(objNum int) int
 }
 
 // NewWriter creates a new PDF Writer.
@@ -27,32 +40,90 @@ func NewWriter(w io.Writer) *Writer {
 		nextObjNum:   1,
 		bytesWritten: 0,
 		encryption:   nil,
+		version:      "1.7",
 	}
 }
 
+// NewIncrementalWriter creates a Writer that appends an incremental update section
+// to an existing file. The existing bytes must already have been written to w.
+// New objects are numbered from base.Size, and existing objects (numbers below
+// base.Size) can be rewritten with WriteObject. WriteTrailer writes a
+// cross-reference section that lists only the written objects and links to the
+// previous one with /Prev. WriteHeader must not be called.
+func NewIncrementalWriter(w io.Writer, base IncrementalBase) *Writer {
+	writer := NewWriter(w)
+	writer.base = &base
+	writer.nextObjNum = max(base.Size, 1)
+	writer.bytesWritten = base.FileSize
+	return writer
+}
+
+// SetVersion sets the version written in the header (e.g. "1.4", "2.0").
+func (w *Writer) SetVersion(version string) {
+	w.version = version
+}
+
 // SetEncryption sets up encryption for the PDF
 func (w *Writer) SetEncryption(encryptionInfo *EncryptionInfo) {
 	w.encryption = encryptionInfo
 }
 
-// WriteHeader writes the PDF header (%PDF-1.7).
+// WriteHeader writes the PDF header (%PDF-1.7 unless SetVersion was called).
 func (w *Writer) WriteHeader() error {
-	header := "%PDF-1.7\n"
+	header := "%PDF-" + w.version + "\n"
 	n, err := io.WriteString(w.w, header)
 	w.bytesWritten += int64(n)
 	return err
 }
 
+// WriteBinaryComment writes the comment line of non-ASCII bytes that follows
+// the header, marking the file as binary for transfer tools (required by PDF/A).
+func (w *Writer) WriteBinaryComment() error {
+	n, err := io.WriteString(w.w, "%\xE2\xE3\xCF\xD3\n")
+	w.bytesWritten += int64(n)
+	return err
+}
+
 // AddObject adds an object to the PDF and returns its object number.
 func (w *Writer) AddObject(obj core.Object) (int, error) {
+	objNum := w.ReserveObject()
+	if err := w.WriteObject(objNum, obj); err != nil {
+		return 0, err
+	}
+	return objNum, nil
+}
+
+// ReserveObject allocates an object number without writing the object.
+// The object must be written later with WriteObject before WriteTrailer is called.
+// This allows forward references (e.g. a page referring to its parent Pages object).
+func (w *Writer) ReserveObject() int {
 	objNum := w.nextObjNum
 	w.nextObjNum++
+	return objNum
+}
 
-	// 暗号化が有効な場合、ストリームオブジェクトを暗号化
-	if w.encryption != nil {
-		if stream, ok := obj.(*core.Stream); ok {
-			obj = w.encryptStream(stream, objNum, 0)
+// WriteObject writes an object using a previously reserved object number.
+func (w *Writer) WriteObject(objNum int, obj core.Object) error {
+	return w.writeObject(objNum, obj, w.encryption != nil)
+}
+
+// writeObject writes an indirect object, encrypting its strings and streams if requested.
+func (w *Writer) writeObject(objNum int, obj core.Object, encrypt bool) error {
+	if objNum <= 0 || objNum >= w.nextObjNum {
+		return fmt.Errorf("object number %d has not been reserved", objNum)
+	}
+	if _, written := w.offsets[objNum]; written {
+		return fmt.Errorf("object %d has already been written", objNum)
+	}
+	generation := w.generation(objNum)
+
+	// 暗号化が有効な場合、文字列とストリームを暗号化
+	if encrypt {
+		encrypted, err := w.encryptObject(obj, objNum, generation)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt object %d: %w", objNum, err)
 		}
+		obj = encrypted
 	}
 
 	// 現在のオフセットを記録
@@ -61,7 +132,7 @@ func (w *Writer) AddObject(obj core.Object) (int, error) {
 	// 間接オブジェクトとして出力
 	indirectObj := &core.IndirectObject{
 		ObjectNumber:     objNum,
-		GenerationNumber: 0,
+		GenerationNumber: generation,
 		Object:           obj,
 	}
 
@@ -71,32 +142,84 @@ func (w *Writer) AddObject(obj core.Object) (int, error) {
 	buf.count = &w.bytesWritten
 
 	tempSerializer := NewSerializer(&buf)
-	if err := tempSerializer.SerializeIndirectObject(indirectObj); err != nil {
-		return 0, err
-	}
+	return tempSerializer.SerializeIndirectObject(indirectObj)
+}
 
-	return objNum, nil
+// generation returns the generation number of an object (non-zero only for
+// existing objects rewritten by an incremental update).
+func (w *Writer) generation(objNum int) int {
+	if w.base == nil || w.base.Generation == nil || objNum >= w.base.Size {
+		return 0
+	}
+	return w.base.Generation(objNum)
 }
 
-// encryptStream encrypts a stream object and returns a new stream with encrypted data
-func (w *Writer) encryptStream(stream *core.Stream, objectNumber, generationNumber int) *core.Stream {
-	// Get key length in bytes
-	keyLengthBytes := w.encryption.KeyLength / 8
+// encryptObject returns a copy of obj with all strings and stream data encrypted
+func (w *Writer) encryptObject(obj core.Object, objectNumber, generationNumber int) (core.Object, error) {
+	switch v := obj.(type) {
+	case core.String:
+		data, err := w.encryptData([]byte(v), objectNumber, generationNumber)
+		if err != nil {
+			return nil, err
+		}
+		return core.String(data), nil
+
+	case core.Array:
+		newArray := make(core.Array, len(v))
+		for i, item := range v {
+			encrypted, err := w.encryptObject(item, objectNumber, generationNumber)
+			if err != nil {
+				return nil, err
+			}
+			newArray[i] = encrypted
+		}
+		return newArray, nil
+
+	case core.Dictionary:
+		newDict := make(core.Dictionary, len(v))
+		for k, item := range v {
+			encrypted, err := w.encryptObject(item, objectNumber, generationNumber)
+			if err != nil {
+				return nil, err
+			}
+			newDict[k] = encrypted
+		}
+		return newDict, nil
 
-	// Encrypt the stream data
-	encryptedData := security.EncryptStream(
-		stream.Data,
+	case *core.Stream:
+		return w.encryptStream(v, objectNumber, generationNumber)
+
+	default:
+		return obj, nil
+	}
+}
+
+// encryptData encrypts raw bytes with the document's algorithm
+func (w *Writer) encryptData(data []byte, objectNumber, generationNumber int) ([]byte, error) {
+	return security.EncryptData(
+		w.encryption.Algorithm,
+		data,
 		w.encryption.EncryptionKey,
 		objectNumber,
 		generationNumber,
-		keyLengthBytes,
+		w.encryption.KeyLength/8,
 	)
+}
+
+// encryptStream encrypts a stream object and returns a new stream with encrypted data
+func (w *Writer) encryptStream(stream *core.Stream, objectNumber, generationNumber int) (*core.Stream, error) {
+	// Encrypt the stream data
+	encryptedData, err := w.encryptData(stream.Data, objectNumber, generationNumber)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create a new stream with encrypted data
-	newDict := make(core.Dictionary)
-	for k, v := range stream.Dict {
-		newDict[k] = v
+	// Create a new stream with encrypted data (strings in the dictionary are encrypted too)
+	dict, err := w.encryptObject(stream.Dict, objectNumber, generationNumber)
+	if err != nil {
+		return nil, err
 	}
+	newDict := dict.(core.Dictionary)
 
 	// Update the Length to match encrypted data length
 	newDict[core.Name("Length")] = core.Integer(len(encryptedData))
@@ -104,7 +227,7 @@ func (w *Writer) encryptStream(stream *core.Stream, objectNumber, generationNumb
 	return &core.Stream{
 		Dict: newDict,
 		Data: encryptedData,
-	}
+	}, nil
 }
 
 // WriteTrailer writes the xref table and trailer.
@@ -112,9 +235,10 @@ func (w *Writer) WriteTrailer(trailer core.Dictionary) error {
 	// 暗号化が有効な場合、Encrypt辞書を追加
 	if w.encryption != nil {
 		// Encrypt辞書をオブジェクトとして追加
+		// Encrypt辞書自体は暗号化しない
 		encryptDict := w.encryption.CreateEncryptDictionary()
-		encryptNum, err := w.AddObject(encryptDict)
-		if err != nil {
+		encryptNum := w.ReserveObject()
+		if err := w.writeObject(encryptNum, encryptDict, false); err != nil {
 			return fmt.Errorf("failed to add Encrypt dictionary: %w", err)
 		}
 
@@ -128,6 +252,21 @@ func (w *Writer) WriteTrailer(trailer core.Dictionary) error {
 		trailer[core.Name("ID")] = w.encryption.CreateFileIDArray()
 	}
 
+	// 予約済みで未出力のオブジェクトがないことを確認
+	first := 1
+	if w.base != nil {
+		first = max(w.base.Size, 1)
+		trailer[core.Name("Prev")] = core.Integer(w.base.PrevXref)
+	}
+	for i := first; i < w.nextObjNum; i++ {
+		if _, ok := w.offsets[i]; !ok {
+			return fmt.Errorf("reserved object %d was never written", i)
+		}
+	}
+
+	// Sizeはxrefテーブルのエントリ数と一致させる
+	trailer[core.Name("Size")] = core.Integer(w.nextObjNum)
+
 	// xrefテーブルの開始位置を記録
 	xrefOffset := w.bytesWritten
 
@@ -152,6 +291,10 @@ func (w *Writer) WriteTrailer(trailer core.Dictionary) error {
 
 // writeXRefTable writes the cross-reference table.
 func (w *Writer) writeXRefTable() error {
+	if w.base != nil {
+		return w.writeIncrementalXRefTable()
+	}
+
 	str := "xref\n"
 	n, err := io.WriteString(w.w, str)
 	w.bytesWritten += int64(n)
@@ -189,6 +332,34 @@ func (w *Writer) writeXRefTable() error {
 	return nil
 }
 
+// writeIncrementalXRefTable writes the cross-reference table of an incremental
+// update, with one subsection for each run of consecutive written objects.
+func (w *Writer) writeIncrementalXRefTable() error {
+	objNums := make([]int, 0, len(w.offsets))
+	for objNum := range w.offsets {
+		objNums = append(objNums, objNum)
+	}
+	sort.Ints(objNums)
+
+	var buf strings.Builder
+	buf.WriteString("xref\n")
+	for start := 0; start < len(objNums); {
+		end := start + 1
+		for end < len(objNums) && objNums[end] == objNums[end-1]+1 {
+			end++
+		}
+		fmt.Fprintf(&buf, "%d %d\n", objNums[start], end-start)
+		for _, objNum := range objNums[start:end] {
+			fmt.Fprintf(&buf, "%010d %05d n \n", w.offsets[objNum], w.generation(objNum))
+		}
+		start = end
+	}
+
+	n, err := io.WriteString(w.w, buf.String())
+	w.bytesWritten += int64(n)
+	return err
+}
+
 // writeTrailerDict writes the trailer dictionary.
 func (w *Writer) writeTrailerDict(trailer core.Dictionary) error {
 	str := "trailer\n"
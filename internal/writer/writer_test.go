@@ -2,6 +2,7 @@ package writer
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -25,6 +26,60 @@ func TestWriterHeader(t *testing.T) {
 	}
 }
 
+// TestWriterSetVersion はヘッダーのバージョン指定をテストする
+func TestWriterSetVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{"PDF 1.4", "1.4", "%PDF-1.4\n"},
+		{"PDF 2.0", "2.0", "%PDF-2.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf)
+			w.SetVersion(tt.version)
+
+			if err := w.WriteHeader(); err != nil {
+				t.Fatalf("WriteHeader() failed: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriterBinaryComment はヘッダー直後のバイナリコメント行をテストする
+func TestWriterBinaryComment(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	if err := w.WriteHeader(); err != nil {
+		t.Fatalf("WriteHeader() failed: %v", err)
+	}
+	if err := w.WriteBinaryComment(); err != nil {
+		t.Fatalf("WriteBinaryComment() failed: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	comment := lines[1]
+	if len(comment) < 5 || comment[0] != '%' {
+		t.Fatalf("second line = %q, want a comment with at least 4 bytes", comment)
+	}
+	for i := 1; i < len(comment); i++ {
+		if comment[i] < 128 {
+			t.Errorf("comment byte %d = %#x, want >= 0x80", i, comment[i])
+		}
+	}
+	if w.bytesWritten != int64(buf.Len()) {
+		t.Errorf("bytesWritten = %d, want %d", w.bytesWritten, buf.Len())
+	}
+}
+
 // TestWriterAddObject はオブジェクトの追加をテストする
 func TestWriterAddObject(t *testing.T) {
 	var buf bytes.Buffer
@@ -210,3 +265,76 @@ func TestObjectOffsets(t *testing.T) {
 		t.Errorf("Offset for object 2 = %d, want %d", w.offsets[2], offset2)
 	}
 }
+
+// TestIncrementalWriter は既存のファイルに追記する増分更新の出力をテストする
+func TestIncrementalWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatalf("WriteHeader() failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := w.AddObject(core.Integer(i)); err != nil {
+			t.Fatalf("AddObject() failed: %v", err)
+		}
+	}
+	if err := w.WriteTrailer(core.Dictionary{core.Name("Root"): &core.Reference{ObjectNumber: 1}}); err != nil {
+		t.Fatalf("WriteTrailer() failed: %v", err)
+	}
+	original := buf.String()
+	prevXref := int64(strings.LastIndex(original, "xref\n"))
+
+	// オブジェクト1（世代番号2）と3を書き換え、新しいオブジェクトを1つ追加する（2は元のまま）
+	iw := NewIncrementalWriter(&buf, IncrementalBase{
+		FileSize: int64(buf.Len()),
+		Size:     4,
+		PrevXref: prevXref,
+		Generation: func(objNum int) int {
+			if objNum == 1 {
+				return 2
+			}
+			return 0
+		},
+	})
+	if err := iw.WriteObject(3, core.Integer(30)); err != nil {
+		t.Fatalf("WriteObject(3) failed: %v", err)
+	}
+	if err := iw.WriteObject(1, core.Integer(10)); err != nil {
+		t.Fatalf("WriteObject(1) failed: %v", err)
+	}
+	newNum, err := iw.AddObject(core.Integer(40))
+	if err != nil {
+		t.Fatalf("AddObject() failed: %v", err)
+	}
+	if newNum != 4 {
+		t.Errorf("new object number = %d, want 4", newNum)
+	}
+	if err := iw.WriteObject(3, core.Integer(31)); err == nil {
+		t.Error("expected error for writing object 3 twice")
+	}
+	if err := iw.WriteTrailer(core.Dictionary{core.Name("Root"): &core.Reference{ObjectNumber: 1}}); err != nil {
+		t.Fatalf("WriteTrailer() failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.HasPrefix(output, original) {
+		t.Fatal("incremental update must not change the original bytes")
+	}
+	update := output[len(original):]
+	offset := func(header string) int {
+		return len(original) + strings.Index(update, header)
+	}
+	wantXref := fmt.Sprintf("xref\n1 1\n%010d 00002 n \n3 2\n%010d 00000 n \n%010d 00000 n \ntrailer\n",
+		offset("1 2 obj"), offset("3 0 obj"), offset("4 0 obj"))
+	if !strings.Contains(update, wantXref) {
+		t.Errorf("update xref = %q, want to contain %q", update, wantXref)
+	}
+	for _, want := range []string{"1 2 obj", "/Prev " + fmt.Sprint(prevXref), "/Size 5"} {
+		if !strings.Contains(update, want) {
+			t.Errorf("update should contain %q", want)
+		}
+	}
+	if got := strings.Count(update, "%%EOF"); got != 1 {
+		t.Errorf("update has %d %%%%EOF markers, want 1", got)
+	}
+}
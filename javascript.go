@@ -0,0 +1,99 @@
+package gopdf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// namedJavaScript はNames/JavaScript名前ツリーに登録されるスクリプト
+type namedJavaScript struct {
+	name   string
+	script string
+}
+
+// AddJavaScript はドキュメントレベルのJavaScriptを追加する
+// スクリプトはドキュメントを開いた際にビューアによって読み込まれ、
+// フォームフィールドのアクションから呼び出す関数の定義などに使用する。
+// 同じ名前で再度追加した場合は上書きされる。
+func (d *Document) AddJavaScript(name, script string) error {
+	if name == "" {
+		return fmt.Errorf("javascript name is required")
+	}
+
+	for i, js := range d.javaScripts {
+		if js.name == name {
+			d.javaScripts[i].script = script
+			return nil
+		}
+	}
+	d.javaScripts = append(d.javaScripts, namedJavaScript{name: name, script: script})
+	return nil
+}
+
+// SetOpenJavaScript はドキュメントを開いたときに一度だけ実行されるJavaScriptを設定する
+// （CatalogのOpenActionとして出力される）。空文字列を指定すると解除される。
+func (d *Document) SetOpenJavaScript(script string) {
+	d.openJavaScript = script
+}
+
+// javaScriptAction はJavaScriptアクション辞書を生成する
+func javaScriptAction(script string) core.Dictionary {
+	return core.Dictionary{
+		core.Name("Type"): core.Name("Action"),
+		core.Name("S"):    core.Name("JavaScript"),
+		core.Name("JS"):   textString(script),
+	}
+}
+
+// addJavaScriptToCatalog はドキュメントレベルのJavaScriptをCatalogに追加する
+func (d *Document) addJavaScriptToCatalog(w *writer.Writer, catalog core.Dictionary) error {
+	if d.openJavaScript != "" {
+		num, err := w.AddObject(javaScriptAction(d.openJavaScript))
+		if err != nil {
+			return err
+		}
+		catalog[core.Name("OpenAction")] = &core.Reference{ObjectNumber: num, GenerationNumber: 0}
+	}
+
+	if len(d.javaScripts) == 0 {
+		return nil
+	}
+
+	// 名前ツリーのキーは昇順である必要がある
+	scripts := make([]namedJavaScript, len(d.javaScripts))
+	copy(scripts, d.javaScripts)
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].name < scripts[j].name
+	})
+
+	names := make(core.Array, 0, len(scripts)*2)
+	for _, js := range scripts {
+		num, err := w.AddObject(javaScriptAction(js.script))
+		if err != nil {
+			return err
+		}
+		names = append(names,
+			textString(js.name),
+			&core.Reference{ObjectNumber: num, GenerationNumber: 0},
+		)
+	}
+
+	namesDict := namesDictionary(catalog)
+	namesDict[core.Name("JavaScript")] = core.Dictionary{
+		core.Name("Names"): names,
+	}
+	return nil
+}
+
+// namesDictionary はCatalogの/Names辞書を取得する（存在しなければ作成する）
+func namesDictionary(catalog core.Dictionary) core.Dictionary {
+	if names, ok := catalog[core.Name("Names")].(core.Dictionary); ok {
+		return names
+	}
+	names := core.Dictionary{}
+	catalog[core.Name("Names")] = names
+	return names
+}
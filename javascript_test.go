@@ -0,0 +1,110 @@
+package gopdf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+func TestDocument_AddJavaScript(t *testing.T) {
+	tests := []struct {
+		name      string
+		scripts   [][2]string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "single script",
+			scripts:   [][2]string{{"init", "var x = 1;"}},
+			wantNames: []string{"init"},
+		},
+		{
+			name:      "sorted names",
+			scripts:   [][2]string{{"b", "1;"}, {"a", "2;"}},
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:      "overwrite same name",
+			scripts:   [][2]string{{"a", "1;"}, {"a", "2;"}},
+			wantNames: []string{"a"},
+		},
+		{
+			name:    "empty name",
+			scripts: [][2]string{{"", "1;"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+
+			var err error
+			for _, s := range tt.scripts {
+				if err = doc.AddJavaScript(s[0], s[1]); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddJavaScript() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			catalog := writeAndReadCatalog(t, doc)
+			names, ok := catalog[core.Name("Names")].(core.Dictionary)
+			if !ok {
+				t.Fatal("Catalog has no Names dictionary")
+			}
+			js, ok := names[core.Name("JavaScript")].(core.Dictionary)
+			if !ok {
+				t.Fatal("Names has no JavaScript tree")
+			}
+			arr := js[core.Name("Names")].(core.Array)
+			if len(arr) != len(tt.wantNames)*2 {
+				t.Fatalf("Names array length = %d, want %d", len(arr), len(tt.wantNames)*2)
+			}
+			for i, want := range tt.wantNames {
+				if got := string(arr[i*2].(core.String)); got != want {
+					t.Errorf("name[%d] = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_SetOpenJavaScript(t *testing.T) {
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	doc.SetOpenJavaScript("app.alert('hello');")
+
+	catalog := writeAndReadCatalog(t, doc)
+	if _, ok := catalog[core.Name("OpenAction")].(*core.Reference); !ok {
+		t.Errorf("OpenAction = %v, want reference", catalog[core.Name("OpenAction")])
+	}
+}
+
+// writeAndReadCatalog はドキュメントを書き出して読み戻し、Catalogを返す
+func writeAndReadCatalog(t *testing.T, doc *Document) core.Dictionary {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+
+	catalog, err := reader.r.GetCatalog()
+	if err != nil {
+		t.Fatalf("GetCatalog() failed: %v", err)
+	}
+	return catalog
+}
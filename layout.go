@@ -1,9 +1,13 @@
 package gopdf
 
 import (
+	"io"
 	"math"
+	"slices"
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ryomak/gopdf/internal/content"
 	"github.com/ryomak/gopdf/internal/core"
@@ -16,24 +20,65 @@ type (
 	ContentBlock            = layout.ContentBlock
 	ContentBlockType        = layout.ContentBlockType
 	PageLayout              = layout.PageLayout
+	PageBoxes               = layout.PageBoxes
 	TextBlock               = layout.TextBlock
+	TextLine                = layout.TextLine
+	TextParagraph           = layout.TextParagraph
 	ImageBlock              = layout.ImageBlock
+	PathBlock               = layout.PathBlock
+	TableBlock              = layout.TableBlock
+	TableRow                = layout.TableRow
+	TableCell               = layout.TableCell
+	VectorBlock             = layout.VectorBlock
+	AnnotationBlock         = layout.AnnotationBlock
+	GroupBlock              = layout.GroupBlock
+	BlockRef                = layout.BlockRef
 	Rectangle               = layout.Rectangle
 	BlockOverlap            = layout.BlockOverlap
 	LayoutStrategy          = layout.LayoutStrategy
 	LayoutAdjustmentOptions = layout.LayoutAdjustmentOptions
+	LayoutJSONOptions       = layout.JSONOptions
+	PageSplitOptions        = layout.PageSplitOptions
+	ColumnOptions           = layout.ColumnOptions
+	BlockAlignment          = layout.BlockAlignment
+	Margins                 = layout.Margins
+	BlockAnchor             = layout.BlockAnchor
+	TextMeasurer            = layout.TextMeasurer
+	SpacingConstraint       = layout.SpacingConstraint
+	LayoutTemplate          = layout.LayoutTemplate
+	TemplateSlot            = layout.TemplateSlot
+	TemplateOptions         = layout.TemplateOptions
+	TextRun                 = layout.TextRun
 )
 
 // 定数エイリアス
 const (
 	ContentBlockTypeText  = layout.ContentBlockTypeText
 	ContentBlockTypeImage = layout.ContentBlockTypeImage
+	ContentBlockTypePath  = layout.ContentBlockTypePath
+	ContentBlockTypeTable = layout.ContentBlockTypeTable
+
+	ContentBlockTypeVector     = layout.ContentBlockTypeVector
+	ContentBlockTypeAnnotation = layout.ContentBlockTypeAnnotation
+	ContentBlockTypeGroup      = layout.ContentBlockTypeGroup
 
 	StrategyPreservePosition = layout.StrategyPreservePosition
 	StrategyCompact          = layout.StrategyCompact
 	StrategyEvenSpacing      = layout.StrategyEvenSpacing
 	StrategyFlowDown         = layout.StrategyFlowDown
 	StrategyFitContent       = layout.StrategyFitContent
+	StrategyColumns          = layout.StrategyColumns
+
+	BlockAlignLeft    = layout.BlockAlignLeft
+	BlockAlignRight   = layout.BlockAlignRight
+	BlockAlignCenterX = layout.BlockAlignCenterX
+	BlockAlignTop     = layout.BlockAlignTop
+	BlockAlignBottom  = layout.BlockAlignBottom
+	BlockAlignCenterY = layout.BlockAlignCenterY
+
+	AnchorNone   = layout.AnchorNone
+	AnchorTop    = layout.AnchorTop
+	AnchorBottom = layout.AnchorBottom
 )
 
 // DefaultLayoutAdjustmentOptions はデフォルトのレイアウト調整オプションを返す
@@ -41,7 +86,14 @@ func DefaultLayoutAdjustmentOptions() LayoutAdjustmentOptions {
 	return layout.DefaultLayoutAdjustmentOptions()
 }
 
+// DecodeLayoutJSON はPageLayout.EncodeJSONで書き出したレイアウトを読み込む
+// 設計書: docs/layout_json_design.md
+func DecodeLayoutJSON(r io.Reader, opts LayoutJSONOptions) (*PageLayout, error) {
+	return layout.DecodeJSON(r, opts)
+}
+
 // ExtractPageLayout はページの完全なレイアウト情報を抽出
+// /Rotateのあるページでは、座標・ページサイズ・読み順を表示される向きで返す
 func (r *PDFReader) ExtractPageLayout(pageNum int) (*PageLayout, error) {
 	// ページを取得
 	page, err := r.r.GetPage(pageNum)
@@ -49,8 +101,9 @@ func (r *PDFReader) ExtractPageLayout(pageNum int) (*PageLayout, error) {
 		return nil, err
 	}
 
-	// ページサイズを取得
+	// ページサイズと境界ボックスを取得
 	width, height := r.getPageSize(page)
+	boxes := r.pageBoxes(page)
 
 	// コンテンツストリームを取得
 	contentsData, err := r.r.GetPageContents(page)
@@ -94,55 +147,83 @@ func (r *PDFReader) ExtractPageLayout(pageNum int) (*PageLayout, error) {
 
 	convertedImageBlocks := convertImageBlocks(imageBlocks)
 
-	// TextElementsをTextBlocksにグループ化（画像を考慮）
-	textBlocks := r.groupTextElementsWithImages(
-		convertTextElements(textElements),
-		convertedImageBlocks,
-	)
-
-	// Y軸が反転している場合、座標を標準座標系に変換
-	if pageCTM != nil && pageCTM.D < 0 {
-		// TextBlocksの座標を変換
-		for i := range textBlocks {
-			// TextBlockのRect座標を変換
-			textBlocks[i].Rect.Y = height - textBlocks[i].Rect.Y - textBlocks[i].Rect.Height
-
-			// 各TextElementの座標も変換
-			for j := range textBlocks[i].Elements {
-				textBlocks[i].Elements[j].Y = height - textBlocks[i].Elements[j].Y
-			}
-		}
-
-		// ImageBlocksの座標を変換
-		for i := range convertedImageBlocks {
-			convertedImageBlocks[i].Y = height - convertedImageBlocks[i].Y - convertedImageBlocks[i].PlacedHeight
+	// 線・矩形・曲線を抽出
+	paths := convertPathBlocks(content.ExtractPaths(operations))
+
+	// ページ上に表示される注釈
+	annotations := r.layoutAnnotations(page)
+
+	// テキスト・画像・パスはどれもCTMを適用したページの座標系で抽出される
+	// （ページレベルのCTMでY軸が反転していても、変換し直す必要はない）
+	elements := convertTextElements(textElements)
+
+	// /Rotateがある場合は、読み順を決める前に表示される向きの座標に変換する
+	rotation := r.pageRotation(page)
+	if rotation != 0 {
+		rotateTextElements(elements, rotation, width, height)
+		rotateImageBlocks(convertedImageBlocks, rotation, width, height)
+		transformPathBlocks(paths, func(x, y float64) (float64, float64) {
+			return rotatePoint(x, y, rotation, width, height)
+		})
+		for i := range annotations {
+			annotations[i].Rect = rotateRect(annotations[i].Rect, rotation, width, height)
 		}
+		boxes = transformPageBoxes(boxes, func(rect Rectangle) Rectangle {
+			return rotateRect(rect, rotation, width, height)
+		})
+		width, height = rotatePageSize(rotation, width, height)
 	}
 
+	// TextElementsをTextBlocksにグループ化（画像を考慮）
+	textBlocks := r.groupTextElementsWithImages(elements, convertedImageBlocks)
+
+	// 罫線と列の揃ったテキストから表を検出
+	tables := detectTables(elements, paths)
+
 	return &PageLayout{
-		PageNum:    pageNum,
-		Width:      width,
-		Height:     height,
-		TextBlocks: textBlocks,
-		Images:     convertedImageBlocks,
-		PageCTM:    pageCTM,
+		PageNum:     pageNum,
+		Width:       width,
+		Height:      height,
+		TextBlocks:  textBlocks,
+		Images:      convertedImageBlocks,
+		Paths:       paths,
+		Tables:      tables,
+		Annotations: annotations,
+		PageCTM:     pageCTM,
+		Rotation:    rotation,
+		Boxes:       boxes,
 	}, nil
 }
 
 // ExtractAllLayouts は全ページのレイアウトを抽出
+// 全ページのレイアウトを同時に保持するため、ページ数の多いPDFではWalkPageLayoutsを使う
 func (r *PDFReader) ExtractAllLayouts() (map[int]*PageLayout, error) {
-	pageCount := r.PageCount()
 	layouts := make(map[int]*PageLayout)
+	err := r.WalkPageLayouts(func(l *PageLayout) error {
+		layouts[l.PageNum] = l
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return layouts, nil
+}
 
+// WalkPageLayouts はページ順にレイアウトを抽出し、1ページずつfnに渡す
+// ページは必要になったときに読み込むため、fnがレイアウトを保持しなければメモリ使用量はページ数によらない
+// fnがエラーを返した場合は走査を中断し、そのエラーを返す
+func (r *PDFReader) WalkPageLayouts(fn func(l *PageLayout) error) error {
+	pageCount := r.PageCount()
 	for i := 0; i < pageCount; i++ {
 		l, err := r.ExtractPageLayout(i)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := fn(l); err != nil {
+			return err
 		}
-		layouts[i] = l
 	}
-
-	return layouts, nil
+	return nil
 }
 
 // getPageSize はページのサイズを取得
@@ -173,17 +254,190 @@ func (r *PDFReader) getPageSize(page core.Dictionary) (width, height float64) {
 	return
 }
 
+// pageBoxes はページの境界ボックスを読む
+// /MediaBoxがない場合はgetPageSizeと同じA4、/CropBoxがない場合は/MediaBox、それ以外のボックスがない場合は/CropBoxを使う
+func (r *PDFReader) pageBoxes(page core.Dictionary) layout.PageBoxes {
+	box := func(key string, fallback Rectangle) Rectangle {
+		rect := r.parseRect(page[core.Name(key)])
+		if rect.Width == 0 || rect.Height == 0 {
+			return fallback
+		}
+		return rect
+	}
+
+	var boxes layout.PageBoxes
+	boxes.MediaBox = box("MediaBox", Rectangle{Width: 595.0, Height: 842.0})
+	boxes.CropBox = box("CropBox", boxes.MediaBox)
+	boxes.BleedBox = box("BleedBox", boxes.CropBox)
+	boxes.TrimBox = box("TrimBox", boxes.CropBox)
+	boxes.ArtBox = box("ArtBox", boxes.CropBox)
+	boxes.Visible = boxes.CropBox.Intersect(boxes.MediaBox)
+	return boxes
+}
+
+// layoutAnnotations はページ上に表示される注釈をAnnotationBlockとして返す
+// リンク（ExtractPageHyperlinksで扱う）とポップアップ（親の注釈を開いたときだけ表示される）、
+// 非表示のフラグ（Hidden、NoView）を持つ注釈は除く
+func (r *PDFReader) layoutAnnotations(page core.Dictionary) []layout.AnnotationBlock {
+	annots, _ := r.r.Resolve(page[core.Name("Annots")]).(core.Array)
+	var blocks []layout.AnnotationBlock
+	for _, item := range annots {
+		dict, ok := r.r.Resolve(item).(core.Dictionary)
+		if !ok {
+			continue
+		}
+		subtype, _ := r.r.Resolve(dict[core.Name("Subtype")]).(core.Name)
+		if subtype == "" || subtype == core.Name(AnnotationTypeLink) || subtype == core.Name(AnnotationTypePopup) {
+			continue
+		}
+		if flags, ok := r.r.Resolve(dict[core.Name("F")]).(core.Integer); ok && flags&(annotFlagHidden|annotFlagNoView) != 0 {
+			continue
+		}
+		rect := r.parseRect(dict[core.Name("Rect")])
+		if rect.Width == 0 && rect.Height == 0 {
+			continue
+		}
+		blocks = append(blocks, layout.AnnotationBlock{
+			Subtype:  string(subtype),
+			Rect:     rect,
+			Contents: rawTextString(r.r.Resolve(dict[core.Name("Contents")])),
+		})
+	}
+	return blocks
+}
+
+// transformPageBoxes は各ボックスをtransformで変換する
+func transformPageBoxes(boxes layout.PageBoxes, transform func(rect Rectangle) Rectangle) layout.PageBoxes {
+	return layout.PageBoxes{
+		MediaBox: transform(boxes.MediaBox),
+		CropBox:  transform(boxes.CropBox),
+		BleedBox: transform(boxes.BleedBox),
+		TrimBox:  transform(boxes.TrimBox),
+		ArtBox:   transform(boxes.ArtBox),
+		Visible:  transform(boxes.Visible),
+	}
+}
+
+// rotateRect は矩形を、時計回りにrotation度回転して表示したときの座標に変換する
+func rotateRect(rect Rectangle, rotation int, width, height float64) Rectangle {
+	// 矩形の対角の2点を変換し、左下と右上を取り直す
+	x1, y1 := rotatePoint(rect.X, rect.Y, rotation, width, height)
+	x2, y2 := rotatePoint(rect.X+rect.Width, rect.Y+rect.Height, rotation, width, height)
+	return Rectangle{X: math.Min(x1, x2), Y: math.Min(y1, y2), Width: math.Abs(x2 - x1), Height: math.Abs(y2 - y1)}
+}
+
+// pageRotation はページの/Rotateを0, 90, 180, 270のいずれかに正規化して返す
+// 90の倍数でない値は無効として0を返す
+func (r *PDFReader) pageRotation(page core.Dictionary) int {
+	rotate, ok := r.r.Resolve(page[core.Name("Rotate")]).(core.Integer)
+	if !ok || rotate%90 != 0 {
+		return 0
+	}
+	return (int(rotate)%360 + 360) % 360
+}
+
+// rotatePoint はページ上の点を、時計回りにrotation度回転して表示したときの座標に変換する
+// width, heightは回転前のページサイズ
+func rotatePoint(x, y float64, rotation int, width, height float64) (float64, float64) {
+	switch rotation {
+	case 90:
+		return y, width - x
+	case 180:
+		return width - x, height - y
+	case 270:
+		return height - y, x
+	default:
+		return x, y
+	}
+}
+
+// rotatePageSize は回転して表示したときのページサイズを返す
+func rotatePageSize(rotation int, width, height float64) (float64, float64) {
+	if rotation == 90 || rotation == 270 {
+		return height, width
+	}
+	return width, height
+}
+
+// rotateTextElements はテキスト要素の位置と向きを表示される向きの座標に変換する
+func rotateTextElements(elements []layout.TextElement, rotation int, width, height float64) {
+	for i := range elements {
+		elements[i].X, elements[i].Y = rotatePoint(elements[i].X, elements[i].Y, rotation, width, height)
+		// ページを時計回りに回すと、ベースラインの向き（反時計回り）はその分だけ小さくなる
+		elements[i].Angle = normalizeAngle(elements[i].Angle - float64(rotation))
+	}
+}
+
+// normalizeAngle は角度（度）を-180より大きく180以下の範囲に正規化する
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 360)
+	if angle > 180 {
+		angle -= 360
+	} else if angle <= -180 {
+		angle += 360
+	}
+	return angle
+}
+
+// rotateImageBlocks は画像の配置矩形を表示される向きの座標に変換する
+// 回転した画像（Angleが0以外）は、原点を変換して向きをページの回転の分だけ戻す
+func rotateImageBlocks(images []layout.ImageBlock, rotation int, width, height float64) {
+	for i := range images {
+		img := &images[i]
+		if img.Angle != 0 {
+			img.X, img.Y = rotatePoint(img.X, img.Y, rotation, width, height)
+			img.Angle = normalizeAngle(img.Angle - float64(rotation))
+			continue
+		}
+		rect := rotateRect(img.Bounds(), rotation, width, height)
+		img.X, img.Y = rect.X, rect.Y
+		img.PlacedWidth, img.PlacedHeight = rect.Width, rect.Height
+	}
+}
+
+// transformPathBlocks はパスの各点をtransformで変換し、バウンディングボックスを取り直す
+func transformPathBlocks(paths []layout.PathBlock, transform func(x, y float64) (float64, float64)) {
+	for i := range paths {
+		path := &paths[i]
+		minX, minY := math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for _, seg := range path.Segments {
+			for j := range seg.Points {
+				p := &seg.Points[j]
+				p.X, p.Y = transform(p.X, p.Y)
+				minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+				minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+			}
+		}
+		if minX <= maxX {
+			path.Rect = Rectangle{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+		}
+	}
+}
+
 // convertTextElements は内部型から公開型に変換
 func convertTextElements(internalElements []content.TextElement) []layout.TextElement {
 	return utils.Map(internalElements, func(elem content.TextElement) layout.TextElement {
+		var baseFont string
+		if elem.Source.Font != nil {
+			baseFont = elem.Source.Font.BaseFont
+		}
 		return layout.TextElement{
 			Text:   elem.Text,
 			X:      elem.X,
 			Y:      elem.Y,
-			Width:  estimateTextWidth(elem.Text, elem.Size, elem.Font),
+			Width:  elem.Width,
 			Height: elem.Size,
 			Font:   elem.Font,
 			Size:   elem.Size,
+
+			BaseFont: baseFont,
+
+			Color:             layout.Color{R: elem.Color[0], G: elem.Color[1], B: elem.Color[2]},
+			RenderMode:        layout.TextRenderMode(elem.RenderMode),
+			HorizontalScaling: elem.HorizontalScaling,
+
+			Angle: elem.Angle,
 		}
 	})
 }
@@ -191,7 +445,7 @@ func convertTextElements(internalElements []content.TextElement) []layout.TextEl
 // convertImageBlocks は内部型から公開型に変換
 func convertImageBlocks(internalBlocks []content.ImageBlock) []layout.ImageBlock {
 	return utils.Map(internalBlocks, func(block content.ImageBlock) layout.ImageBlock {
-		return layout.ImageBlock{
+		img := layout.ImageBlock{
 			ImageInfo: layout.ImageInfo{
 				Name:        block.Name,
 				Width:       block.Width,
@@ -215,6 +469,58 @@ func convertImageBlocks(internalBlocks []content.ImageBlock) []layout.ImageBlock
 				F: block.Transform.F,
 			},
 		}
+		if x, y, width, height, angle, ok := imageRotation(img.Transform); ok {
+			img.X, img.Y, img.PlacedWidth, img.PlacedHeight, img.Angle = x, y, width, height, angle
+		}
+		return img
+	})
+}
+
+// imageRotation はCTMが画像を回転して置く（拡大・縮小と回転だけの）場合に、
+// 回転の原点、回転する前の画像の大きさ、向き（度、反時計回り）を返す
+// 傾き（せん断）や裏返しを含む場合と、向きがrotatedAngleTolerance未満の場合はfalse（画像は囲む矩形で扱う）
+func imageRotation(m layout.Matrix) (x, y, width, height, angle float64, ok bool) {
+	width, height = math.Hypot(m.A, m.B), math.Hypot(m.C, m.D)
+	if width == 0 || height == 0 || m.A*m.D-m.B*m.C <= 0 || math.Abs(m.A*m.C+m.B*m.D) > 1e-6*width*height {
+		return 0, 0, 0, 0, 0, false
+	}
+	angle = math.Atan2(m.B, m.A) * 180 / math.Pi
+	if math.Abs(angle) < rotatedAngleTolerance {
+		return 0, 0, 0, 0, 0, false
+	}
+	return m.E, m.F, width, height, angle, true
+}
+
+// convertPathBlocks は内部型から公開型に変換
+func convertPathBlocks(internalBlocks []content.PathBlock) []layout.PathBlock {
+	return utils.Map(internalBlocks, func(block content.PathBlock) layout.PathBlock {
+		segments := utils.Map(block.Segments, func(seg content.PathSegment) layout.PathSegment {
+			return layout.PathSegment{
+				Op: layout.PathOp(seg.Op),
+				Points: utils.Map(seg.Points, func(p content.Point) layout.Point {
+					return layout.Point{X: p.X, Y: p.Y}
+				}),
+			}
+		})
+		return layout.PathBlock{
+			Kind:        layout.PathKind(block.Kind),
+			Segments:    segments,
+			Rect:        layout.Rectangle{X: block.X, Y: block.Y, Width: block.Width, Height: block.Height},
+			Stroke:      block.Stroke,
+			Fill:        block.Fill,
+			EvenOdd:     block.EvenOdd,
+			StrokeColor: layout.Color{R: block.StrokeColor[0], G: block.StrokeColor[1], B: block.StrokeColor[2]},
+			FillColor:   layout.Color{R: block.FillColor[0], G: block.FillColor[1], B: block.FillColor[2]},
+			LineWidth:   block.LineWidth,
+			Transform: layout.Matrix{
+				A: block.Transform.A,
+				B: block.Transform.B,
+				C: block.Transform.C,
+				D: block.Transform.D,
+				E: block.Transform.E,
+				F: block.Transform.F,
+			},
+		}
 	})
 }
 
@@ -240,51 +546,193 @@ func (r *PDFReader) groupTextElementsWithImages(
 		return nil
 	}
 
+	// 回転したテキストは向きごとに別にまとめる
+	horizontal, rotated := partitionRotatedElements(elements)
+	rotatedBlocks := groupRotatedElements(rotated)
+
 	// 1. 行単位でグルーピング
-	lines := groupElementsByLine(elements)
+	lines := groupElementsByLine(horizontal)
 	if len(lines) == 0 {
-		return nil
+		return rotatedBlocks
 	}
 
 	// 2. 画像のY座標範囲を取得
 	imageRanges := getImageYRanges(images)
 
 	// 3. ブロック単位でグルーピング（画像を考慮）
-	var blocks []layout.TextBlock
-	currentBlock := [][]layout.TextElement{lines[0]}
+	// 段組みでは同じ高さに複数のブロックが並ぶため、直前の行だけでなく
+	// 作成中のすべてのブロックから、直下に続けられるものを探す
+	var blockLines [][][]layout.TextElement
+	for _, line := range lines {
+		best := -1
+		bestSpacing := math.Inf(1)
+		for i, block := range blockLines {
+			lastLine := block[len(block)-1]
+			if !shouldMergeLines(lastLine, line) || !overlapsXRange(lastLine, line) {
+				continue
+			}
+			// 間に画像が挟まっている場合はブロックを分割
+			if hasImageBetween(lastLine, line, imageRanges) {
+				continue
+			}
+			if spacing := minY(lastLine) - maxY(line); spacing < bestSpacing {
+				best, bestSpacing = i, spacing
+			}
+		}
 
-	for i := 1; i < len(lines); i++ {
-		prevLine := lines[i-1]
-		currLine := lines[i]
-
-		// 前の行と現在の行の間に画像があるかチェック
-		hasImage := len(currentBlock) > 0 &&
-			hasImageBetween(currentBlock[len(currentBlock)-1], currLine, imageRanges)
-
-		if hasImage {
-			// 画像が挟まっているのでブロックを分割
-			blocks = append(blocks, createTextBlockFromLines(currentBlock))
-			currentBlock = [][]layout.TextElement{currLine}
-		} else if shouldMergeLines(prevLine, currLine) {
-			// 通常の判定で同じブロックとする
-			currentBlock = append(currentBlock, currLine)
-		} else {
-			// 行間が広いので新しいブロック
-			blocks = append(blocks, createTextBlockFromLines(currentBlock))
-			currentBlock = [][]layout.TextElement{currLine}
+		if best < 0 {
+			// 続けられるブロックがないので新しいブロック
+			blockLines = append(blockLines, [][]layout.TextElement{line})
+			continue
 		}
+		blockLines[best] = append(blockLines[best], line)
 	}
 
-	// 最後のブロックを追加
-	if len(currentBlock) > 0 {
-		blocks = append(blocks, createTextBlockFromLines(currentBlock))
+	blocks := make([]layout.TextBlock, len(blockLines))
+	for i, block := range blockLines {
+		blocks[i] = createTextBlockFromLines(block)
 	}
+	return append(blocks, rotatedBlocks...)
+}
+
+// rotatedAngleTolerance は回転していないとみなすベースラインの傾き（度）
+const rotatedAngleTolerance = 1.0
+
+// partitionRotatedElements は要素を、回転していないもの（左から右へ書くもの）と回転したものに分ける
+func partitionRotatedElements(elements []layout.TextElement) (horizontal, rotated []layout.TextElement) {
+	return utils.Partition(elements, func(elem layout.TextElement) bool {
+		return math.Abs(elem.Angle) < rotatedAngleTolerance
+	})
+}
 
+// groupRotatedElements は回転したテキスト要素を、向き（1度単位）ごとに行にまとめ、1行を1つのブロックにする
+// 縦書きの軸ラベルや斜めのスタンプは段落を作ることがほとんどないため、行より大きくはまとめない
+// 設計書: docs/text_extraction_design.md
+func groupRotatedElements(elements []layout.TextElement) []layout.TextBlock {
+	byAngle := utils.GroupBy(elements, func(elem layout.TextElement) float64 {
+		return math.Round(elem.Angle)
+	})
+	angles := utils.Keys(byAngle)
+	sort.Float64s(angles)
+
+	var blocks []layout.TextBlock
+	for _, angle := range angles {
+		for _, line := range groupRotatedLines(byAngle[angle], angle) {
+			blocks = append(blocks, createRotatedTextBlock(line, angle))
+		}
+	}
 	return blocks
 }
 
+// groupRotatedLines は同じ向きの要素を、回転を戻した座標系で行にまとめる
+// ベースラインに垂直な位置が近い要素を同じ行とし、ベースラインに沿った間隔が段組みの間隔より広いところで分ける
+func groupRotatedLines(elements []layout.TextElement, angle float64) [][]layout.TextElement {
+	local := make([]layout.TextElement, len(elements))
+	for i, elem := range elements {
+		local[i] = toBaselineFrame(elem, angle)
+	}
+
+	// 回転を戻した座標系で上から順に並べ、ベースラインの近い要素をまとめる
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := local[order[i]], local[order[j]]
+		if math.Abs(a.Y-b.Y) < 1.0 {
+			return a.X < b.X
+		}
+		return a.Y > b.Y
+	})
+
+	var lines [][]int
+	for _, idx := range order {
+		if n := len(lines); n > 0 {
+			first := local[lines[n-1][0]]
+			if math.Abs(local[idx].Y-first.Y) < (local[idx].Size+first.Size)/2*0.5 {
+				lines[n-1] = append(lines[n-1], idx)
+				continue
+			}
+		}
+		lines = append(lines, []int{idx})
+	}
+
+	var result [][]layout.TextElement
+	for _, line := range lines {
+		sort.SliceStable(line, func(i, j int) bool { return local[line[i]].X < local[line[j]].X })
+
+		current := []layout.TextElement{elements[line[0]]}
+		right := local[line[0]].X + local[line[0]].Width
+		for _, idx := range line[1:] {
+			elem := local[idx]
+			if elem.X-right > elem.Size*columnGapRatio {
+				result = append(result, current)
+				current = nil
+			}
+			current = append(current, elements[idx])
+			right = math.Max(right, elem.X+elem.Width)
+		}
+		result = append(result, current)
+	}
+	return result
+}
+
+// toBaselineFrame は要素を、ベースラインの向きがX軸になるように回転を戻した座標系に写す
+func toBaselineFrame(elem layout.TextElement, angle float64) layout.TextElement {
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	elem.X, elem.Y = elem.X*cos+elem.Y*sin, -elem.X*sin+elem.Y*cos
+	elem.Angle = 0
+	return elem
+}
+
+// createRotatedTextBlock は回転したテキストの1行からTextBlockを作成
+// テキストの区切りは回転を戻した座標系で判定し、境界は回転した要素を囲む矩形とする
+func createRotatedTextBlock(line []layout.TextElement, angle float64) layout.TextBlock {
+	local := make([]layout.TextElement, len(line))
+	for i, elem := range line {
+		local[i] = toBaselineFrame(elem, angle)
+	}
+
+	textLine := newTextLine(line)
+	textLine.Text = combineLineText(local)
+	lines := []layout.TextLine{textLine}
+
+	return layout.TextBlock{
+		Text:     textLine.Text,
+		Elements: slices.Clone(line),
+		Rect:     textLine.Rect,
+		Font:     line[0].Font,
+		FontSize: textLine.FontSize,
+		Color:    line[0].Color,
+
+		RenderMode:        line[0].RenderMode,
+		HorizontalScaling: line[0].HorizontalScaling,
+
+		Lines:      lines,
+		Paragraphs: segmentParagraphs(lines),
+		Angle:      line[0].Angle,
+	}
+}
+
+// columnGapRatio は同じベースライン上の要素を別の行に分ける横方向の間隔（フォントサイズに対する比）
+// 両端揃えで広がった語間でも1em程度なので、それより十分広い間隔を段組みや表の列の境界とみなす
+const columnGapRatio = 2.0
+
 // groupElementsByLine は要素を行単位でグルーピング
+// ベースラインが近い要素をまとめたうえで、段組みの間隔で左右に分ける
+// 設計書: docs/text_block_grouping_design.md
 func groupElementsByLine(elements []layout.TextElement) [][]layout.TextElement {
+	var lines [][]layout.TextElement
+	for _, cluster := range clusterByBaseline(elements) {
+		lines = append(lines, splitLineAtColumnGaps(cluster)...)
+	}
+	return lines
+}
+
+// clusterByBaseline は要素をベースラインでまとめ、上から順に返す
+// 前の要素ではなく行の平均ベースラインと比べ、少しずつずれた要素が連鎖して1行にならないようにする
+func clusterByBaseline(elements []layout.TextElement) [][]layout.TextElement {
 	if len(elements) == 0 {
 		return nil
 	}
@@ -300,34 +748,59 @@ func groupElementsByLine(elements []layout.TextElement) [][]layout.TextElement {
 		return sorted[i].Y > sorted[j].Y
 	})
 
-	var lines [][]layout.TextElement
+	var clusters [][]layout.TextElement
 	currentLine := []layout.TextElement{sorted[0]}
+	baseline, size := sorted[0].Y, sorted[0].Size
 
-	for i := 1; i < len(sorted); i++ {
-		elem := sorted[i]
-		prevElem := sorted[i-1]
-
-		// Y座標の差を計算
-		yDiff := math.Abs(elem.Y - prevElem.Y)
-		// フォントサイズの平均
-		avgSize := (elem.Size + prevElem.Size) / 2
+	for _, elem := range sorted[1:] {
 		// 同じ行の閾値: フォントサイズの50%
-		lineThreshold := avgSize * 0.5
+		lineThreshold := (elem.Size + size) / 2 * 0.5
 
-		if yDiff < lineThreshold {
+		if math.Abs(elem.Y-baseline) < lineThreshold {
 			// 同じ行
 			currentLine = append(currentLine, elem)
+			baseline = avgBaseline(currentLine)
+			size = avgFontSize(currentLine)
 		} else {
 			// 新しい行
-			lines = append(lines, currentLine)
+			clusters = append(clusters, currentLine)
 			currentLine = []layout.TextElement{elem}
+			baseline, size = elem.Y, elem.Size
 		}
 	}
 
 	// 最後の行を追加
-	lines = append(lines, currentLine)
+	return append(clusters, currentLine)
+}
 
-	return lines
+// splitLineAtColumnGaps は同じベースライン上の要素を、広い間隔のところで別の行に分ける
+func splitLineAtColumnGaps(line []layout.TextElement) [][]layout.TextElement {
+	sort.SliceStable(line, func(i, j int) bool {
+		return line[i].X < line[j].X
+	})
+
+	var result [][]layout.TextElement
+	start := 0
+	right := line[0].X + line[0].Width
+
+	for i := 1; i < len(line); i++ {
+		elem := line[i]
+		gap := elem.X - right
+		if gap > math.Max(elem.Size, line[i-1].Size)*columnGapRatio {
+			result = append(result, line[start:i])
+			start = i
+			right = elem.X + elem.Width
+			continue
+		}
+		right = math.Max(right, elem.X+elem.Width)
+	}
+
+	return append(result, line[start:])
+}
+
+// overlapsXRange は2つの行の横方向の範囲が重なっているかチェック
+func overlapsXRange(line1, line2 []layout.TextElement) bool {
+	return minX(line1) <= maxX(line2) && minX(line2) <= maxX(line1)
 }
 
 // shouldMergeLines は2つの行を同じブロックにマージするべきか判定
@@ -391,6 +864,11 @@ func createTextBlockFromLines(lines [][]layout.TextElement) layout.TextBlock {
 	// テキストを結合（行間に改行を入れる）
 	text := combineBlockText(lines)
 
+	textLines := make([]layout.TextLine, len(lines))
+	for i, line := range lines {
+		textLines[i] = newTextLine(line)
+	}
+
 	return layout.TextBlock{
 		Text:     text,
 		Elements: allElements,
@@ -402,8 +880,38 @@ func createTextBlockFromLines(lines [][]layout.TextElement) layout.TextBlock {
 		},
 		Font:     allElements[0].Font,
 		FontSize: avgSize,
-		Color:    layout.Color{R: 0, G: 0, B: 0},
+		Color:    allElements[0].Color,
+
+		RenderMode:        allElements[0].RenderMode,
+		HorizontalScaling: allElements[0].HorizontalScaling,
+
+		Lines:      textLines,
+		Paragraphs: segmentParagraphs(textLines),
+		Runs:       blockRuns(lines),
+	}
+}
+
+// blockRuns はブロックのテキストをスタイルごとのTextRunに分ける（combineBlockTextと同じ区切り）
+// スタイルが1つしかない場合はnil
+func blockRuns(lines [][]layout.TextElement) []layout.TextRun {
+	var runs []layout.TextRun
+	for i, line := range lines {
+		for j, elem := range line {
+			run := layout.NewTextRun(elem)
+			run.Text = utils.CleanControlCharacters(elem.Text)
+			switch {
+			case j > 0:
+				run.Text = elementSeparator(line[j-1], elem) + run.Text
+			case i > 0:
+				run.Text = "\n" + run.Text
+			}
+			runs = layout.AppendRun(runs, run)
+		}
 	}
+	if len(runs) < 2 {
+		return nil
+	}
+	return runs
 }
 
 // combineBlockText はブロック内のテキストを結合（行間に改行を保持）
@@ -414,32 +922,184 @@ func combineBlockText(lines [][]layout.TextElement) string {
 		if i > 0 {
 			result.WriteString("\n") // 行間は改行
 		}
+		result.WriteString(combineLineText(line))
+	}
 
-		// 行内のテキストを結合（要素間の距離を考慮）
-		for j, elem := range line {
-			if j > 0 {
-				// 前の要素との距離を計算
-				prevElem := line[j-1]
-				gap := elem.X - (prevElem.X + prevElem.Width)
-
-				// 距離の閾値: フォントサイズの35%
-				// これより大きい場合はスペースを入れる
-				// 文字間のカーニングを考慮しつつ、単語間は分離
-				threshold := prevElem.Size * 0.35
-
-				if gap > threshold {
-					result.WriteString(" ")
-				}
+	return result.String()
+}
+
+// combineLineText は行内のテキストを結合（要素間の距離を考慮）
+func combineLineText(line []layout.TextElement) string {
+	var result strings.Builder
+
+	for j, elem := range line {
+		if j > 0 {
+			result.WriteString(elementSeparator(line[j-1], elem))
+		}
+		// 制御文字をクリーンアップしてから追加
+		cleanText := utils.CleanControlCharacters(elem.Text)
+		result.WriteString(cleanText)
+	}
+
+	return result.String()
+}
+
+// elementSeparator は行内の前の要素prevとelemの間に入れる文字（スペースまたは空）
+func elementSeparator(prev, elem layout.TextElement) string {
+	gap := elem.X - (prev.X + prev.Width)
+
+	// 距離の閾値: フォントサイズの35%
+	// これより大きい場合はスペースを入れる
+	// 文字間のカーニングを考慮しつつ、単語間は分離
+	if gap > prev.Size*0.35 {
+		return " "
+	}
+	return ""
+}
+
+// newTextLine は行の要素からTextLineを作成
+func newTextLine(elements []layout.TextElement) layout.TextLine {
+	return layout.TextLine{
+		Text:     combineLineText(elements),
+		Elements: elements,
+		Rect:     textElementsBounds(elements),
+		Baseline: avgBaseline(elements),
+		FontSize: avgFontSize(elements),
+	}
+}
+
+// 段落の区切りを判定する閾値
+const (
+	// paragraphSpacingRatio は段落の区切りとみなす行送り（ブロック内の代表的な行送りに対する比）
+	paragraphSpacingRatio = 1.3
+	// paragraphIndentRatio は段落の1行目とみなす字下げ（フォントサイズに対する比）
+	paragraphIndentRatio = 0.5
+	// paragraphFontSizeTolerance は同じ段落とみなすフォントサイズの差（ポイント）
+	paragraphFontSizeTolerance = 1.0
+)
+
+// segmentParagraphs はブロック内の行を段落に分ける
+// 行送りはブロックごとに異なるため、固定の閾値ではなくブロック内の行送りの中央値と比べる
+// 設計書: docs/text_block_grouping_design.md
+func segmentParagraphs(lines []layout.TextLine) []layout.TextParagraph {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	leading := medianLeading(lines)
+	left := lines[0].Rect.X
+	for _, line := range lines[1:] {
+		left = math.Min(left, line.Rect.X)
+	}
+
+	var paragraphs []layout.TextParagraph
+	start := 0
+	for i := 1; i < len(lines); i++ {
+		if isParagraphStart(lines[i-1], lines[i], leading, left) {
+			paragraphs = append(paragraphs, newTextParagraph(lines[start:i]))
+			start = i
+		}
+	}
+
+	return append(paragraphs, newTextParagraph(lines[start:]))
+}
+
+// isParagraphStart は行が新しい段落の1行目か判定
+// leadingはブロック内の代表的な行送り、leftはブロックの左端
+func isParagraphStart(prev, curr layout.TextLine, leading, left float64) bool {
+	// フォントサイズが変わる（見出しと本文など）
+	if math.Abs(prev.FontSize-curr.FontSize) > paragraphFontSizeTolerance {
+		return true
+	}
+
+	// 行送りが代表的な行送りより広い（段落間のアキ）
+	if leading > 0 && math.Abs(prev.Baseline-curr.Baseline) > leading*paragraphSpacingRatio {
+		return true
+	}
+
+	// 左端から始まる行の次に、字下げされた行が来る
+	indent := curr.FontSize * paragraphIndentRatio
+	return curr.Rect.X-left >= indent && prev.Rect.X-left < indent
+}
+
+// medianLeading は隣り合う行のベースラインの間隔の中央値を返す（偶数個の場合は小さい方）
+// 行が1つの場合は0
+func medianLeading(lines []layout.TextLine) float64 {
+	if len(lines) < 2 {
+		return 0
+	}
+
+	spacings := make([]float64, len(lines)-1)
+	for i := 1; i < len(lines); i++ {
+		spacings[i-1] = math.Abs(lines[i-1].Baseline - lines[i].Baseline)
+	}
+	sort.Float64s(spacings)
+
+	return spacings[(len(spacings)-1)/2]
+}
+
+// newTextParagraph は行のリストからTextParagraphを作成
+// 行はTextBlock.Linesと共有しないようにコピーする（座標の変換が二重にかからないように）
+func newTextParagraph(lines []layout.TextLine) layout.TextParagraph {
+	lines = slices.Clone(lines)
+	for i := range lines {
+		lines[i].Elements = slices.Clone(lines[i].Elements)
+	}
+
+	paragraph := layout.TextParagraph{
+		Text:  joinParagraphLines(lines),
+		Lines: lines,
+		Rect:  lines[0].Rect,
+	}
+
+	for _, line := range lines[1:] {
+		paragraph.Rect = paragraph.Rect.Union(line.Rect)
+	}
+	if len(lines) > 1 {
+		left := lines[1].Rect.X
+		for _, line := range lines[2:] {
+			left = math.Min(left, line.Rect.X)
+		}
+		paragraph.Indent = lines[0].Rect.X - left
+	}
+
+	return paragraph
+}
+
+// joinParagraphLines は段落の行を1つのテキストに連結する
+// 行の境目には空白を入れるが、日本語・中国語の文字どうしの間には入れない
+func joinParagraphLines(lines []layout.TextLine) string {
+	var result strings.Builder
+
+	for i, line := range lines {
+		if i > 0 && result.Len() > 0 && line.Text != "" {
+			prev, _ := utf8.DecodeLastRuneInString(result.String())
+			next, _ := utf8.DecodeRuneInString(line.Text)
+			if !isCJKRune(prev) || !isCJKRune(next) {
+				result.WriteString(" ")
 			}
-			// 制御文字をクリーンアップしてから追加
-			cleanText := utils.CleanControlCharacters(elem.Text)
-			result.WriteString(cleanText)
 		}
+		result.WriteString(line.Text)
 	}
 
 	return result.String()
 }
 
+// isCJKRune は漢字・ひらがな・カタカナ・全角記号か判定
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// textElementsBounds は要素全体のバウンディングボックスを返す（回転した要素は回転した矩形を囲む）
+func textElementsBounds(elements []layout.TextElement) layout.Rectangle {
+	rect := elements[0].Bounds()
+	for _, elem := range elements[1:] {
+		rect = rect.Union(elem.Bounds())
+	}
+	return rect
+}
+
 // ヘルパー関数
 func minY(elements []layout.TextElement) float64 {
 	if len(elements) == 0 {
@@ -480,6 +1140,30 @@ func minX(elements []layout.TextElement) float64 {
 	return min
 }
 
+func maxX(elements []layout.TextElement) float64 {
+	if len(elements) == 0 {
+		return 0
+	}
+	max := elements[0].X + elements[0].Width
+	for _, e := range elements[1:] {
+		if e.X+e.Width > max {
+			max = e.X + e.Width
+		}
+	}
+	return max
+}
+
+func avgBaseline(elements []layout.TextElement) float64 {
+	if len(elements) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, e := range elements {
+		sum += e.Y
+	}
+	return sum / float64(len(elements))
+}
+
 func avgFontSize(elements []layout.TextElement) float64 {
 	if len(elements) == 0 {
 		return 0
@@ -539,7 +1223,10 @@ func createTextBlock(elements []layout.TextElement) layout.TextBlock {
 		},
 		Font:     elements[0].Font,
 		FontSize: avgSize,
-		Color:    layout.Color{R: 0, G: 0, B: 0}, // デフォルト黒
+		Color:    elements[0].Color,
+
+		RenderMode:        elements[0].RenderMode,
+		HorizontalScaling: elements[0].HorizontalScaling,
 	}
 }
 
@@ -559,6 +1246,10 @@ func toFloat64(obj core.Object) float64 {
 func AdjustLayout(pl *PageLayout, opts LayoutAdjustmentOptions) error {
 	// StrategyFitContent以外は layout パッケージの実装を使用
 	if opts.Strategy != StrategyFitContent {
+		// StrategyColumns はテキストを段の幅で折り返して高さを計る
+		if opts.Strategy == StrategyColumns && opts.TextHeight == nil {
+			opts.TextHeight = wrappedTextHeight
+		}
 		return pl.AdjustLayout(opts)
 	}
 
@@ -566,7 +1257,33 @@ func AdjustLayout(pl *PageLayout, opts LayoutAdjustmentOptions) error {
 	return adjustLayoutFitContent(pl, opts)
 }
 
+// wrappedTextHeight はテキストブロックを幅widthで折り返したときの高さを返す（行の高さはフォントサイズの1.2倍）
+func wrappedTextHeight(tb TextBlock, width float64) float64 {
+	fontName := tb.Font
+	if fontName == "" {
+		fontName = "Helvetica"
+	}
+	return float64(len(wrapText(tb.Text, width, fontName, tb.FontSize))) * tb.FontSize * 1.2
+}
+
+// textLinesFit はテキストブロックの行（Textの改行で区切ったもの）が、折り返さずにブロックに収まるかを返す
+// 高さは1行目の上端から最後の行のベースラインまで（行の間隔はフォントサイズのReflowLineSpacing倍）
+func textLinesFit(tb TextBlock, font TextMeasurer) bool {
+	lines := strings.Split(tb.Text, "\n")
+	if tb.FontSize+float64(len(lines)-1)*tb.FontSize*layout.ReflowLineSpacing > tb.Rect.Height {
+		return false
+	}
+	for _, line := range lines {
+		width, err := font.TextWidth(line, tb.FontSize)
+		if err != nil || width > tb.Rect.Width {
+			return false
+		}
+	}
+	return true
+}
+
 // adjustLayoutFitContent はブロックサイズを変えず、コンテンツをブロックに収める
+// 収まらないテキストは折り返し（TextBlock.Reflow）、それでも収まらない場合はフォントサイズを小さくする
 func adjustLayoutFitContent(pl *PageLayout, opts LayoutAdjustmentOptions) error {
 	// TextBlocksを調整
 	for i := range pl.TextBlocks {
@@ -583,41 +1300,46 @@ func adjustLayoutFitContent(pl *PageLayout, opts LayoutAdjustmentOptions) error
 			fontName = "Helvetica"
 		}
 
-		// 現在のフォントサイズで収まるかチェック
-		// wrapTextで改行してから行数をカウント
-		wrapped := wrapText(block.Text, block.Rect.Width, fontName, block.FontSize)
-		lineHeight := block.FontSize * 1.2
-		currentHeight := float64(len(wrapped)) * lineHeight
-
-		// 収まる場合はフォントサイズを変更しない
-		if currentHeight <= block.Rect.Height {
+		// 書かれた行のまま収まる場合は変更しない
+		font := StandardFont(fontName)
+		if block.Angle != 0 || block.FontSize <= 0 || textLinesFit(*block, font) {
 			continue
 		}
 
-		// 収まらない場合のみフィット
-		result, err := FitText(
-			block.Text,
-			block.Rect,
-			fontName,
-			FitTextOptions{
-				MaxFontSize: block.FontSize, // 現在のフォントサイズを最大とする
-				MinFontSize: 6.0,
-				LineSpacing: 1.2,
-				Padding:     0,
-				AllowShrink: true,
-				AllowGrow:   false, // 拡大は許可しない
-			},
-		)
-
-		// エラーが発生した場合は元のフォントサイズを維持
-		if err != nil {
+		// 収まらない場合は、まず今のフォントサイズのまま折り返す
+		reflowed := *block
+		if err := reflowed.Reflow(font, block.FontSize, block.Rect.Width); err != nil {
 			continue
 		}
 
-		// フォントサイズを更新（元より小さい場合のみ）
-		if result.FontSize < block.FontSize {
-			block.FontSize = result.FontSize
+		// 折り返しても収まらない場合だけ、フォントサイズを小さくしてから折り返す
+		if reflowed.Rect.Height > block.Rect.Height {
+			result, err := FitText(
+				block.Text,
+				block.Rect,
+				fontName,
+				FitTextOptions{
+					MaxFontSize: block.FontSize, // 現在のフォントサイズを最大とする
+					MinFontSize: 6.0,
+					LineSpacing: layout.ReflowLineSpacing,
+					Padding:     0,
+					AllowShrink: true,
+					AllowGrow:   false, // 拡大は許可しない
+				},
+			)
+
+			// エラーが発生した場合は元のフォントサイズのまま折り返す
+			if err == nil && result.FontSize < block.FontSize {
+				reflowed = *block
+				if err := reflowed.Reflow(font, result.FontSize, block.Rect.Width); err != nil {
+					continue
+				}
+			}
 		}
+
+		// ブロックの矩形は変えない（行は上端から組まれている）
+		reflowed.Rect = block.Rect
+		*block = reflowed
 	}
 
 	// ImageBlocksを調整（ブロックサイズがないので、最大サイズを制限する場合のみ）
@@ -637,8 +1359,8 @@ func getImageYRanges(images []layout.ImageBlock) []YRange {
 	ranges := make([]YRange, len(images))
 	for i, img := range images {
 		ranges[i] = YRange{
-			Min: img.Y,
-			Max: img.Y + img.PlacedHeight,
+			Min: img.Bounds().Y,
+			Max: img.Bounds().Y + img.Bounds().Height,
 		}
 	}
 	return ranges
@@ -745,6 +1467,8 @@ func mergeContentBlocksAcrossPages(pageBlocks map[int][]layout.ContentBlock) []l
 				// 前のブロックと統合可能
 				currentTextBlock.Text += "\n" + tb.Text
 				currentTextBlock.Elements = append(currentTextBlock.Elements, tb.Elements...)
+				currentTextBlock.Lines = append(currentTextBlock.Lines, tb.Lines...)
+				currentTextBlock.Paragraphs = append(currentTextBlock.Paragraphs, tb.Paragraphs...)
 				// 境界を拡張
 				updateTextBlockBounds(currentTextBlock, tb)
 			} else {
@@ -760,6 +1484,9 @@ func mergeContentBlocksAcrossPages(pageBlocks map[int][]layout.ContentBlock) []l
 				currentTextBlock = nil
 			}
 			merged = append(merged, block)
+
+		default:
+			// 線・図と注釈はヘッダー・フッターの罫線などでテキストの統合を妨げるため、統合した結果には含めない
 		}
 	}
 
@@ -13,6 +13,8 @@ func (pl *PageLayout) AdjustLayout(opts LayoutAdjustmentOptions) error {
 		return pl.adjustLayoutEvenSpacing(opts)
 	case StrategyFitContent:
 		return pl.adjustLayoutFitContent(opts)
+	case StrategyColumns:
+		return pl.adjustLayoutColumns(opts)
 	case StrategyPreservePosition:
 		// 位置を保持するので何もしない
 		return nil
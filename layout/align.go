@@ -0,0 +1,194 @@
+package layout
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BlockAlignment はAlignBlocksの揃え方
+type BlockAlignment int
+
+const (
+	BlockAlignLeft    BlockAlignment = iota // 左端を揃える
+	BlockAlignRight                         // 右端を揃える
+	BlockAlignCenterX                       // 左右の中央を揃える
+	BlockAlignTop                           // 上端を揃える
+	BlockAlignBottom                        // 下端を揃える
+	BlockAlignCenterY                       // 上下の中央を揃える
+)
+
+// AlignBlocks はブロックを、選択したブロック全体のバウンディングボックスの端または中央に揃える
+// refsにはテキストブロック・画像ブロックとグループ（ContentBlockTypeGroup）を指定でき、2つ以上必要
+// 揃える方向以外の位置と、ブロックの大きさは変えない
+// 設計書: docs/layout_auto_adjustment_design.md
+func (pl *PageLayout) AlignBlocks(refs []BlockRef, alignment BlockAlignment) error {
+	if len(refs) < 2 {
+		return fmt.Errorf("aligning needs at least 2 blocks, got %d", len(refs))
+	}
+	rects, bounds, err := pl.selectionRects(refs)
+	if err != nil {
+		return err
+	}
+
+	for i, ref := range refs {
+		r := rects[i]
+		var offsetX, offsetY float64
+		switch alignment {
+		case BlockAlignLeft:
+			offsetX = bounds.X - r.X
+		case BlockAlignRight:
+			offsetX = (bounds.X + bounds.Width) - (r.X + r.Width)
+		case BlockAlignCenterX:
+			offsetX = (bounds.X + bounds.Width/2) - (r.X + r.Width/2)
+		case BlockAlignTop:
+			offsetY = (bounds.Y + bounds.Height) - (r.Y + r.Height)
+		case BlockAlignBottom:
+			offsetY = bounds.Y - r.Y
+		case BlockAlignCenterY:
+			offsetY = (bounds.Y + bounds.Height/2) - (r.Y + r.Height/2)
+		default:
+			return fmt.Errorf("unsupported alignment: %d", alignment)
+		}
+		if err := pl.MoveBlock(ref.Type, ref.Index, offsetX, offsetY); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MirrorHorizontally はテキストブロック・画像ブロックを、ページ（表示される範囲）の左右の中心で反転した位置に移す
+// 右から左に読む言語（アラビア語・ヘブライ語）に翻訳したページで、段組みや図の左右を入れ替えるために使う
+// ブロックの大きさと中身（テキスト・画像の向き）は変えない。グループの矩形は移したメンバーから計算し直す
+// 線・矩形（Paths）・表（Tables）・注釈は動かさない
+// 設計書: docs/pdf_translation_design.md
+func (pl *PageLayout) MirrorHorizontally() {
+	left, width := 0.0, pl.Width
+	if visible := pl.Boxes.Visible; visible.Width > 0 {
+		left, width = visible.X, visible.Width
+	}
+	mirror := func(r Rectangle) float64 {
+		return (2*left + width - r.Width) - 2*r.X
+	}
+	for i := range pl.TextBlocks {
+		pl.TextBlocks[i].Rect.X += mirror(pl.TextBlocks[i].Bounds())
+	}
+	for i := range pl.Images {
+		pl.Images[i].X += mirror(pl.Images[i].Bounds())
+	}
+	pl.refreshGroupRects()
+}
+
+// DistributeVertically はブロックの間隔が等しくなるように縦に並べ直す
+// 上端が最も上のブロックと下端が最も下のブロックの範囲に、上から順（上端の高い順）に置く
+// refsの指定はAlignBlocksと同じで、3つ以上必要。X座標と大きさは変えない
+// ブロックの高さの合計が範囲より大きい場合は、同じ量だけ重ねて置く
+func (pl *PageLayout) DistributeVertically(refs []BlockRef) error {
+	if len(refs) < 3 {
+		return fmt.Errorf("distributing needs at least 3 blocks, got %d", len(refs))
+	}
+	rects, bounds, err := pl.selectionRects(refs)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int, len(refs))
+	total := 0.0
+	for i := range refs {
+		order[i] = i
+		total += rects[i].Height
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rects[order[a]].Y+rects[order[a]].Height > rects[order[b]].Y+rects[order[b]].Height
+	})
+
+	gap := (bounds.Height - total) / float64(len(refs)-1)
+	top := bounds.Y + bounds.Height
+	for _, i := range order {
+		r := rects[i]
+		if err := pl.MoveBlock(refs[i].Type, refs[i].Index, 0, top-r.Height-r.Y); err != nil {
+			return err
+		}
+		top -= r.Height + gap
+	}
+	return nil
+}
+
+// DistributeHorizontally はブロックの間隔が等しくなるように横に並べ直す
+// 左端が最も左のブロックと右端が最も右のブロックの範囲に、左から順（左端の小さい順）に置く
+// refsの指定はAlignBlocksと同じで、3つ以上必要。Y座標と大きさは変えない
+// ブロックの幅の合計が範囲より大きい場合は、同じ量だけ重ねて置く
+func (pl *PageLayout) DistributeHorizontally(refs []BlockRef) error {
+	if len(refs) < 3 {
+		return fmt.Errorf("distributing needs at least 3 blocks, got %d", len(refs))
+	}
+	rects, bounds, err := pl.selectionRects(refs)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int, len(refs))
+	total := 0.0
+	for i := range refs {
+		order[i] = i
+		total += rects[i].Width
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rects[order[a]].X < rects[order[b]].X
+	})
+
+	gap := (bounds.Width - total) / float64(len(refs)-1)
+	left := bounds.X
+	for _, i := range order {
+		r := rects[i]
+		if err := pl.MoveBlock(refs[i].Type, refs[i].Index, left-r.X, 0); err != nil {
+			return err
+		}
+		left += r.Width + gap
+	}
+	return nil
+}
+
+// selectionRects は選択したブロックの矩形と、全体のバウンディングボックスを返す
+// 同じブロックを2回選択した場合や、グループとそのメンバーを両方選択した場合はエラー（2回動いてしまうため）
+func (pl *PageLayout) selectionRects(refs []BlockRef) ([]Rectangle, Rectangle, error) {
+	grouped := pl.groupedRefs()
+	selected := make(map[BlockRef]bool, len(refs))
+	for _, ref := range refs {
+		if selected[ref] {
+			return nil, Rectangle{}, fmt.Errorf("%s block %d is listed twice", ref.Type, ref.Index)
+		}
+		selected[ref] = true
+	}
+
+	rects := make([]Rectangle, len(refs))
+	var bounds Rectangle
+	for i, ref := range refs {
+		var rect Rectangle
+		var err error
+		switch ref.Type {
+		case ContentBlockTypeGroup:
+			if ref.Index < 0 || ref.Index >= len(pl.Groups) {
+				return nil, Rectangle{}, fmt.Errorf("group index %d out of range [0, %d)", ref.Index, len(pl.Groups))
+			}
+			rect, err = pl.membersRect(pl.Groups[ref.Index].Members)
+		case ContentBlockTypeText, ContentBlockTypeImage:
+			rect, err = pl.blockRect(ref)
+			if g, ok := grouped[ref]; ok && selected[BlockRef{Type: ContentBlockTypeGroup, Index: g}] {
+				err = fmt.Errorf("%s block %d belongs to the selected group %d", ref.Type, ref.Index, g)
+			}
+		default:
+			err = fmt.Errorf("unsupported block type: %s", ref.Type)
+		}
+		if err != nil {
+			return nil, Rectangle{}, err
+		}
+
+		rects[i] = rect
+		if i == 0 {
+			bounds = rect
+		} else {
+			bounds = bounds.Union(rect)
+		}
+	}
+	return rects, bounds, nil
+}
@@ -2,17 +2,50 @@ package layout
 
 // Color は色の表現
 type Color struct {
-	R, G, B float64
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
 }
 
 // TextBlock はテキストの論理的なブロック
 type TextBlock struct {
-	Text     string           // テキスト内容
-	Elements []TextElement    // 構成要素
-	Rect     Rectangle        // バウンディングボックス
-	Font     string           // 主要フォント
-	FontSize float64          // 主要フォントサイズ
-	Color    Color            // テキスト色
+	Text     string        `json:"text"`               // テキスト内容
+	Elements []TextElement `json:"elements,omitempty"` // 構成要素
+	Rect     Rectangle     `json:"rect"`               // バウンディングボックス（回転したテキストでは回転した要素を囲む矩形）
+	Font     string        `json:"font"`               // 主要フォント
+	FontSize float64       `json:"fontSize"`           // 主要フォントサイズ
+	Color    Color         `json:"color"`              // テキスト色
+
+	RenderMode        TextRenderMode `json:"renderMode"`        // テキストレンダリングモード（先頭の要素のもの）
+	HorizontalScaling float64        `json:"horizontalScaling"` // 水平スケーリング（Tz、%。先頭の要素のもの）
+
+	Lines      []TextLine      `json:"lines,omitempty"`      // 行（上から順）
+	Paragraphs []TextParagraph `json:"paragraphs,omitempty"` // 段落（上から順。各段落はLinesの連続した一部を持つ）
+	Angle      float64         `json:"angle"`                // ベースラインの向き（度、反時計回り。回転したテキストのブロックのみ0以外）
+
+	Anchor BlockAnchor `json:"anchor,omitempty"` // ページに固定する位置（ヘッダー・フッター。固定したブロックはレイアウト調整で動かさない）
+
+	// Runs はスタイル（太字・斜体・色・フォント）ごとのテキストの並び。連結するとText
+	// スタイルの違う部分があるブロックだけが持つ。描き直しと翻訳（PreserveStyles）で使う（StyleRuns）
+	Runs []TextRun `json:"runs,omitempty"`
+}
+
+// TextLine はブロック内の1行
+// 同じベースライン上にあり、段組みの間隔で区切られていない要素の並び
+type TextLine struct {
+	Text     string        `json:"text"`               // 行のテキスト（語間にスペースを補う）
+	Elements []TextElement `json:"elements,omitempty"` // 構成要素（左から順）
+	Rect     Rectangle     `json:"rect"`               // バウンディングボックス
+	Baseline float64       `json:"baseline"`           // ベースラインのY座標（要素のYの平均）
+	FontSize float64       `json:"fontSize"`           // 平均フォントサイズ
+}
+
+// TextParagraph はブロック内の段落
+type TextParagraph struct {
+	Text   string     `json:"text"`            // 段落のテキスト（行を連結したもの）
+	Lines  []TextLine `json:"lines,omitempty"` // 構成する行
+	Rect   Rectangle  `json:"rect"`            // バウンディングボックス
+	Indent float64    `json:"indent"`          // 1行目の字下げ（2行目以降の左端からの距離。1行だけの段落は0）
 }
 
 // Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
@@ -32,21 +65,37 @@ func (tb TextBlock) Position() (x, y float64) {
 
 // Matrix は変換行列（CTM: Current Transformation Matrix）
 type Matrix struct {
-	A, B, C, D, E, F float64 // [a b c d e f]
+	// [a b c d e f]
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+	D float64 `json:"d"`
+	E float64 `json:"e"`
+	F float64 `json:"f"`
 }
 
 // ImageBlock は画像の配置情報
 type ImageBlock struct {
-	ImageInfo              // 画像データ（埋め込み）
-	X            float64   // 配置X座標
-	Y            float64   // 配置Y座標
-	PlacedWidth  float64   // 表示幅
-	PlacedHeight float64   // 表示高さ
-	Transform    Matrix    // 変換行列（CTM）
+	ImageInfo            // 画像データ（埋め込み）
+	X            float64 `json:"x"`            // 配置X座標
+	Y            float64 `json:"y"`            // 配置Y座標
+	PlacedWidth  float64 `json:"placedWidth"`  // 表示幅
+	PlacedHeight float64 `json:"placedHeight"` // 表示高さ
+	Transform    Matrix  `json:"transform"`    // 変換行列（CTM）
+
+	// Angle は画像の向き（度、反時計回り。回転したスタンプ・縦書きの帯などの画像のみ0以外）
+	// 回転した画像では、(X, Y)は回転の原点（画像の左下の隅）、PlacedWidth・PlacedHeightは回転する前の画像の大きさ
+	Angle float64 `json:"angle,omitempty"`
+
+	Anchor BlockAnchor `json:"anchor,omitempty"` // ページに固定する位置（ヘッダー・フッターのロゴなど）
 }
 
 // Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
+// 回転した画像では、回転した画像を囲む軸に平行な矩形
 func (ib ImageBlock) Bounds() Rectangle {
+	if ib.Angle != 0 {
+		return rotatedBounds(ib.X, ib.Y, ib.PlacedWidth, ib.PlacedHeight, ib.Angle)
+	}
 	return Rectangle{
 		X:      ib.X,
 		Y:      ib.Y,
@@ -64,3 +113,193 @@ func (ib ImageBlock) Type() ContentBlockType {
 func (ib ImageBlock) Position() (x, y float64) {
 	return ib.X, ib.Y
 }
+
+// PathKind はパスの形状の分類
+type PathKind string
+
+const (
+	// PathKindLine は直線（罫線・区切り線など）
+	PathKindLine PathKind = "line"
+	// PathKindRect は軸に平行な矩形（枠・背景・セルの塗りなど）
+	PathKindRect PathKind = "rect"
+	// PathKindCurve はベジェ曲線を含むパス
+	PathKindCurve PathKind = "curve"
+	// PathKindPolygon はそれ以外の折れ線・多角形
+	PathKindPolygon PathKind = "polygon"
+)
+
+// PathOp はパスセグメントの種類
+type PathOp string
+
+const (
+	// PathOpMoveTo は新しいサブパスの開始（Points: 始点）
+	PathOpMoveTo PathOp = "m"
+	// PathOpLineTo は直線（Points: 終点）
+	PathOpLineTo PathOp = "l"
+	// PathOpCurveTo は3次ベジェ曲線（Points: 制御点2つと終点）
+	PathOpCurveTo PathOp = "c"
+	// PathOpClosePath はサブパスを閉じる（Pointsなし）
+	PathOpClosePath PathOp = "h"
+)
+
+// Point は2次元座標
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// PathSegment はパスの構成要素（座標はページの座標系）
+type PathSegment struct {
+	Op     PathOp  `json:"op"`
+	Points []Point `json:"points,omitempty"`
+}
+
+// PathBlock は描画されたパス（線・矩形・曲線）
+type PathBlock struct {
+	Kind        PathKind      `json:"kind"`               // 形状の分類
+	Segments    []PathSegment `json:"segments,omitempty"` // 構成要素
+	Rect        Rectangle     `json:"rect"`               // バウンディングボックス（ベジェ曲線は制御点を含む）
+	Stroke      bool          `json:"stroke"`             // 線を描くか
+	Fill        bool          `json:"fill"`               // 塗りつぶすか
+	EvenOdd     bool          `json:"evenOdd"`            // 塗りつぶしが奇偶規則か
+	StrokeColor Color         `json:"strokeColor"`        // 線の色
+	FillColor   Color         `json:"fillColor"`          // 塗りつぶし色
+	LineWidth   float64       `json:"lineWidth"`          // 線幅
+	Transform   Matrix        `json:"transform"`          // 描画時の変換行列（CTM）
+}
+
+// Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
+func (pb PathBlock) Bounds() Rectangle {
+	return pb.Rect
+}
+
+// Type はブロックの種類を返す（ContentBlockインターフェース実装）
+func (pb PathBlock) Type() ContentBlockType {
+	return ContentBlockTypePath
+}
+
+// Position はブロックの配置位置を返す（ContentBlockインターフェース実装）
+func (pb PathBlock) Position() (x, y float64) {
+	return pb.Rect.X, pb.Rect.Y
+}
+
+// TableBlock は表
+// 罫線の格子、または列の揃ったテキストの並びから検出する
+type TableBlock struct {
+	Rect  Rectangle  `json:"rect"`           // 表全体の境界
+	Rows  []TableRow `json:"rows,omitempty"` // 行（上から順。すべての行が同じ数のセルを持つ）
+	Ruled bool       `json:"ruled"`          // 罫線から検出したか（falseはテキストの配置から推定した表）
+}
+
+// TableRow は表の1行
+type TableRow struct {
+	Rect  Rectangle   `json:"rect"`            // 行の境界
+	Cells []TableCell `json:"cells,omitempty"` // セル（左から順）
+}
+
+// TableCell は表のセル
+type TableCell struct {
+	Text     string        `json:"text"`               // セルのテキスト（複数行は連結する。空のセルは""）
+	Elements []TextElement `json:"elements,omitempty"` // セル内のテキスト要素
+	Rect     Rectangle     `json:"rect"`               // セルの境界
+}
+
+// Records はセルのテキストを行ごとに返す（encoding/csvにそのまま渡せる形）
+func (tb TableBlock) Records() [][]string {
+	records := make([][]string, len(tb.Rows))
+	for i, row := range tb.Rows {
+		records[i] = make([]string, len(row.Cells))
+		for j, cell := range row.Cells {
+			records[i][j] = cell.Text
+		}
+	}
+	return records
+}
+
+// Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
+func (tb TableBlock) Bounds() Rectangle {
+	return tb.Rect
+}
+
+// Type はブロックの種類を返す（ContentBlockインターフェース実装）
+func (tb TableBlock) Type() ContentBlockType {
+	return ContentBlockTypeTable
+}
+
+// Position はブロックの配置位置を返す（ContentBlockインターフェース実装）
+func (tb TableBlock) Position() (x, y float64) {
+	return tb.Rect.X, tb.Rect.Y
+}
+
+// VectorBlock は重なる・接するパスをまとめたブロック（罫線・枠・表の格子・図など）
+// レイアウト調整では動かさず、テキストと画像が重ならないようにする
+type VectorBlock struct {
+	Rect  Rectangle   `json:"rect"`            // バウンディングボックス
+	Paths []PathBlock `json:"paths,omitempty"` // 構成するパス（PageLayout.Pathsでの順）
+}
+
+// Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
+func (vb VectorBlock) Bounds() Rectangle {
+	return vb.Rect
+}
+
+// Type はブロックの種類を返す（ContentBlockインターフェース実装）
+func (vb VectorBlock) Type() ContentBlockType {
+	return ContentBlockTypeVector
+}
+
+// Position はブロックの配置位置を返す（ContentBlockインターフェース実装）
+func (vb VectorBlock) Position() (x, y float64) {
+	return vb.Rect.X, vb.Rect.Y
+}
+
+// AnnotationBlock はページ上に表示される注釈（スタンプ・付箋・図形・フォームのフィールドなど）
+// レイアウト調整では動かさず、テキストと画像が重ならないようにする
+type AnnotationBlock struct {
+	Subtype  string    `json:"subtype"`  // 注釈の種類（/Subtype。Stamp、Text、Widgetなど）
+	Rect     Rectangle `json:"rect"`     // 注釈の矩形（/Rect）
+	Contents string    `json:"contents"` // 注釈のテキスト（/Contents）
+}
+
+// Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
+func (ab AnnotationBlock) Bounds() Rectangle {
+	return ab.Rect
+}
+
+// Type はブロックの種類を返す（ContentBlockインターフェース実装）
+func (ab AnnotationBlock) Type() ContentBlockType {
+	return ContentBlockTypeAnnotation
+}
+
+// Position はブロックの配置位置を返す（ContentBlockインターフェース実装）
+func (ab AnnotationBlock) Position() (x, y float64) {
+	return ab.Rect.X, ab.Rect.Y
+}
+
+// BlockRef はPageLayout内のテキストブロック・画像ブロックへの参照
+type BlockRef struct {
+	Type  ContentBlockType `json:"type"`  // ContentBlockTypeText または ContentBlockTypeImage
+	Index int              `json:"index"` // TextBlocks・Imagesでのインデックス
+}
+
+// GroupBlock は1つの単位として移動・リサイズ・レイアウト調整するブロックのグループ（画像とキャプションなど）
+// PageLayout.GroupBlocksで作成する
+type GroupBlock struct {
+	Rect    Rectangle  `json:"rect"`    // メンバー全体のバウンディングボックス
+	Members []BlockRef `json:"members"` // メンバーのブロック
+}
+
+// Bounds はブロックの境界矩形を返す（ContentBlockインターフェース実装）
+func (gb GroupBlock) Bounds() Rectangle {
+	return gb.Rect
+}
+
+// Type はブロックの種類を返す（ContentBlockインターフェース実装）
+func (gb GroupBlock) Type() ContentBlockType {
+	return ContentBlockTypeGroup
+}
+
+// Position はブロックの配置位置を返す（ContentBlockインターフェース実装）
+func (gb GroupBlock) Position() (x, y float64) {
+	return gb.Rect.X, gb.Rect.Y
+}
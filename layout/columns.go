@@ -0,0 +1,123 @@
+package layout
+
+import "fmt"
+
+// adjustLayoutColumns はブロックを読み順のまま、ページ内のopts.Columns.Count段に流し直す
+// 段の高さがなるべく揃うように各段に入れるブロックを決め、各段の上端（本文の範囲の上端）から詰めて置く
+// テキストブロックは段の幅に広げ、高さはopts.TextHeightで計る（nilの場合は面積が変わらないものとして推定する）
+// 段より広い画像とグループは縦横比を保って段の幅に縮める
+// すべての段に収まらない場合は、最後の段が本文の範囲の下端を越える（SplitIntoPagesで分割できる）
+func (pl *PageLayout) adjustLayoutColumns(opts LayoutAdjustmentOptions) error {
+	count, gutter := opts.Columns.Count, opts.Columns.Gutter
+	if count < 1 {
+		return fmt.Errorf("invalid column count: %d", count)
+	}
+	area := pl.contentArea(opts.PageMargin, opts.MinSpacing)
+	columnWidth := (area.Width - gutter*float64(count-1)) / float64(count)
+	if columnWidth <= 0 {
+		return fmt.Errorf("no room for %d columns with gutter %v", count, gutter)
+	}
+
+	units := pl.flowUnits()
+	if len(units) == 0 {
+		return nil
+	}
+
+	// 段に入れたときの大きさ
+	sizes := make([]Rectangle, len(units))
+	for i, u := range units {
+		sizes[i] = columnSize(u.block, columnWidth, opts.TextHeight)
+	}
+
+	// 段の高さの上限を二分探索し、count段に収まる最小の高さで段に分ける
+	heights := make([]float64, len(units))
+	low, high := 0.0, -opts.MinSpacing
+	for i, size := range sizes {
+		heights[i] = size.Height
+		low = max(low, size.Height)
+		high += size.Height + opts.MinSpacing
+	}
+	for range 50 {
+		mid := (low + high) / 2
+		if len(packColumns(heights, mid, opts.MinSpacing)) <= count {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	columns := packColumns(heights, high, opts.MinSpacing)
+
+	fixed := pl.fixedRects()
+	for c, column := range columns {
+		x := area.X + float64(c)*(columnWidth+gutter)
+		currentY := area.Y + area.Height
+		for _, i := range column {
+			size := sizes[i]
+			// 元の位置から離れるため、ページの上を元の位置として、すべての動かさないブロックを避ける
+			original := Rectangle{X: x, Y: pl.Height, Width: size.Width, Height: size.Height}
+			newY := avoidFixed(original, currentY-size.Height, fixed, opts.MinSpacing)
+			pl.placeUnit(units[i], Rectangle{X: x, Y: newY, Width: size.Width, Height: size.Height})
+			currentY = newY - opts.MinSpacing
+		}
+	}
+
+	return nil
+}
+
+// columnSize は幅columnWidthの段に入れたときのブロックの大きさを返す（X・Yは使わない）
+func columnSize(block ContentBlock, columnWidth float64, textHeight func(tb TextBlock, width float64) float64) Rectangle {
+	bounds := block.Bounds()
+	if tb, ok := block.(TextBlock); ok {
+		height := bounds.Height
+		if textHeight != nil {
+			if h := textHeight(tb, columnWidth); h > 0 {
+				return Rectangle{Width: columnWidth, Height: h}
+			}
+		}
+		if bounds.Width > columnWidth {
+			height = bounds.Height * bounds.Width / columnWidth
+		}
+		return Rectangle{Width: columnWidth, Height: height}
+	}
+
+	if bounds.Width > columnWidth {
+		return Rectangle{Width: columnWidth, Height: bounds.Height * columnWidth / bounds.Width}
+	}
+	return Rectangle{Width: bounds.Width, Height: bounds.Height}
+}
+
+// packColumns は高さheightsのブロックを順に、高さlimitを越えないように段に詰め、各段のブロックのインデックスを返す
+// limitより高いブロックは1つで1段を使う
+func packColumns(heights []float64, limit, spacing float64) [][]int {
+	var columns [][]int
+	used := 0.0
+	for i, h := range heights {
+		n := len(columns)
+		if n > 0 && used+spacing+h <= limit {
+			columns[n-1] = append(columns[n-1], i)
+			used += spacing + h
+			continue
+		}
+		columns = append(columns, []int{i})
+		used = h
+	}
+	return columns
+}
+
+// placeUnit はブロック（グループの場合は全メンバー）をrectの位置と大きさに置く
+func (pl *PageLayout) placeUnit(u flowUnit, rect Rectangle) {
+	switch b := u.block.(type) {
+	case TextBlock:
+		pl.TextBlocks[u.refs[0].Index].Rect = rect
+	case ImageBlock:
+		pl.Images[u.refs[0].Index].SetBounds(rect)
+	case GroupBlock:
+		index := pl.groupIndex(b)
+		if index < 0 {
+			return
+		}
+		pl.resizeGroup(index, rect.Width, rect.Height)
+		current := pl.Groups[index].Rect
+		pl.moveRefs(u.refs, rect.X-current.X, rect.Y-current.Y)
+	}
+}
@@ -0,0 +1,258 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// flowUnit はレイアウト調整で1つの単位として動かすブロック
+type flowUnit struct {
+	block ContentBlock // TextBlock、ImageBlock、またはGroupBlock
+	refs  []BlockRef   // 動かすブロック（グループの場合は全メンバー）
+}
+
+// FlowBlocks はレイアウト調整で動かすブロック（テキストと画像）を読み順（SortedContentBlocksの順）で返す
+// グループのメンバーは個別に返さず、1つのGroupBlockとして返す。ページに固定したブロック（Anchor）は含まない
+// InsertBlock・RemoveBlockのインデックスはこの順序で数える
+func (pl *PageLayout) FlowBlocks() []ContentBlock {
+	var blocks []ContentBlock
+	for _, u := range pl.flowUnits() {
+		blocks = append(blocks, u.block)
+	}
+	return blocks
+}
+
+// flowUnits はFlowBlocksの各ブロックを、動かすブロックへの参照と合わせて返す
+func (pl *PageLayout) flowUnits() []flowUnit {
+	grouped := make(map[BlockRef]bool)
+	var units []flowUnit
+	for _, g := range pl.groupUnits() {
+		for _, ref := range g.Members {
+			grouped[ref] = true
+		}
+		units = append(units, flowUnit{block: g, refs: g.Members})
+	}
+	for i, tb := range pl.TextBlocks {
+		if ref := (BlockRef{Type: ContentBlockTypeText, Index: i}); !grouped[ref] && tb.Anchor == AnchorNone {
+			units = append(units, flowUnit{block: tb, refs: []BlockRef{ref}})
+		}
+	}
+	for i, ib := range pl.Images {
+		if ref := (BlockRef{Type: ContentBlockTypeImage, Index: i}); !grouped[ref] && ib.Anchor == AnchorNone {
+			units = append(units, flowUnit{block: ib, refs: []BlockRef{ref}})
+		}
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return readingOrderLess(units[i].block.Bounds(), units[j].block.Bounds())
+	})
+	return units
+}
+
+// InsertBlock はFlowBlocksのafterIndex番目のブロックの下にblock（TextBlockかImageBlock）を挿入する
+// afterIndexが-1の場合は先頭のブロックの位置に挿入する。X座標と大きさはblockのまま使う
+// 後続のブロックはFlowDown戦略と同じく、前のブロックの下にopts.MinSpacingを空けて押し下げる
+// ただし押し下げるのは横に重なる前のブロックの下だけで、横に並んだブロックや別の段のブロックは動かさない
+// 押し下げて本文の範囲（Marginsとopts.PageMargin、ヘッダー・フッターの内側）の下端を越えたブロックとそれ以降のブロックは、
+// 次のページの本文の上端から相対位置を保って並べた続きのレイアウトに移して返す（はみ出さない場合はnil）
+// 続きのレイアウトには、ページに固定したブロック（ヘッダー・フッター）を写す
+// 続きのレイアウトもはみ出す場合は、SplitIntoPagesで分割する
+func (pl *PageLayout) InsertBlock(afterIndex int, block ContentBlock, opts LayoutAdjustmentOptions) (*PageLayout, error) {
+	units := pl.flowUnits()
+	if afterIndex < -1 || afterIndex >= len(units) {
+		return nil, fmt.Errorf("block index %d out of range [-1, %d)", afterIndex, len(units))
+	}
+
+	// 挿入する位置の上端
+	area := pl.contentArea(opts.PageMargin, opts.MinSpacing)
+	top := area.Y + area.Height
+	if afterIndex >= 0 {
+		top = units[afterIndex].block.Bounds().Y - opts.MinSpacing
+	} else if len(units) > 0 {
+		first := units[0].block.Bounds()
+		top = first.Y + first.Height
+	}
+
+	// 新しいブロックには元の位置がないため、ページの上を元の位置として、すべての動かさないブロックを避ける
+	fixed := pl.fixedRects()
+	bounds := block.Bounds()
+	bounds.Y = pl.Height
+	newY := avoidFixed(bounds, top-bounds.Height, fixed, opts.MinSpacing)
+
+	var inserted flowUnit
+	switch b := block.(type) {
+	case TextBlock:
+		b.Rect.Y = newY
+		pl.TextBlocks = append(pl.TextBlocks, b)
+		inserted = flowUnit{block: b, refs: []BlockRef{{Type: ContentBlockTypeText, Index: len(pl.TextBlocks) - 1}}}
+	case ImageBlock:
+		b.Y = newY
+		pl.Images = append(pl.Images, b)
+		inserted = flowUnit{block: b, refs: []BlockRef{{Type: ContentBlockTypeImage, Index: len(pl.Images) - 1}}}
+	default:
+		return nil, fmt.Errorf("unsupported block type: %s", block.Type())
+	}
+
+	// 後続のブロックを、横に重なる前のブロックの下にopts.MinSpacingを空けて押し下げ、最初にはみ出したブロックを探す
+	following := append([]flowUnit{inserted}, units[afterIndex+1:]...)
+	placed := []Rectangle{inserted.block.Bounds()}
+	overflow := -1
+	for i, u := range following {
+		b := u.block.Bounds()
+		moved := i == 0
+		if i > 0 {
+			idealTop := math.Inf(1)
+			for _, p := range placed {
+				if overlapsHorizontally(p, b) {
+					idealTop = min(idealTop, p.Y-opts.MinSpacing)
+				}
+			}
+			if b.Y+b.Height > idealTop {
+				y := avoidFixed(b, idealTop-b.Height, fixed, opts.MinSpacing)
+				pl.moveRefs(u.refs, 0, y-b.Y)
+				b.Y = y
+				moved = true
+			}
+			placed = append(placed, b)
+		}
+		// 元から下マージンにあったブロックは、はみ出したとみなさない
+		if moved && b.Y < area.Y && overflow < 0 {
+			overflow = i
+		}
+	}
+	if overflow < 0 {
+		return nil, nil
+	}
+
+	// はみ出したブロック以降を、続きのレイアウトの上端に移す
+	following = following[overflow:]
+	next := pl.blankPage(pl.Height)
+	first, _ := pl.membersRect(following[0].refs)
+	offsetY := area.Y + area.Height - (first.Y + first.Height)
+	var refs []BlockRef
+	for _, u := range following {
+		next.appendUnit(pl, u, offsetY)
+		refs = append(refs, u.refs...)
+	}
+	pl.removeRefs(refs)
+	return next, nil
+}
+
+// RemoveBlock はFlowBlocksのindex番目のブロック（グループの場合は全メンバー）を削除する
+// 削除したブロックの下にある後続のブロック（削除したブロックか、詰めたブロックと横に重なるもの）は、
+// 空いた高さ（ブロックの高さとopts.MinSpacing）だけ上に詰める。横に並んでいたブロックや別の段のブロックは動かさない
+func (pl *PageLayout) RemoveBlock(index int, opts LayoutAdjustmentOptions) error {
+	units := pl.flowUnits()
+	if index < 0 || index >= len(units) {
+		return fmt.Errorf("block index %d out of range [0, %d)", index, len(units))
+	}
+
+	removed := units[index].block.Bounds()
+	shift := removed.Height + opts.MinSpacing
+	fixed := pl.fixedRects()
+	shifted := []Rectangle{removed}
+	for _, u := range units[index+1:] {
+		b := u.block.Bounds()
+		if b.Y+b.Height > removed.Y || !slices.ContainsFunc(shifted, func(r Rectangle) bool { return overlapsHorizontally(r, b) }) {
+			continue
+		}
+		// 動かさないブロックを越える場合はその下で止める
+		y := max(avoidFixed(b, b.Y+shift, fixed, opts.MinSpacing), b.Y)
+		pl.moveRefs(u.refs, 0, y-b.Y)
+		shifted = append(shifted, b)
+	}
+
+	// インデックスが変わるため、移動の後に削除する
+	pl.removeRefs(units[index].refs)
+	return nil
+}
+
+// moveRefs はブロックをまとめて移動する
+func (pl *PageLayout) moveRefs(refs []BlockRef, offsetX, offsetY float64) {
+	for _, ref := range refs {
+		switch ref.Type {
+		case ContentBlockTypeText:
+			pl.TextBlocks[ref.Index].Rect.X += offsetX
+			pl.TextBlocks[ref.Index].Rect.Y += offsetY
+		case ContentBlockTypeImage:
+			pl.Images[ref.Index].X += offsetX
+			pl.Images[ref.Index].Y += offsetY
+		}
+	}
+	pl.refreshGroupRects()
+}
+
+// appendUnit はsrcのブロックをY方向にoffsetYずらして追加する
+// グループの場合は追加したブロックのインデックスでグループを作り直す
+func (pl *PageLayout) appendUnit(src *PageLayout, u flowUnit, offsetY float64) {
+	members := make([]BlockRef, len(u.refs))
+	for i, ref := range u.refs {
+		switch ref.Type {
+		case ContentBlockTypeText:
+			tb := src.TextBlocks[ref.Index]
+			tb.Rect.Y += offsetY
+			pl.TextBlocks = append(pl.TextBlocks, tb)
+			members[i] = BlockRef{Type: ContentBlockTypeText, Index: len(pl.TextBlocks) - 1}
+		case ContentBlockTypeImage:
+			ib := src.Images[ref.Index]
+			ib.Y += offsetY
+			pl.Images = append(pl.Images, ib)
+			members[i] = BlockRef{Type: ContentBlockTypeImage, Index: len(pl.Images) - 1}
+		}
+	}
+	if _, ok := u.block.(GroupBlock); ok {
+		group := GroupBlock{Members: members}
+		group.Rect, _ = pl.membersRect(members)
+		pl.Groups = append(pl.Groups, group)
+	}
+}
+
+// removeRefs はブロックを削除し、残ったグループのメンバーのインデックスを付け直す
+// 削除したブロックを含むグループは解除する
+func (pl *PageLayout) removeRefs(refs []BlockRef) {
+	removed := make(map[BlockRef]bool, len(refs))
+	for _, ref := range refs {
+		removed[ref] = true
+	}
+
+	// 古いインデックス -> 新しいインデックス
+	newIndex := make(map[BlockRef]int)
+	var texts []TextBlock
+	for i, tb := range pl.TextBlocks {
+		if ref := (BlockRef{Type: ContentBlockTypeText, Index: i}); !removed[ref] {
+			newIndex[ref] = len(texts)
+			texts = append(texts, tb)
+		}
+	}
+	var images []ImageBlock
+	for i, ib := range pl.Images {
+		if ref := (BlockRef{Type: ContentBlockTypeImage, Index: i}); !removed[ref] {
+			newIndex[ref] = len(images)
+			images = append(images, ib)
+		}
+	}
+	pl.TextBlocks = texts
+	pl.Images = images
+
+	var groups []GroupBlock
+	for _, g := range pl.Groups {
+		members := make([]BlockRef, 0, len(g.Members))
+		for _, ref := range g.Members {
+			if i, ok := newIndex[ref]; ok {
+				members = append(members, BlockRef{Type: ref.Type, Index: i})
+			}
+		}
+		if len(members) == len(g.Members) {
+			g.Members = members
+			groups = append(groups, g)
+		}
+	}
+	pl.Groups = groups
+}
+
+// overlapsHorizontally は2つの矩形のX方向の範囲が重なるかを返す
+func overlapsHorizontally(a, b Rectangle) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width
+}
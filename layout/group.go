@@ -0,0 +1,174 @@
+package layout
+
+import "fmt"
+
+// GroupBlocks はブロックをグループにまとめ、Groupsでのインデックスを返す
+// グループはMoveBlock・ResizeBlock（ContentBlockTypeGroupとインデックスを指定）とAdjustLayout、
+// SplitIntoPagesで1つの単位として扱われ、メンバーの相対位置が保たれる
+// メンバーはテキストブロックか画像ブロック（ページに固定していないもの）で、2つ以上必要。1つのブロックは1つのグループにしか属せない
+// グループはインデックスでメンバーを参照するため、TextBlocks・Imagesの順序を変える場合は先にUngroupBlocksで解除する
+func (pl *PageLayout) GroupBlocks(members ...BlockRef) (int, error) {
+	if len(members) < 2 {
+		return 0, fmt.Errorf("a group needs at least 2 blocks, got %d", len(members))
+	}
+
+	grouped := pl.groupedRefs()
+	seen := make(map[BlockRef]bool, len(members))
+	for _, ref := range members {
+		if _, err := pl.blockRect(ref); err != nil {
+			return 0, err
+		}
+		if seen[ref] {
+			return 0, fmt.Errorf("%s block %d is listed twice", ref.Type, ref.Index)
+		}
+		if g, ok := grouped[ref]; ok {
+			return 0, fmt.Errorf("%s block %d already belongs to group %d", ref.Type, ref.Index, g)
+		}
+		if pl.anchor(ref) != AnchorNone {
+			return 0, fmt.Errorf("%s block %d is anchored to the page", ref.Type, ref.Index)
+		}
+		seen[ref] = true
+	}
+
+	group := GroupBlock{Members: append([]BlockRef(nil), members...)}
+	group.Rect, _ = pl.membersRect(group.Members)
+	pl.Groups = append(pl.Groups, group)
+	return len(pl.Groups) - 1, nil
+}
+
+// UngroupBlocks はグループを解除する。メンバーのブロックはそのまま残る
+func (pl *PageLayout) UngroupBlocks(index int) error {
+	if index < 0 || index >= len(pl.Groups) {
+		return fmt.Errorf("group index %d out of range [0, %d)", index, len(pl.Groups))
+	}
+	pl.Groups = append(pl.Groups[:index], pl.Groups[index+1:]...)
+	return nil
+}
+
+// blockRect はテキストブロック・画像ブロックへの参照の矩形を返す
+func (pl *PageLayout) blockRect(ref BlockRef) (Rectangle, error) {
+	switch ref.Type {
+	case ContentBlockTypeText:
+		if ref.Index < 0 || ref.Index >= len(pl.TextBlocks) {
+			return Rectangle{}, fmt.Errorf("text block index %d out of range [0, %d)", ref.Index, len(pl.TextBlocks))
+		}
+		return pl.TextBlocks[ref.Index].Rect, nil
+	case ContentBlockTypeImage:
+		if ref.Index < 0 || ref.Index >= len(pl.Images) {
+			return Rectangle{}, fmt.Errorf("image block index %d out of range [0, %d)", ref.Index, len(pl.Images))
+		}
+		return pl.Images[ref.Index].Bounds(), nil
+	default:
+		return Rectangle{}, fmt.Errorf("unsupported group member type: %s", ref.Type)
+	}
+}
+
+// membersRect はメンバー全体のバウンディングボックスを返す
+func (pl *PageLayout) membersRect(members []BlockRef) (Rectangle, error) {
+	var rect Rectangle
+	for i, ref := range members {
+		r, err := pl.blockRect(ref)
+		if err != nil {
+			return Rectangle{}, err
+		}
+		if i == 0 {
+			rect = r
+		} else {
+			rect = rect.Union(r)
+		}
+	}
+	return rect, nil
+}
+
+// groupedRefs はグループに属するブロック -> グループのインデックスを返す
+func (pl *PageLayout) groupedRefs() map[BlockRef]int {
+	grouped := make(map[BlockRef]int)
+	for i, g := range pl.Groups {
+		for _, ref := range g.Members {
+			grouped[ref] = i
+		}
+	}
+	return grouped
+}
+
+// groupUnits はメンバーが有効なグループを、現在のメンバーの位置から計算した矩形で返す
+// メンバーが範囲外のグループは無視する（メンバーは個別のブロックとして扱われる）
+func (pl *PageLayout) groupUnits() []GroupBlock {
+	var groups []GroupBlock
+	for i := range pl.Groups {
+		rect, err := pl.membersRect(pl.Groups[i].Members)
+		if err != nil {
+			continue
+		}
+		pl.Groups[i].Rect = rect
+		groups = append(groups, pl.Groups[i])
+	}
+	return groups
+}
+
+// refreshGroupRects はグループの矩形をメンバーの現在の位置に合わせる
+func (pl *PageLayout) refreshGroupRects() {
+	pl.groupUnits()
+}
+
+// groupIndex はgroupUnitsが返したグループのGroupsでのインデックスを返す（見つからない場合は-1）
+// 1つのブロックは1つのグループにしか属さないため、最初のメンバーで識別する
+func (pl *PageLayout) groupIndex(gb GroupBlock) int {
+	if len(gb.Members) == 0 {
+		return -1
+	}
+	for i, g := range pl.Groups {
+		if len(g.Members) > 0 && g.Members[0] == gb.Members[0] {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveGroup はグループのメンバーをまとめて移動する
+func (pl *PageLayout) moveGroup(index int, offsetX, offsetY float64) error {
+	g := &pl.Groups[index]
+	if _, err := pl.membersRect(g.Members); err != nil {
+		return fmt.Errorf("group %d: %w", index, err)
+	}
+	pl.moveRefs(g.Members, offsetX, offsetY)
+	return nil
+}
+
+// resizeGroup はグループの左下を固定して、メンバーの位置と大きさを同じ比率で拡大・縮小する
+// テキストブロックは矩形だけを変える（フォントサイズは変えない）
+func (pl *PageLayout) resizeGroup(index int, newWidth, newHeight float64) error {
+	g := &pl.Groups[index]
+	rect, err := pl.membersRect(g.Members)
+	if err != nil {
+		return fmt.Errorf("group %d: %w", index, err)
+	}
+
+	sx, sy := 1.0, 1.0
+	if rect.Width > 0 {
+		sx = newWidth / rect.Width
+	}
+	if rect.Height > 0 {
+		sy = newHeight / rect.Height
+	}
+	scale := func(r Rectangle) Rectangle {
+		return Rectangle{
+			X:      rect.X + (r.X-rect.X)*sx,
+			Y:      rect.Y + (r.Y-rect.Y)*sy,
+			Width:  r.Width * sx,
+			Height: r.Height * sy,
+		}
+	}
+
+	for _, ref := range g.Members {
+		switch ref.Type {
+		case ContentBlockTypeText:
+			pl.TextBlocks[ref.Index].Rect = scale(pl.TextBlocks[ref.Index].Rect)
+		case ContentBlockTypeImage:
+			img := &pl.Images[ref.Index]
+			img.SetBounds(scale(img.Bounds()))
+		}
+	}
+	g.Rect, _ = pl.membersRect(g.Members)
+	return nil
+}
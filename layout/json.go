@@ -0,0 +1,84 @@
+package layout
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONOptions はEncodeJSON・DecodeJSONの設定
+type JSONOptions struct {
+	Indent string // 1段のインデント（空の場合は改行せずに出力する）
+
+	// Images がnil以外の場合、画像データをJSONに含めず、参照名（データのSHA-256の16進数）を"dataRef"に書き、
+	// 参照名 -> データをImagesに入れる。同じデータの画像は同じ参照名を共有する
+	// DecodeJSONでは"dataRef"の画像データをImagesから読む
+	Images map[string][]byte
+}
+
+// pageLayoutJSON はEncodeJSON・DecodeJSONの形式（imagesだけを参照付きの形式にする）
+type pageLayoutJSON struct {
+	*PageLayout
+	Images []imageBlockJSON `json:"images,omitempty"`
+}
+
+// imageBlockJSON は画像ブロックのJSON（dataとdataRefのどちらか一方を持つ）
+type imageBlockJSON struct {
+	ImageBlock
+	Data    []byte `json:"data,omitempty"`    // 画像データ（Base64）
+	DataRef string `json:"dataRef,omitempty"` // JSONOptions.Imagesの参照名
+}
+
+// EncodeJSON はレイアウトをJSONとして書き出す
+// 外部のサービス（LLMやWebのエディタなど）でブロックを編集し、DecodeJSONで読み戻す場合に使う
+// 画像データは既定でBase64としてJSONに含める。opts.Imagesを指定すると参照名だけを書き、データは別に渡せる
+// 参照名を使わない場合はjson.Marshalと同じ形式になる
+func (pl *PageLayout) EncodeJSON(w io.Writer, opts JSONOptions) error {
+	out := pageLayoutJSON{PageLayout: pl, Images: make([]imageBlockJSON, len(pl.Images))}
+	for i, img := range pl.Images {
+		item := imageBlockJSON{ImageBlock: img, Data: img.Data}
+		if opts.Images != nil && len(img.Data) > 0 {
+			sum := sha256.Sum256(img.Data)
+			item.Data = nil
+			item.DataRef = hex.EncodeToString(sum[:])
+			opts.Images[item.DataRef] = img.Data
+		}
+		out.Images[i] = item
+	}
+
+	enc := json.NewEncoder(w)
+	if opts.Indent != "" {
+		enc.SetIndent("", opts.Indent)
+	}
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("failed to encode layout: %w", err)
+	}
+	return nil
+}
+
+// DecodeJSON はEncodeJSON（またはjson.Marshal）で書き出したレイアウトを読み込む
+// "dataRef"を持つ画像のデータはopts.Imagesから読み、見つからない場合はエラーを返す
+func DecodeJSON(r io.Reader, opts JSONOptions) (*PageLayout, error) {
+	pl := &PageLayout{}
+	in := pageLayoutJSON{PageLayout: pl}
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, fmt.Errorf("failed to decode layout: %w", err)
+	}
+
+	pl.Images = nil
+	for i, item := range in.Images {
+		img := item.ImageBlock
+		img.Data = item.Data
+		if item.DataRef != "" {
+			data, ok := opts.Images[item.DataRef]
+			if !ok {
+				return nil, fmt.Errorf("image %d: data %q not found", i, item.DataRef)
+			}
+			img.Data = data
+		}
+		pl.Images = append(pl.Images, img)
+	}
+	return pl, nil
+}
@@ -22,27 +22,74 @@ const (
 	ContentBlockTypeText ContentBlockType = "text"
 	// ContentBlockTypeImage は画像ブロック
 	ContentBlockTypeImage ContentBlockType = "image"
+	// ContentBlockTypePath はベクターグラフィックス（線・矩形・曲線）のブロック
+	ContentBlockTypePath ContentBlockType = "path"
+	// ContentBlockTypeTable は表のブロック
+	ContentBlockTypeTable ContentBlockType = "table"
+	// ContentBlockTypeVector は重なる・接するパスをまとめたブロック（罫線・枠・図など）
+	ContentBlockTypeVector ContentBlockType = "vector"
+	// ContentBlockTypeAnnotation はページ上に表示される注釈のブロック（スタンプ・フォームのフィールドなど）
+	ContentBlockTypeAnnotation ContentBlockType = "annotation"
+	// ContentBlockTypeGroup はまとめて移動・リサイズするブロックのグループ（画像とキャプションなど）
+	ContentBlockTypeGroup ContentBlockType = "group"
 )
 
 // PageLayout はページの完全なレイアウト情報
 type PageLayout struct {
-	PageNum    int          // ページ番号（0-indexed）
-	Width      float64      // ページ幅
-	Height     float64      // ページ高さ
-	TextBlocks []TextBlock  // テキストブロック
-	Images     []ImageBlock // 画像ブロック
-	PageCTM    *Matrix      // ページレベルのCTM（座標系変換情報）
+	PageNum     int               `json:"pageNum"`               // ページ番号（0-indexed）
+	Width       float64           `json:"width"`                 // ページ幅
+	Height      float64           `json:"height"`                // ページ高さ
+	TextBlocks  []TextBlock       `json:"textBlocks,omitempty"`  // テキストブロック
+	Images      []ImageBlock      `json:"images,omitempty"`      // 画像ブロック
+	Paths       []PathBlock       `json:"paths,omitempty"`       // ベクターグラフィックス（ContentBlocksにはVectorBlocksとしてまとめて含まれる）
+	Tables      []TableBlock      `json:"tables,omitempty"`      // 検出した表（ContentBlocksには含まれない。セルのテキストはTextBlocksにも含まれる）
+	Annotations []AnnotationBlock `json:"annotations,omitempty"` // 表示される注釈（リンクとポップアップを除く）
+	Groups      []GroupBlock      `json:"groups,omitempty"`      // ブロックのグループ（ContentBlocksには含まれず、メンバーが個別に含まれる）
+	PageCTM     *Matrix           `json:"pageCTM,omitempty"`     // ページレベルのCTM（座標系変換情報）
+	Rotation    int               `json:"rotation"`              // 座標に適用したページの回転（/Rotate、0, 90, 180, 270）
+	Boxes       PageBoxes         `json:"boxes"`                 // ページの境界ボックス（ブロックと同じ座標系）
+	Margins     Margins           `json:"margins"`               // 本文を置く範囲の外側の余白（レイアウト調整・ページ分割で使う）
+}
+
+// PageBoxes はページの境界ボックス
+// 省略されたボックスはPDFの既定値（CropBoxはMediaBox、それ以外はCropBox）で補う。値はPDFに書かれたまま（切り詰めない）
+type PageBoxes struct {
+	MediaBox Rectangle `json:"mediaBox"` // 用紙の範囲（/MediaBox）
+	CropBox  Rectangle `json:"cropBox"`  // 表示・印刷される範囲（/CropBox）
+	BleedBox Rectangle `json:"bleedBox"` // 裁ち落としを含む範囲（/BleedBox）
+	TrimBox  Rectangle `json:"trimBox"`  // 仕上がりの範囲（/TrimBox）
+	ArtBox   Rectangle `json:"artBox"`   // 意味のある内容の範囲（/ArtBox）
+	Visible  Rectangle `json:"visible"`  // ビューアで実際に表示される範囲（CropBoxとMediaBoxの重なり）
 }
 
 // Rectangle は矩形領域
 type Rectangle struct {
-	X      float64 // 左下X座標
-	Y      float64 // 左下Y座標
-	Width  float64 // 幅
-	Height float64 // 高さ
+	X      float64 `json:"x"`      // 左下X座標
+	Y      float64 `json:"y"`      // 左下Y座標
+	Width  float64 `json:"width"`  // 幅
+	Height float64 `json:"height"` // 高さ
+}
+
+// Intersect は2つの矩形の重なりを返す（重ならない場合は幅・高さが0の矩形）
+func (r Rectangle) Intersect(other Rectangle) Rectangle {
+	x1, y1 := max(r.X, other.X), max(r.Y, other.Y)
+	x2, y2 := min(r.X+r.Width, other.X+other.Width), min(r.Y+r.Height, other.Y+other.Height)
+	if x2 < x1 || y2 < y1 {
+		return Rectangle{X: x1, Y: y1}
+	}
+	return Rectangle{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}
+}
+
+// Union は2つの矩形を含む最小の矩形を返す
+func (r Rectangle) Union(other Rectangle) Rectangle {
+	x1, y1 := min(r.X, other.X), min(r.Y, other.Y)
+	x2, y2 := max(r.X+r.Width, other.X+other.Width), max(r.Y+r.Height, other.Y+other.Height)
+	return Rectangle{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}
 }
 
-// ContentBlocks はページ内のすべてのコンテンツブロックをY座標順で返す
+// ContentBlocks はページ内のブロックをY座標順で返す
+// テキストと画像に加え、パスをまとめたVectorBlocksと注釈（Annotations）を含む
+// VectorBlockとAnnotationBlockはレイアウト調整で動かさず、テキストと画像が重ならないようにする
 func (pl *PageLayout) ContentBlocks() []ContentBlock {
 	var blocks []ContentBlock
 
@@ -56,6 +103,14 @@ func (pl *PageLayout) ContentBlocks() []ContentBlock {
 		blocks = append(blocks, ib)
 	}
 
+	// VectorBlocksとAnnotationsを追加
+	for _, vb := range pl.VectorBlocks() {
+		blocks = append(blocks, vb)
+	}
+	for _, ab := range pl.Annotations {
+		blocks = append(blocks, ab)
+	}
+
 	// Y座標でソート（上から下）
 	// 注: 座標は既に標準座標系に変換済み（Y値が大きいほど上）
 	sort.Slice(blocks, func(i, j int) bool {
@@ -74,27 +129,29 @@ func (pl *PageLayout) SortedContentBlocks() []ContentBlock {
 	blocks := pl.ContentBlocks()
 
 	sort.Slice(blocks, func(i, j int) bool {
-		boundsI := blocks[i].Bounds()
-		boundsJ := blocks[j].Bounds()
-
-		// 上端（Y+Height）で比較（上から下）
-		// 座標は標準PDF座標系: Y値が大きいほど上にある
-		// 読む順序: 上から下なので、Y値が大きい方を先に
-		topI := boundsI.Y + boundsI.Height
-		topJ := boundsJ.Y + boundsJ.Height
-
-		const epsilon = 1.0
-		if topI-topJ > epsilon || topJ-topI > epsilon {
-			return topI > topJ // 上端が高い方（Y値が大きい方）を先に
-		}
-
-		// X座標で比較（左から右）
-		return boundsI.X < boundsJ.X
+		return readingOrderLess(blocks[i].Bounds(), blocks[j].Bounds())
 	})
 
 	return blocks
 }
 
+// readingOrderLess は矩形boundsIがSortedContentBlocksの順でboundsJより前にあるかを返す
+func readingOrderLess(boundsI, boundsJ Rectangle) bool {
+	// 上端（Y+Height）で比較（上から下）
+	// 座標は標準PDF座標系: Y値が大きいほど上にある
+	// 読む順序: 上から下なので、Y値が大きい方を先に
+	topI := boundsI.Y + boundsI.Height
+	topJ := boundsJ.Y + boundsJ.Height
+
+	const epsilon = 1.0
+	if topI-topJ > epsilon || topJ-topI > epsilon {
+		return topI > topJ // 上端が高い方（Y値が大きい方）を先に
+	}
+
+	// X座標で比較（左から右）
+	return boundsI.X < boundsJ.X
+}
+
 // BlockOverlap はブロックの重なり情報
 type BlockOverlap struct {
 	Block1 ContentBlock // 1つ目のブロック
@@ -120,6 +177,9 @@ const (
 
 	// StrategyFitContent はブロックサイズを変えず、コンテンツをブロックに収める
 	StrategyFitContent LayoutStrategy = "fit_content"
+
+	// StrategyColumns はブロックをページ内の段（LayoutAdjustmentOptions.Columns）に均等な高さで流し直す
+	StrategyColumns LayoutStrategy = "columns"
 )
 
 // LayoutAdjustmentOptions はレイアウト自動調整のオプション
@@ -130,8 +190,24 @@ type LayoutAdjustmentOptions struct {
 	// ブロック間の最小間隔
 	MinSpacing float64
 
-	// ページ端からのマージン
+	// ページ端からのマージン（PageLayout.Marginsが0の辺で使う）
 	PageMargin float64
+
+	// 段組み（StrategyColumnsで使う）
+	Columns ColumnOptions
+
+	// Spacing はブロックの組ごとに保つ縦の間隔（StrategyFlowDownで使う。見出しの後の間隔など）
+	Spacing []SpacingConstraint
+
+	// TextHeight はテキストブロックを幅widthで折り返したときの高さを返す（StrategyColumnsで使う）
+	// nilの場合や0以下を返した場合は、ブロックの面積が変わらないものとして推定する
+	TextHeight func(tb TextBlock, width float64) float64
+}
+
+// ColumnOptions はStrategyColumnsの段組み
+type ColumnOptions struct {
+	Count  int     // 段数
+	Gutter float64 // 段の間隔
 }
 
 // DefaultLayoutAdjustmentOptions はデフォルトのオプション
@@ -143,15 +219,63 @@ func DefaultLayoutAdjustmentOptions() LayoutAdjustmentOptions {
 	}
 }
 
+// TextRenderMode はPDFのテキストレンダリングモード（Tr）
+type TextRenderMode int
+
+const (
+	// TextRenderNormal は通常のテキスト表示（塗りつぶし）
+	TextRenderNormal TextRenderMode = 0
+	// TextRenderStroke はテキストの輪郭のみ表示
+	TextRenderStroke TextRenderMode = 1
+	// TextRenderFillStroke は塗りつぶしと輪郭の両方
+	TextRenderFillStroke TextRenderMode = 2
+	// TextRenderInvisible はテキストを非表示（コピー・検索は可能）
+	TextRenderInvisible TextRenderMode = 3
+	// TextRenderFillClip は塗りつぶしてクリッピングパスに追加
+	TextRenderFillClip TextRenderMode = 4
+	// TextRenderStrokeClip は輪郭を描いてクリッピングパスに追加
+	TextRenderStrokeClip TextRenderMode = 5
+	// TextRenderFillStrokeClip は塗りつぶしと輪郭を描いてクリッピングパスに追加
+	TextRenderFillStrokeClip TextRenderMode = 6
+	// TextRenderClip はクリッピングパスに追加のみ（表示されない）
+	TextRenderClip TextRenderMode = 7
+)
+
+// Visible はテキストがページに表示されるモードかを返す
+// OCRのテキストレイヤーなど、不可視（3）とクリッピングのみ（7）のテキストはfalse
+func (m TextRenderMode) Visible() bool {
+	return m != TextRenderInvisible && m != TextRenderClip
+}
+
 // TextElement はテキスト要素（循環参照を避けるため独自に定義）
 type TextElement struct {
-	Text   string
-	X      float64
-	Y      float64
-	Width  float64
-	Height float64
-	Font   string
-	Size   float64
+	Text   string  `json:"text"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Font   string  `json:"font"`
+	Size   float64 `json:"size"`
+
+	BaseFont string `json:"baseFont,omitempty"` // フォントの/BaseFont（太字・斜体の判定に使う。サブセットの接頭辞を含む）
+
+	Color             Color          `json:"color"`             // 塗りつぶし色（rg, g, k など）
+	RenderMode        TextRenderMode `json:"renderMode"`        // テキストレンダリングモード（Tr）
+	HorizontalScaling float64        `json:"horizontalScaling"` // 水平スケーリング（Tz、%。100が等倍）
+
+	// Angle はベースラインの向き（度、反時計回り、-180〜180。0は左から右へ書く通常のテキスト）
+	// 回転したテキストでは、(X, Y)はベースラインの始点、Widthはベースラインに沿った長さ、Heightはそれに垂直な高さ
+	Angle float64 `json:"angle"`
+}
+
+// Bounds はテキスト要素の境界矩形を返す
+// 回転したテキストでは、回転した矩形を囲む軸に平行な矩形
+func (te TextElement) Bounds() Rectangle {
+	if te.Angle == 0 {
+		return Rectangle{X: te.X, Y: te.Y, Width: te.Width, Height: te.Height}
+	}
+
+	return rotatedBounds(te.X, te.Y, te.Width, te.Height, te.Angle)
 }
 
 // ImageFormat は画像フォーマット
@@ -168,12 +292,12 @@ const (
 
 // ImageInfo は画像情報
 type ImageInfo struct {
-	Name        string
-	Width       int
-	Height      int
-	ColorSpace  string
-	BitsPerComp int
-	Filter      string
-	Data        []byte
-	Format      ImageFormat
+	Name        string      `json:"name"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	ColorSpace  string      `json:"colorSpace"`
+	BitsPerComp int         `json:"bitsPerComp"`
+	Filter      string      `json:"filter"`
+	Data        []byte      `json:"data,omitempty"`
+	Format      ImageFormat `json:"format"`
 }
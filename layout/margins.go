@@ -0,0 +1,95 @@
+package layout
+
+// Margins はページの余白（本文を置く範囲の外側、pt）
+// 0の辺はレイアウト調整・ページ分割のオプションのPageMarginを使う
+type Margins struct {
+	Top    float64 `json:"top"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+	Right  float64 `json:"right"`
+}
+
+// BlockAnchor はブロックをページのどこに固定するか
+type BlockAnchor string
+
+const (
+	// AnchorNone は固定しない（レイアウト調整・ページ分割で動かす）
+	AnchorNone BlockAnchor = ""
+	// AnchorTop はページの上端に固定する（ヘッダー）
+	AnchorTop BlockAnchor = "top"
+	// AnchorBottom はページの下端に固定する（フッター）
+	AnchorBottom BlockAnchor = "bottom"
+)
+
+// contentArea は本文（固定していないブロック）を置く範囲を返す
+// 各辺はMargins（0の辺はmargin）の内側で、上端・下端に固定したブロックがある場合はそのブロックからspacingを空けた内側
+func (pl *PageLayout) contentArea(margin, spacing float64) Rectangle {
+	side := func(m float64) float64 {
+		if m > 0 {
+			return m
+		}
+		return margin
+	}
+	left, right := side(pl.Margins.Left), pl.Width-side(pl.Margins.Right)
+	bottom, top := side(pl.Margins.Bottom), pl.Height-side(pl.Margins.Top)
+
+	for _, ref := range pl.anchoredRefs() {
+		rect, _ := pl.blockRect(ref)
+		switch pl.anchor(ref) {
+		case AnchorTop:
+			top = min(top, rect.Y-spacing)
+		case AnchorBottom:
+			bottom = max(bottom, rect.Y+rect.Height+spacing)
+		}
+	}
+	return Rectangle{X: left, Y: bottom, Width: right - left, Height: top - bottom}
+}
+
+// anchor はテキストブロック・画像ブロックの固定位置を返す
+func (pl *PageLayout) anchor(ref BlockRef) BlockAnchor {
+	switch ref.Type {
+	case ContentBlockTypeText:
+		return pl.TextBlocks[ref.Index].Anchor
+	case ContentBlockTypeImage:
+		return pl.Images[ref.Index].Anchor
+	}
+	return AnchorNone
+}
+
+// anchoredRefs はページに固定したブロック（グループに属さないもの）を返す
+// グループのメンバーの固定位置は無視する（グループとして動かす）
+func (pl *PageLayout) anchoredRefs() []BlockRef {
+	grouped := pl.groupedRefs()
+	var refs []BlockRef
+	for i, tb := range pl.TextBlocks {
+		ref := BlockRef{Type: ContentBlockTypeText, Index: i}
+		if _, ok := grouped[ref]; !ok && tb.Anchor != AnchorNone {
+			refs = append(refs, ref)
+		}
+	}
+	for i, ib := range pl.Images {
+		ref := BlockRef{Type: ContentBlockTypeImage, Index: i}
+		if _, ok := grouped[ref]; !ok && ib.Anchor != AnchorNone {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// blankPage はplと同じ幅・余白で高さheightの、固定したブロック（ヘッダー・フッター）だけを持つページを返す
+// 上端に固定したブロックはページの上端からの距離を、下端に固定したブロックは下端からの距離を保つ
+func (pl *PageLayout) blankPage(height float64) *PageLayout {
+	page := &PageLayout{
+		Width:   pl.Width,
+		Height:  height,
+		Margins: pl.Margins,
+	}
+	for _, ref := range pl.anchoredRefs() {
+		offsetY := 0.0
+		if pl.anchor(ref) == AnchorTop {
+			offsetY = height - pl.Height
+		}
+		page.appendUnit(pl, flowUnit{refs: []BlockRef{ref}}, offsetY)
+	}
+	return page
+}
@@ -6,6 +6,7 @@ import (
 )
 
 // MoveBlock はブロックを移動する
+// ContentBlockTypeGroupの場合は、グループのメンバーをまとめて移動する
 func (pl *PageLayout) MoveBlock(blockType ContentBlockType, index int, offsetX, offsetY float64) error {
 	switch blockType {
 	case ContentBlockTypeText:
@@ -20,13 +21,20 @@ func (pl *PageLayout) MoveBlock(blockType ContentBlockType, index int, offsetX,
 		}
 		pl.Images[index].X += offsetX
 		pl.Images[index].Y += offsetY
+	case ContentBlockTypeGroup:
+		if index < 0 || index >= len(pl.Groups) {
+			return fmt.Errorf("group index %d out of range [0, %d)", index, len(pl.Groups))
+		}
+		return pl.moveGroup(index, offsetX, offsetY)
 	default:
 		return fmt.Errorf("unsupported block type: %s", blockType)
 	}
+	pl.refreshGroupRects()
 	return nil
 }
 
 // ResizeBlock はブロックをリサイズする
+// ContentBlockTypeGroupの場合は、グループの左下を固定してメンバーの位置と大きさを同じ比率で変える
 func (pl *PageLayout) ResizeBlock(blockType ContentBlockType, index int, newWidth, newHeight float64) error {
 	switch blockType {
 	case ContentBlockTypeText:
@@ -39,11 +47,17 @@ func (pl *PageLayout) ResizeBlock(blockType ContentBlockType, index int, newWidt
 		if index < 0 || index >= len(pl.Images) {
 			return fmt.Errorf("image block index %d out of range [0, %d)", index, len(pl.Images))
 		}
-		pl.Images[index].PlacedWidth = newWidth
-		pl.Images[index].PlacedHeight = newHeight
+		bounds := pl.Images[index].Bounds()
+		pl.Images[index].SetBounds(Rectangle{X: bounds.X, Y: bounds.Y, Width: newWidth, Height: newHeight})
+	case ContentBlockTypeGroup:
+		if index < 0 || index >= len(pl.Groups) {
+			return fmt.Errorf("group index %d out of range [0, %d)", index, len(pl.Groups))
+		}
+		return pl.resizeGroup(index, newWidth, newHeight)
 	default:
 		return fmt.Errorf("unsupported block type: %s", blockType)
 	}
+	pl.refreshGroupRects()
 	return nil
 }
 
@@ -70,7 +84,14 @@ func (pl *PageLayout) DetectOverlaps() []BlockOverlap {
 }
 
 // calculateOverlapArea は2つのブロックの重なり面積を計算する
+// 回転したブロックは、境界矩形ではなく回転した輪郭どうしの重なりを計算する
 func calculateOverlapArea(block1, block2 ContentBlock) float64 {
+	outline1, rotated1 := blockOutline(block1)
+	outline2, rotated2 := blockOutline(block2)
+	if rotated1 || rotated2 {
+		return polygonOverlapArea(outline1, outline2)
+	}
+
 	bounds1 := block1.Bounds()
 	bounds2 := block2.Bounds()
 
@@ -105,53 +126,12 @@ func calculateOverlapArea(block1, block2 ContentBlock) float64 {
 }
 
 // SplitIntoPages はPageLayoutを複数ページに分割する
+// ブロックは分割せず、収まらない場合は次のページに送る
+// グループはメンバーを同じページに置き、分割したページのGroupsに作り直す
 func (pl *PageLayout) SplitIntoPages(maxHeight, minSpacing, pageMargin float64) ([]*PageLayout, error) {
-	var pages []*PageLayout
-
-	currentPage := &PageLayout{
-		Width:  pl.Width,
-		Height: maxHeight,
-	}
-	currentY := maxHeight - pageMargin
-
-	blocks := pl.SortedContentBlocks()
-
-	for _, block := range blocks {
-		bounds := block.Bounds()
-
-		// 現在のページに収まらない場合
-		if currentY-bounds.Height < pageMargin {
-			// 現在のページにコンテンツがある場合のみ追加
-			if len(currentPage.TextBlocks) > 0 || len(currentPage.Images) > 0 {
-				pages = append(pages, currentPage)
-			}
-
-			// 新しいページを作成
-			currentPage = &PageLayout{
-				Width:  pl.Width,
-				Height: maxHeight,
-			}
-			currentY = maxHeight - pageMargin
-		}
-
-		// ブロックを新しいY座標で追加
-		newY := currentY - bounds.Height
-		switch block.Type() {
-		case ContentBlockTypeText:
-			tb := block.(TextBlock)
-			tb.Rect.Y = newY
-			currentPage.TextBlocks = append(currentPage.TextBlocks, tb)
-		case ContentBlockTypeImage:
-			ib := block.(ImageBlock)
-			ib.Y = newY
-			currentPage.Images = append(currentPage.Images, ib)
-		}
-
-		currentY = newY - minSpacing
-	}
-
-	// 最後のページを追加（空でも追加）
-	pages = append(pages, currentPage)
-
-	return pages, nil
+	return pl.SplitIntoPagesWithOptions(PageSplitOptions{
+		MaxHeight:  maxHeight,
+		MinSpacing: minSpacing,
+		PageMargin: pageMargin,
+	})
 }
@@ -0,0 +1,268 @@
+package layout
+
+import "strings"
+
+// PageSplitOptions はSplitIntoPagesWithOptionsの設定
+type PageSplitOptions struct {
+	MaxHeight  float64 // 分割後のページの高さ
+	MinSpacing float64 // ブロック間の最小間隔
+	PageMargin float64 // ページ端からのマージン（PageLayout.Marginsが0の辺で使う）
+
+	// BreakTextBlocks はページに収まらないテキストブロックを行の境界で分割するか
+	// falseの場合はブロックを分割せず、次のページに送る（SplitIntoPagesと同じ）
+	// 分割できるのは、Textの行数がLinesと同じブロック（抽出したまま、または行ごとに編集したもの）だけ
+	BreakTextBlocks bool
+
+	// Orphans は分割したブロックのうち、ページの終わりに残す最小の行数（0の場合は1）
+	Orphans int
+	// Widows は分割したブロックのうち、次のページに送る最小の行数（0の場合は1）
+	Widows int
+
+	// KeepWithNext がtrueを返すブロック（見出しなど）は、次のブロックの先頭と同じページに置く
+	KeepWithNext func(block ContentBlock) bool
+	// Unbreakable がtrueを返すテキストブロックは、BreakTextBlocksがtrueでも分割しない
+	Unbreakable func(block ContentBlock) bool
+}
+
+// SplitIntoPagesWithOptions はPageLayoutを複数ページに分割する
+// SplitIntoPagesに加え、テキストブロックの行の境界での分割（ウィドウ・オーファンの制御付き）、
+// 見出しと次のブロックを同じページに置く指定、分割しないブロックの指定ができる
+// ページに固定したブロック（Anchor）はすべてのページに写し、その内側とMarginsの内側に本文を並べる
+// ブロックが1ページに収まらない場合は、指定を守れなくても分割する（分割できないブロックはそのまま置く）
+// 設計書: docs/layout_auto_adjustment_design.md
+func (pl *PageLayout) SplitIntoPagesWithOptions(opts PageSplitOptions) ([]*PageLayout, error) {
+	var pages []*PageLayout
+
+	// 各ページはページに固定したブロック（ヘッダー・フッター）から始め、その内側の本文の範囲に並べる
+	currentPage := pl.blankPage(opts.MaxHeight)
+	anchored := len(currentPage.TextBlocks) + len(currentPage.Images)
+	area := currentPage.contentArea(opts.PageMargin, opts.MinSpacing)
+	top, bottom := area.Y+area.Height, area.Y
+	currentY := top
+
+	isEmpty := func() bool {
+		return len(currentPage.TextBlocks)+len(currentPage.Images) == anchored
+	}
+	newPage := func() {
+		// 現在のページにコンテンツがある場合のみ追加
+		if !isEmpty() {
+			pages = append(pages, currentPage)
+		}
+		currentPage = pl.blankPage(opts.MaxHeight)
+		currentY = top
+	}
+
+	// 線・図と注釈は元のページの位置に結び付いているため、分割したページには含めない
+	units := pl.flowUnits()
+	for i := 0; i < len(units); i++ {
+		u := units[i]
+		bounds := u.block.Bounds()
+
+		// 次のブロックと同じページに置くブロックの連なりが収まらない場合は、新しいページから始める
+		// 連なりが1ページに収まらない場合は指定を無視する
+		if opts.keepWithNext(u.block) && i+1 < len(units) {
+			need := opts.keepHeight(units[i:])
+			if currentY-need < bottom && !isEmpty() && need <= top-bottom {
+				newPage()
+			}
+		}
+
+		// 収まらないテキストブロックは、行の境界で分割できる場合は分割する
+		if currentY-bounds.Height < bottom {
+			tb, ok := u.block.(TextBlock)
+			k := 0
+			if ok && opts.breakable(tb) {
+				k = opts.breakLine(tb, currentY-bottom, isEmpty())
+			}
+			if k > 0 {
+				first, rest := splitTextBlock(tb, k)
+				first.Rect.Y = currentY - first.Rect.Height
+				currentPage.TextBlocks = append(currentPage.TextBlocks, first)
+				newPage()
+				// 残りは新しいページで同じように配置する
+				units[i] = flowUnit{block: rest}
+				i--
+				continue
+			}
+			if !isEmpty() {
+				newPage()
+				i--
+				continue
+			}
+		}
+
+		// ブロックを新しいY座標で追加
+		newY := currentY - bounds.Height
+		if rest, ok := u.block.(TextBlock); ok && u.refs == nil {
+			// 分割したブロックの残り（元のレイアウトにないブロック）
+			rest.Rect.Y = newY
+			currentPage.TextBlocks = append(currentPage.TextBlocks, rest)
+		} else {
+			currentPage.appendUnit(pl, u, newY-bounds.Y)
+		}
+
+		currentY = newY - opts.MinSpacing
+	}
+
+	// 最後のページを追加（空でも追加）
+	pages = append(pages, currentPage)
+
+	return pages, nil
+}
+
+// keepWithNext はブロックを次のブロックと同じページに置くかを返す
+func (opts PageSplitOptions) keepWithNext(block ContentBlock) bool {
+	return opts.KeepWithNext != nil && opts.KeepWithNext(block)
+}
+
+// breakable はテキストブロックを行の境界で分割できるかを返す
+func (opts PageSplitOptions) breakable(tb TextBlock) bool {
+	if !opts.BreakTextBlocks || (opts.Unbreakable != nil && opts.Unbreakable(tb)) {
+		return false
+	}
+	return len(tb.Lines) >= 2 && strings.Count(tb.Text, "\n")+1 == len(tb.Lines)
+}
+
+// keepHeight はunits[0]から、KeepWithNextで次のブロックと同じページに置くブロックの連なりに必要な高さを返す
+// 連なりの最後のブロックは、分割できる場合は先頭のOrphans行だけを数える
+func (opts PageSplitOptions) keepHeight(units []flowUnit) float64 {
+	need := units[0].block.Bounds().Height
+	for j := 0; j+1 < len(units) && opts.keepWithNext(units[j].block); j++ {
+		next := units[j+1].block
+		height := next.Bounds().Height
+		if tb, ok := next.(TextBlock); ok && !opts.keepWithNext(next) && opts.breakable(tb) {
+			k := min(max(opts.Orphans, 1), len(tb.Lines))
+			height = lineBottomOffset(tb, k-1)
+		}
+		need += opts.MinSpacing + height
+	}
+	return need
+}
+
+// breakLine はテキストブロックの上から高さavailableに収まる行数のうち、分割に使う行数を返す（分割しない場合は0）
+// 空のページでも収まらないブロックは、ウィドウ・オーファンの指定を守れなくても分割する
+func (opts PageSplitOptions) breakLine(tb TextBlock, available float64, emptyPage bool) int {
+	fit := 0
+	for fit < len(tb.Lines) && lineBottomOffset(tb, fit) <= available {
+		fit++
+	}
+
+	k := min(fit, len(tb.Lines)-max(opts.Widows, 1))
+	if k >= max(opts.Orphans, 1) {
+		return k
+	}
+	if emptyPage {
+		return min(fit, len(tb.Lines)-1)
+	}
+	return 0
+}
+
+// lineScale はブロックの矩形の高さと、行の上端から下端までの高さの比を返す
+// 行の座標は抽出したときのままなので、移動・リサイズしたブロックでも使えるように矩形に合わせる
+func lineScale(tb TextBlock) float64 {
+	first, last := tb.Lines[0].Rect, tb.Lines[len(tb.Lines)-1].Rect
+	span := first.Y + first.Height - last.Y
+	if span <= 0 {
+		return 1
+	}
+	return tb.Rect.Height / span
+}
+
+// lineBottomOffset はブロックの上端からi番目の行の下端までの距離を返す
+func lineBottomOffset(tb TextBlock, i int) float64 {
+	first := tb.Lines[0].Rect
+	return (first.Y + first.Height - tb.Lines[i].Rect.Y) * lineScale(tb)
+}
+
+// lineTopOffset はブロックの上端からi番目の行の上端までの距離を返す
+func lineTopOffset(tb TextBlock, i int) float64 {
+	first, line := tb.Lines[0].Rect, tb.Lines[i].Rect
+	return (first.Y + first.Height - (line.Y + line.Height)) * lineScale(tb)
+}
+
+// splitTextBlock はテキストブロックを先頭のk行と残りに分ける
+// 2つの矩形はブロックの矩形を行の境界で切ったもの（位置は元のブロックのまま）
+func splitTextBlock(tb TextBlock, k int) (first, rest TextBlock) {
+	texts := strings.Split(tb.Text, "\n")
+	blockTop := tb.Rect.Y + tb.Rect.Height
+
+	first, rest = tb, tb
+	first.Text = strings.Join(texts[:k], "\n")
+	rest.Text = strings.Join(texts[k:], "\n")
+	first.Lines = tb.Lines[:k:k]
+	rest.Lines = tb.Lines[k:]
+
+	firstHeight := lineBottomOffset(tb, k-1)
+	first.Rect.Y = blockTop - firstHeight
+	first.Rect.Height = firstHeight
+	rest.Rect.Height = tb.Rect.Height - lineTopOffset(tb, k)
+
+	first.Elements, rest.Elements = nil, nil
+	for _, line := range first.Lines {
+		first.Elements = append(first.Elements, line.Elements...)
+	}
+	for _, line := range rest.Lines {
+		rest.Elements = append(rest.Elements, line.Elements...)
+	}
+
+	first.Paragraphs, rest.Paragraphs = splitParagraphs(tb.Paragraphs, len(tb.Lines), k)
+	return first, rest
+}
+
+// splitParagraphs は段落を先頭のk行を含むものと残りに分ける。行をまたぐ段落は2つに分ける
+// 段落の行数の合計がブロックの行数と合わない場合は段落を捨てる
+func splitParagraphs(paragraphs []TextParagraph, lines, k int) (first, rest []TextParagraph) {
+	count := 0
+	for _, p := range paragraphs {
+		count += len(p.Lines)
+	}
+	if count != lines {
+		return nil, nil
+	}
+
+	start := 0
+	for _, p := range paragraphs {
+		end := start + len(p.Lines)
+		switch {
+		case end <= k:
+			first = append(first, p)
+		case start >= k:
+			rest = append(rest, p)
+		default:
+			head, tail := p, p
+			head.Lines = p.Lines[: k-start : k-start]
+			tail.Lines = p.Lines[k-start:]
+			head.Text, tail.Text = splitParagraphText(p.Text, head.Lines)
+			head.Rect = linesRect(head.Lines)
+			tail.Rect = linesRect(tail.Lines)
+			tail.Indent = 0
+			first = append(first, head)
+			rest = append(rest, tail)
+		}
+		start = end
+	}
+	return first, rest
+}
+
+// splitParagraphText は段落のテキストを、先頭の行（lines）のテキストとそれ以降に分ける
+// 行の境目の空白は段落のテキストの作り方（言語）で異なるため、行のテキストを順に探して区切る
+func splitParagraphText(text string, lines []TextLine) (head, tail string) {
+	pos := 0
+	for _, line := range lines {
+		i := strings.Index(text[pos:], line.Text)
+		if i < 0 {
+			break
+		}
+		pos += i + len(line.Text)
+	}
+	return text[:pos], strings.TrimLeft(text[pos:], " ")
+}
+
+// linesRect は行全体のバウンディングボックスを返す
+func linesRect(lines []TextLine) Rectangle {
+	rect := lines[0].Rect
+	for _, line := range lines[1:] {
+		rect = rect.Union(line.Rect)
+	}
+	return rect
+}
@@ -0,0 +1,129 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ReflowLineSpacing はReflowで組む行の間隔（フォントサイズに対する倍率）
+const ReflowLineSpacing = 1.2
+
+// TextMeasurer はテキストの幅を測る（*gopdf.TTFFontとgopdf.StandardFontが実装する）
+type TextMeasurer interface {
+	TextWidth(text string, fontSize float64) (float64, error)
+}
+
+// Reflow はブロックのテキストを幅widthで折り返し直し、Lines・Elements・Paragraphsを作り直す
+// 翻訳などでTextを書き換えた後や、ブロックの幅を変えた後に使う
+//   - 折り返す前の段落は、Textが抽出したままの場合はParagraphs、書き換えた場合はTextの改行で区切ったもの
+//   - 行はfontで測った幅で、空白とCJKの文字の間で区切る（1語で幅を超える場合は文字の間）
+//   - 1行目のベースラインは上端からsize下、行の間隔はsizeのReflowLineSpacing倍
+//
+// 上端と左端は変えず、Rect.Widthをwidth、Rect.Heightを行を組んだ高さにする。TextとFontSizeも組んだ結果にする
+// 回転したテキストのブロックは折り返せない
+// 設計書: docs/layout_auto_adjustment_design.md
+func (tb *TextBlock) Reflow(font TextMeasurer, size, width float64) error {
+	if font == nil {
+		return errors.New("reflow: font is nil")
+	}
+	if size <= 0 || width <= 0 {
+		return fmt.Errorf("reflow: invalid font size %v or width %v", size, width)
+	}
+	if tb.Angle != 0 {
+		return fmt.Errorf("reflow: cannot reflow rotated text (angle %v)", tb.Angle)
+	}
+
+	var measureErr error
+	measure := func(s string) float64 {
+		w, err := font.TextWidth(s, size)
+		if err != nil && measureErr == nil {
+			measureErr = fmt.Errorf("reflow: failed to measure %q: %w", s, err)
+		}
+		return w
+	}
+
+	// 段落ごとに折り返し、行を上から組む
+	top := tb.Rect.Y + tb.Rect.Height
+	baseline := top - size
+	var lines []TextLine
+	var paragraphs []TextParagraph
+	for _, source := range tb.reflowSources() {
+		paragraph := TextParagraph{Text: source}
+		for _, text := range WrapText(source, width, measure) {
+			elem := TextElement{
+				Text:              text,
+				X:                 tb.Rect.X,
+				Y:                 baseline,
+				Width:             measure(text),
+				Height:            size,
+				Font:              tb.Font,
+				Size:              size,
+				Color:             tb.Color,
+				RenderMode:        tb.RenderMode,
+				HorizontalScaling: tb.HorizontalScaling,
+			}
+			line := TextLine{
+				Text:     text,
+				Elements: []TextElement{elem},
+				Rect:     elem.Bounds(),
+				Baseline: baseline,
+				FontSize: size,
+			}
+			if len(paragraph.Lines) == 0 {
+				paragraph.Rect = line.Rect
+			} else {
+				paragraph.Rect = paragraph.Rect.Union(line.Rect)
+			}
+			paragraph.Lines = append(paragraph.Lines, line)
+			lines = append(lines, line)
+			baseline -= size * ReflowLineSpacing
+		}
+		paragraphs = append(paragraphs, paragraph)
+	}
+	if measureErr != nil {
+		return measureErr
+	}
+
+	texts := make([]string, len(lines))
+	var elements []TextElement
+	for i, line := range lines {
+		texts[i] = line.Text
+		elements = append(elements, line.Elements...)
+	}
+	height := size + float64(len(lines)-1)*size*ReflowLineSpacing
+
+	tb.Text = strings.Join(texts, "\n")
+	tb.Elements = elements
+	tb.Lines = lines
+	tb.Paragraphs = paragraphs
+	tb.FontSize = size
+	tb.Rect = Rectangle{X: tb.Rect.X, Y: top - height, Width: width, Height: height}
+	return nil
+}
+
+// reflowSources は折り返す前の段落のテキストを返す
+// Textが行を改行で連結したまま（抽出したまま、または前回のReflowの結果）の場合は、行の境目を取り除いたParagraphsのテキストを使う
+func (tb *TextBlock) reflowSources() []string {
+	if len(tb.Paragraphs) > 0 && len(tb.Lines) > 0 {
+		texts := make([]string, len(tb.Lines))
+		for i, line := range tb.Lines {
+			texts[i] = line.Text
+		}
+		if strings.Join(texts, "\n") == tb.Text {
+			sources := make([]string, len(tb.Paragraphs))
+			for i, paragraph := range tb.Paragraphs {
+				sources[i] = paragraph.Text
+			}
+			return sources
+		}
+	}
+	return strings.Split(tb.Text, "\n")
+}
+
+// isCJKRune は漢字・ひらがな・カタカナ・全角記号か判定
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
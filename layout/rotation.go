@@ -0,0 +1,140 @@
+package layout
+
+import "math"
+
+// rotatedBounds は(x, y)を原点として反時計回りにangle度回転した幅width、高さheightの矩形を囲む、軸に平行な矩形を返す
+func rotatedBounds(x, y, width, height, angle float64) Rectangle {
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	xs := []float64{0, width * cos, width*cos - height*sin, -height * sin}
+	ys := []float64{0, width * sin, width*sin + height*cos, height * cos}
+
+	minX, maxX := xs[0], xs[0]
+	minY, maxY := ys[0], ys[0]
+	for i := 1; i < 4; i++ {
+		minX, maxX = math.Min(minX, xs[i]), math.Max(maxX, xs[i])
+		minY, maxY = math.Min(minY, ys[i]), math.Max(maxY, ys[i])
+	}
+	return Rectangle{X: x + minX, Y: y + minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// rotatedCorners は(x, y)を原点として反時計回りにangle度回転した矩形の4隅を、反時計回りの順で返す
+func rotatedCorners(x, y, width, height, angle float64) []Point {
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	return []Point{
+		{X: x, Y: y},
+		{X: x + width*cos, Y: y + width*sin},
+		{X: x + width*cos - height*sin, Y: y + width*sin + height*cos},
+		{X: x - height*sin, Y: y + height*cos},
+	}
+}
+
+// Outline はテキストブロックの輪郭（4隅、反時計回り）を返す
+// 回転したテキストのブロックでは、要素を囲むベースラインの向きの矩形。回転していないブロックと要素のないブロックはRectの4隅
+func (tb TextBlock) Outline() []Point {
+	if tb.Angle == 0 || len(tb.Elements) == 0 {
+		return rotatedCorners(tb.Rect.X, tb.Rect.Y, tb.Rect.Width, tb.Rect.Height, 0)
+	}
+
+	// 要素をベースラインの向きがX軸になる座標系に写して囲み、元の向きに戻す
+	rad := tb.Angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	var local Rectangle
+	for i, elem := range tb.Elements {
+		r := Rectangle{X: elem.X*cos + elem.Y*sin, Y: -elem.X*sin + elem.Y*cos, Width: elem.Width, Height: elem.Height}
+		if i == 0 {
+			local = r
+		} else {
+			local = local.Union(r)
+		}
+	}
+	return rotatedCorners(local.X*cos-local.Y*sin, local.X*sin+local.Y*cos, local.Width, local.Height, tb.Angle)
+}
+
+// Outline は画像ブロックの輪郭（回転した画像の4隅、反時計回り）を返す
+func (ib ImageBlock) Outline() []Point {
+	return rotatedCorners(ib.X, ib.Y, ib.PlacedWidth, ib.PlacedHeight, ib.Angle)
+}
+
+// SetBounds はBounds()がrectになるように画像を置き直す（回転は保つ）
+// 回転していない画像と90度の倍数で回転した画像はrectにちょうど合わせ、それ以外の角度では縦横比を保ってrectに収める
+func (ib *ImageBlock) SetBounds(rect Rectangle) {
+	switch math.Mod(math.Abs(ib.Angle), 180) {
+	case 0:
+		ib.PlacedWidth, ib.PlacedHeight = rect.Width, rect.Height
+	case 90:
+		ib.PlacedWidth, ib.PlacedHeight = rect.Height, rect.Width
+	default:
+		bounds := ib.Bounds()
+		scale := 1.0
+		if bounds.Width > 0 && bounds.Height > 0 {
+			scale = math.Min(rect.Width/bounds.Width, rect.Height/bounds.Height)
+		}
+		ib.PlacedWidth *= scale
+		ib.PlacedHeight *= scale
+	}
+
+	// 回転した矩形を囲む矩形の左下がrectの左下に来るよう、原点を置く
+	offset := rotatedBounds(0, 0, ib.PlacedWidth, ib.PlacedHeight, ib.Angle)
+	ib.X, ib.Y = rect.X-offset.X, rect.Y-offset.Y
+}
+
+// blockOutline はブロックの輪郭を返す
+// 回転したブロック（テキストブロック・画像ブロック）は回転した輪郭とtrue、それ以外は境界矩形の4隅とfalse
+func blockOutline(block ContentBlock) ([]Point, bool) {
+	switch b := block.(type) {
+	case TextBlock:
+		if b.Angle != 0 && len(b.Elements) > 0 {
+			return b.Outline(), true
+		}
+	case ImageBlock:
+		if b.Angle != 0 {
+			return b.Outline(), true
+		}
+	}
+	bounds := block.Bounds()
+	return rotatedCorners(bounds.X, bounds.Y, bounds.Width, bounds.Height, 0), false
+}
+
+// polygonOverlapArea は2つの凸多角形（頂点は反時計回り）の重なりの面積を返す
+func polygonOverlapArea(subject, clip []Point) float64 {
+	polygon := subject
+	for i := range clip {
+		if len(polygon) == 0 {
+			return 0
+		}
+		polygon = clipPolygon(polygon, clip[i], clip[(i+1)%len(clip)])
+	}
+	return polygonArea(polygon)
+}
+
+// clipPolygon は多角形を、辺a→bの左側（反時計回りの多角形の内側）で切り取る（Sutherland-Hodgman）
+func clipPolygon(polygon []Point, a, b Point) []Point {
+	side := func(p Point) float64 {
+		return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+	}
+	var result []Point
+	for i, curr := range polygon {
+		prev := polygon[(i+len(polygon)-1)%len(polygon)]
+		sc, sp := side(curr), side(prev)
+		if (sc >= 0) != (sp >= 0) {
+			t := sp / (sp - sc)
+			result = append(result, Point{X: prev.X + (curr.X-prev.X)*t, Y: prev.Y + (curr.Y-prev.Y)*t})
+		}
+		if sc >= 0 {
+			result = append(result, curr)
+		}
+	}
+	return result
+}
+
+// polygonArea は多角形の面積を返す
+func polygonArea(polygon []Point) float64 {
+	area := 0.0
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	return math.Abs(area) / 2
+}
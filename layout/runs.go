@@ -0,0 +1,271 @@
+package layout
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextRun はテキストブロックの中の、同じスタイルで続くテキスト（太字・斜体・色・フォントの違う部分）
+// 設計書: docs/pdf_translation_design.md
+type TextRun struct {
+	Text   string  `json:"text"`
+	Font   string  `json:"font"` // フォントのリソース名（TextElement.Fontと同じ）
+	Size   float64 `json:"size"`
+	Color  Color   `json:"color"`
+	Bold   bool    `json:"bold,omitempty"`
+	Italic bool    `json:"italic,omitempty"`
+}
+
+// SameStyle はrunとotherのスタイルが同じかを返す（フォントサイズは0.1pt未満の差を同じとみなす）
+func (r TextRun) SameStyle(other TextRun) bool {
+	return r.Font == other.Font && math.Abs(r.Size-other.Size) < 0.1 && r.Color == other.Color &&
+		r.Bold == other.Bold && r.Italic == other.Italic
+}
+
+// FontStyle はフォント名（/BaseFont）から太字・斜体かを判定する
+// "Helvetica-BoldOblique"、"ABCDEF+NotoSans-SemiBold"、"Arial,Italic" のような名前の接尾辞を見る
+func FontStyle(name string) (bold, italic bool) {
+	if i := strings.IndexByte(name, '+'); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.ToLower(name)
+	for _, w := range []string{"bold", "black", "heavy", "demi", "semibold"} {
+		if strings.Contains(name, w) {
+			bold = true
+		}
+	}
+	italic = strings.Contains(name, "italic") || strings.Contains(name, "oblique")
+	return bold, italic
+}
+
+// NewTextRun は要素のテキストとスタイルのTextRunを返す（太字・斜体はBaseFontから判定する）
+func NewTextRun(elem TextElement) TextRun {
+	bold, italic := FontStyle(elem.BaseFont)
+	return TextRun{Text: elem.Text, Font: elem.Font, Size: elem.Size, Color: elem.Color, Bold: bold, Italic: italic}
+}
+
+// AppendRun はrunsの末尾にrunを加える（末尾と同じスタイルの場合はテキストをつなげる）
+func AppendRun(runs []TextRun, run TextRun) []TextRun {
+	if n := len(runs); n > 0 && runs[n-1].SameStyle(run) {
+		runs[n-1].Text += run.Text
+		return runs
+	}
+	return append(runs, run)
+}
+
+// StyleRuns はブロックのスタイルの並び（Runs）を返す
+// Runsを連結したテキストが、空白の違いを除いてTextと一致しない場合（Runsを更新せずにTextを書き換えた場合など）と、
+// スタイルが1つしかない場合はnil（ブロック全体をFont・FontSize・Colorで描く）
+func (tb TextBlock) StyleRuns() []TextRun {
+	if len(tb.Runs) < 2 {
+		return nil
+	}
+	var text strings.Builder
+	for _, run := range tb.Runs {
+		text.WriteString(run.Text)
+	}
+	if strings.Join(strings.Fields(text.String()), " ") != strings.Join(strings.Fields(tb.Text), " ") {
+		return nil
+	}
+	return tb.Runs
+}
+
+// styledRune はスタイル（runsのインデックス）付きの文字
+type styledRune struct {
+	r   rune
+	run int
+	pos int // runsを連結したテキストでのバイト位置
+}
+
+// WrapText はテキストを幅maxWidthの行に折り返す（改行はそのまま行の区切りにする）
+// 語の区切りは空白とCJKの文字の間で、1語で幅を超える場合は文字の間で区切る
+// maxWidthが0以下の場合は折り返さない
+func WrapText(text string, maxWidth float64, measure func(string) float64) []string {
+	lines := wrapStyled(styledRunes([]TextRun{{Text: text}}), maxWidth, func(line []styledRune) float64 {
+		return measure(styledString(line))
+	})
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = styledString(line)
+	}
+	return result
+}
+
+// WrapRuns はスタイルの並びを、WrapTextと同じ規則で幅maxWidthの行に折り返す
+// measureはrunのスタイルでのtextの幅。各行は同じスタイルの部分ごとのTextRun（空の行は空）
+func WrapRuns(runs []TextRun, maxWidth float64, measure func(text string, run TextRun) float64) [][]TextRun {
+	lines := wrapStyled(styledRunes(runs), maxWidth, func(line []styledRune) float64 {
+		var width float64
+		for _, seg := range styledSegments(line) {
+			width += measure(styledString(seg), runs[seg[0].run])
+		}
+		return width
+	})
+
+	result := make([][]TextRun, len(lines))
+	for i, line := range lines {
+		for _, seg := range styledSegments(line) {
+			run := runs[seg[0].run]
+			run.Text = styledString(seg)
+			result[i] = append(result[i], run)
+		}
+	}
+	return result
+}
+
+// WrapRunRanges はWrapRunsと同じ規則で折り返し、各行の範囲をrunsを連結したテキストでのバイト位置[start, end)で返す
+// 行の途中の空白は範囲に含まれる（WrapRunsの行では1つの ' ' にまとめられる）。空の行は前の行の終わりの位置で長さ0
+func WrapRunRanges(runs []TextRun, maxWidth float64, measure func(text string, run TextRun) float64) [][2]int {
+	lines := wrapStyled(styledRunes(runs), maxWidth, func(line []styledRune) float64 {
+		var width float64
+		for _, seg := range styledSegments(line) {
+			width += measure(styledString(seg), runs[seg[0].run])
+		}
+		return width
+	})
+
+	result := make([][2]int, len(lines))
+	end := 0
+	for i, line := range lines {
+		if len(line) > 0 {
+			last := line[len(line)-1]
+			end = last.pos + utf8.RuneLen(last.r)
+			result[i] = [2]int{line[0].pos, end}
+		} else {
+			result[i] = [2]int{end, end}
+		}
+	}
+	return result
+}
+
+// styledRunes はrunsのテキストを、スタイル付きの文字の並びにする
+func styledRunes(runs []TextRun) []styledRune {
+	var text []styledRune
+	pos := 0
+	for i, run := range runs {
+		for j, r := range run.Text {
+			text = append(text, styledRune{r: r, run: i, pos: pos + j})
+		}
+		pos += len(run.Text)
+	}
+	return text
+}
+
+// styledString は文字の並びの文字列を返す
+func styledString(text []styledRune) string {
+	var b strings.Builder
+	for _, sr := range text {
+		b.WriteRune(sr.r)
+	}
+	return b.String()
+}
+
+// styledSegments は文字の並びを、同じスタイルの連続した部分に分ける
+func styledSegments(text []styledRune) [][]styledRune {
+	var segments [][]styledRune
+	start := 0
+	for i := 1; i <= len(text); i++ {
+		if i == len(text) || text[i].run != text[start].run {
+			segments = append(segments, text[start:i])
+			start = i
+		}
+	}
+	return segments
+}
+
+// wrapStyled は文字の並びを幅maxWidthの行に折り返す（WrapText・WrapRunsの本体）
+func wrapStyled(text []styledRune, maxWidth float64, measure func([]styledRune) float64) [][]styledRune {
+	if maxWidth <= 0 {
+		maxWidth = math.Inf(1)
+	}
+	var lines [][]styledRune
+	for _, paragraph := range splitStyled(text) {
+		var line []styledRune
+		for _, token := range wrapTokens(paragraph) {
+			candidate := append(line[:len(line):len(line)], token...)
+			if len(line) == 0 {
+				candidate = trimLeadingSpaces(token)
+			}
+			if measure(candidate) <= maxWidth {
+				line = candidate
+				continue
+			}
+			if len(line) > 0 {
+				lines = append(lines, line)
+				line = nil
+			}
+			// 1語で幅を超える場合は、入る文字数ごとに区切る
+			word := trimLeadingSpaces(token)
+			for len(word) > 0 {
+				n := 1
+				for n < len(word) && measure(word[:n+1]) <= maxWidth {
+					n++
+				}
+				if n == len(word) {
+					line = append(line, word...)
+					break
+				}
+				lines = append(lines, word[:n])
+				word = word[n:]
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitStyled は文字の並びを改行で段落に分ける
+func splitStyled(text []styledRune) [][]styledRune {
+	var paragraphs [][]styledRune
+	start := 0
+	for i, sr := range text {
+		if sr.r == '\n' {
+			paragraphs = append(paragraphs, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(paragraphs, text[start:])
+}
+
+// trimLeadingSpaces は先頭の空白を取り除く
+func trimLeadingSpaces(text []styledRune) []styledRune {
+	for len(text) > 0 && text[0].r == ' ' {
+		text = text[1:]
+	}
+	return text
+}
+
+// wrapTokens は段落を折り返せる単位に分ける
+// 各要素は前の空白を含む語、またはCJKの1文字（前の空白を含む）。空白は1つの ' ' にまとめる
+func wrapTokens(paragraph []styledRune) [][]styledRune {
+	var tokens [][]styledRune
+	var current []styledRune
+	solid := false // currentに空白以外の文字がある
+	flush := func() {
+		if solid {
+			tokens = append(tokens, current)
+			current, solid = nil, false
+		}
+	}
+	for _, sr := range paragraph {
+		switch {
+		case unicode.IsSpace(sr.r):
+			flush()
+			if len(current) == 0 {
+				current = append(current, styledRune{r: ' ', run: sr.run, pos: sr.pos})
+			}
+		case isCJKRune(sr.r):
+			flush()
+			current = append(current, sr)
+			solid = true
+			flush()
+		default:
+			current = append(current, sr)
+			solid = true
+		}
+	}
+	flush()
+	return tokens
+}
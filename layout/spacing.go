@@ -0,0 +1,129 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+)
+
+// SpacingConstraint はStrategyFlowDownで保つ2つのブロックの縦の間隔（Aboveの下端からBelowの上端まで）
+// 見出しと本文の間隔、図とキャプションの間隔など、意図した間隔を翻訳後の調整でも保つために使う
+// Above・BelowはテキストブロックかImageブロック、またはグループ（ContentBlockTypeGroup）への参照
+// グループのメンバーへの参照はグループとして扱う。Aboveにはページに固定したブロック（ヘッダー）も指定できる
+type SpacingConstraint struct {
+	Above BlockRef
+	Below BlockRef
+	Min   float64 // 最小の間隔（Aboveが直前のブロックの場合は、LayoutAdjustmentOptions.MinSpacingの代わりに使う）
+	Max   float64 // 最大の間隔（0以下は上限なし）。MinとMaxを同じ値にすると間隔を固定する
+}
+
+// flowSpacing はflowUnitsのインデックスに解決したSpacingConstraint
+type flowSpacing struct {
+	above    int      // AboveのflowUnitsでのインデックス（ページに固定したブロックの場合は-1）
+	anchored BlockRef // ページに固定したAbove
+	min, max float64
+}
+
+// flowSpacings はopts.Spacingを、BelowのflowUnitsでのインデックス -> 制約に解決する
+// 参照が範囲外の場合、AboveがBelowより読み順で後にある場合、同じブロックどうしの場合はエラー
+func (pl *PageLayout) flowSpacings(units []flowUnit, constraints []SpacingConstraint) (map[int][]flowSpacing, error) {
+	unitOf := make(map[BlockRef]int)
+	for i, u := range units {
+		for _, ref := range u.refs {
+			unitOf[ref] = i
+		}
+	}
+	grouped := pl.groupedRefs()
+	anchored := make(map[BlockRef]bool)
+	for _, ref := range pl.anchoredRefs() {
+		anchored[ref] = true
+	}
+
+	// resolve は参照のflowUnitsでのインデックスを返す（ページに固定したブロックは-1）
+	resolve := func(ref BlockRef) (int, error) {
+		if ref.Type == ContentBlockTypeGroup {
+			if ref.Index < 0 || ref.Index >= len(pl.Groups) {
+				return 0, fmt.Errorf("group index %d out of range [0, %d)", ref.Index, len(pl.Groups))
+			}
+			members := pl.Groups[ref.Index].Members
+			if len(members) == 0 {
+				return 0, fmt.Errorf("group %d has no members", ref.Index)
+			}
+			ref = members[0]
+		}
+		if _, err := pl.blockRect(ref); err != nil {
+			return 0, err
+		}
+		if g, ok := grouped[ref]; ok {
+			ref = pl.Groups[g].Members[0]
+		}
+		if i, ok := unitOf[ref]; ok {
+			return i, nil
+		}
+		if anchored[ref] {
+			return -1, nil
+		}
+		return 0, fmt.Errorf("%s block %d is not laid out", ref.Type, ref.Index)
+	}
+
+	spacings := make(map[int][]flowSpacing)
+	for i, c := range constraints {
+		if c.Min < 0 || (c.Max > 0 && c.Max < c.Min) {
+			return nil, fmt.Errorf("spacing constraint %d: invalid range [%v, %v]", i, c.Min, c.Max)
+		}
+		above, err := resolve(c.Above)
+		if err != nil {
+			return nil, fmt.Errorf("spacing constraint %d: above: %w", i, err)
+		}
+		below, err := resolve(c.Below)
+		if err != nil {
+			return nil, fmt.Errorf("spacing constraint %d: below: %w", i, err)
+		}
+		switch {
+		case below < 0:
+			return nil, fmt.Errorf("spacing constraint %d: below %s block %d is anchored", i, c.Below.Type, c.Below.Index)
+		case above == below:
+			return nil, fmt.Errorf("spacing constraint %d: above and below are the same block", i)
+		case above > below:
+			return nil, fmt.Errorf("spacing constraint %d: above %s block %d comes after below %s block %d", i, c.Above.Type, c.Above.Index, c.Below.Type, c.Below.Index)
+		}
+		spacings[below] = append(spacings[below], flowSpacing{above: above, anchored: c.Above, min: c.Min, max: c.Max})
+	}
+	return spacings, nil
+}
+
+// topRange はflowUnitsのi番目のブロックの上端の範囲を返す（前のブロックはすでに配置済み）
+// 上限は前のブロックの下端からMinSpacing（前のブロックとの制約がある場合はそのMin）と、各制約のMinで決まる
+// 下限は各制約のMaxで決まる（制約がない場合は-Inf）
+func (pl *PageLayout) topRange(units []flowUnit, i int, spacings []flowSpacing, minSpacing float64) (lowest, highest float64) {
+	lowest, highest = math.Inf(-1), math.Inf(1)
+	if i > 0 {
+		// 前のブロックとの制約がある場合は、MinSpacingの代わりにそのMinを使う
+		gap := minSpacing
+		for _, s := range spacings {
+			if s.above == i-1 {
+				gap = s.min
+			}
+		}
+		highest = pl.unitRect(units[i-1]).Y - gap
+	}
+	for _, s := range spacings {
+		var aboveBottom float64
+		if s.above < 0 {
+			rect, _ := pl.blockRect(s.anchored)
+			aboveBottom = rect.Y
+		} else {
+			aboveBottom = pl.unitRect(units[s.above]).Y
+		}
+		highest = math.Min(highest, aboveBottom-s.min)
+		if s.max > 0 {
+			lowest = math.Max(lowest, aboveBottom-s.max)
+		}
+	}
+	return lowest, highest
+}
+
+// unitRect はflowUnitの現在の矩形を返す
+func (pl *PageLayout) unitRect(u flowUnit) Rectangle {
+	rect, _ := pl.membersRect(u.refs)
+	return rect
+}
@@ -1,83 +1,35 @@
 package layout
 
-import "fmt"
+import "math"
 
 // adjustLayoutFlowDown は上から順に配置し、前のブロックとの間隔を保つ
+// opts.Spacingの制約があるブロックは、最小の間隔まで押し下げ、最大の間隔を超えて離れている場合は引き上げる
 func (pl *PageLayout) adjustLayoutFlowDown(opts LayoutAdjustmentOptions) error {
-	blocks := pl.SortedContentBlocks()
-	if len(blocks) == 0 {
+	units := pl.flowUnits()
+	if len(units) == 0 {
 		return nil
 	}
-
-	// ブロックとインデックスのマッピングを保持
-	type blockInfo struct {
-		blockType ContentBlockType
-		index     int
-	}
-
-	blockIndexMap := make(map[interface{}]blockInfo)
-
-	// TextBlocksのマッピング
-	for i := range pl.TextBlocks {
-		// ポインタではなく、テキスト内容で識別
-		key := pl.TextBlocks[i].Text + fmt.Sprintf("_%f_%f", pl.TextBlocks[i].Rect.X, pl.TextBlocks[i].Rect.Width)
-		blockIndexMap[key] = blockInfo{ContentBlockTypeText, i}
+	spacings, err := pl.flowSpacings(units, opts.Spacing)
+	if err != nil {
+		return err
 	}
+	fixed := pl.fixedRects()
 
-	// Imagesのマッピング
-	for i := range pl.Images {
-		key := fmt.Sprintf("img_%f_%f_%f", pl.Images[i].X, pl.Images[i].PlacedWidth, pl.Images[i].PlacedHeight)
-		blockIndexMap[key] = blockInfo{ContentBlockTypeImage, i}
-	}
-
-	getBlockInfo := func(block ContentBlock) (blockInfo, bool) {
-		switch block.Type() {
-		case ContentBlockTypeText:
-			tb := block.(TextBlock)
-			key := tb.Text + fmt.Sprintf("_%f_%f", tb.Rect.X, tb.Rect.Width)
-			info, ok := blockIndexMap[key]
-			return info, ok
-		case ContentBlockTypeImage:
-			ib := block.(ImageBlock)
-			key := fmt.Sprintf("img_%f_%f_%f", ib.X, ib.PlacedWidth, ib.PlacedHeight)
-			info, ok := blockIndexMap[key]
-			return info, ok
-		}
-		return blockInfo{}, false
-	}
-
-	// 前のブロックの下端を追跡
-	prevBottom := blocks[0].Bounds().Y
-
-	for i := 1; i < len(blocks); i++ {
-		currentBounds := blocks[i].Bounds()
-
-		// 現在のブロックの理想的な上端位置（prevBottomの下、minSpacing分離す）
-		idealTop := prevBottom - opts.MinSpacing
-
-		// 現在のブロックの新しい下端位置
-		newY := idealTop - currentBounds.Height
-
-		// 現在の上端位置
+	for i, u := range units {
+		currentBounds := pl.unitRect(u)
 		currentTop := currentBounds.Y + currentBounds.Height
 
-		// 移動が必要かチェック（現在の上端が理想位置より上にある場合）
-		if currentTop > idealTop {
-			// ブロックを移動
-			info, ok := getBlockInfo(blocks[i])
-			if ok {
-				switch info.blockType {
-				case ContentBlockTypeText:
-					pl.TextBlocks[info.index].Rect.Y = newY
-				case ContentBlockTypeImage:
-					pl.Images[info.index].Y = newY
-				}
-			}
-			prevBottom = newY
-		} else {
-			// 移動不要、現在の位置を使用
-			prevBottom = currentBounds.Y
+		// 上端の範囲（前のブロックの下からの間隔と制約）に収める
+		// 最大の間隔より最小の間隔を優先し、前のブロックと重ならないようにする
+		lowest, highest := pl.topRange(units, i, spacings[i], opts.MinSpacing)
+		newTop := math.Min(math.Max(currentTop, lowest), highest)
+		if newTop == currentTop {
+			continue
 		}
+
+		// 動かさないブロックと重なる場合はその下
+		newY := avoidFixed(currentBounds, newTop-currentBounds.Height, fixed, opts.MinSpacing)
+		pl.moveRefs(u.refs, 0, newY-currentBounds.Y)
 	}
 
 	return nil
@@ -85,17 +37,19 @@ func (pl *PageLayout) adjustLayoutFlowDown(opts LayoutAdjustmentOptions) error {
 
 // adjustLayoutCompact はブロックを上に詰めて配置
 func (pl *PageLayout) adjustLayoutCompact(opts LayoutAdjustmentOptions) error {
-	blocks := pl.SortedContentBlocks()
+	blocks := pl.FlowBlocks()
 	if len(blocks) == 0 {
 		return nil
 	}
+	fixed := pl.fixedRects()
+	area := pl.contentArea(opts.PageMargin, opts.MinSpacing)
 
-	// ページトップから配置
-	currentY := pl.Height - opts.PageMargin
+	// 本文の範囲の上端から配置
+	currentY := area.Y + area.Height
 
 	for _, block := range blocks {
 		bounds := block.Bounds()
-		newY := currentY - bounds.Height
+		newY := avoidFixed(bounds, currentY-bounds.Height, fixed, opts.MinSpacing)
 
 		switch block.Type() {
 		case ContentBlockTypeText:
@@ -111,14 +65,15 @@ func (pl *PageLayout) adjustLayoutCompact(opts LayoutAdjustmentOptions) error {
 			}
 		case ContentBlockTypeImage:
 			for i := range pl.Images {
-				if pl.Images[i].X == bounds.X &&
-					pl.Images[i].Y == bounds.Y &&
-					pl.Images[i].PlacedWidth == bounds.Width &&
-					pl.Images[i].PlacedHeight == bounds.Height {
-					pl.Images[i].Y = newY
+				if pl.Images[i].Bounds() == bounds {
+					pl.Images[i].Y += newY - bounds.Y
 					break
 				}
 			}
+		case ContentBlockTypeGroup:
+			if i := pl.groupIndex(block.(GroupBlock)); i >= 0 {
+				pl.moveGroup(i, 0, newY-bounds.Y)
+			}
 		}
 
 		currentY = newY - opts.MinSpacing
@@ -129,10 +84,11 @@ func (pl *PageLayout) adjustLayoutCompact(opts LayoutAdjustmentOptions) error {
 
 // adjustLayoutEvenSpacing はブロックを均等間隔で配置
 func (pl *PageLayout) adjustLayoutEvenSpacing(opts LayoutAdjustmentOptions) error {
-	blocks := pl.SortedContentBlocks()
+	blocks := pl.FlowBlocks()
 	if len(blocks) == 0 {
 		return nil
 	}
+	fixed := pl.fixedRects()
 
 	// 全ブロックの高さの合計を計算
 	totalHeight := float64(0)
@@ -141,7 +97,8 @@ func (pl *PageLayout) adjustLayoutEvenSpacing(opts LayoutAdjustmentOptions) erro
 	}
 
 	// 利用可能な空間
-	availableSpace := pl.Height - 2*opts.PageMargin - totalHeight
+	area := pl.contentArea(opts.PageMargin, opts.MinSpacing)
+	availableSpace := area.Height - totalHeight
 	if availableSpace < 0 {
 		// 空間が足りない場合はCompact戦略にフォールバック
 		return pl.adjustLayoutCompact(opts)
@@ -154,11 +111,11 @@ func (pl *PageLayout) adjustLayoutEvenSpacing(opts LayoutAdjustmentOptions) erro
 	}
 
 	// 配置
-	currentY := pl.Height - opts.PageMargin
+	currentY := area.Y + area.Height
 
 	for _, block := range blocks {
 		bounds := block.Bounds()
-		newY := currentY - bounds.Height
+		newY := avoidFixed(bounds, currentY-bounds.Height, fixed, opts.MinSpacing)
 
 		switch block.Type() {
 		case ContentBlockTypeText:
@@ -173,14 +130,15 @@ func (pl *PageLayout) adjustLayoutEvenSpacing(opts LayoutAdjustmentOptions) erro
 			}
 		case ContentBlockTypeImage:
 			for i := range pl.Images {
-				if pl.Images[i].X == bounds.X &&
-					pl.Images[i].Y == bounds.Y &&
-					pl.Images[i].PlacedWidth == bounds.Width &&
-					pl.Images[i].PlacedHeight == bounds.Height {
-					pl.Images[i].Y = newY
+				if pl.Images[i].Bounds() == bounds {
+					pl.Images[i].Y += newY - bounds.Y
 					break
 				}
 			}
+		case ContentBlockTypeGroup:
+			if i := pl.groupIndex(block.(GroupBlock)); i >= 0 {
+				pl.moveGroup(i, 0, newY-bounds.Y)
+			}
 		}
 
 		currentY = newY - spacing
@@ -197,3 +155,43 @@ func (pl *PageLayout) adjustLayoutFitContent(opts LayoutAdjustmentOptions) error
 	// ここでは何もしない（または簡易的なフォントサイズ調整のみ）
 	return nil
 }
+
+// fixedRects はレイアウト調整で動かさないブロック（VectorBlock、AnnotationBlockとページに固定したブロック）の矩形を返す
+func (pl *PageLayout) fixedRects() []Rectangle {
+	var rects []Rectangle
+	for _, ref := range pl.anchoredRefs() {
+		rect, _ := pl.blockRect(ref)
+		rects = append(rects, rect)
+	}
+	for _, vb := range pl.VectorBlocks() {
+		rects = append(rects, vb.Rect)
+	}
+	for _, ab := range pl.Annotations {
+		rects = append(rects, ab.Rect)
+	}
+	return rects
+}
+
+// avoidFixed は元の矩形がoriginalのブロックを下端newYに置くとき、動かさないブロックと重ならない下端を返す
+// 重なる場合は、そのブロックの下にspacingを空けて置く
+// 元の位置で重なっていた動かさないブロック（テキストを囲む枠や表の罫線など）は避けない
+func avoidFixed(original Rectangle, newY float64, fixed []Rectangle, spacing float64) float64 {
+	for moved := true; moved; {
+		moved = false
+		placed := Rectangle{X: original.X, Y: newY, Width: original.Width, Height: original.Height}
+		for _, rect := range fixed {
+			if crosses(original, rect) || !crosses(placed, rect) {
+				continue
+			}
+			newY = rect.Y - spacing - original.Height
+			placed.Y = newY
+			moved = true
+		}
+	}
+	return newY
+}
+
+// crosses は2つの矩形の内部が交わるかを返す（幅や高さが0の罫線も、矩形を横切れば交わる）
+func crosses(a, b Rectangle) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
@@ -0,0 +1,206 @@
+package layout
+
+import "fmt"
+
+// LayoutTemplate はページの領域（スロット）に名前を付けたテンプレート
+// 翻訳・要約したテキストや画像をスロットに流し込み、同じスタイル（位置・フォント）のPageLayoutを作る
+type LayoutTemplate struct {
+	Width   float64        `json:"width"`   // ページ幅
+	Height  float64        `json:"height"`  // ページ高さ
+	Margins Margins        `json:"margins"` // 作ったPageLayoutのMargins（レイアウト調整・ページ分割で使う）
+	Slots   []TemplateSlot `json:"slots"`   // スロット（名前はテンプレート内で一意）
+}
+
+// TemplateSlot はテンプレートの名前付きの領域（"title"、"body"、"figure"など）
+// 流し込んだブロックは、スロットの上端から順に、スロットの幅で並べる
+type TemplateSlot struct {
+	Name string    `json:"name"`
+	Rect Rectangle `json:"rect"`
+
+	// テキストブロックのスタイル（指定した値でブロックの値を置き換える）
+	Font     string  `json:"font,omitempty"`
+	FontSize float64 `json:"fontSize,omitempty"`
+	Color    *Color  `json:"color,omitempty"`
+
+	// Anchor を指定したスロット（ヘッダー・フッター）の内容は、すべてのページに写してページに固定する
+	Anchor BlockAnchor `json:"anchor,omitempty"`
+	// Flow がtrueのスロットは、収まらない内容を次のページの同じスロットに続ける（テキストブロックは行の境界で分割する）
+	// falseのスロットでは、収まらない内容はスロットの下端を越えて置く
+	Flow bool `json:"flow,omitempty"`
+}
+
+// TemplateOptions はLayoutTemplate.Fillの設定
+type TemplateOptions struct {
+	// Spacing はスロット内のブロックの間隔
+	Spacing float64
+
+	// Measurer はテキストブロックをスロットの幅で折り返す（TextBlock.Reflow）ときの幅の計測
+	// nilの場合は折り返さず、テキストブロックの高さのまま置く
+	Measurer TextMeasurer
+}
+
+// Fill はcontent（スロットの名前 -> 流し込むブロック）をスロットに流し込んだPageLayoutを返す
+// ブロックはTextBlockかImageBlockで、コピーして置く（contentは変えない）
+//   - テキストブロックはスロットの幅に広げ、スロットのスタイルを適用して、opts.Measurerがあれば折り返し直す
+//   - 画像ブロックはスロットより広い場合、縦横比を保ってスロットの幅に縮める
+//
+// Flowのスロットが1ページに収まらない場合はページを増やす。増やしたページには、Anchorのスロットの内容と続きの内容だけを置く
+// 内容のないスロットは空のまま。スロットの名前の重複、contentの未知のスロット、未対応のブロックはエラー
+// 設計書: docs/layout_template_design.md
+func (t *LayoutTemplate) Fill(content map[string][]ContentBlock, opts TemplateOptions) ([]*PageLayout, error) {
+	if t.Width <= 0 || t.Height <= 0 {
+		return nil, fmt.Errorf("invalid template size: %vx%v", t.Width, t.Height)
+	}
+	slots := make(map[string]bool, len(t.Slots))
+	for _, slot := range t.Slots {
+		switch {
+		case slot.Name == "":
+			return nil, fmt.Errorf("template slot has no name")
+		case slots[slot.Name]:
+			return nil, fmt.Errorf("template slot %q is defined twice", slot.Name)
+		case slot.Rect.Width <= 0 || slot.Rect.Height <= 0:
+			return nil, fmt.Errorf("template slot %q has invalid size: %vx%v", slot.Name, slot.Rect.Width, slot.Rect.Height)
+		case slot.Anchor != AnchorNone && slot.Flow:
+			return nil, fmt.Errorf("template slot %q cannot be both anchored and flowing", slot.Name)
+		}
+		slots[slot.Name] = true
+	}
+	for name := range content {
+		if !slots[name] {
+			return nil, fmt.Errorf("unknown template slot %q", name)
+		}
+	}
+
+	pages := []*PageLayout{t.blankPage()}
+	page := func(i int) *PageLayout {
+		for len(pages) <= i {
+			pages = append(pages, t.blankPage())
+		}
+		return pages[i]
+	}
+
+	// 本文のスロットを先に流し込み、ページ数が決まってからヘッダー・フッターを全ページに写す
+	for _, anchored := range []bool{false, true} {
+		for _, slot := range t.Slots {
+			if (slot.Anchor != AnchorNone) != anchored {
+				continue
+			}
+			placed, err := slot.pour(content[slot.Name], opts)
+			if err != nil {
+				return nil, err
+			}
+			if anchored {
+				for _, p := range pages {
+					p.TextBlocks = append(p.TextBlocks, placed[0].TextBlocks...)
+					p.Images = append(p.Images, placed[0].Images...)
+				}
+				continue
+			}
+			for i, p := range placed {
+				page(i).TextBlocks = append(page(i).TextBlocks, p.TextBlocks...)
+				page(i).Images = append(page(i).Images, p.Images...)
+			}
+		}
+	}
+	return pages, nil
+}
+
+// blankPage はテンプレートの大きさと余白の空のページを返す
+func (t *LayoutTemplate) blankPage() *PageLayout {
+	return &PageLayout{Width: t.Width, Height: t.Height, Margins: t.Margins}
+}
+
+// pour はブロックをスロットに上から並べ、ページごとに置いたブロックを返す（少なくとも1ページ）
+func (slot TemplateSlot) pour(blocks []ContentBlock, opts TemplateOptions) ([]*PageLayout, error) {
+	pages := []*PageLayout{{}}
+	top, bottom := slot.Rect.Y+slot.Rect.Height, slot.Rect.Y
+	currentY := top
+	split := PageSplitOptions{BreakTextBlocks: true}
+
+	queue := make([]ContentBlock, 0, len(blocks))
+	for i, block := range blocks {
+		prepared, err := slot.prepare(block, currentY, opts)
+		if err != nil {
+			return nil, fmt.Errorf("template slot %q: block %d: %w", slot.Name, i, err)
+		}
+		queue = append(queue, prepared)
+	}
+
+	for len(queue) > 0 {
+		block := queue[0]
+		height := block.Bounds().Height
+		current := pages[len(pages)-1]
+		empty := currentY == top
+
+		// 収まらないブロックは、Flowのスロットでは行の境界で分割するか、次のページに送る
+		if slot.Flow && currentY-height < bottom {
+			tb, ok := block.(TextBlock)
+			k := 0
+			if ok && split.breakable(tb) {
+				k = split.breakLine(tb, currentY-bottom, empty)
+			}
+			if k > 0 {
+				first, rest := splitTextBlock(tb, k)
+				first.Rect.Y = currentY - first.Rect.Height
+				current.TextBlocks = append(current.TextBlocks, first)
+				queue[0] = rest
+				pages = append(pages, &PageLayout{})
+				currentY = top
+				continue
+			}
+			if !empty {
+				pages = append(pages, &PageLayout{})
+				currentY = top
+				continue
+			}
+		}
+
+		switch b := block.(type) {
+		case TextBlock:
+			b.Rect.Y = currentY - height
+			b.Anchor = slot.Anchor
+			current.TextBlocks = append(current.TextBlocks, b)
+		case ImageBlock:
+			bounds := b.Bounds()
+			b.SetBounds(Rectangle{X: bounds.X, Y: currentY - height, Width: bounds.Width, Height: bounds.Height})
+			b.Anchor = slot.Anchor
+			current.Images = append(current.Images, b)
+		}
+		queue = queue[1:]
+		currentY -= height + opts.Spacing
+	}
+	return pages, nil
+}
+
+// prepare はブロックのコピーをスロットの幅に合わせ、上端をtopに置く
+func (slot TemplateSlot) prepare(block ContentBlock, top float64, opts TemplateOptions) (ContentBlock, error) {
+	switch b := block.(type) {
+	case TextBlock:
+		if slot.Font != "" {
+			b.Font = slot.Font
+		}
+		if slot.FontSize > 0 {
+			b.FontSize = slot.FontSize
+		}
+		if slot.Color != nil {
+			b.Color = *slot.Color
+		}
+		b.Rect = Rectangle{X: slot.Rect.X, Y: top - b.Rect.Height, Width: slot.Rect.Width, Height: b.Rect.Height}
+		if opts.Measurer != nil && b.FontSize > 0 && b.Angle == 0 {
+			if err := b.Reflow(opts.Measurer, b.FontSize, slot.Rect.Width); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case ImageBlock:
+		bounds := b.Bounds()
+		width, height := bounds.Width, bounds.Height
+		if width > slot.Rect.Width {
+			width, height = slot.Rect.Width, height*slot.Rect.Width/width
+		}
+		b.SetBounds(Rectangle{X: slot.Rect.X, Y: top - height, Width: width, Height: height})
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported block type: %s", block.Type())
+	}
+}
@@ -0,0 +1,72 @@
+package layout
+
+import "sort"
+
+// vectorBlockGap はVectorBlockにまとめるパスの間隔の上限（接している罫線や表の格子をまとめる）
+const vectorBlockGap = 2.0
+
+// VectorBlocks はPathsを、重なるか間隔がvectorBlockGap以下のものどうしでまとめて返す
+// 線も塗りもないパスと、ページ全体を覆う背景の塗りは除く
+// 順序はまとめたパスのうち最初のもののPathsでの順
+func (pl *PageLayout) VectorBlocks() []VectorBlock {
+	// group はまとめたパスのPathsでのインデックスと、その矩形
+	type group struct {
+		indices []int
+		rect    Rectangle
+	}
+	var groups []group
+	for i, path := range pl.Paths {
+		if (!path.Stroke && !path.Fill) || pl.isBackground(path.Rect) {
+			continue
+		}
+		current := group{indices: []int{i}, rect: path.Rect}
+
+		// 近いまとまりを取り込む。取り込むと矩形が広がるため、取り込むものがなくなるまで繰り返す
+		for merged := true; merged; {
+			merged = false
+			kept := groups[:0]
+			for _, other := range groups {
+				if nearRects(current.rect, other.rect, vectorBlockGap) {
+					current.indices = append(current.indices, other.indices...)
+					current.rect = current.rect.Union(other.rect)
+					merged = true
+					continue
+				}
+				kept = append(kept, other)
+			}
+			groups = kept
+		}
+		groups = append(groups, current)
+	}
+
+	for _, g := range groups {
+		sort.Ints(g.indices)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].indices[0] < groups[j].indices[0] })
+
+	blocks := make([]VectorBlock, len(groups))
+	for i, g := range groups {
+		paths := make([]PathBlock, len(g.indices))
+		for j, index := range g.indices {
+			paths[j] = pl.Paths[index]
+		}
+		blocks[i] = VectorBlock{Rect: g.rect, Paths: paths}
+	}
+	return blocks
+}
+
+// isBackground はページ全体を覆う矩形（背景の塗り）かを返す
+func (pl *PageLayout) isBackground(rect Rectangle) bool {
+	page := pl.Boxes.Visible
+	if page.Width <= 0 || page.Height <= 0 {
+		page = Rectangle{Width: pl.Width, Height: pl.Height}
+	}
+	covered := rect.Intersect(page)
+	return covered.Width >= page.Width*0.95 && covered.Height >= page.Height*0.95
+}
+
+// nearRects は2つの矩形が重なるか、間隔がgap以下かを返す
+func nearRects(a, b Rectangle, gap float64) bool {
+	return a.X <= b.X+b.Width+gap && b.X <= a.X+a.Width+gap &&
+		a.Y <= b.Y+b.Height+gap && b.Y <= a.Y+a.Height+gap
+}
@@ -0,0 +1,176 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+// alignTestLayout は大きさと位置がばらばらな3つのブロックを持つレイアウトを作成する
+func alignTestLayout() *PageLayout {
+	return &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "A", Rect: Rectangle{X: 50, Y: 700, Width: 100, Height: 40}},
+			{Text: "B", Rect: Rectangle{X: 80, Y: 560, Width: 200, Height: 20}},
+		},
+		Images: []ImageBlock{
+			{X: 300, Y: 400, PlacedWidth: 150, PlacedHeight: 60},
+		},
+	}
+}
+
+// alignTestRefs はalignTestLayoutの全ブロックへの参照
+var alignTestRefs = []BlockRef{
+	{Type: ContentBlockTypeText, Index: 0},
+	{Type: ContentBlockTypeText, Index: 1},
+	{Type: ContentBlockTypeImage, Index: 0},
+}
+
+func TestAlignBlocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment BlockAlignment
+		want      []Rectangle // A, B, 画像
+	}{
+		{"left", BlockAlignLeft, []Rectangle{{X: 50, Y: 700, Width: 100, Height: 40}, {X: 50, Y: 560, Width: 200, Height: 20}, {X: 50, Y: 400, Width: 150, Height: 60}}},
+		{"right", BlockAlignRight, []Rectangle{{X: 350, Y: 700, Width: 100, Height: 40}, {X: 250, Y: 560, Width: 200, Height: 20}, {X: 300, Y: 400, Width: 150, Height: 60}}},
+		{"center x", BlockAlignCenterX, []Rectangle{{X: 200, Y: 700, Width: 100, Height: 40}, {X: 150, Y: 560, Width: 200, Height: 20}, {X: 175, Y: 400, Width: 150, Height: 60}}},
+		{"top", BlockAlignTop, []Rectangle{{X: 50, Y: 700, Width: 100, Height: 40}, {X: 80, Y: 720, Width: 200, Height: 20}, {X: 300, Y: 680, Width: 150, Height: 60}}},
+		{"bottom", BlockAlignBottom, []Rectangle{{X: 50, Y: 400, Width: 100, Height: 40}, {X: 80, Y: 400, Width: 200, Height: 20}, {X: 300, Y: 400, Width: 150, Height: 60}}},
+		{"center y", BlockAlignCenterY, []Rectangle{{X: 50, Y: 550, Width: 100, Height: 40}, {X: 80, Y: 560, Width: 200, Height: 20}, {X: 300, Y: 540, Width: 150, Height: 60}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := alignTestLayout()
+			if err := layout.AlignBlocks(alignTestRefs, tt.alignment); err != nil {
+				t.Fatalf("AlignBlocks failed: %v", err)
+			}
+			got := []Rectangle{layout.TextBlocks[0].Rect, layout.TextBlocks[1].Rect, layout.Images[0].Bounds()}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("block %d rect = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDistributeBlocks(t *testing.T) {
+	// 縦: 上端740から下端400の範囲（高さ340）に高さ120のブロックを並べ、間隔は110
+	layout := alignTestLayout()
+	if err := layout.DistributeVertically(alignTestRefs); err != nil {
+		t.Fatalf("DistributeVertically failed: %v", err)
+	}
+	for i, want := range []float64{700, 570, 400} {
+		got := layout.ContentBlocks()[i].Bounds()
+		if got.Y != want {
+			t.Errorf("block %d at y=%v, want %v", i, got.Y, want)
+		}
+	}
+	if x := layout.TextBlocks[1].Rect.X; x != 80 {
+		t.Errorf("DistributeVertically moved B horizontally to x=%v", x)
+	}
+
+	// 横: 左端50から右端450の範囲（幅400）に幅450のブロックを並べ、25ずつ重なる
+	layout = alignTestLayout()
+	if err := layout.DistributeHorizontally(alignTestRefs); err != nil {
+		t.Fatalf("DistributeHorizontally failed: %v", err)
+	}
+	for i, want := range []float64{50, 125, 300} {
+		got := layout.ContentBlocks()[i].Bounds()
+		if got.X != want {
+			t.Errorf("block %d at x=%v, want %v", i, got.X, want)
+		}
+	}
+}
+
+func TestAlignBlocks_Groups(t *testing.T) {
+	// グループは1つのブロックとして揃え、メンバーの相対位置は保たれる
+	layout := groupTestLayout()
+	g, err := layout.GroupBlocks(figureRefs...)
+	if err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	layout.TextBlocks[0].Rect.X = 300
+	refs := []BlockRef{{Type: ContentBlockTypeText, Index: 0}, {Type: ContentBlockTypeGroup, Index: g}}
+	if err := layout.AlignBlocks(refs, BlockAlignRight); err != nil {
+		t.Fatalf("AlignBlocks failed: %v", err)
+	}
+	if img, caption := layout.Images[0], layout.TextBlocks[1].Rect; img.X != 300 || caption.X != 300 || img.Y != 500 || caption.Y != 480 {
+		t.Errorf("image at (%v, %v), caption at (%v, %v), want (300, 500) and (300, 480)", img.X, img.Y, caption.X, caption.Y)
+	}
+	if want := (Rectangle{X: 300, Y: 480, Width: 200, Height: 170}); layout.Groups[g].Rect != want {
+		t.Errorf("group rect = %+v, want %+v", layout.Groups[g].Rect, want)
+	}
+}
+
+func TestAlignBlocks_Errors(t *testing.T) {
+	layout := groupTestLayout()
+	g, err := layout.GroupBlocks(figureRefs...)
+	if err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	heading := BlockRef{Type: ContentBlockTypeText, Index: 0}
+	group := BlockRef{Type: ContentBlockTypeGroup, Index: g}
+
+	tests := []struct {
+		name string
+		refs []BlockRef
+	}{
+		{"one block", []BlockRef{heading}},
+		{"listed twice", []BlockRef{heading, heading}},
+		{"out of range", []BlockRef{heading, {Type: ContentBlockTypeImage, Index: 1}}},
+		{"group and its member", []BlockRef{group, figureRefs[1]}},
+		{"vector block", []BlockRef{heading, {Type: ContentBlockTypeVector, Index: 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := layout.AlignBlocks(tt.refs, BlockAlignLeft); err == nil {
+				t.Error("AlignBlocks should fail")
+			}
+		})
+	}
+	if err := layout.DistributeVertically([]BlockRef{heading, group}); err == nil {
+		t.Error("DistributeVertically should fail for 2 blocks")
+	}
+	if err := layout.AlignBlocks([]BlockRef{heading, group}, BlockAlignment(-1)); err == nil {
+		t.Error("AlignBlocks should fail for an unknown alignment")
+	}
+	if heading := layout.TextBlocks[0].Rect; heading.X != 100 || heading.Y != 700 {
+		t.Errorf("heading moved to (%v, %v) by failed calls", heading.X, heading.Y)
+	}
+}
+
+func TestMirrorHorizontally(t *testing.T) {
+	tests := []struct {
+		name    string
+		visible Rectangle
+		want    []Rectangle // A, B, 画像
+	}{
+		{"page", Rectangle{}, []Rectangle{{X: 450, Y: 700, Width: 100, Height: 40}, {X: 320, Y: 560, Width: 200, Height: 20}, {X: 150, Y: 400, Width: 150, Height: 60}}},
+		// 表示される範囲が(20, 0)から幅560の場合は、X=300を中心に反転する
+		{"visible area", Rectangle{X: 20, Width: 560, Height: 800}, []Rectangle{{X: 450, Y: 700, Width: 100, Height: 40}, {X: 320, Y: 560, Width: 200, Height: 20}, {X: 150, Y: 400, Width: 150, Height: 60}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := alignTestLayout()
+			layout.Boxes.Visible = tt.visible
+			if _, err := layout.GroupBlocks(alignTestRefs[0], alignTestRefs[1]); err != nil {
+				t.Fatal(err)
+			}
+			layout.MirrorHorizontally()
+			got := []Rectangle{layout.TextBlocks[0].Rect, layout.TextBlocks[1].Rect, layout.Images[0].Bounds()}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("block %d rect = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if want := got[1].Union(got[0]); layout.Groups[0].Rect != want {
+				t.Errorf("group rect = %+v, want %+v", layout.Groups[0].Rect, want)
+			}
+		})
+	}
+}
@@ -318,3 +318,48 @@ func TestAdjustLayout_TranslationUseCase(t *testing.T) {
 		t.Errorf("Spacing between blocks 1-2 = %f, want >= 10", spacing2)
 	}
 }
+
+// TestAdjustLayout_AvoidsFixedBlocks は線・図と注釈を動かさず、テキストと画像が重ならないように置くことをテストする
+func TestAdjustLayout_AvoidsFixedBlocks(t *testing.T) {
+	newLayout := func() *PageLayout {
+		return &PageLayout{
+			Width:  595,
+			Height: 842,
+			TextBlocks: []TextBlock{
+				{Text: "Title", Rect: Rectangle{X: 50, Y: 780, Width: 300, Height: 20}},
+				{Text: "Body", Rect: Rectangle{X: 50, Y: 500, Width: 300, Height: 100}},
+				// 枠の中のテキストは枠を避けない
+				{Text: "Boxed", Rect: Rectangle{X: 60, Y: 210, Width: 100, Height: 20}},
+			},
+			// 区切り線と、テキストを囲む枠
+			Paths: []PathBlock{
+				{Stroke: true, Rect: Rectangle{X: 40, Y: 700, Width: 500}},
+				{Stroke: true, Rect: Rectangle{X: 50, Y: 200, Width: 150, Height: 40}},
+			},
+			Annotations: []AnnotationBlock{{Subtype: "Stamp", Rect: Rectangle{X: 300, Y: 600, Width: 100, Height: 50}}},
+		}
+	}
+
+	for _, strategy := range []LayoutStrategy{StrategyCompact, StrategyEvenSpacing, StrategyFlowDown} {
+		t.Run(string(strategy), func(t *testing.T) {
+			pl := newLayout()
+			opts := LayoutAdjustmentOptions{Strategy: strategy, MinSpacing: 10, PageMargin: 20}
+			if err := pl.AdjustLayout(opts); err != nil {
+				t.Fatalf("AdjustLayout failed: %v", err)
+			}
+
+			fixed := []Rectangle{pl.Paths[0].Rect, pl.Annotations[0].Rect}
+			for _, block := range pl.TextBlocks[:2] {
+				for _, rect := range fixed {
+					if block.Rect.Y < rect.Y+rect.Height && rect.Y < block.Rect.Y+block.Rect.Height &&
+						block.Rect.X < rect.X+rect.Width && rect.X < block.Rect.X+block.Rect.Width {
+						t.Errorf("%q at %+v covers %+v", block.Text, block.Rect, rect)
+					}
+				}
+			}
+			if pl.Paths[0].Rect.Y != 700 || pl.Annotations[0].Rect.Y != 600 {
+				t.Error("fixed blocks were moved")
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// columnsOptions は幅600のページで幅240の2段を作るオプション
+func columnsOptions() LayoutAdjustmentOptions {
+	return LayoutAdjustmentOptions{
+		Strategy:   StrategyColumns,
+		MinSpacing: 10,
+		PageMargin: 50,
+		Columns:    ColumnOptions{Count: 2, Gutter: 20},
+	}
+}
+
+func TestAdjustLayout_Columns(t *testing.T) {
+	// 1段の幅いっぱいの4つの段落は、幅を半分にすると高さが倍になり、2つずつ2段に分かれる
+	layout := &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "A", Rect: Rectangle{X: 50, Y: 650, Width: 480, Height: 100}},
+			{Text: "B", Rect: Rectangle{X: 50, Y: 540, Width: 480, Height: 100}},
+			{Text: "C", Rect: Rectangle{X: 50, Y: 430, Width: 480, Height: 100}},
+			{Text: "D", Rect: Rectangle{X: 50, Y: 320, Width: 480, Height: 100}},
+		},
+	}
+	if err := AdjustLayout(layout, columnsOptions()); err != nil {
+		t.Fatalf("AdjustLayout failed: %v", err)
+	}
+
+	want := map[string]Rectangle{
+		"A": {X: 50, Y: 550, Width: 240, Height: 200},
+		"B": {X: 50, Y: 340, Width: 240, Height: 200},
+		"C": {X: 310, Y: 550, Width: 240, Height: 200},
+		"D": {X: 310, Y: 340, Width: 240, Height: 200},
+	}
+	for text, rect := range textRects(layout) {
+		if rect != want[text] {
+			t.Errorf("%s rect = %+v, want %+v", text, rect, want[text])
+		}
+	}
+}
+
+func TestAdjustLayout_ColumnsMeasuresText(t *testing.T) {
+	// 翻訳で長くなったテキストは、段の幅で折り返した高さになる
+	text := strings.Repeat("translated text grows longer ", 30)
+	layout := &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: text, FontSize: 10, Rect: Rectangle{X: 50, Y: 600, Width: 480, Height: 24}},
+		},
+	}
+	if err := AdjustLayout(layout, columnsOptions()); err != nil {
+		t.Fatalf("AdjustLayout failed: %v", err)
+	}
+
+	wantHeight := float64(EstimateLines(text, 240, "Helvetica", 10)) * 12
+	if rect := layout.TextBlocks[0].Rect; rect.Width != 240 || rect.Height != wantHeight || rect.Y+rect.Height != 750 {
+		t.Errorf("rect = %+v, want 240x%v at the top margin", rect, wantHeight)
+	}
+}
+
+func TestAdjustLayout_ColumnsGroups(t *testing.T) {
+	// 見出しとグループは別の段に置かれ、グループのメンバーの相対位置は保たれる
+	layout := groupTestLayout()
+	if _, err := layout.GroupBlocks(figureRefs...); err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	if err := AdjustLayout(layout, columnsOptions()); err != nil {
+		t.Fatalf("AdjustLayout failed: %v", err)
+	}
+
+	if got, want := layout.TextBlocks[0].Rect, (Rectangle{X: 50, Y: 710, Width: 240, Height: 40}); got != want {
+		t.Errorf("heading rect = %+v, want %+v", got, want)
+	}
+	if got, want := layout.Images[0].Bounds(), (Rectangle{X: 310, Y: 600, Width: 200, Height: 150}); got != want {
+		t.Errorf("image bounds = %+v, want %+v", got, want)
+	}
+	if got, want := layout.TextBlocks[1].Rect, (Rectangle{X: 310, Y: 580, Width: 200, Height: 15}); got != want {
+		t.Errorf("caption rect = %+v, want %+v", got, want)
+	}
+	if got, want := layout.Groups[0].Rect, (Rectangle{X: 310, Y: 580, Width: 200, Height: 170}); got != want {
+		t.Errorf("group rect = %+v, want %+v", got, want)
+	}
+}
+
+func TestAdjustLayout_ColumnsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns ColumnOptions
+	}{
+		{"no columns", ColumnOptions{Count: 0}},
+		{"gutter wider than the page", ColumnOptions{Count: 2, Gutter: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := columnsOptions()
+			opts.Columns = tt.columns
+			if err := AdjustLayout(editTestLayout(), opts); err == nil {
+				t.Error("AdjustLayout should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package gopdf
+
+import (
+	"bytes"
+	"math"
+	"sort"
+)
+
+// layoutDiffTolerance は位置・大きさの差とみなさない値（pt）
+const layoutDiffTolerance = 0.01
+
+// BlockChange はブロックの変化の種類（ビットの組み合わせ）
+type BlockChange int
+
+const (
+	BlockAdded    BlockChange = 1 << iota // bにだけあるブロック
+	BlockRemoved                          // aにだけあるブロック
+	BlockMoved                            // 位置（左下）が変わった
+	BlockResized                          // 大きさが変わった
+	BlockRetexted                         // テキストが変わった（テキストブロックのみ）
+)
+
+// Has はchangeのビットをすべて含むかを返す
+func (c BlockChange) Has(change BlockChange) bool {
+	return c&change == change
+}
+
+// BlockDiff は1つのブロックの変化
+type BlockDiff struct {
+	Type   ContentBlockType // ContentBlockTypeTextかContentBlockTypeImage
+	IndexA int              // aのTextBlocks・Imagesでのインデックス（追加の場合は-1）
+	IndexB int              // bのTextBlocks・Imagesでのインデックス（削除の場合は-1）
+	Change BlockChange
+
+	RectA, RectB    Rectangle // 変化の前後の矩形（追加・削除の場合は片方がゼロ値）
+	DX, DY          float64   // 位置の差（b - a）
+	DWidth, DHeight float64   // 大きさの差（b - a）
+	TextA, TextB    string    // 変化の前後のテキスト（テキストブロックのみ）
+}
+
+// LayoutDiff は2つのレイアウトの比較結果
+type LayoutDiff struct {
+	Blocks []BlockDiff // 変化したブロック（テキスト、画像の順に、aのインデックス順。追加はbのインデックス順で最後）
+}
+
+// Equal は2つのレイアウトのブロックに差がないかを返す
+func (d *LayoutDiff) Equal() bool {
+	return len(d.Blocks) == 0
+}
+
+// Count は変化changeを含むブロックの数を返す
+func (d *LayoutDiff) Count(change BlockChange) int {
+	n := 0
+	for _, block := range d.Blocks {
+		if block.Change.Has(change) {
+			n++
+		}
+	}
+	return n
+}
+
+// StructureChanged はブロックの追加・削除があるかを返す
+// 翻訳や位置の調整では、ブロックの移動・大きさ・テキストは変わっても構造（ブロックの対応）は変わらない
+func (d *LayoutDiff) StructureChanged() bool {
+	return d.Count(BlockAdded) > 0 || d.Count(BlockRemoved) > 0
+}
+
+// DiffLayouts は2つのレイアウトのテキストブロックと画像ブロックを対応付け、
+// 追加・削除・移動・大きさ・テキストの変化をブロックごとに返す
+// ブロックは同じ種類どうしで、次の順に対応付ける
+//  1. 内容（テキスト、画像の名前かデータ）が同じブロックを、近いものから
+//  2. 矩形が重なるブロックを、重なりの割合（IoU）が大きいものから
+//  3. 残ったブロックのうち、インデックスが同じもの（翻訳でテキストと位置が両方変わったブロック）
+//
+// 線・図と注釈、グループは比較しない
+// 設計書: docs/compare_design.md
+func DiffLayouts(a, b *PageLayout) *LayoutDiff {
+	diff := &LayoutDiff{}
+
+	textsA := make([]diffBlock, len(a.TextBlocks))
+	for i, tb := range a.TextBlocks {
+		textsA[i] = diffBlock{rect: tb.Rect, text: tb.Text}
+	}
+	textsB := make([]diffBlock, len(b.TextBlocks))
+	for i, tb := range b.TextBlocks {
+		textsB[i] = diffBlock{rect: tb.Rect, text: tb.Text}
+	}
+	imagesA := make([]diffBlock, len(a.Images))
+	for i, ib := range a.Images {
+		imagesA[i] = diffBlock{rect: ib.Bounds(), image: ib.ImageInfo}
+	}
+	imagesB := make([]diffBlock, len(b.Images))
+	for i, ib := range b.Images {
+		imagesB[i] = diffBlock{rect: ib.Bounds(), image: ib.ImageInfo}
+	}
+
+	var added []BlockDiff
+	for _, kind := range []struct {
+		blockType ContentBlockType
+		a, b      []diffBlock
+	}{
+		{ContentBlockTypeText, textsA, textsB},
+		{ContentBlockTypeImage, imagesA, imagesB},
+	} {
+		pairs := matchDiffBlocks(kind.a, kind.b)
+		matchedB := make(map[int]bool, len(pairs))
+		for i := range kind.a {
+			j, ok := pairs[i]
+			if !ok {
+				diff.Blocks = append(diff.Blocks, kind.a[i].diff(kind.blockType, i, -1, diffBlock{}))
+				continue
+			}
+			matchedB[j] = true
+			if d := kind.a[i].diff(kind.blockType, i, j, kind.b[j]); d.Change != 0 {
+				diff.Blocks = append(diff.Blocks, d)
+			}
+		}
+		for j := range kind.b {
+			if !matchedB[j] {
+				added = append(added, diffBlock{}.diff(kind.blockType, -1, j, kind.b[j]))
+			}
+		}
+	}
+	diff.Blocks = append(diff.Blocks, added...)
+
+	return diff
+}
+
+// diffBlock はDiffLayoutsで比較するブロック
+type diffBlock struct {
+	rect  Rectangle
+	text  string    // テキストブロックのテキスト
+	image ImageInfo // 画像ブロックの画像
+}
+
+// sameContent は2つのブロックの内容が同じかを返す
+// 画像は名前（JSONで参照にした場合など）かデータが同じものを同じとみなす
+func (d diffBlock) sameContent(other diffBlock) bool {
+	if d.image.Name != "" || len(d.image.Data) > 0 {
+		if d.image.Name != "" && d.image.Name == other.image.Name {
+			return true
+		}
+		return len(d.image.Data) > 0 && bytes.Equal(d.image.Data, other.image.Data)
+	}
+	return d.text == other.text
+}
+
+// diff はaのブロックdとbのブロックotherの変化を返す（追加・削除の場合はインデックスが-1）
+func (d diffBlock) diff(blockType ContentBlockType, indexA, indexB int, other diffBlock) BlockDiff {
+	result := BlockDiff{
+		Type:   blockType,
+		IndexA: indexA,
+		IndexB: indexB,
+		RectA:  d.rect,
+		RectB:  other.rect,
+		TextA:  d.text,
+		TextB:  other.text,
+	}
+	switch {
+	case indexB < 0:
+		result.Change = BlockRemoved
+		return result
+	case indexA < 0:
+		result.Change = BlockAdded
+		return result
+	}
+
+	result.DX, result.DY = other.rect.X-d.rect.X, other.rect.Y-d.rect.Y
+	result.DWidth, result.DHeight = other.rect.Width-d.rect.Width, other.rect.Height-d.rect.Height
+	if math.Abs(result.DX) > layoutDiffTolerance || math.Abs(result.DY) > layoutDiffTolerance {
+		result.Change |= BlockMoved
+	}
+	if math.Abs(result.DWidth) > layoutDiffTolerance || math.Abs(result.DHeight) > layoutDiffTolerance {
+		result.Change |= BlockResized
+	}
+	if blockType == ContentBlockTypeText && d.text != other.text {
+		result.Change |= BlockRetexted
+	}
+	return result
+}
+
+// matchDiffBlocks はaのブロックとbのブロックを対応付け、aのインデックス -> bのインデックスを返す
+func matchDiffBlocks(a, b []diffBlock) map[int]int {
+	pairs := make(map[int]int)
+	matchedB := make(map[int]bool)
+
+	// score（大きいほど優先）の高い組から対応付ける
+	match := func(score func(x, y diffBlock) (float64, bool)) {
+		type candidate struct {
+			i, j  int
+			score float64
+		}
+		var candidates []candidate
+		for i := range a {
+			if _, ok := pairs[i]; ok {
+				continue
+			}
+			for j := range b {
+				if matchedB[j] {
+					continue
+				}
+				if s, ok := score(a[i], b[j]); ok {
+					candidates = append(candidates, candidate{i, j, s})
+				}
+			}
+		}
+		sort.SliceStable(candidates, func(x, y int) bool {
+			return candidates[x].score > candidates[y].score
+		})
+		for _, c := range candidates {
+			if _, ok := pairs[c.i]; ok || matchedB[c.j] {
+				continue
+			}
+			pairs[c.i] = c.j
+			matchedB[c.j] = true
+		}
+	}
+
+	// 1. 内容が同じブロックを、左下の距離が近いものから
+	match(func(x, y diffBlock) (float64, bool) {
+		return -math.Hypot(y.rect.X-x.rect.X, y.rect.Y-x.rect.Y), x.sameContent(y)
+	})
+	// 2. 矩形が重なるブロックを、重なりの割合が大きいものから
+	match(func(x, y diffBlock) (float64, bool) {
+		iou := rectIoU(x.rect, y.rect)
+		return iou, iou > 0
+	})
+	// 3. インデックスが同じブロック
+	for i := range a {
+		if _, ok := pairs[i]; !ok && i < len(b) && !matchedB[i] {
+			pairs[i] = i
+			matchedB[i] = true
+		}
+	}
+
+	return pairs
+}
+
+// rectIoU は2つの矩形の重なりの面積を、合わせた面積で割った値を返す
+func rectIoU(a, b Rectangle) float64 {
+	w := math.Min(a.X+a.Width, b.X+b.Width) - math.Max(a.X, b.X)
+	h := math.Min(a.Y+a.Height, b.Y+b.Height) - math.Max(a.Y, b.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	inter := w * h
+	return inter / (a.Width*a.Height + b.Width*b.Height - inter)
+}
@@ -0,0 +1,133 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+func TestDiffLayouts(t *testing.T) {
+	base := func() *PageLayout {
+		return &PageLayout{
+			Width:  600,
+			Height: 800,
+			TextBlocks: []TextBlock{
+				{Text: "Title", Rect: Rectangle{X: 50, Y: 700, Width: 300, Height: 40}},
+				{Text: "Body", Rect: Rectangle{X: 50, Y: 600, Width: 300, Height: 80}},
+			},
+			Images: []ImageBlock{
+				{ImageInfo: ImageInfo{Name: "logo"}, X: 400, Y: 700, PlacedWidth: 100, PlacedHeight: 50},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		edit      func(pl *PageLayout)
+		want      []BlockChange // 変化したブロックごとの変化
+		structure bool
+	}{
+		{
+			name: "unchanged",
+			edit: func(pl *PageLayout) {},
+		},
+		{
+			name: "translated",
+			edit: func(pl *PageLayout) {
+				pl.TextBlocks[1].Text = "本文"
+				pl.TextBlocks[1].Rect.Height = 120
+				pl.TextBlocks[1].Rect.Y = 560
+			},
+			want: []BlockChange{BlockMoved | BlockResized | BlockRetexted},
+		},
+		{
+			name: "translated and moved away",
+			edit: func(pl *PageLayout) {
+				pl.TextBlocks[0].Text = "タイトル"
+				pl.TextBlocks[0].Rect.Y = 300
+			},
+			want: []BlockChange{BlockMoved | BlockRetexted},
+		},
+		{
+			name: "reordered",
+			edit: func(pl *PageLayout) {
+				pl.TextBlocks[0], pl.TextBlocks[1] = pl.TextBlocks[1], pl.TextBlocks[0]
+			},
+		},
+		{
+			name: "image moved",
+			edit: func(pl *PageLayout) {
+				pl.Images[0].X = 50
+				pl.Images[0].Y = 50
+			},
+			want: []BlockChange{BlockMoved},
+		},
+		{
+			name: "block removed and added",
+			edit: func(pl *PageLayout) {
+				pl.TextBlocks = pl.TextBlocks[1:]
+				pl.Images = append(pl.Images, ImageBlock{ImageInfo: ImageInfo{Name: "photo"}, X: 50, Y: 50, PlacedWidth: 200, PlacedHeight: 100})
+			},
+			want:      []BlockChange{BlockRemoved, BlockAdded},
+			structure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base()
+			tt.edit(after)
+			diff := DiffLayouts(base(), after)
+
+			if len(diff.Blocks) != len(tt.want) {
+				t.Fatalf("DiffLayouts returned %d changes (%+v), want %d", len(diff.Blocks), diff.Blocks, len(tt.want))
+			}
+			for i, block := range diff.Blocks {
+				if block.Change != tt.want[i] {
+					t.Errorf("change %d = %b, want %b", i, block.Change, tt.want[i])
+				}
+			}
+			if diff.Equal() != (len(tt.want) == 0) {
+				t.Errorf("Equal() = %v", diff.Equal())
+			}
+			if diff.StructureChanged() != tt.structure {
+				t.Errorf("StructureChanged() = %v, want %v", diff.StructureChanged(), tt.structure)
+			}
+		})
+	}
+}
+
+func TestDiffLayouts_Deltas(t *testing.T) {
+	a := &PageLayout{TextBlocks: []TextBlock{
+		{Text: "Hello", Rect: Rectangle{X: 50, Y: 700, Width: 300, Height: 40}},
+	}}
+	b := &PageLayout{TextBlocks: []TextBlock{
+		{Text: "Note", Rect: Rectangle{X: 50, Y: 200, Width: 100, Height: 20}},
+		{Text: "こんにちは", Rect: Rectangle{X: 60, Y: 690, Width: 320, Height: 50}},
+	}}
+
+	diff := DiffLayouts(a, b)
+	if len(diff.Blocks) != 2 {
+		t.Fatalf("DiffLayouts returned %d changes, want 2", len(diff.Blocks))
+	}
+
+	// 矩形が重なるブロックが対応付けられ、インデックスが同じブロックは新しいブロックとして扱われる
+	changed := diff.Blocks[0]
+	want := BlockDiff{
+		Type:   ContentBlockTypeText,
+		IndexA: 0,
+		IndexB: 1,
+		Change: BlockMoved | BlockResized | BlockRetexted,
+		RectA:  a.TextBlocks[0].Rect,
+		RectB:  b.TextBlocks[1].Rect,
+		DX:     10, DY: -10, DWidth: 20, DHeight: 10,
+		TextA: "Hello", TextB: "こんにちは",
+	}
+	if changed != want {
+		t.Errorf("changed block = %+v, want %+v", changed, want)
+	}
+	if added := diff.Blocks[1]; added.Change != BlockAdded || added.IndexA != -1 || added.IndexB != 0 || added.TextB != "Note" {
+		t.Errorf("added block = %+v", added)
+	}
+	if diff.Count(BlockMoved) != 1 || diff.Count(BlockAdded) != 1 || diff.Count(BlockRemoved) != 0 {
+		t.Errorf("Count = moved %d, added %d, removed %d", diff.Count(BlockMoved), diff.Count(BlockAdded), diff.Count(BlockRemoved))
+	}
+}
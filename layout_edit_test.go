@@ -0,0 +1,232 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+// editTestLayout は縦に並んだ3つの段落と、1つ目の段落の右に並んだブロックを持つレイアウトを作成する
+func editTestLayout() *PageLayout {
+	return &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "A", Rect: Rectangle{X: 50, Y: 700, Width: 300, Height: 40}},
+			{Text: "B", Rect: Rectangle{X: 50, Y: 600, Width: 300, Height: 40}},
+			{Text: "C", Rect: Rectangle{X: 50, Y: 500, Width: 300, Height: 40}},
+			{Text: "Side", Rect: Rectangle{X: 400, Y: 700, Width: 150, Height: 40}},
+		},
+	}
+}
+
+// flowTexts はFlowBlocksの順でテキストを返す（画像とグループは"image"・"group"）
+func flowTexts(pl *PageLayout) []string {
+	var texts []string
+	for _, block := range pl.FlowBlocks() {
+		if tb, ok := block.(TextBlock); ok {
+			texts = append(texts, tb.Text)
+		} else {
+			texts = append(texts, string(block.Type()))
+		}
+	}
+	return texts
+}
+
+// textRects はテキスト -> 矩形を返す
+func textRects(pl *PageLayout) map[string]Rectangle {
+	rects := make(map[string]Rectangle)
+	for _, tb := range pl.TextBlocks {
+		rects[tb.Text] = tb.Rect
+	}
+	return rects
+}
+
+func TestInsertBlock(t *testing.T) {
+	opts := DefaultLayoutAdjustmentOptions()
+	newBlock := TextBlock{Text: "New", Rect: Rectangle{X: 60, Width: 280, Height: 50}}
+
+	tests := []struct {
+		name       string
+		afterIndex int
+		wantOrder  []string
+		wantY      map[string]float64
+	}{
+		{
+			name:       "after first block",
+			afterIndex: 0,
+			wantOrder:  []string{"A", "Side", "New", "B", "C"},
+			// NewはAの下（700 - 10 - 50）、Bはその下に押し下げられ、Cと横に並んだ"Side"は動かない
+			wantY: map[string]float64{"A": 700, "Side": 700, "New": 640, "B": 590, "C": 500},
+		},
+		{
+			name:       "at the top",
+			afterIndex: -1,
+			wantOrder:  []string{"New", "Side", "A", "B", "C"},
+			// "Side"は横に並んでいるため動かない
+			wantY: map[string]float64{"New": 690, "A": 640, "Side": 700, "B": 590, "C": 500},
+		},
+		{
+			name:       "at the end",
+			afterIndex: 3,
+			wantOrder:  []string{"A", "Side", "B", "C", "New"},
+			wantY:      map[string]float64{"A": 700, "B": 600, "C": 500, "New": 440},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := editTestLayout()
+			next, err := layout.InsertBlock(tt.afterIndex, newBlock, opts)
+			if err != nil {
+				t.Fatalf("InsertBlock failed: %v", err)
+			}
+			if next != nil {
+				t.Errorf("InsertBlock returned a continuation with %d text blocks, want nil", len(next.TextBlocks))
+			}
+
+			order := flowTexts(layout)
+			if len(order) != len(tt.wantOrder) {
+				t.Fatalf("FlowBlocks order = %q, want %q", order, tt.wantOrder)
+			}
+			for i := range order {
+				if order[i] != tt.wantOrder[i] {
+					t.Fatalf("FlowBlocks order = %q, want %q", order, tt.wantOrder)
+				}
+			}
+			rects := textRects(layout)
+			for text, y := range tt.wantY {
+				if rects[text].Y != y {
+					t.Errorf("%s at y=%v, want %v", text, rects[text].Y, y)
+				}
+			}
+			if rect := rects["New"]; rect.X != 60 || rect.Width != 280 || rect.Height != 50 {
+				t.Errorf("inserted block rect = %+v, want x=60 280x50", rect)
+			}
+		})
+	}
+}
+
+func TestInsertBlock_Overflow(t *testing.T) {
+	layout := groupTestLayout()
+	if _, err := layout.GroupBlocks(figureRefs...); err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	opts := DefaultLayoutAdjustmentOptions()
+
+	// 見出しの下に高いブロックを挿入すると、画像とキャプションのグループが次のページに押し出される
+	newBlock := TextBlock{Text: "Long paragraph", Rect: Rectangle{X: 100, Width: 200, Height: 500}}
+	next, err := layout.InsertBlock(0, newBlock, opts)
+	if err != nil {
+		t.Fatalf("InsertBlock failed: %v", err)
+	}
+	if next == nil {
+		t.Fatal("InsertBlock did not return a continuation")
+	}
+
+	if len(layout.TextBlocks) != 2 || len(layout.Images) != 0 || len(layout.Groups) != 0 {
+		t.Errorf("page has %d text blocks, %d images and %d groups, want 2, 0 and 0",
+			len(layout.TextBlocks), len(layout.Images), len(layout.Groups))
+	}
+	if got := textRects(layout)["Long paragraph"].Y; got != 190 {
+		t.Errorf("inserted block at y=%v, want 190", got)
+	}
+
+	if next.Width != layout.Width || next.Height != layout.Height {
+		t.Errorf("continuation size = %vx%v, want %vx%v", next.Width, next.Height, layout.Width, layout.Height)
+	}
+	if len(next.TextBlocks) != 1 || len(next.Images) != 1 || len(next.Groups) != 1 {
+		t.Fatalf("continuation has %d text blocks, %d images and %d groups, want 1, 1 and 1",
+			len(next.TextBlocks), len(next.Images), len(next.Groups))
+	}
+	// グループの上端が続きのページの上マージンに揃い、メンバーの相対位置は保たれる
+	img, caption := next.Images[0], next.TextBlocks[0].Rect
+	if top := img.Y + img.PlacedHeight; top != next.Height-opts.PageMargin {
+		t.Errorf("group top = %v, want %v", top, next.Height-opts.PageMargin)
+	}
+	if img.Y-caption.Y != 20 {
+		t.Errorf("caption at y=%v moved relative to the image at y=%v", caption.Y, img.Y)
+	}
+	if want := (Rectangle{X: 100, Y: caption.Y, Width: 200, Height: 170}); next.Groups[0].Rect != want {
+		t.Errorf("group rect = %+v, want %+v", next.Groups[0].Rect, want)
+	}
+}
+
+func TestRemoveBlock(t *testing.T) {
+	opts := DefaultLayoutAdjustmentOptions()
+
+	// 1つ目の段落を削除すると、下の段落が詰められ、横に並んだブロックは動かない
+	layout := editTestLayout()
+	if err := layout.RemoveBlock(0, opts); err != nil {
+		t.Fatalf("RemoveBlock failed: %v", err)
+	}
+	rects := textRects(layout)
+	if _, ok := rects["A"]; ok || len(layout.TextBlocks) != 3 {
+		t.Fatalf("text blocks after RemoveBlock = %q", flowTexts(layout))
+	}
+	for text, y := range map[string]float64{"Side": 700, "B": 650, "C": 550} {
+		if rects[text].Y != y {
+			t.Errorf("%s at y=%v, want %v", text, rects[text].Y, y)
+		}
+	}
+
+	// 別の段のブロックは動かない
+	layout = editTestLayout()
+	layout.TextBlocks = append(layout.TextBlocks, TextBlock{Text: "Note", Rect: Rectangle{X: 400, Y: 300, Width: 150, Height: 40}})
+	if err := layout.RemoveBlock(0, opts); err != nil {
+		t.Fatalf("RemoveBlock failed: %v", err)
+	}
+	if got := textRects(layout)["Note"].Y; got != 300 {
+		t.Errorf("Note at y=%v, want 300", got)
+	}
+}
+
+func TestRemoveBlock_Groups(t *testing.T) {
+	opts := DefaultLayoutAdjustmentOptions()
+
+	// 見出しを削除すると、グループのメンバーのインデックスが付け直される
+	layout := groupTestLayout()
+	if _, err := layout.GroupBlocks(figureRefs...); err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	if err := layout.RemoveBlock(0, opts); err != nil {
+		t.Fatalf("RemoveBlock failed: %v", err)
+	}
+	if len(layout.Groups) != 1 {
+		t.Fatalf("Groups has %d entries, want 1", len(layout.Groups))
+	}
+	caption := layout.Groups[0].Members[1]
+	if caption != (BlockRef{Type: ContentBlockTypeText, Index: 0}) || layout.TextBlocks[0].Text != "Figure 1" {
+		t.Errorf("caption member = %+v (%q), want text block 0", caption, layout.TextBlocks[caption.Index].Text)
+	}
+
+	// グループを削除すると、全メンバーが削除される
+	if err := layout.RemoveBlock(0, opts); err != nil {
+		t.Fatalf("RemoveBlock failed: %v", err)
+	}
+	if len(layout.TextBlocks) != 0 || len(layout.Images) != 0 || len(layout.Groups) != 0 {
+		t.Errorf("layout has %d text blocks, %d images and %d groups, want none",
+			len(layout.TextBlocks), len(layout.Images), len(layout.Groups))
+	}
+}
+
+func TestInsertRemoveBlock_Errors(t *testing.T) {
+	opts := DefaultLayoutAdjustmentOptions()
+	layout := editTestLayout()
+
+	block := TextBlock{Text: "New", Rect: Rectangle{Width: 100, Height: 10}}
+	for _, index := range []int{-2, 4} {
+		if _, err := layout.InsertBlock(index, block, opts); err == nil {
+			t.Errorf("InsertBlock(%d) should fail", index)
+		}
+	}
+	if _, err := layout.InsertBlock(0, VectorBlock{}, opts); err == nil {
+		t.Error("InsertBlock should fail for a vector block")
+	}
+	for _, index := range []int{-1, 4} {
+		if err := layout.RemoveBlock(index, opts); err == nil {
+			t.Errorf("RemoveBlock(%d) should fail", index)
+		}
+	}
+	if len(layout.TextBlocks) != 4 {
+		t.Errorf("layout has %d text blocks after failed edits, want 4", len(layout.TextBlocks))
+	}
+}
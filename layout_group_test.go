@@ -0,0 +1,192 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+// groupTestLayout は見出し、画像、画像のキャプションを持つレイアウトを作成する
+func groupTestLayout() *PageLayout {
+	return &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "Heading", Rect: Rectangle{X: 100, Y: 700, Width: 200, Height: 40}},
+			{Text: "Figure 1", Rect: Rectangle{X: 100, Y: 480, Width: 200, Height: 15}},
+		},
+		Images: []ImageBlock{
+			{X: 100, Y: 500, PlacedWidth: 200, PlacedHeight: 150},
+		},
+	}
+}
+
+// figureRefs は画像とキャプションへの参照
+var figureRefs = []BlockRef{
+	{Type: ContentBlockTypeImage, Index: 0},
+	{Type: ContentBlockTypeText, Index: 1},
+}
+
+func TestGroupBlocks(t *testing.T) {
+	layout := groupTestLayout()
+	index, err := layout.GroupBlocks(figureRefs...)
+	if err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	if index != 0 || len(layout.Groups) != 1 {
+		t.Fatalf("GroupBlocks() = %d with %d groups, want 0 with 1", index, len(layout.Groups))
+	}
+	if want := (Rectangle{X: 100, Y: 480, Width: 200, Height: 170}); layout.Groups[0].Rect != want {
+		t.Errorf("group rect = %+v, want %+v", layout.Groups[0].Rect, want)
+	}
+
+	// グループを移動すると、画像とキャプションがまとめて動く
+	if err := layout.MoveBlock(ContentBlockTypeGroup, 0, 50, -100); err != nil {
+		t.Fatalf("MoveBlock failed: %v", err)
+	}
+	if img := layout.Images[0]; img.X != 150 || img.Y != 400 {
+		t.Errorf("image at (%v, %v), want (150, 400)", img.X, img.Y)
+	}
+	if caption := layout.TextBlocks[1].Rect; caption.X != 150 || caption.Y != 380 {
+		t.Errorf("caption at (%v, %v), want (150, 380)", caption.X, caption.Y)
+	}
+	if heading := layout.TextBlocks[0].Rect; heading.X != 100 || heading.Y != 700 {
+		t.Errorf("heading moved to (%v, %v)", heading.X, heading.Y)
+	}
+	if want := (Rectangle{X: 150, Y: 380, Width: 200, Height: 170}); layout.Groups[0].Rect != want {
+		t.Errorf("group rect = %+v, want %+v", layout.Groups[0].Rect, want)
+	}
+
+	// グループをリサイズすると、左下を固定して同じ比率で拡大・縮小する
+	if err := layout.ResizeBlock(ContentBlockTypeGroup, 0, 100, 85); err != nil {
+		t.Fatalf("ResizeBlock failed: %v", err)
+	}
+	if got, want := layout.Images[0].Bounds(), (Rectangle{X: 150, Y: 390, Width: 100, Height: 75}); got != want {
+		t.Errorf("image bounds = %+v, want %+v", got, want)
+	}
+	if got, want := layout.TextBlocks[1].Rect, (Rectangle{X: 150, Y: 380, Width: 100, Height: 7.5}); got != want {
+		t.Errorf("caption rect = %+v, want %+v", got, want)
+	}
+
+	// グループを解除すると、メンバーは個別に動く
+	if err := layout.UngroupBlocks(0); err != nil {
+		t.Fatalf("UngroupBlocks failed: %v", err)
+	}
+	if len(layout.Groups) != 0 {
+		t.Fatalf("Groups has %d entries after UngroupBlocks, want 0", len(layout.Groups))
+	}
+	if err := layout.MoveBlock(ContentBlockTypeImage, 0, 10, 0); err != nil {
+		t.Fatalf("MoveBlock failed: %v", err)
+	}
+	if layout.TextBlocks[1].Rect.X != 150 {
+		t.Errorf("caption moved with the ungrouped image")
+	}
+}
+
+func TestGroupBlocks_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []BlockRef
+	}{
+		{"single block", figureRefs[:1]},
+		{"out of range", []BlockRef{{Type: ContentBlockTypeText, Index: 0}, {Type: ContentBlockTypeText, Index: 5}}},
+		{"unsupported type", []BlockRef{{Type: ContentBlockTypeText, Index: 0}, {Type: ContentBlockTypeVector, Index: 0}}},
+		{"duplicate", []BlockRef{{Type: ContentBlockTypeText, Index: 0}, {Type: ContentBlockTypeText, Index: 0}}},
+		{"already grouped", []BlockRef{{Type: ContentBlockTypeText, Index: 0}, {Type: ContentBlockTypeText, Index: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := groupTestLayout()
+			if _, err := layout.GroupBlocks(figureRefs...); err != nil {
+				t.Fatalf("GroupBlocks failed: %v", err)
+			}
+			if _, err := layout.GroupBlocks(tt.members...); err == nil {
+				t.Error("GroupBlocks should fail")
+			}
+			if len(layout.Groups) != 1 {
+				t.Errorf("Groups has %d entries, want 1", len(layout.Groups))
+			}
+		})
+	}
+
+	layout := groupTestLayout()
+	if err := layout.UngroupBlocks(0); err == nil {
+		t.Error("UngroupBlocks should fail without groups")
+	}
+	if err := layout.MoveBlock(ContentBlockTypeGroup, 0, 10, 10); err == nil {
+		t.Error("MoveBlock should fail without groups")
+	}
+	if err := layout.ResizeBlock(ContentBlockTypeGroup, 0, 10, 10); err == nil {
+		t.Error("ResizeBlock should fail without groups")
+	}
+}
+
+func TestAdjustLayout_Groups(t *testing.T) {
+	strategies := []LayoutStrategy{StrategyCompact, StrategyEvenSpacing, StrategyFlowDown}
+	for _, strategy := range strategies {
+		t.Run(string(strategy), func(t *testing.T) {
+			layout := groupTestLayout()
+			// キャプションを画像から離して、グループにしない場合は間が詰められるようにする
+			layout.TextBlocks[1].Rect.Y = 300
+			if _, err := layout.GroupBlocks(figureRefs...); err != nil {
+				t.Fatalf("GroupBlocks failed: %v", err)
+			}
+
+			opts := DefaultLayoutAdjustmentOptions()
+			opts.Strategy = strategy
+			opts.MinSpacing = 10
+			opts.PageMargin = 50
+			if err := layout.AdjustLayout(opts); err != nil {
+				t.Fatalf("AdjustLayout failed: %v", err)
+			}
+
+			// 画像とキャプションの相対位置は保たれる
+			img := layout.Images[0]
+			caption := layout.TextBlocks[1].Rect
+			if img.Y-caption.Y != 200 || img.X != caption.X {
+				t.Errorf("caption at (%v, %v) moved relative to the image at (%v, %v)", caption.X, caption.Y, img.X, img.Y)
+			}
+			// 見出しとグループは重ならない
+			heading := layout.TextBlocks[0].Rect
+			if img.Y+img.PlacedHeight > heading.Y {
+				t.Errorf("group top %v overlaps the heading at %v", img.Y+img.PlacedHeight, heading.Y)
+			}
+		})
+	}
+}
+
+func TestSplitIntoPages_Groups(t *testing.T) {
+	layout := groupTestLayout()
+	if _, err := layout.GroupBlocks(figureRefs...); err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+
+	// 見出しの後にグループ全体は入らないため、グループは次のページにまとめて置かれる
+	pages, err := layout.SplitIntoPages(250, 10, 20)
+	if err != nil {
+		t.Fatalf("SplitIntoPages failed: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("SplitIntoPages returned %d pages, want 2", len(pages))
+	}
+	if len(pages[0].TextBlocks) != 1 || len(pages[0].Images) != 0 || len(pages[0].Groups) != 0 {
+		t.Errorf("page 1 has %d text blocks, %d images and %d groups, want 1, 0 and 0",
+			len(pages[0].TextBlocks), len(pages[0].Images), len(pages[0].Groups))
+	}
+
+	page := pages[1]
+	if len(page.TextBlocks) != 1 || len(page.Images) != 1 || len(page.Groups) != 1 {
+		t.Fatalf("page 2 has %d text blocks, %d images and %d groups, want 1, 1 and 1",
+			len(page.TextBlocks), len(page.Images), len(page.Groups))
+	}
+	wantMembers := []BlockRef{{Type: ContentBlockTypeImage, Index: 0}, {Type: ContentBlockTypeText, Index: 0}}
+	for i, ref := range page.Groups[0].Members {
+		if ref != wantMembers[i] {
+			t.Errorf("member %d = %+v, want %+v", i, ref, wantMembers[i])
+		}
+	}
+	if img, caption := page.Images[0], page.TextBlocks[0].Rect; img.Y != 80 || caption.Y != 60 {
+		t.Errorf("image at y=%v and caption at y=%v, want 80 and 60", img.Y, caption.Y)
+	}
+	if got, want := page.Groups[0].Rect, (Rectangle{X: 100, Y: 60, Width: 200, Height: 170}); got != want {
+		t.Errorf("group rect = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPageLayout_EncodeJSON(t *testing.T) {
+	source := extractLayout(t, layoutRenderSourcePDF())
+	// 同じ画像を2か所に置く
+	source.Images = append(source.Images, source.Images[0])
+
+	tests := []struct {
+		name     string
+		opts     LayoutJSONOptions
+		wantRefs int
+	}{
+		{"inline", LayoutJSONOptions{}, 0},
+		{"indent", LayoutJSONOptions{Indent: "  "}, 0},
+		{"image references", LayoutJSONOptions{Images: map[string][]byte{}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := source.EncodeJSON(&buf, tt.opts); err != nil {
+				t.Fatalf("EncodeJSON failed: %v", err)
+			}
+			encoded := buf.String()
+			for _, key := range []string{`"textBlocks"`, `"fontSize"`, `"renderMode"`, `"placedWidth"`, `"boxes"`} {
+				if !strings.Contains(encoded, key) {
+					t.Errorf("JSON does not contain %s", key)
+				}
+			}
+			if got := strings.Contains(encoded, `"dataRef"`); got != (tt.wantRefs > 0) {
+				t.Errorf("JSON contains dataRef = %v, want %v", got, tt.wantRefs > 0)
+			}
+			if len(tt.opts.Images) != tt.wantRefs {
+				t.Errorf("Images has %d entries, want %d", len(tt.opts.Images), tt.wantRefs)
+			}
+
+			decoded, err := DecodeLayoutJSON(strings.NewReader(encoded), tt.opts)
+			if err != nil {
+				t.Fatalf("DecodeLayoutJSON failed: %v", err)
+			}
+			// 空のスライスはnilとして読み込まれるため、JSONで比べる
+			got, _ := json.Marshal(decoded)
+			want, _ := json.Marshal(source)
+			if !bytes.Equal(got, want) {
+				t.Errorf("decoded layout differs from the source:\n got %s\nwant %s", got, want)
+			}
+			if !bytes.Equal(decoded.Images[1].Data, source.Images[1].Data) {
+				t.Error("image data was not restored")
+			}
+		})
+	}
+}
+
+// TestDecodeLayoutJSON_Edited は外部で編集したJSONを読み込み、描き直せることをテストする
+func TestDecodeLayoutJSON_Edited(t *testing.T) {
+	source := extractLayout(t, layoutRenderSourcePDF())
+	encoded, err := json.Marshal(source)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+
+	// json.Marshalと同じ形式なので、汎用のJSONとして編集できる
+	var doc map[string]any
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	block := doc["textBlocks"].([]any)[0].(map[string]any)
+	block["text"] = "Edited paragraph"
+	block["rect"].(map[string]any)["x"] = 100.0
+	edited, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+
+	pl, err := DecodeLayoutJSON(bytes.NewReader(edited), LayoutJSONOptions{})
+	if err != nil {
+		t.Fatalf("DecodeLayoutJSON failed: %v", err)
+	}
+	if pl.TextBlocks[0].Text != "Edited paragraph" || pl.TextBlocks[0].Rect.X != 100 {
+		t.Errorf("edited block = %q at x=%v", pl.TextBlocks[0].Text, pl.TextBlocks[0].Rect.X)
+	}
+	if !bytes.Equal(pl.Images[0].Data, source.Images[0].Data) {
+		t.Error("image data was not restored")
+	}
+
+	out := New()
+	if _, err := out.AddPageFromLayout(pl, LayoutRenderOptions{}); err != nil {
+		t.Fatalf("AddPageFromLayout failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := out.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if got := extractLayout(t, buf.Bytes()); got.TextBlocks[0].Text != "Edited paragraph" {
+		t.Errorf("rendered text = %q, want %q", got.TextBlocks[0].Text, "Edited paragraph")
+	}
+}
+
+func TestDecodeLayoutJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  LayoutJSONOptions
+	}{
+		{"invalid JSON", `{"width": `, LayoutJSONOptions{}},
+		{"missing image data", `{"width": 100, "height": 100, "images": [{"dataRef": "abc"}]}`, LayoutJSONOptions{Images: map[string][]byte{}}},
+		{"no image store", `{"width": 100, "height": 100, "images": [{"dataRef": "abc"}]}`, LayoutJSONOptions{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeLayoutJSON(strings.NewReader(tt.input), tt.opts); err == nil {
+				t.Error("DecodeLayoutJSON should fail")
+			}
+		})
+	}
+}
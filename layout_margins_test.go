@@ -0,0 +1,165 @@
+package gopdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+// marginsTestLayout はヘッダー（上端に固定）、フッター（下端に固定）と2つの段落を持つレイアウトを作成する
+func marginsTestLayout() *PageLayout {
+	return &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "Header", Rect: Rectangle{X: 50, Y: 760, Width: 500, Height: 20}, Anchor: AnchorTop},
+			{Text: "A", Rect: Rectangle{X: 50, Y: 500, Width: 500, Height: 100}},
+			{Text: "B", Rect: Rectangle{X: 50, Y: 300, Width: 500, Height: 100}},
+			{Text: "Footer", Rect: Rectangle{X: 50, Y: 20, Width: 500, Height: 15}, Anchor: AnchorBottom},
+		},
+	}
+}
+
+func TestAdjustLayout_Margins(t *testing.T) {
+	tests := []struct {
+		name    string
+		margins Margins
+		wantA   float64
+	}{
+		// ヘッダーの下にMinSpacingを空けた位置（760 - 10）から詰める
+		{"header", Margins{}, 650},
+		// 余白の方が広い場合は余白の内側から詰める
+		{"top margin", Margins{Top: 100}, 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := marginsTestLayout()
+			layout.Margins = tt.margins
+			opts := DefaultLayoutAdjustmentOptions()
+			opts.Strategy = StrategyCompact
+			if err := AdjustLayout(layout, opts); err != nil {
+				t.Fatalf("AdjustLayout failed: %v", err)
+			}
+
+			rects := textRects(layout)
+			if rects["A"].Y != tt.wantA || rects["B"].Y != tt.wantA-110 {
+				t.Errorf("A at y=%v, B at y=%v, want %v and %v", rects["A"].Y, rects["B"].Y, tt.wantA, tt.wantA-110)
+			}
+			if rects["Header"].Y != 760 || rects["Footer"].Y != 20 {
+				t.Errorf("anchored blocks moved: header y=%v, footer y=%v", rects["Header"].Y, rects["Footer"].Y)
+			}
+		})
+	}
+}
+
+func TestAdjustLayout_MarginsColumns(t *testing.T) {
+	// 左右の余白の内側を段に分ける
+	layout := marginsTestLayout()
+	layout.Margins = Margins{Left: 100, Right: 60}
+	opts := columnsOptions()
+	if err := AdjustLayout(layout, opts); err != nil {
+		t.Fatalf("AdjustLayout failed: %v", err)
+	}
+	rects := textRects(layout)
+	if a, b := rects["A"], rects["B"]; a.X != 100 || b.X != 330 || a.Width != 210 {
+		t.Errorf("A at x=%v (width %v), B at x=%v, want 100 (210) and 330", a.X, a.Width, b.X)
+	}
+	if top := rects["A"].Y + rects["A"].Height; top != 750 {
+		t.Errorf("A top = %v, want 750 (below the header)", top)
+	}
+}
+
+func TestSplitIntoPages_Anchors(t *testing.T) {
+	layout := marginsTestLayout()
+	layout.Margins = Margins{Bottom: 30}
+
+	// 高さ300のページに分けると、段落が1つずつ入り、各ページにヘッダーとフッターが写される
+	pages, err := layout.SplitIntoPages(300, 10, 20)
+	if err != nil {
+		t.Fatalf("SplitIntoPages failed: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("SplitIntoPages returned %d pages, want 2", len(pages))
+	}
+	for i, page := range pages {
+		rects := textRects(page)
+		if len(page.TextBlocks) != 3 {
+			t.Errorf("page %d has %d text blocks, want 3", i+1, len(page.TextBlocks))
+		}
+		// ヘッダーは上端からの距離、フッターは下端からの距離を保つ
+		if rects["Header"].Y != 260 || rects["Footer"].Y != 20 {
+			t.Errorf("page %d: header y=%v, footer y=%v, want 260 and 20", i+1, rects["Header"].Y, rects["Footer"].Y)
+		}
+		if page.Margins != layout.Margins {
+			t.Errorf("page %d margins = %+v, want %+v", i+1, page.Margins, layout.Margins)
+		}
+		// 本文はヘッダーの下端とフッターの上端の内側
+		for _, tb := range page.TextBlocks {
+			if tb.Anchor == AnchorNone && (tb.Rect.Y < 45 || tb.Rect.Y+tb.Rect.Height > 250) {
+				t.Errorf("page %d: %s at %+v overlaps the header or footer", i+1, tb.Text, tb.Rect)
+			}
+		}
+	}
+	if _, ok := textRects(pages[0])["A"]; !ok {
+		t.Errorf("first page does not contain A")
+	}
+}
+
+func TestInsertBlock_Anchors(t *testing.T) {
+	// 押し出したブロックの続きのレイアウトにも、ヘッダーとフッターが写される
+	layout := marginsTestLayout()
+	opts := DefaultLayoutAdjustmentOptions()
+	next, err := layout.InsertBlock(-1, TextBlock{Text: "New", Rect: Rectangle{X: 50, Width: 500, Height: 500}}, opts)
+	if err != nil {
+		t.Fatalf("InsertBlock failed: %v", err)
+	}
+	if next == nil {
+		t.Fatal("InsertBlock did not return a continuation")
+	}
+
+	// 新しいブロックは先頭の段落の位置に入り、段落はフッターの上に収まらずに続きへ移る
+	rects := textRects(layout)
+	if rects["New"].Y+rects["New"].Height != 600 {
+		t.Errorf("inserted block top = %v, want 600", rects["New"].Y+rects["New"].Height)
+	}
+	if _, ok := rects["A"]; ok {
+		t.Errorf("A stayed on the page: %q", flowTexts(layout))
+	}
+	if rects["Footer"].Y != 20 {
+		t.Errorf("footer moved to y=%v", rects["Footer"].Y)
+	}
+	nextRects := textRects(next)
+	if _, ok := nextRects["Header"]; !ok {
+		t.Errorf("continuation has no header: %q", flowTexts(next))
+	}
+	if _, ok := nextRects["Footer"]; !ok {
+		t.Errorf("continuation has no footer: %q", flowTexts(next))
+	}
+	// 続きはヘッダーの下から始まる
+	if top := nextRects["A"].Y + nextRects["A"].Height; top != 750 {
+		t.Errorf("continuation starts at %v, want 750", top)
+	}
+}
+
+func TestMargins_JSONAndGroups(t *testing.T) {
+	layout := marginsTestLayout()
+	layout.Margins = Margins{Top: 40, Bottom: 40, Left: 50, Right: 50}
+
+	var buf bytes.Buffer
+	if err := layout.EncodeJSON(&buf, LayoutJSONOptions{}); err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+	decoded, err := DecodeLayoutJSON(&buf, LayoutJSONOptions{})
+	if err != nil {
+		t.Fatalf("DecodeLayoutJSON failed: %v", err)
+	}
+	if decoded.Margins != layout.Margins || decoded.TextBlocks[0].Anchor != AnchorTop || decoded.TextBlocks[3].Anchor != AnchorBottom {
+		t.Errorf("decoded margins %+v and anchors %q, %q", decoded.Margins, decoded.TextBlocks[0].Anchor, decoded.TextBlocks[3].Anchor)
+	}
+
+	// 固定したブロックはグループにできない
+	refs := []BlockRef{{Type: ContentBlockTypeText, Index: 0}, {Type: ContentBlockTypeText, Index: 1}}
+	if _, err := layout.GroupBlocks(refs...); err == nil {
+		t.Error("GroupBlocks should fail for an anchored block")
+	}
+}
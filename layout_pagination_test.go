@@ -0,0 +1,145 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// linesTextBlock は高さ12の行をn行持つテキストブロックを、上端topに作成する（1つの段落）
+func linesTextBlock(name string, n int, top float64) TextBlock {
+	tb := TextBlock{Rect: Rectangle{X: 50, Y: top - float64(n)*12, Width: 200, Height: float64(n) * 12}, FontSize: 10}
+	var texts []string
+	for i := 0; i < n; i++ {
+		line := TextLine{
+			Text: fmt.Sprintf("%s%d", name, i),
+			Rect: Rectangle{X: 50, Y: top - float64(i+1)*12, Width: 200, Height: 12},
+		}
+		tb.Lines = append(tb.Lines, line)
+		texts = append(texts, line.Text)
+	}
+	tb.Text = strings.Join(texts, "\n")
+	tb.Paragraphs = []TextParagraph{{Text: strings.Join(texts, " "), Lines: tb.Lines, Rect: tb.Rect}}
+	return tb
+}
+
+// pageLineCounts は各ページのテキストブロックの行数を返す（"intro:10 body:2"の形式）
+func pageLineCounts(pages []*PageLayout) []string {
+	var result []string
+	for _, page := range pages {
+		var blocks []string
+		for _, tb := range page.TextBlocks {
+			name := strings.TrimRight(strings.SplitN(tb.Text, "\n", 2)[0], "0123456789")
+			blocks = append(blocks, fmt.Sprintf("%s:%d", name, strings.Count(tb.Text, "\n")+1))
+		}
+		result = append(result, strings.Join(blocks, " "))
+	}
+	return result
+}
+
+func TestSplitIntoPagesWithOptions(t *testing.T) {
+	// 高さ200、マージン20のページでは、10行の導入の後に本文が2行だけ入る
+	intro := linesTextBlock("intro", 10, 780)
+	heading := linesTextBlock("heading", 1, 650)
+	body := linesTextBlock("body", 8, 600)
+	isHeading := func(block ContentBlock) bool {
+		tb, ok := block.(TextBlock)
+		return ok && strings.HasPrefix(tb.Text, "heading")
+	}
+	isBody := func(block ContentBlock) bool {
+		tb, ok := block.(TextBlock)
+		return ok && strings.HasPrefix(tb.Text, "body")
+	}
+	edited := body
+	edited.Text = "body translated into a single paragraph"
+
+	tests := []struct {
+		name   string
+		blocks []TextBlock
+		opts   PageSplitOptions
+		want   []string
+	}{
+		{"no break", []TextBlock{intro, body}, PageSplitOptions{}, []string{"intro:10", "body:8"}},
+		{"break", []TextBlock{intro, body}, PageSplitOptions{BreakTextBlocks: true}, []string{"intro:10 body:2", "body:6"}},
+		{"orphans", []TextBlock{intro, body}, PageSplitOptions{BreakTextBlocks: true, Orphans: 3}, []string{"intro:10", "body:8"}},
+		{"widows", []TextBlock{intro, body}, PageSplitOptions{BreakTextBlocks: true, Widows: 7}, []string{"intro:10 body:1", "body:7"}},
+		{"unbreakable", []TextBlock{intro, body}, PageSplitOptions{BreakTextBlocks: true, Unbreakable: isBody}, []string{"intro:10", "body:8"}},
+		{"edited text", []TextBlock{intro, edited}, PageSplitOptions{BreakTextBlocks: true}, []string{"intro:10", "body translated into a single paragraph:1"}},
+		{"without keep with next", []TextBlock{intro, heading, body}, PageSplitOptions{BreakTextBlocks: true}, []string{"intro:10 heading:1", "body:8"}},
+		{"keep with next", []TextBlock{intro, heading, body}, PageSplitOptions{BreakTextBlocks: true, KeepWithNext: isHeading}, []string{"intro:10", "heading:1 body:8"}},
+		{"taller than a page", []TextBlock{linesTextBlock("long", 30, 780)}, PageSplitOptions{BreakTextBlocks: true, Widows: 2}, []string{"long:13", "long:13", "long:4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := &PageLayout{Width: 300, Height: 800, TextBlocks: tt.blocks}
+			opts := tt.opts
+			opts.MaxHeight, opts.MinSpacing, opts.PageMargin = 200, 10, 20
+
+			pages, err := layout.SplitIntoPagesWithOptions(opts)
+			if err != nil {
+				t.Fatalf("SplitIntoPagesWithOptions failed: %v", err)
+			}
+			if got := pageLineCounts(pages); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("pages = %q, want %q", got, tt.want)
+			}
+
+			// すべてのブロックがページの内側にある
+			for i, page := range pages {
+				for _, tb := range page.TextBlocks {
+					if tb.Rect.Y < opts.PageMargin-0.001 || tb.Rect.Y+tb.Rect.Height > opts.MaxHeight-opts.PageMargin+0.001 {
+						t.Errorf("page %d: block %q at %+v is outside the margins", i+1, tb.Text, tb.Rect)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSplitIntoPagesWithOptions_SplitBlock(t *testing.T) {
+	layout := &PageLayout{Width: 300, Height: 800, TextBlocks: []TextBlock{
+		linesTextBlock("intro", 10, 780),
+		linesTextBlock("body", 8, 600),
+	}}
+	pages, err := layout.SplitIntoPagesWithOptions(PageSplitOptions{MaxHeight: 200, MinSpacing: 10, PageMargin: 20, BreakTextBlocks: true})
+	if err != nil {
+		t.Fatalf("SplitIntoPagesWithOptions failed: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("SplitIntoPagesWithOptions returned %d pages, want 2", len(pages))
+	}
+
+	// 前半はページの終わりに、後半は次のページの上端に置かれる
+	first, rest := pages[0].TextBlocks[1], pages[1].TextBlocks[0]
+	if want := (Rectangle{X: 50, Y: 26, Width: 200, Height: 24}); first.Rect != want {
+		t.Errorf("first part rect = %+v, want %+v", first.Rect, want)
+	}
+	if want := (Rectangle{X: 50, Y: 108, Width: 200, Height: 72}); rest.Rect != want {
+		t.Errorf("rest rect = %+v, want %+v", rest.Rect, want)
+	}
+
+	// 行・段落も分けられる
+	if len(first.Lines) != 2 || len(rest.Lines) != 6 {
+		t.Errorf("parts have %d and %d lines, want 2 and 6", len(first.Lines), len(rest.Lines))
+	}
+	if len(first.Paragraphs) != 1 || first.Paragraphs[0].Text != "body0 body1" {
+		t.Errorf("first part paragraphs = %+v", first.Paragraphs)
+	}
+	if len(rest.Paragraphs) != 1 || !strings.HasPrefix(rest.Paragraphs[0].Text, "body2 ") || len(rest.Paragraphs[0].Lines) != 6 {
+		t.Errorf("rest paragraphs = %+v", rest.Paragraphs)
+	}
+}
+
+func TestSplitContentBlocksIntoPages_Options(t *testing.T) {
+	blocks := []ContentBlock{linesTextBlock("intro", 10, 780), linesTextBlock("body", 8, 600)}
+	options := SplitOptions{MinSpacing: 10, PageMargin: 20, BreakTextBlocks: true, Orphans: 2}
+
+	pages, err := SplitContentBlocksIntoPages(blocks, PageSize{Width: 300, Height: 200}, options)
+	if err != nil {
+		t.Fatalf("SplitContentBlocksIntoPages failed: %v", err)
+	}
+	want := []string{"intro:10 body:2", "body:6"}
+	if got := pageLineCounts(pages); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("pages = %q, want %q", got, want)
+	}
+}
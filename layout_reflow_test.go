@@ -0,0 +1,150 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// halfEmMeasurer は1文字の幅をフォントサイズの半分とする
+type halfEmMeasurer struct{}
+
+func (halfEmMeasurer) TextWidth(text string, fontSize float64) (float64, error) {
+	return float64(len([]rune(text))) * fontSize / 2, nil
+}
+
+func TestTextBlock_Reflow(t *testing.T) {
+	// 抽出したままのブロック（2行で1段落）
+	extracted := func() TextBlock {
+		lines := []TextLine{
+			{Text: "The quick brown", Baseline: 790},
+			{Text: "fox jumps", Baseline: 778},
+		}
+		return TextBlock{
+			Text:       "The quick brown\nfox jumps",
+			Rect:       Rectangle{X: 50, Y: 776, Width: 150, Height: 24},
+			Font:       "Helvetica",
+			FontSize:   10,
+			Lines:      lines,
+			Paragraphs: []TextParagraph{{Text: "The quick brown fox jumps", Lines: lines}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(tb *TextBlock)
+		width float64
+		want  []string
+	}{
+		// 行の境目を取り除いた段落を、幅60（12文字）で折り返し直す
+		{"narrower", func(tb *TextBlock) {}, 60, []string{"The quick", "brown fox", "jumps"}},
+		{"wider", func(tb *TextBlock) {}, 200, []string{"The quick brown fox jumps"}},
+		// 書き換えたテキストは改行を段落の区切りとして折り返す
+		{"edited", func(tb *TextBlock) { tb.Text = "素早い茶色の狐が\n跳ぶ" }, 30, []string{"素早い茶色の", "狐が", "跳ぶ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := extracted()
+			tt.edit(&tb)
+			if err := tb.Reflow(halfEmMeasurer{}, 10, tt.width); err != nil {
+				t.Fatalf("Reflow failed: %v", err)
+			}
+
+			if tb.Text != strings.Join(tt.want, "\n") {
+				t.Errorf("Text = %q, want %q", tb.Text, strings.Join(tt.want, "\n"))
+			}
+			if len(tb.Lines) != len(tt.want) || len(tb.Elements) != len(tt.want) {
+				t.Fatalf("got %d lines and %d elements, want %d", len(tb.Lines), len(tb.Elements), len(tt.want))
+			}
+			// 上端（800）から行を組み、行の間隔はフォントサイズの1.2倍
+			for i, line := range tb.Lines {
+				if want := 790 - float64(i)*12; line.Baseline != want || line.Elements[0].Y != want {
+					t.Errorf("line %d baseline = %v, want %v", i, line.Baseline, want)
+				}
+				if line.Rect.Width > tt.width {
+					t.Errorf("line %q is %v wide, want <= %v", line.Text, line.Rect.Width, tt.width)
+				}
+			}
+			height := 10 + float64(len(tt.want)-1)*12
+			if want := (Rectangle{X: 50, Y: 800 - height, Width: tt.width, Height: height}); tb.Rect != want {
+				t.Errorf("Rect = %+v, want %+v", tb.Rect, want)
+			}
+		})
+	}
+}
+
+func TestTextBlock_ReflowParagraphs(t *testing.T) {
+	// 前回のReflowの結果を別の幅で折り返し直しても、段落の区切りは保たれる
+	tb := TextBlock{Text: "aaa bbb ccc\nddd", Rect: Rectangle{X: 0, Y: 0, Width: 100, Height: 100}, Font: "Helvetica"}
+	if err := tb.Reflow(halfEmMeasurer{}, 10, 20); err != nil {
+		t.Fatalf("Reflow failed: %v", err)
+	}
+	if err := tb.Reflow(halfEmMeasurer{}, 10, 60); err != nil {
+		t.Fatalf("Reflow failed: %v", err)
+	}
+	if tb.Text != "aaa bbb ccc\nddd" {
+		t.Errorf("Text = %q, want %q", tb.Text, "aaa bbb ccc\nddd")
+	}
+	if len(tb.Paragraphs) != 2 || tb.Paragraphs[0].Text != "aaa bbb ccc" || len(tb.Paragraphs[0].Lines) != 1 {
+		t.Errorf("Paragraphs = %+v", tb.Paragraphs)
+	}
+	if tb.Elements[0].Font != "Helvetica" || tb.Elements[0].Size != 10 {
+		t.Errorf("element font = %q %v, want Helvetica 10", tb.Elements[0].Font, tb.Elements[0].Size)
+	}
+}
+
+func TestTextBlock_ReflowErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		tb    TextBlock
+		font  TextMeasurer
+		width float64
+	}{
+		{"nil font", TextBlock{Text: "a"}, nil, 100},
+		{"zero width", TextBlock{Text: "a"}, halfEmMeasurer{}, 0},
+		{"rotated", TextBlock{Text: "a", Angle: 90}, halfEmMeasurer{}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := tt.tb
+			if err := tb.Reflow(tt.font, 10, tt.width); err == nil {
+				t.Error("Reflow should fail")
+			}
+			if tb.Text != tt.tb.Text || len(tb.Lines) != 0 {
+				t.Errorf("failed Reflow changed the block: %+v", tb)
+			}
+		})
+	}
+}
+
+func TestAdjustLayout_FitContentWraps(t *testing.T) {
+	// 1行では幅を超えるが、折り返せば高さに収まる
+	rect := Rectangle{X: 50, Y: 600, Width: 150, Height: 100}
+	layout := &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "This translated sentence is longer than the original one", Font: "Helvetica", FontSize: 12, Rect: rect},
+		},
+	}
+	if err := AdjustLayout(layout, LayoutAdjustmentOptions{Strategy: StrategyFitContent}); err != nil {
+		t.Fatalf("AdjustLayout failed: %v", err)
+	}
+
+	tb := layout.TextBlocks[0]
+	if tb.FontSize != 12 {
+		t.Errorf("FontSize = %v, want 12 (wrapped without shrinking)", tb.FontSize)
+	}
+	if len(tb.Lines) < 2 || strings.Count(tb.Text, "\n") != len(tb.Lines)-1 {
+		t.Errorf("text was not wrapped: %q", tb.Text)
+	}
+	if tb.Rect != rect {
+		t.Errorf("Rect = %+v, want %+v", tb.Rect, rect)
+	}
+	for _, line := range tb.Lines {
+		if width, _ := FontHelvetica.TextWidth(line.Text, 12); width > rect.Width {
+			t.Errorf("line %q is %v wide, want <= %v", line.Text, width, rect.Width)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"strings"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// LayoutRenderOptions はPageLayoutをページに描き直すときの設定
+type LayoutRenderOptions struct {
+	Font        StandardFont // テキストの標準フォント（空の場合はHelvetica。Latin-1以外の文字は '?' になる）
+	TTFFont     *TTFFont     // 日本語などを描く場合のTrueTypeフォント（指定した場合はFontより優先）
+	LineSpacing float64      // 行の間隔のフォントサイズに対する倍率（0の場合はブロックの元の行間、1行のブロックは1.2）
+	MinFontSize float64      // ブロックに収まらない場合に縮小するフォントサイズの下限（0の場合は4）
+	Alignment   TextAlign    // 行の配置
+	SkipImages  bool         // 画像を描かない
+
+	// DrawTables は表（Tables）をセルごとに描く（罫線から検出した表は格子も描く）
+	// 表のセルのテキストを含むテキストブロックは描かない（セルの区切りが失われて段落として描かれるため）
+	DrawTables bool
+}
+
+// defaultLayoutLineSpacing は行間が分からないブロックの行の間隔（フォントサイズに対する倍率）
+const defaultLayoutLineSpacing = 1.2
+
+// defaultLayoutMinFontSize はLayoutRenderOptions.MinFontSizeを省略したときのフォントサイズの下限
+const defaultLayoutMinFontSize = 4.0
+
+// layoutTableCellPadding は罫線のある表のセルの内側の余白（pt）
+const layoutTableCellPadding = 2.0
+
+// AddPageFromLayout はPageLayoutの表示される範囲と同じ大きさのページを追加し、DrawLayoutでブロックを描く
+// ExtractPageLayoutで抽出し、MoveBlock・ResizeBlock・AdjustLayoutやテキストの書き換えをしたレイアウトを
+// 新しいPDFのページとして書き出す場合に使う
+// 設計書: docs/layout_render_design.md
+func (d *Document) AddPageFromLayout(l *PageLayout, opts LayoutRenderOptions) (*Page, error) {
+	width, height := l.Width, l.Height
+	if visible := l.Boxes.Visible; visible.Width > 0 && visible.Height > 0 {
+		width, height = visible.Width, visible.Height
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid layout size: %vx%v", width, height)
+	}
+	page := d.AddPage(PageSize{Width: width, Height: height}, Portrait)
+	if err := page.DrawLayout(l, opts); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// DrawLayout はPageLayoutの画像ブロックとテキストブロックをページに描く
+// 画像を先に描き、その上にテキストを描く。座標はレイアウトの表示される範囲の左下を原点とする
+// テキストはブロックのRectの中で、optsのフォントで折り返し直し、収まらない場合はフォントサイズを小さくする
+// 色とレンダリングモード（透明なテキストなど）はブロックのものを使う。回転したテキストは折り返さずに1行で、回転した画像はその向きで描く
+// 線・矩形（Paths）は描かない。表（Tables）はopts.DrawTablesの場合のみ描く
+func (p *Page) DrawLayout(l *PageLayout, opts LayoutRenderOptions) error {
+	origin := l.Boxes.Visible
+
+	if !opts.SkipImages {
+		for i, img := range l.Images {
+			pdfImage, err := loadImageFromImageInfo(img.ImageInfo)
+			if err != nil {
+				return fmt.Errorf("failed to load image %d (%s): %w", i, img.Name, err)
+			}
+			img.X -= origin.X
+			img.Y -= origin.Y
+			if err := p.drawLayoutImage(pdfImage, img); err != nil {
+				return fmt.Errorf("failed to draw image %d: %w", i, err)
+			}
+		}
+	}
+
+	var tableBlocks map[int]bool
+	if opts.DrawTables {
+		tableBlocks = tableTextBlocks(l)
+	}
+	for i, block := range l.TextBlocks {
+		if strings.TrimSpace(block.Text) == "" || tableBlocks[i] {
+			continue
+		}
+		block.Rect.X -= origin.X
+		block.Rect.Y -= origin.Y
+		if err := p.drawLayoutText(block, opts); err != nil {
+			return fmt.Errorf("failed to draw text block %d: %w", i, err)
+		}
+	}
+
+	if opts.DrawTables {
+		for i, table := range l.Tables {
+			if err := p.drawLayoutTable(table, origin, opts); err != nil {
+				return fmt.Errorf("failed to draw table %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// drawLayoutImage は画像ブロックの位置と大きさに画像を描く（回転した画像はAngleの向きに回して描く）
+func (p *Page) drawLayoutImage(pdfImage *Image, img ImageBlock) error {
+	if img.Angle == 0 {
+		return p.DrawImage(pdfImage, img.X, img.Y, img.PlacedWidth, img.PlacedHeight)
+	}
+	rad := img.Angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	return p.drawImageMatrix(pdfImage, [6]float64{
+		img.PlacedWidth * cos, img.PlacedWidth * sin,
+		-img.PlacedHeight * sin, img.PlacedHeight * cos,
+		img.X, img.Y,
+	})
+}
+
+// drawLayoutTable は表をセルごとに描く
+// 罫線から検出した表はセルの枠を描き、セルのテキストは余白を空けて折り返す
+// 罫線のない表のセルの矩形はテキストの範囲なので、余白を空けない
+func (p *Page) drawLayoutTable(table TableBlock, origin Rectangle, opts LayoutRenderOptions) error {
+	padding := 0.0
+	if table.Ruled {
+		padding = layoutTableCellPadding
+		fmt.Fprintf(&p.content, "q\n")
+		p.SetLineWidth(0.5)
+		p.SetStrokeColor(Color{})
+		for _, row := range table.Rows {
+			for _, cell := range row.Cells {
+				p.DrawRectangle(cell.Rect.X-origin.X, cell.Rect.Y-origin.Y, cell.Rect.Width, cell.Rect.Height)
+			}
+		}
+		fmt.Fprintf(&p.content, "Q\n")
+	}
+
+	for _, row := range table.Rows {
+		for _, cell := range row.Cells {
+			if strings.TrimSpace(cell.Text) == "" {
+				continue
+			}
+			block := TextBlock{
+				Text: cell.Text,
+				Rect: Rectangle{
+					X:      cell.Rect.X - origin.X + padding,
+					Y:      cell.Rect.Y - origin.Y + padding,
+					Width:  cell.Rect.Width - 2*padding,
+					Height: cell.Rect.Height - 2*padding,
+				},
+			}
+			// フォントサイズと色はセルの最初の要素のもの
+			if len(cell.Elements) > 0 {
+				block.FontSize = cell.Elements[0].Size
+				block.Color = cell.Elements[0].Color
+			}
+			if err := p.drawLayoutText(block, opts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tableTextBlocks は表のセルのテキストを含むテキストブロックのインデックスを返す
+// ブロックの要素の半分より多くがセルの要素（同じテキスト・位置）のブロックを表のものとする
+// 要素は移動・リサイズしても変わらないため、レイアウトを調整した後でも判定できる
+func tableTextBlocks(l *PageLayout) map[int]bool {
+	type elementKey struct {
+		text string
+		x, y float64
+	}
+	cellElements := make(map[elementKey]bool)
+	for _, table := range l.Tables {
+		for _, row := range table.Rows {
+			for _, cell := range row.Cells {
+				for _, elem := range cell.Elements {
+					cellElements[elementKey{elem.Text, elem.X, elem.Y}] = true
+				}
+			}
+		}
+	}
+	if len(cellElements) == 0 {
+		return nil
+	}
+
+	blocks := make(map[int]bool)
+	for i, block := range l.TextBlocks {
+		n := 0
+		for _, elem := range block.Elements {
+			if cellElements[elementKey{elem.Text, elem.X, elem.Y}] {
+				n++
+			}
+		}
+		if n*2 > len(block.Elements) {
+			blocks[i] = true
+		}
+	}
+	return blocks
+}
+
+// drawLayoutText はテキストブロックを、Rectの中で折り返して描く
+func (p *Page) drawLayoutText(block TextBlock, opts LayoutRenderOptions) error {
+	fontSize := block.FontSize
+	if fontSize <= 0 {
+		fontSize = 12
+	}
+	text := block.Text
+	if opts.TTFFont == nil {
+		text = toLatin1(text)
+	}
+	measure := func(s string, size float64) float64 {
+		if opts.TTFFont != nil {
+			if width, err := opts.TTFFont.TextWidth(s, size); err == nil {
+				return width
+			}
+		}
+		return estimateTextWidth(s, size, string(opts.font()))
+	}
+
+	if block.Angle != 0 {
+		line := strings.Join(strings.Fields(text), " ")
+		if err := p.setLayoutFont(opts, fontSize); err != nil {
+			return err
+		}
+		// 回転した行を囲む矩形の左下が、Rectの左下に来る位置にする
+		width := measure(line, fontSize)
+		rad := block.Angle * math.Pi / 180
+		cos, sin := math.Cos(rad), math.Sin(rad)
+		minX := min(0, width*cos, width*cos-fontSize*sin, -fontSize*sin)
+		minY := min(0, width*sin, width*sin+fontSize*cos, fontSize*cos)
+		return p.drawLayoutLine(line, [6]float64{cos, sin, -sin, cos, block.Rect.X - minX, block.Rect.Y - minY}, block)
+	}
+
+	spacing := opts.LineSpacing
+	if spacing <= 0 {
+		spacing = defaultLayoutLineSpacing
+		if n := len(block.Lines); n > 1 && block.FontSize > 0 {
+			spacing = (block.Lines[0].Baseline - block.Lines[n-1].Baseline) / float64(n-1) / block.FontSize
+		}
+	}
+	minSize := opts.MinFontSize
+	if minSize <= 0 {
+		minSize = defaultLayoutMinFontSize
+	}
+
+	if runs := block.StyleRuns(); runs != nil {
+		return p.drawLayoutRuns(block, runs, fontSize, spacing, minSize, opts)
+	}
+
+	// 1行目の上端からの高さがRectに収まるまで、フォントサイズを小さくする
+	var lines []string
+	for {
+		lines = layout.WrapText(text, block.Rect.Width, func(s string) float64 { return measure(s, fontSize) })
+		height := fontSize + float64(len(lines)-1)*fontSize*spacing
+		if height <= block.Rect.Height+0.5 || fontSize <= minSize {
+			break
+		}
+		fontSize = math.Max(fontSize*0.95, minSize)
+	}
+
+	if err := p.setLayoutFont(opts, fontSize); err != nil {
+		return err
+	}
+	y := block.Rect.Y + block.Rect.Height - fontSize
+	for _, line := range lines {
+		if line != "" {
+			x := block.Rect.X
+			switch opts.Alignment {
+			case AlignCenter:
+				x += (block.Rect.Width - measure(line, fontSize)) / 2
+			case AlignRight:
+				x += block.Rect.Width - measure(line, fontSize)
+			}
+			if err := p.drawLayoutLine(line, [6]float64{1, 0, 0, 1, x, y}, block); err != nil {
+				return err
+			}
+		}
+		y -= fontSize * spacing
+	}
+	return nil
+}
+
+// layoutFauxItalicSkew はTrueTypeフォントで斜体を描くときの傾き（Tmの係数）
+const layoutFauxItalicSkew = 0.2
+
+// layoutFauxBoldWidth はTrueTypeフォントで太字を描くときの輪郭の線幅（フォントサイズに対する倍率）
+const layoutFauxBoldWidth = 0.03
+
+// standardFontStyles は標準フォントの書体ごとの 標準・太字・斜体・太字斜体
+var standardFontStyles = [][4]StandardFont{
+	{FontHelvetica, FontHelveticaBold, FontHelveticaOblique, FontHelveticaBoldOblique},
+	{FontTimesRoman, FontTimesBold, FontTimesItalic, FontTimesBoldItalic},
+	{FontCourier, FontCourierBold, FontCourierOblique, FontCourierBoldOblique},
+}
+
+// styledStandardFont はfと同じ書体の、太字・斜体の標準フォントを返す（太字・斜体のない書体はfのまま）
+func styledStandardFont(f StandardFont, bold, italic bool) StandardFont {
+	index := 0
+	if bold {
+		index++
+	}
+	if italic {
+		index += 2
+	}
+	for _, styles := range standardFontStyles {
+		for _, style := range styles {
+			if style == f {
+				return styles[index]
+			}
+		}
+	}
+	return f
+}
+
+// drawLayoutRuns はスタイルの違う部分（TextRun）があるブロックを、部分ごとのスタイルで描く
+// 折り返しとフォントサイズの縮小はdrawLayoutTextと同じ。各部分のサイズはブロックのフォントサイズとの比を保つ
+// 標準フォントは同じ書体の太字・斜体を使い、TrueTypeフォントは輪郭の線（太字）と傾き（斜体）で似せる
+func (p *Page) drawLayoutRuns(block TextBlock, runs []TextRun, fontSize, spacing, minSize float64, opts LayoutRenderOptions) error {
+	baseSize := fontSize
+	runs = append([]TextRun(nil), runs...)
+	for i := range runs {
+		if opts.TTFFont == nil {
+			runs[i].Text = toLatin1(runs[i].Text)
+		}
+		if runs[i].Size <= 0 {
+			runs[i].Size = baseSize
+		}
+	}
+	scale := 1.0
+	measure := func(s string, run TextRun) float64 {
+		size := run.Size * scale
+		if opts.TTFFont != nil {
+			if width, err := opts.TTFFont.TextWidth(s, size); err == nil {
+				return width
+			}
+		}
+		return estimateTextWidth(s, size, string(styledStandardFont(opts.font(), run.Bold, run.Italic)))
+	}
+
+	// 1行目の上端からの高さがRectに収まるまで、フォントサイズを小さくする
+	var lines [][]TextRun
+	for {
+		scale = fontSize / baseSize
+		lines = layout.WrapRuns(runs, block.Rect.Width, measure)
+		height := fontSize + float64(len(lines)-1)*fontSize*spacing
+		if height <= block.Rect.Height+0.5 || fontSize <= minSize {
+			break
+		}
+		fontSize = math.Max(fontSize*0.95, minSize)
+	}
+
+	y := block.Rect.Y + block.Rect.Height - fontSize
+	for _, line := range lines {
+		var width float64
+		for _, run := range line {
+			width += measure(run.Text, run)
+		}
+		x := block.Rect.X
+		switch opts.Alignment {
+		case AlignCenter:
+			x += (block.Rect.Width - width) / 2
+		case AlignRight:
+			x += block.Rect.Width - width
+		}
+		for _, run := range line {
+			if err := p.drawLayoutRun(run, run.Size*scale, x, y, block, opts); err != nil {
+				return err
+			}
+			x += measure(run.Text, run)
+		}
+		y -= fontSize * spacing
+	}
+	return nil
+}
+
+// drawLayoutRun は1つの部分を、そのスタイルとフォントサイズsizeで(x, y)に描く
+func (p *Page) drawLayoutRun(run TextRun, size, x, y float64, block TextBlock, opts LayoutRenderOptions) error {
+	block.Color = run.Color
+	if opts.TTFFont == nil {
+		if err := p.SetFont(styledStandardFont(opts.font(), run.Bold, run.Italic), size); err != nil {
+			return err
+		}
+		return p.drawLayoutLine(run.Text, [6]float64{1, 0, 0, 1, x, y}, block)
+	}
+
+	if err := p.SetTTFFont(opts.TTFFont, size); err != nil {
+		return err
+	}
+	tm := [6]float64{1, 0, 0, 1, x, y}
+	if run.Italic {
+		tm[2] = layoutFauxItalicSkew
+	}
+	if !run.Bold {
+		return p.drawLayoutLine(run.Text, tm, block)
+	}
+	block.RenderMode = layout.TextRenderFillStroke
+	fmt.Fprintf(&p.content, "q\n%.2f w\n", size*layoutFauxBoldWidth)
+	if err := p.drawLayoutLine(run.Text, tm, block); err != nil {
+		return err
+	}
+	fmt.Fprintf(&p.content, "Q\n")
+	return nil
+}
+
+// font は標準フォントを返す（省略時はHelvetica）
+func (opts LayoutRenderOptions) font() StandardFont {
+	if opts.Font == "" {
+		return FontHelvetica
+	}
+	return opts.Font
+}
+
+// setLayoutFont はoptsのフォントを現在のフォントにする
+func (p *Page) setLayoutFont(opts LayoutRenderOptions, size float64) error {
+	if opts.TTFFont != nil {
+		return p.SetTTFFont(opts.TTFFont, size)
+	}
+	return p.SetFont(opts.font(), size)
+}
+
+// drawLayoutLine は現在のフォントで1行を、テキスト行列tmの位置に描く
+// 色・レンダリングモード・水平スケーリングはブロックのものを使う
+func (p *Page) drawLayoutLine(text string, tm [6]float64, block TextBlock) error {
+	fontKey, encodedText, useBrackets, err := p.encodeText(text)
+	if err != nil {
+		return err
+	}
+	c := block.Color
+	fmt.Fprintf(&p.content, "BT\n")
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f rg\n%.3f %.3f %.3f RG\n", c.R, c.G, c.B, c.R, c.G, c.B)
+	p.useColorSpace("DeviceRGB")
+	if block.RenderMode != layout.TextRenderNormal {
+		fmt.Fprintf(&p.content, "%d Tr\n", block.RenderMode)
+	}
+	if block.HorizontalScaling > 0 && block.HorizontalScaling != 100 {
+		fmt.Fprintf(&p.content, "%.2f Tz\n", block.HorizontalScaling)
+	}
+	fmt.Fprintf(&p.content, "/%s %.2f Tf\n", fontKey, p.fontSize)
+	fmt.Fprintf(&p.content, "%.4f %.4f %.4f %.4f %.2f %.2f Tm\n", tm[0], tm[1], tm[2], tm[3], tm[4], tm[5])
+	if useBrackets {
+		fmt.Fprintf(&p.content, "(%s) Tj\n", encodedText)
+	} else {
+		fmt.Fprintf(&p.content, "<%s> Tj\n", encodedText)
+	}
+	fmt.Fprintf(&p.content, "ET\n")
+	return nil
+}
+
+// loadImageFromImageInfo はImageInfoからImageを作成
+// JPEGはそのまま埋め込み、それ以外は展開した画素をRGBにしてFlateで圧縮し直す
+func loadImageFromImageInfo(info ImageInfo) (*Image, error) {
+	if info.Format == ImageFormatJPEG {
+		return LoadJPEG(bytes.NewReader(info.Data))
+	}
+	decoded, err := info.ToImage()
+	if err != nil {
+		return nil, err
+	}
+	bounds := decoded.Bounds()
+	rgba := image.NewNRGBA(bounds)
+	draw.Draw(rgba, bounds, decoded, bounds.Min, draw.Src)
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		rgb = append(rgb, rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2])
+	}
+	compressed, err := compressWithZlib(rgb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress image data: %w", err)
+	}
+	return &Image{
+		Width:            bounds.Dx(),
+		Height:           bounds.Dy(),
+		Data:             compressed,
+		ColorSpace:       "DeviceRGB",
+		BitsPerComponent: 8,
+		Filter:           "FlateDecode",
+	}, nil
+}
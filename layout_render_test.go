@@ -0,0 +1,335 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// layoutRenderSourcePDF は赤い2行のテキスト、透明なテキスト、グレースケールの画像（Flate）を持つPDFを作成する
+func layoutRenderSourcePDF() []byte {
+	pixels, _ := compressWithZlib([]byte{0, 255, 255, 0})
+	contents := "1 0 0 rg BT /F1 10 Tf 20 250 Td (The quick brown fox jumps) Tj 0 -12 Td (over the lazy dog) Tj ET " +
+		"0 g BT /F1 8 Tf 3 Tr 300 20 Td (hidden) Tj ET " +
+		"q 40 0 0 20 200 50 cm /Im1 Do Q"
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 400 300] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> /XObject << /Im1 6 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width 2 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", len(pixels), pixels),
+	})
+}
+
+// extractLayout はPDFの最初のページのレイアウトを抽出する
+func extractLayout(t *testing.T, pdf []byte) *PageLayout {
+	t.Helper()
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+	return pl
+}
+
+func TestAddPageFromLayout(t *testing.T) {
+	source := extractLayout(t, layoutRenderSourcePDF())
+	if len(source.TextBlocks) != 2 || len(source.Images) != 1 {
+		t.Fatalf("source layout has %d text blocks and %d images, want 2 and 1", len(source.TextBlocks), len(source.Images))
+	}
+	words := strings.Fields(source.TextBlocks[0].Text)
+
+	// 段落を下に移動して幅を狭め、画像を右に移動する
+	if err := source.MoveBlock(ContentBlockTypeText, 0, 0, -100); err != nil {
+		t.Fatalf("MoveBlock failed: %v", err)
+	}
+	if err := source.ResizeBlock(ContentBlockTypeText, 0, 80, 60); err != nil {
+		t.Fatalf("ResizeBlock failed: %v", err)
+	}
+	if err := source.MoveBlock(ContentBlockTypeImage, 0, 100, 0); err != nil {
+		t.Fatalf("MoveBlock failed: %v", err)
+	}
+	want := source.TextBlocks[0].Rect
+
+	doc := New()
+	if _, err := doc.AddPageFromLayout(source, LayoutRenderOptions{}); err != nil {
+		t.Fatalf("AddPageFromLayout failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	got := extractLayout(t, buf.Bytes())
+
+	if got.Width != 400 || got.Height != 300 {
+		t.Errorf("page size = %vx%v, want 400x300", got.Width, got.Height)
+	}
+	if len(got.TextBlocks) != 2 {
+		t.Fatalf("rendered layout has %d text blocks, want 2: %+v", len(got.TextBlocks), got.TextBlocks)
+	}
+
+	// 段落は移動した位置で、狭めた幅に折り返される
+	paragraph := got.TextBlocks[0]
+	if gotWords := strings.Fields(paragraph.Text); strings.Join(gotWords, " ") != strings.Join(words, " ") {
+		t.Errorf("paragraph words = %q, want %q", gotWords, words)
+	}
+	if len(paragraph.Lines) <= 2 {
+		t.Errorf("paragraph has %d lines, want it re-wrapped to more than 2", len(paragraph.Lines))
+	}
+	if math.Abs(paragraph.Rect.X-want.X) > 0.5 || paragraph.Rect.Y < want.Y-0.5 ||
+		paragraph.Rect.Y+paragraph.Rect.Height > want.Y+want.Height+0.5 {
+		t.Errorf("paragraph rect = %+v, want it inside %+v", paragraph.Rect, want)
+	}
+	for _, line := range paragraph.Lines {
+		if width := line.Rect.Width; width > want.Width+0.5 {
+			t.Errorf("line %q is %v wide, want at most %v", line.Text, width, want.Width)
+		}
+	}
+	if paragraph.Color != (layout.Color{R: 1}) {
+		t.Errorf("paragraph color = %v, want red", paragraph.Color)
+	}
+
+	// 透明なテキストはレンダリングモードを保つ
+	hidden := got.TextBlocks[1]
+	if hidden.Text != "hidden" || hidden.RenderMode != TextRenderInvisible {
+		t.Errorf("hidden block = %q (render mode %d), want invisible %q", hidden.Text, hidden.RenderMode, "hidden")
+	}
+
+	// 画像は移動した位置に、同じ画素で描かれる
+	if len(got.Images) != 1 {
+		t.Fatalf("rendered layout has %d images, want 1", len(got.Images))
+	}
+	img := got.Images[0]
+	if img.X != 300 || img.Y != 50 || img.PlacedWidth != 40 || img.PlacedHeight != 20 {
+		t.Errorf("image placed at (%v, %v) %vx%v, want (300, 50) 40x20", img.X, img.Y, img.PlacedWidth, img.PlacedHeight)
+	}
+	decoded, err := img.ToImage()
+	if err != nil {
+		t.Fatalf("ToImage failed: %v", err)
+	}
+	for i, pt := range [][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		r, _, _, _ := decoded.At(pt[0], pt[1]).RGBA()
+		if wantWhite := i == 1 || i == 2; (r>>8 == 255) != wantWhite {
+			t.Errorf("pixel %v = %d, want white = %v", pt, r>>8, wantWhite)
+		}
+	}
+}
+
+func TestAddPageFromLayout_Options(t *testing.T) {
+	block := TextBlock{
+		Text:     "Title",
+		Rect:     Rectangle{X: 50, Y: 100, Width: 200, Height: 20},
+		FontSize: 12,
+	}
+
+	tests := []struct {
+		name  string
+		block TextBlock
+		opts  LayoutRenderOptions
+		ttf   bool
+		check func(t *testing.T, got TextBlock)
+	}{
+		{
+			name:  "center",
+			block: block,
+			opts:  LayoutRenderOptions{Alignment: AlignCenter},
+			check: func(t *testing.T, got TextBlock) {
+				if center := got.Rect.X + got.Rect.Width/2; math.Abs(center-150) > 0.5 {
+					t.Errorf("text center = %v, want 150", center)
+				}
+			},
+		},
+		{
+			name:  "shrink to fit",
+			block: TextBlock{Text: "a long line that does not fit", Rect: Rectangle{X: 50, Y: 100, Width: 60, Height: 14}, FontSize: 12},
+			check: func(t *testing.T, got TextBlock) {
+				if got.FontSize >= 12 || got.FontSize < defaultLayoutMinFontSize {
+					t.Errorf("font size = %v, want it reduced to fit", got.FontSize)
+				}
+				if got.Rect.Width > 60.5 {
+					t.Errorf("text width = %v, want at most 60", got.Rect.Width)
+				}
+			},
+		},
+		{
+			name:  "rotated",
+			block: TextBlock{Text: "Up", Rect: Rectangle{X: 50, Y: 100, Width: 12, Height: 14.4}, FontSize: 12, Angle: 90},
+			check: func(t *testing.T, got TextBlock) {
+				if got.Angle != 90 || math.Abs(got.Rect.X-50) > 0.5 || math.Abs(got.Rect.Y-100) > 0.5 {
+					t.Errorf("rotated block = angle %v at (%v, %v), want 90 at (50, 100)", got.Angle, got.Rect.X, got.Rect.Y)
+				}
+			},
+		},
+		{
+			name:  "TrueType font",
+			block: block,
+			ttf:   true,
+			check: func(t *testing.T, got TextBlock) {
+				if got.Text != "Title" {
+					t.Errorf("text = %q, want %q", got.Text, "Title")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			if tt.ttf {
+				fontPath := getTestTTFPath()
+				if fontPath == "" {
+					t.Skip("No test font available on this system")
+				}
+				font, err := LoadTTF(fontPath)
+				if err != nil {
+					t.Fatalf("LoadTTF failed: %v", err)
+				}
+				opts.TTFFont = font
+			}
+
+			doc := New()
+			pl := &PageLayout{Width: 300, Height: 200, TextBlocks: []TextBlock{tt.block}}
+			if _, err := doc.AddPageFromLayout(pl, opts); err != nil {
+				t.Fatalf("AddPageFromLayout failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo failed: %v", err)
+			}
+			got := extractLayout(t, buf.Bytes())
+			if len(got.TextBlocks) != 1 {
+				t.Fatalf("rendered layout has %d text blocks, want 1: %+v", len(got.TextBlocks), got.TextBlocks)
+			}
+			tt.check(t, got.TextBlocks[0])
+		})
+	}
+}
+
+func TestAddPageFromLayout_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		pl   *PageLayout
+	}{
+		{"empty page size", &PageLayout{}},
+		{"broken image", &PageLayout{Width: 100, Height: 100, Images: []ImageBlock{
+			{ImageInfo: ImageInfo{Format: ImageFormatPNG, Width: 2, Height: 2, Data: []byte("broken")}, PlacedWidth: 10, PlacedHeight: 10},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New().AddPageFromLayout(tt.pl, LayoutRenderOptions{}); err == nil {
+				t.Error("AddPageFromLayout should fail")
+			}
+		})
+	}
+
+	// SkipImagesの場合は画像を読まない
+	pl := tests[1].pl
+	if _, err := New().AddPageFromLayout(pl, LayoutRenderOptions{SkipImages: true}); err != nil {
+		t.Errorf("AddPageFromLayout with SkipImages failed: %v", err)
+	}
+}
+
+func TestWrapLayoutText(t *testing.T) {
+	// 1文字の幅を1とする
+	measure := func(s string) float64 { return float64(len([]rune(s))) }
+
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth float64
+		want     []string
+	}{
+		{"fits", "hello world", 20, []string{"hello world"}},
+		{"break at space", "hello big world", 10, []string{"hello big", "world"}},
+		{"hard break", "a\n\nb", 10, []string{"a", "", "b"}},
+		{"long word", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"CJK", "日本語の文章", 4, []string{"日本語の", "文章"}},
+		{"no limit", "hello world", 0, []string{"hello world"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := layout.WrapText(tt.text, tt.maxWidth, measure)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("WrapText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddPageFromLayout_Tables(t *testing.T) {
+	contents := "0.5 w 50 660 300 40 re S 50 680 m 350 680 l S 150 660 m 150 700 l S " +
+		"BT /F1 10 Tf 60 686 Td (Item) Tj 100 0 Td (Price) Tj ET " +
+		"BT /F1 10 Tf 60 666 Td (Apple) Tj 100 0 Td (100) Tj ET"
+	source := extractLayout(t, buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 400 800] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}))
+	if len(source.Tables) != 1 {
+		t.Fatalf("source layout has %d tables, want 1", len(source.Tables))
+	}
+
+	// セルとテキストブロックを翻訳する
+	upper := TranslateFunc(func(s string) (string, error) { return strings.ToUpper(s), nil })
+	if err := TranslateTables(source.Tables, upper); err != nil {
+		t.Fatalf("TranslateTables failed: %v", err)
+	}
+	if err := TranslateTextBlocks(source.TextBlocks, upper); err != nil {
+		t.Fatalf("TranslateTextBlocks failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		drawTables bool
+		wantTables int
+	}{
+		{"tables", true, 1},
+		{"text blocks only", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			if _, err := doc.AddPageFromLayout(source, LayoutRenderOptions{DrawTables: tt.drawTables}); err != nil {
+				t.Fatalf("AddPageFromLayout failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo failed: %v", err)
+			}
+			got := extractLayout(t, buf.Bytes())
+
+			// 格子を描いた場合は、描き直したPDFからも同じ行・列の表が検出される
+			if len(got.Tables) != tt.wantTables {
+				t.Fatalf("rendered layout has %d tables, want %d", len(got.Tables), tt.wantTables)
+			}
+			if tt.drawTables {
+				want := [][]string{{"ITEM", "PRICE"}, {"APPLE", "100"}}
+				if records := got.Tables[0].Records(); fmt.Sprint(records) != fmt.Sprint(want) {
+					t.Errorf("Records() = %q, want %q", records, want)
+				}
+			}
+
+			// セルのテキストは1回だけ描かれる
+			var text []string
+			for _, block := range got.TextBlocks {
+				text = append(text, block.Text)
+			}
+			if joined := strings.Join(text, " "); strings.Count(joined, "APPLE") != 1 || strings.Count(joined, "PRICE") != 1 {
+				t.Errorf("rendered text = %q, want each cell once", joined)
+			}
+		})
+	}
+}
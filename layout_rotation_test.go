@@ -0,0 +1,172 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// rectNear は2つの矩形の差がすべて0.01以内かを返す
+func rectNear(a, b Rectangle) bool {
+	return math.Abs(a.X-b.X) < 0.01 && math.Abs(a.Y-b.Y) < 0.01 &&
+		math.Abs(a.Width-b.Width) < 0.01 && math.Abs(a.Height-b.Height) < 0.01
+}
+
+func TestImageBlock_Rotation(t *testing.T) {
+	tests := []struct {
+		name       string
+		angle      float64
+		wantBounds Rectangle
+		setBounds  Rectangle
+		wantSize   [2]float64 // SetBounds後のPlacedWidth, PlacedHeight
+	}{
+		{"none", 0, Rectangle{X: 100, Y: 100, Width: 40, Height: 20}, Rectangle{X: 0, Y: 0, Width: 60, Height: 30}, [2]float64{60, 30}},
+		// 原点（左下の隅）を中心に反時計回りに回すので、囲む矩形は原点の左に出る
+		{"90", 90, Rectangle{X: 80, Y: 100, Width: 20, Height: 40}, Rectangle{X: 0, Y: 0, Width: 30, Height: 80}, [2]float64{80, 30}},
+		{"180", 180, Rectangle{X: 60, Y: 80, Width: 40, Height: 20}, Rectangle{X: 0, Y: 0, Width: 60, Height: 30}, [2]float64{60, 30}},
+		// 90度の倍数でない場合は縦横比を保って収める（30√2の正方形を囲む矩形は60x60）
+		{"45", 45, Rectangle{X: 100 - 10*math.Sqrt2, Y: 100, Width: 30 * math.Sqrt2, Height: 30 * math.Sqrt2}, Rectangle{X: 0, Y: 0, Width: 30 * math.Sqrt2, Height: 60}, [2]float64{40, 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := ImageBlock{X: 100, Y: 100, PlacedWidth: 40, PlacedHeight: 20, Angle: tt.angle}
+			if got := img.Bounds(); !rectNear(got, tt.wantBounds) {
+				t.Errorf("Bounds() = %+v, want %+v", got, tt.wantBounds)
+			}
+			if outline := img.Outline(); len(outline) != 4 || outline[0] != (layout.Point{X: 100, Y: 100}) {
+				t.Errorf("Outline() = %+v, want it to start at the origin (100, 100)", outline)
+			}
+
+			img.SetBounds(tt.setBounds)
+			if math.Abs(img.PlacedWidth-tt.wantSize[0]) > 0.01 || math.Abs(img.PlacedHeight-tt.wantSize[1]) > 0.01 {
+				t.Errorf("SetBounds size = %vx%v, want %vx%v", img.PlacedWidth, img.PlacedHeight, tt.wantSize[0], tt.wantSize[1])
+			}
+			if got := img.Bounds(); math.Abs(got.X-tt.setBounds.X) > 0.01 || math.Abs(got.Y-tt.setBounds.Y) > 0.01 {
+				t.Errorf("Bounds() after SetBounds = %+v, want it at (%v, %v)", got, tt.setBounds.X, tt.setBounds.Y)
+			}
+			if img.Angle != tt.angle {
+				t.Errorf("SetBounds changed the angle to %v", img.Angle)
+			}
+		})
+	}
+}
+
+func TestDetectOverlaps_Rotated(t *testing.T) {
+	// 45度回したひし形の画像（頂点は(50, 0), (100, 50), (50, 100), (0, 50)、囲む矩形は(0, 0)-(100, 100)）
+	diamond := ImageBlock{X: 50, Y: 0, PlacedWidth: 50 * math.Sqrt2, PlacedHeight: 50 * math.Sqrt2, Angle: 45}
+
+	tests := []struct {
+		name  string
+		other TextBlock
+		want  float64
+	}{
+		// 囲む矩形の隅にあるブロックは、ひし形とは重ならない
+		{"corner", TextBlock{Text: "corner", Rect: Rectangle{X: 0, Y: 0, Width: 20, Height: 20}}, 0},
+		{"inside", TextBlock{Text: "inside", Rect: Rectangle{X: 40, Y: 40, Width: 20, Height: 20}}, 400},
+		// ひし形の右下の辺（x - y = 50）で半分に切られる
+		{"edge", TextBlock{Text: "edge", Rect: Rectangle{X: 65, Y: 15, Width: 20, Height: 20}}, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &PageLayout{Width: 200, Height: 200, TextBlocks: []TextBlock{tt.other}, Images: []ImageBlock{diamond}}
+			var area float64
+			for _, overlap := range pl.DetectOverlaps() {
+				area += overlap.Area
+			}
+			if math.Abs(area-tt.want) > 0.01 {
+				t.Errorf("overlap area = %v, want %v", area, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectOverlaps_RotatedText(t *testing.T) {
+	// 90度回したテキスト（縦書きの帯）は、回転した要素の輪郭で重なりを判定する
+	text := TextBlock{
+		Text:  "Sidebar",
+		Rect:  Rectangle{X: 90, Y: 100, Width: 10, Height: 200},
+		Angle: 90,
+		Elements: []TextElement{
+			{Text: "Sidebar", X: 100, Y: 100, Width: 200, Height: 10, Angle: 90},
+		},
+	}
+	outline := text.Outline()
+	want := []layout.Point{{X: 100, Y: 100}, {X: 100, Y: 300}, {X: 90, Y: 300}, {X: 90, Y: 100}}
+	for i := range want {
+		if math.Abs(outline[i].X-want[i].X) > 0.01 || math.Abs(outline[i].Y-want[i].Y) > 0.01 {
+			t.Fatalf("Outline() = %+v, want %+v", outline, want)
+		}
+	}
+
+	pl := &PageLayout{
+		Width:      400,
+		Height:     400,
+		TextBlocks: []TextBlock{text, {Text: "Body", Rect: Rectangle{X: 95, Y: 150, Width: 100, Height: 20}}},
+	}
+	overlaps := pl.DetectOverlaps()
+	if len(overlaps) != 1 || math.Abs(overlaps[0].Area-100) > 0.01 {
+		t.Errorf("DetectOverlaps() = %+v, want one overlap of area 100", overlaps)
+	}
+}
+
+func TestAddPageFromLayout_RotatedImage(t *testing.T) {
+	// 幅40、高さ20の画像を(100, 100)を原点に90度回して描いたPDF
+	pixels, _ := compressWithZlib([]byte{0, 255, 255, 0})
+	contents := "q 0 40 -20 0 100 100 cm /Im1 Do Q"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 400 300] /Contents 4 0 R /Resources << /XObject << /Im1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width 2 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", len(pixels), pixels),
+	})
+
+	source := extractLayout(t, pdf)
+	if len(source.Images) != 1 {
+		t.Fatalf("source layout has %d images, want 1", len(source.Images))
+	}
+	img := source.Images[0]
+	if img.Angle != 90 || img.X != 100 || img.Y != 100 || img.PlacedWidth != 40 || img.PlacedHeight != 20 {
+		t.Fatalf("extracted image = (%v, %v) %vx%v at %v degrees, want (100, 100) 40x20 at 90", img.X, img.Y, img.PlacedWidth, img.PlacedHeight, img.Angle)
+	}
+	if want := (Rectangle{X: 80, Y: 100, Width: 20, Height: 40}); !rectNear(img.Bounds(), want) {
+		t.Errorf("Bounds() = %+v, want %+v", img.Bounds(), want)
+	}
+
+	// 移動して描き直しても、向きと大きさは保たれる
+	if err := source.MoveBlock(ContentBlockTypeImage, 0, 50, 20); err != nil {
+		t.Fatalf("MoveBlock failed: %v", err)
+	}
+	doc := New()
+	if _, err := doc.AddPageFromLayout(source, LayoutRenderOptions{}); err != nil {
+		t.Fatalf("AddPageFromLayout failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	got := extractLayout(t, buf.Bytes())
+	if len(got.Images) != 1 {
+		t.Fatalf("rendered layout has %d images, want 1", len(got.Images))
+	}
+	img = got.Images[0]
+	if math.Abs(img.Angle-90) > 0.01 || math.Abs(img.X-150) > 0.01 || math.Abs(img.Y-120) > 0.01 ||
+		math.Abs(img.PlacedWidth-40) > 0.01 || math.Abs(img.PlacedHeight-20) > 0.01 {
+		t.Errorf("rendered image = (%v, %v) %vx%v at %v degrees, want (150, 120) 40x20 at 90", img.X, img.Y, img.PlacedWidth, img.PlacedHeight, img.Angle)
+	}
+}
+
+func TestRotateImageBlocks_Angle(t *testing.T) {
+	// /Rotate 90のページで90度回した画像は、表示される向きでは回転していない
+	images := []ImageBlock{{X: 100, Y: 200, PlacedWidth: 50, PlacedHeight: 30, Angle: 90}}
+	rotateImageBlocks(images, 90, 612, 792)
+	img := images[0]
+	if img.X != 200 || img.Y != 512 || img.Angle != 0 || img.PlacedWidth != 50 || img.PlacedHeight != 30 {
+		t.Errorf("rotated image = (%v, %v) %vx%v at %v degrees, want (200, 512) 50x30 at 0", img.X, img.Y, img.PlacedWidth, img.PlacedHeight, img.Angle)
+	}
+}
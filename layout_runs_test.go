@@ -0,0 +1,182 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// styledTestPDF は1行に標準・太字・標準のテキストを並べた1ページのPDFを作成する
+func styledTestPDF(t *testing.T) []byte {
+	t.Helper()
+	doc := New()
+	page := doc.AddPage(PageSize{Width: 400, Height: 200}, Portrait)
+	x := 20.0
+	for _, part := range []struct {
+		font StandardFont
+		text string
+	}{
+		{FontHelvetica, "Press the"},
+		{FontHelveticaBold, "Start"},
+		{FontHelvetica, "button to begin"},
+	} {
+		if err := page.SetFont(part.font, 12); err != nil {
+			t.Fatal(err)
+		}
+		if err := page.DrawText(part.text, x, 150); err != nil {
+			t.Fatal(err)
+		}
+		x += estimateTextWidth(part.text+" ", 12, string(part.font))
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFontStyle(t *testing.T) {
+	tests := []struct {
+		name         string
+		bold, italic bool
+	}{
+		{"Helvetica", false, false},
+		{"Helvetica-BoldOblique", true, true},
+		{"ABCDEF+NotoSansJP-SemiBold", true, false},
+		{"Arial,Italic", false, true},
+		{"Times-Roman", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bold, italic := layout.FontStyle(tt.name)
+			if bold != tt.bold || italic != tt.italic {
+				t.Errorf("FontStyle(%q) = %v, %v, want %v, %v", tt.name, bold, italic, tt.bold, tt.italic)
+			}
+		})
+	}
+}
+
+func TestWrapRuns(t *testing.T) {
+	runs := []TextRun{{Text: "aa "}, {Text: "bb cc", Bold: true}, {Text: " dd"}}
+	lines := layout.WrapRuns(runs, 5, func(text string, run TextRun) float64 {
+		return float64(len(text))
+	})
+	var got []string
+	for _, line := range lines {
+		var parts []string
+		for _, run := range line {
+			parts = append(parts, fmt.Sprintf("%q/%v", run.Text, run.Bold))
+		}
+		got = append(got, strings.Join(parts, " "))
+	}
+	want := []string{`"aa "/false "bb"/true`, `"cc"/true " dd"/false`}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("WrapRuns = %v, want %v", got, want)
+	}
+}
+
+func TestExtractPageLayout_Runs(t *testing.T) {
+	reader, err := OpenReader(bytes.NewReader(styledTestPDF(t)))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+	if len(pl.TextBlocks) != 1 {
+		t.Fatalf("got %d text blocks, want 1", len(pl.TextBlocks))
+	}
+	runs := pl.TextBlocks[0].StyleRuns()
+	var got []string
+	for _, run := range runs {
+		got = append(got, fmt.Sprintf("%s/%v", strings.TrimSpace(run.Text), run.Bold))
+	}
+	if want := "Press the/false Start/true button to begin/false"; strings.Join(got, " ") != want {
+		t.Errorf("runs = %v, want %s", got, want)
+	}
+}
+
+func TestStyleRunsMarkup(t *testing.T) {
+	runs := []TextRun{{Text: "Press the"}, {Text: " Start", Bold: true}, {Text: " button to begin"}}
+	if got, want := markStyleRuns(runs), "Press the <s1>Start</s1> button to begin"; got != want {
+		t.Fatalf("markStyleRuns = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name       string
+		translated string
+		wantText   string
+		wantBold   string // 太字の部分（空の場合はRunsなし）
+	}{
+		{"moved", "<s1>開始</s1>ボタンを押して始める", "開始ボタンを押して始める", "開始"},
+		{"unknown tag", "<s7>開始</s7>ボタン", "開始ボタン", ""},
+		{"unclosed", "<s1>開始ボタン", "開始ボタン", ""},
+		{"dropped", "開始ボタンを押す", "開始ボタンを押す", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, got := unmarkStyleRuns(tt.translated, runs)
+			if text != tt.wantText {
+				t.Errorf("text = %q, want %q", text, tt.wantText)
+			}
+			var bold string
+			for _, run := range got {
+				if run.Bold {
+					bold += run.Text
+				}
+			}
+			if bold != tt.wantBold || (tt.wantBold == "") != (got == nil) {
+				t.Errorf("runs = %+v, want bold %q", got, tt.wantBold)
+			}
+		})
+	}
+}
+
+func TestTranslatePDFToWriter_PreserveStyles(t *testing.T) {
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	var received string
+	opts.Translator = TranslateFunc(func(text string) (string, error) {
+		received = text
+		return "To begin, press <s1>START</s1> now", nil
+	})
+	opts.PreserveStyles = true
+
+	var out bytes.Buffer
+	if err := TranslatePDFToWriter(bytes.NewReader(styledTestPDF(t)), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriter failed: %v", err)
+	}
+	if want := "Press the <s1>Start</s1> button to begin"; received != want {
+		t.Errorf("translator received %q, want %q", received, want)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+	var bold []string
+	var text []string
+	for _, tb := range pl.TextBlocks {
+		text = append(text, tb.Text)
+		for _, run := range tb.StyleRuns() {
+			if run.Bold {
+				bold = append(bold, strings.TrimSpace(run.Text))
+			}
+		}
+	}
+	if got := strings.Join(text, " "); got != "To begin, press START now" {
+		t.Errorf("translated text = %q", got)
+	}
+	if fmt.Sprint(bold) != "[START]" {
+		t.Errorf("bold runs = %v, want [START]", bold)
+	}
+}
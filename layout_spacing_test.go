@@ -0,0 +1,131 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+// spacingTestLayout は見出し、本文、注記が上から並び、上端にヘッダーを固定したレイアウトを作成する
+// 見出しと本文の間隔は50、本文と注記の間隔は150
+func spacingTestLayout() *PageLayout {
+	return &PageLayout{
+		Width:  600,
+		Height: 800,
+		TextBlocks: []TextBlock{
+			{Text: "Header", Rect: Rectangle{X: 50, Y: 760, Width: 500, Height: 20}, Anchor: AnchorTop},
+			{Text: "Heading", Rect: Rectangle{X: 50, Y: 700, Width: 500, Height: 30}},
+			{Text: "Body", Rect: Rectangle{X: 50, Y: 500, Width: 500, Height: 150}},
+			{Text: "Note", Rect: Rectangle{X: 50, Y: 300, Width: 500, Height: 50}},
+		},
+	}
+}
+
+var (
+	spacingHeader  = BlockRef{Type: ContentBlockTypeText, Index: 0}
+	spacingHeading = BlockRef{Type: ContentBlockTypeText, Index: 1}
+	spacingBody    = BlockRef{Type: ContentBlockTypeText, Index: 2}
+	spacingNote    = BlockRef{Type: ContentBlockTypeText, Index: 3}
+)
+
+func TestAdjustLayout_Spacing(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(pl *PageLayout)
+		spacing []SpacingConstraint
+		want    []float64 // 見出し、本文、注記の下端
+	}{
+		{"no constraints", nil, nil, []float64{700, 500, 300}},
+		// 見出しから離れすぎた本文を12pt以内に引き上げる
+		{"max gap", nil, []SpacingConstraint{{Above: spacingHeading, Below: spacingBody, Max: 12}}, []float64{700, 538, 300}},
+		// 本文の後の間隔を20ptに固定する
+		{"fixed gap", nil, []SpacingConstraint{{Above: spacingBody, Below: spacingNote, Min: 20, Max: 20}}, []float64{700, 500, 430}},
+		// 翻訳で高くなった見出しの後は、MinSpacingの代わりに24pt空ける
+		{
+			"min gap",
+			func(pl *PageLayout) { pl.TextBlocks[1].Rect = Rectangle{X: 50, Y: 640, Width: 500, Height: 90} },
+			[]SpacingConstraint{{Above: spacingHeading, Below: spacingBody, Min: 24}},
+			[]float64{640, 466, 300},
+		},
+		// ヘッダーの下端から5ptに見出しを置く
+		{"after header", nil, []SpacingConstraint{{Above: spacingHeader, Below: spacingHeading, Min: 5, Max: 5}}, []float64{725, 500, 300}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := spacingTestLayout()
+			if tt.edit != nil {
+				tt.edit(layout)
+			}
+			opts := DefaultLayoutAdjustmentOptions()
+			opts.Strategy = StrategyFlowDown
+			opts.Spacing = tt.spacing
+			if err := AdjustLayout(layout, opts); err != nil {
+				t.Fatalf("AdjustLayout failed: %v", err)
+			}
+
+			for i, want := range tt.want {
+				if got := layout.TextBlocks[i+1].Rect.Y; got != want {
+					t.Errorf("%s at y=%v, want %v", layout.TextBlocks[i+1].Text, got, want)
+				}
+			}
+			if layout.TextBlocks[0].Rect.Y != 760 {
+				t.Errorf("header moved to y=%v", layout.TextBlocks[0].Rect.Y)
+			}
+		})
+	}
+}
+
+func TestAdjustLayout_SpacingErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint SpacingConstraint
+	}{
+		{"above after below", SpacingConstraint{Above: spacingNote, Below: spacingBody}},
+		{"same block", SpacingConstraint{Above: spacingBody, Below: spacingBody}},
+		{"anchored below", SpacingConstraint{Above: spacingHeading, Below: spacingHeader}},
+		{"out of range", SpacingConstraint{Above: spacingHeading, Below: BlockRef{Type: ContentBlockTypeImage, Index: 0}}},
+		{"unknown group", SpacingConstraint{Above: spacingHeading, Below: BlockRef{Type: ContentBlockTypeGroup, Index: 0}}},
+		{"max below min", SpacingConstraint{Above: spacingHeading, Below: spacingBody, Min: 20, Max: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := spacingTestLayout()
+			layout.TextBlocks[2].Rect.Y = 600 // 見出しと重ねる
+			opts := DefaultLayoutAdjustmentOptions()
+			opts.Strategy = StrategyFlowDown
+			opts.Spacing = []SpacingConstraint{tt.constraint}
+			if err := AdjustLayout(layout, opts); err == nil {
+				t.Error("AdjustLayout should fail")
+			}
+			if layout.TextBlocks[2].Rect.Y != 600 {
+				t.Errorf("failed AdjustLayout moved the body to y=%v", layout.TextBlocks[2].Rect.Y)
+			}
+		})
+	}
+}
+
+func TestAdjustLayout_SpacingGroups(t *testing.T) {
+	// グループのメンバーへの制約はグループ全体（画像とキャプション）を動かす
+	layout := groupTestLayout()
+	g, err := layout.GroupBlocks(figureRefs...)
+	if err != nil {
+		t.Fatalf("GroupBlocks failed: %v", err)
+	}
+	before := layout.Groups[g].Rect
+	heading := layout.TextBlocks[0].Rect
+
+	opts := DefaultLayoutAdjustmentOptions()
+	opts.Strategy = StrategyFlowDown
+	opts.Spacing = []SpacingConstraint{{Above: BlockRef{Type: ContentBlockTypeText, Index: 0}, Below: figureRefs[1], Min: 4, Max: 4}}
+	if err := AdjustLayout(layout, opts); err != nil {
+		t.Fatalf("AdjustLayout failed: %v", err)
+	}
+
+	after := layout.Groups[g].Rect
+	if top := after.Y + after.Height; top != heading.Y-4 {
+		t.Errorf("group top = %v, want %v", top, heading.Y-4)
+	}
+	if after.Height != before.Height {
+		t.Errorf("group height changed from %v to %v", before.Height, after.Height)
+	}
+}
@@ -0,0 +1,133 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// templateTestTemplate はヘッダー・タイトル・本文（Flow）のスロットを持つテンプレートを作成する
+func templateTestTemplate() LayoutTemplate {
+	return LayoutTemplate{
+		Width:   200,
+		Height:  200,
+		Margins: Margins{Top: 10, Bottom: 10, Left: 10, Right: 10},
+		Slots: []TemplateSlot{
+			{Name: "header", Rect: Rectangle{X: 10, Y: 180, Width: 180, Height: 10}, FontSize: 8, Anchor: AnchorTop},
+			{Name: "title", Rect: Rectangle{X: 10, Y: 150, Width: 180, Height: 20}, Font: "Helvetica-Bold", FontSize: 16},
+			{Name: "body", Rect: Rectangle{X: 10, Y: 20, Width: 100, Height: 120}, FontSize: 10, Flow: true},
+		},
+	}
+}
+
+func TestLayoutTemplate_Fill(t *testing.T) {
+	// 本文は1行4語で15行（行の高さ10、間隔12）。本文のスロットには10行まで入る
+	bodyText := strings.TrimSpace(strings.Repeat("word ", 60))
+	body := TextBlock{Text: bodyText, Rect: Rectangle{X: 300, Y: 300, Width: 50, Height: 10}, FontSize: 9}
+	figure := ImageBlock{X: 0, Y: 0, PlacedWidth: 200, PlacedHeight: 100}
+
+	tmpl := templateTestTemplate()
+	pages, err := tmpl.Fill(map[string][]ContentBlock{
+		"header": {TextBlock{Text: "Confidential", FontSize: 12}},
+		"title":  {TextBlock{Text: "Report", FontSize: 12}},
+		"body":   {body, figure},
+	}, TemplateOptions{Spacing: 4, Measurer: halfEmMeasurer{}})
+	if err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("Fill returned %d pages, want 2", len(pages))
+	}
+	for i, pl := range pages {
+		if pl.Width != 200 || pl.Height != 200 || pl.Margins != tmpl.Margins {
+			t.Errorf("page %d: size %vx%v, margins %+v", i, pl.Width, pl.Height, pl.Margins)
+		}
+	}
+
+	// 1ページ目：タイトル、本文の先頭10行、ヘッダー
+	first := pages[0]
+	if len(first.TextBlocks) != 3 || len(first.Images) != 0 {
+		t.Fatalf("page 0 has %d text blocks and %d images, want 3 and 0", len(first.TextBlocks), len(first.Images))
+	}
+	title := first.TextBlocks[0]
+	if title.Text != "Report" || title.Font != "Helvetica-Bold" || title.FontSize != 16 || title.Rect != (Rectangle{X: 10, Y: 154, Width: 180, Height: 16}) {
+		t.Errorf("title = %q %s %v at %+v", title.Text, title.Font, title.FontSize, title.Rect)
+	}
+	bodyFirst := first.TextBlocks[1]
+	if len(bodyFirst.Lines) != 10 || bodyFirst.FontSize != 10 || bodyFirst.Rect != (Rectangle{X: 10, Y: 22, Width: 100, Height: 118}) {
+		t.Errorf("body on page 0 = %d lines at %+v", len(bodyFirst.Lines), bodyFirst.Rect)
+	}
+
+	// 2ページ目：本文の残り5行と、幅に縮めた画像
+	second := pages[1]
+	if len(second.TextBlocks) != 2 || len(second.Images) != 1 {
+		t.Fatalf("page 1 has %d text blocks and %d images, want 2 and 1", len(second.TextBlocks), len(second.Images))
+	}
+	bodyRest := second.TextBlocks[0]
+	if len(bodyRest.Lines) != 5 || bodyRest.Rect != (Rectangle{X: 10, Y: 82, Width: 100, Height: 58}) {
+		t.Errorf("body on page 1 = %d lines at %+v", len(bodyRest.Lines), bodyRest.Rect)
+	}
+	if got := second.Images[0].Bounds(); got != (Rectangle{X: 10, Y: 28, Width: 100, Height: 50}) {
+		t.Errorf("figure at %+v, want (10, 28) 100x50", got)
+	}
+
+	// ヘッダーは両方のページに固定される
+	for i, pl := range pages {
+		header := pl.TextBlocks[len(pl.TextBlocks)-1]
+		if header.Text != "Confidential" || header.Anchor != AnchorTop || header.FontSize != 8 || header.Rect.Y != 182 {
+			t.Errorf("page %d: header = %q %v anchored %q at y=%v", i, header.Text, header.FontSize, header.Anchor, header.Rect.Y)
+		}
+	}
+
+	// 流し込んだ内容は変えない
+	if body.Text != bodyText || body.FontSize != 9 || body.Rect.X != 300 || figure.PlacedWidth != 200 {
+		t.Error("Fill modified the content")
+	}
+}
+
+func TestLayoutTemplate_FillWithoutMeasurer(t *testing.T) {
+	// 折り返さない場合は高さを保ち、Flowでないスロットでは下端を越えても同じページに置く
+	tmpl := templateTestTemplate()
+	pages, err := tmpl.Fill(map[string][]ContentBlock{
+		"title": {
+			TextBlock{Text: "Long\ntitle", Rect: Rectangle{Width: 300, Height: 30}},
+			TextBlock{Text: "Subtitle", Rect: Rectangle{Width: 300, Height: 10}},
+		},
+	}, TemplateOptions{})
+	if err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+	if len(pages) != 1 || len(pages[0].TextBlocks) != 2 {
+		t.Fatalf("Fill returned %d pages, want 1 page with 2 text blocks", len(pages))
+	}
+	want := []Rectangle{{X: 10, Y: 140, Width: 180, Height: 30}, {X: 10, Y: 130, Width: 180, Height: 10}}
+	for i, tb := range pages[0].TextBlocks {
+		if tb.Rect != want[i] {
+			t.Errorf("%q at %+v, want %+v", tb.Text, tb.Rect, want[i])
+		}
+	}
+}
+
+func TestLayoutTemplate_FillErrors(t *testing.T) {
+	slot := TemplateSlot{Name: "body", Rect: Rectangle{X: 10, Y: 10, Width: 100, Height: 100}}
+	tests := []struct {
+		name    string
+		slots   []TemplateSlot
+		content map[string][]ContentBlock
+	}{
+		{"empty name", []TemplateSlot{{Rect: slot.Rect}}, nil},
+		{"duplicate name", []TemplateSlot{slot, slot}, nil},
+		{"zero size", []TemplateSlot{{Name: "body", Rect: Rectangle{X: 10, Y: 10}}}, nil},
+		{"anchored flow", []TemplateSlot{{Name: "footer", Rect: slot.Rect, Anchor: AnchorBottom, Flow: true}}, nil},
+		{"unknown slot", []TemplateSlot{slot}, map[string][]ContentBlock{"figure": {TextBlock{Text: "x"}}}},
+		{"unsupported block", []TemplateSlot{slot}, map[string][]ContentBlock{"body": {PathBlock{}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := LayoutTemplate{Width: 200, Height: 200, Slots: tt.slots}
+			if _, err := tmpl.Fill(tt.content, TemplateOptions{}); err == nil {
+				t.Error("Fill should fail")
+			}
+		})
+	}
+}
@@ -2,8 +2,16 @@ package gopdf
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+	"github.com/ryomak/gopdf/layout"
 )
 
 func TestExtractPageLayout(t *testing.T) {
@@ -149,6 +157,124 @@ func TestGroupTextElements(t *testing.T) {
 	}
 }
 
+func TestGroupTextElements_Columns(t *testing.T) {
+	reader := &PDFReader{}
+
+	// 2段組み: 同じベースラインに左右の段の行が並ぶ
+	var elements []TextElement
+	for i, y := range []float64{700, 686, 672} {
+		elements = append(elements,
+			TextElement{Text: fmt.Sprintf("L%d", i+1), X: 50, Y: y, Width: 200, Height: 12, Size: 12},
+			TextElement{Text: fmt.Sprintf("R%d", i+1), X: 300, Y: y, Width: 200, Height: 12, Size: 12},
+		)
+	}
+	// ベースラインが少しずれた上付き文字は同じ行に含める
+	elements = append(elements, TextElement{Text: "2", X: 251, Y: 704, Width: 6, Height: 8, Size: 8})
+
+	blocks := reader.groupTextElements(elements)
+
+	want := []string{"L12\nL2\nL3", "R1\nR2\nR3"}
+	if len(blocks) != len(want) {
+		t.Fatalf("got %d blocks, want %d: %+v", len(blocks), len(want), blocks)
+	}
+	for i, block := range blocks {
+		if block.Text != want[i] {
+			t.Errorf("blocks[%d].Text = %q, want %q", i, block.Text, want[i])
+		}
+		if len(block.Lines) != 3 {
+			t.Errorf("blocks[%d] has %d lines, want 3", i, len(block.Lines))
+		}
+	}
+	if got := blocks[0].Lines[0].Baseline; got != (700+704)/2.0 {
+		t.Errorf("Lines[0].Baseline = %v, want 702", got)
+	}
+}
+
+func TestGroupTextElements_Paragraphs(t *testing.T) {
+	reader := &PDFReader{}
+
+	type line struct {
+		text string
+		x, y float64
+		size float64
+	}
+
+	tests := []struct {
+		name       string
+		lines      []line
+		want       []string // 段落のテキスト
+		wantIndent float64  // 最初の段落の字下げ
+	}{
+		{
+			name: "first line indent",
+			lines: []line{
+				{"Call me", 70, 700, 12}, {"Ishmael.", 50, 686, 12}, {"Some years", 50, 672, 12},
+				{"ago never", 70, 658, 12}, {"mind.", 50, 644, 12},
+			},
+			want:       []string{"Call me Ishmael. Some years", "ago never mind."},
+			wantIndent: 20,
+		},
+		{
+			name: "wider leading between paragraphs",
+			lines: []line{
+				{"one", 50, 700, 12}, {"two", 50, 686, 12}, {"three", 50, 672, 12},
+				{"four", 50, 652, 12}, {"five", 50, 638, 12},
+			},
+			want: []string{"one two three", "four five"},
+		},
+		{
+			name: "loose but uniform leading",
+			lines: []line{
+				{"one", 50, 700, 12}, {"two", 50, 680, 12}, {"three", 50, 660, 12},
+			},
+			want: []string{"one two three"},
+		},
+		{
+			name: "font size change",
+			lines: []line{
+				{"Heading", 50, 700, 16}, {"body", 50, 680, 12}, {"text", 50, 666, 12},
+			},
+			want: []string{"Heading", "body text"},
+		},
+		{
+			name: "japanese lines are joined without spaces",
+			lines: []line{
+				{"これは", 50, 700, 12}, {"日本語", 50, 686, 12},
+			},
+			want: []string{"これは日本語"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var elements []TextElement
+			for _, l := range tt.lines {
+				elements = append(elements, TextElement{
+					Text: l.text, X: l.x, Y: l.y, Width: 100, Height: l.size, Size: l.size,
+				})
+			}
+
+			blocks := reader.groupTextElements(elements)
+			if len(blocks) != 1 {
+				t.Fatalf("got %d blocks, want 1", len(blocks))
+			}
+
+			paragraphs := blocks[0].Paragraphs
+			if len(paragraphs) != len(tt.want) {
+				t.Fatalf("got %d paragraphs %+v, want %d", len(paragraphs), paragraphs, len(tt.want))
+			}
+			for i, p := range paragraphs {
+				if p.Text != tt.want[i] {
+					t.Errorf("Paragraphs[%d].Text = %q, want %q", i, p.Text, tt.want[i])
+				}
+			}
+			if paragraphs[0].Indent != tt.wantIndent {
+				t.Errorf("Paragraphs[0].Indent = %v, want %v", paragraphs[0].Indent, tt.wantIndent)
+			}
+		})
+	}
+}
+
 func TestCreateTextBlock(t *testing.T) {
 	elements := []TextElement{
 		{Text: "Hello", X: 100, Y: 700, Width: 30, Height: 12, Font: "Helvetica", Size: 12},
@@ -213,3 +339,631 @@ func TestGetPageSize(t *testing.T) {
 		t.Errorf("Page size = %.1f x %.1f, want 595.0 x 842.0", width, height)
 	}
 }
+
+func TestExtractPageLayout_RotatedText(t *testing.T) {
+	contents := "BT /F1 10 Tf 100 700 Td (Body text) Tj ET " +
+		// 縦書きの軸ラベル（下から上へ）
+		"BT /F1 10 Tf 0 1 -1 0 40 300 Tm (Axis label) Tj ET " +
+		// 1文字ずつ配置した縦書きのラベル
+		"BT /F1 10 Tf 0 1 -1 0 60 300 Tm (A) Tj 0 1 -1 0 60 306 Tm (B) Tj ET"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+
+	tests := []struct {
+		text  string
+		angle float64
+		rect  Rectangle
+	}{
+		{"Body text", 0, Rectangle{X: 100, Y: 700, Width: 54, Height: 10}},
+		// 幅（1文字6ポイント）は上向き、高さは左向きに伸びる
+		{"Axis label", 90, Rectangle{X: 30, Y: 300, Width: 10, Height: 60}},
+		{"AB", 90, Rectangle{X: 50, Y: 300, Width: 10, Height: 12}},
+	}
+	if len(pl.TextBlocks) != len(tests) {
+		t.Fatalf("TextBlocks = %+v, want %d blocks", pl.TextBlocks, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			block := pl.TextBlocks[i]
+			if block.Text != tt.text {
+				t.Errorf("Text = %q, want %q", block.Text, tt.text)
+			}
+			if block.Angle != tt.angle {
+				t.Errorf("Angle = %v, want %v", block.Angle, tt.angle)
+			}
+			got := block.Rect
+			if math.Abs(got.X-tt.rect.X) > 1e-6 || math.Abs(got.Y-tt.rect.Y) > 1e-6 ||
+				math.Abs(got.Width-tt.rect.Width) > 1e-6 || math.Abs(got.Height-tt.rect.Height) > 1e-6 {
+				t.Errorf("Rect = %+v, want %+v", got, tt.rect)
+			}
+		})
+	}
+}
+
+func TestExtractPageLayout_FlippedCTM(t *testing.T) {
+	// 左上を原点とする座標系で書かれたページ（Y軸を反転するページレベルのCTM）
+	contents := "1 0 0 -1 0 792 cm " +
+		"BT /F1 12 Tf 1 0 0 -1 50 742 Tm (Bottom) Tj ET " +
+		"BT /F1 12 Tf 1 0 0 -1 50 42 Tm (Top) Tj ET"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+
+	if pl.PageCTM == nil || pl.PageCTM.D != -1 {
+		t.Errorf("PageCTM = %+v, want the flipping matrix", pl.PageCTM)
+	}
+	want := []struct {
+		text string
+		y    float64
+	}{
+		{"Top", 750},
+		{"Bottom", 50},
+	}
+	if len(pl.TextBlocks) != len(want) {
+		t.Fatalf("TextBlocks = %+v, want %d blocks", pl.TextBlocks, len(want))
+	}
+	for i, w := range want {
+		block := pl.TextBlocks[i]
+		if block.Text != w.text || block.Elements[0].Y != w.y || block.Angle != 0 {
+			t.Errorf("TextBlocks[%d] = %q at y=%v (angle %v), want %q at y=%v", i, block.Text, block.Elements[0].Y, block.Angle, w.text, w.y)
+		}
+	}
+}
+
+// buildRotatedPDF は612x792の1ページに(100, 700)から"Top"と書いたPDFを生成する
+// rotateが0でなければページに/Rotateを設定する
+func buildRotatedPDF(t *testing.T, rotate int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := writer.NewWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+
+	pagesNum := w.ReserveObject()
+	fontNum, err := w.AddObject(core.Dictionary{
+		core.Name("Type"):     core.Name("Font"),
+		core.Name("Subtype"):  core.Name("Type1"),
+		core.Name("BaseFont"): core.Name("Helvetica"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := []byte("BT\n/F1 12 Tf\n100 700 Td\n(Top) Tj\nET\n")
+	contentsNum, err := w.AddObject(&core.Stream{
+		Dict: core.Dictionary{core.Name("Length"): core.Integer(len(contents))},
+		Data: contents,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := core.Dictionary{
+		core.Name("Type"):     core.Name("Page"),
+		core.Name("Parent"):   &core.Reference{ObjectNumber: pagesNum},
+		core.Name("MediaBox"): core.Array{core.Integer(0), core.Integer(0), core.Integer(612), core.Integer(792)},
+		core.Name("Contents"): &core.Reference{ObjectNumber: contentsNum},
+		core.Name("Resources"): core.Dictionary{
+			core.Name("Font"): core.Dictionary{core.Name("F1"): &core.Reference{ObjectNumber: fontNum}},
+		},
+	}
+	if rotate != 0 {
+		page[core.Name("Rotate")] = core.Integer(rotate)
+	}
+	pageNum, err := w.AddObject(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteObject(pagesNum, core.Dictionary{
+		core.Name("Type"):  core.Name("Pages"),
+		core.Name("Kids"):  core.Array{&core.Reference{ObjectNumber: pageNum}},
+		core.Name("Count"): core.Integer(1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	catalogNum, err := w.AddObject(core.Dictionary{
+		core.Name("Type"):  core.Name("Catalog"),
+		core.Name("Pages"): &core.Reference{ObjectNumber: pagesNum},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteTrailer(core.Dictionary{
+		core.Name("Root"): &core.Reference{ObjectNumber: catalogNum},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestExtractPageLayout_Rotate は/Rotateのあるページのレイアウトが表示される向きになることをテストする
+func TestExtractPageLayout_Rotate(t *testing.T) {
+	tests := []struct {
+		rotate        int
+		wantRotation  int
+		width, height float64
+		x, y          float64
+		angle         float64 // 表示される向きでのベースラインの向き
+	}{
+		{rotate: 0, wantRotation: 0, width: 612, height: 792, x: 100, y: 700, angle: 0},
+		{rotate: 90, wantRotation: 90, width: 792, height: 612, x: 700, y: 512, angle: -90},
+		{rotate: 180, wantRotation: 180, width: 612, height: 792, x: 512, y: 92, angle: 180},
+		{rotate: 270, wantRotation: 270, width: 792, height: 612, x: 92, y: 100, angle: 90},
+		{rotate: -90, wantRotation: 270, width: 792, height: 612, x: 92, y: 100, angle: 90},
+		{rotate: 45, wantRotation: 0, width: 612, height: 792, x: 100, y: 700, angle: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Rotate %d", tt.rotate), func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(buildRotatedPDF(t, tt.rotate)))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			defer reader.Close()
+
+			pl, err := reader.ExtractPageLayout(0)
+			if err != nil {
+				t.Fatalf("ExtractPageLayout() failed: %v", err)
+			}
+			if pl.Rotation != tt.wantRotation {
+				t.Errorf("Rotation = %d, want %d", pl.Rotation, tt.wantRotation)
+			}
+			if pl.Width != tt.width || pl.Height != tt.height {
+				t.Errorf("page size = %.0f x %.0f, want %.0f x %.0f", pl.Width, pl.Height, tt.width, tt.height)
+			}
+			if len(pl.TextBlocks) != 1 || len(pl.TextBlocks[0].Elements) != 1 {
+				t.Fatalf("TextBlocks = %+v, want one block with one element", pl.TextBlocks)
+			}
+			elem := pl.TextBlocks[0].Elements[0]
+			if elem.X != tt.x || elem.Y != tt.y {
+				t.Errorf("layout element at (%.0f, %.0f), want (%.0f, %.0f)", elem.X, elem.Y, tt.x, tt.y)
+			}
+			if elem.Angle != tt.angle || pl.TextBlocks[0].Angle != tt.angle {
+				t.Errorf("Angle = %v (block %v), want %v", elem.Angle, pl.TextBlocks[0].Angle, tt.angle)
+			}
+
+			elements, err := reader.ExtractPageTextElements(0)
+			if err != nil {
+				t.Fatalf("ExtractPageTextElements() failed: %v", err)
+			}
+			if len(elements) != 1 || elements[0].X != tt.x || elements[0].Y != tt.y {
+				t.Errorf("ExtractPageTextElements() = %+v, want one element at (%.0f, %.0f)", elements, tt.x, tt.y)
+			}
+		})
+	}
+}
+
+// TestRotateImageBlocks は画像の配置矩形の回転をテストする
+func TestRotateImageBlocks(t *testing.T) {
+	// 612x792のページの(100, 200)に幅50、高さ30で配置した画像
+	tests := []struct {
+		rotation int
+		want     [4]float64 // X, Y, PlacedWidth, PlacedHeight
+	}{
+		{0, [4]float64{100, 200, 50, 30}},
+		{90, [4]float64{200, 462, 30, 50}},
+		{180, [4]float64{462, 562, 50, 30}},
+		{270, [4]float64{562, 100, 30, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d", tt.rotation), func(t *testing.T) {
+			images := []layout.ImageBlock{{X: 100, Y: 200, PlacedWidth: 50, PlacedHeight: 30}}
+			rotateImageBlocks(images, tt.rotation, 612, 792)
+			got := [4]float64{images[0].X, images[0].Y, images[0].PlacedWidth, images[0].PlacedHeight}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("rotated image = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestWalkPageLayouts はページごとのレイアウトの走査をテストする
+func TestWalkPageLayouts(t *testing.T) {
+	doc := New()
+	for i := 1; i <= 3; i++ {
+		page := doc.AddPage(PageSizeA4, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatalf("Failed to set font: %v", err)
+		}
+		if err := page.DrawText(fmt.Sprintf("Page %d", i), 100, 700); err != nil {
+			t.Fatalf("Failed to draw text: %v", err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("Failed to write PDF: %v", err)
+	}
+
+	errStop := errors.New("stop")
+	tests := []struct {
+		name      string
+		stopAfter int // このページ数を処理したら中断する（0は最後まで）
+		wantPages []int
+		wantErr   error
+	}{
+		{"all pages", 0, []int{0, 1, 2}, nil},
+		{"stop early", 1, []int{0}, errStop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+			reader.SetCacheSize(4)
+
+			var pages []int
+			err = reader.WalkPageLayouts(func(l *PageLayout) error {
+				pages = append(pages, l.PageNum)
+				if len(l.TextBlocks) == 0 || !strings.Contains(l.TextBlocks[0].Text, fmt.Sprintf("Page %d", l.PageNum+1)) {
+					t.Errorf("page %d: unexpected text blocks %+v", l.PageNum, l.TextBlocks)
+				}
+				if len(pages) == tt.stopAfter {
+					return errStop
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WalkPageLayouts() error = %v, want %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(pages) != fmt.Sprint(tt.wantPages) {
+				t.Errorf("pages = %v, want %v", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+// TestExtractPageLayout_Paths は線と矩形がPathsとして抽出されることをテストする
+func TestExtractPageLayout_Paths(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetLineWidth(2)
+	page.SetStrokeColor(Color{R: 1, G: 0, B: 0})
+	page.DrawLine(50, 700, 300, 700)
+	page.SetFillColor(Color{R: 0, G: 0, B: 1})
+	page.FillRectangle(100, 500, 200, 50)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("Failed to write PDF: %v", err)
+	}
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+
+	want := []struct {
+		kind        layout.PathKind
+		stroke      bool
+		fill        bool
+		strokeColor Color
+		fillColor   Color
+		rect        Rectangle
+	}{
+		{layout.PathKindLine, true, false, Color{R: 1}, Color{}, Rectangle{X: 50, Y: 700, Width: 250, Height: 0}},
+		{layout.PathKindRect, false, true, Color{R: 1}, Color{B: 1}, Rectangle{X: 100, Y: 500, Width: 200, Height: 50}},
+	}
+	if len(pl.Paths) != len(want) {
+		t.Fatalf("Paths = %+v, want %d paths", pl.Paths, len(want))
+	}
+	for i, w := range want {
+		p := pl.Paths[i]
+		if p.Kind != w.kind || p.Stroke != w.stroke || p.Fill != w.fill {
+			t.Errorf("Paths[%d] = {Kind:%s Stroke:%v Fill:%v}, want {Kind:%s Stroke:%v Fill:%v}",
+				i, p.Kind, p.Stroke, p.Fill, w.kind, w.stroke, w.fill)
+		}
+		if p.StrokeColor != layout.Color(w.strokeColor) || p.FillColor != layout.Color(w.fillColor) {
+			t.Errorf("Paths[%d] colors = %v/%v, want %v/%v", i, p.StrokeColor, p.FillColor, w.strokeColor, w.fillColor)
+		}
+		if math.Abs(p.Rect.X-w.rect.X) > 0.01 || math.Abs(p.Rect.Y-w.rect.Y) > 0.01 ||
+			math.Abs(p.Rect.Width-w.rect.Width) > 0.01 || math.Abs(p.Rect.Height-w.rect.Height) > 0.01 {
+			t.Errorf("Paths[%d].Rect = %+v, want %+v", i, p.Rect, w.rect)
+		}
+		if p.Type() != ContentBlockTypePath {
+			t.Errorf("Paths[%d].Type() = %s, want %s", i, p.Type(), ContentBlockTypePath)
+		}
+	}
+	if pl.Paths[0].LineWidth != 2 {
+		t.Errorf("Paths[0].LineWidth = %v, want 2", pl.Paths[0].LineWidth)
+	}
+
+	// パスはContentBlocksにVectorBlockとしてまとめて含める（離れた線と矩形は別のブロック）
+	var vectors []VectorBlock
+	for _, block := range pl.ContentBlocks() {
+		switch block.Type() {
+		case ContentBlockTypePath:
+			t.Errorf("ContentBlocks() contains a path block: %+v", block)
+		case ContentBlockTypeVector:
+			vectors = append(vectors, block.(VectorBlock))
+		}
+	}
+	if len(vectors) != 2 || len(vectors[0].Paths) != 1 || vectors[0].Rect != pl.Paths[0].Rect {
+		t.Errorf("ContentBlocks() vector blocks = %+v, want one block per path", vectors)
+	}
+}
+
+// TestExtractPageLayout_Annotations は表示される注釈がAnnotationsとして抽出されることをテストする
+func TestExtractPageLayout_Annotations(t *testing.T) {
+	for _, rotate := range []int{0, 90} {
+		t.Run(fmt.Sprintf("rotate=%d", rotate), func(t *testing.T) {
+			pdf := buildRawPDF([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 400 300] /Rotate %d /Annots [4 0 R 5 0 R 6 0 R 7 0 R 8 0 R] >>", rotate),
+				"<< /Type /Annot /Subtype /Stamp /Rect [300 200 380 240] /Name /Approved /Contents (Approved) >>",
+				"<< /Type /Annot /Subtype /Link /Rect [10 10 50 20] /A << /S /URI /URI (https://example.com) >> >>",
+				"<< /Type /Annot /Subtype /Popup /Rect [100 100 200 150] /Parent 4 0 R >>",
+				"<< /Type /Annot /Subtype /Square /Rect [20 20 60 60] /F 2 >>",
+				"<< /Type /Annot /Subtype /Widget /FT /Tx /T (name) /Rect [20 100 120 120] >>",
+			})
+			reader, err := OpenReader(bytes.NewReader(pdf))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+			pl, err := reader.ExtractPageLayout(0)
+			if err != nil {
+				t.Fatalf("ExtractPageLayout failed: %v", err)
+			}
+
+			// リンク・ポップアップ・非表示の注釈は除く
+			want := []AnnotationBlock{
+				{Subtype: "Stamp", Rect: Rectangle{X: 300, Y: 200, Width: 80, Height: 40}, Contents: "Approved"},
+				{Subtype: "Widget", Rect: Rectangle{X: 20, Y: 100, Width: 100, Height: 20}},
+			}
+			if rotate == 90 {
+				want[0].Rect = Rectangle{X: 200, Y: 20, Width: 40, Height: 80}
+				want[1].Rect = Rectangle{X: 100, Y: 280, Width: 20, Height: 100}
+			}
+			if !reflect.DeepEqual(pl.Annotations, want) {
+				t.Errorf("Annotations = %+v, want %+v", pl.Annotations, want)
+			}
+
+			var found int
+			for _, block := range pl.SortedContentBlocks() {
+				if block.Type() == ContentBlockTypeAnnotation {
+					found++
+				}
+			}
+			if found != len(want) {
+				t.Errorf("SortedContentBlocks() has %d annotation blocks, want %d", found, len(want))
+			}
+		})
+	}
+}
+
+// TestTransformPathBlocks はパスの点の回転とバウンディングボックスの再計算をテストする
+func TestTransformPathBlocks(t *testing.T) {
+	// 612x792のページの(100, 200)から(150, 200)への線
+	tests := []struct {
+		rotation int
+		want     Rectangle
+	}{
+		{0, Rectangle{X: 100, Y: 200, Width: 50, Height: 0}},
+		{90, Rectangle{X: 200, Y: 462, Width: 0, Height: 50}},
+		{180, Rectangle{X: 462, Y: 592, Width: 50, Height: 0}},
+		{270, Rectangle{X: 592, Y: 100, Width: 0, Height: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d", tt.rotation), func(t *testing.T) {
+			paths := []layout.PathBlock{{
+				Segments: []layout.PathSegment{
+					{Op: layout.PathOpMoveTo, Points: []layout.Point{{X: 100, Y: 200}}},
+					{Op: layout.PathOpLineTo, Points: []layout.Point{{X: 150, Y: 200}}},
+				},
+			}}
+			transformPathBlocks(paths, func(x, y float64) (float64, float64) {
+				return rotatePoint(x, y, tt.rotation, 612, 792)
+			})
+			if paths[0].Rect != tt.want {
+				t.Errorf("Rect = %+v, want %+v", paths[0].Rect, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractPageLayout_TextStyle はテキストの色・レンダリングモード・水平スケーリングがTextBlockに伝わることをテストする
+func TestExtractPageLayout_TextStyle(t *testing.T) {
+	contents := "1 0 0 rg BT /F1 12 Tf 100 700 Td (Red) Tj ET " +
+		"0 g BT /F1 12 Tf 3 Tr 50 Tz 100 300 Td (Hidden) Tj ET"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+
+	tests := []struct {
+		text              string
+		color             layout.Color
+		renderMode        TextRenderMode
+		visible           bool
+		horizontalScaling float64
+	}{
+		{"Red", layout.Color{R: 1}, TextRenderNormal, true, 100},
+		{"Hidden", layout.Color{}, TextRenderInvisible, false, 50},
+	}
+	if len(pl.TextBlocks) != len(tests) {
+		t.Fatalf("TextBlocks = %+v, want %d blocks", pl.TextBlocks, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			block := pl.TextBlocks[i]
+			if block.Text != tt.text {
+				t.Fatalf("Text = %q, want %q", block.Text, tt.text)
+			}
+			if block.Color != tt.color || block.Elements[0].Color != tt.color {
+				t.Errorf("Color = %v (element %v), want %v", block.Color, block.Elements[0].Color, tt.color)
+			}
+			if block.RenderMode != tt.renderMode || block.RenderMode.Visible() != tt.visible {
+				t.Errorf("RenderMode = %d (visible %v), want %d", block.RenderMode, block.RenderMode.Visible(), tt.renderMode)
+			}
+			if block.HorizontalScaling != tt.horizontalScaling {
+				t.Errorf("HorizontalScaling = %v, want %v", block.HorizontalScaling, tt.horizontalScaling)
+			}
+			// Helveticaは/Widthsがないので1文字600/1000 em
+			wantWidth := float64(len(tt.text)) * 0.6 * 12 * tt.horizontalScaling / 100
+			if math.Abs(block.Elements[0].Width-wantWidth) > 1e-9 {
+				t.Errorf("Width = %v, want %v", block.Elements[0].Width, wantWidth)
+			}
+		})
+	}
+}
+
+// TestExtractPageLayout_PageBoxes はページの境界ボックスの既定値と回転をテストする
+func TestExtractPageLayout_PageBoxes(t *testing.T) {
+	tests := []struct {
+		rotate int
+		want   PageBoxes
+	}{
+		{
+			rotate: 0,
+			want: PageBoxes{
+				MediaBox: Rectangle{X: 0, Y: 0, Width: 612, Height: 792},
+				CropBox:  Rectangle{X: -10, Y: 20, Width: 610, Height: 760},
+				BleedBox: Rectangle{X: -10, Y: 20, Width: 610, Height: 760},
+				TrimBox:  Rectangle{X: 10, Y: 10, Width: 592, Height: 772},
+				ArtBox:   Rectangle{X: -10, Y: 20, Width: 610, Height: 760},
+				Visible:  Rectangle{X: 0, Y: 20, Width: 600, Height: 760},
+			},
+		},
+		{
+			rotate: 90,
+			want: PageBoxes{
+				MediaBox: Rectangle{X: 0, Y: 0, Width: 792, Height: 612},
+				CropBox:  Rectangle{X: 20, Y: 12, Width: 760, Height: 610},
+				BleedBox: Rectangle{X: 20, Y: 12, Width: 760, Height: 610},
+				TrimBox:  Rectangle{X: 10, Y: 10, Width: 772, Height: 592},
+				ArtBox:   Rectangle{X: 20, Y: 12, Width: 760, Height: 610},
+				Visible:  Rectangle{X: 20, Y: 12, Width: 760, Height: 600},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Rotate %d", tt.rotate), func(t *testing.T) {
+			pdf := buildRawPDF([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				// /MediaBoxは親のPagesノードから継承する
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 612 792] >>",
+				fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Rotate %d /CropBox [-10 20 600 780] /TrimBox [602 782 10 10] >>", tt.rotate),
+			})
+			reader, err := OpenReader(bytes.NewReader(pdf))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+
+			pl, err := reader.ExtractPageLayout(0)
+			if err != nil {
+				t.Fatalf("ExtractPageLayout failed: %v", err)
+			}
+			if pl.Boxes != tt.want {
+				t.Errorf("Boxes = %+v, want %+v", pl.Boxes, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractPageLayout_TJSpacing はTJの位置調整とフォントの文字幅から単語の区切りを求めることをテストする
+func TestExtractPageLayout_TJSpacing(t *testing.T) {
+	widths := strings.TrimSpace(strings.Repeat("500 ", 'z'-'A'+1))
+	contents := "BT /F1 10 Tf 100 700 Td [(Hel) -20 (lo) -400 (World)] TJ ( again) Tj ET"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		fmt.Sprintf("<< /Type /Font /Subtype /TrueType /BaseFont /Test /FirstChar %d /Widths [%s] /FontDescriptor << /MissingWidth 250 >> >>", 'A', widths),
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	elements, err := reader.ExtractPageTextElements(0)
+	if err != nil {
+		t.Fatalf("ExtractPageTextElements failed: %v", err)
+	}
+	// 文字幅は5pt、空白は/MissingWidthで2.5pt。-20は同じ要素、-400（4pt）で要素を分ける
+	want := []struct {
+		text     string
+		x, width float64
+	}{
+		{"Hello", 100, 25.2},
+		{"World", 129.2, 25},
+		{" again", 154.2, 27.5},
+	}
+	if len(elements) != len(want) {
+		t.Fatalf("elements = %+v, want %d elements", elements, len(want))
+	}
+	for i, w := range want {
+		if elements[i].Text != w.text || math.Abs(elements[i].X-w.x) > 1e-9 || math.Abs(elements[i].Width-w.width) > 1e-9 {
+			t.Errorf("elements[%d] = {%q X:%v Width:%v}, want {%q X:%v Width:%v}",
+				i, elements[i].Text, elements[i].X, elements[i].Width, w.text, w.x, w.width)
+		}
+	}
+
+	blocks, err := reader.ExtractPageTextBlocks(0)
+	if err != nil {
+		t.Fatalf("ExtractPageTextBlocks failed: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].Text != "Hello World again" {
+		t.Errorf("TextBlocks = %+v, want one block %q", blocks, "Hello World again")
+	}
+}
@@ -0,0 +1,100 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// Link はページ上のリンク（Link注釈）
+// リンク先はURIか、ドキュメント内のページ（PageとTop）のどちらか一方を指定する
+type Link struct {
+	X, Y, Width, Height float64 // リンク領域（左下の座標と大きさ）
+	URI                 string  // リンク先のURI（URIアクション）
+	Page                *Page   // ドキュメント内の移動先のページ
+	Top                 float64 // 移動先のページで表示する上端のY座標（Pageを指定した場合）
+	Description         string  // リンクの説明（/Contents、支援技術が読み上げる。省略時はURI）
+}
+
+// AddLink はページにリンクを追加する
+// リンク領域をクリックするとURIを開くか、移動先のページを表示する。領域に枠線は描かない（/Border [0 0 0]）
+// タグ付けしたドキュメントで構造要素を開いている場合、注釈は最も内側の要素の子（OBJR）になる
+// 設計書: docs/tagged_pdf_design.md
+func (p *Page) AddLink(link Link) error {
+	if (link.URI == "") == (link.Page == nil) {
+		return fmt.Errorf("link needs either a URI or a destination page")
+	}
+	if link.Width <= 0 || link.Height <= 0 {
+		return fmt.Errorf("invalid link size: %gx%g", link.Width, link.Height)
+	}
+
+	annot := &linkAnnotation{link: link}
+	if p.doc != nil && p.doc.structure != nil && len(p.doc.structure.stack) > 1 {
+		tree := p.doc.structure
+		elem := tree.current()
+		// 要素の内容の順序を保つため、開いているマーク付きコンテンツを閉じてから加える
+		tree.closeContent()
+		elem.kids = append(elem.kids, structKid{page: p, annot: annot})
+		tree.openContent(p, elem)
+		annot.tagged = true
+	}
+	p.annotations = append(p.annotations, annot)
+	return nil
+}
+
+// linkAnnotation はURIアクションまたはドキュメント内の移動先（/Dest）を持つLink注釈
+type linkAnnotation struct {
+	link   Link
+	tagged bool            // 構造ツリーから参照されているか
+	dest   *core.Reference // 移動先のページ（出力時にresolveLinkDestinationsが設定する）
+}
+
+func (a *linkAnnotation) annotationDict(pageRef *core.Reference) core.Dictionary {
+	l := a.link
+	dict := core.Dictionary{
+		core.Name("Type"):    core.Name("Annot"),
+		core.Name("Subtype"): core.Name("Link"),
+		core.Name("Rect"):    rectArray(l.X, l.Y, l.Width, l.Height),
+		core.Name("F"):       core.Integer(4), // Print
+		core.Name("P"):       pageRef,
+		core.Name("Border"):  core.Array{core.Integer(0), core.Integer(0), core.Integer(0)},
+	}
+	description := l.Description
+	if l.Page != nil {
+		dict[core.Name("Dest")] = pageDestination(a.dest, l.Top)
+	} else {
+		dict[core.Name("A")] = core.Dictionary{
+			core.Name("S"):   core.Name("URI"),
+			core.Name("URI"): core.String(l.URI),
+		}
+		if description == "" {
+			description = l.URI
+		}
+	}
+	if description != "" {
+		dict[core.Name("Contents")] = textString(description)
+	}
+	return dict
+}
+
+// resolveLinkDestinations はドキュメント内リンクの移動先のページを、出力するページの参照に対応付ける
+func (d *Document) resolveLinkDestinations(pageRefs []*core.Reference) error {
+	pageIndex := make(map[*Page]int, len(d.pages))
+	for i, page := range d.pages {
+		pageIndex[page] = i
+	}
+	for _, page := range d.pages {
+		for _, annot := range page.annotations {
+			link, ok := annot.(*linkAnnotation)
+			if !ok || link.link.Page == nil {
+				continue
+			}
+			index, ok := pageIndex[link.link.Page]
+			if !ok {
+				return fmt.Errorf("link refers to a page outside the document")
+			}
+			link.dest = pageRefs[index]
+		}
+	}
+	return nil
+}
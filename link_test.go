@@ -0,0 +1,207 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+func TestPageAddLink(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetFont(FontHelvetica, 12)
+	page.DrawText("Visit example", 100, 700)
+	if err := page.AddLink(Link{X: 100, Y: 697, Width: 80, Height: 14, URI: "https://example.com/"}); err != nil {
+		t.Fatalf("AddLink() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	links, err := r.ExtractPageHyperlinks(0)
+	if err != nil {
+		t.Fatalf("ExtractPageHyperlinks() failed: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("got %d links, want 1", len(links))
+	}
+	link := links[0]
+	if link.URI != "https://example.com/" {
+		t.Errorf("URI = %q, want https://example.com/", link.URI)
+	}
+	if math.Abs(link.Rect.X-100) > 0.01 || math.Abs(link.Rect.Width-80) > 0.01 {
+		t.Errorf("Rect = %+v, want x = 100, width = 80", link.Rect)
+	}
+	if !strings.Contains(link.Text, "Visit") {
+		t.Errorf("Text = %q, want the text under the link", link.Text)
+	}
+}
+
+func TestPageAddLink_Page(t *testing.T) {
+	doc := New()
+	first := doc.AddPage(PageSizeA4, Portrait)
+	second := doc.AddPage(PageSizeA4, Portrait)
+	if err := first.AddLink(Link{X: 100, Y: 700, Width: 80, Height: 14, Page: second, Top: 500}); err != nil {
+		t.Fatalf("AddLink() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	annots, err := r.ExtractPageAnnotations(0)
+	if err != nil {
+		t.Fatalf("ExtractPageAnnotations() failed: %v", err)
+	}
+	if len(annots) != 1 {
+		t.Fatalf("got %d annotations, want 1", len(annots))
+	}
+	dest := annots[0].Destination
+	if dest == nil {
+		t.Fatal("link has no destination")
+	}
+	// 移動先は2ページ目の上端500の位置（拡大率は変えない）
+	if dest.PageNum != 1 || dest.Fit != "XYZ" || dest.Top != 500 || dest.Zoom != 0 {
+		t.Errorf("Destination = %+v, want page 1, XYZ, top 500", dest)
+	}
+	if annots[0].URI != "" {
+		t.Errorf("URI = %q, want none", annots[0].URI)
+	}
+}
+
+func TestPageAddLink_PageOutsideDocument(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	other := New().AddPage(PageSizeA4, Portrait)
+	if err := page.AddLink(Link{X: 0, Y: 0, Width: 10, Height: 10, Page: other}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.WriteTo(&bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a page of another document")
+	}
+}
+
+func TestPageAddLink_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		link Link
+	}{
+		{name: "no URI", link: Link{X: 0, Y: 0, Width: 10, Height: 10}},
+		{name: "URI and page", link: Link{X: 0, Y: 0, Width: 10, Height: 10, URI: "https://example.com/", Page: &Page{}}},
+		{name: "no width", link: Link{X: 0, Y: 0, Width: 0, Height: 10, URI: "https://example.com/"}},
+		{name: "negative height", link: Link{X: 0, Y: 0, Width: 10, Height: -1, URI: "https://example.com/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := New().AddPage(PageSizeA4, Portrait)
+			if err := page.AddLink(tt.link); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPageAddLink_Tagged(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetFont(FontHelvetica, 12)
+	mustTag(t, page.BeginTag(StructP))
+	mustTag(t, page.DrawText("See", 100, 700))
+	mustTag(t, page.BeginTag(StructLink))
+	mustTag(t, page.DrawText("the site", 125, 700))
+	mustTag(t, page.AddLink(Link{X: 125, Y: 697, Width: 45, Height: 14, URI: "https://example.com/", Description: "Example site"}))
+	mustTag(t, page.EndTag())
+	mustTag(t, page.EndTag())
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// 注釈はLink要素の子（OBJR）になる
+	root := readStructure(t, r)
+	if got := structureString(root); got != "Document(P(Link))" {
+		t.Fatalf("structure = %s, want Document(P(Link))", got)
+	}
+	if link := root.kids[0].kids[0]; link.annots != 1 || len(link.mcids) != 1 {
+		t.Errorf("Link has %d annotations and %d MCIDs, want 1 and 1", link.annots, len(link.mcids))
+	}
+
+	// 注釈の/StructParentから親ツリーでLink要素を引ける
+	pageDict, err := r.r.GetPage(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annots := r.r.Resolve(pageDict[core.Name("Annots")]).(core.Array)
+	annot := r.r.Resolve(annots[0]).(core.Dictionary)
+	key, ok := annot[core.Name("StructParent")].(core.Integer)
+	if !ok {
+		t.Fatal("annotation has no /StructParent")
+	}
+	if got := annot[core.Name("Contents")]; got != core.String("Example site") {
+		t.Errorf("/Contents = %v, want Example site", got)
+	}
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeRoot := r.r.Resolve(catalog[core.Name("StructTreeRoot")]).(core.Dictionary)
+	parentTree := r.r.Resolve(treeRoot[core.Name("ParentTree")]).(core.Dictionary)
+	nums := parentTree[core.Name("Nums")].(core.Array)
+	var parent core.Dictionary
+	for i := 0; i+1 < len(nums); i += 2 {
+		if nums[i] == key {
+			parent, _ = r.r.Resolve(nums[i+1]).(core.Dictionary)
+		}
+	}
+	if parent == nil || parent[core.Name("S")] != core.Name("Link") {
+		t.Errorf("parent tree entry %d = %v, want the Link element", key, parent)
+	}
+	if got := treeRoot[core.Name("ParentTreeNextKey")]; got != core.Integer(2) {
+		t.Errorf("/ParentTreeNextKey = %v, want 2", got)
+	}
+}
+
+func TestPageAddLink_PDFUA1(t *testing.T) {
+	doc := New()
+	if err := doc.SetConformance(PDFUA1); err != nil {
+		t.Fatal(err)
+	}
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.AddLink(Link{X: 0, Y: 0, Width: 10, Height: 10, URI: "https://example.com/"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// タグの外のリンクは支援技術から辿れない
+	err := doc.WriteTo(&bytes.Buffer{})
+	var confErr *ConformanceError
+	if !errors.As(err, &confErr) {
+		t.Fatalf("WriteTo() error = %v, want *ConformanceError", err)
+	}
+	if !strings.Contains(err.Error(), `link to "https://example.com/" is not tagged`) {
+		t.Errorf("error = %q, want the untagged link", err.Error())
+	}
+}
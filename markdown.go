@@ -3,6 +3,7 @@ package gopdf
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/ryomak/gopdf/internal/markdown"
 )
@@ -34,6 +35,37 @@ type MarkdownOptions struct {
 
 	// ImageBasePath: Base path for resolving relative image paths
 	ImageBasePath string
+
+	// ImageAlign: Horizontal alignment of images and their captions (default: AlignLeft)
+	ImageAlign TextAlign
+
+	// AllowRemoteImages: Download http(s) images (default: false, the alt text is drawn instead)
+	AllowRemoteImages bool
+
+	// Language: Document language as a BCP 47 tag (e.g. "en-US", "ja-JP"), written as /Lang
+	Language string
+
+	// Title: Document title (default: text of the first level-1 heading)
+	Title string
+
+	// GenerateTOC: Insert a table of contents page linking to the H1-H3 headings,
+	// and build the document outline (bookmarks) from them
+	GenerateTOC bool
+
+	// TOCTitle: Title of the table of contents (default: "Contents")
+	TOCTitle string
+
+	// Header, Footer: Running header and footer drawn in the top and bottom margins of every page
+	// (default: none). See MarkdownHeaderFooter for the placeholders.
+	Header, Footer MarkdownHeaderFooter
+}
+
+// MarkdownHeaderFooter is a running header or footer: text templates drawn at the left,
+// center and right of each page. In the templates, {title} is replaced with the document title,
+// {section} with the last H1 or H2 heading on or before the page, {page} with the page number
+// and {pages} with the page count (e.g. "Page {page} of {pages}").
+type MarkdownHeaderFooter struct {
+	Left, Center, Right string
 }
 
 // MarkdownStyle represents styling configuration for Markdown rendering.
@@ -62,8 +94,14 @@ type MarkdownStyle struct {
 	CodeBackground Color
 	LinkColor      Color
 
-	// Font path for TTF fonts (optional)
+	// Font path for TTF fonts (optional, loaded as BodyFont when BodyFont is nil)
 	FontPath string
+
+	// TTF fonts per kind of text (optional). Set them for Japanese, Chinese or Korean text,
+	// which the standard fonts cannot draw. Text without a TTF font uses the standard fonts.
+	BodyFont    *TTFFont // paragraphs, lists, tables and captions
+	HeadingFont *TTFFont // headings and table headers (default: BodyFont)
+	CodeFont    *TTFFont // code blocks (default: BodyFont)
 }
 
 // NewMarkdownDocument creates a PDF document from Markdown text.
@@ -102,18 +140,39 @@ func NewMarkdownDocument(markdownText string, opts *MarkdownOptions) (*Document,
 		}
 	}
 
+	// Resolve the TTF fonts of the style
+	fonts, err := newMarkdownFonts(opts.Style)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse Markdown
 	parser := markdown.NewParser()
 	ast := parser.ParseString(markdownText)
 
 	// Render based on mode
 	var doc *Document
-	var err error
 
 	switch opts.Mode {
 	case MarkdownModeDocument:
 		renderer := newDocumentRenderer(opts.PageSize, opts.Orientation, style, opts.ImageBasePath)
+		renderer.imageAlign = opts.ImageAlign
+		renderer.allowRemoteImages = opts.AllowRemoteImages
+		renderer.generateTOC = opts.GenerateTOC
+		renderer.tocTitle = opts.TOCTitle
+		renderer.fonts = fonts
+		renderer.header = opts.Header
+		renderer.footer = opts.Footer
+		renderer.title = opts.Title
 		doc, err = renderer.render(ast)
+		if err == nil {
+			// Headings and paragraphs are tagged; add the language and title
+			// that assistive technology needs to present the document.
+			doc.SetLanguage(opts.Language)
+			if renderer.title != "" {
+				doc.SetMetadata(Metadata{Title: renderer.title})
+			}
+		}
 	case MarkdownModeSlide:
 		// TODO: Implement slide renderer
 		return nil, fmt.Errorf("slide mode not yet implemented")
@@ -129,16 +188,19 @@ func NewMarkdownDocument(markdownText string, opts *MarkdownOptions) (*Document,
 }
 
 // NewMarkdownDocumentFromFile creates a PDF document from a Markdown file.
-func NewMarkdownDocumentFromFile(filepath string, opts *MarkdownOptions) (*Document, error) {
+func NewMarkdownDocumentFromFile(path string, opts *MarkdownOptions) (*Document, error) {
 	// Read the Markdown file
-	data, err := os.ReadFile(filepath)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read markdown file: %w", err)
 	}
 
 	// Set default image base path to the directory of the markdown file
-	if opts != nil && opts.ImageBasePath == "" {
-		opts.ImageBasePath = filepath[:len(filepath)-len(filepath[len(filepath)-1:])]
+	if opts == nil {
+		opts = &MarkdownOptions{Mode: MarkdownModeDocument}
+	}
+	if opts.ImageBasePath == "" {
+		opts.ImageBasePath = filepath.Dir(path)
 	}
 
 	return NewMarkdownDocument(string(data), opts)
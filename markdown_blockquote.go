@@ -0,0 +1,105 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// Blockquote and horizontal rule layout settings (points).
+const (
+	markdownQuoteIndent    = 16.0 // indentation of each quote level, including the rule
+	markdownQuoteRuleWidth = 3.0  // width of the rule on the left of a quote
+	markdownRuleWidth      = 1.0  // width of a horizontal rule
+)
+
+// markdownRuleColor is the color of blockquote rules and horizontal rules.
+var markdownRuleColor = Color{R: 0.75, G: 0.75, B: 0.75}
+
+// markdownQuote is a blockquote whose left rule is still being drawn.
+type markdownQuote struct {
+	x   float64 // center of the rule
+	top float64 // top of the rule on the current page
+}
+
+// renderBlockQuote renders a blockquote: its blocks are indented by markdownQuoteIndent
+// with a rule on the left, and nested quotes are indented further with their own rule.
+// A quote that continues on the next page gets a rule on each page.
+func (r *documentRenderer) renderBlockQuote(node *ast.BlockQuote) error {
+	// Keep the start of the quote on the page of its first lines, as paragraphs do
+	r.checkPageBreak(r.style.BodySize * r.style.LineSpacing * 3)
+	if err := r.currentPage.BeginTag(StructBlockQuote); err != nil {
+		return err
+	}
+
+	r.quotes = append(r.quotes, markdownQuote{
+		x:   r.contentLeft() + markdownQuoteRuleWidth/2,
+		top: r.currentY + r.style.BodySize,
+	})
+	r.indent += markdownQuoteIndent
+	defer func() { r.indent -= markdownQuoteIndent }()
+
+	for _, child := range node.GetChildren() {
+		if err := r.walkNode(child); err != nil {
+			return err
+		}
+	}
+
+	// The last block has already moved the position below its paragraph spacing
+	quote := r.quotes[len(r.quotes)-1]
+	r.quotes = r.quotes[:len(r.quotes)-1]
+	r.drawQuoteRule(quote, r.currentY+r.style.ParagraphSpacing+r.style.BodySize)
+
+	return r.currentPage.EndTag()
+}
+
+// drawQuoteRules draws the rules of the open quotes down to the bottom margin before the page ends.
+func (r *documentRenderer) drawQuoteRules() {
+	for _, quote := range r.quotes {
+		r.drawQuoteRule(quote, r.style.MarginBottom)
+	}
+}
+
+// drawQuoteRule draws the left rule of a quote from its top down to bottom as an artifact.
+func (r *documentRenderer) drawQuoteRule(quote markdownQuote, bottom float64) {
+	if bottom >= quote.top {
+		return
+	}
+	page := r.currentPage
+	// Drawing the line cannot fail
+	_ = page.artifact(func() error {
+		fmt.Fprintf(&page.content, "q\n")
+		page.SetLineWidth(markdownQuoteRuleWidth)
+		page.SetStrokeColor(markdownRuleColor)
+		page.DrawLine(quote.x, quote.top, quote.x, bottom)
+		fmt.Fprintf(&page.content, "Q\n")
+		return nil
+	})
+}
+
+// renderHorizontalRule draws a thematic break as a line across the content width,
+// centered in a gap of twice the paragraph spacing. The line is an artifact.
+func (r *documentRenderer) renderHorizontalRule() error {
+	y := r.currentY + r.style.BodySize - r.style.ParagraphSpacing/2
+	if y < r.style.MarginBottom {
+		r.newPage()
+		y = r.currentY + r.style.BodySize - r.style.ParagraphSpacing/2
+	}
+
+	page := r.currentPage
+	left := r.contentLeft()
+	err := page.artifact(func() error {
+		fmt.Fprintf(&page.content, "q\n")
+		page.SetLineWidth(markdownRuleWidth)
+		page.SetStrokeColor(markdownRuleColor)
+		page.DrawLine(left, y, left+r.contentWidth(), y)
+		fmt.Fprintf(&page.content, "Q\n")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.currentY -= 2 * r.style.ParagraphSpacing
+	return nil
+}
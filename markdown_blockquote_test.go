@@ -0,0 +1,107 @@
+package gopdf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNewMarkdownDocument_BlockQuote(t *testing.T) {
+	doc, err := NewMarkdownDocument("Before.\n\n> Quoted.\n>\n> > Nested.\n\nAfter.\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	content := doc.pages[0].content.String()
+
+	// 1段ごとに16ポイント字下げする
+	for _, tt := range []struct {
+		text string
+		x    float64
+	}{
+		{"Before.", 72},
+		{"Quoted.", 72 + markdownQuoteIndent},
+		{"Nested.", 72 + 2*markdownQuoteIndent},
+		{"After.", 72},
+	} {
+		m := regexp.MustCompile(`([\d.]+) [\d.]+ Td\n\(` + regexp.QuoteMeta(tt.text) + `\) Tj`).FindStringSubmatch(content)
+		if m == nil || m[1] != fmt.Sprintf("%.2f", tt.x) {
+			t.Errorf("%q is drawn at %v, want x = %.2f", tt.text, m, tt.x)
+		}
+	}
+
+	// 引用の段ごとに左の線を描く
+	for _, x := range []float64{72, 72 + markdownQuoteIndent} {
+		rule := fmt.Sprintf("%.2f ", x+markdownQuoteRuleWidth/2)
+		if !regexp.MustCompile(`\n` + regexp.QuoteMeta(rule) + `[\d.]+ m\n` + regexp.QuoteMeta(rule)).MatchString(content) {
+			t.Errorf("content does not draw the rule at x = %s", rule)
+		}
+	}
+
+	r := renderMarkdownForTest(t, "Before.\n\n> Quoted.\n>\n> > Nested.\n\nAfter.\n")
+	if got, want := structureString(readStructure(t, r)), "Document(P BlockQuote(P BlockQuote(P)) P)"; got != want {
+		t.Errorf("structure = %s, want %s", got, want)
+	}
+}
+
+func TestNewMarkdownDocument_BlockQuotePageBreak(t *testing.T) {
+	md := strings.Repeat("> Quoted paragraph.\n>\n", 80)
+	doc, err := NewMarkdownDocument(md, nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	if len(doc.pages) < 2 {
+		t.Fatalf("got %d pages, want the quote to continue on the next page", len(doc.pages))
+	}
+	// 引用が続くページごとに線を描く
+	rule := fmt.Sprintf("%.2f %.2f m\n", 72+markdownQuoteRuleWidth/2, doc.pages[1].Height()-72+12)
+	for i, page := range doc.pages {
+		content := page.content.String()
+		if !strings.Contains(content, fmt.Sprintf("%.2f ", 72+markdownQuoteRuleWidth/2)) {
+			t.Errorf("page %d has no rule", i)
+		}
+		if i > 0 && !strings.Contains(content, rule) {
+			t.Errorf("page %d does not start the rule at the top", i)
+		}
+		if i < len(doc.pages)-1 && !strings.Contains(content, fmt.Sprintf("%.2f l\n", 72.0)) {
+			t.Errorf("page %d does not draw the rule down to the bottom margin", i)
+		}
+	}
+}
+
+func TestNewMarkdownDocument_HorizontalRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+	}{
+		{name: "dashes", markdown: "Before.\n\n---\n\nAfter.\n"},
+		{name: "asterisks", markdown: "Before.\n\n***\n\nAfter.\n"},
+		{name: "underscores", markdown: "Before.\n\n___\n\nAfter.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewMarkdownDocument(tt.markdown, nil)
+			if err != nil {
+				t.Fatalf("NewMarkdownDocument() failed: %v", err)
+			}
+			content := doc.pages[0].content.String()
+			// 本文の幅いっぱいに線を引く
+			line := regexp.MustCompile(`72\.00 ([\d.]+) m\n([\d.]+) ([\d.]+) l\n`).FindStringSubmatch(content)
+			if line == nil {
+				t.Fatalf("content does not draw a rule:\n%s", content)
+			}
+			if want := fmt.Sprintf("%.2f", PageSizeA4.Width-72); line[2] != want || line[1] != line[3] {
+				t.Errorf("rule = %v, want a horizontal line to x = %s", line, want)
+			}
+			if strings.Contains(content, "(---) Tj") || strings.Contains(content, "(***) Tj") {
+				t.Error("rule is drawn as text")
+			}
+
+			r := renderMarkdownForTest(t, tt.markdown)
+			if got, want := structureString(readStructure(t, r)), "Document(P P)"; got != want {
+				t.Errorf("structure = %s, want %s", got, want)
+			}
+		})
+	}
+}
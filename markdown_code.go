@@ -0,0 +1,77 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// Code block layout.
+const (
+	markdownCodePadding = 6 // space between the background and the code
+	markdownCodeTabSize = 4 // spaces per tab
+)
+
+// renderCodeBlock draws a fenced or indented code block in the code font on a CodeBackground background.
+// Lines are not wrapped; a block that does not fit continues on the next page with its own background.
+// The code is tagged as Code; the background is an artifact.
+func (r *documentRenderer) renderCodeBlock(block *ast.CodeBlock) error {
+	code := strings.ReplaceAll(strings.TrimRight(string(block.Literal), "\n"), "\t", strings.Repeat(" ", markdownCodeTabSize))
+	lines := strings.Split(code, "\n")
+	font := r.codeFont()
+	size := r.style.CodeSize
+	lineHeight := size * r.style.LineSpacing
+
+	if err := r.currentPage.BeginTag(StructCode); err != nil {
+		return err
+	}
+	top := r.currentY + r.style.BodySize
+	for len(lines) > 0 {
+		// Lines that fit above the bottom margin; at least one on an empty page
+		n := min(int((top-r.style.MarginBottom-2*markdownCodePadding)/lineHeight), len(lines))
+		if n < 1 {
+			if !r.atPageTop() {
+				r.newPage()
+				top = r.currentY + r.style.BodySize
+				continue
+			}
+			n = 1
+		}
+
+		page := r.currentPage
+		height := float64(n)*lineHeight + 2*markdownCodePadding
+		width := r.contentWidth()
+		err := page.artifact(func() error {
+			fmt.Fprintf(&page.content, "q\n")
+			page.SetFillColor(convertColor(r.style.CodeBackground))
+			page.FillRectangle(r.contentLeft(), top-height, width, height)
+			fmt.Fprintf(&page.content, "Q\n")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := font.set(page, size); err != nil {
+			return fmt.Errorf("failed to set font: %w", err)
+		}
+		page.SetFillColor(convertColor(r.style.TextColor))
+		for i, line := range lines[:n] {
+			if line == "" {
+				continue
+			}
+			baseline := top - markdownCodePadding - size - float64(i)*lineHeight
+			if err := page.DrawText(line, r.contentLeft()+markdownCodePadding, baseline); err != nil {
+				return fmt.Errorf("failed to draw code: %w", err)
+			}
+		}
+
+		lines = lines[n:]
+		r.currentY = top - height - r.style.ParagraphSpacing - r.style.BodySize
+		if len(lines) > 0 {
+			r.newPage()
+			top = r.currentY + r.style.BodySize
+		}
+	}
+	return r.currentPage.EndTag()
+}
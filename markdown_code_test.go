@@ -0,0 +1,69 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNewMarkdownDocument_CodeBlock(t *testing.T) {
+	doc, err := NewMarkdownDocument("Run:\n\n```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\nDone.\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	content := doc.pages[0].content.String()
+
+	// コードは等幅フォントで行ごとに描き、タブは空白にする
+	courier := false
+	for _, font := range doc.pages[0].fonts {
+		courier = courier || string(font) == string(FontCourier)
+	}
+	if !courier {
+		t.Error("code is not drawn in Courier")
+	}
+	for _, line := range []string{"(func main\\(\\) {) Tj", "(    fmt.Println\\(\"hi\"\\)) Tj", "(}) Tj"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("content does not contain %q:\n%s", line, content)
+		}
+	}
+	// 背景はCodeBackgroundの矩形
+	if !strings.Contains(content, "0.95 0.95 0.95 rg\n") || !strings.Contains(content, " re\nf\n") {
+		t.Errorf("code block has no background:\n%s", content)
+	}
+}
+
+func TestNewMarkdownDocument_CodeBlockStructure(t *testing.T) {
+	r := renderMarkdownForTest(t, "Intro.\n\n    indented code\n\nAfter.\n")
+	if got := structureString(readStructure(t, r)); got != "Document(P Code P)" {
+		t.Errorf("structure = %s, want Document(P Code P)", got)
+	}
+	text, err := r.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText() failed: %v", err)
+	}
+	if !strings.Contains(text, "indented code") {
+		t.Errorf("text = %q, want the code", text)
+	}
+}
+
+func TestNewMarkdownDocument_CodeBlockPageBreak(t *testing.T) {
+	// 長いコードは次のページに続き、ページごとに背景を描く
+	var code strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&code, "line %d\n", i+1)
+	}
+	r := renderMarkdownForTest(t, "```\n"+code.String()+"```\n")
+	if r.PageCount() < 2 {
+		t.Fatalf("page count = %d, want at least 2", r.PageCount())
+	}
+	last, err := r.ExtractPageText(r.PageCount() - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(last, "line 100") {
+		t.Errorf("last page = %q, want the last line", last)
+	}
+	if got := structureString(readStructure(t, r)); got != "Document(Code)" {
+		t.Errorf("structure = %s, want Document(Code)", got)
+	}
+}
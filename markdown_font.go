@@ -0,0 +1,85 @@
+package gopdf
+
+import "fmt"
+
+// markdownFont is the font of a kind of text: the TTF font assigned to it in MarkdownStyle,
+// or a standard font when none is assigned.
+type markdownFont struct {
+	standard StandardFont
+	ttf      *TTFFont
+}
+
+// set makes the font the current font of page.
+func (f markdownFont) set(page *Page, size float64) error {
+	if f.ttf != nil {
+		return page.SetTTFFont(f.ttf, size)
+	}
+	return page.SetFont(f.standard, size)
+}
+
+// markdownFonts holds the TTF fonts of the kinds of text; nil means the standard fonts.
+type markdownFonts struct {
+	body    *TTFFont // paragraphs, lists, tables, captions and the table of contents
+	heading *TTFFont // headings, table headers and the title of the table of contents
+	code    *TTFFont // code blocks
+}
+
+// newMarkdownFonts returns the fonts assigned in style.
+// Without BodyFont, the body font is loaded from FontPath if it is set.
+// Headings and code use the body font unless they have their own.
+func newMarkdownFonts(style *MarkdownStyle) (markdownFonts, error) {
+	if style == nil {
+		return markdownFonts{}, nil
+	}
+	body := style.BodyFont
+	if body == nil && style.FontPath != "" {
+		font, err := LoadTTF(style.FontPath)
+		if err != nil {
+			return markdownFonts{}, fmt.Errorf("failed to load font: %w", err)
+		}
+		body = font
+	}
+
+	fonts := markdownFonts{body: body, heading: style.HeadingFont, code: style.CodeFont}
+	if fonts.heading == nil {
+		fonts.heading = body
+	}
+	if fonts.code == nil {
+		fonts.code = body
+	}
+	return fonts, nil
+}
+
+// bodyFont returns the font of body text.
+func (r *documentRenderer) bodyFont() markdownFont {
+	return markdownFont{standard: FontHelvetica, ttf: r.fonts.body}
+}
+
+// headingFont returns the font of headings and other bold text.
+func (r *documentRenderer) headingFont() markdownFont {
+	return markdownFont{standard: FontHelveticaBold, ttf: r.fonts.heading}
+}
+
+// captionFont returns the font of image captions.
+func (r *documentRenderer) captionFont() markdownFont {
+	return markdownFont{standard: FontHelveticaOblique, ttf: r.fonts.body}
+}
+
+// codeFont returns the font of code blocks.
+func (r *documentRenderer) codeFont() markdownFont {
+	return markdownFont{standard: FontCourier, ttf: r.fonts.code}
+}
+
+// inlineFont returns the font of text in format.
+// Bold text uses the heading font; TTF fonts have no italic variant, so italic text keeps its TTF font upright.
+func (r *documentRenderer) inlineFont(format markdownFormat) markdownFont {
+	switch {
+	case format.bold && format.italic:
+		return markdownFont{standard: FontHelveticaBoldOblique, ttf: r.fonts.heading}
+	case format.bold:
+		return r.headingFont()
+	case format.italic:
+		return markdownFont{standard: FontHelveticaOblique, ttf: r.fonts.body}
+	}
+	return r.bodyFont()
+}
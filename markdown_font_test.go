@@ -0,0 +1,107 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNewMarkdownFonts(t *testing.T) {
+	body, heading, code := &TTFFont{}, &TTFFont{}, &TTFFont{}
+	tests := []struct {
+		name  string
+		style *MarkdownStyle
+		want  markdownFonts
+	}{
+		{name: "no style", style: nil, want: markdownFonts{}},
+		{name: "standard fonts", style: DefaultMarkdownStyle(), want: markdownFonts{}},
+		{
+			// 見出しとコードは本文のフォントを引き継ぐ
+			name:  "body",
+			style: &MarkdownStyle{BodyFont: body},
+			want:  markdownFonts{body: body, heading: body, code: body},
+		},
+		{
+			name:  "all",
+			style: &MarkdownStyle{BodyFont: body, HeadingFont: heading, CodeFont: code},
+			want:  markdownFonts{body: body, heading: heading, code: code},
+		},
+		{
+			// 本文のフォントがなければ、見出しだけTTFフォントで描く
+			name:  "heading only",
+			style: &MarkdownStyle{HeadingFont: heading},
+			want:  markdownFonts{heading: heading},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newMarkdownFonts(tt.style)
+			if err != nil {
+				t.Fatalf("newMarkdownFonts() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("newMarkdownFonts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMarkdownDocument_FontPathError(t *testing.T) {
+	style := DefaultMarkdownStyle()
+	style.FontPath = "testdata/no-such-font.ttf"
+	_, err := NewMarkdownDocument("# Title\n", &MarkdownOptions{Mode: MarkdownModeDocument, Style: style})
+	if err == nil || !strings.Contains(err.Error(), "failed to load font") {
+		t.Errorf("error = %v, want a font loading error", err)
+	}
+}
+
+func TestNewMarkdownDocument_TTFFont(t *testing.T) {
+	jpFont, err := DefaultJapaneseFont()
+	if err != nil {
+		t.Fatalf("DefaultJapaneseFont() failed: %v", err)
+	}
+	style := DefaultMarkdownStyle()
+	style.BodyFont = jpFont
+
+	markdown := "# 利用ガイド\n\n" +
+		"日本語の段落です。\n\n" +
+		"- 項目その一\n- 項目その二\n\n" +
+		"| 名前 | 説明 |\n|---|---|\n| 東京 | 首都 |\n\n" +
+		"```\nfmt.Println(\"こんにちは\")\n```\n"
+	doc, err := NewMarkdownDocument(markdown, &MarkdownOptions{Mode: MarkdownModeDocument, Style: style})
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+
+	// 標準フォントを使わず、すべてTTFフォントで描く
+	page := doc.pages[0]
+	if len(page.fonts) != 0 {
+		t.Errorf("page uses standard fonts: %v", page.fonts)
+	}
+	if len(page.ttfFonts) != 1 {
+		t.Errorf("page uses %d TTF fonts, want 1", len(page.ttfFonts))
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	defer r.Close()
+	text, err := r.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText() failed: %v", err)
+	}
+	for _, want := range []string{"利用ガイド", "日本語の段落です。", "項目その一", "東京", "首都", "こんにちは"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text does not contain %q:\n%s", want, text)
+		}
+	}
+	if got := r.Info().Title; got != "利用ガイド" {
+		t.Errorf("Title = %q, want 利用ガイド", got)
+	}
+}
@@ -0,0 +1,85 @@
+package gopdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Header and footer layout.
+const (
+	markdownRunningSize   = 0.75 // font size of headers and footers relative to BodySize
+	markdownRunningCenter = 0.35 // distance from the baseline to the middle of the text, relative to the font size
+)
+
+// drawHeadersFooters draws the running header and footer on every page, after the pages are final.
+// The templates are expanded per page; the text is an artifact, centered vertically in the top and bottom margins.
+func (r *documentRenderer) drawHeadersFooters() error {
+	if r.header == (MarkdownHeaderFooter{}) && r.footer == (MarkdownHeaderFooter{}) {
+		return nil
+	}
+
+	sections := make(map[*Page]string)
+	for _, heading := range r.headings {
+		if heading.level <= 2 {
+			sections[heading.page] = heading.text
+		}
+	}
+
+	font := r.bodyFont()
+	size := r.style.BodySize * markdownRunningSize
+	section := ""
+	pages := r.doc.pages
+	for i, page := range pages {
+		if text, ok := sections[page]; ok {
+			section = text
+		}
+		replacer := strings.NewReplacer(
+			"{title}", r.title,
+			"{section}", section,
+			"{page}", strconv.Itoa(i+1),
+			"{pages}", strconv.Itoa(len(pages)),
+		)
+
+		err := page.artifact(func() error {
+			if err := font.set(page, size); err != nil {
+				return fmt.Errorf("failed to set font: %w", err)
+			}
+			page.SetFillColor(convertColor(r.style.TextColor))
+			top := page.Height() - r.style.MarginTop/2 - size*markdownRunningCenter
+			if err := r.drawRunningText(page, r.header, replacer, top, font, size); err != nil {
+				return err
+			}
+			bottom := r.style.MarginBottom/2 - size*markdownRunningCenter
+			return r.drawRunningText(page, r.footer, replacer, bottom, font, size)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to draw header or footer: %w", err)
+		}
+	}
+	return nil
+}
+
+// drawRunningText draws the left, center and right parts of a header or footer on a line at y.
+func (r *documentRenderer) drawRunningText(page *Page, template MarkdownHeaderFooter, replacer *strings.Replacer, y float64, font markdownFont, size float64) error {
+	left := r.style.MarginLeft
+	right := page.Width() - r.style.MarginRight
+	parts := []struct {
+		text string
+		x    func(width float64) float64
+	}{
+		{template.Left, func(float64) float64 { return left }},
+		{template.Center, func(width float64) float64 { return (left + right - width) / 2 }},
+		{template.Right, func(width float64) float64 { return right - width }},
+	}
+	for _, part := range parts {
+		text := replacer.Replace(part.text)
+		if text == "" {
+			continue
+		}
+		if err := page.DrawText(text, part.x(r.textWidth(text, font, size)), y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
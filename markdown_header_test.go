@@ -0,0 +1,139 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// renderMarkdownOptionsForTest はoptsでMarkdownをPDFに変換し、読み込んだReaderを返す
+func renderMarkdownOptionsForTest(t *testing.T, markdown string, opts *MarkdownOptions) *PDFReader {
+	t.Helper()
+	doc, err := NewMarkdownDocument(markdown, opts)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// longMarkdownForTest は見出しごとに1ページ以上の本文がある3章のMarkdownを返す
+func longMarkdownForTest() string {
+	var md strings.Builder
+	md.WriteString("# Manual\n\n")
+	for _, section := range []string{"Install", "Usage", "FAQ"} {
+		fmt.Fprintf(&md, "## %s\n\n", section)
+		for i := range 30 {
+			fmt.Fprintf(&md, "%s step %d.\n\n", section, i+1)
+		}
+	}
+	return md.String()
+}
+
+func TestNewMarkdownDocument_HeaderFooter(t *testing.T) {
+	r := renderMarkdownOptionsForTest(t, longMarkdownForTest(), &MarkdownOptions{
+		Mode:   MarkdownModeDocument,
+		Header: MarkdownHeaderFooter{Left: "{title}", Right: "{section}"},
+		Footer: MarkdownHeaderFooter{Center: "Page {page} of {pages}"},
+	})
+
+	pages := r.PageCount()
+	if pages < 3 {
+		t.Fatalf("page count = %d, want at least 3", pages)
+	}
+	section := ""
+	for i := range pages {
+		text, err := r.ExtractPageText(i)
+		if err != nil {
+			t.Fatalf("ExtractPageText() failed: %v", err)
+		}
+		// 見出しはページ内で最後のH1・H2、なければ前のページから引き継ぐ
+		for _, name := range []string{"Manual", "Install", "Usage", "FAQ"} {
+			if strings.Contains(text, name+" step 1.") || (name == "Manual" && i == 0) {
+				section = name
+			}
+		}
+		for _, want := range []string{"Manual", section, fmt.Sprintf("Page %d of %d", i+1, pages)} {
+			if !strings.Contains(text, want) {
+				t.Errorf("page %d does not contain %q:\n%s", i+1, want, text)
+			}
+		}
+	}
+
+	// ヘッダーとフッターはアーティファクトで、論理構造に含めない
+	structure := structureString(readStructure(t, r))
+	if strings.Count(structure, "H2") != 3 || !strings.HasPrefix(structure, "Document(H1 H2 P") {
+		t.Errorf("structure = %s, want only the Markdown blocks", structure)
+	}
+}
+
+func TestNewMarkdownDocument_HeaderFooterPosition(t *testing.T) {
+	doc, err := NewMarkdownDocument("# Title\n\nText.\n", &MarkdownOptions{
+		Mode:   MarkdownModeDocument,
+		Title:  "Report",
+		Header: MarkdownHeaderFooter{Right: "{title}"},
+		Footer: MarkdownHeaderFooter{Left: "{page}"},
+	})
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	content := doc.pages[0].content.String()
+
+	// ヘッダーは上の余白、フッターは下の余白の中央に描く（既定の余白72、文字の大きさ9）
+	style := DefaultMarkdownStyle()
+	size := style.BodySize * markdownRunningSize
+	width, _ := FontHelvetica.TextWidth("Report", size)
+	header := fmt.Sprintf("%.2f %.2f Td\n(Report) Tj", PageSizeA4.Width-style.MarginRight-width, PageSizeA4.Height-36-size*markdownRunningCenter)
+	footer := fmt.Sprintf("%.2f %.2f Td\n(1) Tj", style.MarginLeft, 36-size*markdownRunningCenter)
+	for _, want := range []string{header, footer} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content does not contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestNewMarkdownDocument_HeaderFooterTOC(t *testing.T) {
+	// 目次のページも数え、目次のページにはまだ見出しがない
+	r := renderMarkdownOptionsForTest(t, longMarkdownForTest(), &MarkdownOptions{
+		Mode:        MarkdownModeDocument,
+		GenerateTOC: true,
+		Footer:      MarkdownHeaderFooter{Left: "[{section}]", Right: "{page}/{pages}"},
+	})
+
+	pages := r.PageCount()
+	toc, err := r.ExtractPageText(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Contents", "[]", fmt.Sprintf("1/%d", pages)} {
+		if !strings.Contains(toc, want) {
+			t.Errorf("table of contents does not contain %q:\n%s", want, toc)
+		}
+	}
+	last, err := r.ExtractPageText(pages - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("%d/%d", pages, pages); !strings.Contains(last, want) || !strings.Contains(last, "[FAQ]") {
+		t.Errorf("last page does not contain %q and [FAQ]:\n%s", want, last)
+	}
+}
+
+func TestNewMarkdownDocument_NoHeaderFooter(t *testing.T) {
+	doc, err := NewMarkdownDocument("Text.\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	if content := doc.pages[0].content.String(); strings.Count(content, "BT\n") != 1 {
+		t.Errorf("content has text besides the paragraph:\n%s", content)
+	}
+}
@@ -0,0 +1,194 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// Image layout settings (points).
+const (
+	markdownCaptionGap       = 4.0 // space between an image and its caption
+	markdownCaptionSizeRatio = 0.9 // caption font size relative to the body size
+)
+
+// markdownMaxImageSize is the largest remote image that is downloaded.
+const markdownMaxImageSize = 32 << 20
+
+// markdownHTTPClient fetches remote images when MarkdownOptions.AllowRemoteImages is set.
+var markdownHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// renderImage renders an image as a block: scaled down to fit the content width and page height,
+// aligned by MarkdownOptions.ImageAlign, and followed by its caption (the title, or the alt text).
+// Remote images are drawn as their alt text unless MarkdownOptions.AllowRemoteImages is set.
+func (r *documentRenderer) renderImage(node *ast.Image) error {
+	alt := strings.TrimSpace(r.extractText(node))
+	src := string(node.Destination)
+	if isRemoteImage(src) && !r.allowRemoteImages {
+		return r.drawParagraph(alt, nil, nil)
+	}
+
+	img, err := r.loadImage(src)
+	if err != nil {
+		return fmt.Errorf("failed to load image %q: %w", markdownImageName(src), err)
+	}
+
+	// Images are placed at 72 DPI and only ever scaled down
+	caption := strings.TrimSpace(string(node.Title))
+	if caption == "" {
+		caption = alt
+	}
+	captionSize := r.style.BodySize * markdownCaptionSizeRatio
+	captionHeight := 0.0
+	if caption != "" {
+		captionHeight = markdownCaptionGap + captionSize*r.style.LineSpacing
+	}
+	maxWidth := r.contentWidth()
+	maxHeight := r.currentPage.Height() - r.style.MarginTop - r.style.MarginBottom - captionHeight
+	scale := min(1, maxWidth/float64(img.Width), maxHeight/float64(img.Height))
+	width, height := float64(img.Width)*scale, float64(img.Height)*scale
+
+	// The image starts where the text of a line at the current baseline would start
+	top := r.currentY + r.style.BodySize
+	if top-height-captionHeight < r.style.MarginBottom && !r.atPageTop() {
+		r.newPage()
+		top = r.currentY + r.style.BodySize
+	}
+
+	x := r.alignedX(width, maxWidth)
+	if err := r.currentPage.DrawImageWithOptions(img, x, top-height, width, height, ImageOptions{AltText: alt}); err != nil {
+		return fmt.Errorf("failed to draw image: %w", err)
+	}
+	bottom := top - height
+
+	if caption != "" {
+		page := r.currentPage
+		if err := r.captionFont().set(page, captionSize); err != nil {
+			return fmt.Errorf("failed to set font: %w", err)
+		}
+		page.SetFillColor(convertColor(r.style.TextColor))
+		captionWidth := r.textWidth(caption, r.captionFont(), captionSize)
+		baseline := bottom - markdownCaptionGap - captionSize
+		if err := page.BeginTag(StructCaption); err != nil {
+			return err
+		}
+		if err := page.DrawText(caption, r.alignedX(min(captionWidth, maxWidth), maxWidth), baseline); err != nil {
+			return fmt.Errorf("failed to draw caption: %w", err)
+		}
+		if err := page.EndTag(); err != nil {
+			return err
+		}
+		bottom -= captionHeight
+	}
+
+	r.currentY = bottom - r.style.ParagraphSpacing - r.style.BodySize
+	return nil
+}
+
+// alignedX returns the left edge of a block of the given width aligned within the content width.
+func (r *documentRenderer) alignedX(width, contentWidth float64) float64 {
+	switch r.imageAlign {
+	case AlignCenter:
+		return r.contentLeft() + (contentWidth-width)/2
+	case AlignRight:
+		return r.contentLeft() + contentWidth - width
+	default:
+		return r.contentLeft()
+	}
+}
+
+// loadImage loads a PNG or JPEG image from a data URI, a remote URL or a local path.
+// Relative paths are resolved against the image base path.
+func (r *documentRenderer) loadImage(src string) (*Image, error) {
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(src, "data:"):
+		data, err = decodeDataURI(src)
+	case isRemoteImage(src):
+		data, err = fetchRemoteImage(src)
+	default:
+		path := src
+		if !filepath.IsAbs(path) && r.imageBasePath != "" {
+			path = filepath.Join(r.imageBasePath, path)
+		}
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return LoadPNG(bytes.NewReader(data))
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return LoadJPEG(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported image format (PNG and JPEG are supported)")
+	}
+}
+
+// isRemoteImage reports whether src is an http or https URL.
+func isRemoteImage(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
+
+// markdownImageName shortens a data URI for error messages.
+func markdownImageName(src string) string {
+	if strings.HasPrefix(src, "data:") {
+		if i := strings.IndexByte(src, ','); i >= 0 {
+			return src[:i] + ",..."
+		}
+	}
+	return src
+}
+
+// decodeDataURI returns the data of a data URI ("data:image/png;base64,...").
+func decodeDataURI(uri string) ([]byte, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid data URI: missing comma")
+	}
+	if strings.HasSuffix(header, ";base64") {
+		payload = strings.Join(strings.Fields(payload), "")
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data URI: %w", err)
+		}
+		return data, nil
+	}
+	text, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data URI: %w", err)
+	}
+	return []byte(text), nil
+}
+
+// fetchRemoteImage downloads an image, up to markdownMaxImageSize bytes.
+func fetchRemoteImage(src string) ([]byte, error) {
+	resp, err := markdownHTTPClient.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, markdownMaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > markdownMaxImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", markdownMaxImageSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,147 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// markdownTestPNG はwidth×heightのPNGを作成する
+func markdownTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{0, 128, 255, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewMarkdownDocument_Image(t *testing.T) {
+	contentWidth := PageSizeA4.Width - 144
+
+	tests := []struct {
+		name          string
+		width, height int
+		align         TextAlign
+		wantW, wantH  float64
+		wantX         float64
+	}{
+		// 小さい画像は拡大しない
+		{name: "left", width: 100, height: 50, align: AlignLeft, wantW: 100, wantH: 50, wantX: 72},
+		{name: "center", width: 100, height: 50, align: AlignCenter, wantW: 100, wantH: 50, wantX: 72 + (contentWidth-100)/2},
+		{name: "right", width: 100, height: 50, align: AlignRight, wantW: 100, wantH: 50, wantX: 72 + contentWidth - 100},
+		// 本文の幅より大きい画像は本文の幅に縮める
+		{name: "wide", width: 2000, height: 1000, align: AlignCenter, wantW: contentWidth, wantH: contentWidth / 2, wantX: 72},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(markdownTestPNG(t, tt.width, tt.height))
+			doc, err := NewMarkdownDocument("![A chart]("+dataURI+")\n", &MarkdownOptions{Mode: MarkdownModeDocument, ImageAlign: tt.align})
+			if err != nil {
+				t.Fatalf("NewMarkdownDocument() failed: %v", err)
+			}
+			want := fmt.Sprintf("%.2f 0.00 0.00 %.2f %.2f ", tt.wantW, tt.wantH, tt.wantX)
+			if content := doc.pages[0].content.String(); !strings.Contains(content, want) {
+				t.Errorf("content does not contain %q:\n%s", want, content)
+			}
+		})
+	}
+}
+
+func TestNewMarkdownDocument_ImageCaption(t *testing.T) {
+	small := "data:image/png;base64," + base64.StdEncoding.EncodeToString(markdownTestPNG(t, 100, 50))
+	r := renderMarkdownForTest(t, "Before.\n\n![Company logo]("+small+" \"Figure 1: Logo\")\n\nAfter.\n")
+
+	text, err := r.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText() failed: %v", err)
+	}
+	for _, want := range []string{"Before.", "Figure 1: Logo", "After."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text = %q, want it to contain %q", text, want)
+		}
+	}
+	// 代替テキストは図の/Altで、本文には描かない
+	if strings.Contains(text, "Company logo") {
+		t.Errorf("text = %q, want no alt text", text)
+	}
+	if got, want := structureString(readStructure(t, r)), "Document(P Figure Caption P)"; got != want {
+		t.Errorf("structure = %s, want %s", got, want)
+	}
+}
+
+func TestNewMarkdownDocumentFromFile_LocalImage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "img"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "img", "logo.png"), markdownTestPNG(t, 10, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "doc.md")
+	if err := os.WriteFile(path, []byte("![logo](img/logo.png)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 相対パスはMarkdownファイルのディレクトリから探す
+	doc, err := NewMarkdownDocumentFromFile(path, nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocumentFromFile() failed: %v", err)
+	}
+	if len(doc.pages[0].images) != 1 {
+		t.Errorf("page has %d images, want 1", len(doc.pages[0].images))
+	}
+
+	if _, err := NewMarkdownDocument("![missing](nothing.png)\n", &MarkdownOptions{Mode: MarkdownModeDocument, ImageBasePath: dir}); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+	if _, err := NewMarkdownDocument("![text](data:text/plain,hello)\n", nil); err == nil {
+		t.Error("expected an error for an unsupported image format")
+	}
+}
+
+func TestNewMarkdownDocument_RemoteImage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Write(markdownTestPNG(t, 10, 10))
+	}))
+	defer server.Close()
+	markdown := "![Remote logo](" + server.URL + "/logo.png)\n"
+
+	// 既定ではダウンロードせず、代替テキストを描く
+	doc, err := NewMarkdownDocument(markdown, nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	if requests != 0 || len(doc.pages[0].images) != 0 {
+		t.Errorf("requests = %d, images = %d, want no download", requests, len(doc.pages[0].images))
+	}
+	if !strings.Contains(doc.pages[0].content.String(), "(Remote logo) Tj") {
+		t.Error("alt text is not drawn")
+	}
+
+	doc, err = NewMarkdownDocument(markdown, &MarkdownOptions{Mode: MarkdownModeDocument, AllowRemoteImages: true})
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	if requests != 1 || len(doc.pages[0].images) != 1 {
+		t.Errorf("requests = %d, images = %d, want the image downloaded", requests, len(doc.pages[0].images))
+	}
+}
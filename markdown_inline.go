@@ -0,0 +1,395 @@
+package gopdf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// Subscripts and superscripts relative to the font size.
+const (
+	markdownScriptSize  = 0.7  // font size of subscripts and superscripts
+	markdownSuperscript = 0.35 // distance of the superscript baseline above the baseline of the line
+	markdownSubscript   = 0.15 // distance of the subscript baseline below the baseline of the line
+)
+
+// markdownFormat is the formatting of a part of the text of a block,
+// from Markdown emphasis and the supported inline HTML tags.
+type markdownFormat struct {
+	bold      bool   // **text**, <b>, <strong>
+	italic    bool   // *text*, <i>, <em>
+	underline bool   // <u>
+	script    int    // 1 in <sup>, -1 in <sub>
+	color     *Color // <span style="color: ...">
+}
+
+// markdownSpan is formatted text in a block: the bytes [start, end) of the text have format.
+// The spans of a block are in order and do not overlap; text outside them is not formatted.
+type markdownSpan struct {
+	start, end int
+	format     markdownFormat
+}
+
+// markdownInlineTags are the supported inline HTML tags and the formatting they apply, given the attributes of the tag.
+// Other tags are ignored and their text is drawn as is, except the text of markdownHiddenTags.
+var markdownInlineTags = map[string]func(format *markdownFormat, attrs string){
+	"b":      func(f *markdownFormat, _ string) { f.bold = true },
+	"strong": func(f *markdownFormat, _ string) { f.bold = true },
+	"i":      func(f *markdownFormat, _ string) { f.italic = true },
+	"em":     func(f *markdownFormat, _ string) { f.italic = true },
+	"u":      func(f *markdownFormat, _ string) { f.underline = true },
+	"sup":    func(f *markdownFormat, _ string) { f.script = 1 },
+	"sub":    func(f *markdownFormat, _ string) { f.script = -1 },
+	"span": func(f *markdownFormat, attrs string) {
+		if color, ok := styleColor(attrs); ok {
+			f.color = &color
+		}
+	},
+}
+
+// markdownHiddenTags are the tags whose text is not drawn.
+var markdownHiddenTags = map[string]bool{"script": true, "style": true}
+
+var (
+	markdownTagPattern   = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)([^>]*)>$`)
+	markdownStylePattern = regexp.MustCompile(`(?i)\bstyle\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	markdownRGBPattern   = regexp.MustCompile(`^rgb\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*\)$`)
+)
+
+// markdownColorNames are the CSS color names accepted in <span style="color: ...">.
+var markdownColorNames = map[string]Color{
+	"black":   {0, 0, 0},
+	"white":   {1, 1, 1},
+	"gray":    {128.0 / 255, 128.0 / 255, 128.0 / 255},
+	"grey":    {128.0 / 255, 128.0 / 255, 128.0 / 255},
+	"silver":  {192.0 / 255, 192.0 / 255, 192.0 / 255},
+	"red":     {1, 0, 0},
+	"maroon":  {128.0 / 255, 0, 0},
+	"orange":  {1, 165.0 / 255, 0},
+	"yellow":  {1, 1, 0},
+	"olive":   {128.0 / 255, 128.0 / 255, 0},
+	"lime":    {0, 1, 0},
+	"green":   {0, 128.0 / 255, 0},
+	"aqua":    {0, 1, 1},
+	"cyan":    {0, 1, 1},
+	"teal":    {0, 128.0 / 255, 128.0 / 255},
+	"blue":    {0, 0, 1},
+	"navy":    {0, 0, 128.0 / 255},
+	"fuchsia": {1, 0, 1},
+	"magenta": {1, 0, 1},
+	"purple":  {128.0 / 255, 0, 128.0 / 255},
+}
+
+// markdownOpenTag is an open HTML tag or emphasis, with the format in front of it.
+type markdownOpenTag struct {
+	name   string
+	before markdownFormat
+}
+
+// markdownInlineText builds the text of a block and its formatted spans.
+type markdownInlineText struct {
+	text   strings.Builder
+	spans  []markdownSpan
+	format markdownFormat
+	open   []markdownOpenTag // innermost last
+	hidden int               // number of open markdownHiddenTags
+}
+
+// write adds s to the text in the current format.
+func (t *markdownInlineText) write(s string) {
+	if t.hidden > 0 || s == "" {
+		return
+	}
+	start := t.text.Len()
+	t.text.WriteString(s)
+	if t.format == (markdownFormat{}) {
+		return
+	}
+	if n := len(t.spans); n > 0 && t.spans[n-1].end == start && t.spans[n-1].format == t.format {
+		t.spans[n-1].end = t.text.Len()
+		return
+	}
+	t.spans = append(t.spans, markdownSpan{start: start, end: t.text.Len(), format: t.format})
+}
+
+// push opens the tag name, which applies apply to the current format.
+func (t *markdownInlineText) push(name string, apply func(*markdownFormat)) {
+	t.open = append(t.open, markdownOpenTag{name: name, before: t.format})
+	apply(&t.format)
+}
+
+// pop closes the innermost open tag name and the tags inside it that are still open.
+// A tag that is not open is ignored.
+func (t *markdownInlineText) pop(name string) {
+	for i := len(t.open) - 1; i >= 0; i-- {
+		if t.open[i].name == name {
+			t.format = t.open[i].before
+			t.open = t.open[:i]
+			return
+		}
+	}
+}
+
+// html applies an inline HTML tag such as <b>, </b> or <br>.
+func (t *markdownInlineText) html(literal string) {
+	m := markdownTagPattern.FindStringSubmatch(strings.TrimSpace(literal))
+	if m == nil {
+		return
+	}
+	closing, name, attrs := m[1] == "/", strings.ToLower(m[2]), m[3]
+
+	switch {
+	case markdownHiddenTags[name]:
+		if closing {
+			t.hidden = max(t.hidden-1, 0)
+		} else if !strings.HasSuffix(attrs, "/") {
+			t.hidden++
+		}
+	case name == "br":
+		t.write("\n")
+	case markdownInlineTags[name] != nil:
+		if closing {
+			t.pop(name)
+		} else if !strings.HasSuffix(attrs, "/") {
+			t.push(name, func(f *markdownFormat) { markdownInlineTags[name](f, attrs) })
+		}
+	}
+}
+
+// styleColor returns the color of the style attribute in the attributes of a tag.
+func styleColor(attrs string) (Color, bool) {
+	m := markdownStylePattern.FindStringSubmatch(attrs)
+	if m == nil {
+		return Color{}, false
+	}
+	var color Color
+	found := false
+	for _, decl := range strings.Split(m[1]+m[2], ";") {
+		property, value, ok := strings.Cut(decl, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(property), "color") {
+			continue
+		}
+		// The last valid declaration wins, as in CSS
+		if c, ok := parseCSSColor(value); ok {
+			color, found = c, true
+		}
+	}
+	return color, found
+}
+
+// parseCSSColor parses a CSS color: a color name, #rgb, #rrggbb or rgb(r, g, b).
+func parseCSSColor(value string) (Color, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if color, ok := markdownColorNames[value]; ok {
+		return color, true
+	}
+
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return Color{}, false
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return Color{}, false
+		}
+		return NewRGB(uint8(n>>16), uint8(n>>8), uint8(n)), true
+	}
+
+	if m := markdownRGBPattern.FindStringSubmatch(value); m != nil {
+		var rgb [3]uint8
+		for i, s := range m[1:] {
+			n, err := strconv.Atoi(s)
+			if err != nil || n > 255 {
+				return Color{}, false
+			}
+			rgb[i] = uint8(n)
+		}
+		return NewRGB(rgb[0], rgb[1], rgb[2]), true
+	}
+	return Color{}, false
+}
+
+// formatAt returns the format of the byte at offset in the text of a block.
+func formatAt(spans []markdownSpan, offset int) markdownFormat {
+	for _, span := range spans {
+		if span.start <= offset && offset < span.end {
+			return span.format
+		}
+	}
+	return markdownFormat{}
+}
+
+// scriptSize returns the font size of text in format and the distance of its baseline above the baseline of the line.
+func scriptSize(format markdownFormat, size float64) (float64, float64) {
+	switch format.script {
+	case 1:
+		return size * markdownScriptSize, size * markdownSuperscript
+	case -1:
+		return size * markdownScriptSize, -size * markdownSubscript
+	}
+	return size, 0
+}
+
+// collapseSpaces replaces each run of spaces and tabs in text (not line breaks) with a single space,
+// as wrapping does, and moves the links and the formatted spans with the text.
+func collapseSpaces(text string, links []markdownLink, spans []markdownSpan) (string, []markdownLink, []markdownSpan) {
+	var b strings.Builder
+	moved := make([]int, len(text)+1) // position in the result of each character of text; links and spans start and end at characters
+	space := false
+	for i, c := range text {
+		moved[i] = b.Len()
+		if c != '\n' && unicode.IsSpace(c) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(c)
+	}
+	moved[len(text)] = b.Len()
+
+	var movedLinks []markdownLink
+	for _, link := range links {
+		link.start, link.end = moved[link.start], moved[link.end]
+		if link.start < link.end {
+			movedLinks = append(movedLinks, link)
+		}
+	}
+	var movedSpans []markdownSpan
+	for _, span := range spans {
+		span.start, span.end = moved[span.start], moved[span.end]
+		if span.start < span.end {
+			movedSpans = append(movedSpans, span)
+		}
+	}
+	return b.String(), movedLinks, movedSpans
+}
+
+// wrapInline wraps the text of a block to the content width, measuring each part in its format.
+// It returns the byte range [start, end) of each line in text.
+func (r *documentRenderer) wrapInline(text string, spans []markdownSpan, size float64) [][2]int {
+	var runs []layout.TextRun
+	pos := 0
+	for _, span := range spans {
+		if span.start > pos {
+			runs = append(runs, layout.TextRun{Text: text[pos:span.start], Size: size})
+		}
+		spanSize, _ := scriptSize(span.format, size)
+		runs = append(runs, layout.TextRun{Text: text[span.start:span.end], Size: spanSize, Bold: span.format.bold, Italic: span.format.italic})
+		pos = span.end
+	}
+	if pos < len(text) {
+		runs = append(runs, layout.TextRun{Text: text[pos:], Size: size})
+	}
+
+	return layout.WrapRunRanges(runs, r.contentWidth(), func(text string, run layout.TextRun) float64 {
+		return r.textWidth(text, r.inlineFont(markdownFormat{bold: run.Bold, italic: run.Italic}), run.Size)
+	})
+}
+
+// drawInlineText draws the text of a block with its links and formatting, wrapped to the content width.
+// Line breaks in the text (hard breaks and <br>) start a new line.
+func (r *documentRenderer) drawInlineText(text string, links []markdownLink, spans []markdownSpan) error {
+	size := r.style.BodySize
+	lineHeight := size * r.style.LineSpacing
+	// Spaces are measured and drawn as wrapping collapses them, so each line is a part of the text
+	text, links, spans = collapseSpaces(text, links, spans)
+
+	var page *Page
+	for _, line := range r.wrapInline(text, spans, size) {
+		r.checkPageBreak(lineHeight)
+		if page != r.currentPage {
+			page = r.currentPage
+			page.SetFillColor(convertColor(r.style.TextColor))
+		}
+		if err := r.drawTextLinks(text[line[0]:line[1]], line[0], links, spans, r.contentLeft(), r.currentY, size); err != nil {
+			return err
+		}
+		r.currentY -= lineHeight
+	}
+	return nil
+}
+
+// drawFormatted draws text whose bytes start at offset in the text of its block, part by part in the format of its spans,
+// and returns its width. Parts without a color of their own are drawn in color, or in black if color is nil.
+func (r *documentRenderer) drawFormatted(text string, offset int, spans []markdownSpan, x, y, size float64, color *Color) (float64, error) {
+	left := x
+	for start := 0; start < len(text); {
+		// The part ends where a span starts or ends
+		end := len(text)
+		for _, span := range spans {
+			for _, boundary := range []int{span.start - offset, span.end - offset} {
+				if boundary > start && boundary < end {
+					end = boundary
+				}
+			}
+		}
+		width, err := r.drawFormattedPart(text[start:end], formatAt(spans, offset+start), x, y, size, color)
+		if err != nil {
+			return 0, err
+		}
+		x += width
+		start = end
+	}
+	return x - left, nil
+}
+
+// drawFormattedPart draws text in format and returns its width.
+// Subscripts and superscripts are smaller and shifted from the baseline y; the underline is an artifact.
+func (r *documentRenderer) drawFormattedPart(text string, format markdownFormat, x, y, size float64, color *Color) (float64, error) {
+	page := r.currentPage
+	font := r.inlineFont(format)
+	partSize, rise := scriptSize(format, size)
+	if err := font.set(page, partSize); err != nil {
+		return 0, fmt.Errorf("failed to set font: %w", err)
+	}
+	if format.color != nil {
+		color = format.color
+	}
+
+	if color == nil {
+		if err := page.DrawText(text, x, y+rise); err != nil {
+			return 0, err
+		}
+	} else {
+		block := TextBlock{Color: layout.Color{R: color.R, G: color.G, B: color.B}}
+		if err := page.drawLayoutLine(text, [6]float64{1, 0, 0, 1, x, y + rise}, block); err != nil {
+			return 0, err
+		}
+	}
+
+	width := r.textWidth(text, font, partSize)
+	if format.underline {
+		underlineColor := convertColor(r.style.TextColor)
+		if color != nil {
+			underlineColor = *color
+		}
+		if err := r.drawUnderline(x, y, width, size, underlineColor); err != nil {
+			return 0, err
+		}
+	}
+	return width, nil
+}
+
+// drawUnderline draws an underline of width below the baseline y of text of size, as an artifact.
+func (r *documentRenderer) drawUnderline(x, y, width, size float64, color Color) error {
+	page := r.currentPage
+	return page.artifact(func() error {
+		fmt.Fprintf(&page.content, "q\n")
+		page.SetStrokeColor(color)
+		page.SetLineWidth(size * markdownUnderlineWidth)
+		underline := y - size*markdownUnderlineOffset
+		page.DrawLine(x, underline, x+width, underline)
+		fmt.Fprintf(&page.content, "Q\n")
+		return nil
+	})
+}
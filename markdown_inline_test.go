@@ -0,0 +1,241 @@
+package gopdf
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/markdown"
+)
+
+func TestDocumentRenderer_ExtractInline(t *testing.T) {
+	red := Color{R: 1}
+	tests := []struct {
+		name      string
+		markdown  string
+		wantText  string
+		wantSpans []markdownSpan
+	}{
+		{
+			name:      "emphasis",
+			markdown:  "a **b** *c*",
+			wantText:  "a b c",
+			wantSpans: []markdownSpan{{start: 2, end: 3, format: markdownFormat{bold: true}}, {start: 4, end: 5, format: markdownFormat{italic: true}}},
+		},
+		{
+			name:     "tags",
+			markdown: "<b>b</b><I>i</I><u>u</u>x<sup>2</sup>H<sub>2</sub>",
+			wantText: "biux2H2",
+			wantSpans: []markdownSpan{
+				{start: 0, end: 1, format: markdownFormat{bold: true}},
+				{start: 1, end: 2, format: markdownFormat{italic: true}},
+				{start: 2, end: 3, format: markdownFormat{underline: true}},
+				{start: 4, end: 5, format: markdownFormat{script: 1}},
+				{start: 6, end: 7, format: markdownFormat{script: -1}},
+			},
+		},
+		{
+			name:      "nested",
+			markdown:  "<b>a <i>b</i></b> c",
+			wantText:  "a b c",
+			wantSpans: []markdownSpan{{start: 0, end: 2, format: markdownFormat{bold: true}}, {start: 2, end: 3, format: markdownFormat{bold: true, italic: true}}},
+		},
+		{
+			name:      "color",
+			markdown:  `<span style="font-weight: bold; color: #f00">red</span>`,
+			wantText:  "red",
+			wantSpans: []markdownSpan{{start: 0, end: 3, format: markdownFormat{color: &red}}},
+		},
+		{
+			name:     "span without color",
+			markdown: `<span class="note">text</span>`,
+			wantText: "text",
+		},
+		{
+			name:     "line break",
+			markdown: "one<br>two<br/>three<BR />four",
+			wantText: "one\ntwo\nthree\nfour",
+		},
+		{
+			// 対応していないタグは無視し、scriptとstyleの中身は描かない
+			name:     "unsupported",
+			markdown: `<font color="red">a</font><script>alert(1)</script>b`,
+			wantText: "ab",
+		},
+		{
+			// 閉じていないタグは段落の終わりまで、開いていない閉じタグは無視する
+			name:      "unbalanced",
+			markdown:  "</b>a <u>b",
+			wantText:  "a b",
+			wantSpans: []markdownSpan{{start: 2, end: 3, format: markdownFormat{underline: true}}},
+		},
+	}
+
+	r := newDocumentRenderer(PageSizeA4, Portrait, nil, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := markdown.NewParser().ParseString(tt.markdown)
+			text, _, spans := r.extractTextLinks(root.GetChildren()[0])
+			if text != tt.wantText || !reflect.DeepEqual(spans, tt.wantSpans) {
+				t.Errorf("extractTextLinks() = %q, %+v, want %q, %+v", text, spans, tt.wantText, tt.wantSpans)
+			}
+		})
+	}
+}
+
+func TestParseCSSColor(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Color
+		wantOK bool
+	}{
+		{value: "red", want: Color{R: 1}, wantOK: true},
+		{value: " Navy ", want: Color{B: 128.0 / 255}, wantOK: true},
+		{value: "#00ff00", want: Color{G: 1}, wantOK: true},
+		{value: "#00F", want: Color{B: 1}, wantOK: true},
+		{value: "rgb(255, 0, 0)", want: Color{R: 1}, wantOK: true},
+		{value: "rgb(256, 0, 0)"},
+		{value: "#12345"},
+		{value: "#ggg"},
+		{value: "url(x)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseCSSColor(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseCSSColor(%q) = %+v, %v, want %+v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewMarkdownDocument_InlineHTML(t *testing.T) {
+	doc, err := NewMarkdownDocument(`Text <b>bold</b> <i>italic</i> <b><i>both</i></b> <span style="color: red">red</span> H<sub>2</sub>O x<sup>2</sup> <u>under</u>`+"\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	content := doc.pages[0].content.String()
+
+	// 太字・斜体は標準フォントの太字・斜体で描く
+	for _, want := range []string{"/F2 12.00 Tf\n", "(bold) Tj", "/F3 12.00 Tf\n", "(italic) Tj", "/F4 12.00 Tf\n", "(both) Tj"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content does not contain %q:\n%s", want, content)
+		}
+	}
+	// 色はspanのstyleのcolor
+	if !strings.Contains(content, "1.000 0.000 0.000 rg\n") {
+		t.Errorf("colored text is not drawn in red:\n%s", content)
+	}
+	// 下付き・上付きは小さい文字で、ベースラインをずらす
+	if strings.Count(content, "/F1 8.40 Tf\n") != 2 {
+		t.Errorf("subscript and superscript are not drawn at 8.4pt:\n%s", content)
+	}
+	// 下線は線（アーティファクト）
+	if !strings.Contains(content, " l\nS\n") {
+		t.Errorf("underline is not drawn:\n%s", content)
+	}
+}
+
+func TestNewMarkdownDocument_InlineHTMLLayout(t *testing.T) {
+	r := renderMarkdownForTest(t, "First<br>second H<sub>2</sub>O x<sup>2</sup>\n")
+	elements, err := r.ExtractPageTextElements(0)
+	if err != nil {
+		t.Fatalf("ExtractPageTextElements() failed: %v", err)
+	}
+	y := make(map[string]float64)
+	for _, elem := range elements {
+		y[strings.TrimSpace(elem.Text)] = elem.Y
+	}
+
+	// <br>の後は次の行に描く
+	if y["First"]-y["second H"] < 12 {
+		t.Errorf("line after <br> at y = %g, want below the first line at y = %g", y["second H"], y["First"])
+	}
+	// 下付きはベースラインの下、上付きは上（どちらも"2"で、下付きが先）
+	var scripts []float64
+	for _, elem := range elements {
+		if elem.Text == "2" {
+			scripts = append(scripts, elem.Y)
+		}
+	}
+	if len(scripts) != 2 {
+		t.Fatalf("text elements = %+v, want two scripts", elements)
+	}
+	sub, sup := scripts[0], scripts[1]
+	if base := y["O x"]; sub >= base || sup <= base {
+		t.Errorf("subscript y = %g, superscript y = %g, want below and above the baseline y = %g", sub, sup, base)
+	}
+}
+
+func TestNewMarkdownDocument_ParagraphWrap(t *testing.T) {
+	long := strings.TrimSpace(strings.Repeat("word ", 100))
+	doc, err := NewMarkdownDocument(long+"\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	// 長い段落は本文の幅に折り返す
+	page := doc.pages[0]
+	lines := 0
+	for _, line := range strings.Split(page.content.String(), "\n") {
+		if strings.HasSuffix(line, ") Tj") {
+			lines++
+			if width, _ := FontHelvetica.TextWidth(line[1:len(line)-4], 12); width > page.Width()-72-72 {
+				t.Errorf("line %q is wider than the content", line)
+			}
+		}
+	}
+	if lines < 2 {
+		t.Errorf("paragraph is drawn in %d lines, want it wrapped", lines)
+	}
+}
+
+func TestNewMarkdownDocument_ParagraphWrapTab(t *testing.T) {
+	// 折り返す段落の途中のタブと連続した空白
+	md := strings.Repeat("word ", 200) + "\tword  **bold** " + strings.Repeat("word ", 200) + "[link](https://example.com/) end\n"
+	doc, err := NewMarkdownDocument(md, nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+
+	// 書式とリンクは元の語に付いたまま
+	var content strings.Builder
+	for _, page := range doc.pages {
+		content.WriteString(page.content.String())
+	}
+	if !regexp.MustCompile(`/F2 12.00 Tf\n[^\n]* Td\n\(bold\) Tj`).MatchString(content.String()) {
+		t.Errorf("bold is not drawn in Helvetica-Bold")
+	}
+	for _, text := range []string{"(bold) Tj", "(link) Tj", "( end) Tj"} {
+		if !strings.Contains(content.String(), text) {
+			t.Errorf("content does not contain %q", text)
+		}
+	}
+	var annots []string
+	for _, page := range doc.pages {
+		for _, annot := range page.annotations {
+			if link, ok := annot.(*linkAnnotation); ok {
+				annots = append(annots, link.link.Description)
+			}
+		}
+	}
+	if len(annots) != 1 || annots[0] != "link" {
+		t.Errorf("link annotations = %q, want [link]", annots)
+	}
+}
+
+func TestCollapseSpaces(t *testing.T) {
+	text, links, spans := collapseSpaces("a \t b\n  c",
+		[]markdownLink{{start: 4, end: 5, uri: "u"}},
+		[]markdownSpan{{start: 8, end: 9, format: markdownFormat{bold: true}}})
+	if text != "a b\n c" {
+		t.Errorf("text = %q, want %q", text, "a b\n c")
+	}
+	if want := []markdownLink{{start: 2, end: 3, uri: "u"}}; !reflect.DeepEqual(links, want) {
+		t.Errorf("links = %+v, want %+v", links, want)
+	}
+	if want := []markdownSpan{{start: 5, end: 6, format: markdownFormat{bold: true}}}; !reflect.DeepEqual(spans, want) {
+		t.Errorf("spans = %+v, want %+v", spans, want)
+	}
+}
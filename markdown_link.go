@@ -0,0 +1,112 @@
+package gopdf
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Link styling relative to the font size.
+const (
+	markdownUnderlineOffset = 0.12 // distance of the underline below the baseline
+	markdownUnderlineWidth  = 0.06 // width of the underline
+	markdownLinkDescent     = 0.2  // part of the link area below the baseline
+	markdownLinkHeight      = 1.1  // height of the link area
+)
+
+// markdownLink is a link in the text of a block: the bytes [start, end) of the text open uri.
+type markdownLink struct {
+	start, end int
+	uri        string
+}
+
+// isLinkDestination reports whether a link destination can be opened from a PDF:
+// an absolute URI such as https://... or mailto:...
+func isLinkDestination(dest string) bool {
+	u, err := url.Parse(dest)
+	return err == nil && u.Scheme != ""
+}
+
+// trimTextLinks skips the first skip bytes of text, trims the spaces around the rest,
+// and moves the links and the formatted spans with the text. Links and spans left without text are dropped.
+func trimTextLinks(text string, links []markdownLink, spans []markdownSpan, skip int) (string, []markdownLink, []markdownSpan) {
+	rest := text[skip:]
+	trimmed := strings.TrimLeftFunc(rest, unicode.IsSpace)
+	shift := skip + len(rest) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+
+	var moved []markdownLink
+	for _, link := range links {
+		link.start = max(link.start-shift, 0)
+		link.end = min(link.end-shift, len(trimmed))
+		if link.start < link.end {
+			moved = append(moved, link)
+		}
+	}
+	var movedSpans []markdownSpan
+	for _, span := range spans {
+		span.start = max(span.start-shift, 0)
+		span.end = min(span.end-shift, len(trimmed))
+		if span.start < span.end {
+			movedSpans = append(movedSpans, span)
+		}
+	}
+	return trimmed, moved, movedSpans
+}
+
+// drawTextLinks draws a line of text whose bytes start at offset in the text of its block,
+// in the format of its spans. The parts of the line inside links are drawn by drawLink.
+func (r *documentRenderer) drawTextLinks(line string, offset int, links []markdownLink, spans []markdownSpan, x, y, size float64) error {
+	pos := 0
+	for _, link := range links {
+		start, end := max(link.start-offset, pos), min(link.end-offset, len(line))
+		if start >= end {
+			continue
+		}
+		width, err := r.drawFormatted(line[pos:start], offset+pos, spans, x, y, size, nil)
+		if err != nil {
+			return err
+		}
+		x += width
+		width, err = r.drawLink(line[start:end], offset+start, spans, link.uri, x, y, size)
+		if err != nil {
+			return err
+		}
+		x += width
+		pos = end
+	}
+	_, err := r.drawFormatted(line[pos:], offset+pos, spans, x, y, size, nil)
+	return err
+}
+
+// drawLink draws the text of a link in LinkColor, underlined, and covers it with a Link annotation.
+// It returns the width of the text. Formatted parts of the text keep their format; a color of their own replaces LinkColor.
+// The text and the annotation are tagged as Link; the underline is an artifact.
+func (r *documentRenderer) drawLink(text string, offset int, spans []markdownSpan, uri string, x, y, size float64) (float64, error) {
+	page := r.currentPage
+	color := convertColor(r.style.LinkColor)
+
+	if err := page.BeginTag(StructLink); err != nil {
+		return 0, err
+	}
+	width, err := r.drawFormatted(text, offset, spans, x, y, size, &color)
+	if err != nil {
+		return 0, fmt.Errorf("failed to draw link: %w", err)
+	}
+	if err := r.drawUnderline(x, y, width, size, color); err != nil {
+		return 0, err
+	}
+	err = page.AddLink(Link{
+		X:           x,
+		Y:           y - size*markdownLinkDescent,
+		Width:       width,
+		Height:      size * markdownLinkHeight,
+		URI:         uri,
+		Description: text,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to add link: %w", err)
+	}
+	return width, page.EndTag()
+}
@@ -0,0 +1,149 @@
+package gopdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewMarkdownDocument_Links(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		want      []Hyperlink // URIとText
+		structure string
+	}{
+		{
+			name:      "inline",
+			markdown:  "See [the site](https://example.com/) for details.\n",
+			want:      []Hyperlink{{URI: "https://example.com/", Text: "the site"}},
+			structure: "Document(P(Link))",
+		},
+		{
+			// 参照形式のリンクは末尾の定義から宛先を引き、定義の行は描かない
+			name:      "reference",
+			markdown:  "Read [the guide][guide] and [Go].\n\n[guide]: https://example.com/guide \"Guide\"\n[go]: https://go.dev/\n",
+			want:      []Hyperlink{{URI: "https://example.com/guide", Text: "the guide"}, {URI: "https://go.dev/", Text: "Go"}},
+			structure: "Document(P(Link Link))",
+		},
+		{
+			name:      "autolink",
+			markdown:  "Mail <mailto:info@example.com> or https://example.org now.\n",
+			want:      []Hyperlink{{URI: "mailto:info@example.com", Text: "info@example.com"}, {URI: "https://example.org", Text: "https://example.org"}},
+			structure: "Document(P(Link Link))",
+		},
+		{
+			// 相対パスのリンク先はPDFから開けないため、通常のテキストとして描く
+			name:      "relative",
+			markdown:  "See [the notes](notes.md).\n",
+			structure: "Document(P)",
+		},
+		{
+			name:      "list item",
+			markdown:  "- [x] Read [the docs](https://example.com/docs)\n",
+			want:      []Hyperlink{{URI: "https://example.com/docs", Text: "the docs"}},
+			structure: "Document(L(LI(Lbl LBody(Link))))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := renderMarkdownForTest(t, tt.markdown)
+			links, err := r.ExtractPageHyperlinks(0)
+			if err != nil {
+				t.Fatalf("ExtractPageHyperlinks() failed: %v", err)
+			}
+			var got []Hyperlink
+			for _, link := range links {
+				got = append(got, Hyperlink{URI: link.URI, Text: link.Text})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("links = %+v, want %+v", got, tt.want)
+			}
+			if got := structureString(readStructure(t, r)); got != tt.structure {
+				t.Errorf("structure = %s, want %s", got, tt.structure)
+			}
+
+			text, err := r.ExtractPageText(0)
+			if err != nil {
+				t.Fatalf("ExtractPageText() failed: %v", err)
+			}
+			if strings.Contains(text, "]:") || strings.Contains(text, "](") {
+				t.Errorf("text = %q, want no Markdown syntax", text)
+			}
+		})
+	}
+}
+
+func TestNewMarkdownDocument_LinkStyle(t *testing.T) {
+	doc, err := NewMarkdownDocument("Go to [home](https://example.com/).\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	content := doc.pages[0].content.String()
+
+	// リンクの文字はLinkColor（既定は青）で描き、下線を引く
+	if !strings.Contains(content, "0.000 0.000 1.000 rg\n") || !strings.Contains(content, "(home) Tj") {
+		t.Errorf("link is not drawn in the link color:\n%s", content)
+	}
+	if !strings.Contains(content, "0.00 0.00 1.00 RG\n") {
+		t.Errorf("link is not underlined:\n%s", content)
+	}
+	// リンクの前後は続けて描く
+	for _, text := range []string{"(Go to ) Tj", "(.) Tj"} {
+		if !strings.Contains(content, text) {
+			t.Errorf("content does not contain %q", text)
+		}
+	}
+}
+
+func TestTrimTextLinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		links     []markdownLink
+		spans     []markdownSpan
+		skip      int
+		wantText  string
+		wantLinks []markdownLink
+		wantSpans []markdownSpan
+	}{
+		{
+			name:      "trim",
+			text:      "  see link  ",
+			links:     []markdownLink{{start: 6, end: 10, uri: "u"}},
+			wantText:  "see link",
+			wantLinks: []markdownLink{{start: 4, end: 8, uri: "u"}},
+		},
+		{
+			name:      "skip",
+			text:      "[x] link",
+			links:     []markdownLink{{start: 4, end: 8, uri: "u"}},
+			skip:      4,
+			wantText:  "link",
+			wantLinks: []markdownLink{{start: 0, end: 4, uri: "u"}},
+		},
+		{
+			name:     "drop",
+			text:     "text ",
+			links:    []markdownLink{{start: 4, end: 5, uri: "u"}},
+			wantText: "text",
+		},
+		{
+			name:      "spans",
+			text:      " a bold ",
+			spans:     []markdownSpan{{start: 3, end: 7, format: markdownFormat{bold: true}}},
+			wantText:  "a bold",
+			wantSpans: []markdownSpan{{start: 2, end: 6, format: markdownFormat{bold: true}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, links, spans := trimTextLinks(tt.text, tt.links, tt.spans, tt.skip)
+			if text != tt.wantText || !reflect.DeepEqual(links, tt.wantLinks) || !reflect.DeepEqual(spans, tt.wantSpans) {
+				t.Errorf("trimTextLinks() = %q, %+v, %+v, want %q, %+v, %+v", text, links, spans, tt.wantText, tt.wantLinks, tt.wantSpans)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// List layout settings (points).
+const (
+	markdownListIndent    = 24.0 // indentation of each list level
+	markdownListMarkerGap = 6.0  // space between a marker and the item text
+)
+
+// Marker glyph sizes relative to the body size.
+const (
+	markdownBulletRadius   = 0.17 // radius of the disc and circle bullets
+	markdownBulletCenter   = 0.33 // height of the bullet center above the baseline
+	markdownCheckboxSize   = 0.75 // side of the task list checkbox
+	markdownCheckboxOffset = 0.05 // distance of the checkbox below the baseline
+	markdownMarkerLine     = 0.07 // line width of the circle bullet and the checkbox
+)
+
+// markdownTaskPrefixes are the GFM task list markers at the start of an item.
+var markdownTaskPrefixes = []struct {
+	prefix  string
+	checked bool
+}{
+	{"[ ] ", false},
+	{"[x] ", true},
+	{"[X] ", true},
+}
+
+// markdownListItem describes the marker of a list item.
+type markdownListItem struct {
+	depth   int    // nesting level, 0 for a top-level list
+	label   string // number and delimiter of an ordered item ("1."), empty for bullets
+	task    bool   // GFM task list item
+	checked bool
+}
+
+// renderList renders an ordered or unordered list and the lists nested in its items.
+// Each level is indented by markdownListIndent and the markers are right-aligned in front of the text:
+// numbers for ordered lists, and a disc, circle or square (by depth) for unordered lists.
+// GFM task list items ("- [ ] todo", "- [x] done") get a checkbox drawn with vector graphics instead.
+func (r *documentRenderer) renderList(list *ast.List, depth int) error {
+	if err := r.currentPage.BeginTag(StructL); err != nil {
+		return err
+	}
+
+	ordered := list.ListFlags&ast.ListTypeOrdered != 0
+	delimiter := list.Delimiter
+	if delimiter == 0 {
+		delimiter = '.'
+	}
+	number := max(list.Start, 1)
+	for i, child := range list.GetChildren() {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
+			continue
+		}
+		if i > 0 && !list.Tight {
+			r.currentY -= r.style.ParagraphSpacing
+		}
+		marker := markdownListItem{depth: depth}
+		if ordered {
+			marker.label = fmt.Sprintf("%d%c", number, delimiter)
+			number++
+		}
+		if err := r.renderListItem(item, marker, list.Tight); err != nil {
+			return fmt.Errorf("failed to draw list item: %w", err)
+		}
+	}
+
+	if err := r.currentPage.EndTag(); err != nil {
+		return err
+	}
+	if depth == 0 {
+		r.currentY -= r.style.ParagraphSpacing
+	}
+	return nil
+}
+
+// renderListItem renders a list item tagged as LI, with its marker as Lbl and its content as LBody.
+func (r *documentRenderer) renderListItem(item *ast.ListItem, marker markdownListItem, tight bool) error {
+	children := item.GetChildren()
+	var text string
+	var links []markdownLink
+	var spans []markdownSpan
+	if len(children) > 0 {
+		if para, ok := children[0].(*ast.Paragraph); ok {
+			text, links, spans = r.extractTextLinks(para)
+			text, links, spans = trimTextLinks(text, links, spans, 0)
+			for _, task := range markdownTaskPrefixes {
+				if strings.HasPrefix(text+" ", task.prefix) {
+					marker.task, marker.checked = true, task.checked
+					text, links, spans = trimTextLinks(text, links, spans, min(len(task.prefix), len(text)))
+					break
+				}
+			}
+		}
+	}
+
+	if err := r.currentPage.BeginTag(StructLI); err != nil {
+		return err
+	}
+	// Keep the marker on the page of the first line
+	r.checkPageBreak(r.style.BodySize * r.style.LineSpacing)
+	r.indent += markdownListIndent
+	defer func() { r.indent -= markdownListIndent }()
+
+	if err := r.currentPage.BeginTag(StructLbl); err != nil {
+		return err
+	}
+	if err := r.drawListMarker(marker); err != nil {
+		return err
+	}
+	if err := r.currentPage.EndTag(); err != nil {
+		return err
+	}
+
+	if err := r.currentPage.BeginTag(StructLBody); err != nil {
+		return err
+	}
+	startY := r.currentY
+	for i, child := range children {
+		switch c := child.(type) {
+		case *ast.Paragraph:
+			if i > 0 {
+				if !tight {
+					r.currentY -= r.style.ParagraphSpacing
+				}
+				text, links, spans = r.extractTextLinks(c)
+				text, links, spans = trimTextLinks(text, links, spans, 0)
+			}
+			if err := r.drawInlineText(text, links, spans); err != nil {
+				return err
+			}
+			if err := r.renderParagraphImages(c); err != nil {
+				return err
+			}
+		case *ast.List:
+			if err := r.renderList(c, marker.depth+1); err != nil {
+				return err
+			}
+		default:
+			if err := r.walkNode(child); err != nil {
+				return err
+			}
+		}
+	}
+	if err := r.currentPage.EndTag(); err != nil {
+		return err
+	}
+	// An empty item still takes up the line of its marker
+	if r.currentY == startY {
+		r.currentY -= r.style.BodySize * r.style.LineSpacing
+	}
+
+	return r.currentPage.EndTag()
+}
+
+// drawListMarker draws the marker of a list item right-aligned in front of the item text.
+// Bullets and checkboxes are vector graphics; their structure element gets the symbol as /Alt.
+func (r *documentRenderer) drawListMarker(marker markdownListItem) error {
+	page := r.currentPage
+	size := r.style.BodySize
+	right := r.contentLeft() - markdownListMarkerGap
+	baseline := r.currentY
+	color := convertColor(r.style.TextColor)
+
+	if marker.label != "" && !marker.task {
+		if err := r.bodyFont().set(page, size); err != nil {
+			return fmt.Errorf("failed to set font: %w", err)
+		}
+		page.SetFillColor(color)
+		return page.DrawText(marker.label, right-r.textWidth(marker.label, r.bodyFont(), size), baseline)
+	}
+
+	fmt.Fprintf(&page.content, "q\n")
+	page.SetFillColor(color)
+	page.SetStrokeColor(color)
+	page.SetLineWidth(size * markdownMarkerLine)
+	alt := "•"
+	if marker.task {
+		side := size * markdownCheckboxSize
+		x, y := right-side, baseline-size*markdownCheckboxOffset
+		page.DrawRectangle(x, y, side, side)
+		alt = "☐"
+		if marker.checked {
+			page.SetLineCap(RoundCap)
+			page.SetLineJoin(RoundJoin)
+			fmt.Fprintf(&page.content, "%.2f %.2f m %.2f %.2f l %.2f %.2f l S\n",
+				x+side*0.2, y+side*0.5, x+side*0.42, y+side*0.25, x+side*0.8, y+side*0.78)
+			alt = "☑"
+		}
+	} else {
+		radius := size * markdownBulletRadius
+		cx, cy := right-radius, baseline+size*markdownBulletCenter
+		switch marker.depth % 3 {
+		case 0:
+			page.FillCircle(cx, cy, radius)
+		case 1:
+			page.DrawCircle(cx, cy, radius-size*markdownMarkerLine/2)
+		default:
+			page.FillRectangle(cx-radius, cy-radius, 2*radius, 2*radius)
+		}
+	}
+	fmt.Fprintf(&page.content, "Q\n")
+	return page.SetAltText(alt)
+}
@@ -0,0 +1,111 @@
+package gopdf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNewMarkdownDocument_List(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		wantText  []string
+		structure string
+	}{
+		{
+			name:      "unordered",
+			markdown:  "- Apple\n- Banana\n",
+			wantText:  []string{"Apple", "Banana"},
+			structure: "Document(L(LI(Lbl LBody) LI(Lbl LBody)))",
+		},
+		{
+			// 番号は開始番号と区切り文字を引き継ぐ
+			name:      "ordered",
+			markdown:  "3) Three\n4) Four\n",
+			wantText:  []string{"3)", "Three", "4)", "Four"},
+			structure: "Document(L(LI(Lbl LBody) LI(Lbl LBody)))",
+		},
+		{
+			// 入れ子のリストは項目のLBodyに入る
+			name:      "nested",
+			markdown:  "1. One\n   - Sub\n     - Deeper\n2. Two\n\nAfter.\n",
+			wantText:  []string{"1.", "One", "Sub", "Deeper", "2.", "Two", "After."},
+			structure: "Document(L(LI(Lbl LBody(L(LI(Lbl LBody(L(LI(Lbl LBody))))))) LI(Lbl LBody)) P)",
+		},
+		{
+			// タスクの印はテキストに含めない
+			name:      "task",
+			markdown:  "- [ ] Todo\n- [x] Done\n",
+			wantText:  []string{"Todo", "Done"},
+			structure: "Document(L(LI(Lbl LBody) LI(Lbl LBody)))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := renderMarkdownForTest(t, tt.markdown)
+			text, err := r.ExtractPageText(0)
+			if err != nil {
+				t.Fatalf("ExtractPageText() failed: %v", err)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(text, want) {
+					t.Errorf("text = %q, want it to contain %q", text, want)
+				}
+			}
+			if strings.Contains(text, "[") {
+				t.Errorf("text = %q, want no task markers", text)
+			}
+			if got := structureString(readStructure(t, r)); got != tt.structure {
+				t.Errorf("structure = %s, want %s", got, tt.structure)
+			}
+		})
+	}
+}
+
+func TestNewMarkdownDocument_ListLayout(t *testing.T) {
+	doc, err := NewMarkdownDocument("- One\n  - Two\n    - Three\n- [x] Done\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	content := doc.pages[0].content.String()
+
+	// 1段ごとに24ポイント字下げする
+	for i, text := range []string{"One", "Two", "Three"} {
+		want := fmt.Sprintf("%.2f", 72+markdownListIndent*float64(i+1))
+		m := regexp.MustCompile(`([\d.]+) [\d.]+ Td\n\(` + text + `\) Tj`).FindStringSubmatch(content)
+		if m == nil || m[1] != want {
+			t.Errorf("%q is drawn at %v, want x = %s", text, m, want)
+		}
+	}
+	// 深さによって黒丸・白丸・四角の印を描き、完了したタスクにはチェックを描く
+	for _, op := range []string{"c\nf\n", "c\nS\n", " re\nf\n", " l S\n"} {
+		if !strings.Contains(content, op) {
+			t.Errorf("content does not contain %q", op)
+		}
+	}
+}
+
+func TestNewMarkdownDocument_ListWrap(t *testing.T) {
+	long := strings.TrimSpace(strings.Repeat("word ", 2000))
+	doc, err := NewMarkdownDocument("- "+long+"\n", nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	if len(doc.pages) < 2 {
+		t.Fatalf("got %d pages, want the item to continue on the next page", len(doc.pages))
+	}
+	// 長い項目は本文の幅に折り返す
+	for _, page := range doc.pages {
+		content := page.content.String()
+		for _, line := range strings.Split(content, "\n") {
+			if strings.HasSuffix(line, ") Tj") {
+				if width, _ := FontHelvetica.TextWidth(line[1:len(line)-4], 12); width > page.Width()-72-96 {
+					t.Errorf("line %q is wider than the content", line)
+				}
+			}
+		}
+	}
+}
@@ -17,6 +17,21 @@ type documentRenderer struct {
 	pageSize     PageSize
 	orientation  Orientation
 	imageBasePath string
+	title        string // document title: MarkdownOptions.Title or the text of the first H1
+
+	imageAlign        TextAlign // horizontal alignment of images and their captions
+	allowRemoteImages bool      // fetch http(s) images instead of drawing their alt text
+
+	indent float64         // left indentation of nested blocks such as list items and quotes
+	quotes []markdownQuote // open blockquotes, whose rules continue on the next page
+
+	generateTOC bool              // insert a table of contents and build the outline
+	tocTitle    string            // title of the table of contents
+	headings    []markdownHeading // H1-H3 headings, listed in the table of contents
+
+	fonts markdownFonts // TTF fonts of the kinds of text (nil fonts use the standard fonts)
+
+	header, footer MarkdownHeaderFooter // running header and footer templates
 }
 
 // newDocumentRenderer creates a new document renderer.
@@ -43,13 +58,30 @@ func (r *documentRenderer) render(root ast.Node) (*Document, error) {
 		return nil, err
 	}
 
+	if r.generateTOC && len(r.headings) > 0 {
+		if err := r.renderTOC(); err != nil {
+			return nil, err
+		}
+		r.buildOutline()
+	}
+	if err := r.drawHeadersFooters(); err != nil {
+		return nil, err
+	}
+
 	return r.doc, nil
 }
 
 // newPage creates a new page and resets the Y position.
+// The rules of open blockquotes are finished on the old page and restarted on the new one.
 func (r *documentRenderer) newPage() {
+	if r.currentPage != nil {
+		r.drawQuoteRules()
+	}
 	r.currentPage = r.doc.AddPage(r.pageSize, r.orientation)
 	r.currentY = r.currentPage.Height() - r.style.MarginTop
+	for i := range r.quotes {
+		r.quotes[i].top = r.currentY + r.style.BodySize
+	}
 }
 
 // checkPageBreak checks if we need a new page and creates one if necessary.
@@ -61,6 +93,19 @@ func (r *documentRenderer) checkPageBreak(requiredHeight float64) {
 
 // walkNode walks the AST recursively and renders nodes.
 func (r *documentRenderer) walkNode(node ast.Node) error {
+	// Tables are rendered as a whole, including the text of their cells
+	if table, ok := node.(*ast.Table); ok {
+		return r.renderTable(table)
+	}
+	// Lists are rendered as a whole, including their nested lists
+	if list, ok := node.(*ast.List); ok {
+		return r.renderList(list, 0)
+	}
+	// Blockquotes draw their rule after their blocks
+	if quote, ok := node.(*ast.BlockQuote); ok {
+		return r.renderBlockQuote(quote)
+	}
+
 	// Process current node
 	if err := r.renderNode(node); err != nil {
 		return err
@@ -85,6 +130,10 @@ func (r *documentRenderer) renderNode(node ast.Node) error {
 		return r.renderParagraph(n)
 	case *ast.Text:
 		return r.renderText(n)
+	case *ast.HorizontalRule:
+		return r.renderHorizontalRule()
+	case *ast.CodeBlock:
+		return r.renderCodeBlock(n)
 	case *ast.Softbreak, *ast.Hardbreak:
 		// Line breaks are handled by the parent node
 		return nil
@@ -126,17 +175,24 @@ func (r *documentRenderer) renderHeading(heading *ast.Heading) error {
 	r.checkPageBreak(fontSize + r.style.ParagraphSpacing)
 
 	// Set font and color
-	if err := r.currentPage.SetFont(FontHelveticaBold, fontSize); err != nil {
+	if err := r.headingFont().set(r.currentPage, fontSize); err != nil {
 		return fmt.Errorf("failed to set font: %w", err)
 	}
 	r.currentPage.SetFillColor(convertColor(r.style.HeadingColor))
 
-	// Extract text from children
-	text := r.extractText(heading)
+	// Extract text from children; line breaks (<br>) become spaces in a single-line heading
+	text := strings.ReplaceAll(r.extractText(heading), "\n", " ")
+
+	// The first top-level heading becomes the document title
+	if level == 1 && r.title == "" {
+		r.title = text
+	}
+	if level <= markdownTOCMaxLevel && strings.TrimSpace(text) != "" {
+		r.headings = append(r.headings, markdownHeading{level: level, text: text, page: r.currentPage, top: r.currentY + fontSize})
+	}
 
-	// Draw the heading
-	err := r.currentPage.DrawText(text, r.style.MarginLeft, r.currentY)
-	if err != nil {
+	// Draw the heading (tagged as H1-H6)
+	if err := r.drawTagged(headingStructureType(level), text); err != nil {
 		return fmt.Errorf("failed to draw heading: %w", err)
 	}
 
@@ -147,11 +203,36 @@ func (r *documentRenderer) renderHeading(heading *ast.Heading) error {
 }
 
 // renderParagraph renders a paragraph node.
+// Images in the paragraph are rendered as blocks after its text.
 func (r *documentRenderer) renderParagraph(para *ast.Paragraph) error {
 	// Extract text from children
-	text := r.extractText(para)
+	if err := r.drawParagraph(r.extractTextLinks(para)); err != nil {
+		return err
+	}
+	return r.renderParagraphImages(para)
+}
 
-	if text == "" {
+// renderParagraphImages renders the images in a paragraph as blocks.
+func (r *documentRenderer) renderParagraphImages(para *ast.Paragraph) error {
+	var images []*ast.Image
+	ast.WalkFunc(para, func(n ast.Node, entering bool) ast.WalkStatus {
+		if img, ok := n.(*ast.Image); ok && entering {
+			images = append(images, img)
+			return ast.SkipChildren
+		}
+		return ast.GoToNext
+	})
+	for _, img := range images {
+		if err := r.renderImage(img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawParagraph draws the text of a paragraph with its links and formatting, wrapped to the content width.
+func (r *documentRenderer) drawParagraph(text string, links []markdownLink, spans []markdownSpan) error {
+	if strings.TrimSpace(text) == "" {
 		return nil
 	}
 
@@ -159,25 +240,33 @@ func (r *documentRenderer) renderParagraph(para *ast.Paragraph) error {
 	estimatedHeight := r.style.BodySize * r.style.LineSpacing * 3 // Estimate 3 lines
 	r.checkPageBreak(estimatedHeight)
 
-	// Set font and color
-	if err := r.currentPage.SetFont(FontHelvetica, r.style.BodySize); err != nil {
-		return fmt.Errorf("failed to set font: %w", err)
+	if err := r.currentPage.BeginTag(StructP); err != nil {
+		return err
 	}
-	r.currentPage.SetFillColor(convertColor(r.style.TextColor))
-
-	// For now, draw as a single line
-	// TODO: Implement word wrapping for long paragraphs
-	err := r.currentPage.DrawText(text, r.style.MarginLeft, r.currentY)
-	if err != nil {
+	if err := r.drawInlineText(text, links, spans); err != nil {
 		return fmt.Errorf("failed to draw paragraph: %w", err)
 	}
+	if err := r.currentPage.EndTag(); err != nil {
+		return err
+	}
 
 	// Move Y position down
-	r.currentY -= r.style.BodySize * r.style.LineSpacing + r.style.ParagraphSpacing
+	r.currentY -= r.style.ParagraphSpacing
 
 	return nil
 }
 
+// drawTagged draws a line of text enclosed in a structure element.
+func (r *documentRenderer) drawTagged(typ StructureType, text string) error {
+	if err := r.currentPage.BeginTag(typ); err != nil {
+		return err
+	}
+	if err := r.currentPage.DrawText(text, r.contentLeft(), r.currentY); err != nil {
+		return err
+	}
+	return r.currentPage.EndTag()
+}
+
 // renderText renders a text node (usually handled by parent).
 func (r *documentRenderer) renderText(text *ast.Text) error {
 	// Text nodes are typically handled by their parent (paragraph, heading, etc.)
@@ -186,27 +275,90 @@ func (r *documentRenderer) renderText(text *ast.Text) error {
 }
 
 // extractText extracts all text content from a node and its children.
+// The alt text of images inside the node is skipped, since images are drawn as blocks.
 func (r *documentRenderer) extractText(node ast.Node) string {
-	var text strings.Builder
+	text, _, _ := r.extractTextLinks(node)
+	return text
+}
+
+// extractTextLinks extracts the text of a node like extractText, together with the links in it
+// that can be opened from a PDF (see isLinkDestination) and its formatted spans.
+// Reference links ([text][ref]) are resolved by the parser and are returned like inline links.
+// Emphasis and the supported inline HTML tags (see markdownInlineTags) become spans; <br> is a line break.
+func (r *documentRenderer) extractTextLinks(node ast.Node) (string, []markdownLink, []markdownSpan) {
+	var text markdownInlineText
+	var links []markdownLink
 
 	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		if link, ok := n.(*ast.Link); ok && isLinkDestination(string(link.Destination)) {
+			if entering {
+				links = append(links, markdownLink{start: text.text.Len(), uri: string(link.Destination)})
+			} else {
+				links[len(links)-1].end = text.text.Len()
+			}
+			return ast.GoToNext
+		}
+		switch n.(type) {
+		case *ast.Strong:
+			if entering {
+				text.push("**", func(f *markdownFormat) { f.bold = true })
+			} else {
+				text.pop("**")
+			}
+			return ast.GoToNext
+		case *ast.Emph:
+			if entering {
+				text.push("*", func(f *markdownFormat) { f.italic = true })
+			} else {
+				text.pop("*")
+			}
+			return ast.GoToNext
+		}
 		if !entering {
 			return ast.GoToNext
 		}
+		if _, ok := n.(*ast.Image); ok && n != node {
+			return ast.SkipChildren
+		}
 
 		switch t := n.(type) {
 		case *ast.Text:
-			text.Write(t.Literal)
+			text.write(string(t.Literal))
+		case *ast.Code:
+			text.write(string(t.Literal))
 		case *ast.Softbreak:
-			text.WriteString(" ")
+			text.write(" ")
 		case *ast.Hardbreak:
-			text.WriteString("\n")
+			text.write("\n")
+		case *ast.HTMLSpan:
+			text.html(string(t.Literal))
 		}
 
 		return ast.GoToNext
 	})
 
-	return text.String()
+	return text.text.String(), links, text.spans
+}
+
+// textWidth returns the width of text drawn in font at size.
+// TTF fonts are measured with their glyph widths.
+func (r *documentRenderer) textWidth(text string, font markdownFont, size float64) float64 {
+	if font.ttf != nil {
+		width, _ := font.ttf.TextWidth(text, size)
+		return width
+	}
+	width, _ := font.standard.TextWidth(text, size)
+	return width
+}
+
+// contentLeft returns the left edge of the content, after the margin and indentation.
+func (r *documentRenderer) contentLeft() float64 {
+	return r.style.MarginLeft + r.indent
+}
+
+// contentWidth returns the width between the indented left edge and the right margin.
+func (r *documentRenderer) contentWidth() float64 {
+	return r.currentPage.Width() - r.contentLeft() - r.style.MarginRight
 }
 
 // convertColor converts internal markdown Color to gopdf Color.
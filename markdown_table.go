@@ -0,0 +1,363 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/ryomak/gopdf/layout"
+)
+
+// Table layout settings (points).
+const (
+	markdownTableCellPadding = 4.0
+	markdownTableBorderWidth = 0.5
+)
+
+// markdownTableBorderColor is the color of the cell borders.
+var markdownTableBorderColor = Color{R: 0.6, G: 0.6, B: 0.6}
+
+// markdownTable is a GFM pipe table laid out for rendering.
+type markdownTable struct {
+	header []markdownTableRow // header rows, repeated at the top of each page the table continues on
+	body   []markdownTableRow
+	aligns []ast.CellAlignFlags // alignment of each column
+	widths []float64            // width of each column, including the cell padding
+}
+
+// markdownTableRow is a table row whose cells are wrapped into lines.
+type markdownTableRow struct {
+	cells  [][]string // wrapped lines of each column
+	header bool
+}
+
+// lineCount returns the number of lines of the tallest cell.
+func (row markdownTableRow) lineCount() int {
+	n := 1
+	for _, lines := range row.cells {
+		n = max(n, len(lines))
+	}
+	return n
+}
+
+// renderTable renders a GFM table.
+// Columns are sized to their content and shrunk to the content width, cells are wrapped,
+// header rows are shaded with CodeBackground, and rows that do not fit on the page continue
+// on the next one below a repeated header.
+func (r *documentRenderer) renderTable(node *ast.Table) error {
+	table := r.layoutTable(node)
+	if len(table.widths) == 0 {
+		return nil
+	}
+
+	// The table starts where the text of a line at the current baseline would start
+	r.currentY += r.style.BodySize
+	if err := r.currentPage.BeginTag(StructTable); err != nil {
+		return err
+	}
+	for _, group := range []struct {
+		typ  StructureType
+		rows []markdownTableRow
+	}{
+		{StructTHead, table.header},
+		{StructTBody, table.body},
+	} {
+		if len(group.rows) == 0 {
+			continue
+		}
+		if err := r.currentPage.BeginTag(group.typ); err != nil {
+			return err
+		}
+		for _, row := range group.rows {
+			if err := r.renderTableRow(table, row); err != nil {
+				return fmt.Errorf("failed to draw table row: %w", err)
+			}
+		}
+		if err := r.currentPage.EndTag(); err != nil {
+			return err
+		}
+	}
+	if err := r.currentPage.EndTag(); err != nil {
+		return err
+	}
+
+	r.currentY -= r.style.ParagraphSpacing + r.style.BodySize
+	return nil
+}
+
+// layoutTable collects the cells of a table, sizes its columns and wraps the cells.
+func (r *documentRenderer) layoutTable(node *ast.Table) markdownTable {
+	var table markdownTable
+	var texts [][]string
+	var headers []bool
+	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		row, ok := n.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		var cells []string
+		header := false
+		for i, child := range row.GetChildren() {
+			cell, ok := child.(*ast.TableCell)
+			if !ok {
+				continue
+			}
+			cells = append(cells, strings.TrimSpace(r.extractText(cell)))
+			header = header || cell.IsHeader
+			if i >= len(table.aligns) {
+				table.aligns = append(table.aligns, cell.Align)
+			}
+		}
+		texts = append(texts, cells)
+		headers = append(headers, header)
+		return ast.SkipChildren
+	})
+
+	columns := len(table.aligns)
+	if columns == 0 {
+		return table
+	}
+	natural := make([]float64, columns)
+	minimum := make([]float64, columns)
+	for i, cells := range texts {
+		font := r.tableFont(headers[i])
+		for col, text := range cells {
+			natural[col] = max(natural[col], r.textWidth(text, font, r.style.BodySize)+2*markdownTableCellPadding)
+			for _, word := range strings.Fields(text) {
+				minimum[col] = max(minimum[col], r.textWidth(word, font, r.style.BodySize)+2*markdownTableCellPadding)
+			}
+		}
+	}
+	table.widths = markdownColumnWidths(natural, minimum, r.contentWidth())
+
+	for i, cells := range texts {
+		font := r.tableFont(headers[i])
+		row := markdownTableRow{cells: make([][]string, columns), header: headers[i]}
+		for col, text := range cells {
+			if text == "" {
+				continue
+			}
+			row.cells[col] = layout.WrapText(text, table.widths[col]-2*markdownTableCellPadding, func(s string) float64 {
+				return r.textWidth(s, font, r.style.BodySize)
+			})
+		}
+		if row.header {
+			table.header = append(table.header, row)
+		} else {
+			table.body = append(table.body, row)
+		}
+	}
+	return table
+}
+
+// markdownColumnWidths fits columns into maxWidth.
+// natural is the width each column needs without wrapping and minimum the width of its longest word.
+// Columns keep their natural width when the table fits; otherwise each column gets its minimum width
+// plus a share of the remaining space proportional to how much wrapping shrinks it.
+// When even the minimum widths do not fit, they are scaled down to maxWidth.
+func markdownColumnWidths(natural, minimum []float64, maxWidth float64) []float64 {
+	var sumNatural, sumMinimum float64
+	minimum = slices.Clone(minimum)
+	for i := range natural {
+		minimum[i] = min(minimum[i], natural[i])
+		sumNatural += natural[i]
+		sumMinimum += minimum[i]
+	}
+
+	widths := make([]float64, len(natural))
+	switch {
+	case sumNatural <= maxWidth:
+		copy(widths, natural)
+	case sumMinimum >= maxWidth:
+		for i := range widths {
+			widths[i] = minimum[i] * maxWidth / sumMinimum
+		}
+	default:
+		ratio := (maxWidth - sumMinimum) / (sumNatural - sumMinimum)
+		for i := range widths {
+			widths[i] = minimum[i] + (natural[i]-minimum[i])*ratio
+		}
+	}
+	return widths
+}
+
+// renderTableRow draws a row below the current position.
+// A row taller than the space left on the page moves to the next page when it fits there,
+// and is otherwise split between the pages line by line.
+func (r *documentRenderer) renderTableRow(table markdownTable, row markdownTableRow) error {
+	if err := r.currentPage.BeginTag(StructTR); err != nil {
+		return err
+	}
+
+	lineHeight := r.style.BodySize * r.style.LineSpacing
+	lines := row.lineCount()
+	// One TH or TD per cell, continued by each part of a row split across pages
+	cells := make([]*structElement, len(table.widths))
+	fresh := r.atPageTop() // nothing but the repeated header is above the row on this page
+	for start := 0; ; {
+		available := r.currentY - r.style.MarginBottom - 2*markdownTableCellPadding
+		if float64(lines-start)*lineHeight <= available {
+			if err := r.drawTableRow(table, row, start, lines, cells); err != nil {
+				return err
+			}
+			break
+		}
+
+		fit := int(math.Floor(available / lineHeight))
+		if fit >= 1 && (start > 0 || fresh || float64(lines)*lineHeight > r.tablePageSpace(table, row)) {
+			if err := r.drawTableRow(table, row, start, start+fit, cells); err != nil {
+				return err
+			}
+			start += fit
+		} else if fit < 1 && fresh {
+			// Not even one line fits below the margin: draw it anyway to make progress
+			if err := r.drawTableRow(table, row, start, start+1, cells); err != nil {
+				return err
+			}
+			start++
+			if start == lines {
+				break
+			}
+		}
+
+		r.newPage()
+		if !row.header {
+			if err := r.repeatTableHeader(table); err != nil {
+				return err
+			}
+		}
+		fresh = true
+	}
+
+	return r.currentPage.EndTag()
+}
+
+// tablePageSpace returns the height available to a row's lines on a new page, below the repeated header.
+func (r *documentRenderer) tablePageSpace(table markdownTable, row markdownTableRow) float64 {
+	space := r.currentPage.Height() - r.style.MarginTop - r.style.MarginBottom - 2*markdownTableCellPadding
+	if !row.header {
+		lineHeight := r.style.BodySize * r.style.LineSpacing
+		for _, h := range table.header {
+			space -= float64(h.lineCount())*lineHeight + 2*markdownTableCellPadding
+		}
+	}
+	return space
+}
+
+// atPageTop reports whether nothing has been drawn on the current page yet.
+func (r *documentRenderer) atPageTop() bool {
+	return r.currentY >= r.currentPage.Height()-r.style.MarginTop
+}
+
+// repeatTableHeader draws the header rows again at the top of a page the table continues on.
+// The copy is an artifact, so the header appears only once in the structure tree.
+func (r *documentRenderer) repeatTableHeader(table markdownTable) error {
+	return r.currentPage.artifact(func() error {
+		for _, row := range table.header {
+			if err := r.drawTableRowLines(table, row, 0, row.lineCount(), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// drawTableRow draws lines [start, end) of each cell of a row as tagged cells.
+// cells holds the structure element of each cell: nil before the first part of the row, which opens them.
+func (r *documentRenderer) drawTableRow(table markdownTable, row markdownTableRow, start, end int, cells []*structElement) error {
+	return r.drawTableRowLines(table, row, start, end, cells)
+}
+
+// drawTableRowLines draws lines [start, end) of each cell of a row and moves the position below it.
+// The shading and borders are artifacts; the text of each cell is tagged as TH or TD when cells is not nil.
+// A cell whose element in cells is already open continues it, so a row split across pages keeps one element per cell.
+func (r *documentRenderer) drawTableRowLines(table markdownTable, row markdownTableRow, start, end int, cells []*structElement) error {
+	page := r.currentPage
+	size := r.style.BodySize
+	lineHeight := size * r.style.LineSpacing
+	height := float64(end-start)*lineHeight + 2*markdownTableCellPadding
+	top := r.currentY
+	left := r.contentLeft()
+
+	var width float64
+	for _, w := range table.widths {
+		width += w
+	}
+	err := page.artifact(func() error {
+		fmt.Fprintf(&page.content, "q\n")
+		if row.header {
+			page.SetFillColor(convertColor(r.style.CodeBackground))
+			page.FillRectangle(left, top-height, width, height)
+		}
+		page.SetLineWidth(markdownTableBorderWidth)
+		page.SetStrokeColor(markdownTableBorderColor)
+		x := left
+		for _, w := range table.widths {
+			page.DrawRectangle(x, top-height, w, height)
+			x += w
+		}
+		fmt.Fprintf(&page.content, "Q\n")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	font := r.tableFont(row.header)
+	if err := font.set(page, size); err != nil {
+		return fmt.Errorf("failed to set font: %w", err)
+	}
+	page.SetFillColor(convertColor(r.style.TextColor))
+
+	cellType := StructTD
+	if row.header {
+		cellType = StructTH
+	}
+	x := left
+	for col, w := range table.widths {
+		lines := row.cells[col][min(start, len(row.cells[col])):min(end, len(row.cells[col]))]
+		if cells != nil {
+			if cells[col] == nil {
+				if err := page.BeginTag(cellType); err != nil {
+					return err
+				}
+				cells[col] = page.doc.structure.current()
+			} else if err := page.resumeTag(cells[col]); err != nil {
+				return err
+			}
+		}
+		for i, line := range lines {
+			lineX := x + markdownTableCellPadding
+			free := w - 2*markdownTableCellPadding - r.textWidth(line, font, size)
+			switch table.aligns[col] {
+			case ast.TableAlignmentRight:
+				lineX += free
+			case ast.TableAlignmentCenter:
+				lineX += free / 2
+			}
+			baseline := top - markdownTableCellPadding - float64(i)*lineHeight - size
+			if err := page.DrawText(line, lineX, baseline); err != nil {
+				return err
+			}
+		}
+		if cells != nil {
+			if err := page.EndTag(); err != nil {
+				return err
+			}
+		}
+		x += w
+	}
+
+	r.currentY -= height
+	return nil
+}
+
+// tableFont returns the font of header or body cells.
+func (r *documentRenderer) tableFont(header bool) markdownFont {
+	if header {
+		return r.headingFont()
+	}
+	return r.bodyFont()
+}
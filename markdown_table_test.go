@@ -0,0 +1,163 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// renderMarkdownForTest はMarkdownをPDFにして読み込む
+func renderMarkdownForTest(t *testing.T, markdown string) *PDFReader {
+	t.Helper()
+	doc, err := NewMarkdownDocument(markdown, nil)
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// structureString は構造ツリーを "Table(TR(TD TD))" の形の文字列にする
+func structureString(node structNode) string {
+	if len(node.kids) == 0 {
+		return node.typ
+	}
+	kids := make([]string, len(node.kids))
+	for i, kid := range node.kids {
+		kids[i] = structureString(kid)
+	}
+	return node.typ + "(" + strings.Join(kids, " ") + ")"
+}
+
+func TestMarkdownColumnWidths(t *testing.T) {
+	tests := []struct {
+		name     string
+		natural  []float64
+		minimum  []float64
+		maxWidth float64
+		want     []float64
+	}{
+		{
+			name:     "fits",
+			natural:  []float64{100, 50},
+			minimum:  []float64{40, 30},
+			maxWidth: 400,
+			want:     []float64{100, 50},
+		},
+		{
+			// 残りの100を、折り返しで縮む量（200と100）の比で分ける
+			name:     "wraps",
+			natural:  []float64{240, 140},
+			minimum:  []float64{40, 40},
+			maxWidth: 180,
+			want:     []float64{40 + 200.0/3, 40 + 100.0/3},
+		},
+		{
+			name:     "minimum overflows",
+			natural:  []float64{300, 300},
+			minimum:  []float64{150, 50},
+			maxWidth: 100,
+			want:     []float64{75, 25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := markdownColumnWidths(tt.natural, tt.minimum, tt.maxWidth)
+			for i := range tt.want {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Fatalf("widths = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMarkdownDocument_Table(t *testing.T) {
+	r := renderMarkdownForTest(t, "# Prices\n\n| Item | Price |\n|:-----|------:|\n| Apple | 100 |\n| `Banana` | 80 |\n\nAfter the table.\n")
+
+	text, err := r.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText() failed: %v", err)
+	}
+	for _, want := range []string{"Item", "Price", "Apple", "100", "Banana", "80", "After the table."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text = %q, want it to contain %q", text, want)
+		}
+	}
+
+	got := structureString(readStructure(t, r))
+	want := "Document(H1 Table(THead(TR(TH TH)) TBody(TR(TD TD) TR(TD TD))) P)"
+	if got != want {
+		t.Errorf("structure = %s, want %s", got, want)
+	}
+}
+
+func TestNewMarkdownDocument_TablePageBreak(t *testing.T) {
+	var md strings.Builder
+	md.WriteString("| Item | Note |\n|---|---|\n")
+	for i := range 80 {
+		fmt.Fprintf(&md, "| row%d | note |\n", i)
+	}
+	// 1ページに収まらない長いセルは、行ごとに次のページへ分ける
+	fmt.Fprintf(&md, "| long | %s |\n", strings.TrimSpace(strings.Repeat("word ", 800)))
+
+	r := renderMarkdownForTest(t, md.String())
+	if r.PageCount() < 3 {
+		t.Fatalf("PageCount() = %d, want at least 3", r.PageCount())
+	}
+	for i := range r.PageCount() {
+		text, err := r.ExtractPageText(i)
+		if err != nil {
+			t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+		}
+		// 表が続くページには見出しを繰り返す
+		if !strings.Contains(text, "Item") {
+			t.Errorf("page %d does not repeat the header: %q", i, text)
+		}
+	}
+	last, _ := r.ExtractPageText(r.PageCount() - 1)
+	if !strings.Contains(last, "word") {
+		t.Errorf("last page = %q, want the rest of the long cell", last)
+	}
+
+	// 繰り返した見出しはアーティファクトで、構造ツリーの見出しは1つ
+	structure := structureString(readStructure(t, r))
+	if got := strings.Count(structure, "TH ") + strings.Count(structure, "TH)"); got != 2 {
+		t.Errorf("structure has %d TH, want 2", got)
+	}
+}
+
+func TestNewMarkdownDocument_TableRowSplitStructure(t *testing.T) {
+	// 2ページ以上にまたがるセルのある行
+	md := fmt.Sprintf("| Item | Note |\n|---|---|\n| long | %s |\n| next | note |\n", strings.TrimSpace(strings.Repeat("word ", 800)))
+	r := renderMarkdownForTest(t, md)
+	if r.PageCount() < 2 {
+		t.Fatalf("PageCount() = %d, want the row split across pages", r.PageCount())
+	}
+
+	// ページをまたいでもセルの要素は1つで、各TRの子は列の数
+	want := "Document(Table(THead(TR(TH TH)) TBody(TR(TD TD) TR(TD TD))))"
+	if got := structureString(readStructure(t, r)); got != want {
+		t.Errorf("structure = %s, want %s", got, want)
+	}
+	table := readStructure(t, r).kids[0]
+	cell := table.kids[1].kids[0].kids[1]
+	pages := make(map[int]bool)
+	for _, page := range cell.pages {
+		pages[page] = true
+	}
+	if len(pages) < 2 {
+		t.Errorf("long cell has content on pages %v, want several pages", cell.pages)
+	}
+}
@@ -0,0 +1,185 @@
+package gopdf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Table of contents layout.
+const (
+	markdownTOCMaxLevel  = 3  // deepest heading level in the table of contents and the outline
+	markdownTOCIndent    = 16 // indentation of an entry per heading level
+	markdownTOCLeaderGap = 4  // space between the dot leaders and the text around them
+	markdownTOCTitle     = "Contents"
+)
+
+// markdownHeading is a heading listed in the table of contents and the outline.
+type markdownHeading struct {
+	level int
+	text  string
+	page  *Page
+	top   float64 // top of the heading, shown at the top of the window when the heading is opened
+}
+
+// renderTOC draws the table of contents on new pages and moves them to the front of the document.
+// Each entry shows the page number of its heading and links to it.
+// The title and the entries (TOC with a TOCI per heading) are moved to the front of the structure tree.
+func (r *documentRenderer) renderTOC() error {
+	doc := r.doc
+	contentPages := len(doc.pages)
+	tocPages := r.tocPageCount()
+	pageNumbers := make(map[*Page]int, contentPages)
+	for i, page := range doc.pages {
+		pageNumbers[page] = tocPages + i + 1
+	}
+	tree := doc.structureTree()
+	contentElems := len(tree.root.kids)
+
+	r.newPage()
+	title := r.tocTitle
+	if title == "" {
+		title = markdownTOCTitle
+	}
+	if err := r.headingFont().set(r.currentPage, r.style.H2Size); err != nil {
+		return fmt.Errorf("failed to set font: %w", err)
+	}
+	r.currentPage.SetFillColor(convertColor(r.style.HeadingColor))
+	if err := r.drawTagged(StructH1, title); err != nil {
+		return fmt.Errorf("failed to draw table of contents: %w", err)
+	}
+	r.currentY -= r.style.H2Size + r.style.ParagraphSpacing
+
+	if err := r.currentPage.BeginTag(StructTOC); err != nil {
+		return err
+	}
+	lineHeight := r.style.BodySize * r.style.LineSpacing
+	for _, heading := range r.headings {
+		r.checkPageBreak(lineHeight)
+		if err := r.drawTOCEntry(heading, pageNumbers[heading.page]); err != nil {
+			return fmt.Errorf("failed to draw table of contents: %w", err)
+		}
+		r.currentY -= lineHeight
+	}
+	if err := r.currentPage.EndTag(); err != nil {
+		return err
+	}
+
+	doc.pages = slices.Concat(doc.pages[contentPages:], doc.pages[:contentPages])
+	tree.root.kids = slices.Concat(tree.root.kids[contentElems:], tree.root.kids[:contentElems])
+	return nil
+}
+
+// tocPageCount returns the number of pages renderTOC needs, laying out the entries the same way.
+func (r *documentRenderer) tocPageCount() int {
+	top := r.currentPage.Height() - r.style.MarginTop
+	lineHeight := r.style.BodySize * r.style.LineSpacing
+	y := top - r.style.H2Size - r.style.ParagraphSpacing
+	pages := 1
+	for range r.headings {
+		if y-lineHeight < r.style.MarginBottom {
+			pages++
+			y = top
+		}
+		y -= lineHeight
+	}
+	return pages
+}
+
+// drawTOCEntry draws a line of the table of contents: the heading indented by its level,
+// dot leaders (an artifact) and the page number at the right margin.
+// The whole line links to the heading.
+func (r *documentRenderer) drawTOCEntry(heading markdownHeading, pageNumber int) error {
+	page := r.currentPage
+	size := r.style.BodySize
+	font := r.bodyFont()
+	if heading.level == 1 {
+		font = r.headingFont()
+	}
+	x := r.contentLeft() + float64(heading.level-1)*markdownTOCIndent
+	right := page.Width() - r.style.MarginRight
+	number := strconv.Itoa(pageNumber)
+	numberX := right - r.textWidth(number, font, size)
+	text := r.fitText(heading.text, font, size, numberX-x-2*markdownTOCLeaderGap)
+
+	if err := font.set(page, size); err != nil {
+		return err
+	}
+	page.SetFillColor(convertColor(r.style.TextColor))
+	if err := page.BeginTag(StructTOCI); err != nil {
+		return err
+	}
+	if err := page.BeginTag(StructLink); err != nil {
+		return err
+	}
+	if err := page.DrawText(text, x, r.currentY); err != nil {
+		return err
+	}
+	err := page.artifact(func() error {
+		dot := r.textWidth(".", font, size)
+		start := x + r.textWidth(text, font, size) + markdownTOCLeaderGap
+		end := numberX - markdownTOCLeaderGap
+		if n := int((end - start) / dot); n > 0 {
+			return page.DrawText(strings.Repeat(".", n), end-float64(n)*dot, r.currentY)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := page.DrawText(number, numberX, r.currentY); err != nil {
+		return err
+	}
+	err = page.AddLink(Link{
+		X:           x,
+		Y:           r.currentY - size*markdownLinkDescent,
+		Width:       right - x,
+		Height:      size * markdownLinkHeight,
+		Page:        heading.page,
+		Top:         heading.top,
+		Description: heading.text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add link: %w", err)
+	}
+	if err := page.EndTag(); err != nil {
+		return err
+	}
+	return page.EndTag()
+}
+
+// fitText shortens text with "..." so that it fits in width.
+func (r *documentRenderer) fitText(text string, font markdownFont, size, width float64) string {
+	if r.textWidth(text, font, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && r.textWidth(string(runes)+"...", font, size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRightFunc(string(runes), func(c rune) bool { return c == ' ' }) + "..."
+}
+
+// buildOutline adds the headings to the document outline.
+// H2 headings are nested under the preceding H1, H3 headings under the preceding H2 (or H1).
+func (r *documentRenderer) buildOutline() {
+	var parents [markdownTOCMaxLevel]*OutlineItem
+	for _, heading := range r.headings {
+		var parent *OutlineItem
+		for level := heading.level - 1; level >= 1 && parent == nil; level-- {
+			parent = parents[level-1]
+		}
+
+		var item *OutlineItem
+		if parent == nil {
+			item = r.doc.AddOutline(heading.text, heading.page, heading.top)
+		} else {
+			item = parent.AddChild(heading.text, heading.page, heading.top)
+		}
+		parents[heading.level-1] = item
+		for level := heading.level; level < markdownTOCMaxLevel; level++ {
+			parents[level] = nil
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// renderMarkdownTOCForTest は目次付きでMarkdownをPDFに変換し、読み込んだReaderを返す
+func renderMarkdownTOCForTest(t *testing.T, markdown string) *PDFReader {
+	t.Helper()
+	doc, err := NewMarkdownDocument(markdown, &MarkdownOptions{Mode: MarkdownModeDocument, GenerateTOC: true})
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestNewMarkdownDocument_TOC(t *testing.T) {
+	var md strings.Builder
+	md.WriteString("# Guide\n\nIntroduction.\n\n## Install\n\n")
+	for i := range 30 {
+		fmt.Fprintf(&md, "Step %d.\n\n", i+1)
+	}
+	md.WriteString("### Linux\n\n#### Details\n\n## Usage\n\nRun it.\n")
+	r := renderMarkdownTOCForTest(t, md.String())
+
+	// 目次のページが先頭に入る
+	toc, err := r.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText() failed: %v", err)
+	}
+	for _, text := range []string{"Contents", "Guide", "Install", "Linux", "Usage"} {
+		if !strings.Contains(toc, text) {
+			t.Errorf("table of contents does not contain %q:\n%s", text, toc)
+		}
+	}
+	// H4以下の見出しは載せない
+	if strings.Contains(toc, "Details") {
+		t.Errorf("table of contents contains the H4 heading:\n%s", toc)
+	}
+
+	// 各項目は見出しのあるページへのリンクで、表示するページ番号と一致する
+	annots, err := r.ExtractPageAnnotations(0)
+	if err != nil {
+		t.Fatalf("ExtractPageAnnotations() failed: %v", err)
+	}
+	wantPages := map[string]int{"Guide": 1, "Install": 1, "Linux": 2, "Usage": 2}
+	if len(annots) != len(wantPages) {
+		t.Fatalf("got %d links, want %d", len(annots), len(wantPages))
+	}
+	for _, annot := range annots {
+		want, ok := wantPages[annot.Contents]
+		if !ok {
+			t.Errorf("unexpected link %q", annot.Contents)
+			continue
+		}
+		if annot.Destination == nil || annot.Destination.PageNum != want {
+			t.Errorf("link %q: destination = %+v, want page %d", annot.Contents, annot.Destination, want)
+			continue
+		}
+		if !strings.Contains(toc, fmt.Sprint(want+1)) {
+			t.Errorf("link %q: page number %d is not shown", annot.Contents, want+1)
+		}
+		page, err := r.ExtractPageText(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(page, annot.Contents) {
+			t.Errorf("link %q: page %d does not contain the heading", annot.Contents, want)
+		}
+	}
+
+	// 目次の見出しと項目は論理構造の先頭に入る
+	structure := structureString(readStructure(t, r))
+	if want := "Document(H1 TOC(TOCI(Link) TOCI(Link) TOCI(Link) TOCI(Link)) H1 P H2 "; !strings.HasPrefix(structure, want) {
+		t.Errorf("structure = %s, want prefix %s", structure, want)
+	}
+
+	// しおりはH1の下にH2、H2の下にH3を入れる
+	var got []string
+	var walk func(nodes []outlineNode, depth int)
+	walk = func(nodes []outlineNode, depth int) {
+		for _, node := range nodes {
+			got = append(got, fmt.Sprintf("%d:%s@%d", depth, node.title, node.page))
+			walk(node.children, depth+1)
+		}
+	}
+	walk(readOutlines(t, r), 0)
+	want := []string{"0:Guide@1", "1:Install@1", "2:Linux@2", "1:Usage@2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outlines = %v, want %v", got, want)
+	}
+}
+
+func TestNewMarkdownDocument_TOCPages(t *testing.T) {
+	// 目次が複数ページにわたる場合も、ページ番号は目次のページ数を含めて数える
+	var md strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&md, "## Section %d\n\nText.\n\n", i+1)
+	}
+	r := renderMarkdownTOCForTest(t, md.String())
+
+	tocPages := 0
+	for {
+		text, err := r.ExtractPageText(tocPages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(text, "...") {
+			break
+		}
+		tocPages++
+	}
+	if tocPages < 2 {
+		t.Fatalf("table of contents has %d pages, want at least 2", tocPages)
+	}
+
+	for page := range tocPages {
+		annots, err := r.ExtractPageAnnotations(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, annot := range annots {
+			if annot.Destination == nil || annot.Destination.PageNum < tocPages {
+				t.Errorf("link %q: destination = %+v, want a content page", annot.Contents, annot.Destination)
+				continue
+			}
+			text, err := r.ExtractPageText(annot.Destination.PageNum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(text, annot.Contents+" Text.") {
+				t.Errorf("link %q: page %d does not contain the heading", annot.Contents, annot.Destination.PageNum)
+			}
+		}
+	}
+}
+
+func TestNewMarkdownDocument_NoTOC(t *testing.T) {
+	r := renderMarkdownForTest(t, "# Guide\n\n## Install\n")
+	if r.PageCount() != 1 {
+		t.Errorf("page count = %d, want 1", r.PageCount())
+	}
+	if got := readOutlines(t, r); got != nil {
+		t.Errorf("outlines = %+v, want none", got)
+	}
+}
+
+func TestDocumentRenderer_FitText(t *testing.T) {
+	r := newDocumentRenderer(PageSizeA4, Portrait, nil, "")
+	tests := []struct {
+		name  string
+		text  string
+		width float64
+		want  string
+	}{
+		{name: "fits", text: "Install", width: 100, want: "Install"},
+		{name: "shortened", text: "Install the command line tools", width: 95, want: "Install th..."},
+		{name: "trailing space", text: "Run it now", width: 47, want: "Run..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.fitText(tt.text, r.bodyFont(), 12, tt.width)
+			if got != tt.want {
+				t.Errorf("fitText() = %q, want %q", got, tt.want)
+			}
+			if width := r.textWidth(got, r.bodyFont(), 12); width > tt.width {
+				t.Errorf("width = %g, want at most %g", width, tt.width)
+			}
+		})
+	}
+}
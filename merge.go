@@ -0,0 +1,230 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// pdfImport は1回のAppendPDFでページを追加した元のPDF
+// 同じ元のPDFのページが共有するフォントや画像などのオブジェクトは、一度だけ出力する
+type pdfImport struct {
+	src   *reader.Reader
+	prune bool // ページのコンテンツが使わないリソースを出力しない
+}
+
+// sourcePage は既存のPDFから追加したページ
+type sourcePage struct {
+	pdf  *pdfImport
+	ref  *core.Reference // 元のPageオブジェクト
+	dict core.Dictionary // 継承可能な属性を補った元のPage辞書
+}
+
+// droppedPageKeys は追加したページを出力するときに引き継がないPage辞書のキー
+// /Parentは出力側のページツリーに置き換え、論理構造（/StructParents）とアーティクル（/B）は元の文書にしかないため除く
+var droppedPageKeys = []core.Name{"Parent", "StructParents", "B"}
+
+// Merge は複数のPDFを順に連結し、1つのPDFとして書き出す
+// 各ページはフォント・画像・注釈などのリソースごと複製される（AppendPDFを参照）
+func Merge(out io.Writer, inputs ...io.Reader) error {
+	doc := New()
+	for i, in := range inputs {
+		rs, ok := in.(io.ReadSeeker)
+		if !ok {
+			data, err := io.ReadAll(in)
+			if err != nil {
+				return fmt.Errorf("failed to read input %d: %w", i, err)
+			}
+			rs = bytes.NewReader(data)
+		}
+
+		r, err := OpenReader(rs)
+		if err != nil {
+			return fmt.Errorf("failed to open input %d: %w", i, err)
+		}
+		if err := doc.AppendPDF(r); err != nil {
+			return fmt.Errorf("failed to append input %d: %w", i, err)
+		}
+	}
+	return doc.WriteTo(out)
+}
+
+// AppendPDF は読み込んだPDFの全ページを文書の末尾に追加する
+// ページが使うフォント・画像・注釈などのオブジェクトは参照を辿って複製し、オブジェクト番号はWriteToで振り直す
+// 注釈の/Pなど、追加したページへの参照は出力側のページに置き換える
+// 複製はWriteToで行うため、rはWriteToが終わるまで閉じないこと
+// 設計書: docs/merge_design.md
+func (d *Document) AppendPDF(r *PDFReader) error {
+	refs, err := r.r.GetPageReferences()
+	if err != nil {
+		return fmt.Errorf("failed to get pages: %w", err)
+	}
+
+	pageNums := make([]int, len(refs))
+	for i := range refs {
+		pageNums[i] = i
+	}
+	return d.appendPages(r, refs, pageNums, false)
+}
+
+// appendPages は元のPDFのpageNumsのページ（0-indexed、refsはGetPageReferencesの結果）を順に文書の末尾に追加する
+// 同じページが2回以上現れた場合は、番号の対応付けが衝突しないよう別の追加元として扱う
+// pruneがtrueの場合は、ページのコンテンツが使わないリソースを出力しない
+func (d *Document) appendPages(r *PDFReader, refs []*core.Reference, pageNums []int, prune bool) error {
+	if r.r.IsEncrypted() && !r.r.IsAuthenticated() {
+		return fmt.Errorf("PDF is encrypted: authenticate with a password before copying its pages")
+	}
+
+	imp := &pdfImport{src: r.r, prune: prune}
+	seen := make(map[int]bool)
+	pages := make([]*Page, 0, len(pageNums))
+	for _, pageNum := range pageNums {
+		if pageNum < 0 || pageNum >= len(refs) {
+			return fmt.Errorf("page number %d out of range [0, %d)", pageNum, len(refs))
+		}
+		if seen[pageNum] {
+			imp = &pdfImport{src: r.r, prune: prune}
+			seen = make(map[int]bool)
+		}
+		seen[pageNum] = true
+
+		ref := refs[pageNum]
+		dict, err := r.r.GetPageByReference(ref)
+		if err != nil {
+			return fmt.Errorf("failed to read page %d: %w", pageNum, err)
+		}
+		mediaBox := r.pageBoxes(dict).MediaBox
+		pages = append(pages, &Page{
+			width:  mediaBox.Width,
+			height: mediaBox.Height,
+			doc:    d,
+			source: &sourcePage{pdf: imp, ref: ref, dict: dict},
+		})
+	}
+
+	d.pages = append(d.pages, pages...)
+	return nil
+}
+
+// newImportCopiers は追加元のPDFごとにobjectCopierを作成する（順序は最初にページが現れた順）
+// 追加したページは予約済みのページの番号に対応付け、元のPDFのそれ以外のページとページツリーへの参照はnullにする
+// ページに描画した取り込んだページ（ImportPage）の元のPDFも含める
+func (d *Document) newImportCopiers(w *writer.Writer, pageRefs []*core.Reference) ([]*pdfImport, map[*pdfImport]*objectCopier, error) {
+	var order []*pdfImport
+	copiers := make(map[*pdfImport]*objectCopier)
+	copier := func(imp *pdfImport) (*objectCopier, error) {
+		if c, ok := copiers[imp]; ok {
+			return c, nil
+		}
+		c := newObjectCopier(imp.src, w)
+		c.skip = make(map[int]bool)
+		if err := skipPageTree(imp.src, c.skip); err != nil {
+			return nil, err
+		}
+		copiers[imp] = c
+		order = append(order, imp)
+		return c, nil
+	}
+
+	for i, page := range d.pages {
+		for _, tpl := range page.templates {
+			if _, err := copier(tpl.source.pdf); err != nil {
+				return nil, nil, err
+			}
+		}
+		if page.source == nil {
+			continue
+		}
+		c, err := copier(page.source.pdf)
+		if err != nil {
+			return nil, nil, err
+		}
+		delete(c.skip, page.source.ref.ObjectNumber)
+		c.mapping[page.source.ref.ObjectNumber] = pageRefs[i].ObjectNumber
+	}
+	return order, copiers, nil
+}
+
+// skipPageTree は元のPDFのページツリーのルートと全ページのオブジェクト番号をskipに加える
+func skipPageTree(src *reader.Reader, skip map[int]bool) error {
+	catalog, err := src.GetCatalog()
+	if err != nil {
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+	if pages, ok := catalog[core.Name("Pages")].(*core.Reference); ok {
+		skip[pages.ObjectNumber] = true
+	}
+
+	refs, err := src.GetPageReferences()
+	if err != nil {
+		return fmt.Errorf("failed to get pages: %w", err)
+	}
+	for _, ref := range refs {
+		skip[ref.ObjectNumber] = true
+	}
+	return nil
+}
+
+// writeImportedPage は既存のPDFから追加したページを、親をparentに置き換えて出力する
+// annots は元の/Annotsの後ろに追加する注釈（署名フィールドのウィジェットなど）
+func writeImportedPage(w *writer.Writer, c *objectCopier, page *sourcePage, pageRef, parent *core.Reference, annots core.Array) error {
+	dict := make(core.Dictionary, len(page.dict))
+	for k, v := range page.dict {
+		dict[k] = v
+	}
+	for _, key := range droppedPageKeys {
+		delete(dict, key)
+	}
+	if page.pdf.prune {
+		if resources, ok := prunePageResources(c.src, page.dict); ok {
+			dict[core.Name("Resources")] = resources
+		}
+	}
+	// 注釈を追加する場合は、/Annotsを間接参照ではなく配列で持たせる
+	if len(annots) > 0 {
+		existing, _ := c.src.Resolve(dict[core.Name("Annots")]).(core.Array)
+		dict[core.Name("Annots")] = existing
+	}
+
+	pageDict, ok := c.copyObject(dict).(core.Dictionary)
+	if !ok {
+		return fmt.Errorf("page is not a dictionary")
+	}
+	pageDict[core.Name("Parent")] = parent
+	if len(annots) > 0 {
+		existing, _ := pageDict[core.Name("Annots")].(core.Array)
+		pageDict[core.Name("Annots")] = append(existing, annots...)
+	}
+
+	return w.WriteObject(pageRef.ObjectNumber, pageDict)
+}
+
+// importedFieldRefs は追加元のPDFのフォームフィールドのうち、複製したものの参照を返す
+// 追加したページにウィジェットがあるフィールドだけが複製されている（flushの後に呼ぶ）
+func importedFieldRefs(c *objectCopier) []formFieldRef {
+	catalog, err := c.src.GetCatalog()
+	if err != nil {
+		return nil
+	}
+	acroForm, ok := c.src.Resolve(catalog[core.Name("AcroForm")]).(core.Dictionary)
+	if !ok {
+		return nil
+	}
+	fields, _ := c.src.Resolve(acroForm[core.Name("Fields")]).(core.Array)
+
+	var refs []formFieldRef
+	for _, field := range fields {
+		ref, ok := field.(*core.Reference)
+		if !ok {
+			continue
+		}
+		if num, ok := c.mapping[ref.ObjectNumber]; ok {
+			refs = append(refs, formFieldRef{ref: &core.Reference{ObjectNumber: num}})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,248 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// mergeSourcePDF はページツリーから/Resourcesと/MediaBoxを継承する2ページのPDFを作成する
+// 1ページ目には2ページ目へのリンク注釈がある
+func mergeSourcePDF() []byte {
+	first := "BT /F1 12 Tf 20 150 Td (First) Tj ET"
+	second := "BT /F1 12 Tf 20 150 Td (Second) Tj ET"
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 300 200] /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R /Annots [8 0 R] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 7 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(first), first),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(second), second),
+		"<< /Type /Annot /Subtype /Link /Rect [20 140 80 165] /P 3 0 R /Dest [4 0 R /Fit] >>",
+	})
+}
+
+// mergeGeneratedPDF は画像とテキストフィールドのある1ページのPDFを作成する
+func mergeGeneratedPDF(t *testing.T) []byte {
+	t.Helper()
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	img, err := LoadPNG(&pngData)
+	if err != nil {
+		t.Fatalf("LoadPNG failed: %v", err)
+	}
+
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatalf("SetFont failed: %v", err)
+	}
+	if err := page.DrawText("Generated", 50, 700); err != nil {
+		t.Fatalf("DrawText failed: %v", err)
+	}
+	if err := page.DrawImage(img, 50, 500, 100, 100); err != nil {
+		t.Fatalf("DrawImage failed: %v", err)
+	}
+	if err := page.AddTextField(TextField{Name: "name", X: 50, Y: 400, Width: 200, Height: 20, Value: "Taro"}); err != nil {
+		t.Fatalf("AddTextField failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMerge(t *testing.T) {
+	var out bytes.Buffer
+	if err := Merge(&out, bytes.NewReader(mergeSourcePDF()), bytes.NewBuffer(mergeGeneratedPDF(t))); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open merged PDF: %v", err)
+	}
+	defer reader.Close()
+
+	if got := reader.PageCount(); got != 3 {
+		t.Fatalf("PageCount = %d, want 3", got)
+	}
+
+	tests := []struct {
+		page       int
+		wantText   string
+		wantWidth  float64
+		wantImages int
+	}{
+		{page: 0, wantText: "First", wantWidth: 300},
+		{page: 1, wantText: "Second", wantWidth: 300},
+		{page: 2, wantText: "Generated", wantWidth: PageSizeA4.Width, wantImages: 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("page %d", tt.page), func(t *testing.T) {
+			text, err := reader.ExtractPageText(tt.page)
+			if err != nil {
+				t.Fatalf("ExtractPageText failed: %v", err)
+			}
+			if !strings.Contains(text, tt.wantText) {
+				t.Errorf("text = %q, want to contain %q", text, tt.wantText)
+			}
+
+			page, err := reader.r.GetPage(tt.page)
+			if err != nil {
+				t.Fatalf("GetPage failed: %v", err)
+			}
+			if got := reader.pageBoxes(page).MediaBox.Width; got != tt.wantWidth {
+				t.Errorf("MediaBox width = %v, want %v", got, tt.wantWidth)
+			}
+
+			images, err := reader.ExtractImages(tt.page)
+			if err != nil {
+				t.Fatalf("ExtractImages failed: %v", err)
+			}
+			if len(images) != tt.wantImages {
+				t.Errorf("images = %d, want %d", len(images), tt.wantImages)
+			}
+		})
+	}
+
+	annotations, err := reader.ExtractPageAnnotations(0)
+	if err != nil {
+		t.Fatalf("ExtractPageAnnotations failed: %v", err)
+	}
+	if len(annotations) != 1 || annotations[0].Destination == nil || annotations[0].Destination.PageNum != 1 {
+		t.Errorf("annotations = %+v, want a link to page 1", annotations)
+	}
+
+	fields, err := reader.ExtractFormFields()
+	if err != nil {
+		t.Fatalf("ExtractFormFields failed: %v", err)
+	}
+	if len(fields) != 1 || fields[0].Name != "name" || fields[0].Value != "Taro" {
+		t.Fatalf("fields = %+v, want the text field from the second input", fields)
+	}
+	if widgets := fields[0].Widgets; len(widgets) != 1 || widgets[0].PageNum != 2 {
+		t.Errorf("widgets = %+v, want one widget on page 2", widgets)
+	}
+}
+
+func TestDocument_AppendPDF(t *testing.T) {
+	source, err := OpenReader(bytes.NewReader(mergeSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer source.Close()
+
+	doc := New()
+	addTextPage := func(text string) {
+		page := doc.AddPage(PageSizeA4, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatalf("SetFont failed: %v", err)
+		}
+		if err := page.DrawText(text, 50, 700); err != nil {
+			t.Fatalf("DrawText failed: %v", err)
+		}
+	}
+	addTextPage("Cover")
+	if err := doc.AppendPDF(source); err != nil {
+		t.Fatalf("AppendPDF failed: %v", err)
+	}
+	addTextPage("End")
+
+	if got := doc.PageCount(); got != 4 {
+		t.Fatalf("PageCount = %d, want 4", got)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open written PDF: %v", err)
+	}
+	defer reader.Close()
+
+	for i, want := range []string{"Cover", "First", "Second", "End"} {
+		text, err := reader.ExtractPageText(i)
+		if err != nil {
+			t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+		}
+		if !strings.Contains(text, want) {
+			t.Errorf("page %d text = %q, want to contain %q", i, text, want)
+		}
+	}
+
+	// リンク先は追加した位置に合わせて2ページ目（0-indexed）になる
+	annotations, err := reader.ExtractPageAnnotations(1)
+	if err != nil {
+		t.Fatalf("ExtractPageAnnotations failed: %v", err)
+	}
+	if len(annotations) != 1 || annotations[0].Destination == nil || annotations[0].Destination.PageNum != 2 {
+		t.Errorf("annotations = %+v, want a link to page 2", annotations)
+	}
+}
+
+func TestDocument_AppendPDF_Encrypted(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatalf("SetFont failed: %v", err)
+	}
+	if err := page.DrawText("Secret", 50, 700); err != nil {
+		t.Fatalf("DrawText failed: %v", err)
+	}
+	if err := doc.SetEncryption(EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128}); err != nil {
+		t.Fatalf("SetEncryption failed: %v", err)
+	}
+	var encrypted bytes.Buffer
+	if err := doc.WriteTo(&encrypted); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	source, err := OpenReader(bytes.NewReader(encrypted.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	merged := New()
+	if err := merged.AppendPDF(source); err == nil {
+		t.Fatal("expected error for unauthenticated encrypted PDF")
+	}
+
+	if err := source.AuthenticateWithPassword("user"); err != nil {
+		t.Fatalf("AuthenticateWithPassword failed: %v", err)
+	}
+	if err := merged.AppendPDF(source); err != nil {
+		t.Fatalf("AppendPDF failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := merged.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open merged PDF: %v", err)
+	}
+	if reader.IsEncrypted() {
+		t.Error("merged PDF should not be encrypted")
+	}
+	text, err := reader.ExtractPageText(0)
+	if err != nil {
+		t.Fatalf("ExtractPageText failed: %v", err)
+	}
+	if !strings.Contains(text, "Secret") {
+		t.Errorf("text = %q, want to contain %q", text, "Secret")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf16"
 
 	"github.com/ryomak/gopdf/internal/core"
 )
@@ -23,7 +24,12 @@ type Metadata struct {
 
 	// Custom fields (key-value pairs)
 	// Any additional metadata fields not covered by standard fields
+	// (written to the XMP packet in the pdfx namespace as well)
 	Custom map[string]string
+
+	// XMP holds additional properties in custom namespaces.
+	// They are written only to the XMP /Metadata stream, not to the Info dictionary.
+	XMP []XMPProperty
 }
 
 // SetMetadata sets the document metadata.
@@ -136,6 +142,52 @@ func encodeTextString(s string) core.Object {
 	return core.String("<" + string(hexBytes) + ">")
 }
 
+// textString encodes a string as raw PDF text string bytes.
+// ASCII strings are stored as-is; other strings are stored as UTF-16BE with BOM.
+// Unlike encodeTextString, the result carries no delimiters, so the serializer
+// chooses between literal and hex notation.
+func textString(s string) core.String {
+	if isASCII(s) {
+		return core.String(s)
+	}
+
+	units := utf16.Encode([]rune(s))
+	buf := make([]byte, 0, 2+len(units)*2)
+	buf = append(buf, 0xFE, 0xFF)
+	for _, u := range units {
+		buf = append(buf, byte(u>>8), byte(u))
+	}
+	return core.String(buf)
+}
+
+// rawTextString decodes a text string object read from a PDF.
+// The object holds the raw string bytes: UTF-16BE with BOM, UTF-8 with BOM (PDF 2.0),
+// or PDFDocEncoding, which is treated as Latin-1.
+func rawTextString(obj core.Object) string {
+	str, ok := obj.(core.String)
+	if !ok {
+		return ""
+	}
+	b := []byte(str)
+
+	switch {
+	case len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF:
+		units := make([]uint16, 0, (len(b)-2)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	case len(b) >= 3 && b[0] == 0xEF && b[1] == 0xBB && b[2] == 0xBF:
+		return string(b[3:])
+	}
+
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
 // hexChar converts a 4-bit value to a hex character
 func hexChar(b byte) byte {
 	b &= 0x0F
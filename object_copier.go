@@ -0,0 +1,186 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// objectCopier は読み込んだPDFのオブジェクトを参照を辿りながら別のWriterへ複製する
+// 出力側のオブジェクト番号は振り直され、参照は自動的に置き換えられる
+type objectCopier struct {
+	src     *reader.Reader
+	w       *writer.Writer
+	mapping map[int]int         // 元のオブジェクト番号 -> 出力側のオブジェクト番号
+	pending []int               // 出力待ちの元のオブジェクト番号
+	skip    map[int]bool        // 複製せずnullに置き換える元のオブジェクト番号（出力しないページなど）
+	replace map[int]core.Object // 元のオブジェクトの代わりに出力するオブジェクト（位置を変えた注釈など）
+	inPlace bool                // 元のファイルに追記する（参照はそのまま使い、replaceのオブジェクトだけを同じ番号で出力する）
+}
+
+// newObjectCopier は新しいobjectCopierを作成する
+func newObjectCopier(src *reader.Reader, w *writer.Writer) *objectCopier {
+	return &objectCopier{
+		src:     src,
+		w:       w,
+		mapping: make(map[int]int),
+	}
+}
+
+// ref は元のオブジェクト番号に対応する出力側の参照を返す
+// 初めて参照されたオブジェクトは番号を予約し、flushで出力する
+func (c *objectCopier) ref(objNum int) *core.Reference {
+	newNum, ok := c.mapping[objNum]
+	if !ok {
+		newNum = c.w.ReserveObject()
+		c.mapping[objNum] = newNum
+		c.pending = append(c.pending, objNum)
+	}
+	return &core.Reference{ObjectNumber: newNum}
+}
+
+// copyObject は参照を出力側の番号に置き換えたオブジェクトの複製を返す
+func (c *objectCopier) copyObject(obj core.Object) core.Object {
+	switch v := obj.(type) {
+	case *core.Reference:
+		if c.inPlace {
+			return v
+		}
+		if c.skip[v.ObjectNumber] {
+			return core.Null{}
+		}
+		return c.ref(v.ObjectNumber)
+
+	case core.Array:
+		out := make(core.Array, len(v))
+		for i, item := range v {
+			out[i] = c.copyObject(item)
+		}
+		return out
+
+	case core.Dictionary:
+		out := make(core.Dictionary, len(v))
+		for k, item := range v {
+			out[k] = c.copyObject(item)
+		}
+		return out
+
+	case *core.Stream:
+		dict := c.copyObject(v.Dict).(core.Dictionary)
+		// /Length は間接参照の場合もあるため、実データ長で置き換える
+		dict[core.Name("Length")] = core.Integer(len(v.Data))
+		return &core.Stream{Dict: dict, Data: v.Data}
+
+	default:
+		return obj
+	}
+}
+
+// flush は出力待ちのオブジェクトをすべて書き込む
+// 書き込み中に新たに参照されたオブジェクトも続けて出力する
+// inPlaceの場合は、replaceのオブジェクトを元の番号で書き込む
+func (c *objectCopier) flush() error {
+	if c.inPlace {
+		objNums := make([]int, 0, len(c.replace))
+		for objNum := range c.replace {
+			objNums = append(objNums, objNum)
+		}
+		sort.Ints(objNums)
+		for _, objNum := range objNums {
+			if err := c.w.WriteObject(objNum, c.copyObject(c.replace[objNum])); err != nil {
+				return fmt.Errorf("failed to write object %d: %w", objNum, err)
+			}
+			delete(c.replace, objNum)
+		}
+		return nil
+	}
+
+	for len(c.pending) > 0 {
+		objNum := c.pending[0]
+		c.pending = c.pending[1:]
+
+		obj, ok := c.replace[objNum]
+		if !ok {
+			var err error
+			obj, err = c.src.GetObject(objNum)
+			if err != nil {
+				return fmt.Errorf("failed to read object %d: %w", objNum, err)
+			}
+		}
+
+		if err := c.w.WriteObject(c.mapping[objNum], c.copyObject(obj)); err != nil {
+			return fmt.Errorf("failed to write object %d: %w", objNum, err)
+		}
+	}
+	return nil
+}
+
+// rewritePDF は読み込んだPDFをカタログとInfo辞書から辿れるオブジェクトだけで書き直す
+// encryption が nil の場合は暗号化なしで出力する
+func rewritePDF(src *reader.Reader, out io.Writer, encryption *writer.EncryptionInfo) error {
+	w := writer.NewWriter(out)
+	if encryption != nil {
+		w.SetEncryption(encryption)
+	}
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+	return copyDocument(newObjectCopier(src, w), encryption)
+}
+
+// copyDocument はカタログとInfo辞書から辿れるオブジェクトをcのWriterに複製し、trailerまで書き込む
+// encryption は書き込み先のWriterに設定した暗号化（nil = 暗号化なし）
+// c.replaceにカタログがあればそれを使い、c.skipにInfo辞書があれば/Infoを出力しない
+func copyDocument(c *objectCopier, encryption *writer.EncryptionInfo) error {
+	src, w := c.src, c.w
+	srcTrailer := src.GetTrailer()
+
+	root, ok := srcTrailer[core.Name("Root")].(*core.Reference)
+	if !ok {
+		return fmt.Errorf("trailer /Root is missing or not a reference")
+	}
+
+	// カタログは暗号化方式に応じて拡張宣言を追加するため、先に複製して書き込む
+	catalog, err := src.GetCatalog()
+	if err != nil {
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+	if replaced, ok := c.replace[root.ObjectNumber].(core.Dictionary); ok {
+		catalog = replaced
+	}
+	rootNum := w.ReserveObject()
+	c.mapping[root.ObjectNumber] = rootNum
+	catalogCopy, ok := c.copyObject(catalog).(core.Dictionary)
+	if !ok {
+		return fmt.Errorf("catalog is not a dictionary")
+	}
+	if encryption != nil && encryption.Revision == 6 {
+		addAES256Extension(catalogCopy)
+	}
+	if err := w.WriteObject(rootNum, catalogCopy); err != nil {
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+
+	trailer := core.Dictionary{
+		core.Name("Root"): &core.Reference{ObjectNumber: rootNum},
+	}
+	if info, ok := srcTrailer[core.Name("Info")]; ok {
+		if info := c.copyObject(info); info != (core.Null{}) {
+			trailer[core.Name("Info")] = info
+		}
+	}
+	// 暗号化しない場合は元のファイルIDを引き継ぐ（暗号化時はWriterが新しく生成する）
+	if id, ok := srcTrailer[core.Name("ID")]; ok && encryption == nil {
+		trailer[core.Name("ID")] = id
+	}
+
+	if err := c.flush(); err != nil {
+		return err
+	}
+
+	return w.WriteTrailer(trailer)
+}
@@ -0,0 +1,111 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseALTO はALTO XML（図書館のデジタル化で使われるOCRの形式）を読み込み、1ページのOCRResultを返す
+// String要素を単語（CONTENT、位置はHPOS・VPOS・WIDTH・HEIGHT、信頼度はWC）に、TextLineを行にする
+// 座標と画像の大きさ（PageのWIDTH・HEIGHT）はMeasurementUnit（pixel・mm10・inch1200）のまま。ToTextLayerは比で変換するため、単位によらずページに重なる
+// 複数ページのALTOはParseALTOPagesで読み込む
+// 設計書: docs/ocr_text_layer_design.md
+func ParseALTO(data []byte) (OCRResult, error) {
+	pages, err := ParseALTOPages(data)
+	if err != nil {
+		return OCRResult{}, err
+	}
+	if len(pages) != 1 {
+		return OCRResult{}, fmt.Errorf("ALTO has %d pages, use ParseALTOPages", len(pages))
+	}
+	return pages[0], nil
+}
+
+// ParseALTOPages はALTO XMLを読み込み、ページ（Page）ごとのOCRResultを返す
+func ParseALTOPages(data []byte) ([]OCRResult, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var pages []OCRResult
+	var lines [][]string // 今のページの行ごとの単語
+	finishPage := func() {
+		if len(pages) > 0 {
+			pages[len(pages)-1].Text = joinOCRLines(lines)
+		}
+		lines = nil
+	}
+
+	root := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid ALTO: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "alto":
+			root = true
+		case "Page":
+			finishPage()
+			pages = append(pages, OCRResult{
+				ImageWidth:  int(math.Round(altoNumber(start, "WIDTH"))),
+				ImageHeight: int(math.Round(altoNumber(start, "HEIGHT"))),
+			})
+		case "TextLine":
+			lines = append(lines, nil)
+		case "String":
+			if len(pages) == 0 {
+				continue
+			}
+			text := strings.TrimSpace(xmlAttr(start, "CONTENT"))
+			if text == "" {
+				continue
+			}
+			page := &pages[len(pages)-1]
+			page.Words = append(page.Words, OCRWord{
+				Text:       text,
+				Confidence: altoNumber(start, "WC"),
+				Bounds: Rectangle{
+					X:      altoNumber(start, "HPOS"),
+					Y:      altoNumber(start, "VPOS"),
+					Width:  altoNumber(start, "WIDTH"),
+					Height: altoNumber(start, "HEIGHT"),
+				},
+			})
+			if len(lines) == 0 {
+				lines = append(lines, nil)
+			}
+			lines[len(lines)-1] = append(lines[len(lines)-1], text)
+		}
+	}
+	finishPage()
+
+	if !root {
+		return nil, fmt.Errorf("invalid ALTO: no alto element")
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("invalid ALTO: no Page element")
+	}
+	return pages, nil
+}
+
+// altoNumber は要素の数値の属性を返す（ない場合・数値でない場合は0）
+func altoNumber(e xml.StartElement, name string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(xmlAttr(e, name)), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+// testALTO はALTO v4の1ページの出力
+const testALTO = `<?xml version="1.0" encoding="UTF-8"?>
+<alto xmlns="http://www.loc.gov/standards/alto/ns-v4#">
+  <Description>
+    <MeasurementUnit>pixel</MeasurementUnit>
+    <sourceImageInformation><fileName>scan.tif</fileName></sourceImageInformation>
+  </Description>
+  <Layout>
+    <Page ID="page_0" WIDTH="2000" HEIGHT="2800" PHYSICAL_IMG_NR="1">
+      <PrintSpace HPOS="0" VPOS="0" WIDTH="2000" HEIGHT="2800">
+        <TextBlock ID="block_0" HPOS="200" VPOS="200" WIDTH="1000" HEIGHT="200">
+          <TextLine ID="line_0" HPOS="200" VPOS="200" WIDTH="1000" HEIGHT="80">
+            <String ID="string_0" HPOS="200" VPOS="200" WIDTH="320" HEIGHT="80" WC="0.97" CONTENT="Digital"/>
+            <SP WIDTH="40" VPOS="200" HPOS="520"/>
+            <String ID="string_1" HPOS="560" VPOS="200" WIDTH="400" HEIGHT="80" WC="0.61" CONTENT="library"/>
+          </TextLine>
+          <TextLine ID="line_1" HPOS="200" VPOS="320" WIDTH="600" HEIGHT="80">
+            <String ID="string_2" HPOS="200" VPOS="320" WIDTH="600" HEIGHT="80" CONTENT="R&amp;D"/>
+          </TextLine>
+        </TextBlock>
+      </PrintSpace>
+    </Page>
+  </Layout>
+</alto>`
+
+func TestParseALTO(t *testing.T) {
+	result, err := ParseALTO([]byte(testALTO))
+	if err != nil {
+		t.Fatalf("ParseALTO failed: %v", err)
+	}
+	if result.ImageWidth != 2000 || result.ImageHeight != 2800 {
+		t.Errorf("image size = %dx%d, want 2000x2800", result.ImageWidth, result.ImageHeight)
+	}
+	if result.Text != "Digital library\nR&D" {
+		t.Errorf("Text = %q", result.Text)
+	}
+	want := []OCRWord{
+		{Text: "Digital", Confidence: 0.97, Bounds: Rectangle{X: 200, Y: 200, Width: 320, Height: 80}},
+		{Text: "library", Confidence: 0.61, Bounds: Rectangle{X: 560, Y: 200, Width: 400, Height: 80}},
+		{Text: "R&D", Bounds: Rectangle{X: 200, Y: 320, Width: 600, Height: 80}},
+	}
+	if len(result.Words) != len(want) {
+		t.Fatalf("got %d words, want %d: %+v", len(result.Words), len(want), result.Words)
+	}
+	for i, w := range want {
+		if result.Words[i] != w {
+			t.Errorf("word %d = %+v, want %+v", i, result.Words[i], w)
+		}
+	}
+
+	layer := result.ToTextLayer(result.ImageWidth, result.ImageHeight, 500, 700)
+	if got := layer.Words[0].Bounds; got != (Rectangle{X: 50, Y: 630, Width: 80, Height: 20}) {
+		t.Errorf("text layer word = %+v", got)
+	}
+}
+
+func TestParseALTOPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		pages   int
+		wantErr bool
+	}{
+		{
+			"two pages",
+			`<alto><Layout><Page WIDTH="10" HEIGHT="10"><TextLine><String CONTENT="a"/></TextLine></Page><Page WIDTH="10" HEIGHT="10"/></Layout></alto>`,
+			2, false,
+		},
+		{"no page", `<alto><Layout/></alto>`, 0, true},
+		{"not ALTO", `<html><Page/></html>`, 0, true},
+		{"broken XML", `<alto><Layout><Page>`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := ParseALTOPages([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseALTOPages should fail, got %+v", pages)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseALTOPages failed: %v", err)
+			}
+			if len(pages) != tt.pages {
+				t.Errorf("got %d pages, want %d", len(pages), tt.pages)
+			}
+			if _, err := ParseALTO([]byte(tt.data)); err == nil {
+				t.Error("ParseALTO should fail for a multi-page ALTO")
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// hocrLineClasses は行を表すhOCRのクラス
+var hocrLineClasses = []string{"ocr_line", "ocrx_line", "ocr_caption", "ocr_header", "ocr_textfloat"}
+
+// ParseHOCR はhOCR（TesseractのhOCR出力など）を読み込み、1ページのOCRResultを返す
+// 単語（ocrx_word）の位置（title の bbox）はピクセル座標、信頼度は x_wconf を0.0-1.0にしたもの（ない場合は0）
+// 画像の大きさはページ（ocr_page）の bbox から、Textは単語を空白、行を改行でつないだもの
+// 単語の角度は行の textangle と baseline の傾きから求める（傾いたスキャンでも単語に重なるように）
+// 複数ページのhOCRはParseHOCRPagesで読み込む
+// 設計書: docs/ocr_text_layer_design.md
+func ParseHOCR(data []byte) (OCRResult, error) {
+	pages, err := ParseHOCRPages(data)
+	if err != nil {
+		return OCRResult{}, err
+	}
+	if len(pages) != 1 {
+		return OCRResult{}, fmt.Errorf("hOCR has %d pages, use ParseHOCRPages", len(pages))
+	}
+	return pages[0], nil
+}
+
+// ParseHOCRPages はhOCRを読み込み、ページ（ocr_page）ごとのOCRResultを返す
+func ParseHOCRPages(data []byte) ([]OCRResult, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var pages []OCRResult
+	var lines [][]string // 今のページの行ごとの単語
+	finishPage := func() {
+		if len(pages) > 0 {
+			pages[len(pages)-1].Text = joinOCRLines(lines)
+		}
+		lines = nil
+	}
+
+	var word *OCRWord
+	var wordText strings.Builder
+	var lineAngle float64 // 今の行のベースラインの角度
+	depth, wordDepth := 0, 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid hOCR: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			classes := strings.Fields(xmlAttr(t, "class"))
+			title := hocrTitle(xmlAttr(t, "title"))
+			switch {
+			case containsAny(classes, "ocr_page"):
+				finishPage()
+				page := OCRResult{}
+				if bbox, ok := title.bbox(); ok {
+					page.ImageWidth, page.ImageHeight = int(bbox.X+bbox.Width), int(bbox.Y+bbox.Height)
+				}
+				pages = append(pages, page)
+				lineAngle = 0
+			case containsAny(classes, hocrLineClasses...):
+				lines = append(lines, nil)
+				lineAngle = title.angle()
+			case containsAny(classes, "ocrx_word") && len(pages) > 0:
+				word = &OCRWord{Angle: lineAngle}
+				word.Bounds, _ = title.bbox()
+				if conf, ok := title["x_wconf"]; ok && len(conf) == 1 {
+					if v, err := strconv.ParseFloat(conf[0], 64); err == nil {
+						word.Confidence = v / 100
+					}
+				}
+				wordText.Reset()
+				wordDepth = depth
+			}
+
+		case xml.EndElement:
+			if word != nil && depth == wordDepth {
+				if word.Text = strings.TrimSpace(wordText.String()); word.Text != "" {
+					page := &pages[len(pages)-1]
+					page.Words = append(page.Words, *word)
+					if len(lines) == 0 {
+						lines = append(lines, nil)
+					}
+					lines[len(lines)-1] = append(lines[len(lines)-1], word.Text)
+				}
+				word = nil
+			}
+			depth--
+
+		case xml.CharData:
+			if word != nil {
+				wordText.Write(t)
+			}
+		}
+	}
+	finishPage()
+
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("invalid hOCR: no ocr_page element")
+	}
+	return pages, nil
+}
+
+// hocrProperties はhOCRのtitle属性のプロパティ（"bbox 0 0 10 10; x_wconf 95"）
+type hocrProperties map[string][]string
+
+// hocrTitle はtitle属性をプロパティに分ける
+func hocrTitle(title string) hocrProperties {
+	props := make(hocrProperties)
+	for _, prop := range strings.Split(title, ";") {
+		if fields := strings.Fields(prop); len(fields) > 0 {
+			props[fields[0]] = fields[1:]
+		}
+	}
+	return props
+}
+
+// bbox はbboxプロパティ（x0 y0 x1 y1）の矩形（ピクセル座標、左上原点）を返す
+func (p hocrProperties) bbox() (Rectangle, bool) {
+	values := p["bbox"]
+	if len(values) != 4 {
+		return Rectangle{}, false
+	}
+	var v [4]float64
+	for i, s := range values {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Rectangle{}, false
+		}
+		v[i] = f
+	}
+	return Rectangle{X: v[0], Y: v[1], Width: v[2] - v[0], Height: v[3] - v[1]}, true
+}
+
+// angle はtextangle（度、反時計回り）とbaseline（傾き 切片。画像の座標なので下がる傾きが正）から、
+// ベースラインの角度（度、画像の見た目で反時計回り）を返す
+func (p hocrProperties) angle() float64 {
+	var angle float64
+	if values := p["textangle"]; len(values) == 1 {
+		if v, err := strconv.ParseFloat(values[0], 64); err == nil {
+			angle = v
+		}
+	}
+	if values := p["baseline"]; len(values) == 2 {
+		if slope, err := strconv.ParseFloat(values[0], 64); err == nil {
+			angle -= math.Atan(slope) * 180 / math.Pi
+		}
+	}
+	return angle
+}
+
+// xmlAttr は要素の属性の値を返す（ない場合は空）
+func xmlAttr(e xml.StartElement, name string) string {
+	for _, attr := range e.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// containsAny はclassesにnamesのいずれかが含まれるかを返す
+func containsAny(classes []string, names ...string) bool {
+	for _, class := range classes {
+		for _, name := range names {
+			if class == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// joinOCRLines は行ごとの単語を、単語を空白、行を改行でつないだテキストにする（単語のない行は除く）
+func joinOCRLines(lines [][]string) string {
+	var texts []string
+	for _, line := range lines {
+		if len(line) > 0 {
+			texts = append(texts, strings.Join(line, " "))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,118 @@
+package gopdf
+
+import (
+	"math"
+	"testing"
+)
+
+// testHOCR はTesseractのhOCR出力と同じ形の1ページのhOCR
+const testHOCR = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+ <head>
+  <title></title>
+  <meta http-equiv="Content-Type" content="text/html;charset=utf-8"/>
+  <meta name='ocr-system' content='tesseract 5.3.0' />
+ </head>
+ <body>
+  <div class='ocr_page' id='page_1' title='image "scan.png"; bbox 0 0 1000 1400; ppageno 0'>
+   <div class='ocr_carea' id='block_1_1' title="bbox 100 100 600 200">
+    <p class='ocr_par' id='par_1_1' lang='eng' title="bbox 100 100 600 200">
+     <span class='ocr_line' id='line_1_1' title="bbox 100 100 600 140; baseline 0 -8; x_size 40">
+      <span class='ocrx_word' id='word_1_1' title='bbox 100 100 260 140; x_wconf 96'>Hello</span>
+      <span class='ocrx_word' id='word_1_2' title='bbox 280 100 420 140; x_wconf 91'><strong>World</strong></span>
+     </span>
+     <span class='ocr_line' id='line_1_2' title="bbox 100 160 600 200">
+      <span class='ocrx_word' id='word_1_3' title='bbox 100 160 300 200; x_wconf 42'>Tom&amp;Jerry</span>
+      <span class='ocrx_word' id='word_1_4' title='bbox 320 160 340 200; x_wconf 10'> </span>
+     </span>
+    </p>
+   </div>
+  </div>
+ </body>
+</html>`
+
+func TestParseHOCR(t *testing.T) {
+	result, err := ParseHOCR([]byte(testHOCR))
+	if err != nil {
+		t.Fatalf("ParseHOCR failed: %v", err)
+	}
+	if result.ImageWidth != 1000 || result.ImageHeight != 1400 {
+		t.Errorf("image size = %dx%d, want 1000x1400", result.ImageWidth, result.ImageHeight)
+	}
+	if result.Text != "Hello World\nTom&Jerry" {
+		t.Errorf("Text = %q", result.Text)
+	}
+
+	want := []OCRWord{
+		{Text: "Hello", Confidence: 0.96, Bounds: Rectangle{X: 100, Y: 100, Width: 160, Height: 40}},
+		{Text: "World", Confidence: 0.91, Bounds: Rectangle{X: 280, Y: 100, Width: 140, Height: 40}},
+		{Text: "Tom&Jerry", Confidence: 0.42, Bounds: Rectangle{X: 100, Y: 160, Width: 200, Height: 40}},
+	}
+	if len(result.Words) != len(want) {
+		t.Fatalf("got %d words, want %d: %+v", len(result.Words), len(want), result.Words)
+	}
+	for i, w := range want {
+		if result.Words[i] != w {
+			t.Errorf("word %d = %+v, want %+v", i, result.Words[i], w)
+		}
+	}
+
+	// 画像の大きさでページに重ねられる
+	layer := result.ToTextLayer(result.ImageWidth, result.ImageHeight, 500, 700)
+	if got := layer.Words[0].Bounds; got != (Rectangle{X: 50, Y: 630, Width: 80, Height: 20}) {
+		t.Errorf("text layer word = %+v", got)
+	}
+}
+
+func TestParseHOCRPages(t *testing.T) {
+	twoPages := `<html><body>
+<div class="ocr_page" title="bbox 0 0 100 200"><span class="ocrx_word" title="bbox 1 2 11 12; x_wconf 80">one</span></div>
+<div class="ocr_page" title="bbox 0 0 300 400"><span class="ocrx_word" title="bbox 5 5 25 15">two</span></div>
+</body></html>`
+
+	pages, err := ParseHOCRPages([]byte(twoPages))
+	if err != nil {
+		t.Fatalf("ParseHOCRPages failed: %v", err)
+	}
+	if len(pages) != 2 || pages[0].Text != "one" || pages[1].Text != "two" || pages[1].ImageWidth != 300 {
+		t.Fatalf("pages = %+v", pages)
+	}
+	if pages[1].Words[0].Confidence != 0 {
+		t.Errorf("confidence without x_wconf = %v, want 0", pages[1].Words[0].Confidence)
+	}
+	if _, err := ParseHOCR([]byte(twoPages)); err == nil {
+		t.Error("ParseHOCR should fail for a multi-page hOCR")
+	}
+	if _, err := ParseHOCR([]byte("<html><body><p>no OCR</p></body></html>")); err == nil {
+		t.Error("ParseHOCR should fail without ocr_page")
+	}
+}
+
+func TestParseHOCR_Angle(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		angle float64
+	}{
+		{name: "no baseline", line: "bbox 0 0 100 20", angle: 0},
+		{name: "descending baseline", line: "bbox 0 0 100 20; baseline 0.0175 -4", angle: -math.Atan(0.0175) * 180 / math.Pi},
+		{name: "ascending baseline", line: "bbox 0 0 100 20; baseline -0.05 -4", angle: math.Atan(0.05) * 180 / math.Pi},
+		{name: "textangle", line: "bbox 0 0 20 100; textangle 90", angle: 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hocr := `<div class="ocr_page" title="bbox 0 0 200 200"><span class="ocr_line" title="` + tt.line + `">` +
+				`<span class="ocrx_word" title="bbox 0 0 100 20">word</span></span>` +
+				`<span class="ocrx_word" title="bbox 0 40 100 60">after</span></div>`
+			result, err := ParseHOCR([]byte(hocr))
+			if err != nil {
+				t.Fatalf("ParseHOCR failed: %v", err)
+			}
+			if got := result.Words[0].Angle; math.Abs(got-tt.angle) > 1e-9 {
+				t.Errorf("Angle = %v, want %v", got, tt.angle)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package gopdf
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// OCREngine は画像の文字を認識するOCRエンジン（TesseractやクラウドのOCRなどをラップして実装する）
+type OCREngine interface {
+	// Recognize はimgの文字を認識する。単語の位置はimgのピクセル座標（左上原点）で返す
+	Recognize(img image.Image) (OCRResult, error)
+}
+
+// OCRFunc は関数をOCREngineとして使うための型
+type OCRFunc func(img image.Image) (OCRResult, error)
+
+// Recognize はOCREngineインターフェースの実装
+func (f OCRFunc) Recognize(img image.Image) (OCRResult, error) {
+	return f(img)
+}
+
+// searchableDPI はMakeSearchableでページをOCRに渡す画像に描画するときの解像度
+const searchableDPI = 300.0
+
+// MakeSearchable は画像だけのPDF（スキャンしたPDFなど）を、各ページをOCRして透明なテキストレイヤーを重ねた検索可能なPDFにする
+// ページの見た目はそのまま複製する。すでにテキストを抽出できるページはOCRせずに複製だけする
+// 認識した文字がLatin-1にない場合は、FontResolverで選んだ埋め込みフォントでテキストレイヤーを書く
+// 設計書: docs/ocr_text_layer_design.md
+func MakeSearchable(input io.ReadSeeker, output io.Writer, engine OCREngine) error {
+	if engine == nil {
+		return fmt.Errorf("OCR engine is required")
+	}
+
+	// 1. 元PDFを読み込み
+	reader, err := OpenReader(input)
+	if err != nil {
+		return fmt.Errorf("failed to open input PDF: %w", err)
+	}
+	defer reader.Close()
+
+	// 2. 各ページを複製してテキストレイヤーを重ねる
+	doc := New()
+	for i := 0; i < reader.PageCount(); i++ {
+		if err := addSearchablePage(doc, reader, i, engine); err != nil {
+			return fmt.Errorf("page %d: %w", i, err)
+		}
+	}
+
+	// 3. 出力（取り込んだページはWriteToで複製するため、readerはここまで閉じない）
+	return doc.WriteTo(output)
+}
+
+// addSearchablePage はreaderのページpageNumを複製したページをdocに追加し、必要ならOCRしたテキストレイヤーを重ねる
+func addSearchablePage(doc *Document, reader *PDFReader, pageNum int, engine OCREngine) error {
+	tpl, err := doc.ImportPage(reader, pageNum)
+	if err != nil {
+		return err
+	}
+	page := doc.AddPage(PageSize{Width: tpl.Width(), Height: tpl.Height()}, Portrait)
+	if err := page.DrawImportedPage(tpl, 0, 0, 0, 0); err != nil {
+		return err
+	}
+
+	text, err := reader.ExtractPageText(pageNum)
+	if err == nil && strings.TrimSpace(text) != "" {
+		return nil
+	}
+
+	img, err := reader.RenderPage(pageNum, RenderOptions{DPI: searchableDPI})
+	if err != nil {
+		return fmt.Errorf("failed to render page: %w", err)
+	}
+	result, err := engine.Recognize(img)
+	if err != nil {
+		return fmt.Errorf("failed to recognize text: %w", err)
+	}
+
+	imageWidth, imageHeight := img.Bounds().Dx(), img.Bounds().Dy()
+	if result.ImageWidth > 0 && result.ImageHeight > 0 {
+		imageWidth, imageHeight = result.ImageWidth, result.ImageHeight
+	}
+	layer := result.ToTextLayer(imageWidth, imageHeight, tpl.Width(), tpl.Height())
+	if err := setTextLayerFont(page, layer); err != nil {
+		return err
+	}
+	return page.AddTextLayer(layer)
+}
+
+// setTextLayerFont はlayerの単語がすべてLatin-1にあれば標準フォント、なければ描けるTTFフォントをpageに設定する
+func setTextLayerFont(page *Page, layer TextLayer) error {
+	var sb strings.Builder
+	latin1 := true
+	for _, word := range layer.Words {
+		sb.WriteString(word.Text)
+		sb.WriteByte(' ')
+		for _, r := range word.Text {
+			if r > 0xFF {
+				latin1 = false
+			}
+		}
+	}
+	if latin1 {
+		return page.SetFont(FontHelvetica, 12)
+	}
+
+	font, err := NewFontResolver().FontForText(sb.String())
+	if err != nil {
+		return fmt.Errorf("failed to select text layer font: %w", err)
+	}
+	return page.SetTTFFont(font, 12)
+}
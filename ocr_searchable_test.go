@@ -0,0 +1,131 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// scannedTestPDF は1ページ目が画像だけ、2ページ目がテキストのあるPDFを作成する
+func scannedTestPDF(t *testing.T) []byte {
+	t.Helper()
+	src := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			src.Set(x, y, color.RGBA{200, 200, 200, 255})
+		}
+	}
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	img, err := LoadPNG(&pngData)
+	if err != nil {
+		t.Fatalf("LoadPNG failed: %v", err)
+	}
+
+	doc := New()
+	size := PageSize{Width: 200, Height: 100}
+	scanned := doc.AddPage(size, Portrait)
+	if err := scanned.DrawImage(img, 0, 0, 200, 100); err != nil {
+		t.Fatalf("DrawImage failed: %v", err)
+	}
+	text := doc.AddPage(size, Portrait)
+	if err := text.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatalf("SetFont failed: %v", err)
+	}
+	if err := text.DrawText("Already text", 10, 50); err != nil {
+		t.Fatalf("DrawText failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMakeSearchable(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+	}{
+		{name: "latin", words: []string{"Scanned", "Invoice"}},
+		{name: "japanese", words: []string{"請求書", "日本語"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []image.Rectangle
+			engine := OCRFunc(func(img image.Image) (OCRResult, error) {
+				calls = append(calls, img.Bounds())
+				w := img.Bounds().Dx()
+				result := OCRResult{Text: strings.Join(tt.words, " ")}
+				for i, word := range tt.words {
+					result.Words = append(result.Words, OCRWord{
+						Text:       word,
+						Confidence: 0.9,
+						Bounds:     Rectangle{X: float64(i*w) / 2, Y: 100, Width: float64(w) / 4, Height: 60},
+					})
+				}
+				return result, nil
+			})
+
+			var out bytes.Buffer
+			if err := MakeSearchable(bytes.NewReader(scannedTestPDF(t)), &out, engine); err != nil {
+				t.Fatalf("MakeSearchable failed: %v", err)
+			}
+
+			// テキストのある2ページ目はOCRしない
+			if len(calls) != 1 {
+				t.Fatalf("Recognize called %d times, want 1", len(calls))
+			}
+			if calls[0].Dx() <= 200 || calls[0].Dy() <= 100 {
+				t.Errorf("image size = %v, want larger than the page at %v DPI", calls[0], searchableDPI)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader failed: %v", err)
+			}
+			defer reader.Close()
+			if reader.PageCount() != 2 {
+				t.Fatalf("PageCount = %d, want 2", reader.PageCount())
+			}
+
+			text, err := reader.ExtractPageText(0)
+			if err != nil {
+				t.Fatalf("ExtractPageText failed: %v", err)
+			}
+			for _, word := range tt.words {
+				if !strings.Contains(text, word) {
+					t.Errorf("page 0 text = %q, want it to contain %q", text, word)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeSearchable_Errors(t *testing.T) {
+	errOCR := errors.New("ocr failed")
+	failing := OCRFunc(func(img image.Image) (OCRResult, error) {
+		return OCRResult{}, errOCR
+	})
+
+	var out bytes.Buffer
+	err := MakeSearchable(bytes.NewReader(scannedTestPDF(t)), &out, failing)
+	if !errors.Is(err, errOCR) {
+		t.Errorf("error = %v, want %v", err, errOCR)
+	}
+	if err != nil && !strings.Contains(err.Error(), "page 0") {
+		t.Errorf("error = %v, want the page number", err)
+	}
+
+	if err := MakeSearchable(bytes.NewReader(scannedTestPDF(t)), &out, nil); err == nil {
+		t.Error("expected an error for a nil engine")
+	}
+}
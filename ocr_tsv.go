@@ -0,0 +1,114 @@
+package gopdf
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tesseractTSVColumns はTesseractのTSV出力の列（1行目の見出し）
+var tesseractTSVColumns = []string{
+	"level", "page_num", "block_num", "par_num", "line_num", "word_num",
+	"left", "top", "width", "height", "conf", "text",
+}
+
+// Tesseractの出力の階層（level列）
+const (
+	tesseractLevelPage = 1
+	tesseractLevelWord = 5
+)
+
+// tesseractTSVPage は読み込み中のページ
+type tesseractTSVPage struct {
+	result OCRResult
+	lines  [][]string // 行ごとの単語
+	line   [3]int     // 最後の行（block_num, par_num, line_num）
+}
+
+// ParseTesseractTSV はTesseractのTSV出力（tesseract image out tsv）を読み込み、1ページのOCRResultを返す
+// 単語の行（level 5）を単語（位置はleft・top・width・height、信頼度はconfを0.0-1.0にしたもの）に、
+// 同じblock_num・par_num・line_numの単語を1行にする。画像の大きさはページの行（level 1）から
+// 複数ページのTSVはParseTesseractTSVPagesで読み込む
+// 設計書: docs/ocr_text_layer_design.md
+func ParseTesseractTSV(data []byte) (OCRResult, error) {
+	pages, err := ParseTesseractTSVPages(data)
+	if err != nil {
+		return OCRResult{}, err
+	}
+	if len(pages) != 1 {
+		return OCRResult{}, fmt.Errorf("TSV has %d pages, use ParseTesseractTSVPages", len(pages))
+	}
+	return pages[0], nil
+}
+
+// ParseTesseractTSVPages はTesseractのTSV出力を読み込み、ページ（page_num）ごとのOCRResultをページ番号の順に返す
+func ParseTesseractTSVPages(data []byte) ([]OCRResult, error) {
+	rows := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	header := strings.Split(rows[0], "\t")
+	if len(header) != len(tesseractTSVColumns) {
+		return nil, fmt.Errorf("invalid Tesseract TSV: header has %d columns, want %d", len(header), len(tesseractTSVColumns))
+	}
+	for i, name := range tesseractTSVColumns {
+		if strings.TrimSpace(header[i]) != name {
+			return nil, fmt.Errorf("invalid Tesseract TSV: column %d is %q, want %q", i+1, header[i], name)
+		}
+	}
+
+	pages := make(map[int]*tesseractTSVPage)
+	for n, row := range rows[1:] {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		fields := strings.SplitN(row, "\t", len(tesseractTSVColumns))
+		if len(fields) < len(tesseractTSVColumns)-1 {
+			return nil, fmt.Errorf("invalid Tesseract TSV: line %d has %d columns", n+2, len(fields))
+		}
+		var v [11]float64 // text以外の数値の列
+		for i := range v {
+			f, err := strconv.ParseFloat(strings.TrimSpace(fields[i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Tesseract TSV: line %d, column %s: %w", n+2, tesseractTSVColumns[i], err)
+			}
+			v[i] = f
+		}
+		text := ""
+		if len(fields) == len(tesseractTSVColumns) {
+			text = strings.TrimSpace(fields[11])
+		}
+
+		page, ok := pages[int(v[1])]
+		if !ok {
+			page = &tesseractTSVPage{line: [3]int{-1, -1, -1}}
+			pages[int(v[1])] = page
+		}
+		bounds := Rectangle{X: v[6], Y: v[7], Width: v[8], Height: v[9]}
+		switch int(v[0]) {
+		case tesseractLevelPage:
+			page.result.ImageWidth, page.result.ImageHeight = int(bounds.Width), int(bounds.Height)
+		case tesseractLevelWord:
+			if text == "" {
+				continue
+			}
+			// confは単語以外の行と認識できなかった単語で-1
+			page.result.Words = append(page.result.Words, OCRWord{Text: text, Confidence: max(v[10], 0) / 100, Bounds: bounds})
+			if line := [3]int{int(v[2]), int(v[3]), int(v[4])}; line != page.line {
+				page.lines = append(page.lines, nil)
+				page.line = line
+			}
+			page.lines[len(page.lines)-1] = append(page.lines[len(page.lines)-1], text)
+		}
+	}
+
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("invalid Tesseract TSV: no rows")
+	}
+	results := make([]OCRResult, 0, len(pages))
+	for _, num := range slices.Sorted(maps.Keys(pages)) {
+		page := pages[num]
+		page.result.Text = joinOCRLines(page.lines)
+		results = append(results, page.result)
+	}
+	return results, nil
+}
@@ -0,0 +1,87 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// testTesseractTSV はtesseract scan.png out tsv の出力と同じ形のTSV
+var testTesseractTSV = strings.Join([]string{
+	"level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext",
+	"1\t1\t0\t0\t0\t0\t0\t0\t1000\t1400\t-1\t",
+	"2\t1\t1\t0\t0\t0\t100\t100\t500\t100\t-1\t",
+	"3\t1\t1\t1\t0\t0\t100\t100\t500\t100\t-1\t",
+	"4\t1\t1\t1\t1\t0\t100\t100\t500\t40\t-1\t",
+	"5\t1\t1\t1\t1\t1\t100\t100\t160\t40\t96.5\tQuarterly",
+	"5\t1\t1\t1\t1\t2\t280\t100\t140\t40\t91\treport",
+	"4\t1\t1\t1\t2\t0\t100\t160\t500\t40\t-1\t",
+	"5\t1\t1\t1\t2\t1\t100\t160\t200\t40\t38.25\tQ3",
+	"5\t1\t1\t1\t2\t2\t320\t160\t20\t40\t-1\t ",
+}, "\n") + "\n"
+
+func TestParseTesseractTSV(t *testing.T) {
+	result, err := ParseTesseractTSV([]byte(testTesseractTSV))
+	if err != nil {
+		t.Fatalf("ParseTesseractTSV failed: %v", err)
+	}
+	if result.ImageWidth != 1000 || result.ImageHeight != 1400 {
+		t.Errorf("image size = %dx%d, want 1000x1400", result.ImageWidth, result.ImageHeight)
+	}
+	if result.Text != "Quarterly report\nQ3" {
+		t.Errorf("Text = %q", result.Text)
+	}
+	want := []OCRWord{
+		{Text: "Quarterly", Confidence: 0.965, Bounds: Rectangle{X: 100, Y: 100, Width: 160, Height: 40}},
+		{Text: "report", Confidence: 0.91, Bounds: Rectangle{X: 280, Y: 100, Width: 140, Height: 40}},
+		{Text: "Q3", Confidence: 0.3825, Bounds: Rectangle{X: 100, Y: 160, Width: 200, Height: 40}},
+	}
+	if len(result.Words) != len(want) {
+		t.Fatalf("got %d words, want %d: %+v", len(result.Words), len(want), result.Words)
+	}
+	for i, w := range want {
+		if result.Words[i] != w {
+			t.Errorf("word %d = %+v, want %+v", i, result.Words[i], w)
+		}
+	}
+}
+
+func TestParseTesseractTSVPages(t *testing.T) {
+	header := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n"
+	tests := []struct {
+		name    string
+		data    string
+		want    []string // ページごとのText
+		wantErr bool
+	}{
+		{
+			"pages in order",
+			header + "5\t2\t1\t1\t1\t1\t0\t0\t10\t10\t90\tsecond\n5\t1\t1\t1\t1\t1\t0\t0\t10\t10\t90\tfirst\r\n",
+			[]string{"first", "second"},
+			false,
+		},
+		{"no header", "5\t1\t1\t1\t1\t1\t0\t0\t10\t10\t90\tword\n", nil, true},
+		{"no rows", header, nil, true},
+		{"not a number", header + "5\t1\t1\t1\t1\t1\tx\t0\t10\t10\t90\tword\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := ParseTesseractTSVPages([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTesseractTSVPages should fail, got %+v", pages)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTesseractTSVPages failed: %v", err)
+			}
+			var got []string
+			for _, page := range pages {
+				got = append(got, page.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("pages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
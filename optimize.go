@@ -0,0 +1,286 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// OptimizeResult はOptimizeで小さくした結果
+type OptimizeResult struct {
+	OriginalSize      int64 // 元のPDFのバイト数
+	OptimizedSize     int64 // 書き出したPDFのバイト数
+	CompressedStreams int   // Flateで圧縮したストリームの数
+	DuplicateObjects  int   // 同じ内容のオブジェクトと共有して除いたオブジェクトの数
+	UnusedObjects     int   // どこからも参照されていないため除いたオブジェクトの数
+}
+
+// Saved は減ったバイト数を返す（大きくなった場合は負の値）
+func (r *OptimizeResult) Saved() int64 {
+	return r.OriginalSize - r.OptimizedSize
+}
+
+// dedupTypes は同じ内容であれば共有する辞書の/Type（ストリームは/Typeによらず共有する）
+// フィールドや注釈、ページなど、同じ内容でも別のものとして扱われる辞書は共有しない
+var dedupTypes = map[core.Name]bool{
+	"Font":           true,
+	"FontDescriptor": true,
+	"Encoding":       true,
+	"ExtGState":      true,
+}
+
+// Optimize はPDFを小さくして書き出す
+// 圧縮されていないストリームをFlateで圧縮し、同じ内容のフォントや画像などのオブジェクトを1つにまとめ、
+// カタログとInfo辞書から辿れないオブジェクトを除く。ページ内容・メタデータ・文書構造はそのまま保持される
+// XMPメタデータのストリームは、PDF/Aで圧縮しないことが求められるため圧縮しない
+// 暗号化されたPDFは、Decryptで暗号化を解除してから渡す
+// 設計書: docs/optimize_design.md
+func Optimize(in io.ReadSeeker, out io.Writer) (*OptimizeResult, error) {
+	src, err := reader.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	if src.IsEncrypted() {
+		return nil, fmt.Errorf("PDF is encrypted: decrypt it before optimizing")
+	}
+	size, err := src.Size()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file size: %w", err)
+	}
+	result := &OptimizeResult{OriginalSize: size}
+
+	objects := reachableObjects(src)
+	for _, objNum := range src.ObjectNumbers() {
+		if _, ok := objects[objNum]; ok {
+			continue
+		}
+		// オブジェクトストリームとxrefストリームは、書き出すときに使わないだけで不要なオブジェクトではない
+		if stream, ok := objectOrNil(src, objNum).(*core.Stream); ok {
+			if t := stream.Dict[core.Name("Type")]; t == core.Name("ObjStm") || t == core.Name("XRef") {
+				continue
+			}
+		}
+		result.UnusedObjects++
+	}
+
+	counter := &countingWriter{w: out}
+	w := writer.NewWriter(counter)
+	if err := w.WriteHeader(); err != nil {
+		return nil, err
+	}
+	c := newObjectCopier(src, w)
+
+	// 同じ内容のオブジェクトへの参照は、まとめたオブジェクトへの参照にする
+	duplicates := findDuplicates(objects)
+	result.DuplicateObjects = len(duplicates)
+	dupNums := make([]int, 0, len(duplicates))
+	for objNum := range duplicates {
+		dupNums = append(dupNums, objNum)
+	}
+	sort.Ints(dupNums)
+	for _, objNum := range dupNums {
+		c.mapping[objNum] = c.ref(duplicates[objNum]).ObjectNumber
+	}
+
+	c.replace = make(map[int]core.Object)
+	for objNum, obj := range objects {
+		if _, dup := duplicates[objNum]; dup {
+			continue
+		}
+		stream, ok := obj.(*core.Stream)
+		if !ok {
+			continue
+		}
+		compressed, err := compressStream(stream)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress object %d: %w", objNum, err)
+		}
+		if compressed != nil {
+			c.replace[objNum] = compressed
+			result.CompressedStreams++
+		}
+	}
+
+	if err := copyDocument(c, nil); err != nil {
+		return nil, err
+	}
+	result.OptimizedSize = counter.n
+	return result, nil
+}
+
+// reachableObjects はtrailerの/Rootと/Infoから参照を辿れるオブジェクトを、オブジェクト番号 -> オブジェクトで返す
+func reachableObjects(src *reader.Reader) map[int]core.Object {
+	objects := make(map[int]core.Object)
+	var queue []int
+	var visit func(obj core.Object)
+	visit = func(obj core.Object) {
+		switch v := obj.(type) {
+		case *core.Reference:
+			if _, ok := objects[v.ObjectNumber]; !ok {
+				objects[v.ObjectNumber] = nil
+				queue = append(queue, v.ObjectNumber)
+			}
+		case core.Array:
+			for _, item := range v {
+				visit(item)
+			}
+		case core.Dictionary:
+			for _, item := range v {
+				visit(item)
+			}
+		case *core.Stream:
+			visit(v.Dict)
+		}
+	}
+
+	trailer := src.GetTrailer()
+	visit(trailer[core.Name("Root")])
+	visit(trailer[core.Name("Info")])
+	for len(queue) > 0 {
+		objNum := queue[0]
+		queue = queue[1:]
+		obj, err := src.GetObject(objNum)
+		if err != nil {
+			// 読み込めないオブジェクトは、複製するときにエラーになる
+			continue
+		}
+		objects[objNum] = obj
+		visit(obj)
+	}
+	return objects
+}
+
+// findDuplicates は同じ内容のストリームとdedupTypesの辞書を探し、重複したオブジェクトの番号 -> 残すオブジェクトの番号で返す
+// 参照するオブジェクトが同じ内容であれば同じ内容とみなし（フォント -> FontDescriptor -> フォントファイルなど）、
+// まとめられるものがなくなるまで繰り返す。残すのは最も小さい番号のオブジェクト
+func findDuplicates(objects map[int]core.Object) map[int]int {
+	var candidates []int
+	for objNum, obj := range objects {
+		switch v := obj.(type) {
+		case *core.Stream:
+			candidates = append(candidates, objNum)
+		case core.Dictionary:
+			if t, ok := v[core.Name("Type")].(core.Name); ok && dedupTypes[t] {
+				candidates = append(candidates, objNum)
+			}
+		}
+	}
+	sort.Ints(candidates)
+
+	duplicates := make(map[int]int)
+	for {
+		changed := false
+		first := make(map[[sha256.Size]byte]int, len(candidates))
+		for _, objNum := range candidates {
+			key, err := objectDigest(objects[objNum], duplicates)
+			if err != nil {
+				continue
+			}
+			rep, ok := first[key]
+			if !ok {
+				first[key] = objNum
+				continue
+			}
+			if duplicates[objNum] != rep {
+				duplicates[objNum] = rep
+				changed = true
+			}
+		}
+		if !changed {
+			return duplicates
+		}
+	}
+}
+
+// objectDigest はオブジェクトの内容のハッシュを返す
+// 参照はduplicatesでまとめた番号に置き換え、ストリームの/Lengthは除く
+func objectDigest(obj core.Object, duplicates map[int]int) ([sha256.Size]byte, error) {
+	var canonical func(obj core.Object) core.Object
+	canonical = func(obj core.Object) core.Object {
+		switch v := obj.(type) {
+		case *core.Reference:
+			if rep, ok := duplicates[v.ObjectNumber]; ok {
+				return &core.Reference{ObjectNumber: rep}
+			}
+			return &core.Reference{ObjectNumber: v.ObjectNumber}
+		case core.Array:
+			out := make(core.Array, len(v))
+			for i, item := range v {
+				out[i] = canonical(item)
+			}
+			return out
+		case core.Dictionary:
+			out := make(core.Dictionary, len(v))
+			for k, item := range v {
+				out[k] = canonical(item)
+			}
+			return out
+		case *core.Stream:
+			dict := canonical(v.Dict).(core.Dictionary)
+			delete(dict, core.Name("Length"))
+			return &core.Stream{Dict: dict, Data: v.Data}
+		case nil:
+			return core.Null{}
+		default:
+			return obj
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writer.NewSerializer(&buf).Serialize(canonical(obj)); err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(buf.Bytes()), nil
+}
+
+// compressStream は圧縮されていないストリームをFlateで圧縮したストリームを返す
+// 圧縮済みのストリーム、XMPメタデータ、圧縮しても小さくならないストリームの場合はnilを返す
+func compressStream(stream *core.Stream) (*core.Stream, error) {
+	if _, ok := stream.Dict[core.Name("Filter")]; ok || len(stream.Data) == 0 {
+		return nil, nil
+	}
+	if stream.Dict[core.Name("Type")] == core.Name("Metadata") {
+		return nil, nil
+	}
+	compressed, err := compressWithZlib(stream.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(compressed) >= len(stream.Data) {
+		return nil, nil
+	}
+	dict := make(core.Dictionary, len(stream.Dict)+1)
+	for k, v := range stream.Dict {
+		dict[k] = v
+	}
+	delete(dict, core.Name("DecodeParms"))
+	dict[core.Name("Filter")] = core.Name("FlateDecode")
+	return &core.Stream{Dict: dict, Data: compressed}, nil
+}
+
+// objectOrNil は読み込めたオブジェクトを返す（読み込めない場合はnil）
+func objectOrNil(src *reader.Reader, objNum int) core.Object {
+	obj, err := src.GetObject(objNum)
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// countingWriter は書き込んだバイト数を数えるio.Writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,173 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// optimizeSourcePDF は圧縮されていないストリーム、同じ内容のフォントと画像、参照されていないオブジェクトを持つPDFを作成する
+func optimizeSourcePDF() []byte {
+	stream := func(dict, data string) string {
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+	}
+	text := strings.Repeat("BT /F1 12 Tf 20 150 Td (Hello World) Tj ET\n", 20)
+	pixels := strings.Repeat("\x10\x20\x30", 16*16)
+	xmp := "<x:xmpmeta xmlns:x=\"adobe:ns:meta/\"></x:xmpmeta>" + strings.Repeat(" ", 200)
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R /Metadata 13 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 300 200] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R /Resources << /Font << /F1 7 0 R >> /XObject << /Im1 10 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R /Resources << /Font << /F1 8 0 R >> /XObject << /Im1 11 0 R >> >> >>",
+		stream("", text+"q 16 0 0 16 0 0 cm /Im1 Do Q"),
+		stream("", strings.ReplaceAll(text, "Hello", "Again")+"q 16 0 0 16 0 0 cm /Im1 Do Q"),
+		// 同じ内容のフォント（FontDescriptorも同じ内容の別のオブジェクト）
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /FontDescriptor 9 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /FontDescriptor 12 0 R >>",
+		"<< /Type /FontDescriptor /FontName /Helvetica /Flags 32 /ItalicAngle 0 >>",
+		// 同じ内容の画像（キーの順序だけが違う）
+		stream("/Type /XObject /Subtype /Image /Width 16 /Height 16 /ColorSpace /DeviceRGB /BitsPerComponent 8", pixels),
+		stream("/Subtype /Image /Type /XObject /BitsPerComponent 8 /ColorSpace /DeviceRGB /Width 16 /Height 16", pixels),
+		"<< /Type /FontDescriptor /FontName /Helvetica /Flags 32 /ItalicAngle 0 >>",
+		stream("/Type /Metadata /Subtype /XML", xmp),
+		// どこからも参照されていないオブジェクト
+		stream("", strings.Repeat("unused ", 100)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	})
+}
+
+func TestOptimize(t *testing.T) {
+	original := optimizeSourcePDF()
+
+	var out bytes.Buffer
+	result, err := Optimize(bytes.NewReader(original), &out)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	want := OptimizeResult{
+		OriginalSize:      int64(len(original)),
+		OptimizedSize:     int64(out.Len()),
+		CompressedStreams: 3, // 2つのコンテンツと、まとめた画像（XMPメタデータは圧縮しない）
+		DuplicateObjects:  3, // フォント、FontDescriptor、画像
+		UnusedObjects:     2,
+	}
+	if *result != want {
+		t.Errorf("Optimize() = %+v, want %+v", *result, want)
+	}
+	if result.Saved() <= 0 {
+		t.Errorf("Saved() = %d, want a positive value", result.Saved())
+	}
+
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open optimized PDF: %v", err)
+	}
+	defer reader.Close()
+
+	for pageNum, want := range []string{"Hello World", "Again World"} {
+		text, err := reader.ExtractPageText(pageNum)
+		if err != nil {
+			t.Fatalf("ExtractPageText(%d) failed: %v", pageNum, err)
+		}
+		if !strings.HasPrefix(strings.TrimSpace(text), want) {
+			t.Errorf("page %d text = %q, want it to start with %q", pageNum, text, want)
+		}
+	}
+
+	// 同じ内容のフォントと画像は、両方のページから同じオブジェクトを参照する
+	refs := make([][2]*core.Reference, 2)
+	for pageNum := range refs {
+		page, err := reader.r.GetPage(pageNum)
+		if err != nil {
+			t.Fatalf("GetPage(%d) failed: %v", pageNum, err)
+		}
+		resources, _ := reader.r.GetPageResources(page)
+		fonts, _ := reader.r.Resolve(resources[core.Name("Font")]).(core.Dictionary)
+		xobjects, _ := reader.r.Resolve(resources[core.Name("XObject")]).(core.Dictionary)
+		font, _ := fonts[core.Name("F1")].(*core.Reference)
+		image, _ := xobjects[core.Name("Im1")].(*core.Reference)
+		if font == nil || image == nil {
+			t.Fatalf("page %d resources are not references: %v %v", pageNum, fonts, xobjects)
+		}
+		refs[pageNum] = [2]*core.Reference{font, image}
+	}
+	for i, name := range []string{"font", "image"} {
+		if refs[0][i].ObjectNumber != refs[1][i].ObjectNumber {
+			t.Errorf("%s is not shared: %d and %d", name, refs[0][i].ObjectNumber, refs[1][i].ObjectNumber)
+		}
+	}
+
+	catalog, err := reader.r.GetCatalog()
+	if err != nil {
+		t.Fatalf("GetCatalog failed: %v", err)
+	}
+	metadata, _ := reader.r.Resolve(catalog[core.Name("Metadata")]).(*core.Stream)
+	if metadata == nil || metadata.Dict[core.Name("Filter")] != nil {
+		t.Errorf("XMP metadata should be kept uncompressed")
+	}
+}
+
+func TestOptimize_Document(t *testing.T) {
+	doc := New()
+	for i := 0; i < 3; i++ {
+		page := doc.AddPage(PageSizeA4, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatal(err)
+		}
+		for line := 0; line < 30; line++ {
+			if err := page.DrawText(fmt.Sprintf("Page %d, line %d", i+1, line), 50, 800-float64(line)*20); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var original bytes.Buffer
+	if err := doc.WriteTo(&original); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	result, err := Optimize(bytes.NewReader(original.Bytes()), &out)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+	if result.CompressedStreams != 3 {
+		t.Errorf("CompressedStreams = %d, want 3", result.CompressedStreams)
+	}
+	if result.Saved() <= 0 {
+		t.Errorf("Saved() = %d, want a positive value (%d -> %d bytes)", result.Saved(), result.OriginalSize, result.OptimizedSize)
+	}
+
+	// もう一度最適化しても、まとめるものや圧縮するものはない
+	optimized := out.Bytes()
+	result, err = Optimize(bytes.NewReader(optimized), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+	if result.CompressedStreams != 0 || result.DuplicateObjects != 0 || result.UnusedObjects != 0 {
+		t.Errorf("second Optimize() = %+v, want nothing to optimize", *result)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(optimized))
+	if err != nil {
+		t.Fatalf("Failed to open optimized PDF: %v", err)
+	}
+	defer reader.Close()
+	text, err := reader.ExtractPageText(2)
+	if err != nil {
+		t.Fatalf("ExtractPageText failed: %v", err)
+	}
+	if !strings.Contains(text, "Page 3, line 29") {
+		t.Errorf("page text = %q, want it to contain %q", text, "Page 3, line 29")
+	}
+}
+
+func TestOptimize_Encrypted(t *testing.T) {
+	encrypted := buildEncryptedPDF(t, &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128})
+	if _, err := Optimize(bytes.NewReader(encrypted), &bytes.Buffer{}); err == nil {
+		t.Error("Optimize should fail for an encrypted PDF")
+	}
+}
@@ -0,0 +1,102 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// OutlineItem はしおり（文書のアウトライン）の項目
+// ビューアーのしおりの一覧に表示され、選ぶと移動先のページを表示する
+type OutlineItem struct {
+	Title    string
+	Page     *Page   // 移動先のページ
+	Top      float64 // 移動先のページで表示する上端のY座標
+	Children []*OutlineItem
+}
+
+// AddOutline はしおりの最上位に項目を追加し、追加した項目を返す
+// 下位の項目は返した項目のAddChildで追加する。しおりのある文書は、開いたときにしおりの一覧を表示する
+// 設計書: docs/outline_design.md
+func (d *Document) AddOutline(title string, page *Page, top float64) *OutlineItem {
+	item := &OutlineItem{Title: title, Page: page, Top: top}
+	d.outlines = append(d.outlines, item)
+	return item
+}
+
+// AddChild は項目の下位に項目を追加し、追加した項目を返す
+func (o *OutlineItem) AddChild(title string, page *Page, top float64) *OutlineItem {
+	item := &OutlineItem{Title: title, Page: page, Top: top}
+	o.Children = append(o.Children, item)
+	return item
+}
+
+// pageDestination はページ上の位置を表示する移動先（[page /XYZ null top null]、拡大率は変えない）
+func pageDestination(pageRef *core.Reference, top float64) core.Array {
+	return core.Array{pageRef, core.Name("XYZ"), core.Null{}, core.Real(top), core.Null{}}
+}
+
+// writeOutlines はしおりの項目とOutlines辞書を出力し、Outlines辞書への参照を返す
+// 項目はすべて開いた状態（/Countは表示される子孫の数）で出力する
+func (d *Document) writeOutlines(w *writer.Writer, pageRefs []*core.Reference) (*core.Reference, error) {
+	pageIndex := make(map[*Page]int, len(d.pages))
+	for i, page := range d.pages {
+		pageIndex[page] = i
+	}
+
+	rootRef := &core.Reference{ObjectNumber: w.ReserveObject()}
+	var write func(items []*OutlineItem, parent *core.Reference) (first, last *core.Reference, count int, err error)
+	write = func(items []*OutlineItem, parent *core.Reference) (first, last *core.Reference, count int, err error) {
+		refs := make([]*core.Reference, len(items))
+		for i := range items {
+			refs[i] = &core.Reference{ObjectNumber: w.ReserveObject()}
+		}
+		for i, item := range items {
+			index, ok := pageIndex[item.Page]
+			if !ok {
+				return nil, nil, 0, fmt.Errorf("outline item %q refers to a page outside the document", item.Title)
+			}
+			dict := core.Dictionary{
+				core.Name("Title"):  textString(item.Title),
+				core.Name("Parent"): parent,
+				core.Name("Dest"):   pageDestination(pageRefs[index], item.Top),
+			}
+			if i > 0 {
+				dict[core.Name("Prev")] = refs[i-1]
+			}
+			if i < len(items)-1 {
+				dict[core.Name("Next")] = refs[i+1]
+			}
+			if len(item.Children) > 0 {
+				childFirst, childLast, childCount, err := write(item.Children, refs[i])
+				if err != nil {
+					return nil, nil, 0, err
+				}
+				dict[core.Name("First")] = childFirst
+				dict[core.Name("Last")] = childLast
+				dict[core.Name("Count")] = core.Integer(childCount)
+				count += childCount
+			}
+			if err := w.WriteObject(refs[i].ObjectNumber, dict); err != nil {
+				return nil, nil, 0, err
+			}
+		}
+		return refs[0], refs[len(refs)-1], count + len(items), nil
+	}
+
+	first, last, count, err := write(d.outlines, rootRef)
+	if err != nil {
+		return nil, err
+	}
+	rootDict := core.Dictionary{
+		core.Name("Type"):  core.Name("Outlines"),
+		core.Name("First"): first,
+		core.Name("Last"):  last,
+		core.Name("Count"): core.Integer(count),
+	}
+	if err := w.WriteObject(rootRef.ObjectNumber, rootDict); err != nil {
+		return nil, err
+	}
+	return rootRef, nil
+}
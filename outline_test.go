@@ -0,0 +1,125 @@
+package gopdf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// outlineNode はテスト用に読み取ったしおりの項目
+type outlineNode struct {
+	title    string
+	page     int
+	top      float64
+	children []outlineNode
+}
+
+// readOutlines はカタログの/Outlinesからしおりの項目を読み取る
+func readOutlines(t *testing.T, r *PDFReader) []outlineNode {
+	t.Helper()
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outlines, ok := r.r.Resolve(catalog[core.Name("Outlines")]).(core.Dictionary)
+	if !ok {
+		return nil
+	}
+	resolver := newDestinationResolver(r)
+
+	var read func(dict core.Dictionary) []outlineNode
+	read = func(dict core.Dictionary) []outlineNode {
+		var nodes []outlineNode
+		item, _ := r.r.Resolve(dict[core.Name("First")]).(core.Dictionary)
+		for item != nil {
+			node := outlineNode{title: rawTextString(r.r.Resolve(item[core.Name("Title")]).(core.String))}
+			if dest := resolver.resolve(r.r.Resolve(item[core.Name("Dest")])); dest != nil {
+				node.page, node.top = dest.PageNum, dest.Top
+			}
+			node.children = read(item)
+			if count, _ := item[core.Name("Count")].(core.Integer); int(count) != countOutline(node.children) {
+				t.Errorf("%q: /Count = %d, want %d", node.title, count, countOutline(node.children))
+			}
+			nodes = append(nodes, node)
+			item, _ = r.r.Resolve(item[core.Name("Next")]).(core.Dictionary)
+		}
+		return nodes
+	}
+	return read(outlines)
+}
+
+// countOutline は開いた状態で表示される項目の数を返す
+func countOutline(nodes []outlineNode) int {
+	count := len(nodes)
+	for _, node := range nodes {
+		count += countOutline(node.children)
+	}
+	return count
+}
+
+func TestDocumentAddOutline(t *testing.T) {
+	doc := New()
+	first := doc.AddPage(PageSizeA4, Portrait)
+	second := doc.AddPage(PageSizeA4, Portrait)
+	chapter := doc.AddOutline("Chapter 1", first, 800)
+	chapter.AddChild("Section 1.1", first, 400)
+	chapter.AddChild("Section 1.2", second, 700).AddChild("Detail", second, 300)
+	doc.AddOutline("Chapter 2", second, 200)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	want := []outlineNode{
+		{title: "Chapter 1", page: 0, top: 800, children: []outlineNode{
+			{title: "Section 1.1", page: 0, top: 400},
+			{title: "Section 1.2", page: 1, top: 700, children: []outlineNode{
+				{title: "Detail", page: 1, top: 300},
+			}},
+		}},
+		{title: "Chapter 2", page: 1, top: 200},
+	}
+	if got := readOutlines(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("outlines = %+v, want %+v", got, want)
+	}
+
+	// しおりのある文書はしおりの一覧を表示して開く
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := catalog[core.Name("PageMode")]; got != core.Name("UseOutlines") {
+		t.Errorf("/PageMode = %v, want UseOutlines", got)
+	}
+}
+
+func TestDocumentAddOutline_None(t *testing.T) {
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("/Outlines")) || bytes.Contains(buf.Bytes(), []byte("/PageMode")) {
+		t.Error("document without outlines has /Outlines or /PageMode")
+	}
+}
+
+func TestDocumentAddOutline_PageOutsideDocument(t *testing.T) {
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	doc.AddOutline("Elsewhere", New().AddPage(PageSizeA4, Portrait), 0)
+
+	if err := doc.WriteTo(&bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a page of another document")
+	}
+}
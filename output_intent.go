@@ -0,0 +1,129 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/icc"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// OutputIntentSubtype は出力インテントの種類（/S）
+type OutputIntentSubtype string
+
+const (
+	// OutputIntentPDFA は PDF/A 用の出力インテント
+	OutputIntentPDFA OutputIntentSubtype = "GTS_PDFA1"
+	// OutputIntentPDFX は PDF/X（印刷入稿）用の出力インテント
+	OutputIntentPDFX OutputIntentSubtype = "GTS_PDFX"
+	// OutputIntentPDFE は PDF/E（技術文書）用の出力インテント
+	OutputIntentPDFE OutputIntentSubtype = "ISO_PDFE1"
+)
+
+// outputIntent は出力インテント（文書の色を解釈するための出力条件とICCプロファイル）
+type outputIntent struct {
+	subtype    OutputIntentSubtype // /S
+	identifier string              // /OutputConditionIdentifier
+	info       string              // /Info
+	profile    []byte              // ICCプロファイル
+	components int                 // プロファイルの色成分数（/N）
+}
+
+// defaultOutputIntent はPDF/A用のsRGB出力インテントを返す
+func defaultOutputIntent() *outputIntent {
+	return &outputIntent{
+		subtype:    OutputIntentPDFA,
+		identifier: icc.SRGBDescription,
+		info:       icc.SRGBDescription,
+		profile:    icc.SRGB(),
+		components: 3,
+	}
+}
+
+// SetOutputIntent は文書の出力条件（印刷先の色空間など）を宣言する
+// iccProfile は出力デバイス（プリンターまたはモニター）のICCプロファイル、
+// info は出力条件の名前（例: "FOGRA39"、"Coated FOGRA39 (ISO 12647-2:2004)"）で、
+// /OutputConditionIdentifier と /Info の両方に使われる
+// PDF/A出力では既定のsRGBの代わりにこのプロファイルを使う
+func (d *Document) SetOutputIntent(subtype OutputIntentSubtype, iccProfile []byte, info string) error {
+	if subtype == "" {
+		return fmt.Errorf("output intent subtype is required")
+	}
+	if info == "" {
+		return fmt.Errorf("output condition info is required")
+	}
+	header, err := icc.ParseHeader(iccProfile)
+	if err != nil {
+		return fmt.Errorf("invalid output intent profile: %w", err)
+	}
+	if header.Class != "prtr" && header.Class != "mntr" {
+		return fmt.Errorf("output intent profile must be an output or display device profile, got class %q", header.Class)
+	}
+	components := header.Components()
+	if components == 0 || header.ColorSpace == "Lab " {
+		return fmt.Errorf("unsupported output intent color space %q", header.ColorSpace)
+	}
+
+	d.outputIntent = &outputIntent{
+		subtype:    subtype,
+		identifier: info,
+		info:       info,
+		profile:    append([]byte(nil), iccProfile...),
+		components: components,
+	}
+	return nil
+}
+
+// effectiveOutputIntent は出力する出力インテントを返す（なければnil）
+// PDF/Aで未設定の場合はsRGBを使う
+func (d *Document) effectiveOutputIntent() *outputIntent {
+	if d.outputIntent != nil {
+		return d.outputIntent
+	}
+	if _, _, pdfa := d.conformance.pdfaID(); pdfa {
+		return defaultOutputIntent()
+	}
+	return nil
+}
+
+// writeOutputIntents はICCプロファイルのストリームを出力し、カタログの/OutputIntents配列を返す
+// PDF/Aで種類がGTS_PDFA1以外の場合は、同じプロファイルを参照するGTS_PDFA1の出力インテントも加える
+func (d *Document) writeOutputIntents(w *writer.Writer) (core.Array, error) {
+	oi := d.effectiveOutputIntent()
+	if oi == nil {
+		return nil, nil
+	}
+
+	data, err := compressWithZlib(oi.profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress ICC profile: %w", err)
+	}
+	profileNum, err := w.AddObject(&core.Stream{
+		Dict: core.Dictionary{
+			core.Name("N"):      core.Integer(oi.components),
+			core.Name("Filter"): core.Name("FlateDecode"),
+			core.Name("Length"): core.Integer(len(data)),
+		},
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subtypes := []OutputIntentSubtype{oi.subtype}
+	if _, _, pdfa := d.conformance.pdfaID(); pdfa && oi.subtype != OutputIntentPDFA {
+		subtypes = append(subtypes, OutputIntentPDFA)
+	}
+
+	intents := make(core.Array, 0, len(subtypes))
+	for _, subtype := range subtypes {
+		intents = append(intents, core.Dictionary{
+			core.Name("Type"):                      core.Name("OutputIntent"),
+			core.Name("S"):                         core.Name(subtype),
+			core.Name("OutputConditionIdentifier"): textString(oi.identifier),
+			core.Name("Info"):                      textString(oi.info),
+			core.Name("DestOutputProfile"):         &core.Reference{ObjectNumber: profileNum},
+		})
+	}
+	return intents, nil
+}
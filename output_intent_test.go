@@ -0,0 +1,194 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/icc"
+)
+
+// modifiedProfile はsRGBプロファイルのヘッダーの一部を書き換えたプロファイルを返す
+func modifiedProfile(class, colorSpace string) []byte {
+	profile := icc.SRGB()
+	copy(profile[12:16], class)
+	copy(profile[16:20], colorSpace)
+	return profile
+}
+
+func TestDocumentSetOutputIntent(t *testing.T) {
+	tests := []struct {
+		name    string
+		subtype OutputIntentSubtype
+		profile []byte
+		info    string
+		wantErr bool
+	}{
+		{"RGB display profile", OutputIntentPDFX, icc.SRGB(), "sRGB", false},
+		{"CMYK printer profile", OutputIntentPDFX, modifiedProfile("prtr", "CMYK"), "FOGRA39", false},
+		{"missing subtype", "", icc.SRGB(), "sRGB", true},
+		{"missing info", OutputIntentPDFX, icc.SRGB(), "", true},
+		{"not a profile", OutputIntentPDFX, []byte("not an ICC profile"), "sRGB", true},
+		{"input device class", OutputIntentPDFX, modifiedProfile("scnr", "RGB "), "scanner", true},
+		{"Lab color space", OutputIntentPDFX, modifiedProfile("prtr", "Lab "), "Lab", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().SetOutputIntent(tt.subtype, tt.profile, tt.info)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetOutputIntent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDocumentWriteTo_OutputIntent(t *testing.T) {
+	tests := []struct {
+		name         string
+		conformance  Conformance
+		subtype      OutputIntentSubtype
+		wantSubtypes []string
+	}{
+		{"PDF/X", ConformanceNone, OutputIntentPDFX, []string{"GTS_PDFX"}},
+		{"PDF/X with PDF/A", PDFA2B, OutputIntentPDFX, []string{"GTS_PDFX", "GTS_PDFA1"}},
+		{"PDF/A", PDFA2B, OutputIntentPDFA, []string{"GTS_PDFA1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			if err := doc.SetConformance(tt.conformance); err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.SetOutputIntent(tt.subtype, icc.SRGB(), "Custom RGB"); err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+			r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			catalog, _ := r.r.GetCatalog()
+			intents, ok := r.r.Resolve(catalog[core.Name("OutputIntents")]).(core.Array)
+			if !ok || len(intents) != len(tt.wantSubtypes) {
+				t.Fatalf("/OutputIntents = %v, want %d entries", catalog[core.Name("OutputIntents")], len(tt.wantSubtypes))
+			}
+			var profileNum int
+			for i, obj := range intents {
+				intent := r.r.Resolve(obj).(core.Dictionary)
+				if got := string(intent[core.Name("S")].(core.Name)); got != tt.wantSubtypes[i] {
+					t.Errorf("intent %d /S = %s, want %s", i, got, tt.wantSubtypes[i])
+				}
+				if id := rawTextString(intent[core.Name("OutputConditionIdentifier")]); id != "Custom RGB" {
+					t.Errorf("intent %d /OutputConditionIdentifier = %q, want %q", i, id, "Custom RGB")
+				}
+				ref := intent[core.Name("DestOutputProfile")].(*core.Reference)
+				if i > 0 && ref.ObjectNumber != profileNum {
+					t.Error("output intents refer to different profiles")
+				}
+				profileNum = ref.ObjectNumber
+			}
+
+			profile, ok := r.r.Resolve(&core.Reference{ObjectNumber: profileNum}).(*core.Stream)
+			if !ok || profile.Dict[core.Name("N")] != core.Integer(3) {
+				t.Errorf("profile stream = %v, want /N 3", profile)
+			}
+		})
+	}
+}
+
+func TestDocumentWriteTo_PDFA2BWithCMYKIntent(t *testing.T) {
+	doc := New()
+	if err := doc.SetConformance(PDFA2B); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetOutputIntent(OutputIntentPDFX, modifiedProfile("prtr", "CMYK"), "FOGRA39"); err != nil {
+		t.Fatal(err)
+	}
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetFillColor(Color{R: 1})
+	page.FillRectangle(10, 10, 100, 100)
+	if err := page.DrawImage(newTestImage(t), 0, 0, 10, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	err := doc.WriteTo(&bytes.Buffer{})
+	var confErr *ConformanceError
+	if !errors.As(err, &confErr) {
+		t.Fatalf("WriteTo() error = %v, want *ConformanceError", err)
+	}
+	for _, want := range []string{"DeviceRGB image", "uses DeviceRGB colors"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestDocumentWriteTo_PDFA2BColorSpaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		draw    func(t *testing.T, page *Page)
+		wantRGB bool
+	}{
+		{
+			name:    "fill color",
+			draw:    func(t *testing.T, page *Page) { page.SetFillColor(Color{R: 1}) },
+			wantRGB: true,
+		},
+		{
+			// RGは塗りの色と同じく/DeviceRGB
+			name:    "stroke color",
+			draw:    func(t *testing.T, page *Page) { page.SetStrokeColor(Color{G: 1}) },
+			wantRGB: true,
+		},
+		{
+			// テキストは黒（0 0 0 rg）で描く
+			name: "text",
+			draw: func(t *testing.T, page *Page) {
+				if err := page.SetFont(FontHelvetica, 12); err != nil {
+					t.Fatal(err)
+				}
+				if err := page.DrawText("Hello", 50, 750); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantRGB: true,
+		},
+		{
+			name: "no color operators",
+			draw: func(t *testing.T, page *Page) {
+				page.SetLineWidth(2)
+				page.DrawLine(10, 10, 100, 100)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			if err := doc.SetConformance(PDFA2B); err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.SetOutputIntent(OutputIntentPDFX, modifiedProfile("prtr", "CMYK"), "FOGRA39"); err != nil {
+				t.Fatal(err)
+			}
+			page := doc.AddPage(PageSizeA4, Portrait)
+			tt.draw(t, page)
+
+			err := doc.WriteTo(&bytes.Buffer{})
+			if gotRGB := err != nil && strings.Contains(err.Error(), "uses DeviceRGB colors"); gotRGB != tt.wantRGB {
+				t.Errorf("WriteTo() error = %v, want DeviceRGB violation %v", err, tt.wantRGB)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package gopdf
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/ryomak/gopdf/internal/font"
 )
@@ -18,6 +19,14 @@ type Page struct {
 	fonts          map[string]font.StandardFont // fontKey -> font
 	ttfFonts       map[string]*TTFFont          // fontKey -> TTF font
 	images         []*Image                     // images used in this page
+	annotations    []pageAnnotation             // annotations (including form widgets)
+	doc            *Document                    // owning document (for structure tags)
+	nextMCID       int                          // next marked-content ID on this page
+	taggedRanges   [][2]int                     // content ranges enclosed in tagged marked content
+	templates      []*ImportedPage              // imported pages drawn as Form XObjects
+	source         *sourcePage                  // page copied from an existing PDF (nil = drawn with the Page API)
+	colorSpaces    map[string]bool              // color spaces set by color operators (rg/RG = DeviceRGB)
+	usesGS1        bool                         // content references the undefined /GS1 graphics state
 }
 
 // Width returns the page width in points.
@@ -36,6 +45,7 @@ func (p *Page) SetFont(f StandardFont, size float64) error {
 	internalFont := font.StandardFont(f)
 
 	p.currentFont = &internalFont
+	p.currentTTFFont = nil // Clear TTF font
 	p.fontSize = size
 
 	// フォントをページのフォントリストに追加
@@ -59,6 +69,7 @@ func (p *Page) drawTextInternal(
 	fmt.Fprintf(&p.content, "BT\n")
 	// Set text color to black (RGB: 0, 0, 0)
 	fmt.Fprintf(&p.content, "0 0 0 rg\n")
+	p.useColorSpace("DeviceRGB")
 	fmt.Fprintf(&p.content, "/%s %.2f Tf\n", fontKey, p.fontSize)
 	fmt.Fprintf(&p.content, "%.2f %.2f Td\n", x, y)
 
@@ -74,27 +85,33 @@ func (p *Page) drawTextInternal(
 // DrawText draws text at the specified position.
 // The position (x, y) is in PDF units (points), where (0, 0) is the bottom-left corner.
 func (p *Page) DrawText(text string, x, y float64) error {
+	fontKey, encodedText, useBrackets, err := p.encodeText(text)
+	if err != nil {
+		return err
+	}
+	p.drawTextInternal(x, y, fontKey, encodedText, useBrackets)
+	return nil
+}
+
+// encodeText は現在のフォントのリソース名と、Tjに渡す形にエンコードしたテキストを返す
+// useBracketsがtrueの場合は()で、falseの場合は<>で囲む
+func (p *Page) encodeText(text string) (fontKey, encodedText string, useBrackets bool, err error) {
 	// Support both standard fonts and TTF fonts
 	if p.currentTTFFont != nil {
 		// Use TTF font (supports Unicode)
-		fontKey := p.getTTFFontKey(p.currentTTFFont)
 		encodedText, err := p.textToGlyphIndices(text, p.currentTTFFont)
 		if err != nil {
-			return fmt.Errorf("failed to convert text to glyph indices: %w", err)
+			return "", "", false, fmt.Errorf("failed to convert text to glyph indices: %w", err)
 		}
-		p.drawTextInternal(x, y, fontKey, encodedText, false)
-		return nil
+		return p.getTTFFontKey(p.currentTTFFont), encodedText, false, nil
 	}
 
 	if p.currentFont != nil {
 		// Use standard font (ASCII/Latin-1 only)
-		fontKey := p.getFontKey(*p.currentFont)
-		encodedText := p.escapeString(text)
-		p.drawTextInternal(x, y, fontKey, encodedText, true)
-		return nil
+		return p.getFontKey(*p.currentFont), p.escapeString(text), true, nil
 	}
 
-	return fmt.Errorf("no font set; call SetFont or SetTTFFont before DrawText")
+	return "", "", false, fmt.Errorf("no font set; call SetFont or SetTTFFont before DrawText")
 }
 
 // getFontKey returns the font resource name (e.g., "F1", "F2") for a given font.
@@ -168,11 +185,22 @@ func (p *Page) SetLineWidth(width float64) {
 // SetStrokeColor sets the stroke color for subsequent drawing operations.
 func (p *Page) SetStrokeColor(c Color) {
 	fmt.Fprintf(&p.content, "%.2f %.2f %.2f RG\n", c.R, c.G, c.B)
+	p.useColorSpace("DeviceRGB")
 }
 
 // SetFillColor sets the fill color for subsequent drawing operations.
 func (p *Page) SetFillColor(c Color) {
 	fmt.Fprintf(&p.content, "%.2f %.2f %.2f rg\n", c.R, c.G, c.B)
+	p.useColorSpace("DeviceRGB")
+}
+
+// useColorSpace records that a color operator on this page sets a color in the named color space.
+// PDF/A validation checks this record instead of parsing the content stream.
+func (p *Page) useColorSpace(name string) {
+	if p.colorSpaces == nil {
+		p.colorSpaces = make(map[string]bool)
+	}
+	p.colorSpaces[name] = true
 }
 
 // SetLineCap sets the line cap style for subsequent drawing operations.
@@ -283,11 +311,7 @@ func (p *Page) DrawImage(img *Image, x, y, width, height float64) error {
 		return fmt.Errorf("image cannot be nil")
 	}
 
-	// Add image to the page's image list
-	p.images = append(p.images, img)
-
-	// Get image resource name (Im1, Im2, etc.)
-	imageKey := fmt.Sprintf("Im%d", len(p.images))
+	imageKey := p.addImage(img)
 
 	// Write PDF operators to content stream
 	// q: Save graphics state
@@ -302,6 +326,55 @@ func (p *Page) DrawImage(img *Image, x, y, width, height float64) error {
 	return nil
 }
 
+// addImage adds an image to the page's image list and returns its resource name (Im1, Im2, etc.).
+func (p *Page) addImage(img *Image) string {
+	p.images = append(p.images, img)
+	return fmt.Sprintf("Im%d", len(p.images))
+}
+
+// drawImageMatrix draws an image with a transformation matrix that maps the unit square
+// onto the page, for images that are rotated as well as scaled.
+func (p *Page) drawImageMatrix(img *Image, m [6]float64) error {
+	if img == nil {
+		return fmt.Errorf("image cannot be nil")
+	}
+
+	imageKey := p.addImage(img)
+	fmt.Fprintf(&p.content, "q\n")
+	fmt.Fprintf(&p.content, "%.4f %.4f %.4f %.4f %.2f %.2f cm\n", m[0], m[1], m[2], m[3], m[4], m[5])
+	fmt.Fprintf(&p.content, "/%s Do\n", imageKey)
+	fmt.Fprintf(&p.content, "Q\n")
+
+	return nil
+}
+
+// ImageOptions holds optional settings for DrawImageWithOptions.
+type ImageOptions struct {
+	// AltText describes the image for assistive technology. When set, the image
+	// is tagged as a Figure structure element with this text as /Alt.
+	AltText string
+}
+
+// DrawImageWithOptions draws an image like DrawImage, applying opts.
+func (p *Page) DrawImageWithOptions(img *Image, x, y, width, height float64, opts ImageOptions) error {
+	if opts.AltText == "" {
+		return p.DrawImage(img, x, y, width, height)
+	}
+
+	if err := p.BeginTag(StructFigure); err != nil {
+		return err
+	}
+	figure := p.doc.structure.current()
+	figure.alt = opts.AltText
+	figure.bbox = &[4]float64{x, y, x + width, y + height}
+	if err := p.DrawImage(img, x, y, width, height); err != nil {
+		// Close the Figure anyway so that later elements do not nest under it
+		p.EndTag()
+		return err
+	}
+	return p.EndTag()
+}
+
 // SetTTFFont sets the current TTF font and size for subsequent text operations.
 func (p *Page) SetTTFFont(f *TTFFont, size float64) error {
 	if f == nil {
@@ -381,25 +454,7 @@ func (p *Page) textToHexString(text string) string {
 // textToGlyphIndices converts UTF-8 text to glyph indices for TTF fonts
 // This ensures proper rendering by using actual glyph IDs from the font
 func (p *Page) textToGlyphIndices(text string, ttfFont *TTFFont) (string, error) {
-	var result string
-
-	for _, r := range text {
-		// Get the glyph index for this character
-		glyphIndex, err := ttfFont.internal.GetGlyphIndex(r)
-		if err != nil {
-			return "", fmt.Errorf("failed to get glyph index for character %c (U+%04X): %w", r, r, err)
-		}
-
-		// Record glyph usage for ToUnicode CMap generation
-		ttfFont.glyphsMutex.Lock()
-		ttfFont.usedGlyphs[uint16(glyphIndex)] = r
-		ttfFont.glyphsMutex.Unlock()
-
-		// Convert glyph index to 4-digit hex string
-		result += fmt.Sprintf("%04X", glyphIndex)
-	}
-
-	return result, nil
+	return ttfFont.encodeGlyphs(text)
 }
 
 // DrawRuby draws ruby (furigana) text above base text
@@ -577,6 +632,7 @@ func (p *Page) AddTextLayer(layer TextLayer) error {
 	if layer.Opacity < 1.0 {
 		fmt.Fprintf(&p.content, "q\n") // Save graphics state
 		fmt.Fprintf(&p.content, "/GS1 gs\n")
+		p.usesGS1 = true
 	}
 
 	// 各単語を描画
@@ -606,8 +662,22 @@ func (p *Page) AddTextLayer(layer TextLayer) error {
 		// テキストレンダリングモードを設定
 		fmt.Fprintf(&p.content, "%d Tr\n", layer.RenderMode)
 
-		// 位置を設定
-		fmt.Fprintf(&p.content, "%.2f %.2f Td\n", word.Bounds.X, word.Bounds.Y)
+		// 単語の幅をBoundsの幅に合わせる（選択したときのハイライトが単語に重なるように）
+		scaling, wordSpacing := p.textLayerFit(word, fontSize)
+		if wordSpacing != 0 {
+			fmt.Fprintf(&p.content, "%.3f Tw\n", wordSpacing)
+		}
+		if scaling != 100 {
+			fmt.Fprintf(&p.content, "%.2f Tz\n", scaling)
+		}
+
+		// 位置を設定（角度がある場合はベースラインの向きに回す）
+		if word.Angle != 0 {
+			cos, sin := word.rotation()
+			fmt.Fprintf(&p.content, "%.4f %.4f %.4f %.4f %.2f %.2f Tm\n", cos, sin, -sin, cos, word.Bounds.X, word.Bounds.Y)
+		} else {
+			fmt.Fprintf(&p.content, "%.2f %.2f Td\n", word.Bounds.X, word.Bounds.Y)
+		}
 
 		// テキストを描画
 		if p.currentTTFFont != nil {
@@ -618,6 +688,14 @@ func (p *Page) AddTextLayer(layer TextLayer) error {
 		}
 
 		fmt.Fprintf(&p.content, "ET\n") // End Text
+
+		// Tw・TzはETの後も残るため、後の描画のために戻す
+		if wordSpacing != 0 {
+			fmt.Fprintf(&p.content, "0 Tw\n")
+		}
+		if scaling != 100 {
+			fmt.Fprintf(&p.content, "100 Tz\n")
+		}
 	}
 
 	// Restore graphics state
@@ -625,9 +703,73 @@ func (p *Page) AddTextLayer(layer TextLayer) error {
 		fmt.Fprintf(&p.content, "Q\n")
 	}
 
+	if layer.Debug {
+		p.drawTextLayerOutlines(layer)
+	}
+
 	return nil
 }
 
+// textLayerFit は現在のフォントで描いた単語の幅がBoundsの幅になる水平スケーリング（%）と語間（Tw）を返す
+// 標準フォントで空白を含む単語をBoundsより狭く描く場合は、文字を伸ばさずに空白の幅で埋める
+// TTFフォントは2バイトのコードで書くためTwが効かず、常に水平スケーリングで合わせる
+func (p *Page) textLayerFit(word TextLayerWord, fontSize float64) (scaling, wordSpacing float64) {
+	natural := p.textLayerWordWidth(word.Text, fontSize)
+	if natural <= 0 || word.Bounds.Width <= 0 {
+		return 100, 0
+	}
+	extra := word.Bounds.Width - natural
+	if spaces := strings.Count(word.Text, " "); spaces > 0 && extra > 0 && p.currentTTFFont == nil {
+		return 100, extra / float64(spaces)
+	}
+	return 100 * word.Bounds.Width / natural, 0
+}
+
+// textLayerWordWidth は現在のフォントで単語を描いたときの幅（水平スケーリング100%）を返す
+func (p *Page) textLayerWordWidth(text string, fontSize float64) float64 {
+	if p.currentTTFFont != nil {
+		if width, err := p.currentTTFFont.TextWidth(text, fontSize); err == nil {
+			return width
+		}
+		return 0
+	}
+	return estimateTextWidth(text, fontSize, p.currentFont.Name())
+}
+
+// drawTextLayerOutlines は単語の矩形に、信頼度が低い単語は赤、それ以外は緑の枠を描く（TextLayer.Debug用）
+func (p *Page) drawTextLayerOutlines(layer TextLayer) {
+	low := layer.LowConfidence
+	if low == 0 {
+		low = DefaultLowConfidence
+	}
+
+	fmt.Fprintf(&p.content, "q\n")
+	fmt.Fprintf(&p.content, "%.2f w\n", textLayerOutlineWidth)
+	for _, word := range layer.Words {
+		if word.Text == "" {
+			continue
+		}
+		c := ColorGreen
+		if word.Confidence < low {
+			c = ColorRed
+		}
+		fmt.Fprintf(&p.content, "%.2f %.2f %.2f RG\n", c.R, c.G, c.B)
+		p.useColorSpace("DeviceRGB")
+		if word.Angle != 0 {
+			cos, sin := word.rotation()
+			fmt.Fprintf(&p.content, "q\n")
+			fmt.Fprintf(&p.content, "%.4f %.4f %.4f %.4f %.2f %.2f cm\n", cos, sin, -sin, cos, word.Bounds.X, word.Bounds.Y)
+			fmt.Fprintf(&p.content, "0 0 %.2f %.2f re\n", word.Bounds.Width, word.Bounds.Height)
+			fmt.Fprintf(&p.content, "S\n")
+			fmt.Fprintf(&p.content, "Q\n")
+			continue
+		}
+		fmt.Fprintf(&p.content, "%.2f %.2f %.2f %.2f re\n", word.Bounds.X, word.Bounds.Y, word.Bounds.Width, word.Bounds.Height)
+		fmt.Fprintf(&p.content, "S\n")
+	}
+	fmt.Fprintf(&p.content, "Q\n")
+}
+
 // AddTextLayerWords は個別の単語を追加する（簡易版）
 func (p *Page) AddTextLayerWords(words []TextLayerWord) error {
 	layer := NewTextLayer(words)
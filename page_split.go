@@ -4,6 +4,14 @@ package gopdf
 type SplitOptions struct {
 	MinSpacing float64 // ブロック間の最小間隔（デフォルト: 10.0）
 	PageMargin float64 // ページ端からのマージン（デフォルト: 50.0）
+	Margins    Margins // 分割したページの余白（0の辺はPageMargin）
+
+	// 以下はPageSplitOptionsと同じ（ゼロ値の場合はブロックを分割しない）
+	BreakTextBlocks bool                          // 収まらないテキストブロックを行の境界で分割する
+	Orphans         int                           // ページの終わりに残す最小の行数
+	Widows          int                           // 次のページに送る最小の行数
+	KeepWithNext    func(block ContentBlock) bool // 次のブロックと同じページに置くブロック（見出しなど）
+	Unbreakable     func(block ContentBlock) bool // 分割しないテキストブロック
 }
 
 // DefaultSplitOptions はデフォルトのページ分割オプションを返す
@@ -15,7 +23,7 @@ func DefaultSplitOptions() SplitOptions {
 }
 
 // SplitContentBlocksIntoPages はコンテンツブロックをページに分割する
-// 既存の layout.PageLayout.SplitIntoPages を使いやすくラップ
+// 既存の layout.PageLayout.SplitIntoPagesWithOptions を使いやすくラップ
 // 設計書: docs/cross_page_block_merging_design.md
 func SplitContentBlocksIntoPages(
 	blocks []ContentBlock,
@@ -32,8 +40,9 @@ func SplitContentBlocksIntoPages(
 
 	// PageLayoutを作成
 	pageLayout := &PageLayout{
-		Width:  pageSize.Width,
-		Height: pageSize.Height,
+		Width:   pageSize.Width,
+		Height:  pageSize.Height,
+		Margins: options.Margins,
 	}
 
 	// ブロックをTextBlocksとImagesに分類
@@ -46,8 +55,16 @@ func SplitContentBlocksIntoPages(
 		}
 	}
 
-	// 既存の SplitIntoPages を使用
-	return pageLayout.SplitIntoPages(pageSize.Height, options.MinSpacing, options.PageMargin)
+	return pageLayout.SplitIntoPagesWithOptions(PageSplitOptions{
+		MaxHeight:       pageSize.Height,
+		MinSpacing:      options.MinSpacing,
+		PageMargin:      options.PageMargin,
+		BreakTextBlocks: options.BreakTextBlocks,
+		Orphans:         options.Orphans,
+		Widows:          options.Widows,
+		KeepWithNext:    options.KeepWithNext,
+		Unbreakable:     options.Unbreakable,
+	})
 }
 
 // SplitContentBlocksIntoPagesWithDefaults はデフォルトオプションでページ分割する
@@ -2,6 +2,7 @@ package gopdf
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 )
@@ -256,3 +257,159 @@ func TestPage_AddTextLayer_Integration(t *testing.T) {
 
 	t.Logf("Created test PDF: %s (size: %d bytes)", tmpFile.Name(), stat.Size())
 }
+
+func TestPage_AddTextLayer_Debug(t *testing.T) {
+	tests := []struct {
+		name          string
+		debug         bool
+		lowConfidence float64
+		wantRed       bool
+		wantGreen     bool
+	}{
+		{name: "default threshold", debug: true, wantRed: true, wantGreen: true},
+		{name: "custom threshold", debug: true, lowConfidence: 0.95, wantRed: true},
+		{name: "disabled", debug: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			page := doc.AddPage(PageSizeA4, Portrait)
+			layer := NewTextLayer([]TextLayerWord{
+				{Text: "sure", Bounds: Rectangle{X: 100, Y: 700, Width: 50, Height: 12}, Confidence: 0.9},
+				{Text: "unsure", Bounds: Rectangle{X: 160, Y: 700, Width: 50, Height: 12}, Confidence: 0.3},
+			})
+			layer.Debug = tt.debug
+			layer.LowConfidence = tt.lowConfidence
+
+			if err := page.AddTextLayer(layer); err != nil {
+				t.Fatalf("AddTextLayer failed: %v", err)
+			}
+
+			content := page.content.String()
+			if got := strings.Contains(content, "1.00 0.00 0.00 RG"); got != tt.wantRed {
+				t.Errorf("red outline = %v, want %v", got, tt.wantRed)
+			}
+			if got := strings.Contains(content, "0.00 1.00 0.00 RG"); got != tt.wantGreen {
+				t.Errorf("green outline = %v, want %v", got, tt.wantGreen)
+			}
+			if got := strings.Count(content, " re\n"); tt.debug && got != 2 {
+				t.Errorf("outline count = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestPage_AddTextLayer_Angle(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	layer := NewTextLayer([]TextLayerWord{
+		{Text: "skewed", Bounds: Rectangle{X: 100, Y: 700, Width: 50, Height: 12}, Angle: 30},
+		{Text: "flat", Bounds: Rectangle{X: 100, Y: 600, Width: 50, Height: 12}},
+	})
+	layer.Debug = true
+
+	if err := page.AddTextLayer(layer); err != nil {
+		t.Fatalf("AddTextLayer failed: %v", err)
+	}
+
+	content := page.content.String()
+	// 傾いた単語はTmで回し、枠も同じ角度で描く。水平な単語はこれまで通りTd
+	for _, want := range []string{
+		"0.8660 0.5000 -0.5000 0.8660 100.00 700.00 Tm\n",
+		"100.00 600.00 Td\n",
+		"0.8660 0.5000 -0.5000 0.8660 100.00 700.00 cm\n0 0 50.00 12.00 re\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content does not contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestPage_AddTextLayer_Fit(t *testing.T) {
+	latin, err := defaultLatinTTFFont()
+	if err != nil {
+		t.Fatalf("defaultLatinTTFFont failed: %v", err)
+	}
+	ttfWidth, err := latin.TextWidth("Hello World", 12)
+	if err != nil {
+		t.Fatalf("TextWidth failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ttf     bool
+		text    string
+		width   float64
+		want    []string
+		notWant []string
+	}{
+		{
+			// Helveticaの概算幅は 5文字 × 12 × 0.6 = 36
+			name:    "wider box stretches glyphs",
+			text:    "Hello",
+			width:   72,
+			want:    []string{"200.00 Tz\n", "ET\n100 Tz\n"},
+			notWant: []string{" Tw\n"},
+		},
+		{
+			name:    "narrower box compresses glyphs",
+			text:    "Hello",
+			width:   18,
+			want:    []string{"50.00 Tz\n"},
+			notWant: []string{" Tw\n"},
+		},
+		{
+			// 11文字 × 7.2 = 79.2、残りの20.8を1つの空白で埋める
+			name:    "spaces absorb extra width",
+			text:    "Hello World",
+			width:   100,
+			want:    []string{"20.800 Tw\n", "ET\n0 Tw\n"},
+			notWant: []string{" Tz\n"},
+		},
+		{
+			name:    "matching width",
+			text:    "Hello",
+			width:   36,
+			notWant: []string{" Tz\n", " Tw\n"},
+		},
+		{
+			name:    "TTF font uses scaling only",
+			ttf:     true,
+			text:    "Hello World",
+			width:   ttfWidth * 1.5,
+			want:    []string{"150.00 Tz\n"},
+			notWant: []string{" Tw\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			page := doc.AddPage(PageSizeA4, Portrait)
+			if tt.ttf {
+				if err := page.SetTTFFont(latin, 12); err != nil {
+					t.Fatalf("SetTTFFont failed: %v", err)
+				}
+			}
+			err := page.AddTextLayer(NewTextLayer([]TextLayerWord{
+				{Text: tt.text, Bounds: Rectangle{X: 100, Y: 700, Width: tt.width, Height: 12}},
+			}))
+			if err != nil {
+				t.Fatalf("AddTextLayer failed: %v", err)
+			}
+
+			content := page.content.String()
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("content does not contain %q:\n%s", want, content)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(content, notWant) {
+					t.Errorf("content contains %q:\n%s", notWant, content)
+				}
+			}
+		})
+	}
+}
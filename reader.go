@@ -1,12 +1,15 @@
 package gopdf
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/internal/core"
 	"github.com/ryomak/gopdf/internal/reader"
+	"github.com/ryomak/gopdf/internal/security"
 	"github.com/ryomak/gopdf/layout"
 )
 
@@ -60,13 +63,51 @@ func (r *PDFReader) PageCount() int {
 }
 
 // Info はメタデータを返す
+// Info辞書にない項目はXMPメタデータ（カタログの/Metadata）から補完する
 func (r *PDFReader) Info() Metadata {
-	infoDict, err := r.r.GetInfo()
+	var metadata Metadata
+	if infoDict, err := r.r.GetInfo(); err == nil {
+		metadata = parseInfoDict(infoDict)
+	}
+
+	if xmp, err := r.XMPMetadata(); err == nil && xmp != nil {
+		mergeMetadata(&metadata, xmp)
+	}
+	return metadata
+}
+
+// XMPMetadata はカタログの/Metadataストリーム（XMP）を解析したメタデータを返す
+// XMPメタデータがない場合は nil を返す
+func (r *PDFReader) XMPMetadata() (*Metadata, error) {
+	packet, err := r.RawXMP()
+	if err != nil || packet == nil {
+		return nil, err
+	}
+
+	metadata, err := parseXMP(packet)
 	if err != nil {
-		return Metadata{}
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// RawXMP はカタログの/Metadataストリーム（XMPパケット）をデコードして返す
+// XMPメタデータがない場合は nil を返す
+func (r *PDFReader) RawXMP() ([]byte, error) {
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	stream, ok := r.r.Resolve(catalog[core.Name("Metadata")]).(*core.Stream)
+	if !ok {
+		return nil, nil
 	}
 
-	return parseInfoDict(infoDict)
+	data, err := r.r.DecodeStream(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode XMP metadata: %w", err)
+	}
+	return data, nil
 }
 
 // 型エイリアス（layout パッケージから）
@@ -86,11 +127,12 @@ const (
 // EncryptionInfo はPDF暗号化の情報
 type EncryptionInfo struct {
 	Filter  string // 暗号化フィルター（通常は "Standard"）
-	V       int    // アルゴリズムバージョン（1 or 2）
-	R       int    // リビジョン番号（2 or 3）
-	Length  int    // 鍵長（ビット単位、40 or 128）
+	V       int    // アルゴリズムバージョン（1, 2, 4 or 5）
+	R       int    // リビジョン番号（2, 3, 4, 5 or 6）
+	Length  int    // 鍵長（ビット単位、40, 128 or 256）
 	P       int32  // パーミッションフラグ
 	IsOwner bool   // オーナーとして認証されたか
+	Method  string // ストリームの暗号方式（"V2" = RC4, "AESV2", "AESV3"）
 }
 
 // ExtractPageText は指定されたページのテキストを抽出する（0-indexed）
@@ -147,6 +189,7 @@ func (r *PDFReader) ExtractText() (string, error) {
 }
 
 // ExtractPageTextElements は位置情報付きテキスト要素を抽出する（0-indexed）
+// /Rotateのあるページでは、表示される向きの座標を返す
 func (r *PDFReader) ExtractPageTextElements(pageNum int) ([]TextElement, error) {
 	// ページを取得
 	page, err := r.r.GetPage(pageNum)
@@ -154,6 +197,27 @@ func (r *PDFReader) ExtractPageTextElements(pageNum int) ([]TextElement, error)
 		return nil, err
 	}
 
+	// テキストを抽出
+	internalElements, err := r.extractContentTextElements(page)
+	if err != nil {
+		return nil, err
+	}
+
+	// 内部型から公開型に変換
+	elements := convertTextElements(internalElements)
+
+	// /Rotateがある場合は表示される向きの座標にする
+	if rotation := r.pageRotation(page); rotation != 0 {
+		width, height := r.getPageSize(page)
+		rotateTextElements(elements, rotation, width, height)
+	}
+
+	return elements, nil
+}
+
+// extractContentTextElements はページのコンテンツストリームからテキスト要素を抽出する
+// 座標は/Rotateを適用する前のもの
+func (r *PDFReader) extractContentTextElements(page core.Dictionary) ([]content.TextElement, error) {
 	// コンテンツストリームを取得
 	contentsData, err := r.r.GetPageContents(page)
 	if err != nil {
@@ -167,28 +231,8 @@ func (r *PDFReader) ExtractPageTextElements(pageNum int) ([]TextElement, error)
 		return nil, err
 	}
 
-	// テキストを抽出
 	extractor := content.NewTextExtractor(operations, r.r, page)
-	internalElements, err := extractor.Extract()
-	if err != nil {
-		return nil, err
-	}
-
-	// 内部型から公開型に変換
-	elements := make([]TextElement, len(internalElements))
-	for i, elem := range internalElements {
-		elements[i] = TextElement{
-			Text:   elem.Text,
-			X:      elem.X,
-			Y:      elem.Y,
-			Width:  estimateTextWidth(elem.Text, elem.Size, elem.Font),
-			Height: elem.Size,
-			Font:   elem.Font,
-			Size:   elem.Size,
-		}
-	}
-
-	return elements, nil
+	return extractor.Extract()
 }
 
 // ExtractAllTextElements は全ページのテキスト要素を抽出する
@@ -240,10 +284,10 @@ func (r *PDFReader) ExtractAllTextBlocks() (map[int][]TextBlock, error) {
 	return result, nil
 }
 
-// ExtractPageContentBlocks はテキストと画像を統合したコンテンツブロックを抽出（0-indexed）
+// ExtractPageContentBlocks はテキスト・画像・線や図（VectorBlock）・注釈（AnnotationBlock）のコンテンツブロックを抽出（0-indexed）
 // 設計書: docs/unified_content_grouping_design.md
 func (r *PDFReader) ExtractPageContentBlocks(pageNum int) ([]ContentBlock, error) {
-	// PageLayoutを取得（テキストと画像の両方）
+	// PageLayoutを取得
 	pageLayout, err := r.ExtractPageLayout(pageNum)
 	if err != nil {
 		return nil, err
@@ -340,6 +384,25 @@ func (r *PDFReader) ExtractAllImages() (map[int][]ImageInfo, error) {
 	return result, nil
 }
 
+// Repaired は壊れたxrefをファイルの走査で作り直して読み込んだかを返す
+// startxrefやxrefテーブルが壊れている、またはファイルが途中で切れている場合にtrueになる
+func (r *PDFReader) Repaired() bool {
+	return r.r.Repaired()
+}
+
+// SetCacheSize は読み込んだPDFオブジェクトを保持する数の上限を設定する
+// 上限を超えると最も長く使っていないオブジェクトから捨て、必要になったときにファイルから読み直す
+// 0以下は無制限（デフォルト）。ページ数の多いPDFを順に処理する場合に、メモリ使用量を一定に保てる
+func (r *PDFReader) SetCacheSize(n int) {
+	r.r.SetCacheSize(n)
+}
+
+// IsLinearized はPDFがリニアライズ（Web表示用に最適化）されているかを返す
+// リニアライズされている場合、ページはページツリーを辿らずにヒントテーブルから探す
+func (r *PDFReader) IsLinearized() bool {
+	return r.r.Linearization() != nil
+}
+
 // IsEncrypted はPDFが暗号化されているかどうかを確認する
 func (r *PDFReader) IsEncrypted() bool {
 	return r.r.IsEncrypted()
@@ -367,5 +430,75 @@ func (r *PDFReader) GetEncryptionInfo() *EncryptionInfo {
 		Length:  internalInfo.Length,
 		P:       internalInfo.P,
 		IsOwner: internalInfo.IsOwner,
+		Method:  internalInfo.StreamAlgorithm.String(),
+	}
+}
+
+// Permissions はPDFの/Pエントリに設定されたアクセス権限を返す
+// 暗号化されていないPDFではすべて許可（DefaultPermissions）を返す。
+// オーナーとして認証されているかは考慮しないため、実際に操作できるかは CanPrint などで確認する。
+func (r *PDFReader) Permissions() Permissions {
+	info := r.r.GetEncryptionInfo()
+	if info == nil {
+		return DefaultPermissions()
+	}
+
+	perms := permissionsFromInternal(security.FromInt32(info.P))
+	if info.R == 2 {
+		// リビジョン2ではビット9〜12は使われず、それぞれ対応する基本の権限に従う
+		perms.FillForms = perms.Annotate
+		perms.ExtractContent = perms.Copy
+		perms.Assemble = perms.Modify
+		perms.PrintHighQuality = perms.Print
+	}
+	return perms
+}
+
+// CanPrint は印刷できるかを返す
+func (r *PDFReader) CanPrint() bool {
+	return r.allows(func(p Permissions) bool { return p.Print })
+}
+
+// CanPrintHighQuality は高解像度で印刷できるかを返す
+func (r *PDFReader) CanPrintHighQuality() bool {
+	return r.allows(func(p Permissions) bool { return p.PrintHighQuality })
+}
+
+// CanModify は内容を変更できるかを返す
+func (r *PDFReader) CanModify() bool {
+	return r.allows(func(p Permissions) bool { return p.Modify })
+}
+
+// CanCopy はテキスト・グラフィックをコピーできるかを返す
+func (r *PDFReader) CanCopy() bool {
+	return r.allows(func(p Permissions) bool { return p.Copy })
+}
+
+// CanAnnotate は注釈を追加・変更できるかを返す
+func (r *PDFReader) CanAnnotate() bool {
+	return r.allows(func(p Permissions) bool { return p.Annotate })
+}
+
+// CanFillForms はフォームフィールドに入力できるかを返す
+func (r *PDFReader) CanFillForms() bool {
+	return r.allows(func(p Permissions) bool { return p.FillForms })
+}
+
+// CanExtractContent はアクセシビリティ目的でテキスト・グラフィックを抽出できるかを返す
+func (r *PDFReader) CanExtractContent() bool {
+	return r.allows(func(p Permissions) bool { return p.ExtractContent })
+}
+
+// CanAssemble はページの挿入・削除・回転ができるかを返す
+func (r *PDFReader) CanAssemble() bool {
+	return r.allows(func(p Permissions) bool { return p.Assemble })
+}
+
+// allows は権限の判定を行う。暗号化されていないPDFとオーナー認証済みのPDFではすべて許可される
+func (r *PDFReader) allows(check func(Permissions) bool) bool {
+	info := r.r.GetEncryptionInfo()
+	if info == nil || info.IsOwner {
+		return true
 	}
+	return check(r.Permissions())
 }
@@ -0,0 +1,405 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// AnnotationType は注釈の種類（/Subtype の値）
+type AnnotationType string
+
+const (
+	AnnotationTypeLink           AnnotationType = "Link"
+	AnnotationTypeText           AnnotationType = "Text" // 付箋（ノート）
+	AnnotationTypeFreeText       AnnotationType = "FreeText"
+	AnnotationTypeHighlight      AnnotationType = "Highlight"
+	AnnotationTypeUnderline      AnnotationType = "Underline"
+	AnnotationTypeStrikeOut      AnnotationType = "StrikeOut"
+	AnnotationTypeSquiggly       AnnotationType = "Squiggly"
+	AnnotationTypeSquare         AnnotationType = "Square"
+	AnnotationTypeCircle         AnnotationType = "Circle"
+	AnnotationTypeInk            AnnotationType = "Ink"
+	AnnotationTypeStamp          AnnotationType = "Stamp"
+	AnnotationTypePopup          AnnotationType = "Popup"
+	AnnotationTypeFileAttachment AnnotationType = "FileAttachment"
+	AnnotationTypeWidget         AnnotationType = "Widget"
+)
+
+// Point は2次元座標
+type Point struct {
+	X, Y float64
+}
+
+// Quad はテキストマークアップ注釈の四角形（/QuadPoints の1要素）
+// PDFの慣例に従い、左上・右上・左下・右下の順に格納される
+type Quad [4]Point
+
+// Bounds はQuadを囲む矩形を返す
+func (q Quad) Bounds() Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range q {
+		minX = math.Min(minX, p.X)
+		minY = math.Min(minY, p.Y)
+		maxX = math.Max(maxX, p.X)
+		maxY = math.Max(maxY, p.Y)
+	}
+	return Rectangle{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// Destination はドキュメント内の移動先
+type Destination struct {
+	PageNum int     // 移動先ページ（0-indexed、解決できない場合は-1）
+	Fit     string  // 表示方法（XYZ, Fit, FitH, FitV, FitR, FitB, FitBH, FitBV）
+	Left    float64 // 左端座標（XYZ, FitV, FitR）
+	Top     float64 // 上端座標（XYZ, FitH, FitR）
+	Zoom    float64 // 拡大率（XYZ、0 = 変更しない）
+	Name    string  // 名前付き移動先の場合の名前
+}
+
+// Annotation はページ上の注釈
+type Annotation struct {
+	Type     AnnotationType
+	Rect     Rectangle
+	Contents string    // 注釈のテキスト（/Contents）
+	Author   string    // 作成者（/T）
+	Subject  string    // 件名（/Subj）
+	Modified time.Time // 更新日時（/M）
+	Color    *Color    // 注釈の色（/C、未指定の場合はnil）
+	Flags    int       // 注釈フラグ（/F）
+
+	// Link注釈
+	URI         string       // URIアクションのリンク先
+	Destination *Destination // ドキュメント内リンクの移動先
+	RemoteFile  string       // GoToRアクションの参照先ファイル
+
+	// テキストマークアップ注釈（Highlight/Underline/StrikeOut/Squiggly）とLink注釈
+	QuadPoints []Quad
+
+	// テキスト注釈（付箋）
+	Open bool   // 初期状態で開いているか
+	Icon string // アイコン名（/Name）
+}
+
+// IsMarkup はテキストマークアップ注釈かどうかを返す
+func (a Annotation) IsMarkup() bool {
+	switch a.Type {
+	case AnnotationTypeHighlight, AnnotationTypeUnderline, AnnotationTypeStrikeOut, AnnotationTypeSquiggly:
+		return true
+	}
+	return false
+}
+
+// ExtractPageAnnotations は指定されたページの注釈を抽出する（0-indexed）
+func (r *PDFReader) ExtractPageAnnotations(pageNum int) ([]Annotation, error) {
+	page, err := r.r.GetPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	annots, ok := r.r.Resolve(page[core.Name("Annots")]).(core.Array)
+	if !ok {
+		return nil, nil
+	}
+
+	resolver := newDestinationResolver(r)
+	annotations := make([]Annotation, 0, len(annots))
+	for _, item := range annots {
+		dict, ok := r.r.Resolve(item).(core.Dictionary)
+		if !ok {
+			continue
+		}
+		annotations = append(annotations, r.parseAnnotation(dict, resolver))
+	}
+
+	return annotations, nil
+}
+
+// ExtractAllAnnotations は全ページの注釈を抽出する
+func (r *PDFReader) ExtractAllAnnotations() (map[int][]Annotation, error) {
+	pageCount := r.PageCount()
+	result := make(map[int][]Annotation)
+
+	for i := 0; i < pageCount; i++ {
+		annotations, err := r.ExtractPageAnnotations(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract annotations from page %d: %w", i, err)
+		}
+		if len(annotations) > 0 {
+			result[i] = annotations
+		}
+	}
+
+	return result, nil
+}
+
+// parseAnnotation は注釈辞書をAnnotationに変換する
+func (r *PDFReader) parseAnnotation(dict core.Dictionary, resolver *destinationResolver) Annotation {
+	subtype, _ := dict[core.Name("Subtype")].(core.Name)
+	annot := Annotation{
+		Type:     AnnotationType(subtype),
+		Rect:     r.parseRect(dict[core.Name("Rect")]),
+		Contents: rawTextString(r.r.Resolve(dict[core.Name("Contents")])),
+		Author:   rawTextString(r.r.Resolve(dict[core.Name("T")])),
+		Subject:  rawTextString(r.r.Resolve(dict[core.Name("Subj")])),
+		Color:    parseAnnotationColor(r.r.Resolve(dict[core.Name("C")])),
+	}
+
+	if m := rawTextString(r.r.Resolve(dict[core.Name("M")])); m != "" {
+		if t, err := parsePDFDate(m); err == nil {
+			annot.Modified = t
+		}
+	}
+	if f, ok := r.r.Resolve(dict[core.Name("F")]).(core.Integer); ok {
+		annot.Flags = int(f)
+	}
+
+	switch annot.Type {
+	case AnnotationTypeLink:
+		if action, ok := r.r.Resolve(dict[core.Name("A")]).(core.Dictionary); ok {
+			r.parseLinkAction(&annot, action, resolver)
+		} else if dest, ok := dict[core.Name("Dest")]; ok {
+			annot.Destination = resolver.resolve(dest)
+		}
+	case AnnotationTypeText:
+		if open, ok := r.r.Resolve(dict[core.Name("Open")]).(core.Boolean); ok {
+			annot.Open = bool(open)
+		}
+		if icon, ok := r.r.Resolve(dict[core.Name("Name")]).(core.Name); ok {
+			annot.Icon = string(icon)
+		}
+	}
+
+	// Link注釈も、複数行にわたるリンクの領域を/QuadPointsで表すことがある
+	if annot.IsMarkup() || annot.Type == AnnotationTypeLink {
+		annot.QuadPoints = parseQuadPoints(r.r.Resolve(dict[core.Name("QuadPoints")]))
+	}
+
+	return annot
+}
+
+// parseLinkAction はLink注釈のアクション辞書を解析する
+func (r *PDFReader) parseLinkAction(annot *Annotation, action core.Dictionary, resolver *destinationResolver) {
+	s, _ := action[core.Name("S")].(core.Name)
+	switch s {
+	case "URI":
+		annot.URI = rawTextString(r.r.Resolve(action[core.Name("URI")]))
+	case "GoTo":
+		annot.Destination = resolver.resolve(action[core.Name("D")])
+	case "GoToR":
+		annot.Destination = resolver.resolveRemote(action[core.Name("D")])
+		annot.RemoteFile = r.fileSpecName(action[core.Name("F")])
+	case "Launch":
+		annot.RemoteFile = r.fileSpecName(action[core.Name("F")])
+	}
+}
+
+// fileSpecName はファイル指定（文字列またはファイル指定辞書）からファイル名を取得する
+func (r *PDFReader) fileSpecName(obj core.Object) string {
+	switch v := r.r.Resolve(obj).(type) {
+	case core.String:
+		return rawTextString(v)
+	case core.Dictionary:
+		for _, key := range []string{"UF", "F", "Unix", "DOS", "Mac"} {
+			if name := rawTextString(r.r.Resolve(v[core.Name(key)])); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// parseRect は矩形配列 [llx lly urx ury] をRectangleに変換する
+func (r *PDFReader) parseRect(obj core.Object) Rectangle {
+	arr, ok := r.r.Resolve(obj).(core.Array)
+	if !ok || len(arr) < 4 {
+		return Rectangle{}
+	}
+	x1, y1 := toFloat64(r.r.Resolve(arr[0])), toFloat64(r.r.Resolve(arr[1]))
+	x2, y2 := toFloat64(r.r.Resolve(arr[2])), toFloat64(r.r.Resolve(arr[3]))
+	return Rectangle{
+		X:      math.Min(x1, x2),
+		Y:      math.Min(y1, y2),
+		Width:  math.Abs(x2 - x1),
+		Height: math.Abs(y2 - y1),
+	}
+}
+
+// parseQuadPoints は/QuadPoints配列をQuadのスライスに変換する
+func parseQuadPoints(obj core.Object) []Quad {
+	arr, ok := obj.(core.Array)
+	if !ok {
+		return nil
+	}
+
+	quads := make([]Quad, 0, len(arr)/8)
+	for i := 0; i+8 <= len(arr); i += 8 {
+		var q Quad
+		for j := 0; j < 4; j++ {
+			q[j] = Point{X: toFloat64(arr[i+j*2]), Y: toFloat64(arr[i+j*2+1])}
+		}
+		quads = append(quads, q)
+	}
+	return quads
+}
+
+// parseAnnotationColor は/C配列（0, 1, 3, 4要素）をRGBのColorに変換する
+func parseAnnotationColor(obj core.Object) *Color {
+	arr, ok := obj.(core.Array)
+	if !ok {
+		return nil
+	}
+
+	switch len(arr) {
+	case 1:
+		g := toFloat64(arr[0])
+		return &Color{R: g, G: g, B: g}
+	case 3:
+		return &Color{R: toFloat64(arr[0]), G: toFloat64(arr[1]), B: toFloat64(arr[2])}
+	case 4:
+		c, m, y, k := toFloat64(arr[0]), toFloat64(arr[1]), toFloat64(arr[2]), toFloat64(arr[3])
+		return &Color{R: (1 - c) * (1 - k), G: (1 - m) * (1 - k), B: (1 - y) * (1 - k)}
+	default:
+		// 0要素は透明（色なし）
+		return nil
+	}
+}
+
+// destinationResolver は移動先（明示的な配列または名前付き移動先）を解決する
+type destinationResolver struct {
+	r         *PDFReader
+	pageIndex map[int]int // ページのオブジェクト番号 -> ページ番号
+}
+
+func newDestinationResolver(r *PDFReader) *destinationResolver {
+	resolver := &destinationResolver{r: r, pageIndex: make(map[int]int)}
+	if refs, err := r.r.GetPageReferences(); err == nil {
+		for i, ref := range refs {
+			resolver.pageIndex[ref.ObjectNumber] = i
+		}
+	}
+	return resolver
+}
+
+// resolve は移動先オブジェクトを解決する
+func (d *destinationResolver) resolve(obj core.Object) *Destination {
+	switch v := d.r.r.Resolve(obj).(type) {
+	case core.Array:
+		return d.parseExplicit(v, false)
+	case core.Name:
+		return d.resolveNamed(string(v))
+	case core.String:
+		return d.resolveNamed(string(v))
+	case core.Dictionary:
+		// 名前付き移動先の値は /D を持つ辞書の場合がある
+		return d.resolve(v[core.Name("D")])
+	}
+	return nil
+}
+
+// resolveRemote は別ファイルへの移動先を解決する（ページは番号で指定される）
+func (d *destinationResolver) resolveRemote(obj core.Object) *Destination {
+	switch v := d.r.r.Resolve(obj).(type) {
+	case core.Array:
+		return d.parseExplicit(v, true)
+	case core.Name:
+		return &Destination{PageNum: -1, Name: string(v)}
+	case core.String:
+		return &Destination{PageNum: -1, Name: rawTextString(v)}
+	}
+	return nil
+}
+
+// resolveNamed は名前付き移動先をCatalogの/Destsまたは/Names/Destsから解決する
+func (d *destinationResolver) resolveNamed(name string) *Destination {
+	dest := &Destination{PageNum: -1, Name: name}
+
+	catalog, err := d.r.r.GetCatalog()
+	if err != nil {
+		return dest
+	}
+
+	var target core.Object
+	if names, ok := d.r.r.Resolve(catalog[core.Name("Names")]).(core.Dictionary); ok {
+		if v, found := d.r.r.LookupNameTree(names[core.Name("Dests")], name); found {
+			target = v
+		}
+	}
+	if target == nil {
+		if dests, ok := d.r.r.Resolve(catalog[core.Name("Dests")]).(core.Dictionary); ok {
+			target = dests[core.Name(name)]
+		}
+	}
+
+	if target == nil {
+		return dest
+	}
+
+	var arr core.Array
+	switch v := d.r.r.Resolve(target).(type) {
+	case core.Array:
+		arr = v
+	case core.Dictionary:
+		arr, _ = d.r.r.Resolve(v[core.Name("D")]).(core.Array)
+	}
+	if arr == nil {
+		return dest
+	}
+
+	resolved := d.parseExplicit(arr, false)
+	resolved.Name = name
+	return resolved
+}
+
+// parseExplicit は明示的な移動先配列 [page /XYZ left top zoom] などを解析する
+func (d *destinationResolver) parseExplicit(arr core.Array, remote bool) *Destination {
+	dest := &Destination{PageNum: -1}
+	if len(arr) == 0 {
+		return dest
+	}
+
+	switch p := arr[0].(type) {
+	case *core.Reference:
+		if idx, ok := d.pageIndex[p.ObjectNumber]; ok {
+			dest.PageNum = idx
+		}
+	case core.Integer:
+		// 別ファイルへの移動先ではページ番号（0-indexed）で指定される
+		if remote {
+			dest.PageNum = int(p)
+		}
+	}
+
+	if len(arr) > 1 {
+		if fit, ok := arr[1].(core.Name); ok {
+			dest.Fit = string(fit)
+		}
+	}
+
+	params := make([]float64, 0, 4)
+	for i := 2; i < len(arr) && i < 6; i++ {
+		params = append(params, toFloat64(arr[i]))
+	}
+	param := func(i int) float64 {
+		if i < len(params) {
+			return params[i]
+		}
+		return 0
+	}
+
+	switch dest.Fit {
+	case "XYZ":
+		dest.Left, dest.Top, dest.Zoom = param(0), param(1), param(2)
+	case "FitH", "FitBH":
+		dest.Top = param(0)
+	case "FitV", "FitBV":
+		dest.Left = param(0)
+	case "FitR":
+		dest.Left, dest.Top = param(0), param(3)
+	}
+
+	return dest
+}
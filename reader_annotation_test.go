@@ -0,0 +1,250 @@
+package gopdf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// buildAnnotatedPDF は2ページのPDFを生成し、1ページ目に指定された注釈を配置する
+// 注釈辞書内の "@page2" という名前は2ページ目への参照に置き換えられる
+func buildAnnotatedPDF(t *testing.T, annots []core.Dictionary, catalogExtra core.Dictionary) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := writer.NewWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+
+	pagesNum := w.ReserveObject()
+	page1Num := w.ReserveObject()
+	page2Num := w.ReserveObject()
+	page2Ref := &core.Reference{ObjectNumber: page2Num}
+
+	var replacePage func(obj core.Object) core.Object
+	replacePage = func(obj core.Object) core.Object {
+		switch v := obj.(type) {
+		case core.Name:
+			if v == "@page2" {
+				return page2Ref
+			}
+		case core.Array:
+			out := make(core.Array, len(v))
+			for i, item := range v {
+				out[i] = replacePage(item)
+			}
+			return out
+		case core.Dictionary:
+			out := core.Dictionary{}
+			for k, item := range v {
+				out[k] = replacePage(item)
+			}
+			return out
+		}
+		return obj
+	}
+
+	annotRefs := core.Array{}
+	for _, a := range annots {
+		num, err := w.AddObject(replacePage(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		annotRefs = append(annotRefs, &core.Reference{ObjectNumber: num})
+	}
+
+	pagesRef := &core.Reference{ObjectNumber: pagesNum}
+	mediaBox := core.Array{core.Integer(0), core.Integer(0), core.Integer(612), core.Integer(792)}
+	if err := w.WriteObject(page1Num, core.Dictionary{
+		core.Name("Type"):     core.Name("Page"),
+		core.Name("Parent"):   pagesRef,
+		core.Name("MediaBox"): mediaBox,
+		core.Name("Annots"):   annotRefs,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteObject(page2Num, core.Dictionary{
+		core.Name("Type"):     core.Name("Page"),
+		core.Name("Parent"):   pagesRef,
+		core.Name("MediaBox"): mediaBox,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteObject(pagesNum, core.Dictionary{
+		core.Name("Type"):  core.Name("Pages"),
+		core.Name("Kids"):  core.Array{&core.Reference{ObjectNumber: page1Num}, page2Ref},
+		core.Name("Count"): core.Integer(2),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := core.Dictionary{
+		core.Name("Type"):  core.Name("Catalog"),
+		core.Name("Pages"): pagesRef,
+	}
+	for k, v := range catalogExtra {
+		catalog[k] = replacePage(v)
+	}
+	catalogNum, err := w.AddObject(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteTrailer(core.Dictionary{
+		core.Name("Root"): &core.Reference{ObjectNumber: catalogNum},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPDFReader_ExtractPageAnnotations(t *testing.T) {
+	rect := core.Array{core.Integer(100), core.Integer(700), core.Integer(200), core.Integer(720)}
+	annots := []core.Dictionary{
+		{
+			core.Name("Type"):    core.Name("Annot"),
+			core.Name("Subtype"): core.Name("Link"),
+			core.Name("Rect"):    rect,
+			core.Name("A"): core.Dictionary{
+				core.Name("S"):   core.Name("URI"),
+				core.Name("URI"): core.String("https://example.com"),
+			},
+		},
+		{
+			core.Name("Type"):    core.Name("Annot"),
+			core.Name("Subtype"): core.Name("Link"),
+			core.Name("Rect"):    rect,
+			core.Name("Dest"): core.Array{
+				core.Name("@page2"), core.Name("XYZ"), core.Integer(0), core.Integer(792), core.Integer(0),
+			},
+		},
+		{
+			core.Name("Type"):    core.Name("Annot"),
+			core.Name("Subtype"): core.Name("Link"),
+			core.Name("Rect"):    rect,
+			core.Name("A"): core.Dictionary{
+				core.Name("S"): core.Name("GoTo"),
+				core.Name("D"): core.String("chapter1"),
+			},
+		},
+		{
+			core.Name("Type"):       core.Name("Annot"),
+			core.Name("Subtype"):    core.Name("Highlight"),
+			core.Name("Rect"):       rect,
+			core.Name("C"):          core.Array{core.Integer(1), core.Integer(1), core.Integer(0)},
+			core.Name("T"):          textString("レビュアー"),
+			core.Name("Contents"):   core.String("check this"),
+			core.Name("QuadPoints"): core.Array{core.Integer(100), core.Integer(720), core.Integer(200), core.Integer(720), core.Integer(100), core.Integer(700), core.Integer(200), core.Integer(700)},
+		},
+		{
+			core.Name("Type"):     core.Name("Annot"),
+			core.Name("Subtype"):  core.Name("Text"),
+			core.Name("Rect"):     rect,
+			core.Name("Contents"): core.String("note"),
+			core.Name("Open"):     core.Boolean(true),
+			core.Name("Name"):     core.Name("Comment"),
+			core.Name("M"):        core.String("D:20250129123045Z"),
+		},
+	}
+	catalogExtra := core.Dictionary{
+		core.Name("Names"): core.Dictionary{
+			core.Name("Dests"): core.Dictionary{
+				core.Name("Names"): core.Array{
+					core.String("chapter1"),
+					core.Array{core.Name("@page2"), core.Name("Fit")},
+				},
+			},
+		},
+	}
+
+	data := buildAnnotatedPDF(t, annots, catalogExtra)
+	reader, err := OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	defer reader.Close()
+
+	got, err := reader.ExtractPageAnnotations(0)
+	if err != nil {
+		t.Fatalf("ExtractPageAnnotations() failed: %v", err)
+	}
+	if len(got) != len(annots) {
+		t.Fatalf("got %d annotations, want %d", len(got), len(annots))
+	}
+
+	t.Run("uri link", func(t *testing.T) {
+		a := got[0]
+		if a.Type != AnnotationTypeLink || a.URI != "https://example.com" {
+			t.Errorf("got %+v", a)
+		}
+		if a.Rect != (Rectangle{X: 100, Y: 700, Width: 100, Height: 20}) {
+			t.Errorf("Rect = %+v", a.Rect)
+		}
+	})
+
+	t.Run("explicit destination", func(t *testing.T) {
+		d := got[1].Destination
+		if d == nil || d.PageNum != 1 || d.Fit != "XYZ" || d.Top != 792 {
+			t.Errorf("Destination = %+v", d)
+		}
+	})
+
+	t.Run("named destination", func(t *testing.T) {
+		d := got[2].Destination
+		if d == nil || d.PageNum != 1 || d.Fit != "Fit" || d.Name != "chapter1" {
+			t.Errorf("Destination = %+v", d)
+		}
+	})
+
+	t.Run("highlight", func(t *testing.T) {
+		a := got[3]
+		if !a.IsMarkup() || len(a.QuadPoints) != 1 {
+			t.Fatalf("got %+v", a)
+		}
+		if a.Author != "レビュアー" || a.Contents != "check this" {
+			t.Errorf("Author = %q, Contents = %q", a.Author, a.Contents)
+		}
+		if a.Color == nil || *a.Color != (Color{R: 1, G: 1, B: 0}) {
+			t.Errorf("Color = %+v", a.Color)
+		}
+		if b := a.QuadPoints[0].Bounds(); b != (Rectangle{X: 100, Y: 700, Width: 100, Height: 20}) {
+			t.Errorf("Quad bounds = %+v", b)
+		}
+	})
+
+	t.Run("note", func(t *testing.T) {
+		a := got[4]
+		if a.Type != AnnotationTypeText || !a.Open || a.Icon != "Comment" || a.Contents != "note" {
+			t.Errorf("got %+v", a)
+		}
+		if a.Modified.Year() != 2025 {
+			t.Errorf("Modified = %v", a.Modified)
+		}
+	})
+}
+
+func TestPDFReader_ExtractPageAnnotations_None(t *testing.T) {
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	got, err := reader.ExtractPageAnnotations(0)
+	if err != nil {
+		t.Fatalf("ExtractPageAnnotations() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d annotations, want 0", len(got))
+	}
+}
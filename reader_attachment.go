@@ -0,0 +1,87 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// ExtractAttachments はカタログの/Names/EmbeddedFilesに登録された埋め込みファイルを返す（名前ツリーの順）
+// Dataはフィルターを展開した内容。外部ファイルへの参照（/EFのないファイル指定）は含めない
+// 埋め込みファイルがない場合は空のスライスを返す
+func (r *PDFReader) ExtractAttachments() ([]FileAttachment, error) {
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	attachments := []FileAttachment{}
+	names, ok := r.r.Resolve(catalog[core.Name("Names")]).(core.Dictionary)
+	if !ok {
+		return attachments, nil
+	}
+	err = r.r.WalkNameTree(names[core.Name("EmbeddedFiles")], func(key string, value core.Object) error {
+		spec, ok := r.r.Resolve(value).(core.Dictionary)
+		if !ok {
+			return nil
+		}
+		a, ok, err := r.parseFileSpec(spec)
+		if err != nil {
+			return fmt.Errorf("failed to extract attachment %s: %w", key, err)
+		}
+		if !ok {
+			return nil
+		}
+		if a.Name == "" {
+			a.Name = key
+		}
+		attachments = append(attachments, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// parseFileSpec はファイル指定辞書から埋め込みファイルを読む
+// 埋め込みファイルストリーム（/EFの/UFまたは/F）がない場合はfalseを返す
+func (r *PDFReader) parseFileSpec(spec core.Dictionary) (FileAttachment, bool, error) {
+	ef, ok := r.r.Resolve(spec[core.Name("EF")]).(core.Dictionary)
+	if !ok {
+		return FileAttachment{}, false, nil
+	}
+	var stream *core.Stream
+	for _, key := range []string{"UF", "F"} {
+		if s, ok := r.r.Resolve(ef[core.Name(key)]).(*core.Stream); ok {
+			stream = s
+			break
+		}
+	}
+	if stream == nil {
+		return FileAttachment{}, false, nil
+	}
+
+	data, err := r.r.DecodeStream(stream)
+	if err != nil {
+		return FileAttachment{}, false, err
+	}
+
+	a := FileAttachment{
+		Name:        r.fileSpecName(spec),
+		Data:        data,
+		Description: rawTextString(r.r.Resolve(spec[core.Name("Desc")])),
+	}
+	if subtype, ok := stream.Dict[core.Name("Subtype")].(core.Name); ok {
+		a.MIMEType = string(subtype)
+	}
+	if rel, ok := spec[core.Name("AFRelationship")].(core.Name); ok {
+		a.Relationship = AFRelationship(rel)
+	}
+	if params, ok := r.r.Resolve(stream.Dict[core.Name("Params")]).(core.Dictionary); ok {
+		if modDate, err := parsePDFDate(rawTextString(r.r.Resolve(params[core.Name("ModDate")]))); err == nil {
+			a.ModDate = modDate
+		}
+	}
+	return a, true, nil
+}
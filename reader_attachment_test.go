@@ -0,0 +1,76 @@
+package gopdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPDFReader_ExtractAttachments(t *testing.T) {
+	modDate := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	attachments := []FileAttachment{
+		{
+			Name:         "report.csv",
+			Data:         []byte("month,total\n1,100\n"),
+			MIMEType:     "text/csv",
+			Description:  "月次集計",
+			ModDate:      modDate,
+			Relationship: AFRelationshipData,
+		},
+		{Name: "factur-x.xml", Data: []byte("<Invoice/>"), MIMEType: "text/xml", ModDate: modDate, Relationship: AFRelationshipAlternative},
+		{Name: "empty.bin", ModDate: modDate},
+	}
+
+	tests := []struct {
+		name        string
+		attachments []FileAttachment
+		want        []FileAttachment // 名前ツリーの順（名前の昇順）
+	}{
+		{"no attachments", nil, []FileAttachment{}},
+		{"attachments", attachments, []FileAttachment{attachments[2], attachments[1], attachments[0]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			for _, a := range tt.attachments {
+				if err := doc.AttachFile(a); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+
+			r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			got, err := r.ExtractAttachments()
+			if err != nil {
+				t.Fatalf("ExtractAttachments() failed: %v", err)
+			}
+			if got == nil || len(got) != len(tt.want) {
+				t.Fatalf("ExtractAttachments() returned %d attachments, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				a := got[i]
+				if a.Name != want.Name || a.MIMEType != want.MIMEType || a.Description != want.Description || a.Relationship != want.Relationship {
+					t.Errorf("attachment %d = {%q %q %q %q}, want {%q %q %q %q}", i,
+						a.Name, a.MIMEType, a.Description, a.Relationship,
+						want.Name, want.MIMEType, want.Description, want.Relationship)
+				}
+				if !bytes.Equal(a.Data, want.Data) {
+					t.Errorf("attachment %s: Data = %q, want %q", want.Name, a.Data, want.Data)
+				}
+				if !a.ModDate.Equal(want.ModDate) {
+					t.Errorf("attachment %s: ModDate = %v, want %v", want.Name, a.ModDate, want.ModDate)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package gopdf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// maxResourceDepth はフォームXObjectのリソースをたどる深さの上限（循環参照を防ぐ）
+const maxResourceDepth = 16
+
+// FontProgramType は埋め込まれたフォントプログラムの形式
+type FontProgramType string
+
+const (
+	// FontProgramType1 はType 1フォント（/FontFile）
+	FontProgramType1 FontProgramType = "Type1"
+	// FontProgramTrueType はTrueTypeフォント（/FontFile2）
+	FontProgramTrueType FontProgramType = "TrueType"
+	// FontProgramType1C はCFF形式のType 1フォント（/FontFile3 /Subtype /Type1C）
+	FontProgramType1C FontProgramType = "Type1C"
+	// FontProgramCIDFontType0C はCFF形式のCIDフォント（/FontFile3 /Subtype /CIDFontType0C）
+	FontProgramCIDFontType0C FontProgramType = "CIDFontType0C"
+	// FontProgramOpenType はOpenTypeフォント（/FontFile3 /Subtype /OpenType）
+	FontProgramOpenType FontProgramType = "OpenType"
+)
+
+// PageFont はページで使われるフォントの情報
+type PageFont struct {
+	Name        string          // リソース名（/Resources /Font のキー。例: "F1"）
+	BaseFont    string          // /BaseFont（サブセットの場合は "ABCDEF+" の接頭辞を含む）
+	Subtype     string          // /Subtype（Type1, TrueType, Type0, Type3 など）
+	Encoding    string          // 宣言されたエンコーディング（/Encodingの名前、辞書の場合は/BaseEncoding、CMapストリームの場合は/CMapName）
+	Subset      bool            // サブセット化されているか（BaseFontが "ABCDEF+" で始まる）
+	ToUnicode   bool            // /ToUnicodeがあるか
+	ProgramType FontProgramType // 埋め込まれたフォントプログラムの形式（埋め込まれていない場合は空）
+	Program     []byte          // フォントプログラム（フィルターを展開した内容。埋め込まれていない場合はnil）
+}
+
+// Embedded はフォントプログラムが埋め込まれているかを返す
+func (f PageFont) Embedded() bool {
+	return f.ProgramType != ""
+}
+
+// ExtractFonts は指定されたページのフォントを返す（0-indexed）
+// ページの/Resources /Fontに加えて、ページから使われるフォームXObjectのフォントも含める
+// 同じフォント辞書は1回だけ返し、リソース名の順に並べる（フォームXObjectのフォントはページのフォントの後）
+func (r *PDFReader) ExtractFonts(pageNum int) ([]PageFont, error) {
+	page, err := r.r.GetPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	fonts := []PageFont{}
+	seenFonts := make(map[int]bool)
+	seenForms := make(map[int]bool)
+	var walk func(resources core.Dictionary, depth int) error
+	walk = func(resources core.Dictionary, depth int) error {
+		if depth > maxResourceDepth {
+			return nil
+		}
+		fontDict, _ := r.r.Resolve(resources[core.Name("Font")]).(core.Dictionary)
+		for _, name := range sortedDictKeys(fontDict) {
+			if ref, ok := fontDict[name].(*core.Reference); ok {
+				if seenFonts[ref.ObjectNumber] {
+					continue
+				}
+				seenFonts[ref.ObjectNumber] = true
+			}
+			font, ok := r.r.Resolve(fontDict[name]).(core.Dictionary)
+			if !ok {
+				continue
+			}
+			f, err := r.parsePageFont(string(name), font)
+			if err != nil {
+				return fmt.Errorf("failed to read font %s: %w", name, err)
+			}
+			fonts = append(fonts, f)
+		}
+
+		xObjects, _ := r.r.Resolve(resources[core.Name("XObject")]).(core.Dictionary)
+		for _, name := range sortedDictKeys(xObjects) {
+			ref, ok := xObjects[name].(*core.Reference)
+			if !ok || seenForms[ref.ObjectNumber] {
+				continue
+			}
+			seenForms[ref.ObjectNumber] = true
+			form, ok := r.r.Resolve(ref).(*core.Stream)
+			if !ok || form.Dict[core.Name("Subtype")] != core.Name("Form") {
+				continue
+			}
+			formResources, ok := r.r.Resolve(form.Dict[core.Name("Resources")]).(core.Dictionary)
+			if !ok {
+				continue
+			}
+			if err := walk(formResources, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	resources, _ := r.r.Resolve(page[core.Name("Resources")]).(core.Dictionary)
+	if err := walk(resources, 0); err != nil {
+		return nil, err
+	}
+	return fonts, nil
+}
+
+// parsePageFont はフォント辞書からフォントの情報を読む
+// 複合フォント（Type0）のフォント記述子は/DescendantFontsのCIDフォントから探す
+func (r *PDFReader) parsePageFont(name string, font core.Dictionary) (PageFont, error) {
+	f := PageFont{Name: name}
+	if baseFont, ok := font[core.Name("BaseFont")].(core.Name); ok {
+		f.BaseFont = string(baseFont)
+	}
+	if subtype, ok := font[core.Name("Subtype")].(core.Name); ok {
+		f.Subtype = string(subtype)
+	}
+	_, f.ToUnicode = font[core.Name("ToUnicode")]
+	f.Subset = isSubsetFontName(f.BaseFont)
+
+	switch enc := r.r.Resolve(font[core.Name("Encoding")]).(type) {
+	case core.Name:
+		f.Encoding = string(enc)
+	case core.Dictionary:
+		if base, ok := enc[core.Name("BaseEncoding")].(core.Name); ok {
+			f.Encoding = string(base)
+		}
+	case *core.Stream:
+		if cmapName, ok := enc.Dict[core.Name("CMapName")].(core.Name); ok {
+			f.Encoding = string(cmapName)
+		}
+	}
+
+	descriptorOwner := font
+	if f.Subtype == "Type0" {
+		if descendants, ok := r.r.Resolve(font[core.Name("DescendantFonts")]).(core.Array); ok && len(descendants) > 0 {
+			if cidFont, ok := r.r.Resolve(descendants[0]).(core.Dictionary); ok {
+				descriptorOwner = cidFont
+			}
+		}
+	}
+	descriptor, ok := r.r.Resolve(descriptorOwner[core.Name("FontDescriptor")]).(core.Dictionary)
+	if !ok {
+		return f, nil
+	}
+
+	for _, entry := range []struct {
+		key         string
+		programType FontProgramType
+	}{
+		{"FontFile", FontProgramType1},
+		{"FontFile2", FontProgramTrueType},
+		{"FontFile3", ""},
+	} {
+		stream, ok := r.r.Resolve(descriptor[core.Name(entry.key)]).(*core.Stream)
+		if !ok {
+			continue
+		}
+		data, err := r.r.DecodeStream(stream)
+		if err != nil {
+			return f, err
+		}
+		f.ProgramType = entry.programType
+		if f.ProgramType == "" {
+			// /FontFile3の形式はストリームの/Subtypeで決まる（ない場合はType1Cとみなす）
+			f.ProgramType = FontProgramType1C
+			if subtype, ok := stream.Dict[core.Name("Subtype")].(core.Name); ok {
+				f.ProgramType = FontProgramType(subtype)
+			}
+		}
+		f.Program = data
+		break
+	}
+	return f, nil
+}
+
+// isSubsetFontName はフォント名がサブセットの接頭辞（大文字6文字と "+"）で始まるかを返す
+func isSubsetFontName(name string) bool {
+	if len(name) < 7 || name[6] != '+' {
+		return false
+	}
+	for i := 0; i < 6; i++ {
+		if name[i] < 'A' || name[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedDictKeys は辞書のキーを昇順で返す
+func sortedDictKeys(dict core.Dictionary) []core.Name {
+	keys := make([]core.Name, 0, len(dict))
+	for k := range dict {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,208 @@
+package gopdf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// buildFontsPDF は次のフォントを使う1ページのPDFを生成する
+// F1: 埋め込みなしのHelvetica、F2: サブセットのTrueType（/FontFile2、/Differences付き）、
+// F3: CIDフォントを持つType0（/FontFile3 /CIDFontType0C）、フォームXObjectのF4: Type 1（/FontFile）とF1の重複
+func buildFontsPDF(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := writer.NewWriter(&buf)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	add := func(obj core.Object) *core.Reference {
+		num, err := w.AddObject(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &core.Reference{ObjectNumber: num}
+	}
+	fontFile := func(subtype string, data string) *core.Reference {
+		compressed, err := compressWithZlib([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dict := core.Dictionary{
+			core.Name("Filter"): core.Name("FlateDecode"),
+			core.Name("Length"): core.Integer(len(compressed)),
+		}
+		if subtype != "" {
+			dict[core.Name("Subtype")] = core.Name(subtype)
+		}
+		return add(&core.Stream{Dict: dict, Data: compressed})
+	}
+
+	helvetica := add(core.Dictionary{
+		core.Name("Type"):     core.Name("Font"),
+		core.Name("Subtype"):  core.Name("Type1"),
+		core.Name("BaseFont"): core.Name("Helvetica"),
+		core.Name("Encoding"): core.Name("WinAnsiEncoding"),
+	})
+	trueType := add(core.Dictionary{
+		core.Name("Type"):     core.Name("Font"),
+		core.Name("Subtype"):  core.Name("TrueType"),
+		core.Name("BaseFont"): core.Name("ABCDEF+Arial"),
+		core.Name("Encoding"): core.Dictionary{
+			core.Name("BaseEncoding"): core.Name("MacRomanEncoding"),
+			core.Name("Differences"):  core.Array{core.Integer(1), core.Name("A")},
+		},
+		core.Name("FontDescriptor"): add(core.Dictionary{
+			core.Name("Type"):      core.Name("FontDescriptor"),
+			core.Name("FontName"):  core.Name("ABCDEF+Arial"),
+			core.Name("FontFile2"): fontFile("", "truetype program"),
+		}),
+	})
+	cidFont := add(core.Dictionary{
+		core.Name("Type"):     core.Name("Font"),
+		core.Name("Subtype"):  core.Name("CIDFontType0"),
+		core.Name("BaseFont"): core.Name("KozMinPr6N-Regular"),
+		core.Name("FontDescriptor"): add(core.Dictionary{
+			core.Name("Type"):      core.Name("FontDescriptor"),
+			core.Name("FontFile3"): fontFile("CIDFontType0C", "cff program"),
+		}),
+	})
+	type0 := add(core.Dictionary{
+		core.Name("Type"):            core.Name("Font"),
+		core.Name("Subtype"):         core.Name("Type0"),
+		core.Name("BaseFont"):        core.Name("KozMinPr6N-Regular"),
+		core.Name("Encoding"):        core.Name("UniJIS-UTF16-H"),
+		core.Name("DescendantFonts"): core.Array{cidFont},
+		core.Name("ToUnicode"):       fontFile("", "cmap"),
+	})
+	type1 := add(core.Dictionary{
+		core.Name("Type"):     core.Name("Font"),
+		core.Name("Subtype"):  core.Name("Type1"),
+		core.Name("BaseFont"): core.Name("Custom+Font"),
+		core.Name("FontDescriptor"): add(core.Dictionary{
+			core.Name("Type"):     core.Name("FontDescriptor"),
+			core.Name("FontFile"): fontFile("", "type1 program"),
+		}),
+	})
+
+	formContents := []byte("BT /F4 10 Tf (form) Tj ET")
+	form := add(&core.Stream{
+		Dict: core.Dictionary{
+			core.Name("Type"):    core.Name("XObject"),
+			core.Name("Subtype"): core.Name("Form"),
+			core.Name("BBox"):    core.Array{core.Integer(0), core.Integer(0), core.Integer(100), core.Integer(100)},
+			core.Name("Length"):  core.Integer(len(formContents)),
+			core.Name("Resources"): core.Dictionary{
+				core.Name("Font"): core.Dictionary{core.Name("F4"): type1, core.Name("H"): helvetica},
+			},
+		},
+		Data: formContents,
+	})
+
+	contents := []byte("BT /F1 12 Tf (a) Tj ET /Fm1 Do")
+	contentsRef := add(&core.Stream{
+		Dict: core.Dictionary{core.Name("Length"): core.Integer(len(contents))},
+		Data: contents,
+	})
+
+	pagesNum := w.ReserveObject()
+	page := add(core.Dictionary{
+		core.Name("Type"):     core.Name("Page"),
+		core.Name("Parent"):   &core.Reference{ObjectNumber: pagesNum},
+		core.Name("MediaBox"): core.Array{core.Integer(0), core.Integer(0), core.Integer(612), core.Integer(792)},
+		core.Name("Contents"): contentsRef,
+		core.Name("Resources"): core.Dictionary{
+			core.Name("Font"): core.Dictionary{
+				core.Name("F1"): helvetica,
+				core.Name("F2"): trueType,
+				core.Name("F3"): type0,
+			},
+			core.Name("XObject"): core.Dictionary{core.Name("Fm1"): form},
+		},
+	})
+	if err := w.WriteObject(pagesNum, core.Dictionary{
+		core.Name("Type"):  core.Name("Pages"),
+		core.Name("Kids"):  core.Array{page},
+		core.Name("Count"): core.Integer(1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	catalog := add(core.Dictionary{
+		core.Name("Type"):  core.Name("Catalog"),
+		core.Name("Pages"): &core.Reference{ObjectNumber: pagesNum},
+	})
+	if err := w.WriteTrailer(core.Dictionary{core.Name("Root"): catalog}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPDFReader_ExtractFonts(t *testing.T) {
+	r, err := OpenReader(bytes.NewReader(buildFontsPDF(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	fonts, err := r.ExtractFonts(0)
+	if err != nil {
+		t.Fatalf("ExtractFonts() failed: %v", err)
+	}
+
+	want := []PageFont{
+		{Name: "F1", BaseFont: "Helvetica", Subtype: "Type1", Encoding: "WinAnsiEncoding"},
+		{Name: "F2", BaseFont: "ABCDEF+Arial", Subtype: "TrueType", Encoding: "MacRomanEncoding", Subset: true,
+			ProgramType: FontProgramTrueType, Program: []byte("truetype program")},
+		{Name: "F3", BaseFont: "KozMinPr6N-Regular", Subtype: "Type0", Encoding: "UniJIS-UTF16-H", ToUnicode: true,
+			ProgramType: FontProgramCIDFontType0C, Program: []byte("cff program")},
+		{Name: "F4", BaseFont: "Custom+Font", Subtype: "Type1",
+			ProgramType: FontProgramType1, Program: []byte("type1 program")},
+	}
+	if len(fonts) != len(want) {
+		t.Fatalf("ExtractFonts() returned %d fonts, want %d: %+v", len(fonts), len(want), fonts)
+	}
+	for i, w := range want {
+		t.Run(w.Name, func(t *testing.T) {
+			f := fonts[i]
+			if f.Name != w.Name || f.BaseFont != w.BaseFont || f.Subtype != w.Subtype || f.Encoding != w.Encoding ||
+				f.Subset != w.Subset || f.ToUnicode != w.ToUnicode || f.ProgramType != w.ProgramType {
+				t.Errorf("font = %+v, want %+v", f, w)
+			}
+			if !bytes.Equal(f.Program, w.Program) {
+				t.Errorf("Program = %q, want %q", f.Program, w.Program)
+			}
+			if f.Embedded() != (w.Program != nil) {
+				t.Errorf("Embedded() = %v", f.Embedded())
+			}
+		})
+	}
+
+	if _, err := r.ExtractFonts(1); err == nil {
+		t.Error("ExtractFonts(1) succeeded, want error")
+	}
+}
+
+func TestIsSubsetFontName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"ABCDEF+Arial", true},
+		{"AAAAAA+", true},
+		{"Arial", false},
+		{"abcdef+Arial", false},
+		{"ABCDE+Arial", false},
+		{"Custom+Font", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSubsetFontName(tt.name); got != tt.want {
+				t.Errorf("isSubsetFontName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
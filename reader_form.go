@@ -0,0 +1,227 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// ボタン・選択フィールドの種類を表すフィールドフラグ（/Ff）のビット
+const (
+	fieldFlagRadio       = 1 << 15
+	fieldFlagPushbutton  = 1 << 16
+	fieldFlagCombo       = 1 << 17
+	fieldFlagMultiSelect = 1 << 21
+)
+
+// FormFieldType はフォームフィールドの種類
+type FormFieldType string
+
+const (
+	FormFieldText       FormFieldType = "Text"       // テキスト（/FT /Tx）
+	FormFieldCheckBox   FormFieldType = "CheckBox"   // チェックボックス（/FT /Btn）
+	FormFieldRadio      FormFieldType = "Radio"      // ラジオボタン（/FT /Btn、Radioフラグ）
+	FormFieldPushButton FormFieldType = "PushButton" // プッシュボタン（/FT /Btn、Pushbuttonフラグ）
+	FormFieldComboBox   FormFieldType = "ComboBox"   // コンボボックス（/FT /Ch、Comboフラグ）
+	FormFieldListBox    FormFieldType = "ListBox"    // リストボックス（/FT /Ch）
+	FormFieldSignature  FormFieldType = "Signature"  // 署名（/FT /Sig）
+)
+
+// FormFieldWidget はフォームフィールドのウィジェット注釈（ページ上の表示位置）
+type FormFieldWidget struct {
+	PageNum int       // ページ番号（0-indexed、解決できない場合は-1）
+	Rect    Rectangle // 表示位置（/Rect）
+}
+
+// FormField はフォームフィールドとその現在の値
+type FormField struct {
+	Name     string        // 完全名（親フィールドの名前とピリオドで連結した名前）
+	Type     FormFieldType // フィールドの種類（/FTのない場合は空）
+	Value    string        // 現在の値（/V）。チェックボックスとラジオボタンはオンの状態名（オフは"Off"）
+	Values   []string      // 複数選択のリストボックスで選ばれている値（/Vが配列の場合）
+	Options  []string      // 選択肢の書き出し値（/Opt）
+	ReadOnly bool          // 読み取り専用
+	Required bool          // 入力必須
+	Widgets  []FormFieldWidget
+}
+
+// ExtractFormFields はAcroFormのフォームフィールドを返す（フィールド階層の順）
+// /V、/Ff、/Optは親フィールドから継承した値を含める。署名フィールドの/V（署名辞書）は値に含めない
+// フォームがない場合は空のスライスを返す
+func (r *PDFReader) ExtractFormFields() ([]FormField, error) {
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	result := []FormField{}
+	acroForm, ok := r.r.Resolve(catalog[core.Name("AcroForm")]).(core.Dictionary)
+	if !ok {
+		return result, nil
+	}
+
+	locator := newWidgetLocator(r)
+	fields, _ := r.r.Resolve(acroForm[core.Name("Fields")]).(core.Array)
+	err = r.walkFormFields(fields, func(name string, field core.Dictionary, fieldType string) error {
+		flags := toInt(r.inheritedFieldValue(field, "Ff"))
+		f := FormField{
+			Name:     name,
+			Type:     formFieldType(fieldType, flags),
+			ReadOnly: flags&fieldFlagReadOnly != 0,
+			Required: flags&fieldFlagRequired != 0,
+		}
+
+		if f.Type != FormFieldSignature {
+			switch v := r.r.Resolve(r.inheritedFieldValue(field, "V")).(type) {
+			case core.String:
+				f.Value = rawTextString(v)
+			case core.Name:
+				f.Value = string(v)
+			case core.Array:
+				for _, item := range v {
+					f.Values = append(f.Values, rawTextString(r.r.Resolve(item)))
+				}
+				if len(f.Values) > 0 && flags&fieldFlagMultiSelect == 0 {
+					f.Value = f.Values[0]
+				}
+			}
+		}
+
+		opts, _ := r.r.Resolve(r.inheritedFieldValue(field, "Opt")).(core.Array)
+		for _, opt := range opts {
+			// 選択肢は文字列、または [書き出し値 表示名] の配列
+			if pair, ok := r.r.Resolve(opt).(core.Array); ok && len(pair) > 0 {
+				opt = pair[0]
+			}
+			f.Options = append(f.Options, rawTextString(r.r.Resolve(opt)))
+		}
+
+		f.Widgets = locator.widgets(field)
+		result = append(result, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// formFieldType は/FTとフィールドフラグからフィールドの種類を決める
+func formFieldType(fieldType string, flags int) FormFieldType {
+	switch fieldType {
+	case "Tx":
+		return FormFieldText
+	case "Btn":
+		switch {
+		case flags&fieldFlagPushbutton != 0:
+			return FormFieldPushButton
+		case flags&fieldFlagRadio != 0:
+			return FormFieldRadio
+		}
+		return FormFieldCheckBox
+	case "Ch":
+		if flags&fieldFlagCombo != 0 {
+			return FormFieldComboBox
+		}
+		return FormFieldListBox
+	case "Sig":
+		return FormFieldSignature
+	}
+	return ""
+}
+
+// inheritedFieldValue はフィールドのkeyの値を返す。ない場合は/Parentを辿って親の値を返す
+func (r *PDFReader) inheritedFieldValue(field core.Dictionary, key string) core.Object {
+	node := field
+	for depth := 0; depth <= maxFieldDepth; depth++ {
+		if v, ok := node[core.Name(key)]; ok {
+			return v
+		}
+		parent, ok := r.r.Resolve(node[core.Name("Parent")]).(core.Dictionary)
+		if !ok {
+			return nil
+		}
+		node = parent
+	}
+	return nil
+}
+
+// toInt は整数オブジェクトの値を返す（整数でなければ0）
+func toInt(obj core.Object) int {
+	n, _ := obj.(core.Integer)
+	return int(n)
+}
+
+// widgetLocator はウィジェット注釈のページ番号を求める
+type widgetLocator struct {
+	r         *PDFReader
+	pageIndex map[int]int // ページのオブジェクト番号 -> ページ番号
+	annotPage map[int]int // 注釈のオブジェクト番号 -> ページ番号（/Pのない注釈のため、必要になったときに作る）
+}
+
+func newWidgetLocator(r *PDFReader) *widgetLocator {
+	locator := &widgetLocator{r: r, pageIndex: make(map[int]int)}
+	if refs, err := r.r.GetPageReferences(); err == nil {
+		for i, ref := range refs {
+			locator.pageIndex[ref.ObjectNumber] = i
+		}
+	}
+	return locator
+}
+
+// widgets は終端フィールドのウィジェット注釈を返す
+// フィールドとウィジェットが1つの辞書の場合はフィールド自身、そうでなければ/Kidsがウィジェット
+func (l *widgetLocator) widgets(field core.Dictionary) []FormFieldWidget {
+	kids, ok := l.r.r.Resolve(field[core.Name("Kids")]).(core.Array)
+	if !ok {
+		if _, hasRect := field[core.Name("Rect")]; !hasRect {
+			return nil
+		}
+		return []FormFieldWidget{l.widget(field, nil)}
+	}
+
+	var widgets []FormFieldWidget
+	for _, kid := range kids {
+		ref, _ := kid.(*core.Reference)
+		if widget, ok := l.r.r.Resolve(kid).(core.Dictionary); ok {
+			widgets = append(widgets, l.widget(widget, ref))
+		}
+	}
+	return widgets
+}
+
+func (l *widgetLocator) widget(widget core.Dictionary, ref *core.Reference) FormFieldWidget {
+	w := FormFieldWidget{PageNum: -1, Rect: l.r.parseRect(widget[core.Name("Rect")])}
+	if p, ok := widget[core.Name("P")].(*core.Reference); ok {
+		if i, ok := l.pageIndex[p.ObjectNumber]; ok {
+			w.PageNum = i
+			return w
+		}
+	}
+	if ref != nil {
+		if i, ok := l.annotationPages()[ref.ObjectNumber]; ok {
+			w.PageNum = i
+		}
+	}
+	return w
+}
+
+// annotationPages は各ページの/Annotsから注釈のオブジェクト番号 -> ページ番号を作る
+func (l *widgetLocator) annotationPages() map[int]int {
+	if l.annotPage != nil {
+		return l.annotPage
+	}
+	l.annotPage = make(map[int]int)
+	for i := 0; i < l.r.PageCount(); i++ {
+		page, err := l.r.r.GetPage(i)
+		if err != nil {
+			continue
+		}
+		annots, _ := l.r.r.Resolve(page[core.Name("Annots")]).(core.Array)
+		for _, a := range annots {
+			if ref, ok := a.(*core.Reference); ok {
+				l.annotPage[ref.ObjectNumber] = i
+			}
+		}
+	}
+	return l.annotPage
+}
@@ -0,0 +1,137 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// buildRawPDF はobjects[i]をオブジェクト番号i+1として並べたPDFを生成する（1番目がCatalog）
+func buildRawPDF(objects []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xrefStart := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, xrefStart)
+	return buf.Bytes()
+}
+
+func TestPDFReader_ExtractFormFields(t *testing.T) {
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R /AcroForm << /Fields [4 0 R 5 0 R 8 0 R 9 0 R 10 0 R 11 0 R] >> >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Annots [4 0 R 6 0 R 7 0 R 12 0 R] >>",
+		// フィールドとウィジェットを兼ねるテキストフィールド（入力必須）
+		"<< /Type /Annot /Subtype /Widget /FT /Tx /T (name) /V <FEFF5C717530> /Ff 2 /Rect [10 700 210 720] /P 3 0 R >>",
+		// ラジオボタン（ウィジェットは/Kids、6は/Pなし）
+		"<< /FT /Btn /T (gender) /Ff 49152 /V /female /Kids [6 0 R 7 0 R] >>",
+		"<< /Type /Annot /Subtype /Widget /Parent 5 0 R /Rect [10 650 20 660] /AS /Off >>",
+		"<< /Type /Annot /Subtype /Widget /Parent 5 0 R /Rect [30 650 40 660] /P 3 0 R /AS /female >>",
+		// 中間ノードの下のコンボボックス
+		"<< /T (address) /Kids [12 0 R] >>",
+		// 複数選択のリストボックス（ウィジェットなし）
+		"<< /FT /Ch /T (langs) /Ff 2097152 /Opt [(Go) (Rust) (C)] /V [(Go) (C)] >>",
+		// 読み取り専用のチェックボックス
+		"<< /FT /Btn /T (agree) /V /Yes /Ff 1 >>",
+		"<< /FT /Sig /T (sig) /V 13 0 R >>",
+		"<< /Type /Annot /Subtype /Widget /Parent 8 0 R /T (city) /FT /Ch /Ff 131072 /Opt [(Tokyo) [(osaka) (Osaka)]] /V (osaka) /Rect [10 600 110 620] /P 3 0 R >>",
+		"<< /Type /Sig /Filter /Adobe.PPKLite >>",
+	})
+
+	r, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	fields, err := r.ExtractFormFields()
+	if err != nil {
+		t.Fatalf("ExtractFormFields() failed: %v", err)
+	}
+
+	want := []FormField{
+		{Name: "name", Type: FormFieldText, Value: "山田", Required: true,
+			Widgets: []FormFieldWidget{{PageNum: 0, Rect: Rectangle{X: 10, Y: 700, Width: 200, Height: 20}}}},
+		{Name: "gender", Type: FormFieldRadio, Value: "female",
+			Widgets: []FormFieldWidget{
+				{PageNum: 0, Rect: Rectangle{X: 10, Y: 650, Width: 10, Height: 10}},
+				{PageNum: 0, Rect: Rectangle{X: 30, Y: 650, Width: 10, Height: 10}},
+			}},
+		{Name: "address.city", Type: FormFieldComboBox, Value: "osaka", Options: []string{"Tokyo", "osaka"},
+			Widgets: []FormFieldWidget{{PageNum: 0, Rect: Rectangle{X: 10, Y: 600, Width: 100, Height: 20}}}},
+		{Name: "langs", Type: FormFieldListBox, Values: []string{"Go", "C"}, Options: []string{"Go", "Rust", "C"}},
+		{Name: "agree", Type: FormFieldCheckBox, Value: "Yes", ReadOnly: true},
+		{Name: "sig", Type: FormFieldSignature},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("ExtractFormFields() returned %d fields, want %d: %+v", len(fields), len(want), fields)
+	}
+	for i, w := range want {
+		t.Run(w.Name, func(t *testing.T) {
+			if !reflect.DeepEqual(fields[i], w) {
+				t.Errorf("field = %+v, want %+v", fields[i], w)
+			}
+		})
+	}
+}
+
+func TestPDFReader_ExtractFormFields_TextField(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.AddTextField(TextField{Name: "email", X: 50, Y: 600, Width: 200, Height: 24, Value: "a@example.com", ReadOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	fields, err := r.ExtractFormFields()
+	if err != nil {
+		t.Fatalf("ExtractFormFields() failed: %v", err)
+	}
+	want := []FormField{{
+		Name: "email", Type: FormFieldText, Value: "a@example.com", ReadOnly: true,
+		Widgets: []FormFieldWidget{{PageNum: 0, Rect: Rectangle{X: 50, Y: 600, Width: 200, Height: 24}}},
+	}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ExtractFormFields() = %+v, want %+v", fields, want)
+	}
+}
+
+func TestPDFReader_ExtractFormFields_NoForm(t *testing.T) {
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	fields, err := r.ExtractFormFields()
+	if err != nil {
+		t.Fatalf("ExtractFormFields() failed: %v", err)
+	}
+	if fields == nil || len(fields) != 0 {
+		t.Errorf("ExtractFormFields() = %v, want empty", fields)
+	}
+}
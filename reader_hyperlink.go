@@ -0,0 +1,134 @@
+package gopdf
+
+import (
+	"fmt"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// Hyperlink はLink注釈と、そのリンク領域に表示されているテキスト（アンカーテキスト）の組
+type Hyperlink struct {
+	Text  string    // リンク領域にあるテキスト（行の区切りは改行、テキストがなければ空）
+	Rect  Rectangle // リンク領域（/Rect、表示される向きの座標）
+	Areas []Quad    // リンク領域の四角形（/QuadPointsがあればその四角形、なければRect。表示される向きの座標）
+
+	URI         string       // URIアクションのリンク先
+	Destination *Destination // ドキュメント内リンクの移動先
+	RemoteFile  string       // GoToR・Launchアクションの参照先ファイル
+}
+
+// ExtractPageHyperlinks は指定されたページのLink注釈を、リンク領域のテキストと組にして返す（0-indexed）
+// 座標はExtractPageLayoutと同じく/Rotateを適用した表示される向きのもの
+// 設計書: docs/hyperlink_extraction_design.md
+func (r *PDFReader) ExtractPageHyperlinks(pageNum int) ([]Hyperlink, error) {
+	annotations, err := r.ExtractPageAnnotations(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []Hyperlink
+	for _, annot := range annotations {
+		if annot.Type == AnnotationTypeLink {
+			links = append(links, newHyperlink(annot))
+		}
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	page, err := r.r.GetPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	if rotation := r.pageRotation(page); rotation != 0 {
+		width, height := r.getPageSize(page)
+		for i := range links {
+			rotateHyperlink(&links[i], rotation, width, height)
+		}
+	}
+
+	glyphs, err := r.extractPageGlyphs(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].Text = anchorText(glyphs, links[i].Areas)
+	}
+	return links, nil
+}
+
+// ExtractAllHyperlinks は全ページのリンクを抽出する
+// リンクのないページはマップに含めない
+func (r *PDFReader) ExtractAllHyperlinks() (map[int][]Hyperlink, error) {
+	pageCount := r.PageCount()
+	result := make(map[int][]Hyperlink)
+
+	for i := 0; i < pageCount; i++ {
+		links, err := r.ExtractPageHyperlinks(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract hyperlinks from page %d: %w", i, err)
+		}
+		if len(links) > 0 {
+			result[i] = links
+		}
+	}
+
+	return result, nil
+}
+
+// newHyperlink はLink注釈からHyperlinkを作成する（座標は回転前のもの）
+func newHyperlink(annot Annotation) Hyperlink {
+	areas := annot.QuadPoints
+	if len(areas) == 0 {
+		rect := annot.Rect
+		areas = []Quad{{
+			{X: rect.X, Y: rect.Y + rect.Height},
+			{X: rect.X + rect.Width, Y: rect.Y + rect.Height},
+			{X: rect.X, Y: rect.Y},
+			{X: rect.X + rect.Width, Y: rect.Y},
+		}}
+	}
+
+	return Hyperlink{
+		Rect:        annot.Rect,
+		Areas:       areas,
+		URI:         annot.URI,
+		Destination: annot.Destination,
+		RemoteFile:  annot.RemoteFile,
+	}
+}
+
+// rotateHyperlink はリンク領域を表示される向きの座標に変換する
+func rotateHyperlink(link *Hyperlink, rotation int, width, height float64) {
+	link.Rect = rotateRect(link.Rect, rotation, width, height)
+
+	areas := make([]Quad, len(link.Areas))
+	for i, q := range link.Areas {
+		for j, p := range q {
+			areas[i][j].X, areas[i][j].Y = rotatePoint(p.X, p.Y, rotation, width, height)
+		}
+	}
+	link.Areas = areas
+}
+
+// anchorText はリンク領域にある文字のテキストを返す
+// リンク領域は文字にぴったり合わせず余白を持たせることが多く、隣の行の文字にかかりやすいため、
+// 文字の領域の中心がいずれかの四角形（を囲む矩形）に含まれる文字だけを選ぶ
+func anchorText(glyphs [][]layout.TextElement, areas []Quad) string {
+	bounds := make([]Rectangle, len(areas))
+	for i, q := range areas {
+		bounds[i] = q.Bounds()
+	}
+
+	runs := selectGlyphRuns(glyphs, func(glyph layout.TextElement) bool {
+		b := glyph.Bounds()
+		cx, cy := b.X+b.Width/2, b.Y+b.Height/2
+		for _, area := range bounds {
+			if containsPoint(area, cx, cy) {
+				return true
+			}
+		}
+		return false
+	})
+	return glyphRunsText(runs)
+}
@@ -0,0 +1,117 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestPDFReader_ExtractPageHyperlinks(t *testing.T) {
+	// 標準フォントは幅がないため、10ポイントで1文字6ポイントになる
+	contents := "BT /F1 10 Tf 50 700 Td (Visit our site for details) Tj 0 -14 Td (Go to page two) Tj ET"
+	annots := "[" +
+		// "our site"（下の行にかからない余白付き）
+		"<< /Type /Annot /Subtype /Link /Rect [85 697 135 711] /A << /S /URI /URI (https://example.com) >> >> " +
+		// "page two"
+		"<< /Type /Annot /Subtype /Link /Rect [84 684 136 698] /Dest [6 0 R /Fit] >> " +
+		// 行をまたぐ "details" と "Go"
+		"<< /Type /Annot /Subtype /Link /Rect [50 686 206 710] " +
+		"/QuadPoints [164 710 206 710 164 700 206 700 50 696 62 696 50 686 62 686] " +
+		"/A << /S /URI /URI (https://example.com/details) >> >> " +
+		// リンクでない注釈は含めない
+		"<< /Type /Annot /Subtype /Text /Rect [0 0 10 10] /Contents (note) >>" +
+		"]"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 6 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> /Annots " + annots + " >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	links, err := reader.ExtractPageHyperlinks(0)
+	if err != nil {
+		t.Fatalf("ExtractPageHyperlinks failed: %v", err)
+	}
+
+	tests := []struct {
+		text     string
+		uri      string
+		destPage int // -1は移動先なし
+		areas    int
+	}{
+		{"our site", "https://example.com", -1, 1},
+		{"page two", "", 1, 1},
+		{"details\nGo", "https://example.com/details", -1, 2},
+	}
+	if len(links) != len(tests) {
+		t.Fatalf("got %d links %+v, want %d", len(links), links, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			link := links[i]
+			if link.Text != tt.text {
+				t.Errorf("Text = %q, want %q", link.Text, tt.text)
+			}
+			if link.URI != tt.uri {
+				t.Errorf("URI = %q, want %q", link.URI, tt.uri)
+			}
+			destPage := -1
+			if link.Destination != nil {
+				destPage = link.Destination.PageNum
+			}
+			if destPage != tt.destPage {
+				t.Errorf("Destination page = %d, want %d", destPage, tt.destPage)
+			}
+			if len(link.Areas) != tt.areas {
+				t.Errorf("len(Areas) = %d, want %d", len(link.Areas), tt.areas)
+			}
+		})
+	}
+
+	all, err := reader.ExtractAllHyperlinks()
+	if err != nil {
+		t.Fatalf("ExtractAllHyperlinks failed: %v", err)
+	}
+	if len(all) != 1 || len(all[0]) != len(tests) {
+		t.Errorf("ExtractAllHyperlinks() = %+v, want only page 0 with %d links", all, len(tests))
+	}
+}
+
+func TestPDFReader_ExtractPageHyperlinks_Rotated(t *testing.T) {
+	// /Rotate 90のページで、表示したときに横書きになるテキスト（表示される座標では(100, 312)から右へ）
+	contents := "BT /F1 10 Tf 0 1 -1 0 300 100 Tm (Link text) Tj ET"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Rotate 90 /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> " +
+			"/Annots [<< /Type /Annot /Subtype /Link /Rect [288 98 302 126] /A << /S /URI /URI (https://example.com) >> >>] >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	links, err := reader.ExtractPageHyperlinks(0)
+	if err != nil {
+		t.Fatalf("ExtractPageHyperlinks failed: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("got %d links, want 1", len(links))
+	}
+	if links[0].Text != "Link" {
+		t.Errorf("Text = %q, want %q", links[0].Text, "Link")
+	}
+	if want := (Rectangle{X: 98, Y: 310, Width: 28, Height: 14}); links[0].Rect != want {
+		t.Errorf("Rect = %+v, want %+v", links[0].Rect, want)
+	}
+}
@@ -0,0 +1,249 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ryomak/gopdf/internal/utils"
+	"github.com/ryomak/gopdf/layout"
+)
+
+// SearchOptions は全文検索のオプション
+type SearchOptions struct {
+	IgnoreCase bool // 大文字と小文字を区別しない
+	WholeWord  bool // 前後が文字・数字・_でない（単語全体に一致する）ものだけを返す
+	Regexp     bool // queryを正規表現（regexpパッケージの構文）として扱う
+}
+
+// SearchResult は検索で一致した1か所
+type SearchResult struct {
+	Page  int    // ページ番号（0-indexed）
+	Text  string // 一致したテキスト（行の区切りは改行）
+	Quads []Quad // 一致した文字を囲む四角形（行ごとに1つ、表示される向きの座標）
+}
+
+// Bounds は一致した文字全体を囲む矩形を返す
+func (s SearchResult) Bounds() Rectangle {
+	var bounds Rectangle
+	for i, q := range s.Quads {
+		if i == 0 {
+			bounds = q.Bounds()
+			continue
+		}
+		bounds = bounds.Union(q.Bounds())
+	}
+	return bounds
+}
+
+// Search は全ページからqueryに一致するテキストを探し、ページ順・読み順に返す
+// 正規表現でない場合、queryの空白は改行を含む任意の空白に一致する（行をまたいだ語句も見つかる）
+// QuadsはテキストマークアップのQuadPointsと同じ順（左上・右上・左下・右下）で、ハイライト注釈にそのまま使える
+// 設計書: docs/text_search_design.md
+func (r *PDFReader) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	re, err := compileSearchQuery(query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for i := 0; i < r.PageCount(); i++ {
+		pageResults, err := r.searchPage(i, re, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search page %d: %w", i, err)
+		}
+		results = append(results, pageResults...)
+	}
+	return results, nil
+}
+
+// SearchPage は指定されたページからqueryに一致するテキストを探す（0-indexed）
+func (r *PDFReader) SearchPage(pageNum int, query string, opts SearchOptions) ([]SearchResult, error) {
+	re, err := compileSearchQuery(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.searchPage(pageNum, re, opts)
+}
+
+// compileSearchQuery は検索の条件を正規表現にする
+func compileSearchQuery(query string, opts SearchOptions) (*regexp.Regexp, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("search query is empty")
+	}
+
+	pattern := query
+	if !opts.Regexp {
+		pattern = strings.Join(utils.Map(strings.Fields(query), regexp.QuoteMeta), `\s+`)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern: %w", err)
+	}
+	return re, nil
+}
+
+// searchPage はページのテキストから正規表現に一致する箇所を探す
+func (r *PDFReader) searchPage(pageNum int, re *regexp.Regexp, opts SearchOptions) ([]SearchResult, error) {
+	glyphs, err := r.extractPageGlyphs(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	text, spans := newSearchText(glyphs)
+
+	var results []SearchResult
+	for _, m := range re.FindAllStringIndex(text, -1) {
+		start, end := m[0], m[1]
+		if start == end {
+			continue
+		}
+		if opts.WholeWord && !isWholeWord(text, start, end) {
+			continue
+		}
+		results = append(results, SearchResult{
+			Page:  pageNum,
+			Text:  text[start:end],
+			Quads: matchQuads(spans, start, end),
+		})
+	}
+	return results, nil
+}
+
+// glyphSpan は検索用のテキストのうち、1文字が占める範囲（バイト位置）
+type glyphSpan struct {
+	start, end int
+	line       int // 行の番号
+	glyph      layout.TextElement
+}
+
+// newSearchText は文字を行にまとめて読み順に連結した検索用のテキストと、各文字の範囲を返す
+// 行の中の空白は要素を連結するときと同じく文字の間隔から決め、行は改行で区切る
+// 回転したテキストは、テキストブロックと同じく向きごとに行にまとめて横書きのテキストの後に続ける
+func newSearchText(glyphs [][]layout.TextElement) (string, []glyphSpan) {
+	var all []layout.TextElement
+	for _, elemGlyphs := range glyphs {
+		all = append(all, elemGlyphs...)
+	}
+	horizontal, rotated := partitionRotatedElements(all)
+
+	var lines [][]layout.TextElement
+	for _, line := range clusterByBaseline(horizontal) {
+		sort.SliceStable(line, func(i, j int) bool {
+			return line[i].X < line[j].X
+		})
+		lines = append(lines, line)
+	}
+	byAngle := utils.GroupBy(rotated, func(elem layout.TextElement) float64 {
+		return math.Round(elem.Angle)
+	})
+	angles := utils.Keys(byAngle)
+	sort.Float64s(angles)
+	for _, angle := range angles {
+		lines = append(lines, groupRotatedLines(byAngle[angle], angle)...)
+	}
+
+	var text strings.Builder
+	var spans []glyphSpan
+	for i, line := range lines {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		for j, glyph := range line {
+			if j > 0 && glyphGap(line[j-1], glyph) > line[j-1].Size*0.35 {
+				text.WriteString(" ")
+			}
+			start := text.Len()
+			text.WriteString(utils.CleanControlCharacters(glyph.Text))
+			spans = append(spans, glyphSpan{start: start, end: text.Len(), line: i, glyph: glyph})
+		}
+	}
+	return text.String(), spans
+}
+
+// glyphGap はベースラインに沿った、前の文字の終わりから次の文字の始まりまでの間隔を返す
+func glyphGap(prev, next layout.TextElement) float64 {
+	rad := prev.Angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	return (next.X-prev.X)*cos + (next.Y-prev.Y)*sin - prev.Width
+}
+
+// isWholeWord は一致した範囲の前後が単語の文字（文字・数字・_）でないかを返す
+func isWholeWord(text string, start, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+// isWordRune は単語を構成する文字かを返す
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// matchQuads は範囲[start, end)にかかる文字を、行ごとに1つの四角形で囲む
+// 四角形は行の最初の文字のベースラインから最後の文字の終わりまで、高さは最大のフォントサイズとする
+func matchQuads(spans []glyphSpan, start, end int) []Quad {
+	var quads []Quad
+	var first, last *glyphSpan
+	var size float64
+
+	flush := func() {
+		if first == nil {
+			return
+		}
+		quads = append(quads, glyphRunQuad(first.glyph, last.glyph, size))
+		first, last = nil, nil
+	}
+
+	// spansはテキストの先頭から順に並んでいる
+	from := sort.Search(len(spans), func(i int) bool { return spans[i].end > start })
+	for i := from; i < len(spans) && spans[i].start < end; i++ {
+		span := &spans[i]
+		if span.start == span.end {
+			continue
+		}
+		if first != nil && span.line != first.line {
+			flush()
+		}
+		if first == nil {
+			first, size = span, 0
+		}
+		last = span
+		size = math.Max(size, span.glyph.Size)
+	}
+	flush()
+	return quads
+}
+
+// glyphRunQuad はfirstの始点からlastの終わりまでの、高さsizeの四角形を返す
+// 順序はQuadPointsと同じ左上・右上・左下・右下
+func glyphRunQuad(first, last layout.TextElement, size float64) Quad {
+	rad := first.Angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+
+	// ベースラインに沿った長さ
+	length := (last.X-first.X)*cos + (last.Y-first.Y)*sin + last.Width
+
+	lowerLeft := Point{X: first.X, Y: first.Y}
+	lowerRight := Point{X: first.X + length*cos, Y: first.Y + length*sin}
+	up := Point{X: -size * sin, Y: size * cos}
+	return Quad{
+		{X: lowerLeft.X + up.X, Y: lowerLeft.Y + up.Y},
+		{X: lowerRight.X + up.X, Y: lowerRight.Y + up.Y},
+		lowerLeft,
+		lowerRight,
+	}
+}
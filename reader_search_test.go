@@ -0,0 +1,187 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// searchTestPDF は1ページずつ、与えたコンテンツストリームを持つPDFを返す
+func searchTestPDF(pages ...string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // ページツリーは後で作る
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	var kids []string
+	for _, contents := range pages {
+		pageNum := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageNum))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents %d 0 R /Resources << /Font << /F1 3 0 R >> >> >>", pageNum+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+	return buildRawPDF(objects)
+}
+
+func TestSearch(t *testing.T) {
+	// 標準フォントは幅がないため、10ポイントで1文字6ポイントになる
+	pdf := searchTestPDF(
+		"BT /F1 10 Tf 50 700 Td (Hello World, hello gopher) Tj ET "+
+			"BT /F1 10 Tf 50 686 Td (say hello) Tj ET "+
+			"BT /F1 10 Tf 50 672 Td (world peace) Tj ET",
+		"BT /F1 10 Tf 50 700 Td (Invoice 2024-001 and 2024-002 for Othello) Tj ET",
+	)
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	type hit struct {
+		Page  int
+		Text  string
+		Lines int // Quadsの数
+	}
+	tests := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+		want  []hit
+	}{
+		{
+			name:  "case sensitive",
+			query: "hello",
+			want:  []hit{{0, "hello", 1}, {0, "hello", 1}, {1, "hello", 1}},
+		},
+		{
+			name:  "ignore case",
+			query: "HELLO",
+			opts:  SearchOptions{IgnoreCase: true},
+			want:  []hit{{0, "Hello", 1}, {0, "hello", 1}, {0, "hello", 1}, {1, "hello", 1}},
+		},
+		{
+			name:  "whole word",
+			query: "hello",
+			opts:  SearchOptions{IgnoreCase: true, WholeWord: true},
+			want:  []hit{{0, "Hello", 1}, {0, "hello", 1}, {0, "hello", 1}},
+		},
+		{
+			name:  "regexp",
+			query: `2024-\d+`,
+			opts:  SearchOptions{Regexp: true},
+			want:  []hit{{1, "2024-001", 1}, {1, "2024-002", 1}},
+		},
+		{
+			name:  "phrase across lines",
+			query: "hello  world",
+			want:  []hit{{0, "hello\nworld", 2}},
+		},
+		{
+			name:  "no match",
+			query: "missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := reader.Search(tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			var got []hit
+			for _, r := range results {
+				got = append(got, hit{r.Page, r.Text, len(r.Quads)})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch_Quads(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		query    string
+		want     []Quad
+	}{
+		{
+			name:     "horizontal",
+			contents: "BT /F1 10 Tf 50 700 Td (Hello World) Tj ET",
+			query:    "World",
+			want:     []Quad{{{X: 86, Y: 710}, {X: 116, Y: 710}, {X: 86, Y: 700}, {X: 116, Y: 700}}},
+		},
+		{
+			name:     "two lines",
+			contents: "BT /F1 10 Tf 50 700 Td (say hello) Tj 0 -14 Td (world peace) Tj ET",
+			query:    "hello world",
+			want: []Quad{
+				{{X: 74, Y: 710}, {X: 104, Y: 710}, {X: 74, Y: 700}, {X: 104, Y: 700}},
+				{{X: 50, Y: 696}, {X: 80, Y: 696}, {X: 50, Y: 686}, {X: 80, Y: 686}},
+			},
+		},
+		{
+			name:     "vertical",
+			contents: "BT /F1 10 Tf 0 1 -1 0 40 300 Tm (Hi there) Tj ET",
+			query:    "Hi",
+			want:     []Quad{{{X: 30, Y: 300}, {X: 30, Y: 312}, {X: 40, Y: 300}, {X: 40, Y: 312}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(searchTestPDF(tt.contents)))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+
+			results, err := reader.SearchPage(0, tt.query, SearchOptions{})
+			if err != nil {
+				t.Fatalf("SearchPage failed: %v", err)
+			}
+			if len(results) != 1 || len(results[0].Quads) != len(tt.want) {
+				t.Fatalf("SearchPage() = %+v, want 1 result with %d quads", results, len(tt.want))
+			}
+			for i, q := range results[0].Quads {
+				for j := range q {
+					if math.Abs(q[j].X-tt.want[i][j].X) > 1e-6 || math.Abs(q[j].Y-tt.want[i][j].Y) > 1e-6 {
+						t.Errorf("Quads[%d] = %v, want %v", i, q, tt.want[i])
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSearch_InvalidQuery(t *testing.T) {
+	reader, err := OpenReader(bytes.NewReader(searchTestPDF("BT /F1 10 Tf 50 700 Td (text) Tj ET")))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	tests := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+	}{
+		{"empty", " ", SearchOptions{}},
+		{"invalid regexp", "(", SearchOptions{Regexp: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := reader.Search(tt.query, tt.opts); err == nil {
+				t.Error("Search() error = nil, want error")
+			}
+		})
+	}
+}
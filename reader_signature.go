@@ -0,0 +1,341 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/cms"
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/tsa"
+)
+
+// maxFieldDepth はフォームフィールド階層を辿る最大の深さ
+const maxFieldDepth = 32
+
+// SignatureVerification はデジタル署名1件の検証結果
+type SignatureVerification struct {
+	FieldName   string    // 署名フィールドの完全名
+	Name        string    // 署名者名（/Name）
+	Reason      string    // 署名理由
+	Location    string    // 署名場所
+	ContactInfo string    // 連絡先
+	SigningTime time.Time // 署名日時（CMSのsigningTime、なければ/M）
+	Timestamp   time.Time // 検証済みRFC 3161タイムスタンプの時刻（なければゼロ値）
+	SubFilter   string    // 署名形式（adbe.pkcs7.detached など）
+
+	Certificate *x509.Certificate   // 署名者の証明書
+	Chain       []*x509.Certificate // 署名に埋め込まれた証明書
+
+	Intact               bool  // 署名範囲のダイジェストと署名値が正しい
+	Trusted              bool  // 証明書チェーンが信頼されたルートまで検証できた
+	ModifiedAfterSigning bool  // 署名範囲の後にデータが追記されている
+	Err                  error // 検証に失敗した理由（成功時はnil）
+}
+
+// Valid は署名が改ざんされておらず、信頼でき、署名後に変更されていないかを返す
+func (v SignatureVerification) Valid() bool {
+	return v.Intact && v.Trusted && !v.ModifiedAfterSigning && v.Err == nil
+}
+
+// VerifySignatures はドキュメント内のすべてのデジタル署名を検証する
+// roots は信頼するルート証明書（nilの場合はシステムのルート証明書を使用）
+// 署名のないPDFでは空のスライスを返す。個々の署名の失敗はエラーではなく結果のErrに格納される。
+func (r *PDFReader) VerifySignatures(roots *x509.CertPool) ([]SignatureVerification, error) {
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	acroForm, ok := r.r.Resolve(catalog[core.Name("AcroForm")]).(core.Dictionary)
+	if !ok {
+		return []SignatureVerification{}, nil
+	}
+
+	size, err := r.r.Size()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file size: %w", err)
+	}
+
+	results := []SignatureVerification{}
+	fields, _ := r.r.Resolve(acroForm[core.Name("Fields")]).(core.Array)
+	err = r.walkFormFields(fields, func(name string, field core.Dictionary, fieldType string) error {
+		if fieldType != "Sig" {
+			return nil
+		}
+		sig, ok := r.r.Resolve(field[core.Name("V")]).(core.Dictionary)
+		if !ok {
+			return nil // 未署名の署名フィールド
+		}
+		ref, _ := field[core.Name("V")].(*core.Reference)
+		results = append(results, r.verifySignature(name, ref, sig, size, roots))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// walkFormFields はフォームフィールドの階層を辿り、終端フィールドごとにfnを呼び出す
+// nameはピリオド区切りの完全名、fieldTypeは親から継承した値を含む/FT
+func (r *PDFReader) walkFormFields(fields core.Array, fn func(name string, field core.Dictionary, fieldType string) error) error {
+	var walk func(fields core.Array, parentName, parentType string, depth int) error
+	walk = func(fields core.Array, parentName, parentType string, depth int) error {
+		if depth > maxFieldDepth {
+			return fmt.Errorf("form field tree is too deep")
+		}
+		for _, f := range fields {
+			field, ok := r.r.Resolve(f).(core.Dictionary)
+			if !ok {
+				continue
+			}
+
+			name := parentName
+			if t := rawTextString(field[core.Name("T")]); t != "" {
+				if name != "" {
+					name += "."
+				}
+				name += t
+			}
+			fieldType := parentType
+			if ft, ok := field[core.Name("FT")].(core.Name); ok {
+				fieldType = string(ft)
+			}
+
+			// /Tを持つ子がある場合は中間ノード（/Tのない子はウィジェット）
+			kids, _ := r.r.Resolve(field[core.Name("Kids")]).(core.Array)
+			if r.hasNamedKids(kids) {
+				if err := walk(kids, name, fieldType, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := fn(name, field, fieldType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(fields, "", "", 0)
+}
+
+// hasNamedKids は子要素にフィールド（/Tを持つ辞書）が含まれるかを返す
+func (r *PDFReader) hasNamedKids(kids core.Array) bool {
+	for _, k := range kids {
+		if kid, ok := r.r.Resolve(k).(core.Dictionary); ok {
+			if _, named := kid[core.Name("T")]; named {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifySignature は署名辞書1件を検証する
+// refは署名辞書の間接参照（/Contentsの値のファイル内の位置を調べるのに使う）
+func (r *PDFReader) verifySignature(fieldName string, ref *core.Reference, sig core.Dictionary, fileSize int64, roots *x509.CertPool) SignatureVerification {
+	result := SignatureVerification{
+		FieldName:   fieldName,
+		Name:        rawTextString(sig[core.Name("Name")]),
+		Reason:      rawTextString(sig[core.Name("Reason")]),
+		Location:    rawTextString(sig[core.Name("Location")]),
+		ContactInfo: rawTextString(sig[core.Name("ContactInfo")]),
+	}
+	if subFilter, ok := sig[core.Name("SubFilter")].(core.Name); ok {
+		result.SubFilter = string(subFilter)
+	}
+	if m := rawTextString(sig[core.Name("M")]); m != "" {
+		if t, err := parsePDFDate(m); err == nil {
+			result.SigningTime = t
+		}
+	}
+
+	switch result.SubFilter {
+	case "adbe.pkcs7.detached", "ETSI.CAdES.detached", "ETSI.RFC3161":
+	default:
+		result.Err = fmt.Errorf("unsupported signature SubFilter: %s", result.SubFilter)
+		return result
+	}
+
+	// 署名辞書は間接オブジェクトでなければならない（/Vは間接参照）
+	if ref == nil {
+		result.Err = fmt.Errorf("signature dictionary is not an indirect object")
+		return result
+	}
+	contentsStart, contentsEnd, err := r.r.HexStringRange(ref.ObjectNumber, "Contents")
+	if err != nil {
+		result.Err = fmt.Errorf("signature /Contents: %w", err)
+		return result
+	}
+	byteRange, err := parseByteRange(r.r.Resolve(sig[core.Name("ByteRange")]), fileSize, contentsStart, contentsEnd)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.ModifiedAfterSigning = byteRange[2]+byteRange[3] != fileSize
+
+	contents, ok := sig[core.Name("Contents")].(core.String)
+	if !ok {
+		result.Err = fmt.Errorf("signature /Contents is missing")
+		return result
+	}
+
+	// 署名範囲（/Contentsの値を除く部分）
+	var parts [2][]byte
+	for i := range parts {
+		data, err := r.r.ReadRange(byteRange[2*i], byteRange[2*i+1])
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		parts[i] = data
+	}
+	signed := func() io.Reader {
+		return io.MultiReader(bytes.NewReader(parts[0]), bytes.NewReader(parts[1]))
+	}
+
+	if result.SubFilter == "ETSI.RFC3161" {
+		return verifyDocumentTimestamp(result, []byte(contents), signed(), roots)
+	}
+
+	sd, err := cms.Parse([]byte(contents))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Certificate = sd.Signer
+	result.Chain = sd.Certificates
+	if !sd.SigningTime.IsZero() {
+		result.SigningTime = sd.SigningTime
+	}
+
+	// 署名範囲のダイジェストを検証
+	if err := sd.VerifyDetached(signed()); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Intact = true
+
+	// 署名タイムスタンプ（署名値に対するタイムスタンプトークン）を検証
+	if token, ok := sd.UnsignedAttribute(cms.OIDTimeStampToken); ok {
+		ts, err := verifyTimestampToken(token, bytes.NewReader(sd.Signature()), roots)
+		if err != nil {
+			result.Err = fmt.Errorf("invalid signature timestamp: %w", err)
+			return result
+		}
+		result.Timestamp = ts.GenTime
+	}
+
+	// 証明書チェーンを検証する。署名日時は署名者が自由に書けるので使わず、
+	// 検証済みのタイムスタンプがあればその時刻、なければ現在時刻で有効期限を判定する
+	var at time.Time
+	if !result.Timestamp.IsZero() {
+		at = result.Timestamp
+	}
+	if err := verifyCertificateChain(sd.Signer, sd.Certificates, roots, x509.ExtKeyUsageAny, at); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Trusted = true
+
+	if result.ModifiedAfterSigning {
+		result.Err = fmt.Errorf("document was modified after signing")
+	}
+	return result
+}
+
+// verifyDocumentTimestamp はドキュメントタイムスタンプ（/SubFilter /ETSI.RFC3161）を検証する
+func verifyDocumentTimestamp(result SignatureVerification, token []byte, signed io.Reader, roots *x509.CertPool) SignatureVerification {
+	ts, err := tsa.ParseToken(token)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Certificate = ts.Certificate
+	result.Chain = ts.Certificates
+	result.SigningTime = ts.GenTime
+
+	if err := ts.VerifyData(signed); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Intact = true
+	result.Timestamp = ts.GenTime
+
+	if err := verifyCertificateChain(ts.Certificate, ts.Certificates, roots, x509.ExtKeyUsageTimeStamping, ts.GenTime); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Trusted = true
+
+	if result.ModifiedAfterSigning {
+		result.Err = fmt.Errorf("document was modified after timestamping")
+	}
+	return result
+}
+
+// verifyTimestampToken はタイムスタンプトークンがdataに対するものであり、TSAの証明書が信頼できるかを検証する
+func verifyTimestampToken(token []byte, data io.Reader, roots *x509.CertPool) (*tsa.Token, error) {
+	ts, err := tsa.ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.VerifyData(data); err != nil {
+		return nil, err
+	}
+	if err := verifyCertificateChain(ts.Certificate, ts.Certificates, roots, x509.ExtKeyUsageTimeStamping, ts.GenTime); err != nil {
+		return nil, fmt.Errorf("TSA %w", err)
+	}
+	return ts, nil
+}
+
+// verifyCertificateChain は埋め込み証明書を中間証明書として、certがrootsまで検証できるかを確認する
+// atがゼロ値でなければその時刻、ゼロ値なら現在時刻で有効期限を判定する
+func verifyCertificateChain(cert *x509.Certificate, embedded []*x509.Certificate, roots *x509.CertPool, usage x509.ExtKeyUsage, at time.Time) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range embedded {
+		if c != cert {
+			intermediates.AddCert(c)
+		}
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+		CurrentTime:   at,
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		return fmt.Errorf("certificate verification failed: %w", err)
+	}
+	return nil
+}
+
+// parseByteRange は/ByteRangeを検証して [offset1 length1 offset2 length2] を返す
+// contentsStartとcontentsEndは署名辞書の/Contentsの値（'<'から'>'まで）のファイル内の範囲
+func parseByteRange(obj core.Object, fileSize, contentsStart, contentsEnd int64) ([4]int64, error) {
+	var br [4]int64
+	arr, ok := obj.(core.Array)
+	if !ok || len(arr) != 4 {
+		return br, fmt.Errorf("invalid signature /ByteRange")
+	}
+	for i, v := range arr {
+		n, ok := v.(core.Integer)
+		if !ok || n < 0 {
+			return br, fmt.Errorf("invalid signature /ByteRange")
+		}
+		br[i] = int64(n)
+	}
+
+	// 署名範囲はファイル先頭から始まり、/Contentsの値だけを除外していなければならない
+	if br[0] != 0 || br[2] < br[1] || br[2]+br[3] > fileSize {
+		return br, fmt.Errorf("signature /ByteRange %v does not match the file (size %d)", br, fileSize)
+	}
+	// 署名されない隙間は/Contentsの値とちょうど一致しなければならない（他のバイトを署名範囲から外させない）
+	if br[1] != contentsStart || br[2] != contentsEnd {
+		return br, fmt.Errorf("signature /ByteRange %v does not exclude exactly the /Contents value at %d-%d", br, contentsStart, contentsEnd)
+	}
+	return br, nil
+}
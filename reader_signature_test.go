@@ -0,0 +1,258 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCertificateChain はルートCAと、そのCAが発行した署名者証明書を生成する
+func newTestCertificateChain(t *testing.T) (root *x509.Certificate, leaf *x509.Certificate, leafKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err = x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test Leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, leafKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err = x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, leaf, leafKey
+}
+
+func TestPDFReader_VerifySignatures(t *testing.T) {
+	root, leaf, key := newTestCertificateChain(t)
+
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.DrawText("Signed content", 100, 700); err != nil {
+		t.Fatal(err)
+	}
+	signingTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	if err := doc.Sign(SignatureOptions{Signer: key, Certificate: leaf, Reason: "Approval", SigningTime: signingTime}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	signed := buf.Bytes()
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(root)
+
+	tests := []struct {
+		name         string
+		data         func() []byte
+		roots        *x509.CertPool
+		wantIntact   bool
+		wantTrusted  bool
+		wantModified bool
+	}{
+		{
+			name:        "valid signature",
+			data:        func() []byte { return signed },
+			roots:       trusted,
+			wantIntact:  true,
+			wantTrusted: true,
+		},
+		{
+			name:       "untrusted root",
+			data:       func() []byte { return signed },
+			roots:      x509.NewCertPool(),
+			wantIntact: true,
+		},
+		{
+			name: "tampered content",
+			data: func() []byte {
+				return bytes.Replace(signed, []byte("Signed content"), []byte("Forged content"), 1)
+			},
+			roots: trusted,
+		},
+		{
+			name: "appended after signing",
+			data: func() []byte {
+				return append(append([]byte{}, signed...), []byte("% appended\n")...)
+			},
+			roots:        trusted,
+			wantIntact:   true,
+			wantTrusted:  true,
+			wantModified: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(tt.data()))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			defer reader.Close()
+
+			results, err := reader.VerifySignatures(tt.roots)
+			if err != nil {
+				t.Fatalf("VerifySignatures() failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d signatures, want 1", len(results))
+			}
+
+			v := results[0]
+			if v.Intact != tt.wantIntact || v.Trusted != tt.wantTrusted || v.ModifiedAfterSigning != tt.wantModified {
+				t.Errorf("Intact/Trusted/Modified = %v/%v/%v, want %v/%v/%v (err: %v)",
+					v.Intact, v.Trusted, v.ModifiedAfterSigning, tt.wantIntact, tt.wantTrusted, tt.wantModified, v.Err)
+			}
+			wantValid := tt.wantIntact && tt.wantTrusted && !tt.wantModified
+			if v.Valid() != wantValid || (v.Err == nil) != wantValid {
+				t.Errorf("Valid() = %v, Err = %v, want valid %v", v.Valid(), v.Err, wantValid)
+			}
+			if v.FieldName != "Signature1" || v.Reason != "Approval" || v.Name != "Test Leaf" {
+				t.Errorf("FieldName/Reason/Name = %q/%q/%q", v.FieldName, v.Reason, v.Name)
+			}
+			if !v.SigningTime.Equal(signingTime) {
+				t.Errorf("SigningTime = %v, want %v", v.SigningTime, signingTime)
+			}
+			if v.Certificate == nil || !v.Certificate.Equal(leaf) {
+				t.Error("Certificate does not match the signer")
+			}
+		})
+	}
+}
+
+func TestPDFReader_VerifySignatures_ByteRangeGap(t *testing.T) {
+	root, leaf, key := newTestCertificateChain(t)
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	if err := doc.Sign(SignatureOptions{Signer: key, Certificate: leaf}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	signed := buf.Bytes()
+
+	byteRange := regexp.MustCompile(`/ByteRange \[0 (\d+) (\d+) (\d+) *\]`)
+	m := byteRange.FindSubmatch(signed)
+	if m == nil {
+		t.Fatal("ByteRange not found")
+	}
+	start, _ := strconv.Atoi(string(m[1]))
+	end, _ := strconv.Atoi(string(m[2]))
+
+	tests := []struct {
+		name       string
+		start, end int
+	}{
+		// 署名されない隙間が/Contentsの値の外のバイトを含む
+		{"gap starts before /Contents", start - 10, end},
+		{"gap ends after /Contents", start, end + 3},
+		// 隙間が/Contentsの値の一部しか除いていない
+		{"gap inside /Contents", start + 1, end - 1},
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 桁数を変えないように空白で埋めて/ByteRangeを書き換える
+			replaced := fmt.Sprintf("/ByteRange [0 %d %d %d", tt.start, tt.end, len(signed)-tt.end)
+			replaced += strings.Repeat(" ", len(m[0])-len(replaced)-1) + "]"
+			data := bytes.Replace(signed, m[0], []byte(replaced), 1)
+
+			reader, err := OpenReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			defer reader.Close()
+
+			results, err := reader.VerifySignatures(roots)
+			if err != nil {
+				t.Fatalf("VerifySignatures() failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d signatures, want 1", len(results))
+			}
+			v := results[0]
+			if v.Intact || v.Valid() {
+				t.Errorf("Intact = %v, Valid() = %v, want false", v.Intact, v.Valid())
+			}
+			if v.Err == nil || !strings.Contains(v.Err.Error(), "/Contents") {
+				t.Errorf("Err = %v, want a /ByteRange mismatch with /Contents", v.Err)
+			}
+		})
+	}
+}
+
+func TestPDFReader_VerifySignatures_Unsigned(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.AddTextField(TextField{Name: "name", Width: 100, Height: 20}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	results, err := reader.VerifySignatures(nil)
+	if err != nil {
+		t.Fatalf("VerifySignatures() failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d signatures, want 0", len(results))
+	}
+}
@@ -0,0 +1,225 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// maxStructureDepth は構造ツリーをたどる深さの上限（壊れたPDFでの無限再帰を防ぐ）
+const maxStructureDepth = 256
+
+// StructElement はタグ付きPDFの構造要素（見出し、段落、表、図など）
+type StructElement struct {
+	Type       StructureType    // 構造型（/RoleMapで標準構造型に対応付けた値）
+	Tag        string           // PDFに記録された構造型（/S）
+	Title      string           // 要素のタイトル（/T）
+	Lang       string           // 言語（/Lang）
+	Alt        string           // 代替テキスト（/Alt）
+	ActualText string           // 置き換えテキスト（/ActualText）
+	MCIDs      []int            // このページで要素に直接属するマーク付きコンテンツのID（出現順）
+	Text       string           // 子要素を含む、このページ上の要素のテキスト（出現順）
+	Children   []*StructElement // 子要素
+}
+
+// ExtractStructure は指定されたページの構造ツリーを抽出する（0-indexed）
+// /StructTreeRootをたどり、このページにマーク付きコンテンツを持つ要素だけを返す
+// 各要素のテキストはマーク付きコンテンツ（MCID）から取得するため、
+// 位置からテキストをまとめるExtractPageTextBlocksより正確に見出しや段落を区別できる
+// タグ付きでないPDFではnilを返す
+func (r *PDFReader) ExtractStructure(pageNum int) ([]*StructElement, error) {
+	page, err := r.r.GetPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.r.GetPageReferences()
+	if err != nil {
+		return nil, err
+	}
+	if pageNum >= len(refs) {
+		return nil, fmt.Errorf("page %d not found", pageNum)
+	}
+
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := r.r.Resolve(catalog[core.Name("StructTreeRoot")]).(core.Dictionary)
+	if !ok {
+		return nil, nil
+	}
+
+	texts, err := r.markedContentTexts(page)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &structureWalker{
+		r:       r,
+		pageObj: refs[pageNum].ObjectNumber,
+		texts:   texts,
+		visited: map[int]bool{},
+	}
+	w.roleMap, _ = r.r.Resolve(root[core.Name("RoleMap")]).(core.Dictionary)
+
+	var elements []*StructElement
+	for _, kid := range w.kidList(root[core.Name("K")]) {
+		elem, err := w.element(kid, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		if elem != nil {
+			elements = append(elements, elem)
+		}
+	}
+	return elements, nil
+}
+
+// markedContentTexts はページのテキストをMCIDごとにまとめる
+func (r *PDFReader) markedContentTexts(page core.Dictionary) (map[int]string, error) {
+	contentsData, err := r.r.GetPageContents(page)
+	if err != nil {
+		return nil, err
+	}
+	operations, err := content.NewStreamParser(contentsData).ParseOperations()
+	if err != nil {
+		return nil, err
+	}
+	elements, err := content.NewTextExtractor(operations, r.r, page).Extract()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := map[int][]string{}
+	for _, elem := range elements {
+		if elem.MCID >= 0 && elem.Text != "" {
+			parts[elem.MCID] = append(parts[elem.MCID], elem.Text)
+		}
+	}
+	texts := make(map[int]string, len(parts))
+	for mcid, p := range parts {
+		texts[mcid] = strings.Join(p, " ")
+	}
+	return texts, nil
+}
+
+// structureWalker は構造ツリーをたどって1ページ分の要素を集める
+type structureWalker struct {
+	r       *PDFReader
+	pageObj int             // 対象ページのオブジェクト番号
+	roleMap core.Dictionary // 独自の構造型 -> 標準構造型
+	texts   map[int]string  // MCID -> テキスト
+	visited map[int]bool    // たどった構造要素のオブジェクト番号（循環参照対策）
+}
+
+// kidList は/Kの値（単一のオブジェクトまたは配列）を配列にする
+func (w *structureWalker) kidList(obj core.Object) core.Array {
+	switch v := w.r.r.Resolve(obj).(type) {
+	case nil, core.Null:
+		return nil
+	case core.Array:
+		return v
+	default:
+		// 参照のまま返して、呼び出し側で循環を検出できるようにする
+		return core.Array{obj}
+	}
+}
+
+// element は構造要素を変換する。このページに内容がなければnilを返す
+// pageObjは親から継承したページ（/Pg）のオブジェクト番号
+func (w *structureWalker) element(obj core.Object, pageObj, depth int) (*StructElement, error) {
+	if depth > maxStructureDepth {
+		return nil, fmt.Errorf("structure tree is too deep")
+	}
+	if ref, ok := obj.(*core.Reference); ok {
+		if w.visited[ref.ObjectNumber] {
+			return nil, nil
+		}
+		w.visited[ref.ObjectNumber] = true
+	}
+	dict, ok := w.r.r.Resolve(obj).(core.Dictionary)
+	if !ok {
+		return nil, nil
+	}
+	if pg, ok := dict[core.Name("Pg")].(*core.Reference); ok {
+		pageObj = pg.ObjectNumber
+	}
+
+	tag, _ := dict[core.Name("S")].(core.Name)
+	elem := &StructElement{
+		Type:       w.standardType(string(tag)),
+		Tag:        string(tag),
+		Title:      rawTextString(w.r.r.Resolve(dict[core.Name("T")])),
+		Lang:       rawTextString(w.r.r.Resolve(dict[core.Name("Lang")])),
+		Alt:        rawTextString(w.r.r.Resolve(dict[core.Name("Alt")])),
+		ActualText: rawTextString(w.r.r.Resolve(dict[core.Name("ActualText")])),
+	}
+
+	var texts []string
+	addContent := func(mcid, onPage int) {
+		if onPage != w.pageObj {
+			return
+		}
+		elem.MCIDs = append(elem.MCIDs, mcid)
+		if text := w.texts[mcid]; text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	for _, kid := range w.kidList(dict[core.Name("K")]) {
+		switch v := w.r.r.Resolve(kid).(type) {
+		case core.Integer:
+			addContent(int(v), pageObj)
+		case core.Dictionary:
+			switch v[core.Name("Type")] {
+			case core.Name("MCR"):
+				// XObject内のマーク付きコンテンツ（/Stm）は対象外
+				if _, ok := v[core.Name("Stm")]; ok {
+					continue
+				}
+				mcid, ok := v[core.Name("MCID")].(core.Integer)
+				if !ok {
+					continue
+				}
+				onPage := pageObj
+				if pg, ok := v[core.Name("Pg")].(*core.Reference); ok {
+					onPage = pg.ObjectNumber
+				}
+				addContent(int(mcid), onPage)
+			case core.Name("OBJR"):
+				// 注釈などのオブジェクト参照はテキストを持たない
+			default:
+				child, err := w.element(kid, pageObj, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				if child != nil {
+					elem.Children = append(elem.Children, child)
+					if child.Text != "" {
+						texts = append(texts, child.Text)
+					}
+				}
+			}
+		}
+	}
+
+	if len(elem.MCIDs) == 0 && len(elem.Children) == 0 {
+		return nil, nil
+	}
+	elem.Text = strings.Join(texts, " ")
+	return elem, nil
+}
+
+// standardType はロールマップをたどって構造型を標準構造型に対応付ける
+func (w *structureWalker) standardType(tag string) StructureType {
+	for i := 0; i < 16; i++ {
+		mapped, ok := w.roleMap[core.Name(tag)].(core.Name)
+		if !ok || string(mapped) == tag {
+			break
+		}
+		tag = string(mapped)
+	}
+	return StructureType(tag)
+}
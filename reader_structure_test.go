@@ -0,0 +1,168 @@
+package gopdf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// structSummary はテスト用に構造要素を「型:テキスト」の形にまとめる
+func structSummary(elems []*StructElement) []string {
+	var out []string
+	var walk func(elem *StructElement, indent string)
+	walk = func(elem *StructElement, indent string) {
+		out = append(out, indent+string(elem.Type)+":"+elem.Text)
+		for _, child := range elem.Children {
+			walk(child, indent+"  ")
+		}
+	}
+	for _, elem := range elems {
+		walk(elem, "")
+	}
+	return out
+}
+
+func TestPDFReader_ExtractStructure(t *testing.T) {
+	doc := New()
+	page1 := doc.AddPage(PageSizeA4, Portrait)
+	page1.SetFont(FontHelvetica, 12)
+	page1.DrawText("Header", 50, 820) // アーティファクト
+	mustTag(t, page1.BeginTag(StructH1))
+	mustTag(t, page1.DrawText("Title", 50, 780))
+	mustTag(t, page1.EndTag())
+	mustTag(t, page1.BeginTag(StructP))
+	mustTag(t, page1.DrawText("Hello", 50, 750))
+	mustTag(t, page1.BeginTag(StructSpan))
+	mustTag(t, page1.DrawText("world", 80, 750))
+	mustTag(t, page1.EndTag())
+	mustTag(t, page1.DrawText("again", 120, 750))
+	mustTag(t, page1.EndTag())
+	if err := page1.DrawImageWithOptions(newTestImage(t), 50, 600, 100, 100, ImageOptions{AltText: "Logo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 段落はページをまたぐ
+	mustTag(t, page1.BeginTag(StructP))
+	mustTag(t, page1.DrawText("Continued", 50, 100))
+	page2 := doc.AddPage(PageSizeA4, Portrait)
+	page2.SetFont(FontHelvetica, 12)
+	mustTag(t, page2.DrawText("on next page", 50, 800))
+	mustTag(t, page2.EndTag())
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	tests := []struct {
+		name    string
+		pageNum int
+		want    []string
+	}{
+		{
+			name:    "first page",
+			pageNum: 0,
+			want: []string{
+				"Document:Title Hello world again Continued",
+				"  H1:Title",
+				"  P:Hello world again",
+				"    Span:world",
+				"  Figure:",
+				"  P:Continued",
+			},
+		},
+		{
+			name:    "second page",
+			pageNum: 1,
+			want: []string{
+				"Document:on next page",
+				"  P:on next page",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elems, err := r.ExtractStructure(tt.pageNum)
+			if err != nil {
+				t.Fatalf("ExtractStructure() failed: %v", err)
+			}
+			if got := structSummary(elems); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("structure = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	elems, err := r.ExtractStructure(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	figure := elems[0].Children[2]
+	if figure.Alt != "Logo" || len(figure.MCIDs) != 1 {
+		t.Errorf("figure = %+v, want Alt Logo with one MCID", figure)
+	}
+	// 段落の内容はSpanの前後の2つのマーク付きコンテンツに分かれる
+	if p := elems[0].Children[1]; len(p.MCIDs) != 2 {
+		t.Errorf("paragraph MCIDs = %v, want 2", p.MCIDs)
+	}
+}
+
+func TestPDFReader_ExtractStructure_Untagged(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetFont(FontHelvetica, 12)
+	page.DrawText("Plain", 50, 750)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	elems, err := r.ExtractStructure(0)
+	if err != nil {
+		t.Fatalf("ExtractStructure() failed: %v", err)
+	}
+	if elems != nil {
+		t.Errorf("ExtractStructure() = %v, want nil", elems)
+	}
+	if _, err := r.ExtractStructure(5); err == nil {
+		t.Error("ExtractStructure(5) succeeded, want error")
+	}
+}
+
+func TestStructureWalker_StandardType(t *testing.T) {
+	w := &structureWalker{roleMap: core.Dictionary{
+		core.Name("Heading"): core.Name("Title"),
+		core.Name("Title"):   core.Name("H1"),
+		core.Name("Loop"):    core.Name("Loop"),
+	}}
+
+	tests := []struct {
+		tag  string
+		want StructureType
+	}{
+		{"P", StructP},
+		{"Title", StructH1},
+		{"Heading", StructH1},
+		{"Loop", "Loop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := w.standardType(tt.tag); got != tt.want {
+				t.Errorf("standardType(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
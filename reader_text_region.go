@@ -0,0 +1,116 @@
+package gopdf
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/layout"
+)
+
+// ExtractTextInRect は指定されたページで、文字の領域が矩形と重なるテキストを抽出する（0-indexed）
+// 矩形はExtractPageLayoutと同じ座標系（/Rotateを適用した、表示される向きの座標）で指定する
+// 文字ごとに判定するため、テキスト要素の一部だけが矩形にかかる場合はその部分だけを返す
+// 行は上から順に改行で区切り、回転したテキストは横書きのテキストの後に続ける
+// 設計書: docs/text_extraction_design.md
+func (r *PDFReader) ExtractTextInRect(pageNum int, rect Rectangle) (string, error) {
+	glyphs, err := r.extractPageGlyphs(pageNum)
+	if err != nil {
+		return "", err
+	}
+	// 文字の領域は、ベースラインから文字の高さ（フォントサイズ）までの矩形を回転したものを囲む矩形とする
+	runs := selectGlyphRuns(glyphs, func(glyph layout.TextElement) bool {
+		return overlapsRect(glyph.Bounds(), rect)
+	})
+	return glyphRunsText(runs), nil
+}
+
+// extractPageGlyphs はページのテキスト要素ごとに、その文字を1文字ずつの要素にして返す
+// ExtractPageTextElementsと同じく、/Rotateのあるページでは表示される向きの座標にする
+func (r *PDFReader) extractPageGlyphs(pageNum int) ([][]layout.TextElement, error) {
+	page, err := r.r.GetPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	internalElements, err := r.extractContentTextElements(page)
+	if err != nil {
+		return nil, err
+	}
+
+	glyphs := convertGlyphs(internalElements)
+	if rotation := r.pageRotation(page); rotation != 0 {
+		width, height := r.getPageSize(page)
+		for _, elemGlyphs := range glyphs {
+			rotateTextElements(elemGlyphs, rotation, width, height)
+		}
+	}
+	return glyphs, nil
+}
+
+// convertGlyphs はテキスト要素ごとに、その文字を1文字ずつの公開型の要素に変換する
+func convertGlyphs(internalElements []content.TextElement) [][]layout.TextElement {
+	result := make([][]layout.TextElement, len(internalElements))
+	for i, elem := range convertTextElements(internalElements) {
+		for _, glyph := range internalElements[i].Glyphs {
+			g := elem
+			g.Text = glyph.Text
+			g.X, g.Y = glyph.X, glyph.Y
+			g.Width = glyph.Width
+			result[i] = append(result[i], g)
+		}
+	}
+	return result
+}
+
+// selectGlyphRuns は条件を満たす文字を、同じテキスト要素の中で連続するものごとに1つの要素にまとめる
+func selectGlyphRuns(glyphs [][]layout.TextElement, selected func(layout.TextElement) bool) []layout.TextElement {
+	var runs []layout.TextElement
+	for _, elemGlyphs := range glyphs {
+		var current *layout.TextElement
+		for _, glyph := range elemGlyphs {
+			if !selected(glyph) {
+				if current != nil {
+					runs = append(runs, *current)
+					current = nil
+				}
+				continue
+			}
+			if current == nil {
+				run := glyph
+				current = &run
+				continue
+			}
+			current.Text += glyph.Text
+			current.Width += glyph.Width
+		}
+		if current != nil {
+			runs = append(runs, *current)
+		}
+	}
+	return runs
+}
+
+// overlapsRect は2つの矩形が重なるかを返す（辺が接するだけの場合は重ならない）
+func overlapsRect(a, b Rectangle) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width &&
+		a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// glyphRunsText は要素を行ごとに連結し、行を改行で区切ったテキストを返す
+// 横書きの要素はベースラインで行にまとめ、回転した要素はテキストブロックと同じく向きごとに行にまとめる
+func glyphRunsText(runs []layout.TextElement) string {
+	horizontal, rotated := partitionRotatedElements(runs)
+
+	var lines []string
+	for _, line := range clusterByBaseline(horizontal) {
+		sort.SliceStable(line, func(i, j int) bool {
+			return line[i].X < line[j].X
+		})
+		lines = append(lines, combineLineText(line))
+	}
+	for _, block := range groupRotatedElements(rotated) {
+		lines = append(lines, block.Text)
+	}
+	return strings.Join(lines, "\n")
+}
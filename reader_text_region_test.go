@@ -0,0 +1,56 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestExtractTextInRect(t *testing.T) {
+	// 標準フォントは幅がないため、10ポイントで1文字6ポイントになる
+	invoice := "BT /F1 10 Tf 50 700 Td (Invoice No. 123) Tj ET " +
+		"BT /F1 10 Tf 50 600 Td (Total:) Tj 200 0 Td (1,234.00) Tj ET " +
+		"BT /F1 10 Tf 50 580 Td (Thank you) Tj ET"
+	// /Rotate 90のページで、表示したときに横書きになるテキスト（表示される座標では(100, 312)から右へ）
+	rotated := "BT /F1 10 Tf 0 1 -1 0 300 100 Tm (Total 99) Tj ET"
+
+	tests := []struct {
+		name     string
+		contents string
+		rotate   int
+		rect     Rectangle
+		want     string
+	}{
+		{"value box", invoice, 0, Rectangle{X: 240, Y: 595, Width: 80, Height: 20}, "1,234.00"},
+		{"whole line", invoice, 0, Rectangle{X: 40, Y: 595, Width: 300, Height: 20}, "Total: 1,234.00"},
+		{"part of an element", invoice, 0, Rectangle{X: 123, Y: 695, Width: 100, Height: 20}, "123"},
+		{"multiple lines", invoice, 0, Rectangle{X: 40, Y: 575, Width: 300, Height: 40}, "Total: 1,234.00\nThank you"},
+		{"empty area", invoice, 0, Rectangle{X: 400, Y: 100, Width: 50, Height: 50}, ""},
+		{"rotated page", rotated, 90, Rectangle{X: 95, Y: 305, Width: 32, Height: 20}, "Total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf := buildRawPDF([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Rotate %d /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>", tt.rotate),
+				fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(tt.contents), tt.contents),
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+			})
+			reader, err := OpenReader(bytes.NewReader(pdf))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+
+			got, err := reader.ExtractTextInRect(0, tt.rect)
+			if err != nil {
+				t.Fatalf("ExtractTextInRect failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractTextInRect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
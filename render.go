@@ -0,0 +1,97 @@
+package gopdf
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/ryomak/gopdf/internal/content"
+)
+
+// DefaultRenderDPI はRenderOptions.DPIを省略したときの解像度（1ポイントが1ピクセル）
+const DefaultRenderDPI = 72.0
+
+// DefaultRenderMaxPixels はRenderOptions.MaxPixelsを省略したときの画像のピクセル数の上限（RGBAで約400MB）
+const DefaultRenderMaxPixels = 100_000_000
+
+// RenderOptions はページを画像に描画するときの設定
+type RenderOptions struct {
+	DPI        float64     // 解像度（0の場合はDefaultRenderDPI）
+	Background color.Color // 背景色（nilの場合は白）
+	MaxPixels  int         // 画像のピクセル数の上限（0の場合はDefaultRenderMaxPixels）
+}
+
+// RenderPage は指定されたページを画像に描画する（0-indexed）
+// 描画する範囲は表示される領域（/CropBoxと/MediaBoxの共通部分）で、/Rotateを適用した向きにする
+// 画像の大きさは、表示される領域の大きさ（ポイント）× DPI / 72 を切り上げたピクセル数
+// 画像が空になる場合や、ピクセル数がMaxPixelsを超える場合は、画像を確保せずにエラーを返す
+// 設計書: docs/rendering_design.md
+func (r *PDFReader) RenderPage(pageNum int, opts RenderOptions) (image.Image, error) {
+	page, err := r.r.GetPage(pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	dpi := opts.DPI
+	if dpi == 0 {
+		dpi = DefaultRenderDPI
+	}
+	if dpi < 0 || math.IsNaN(dpi) || math.IsInf(dpi, 0) {
+		return nil, fmt.Errorf("invalid DPI: %v", opts.DPI)
+	}
+	maxPixels := opts.MaxPixels
+	if maxPixels == 0 {
+		maxPixels = DefaultRenderMaxPixels
+	}
+	if maxPixels < 0 {
+		return nil, fmt.Errorf("invalid MaxPixels: %d", opts.MaxPixels)
+	}
+	background := opts.Background
+	if background == nil {
+		background = color.White
+	}
+
+	visible := r.pageBoxes(page).Visible
+	rotation := r.pageRotation(page)
+	device := renderDeviceMatrix(visible, rotation, dpi/72)
+
+	// ページの箱の値はファイルのものなので、intに変換する前に浮動小数点数のまま大きさを確かめる
+	width, height := rotatePageSize(rotation, visible.Width, visible.Height)
+	pixelWidth, pixelHeight := math.Ceil(width*dpi/72), math.Ceil(height*dpi/72)
+	if !(pixelWidth >= 1 && pixelHeight >= 1) {
+		return nil, fmt.Errorf("page %d has an empty visible area", pageNum)
+	}
+	if pixelWidth*pixelHeight > float64(maxPixels) {
+		return nil, fmt.Errorf("page %d is too large to render: %.0f×%.0f pixels exceeds the limit of %d", pageNum, pixelWidth, pixelHeight, maxPixels)
+	}
+	bounds := image.Rect(0, 0, int(pixelWidth), int(pixelHeight))
+	dst := image.NewRGBA(bounds)
+	draw.Draw(dst, bounds, image.NewUniform(background), image.Point{}, draw.Src)
+
+	if err := content.NewRenderer(r.r, dst, device).RenderPage(page); err != nil {
+		return nil, fmt.Errorf("failed to render page %d: %w", pageNum, err)
+	}
+	return dst, nil
+}
+
+// renderDeviceMatrix はページの座標系を画像のピクセル座標に写す行列を返す
+// 表示される領域の左下を原点に移し、時計回りにrotation度回転して、scale倍に拡大し、y軸を下向きにする
+func renderDeviceMatrix(visible Rectangle, rotation int, scale float64) content.Matrix {
+	w, h := visible.Width, visible.Height
+	m := content.Matrix{A: 1, D: 1, E: -visible.X, F: -visible.Y}
+
+	// rotatePointと同じ回転
+	switch rotation {
+	case 90:
+		m = m.Multiply(content.Matrix{B: -1, C: 1, F: w})
+	case 180:
+		m = m.Multiply(content.Matrix{A: -1, D: -1, E: w, F: h})
+	case 270:
+		m = m.Multiply(content.Matrix{B: 1, C: -1, E: h})
+	}
+
+	_, displayHeight := rotatePageSize(rotation, w, h)
+	return m.Multiply(content.Matrix{A: scale, D: -scale, F: displayHeight * scale})
+}
@@ -0,0 +1,219 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// renderTestPDF は赤い矩形、テキスト、2×1ピクセルの画像（赤と青）を描いた200×100ポイントのページを作成する
+func renderTestPDF(t *testing.T, pageExtra string) *PDFReader {
+	t.Helper()
+	contents := "1 0 0 rg 0 0 50 50 re f " +
+		"0 g BT /F1 20 Tf 100 40 Td (HELLO) Tj ET " +
+		"q 20 0 0 10 60 60 cm /Im1 Do Q"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 100] /Contents 4 0 R " +
+			"/Resources << /Font << /F1 5 0 R >> /XObject << /Im1 6 0 R >> >> " + pageExtra + " >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /XObject /Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Length 6 >>\nstream\n\xff\x00\x00\x00\x00\xff\nendstream",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+// countDarkPixels は範囲rectの中の暗いピクセルの数を返す
+func countDarkPixels(img image.Image, rect image.Rectangle) int {
+	count := 0
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r < 0x4000 && g < 0x4000 && b < 0x4000 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestPDFReader_RenderPage(t *testing.T) {
+	isRed := func(c color.Color) bool {
+		r, g, b, _ := c.RGBA()
+		return r > 0xE000 && g < 0x2000 && b < 0x2000
+	}
+	isBlue := func(c color.Color) bool {
+		r, g, b, _ := c.RGBA()
+		return r < 0x2000 && g < 0x2000 && b > 0xE000
+	}
+
+	tests := []struct {
+		name      string
+		pageExtra string
+		opts      RenderOptions
+		wantSize  image.Point
+		check     func(t *testing.T, img image.Image)
+	}{
+		{
+			name:     "default 72 dpi",
+			wantSize: image.Point{X: 200, Y: 100},
+			check: func(t *testing.T, img image.Image) {
+				if c := img.At(25, 75); !isRed(c) {
+					t.Errorf("rect pixel = %v, want red", c)
+				}
+				if c := img.At(150, 90); c != (color.RGBA{255, 255, 255, 255}) {
+					t.Errorf("background pixel = %v, want white", c)
+				}
+				if c := img.At(64, 35); !isRed(c) {
+					t.Errorf("image left pixel = %v, want red", c)
+				}
+				if c := img.At(76, 35); !isBlue(c) {
+					t.Errorf("image right pixel = %v, want blue", c)
+				}
+				// "HELLO"はページの(100, 40)から右上に描かれる
+				if n := countDarkPixels(img, image.Rect(100, 40, 170, 60)); n < 50 {
+					t.Errorf("text dark pixels = %d, want text to be drawn", n)
+				}
+			},
+		},
+		{
+			name:     "144 dpi",
+			opts:     RenderOptions{DPI: 144},
+			wantSize: image.Point{X: 400, Y: 200},
+			check: func(t *testing.T, img image.Image) {
+				if c := img.At(50, 150); !isRed(c) {
+					t.Errorf("rect pixel = %v, want red", c)
+				}
+			},
+		},
+		{
+			name:      "rotate 90",
+			pageExtra: "/Rotate 90",
+			wantSize:  image.Point{X: 100, Y: 200},
+			check: func(t *testing.T, img image.Image) {
+				// ページの左下は、時計回りに90度回転して表示すると左上になる
+				if c := img.At(25, 25); !isRed(c) {
+					t.Errorf("rect pixel = %v, want red", c)
+				}
+			},
+		},
+		{
+			name:      "crop box",
+			pageExtra: "/CropBox [25 25 200 100]",
+			wantSize:  image.Point{X: 175, Y: 75},
+			check: func(t *testing.T, img image.Image) {
+				if c := img.At(10, 65); !isRed(c) {
+					t.Errorf("rect pixel = %v, want red", c)
+				}
+			},
+		},
+		{
+			name:     "background",
+			opts:     RenderOptions{Background: color.Black},
+			wantSize: image.Point{X: 200, Y: 100},
+			check: func(t *testing.T, img image.Image) {
+				if c := img.At(150, 90); c != (color.RGBA{0, 0, 0, 255}) {
+					t.Errorf("background pixel = %v, want black", c)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := renderTestPDF(t, tt.pageExtra)
+			img, err := reader.RenderPage(0, tt.opts)
+			if err != nil {
+				t.Fatalf("RenderPage failed: %v", err)
+			}
+			if got := img.Bounds().Size(); got != tt.wantSize {
+				t.Fatalf("size = %v, want %v", got, tt.wantSize)
+			}
+			tt.check(t, img)
+		})
+	}
+}
+
+func TestPDFReader_RenderPage_EmbeddedFont(t *testing.T) {
+	jpFont, err := DefaultJapaneseFont()
+	if err != nil {
+		t.Fatalf("DefaultJapaneseFont failed: %v", err)
+	}
+
+	doc := New()
+	page := doc.AddPage(PageSize{Width: 200, Height: 100}, Portrait)
+	if err := page.SetTTFFont(jpFont, 40); err != nil {
+		t.Fatalf("SetTTFFont failed: %v", err)
+	}
+	if err := page.DrawText("日本", 20, 30); err != nil {
+		t.Fatalf("DrawText failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	img, err := reader.RenderPage(0, RenderOptions{})
+	if err != nil {
+		t.Fatalf("RenderPage failed: %v", err)
+	}
+	// 2文字はページの(20, 30)から右上の80×40ポイントに描かれる
+	if n := countDarkPixels(img, image.Rect(20, 30, 100, 70)); n < 200 {
+		t.Errorf("text dark pixels = %d, want text to be drawn", n)
+	}
+	if n := countDarkPixels(img, image.Rect(120, 0, 200, 100)); n != 0 {
+		t.Errorf("dark pixels outside text = %d, want 0", n)
+	}
+
+	_, err = reader.RenderPage(0, RenderOptions{DPI: -1})
+	if err == nil {
+		t.Error("expected error for negative DPI")
+	}
+}
+
+func TestPDFReader_RenderPage_Size(t *testing.T) {
+	tests := []struct {
+		name     string
+		mediaBox string
+		opts     RenderOptions
+	}{
+		// 巨大な箱は画像を確保する前にエラーにする
+		{name: "too large for int", mediaBox: "[0 0 10000000 10000000]"},
+		{name: "too many pixels", mediaBox: "[0 0 200000 200000]"},
+		{name: "over MaxPixels", mediaBox: "[0 0 200 100]", opts: RenderOptions{MaxPixels: 100}},
+		{name: "negative MaxPixels", mediaBox: "[0 0 200 100]", opts: RenderOptions{MaxPixels: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf := buildRawPDF([]string{
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /MediaBox " + tt.mediaBox + " >>",
+			})
+			reader, err := OpenReader(bytes.NewReader(pdf))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer reader.Close()
+
+			if _, err := reader.RenderPage(0, tt.opts); err == nil {
+				t.Error("RenderPage succeeded, want error")
+			}
+		})
+	}
+}
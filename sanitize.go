@@ -0,0 +1,296 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// SanitizeOptions はSanitizeで残す情報（ゼロ値の場合はすべて削除する）
+type SanitizeOptions struct {
+	KeepMetadata          bool // Info辞書とXMPメタデータを残す
+	KeepAttachments       bool // 埋め込みファイル（添付ファイル注釈と関連ファイルを含む）を残す
+	KeepScripts           bool // JavaScriptのアクションを残す
+	KeepAnnotationAuthors bool // 注釈の作成者（/T）を残す
+}
+
+// SanitizeReport はSanitizeで削除した情報
+type SanitizeReport struct {
+	InfoKeys          []string // 削除したInfo辞書のキー（Title、Authorなど）
+	XMPMetadata       int      // 削除したXMPメタデータのストリームの数
+	Attachments       []string // 削除した埋め込みファイルの名前
+	Scripts           int      // 削除したJavaScriptのアクションの数
+	AnnotationAuthors int      // 作成者を削除した注釈の数
+}
+
+// Sanitize は外部に配布する前に、PDFから隠れた情報を削除して書き出す
+// Info辞書とXMPメタデータ、埋め込みファイル、JavaScript、注釈の作成者を削除し、削除したものを返す
+// ページ内容・しおり・フォーム・論理構造はそのまま保持される。残す情報はoptsで指定する
+// 暗号化されたPDFは、Decryptで暗号化を解除してから渡す
+// 設計書: docs/sanitize_design.md
+func Sanitize(in io.ReadSeeker, out io.Writer, opts SanitizeOptions) (*SanitizeReport, error) {
+	r, err := OpenReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	src := r.r
+	if src.IsEncrypted() {
+		return nil, fmt.Errorf("PDF is encrypted: decrypt it before sanitizing")
+	}
+
+	w := writer.NewWriter(out)
+	if err := w.WriteHeader(); err != nil {
+		return nil, err
+	}
+	c := newObjectCopier(src, w)
+	c.skip = make(map[int]bool)
+	c.replace = make(map[int]core.Object)
+
+	s := &sanitizer{r: r, opts: opts, report: &SanitizeReport{}, attachments: make(map[int]bool)}
+	if !opts.KeepMetadata {
+		if ref, ok := src.GetTrailer()[core.Name("Info")].(*core.Reference); ok {
+			c.skip[ref.ObjectNumber] = true
+			if info, ok := src.Resolve(ref).(core.Dictionary); ok {
+				for key := range info {
+					s.report.InfoKeys = append(s.report.InfoKeys, string(key))
+				}
+				sort.Strings(s.report.InfoKeys)
+			}
+		}
+	}
+
+	objects := reachableObjects(src)
+	objNums := make([]int, 0, len(objects))
+	for objNum := range objects {
+		objNums = append(objNums, objNum)
+	}
+	sort.Ints(objNums)
+	for _, objNum := range objNums {
+		obj := objects[objNum]
+		if c.skip[objNum] || obj == nil {
+			continue
+		}
+		// 削除するオブジェクトは、残った参照があってもnullとして複製する
+		if s.removed(obj) {
+			c.skip[objNum] = true
+			continue
+		}
+		if dict, ok := obj.(core.Dictionary); ok && dict[core.Name("EF")] != nil && !opts.KeepAttachments {
+			s.recordAttachment(&core.Reference{ObjectNumber: objNum})
+		}
+		if cleaned, changed := s.clean(obj); changed {
+			c.replace[objNum] = cleaned
+		}
+	}
+
+	if err := copyDocument(c, nil); err != nil {
+		return nil, err
+	}
+	return s.report, nil
+}
+
+// sanitizer はオブジェクトから削除する情報を取り除き、削除したものを記録する
+type sanitizer struct {
+	r           *PDFReader
+	opts        SanitizeOptions
+	report      *SanitizeReport
+	attachments map[int]bool // 記録したファイル指定辞書のオブジェクト番号
+}
+
+// removed は間接オブジェクトそのものを削除するかを返す
+// XMPメタデータ、埋め込みファイルのストリーム、JavaScriptのアクション、添付ファイル注釈とそのポップアップを削除する
+func (s *sanitizer) removed(obj core.Object) bool {
+	if stream, ok := obj.(*core.Stream); ok {
+		switch stream.Dict[core.Name("Type")] {
+		case core.Name("Metadata"):
+			if !s.opts.KeepMetadata {
+				s.report.XMPMetadata++
+				return true
+			}
+		case core.Name("EmbeddedFile"):
+			return !s.opts.KeepAttachments
+		}
+		return false
+	}
+	if !s.isRemoved(obj) {
+		return false
+	}
+	if dict, ok := obj.(core.Dictionary); ok && isJavaScriptAction(dict) {
+		s.report.Scripts++
+	}
+	if dict, ok := obj.(core.Dictionary); ok && dict[core.Name("Subtype")] == core.Name("FileAttachment") {
+		s.recordAttachment(dict[core.Name("FS")])
+	}
+	return true
+}
+
+// isRemoved は値が（参照先を含めて）削除するアクションや注釈かを返す
+func (s *sanitizer) isRemoved(obj core.Object) bool {
+	dict, ok := s.r.r.Resolve(obj).(core.Dictionary)
+	if !ok {
+		return false
+	}
+	if !s.opts.KeepScripts && isJavaScriptAction(dict) {
+		return true
+	}
+	if !s.opts.KeepAttachments {
+		switch dict[core.Name("Subtype")] {
+		case core.Name("FileAttachment"):
+			return true
+		case core.Name("Popup"):
+			// 添付ファイル注釈のポップアップは、/Parentから添付ファイル注釈を参照する
+			parent, ok := s.r.r.Resolve(dict[core.Name("Parent")]).(core.Dictionary)
+			return ok && parent[core.Name("Subtype")] == core.Name("FileAttachment")
+		}
+	}
+	return false
+}
+
+// clean は直接のオブジェクト（辞書・配列・ストリームの辞書）から削除する情報を取り除いた複製を返す
+// 何も取り除かなかった場合はfalseを返す
+func (s *sanitizer) clean(obj core.Object) (core.Object, bool) {
+	switch v := obj.(type) {
+	case core.Dictionary:
+		return s.cleanDict(v)
+	case core.Array:
+		out := make(core.Array, 0, len(v))
+		changed := false
+		for _, item := range v {
+			if s.isRemoved(item) {
+				s.countDirect(item)
+				changed = true
+				continue
+			}
+			cleaned, itemChanged := s.cleanValue(item)
+			changed = changed || itemChanged
+			out = append(out, cleaned)
+		}
+		if !changed {
+			return v, false
+		}
+		return out, true
+	case *core.Stream:
+		dict, changed := s.cleanDict(v.Dict)
+		if !changed {
+			return v, false
+		}
+		return &core.Stream{Dict: dict, Data: v.Data}, true
+	default:
+		return obj, false
+	}
+}
+
+// cleanDict は辞書から削除する情報を取り除いた複製を返す
+func (s *sanitizer) cleanDict(dict core.Dictionary) (core.Dictionary, bool) {
+	src := s.r.r
+	out := make(core.Dictionary, len(dict))
+	changed := false
+	for key, value := range dict {
+		drop := false
+		switch key {
+		case core.Name("Metadata"):
+			_, isStream := src.Resolve(value).(*core.Stream)
+			drop = isStream && !s.opts.KeepMetadata
+		case core.Name("EmbeddedFiles"):
+			drop = !s.opts.KeepAttachments && isNameTree(src.Resolve(value))
+			if drop {
+				_ = src.WalkNameTree(value, func(_ string, spec core.Object) error {
+					s.recordAttachment(spec)
+					return nil
+				})
+			}
+		case core.Name("JavaScript"):
+			drop = !s.opts.KeepScripts && isNameTree(src.Resolve(value))
+		case core.Name("EF"), core.Name("RF"):
+			// ファイル指定辞書は残し（リンク先のファイル名として使われる）、埋め込んだ内容だけを除く
+			drop = !s.opts.KeepAttachments
+		case core.Name("AF"):
+			drop = !s.opts.KeepAttachments
+		case core.Name("T"):
+			// ウィジェット注釈の/Tはフィールド名なので残す
+			subtype, _ := dict[core.Name("Subtype")].(core.Name)
+			if !s.opts.KeepAnnotationAuthors && subtype != "" && subtype != "Widget" && dict[core.Name("Rect")] != nil {
+				drop = true
+				s.report.AnnotationAuthors++
+			}
+		default:
+			if s.isRemoved(value) {
+				drop = true
+				s.countDirect(value)
+			}
+		}
+		if drop {
+			changed = true
+			continue
+		}
+		cleaned, valueChanged := s.cleanValue(value)
+		changed = changed || valueChanged
+		out[key] = cleaned
+	}
+
+	// JavaScriptだけだった追加アクション（/AA）は辞書ごと除く
+	if aa, ok := out[core.Name("AA")].(core.Dictionary); ok && len(aa) == 0 && changed {
+		delete(out, core.Name("AA"))
+	}
+	if !changed {
+		return dict, false
+	}
+	return out, true
+}
+
+// cleanValue は辞書や配列の要素をcleanで取り除く。埋め込みファイルを持つ直接のファイル指定辞書は記録する
+// （間接オブジェクトのファイル指定辞書はSanitizeで記録する）
+func (s *sanitizer) cleanValue(value core.Object) (core.Object, bool) {
+	if dict, ok := value.(core.Dictionary); ok && dict[core.Name("EF")] != nil && !s.opts.KeepAttachments {
+		s.recordAttachment(dict)
+	}
+	return s.clean(value)
+}
+
+// countDirect は直接のオブジェクトとして削除したアクションや注釈を記録する（間接オブジェクトはremovedで記録する）
+func (s *sanitizer) countDirect(obj core.Object) {
+	dict, ok := obj.(core.Dictionary)
+	if !ok {
+		return
+	}
+	if isJavaScriptAction(dict) {
+		s.report.Scripts++
+	}
+	if dict[core.Name("Subtype")] == core.Name("FileAttachment") {
+		s.recordAttachment(dict[core.Name("FS")])
+	}
+}
+
+// recordAttachment は埋め込みファイルを持つファイル指定辞書の名前を記録する（同じファイル指定辞書は1回だけ）
+func (s *sanitizer) recordAttachment(spec core.Object) {
+	if ref, ok := spec.(*core.Reference); ok {
+		if s.attachments[ref.ObjectNumber] {
+			return
+		}
+		s.attachments[ref.ObjectNumber] = true
+	}
+	dict, ok := s.r.r.Resolve(spec).(core.Dictionary)
+	if !ok || dict[core.Name("EF")] == nil {
+		return
+	}
+	s.report.Attachments = append(s.report.Attachments, s.r.fileSpecName(dict))
+}
+
+// isJavaScriptAction はJavaScriptのアクション辞書かを返す
+func isJavaScriptAction(dict core.Dictionary) bool {
+	return dict[core.Name("S")] == core.Name("JavaScript")
+}
+
+// isNameTree は名前ツリーのノード（/Namesまたは/Kidsを持つ辞書）かを返す
+func isNameTree(obj core.Object) bool {
+	dict, ok := obj.(core.Dictionary)
+	if !ok {
+		return false
+	}
+	_, hasNames := dict[core.Name("Names")]
+	_, hasKids := dict[core.Name("Kids")]
+	return hasNames || hasKids
+}
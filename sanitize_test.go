@@ -0,0 +1,145 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sanitizeSourcePDF はメタデータ・埋め込みファイル・JavaScript・注釈の作成者を持つPDFを作成する
+func sanitizeSourcePDF() []byte {
+	stream := func(dict, data string) string {
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+	}
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R /Metadata 5 0 R /Names << /EmbeddedFiles << /Names [(report.csv) 6 0 R] >> /JavaScript << /Names [(init) 8 0 R] >> >> " +
+			"/OpenAction << /S /JavaScript /JS (app.alert\\('opened'\\);) >> /AcroForm << /Fields [12 0 R] >> >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 300 200] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Annots [9 0 R 10 0 R 11 0 R 12 0 R 13 0 R] /AA << /O 8 0 R >> >>",
+		stream("", "BT /F1 12 Tf 20 150 Td (Hello) Tj ET"),
+		stream("/Type /Metadata /Subtype /XML", "<x:xmpmeta xmlns:x=\"adobe:ns:meta/\"></x:xmpmeta>"),
+		"<< /Type /Filespec /F (report.csv) /UF (report.csv) /EF << /F 7 0 R >> >>",
+		stream("/Type /EmbeddedFile", "a,b\n1,2\n"),
+		"<< /S /JavaScript /JS (console.println\\('hi'\\);) >>",
+		"<< /Type /Annot /Subtype /Text /Rect [10 10 30 30] /T (Alice) /Contents (Check this) >>",
+		"<< /Type /Annot /Subtype /FileAttachment /Rect [40 10 60 30] /T (Bob) /FS << /Type /Filespec /F (notes.txt) /EF << /F 14 0 R >> >> /Popup 13 0 R >>",
+		"<< /Type /Annot /Subtype /Link /Rect [70 10 90 30] /A << /S /JavaScript /JS (print\\(\\);) >> >>",
+		"<< /Type /Annot /Subtype /Widget /FT /Tx /T (name) /Rect [100 10 200 30] /AA << /K << /S /JavaScript /JS (AFNumber_Keystroke\\(\\);) >> >> >>",
+		"<< /Type /Annot /Subtype /Popup /Rect [40 40 140 90] /Parent 10 0 R >>",
+		stream("/Type /EmbeddedFile", "secret notes"),
+		"<< /Title (Draft) /Author (Alice) /Producer (Word) >>",
+	})
+}
+
+// withInfo はtrailerに/Infoを加えたPDFを返す
+func withInfo(pdf []byte, infoNum int) []byte {
+	return bytes.Replace(pdf, []byte("/Root 1 0 R"), []byte(fmt.Sprintf("/Root 1 0 R /Info %d 0 R", infoNum)), 1)
+}
+
+func TestSanitize(t *testing.T) {
+	original := withInfo(sanitizeSourcePDF(), 15)
+
+	tests := []struct {
+		name       string
+		opts       SanitizeOptions
+		want       SanitizeReport
+		wantAuthor string
+	}{
+		{
+			name: "remove everything",
+			want: SanitizeReport{
+				InfoKeys:          []string{"Author", "Producer", "Title"},
+				XMPMetadata:       1,
+				Attachments:       []string{"report.csv", "notes.txt"},
+				Scripts:           4,
+				AnnotationAuthors: 1,
+			},
+		},
+		{
+			name:       "keep everything",
+			opts:       SanitizeOptions{KeepMetadata: true, KeepAttachments: true, KeepScripts: true, KeepAnnotationAuthors: true},
+			want:       SanitizeReport{},
+			wantAuthor: "Alice",
+		},
+		{
+			name: "keep metadata and attachments",
+			opts: SanitizeOptions{KeepMetadata: true, KeepAttachments: true},
+			want: SanitizeReport{Scripts: 4, AnnotationAuthors: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			report, err := Sanitize(bytes.NewReader(original), &out, tt.opts)
+			if err != nil {
+				t.Fatalf("Sanitize failed: %v", err)
+			}
+			if !reflect.DeepEqual(*report, tt.want) {
+				t.Errorf("Sanitize() report = %+v, want %+v", *report, tt.want)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open sanitized PDF: %v", err)
+			}
+			defer reader.Close()
+
+			if got := reader.Info().Title; (got == "") != !tt.opts.KeepMetadata {
+				t.Errorf("Info().Title = %q, KeepMetadata = %v", got, tt.opts.KeepMetadata)
+			}
+			if got := bytes.Contains(out.Bytes(), []byte("xmpmeta")); got != tt.opts.KeepMetadata {
+				t.Errorf("XMP metadata in output = %v, KeepMetadata = %v", got, tt.opts.KeepMetadata)
+			}
+			attachments, err := reader.ExtractAttachments()
+			if err != nil {
+				t.Fatalf("ExtractAttachments failed: %v", err)
+			}
+			if got := len(attachments) > 0; got != tt.opts.KeepAttachments {
+				t.Errorf("attachments = %d, KeepAttachments = %v", len(attachments), tt.opts.KeepAttachments)
+			}
+			if got := bytes.Contains(out.Bytes(), []byte("secret notes")); got != tt.opts.KeepAttachments {
+				t.Errorf("embedded file of the annotation in output = %v, KeepAttachments = %v", got, tt.opts.KeepAttachments)
+			}
+			if got := bytes.Contains(out.Bytes(), []byte("JavaScript")); got != tt.opts.KeepScripts {
+				t.Errorf("JavaScript in output = %v, KeepScripts = %v", got, tt.opts.KeepScripts)
+			}
+
+			annotations, err := reader.ExtractPageAnnotations(0)
+			if err != nil {
+				t.Fatalf("ExtractPageAnnotations failed: %v", err)
+			}
+			wantAnnotations := 5
+			if !tt.opts.KeepAttachments {
+				wantAnnotations = 3 // 添付ファイル注釈とそのポップアップを除く
+			}
+			if len(annotations) != wantAnnotations {
+				t.Fatalf("page has %d annotations, want %d", len(annotations), wantAnnotations)
+			}
+			if annotations[0].Author != tt.wantAuthor {
+				t.Errorf("text annotation author = %q, want %q", annotations[0].Author, tt.wantAuthor)
+			}
+
+			// フィールド名（ウィジェットの/T）とページの内容は残る
+			fields, err := reader.ExtractFormFields()
+			if err != nil {
+				t.Fatalf("ExtractFormFields failed: %v", err)
+			}
+			if len(fields) != 1 || fields[0].Name != "name" {
+				t.Errorf("form fields = %+v, want the field %q", fields, "name")
+			}
+			if text, _ := reader.ExtractPageText(0); !strings.Contains(text, "Hello") {
+				t.Errorf("page text = %q, want it to contain %q", text, "Hello")
+			}
+		})
+	}
+}
+
+func TestSanitize_Encrypted(t *testing.T) {
+	encrypted := buildEncryptedPDF(t, &EncryptionOptions{UserPassword: "user", OwnerPassword: "owner", Permissions: DefaultPermissions(), KeyLength: 128})
+	if _, err := Sanitize(bytes.NewReader(encrypted), &bytes.Buffer{}, SanitizeOptions{}); err == nil {
+		t.Error("Sanitize should fail for an encrypted PDF")
+	}
+}
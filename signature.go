@@ -0,0 +1,395 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/cms"
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/tsa"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+const (
+	// defaultSignatureFieldName は署名フィールド名の既定値
+	defaultSignatureFieldName = "Signature1"
+	// defaultSignatureContentsSize は署名データ（/Contents）に確保する既定のバイト数
+	defaultSignatureContentsSize = 8192
+	// defaultTimestampContentsSize はタイムスタンプを含む場合の既定のバイト数（TSAの証明書チェーン分を上乗せ）
+	defaultTimestampContentsSize = 20000
+	// defaultDocumentTimestampFieldName はドキュメントタイムスタンプのフィールド名の既定値
+	defaultDocumentTimestampFieldName = "Timestamp1"
+
+	// sigFlagsSignaturesExist | sigFlagsAppendOnly（AcroFormの/SigFlags）
+	sigFlagsSignaturesExist = 1 << 0
+	sigFlagsAppendOnly      = 1 << 1
+
+	// annotFlagPrint | annotFlagLocked（署名ウィジェットの/F）
+	annotFlagPrint  = 1 << 2
+	annotFlagLocked = 1 << 7
+
+	// annotFlagHidden | annotFlagNoView（表示されない注釈の/F）
+	annotFlagHidden = 1 << 1
+	annotFlagNoView = 1 << 5
+)
+
+// byteRangePlaceholder は署名前の/ByteRange（各値を10桁分確保する）
+var byteRangePlaceholder = core.Array{
+	core.Integer(0), core.Integer(9999999999), core.Integer(9999999999), core.Integer(9999999999),
+}
+
+// SignatureOptions はデジタル署名のオプション
+type SignatureOptions struct {
+	Signer      crypto.Signer       // 署名鍵（RSA / ECDSA。HSMやKMSの鍵も利用可能）
+	Certificate *x509.Certificate   // 署名者の証明書
+	Chain       []*x509.Certificate // 埋め込む中間証明書（任意）
+
+	Name        string    // 署名者名（省略時は証明書のCommonName）
+	Reason      string    // 署名理由
+	Location    string    // 署名場所
+	ContactInfo string    // 連絡先
+	SigningTime time.Time // 署名日時（省略時は出力時の現在時刻）
+
+	FieldName    string               // 署名フィールド名（省略時は "Signature1"）
+	Appearance   *SignatureAppearance // 可視署名の外観（nil = 不可視署名）
+	ContentsSize int                  // 署名データ用に確保するバイト数（省略時は8192、タイムスタンプ付きは20000）
+
+	// Timestamp を設定すると、署名値に対するRFC 3161タイムスタンプをTSAから取得して埋め込む
+	// （証明書の有効期限後も署名時刻を証明できる）
+	Timestamp *TimestampOptions
+
+	// documentTimestamp はドキュメントタイムスタンプ（/Type /DocTimeStamp）として出力するか
+	documentTimestamp bool
+}
+
+// TimestampOptions はRFC 3161タイムスタンプ局（TSA）への接続設定
+type TimestampOptions struct {
+	URL      string       // TSAのURL
+	Client   *http.Client // HTTPクライアント（省略時は http.DefaultClient）
+	Username string       // Basic認証のユーザー名（任意）
+	Password string       // Basic認証のパスワード（任意）
+
+	ContentsSize int // AddDocumentTimestamp でトークン用に確保するバイト数（省略時は20000）
+}
+
+// client はTSAクライアントを生成する
+func (t *TimestampOptions) client() *tsa.Client {
+	return &tsa.Client{URL: t.URL, HTTPClient: t.Client, Username: t.Username, Password: t.Password}
+}
+
+// SignatureAppearance は可視署名の表示位置と内容
+type SignatureAppearance struct {
+	Page   int     // 表示するページ（0-indexed）
+	X, Y   float64 // 左下座標（ポイント）
+	Width  float64 // 幅（ポイント）
+	Height float64 // 高さ（ポイント）
+	Text   string  // 表示テキスト（改行区切り。省略時は署名者名・日時・理由・場所）
+}
+
+// Sign はドキュメントにデジタル署名を設定する
+// 署名はWriteTo時に計算され、/ByteRangeで示される範囲のSHA-256ダイジェストに対する
+// detachedなCMS（PKCS#7）署名として/Contentsに埋め込まれる。
+// 暗号化との併用はサポートしていない。
+func (d *Document) Sign(opts SignatureOptions) error {
+	if d.signature != nil && d.signature.documentTimestamp {
+		return fmt.Errorf("document already has a document timestamp; only one signature per document is supported")
+	}
+	if opts.Signer == nil {
+		return fmt.Errorf("signer is required")
+	}
+	if opts.Certificate == nil {
+		return fmt.Errorf("certificate is required")
+	}
+	if pub, ok := opts.Signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(opts.Certificate.PublicKey) {
+		return fmt.Errorf("signer public key does not match the certificate")
+	}
+	if a := opts.Appearance; a != nil && (a.Width <= 0 || a.Height <= 0) {
+		return fmt.Errorf("signature appearance has invalid size: %.2fx%.2f", a.Width, a.Height)
+	}
+	if opts.ContentsSize < 0 {
+		return fmt.Errorf("contents size must not be negative, got %d", opts.ContentsSize)
+	}
+	if opts.Timestamp != nil && opts.Timestamp.URL == "" {
+		return fmt.Errorf("timestamp URL is required")
+	}
+
+	if opts.FieldName == "" {
+		opts.FieldName = defaultSignatureFieldName
+	}
+	if opts.ContentsSize == 0 {
+		opts.ContentsSize = defaultSignatureContentsSize
+		if opts.Timestamp != nil {
+			opts.ContentsSize = defaultTimestampContentsSize
+		}
+	}
+	if opts.Name == "" {
+		opts.Name = opts.Certificate.Subject.CommonName
+	}
+
+	d.signature = &opts
+	return nil
+}
+
+// AddDocumentTimestamp はドキュメントタイムスタンプ（ISO 32000-2 の /DocTimeStamp）を設定する
+// WriteTo時に/ByteRangeの範囲のSHA-256ダイジェストに対するタイムスタンプトークンをTSAから取得し、
+// /SubFilter /ETSI.RFC3161 の署名として埋め込む。署名鍵は不要。
+// 通常の署名（Sign）との併用はサポートしていない。
+func (d *Document) AddDocumentTimestamp(opts TimestampOptions) error {
+	if d.signature != nil && !d.signature.documentTimestamp {
+		return fmt.Errorf("document is already signed; only one signature per document is supported")
+	}
+	if opts.URL == "" {
+		return fmt.Errorf("timestamp URL is required")
+	}
+	if opts.ContentsSize < 0 {
+		return fmt.Errorf("contents size must not be negative, got %d", opts.ContentsSize)
+	}
+
+	contentsSize := opts.ContentsSize
+	if contentsSize == 0 {
+		contentsSize = defaultTimestampContentsSize
+	}
+
+	d.signature = &SignatureOptions{
+		FieldName:         defaultDocumentTimestampFieldName,
+		ContentsSize:      contentsSize,
+		Timestamp:         &opts,
+		documentTimestamp: true,
+	}
+	return nil
+}
+
+// signatureFieldAnnotation は署名フィールドとそのウィジェット注釈を兼ねる辞書を生成する
+type signatureFieldAnnotation struct {
+	name   string
+	page   int
+	rect   core.Array
+	sigRef *core.Reference // 署名辞書（/V）
+	apRef  *core.Reference // 外観ストリーム（nil = 不可視署名）
+}
+
+func (a *signatureFieldAnnotation) hasCalculation() bool {
+	return false
+}
+
+func (a *signatureFieldAnnotation) annotationDict(pageRef *core.Reference) core.Dictionary {
+	dict := core.Dictionary{
+		core.Name("Type"):    core.Name("Annot"),
+		core.Name("Subtype"): core.Name("Widget"),
+		core.Name("FT"):      core.Name("Sig"),
+		core.Name("T"):       textString(a.name),
+		core.Name("Rect"):    a.rect,
+		core.Name("F"):       core.Integer(annotFlagPrint | annotFlagLocked),
+		core.Name("P"):       pageRef,
+		core.Name("V"):       a.sigRef,
+	}
+	if a.apRef != nil {
+		dict[core.Name("AP")] = core.Dictionary{core.Name("N"): a.apRef}
+	}
+	return dict
+}
+
+// prepareField は署名辞書の番号を予約し、外観ストリームを出力して署名フィールドを生成する
+func (o *SignatureOptions) prepareField(w *writer.Writer, pageCount int) (*signatureFieldAnnotation, error) {
+	field := &signatureFieldAnnotation{
+		name:   o.FieldName,
+		rect:   rectArray(0, 0, 0, 0),
+		sigRef: &core.Reference{ObjectNumber: w.ReserveObject()},
+	}
+
+	if a := o.Appearance; a != nil {
+		if a.Page < 0 || a.Page >= pageCount {
+			return nil, fmt.Errorf("signature appearance page %d out of range (0-%d)", a.Page, pageCount-1)
+		}
+		apNum, err := w.AddObject(o.appearanceStream())
+		if err != nil {
+			return nil, err
+		}
+		field.page = a.Page
+		field.rect = rectArray(a.X, a.Y, a.Width, a.Height)
+		field.apRef = &core.Reference{ObjectNumber: apNum}
+	} else if pageCount == 0 {
+		return nil, fmt.Errorf("cannot sign a document without pages")
+	}
+
+	return field, nil
+}
+
+// signatureDict は署名値を埋め込む前の署名辞書を生成する
+func (o *SignatureOptions) signatureDict() core.Dictionary {
+	if o.documentTimestamp {
+		return core.Dictionary{
+			core.Name("Type"):      core.Name("DocTimeStamp"),
+			core.Name("Filter"):    core.Name("Adobe.PPKLite"),
+			core.Name("SubFilter"): core.Name("ETSI.RFC3161"),
+			core.Name("ByteRange"): byteRangePlaceholder,
+			core.Name("Contents"):  core.String(make([]byte, o.ContentsSize)),
+		}
+	}
+
+	dict := core.Dictionary{
+		core.Name("Type"):      core.Name("Sig"),
+		core.Name("Filter"):    core.Name("Adobe.PPKLite"),
+		core.Name("SubFilter"): core.Name("adbe.pkcs7.detached"),
+		core.Name("ByteRange"): byteRangePlaceholder,
+		core.Name("Contents"):  core.String(make([]byte, o.ContentsSize)),
+		core.Name("M"):         core.String(formatPDFDate(o.SigningTime)),
+	}
+	for key, value := range map[string]string{
+		"Name":        o.Name,
+		"Reason":      o.Reason,
+		"Location":    o.Location,
+		"ContactInfo": o.ContactInfo,
+	} {
+		if value != "" {
+			dict[core.Name(key)] = textString(value)
+		}
+	}
+	return dict
+}
+
+// appearanceStream は可視署名の外観（枠線とテキスト）を描くForm XObjectを生成する
+func (o *SignatureOptions) appearanceStream() *core.Stream {
+	a := o.Appearance
+
+	lines := strings.Split(a.Text, "\n")
+	if a.Text == "" {
+		lines = []string{
+			"Digitally signed by " + o.Name,
+			"Date: " + o.SigningTime.Format("2006-01-02 15:04:05 -07:00"),
+		}
+		if o.Reason != "" {
+			lines = append(lines, "Reason: "+o.Reason)
+		}
+		if o.Location != "" {
+			lines = append(lines, "Location: "+o.Location)
+		}
+	}
+
+	const padding = 4.0
+	fontSize := (a.Height - 2*padding) / (float64(len(lines)) * 1.2)
+	if fontSize > 10 {
+		fontSize = 10
+	}
+	leading := fontSize * 1.2
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "q 0 G 0.5 w 0.25 0.25 %.2f %.2f re S Q\n", a.Width-0.5, a.Height-0.5)
+	fmt.Fprintf(&content, "BT\n/Helv %.2f Tf 0 g\n%.2f %.2f Td\n%.2f TL\n", fontSize, padding, a.Height-padding-fontSize, leading)
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("T*\n")
+		}
+		fmt.Fprintf(&content, "(%s) Tj\n", escapeString(toLatin1(line)))
+	}
+	content.WriteString("ET\n")
+
+	return &core.Stream{
+		Dict: core.Dictionary{
+			core.Name("Type"):    core.Name("XObject"),
+			core.Name("Subtype"): core.Name("Form"),
+			core.Name("BBox"):    core.Array{core.Integer(0), core.Integer(0), core.Real(a.Width), core.Real(a.Height)},
+			core.Name("Resources"): core.Dictionary{
+				core.Name("Font"): core.Dictionary{
+					core.Name("Helv"): core.Dictionary{
+						core.Name("Type"):     core.Name("Font"),
+						core.Name("Subtype"):  core.Name("Type1"),
+						core.Name("BaseFont"): core.Name("Helvetica"),
+						core.Name("Encoding"): core.Name("WinAnsiEncoding"),
+					},
+				},
+			},
+			core.Name("Length"): core.Integer(content.Len()),
+		},
+		Data: content.Bytes(),
+	}
+}
+
+// toLatin1 は標準フォントで描画できない文字を '?' に置き換える
+func toLatin1(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < 256 {
+			b = append(b, byte(r))
+		} else {
+			b = append(b, '?')
+		}
+	}
+	return string(b)
+}
+
+// sign は出力済みPDFの/ByteRangeと/Contentsを埋め、署名を完成させる（pdfを直接書き換える）
+func (o *SignatureOptions) sign(pdf []byte) error {
+	contentsPlaceholder := []byte("/Contents <" + strings.Repeat("0", 2*o.ContentsSize) + ">")
+	pos := bytes.Index(pdf, contentsPlaceholder)
+	if pos < 0 {
+		return fmt.Errorf("signature contents placeholder not found")
+	}
+	start := pos + len("/Contents ")
+	end := pos + len(contentsPlaceholder)
+
+	// 署名辞書のキーはソートされて出力されるため、/ByteRangeは/Contentsより前にある
+	brPlaceholder := []byte("/ByteRange [0 9999999999 9999999999 9999999999]")
+	brPos := bytes.LastIndex(pdf[:pos], brPlaceholder)
+	if brPos < 0 {
+		return fmt.Errorf("signature byte range placeholder not found")
+	}
+	byteRange := fmt.Sprintf("/ByteRange [0 %d %d %d]", start, end, len(pdf)-end)
+	copy(pdf[brPos:], byteRange+strings.Repeat(" ", len(brPlaceholder)-len(byteRange)))
+
+	digest := sha256.New()
+	digest.Write(pdf[:start])
+	digest.Write(pdf[end:])
+
+	der, err := o.signDigest(digest.Sum(nil))
+	if err != nil {
+		return err
+	}
+
+	encoded := strings.ToUpper(hex.EncodeToString(der))
+	if len(encoded) > 2*o.ContentsSize {
+		return fmt.Errorf("signature needs %d bytes but only %d are reserved; increase ContentsSize", len(der), o.ContentsSize)
+	}
+	copy(pdf[start+1:], encoded)
+	return nil
+}
+
+// signDigest は署名範囲のダイジェストから/Contentsに埋め込むDERデータを生成する
+func (o *SignatureOptions) signDigest(digest []byte) ([]byte, error) {
+	// ドキュメントタイムスタンプはタイムスタンプトークンそのものを埋め込む
+	if o.documentTimestamp {
+		token, err := o.Timestamp.client().Timestamp(digest, crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document timestamp: %w", err)
+		}
+		return token, nil
+	}
+
+	signOpts := cms.SignOptions{
+		Signer:      o.Signer,
+		Certificate: o.Certificate,
+		Chain:       o.Chain,
+		SigningTime: o.SigningTime,
+	}
+	if o.Timestamp != nil {
+		// 署名タイムスタンプは署名値のハッシュに対して取得する（RFC 3161 Appendix A）
+		client := o.Timestamp.client()
+		signOpts.Timestamp = func(signature []byte) ([]byte, error) {
+			sum := sha256.Sum256(signature)
+			return client.Timestamp(sum[:], crypto.SHA256)
+		}
+	}
+
+	der, err := cms.SignDetached(digest, signOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signature: %w", err)
+	}
+	return der, nil
+}
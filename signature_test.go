@@ -0,0 +1,509 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/cms"
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// newTestCertificate は自己署名証明書と鍵を生成する
+func newTestCertificate(t *testing.T, key crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "Test Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() failed: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// recordingSigner は署名対象のダイジェストと署名値を記録する
+type recordingSigner struct {
+	crypto.Signer
+	digest    []byte
+	signature []byte
+}
+
+func (s *recordingSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	sig, err := s.Signer.Sign(r, digest, opts)
+	s.digest, s.signature = digest, sig
+	return sig, err
+}
+
+// signatureDictOf は出力されたPDFから署名辞書を取り出す
+func signatureDictOf(t *testing.T, data []byte) core.Dictionary {
+	t.Helper()
+
+	reader, err := OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	catalog, err := reader.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	acroForm, ok := reader.r.Resolve(catalog["AcroForm"]).(core.Dictionary)
+	if !ok {
+		t.Fatal("AcroForm not found")
+	}
+	if acroForm["SigFlags"] != core.Integer(3) {
+		t.Errorf("SigFlags = %v, want 3", acroForm["SigFlags"])
+	}
+	fields, _ := reader.r.Resolve(acroForm["Fields"]).(core.Array)
+	for _, f := range fields {
+		field, _ := reader.r.Resolve(f).(core.Dictionary)
+		if field["FT"] == core.Name("Sig") {
+			sig, ok := reader.r.Resolve(field["V"]).(core.Dictionary)
+			if !ok {
+				t.Fatal("signature field has no /V dictionary")
+			}
+			return sig
+		}
+	}
+	t.Fatal("signature field not found")
+	return nil
+}
+
+func TestDocumentSign(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		key        crypto.Signer
+		appearance *SignatureAppearance
+		verify     func(pub crypto.PublicKey, digest, sig []byte) bool
+	}{
+		{
+			name: "RSA invisible",
+			key:  rsaKey,
+			verify: func(pub crypto.PublicKey, digest, sig []byte) bool {
+				return rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), crypto.SHA256, digest, sig) == nil
+			},
+		},
+		{
+			name:       "ECDSA visible",
+			key:        ecKey,
+			appearance: &SignatureAppearance{Page: 1, X: 50, Y: 50, Width: 200, Height: 60},
+			verify: func(pub crypto.PublicKey, digest, sig []byte) bool {
+				return ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), digest, sig)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := newTestCertificate(t, tt.key)
+			signer := &recordingSigner{Signer: tt.key}
+
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			page := doc.AddPage(PageSizeA4, Portrait)
+			if err := page.SetFont(FontHelvetica, 12); err != nil {
+				t.Fatal(err)
+			}
+			if err := page.DrawText("Signed content", 100, 700); err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.Sign(SignatureOptions{
+				Signer:      signer,
+				Certificate: cert,
+				Reason:      "承認",
+				Location:    "Tokyo",
+				Appearance:  tt.appearance,
+			}); err != nil {
+				t.Fatalf("Sign() failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+			data := buf.Bytes()
+
+			sig := signatureDictOf(t, data)
+			if sig["SubFilter"] != core.Name("adbe.pkcs7.detached") {
+				t.Errorf("SubFilter = %v", sig["SubFilter"])
+			}
+			if got := rawTextString(sig["Name"]); got != "Test Signer" {
+				t.Errorf("Name = %q, want %q", got, "Test Signer")
+			}
+			if got := rawTextString(sig["Reason"]); got != "承認" {
+				t.Errorf("Reason = %q, want %q", got, "承認")
+			}
+
+			// ByteRangeは/Contentsの16進文字列を除くファイル全体を覆う
+			br, _ := sig["ByteRange"].(core.Array)
+			if len(br) != 4 {
+				t.Fatalf("ByteRange = %v", sig["ByteRange"])
+			}
+			start, end := int(br[1].(core.Integer)), int(br[2].(core.Integer))
+			if br[0] != core.Integer(0) || int(br[3].(core.Integer)) != len(data)-end {
+				t.Fatalf("ByteRange = %v does not cover the file (len %d)", br, len(data))
+			}
+			if data[start] != '<' || data[end-1] != '>' {
+				t.Fatalf("ByteRange gap does not match /Contents: %q...%q", data[start], data[end-1])
+			}
+
+			// CMSに含まれるmessageDigestがByteRangeのダイジェストと一致する
+			digest := sha256.New()
+			digest.Write(data[:start])
+			digest.Write(data[end:])
+			contents := []byte(sig["Contents"].(core.String))
+			if !bytes.Contains(contents, digest.Sum(nil)) {
+				t.Error("CMS does not contain the ByteRange digest")
+			}
+			if !bytes.Contains(contents, cert.Raw) {
+				t.Error("CMS does not contain the signer certificate")
+			}
+			if !bytes.Contains(contents, signer.signature) {
+				t.Error("CMS does not contain the signature value")
+			}
+			if !tt.verify(cert.PublicKey, signer.digest, signer.signature) {
+				t.Error("signature does not verify")
+			}
+		})
+	}
+}
+
+func TestDocumentSign_Errors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+
+	tests := []struct {
+		name      string
+		opts      SignatureOptions
+		encrypt   bool
+		wantSign  bool // Sign() でエラーになるか
+		wantWrite bool // WriteTo() でエラーになるか
+	}{
+		{name: "missing signer", opts: SignatureOptions{Certificate: cert}, wantSign: true},
+		{name: "missing certificate", opts: SignatureOptions{Signer: key}, wantSign: true},
+		{name: "key mismatch", opts: SignatureOptions{Signer: otherKey, Certificate: cert}, wantSign: true},
+		{name: "invalid appearance", opts: SignatureOptions{Signer: key, Certificate: cert, Appearance: &SignatureAppearance{}}, wantSign: true},
+		{name: "appearance page out of range", opts: SignatureOptions{Signer: key, Certificate: cert, Appearance: &SignatureAppearance{Page: 3, Width: 10, Height: 10}}, wantWrite: true},
+		{name: "contents too small", opts: SignatureOptions{Signer: key, Certificate: cert, ContentsSize: 16}, wantWrite: true},
+		{name: "with encryption", opts: SignatureOptions{Signer: key, Certificate: cert}, encrypt: true, wantWrite: true},
+		{name: "timestamp without URL", opts: SignatureOptions{Signer: key, Certificate: cert, Timestamp: &TimestampOptions{}}, wantSign: true},
+		{name: "timestamp server unavailable", opts: SignatureOptions{Signer: key, Certificate: cert, Timestamp: &TimestampOptions{URL: "http://127.0.0.1:1/"}}, wantWrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			if tt.encrypt {
+				if err := doc.SetEncryption(EncryptionOptions{UserPassword: "user", KeyLength: 128}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := doc.Sign(tt.opts)
+			if (err != nil) != tt.wantSign {
+				t.Fatalf("Sign() error = %v, wantErr %v", err, tt.wantSign)
+			}
+			if err != nil {
+				return
+			}
+
+			err = doc.WriteTo(io.Discard)
+			if (err != nil) != tt.wantWrite {
+				t.Errorf("WriteTo() error = %v, wantErr %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
+
+// testMessageImprint / testTSTInfo などはテスト用TSAが扱うRFC 3161の構造
+type testMessageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+type testTimeStampReq struct {
+	Version        int
+	MessageImprint testMessageImprint
+	Nonce          *big.Int `asn1:"optional"`
+	CertReq        bool     `asn1:"optional,default:false"`
+}
+
+type testTSTInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint testMessageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+	Nonce          *big.Int  `asn1:"optional"`
+}
+
+type testTimeStampResp struct {
+	Status struct{ Status int }
+	Token  asn1.RawValue
+}
+
+// newTestTSA はgenTimeの時刻でタイムスタンプを発行するテスト用TSAを起動し、TSAの証明書を返す
+func newTestTSA(t *testing.T, genTime time.Time) (*httptest.Server, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(100),
+		Subject:      pkix.Name{CommonName: "Test TSA"},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req testTimeStampReq
+		if _, err := asn1.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		info, err := asn1.Marshal(testTSTInfo{
+			Version:        1,
+			Policy:         asn1.ObjectIdentifier{1, 2, 3, 4},
+			MessageImprint: req.MessageImprint,
+			SerialNumber:   big.NewInt(1),
+			GenTime:        genTime.UTC(),
+			Nonce:          req.Nonce,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		token, err := cms.SignAttached(info, cms.OIDTSTInfo, cms.SignOptions{Signer: key, Certificate: cert})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp, _ := asn1.Marshal(testTimeStampResp{Token: asn1.RawValue{FullBytes: token}})
+		w.Write(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, cert
+}
+
+func TestDocumentSign_Timestamp(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 署名者の証明書は現在すでに失効しているが、タイムスタンプの時刻には有効だった
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(43),
+		Subject:      pkix.Name{CommonName: "Expired Signer"},
+		NotBefore:    time.Now().Add(-3 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genTime := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	server, tsaCert := newTestTSA(t, genTime)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	roots.AddCert(tsaCert)
+
+	tests := []struct {
+		name          string
+		timestamp     *TimestampOptions
+		signingTime   time.Time
+		wantTimestamp bool
+		wantValid     bool
+	}{
+		{
+			name:          "with timestamp",
+			timestamp:     &TimestampOptions{URL: server.URL},
+			wantTimestamp: true,
+			wantValid:     true,
+		},
+		{
+			name:      "without timestamp",
+			timestamp: nil,
+			wantValid: false, // 署名時刻（現在）には証明書が失効している
+		},
+		{
+			// 署名日時は署名者が自由に書けるので、有効期間内に遡らせてもチェーンの検証には使わない
+			name:        "backdated signing time",
+			signingTime: genTime,
+			wantValid:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			if err := doc.Sign(SignatureOptions{Signer: key, Certificate: cert, Timestamp: tt.timestamp, SigningTime: tt.signingTime}); err != nil {
+				t.Fatalf("Sign() failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reader.Close()
+
+			results, err := reader.VerifySignatures(roots)
+			if err != nil {
+				t.Fatalf("VerifySignatures() failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d signatures, want 1", len(results))
+			}
+
+			v := results[0]
+			if !v.Intact {
+				t.Errorf("Intact = false (err: %v)", v.Err)
+			}
+			if v.Valid() != tt.wantValid {
+				t.Errorf("Valid() = %v, want %v (err: %v)", v.Valid(), tt.wantValid, v.Err)
+			}
+			if tt.wantTimestamp && !v.Timestamp.Equal(genTime) {
+				t.Errorf("Timestamp = %v, want %v", v.Timestamp, genTime)
+			}
+			if !tt.wantTimestamp && !v.Timestamp.IsZero() {
+				t.Errorf("Timestamp = %v, want zero", v.Timestamp)
+			}
+		})
+	}
+}
+
+func TestDocumentAddDocumentTimestamp(t *testing.T) {
+	genTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	server, tsaCert := newTestTSA(t, genTime)
+
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.DrawText("Archived content", 100, 700); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.AddDocumentTimestamp(TimestampOptions{URL: server.URL}); err != nil {
+		t.Fatalf("AddDocumentTimestamp() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	stamped := buf.Bytes()
+
+	sig := signatureDictOf(t, stamped)
+	if sig[core.Name("Type")] != core.Name("DocTimeStamp") || sig[core.Name("SubFilter")] != core.Name("ETSI.RFC3161") {
+		t.Errorf("Type/SubFilter = %v/%v", sig[core.Name("Type")], sig[core.Name("SubFilter")])
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tsaCert)
+
+	tests := []struct {
+		name      string
+		data      []byte
+		wantValid bool
+	}{
+		{name: "valid", data: stamped, wantValid: true},
+		{name: "tampered", data: bytes.Replace(stamped, []byte("Archived content"), []byte("Modified content"), 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reader.Close()
+
+			results, err := reader.VerifySignatures(roots)
+			if err != nil {
+				t.Fatalf("VerifySignatures() failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d signatures, want 1", len(results))
+			}
+
+			v := results[0]
+			if v.Valid() != tt.wantValid {
+				t.Errorf("Valid() = %v, want %v (err: %v)", v.Valid(), tt.wantValid, v.Err)
+			}
+			if v.FieldName != "Timestamp1" || v.SubFilter != "ETSI.RFC3161" {
+				t.Errorf("FieldName/SubFilter = %q/%q", v.FieldName, v.SubFilter)
+			}
+			if tt.wantValid && !v.Timestamp.Equal(genTime) {
+				t.Errorf("Timestamp = %v, want %v", v.Timestamp, genTime)
+			}
+			if v.Certificate == nil || !v.Certificate.Equal(tsaCert) {
+				t.Error("Certificate is not the TSA certificate")
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ryomak/gopdf/internal/content"
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/reader"
+)
+
+// PageRange はページの範囲（0-indexed、StartとEndを含む）
+type PageRange struct {
+	Start int
+	End   int
+}
+
+// SinglePage は1ページだけの範囲を返す
+func SinglePage(pageNum int) PageRange {
+	return PageRange{Start: pageNum, End: pageNum}
+}
+
+// Split はPDFを範囲ごとに分割し、それぞれを独立したPDFとして返す
+// 結果はrangesと同じ順に並ぶ。各PDFにはその範囲のページが使うオブジェクトだけが含まれる
+func Split(in io.ReadSeeker, ranges []PageRange) ([][]byte, error) {
+	r, err := OpenReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	outputs := make([][]byte, 0, len(ranges))
+	for i, pr := range ranges {
+		var buf bytes.Buffer
+		if err := r.ExtractPages(&buf, pr); err != nil {
+			return nil, fmt.Errorf("failed to extract range %d: %w", i, err)
+		}
+		outputs = append(outputs, buf.Bytes())
+	}
+	return outputs, nil
+}
+
+// ExtractPages は範囲内のページだけを持つPDFを書き出す
+// ページは範囲の順に並び、同じページを複数回含めることもできる
+// ページのコンテンツが使わないリソース（ページツリーから継承した共有のフォントなど）とほかのページは出力しない
+// Info辞書のメタデータは引き継ぐ
+// 設計書: docs/merge_design.md
+func (r *PDFReader) ExtractPages(out io.Writer, ranges ...PageRange) error {
+	if len(ranges) == 0 {
+		return fmt.Errorf("no page ranges")
+	}
+
+	refs, err := r.r.GetPageReferences()
+	if err != nil {
+		return fmt.Errorf("failed to get pages: %w", err)
+	}
+
+	var pageNums []int
+	for _, pr := range ranges {
+		if pr.Start < 0 || pr.End >= len(refs) || pr.Start > pr.End {
+			return fmt.Errorf("invalid page range [%d, %d] for %d pages", pr.Start, pr.End, len(refs))
+		}
+		for i := pr.Start; i <= pr.End; i++ {
+			pageNums = append(pageNums, i)
+		}
+	}
+
+	doc := New()
+	if err := doc.appendPages(r, refs, pageNums, true); err != nil {
+		return err
+	}
+	if info, err := r.r.GetInfo(); err == nil && len(info) > 0 {
+		doc.SetMetadata(parseInfoDict(info))
+	}
+	return doc.WriteTo(out)
+}
+
+// resourceCategories はコンテンツストリームから名前で参照されるリソースの種類
+var resourceCategories = []core.Name{"Font", "XObject", "ExtGState", "ColorSpace", "Pattern", "Shading", "Properties"}
+
+// maxResourceUsageDepth はリソースを持たないフォームXObjectを辿る最大深さ
+const maxResourceUsageDepth = 16
+
+// prunePageResources はページの/Resourcesから、コンテンツが名前で参照しないものを除いた辞書を返す
+// コンテンツを解析できない場合や、使われるリソースを確定できない場合はfalseを返す（元の/Resourcesをそのまま使う）
+func prunePageResources(src *reader.Reader, page core.Dictionary) (core.Dictionary, bool) {
+	resources, ok := src.Resolve(page[core.Name("Resources")]).(core.Dictionary)
+	if !ok {
+		return nil, false
+	}
+	data, err := src.GetPageContents(page)
+	if err != nil {
+		return nil, false
+	}
+
+	used := make(map[core.Name]map[core.Name]bool)
+	if err := collectResourceUsage(src, data, resources, used, 0); err != nil {
+		return nil, false
+	}
+
+	pruned := make(core.Dictionary, len(resources))
+	for key, value := range resources {
+		if !isResourceCategory(key) {
+			pruned[key] = value
+			continue
+		}
+		entries, ok := src.Resolve(value).(core.Dictionary)
+		if !ok {
+			continue
+		}
+		kept := core.Dictionary{}
+		for name, entry := range entries {
+			if used[key][name] {
+				kept[name] = entry
+			}
+		}
+		if len(kept) > 0 {
+			pruned[key] = kept
+		}
+	}
+	return pruned, true
+}
+
+// isResourceCategory はkeyが名前で参照されるリソースの種類かを返す
+func isResourceCategory(key core.Name) bool {
+	for _, category := range resourceCategories {
+		if key == category {
+			return true
+		}
+	}
+	return false
+}
+
+// collectResourceUsage はコンテンツストリームのオペレータが参照するリソースの名前をusedに加える
+// /Resourcesを持たないフォームXObjectとType 3フォントはページのリソースを使うため、
+// フォームはその内容も辿り、Type 3フォントは確定できないとしてエラーを返す
+func collectResourceUsage(src *reader.Reader, data []byte, resources core.Dictionary, used map[core.Name]map[core.Name]bool, depth int) error {
+	if depth > maxResourceUsageDepth {
+		return fmt.Errorf("form XObjects are nested too deeply")
+	}
+	operations, err := content.NewStreamParser(data).ParseOperations()
+	if err != nil {
+		return err
+	}
+
+	use := func(category core.Name, obj core.Object) (core.Name, bool) {
+		name, ok := obj.(core.Name)
+		if !ok {
+			return "", false
+		}
+		if used[category] == nil {
+			used[category] = make(map[core.Name]bool)
+		}
+		first := !used[category][name]
+		used[category][name] = true
+		return name, first
+	}
+	lookup := func(category, name core.Name) core.Object {
+		entries, _ := src.Resolve(resources[category]).(core.Dictionary)
+		return src.Resolve(entries[name])
+	}
+
+	for _, op := range operations {
+		operands := op.Operands
+		switch op.Operator {
+		case "Tf": // Set font
+			if len(operands) < 1 {
+				continue
+			}
+			name, first := use("Font", operands[0])
+			if !first {
+				continue
+			}
+			font, _ := lookup("Font", name).(core.Dictionary)
+			if subtype, _ := font[core.Name("Subtype")].(core.Name); subtype == "Type3" {
+				if _, ok := font[core.Name("Resources")]; !ok {
+					return fmt.Errorf("type 3 font %s uses the page resources", name)
+				}
+			}
+
+		case "Do": // Paint XObject
+			if len(operands) < 1 {
+				continue
+			}
+			name, first := use("XObject", operands[0])
+			if !first {
+				continue
+			}
+			form, ok := lookup("XObject", name).(*core.Stream)
+			if !ok {
+				continue
+			}
+			if subtype, _ := form.Dict[core.Name("Subtype")].(core.Name); subtype != "Form" {
+				continue
+			}
+			if _, ok := form.Dict[core.Name("Resources")]; ok {
+				continue
+			}
+			formData, err := src.DecodeStream(form)
+			if err != nil {
+				return err
+			}
+			if err := collectResourceUsage(src, formData, resources, used, depth+1); err != nil {
+				return err
+			}
+
+		case "gs": // Set graphics state parameters
+			if len(operands) >= 1 {
+				use("ExtGState", operands[0])
+			}
+
+		case "CS", "cs": // Set color space
+			if len(operands) >= 1 {
+				use("ColorSpace", operands[0])
+			}
+
+		case "SCN", "scn": // Set color (pattern name)
+			if len(operands) >= 1 {
+				use("Pattern", operands[len(operands)-1])
+			}
+
+		case "sh": // Paint shading
+			if len(operands) >= 1 {
+				use("Shading", operands[0])
+			}
+
+		case "BDC", "DP": // Marked content with property list
+			if len(operands) >= 2 {
+				use("Properties", operands[1])
+			}
+
+		case "ID": // Inline image data (the operands are the image dictionary)
+			for i := 0; i+1 < len(operands); i += 2 {
+				if key, _ := operands[i].(core.Name); key == "CS" || key == "ColorSpace" {
+					use("ColorSpace", operands[i+1])
+				}
+			}
+		}
+	}
+	return nil
+}
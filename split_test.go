@@ -0,0 +1,164 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// splitSourcePDF はページツリーの共有リソース（F1, F2, Fm1, Im1）を継承する3ページのPDFを作成する
+// 1ページ目はF1、2ページ目はF2とF1を使うフォーム（リソースなし）、3ページ目は画像を使う
+func splitSourcePDF() []byte {
+	stream := func(data string) string {
+		return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+	}
+	return buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 /MediaBox [0 0 300 200] " +
+			"/Resources << /Font << /F1 6 0 R /F2 7 0 R >> /XObject << /Fm1 8 0 R /Im1 9 0 R >> /ProcSet [/PDF /Text] >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 10 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 11 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 12 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		"<< /Type /XObject /Subtype /Form /BBox [0 0 300 200] /Length 36 >>\nstream\nBT /F1 12 Tf 20 50 Td (Footer) Tj ET\nendstream",
+		"<< /Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8 /Length 1 >>\nstream\n\x80\nendstream",
+		stream("BT /F1 12 Tf 20 150 Td (One) Tj ET"),
+		stream("BT /F2 12 Tf 20 150 Td (Two) Tj ET /Fm1 Do"),
+		stream("q 100 0 0 100 0 0 cm /Im1 Do Q"),
+	})
+}
+
+// pageResourceNames はページの/Resourcesの種類ごとの名前を "Font:F1" の形で返す
+func pageResourceNames(t *testing.T, reader *PDFReader, pageNum int) []string {
+	t.Helper()
+	page, err := reader.r.GetPage(pageNum)
+	if err != nil {
+		t.Fatalf("GetPage failed: %v", err)
+	}
+	resources, err := reader.r.GetPageResources(page)
+	if err != nil {
+		t.Fatalf("GetPageResources failed: %v", err)
+	}
+	var names []string
+	for category, value := range resources {
+		entries, ok := reader.r.Resolve(value).(core.Dictionary)
+		if !ok {
+			names = append(names, string(category))
+			continue
+		}
+		for name := range entries {
+			names = append(names, string(category)+":"+string(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSplit(t *testing.T) {
+	outputs, err := Split(bytes.NewReader(splitSourcePDF()), []PageRange{SinglePage(0), {Start: 1, End: 2}})
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if len(outputs) != 2 {
+		t.Fatalf("outputs = %d, want 2", len(outputs))
+	}
+
+	tests := []struct {
+		name      string
+		output    int
+		page      int
+		wantText  string
+		wantNames []string
+	}{
+		{name: "first range", output: 0, page: 0, wantText: "One", wantNames: []string{"Font:F1", "ProcSet"}},
+		{name: "form uses page resources", output: 1, page: 0, wantText: "Two", wantNames: []string{"Font:F1", "Font:F2", "ProcSet", "XObject:Fm1"}},
+		{name: "image page", output: 1, page: 1, wantNames: []string{"ProcSet", "XObject:Im1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(outputs[tt.output]))
+			if err != nil {
+				t.Fatalf("Failed to open output: %v", err)
+			}
+			defer reader.Close()
+
+			text, err := reader.ExtractPageText(tt.page)
+			if err != nil {
+				t.Fatalf("ExtractPageText failed: %v", err)
+			}
+			if !strings.Contains(text, tt.wantText) {
+				t.Errorf("text = %q, want to contain %q", text, tt.wantText)
+			}
+			if got := pageResourceNames(t, reader, tt.page); strings.Join(got, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("resources = %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+
+	// 使わないオブジェクト（Courierと画像）は出力されない
+	first := string(outputs[0])
+	for _, unused := range []string{"/Courier", "/Image"} {
+		if strings.Contains(first, unused) {
+			t.Errorf("first output contains unused object %s", unused)
+		}
+	}
+}
+
+func TestPDFReader_ExtractPages(t *testing.T) {
+	reader, err := OpenReader(bytes.NewReader(splitSourcePDF()))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	tests := []struct {
+		name      string
+		ranges    []PageRange
+		wantTexts []string
+		wantErr   bool
+	}{
+		{name: "reordered", ranges: []PageRange{SinglePage(1), SinglePage(0)}, wantTexts: []string{"Two", "One"}},
+		{name: "repeated page", ranges: []PageRange{SinglePage(0), {Start: 0, End: 1}}, wantTexts: []string{"One", "One", "Two"}},
+		{name: "out of range", ranges: []PageRange{{Start: 2, End: 3}}, wantErr: true},
+		{name: "reversed range", ranges: []PageRange{{Start: 1, End: 0}}, wantErr: true},
+		{name: "no ranges", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := reader.ExtractPages(&buf, tt.ranges...)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractPages failed: %v", err)
+			}
+
+			out, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open output: %v", err)
+			}
+			defer out.Close()
+			if got := out.PageCount(); got != len(tt.wantTexts) {
+				t.Fatalf("PageCount = %d, want %d", got, len(tt.wantTexts))
+			}
+			for i, want := range tt.wantTexts {
+				text, err := out.ExtractPageText(i)
+				if err != nil {
+					t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+				}
+				if !strings.Contains(text, want) {
+					t.Errorf("page %d text = %q, want to contain %q", i, text, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,498 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// StampPosition はスタンプを置くページ上の基準位置
+type StampPosition int
+
+const (
+	StampCenter      StampPosition = iota // 中央（デフォルト）
+	StampTopLeft                          // 左上
+	StampTop                              // 上端の中央
+	StampTopRight                         // 右上
+	StampLeft                             // 左端の中央
+	StampRight                            // 右端の中央
+	StampBottomLeft                       // 左下
+	StampBottom                           // 下端の中央
+	StampBottomRight                      // 右下
+)
+
+// StampOptions はスタンプを押すページと、配置・見た目の設定
+// 位置は表示される向き（/Rotateを適用した向き）の、表示される領域（CropBox）に対して決める
+type StampOptions struct {
+	Pages    []int         // 押すページ（0-indexed）。nilの場合はすべてのページ
+	Position StampPosition // 基準位置
+	Margin   float64       // 基準位置が端の場合の、ページの端からの余白（ポイント）
+	OffsetX  float64       // 基準位置から右にずらす量（ポイント）
+	OffsetY  float64       // 基準位置から上にずらす量（ポイント）
+	Rotation float64       // 反時計回りの回転角度（度）。スタンプの中心を軸に回転する
+	Opacity  float64       // 不透明度（0〜1、0の場合は1 = 不透明）
+	Underlay bool          // 元のコンテンツの下に描く（背景の透かし）
+}
+
+// TextStamp はページに押す文字列
+// Textの {page} はページ番号（1始まり）、{pages} は総ページ数、{bates} はベイツ番号に置き換える
+type TextStamp struct {
+	Text     string         // 押す文字列
+	Font     StandardFont   // 標準フォント（空の場合はHelvetica。Latin-1以外の文字は '?' になる）
+	TTFFont  *TTFFont       // 日本語などを押す場合のTrueTypeフォント（指定した場合はFontより優先）
+	FontSize float64        // フォントサイズ（0の場合は12）
+	Color    Color          // 文字色
+	Bates    BatesNumbering // {bates} の番号の付け方
+}
+
+// BatesNumbering はベイツ番号（{bates}）の付け方
+// 番号は、スタンプを押すページにページ順で1つずつ振る
+type BatesNumbering struct {
+	Prefix string // 番号の前に付ける文字列（例: "ABC-"）
+	Start  int    // 最初のページの番号（0の場合は1）
+	Digits int    // ゼロ埋めする桁数（例: 6 の場合は 000001）
+}
+
+// ImageStamp はページに押す画像
+// WidthとHeightが両方0の場合は画像のピクセル数をポイントとした大きさ、片方だけ0の場合は縦横比を保つ
+type ImageStamp struct {
+	Image  *Image
+	Width  float64 // 描画する幅（ポイント）
+	Height float64 // 描画する高さ（ポイント）
+}
+
+// PageStamp はページに重ねる別のPDFのページ（レターヘッドの下敷き、記入済みの帳票の重ね合わせなど）
+// 重ねるページは表示される向き（/Rotateを適用した向き）で、表示される領域の内容を描く
+type PageStamp struct {
+	Reader     *PDFReader // 重ねるページを読み込んだPDF（Saveが終わるまで閉じないこと）
+	PageNum    int        // 重ねるページ（0-indexed）。MatchPagesの場合は無視する
+	MatchPages bool       // ページNにReaderのページNを重ねる（Readerにページがないページには重ねない）
+	Scale      float64    // 拡大率（0の場合は1）
+}
+
+// defaultStampFontSize はTextStamp.FontSizeを省略したときのフォントサイズ
+const defaultStampFontSize = 12.0
+
+// Stamper は既存のPDFの各ページに文字列や画像、別のPDFのページを重ねて押し、保存する
+// 「社外秘」などの斜めの透かし、ページ番号、ベイツ番号の付与や、レターヘッドの下敷きに使う
+// 保存はEditorと同じくPDFのオブジェクトを複製するため、しおり・フォーム・注釈などは残る
+// 設計書: docs/stamper_design.md
+type Stamper struct {
+	editor  *Editor
+	stamps  []*stampEntry             // 追加した順（後に追加したものほど上に描く）
+	imports map[*PDFReader]*pdfImport // AddPageで重ねるページの元のPDF（同じPDFのリソースは一度だけ出力する）
+}
+
+// stampEntry はAddText、AddImage、AddPageで追加したスタンプ
+type stampEntry struct {
+	text      *TextStamp
+	image     *ImageStamp
+	page      *PageStamp
+	templates []*ImportedPage // AddPageで重ねるページ（MatchPagesの場合はページ番号順、それ以外は1つ）
+	opts      StampOptions
+}
+
+// placedStamp は1ページに押すスタンプと、その中心を原点として描くコンテンツ
+type placedStamp struct {
+	entry    *stampEntry
+	template *ImportedPage // 重ねるページ（AddPageの場合）
+	width    float64       // 回転する前の幅
+	height   float64       // 回転する前の高さ
+	draw     string        // 中心を原点として描くオペレータ
+}
+
+// NewStamper は読み込んだPDFにスタンプを押すStamperを作成する
+// 暗号化されたPDFは、AuthenticateWithPasswordで認証してから渡す（保存したPDFは暗号化されない）
+// 保存はSaveで行うため、rはSaveが終わるまで閉じないこと
+func NewStamper(r *PDFReader) (*Stamper, error) {
+	editor, err := NewEditor(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Stamper{editor: editor}, nil
+}
+
+// AddText は文字列のスタンプを追加する
+func (s *Stamper) AddText(stamp TextStamp, opts StampOptions) error {
+	if stamp.Text == "" {
+		return fmt.Errorf("stamp text cannot be empty")
+	}
+	if stamp.FontSize < 0 {
+		return fmt.Errorf("invalid font size: %v", stamp.FontSize)
+	}
+	if err := s.checkOptions(opts); err != nil {
+		return err
+	}
+	s.stamps = append(s.stamps, &stampEntry{text: &stamp, opts: opts})
+	return nil
+}
+
+// AddImage は画像のスタンプを追加する
+func (s *Stamper) AddImage(stamp ImageStamp, opts StampOptions) error {
+	if stamp.Image == nil {
+		return fmt.Errorf("image cannot be nil")
+	}
+	if stamp.Width < 0 || stamp.Height < 0 {
+		return fmt.Errorf("invalid image size: %vx%v", stamp.Width, stamp.Height)
+	}
+	if err := s.checkOptions(opts); err != nil {
+		return err
+	}
+	s.stamps = append(s.stamps, &stampEntry{image: &stamp, opts: opts})
+	return nil
+}
+
+// AddPage は別のPDFのページを重ねるスタンプを追加する
+// 下敷きにする場合はopts.Underlayを指定する。位置の既定は中央なので、同じ大きさのページはそのまま重なる
+func (s *Stamper) AddPage(stamp PageStamp, opts StampOptions) error {
+	if stamp.Reader == nil {
+		return fmt.Errorf("reader cannot be nil")
+	}
+	if stamp.Scale < 0 {
+		return fmt.Errorf("invalid scale: %v", stamp.Scale)
+	}
+	if err := s.checkOptions(opts); err != nil {
+		return err
+	}
+
+	imp, ok := s.imports[stamp.Reader]
+	if !ok {
+		imp = &pdfImport{src: stamp.Reader.r, prune: true}
+	}
+	pageNums := []int{stamp.PageNum}
+	if stamp.MatchPages {
+		pageNums = make([]int, min(stamp.Reader.PageCount(), s.editor.PageCount()))
+		for i := range pageNums {
+			pageNums[i] = i
+		}
+	}
+	entry := &stampEntry{page: &stamp, opts: opts}
+	for _, pageNum := range pageNums {
+		tpl, err := newImportedPage(stamp.Reader, imp, pageNum)
+		if err != nil {
+			return err
+		}
+		entry.templates = append(entry.templates, tpl)
+	}
+
+	if s.imports == nil {
+		s.imports = make(map[*PDFReader]*pdfImport)
+	}
+	s.imports[stamp.Reader] = imp
+	s.stamps = append(s.stamps, entry)
+	return nil
+}
+
+// checkOptions はスタンプを押すページと不透明度を確認する
+func (s *Stamper) checkOptions(opts StampOptions) error {
+	for _, pageNum := range opts.Pages {
+		if err := s.editor.checkPage(pageNum); err != nil {
+			return err
+		}
+	}
+	if opts.Opacity < 0 || opts.Opacity > 1 {
+		return fmt.Errorf("opacity must be between 0 and 1, got %v", opts.Opacity)
+	}
+	return nil
+}
+
+// Save はスタンプを押したPDFを書き出す
+// 各スタンプはページごとのフォームXObjectとして出力し、元のコンテンツの上（Underlayの場合は下）に描く
+func (s *Stamper) Save(out io.Writer) error {
+	return s.save(out, false)
+}
+
+// SaveIncremental はスタンプを、元のPDFの後に増分更新として追記する（Editor.SaveIncrementalを参照）
+// 既存の電子署名を壊さずに、承認印や受付番号を押す場合に使う
+func (s *Stamper) SaveIncremental(out io.Writer) error {
+	return s.save(out, true)
+}
+
+// save はスタンプを押したPDFを書き出す。incrementalの場合は増分更新として追記する
+func (s *Stamper) save(out io.Writer, incremental bool) error {
+	// {page} などを置き換えた文字列をページごとに先に決める
+	// （TrueTypeフォントのサブセットに含める文字を、フォントを出力する前に確定させるため）
+	pageCount := s.editor.PageCount()
+	placed := make([][]*placedStamp, pageCount)
+	for _, entry := range s.stamps {
+		for i, pageNum := range entry.pages(pageCount) {
+			stamp, err := entry.place(pageNum, pageCount, i)
+			if err != nil {
+				return fmt.Errorf("failed to prepare stamp on page %d: %w", pageNum, err)
+			}
+			if stamp != nil {
+				placed[pageNum] = append(placed[pageNum], stamp)
+			}
+		}
+	}
+
+	// フォント・画像・重ねるページは、最初に使うときに一度だけ出力する
+	ttfRefs := make(map[*TTFFont]*core.Reference)
+	imageRefs := make(map[*Image]*core.Reference)
+	templateRefs := make(map[*ImportedPage]*core.Reference)
+	copiers := make(map[*pdfImport]*objectCopier)
+	resources := func(w *writer.Writer, stamp *placedStamp) (core.Dictionary, error) {
+		entry := stamp.entry
+		dict := core.Dictionary{}
+		switch {
+		case stamp.template != nil:
+			tpl := stamp.template
+			if _, ok := templateRefs[tpl]; !ok {
+				c, ok := copiers[tpl.source.pdf]
+				if !ok {
+					c = newObjectCopier(tpl.source.pdf.src, w)
+					c.skip = make(map[int]bool)
+					if err := skipPageTree(c.src, c.skip); err != nil {
+						return nil, err
+					}
+					copiers[tpl.source.pdf] = c
+				}
+				ref, err := writeTemplate(w, c, tpl)
+				if err != nil {
+					return nil, fmt.Errorf("failed to write page: %w", err)
+				}
+				// 重ねるページのリソースは、ページごとにその場で出力する
+				if err := c.flush(); err != nil {
+					return nil, err
+				}
+				templateRefs[tpl] = ref
+			}
+			dict[core.Name("XObject")] = core.Dictionary{core.Name("Tpl1"): templateRefs[tpl]}
+		case entry.text != nil && entry.text.TTFFont != nil:
+			ttf := entry.text.TTFFont
+			if _, ok := ttfRefs[ttf]; !ok {
+				ref, err := writer.NewTTFFontEmbedder(w).EmbedTTFFont(ttf.internal, ttf.usedGlyphsSnapshot())
+				if err != nil {
+					return nil, fmt.Errorf("failed to embed TTF font: %w", err)
+				}
+				ttfRefs[ttf] = ref
+			}
+			dict[core.Name("Font")] = core.Dictionary{core.Name("F1"): ttfRefs[ttf]}
+		case entry.text != nil:
+			dict[core.Name("Font")] = core.Dictionary{
+				core.Name("F1"): core.Dictionary{
+					core.Name("Type"):     core.Name("Font"),
+					core.Name("Subtype"):  core.Name("Type1"),
+					core.Name("BaseFont"): core.Name(entry.text.font()),
+					core.Name("Encoding"): core.Name("WinAnsiEncoding"),
+				},
+			}
+		default:
+			img := entry.image.Image
+			if _, ok := imageRefs[img]; !ok {
+				ref, err := writeImageXObject(w, img)
+				if err != nil {
+					return nil, err
+				}
+				imageRefs[img] = ref
+			}
+			dict[core.Name("XObject")] = core.Dictionary{core.Name("Im1"): imageRefs[img]}
+		}
+		if opacity := entry.opts.Opacity; opacity > 0 && opacity < 1 {
+			dict[core.Name("ExtGState")] = core.Dictionary{
+				core.Name("GS1"): core.Dictionary{
+					core.Name("Type"): core.Name("ExtGState"),
+					core.Name("CA"):   core.Real(opacity),
+					core.Name("ca"):   core.Real(opacity),
+				},
+			}
+		}
+		return dict, nil
+	}
+
+	overlay := func(w *writer.Writer, pageNum int, page core.Dictionary) ([]pageOverlay, error) {
+		if len(placed[pageNum]) == 0 {
+			return nil, nil
+		}
+		box := s.editor.r.pageBoxes(page).Visible
+		rotation := s.editor.r.pageRotation(page)
+		width, height := rotatePageSize(rotation, box.Width, box.Height)
+
+		overlays := make([]pageOverlay, 0, len(placed[pageNum]))
+		for _, stamp := range placed[pageNum] {
+			res, err := resources(w, stamp)
+			if err != nil {
+				return nil, err
+			}
+			data := stamp.content(width, height)
+			num, err := w.AddObject(&core.Stream{
+				Dict: core.Dictionary{
+					core.Name("Type"):      core.Name("XObject"),
+					core.Name("Subtype"):   core.Name("Form"),
+					core.Name("BBox"):      rectArray(0, 0, width, height),
+					core.Name("Matrix"):    matrixArray(invertMatrix(displayMatrix(box, rotation))),
+					core.Name("Resources"): res,
+					core.Name("Length"):    core.Integer(len(data)),
+				},
+				Data: data,
+			})
+			if err != nil {
+				return nil, err
+			}
+			overlays = append(overlays, pageOverlay{
+				form:     &core.Reference{ObjectNumber: num},
+				underlay: stamp.entry.opts.Underlay,
+			})
+		}
+		return overlays, nil
+	}
+	return s.editor.save(out, overlay, incremental)
+}
+
+// pages はスタンプを押すページ番号を昇順で返す（重複は除く）
+// MatchPagesで重ねるページがないページは、placeでnilを返す
+func (e *stampEntry) pages(pageCount int) []int {
+	if e.opts.Pages == nil {
+		pages := make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+		return pages
+	}
+	seen := make(map[int]bool, len(e.opts.Pages))
+	var pages []int
+	for _, pageNum := range e.opts.Pages {
+		if !seen[pageNum] {
+			seen[pageNum] = true
+			pages = append(pages, pageNum)
+		}
+	}
+	sort.Ints(pages)
+	return pages
+}
+
+// place はpageNumのページ（総ページ数pageCount、index番目に押すページ）に押すスタンプの内容を決める
+// 押すものがない場合はnilを返す
+func (e *stampEntry) place(pageNum, pageCount, index int) (*placedStamp, error) {
+	if e.page != nil {
+		tpl := e.templates[0]
+		if e.page.MatchPages {
+			if pageNum >= len(e.templates) {
+				return nil, nil
+			}
+			tpl = e.templates[pageNum]
+		}
+		scale := e.page.Scale
+		if scale == 0 {
+			scale = 1
+		}
+		width, height := tpl.width*scale, tpl.height*scale
+		return &placedStamp{
+			entry:    e,
+			template: tpl,
+			width:    width,
+			height:   height,
+			draw:     fmt.Sprintf("%.4f 0 0 %.4f %.4f %.4f cm\n/Tpl1 Do\n", scale, scale, -width/2, -height/2),
+		}, nil
+	}
+
+	if e.image != nil {
+		img := e.image
+		width, height := img.Width, img.Height
+		switch {
+		case width == 0 && height == 0:
+			width, height = float64(img.Image.Width), float64(img.Image.Height)
+		case width == 0:
+			width = float64(img.Image.Width) * height / float64(img.Image.Height)
+		case height == 0:
+			height = float64(img.Image.Height) * width / float64(img.Image.Width)
+		}
+		return &placedStamp{
+			entry:  e,
+			width:  width,
+			height: height,
+			draw:   fmt.Sprintf("%.4f 0 0 %.4f %.4f %.4f cm\n/Im1 Do\n", width, height, -width/2, -height/2),
+		}, nil
+	}
+
+	ts := e.text
+	bates := ts.Bates.Start
+	if bates == 0 {
+		bates = 1
+	}
+	text := strings.NewReplacer(
+		"{page}", strconv.Itoa(pageNum+1),
+		"{pages}", strconv.Itoa(pageCount),
+		"{bates}", fmt.Sprintf("%s%0*d", ts.Bates.Prefix, ts.Bates.Digits, bates+index),
+	).Replace(ts.Text)
+
+	fontSize := ts.FontSize
+	if fontSize == 0 {
+		fontSize = defaultStampFontSize
+	}
+	var width float64
+	var encoded string
+	if ts.TTFFont != nil {
+		var err error
+		if width, err = ts.TTFFont.TextWidth(text, fontSize); err != nil {
+			return nil, fmt.Errorf("failed to measure text: %w", err)
+		}
+		glyphs, err := ts.TTFFont.encodeGlyphs(text)
+		if err != nil {
+			return nil, err
+		}
+		encoded = "<" + glyphs + ">"
+	} else {
+		latin1 := toLatin1(text)
+		width = estimateTextWidth(latin1, fontSize, ts.font().Name())
+		encoded = "(" + escapeString(latin1) + ")"
+	}
+
+	// ベースラインは、大文字の高さ（およそ0.7em）の中央が原点に来る位置にする
+	var draw bytes.Buffer
+	fmt.Fprintf(&draw, "BT\n/F1 %.2f Tf\n%.3f %.3f %.3f rg\n", fontSize, ts.Color.R, ts.Color.G, ts.Color.B)
+	fmt.Fprintf(&draw, "%.4f %.4f Td\n%s Tj\nET\n", -width/2, -fontSize*0.35, encoded)
+	return &placedStamp{entry: e, width: width, height: fontSize, draw: draw.String()}, nil
+}
+
+// font は標準フォントの名前を返す（省略時はHelvetica）
+func (ts *TextStamp) font() StandardFont {
+	if ts.Font == "" {
+		return FontHelvetica
+	}
+	return ts.Font
+}
+
+// content は表示される向きの width × height のページにスタンプを描くコンテンツストリームを返す
+// 端に置く場合は、回転したスタンプ全体が余白の内側に収まる位置にする
+func (p *placedStamp) content(width, height float64) []byte {
+	opts := p.entry.opts
+	rad := opts.Rotation * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	// 回転したスタンプを囲む矩形の大きさ
+	boundW := math.Abs(p.width*cos) + math.Abs(p.height*sin)
+	boundH := math.Abs(p.width*sin) + math.Abs(p.height*cos)
+
+	x, y := width/2, height/2
+	switch opts.Position {
+	case StampTopLeft, StampLeft, StampBottomLeft:
+		x = opts.Margin + boundW/2
+	case StampTopRight, StampRight, StampBottomRight:
+		x = width - opts.Margin - boundW/2
+	}
+	switch opts.Position {
+	case StampTopLeft, StampTop, StampTopRight:
+		y = height - opts.Margin - boundH/2
+	case StampBottomLeft, StampBottom, StampBottomRight:
+		y = opts.Margin + boundH/2
+	}
+	x += opts.OffsetX
+	y += opts.OffsetY
+
+	var buf bytes.Buffer
+	buf.WriteString("q\n")
+	if opts.Opacity > 0 && opts.Opacity < 1 {
+		buf.WriteString("/GS1 gs\n")
+	}
+	fmt.Fprintf(&buf, "%.4f %.4f %.4f %.4f %.4f %.4f cm\n", cos, sin, -sin, cos, x, y)
+	buf.WriteString(p.draw)
+	buf.WriteString("Q\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,491 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// stampContents はページに重ねたフォーム（/Overlay1, /Overlay2, ...）のコンテンツを名前順に返す
+func stampContents(t *testing.T, reader *PDFReader, pageNum int) []string {
+	t.Helper()
+	page, err := reader.r.GetPage(pageNum)
+	if err != nil {
+		t.Fatalf("GetPage(%d) failed: %v", pageNum, err)
+	}
+	resources, _ := reader.r.Resolve(page[core.Name("Resources")]).(core.Dictionary)
+	xobjects, _ := reader.r.Resolve(resources[core.Name("XObject")]).(core.Dictionary)
+
+	var names []string
+	for name := range xobjects {
+		if strings.HasPrefix(string(name), "Overlay") {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+
+	var contents []string
+	for _, name := range names {
+		form, ok := reader.r.Resolve(xobjects[core.Name(name)]).(*core.Stream)
+		if !ok {
+			t.Fatalf("page %d: %s is not a stream", pageNum, name)
+		}
+		data, err := reader.r.DecodeStream(form)
+		if err != nil {
+			t.Fatalf("page %d: failed to decode %s: %v", pageNum, name, err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+func TestStamper(t *testing.T) {
+	jpFont, err := DefaultJapaneseFont()
+	if err != nil {
+		t.Fatalf("DefaultJapaneseFont failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stamps []TextStamp
+		opts   []StampOptions
+		want   [][]string // ページごとの、重ねたフォームが描く文字列
+	}{
+		{
+			name:   "page numbers",
+			stamps: []TextStamp{{Text: "Page {page} of {pages}"}},
+			opts:   []StampOptions{{Position: StampBottom, Margin: 10}},
+			want:   [][]string{{"(Page 1 of 3) Tj"}, {"(Page 2 of 3) Tj"}, {"(Page 3 of 3) Tj"}},
+		},
+		{
+			name:   "bates numbers on selected pages",
+			stamps: []TextStamp{{Text: "{bates}", Bates: BatesNumbering{Prefix: "ABC", Start: 100, Digits: 6}}},
+			opts:   []StampOptions{{Pages: []int{2, 0}, Position: StampBottomRight}},
+			want:   [][]string{{"(ABC000100) Tj"}, nil, {"(ABC000101) Tj"}},
+		},
+		{
+			name: "watermark and page number",
+			stamps: []TextStamp{
+				{Text: "CONFIDENTIAL", FontSize: 40, Color: ColorRed},
+				{Text: "{page}"},
+			},
+			opts: []StampOptions{
+				{Rotation: 45, Opacity: 0.3, Underlay: true},
+				{Pages: []int{1}},
+			},
+			want: [][]string{{"(CONFIDENTIAL) Tj"}, {"(CONFIDENTIAL) Tj", "(2) Tj"}, {"(CONFIDENTIAL) Tj"}},
+		},
+		{
+			name:   "japanese text",
+			stamps: []TextStamp{{Text: "社外秘", TTFFont: jpFont, FontSize: 30}},
+			opts:   []StampOptions{{Pages: []int{0}, Position: StampTopRight}},
+			want:   [][]string{{"> Tj"}, nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+
+			stamper, err := NewStamper(source)
+			if err != nil {
+				t.Fatalf("NewStamper failed: %v", err)
+			}
+			for i, stamp := range tt.stamps {
+				if err := stamper.AddText(stamp, tt.opts[i]); err != nil {
+					t.Fatalf("AddText failed: %v", err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := stamper.Save(&buf); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+
+			originals := []string{"One", "Two", "Three"}
+			for i, want := range tt.want {
+				contents := stampContents(t, reader, i)
+				if len(contents) != len(want) {
+					t.Fatalf("page %d stamps = %q, want %d", i, contents, len(want))
+				}
+				for j, text := range want {
+					if !strings.Contains(contents[j], text) {
+						t.Errorf("page %d stamp %d = %q, want to contain %q", i, j, contents[j], text)
+					}
+				}
+
+				// 元のコンテンツと注釈は残る
+				text, err := reader.ExtractPageText(i)
+				if err != nil {
+					t.Fatalf("ExtractPageText(%d) failed: %v", i, err)
+				}
+				if !strings.Contains(text, originals[i]) {
+					t.Errorf("page %d text = %q, want to contain %q", i, text, originals[i])
+				}
+			}
+			annotations, err := reader.ExtractPageAnnotations(1)
+			if err != nil {
+				t.Fatalf("ExtractPageAnnotations failed: %v", err)
+			}
+			if len(annotations) != 2 {
+				t.Errorf("annotations = %d, want 2", len(annotations))
+			}
+		})
+	}
+}
+
+func TestStamper_Render(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	data, err := compressWithZlib([]byte{0, 255, 0})
+	if err != nil {
+		t.Fatalf("compressWithZlib failed: %v", err)
+	}
+	greenImage := &Image{Width: 1, Height: 1, Data: data, ColorSpace: "DeviceRGB", BitsPerComponent: 8, Filter: "FlateDecode"}
+
+	// 元のページは200×100ポイントで、左下に赤、その右に青の50×50ポイントの矩形がある
+	tests := []struct {
+		name       string
+		pageExtra  string
+		stamp      ImageStamp
+		opts       StampOptions
+		wantPixels map[image.Point]color.RGBA
+	}{
+		{
+			name:  "top left with margin",
+			stamp: ImageStamp{Image: greenImage, Width: 20, Height: 20},
+			opts:  StampOptions{Position: StampTopLeft, Margin: 10},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 20, Y: 20}: green,
+				{X: 5, Y: 5}:   white,
+				{X: 35, Y: 20}: white,
+			},
+		},
+		{
+			name:  "over the contents",
+			stamp: ImageStamp{Image: greenImage, Width: 20, Height: 20},
+			opts:  StampOptions{Position: StampBottomLeft},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 10, Y: 90}: green,
+				{X: 30, Y: 90}: red,
+			},
+		},
+		{
+			name:  "underlay",
+			stamp: ImageStamp{Image: greenImage, Width: 20, Height: 20},
+			opts:  StampOptions{Position: StampBottomLeft, Underlay: true},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 10, Y: 90}: red,
+			},
+		},
+		{
+			name:  "opacity",
+			stamp: ImageStamp{Image: greenImage, Width: 20, Height: 20},
+			opts:  StampOptions{Opacity: 0.5},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 100, Y: 50}: {128, 255, 128, 255},
+			},
+		},
+		{
+			name:  "rotated stamp",
+			stamp: ImageStamp{Image: greenImage, Width: 40, Height: 10},
+			opts:  StampOptions{Rotation: 90},
+			wantPixels: map[image.Point]color.RGBA{
+				{X: 100, Y: 35}: green,
+				{X: 115, Y: 50}: white,
+			},
+		},
+		{
+			name:      "rotated page",
+			pageExtra: "/Rotate 90",
+			stamp:     ImageStamp{Image: greenImage, Width: 20},
+			opts:      StampOptions{Position: StampTopLeft},
+			wantPixels: map[image.Point]color.RGBA{
+				// 表示される向きの左上（元のページの左下にある赤の矩形の上）に押す
+				{X: 10, Y: 10}: green,
+				{X: 40, Y: 40}: red,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stamper, err := NewStamper(importSourcePDF(t, tt.pageExtra))
+			if err != nil {
+				t.Fatalf("NewStamper failed: %v", err)
+			}
+			if err := stamper.AddImage(tt.stamp, tt.opts); err != nil {
+				t.Fatalf("AddImage failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := stamper.Save(&buf); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+			img, err := reader.RenderPage(0, RenderOptions{})
+			if err != nil {
+				t.Fatalf("RenderPage failed: %v", err)
+			}
+			for p, want := range tt.wantPixels {
+				got := color.RGBAModel.Convert(img.At(p.X, p.Y)).(color.RGBA)
+				if !closeColor(got, want) {
+					t.Errorf("pixel %v = %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+// closeColor は2つの色の各成分の差が2以内かを返す
+func closeColor(a, b color.RGBA) bool {
+	diff := func(x, y uint8) bool { return int(x)-int(y) <= 2 && int(y)-int(x) <= 2 }
+	return diff(a.R, b.R) && diff(a.G, b.G) && diff(a.B, b.B) && diff(a.A, b.A)
+}
+
+// overlaySourcePDF は左下に50×50ポイントの緑の矩形がある1ページ目と、青の矩形がある2ページ目の300×200ポイントのPDFを作成する
+func overlaySourcePDF(t *testing.T) *PDFReader {
+	t.Helper()
+	stream := func(data string) string {
+		return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+	}
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 300 200] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		stream("0 1 0 rg 0 0 50 50 re f"),
+		stream("0 0 1 rg 0 0 50 50 re f"),
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+func TestStamper_AddPage(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// 元のPDFは300×200ポイントの3ページ（3ページ目は90度回転）
+	tests := []struct {
+		name       string
+		stamp      PageStamp
+		opts       StampOptions
+		wantPixels []map[image.Point]color.RGBA // ページごと（nilは重ねないページ）
+		wantForms  int                          // 重ねるページのフォームの数
+	}{
+		{
+			name:  "match pages",
+			stamp: PageStamp{MatchPages: true},
+			wantPixels: []map[image.Point]color.RGBA{
+				{{X: 25, Y: 175}: green, {X: 75, Y: 175}: white},
+				{{X: 25, Y: 175}: blue},
+				nil,
+			},
+			wantForms: 2,
+		},
+		{
+			name:  "same page on every page",
+			stamp: PageStamp{PageNum: 1},
+			opts:  StampOptions{Pages: []int{0, 1}, Underlay: true},
+			wantPixels: []map[image.Point]color.RGBA{
+				{{X: 25, Y: 175}: blue},
+				{{X: 25, Y: 175}: blue},
+				nil,
+			},
+			wantForms: 1,
+		},
+		{
+			name:  "scaled and moved",
+			stamp: PageStamp{PageNum: 1, Scale: 0.5},
+			opts:  StampOptions{Pages: []int{0}, Position: StampBottomLeft, OffsetX: 10},
+			wantPixels: []map[image.Point]color.RGBA{
+				// 25×25ポイントに縮小した矩形が、左端から10ポイントの位置に来る
+				{{X: 20, Y: 190}: blue, {X: 5, Y: 190}: white, {X: 40, Y: 190}: white},
+				nil,
+				nil,
+			},
+			wantForms: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(editorSourcePDF()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+
+			stamper, err := NewStamper(source)
+			if err != nil {
+				t.Fatalf("NewStamper failed: %v", err)
+			}
+			stamp := tt.stamp
+			stamp.Reader = overlaySourcePDF(t)
+			if err := stamper.AddPage(stamp, tt.opts); err != nil {
+				t.Fatalf("AddPage failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := stamper.Save(&buf); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			// 重ねるページは、複数のページで使っても一度だけ出力する
+			forms := strings.Count(buf.String(), "0 1 0 rg 0 0 50 50 re f") + strings.Count(buf.String(), "0 0 1 rg 0 0 50 50 re f")
+			if forms != tt.wantForms {
+				t.Errorf("imported pages = %d, want %d", forms, tt.wantForms)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+			for i, pixels := range tt.wantPixels {
+				if pixels == nil {
+					if contents := stampContents(t, reader, i); len(contents) != 0 {
+						t.Errorf("page %d stamps = %q, want none", i, contents)
+					}
+					continue
+				}
+				img, err := reader.RenderPage(i, RenderOptions{})
+				if err != nil {
+					t.Fatalf("RenderPage(%d) failed: %v", i, err)
+				}
+				for p, want := range pixels {
+					if got := color.RGBAModel.Convert(img.At(p.X, p.Y)); got != want {
+						t.Errorf("page %d pixel %v = %v, want %v", i, p, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStamper_SaveIncremental(t *testing.T) {
+	root, leaf, key := newTestCertificateChain(t)
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.DrawText("Signed content", 100, 700); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Sign(SignatureOptions{Signer: key, Certificate: leaf}); err != nil {
+		t.Fatal(err)
+	}
+	var signed bytes.Buffer
+	if err := doc.WriteTo(&signed); err != nil {
+		t.Fatal(err)
+	}
+	trusted := x509.NewCertPool()
+	trusted.AddCert(root)
+
+	tests := []struct {
+		name        string
+		incremental bool
+		wantIntact  bool
+	}{
+		{name: "incremental update keeps the signature intact", incremental: true, wantIntact: true},
+		{name: "full rewrite breaks the signature", incremental: false, wantIntact: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := OpenReader(bytes.NewReader(signed.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open PDF: %v", err)
+			}
+			defer source.Close()
+			stamper, err := NewStamper(source)
+			if err != nil {
+				t.Fatalf("NewStamper failed: %v", err)
+			}
+			if err := stamper.AddText(TextStamp{Text: "RECEIVED"}, StampOptions{Position: StampTopRight}); err != nil {
+				t.Fatalf("AddText failed: %v", err)
+			}
+			var buf bytes.Buffer
+			save := stamper.Save
+			if tt.incremental {
+				save = stamper.SaveIncremental
+			}
+			if err := save(&buf); err != nil {
+				t.Fatalf("save failed: %v", err)
+			}
+
+			reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to open saved PDF: %v", err)
+			}
+			defer reader.Close()
+			if contents := stampContents(t, reader, 0); len(contents) != 1 || !strings.Contains(contents[0], "(RECEIVED)") {
+				t.Errorf("stamps = %q, want RECEIVED", contents)
+			}
+
+			results, err := reader.VerifySignatures(trusted)
+			if err != nil {
+				t.Fatalf("VerifySignatures failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d signatures, want 1", len(results))
+			}
+			if got := results[0]; got.Intact != tt.wantIntact || (tt.wantIntact && !got.ModifiedAfterSigning) {
+				t.Errorf("Intact/Modified = %v/%v, want %v/true (err: %v)", got.Intact, got.ModifiedAfterSigning, tt.wantIntact, got.Err)
+			}
+		})
+	}
+}
+
+func TestStamper_Errors(t *testing.T) {
+	stamper, err := NewStamper(importSourcePDF(t, ""))
+	if err != nil {
+		t.Fatalf("NewStamper failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		add  func() error
+	}{
+		{name: "empty text", add: func() error { return stamper.AddText(TextStamp{}, StampOptions{}) }},
+		{name: "page out of range", add: func() error { return stamper.AddText(TextStamp{Text: "x"}, StampOptions{Pages: []int{1}}) }},
+		{name: "opacity above 1", add: func() error { return stamper.AddText(TextStamp{Text: "x"}, StampOptions{Opacity: 2}) }},
+		{name: "nil image", add: func() error { return stamper.AddImage(ImageStamp{}, StampOptions{}) }},
+		{name: "nil reader", add: func() error { return stamper.AddPage(PageStamp{}, StampOptions{}) }},
+		{name: "page of other PDF out of range", add: func() error {
+			return stamper.AddPage(PageStamp{Reader: overlaySourcePDF(t), PageNum: 2}, StampOptions{})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.add(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
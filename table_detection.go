@@ -0,0 +1,484 @@
+package gopdf
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// 表の検出の閾値
+const (
+	// rulingTolerance は罫線の位置を同じとみなす誤差、交わっているとみなす距離（ポイント）
+	rulingTolerance = 2.0
+	// thinRectThickness は塗りつぶした矩形を罫線とみなす太さの上限（ポイント）
+	thinRectThickness = 2.0
+	// minTextTableRows は罫線のない表とみなす行数の下限
+	minTextTableRows = 3
+	// minTextTableColumns は罫線のない表とみなす列数の下限
+	// 2列の並びは段組みの本文やラベルと値の並びと区別できないため3列から
+	minTextTableColumns = 3
+	// textTableRowSpacingRatio は罫線のない表の行とみなすベースラインの間隔の上限（フォントサイズに対する比）
+	textTableRowSpacingRatio = 2.5
+	// proseCellLength は本文の段とみなすセルの文字数（列のセルの文字数の中央値がこれ以上なら本文）
+	proseCellLength = 20
+)
+
+// ruling は水平または垂直の罫線
+type ruling struct {
+	horizontal bool
+	pos        float64 // 水平線はY座標、垂直線はX座標
+	start, end float64 // 水平線はX座標、垂直線はY座標の範囲（start <= end）
+}
+
+// columnRange は罫線のない表の列の横方向の範囲
+type columnRange struct {
+	left, right float64
+}
+
+// detectTables はページのテキスト要素とパスから表を検出する
+// 罫線の格子から表を作り、残った要素から列の揃ったテキストの並びを表として検出する
+// 設計書: docs/table_detection_design.md
+func detectTables(elements []layout.TextElement, paths []layout.PathBlock) []layout.TableBlock {
+	// 表のセルは左から右へ書かれたテキストのみを対象にする
+	elements, _ = partitionRotatedElements(elements)
+	tables := detectRuledTables(elements, paths)
+
+	// 罫線の表に含まれる要素は、罫線のない表の検出から除く
+	var rest []layout.TextElement
+	for _, elem := range elements {
+		inTable := slices.ContainsFunc(tables, func(table layout.TableBlock) bool {
+			return containsPoint(table.Rect, elem.X+elem.Width/2, elem.Y)
+		})
+		if !inTable {
+			rest = append(rest, elem)
+		}
+	}
+
+	return append(tables, detectTextTables(rest)...)
+}
+
+// detectRuledTables は交わる罫線のまとまりごとに、水平線と垂直線の格子を表とする
+func detectRuledTables(elements []layout.TextElement, paths []layout.PathBlock) []layout.TableBlock {
+	rulings := extractRulings(paths)
+	if len(rulings) == 0 {
+		return nil
+	}
+
+	// 交わる水平線と垂直線を同じまとまりにする（Union-Find）
+	parent := make([]int, len(rulings))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i, a := range rulings {
+		for j := i + 1; j < len(rulings); j++ {
+			if rulingsIntersect(a, rulings[j]) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]ruling)
+	var roots []int
+	for i, r := range rulings {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], r)
+	}
+
+	var tables []layout.TableBlock
+	for _, root := range roots {
+		if table, ok := ruledTable(groups[root], elements); ok {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// ruledTable は1つのまとまりの罫線の格子から表を作る
+// セルが2つ以上あり、テキストを含む場合のみ表とする（文章を囲む枠は表にしない）
+func ruledTable(rulings []ruling, elements []layout.TextElement) (layout.TableBlock, bool) {
+	var ys, xs []float64
+	for _, r := range rulings {
+		if r.horizontal {
+			ys = append(ys, r.pos)
+		} else {
+			xs = append(xs, r.pos)
+		}
+	}
+	ys = snapPositions(ys)
+	xs = snapPositions(xs)
+	if len(ys) < 2 || len(xs) < 2 || (len(ys)-1)*(len(xs)-1) < 2 {
+		return layout.TableBlock{}, false
+	}
+	// 行は上から順
+	slices.Reverse(ys)
+
+	cellElements := make([][][]layout.TextElement, len(ys)-1)
+	for i := range cellElements {
+		cellElements[i] = make([][]layout.TextElement, len(xs)-1)
+	}
+
+	found := false
+	for _, elem := range elements {
+		x := elem.X + elem.Width/2
+		row := sort.Search(len(ys)-1, func(i int) bool { return elem.Y >= ys[i+1] })
+		col := sort.Search(len(xs)-1, func(j int) bool { return x <= xs[j+1] })
+		if row == len(ys)-1 || col == len(xs)-1 || elem.Y > ys[0] || x < xs[0] {
+			continue
+		}
+		cellElements[row][col] = append(cellElements[row][col], elem)
+		found = true
+	}
+	if !found {
+		return layout.TableBlock{}, false
+	}
+
+	table := layout.TableBlock{
+		Rect: layout.Rectangle{
+			X:      xs[0],
+			Y:      ys[len(ys)-1],
+			Width:  xs[len(xs)-1] - xs[0],
+			Height: ys[0] - ys[len(ys)-1],
+		},
+		Ruled: true,
+	}
+	for i, cells := range cellElements {
+		row := layout.TableRow{
+			Rect: layout.Rectangle{X: xs[0], Y: ys[i+1], Width: table.Rect.Width, Height: ys[i] - ys[i+1]},
+		}
+		for j, elems := range cells {
+			rect := layout.Rectangle{X: xs[j], Y: ys[i+1], Width: xs[j+1] - xs[j], Height: ys[i] - ys[i+1]}
+			row.Cells = append(row.Cells, newTableCell(rect, elems))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, true
+}
+
+// extractRulings はパスから水平・垂直の罫線を取り出す
+// 線を描くパスは軸に平行な辺をすべて、塗りつぶすだけのパスは細い矩形を罫線とする
+func extractRulings(paths []layout.PathBlock) []ruling {
+	var rulings []ruling
+	for _, path := range paths {
+		if path.Kind == layout.PathKindCurve {
+			continue
+		}
+
+		for _, points := range subpathPoints(path.Segments) {
+			if path.Stroke {
+				for i := 1; i < len(points); i++ {
+					if r, ok := newRuling(points[i-1], points[i]); ok {
+						rulings = append(rulings, r)
+					}
+				}
+				continue
+			}
+			if path.Fill {
+				if r, ok := thinRectRuling(points); ok {
+					rulings = append(rulings, r)
+				}
+			}
+		}
+	}
+
+	return mergeRulings(rulings)
+}
+
+// subpathPoints はパスをサブパスごとの頂点の列に分ける（閉じたサブパスは始点を末尾に加える）
+func subpathPoints(segments []layout.PathSegment) [][]layout.Point {
+	var result [][]layout.Point
+	var current []layout.Point
+
+	for _, seg := range segments {
+		switch seg.Op {
+		case layout.PathOpMoveTo:
+			if len(current) > 1 {
+				result = append(result, current)
+			}
+			current = nil
+			if len(seg.Points) > 0 {
+				current = []layout.Point{seg.Points[0]}
+			}
+		case layout.PathOpLineTo, layout.PathOpCurveTo:
+			if len(seg.Points) > 0 {
+				current = append(current, seg.Points[len(seg.Points)-1])
+			}
+		case layout.PathOpClosePath:
+			if len(current) > 1 {
+				current = append(current, current[0])
+			}
+		}
+	}
+
+	if len(current) > 1 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// newRuling は2点を結ぶ線分が水平または垂直なら罫線として返す
+func newRuling(a, b layout.Point) (ruling, bool) {
+	switch {
+	case math.Abs(a.Y-b.Y) <= rulingTolerance/2 && math.Abs(a.X-b.X) > rulingTolerance:
+		return ruling{horizontal: true, pos: (a.Y + b.Y) / 2, start: math.Min(a.X, b.X), end: math.Max(a.X, b.X)}, true
+	case math.Abs(a.X-b.X) <= rulingTolerance/2 && math.Abs(a.Y-b.Y) > rulingTolerance:
+		return ruling{horizontal: false, pos: (a.X + b.X) / 2, start: math.Min(a.Y, b.Y), end: math.Max(a.Y, b.Y)}, true
+	}
+	return ruling{}, false
+}
+
+// thinRectRuling は細い塗りつぶしの矩形（罫線を矩形で描くPDFが多い）をその中心線の罫線として返す
+func thinRectRuling(points []layout.Point) (ruling, bool) {
+	minX, minY := points[0].X, points[0].Y
+	maxX, maxY := minX, minY
+	for _, p := range points[1:] {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	width, height := maxX-minX, maxY-minY
+	switch {
+	case height <= thinRectThickness && width > rulingTolerance:
+		return ruling{horizontal: true, pos: (minY + maxY) / 2, start: minX, end: maxX}, true
+	case width <= thinRectThickness && height > rulingTolerance:
+		return ruling{horizontal: false, pos: (minX + maxX) / 2, start: minY, end: maxY}, true
+	}
+	return ruling{}, false
+}
+
+// mergeRulings は同じ位置で重なる・つながる罫線を1本にまとめる
+// セルごとに分けて描かれた罫線を、表の幅・高さの1本の線として扱うため
+func mergeRulings(rulings []ruling) []ruling {
+	sort.Slice(rulings, func(i, j int) bool {
+		a, b := rulings[i], rulings[j]
+		if a.horizontal != b.horizontal {
+			return a.horizontal
+		}
+		if a.pos != b.pos {
+			return a.pos < b.pos
+		}
+		return a.start < b.start
+	})
+
+	var merged []ruling
+	for _, r := range rulings {
+		joined := false
+		for i := len(merged) - 1; i >= 0; i-- {
+			m := &merged[i]
+			if m.horizontal != r.horizontal || r.pos-m.pos > rulingTolerance {
+				break
+			}
+			if r.start <= m.end+rulingTolerance && m.start <= r.end+rulingTolerance {
+				m.start = math.Min(m.start, r.start)
+				m.end = math.Max(m.end, r.end)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// rulingsIntersect は水平線と垂直線が交わる（端が接する場合を含む）か判定
+func rulingsIntersect(a, b ruling) bool {
+	if a.horizontal == b.horizontal {
+		return false
+	}
+	if !a.horizontal {
+		a, b = b, a
+	}
+	return b.pos >= a.start-rulingTolerance && b.pos <= a.end+rulingTolerance &&
+		a.pos >= b.start-rulingTolerance && a.pos <= b.end+rulingTolerance
+}
+
+// snapPositions は罫線の位置を昇順に並べ、誤差の範囲で同じ位置のものを1つにまとめる
+func snapPositions(positions []float64) []float64 {
+	sort.Float64s(positions)
+
+	var result []float64
+	for _, p := range positions {
+		if len(result) > 0 && p-result[len(result)-1] <= rulingTolerance {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// detectTextTables は罫線のない表を、列の揃ったテキストの並びから検出する
+// 段組みの間隔（columnGapRatio）で2つ以上に分かれる行が続く範囲を1つの候補とし、
+// 各行の断片の横方向の範囲を重ね合わせて列を決める
+func detectTextTables(elements []layout.TextElement) []layout.TableBlock {
+	rows := clusterByBaseline(elements)
+	fragments := make([][][]layout.TextElement, len(rows))
+	for i, row := range rows {
+		fragments[i] = splitLineAtColumnGaps(row)
+	}
+
+	var tables []layout.TableBlock
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && len(fragments[end]) >= 2 &&
+			(end == start || isTextTableRowSpacing(rows[end-1], rows[end])) {
+			end++
+		}
+		if end == start {
+			start++
+			continue
+		}
+
+		if table, ok := textTable(fragments[start:end]); ok {
+			tables = append(tables, table)
+		}
+		start = end
+	}
+	return tables
+}
+
+// isTextTableRowSpacing は2つの行の間隔が罫線のない表の行どうしとして自然か判定
+func isTextTableRowSpacing(prev, curr []layout.TextElement) bool {
+	size := math.Max(avgFontSize(prev), avgFontSize(curr))
+	return math.Abs(avgBaseline(prev)-avgBaseline(curr)) <= size*textTableRowSpacingRatio
+}
+
+// textTable は行ごとの断片から罫線のない表を作る
+func textTable(rows [][][]layout.TextElement) (layout.TableBlock, bool) {
+	if len(rows) < minTextTableRows {
+		return layout.TableBlock{}, false
+	}
+
+	columns := textTableColumns(rows)
+	if len(columns) < minTextTableColumns {
+		return layout.TableBlock{}, false
+	}
+
+	cellElements := make([][][]layout.TextElement, len(rows))
+	for i, fragments := range rows {
+		cellElements[i] = make([][]layout.TextElement, len(columns))
+		for _, fragment := range fragments {
+			center := (minX(fragment) + maxX(fragment)) / 2
+			col := sort.Search(len(columns), func(j int) bool { return center <= columns[j].right })
+			cellElements[i][col] = append(cellElements[i][col], fragment...)
+		}
+	}
+	if isProseColumns(cellElements) {
+		return layout.TableBlock{}, false
+	}
+
+	table := layout.TableBlock{}
+	for i, cells := range cellElements {
+		var rowElements []layout.TextElement
+		for _, fragment := range rows[i] {
+			rowElements = append(rowElements, fragment...)
+		}
+		bounds := textElementsBounds(rowElements)
+
+		row := layout.TableRow{
+			Rect: layout.Rectangle{
+				X:      columns[0].left,
+				Y:      bounds.Y,
+				Width:  columns[len(columns)-1].right - columns[0].left,
+				Height: bounds.Height,
+			},
+		}
+		for j, elems := range cells {
+			rect := layout.Rectangle{X: columns[j].left, Y: bounds.Y, Width: columns[j].right - columns[j].left, Height: bounds.Height}
+			row.Cells = append(row.Cells, newTableCell(rect, elems))
+		}
+
+		if i == 0 {
+			table.Rect = row.Rect
+		} else {
+			table.Rect = table.Rect.Union(row.Rect)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, true
+}
+
+// textTableColumns はすべての行の断片の横方向の範囲を重ね合わせ、重なるものをまとめて列とする
+func textTableColumns(rows [][][]layout.TextElement) []columnRange {
+	var ranges []columnRange
+	for _, fragments := range rows {
+		for _, fragment := range fragments {
+			ranges = append(ranges, columnRange{left: minX(fragment), right: maxX(fragment)})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].left < ranges[j].left })
+
+	var columns []columnRange
+	for _, r := range ranges {
+		if n := len(columns); n > 0 && r.left <= columns[n-1].right {
+			columns[n-1].right = math.Max(columns[n-1].right, r.right)
+			continue
+		}
+		columns = append(columns, r)
+	}
+	return columns
+}
+
+// isProseColumns はすべての列が長い文で埋まっているか（表ではなく段組みの本文か）判定
+func isProseColumns(cells [][][]layout.TextElement) bool {
+	for col := range cells[0] {
+		var lengths []int
+		for _, row := range cells {
+			if n := textLength(row[col]); n > 0 {
+				lengths = append(lengths, n)
+			}
+		}
+		if len(lengths) == 0 {
+			return false
+		}
+		sort.Ints(lengths)
+		if lengths[(len(lengths)-1)/2] < proseCellLength {
+			return false
+		}
+	}
+	return true
+}
+
+// textLength は要素のテキストの文字数を返す
+func textLength(elements []layout.TextElement) int {
+	n := 0
+	for _, elem := range elements {
+		n += utf8.RuneCountInString(elem.Text)
+	}
+	return n
+}
+
+// newTableCell はセルの範囲と要素からTableCellを作成
+// セル内で折り返されたテキストは段落と同じように連結する
+func newTableCell(rect layout.Rectangle, elements []layout.TextElement) layout.TableCell {
+	cell := layout.TableCell{Rect: rect}
+
+	var lines []layout.TextLine
+	for _, line := range groupElementsByLine(elements) {
+		lines = append(lines, newTextLine(line))
+		cell.Elements = append(cell.Elements, line...)
+	}
+	cell.Text = joinParagraphLines(lines)
+
+	return cell
+}
+
+// containsPoint は点が矩形に含まれる（境界を含む）か判定
+func containsPoint(rect layout.Rectangle, x, y float64) bool {
+	return x >= rect.X && x <= rect.X+rect.Width && y >= rect.Y && y <= rect.Y+rect.Height
+}
@@ -0,0 +1,210 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// strokedLine は(x1, y1)から(x2, y2)への線を描くパスを返す
+func strokedLine(x1, y1, x2, y2 float64) layout.PathBlock {
+	return layout.PathBlock{
+		Kind: layout.PathKindLine,
+		Segments: []layout.PathSegment{
+			{Op: layout.PathOpMoveTo, Points: []layout.Point{{X: x1, Y: y1}}},
+			{Op: layout.PathOpLineTo, Points: []layout.Point{{X: x2, Y: y2}}},
+		},
+		Stroke: true,
+	}
+}
+
+// filledRects は矩形（x, y, width, height）を並べて塗りつぶすパスを返す
+func filledRects(rects ...[4]float64) layout.PathBlock {
+	path := layout.PathBlock{Kind: layout.PathKindRect, Fill: true}
+	for _, r := range rects {
+		x, y, w, h := r[0], r[1], r[2], r[3]
+		path.Segments = append(path.Segments,
+			layout.PathSegment{Op: layout.PathOpMoveTo, Points: []layout.Point{{X: x, Y: y}}},
+			layout.PathSegment{Op: layout.PathOpLineTo, Points: []layout.Point{{X: x + w, Y: y}}},
+			layout.PathSegment{Op: layout.PathOpLineTo, Points: []layout.Point{{X: x + w, Y: y + h}}},
+			layout.PathSegment{Op: layout.PathOpLineTo, Points: []layout.Point{{X: x, Y: y + h}}},
+			layout.PathSegment{Op: layout.PathOpClosePath},
+		)
+	}
+	return path
+}
+
+// textAt は(x, y)に置いた12ポイントのテキスト要素を返す（幅は1文字6ポイント）
+func textAt(text string, x, y float64) layout.TextElement {
+	return layout.TextElement{Text: text, X: x, Y: y, Width: float64(len(text)) * 6, Height: 12, Size: 12}
+}
+
+func TestDetectTables(t *testing.T) {
+	// 2行2列の格子（y=700, 680, 660、x=50, 150, 350）
+	grid := []layout.PathBlock{
+		strokedLine(50, 700, 350, 700),
+		// 中央の水平線はセルごとに分けて描かれている
+		strokedLine(50, 680, 150, 680),
+		strokedLine(150, 680, 350, 680),
+		strokedLine(50, 660, 350, 660),
+		strokedLine(50, 660, 50, 700),
+		strokedLine(150, 660, 150, 700),
+		strokedLine(350, 660, 350, 700),
+	}
+	thinRects := []layout.PathBlock{filledRects(
+		[4]float64{50, 699.5, 300, 1}, [4]float64{50, 679.5, 300, 1}, [4]float64{50, 659.5, 300, 1},
+		[4]float64{49.5, 660, 1, 40}, [4]float64{149.5, 660, 1, 40}, [4]float64{349.5, 660, 1, 40},
+	)}
+	gridText := []layout.TextElement{
+		textAt("Item", 60, 686), textAt("Price", 160, 686),
+		textAt("Apple", 60, 666), textAt("100", 160, 666),
+	}
+
+	invoice := []layout.TextElement{
+		textAt("Description", 50, 500), textAt("Qty", 250, 500), textAt("Amount", 350, 500),
+		textAt("Consulting", 50, 484), textAt("2", 256, 484), textAt("2,000", 356, 484),
+		textAt("Support", 50, 468), textAt("10", 256, 468), textAt("500", 362, 468),
+		// 金額の列にだけ値がある行
+		textAt("1", 62, 452), textAt("2,500", 356, 452),
+		// 表の後の本文
+		textAt("Thank you for your business.", 50, 400),
+	}
+
+	long := "this is a fairly long line of body text"
+	threeColumns := []layout.TextElement{
+		textAt(long, 50, 700), textAt(long, 330, 700), textAt(long, 610, 700),
+		textAt(long, 50, 686), textAt(long, 330, 686), textAt(long, 610, 686),
+		textAt(long, 50, 672), textAt(long, 330, 672), textAt(long, 610, 672),
+	}
+
+	tests := []struct {
+		name      string
+		elements  []layout.TextElement
+		paths     []layout.PathBlock
+		want      [][][]string // 表ごとのRecords()
+		wantRuled []bool
+	}{
+		{
+			name:      "stroked grid",
+			elements:  gridText,
+			paths:     grid,
+			want:      [][][]string{{{"Item", "Price"}, {"Apple", "100"}}},
+			wantRuled: []bool{true},
+		},
+		{
+			name:      "thin filled rects",
+			elements:  gridText,
+			paths:     thinRects,
+			want:      [][][]string{{{"Item", "Price"}, {"Apple", "100"}}},
+			wantRuled: []bool{true},
+		},
+		{
+			name:     "box around text is not a table",
+			elements: []layout.TextElement{textAt("Note", 60, 686)},
+			paths:    []layout.PathBlock{filledRects([4]float64{50, 680, 100, 1}), strokedLine(50, 700, 150, 700), strokedLine(50, 680, 50, 700), strokedLine(150, 680, 150, 700)},
+		},
+		{
+			name:     "grid without text is not a table",
+			paths:    grid,
+			elements: []layout.TextElement{textAt("Outside", 400, 686)},
+		},
+		{
+			name:     "aligned columns",
+			elements: invoice,
+			want: [][][]string{{
+				{"Description", "Qty", "Amount"},
+				{"Consulting", "2", "2,000"},
+				{"Support", "10", "500"},
+				{"1", "", "2,500"},
+			}},
+			wantRuled: []bool{false},
+		},
+		{
+			name: "two columns of labels and values",
+			elements: []layout.TextElement{
+				textAt("Name", 50, 700), textAt("Alice", 200, 700),
+				textAt("Email", 50, 686), textAt("alice@example.com", 200, 686),
+				textAt("Phone", 50, 672), textAt("000-0000", 200, 672),
+			},
+		},
+		{
+			name:     "multi-column body text",
+			elements: threeColumns,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := detectTables(tt.elements, tt.paths)
+			if len(tables) != len(tt.want) {
+				t.Fatalf("got %d tables %+v, want %d", len(tables), tables, len(tt.want))
+			}
+			for i, table := range tables {
+				if got := table.Records(); !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("tables[%d].Records() = %q, want %q", i, got, tt.want[i])
+				}
+				if table.Ruled != tt.wantRuled[i] {
+					t.Errorf("tables[%d].Ruled = %v, want %v", i, table.Ruled, tt.wantRuled[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectTables_CellBounds(t *testing.T) {
+	tables := detectTables(
+		[]layout.TextElement{textAt("A", 60, 686), textAt("B", 160, 666)},
+		[]layout.PathBlock{
+			strokedLine(50, 700, 350, 700), strokedLine(50, 680, 350, 680), strokedLine(50, 660, 350, 660),
+			strokedLine(50, 660, 50, 700), strokedLine(150, 660, 150, 700), strokedLine(350, 660, 350, 700),
+		},
+	)
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+
+	table := tables[0]
+	if want := (Rectangle{X: 50, Y: 660, Width: 300, Height: 40}); table.Rect != want {
+		t.Errorf("Rect = %+v, want %+v", table.Rect, want)
+	}
+	if want := (Rectangle{X: 150, Y: 660, Width: 200, Height: 20}); table.Rows[1].Cells[1].Rect != want {
+		t.Errorf("Rows[1].Cells[1].Rect = %+v, want %+v", table.Rows[1].Cells[1].Rect, want)
+	}
+}
+
+func TestExtractPageLayout_Tables(t *testing.T) {
+	contents := "0.5 w 50 660 300 40 re S 50 680 m 350 680 l S 150 660 m 150 700 l S " +
+		"BT /F1 10 Tf 60 686 Td (Item) Tj 100 0 Td (Price) Tj ET " +
+		"BT /F1 10 Tf 60 666 Td (Apple) Tj 100 0 Td (100) Tj ET"
+	pdf := buildRawPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(contents), contents),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	})
+	reader, err := OpenReader(bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer reader.Close()
+
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+
+	if len(pl.Tables) != 1 {
+		t.Fatalf("Tables = %+v, want 1 table", pl.Tables)
+	}
+	want := [][]string{{"Item", "Price"}, {"Apple", "100"}}
+	if got := pl.Tables[0].Records(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Records() = %q, want %q", got, want)
+	}
+	if !pl.Tables[0].Ruled {
+		t.Error("Ruled = false, want true")
+	}
+}
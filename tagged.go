@@ -0,0 +1,433 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ryomak/gopdf/internal/core"
+	"github.com/ryomak/gopdf/internal/writer"
+)
+
+// StructureType は構造要素の種類（PDF 1.7 14.8.4 の標準構造型）
+type StructureType string
+
+const (
+	// グループ化要素
+	StructDocument   StructureType = "Document"
+	StructPart       StructureType = "Part"
+	StructSect       StructureType = "Sect"
+	StructDiv        StructureType = "Div"
+	StructBlockQuote StructureType = "BlockQuote"
+	StructCaption    StructureType = "Caption"
+
+	// 見出しと段落
+	StructH1 StructureType = "H1"
+	StructH2 StructureType = "H2"
+	StructH3 StructureType = "H3"
+	StructH4 StructureType = "H4"
+	StructH5 StructureType = "H5"
+	StructH6 StructureType = "H6"
+	StructP  StructureType = "P"
+
+	// リスト
+	StructL     StructureType = "L"
+	StructLI    StructureType = "LI"
+	StructLbl   StructureType = "Lbl"
+	StructLBody StructureType = "LBody"
+
+	// 目次
+	StructTOC  StructureType = "TOC"
+	StructTOCI StructureType = "TOCI"
+
+	// 表
+	StructTable StructureType = "Table"
+	StructTHead StructureType = "THead"
+	StructTBody StructureType = "TBody"
+	StructTR    StructureType = "TR"
+	StructTH    StructureType = "TH"
+	StructTD    StructureType = "TD"
+
+	// インライン要素と図
+	StructSpan   StructureType = "Span"
+	StructCode   StructureType = "Code"
+	StructLink   StructureType = "Link"
+	StructFigure StructureType = "Figure"
+)
+
+// headingStructureType は見出しレベル（1〜6）に対応する構造型を返す
+func headingStructureType(level int) StructureType {
+	switch {
+	case level <= 1:
+		return StructH1
+	case level >= 6:
+		return StructH6
+	default:
+		return StructureType(fmt.Sprintf("H%d", level))
+	}
+}
+
+// structElement は構造ツリーの要素
+type structElement struct {
+	typ    StructureType
+	parent *structElement
+	kids   []structKid
+	alt    string      // 代替テキスト（/Alt）
+	bbox   *[4]float64 // 図などの領域（レイアウト属性の/BBox）
+}
+
+// structKid は構造要素の子（子要素、ページ上のマーク付きコンテンツ、または注釈）
+type structKid struct {
+	elem  *structElement
+	page  *Page
+	mcid  int
+	annot pageAnnotation // 注釈の参照（OBJR）。リンクなど
+}
+
+// structureTree はドキュメントの構造ツリーとタグ付けの状態
+type structureTree struct {
+	root  *structElement   // Document要素
+	stack []*structElement // 開いている要素（先頭はroot）
+	open  *markedContent   // 現在開いているマーク付きコンテンツ
+}
+
+// markedContent はコンテンツストリーム内の開いているマーク付きコンテンツ（BDC〜EMC）
+type markedContent struct {
+	page  *Page
+	elem  *structElement
+	mcid  int
+	begin int // BDC演算子の位置
+	start int // 内容の開始位置
+}
+
+// current は最も内側の開いている要素を返す
+func (t *structureTree) current() *structElement {
+	return t.stack[len(t.stack)-1]
+}
+
+// openContent はpage上にelemのマーク付きコンテンツを開始する
+func (t *structureTree) openContent(page *Page, elem *structElement) {
+	mc := &markedContent{page: page, elem: elem, mcid: page.nextMCID, begin: page.content.Len()}
+	page.nextMCID++
+	fmt.Fprintf(&page.content, "/%s <</MCID %d>> BDC\n", elem.typ, mc.mcid)
+	mc.start = page.content.Len()
+	t.open = mc
+}
+
+// closeContent は開いているマーク付きコンテンツを終了する
+// 何も描画されていなければ、BDCごと取り除いてMCIDを再利用する
+func (t *structureTree) closeContent() {
+	mc := t.open
+	if mc == nil {
+		return
+	}
+	t.open = nil
+
+	page := mc.page
+	if page.content.Len() == mc.start {
+		page.content.Truncate(mc.begin)
+		page.nextMCID--
+		return
+	}
+	fmt.Fprintf(&page.content, "EMC\n")
+	page.taggedRanges = append(page.taggedRanges, [2]int{mc.begin, page.content.Len()})
+	mc.elem.kids = append(mc.elem.kids, structKid{page: page, mcid: mc.mcid})
+}
+
+// BeginTag は構造要素を開始する
+// EndTagを呼ぶまでにこのページへ描画した内容が要素に属し、要素は開いている要素の子になる
+// 要素はページをまたいでもよい（AddPage後も開いたまま、新しいページの内容が続けて属する）
+// タグ付けしたドキュメントでは、どの要素にも属さない内容はアーティファクト（装飾）として出力される
+func (p *Page) BeginTag(typ StructureType) error {
+	if p.doc == nil {
+		return fmt.Errorf("page does not belong to a document")
+	}
+	if typ == "" || typ == StructDocument {
+		return fmt.Errorf("invalid structure type: %q", typ)
+	}
+
+	tree := p.doc.structureTree()
+	tree.closeContent()
+	parent := tree.current()
+	elem := &structElement{typ: typ, parent: parent}
+	parent.kids = append(parent.kids, structKid{elem: elem})
+	tree.stack = append(tree.stack, elem)
+	tree.openContent(p, elem)
+	return nil
+}
+
+// EndTag は最も内側の構造要素を終了する
+func (p *Page) EndTag() error {
+	if p.doc == nil || p.doc.structure == nil || len(p.doc.structure.stack) == 1 {
+		return fmt.Errorf("no open structure element")
+	}
+
+	tree := p.doc.structure
+	tree.closeContent()
+	tree.stack = tree.stack[:len(tree.stack)-1]
+	// 親要素の内容が続く場合に備えて、親のマーク付きコンテンツを開き直す
+	if parent := tree.current(); parent != tree.root {
+		tree.openContent(p, parent)
+	}
+	return nil
+}
+
+// resumeTag は終了した構造要素elemを開き直し、EndTagまでに描画した内容を続けて加える
+// elemは開いている要素の子であること。表のセルのように、兄弟の要素の内容が交互に続く場合に使う
+func (p *Page) resumeTag(elem *structElement) error {
+	if p.doc == nil || p.doc.structure == nil {
+		return fmt.Errorf("no structure element to resume")
+	}
+	tree := p.doc.structure
+	tree.closeContent()
+	tree.stack = append(tree.stack, elem)
+	tree.openContent(p, elem)
+	return nil
+}
+
+// artifact はfnで描く内容を、開いている構造要素に属さないアーティファクト（装飾）にする
+// 表の罫線や背景、ページごとに繰り返す表の見出しなど、読み上げの対象にしない内容に使う
+func (p *Page) artifact(fn func() error) error {
+	if p.doc == nil || p.doc.structure == nil {
+		return fn()
+	}
+	tree := p.doc.structure
+	tree.closeContent()
+	err := fn()
+	if elem := tree.current(); elem != tree.root {
+		tree.openContent(p, elem)
+	}
+	return err
+}
+
+// SetAltText は最も内側の開いている構造要素に代替テキスト（/Alt）を設定する
+// 図（StructFigure）や数式など、テキストとして読み取れない内容の説明に使う
+func (p *Page) SetAltText(alt string) error {
+	if p.doc == nil || p.doc.structure == nil || len(p.doc.structure.stack) == 1 {
+		return fmt.Errorf("no open structure element")
+	}
+	p.doc.structure.current().alt = alt
+	return nil
+}
+
+// figuresWithoutAlt は代替テキストのない図の数を返す
+func (t *structureTree) figuresWithoutAlt() int {
+	count := 0
+	var walk func(elem *structElement)
+	walk = func(elem *structElement) {
+		if elem.typ == StructFigure && elem.alt == "" {
+			count++
+		}
+		for _, kid := range elem.kids {
+			if kid.elem != nil {
+				walk(kid.elem)
+			}
+		}
+	}
+	walk(t.root)
+	return count
+}
+
+// structureTree はドキュメントの構造ツリーを返す（初回呼び出し時に作成する）
+func (d *Document) structureTree() *structureTree {
+	if d.structure == nil {
+		root := &structElement{typ: StructDocument}
+		d.structure = &structureTree{root: root, stack: []*structElement{root}}
+	}
+	return d.structure
+}
+
+// IsTagged は構造タグ（BeginTag）が使われているかを返す
+func (d *Document) IsTagged() bool {
+	return d.structure != nil
+}
+
+// SetLanguage は文書の言語（BCP 47の言語タグ、例: "ja-JP"）を設定する
+// カタログの/Langとして出力され、読み上げソフトなどが参照する
+func (d *Document) SetLanguage(lang string) {
+	d.language = lang
+}
+
+// Language は設定されている文書の言語を返す
+func (d *Document) Language() string {
+	return d.language
+}
+
+// startPage は新しいページで開いている構造要素の内容を続けられるようにする
+func (t *structureTree) startPage(page *Page) {
+	t.closeContent()
+	if elem := t.current(); elem != t.root {
+		t.openContent(page, elem)
+	}
+}
+
+// contentBytes は出力するコンテンツストリームを返す
+// タグ付けしたドキュメントでは、どの要素にも属さない内容を/Artifactで囲む
+func (p *Page) contentBytes() []byte {
+	data := p.content.Bytes()
+	if p.doc == nil || p.doc.structure == nil {
+		return data
+	}
+
+	var out bytes.Buffer
+	wrap := func(part []byte) {
+		if len(bytes.TrimSpace(part)) == 0 {
+			out.Write(part)
+			return
+		}
+		out.WriteString("/Artifact BMC\n")
+		out.Write(part)
+		if part[len(part)-1] != '\n' {
+			out.WriteByte('\n')
+		}
+		out.WriteString("EMC\n")
+	}
+
+	pos := 0
+	for _, r := range p.taggedRanges {
+		wrap(data[pos:r[0]])
+		out.Write(data[r[0]:r[1]])
+		pos = r[1]
+	}
+	wrap(data[pos:])
+	return out.Bytes()
+}
+
+// taggedPDF は構造ツリーの出力結果
+type taggedPDF struct {
+	structTreeRoot *core.Reference
+	structParents  map[*Page]int                      // ページ -> /StructParents
+	annotRefs      map[pageAnnotation]*core.Reference // 構造要素が参照する注釈 -> 予約したオブジェクト
+	annotParents   map[pageAnnotation]int             // 構造要素が参照する注釈 -> /StructParent
+}
+
+// writeStructureTree は構造要素、親ツリー、StructTreeRootを出力する
+func (d *Document) writeStructureTree(w *writer.Writer, pageRefs []*core.Reference) (*taggedPDF, error) {
+	tree := d.structure
+	if len(tree.stack) > 1 {
+		return nil, fmt.Errorf("structure element %s was not closed (call EndTag)", tree.current().typ)
+	}
+
+	pageIndex := make(map[*Page]int, len(d.pages))
+	for i, page := range d.pages {
+		pageIndex[page] = i
+	}
+
+	// 要素の番号を先に予約し、MCIDごとの親要素（親ツリー）を集める
+	// 注釈は構造ツリーから参照するため番号を予約し、親ツリーのキーはページのキーの後に続ける
+	rootNum := w.ReserveObject()
+	refs := map[*structElement]*core.Reference{}
+	parents := map[*Page][]core.Object{}
+	result := &taggedPDF{
+		structParents: map[*Page]int{},
+		annotRefs:     map[pageAnnotation]*core.Reference{},
+		annotParents:  map[pageAnnotation]int{},
+	}
+	var annotElems []*structElement
+	var reserve func(elem *structElement)
+	reserve = func(elem *structElement) {
+		refs[elem] = &core.Reference{ObjectNumber: w.ReserveObject()}
+		for _, kid := range elem.kids {
+			if kid.elem != nil {
+				reserve(kid.elem)
+				continue
+			}
+			if kid.annot != nil {
+				result.annotRefs[kid.annot] = &core.Reference{ObjectNumber: w.ReserveObject()}
+				result.annotParents[kid.annot] = len(d.pages) + len(annotElems)
+				annotElems = append(annotElems, elem)
+				continue
+			}
+			mcids := parents[kid.page]
+			for len(mcids) <= kid.mcid {
+				mcids = append(mcids, core.Null{})
+			}
+			mcids[kid.mcid] = refs[elem]
+			parents[kid.page] = mcids
+		}
+	}
+	reserve(tree.root)
+
+	var write func(elem *structElement, parentRef *core.Reference) error
+	write = func(elem *structElement, parentRef *core.Reference) error {
+		kids := make(core.Array, 0, len(elem.kids))
+		for _, kid := range elem.kids {
+			if kid.elem != nil {
+				if err := write(kid.elem, refs[elem]); err != nil {
+					return err
+				}
+				kids = append(kids, refs[kid.elem])
+				continue
+			}
+			index, ok := pageIndex[kid.page]
+			if !ok {
+				return fmt.Errorf("structure element %s refers to a page outside the document", elem.typ)
+			}
+			if kid.annot != nil {
+				kids = append(kids, core.Dictionary{
+					core.Name("Type"): core.Name("OBJR"),
+					core.Name("Pg"):   pageRefs[index],
+					core.Name("Obj"):  result.annotRefs[kid.annot],
+				})
+				continue
+			}
+			kids = append(kids, core.Dictionary{
+				core.Name("Type"): core.Name("MCR"),
+				core.Name("Pg"):   pageRefs[index],
+				core.Name("MCID"): core.Integer(kid.mcid),
+			})
+		}
+		dict := core.Dictionary{
+			core.Name("Type"): core.Name("StructElem"),
+			core.Name("S"):    core.Name(elem.typ),
+			core.Name("P"):    parentRef,
+			core.Name("K"):    kids,
+		}
+		if elem.alt != "" {
+			dict[core.Name("Alt")] = textString(elem.alt)
+		}
+		if elem.bbox != nil {
+			dict[core.Name("A")] = core.Dictionary{
+				core.Name("O"): core.Name("Layout"),
+				core.Name("BBox"): core.Array{
+					core.Real(elem.bbox[0]), core.Real(elem.bbox[1]),
+					core.Real(elem.bbox[2]), core.Real(elem.bbox[3]),
+				},
+			}
+		}
+		return w.WriteObject(refs[elem].ObjectNumber, dict)
+	}
+	rootRef := &core.Reference{ObjectNumber: rootNum}
+	if err := write(tree.root, rootRef); err != nil {
+		return nil, err
+	}
+
+	// 親ツリー（数値ツリー）のキーはページの/StructParentsと注釈の/StructParent
+	result.structTreeRoot = rootRef
+	nums := core.Array{}
+	for i, page := range d.pages {
+		mcids, ok := parents[page]
+		if !ok {
+			continue
+		}
+		result.structParents[page] = i
+		nums = append(nums, core.Integer(i), core.Array(mcids))
+	}
+	for i, elem := range annotElems {
+		nums = append(nums, core.Integer(len(d.pages)+i), refs[elem])
+	}
+	parentTreeNum, err := w.AddObject(core.Dictionary{core.Name("Nums"): nums})
+	if err != nil {
+		return nil, err
+	}
+
+	rootDict := core.Dictionary{
+		core.Name("Type"):              core.Name("StructTreeRoot"),
+		core.Name("K"):                 refs[tree.root],
+		core.Name("ParentTree"):        &core.Reference{ObjectNumber: parentTreeNum},
+		core.Name("ParentTreeNextKey"): core.Integer(len(d.pages) + len(annotElems)),
+	}
+	if err := w.WriteObject(rootNum, rootDict); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
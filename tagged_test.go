@@ -0,0 +1,500 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// structNode はテスト用に読み出した構造要素
+type structNode struct {
+	typ    string
+	kids   []structNode
+	mcids  []int // マーク付きコンテンツ参照のMCID
+	pages  []int // マーク付きコンテンツ参照のページ（0始まり）
+	annots int   // 注釈の参照（OBJR）の数
+}
+
+// readStructure はStructTreeRootから構造ツリーを読み出す
+func readStructure(t *testing.T, r *PDFReader) structNode {
+	t.Helper()
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, ok := r.r.Resolve(catalog[core.Name("StructTreeRoot")]).(core.Dictionary)
+	if !ok {
+		t.Fatal("catalog has no /StructTreeRoot")
+	}
+	pageRefs, err := r.r.GetPageReferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var read func(obj core.Object) structNode
+	read = func(obj core.Object) structNode {
+		elem := r.r.Resolve(obj).(core.Dictionary)
+		node := structNode{typ: string(elem[core.Name("S")].(core.Name))}
+		kids, _ := elem[core.Name("K")].(core.Array)
+		for _, kid := range kids {
+			if dict, ok := kid.(core.Dictionary); ok && dict[core.Name("Type")] == core.Name("MCR") {
+				node.mcids = append(node.mcids, int(dict[core.Name("MCID")].(core.Integer)))
+				pg := dict[core.Name("Pg")].(*core.Reference)
+				for i, ref := range pageRefs {
+					if ref.ObjectNumber == pg.ObjectNumber {
+						node.pages = append(node.pages, i)
+					}
+				}
+				continue
+			}
+			if dict, ok := kid.(core.Dictionary); ok && dict[core.Name("Type")] == core.Name("OBJR") {
+				node.annots++
+				continue
+			}
+			node.kids = append(node.kids, read(kid))
+		}
+		return node
+	}
+	return read(root[core.Name("K")])
+}
+
+func TestPageBeginTag(t *testing.T) {
+	doc := New()
+	doc.SetLanguage("en-US")
+	doc.SetMetadata(Metadata{Title: "Report"})
+
+	page1 := doc.AddPage(PageSizeA4, Portrait)
+	page1.SetFont(FontHelvetica, 12)
+	page1.DrawLine(0, 0, 100, 100) // タグの外側（アーティファクト）
+	mustTag(t, page1.BeginTag(StructH1))
+	mustTag(t, page1.DrawText("Title", 50, 800))
+	mustTag(t, page1.EndTag())
+
+	// 表はページをまたぐ。行の外側に描いた表の枠も表の内容になる
+	mustTag(t, page1.BeginTag(StructTable))
+	page1.DrawRectangle(40, 600, 300, 100)
+	mustTag(t, page1.BeginTag(StructTR))
+	mustTag(t, page1.BeginTag(StructTD))
+	mustTag(t, page1.DrawText("A1", 50, 650))
+	mustTag(t, page1.EndTag())
+	mustTag(t, page1.EndTag())
+	page2 := doc.AddPage(PageSizeA4, Portrait)
+	page2.SetFont(FontHelvetica, 12)
+	mustTag(t, page2.BeginTag(StructTR))
+	mustTag(t, page2.BeginTag(StructTD))
+	mustTag(t, page2.DrawText("A2", 50, 750))
+	mustTag(t, page2.EndTag())
+	mustTag(t, page2.EndTag())
+	mustTag(t, page2.EndTag())
+
+	// 何も描画しなかった要素はマーク付きコンテンツを持たない
+	mustTag(t, page2.BeginTag(StructP))
+	mustTag(t, page2.EndTag())
+
+	if !doc.IsTagged() {
+		t.Fatal("IsTagged() = false, want true")
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	catalog, err := r.r.GetCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang := rawTextString(catalog[core.Name("Lang")]); lang != "en-US" {
+		t.Errorf("/Lang = %q, want %q", lang, "en-US")
+	}
+	markInfo, _ := r.r.Resolve(catalog[core.Name("MarkInfo")]).(core.Dictionary)
+	if markInfo[core.Name("Marked")] != core.Boolean(true) {
+		t.Errorf("/MarkInfo = %v, want /Marked true", markInfo)
+	}
+	prefs, _ := r.r.Resolve(catalog[core.Name("ViewerPreferences")]).(core.Dictionary)
+	if prefs[core.Name("DisplayDocTitle")] != core.Boolean(true) {
+		t.Errorf("/ViewerPreferences = %v, want /DisplayDocTitle true", prefs)
+	}
+
+	root := readStructure(t, r)
+	if root.typ != "Document" || len(root.kids) != 3 {
+		t.Fatalf("root = %+v, want Document with 3 children", root)
+	}
+	h1, table, p := root.kids[0], root.kids[1], root.kids[2]
+	if h1.typ != "H1" || len(h1.mcids) != 1 || h1.pages[0] != 0 {
+		t.Errorf("H1 = %+v, want one marked content on page 1", h1)
+	}
+	if table.typ != "Table" || len(table.kids) != 2 || len(table.mcids) != 1 {
+		t.Fatalf("Table = %+v, want 2 rows and the frame", table)
+	}
+	for i, row := range table.kids {
+		if row.typ != "TR" || len(row.kids) != 1 || row.kids[0].typ != "TD" {
+			t.Errorf("row %d = %+v, want TR with one TD", i, row)
+			continue
+		}
+		if cell := row.kids[0]; len(cell.pages) != 1 || cell.pages[0] != i {
+			t.Errorf("row %d cell is on pages %v, want [%d]", i, cell.pages, i)
+		}
+	}
+	if p.typ != "P" || len(p.mcids) != 0 {
+		t.Errorf("P = %+v, want no marked content", p)
+	}
+
+	tests := []struct {
+		name    string
+		page    int
+		parents int // 親ツリーの要素数（ページのMCID数）
+		want    []string
+	}{
+		{"page 1", 1, 3, []string{"/Artifact BMC", "/H1 <</MCID 0>> BDC", "/Table <</MCID 1>> BDC", "/TD <</MCID 2>> BDC"}},
+		{"page 2", 2, 1, []string{"/TD <</MCID 0>> BDC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := r.r.GetPage(tt.page - 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := r.r.GetPageContents(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !bytes.Contains(content, []byte(want)) {
+					t.Errorf("content does not contain %q:\n%s", want, content)
+				}
+			}
+			if n := bytes.Count(content, []byte("BDC")) + bytes.Count(content, []byte("BMC")); n != bytes.Count(content, []byte("EMC")) {
+				t.Errorf("unbalanced marked content:\n%s", content)
+			}
+
+			key, ok := page[core.Name("StructParents")].(core.Integer)
+			if !ok {
+				t.Fatal("page has no /StructParents")
+			}
+			catalog, _ := r.r.GetCatalog()
+			structRoot := r.r.Resolve(catalog[core.Name("StructTreeRoot")]).(core.Dictionary)
+			parentTree := r.r.Resolve(structRoot[core.Name("ParentTree")]).(core.Dictionary)
+			nums := parentTree[core.Name("Nums")].(core.Array)
+			for i := 0; i+1 < len(nums); i += 2 {
+				if nums[i] == key {
+					if parents := nums[i+1].(core.Array); len(parents) != tt.parents {
+						t.Errorf("parent tree entry has %d elements, want %d", len(parents), tt.parents)
+					}
+					return
+				}
+			}
+			t.Errorf("parent tree has no entry for key %d", key)
+		})
+	}
+}
+
+func mustTag(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPageBeginTag_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(doc *Document) error
+	}{
+		{
+			name: "EndTag without BeginTag",
+			build: func(doc *Document) error {
+				return doc.AddPage(PageSizeA4, Portrait).EndTag()
+			},
+		},
+		{
+			name: "empty structure type",
+			build: func(doc *Document) error {
+				return doc.AddPage(PageSizeA4, Portrait).BeginTag("")
+			},
+		},
+		{
+			name: "nested Document",
+			build: func(doc *Document) error {
+				return doc.AddPage(PageSizeA4, Portrait).BeginTag(StructDocument)
+			},
+		},
+		{
+			name: "unclosed tag",
+			build: func(doc *Document) error {
+				if err := doc.AddPage(PageSizeA4, Portrait).BeginTag(StructP); err != nil {
+					return err
+				}
+				return doc.WriteTo(&bytes.Buffer{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.build(New()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDocumentWriteTo_Untagged(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.DrawLine(0, 0, 100, 100)
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	for _, unwanted := range []string{"/StructTreeRoot", "/MarkInfo", "/Artifact", "/Lang"} {
+		if bytes.Contains(buf.Bytes(), []byte(unwanted)) {
+			t.Errorf("untagged document contains %s", unwanted)
+		}
+	}
+}
+
+func TestDocumentWriteTo_PDFUA1(t *testing.T) {
+	fontPath := getTestTTFPath()
+	if fontPath == "" {
+		t.Skip("No test font available on this system")
+	}
+	ttf, err := LoadTTF(fontPath)
+	if err != nil {
+		t.Fatalf("LoadTTF failed: %v", err)
+	}
+
+	doc := New()
+	if err := doc.SetConformance(PDFUA1); err != nil {
+		t.Fatal(err)
+	}
+	doc.SetLanguage("en")
+	doc.SetMetadata(Metadata{Title: "Accessible"})
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetTTFFont(ttf, 12); err != nil {
+		t.Fatal(err)
+	}
+	mustTag(t, page.BeginTag(StructP))
+	mustTag(t, page.DrawTextUTF8("Hello", 50, 750))
+	mustTag(t, page.EndTag())
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	packet, err := r.RawXMP()
+	if err != nil {
+		t.Fatalf("RawXMP() failed: %v", err)
+	}
+	if !bytes.Contains(packet, []byte("<pdfuaid:part>1</pdfuaid:part>")) {
+		t.Error("XMP does not contain the PDF/UA identification")
+	}
+	if !bytes.Contains(packet, []byte("Accessible")) {
+		t.Error("XMP does not contain the title")
+	}
+}
+
+func TestDocumentWriteTo_PDFUA1Violations(t *testing.T) {
+	doc := New()
+	if err := doc.SetConformance(PDFUA1); err != nil {
+		t.Fatal(err)
+	}
+	page := doc.AddPage(PageSizeA4, Portrait)
+	page.SetFont(FontHelvetica, 12)
+	page.DrawText("untagged", 50, 750)
+
+	err := doc.WriteTo(&bytes.Buffer{})
+	var confErr *ConformanceError
+	if !errors.As(err, &confErr) {
+		t.Fatalf("WriteTo() error = %v, want *ConformanceError", err)
+	}
+	for _, want := range []string{"no structure tags", "language is not set", "title is not set", "non-embedded standard font Helvetica"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestNewMarkdownDocument_Tagged(t *testing.T) {
+	doc, err := NewMarkdownDocument("# Guide\n\nFirst paragraph.\n\n## Details\n\nSecond paragraph.\n", &MarkdownOptions{
+		Mode:     MarkdownModeDocument,
+		Language: "en-US",
+	})
+	if err != nil {
+		t.Fatalf("NewMarkdownDocument() failed: %v", err)
+	}
+	if doc.Language() != "en-US" {
+		t.Errorf("Language() = %q, want %q", doc.Language(), "en-US")
+	}
+	if m := doc.GetMetadata(); m == nil || m.Title != "Guide" {
+		t.Errorf("GetMetadata() = %+v, want Title %q", m, "Guide")
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	root := readStructure(t, r)
+	var types []string
+	for _, kid := range root.kids {
+		types = append(types, kid.typ)
+	}
+	if got, want := strings.Join(types, " "), "H1 P H2 P"; got != want {
+		t.Errorf("structure = %q, want %q", got, want)
+	}
+}
+
+// newTestImage は1x1ピクセルのRGB画像を作成する
+func newTestImage(t *testing.T) *Image {
+	t.Helper()
+	data, err := compressWithZlib([]byte{255, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Image{Width: 1, Height: 1, ColorSpace: "DeviceRGB", BitsPerComponent: 8, Filter: "FlateDecode", Data: data}
+}
+
+func TestPageDrawImageWithOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       ImageOptions
+		wantTagged bool
+	}{
+		{"with alt text", ImageOptions{AltText: "売上グラフ"}, true},
+		{"without alt text", ImageOptions{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			page := doc.AddPage(PageSizeA4, Portrait)
+			if err := page.DrawImageWithOptions(newTestImage(t), 100, 200, 300, 150, tt.opts); err != nil {
+				t.Fatalf("DrawImageWithOptions() failed: %v", err)
+			}
+			if doc.IsTagged() != tt.wantTagged {
+				t.Fatalf("IsTagged() = %v, want %v", doc.IsTagged(), tt.wantTagged)
+			}
+
+			var buf bytes.Buffer
+			if err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+			if !tt.wantTagged {
+				return
+			}
+
+			r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			catalog, _ := r.r.GetCatalog()
+			root := r.r.Resolve(catalog[core.Name("StructTreeRoot")]).(core.Dictionary)
+			document := r.r.Resolve(root[core.Name("K")]).(core.Dictionary)
+			figure := r.r.Resolve(document[core.Name("K")].(core.Array)[0]).(core.Dictionary)
+			if figure[core.Name("S")] != core.Name("Figure") {
+				t.Fatalf("/S = %v, want /Figure", figure[core.Name("S")])
+			}
+			if alt := rawTextString(figure[core.Name("Alt")]); alt != tt.opts.AltText {
+				t.Errorf("/Alt = %q, want %q", alt, tt.opts.AltText)
+			}
+			attrs, _ := figure[core.Name("A")].(core.Dictionary)
+			bbox, _ := attrs[core.Name("BBox")].(core.Array)
+			if attrs[core.Name("O")] != core.Name("Layout") || len(bbox) != 4 {
+				t.Errorf("/A = %v, want a layout attribute with /BBox", attrs)
+			}
+		})
+	}
+}
+
+func TestPageDrawImageWithOptions_Error(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.DrawImageWithOptions(nil, 100, 200, 300, 150, ImageOptions{AltText: "Logo"}); err == nil {
+		t.Fatal("DrawImageWithOptions() with a nil image should fail")
+	}
+	// 失敗してもFigureは閉じ、次の要素はFigureの子ではなく兄弟になる
+	if err := page.BeginTag(StructP); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.SetFont(FontHelvetica, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.DrawText("Caption", 100, 180); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.EndTag(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	root := readStructure(t, r)
+	var types []string
+	for _, kid := range root.kids {
+		types = append(types, kid.typ)
+	}
+	if got, want := strings.Join(types, " "), "Figure P"; got != want {
+		t.Errorf("structure = %q, want %q", got, want)
+	}
+}
+
+func TestPageSetAltText(t *testing.T) {
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetAltText("orphan"); err == nil {
+		t.Error("SetAltText() without an open element should fail")
+	}
+
+	if err := doc.SetConformance(PDFUA1); err != nil {
+		t.Fatal(err)
+	}
+	mustTag(t, page.BeginTag(StructFigure))
+	page.DrawCircle(100, 100, 50)
+	mustTag(t, page.EndTag())
+
+	err := doc.WriteTo(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "1 figure(s) have no alternative text") {
+		t.Errorf("WriteTo() error = %v, want a missing alternative text violation", err)
+	}
+
+	// 代替テキストを設定した図は違反にならない
+	mustTag(t, page.BeginTag(StructFigure))
+	mustTag(t, page.SetAltText("Circle"))
+	page.DrawCircle(100, 100, 50)
+	mustTag(t, page.EndTag())
+	if got := doc.structure.figuresWithoutAlt(); got != 1 {
+		t.Errorf("figuresWithoutAlt() = %d, want 1", got)
+	}
+}
@@ -1,23 +1,42 @@
 package gopdf
 
+import (
+	"math"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
 // TextRenderMode はPDFのテキストレンダリングモード
-type TextRenderMode int
+type TextRenderMode = layout.TextRenderMode
 
 const (
 	// TextRenderNormal は通常のテキスト表示（塗りつぶし）
-	TextRenderNormal TextRenderMode = 0
+	TextRenderNormal = layout.TextRenderNormal
 	// TextRenderStroke はテキストの輪郭のみ表示
-	TextRenderStroke TextRenderMode = 1
+	TextRenderStroke = layout.TextRenderStroke
 	// TextRenderFillStroke は塗りつぶしと輪郭の両方
-	TextRenderFillStroke TextRenderMode = 2
+	TextRenderFillStroke = layout.TextRenderFillStroke
 	// TextRenderInvisible はテキストを非表示（コピー・検索は可能）
-	TextRenderInvisible TextRenderMode = 3
+	TextRenderInvisible = layout.TextRenderInvisible
+	// TextRenderClip はクリッピングパスに追加のみ（表示されない）
+	TextRenderClip = layout.TextRenderClip
 )
 
 // TextLayerWord は1つの単語とその位置情報
 type TextLayerWord struct {
-	Text   string    // 単語のテキスト
-	Bounds Rectangle // 位置と範囲（PDF座標系）
+	Text       string    // 単語のテキスト
+	Bounds     Rectangle // 位置と範囲（PDF座標系）
+	Confidence float64   // OCRの信頼度（0.0-1.0、OCRResult.ToTextLayerで設定。TextLayer.Debugの色分けに使う）
+
+	// Angle はベースラインの角度（度、反時計回り。0は水平）
+	// 0以外の場合、Boundsは回す前の単語の矩形で、左下(X, Y)を中心にAngleだけ回した位置に描く
+	Angle float64
+}
+
+// rotation はAngleのcosとsinを返す
+func (w TextLayerWord) rotation() (cos, sin float64) {
+	rad := w.Angle * math.Pi / 180
+	return math.Cos(rad), math.Sin(rad)
 }
 
 // TextLayer はページのテキストレイヤー
@@ -25,8 +44,19 @@ type TextLayer struct {
 	Words      []TextLayerWord // 単語のリスト
 	RenderMode TextRenderMode  // レンダリングモード
 	Opacity    float64         // 不透明度（0.0-1.0、デフォルト: 0.0 = 完全透明）
+
+	// Debug は単語の矩形に枠を描く（OCRの位置が画像と合っているかを目で確かめる用。出力するPDFでは使わない）
+	// 信頼度がLowConfidence未満の単語は赤、それ以外は緑の枠になる
+	Debug         bool
+	LowConfidence float64 // Debugで赤い枠にする信頼度のしきい値（0の場合はDefaultLowConfidence）
 }
 
+// DefaultLowConfidence はTextLayer.LowConfidenceを省略したときのしきい値
+const DefaultLowConfidence = 0.6
+
+// textLayerOutlineWidth はTextLayer.Debugで描く枠の線幅（ポイント）
+const textLayerOutlineWidth = 0.5
+
 // DefaultTextLayer はデフォルトのTextLayerを作成（透明テキスト）
 func DefaultTextLayer() TextLayer {
 	return TextLayer{
@@ -108,13 +138,18 @@ func ConvertPixelToPDFRect(
 type OCRWord struct {
 	Text       string    // 単語
 	Confidence float64   // 信頼度（0.0-1.0）
-	Bounds     Rectangle // 位置（ピクセル座標、左上原点）
+	Bounds     Rectangle // 位置（ピクセル座標、左上原点。Angleがある場合は回した単語を囲む矩形）
+	Angle      float64   // ベースラインの角度（度、画像の見た目で反時計回り。傾いたスキャンなど）
 }
 
 // OCRResult はOCR処理の結果
 type OCRResult struct {
 	Text  string    // 全体テキスト
 	Words []OCRWord // 個別の単語
+
+	// ImageWidth・ImageHeight はOCRした画像の大きさ（ピクセル。hOCRなど結果に含まれる場合のみ。ToTextLayerに渡す）
+	ImageWidth  int
+	ImageHeight int
 }
 
 // ToTextLayer はOCRResultをTextLayerに変換
@@ -125,6 +160,8 @@ func (r OCRResult) ToTextLayer(
 	pdfWidth, pdfHeight float64,
 ) TextLayer {
 	words := make([]TextLayerWord, 0, len(r.Words))
+	scaleX := pdfWidth / float64(imageWidth)
+	scaleY := pdfHeight / float64(imageHeight)
 
 	for _, ocrWord := range r.Words {
 		// ピクセル座標をPDF座標に変換
@@ -134,11 +171,64 @@ func (r OCRResult) ToTextLayer(
 			pdfWidth, pdfHeight,
 		)
 
+		// 傾いた単語は、縦横の倍率の違いを反映した角度で、囲む矩形から回す前の矩形に戻す
+		angle := 0.0
+		if ocrWord.Angle != 0 {
+			rad := ocrWord.Angle * math.Pi / 180
+			angle = math.Atan2(math.Sin(rad)*scaleY, math.Cos(rad)*scaleX) * 180 / math.Pi
+			var ok bool
+			if pdfBounds, ok = unrotateWordBounds(pdfBounds, angle); !ok {
+				angle = 0
+			}
+		}
+
 		words = append(words, TextLayerWord{
-			Text:   ocrWord.Text,
-			Bounds: pdfBounds,
+			Text:       ocrWord.Text,
+			Bounds:     pdfBounds,
+			Confidence: ocrWord.Confidence,
+			Angle:      angle,
 		})
 	}
 
 	return NewTextLayer(words)
 }
+
+// minWordRotationDet は囲む矩形から回す前の単語の矩形を求めるときの、cos²-sin²の絶対値の下限
+// 45度付近では解が不安定になるため、これより小さい場合は回さずに囲む矩形のまま使う
+const minWordRotationDet = 0.05
+
+// unrotateWordBounds は角度angle（度）で回した単語を囲む矩形boundsから、回す前の単語の矩形（左下が回転の中心）を求める
+// 求められない場合（45度付近、幅か高さが0以下になる場合）はfalseを返す
+func unrotateWordBounds(bounds Rectangle, angle float64) (Rectangle, bool) {
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	c, s := math.Abs(cos), math.Abs(sin)
+
+	// 囲む矩形の幅 = w|cos|+h|sin|、高さ = w|sin|+h|cos| を解く
+	det := c*c - s*s
+	if math.Abs(det) < minWordRotationDet {
+		return bounds, false
+	}
+	width := (bounds.Width*c - bounds.Height*s) / det
+	height := (bounds.Height*c - bounds.Width*s) / det
+	if width <= 0 || height <= 0 {
+		return bounds, false
+	}
+
+	// 回した単語の4隅のうち、左端・下端にある隅が囲む矩形の左下に接する
+	minX := min(0, width*cos, -height*sin, width*cos-height*sin)
+	minY := min(0, width*sin, height*cos, width*sin+height*cos)
+	return Rectangle{X: bounds.X - minX, Y: bounds.Y - minY, Width: width, Height: height}, true
+}
+
+// FilterByConfidence は信頼度がmin以上の単語だけを残したOCRResultを返す（Textと画像の大きさはそのまま）
+func (r OCRResult) FilterByConfidence(min float64) OCRResult {
+	words := make([]OCRWord, 0, len(r.Words))
+	for _, word := range r.Words {
+		if word.Confidence >= min {
+			words = append(words, word)
+		}
+	}
+	r.Words = words
+	return r
+}
@@ -2,6 +2,7 @@ package gopdf
 
 import (
 	"math"
+	"slices"
 	"testing"
 )
 
@@ -239,12 +240,127 @@ func TestOCRResult_ToTextLayer(t *testing.T) {
 		t.Errorf("Word[1].Text = %q, want %q", layer.Words[1].Text, "World")
 	}
 
+	if layer.Words[0].Confidence != 0.99 {
+		t.Errorf("Word[0].Confidence = %v, want 0.99", layer.Words[0].Confidence)
+	}
+
 	// 座標が変換されていることを確認
 	if layer.Words[0].Bounds.X <= 0 || layer.Words[0].Bounds.X > pdfWidth {
 		t.Errorf("Word[0] X coordinate not properly converted: %f", layer.Words[0].Bounds.X)
 	}
 }
 
+func TestOCRResult_FilterByConfidence(t *testing.T) {
+	result := OCRResult{
+		Text: "a b c",
+		Words: []OCRWord{
+			{Text: "a", Confidence: 0.9},
+			{Text: "b", Confidence: 0.5},
+			{Text: "c", Confidence: 0.7},
+		},
+		ImageWidth:  100,
+		ImageHeight: 50,
+	}
+
+	tests := []struct {
+		name string
+		min  float64
+		want []string
+	}{
+		{name: "zero keeps all", min: 0, want: []string{"a", "b", "c"}},
+		{name: "inclusive threshold", min: 0.7, want: []string{"a", "c"}},
+		{name: "none", min: 0.95, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := result.FilterByConfidence(tt.min)
+			var texts []string
+			for _, w := range got.Words {
+				texts = append(texts, w.Text)
+			}
+			if len(texts) != len(tt.want) {
+				t.Fatalf("words = %v, want %v", texts, tt.want)
+			}
+			for i := range texts {
+				if texts[i] != tt.want[i] {
+					t.Errorf("words = %v, want %v", texts, tt.want)
+				}
+			}
+			if got.Text != result.Text || got.ImageWidth != 100 || got.ImageHeight != 50 {
+				t.Errorf("FilterByConfidence changed Text or image size: %+v", got)
+			}
+		})
+	}
+
+	if len(result.Words) != 3 {
+		t.Errorf("FilterByConfidence modified the receiver: %d words", len(result.Words))
+	}
+}
+
+func TestUnrotateWordBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		angle  float64
+		wantOK bool
+	}{
+		{name: "slight skew", angle: 2, wantOK: true},
+		{name: "negative skew", angle: -15, wantOK: true},
+		{name: "steep", angle: 30, wantOK: true},
+		{name: "vertical", angle: 90, wantOK: true},
+		{name: "upside down", angle: 180, wantOK: true},
+		{name: "diagonal", angle: 45, wantOK: false},
+	}
+
+	word := Rectangle{X: 50, Y: 60, Width: 100, Height: 20}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wordを左下を中心に回したときの4隅を囲む矩形
+			tw := TextLayerWord{Angle: tt.angle}
+			cos, sin := tw.rotation()
+			xs := []float64{0, word.Width * cos, -word.Height * sin, word.Width*cos - word.Height*sin}
+			ys := []float64{0, word.Width * sin, word.Height * cos, word.Width*sin + word.Height*cos}
+			minX, maxX := slices.Min(xs), slices.Max(xs)
+			minY, maxY := slices.Min(ys), slices.Max(ys)
+			bounds := Rectangle{X: word.X + minX, Y: word.Y + minY, Width: maxX - minX, Height: maxY - minY}
+
+			got, ok := unrotateWordBounds(bounds, tt.angle)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if got != bounds {
+					t.Errorf("bounds = %+v, want unchanged %+v", got, bounds)
+				}
+				return
+			}
+			for _, v := range [][2]float64{{got.X, word.X}, {got.Y, word.Y}, {got.Width, word.Width}, {got.Height, word.Height}} {
+				if math.Abs(v[0]-v[1]) > 1e-6 {
+					t.Errorf("bounds = %+v, want %+v", got, word)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestOCRResult_ToTextLayer_Angle(t *testing.T) {
+	result := OCRResult{Words: []OCRWord{
+		{Text: "skewed", Bounds: Rectangle{X: 10, Y: 10, Width: 50, Height: 60}, Angle: 45},
+		{Text: "flat", Bounds: Rectangle{X: 10, Y: 60, Width: 50, Height: 10}},
+	}}
+
+	// 横に2倍にすると、見た目の45度は atan(1/2) になる
+	layer := result.ToTextLayer(200, 100, 400, 100)
+	want := math.Atan2(1, 2) * 180 / math.Pi
+	if got := layer.Words[0].Angle; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Angle = %v, want %v", got, want)
+	}
+	if got := layer.Words[1].Angle; got != 0 {
+		t.Errorf("flat word Angle = %v, want 0", got)
+	}
+}
+
 func TestTextRenderMode_Constants(t *testing.T) {
 	tests := []struct {
 		name string
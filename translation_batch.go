@@ -0,0 +1,81 @@
+package gopdf
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchTranslator は複数のテキストをまとめて翻訳するTranslator
+// TranslatePDFなどはTranslatorがBatchTranslatorも実装していれば、ページのテキストを1回の呼び出しで翻訳する（翻訳APIの往復を減らす）
+type BatchTranslator interface {
+	Translator
+	// TranslateBatch はtextsを翻訳し、同じ順序・同じ数の翻訳結果を返す
+	TranslateBatch(texts []string) ([]string, error)
+}
+
+// ContextBatchTranslator はcontextを受け取るBatchTranslator
+type ContextBatchTranslator interface {
+	TranslateBatchContext(ctx context.Context, texts []string) ([]string, error)
+}
+
+// BatchTranslateFunc は関数型BatchTranslator
+type BatchTranslateFunc func([]string) ([]string, error)
+
+// Translate はtextだけをまとめて翻訳する
+func (f BatchTranslateFunc) Translate(text string) (string, error) {
+	translated, err := f.TranslateBatch([]string{text})
+	if err != nil {
+		return "", err
+	}
+	return translated[0], nil
+}
+
+// TranslateBatch はBatchTranslateFuncの実装
+func (f BatchTranslateFunc) TranslateBatch(texts []string) ([]string, error) {
+	translated, err := f(texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("batch translation returned %d texts for %d", len(translated), len(texts))
+	}
+	return translated, nil
+}
+
+// translateBatch はtranslatorでtextsを翻訳する
+// ContextBatchTranslator・BatchTranslatorの場合はまとめて1回で、それ以外は1つずつ翻訳する
+func translateBatch(ctx context.Context, translator Translator, texts []string) ([]string, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	var translated []string
+	var err error
+	switch t := translator.(type) {
+	case ContextBatchTranslator:
+		translated, err = t.TranslateBatchContext(ctx, texts)
+	case BatchTranslator:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		translated, err = t.TranslateBatch(texts)
+	default:
+		translated = make([]string, len(texts))
+		for i, text := range texts {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if translated[i], err = translateText(ctx, translator, text); err != nil {
+				return nil, err
+			}
+		}
+		return translated, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("batch translation returned %d texts for %d", len(translated), len(texts))
+	}
+	return translated, nil
+}
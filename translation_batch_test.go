@@ -0,0 +1,158 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingBatchTranslator はTranslateBatchに渡されたテキストを記録し、大文字にして返す
+type recordingBatchTranslator struct {
+	mu      sync.Mutex
+	batches [][]string
+	singles int
+}
+
+func (r *recordingBatchTranslator) Translate(text string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.singles++
+	return strings.ToUpper(text), nil
+}
+
+func (r *recordingBatchTranslator) TranslateBatch(texts []string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]string(nil), texts...))
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = strings.ToUpper(text)
+	}
+	return translated, nil
+}
+
+func TestTranslatePDFToWriter_Batch(t *testing.T) {
+	// ページのテキストブロックは、ページごとに1回でまとめて翻訳する
+	doc := New()
+	for _, texts := range [][]string{{"Title", "Body text"}, {"Appendix"}} {
+		page := doc.AddPage(PageSize{Width: 300, Height: 300}, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatal(err)
+		}
+		for i, text := range texts {
+			if err := page.DrawText(text, 20, 250-float64(i)*100); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var input bytes.Buffer
+	if err := doc.WriteTo(&input); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &recordingBatchTranslator{}
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	opts.Translator = backend
+	var out bytes.Buffer
+	if err := TranslatePDFToWriter(bytes.NewReader(input.Bytes()), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriter failed: %v", err)
+	}
+	if got := fmt.Sprint(backend.batches); got != "[[Title Body text] [Appendix]]" || backend.singles != 0 {
+		t.Errorf("batches = %s, single calls = %d", got, backend.singles)
+	}
+}
+
+func TestTranslateBatch_Wrappers(t *testing.T) {
+	tests := []struct {
+		name        string
+		wrap        func(Translator) Translator
+		texts       []string
+		wantBatches string // 元のTranslatorに渡されたバッチ
+	}{
+		{"plain", func(t Translator) Translator { return t }, []string{"a", "b"}, "[[a b]]"},
+		{
+			"cache",
+			func(t Translator) Translator {
+				cache := NewMemoryTranslationCache()
+				_ = cache.Put(TranslationCacheKey{Text: "c", Language: "ja"}, "C")
+				return NewCachedTranslator(t, cache, "ja")
+			},
+			// キャッシュにある"c"と、重複した"a"は送らない
+			[]string{"a", "b", "a", "c"},
+			"[[a b]]",
+		},
+		{
+			"glossary",
+			func(t Translator) Translator {
+				return WithGlossary(t, map[string]string{"gopdf": "GOPDF"}, []*regexp.Regexp{regexp.MustCompile(`\d+`)})
+			},
+			// 数値だけのテキストは送らない
+			[]string{"12", "hello gopdf"},
+			"[[hello {{0}}]]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &recordingBatchTranslator{}
+			blocks := make([]TextBlock, len(tt.texts))
+			for i, text := range tt.texts {
+				blocks[i].Text = text
+			}
+			if err := TranslateTextBlocks(blocks, tt.wrap(backend)); err != nil {
+				t.Fatalf("TranslateTextBlocks failed: %v", err)
+			}
+			if got := fmt.Sprint(backend.batches); got != tt.wantBatches || backend.singles != 0 {
+				t.Errorf("batches = %s, single calls = %d, want %s", got, backend.singles, tt.wantBatches)
+			}
+			for i, tb := range blocks {
+				if want := strings.ToUpper(tt.texts[i]); tb.Text != want {
+					t.Errorf("block %d = %q, want %q", i, tb.Text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslateTables_Batch(t *testing.T) {
+	tables := []TableBlock{{Rows: []TableRow{
+		{Cells: []TableCell{{Text: "Name"}, {Text: " "}}},
+		{Cells: []TableCell{{Text: "Price"}, {Text: "Total"}}},
+	}}}
+	backend := &recordingBatchTranslator{}
+	if err := TranslateTables(tables, backend); err != nil {
+		t.Fatalf("TranslateTables failed: %v", err)
+	}
+	if got := fmt.Sprint(backend.batches); got != "[[Name Price Total]]" {
+		t.Errorf("batches = %s, want [[Name Price Total]]", got)
+	}
+	if tables[0].Rows[1].Cells[1].Text != "TOTAL" || tables[0].Rows[0].Cells[1].Text != " " {
+		t.Errorf("cells = %+v", tables[0].Rows)
+	}
+}
+
+func TestBatchTranslateFunc(t *testing.T) {
+	short := BatchTranslateFunc(func(texts []string) ([]string, error) {
+		return texts[:len(texts)-1], nil
+	})
+	if _, err := short.TranslateBatch([]string{"a", "b"}); err == nil {
+		t.Error("TranslateBatch should fail when the result count differs")
+	}
+	if err := TranslateTextBlocks([]TextBlock{{Text: "a"}}, short); err == nil {
+		t.Error("TranslateTextBlocks should fail when the result count differs")
+	}
+
+	upper := BatchTranslateFunc(func(texts []string) ([]string, error) {
+		out := make([]string, len(texts))
+		for i, text := range texts {
+			out[i] = strings.ToUpper(text)
+		}
+		return out, nil
+	})
+	if got, err := upper.Translate("hi"); err != nil || got != "HI" {
+		t.Errorf("Translate = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,267 @@
+package gopdf
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// TranslationCacheKey は翻訳キャッシュのキー（原文と翻訳先の言語）
+type TranslationCacheKey struct {
+	Text     string `json:"text"`
+	Language string `json:"language"`
+}
+
+// TranslationCache は翻訳結果のキャッシュ
+// CachedTranslatorから並行に呼ばれるため、実装は並行に使えること
+type TranslationCache interface {
+	// Get はキーの翻訳結果を返す（ない場合はfalse）
+	Get(key TranslationCacheKey) (string, bool)
+	// Put はキーの翻訳結果を保存する
+	Put(key TranslationCacheKey, translated string) error
+}
+
+// CachedTranslator は翻訳結果をキャッシュするTranslator
+// ヘッダー・フッターや定型文など、同じ原文を有料の翻訳APIに何度も送らないために使う
+// 同じ原文の翻訳が並行に呼ばれた場合も、Translatorは1回だけ呼ぶ。TranslatorがBatchTranslatorの場合は、キャッシュにないテキストをまとめて翻訳する
+// 設計書: docs/pdf_translation_design.md
+type CachedTranslator struct {
+	Translator Translator       // 実際に翻訳するTranslator（ContextTranslatorの場合はctxを渡す）
+	Cache      TranslationCache // 翻訳結果のキャッシュ
+	Language   string           // 翻訳先の言語（キャッシュのキーに使う。例: "ja"）
+
+	mu       sync.Mutex
+	inflight map[TranslationCacheKey]*translationCall
+}
+
+// translationCall は実行中の翻訳
+type translationCall struct {
+	done       chan struct{}
+	translated string
+	err        error
+}
+
+// NewCachedTranslator はtranslatorの翻訳結果をcacheに保存するCachedTranslatorを作成する
+func NewCachedTranslator(translator Translator, cache TranslationCache, language string) *CachedTranslator {
+	return &CachedTranslator{Translator: translator, Cache: cache, Language: language}
+}
+
+// Translate はキャッシュにあればその翻訳結果を、なければTranslatorで翻訳して保存した結果を返す
+func (c *CachedTranslator) Translate(text string) (string, error) {
+	return c.TranslateContext(context.Background(), text)
+}
+
+// TranslateContext はctxを使ってTranslateを行う
+func (c *CachedTranslator) TranslateContext(ctx context.Context, text string) (string, error) {
+	translated, err := c.TranslateBatchContext(ctx, []string{text})
+	if err != nil {
+		return "", err
+	}
+	return translated[0], nil
+}
+
+// TranslateBatch はキャッシュにないテキストだけをまとめてTranslatorで翻訳する
+// TranslatorがBatchTranslatorでない場合は1つずつ翻訳する
+func (c *CachedTranslator) TranslateBatch(texts []string) ([]string, error) {
+	return c.TranslateBatchContext(context.Background(), texts)
+}
+
+// TranslateBatchContext はctxを使ってTranslateBatchを行う
+func (c *CachedTranslator) TranslateBatchContext(ctx context.Context, texts []string) ([]string, error) {
+	if c.Translator == nil || c.Cache == nil {
+		return nil, fmt.Errorf("cached translator requires a translator and a cache")
+	}
+
+	results := make([]string, len(texts))
+	waiting := make(map[int]*translationCall)
+	owned := make(map[TranslationCacheKey]*translationCall)
+	var pending []string
+	for i, text := range texts {
+		key := TranslationCacheKey{Text: text, Language: c.Language}
+		if translated, ok := c.Cache.Get(key); ok {
+			results[i] = translated
+			continue
+		}
+
+		// 同じ原文の翻訳が実行中（他の呼び出し、またはこのバッチの前のテキスト）なら、その結果を待つ
+		c.mu.Lock()
+		call, ok := c.inflight[key]
+		if !ok {
+			if c.inflight == nil {
+				c.inflight = make(map[TranslationCacheKey]*translationCall)
+			}
+			call = &translationCall{done: make(chan struct{})}
+			c.inflight[key] = call
+			owned[key] = call
+			pending = append(pending, text)
+		}
+		c.mu.Unlock()
+		waiting[i] = call
+	}
+
+	if len(pending) > 0 {
+		translated, err := translateBatch(ctx, c.Translator, pending)
+		for j, text := range pending {
+			key := TranslationCacheKey{Text: text, Language: c.Language}
+			call := owned[key]
+			if err != nil {
+				call.err = err
+				continue
+			}
+			call.translated = translated[j]
+			if err := c.Cache.Put(key, call.translated); err != nil {
+				call.translated, call.err = "", fmt.Errorf("failed to cache translation: %w", err)
+			}
+		}
+
+		c.mu.Lock()
+		for key, call := range owned {
+			delete(c.inflight, key)
+			close(call.done)
+		}
+		c.mu.Unlock()
+	}
+
+	for i, call := range waiting {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		results[i] = call.translated
+	}
+	return results, nil
+}
+
+// MemoryTranslationCache はメモリ上の翻訳キャッシュ
+type MemoryTranslationCache struct {
+	mu      sync.RWMutex
+	entries map[TranslationCacheKey]string
+}
+
+// NewMemoryTranslationCache は空のMemoryTranslationCacheを作成する
+func NewMemoryTranslationCache() *MemoryTranslationCache {
+	return &MemoryTranslationCache{entries: make(map[TranslationCacheKey]string)}
+}
+
+// Get はキーの翻訳結果を返す
+func (c *MemoryTranslationCache) Get(key TranslationCacheKey) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	translated, ok := c.entries[key]
+	return translated, ok
+}
+
+// Put はキーの翻訳結果を保存する
+func (c *MemoryTranslationCache) Put(key TranslationCacheKey, translated string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = translated
+	return nil
+}
+
+// Len は保存している翻訳結果の数を返す
+func (c *MemoryTranslationCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// fileCacheEntry はFileTranslationCacheのファイルの1行
+type fileCacheEntry struct {
+	TranslationCacheKey
+	Translation string `json:"translation"`
+}
+
+// FileTranslationCache はファイルに保存する翻訳キャッシュ
+// ファイルは1行に1件のJSON（JSON Lines）で、Putのたびに追記する。開くときに全件をメモリに読み込む
+// 同じキーが複数行ある場合は後の行を使う
+type FileTranslationCache struct {
+	memory *MemoryTranslationCache
+	mu     sync.Mutex
+	file   *os.File
+}
+
+// OpenFileTranslationCache はpathの翻訳キャッシュを開く（ファイルがなければ作成する）
+// 使い終わったらCloseで閉じる
+func OpenFileTranslationCache(path string) (*FileTranslationCache, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open translation cache: %w", err)
+	}
+	memory, err := readTranslationCache(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read translation cache %s: %w", path, err)
+	}
+	return &FileTranslationCache{memory: memory, file: file}, nil
+}
+
+// readTranslationCache はJSON Linesの翻訳キャッシュを読み込む
+func readTranslationCache(r io.Reader) (*MemoryTranslationCache, error) {
+	memory := NewMemoryTranslationCache()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry fileCacheEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		memory.entries[entry.TranslationCacheKey] = entry.Translation
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return memory, nil
+}
+
+// Get はキーの翻訳結果を返す
+func (c *FileTranslationCache) Get(key TranslationCacheKey) (string, bool) {
+	return c.memory.Get(key)
+}
+
+// Put はキーの翻訳結果を保存し、ファイルに追記する
+func (c *FileTranslationCache) Put(key TranslationCacheKey, translated string) error {
+	data, err := json.Marshal(fileCacheEntry{TranslationCacheKey: key, Translation: translated})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return errors.New("translation cache is closed")
+	}
+	if _, err := c.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write translation cache: %w", err)
+	}
+	return c.memory.Put(key, translated)
+}
+
+// Len は保存している翻訳結果の数を返す
+func (c *FileTranslationCache) Len() int {
+	return c.memory.Len()
+}
+
+// Close はキャッシュのファイルを閉じる
+func (c *FileTranslationCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
@@ -0,0 +1,173 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// countingTranslator は呼ばれた回数を数え、原文を大文字にして返す
+type countingTranslator struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingTranslator) Translate(text string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[text]++
+	if text == "fail" {
+		return "", errors.New("translation failed")
+	}
+	return strings.ToUpper(text), nil
+}
+
+func TestCachedTranslator(t *testing.T) {
+	backend := &countingTranslator{}
+	cache := NewMemoryTranslationCache()
+	ja := NewCachedTranslator(backend, cache, "ja")
+	fr := NewCachedTranslator(backend, cache, "fr")
+
+	for _, step := range []struct {
+		translator *CachedTranslator
+		text       string
+		wantErr    bool
+	}{
+		{ja, "header", false},
+		{ja, "header", false},
+		{fr, "header", false}, // 言語が違えば別のキー
+		{ja, "fail", true},
+		{ja, "fail", true}, // 失敗した翻訳は保存しない
+	} {
+		got, err := step.translator.Translate(step.text)
+		if (err != nil) != step.wantErr {
+			t.Fatalf("Translate(%q) error = %v, wantErr %v", step.text, err, step.wantErr)
+		}
+		if !step.wantErr && got != strings.ToUpper(step.text) {
+			t.Errorf("Translate(%q) = %q", step.text, got)
+		}
+	}
+
+	if backend.calls["header"] != 2 || backend.calls["fail"] != 2 {
+		t.Errorf("backend calls = %v, want header:2 fail:2", backend.calls)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("cache has %d entries, want 2", cache.Len())
+	}
+}
+
+func TestCachedTranslator_Concurrent(t *testing.T) {
+	// 同じ原文の翻訳が並行に呼ばれても、翻訳APIは1回だけ呼ぶ
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	backend := TranslateFunc(func(text string) (string, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return strings.ToUpper(text), nil
+	})
+	translator := NewCachedTranslator(backend, NewMemoryTranslationCache(), "ja")
+
+	var wg sync.WaitGroup
+	results := make([]string, 8)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = translator.Translate("footer")
+		}()
+	}
+	// 最初の呼び出しが翻訳中になってから解放する
+	for {
+		mu.Lock()
+		started := calls > 0
+		mu.Unlock()
+		if started {
+			break
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("backend called %d times, want 1", calls)
+	}
+	for i, got := range results {
+		if got != "FOOTER" {
+			t.Errorf("result %d = %q, want FOOTER", i, got)
+		}
+	}
+}
+
+func TestFileTranslationCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+
+	cache, err := OpenFileTranslationCache(path)
+	if err != nil {
+		t.Fatalf("OpenFileTranslationCache failed: %v", err)
+	}
+	backend := &countingTranslator{}
+	translator := NewCachedTranslator(backend, cache, "ja")
+	for _, text := range []string{"Confidential", "Page", "Confidential"} {
+		if _, err := translator.Translate(text); err != nil {
+			t.Fatalf("Translate(%q) failed: %v", text, err)
+		}
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := cache.Put(TranslationCacheKey{Text: "x", Language: "ja"}, "X"); err == nil {
+		t.Error("Put on a closed cache should fail")
+	}
+
+	// 開き直すと前回の翻訳結果を使う
+	reopened, err := OpenFileTranslationCache(path)
+	if err != nil {
+		t.Fatalf("OpenFileTranslationCache failed: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.Len() != 2 {
+		t.Errorf("reopened cache has %d entries, want 2", reopened.Len())
+	}
+	if got, ok := reopened.Get(TranslationCacheKey{Text: "Page", Language: "ja"}); !ok || got != "PAGE" {
+		t.Errorf("Get(Page) = %q, %v", got, ok)
+	}
+	if _, ok := reopened.Get(TranslationCacheKey{Text: "Page", Language: "fr"}); ok {
+		t.Error("Get(Page, fr) should miss")
+	}
+
+	// 壊れたファイルは開けない
+	broken := filepath.Join(t.TempDir(), "broken.jsonl")
+	if err := os.WriteFile(broken, []byte("{not json}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileTranslationCache(broken); err == nil {
+		t.Error("OpenFileTranslationCache should fail on a broken file")
+	}
+}
+
+func TestTranslatePDFToWriter_Cache(t *testing.T) {
+	// 繰り返すヘッダーは1回だけ翻訳する
+	backend := &countingTranslator{}
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	opts.Translator = NewCachedTranslator(backend, NewMemoryTranslationCache(), "ja")
+	opts.Workers = 3
+
+	input := translatorTestPDF(t, "Annual report", "Annual report", "Appendix")
+	var out bytes.Buffer
+	if err := TranslatePDFToWriter(bytes.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriter failed: %v", err)
+	}
+	if backend.calls["Annual report"] != 1 || backend.calls["Appendix"] != 1 {
+		t.Errorf("backend calls = %v, want each text once", backend.calls)
+	}
+}
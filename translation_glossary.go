@@ -0,0 +1,204 @@
+package gopdf
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// termPlaceholder は翻訳の前に用語を置き換える目印（{{0}}、{{1}}、...）
+// 原文にもともと目印と同じ形の文字列がある場合は、それも保護して元に戻す
+var termPlaceholder = regexp.MustCompile(`\{\{\d+\}\}`)
+
+// termProtector は用語集の用語と保護するパターンを目印に置き換え、翻訳後に戻す
+type termProtector struct {
+	glossary map[string]string
+	terms    *regexp.Regexp   // 用語集の用語（長い順）
+	patterns []*regexp.Regexp // 翻訳しない部分
+}
+
+// termMatch は原文の中の、目印に置き換える部分
+type termMatch struct {
+	start, end int
+	restore    string // 翻訳後に目印を戻す文字列
+}
+
+// WithGlossary はtranslatorの前後で用語を保護するTranslatorを返す
+//   - glossaryの用語（原文 -> 訳語）は、翻訳の前に目印（{{0}}など）に置き換え、翻訳後に訳語に戻す
+//   - patternsに一致する部分（製品名・コード・数値など）は、翻訳後に原文のまま戻す
+//
+// 用語は大文字・小文字を区別し、英数字で始まる・終わる用語は単語の境界でだけ一致する。重なる場合は先に始まる方、同じ位置では長い方を使う
+// 目印だけのテキストはtranslatorを呼ばずに戻す。翻訳結果から目印が消えた場合はエラー
+// glossaryもpatternsも空の場合はtranslatorをそのまま返す
+// 設計書: docs/pdf_translation_design.md
+func WithGlossary(translator Translator, glossary map[string]string, patterns []*regexp.Regexp) Translator {
+	if len(glossary) == 0 && len(patterns) == 0 {
+		return translator
+	}
+	return &glossaryTranslator{translator: translator, protector: newTermProtector(glossary, patterns)}
+}
+
+// glossaryTranslator はWithGlossaryのTranslator
+type glossaryTranslator struct {
+	translator Translator
+	protector  *termProtector
+}
+
+func (g *glossaryTranslator) Translate(text string) (string, error) {
+	return g.TranslateContext(context.Background(), text)
+}
+
+func (g *glossaryTranslator) TranslateContext(ctx context.Context, text string) (string, error) {
+	translated, err := g.TranslateBatchContext(ctx, []string{text})
+	if err != nil {
+		return "", err
+	}
+	return translated[0], nil
+}
+
+func (g *glossaryTranslator) TranslateBatch(texts []string) ([]string, error) {
+	return g.TranslateBatchContext(context.Background(), texts)
+}
+
+// TranslateBatchContext は目印に置き換えたテキストを、目印だけのものを除いてまとめて翻訳する
+func (g *glossaryTranslator) TranslateBatchContext(ctx context.Context, texts []string) ([]string, error) {
+	results := make([]string, len(texts))
+	restores := make([][]string, len(texts))
+	var pending []string
+	var indices []int
+	for i, text := range texts {
+		results[i], restores[i] = g.protector.mask(text)
+		if len(restores[i]) > 0 && strings.TrimSpace(termPlaceholder.ReplaceAllString(results[i], "")) == "" {
+			continue
+		}
+		pending = append(pending, results[i])
+		indices = append(indices, i)
+	}
+
+	translated, err := translateBatch(ctx, g.translator, pending)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range indices {
+		results[i] = translated[j]
+	}
+	for i := range results {
+		if results[i], err = g.protector.unmask(results[i], restores[i]); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// newTermProtector は用語集とパターンのtermProtectorを作成する
+func newTermProtector(glossary map[string]string, patterns []*regexp.Regexp) *termProtector {
+	p := &termProtector{glossary: glossary, patterns: patterns}
+
+	terms := make([]string, 0, len(glossary))
+	for term := range glossary {
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return p
+	}
+	// 正規表現の選択は左から順に試すので、長い用語を先にする
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+	alternatives := make([]string, len(terms))
+	for i, term := range terms {
+		alternatives[i] = wordBoundary(term, true) + regexp.QuoteMeta(term) + wordBoundary(term, false)
+	}
+	p.terms = regexp.MustCompile(strings.Join(alternatives, "|"))
+	return p
+}
+
+// wordBoundary は用語の先頭（start）または末尾が英数字の場合に単語の境界を返す
+func wordBoundary(term string, start bool) string {
+	var r rune
+	if start {
+		r, _ = utf8.DecodeRuneInString(term)
+	} else {
+		r, _ = utf8.DecodeLastRuneInString(term)
+	}
+	if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+		return `\b`
+	}
+	return ""
+}
+
+// mask はtextの用語と保護する部分を目印に置き換え、目印の順に戻す文字列を返す
+func (p *termProtector) mask(text string) (string, []string) {
+	var matches []termMatch
+	for _, loc := range termPlaceholder.FindAllStringIndex(text, -1) {
+		matches = append(matches, termMatch{start: loc[0], end: loc[1], restore: text[loc[0]:loc[1]]})
+	}
+	if p.terms != nil {
+		for _, loc := range p.terms.FindAllStringIndex(text, -1) {
+			matches = append(matches, termMatch{start: loc[0], end: loc[1], restore: p.glossary[text[loc[0]:loc[1]]]})
+		}
+	}
+	for _, pattern := range p.patterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			if loc[0] < loc[1] {
+				matches = append(matches, termMatch{start: loc[0], end: loc[1], restore: text[loc[0]:loc[1]]})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+	var b strings.Builder
+	var restore []string
+	pos := 0
+	for _, m := range matches {
+		if m.start < pos {
+			continue // 先に置き換えた部分と重なる
+		}
+		b.WriteString(text[pos:m.start])
+		b.WriteString("{{" + strconv.Itoa(len(restore)) + "}}")
+		restore = append(restore, m.restore)
+		pos = m.end
+	}
+	b.WriteString(text[pos:])
+	return b.String(), restore
+}
+
+// unmask は翻訳結果の目印を戻す。目印が欠けている場合はエラー
+func (p *termProtector) unmask(text string, restore []string) (string, error) {
+	if len(restore) == 0 {
+		return text, nil
+	}
+	found := make([]bool, len(restore))
+	result := termPlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		i, err := strconv.Atoi(placeholder[2 : len(placeholder)-2])
+		if err != nil || i >= len(restore) {
+			return placeholder
+		}
+		found[i] = true
+		return restore[i]
+	})
+	for i, ok := range found {
+		if !ok {
+			return "", fmt.Errorf("translation dropped the protected term %q", restore[i])
+		}
+	}
+	return result, nil
+}
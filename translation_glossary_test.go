@@ -0,0 +1,92 @@
+package gopdf
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithGlossary(t *testing.T) {
+	glossary := map[string]string{
+		"gopdf":         "gopdf",
+		"layout":        "レイアウト",
+		"layout engine": "レイアウトエンジン",
+		"API":           "API",
+	}
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`\d+(\.\d+)?`),
+		regexp.MustCompile("`[^`]+`"),
+	}
+
+	tests := []struct {
+		name       string
+		text       string
+		wantMasked string // Translatorに渡るテキスト（空の場合は呼ばれない）
+		want       string
+	}{
+		{"no terms", "Hello world", "Hello world", "HELLO WORLD"},
+		{"glossary", "The layout engine of gopdf", "The {{0}} of {{1}}", "THE レイアウトエンジン OF gopdf"},
+		{"word boundary", "layouts and layout", "layouts and {{0}}", "LAYOUTS AND レイアウト"},
+		{"patterns", "Call `Open()` 3 times", "Call {{0}} {{1}} times", "CALL `Open()` 3 TIMES"},
+		{"placeholder in source", "Use {{0}} with API", "Use {{0}} with {{1}}", "USE {{0}} WITH API"},
+		{"only protected", "12.5", "", "12.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var masked string
+			translator := WithGlossary(TranslateFunc(func(text string) (string, error) {
+				masked = text
+				return strings.ToUpper(text), nil
+			}), glossary, patterns)
+
+			got, err := translator.Translate(tt.text)
+			if err != nil {
+				t.Fatalf("Translate failed: %v", err)
+			}
+			if masked != tt.wantMasked {
+				t.Errorf("translator received %q, want %q", masked, tt.wantMasked)
+			}
+			if got != tt.want {
+				t.Errorf("Translate(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithGlossary_DroppedPlaceholder(t *testing.T) {
+	translator := WithGlossary(TranslateFunc(func(text string) (string, error) {
+		return "翻訳", nil // 目印を落とす
+	}), map[string]string{"gopdf": "gopdf"}, nil)
+	if _, err := translator.Translate("Welcome to gopdf"); err == nil {
+		t.Error("Translate should fail when the translator drops a placeholder")
+	}
+}
+
+func TestTranslatePDFToWriter_Glossary(t *testing.T) {
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	opts.Translator = TranslateFunc(func(text string) (string, error) {
+		return strings.ToUpper(text), nil
+	})
+	opts.Glossary = map[string]string{"Acme": "Acme Corp"}
+	opts.ProtectedPatterns = []*regexp.Regexp{regexp.MustCompile(`v\d+`)}
+
+	input := translatorTestPDF(t, "Acme release v2")
+	var out bytes.Buffer
+	if err := TranslatePDFToWriter(bytes.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriter failed: %v", err)
+	}
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	layout, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+	if len(layout.TextBlocks) != 1 || layout.TextBlocks[0].Text != "Acme Corp RELEASE v2" {
+		t.Errorf("translated page = %+v, want %q", layout.TextBlocks, "Acme Corp RELEASE v2")
+	}
+}
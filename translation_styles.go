@@ -0,0 +1,103 @@
+package gopdf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ryomak/gopdf/layout"
+)
+
+// styleTag は翻訳の前に、スタイルの違う部分（TextRun）を囲む目印（<s1>…</s1>）
+// 数字はブロックのRunsのインデックス。ブロックで最も長いスタイル（基本のスタイル）の部分は囲まない
+var styleTag = regexp.MustCompile(`<(/?)s(\d+)>`)
+
+// baseStyleRun はrunsのうち、テキストの合計が最も長いスタイル（基本のスタイル）の最初の部分のインデックスを返す
+func baseStyleRun(runs []TextRun) int {
+	base, longest := 0, -1
+	for i, run := range runs {
+		n := 0
+		for _, other := range runs {
+			if other.SameStyle(run) {
+				n += utf8.RuneCountInString(strings.TrimSpace(other.Text))
+			}
+		}
+		if n > longest {
+			base, longest = i, n
+		}
+	}
+	return base
+}
+
+// markStyleRuns は基本のスタイル以外の部分を目印で囲んだ、翻訳に渡すテキストを返す
+// 部分の前後の空白は目印の外に出す
+func markStyleRuns(runs []TextRun) string {
+	base := baseStyleRun(runs)
+	var b strings.Builder
+	for i, run := range runs {
+		text := strings.TrimSpace(run.Text)
+		if text == "" || run.SameStyle(runs[base]) {
+			b.WriteString(run.Text)
+			continue
+		}
+		start := strings.Index(run.Text, text)
+		tag := strconv.Itoa(i)
+		b.WriteString(run.Text[:start])
+		b.WriteString("<s" + tag + ">" + text + "</s" + tag + ">")
+		b.WriteString(run.Text[start+len(text):])
+	}
+	return b.String()
+}
+
+// unmarkStyleRuns は翻訳結果の目印を取り除き、テキストと部分ごとのスタイル（runsのスタイル）を返す
+// 目印の外は基本のスタイルにする。目印が壊れている場合（ない番号・入れ子・閉じていない）は、
+// 目印を取り除いたテキストとnil（ブロック全体を1つのスタイルで描く）を返す
+func unmarkStyleRuns(text string, runs []TextRun) (string, []TextRun) {
+	base := baseStyleRun(runs)
+	var result []TextRun
+	add := func(s string, style int) {
+		if s == "" {
+			return
+		}
+		run := runs[style]
+		run.Text = s
+		result = layout.AppendRun(result, run)
+	}
+
+	broken := func() (string, []TextRun) {
+		return styleTag.ReplaceAllString(text, ""), nil
+	}
+	open, pos := -1, 0
+	for _, loc := range styleTag.FindAllStringSubmatchIndex(text, -1) {
+		index, err := strconv.Atoi(text[loc[4]:loc[5]])
+		if err != nil || index >= len(runs) {
+			return broken()
+		}
+		closing := loc[3] > loc[2]
+		switch {
+		case !closing && open < 0:
+			add(text[pos:loc[0]], base)
+			open = index
+		case closing && open == index:
+			add(text[pos:loc[0]], index)
+			open = -1
+		default:
+			return broken()
+		}
+		pos = loc[1]
+	}
+	if open >= 0 {
+		return broken()
+	}
+	add(text[pos:], base)
+
+	var b strings.Builder
+	for _, run := range result {
+		b.WriteString(run.Text)
+	}
+	if len(result) < 2 {
+		return b.String(), nil
+	}
+	return b.String(), result
+}
@@ -1,10 +1,14 @@
 package gopdf
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 )
 
 // Translator はテキスト翻訳のインターフェース
@@ -21,14 +25,108 @@ func (f TranslateFunc) Translate(text string) (string, error) {
 	return f(text)
 }
 
+// ContextTranslator はcontextを受け取るTranslator
+// TranslatePDFContextは、TranslatorがContextTranslatorも実装していればTranslateContextを使う（翻訳APIの呼び出しを途中で取り消せる）
+type ContextTranslator interface {
+	TranslateContext(ctx context.Context, text string) (string, error)
+}
+
+// translateText はtranslatorでtextを翻訳する（ContextTranslatorの場合はctxを渡す）
+func translateText(ctx context.Context, translator Translator, text string) (string, error) {
+	if ct, ok := translator.(ContextTranslator); ok {
+		return ct.TranslateContext(ctx, text)
+	}
+	return translator.Translate(text)
+}
+
 // PDFTranslatorOptions は翻訳オプション
 type PDFTranslatorOptions struct {
-	Translator     Translator    // 翻訳インターフェース
-	TargetFont     interface{}   // ターゲット言語のフォント (font.StandardFont or *TTFFont)
-	TargetFontName string        // フォント名（estimateTextWidth用）
+	Translator     Translator     // 翻訳インターフェース
+	TargetFont     interface{}    // ターゲット言語のフォント (font.StandardFont or *TTFFont)
+	TargetFontName string         // フォント名（estimateTextWidth用）
 	FittingOptions FitTextOptions // テキストフィッティングオプション
-	KeepImages     bool          // 画像を保持（デフォルト: true）
-	KeepLayout     bool          // レイアウトを保持（デフォルト: true）
+	KeepImages     bool           // 画像を保持（デフォルト: true）
+	KeepLayout     bool           // レイアウトを保持（デフォルト: true）
+
+	// TargetLanguage は翻訳先の言語（"ja"、"ru"、"th" など）。TargetFontがnilの場合、この言語の文字を描けるフォントをFontResolverで選ぶ
+	TargetLanguage string
+	// FontResolver はTargetFontを選ぶFontResolver（nilの場合は埋め込まれたフォントだけを使う）
+	FontResolver *FontResolver
+	// MirrorLayout は翻訳したページのテキストブロック・画像ブロックの左右を反転し（MirrorHorizontally）、行を右揃えで描く
+	// 右から左に書く言語（IsRightToLeft）に翻訳する場合に使う
+	MirrorLayout bool
+
+	// OnProgress はページの処理が終わるたびに呼ばれる（pageは0始まりのページ番号、totalはページ数）
+	// TranslateErrorSkipPageで飛ばしたページでも呼ばれる
+	OnProgress func(page, total int)
+	// ErrorPolicy はページの処理に失敗したときの動作（デフォルト: TranslateErrorAbort）
+	ErrorPolicy TranslateErrorPolicy
+	// Workers は同時に翻訳するページ数（0以下は1）。2以上の場合、Translatorは並行に呼ばれる
+	Workers int
+
+	// Glossary は用語集（原文の用語 -> 訳語）。用語は翻訳の前に目印に置き換え、翻訳後に訳語に戻す（WithGlossary）
+	Glossary map[string]string
+	// ProtectedPatterns は翻訳しない部分（製品名・コード・数値など）。一致した部分は原文のまま残す（WithGlossary）
+	ProtectedPatterns []*regexp.Regexp
+
+	// Pages は翻訳するページ（0始まりのページ番号。空の場合はすべてのページ）
+	// それ以外のページは翻訳せず、抽出したレイアウトのまま描き直す
+	Pages []int
+	// Regions は翻訳する範囲（抽出したレイアウトの座標。空の場合はページ全体）
+	// いずれかの範囲と重なるテキストブロックだけを翻訳し、それ以外（ヘッダー・フッターや表など）は原文のまま描き直す
+	Regions []Rectangle
+
+	// PreserveStyles はブロック内の太字・斜体・色などの違う部分（TextBlock.Runs）を訳文に引き継ぐ
+	// 基本のスタイル以外の部分を <s1>…</s1> のような目印で囲んで翻訳し、訳文の目印の位置のスタイルで描く
+	// Translatorは目印を残して翻訳すること（目印が壊れた場合はブロック全体を1つのスタイルで描く）
+	PreserveStyles bool
+}
+
+// TranslateErrorPolicy はページの処理（抽出・翻訳・描画）に失敗したときの動作
+type TranslateErrorPolicy int
+
+const (
+	// TranslateErrorAbort は最初のエラーで中止し、*PageErrorを返す（何も出力しない）
+	TranslateErrorAbort TranslateErrorPolicy = iota
+	// TranslateErrorSkipPage は失敗したページを出力せずに残りのページを処理する
+	// 処理できたページを出力した上で、飛ばしたページのエラーを*TranslationErrorで返す
+	TranslateErrorSkipPage
+)
+
+// PageError はページの処理のエラー
+type PageError struct {
+	Page int // 0始まりのページ番号
+	Err  error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d: %v", e.Page, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// TranslationError はTranslateErrorSkipPageで飛ばしたページのエラーの一覧
+type TranslationError struct {
+	Total int          // 元のPDFのページ数
+	Pages []*PageError // 飛ばしたページのエラー（ページ順）
+}
+
+func (e *TranslationError) Error() string {
+	msgs := make([]string, len(e.Pages))
+	for i, p := range e.Pages {
+		msgs[i] = p.Error()
+	}
+	return fmt.Sprintf("skipped %d of %d pages: %s", len(e.Pages), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *TranslationError) Unwrap() []error {
+	errs := make([]error, len(e.Pages))
+	for i, p := range e.Pages {
+		errs[i] = p
+	}
+	return errs
 }
 
 // DefaultPDFTranslatorOptions はデフォルトのオプション
@@ -44,7 +142,15 @@ func DefaultPDFTranslatorOptions(targetFont interface{}, fontName string) PDFTra
 }
 
 // TranslatePDF はPDFを翻訳して新しいPDFを生成
+// opts.ErrorPolicyがTranslateErrorSkipPageの場合、飛ばしたページがあればPDFを出力した上で*TranslationErrorを返す
 func TranslatePDF(inputPath string, outputPath string, opts PDFTranslatorOptions) error {
+	return TranslatePDFContext(context.Background(), inputPath, outputPath, opts)
+}
+
+// TranslatePDFContext はctxを使ってTranslatePDFを行う
+// opts.Workersのページを並行に翻訳し、ctxが取り消された場合は何も出力せずにctxのエラーを返す
+// 設計書: docs/pdf_translation_design.md
+func TranslatePDFContext(ctx context.Context, inputPath string, outputPath string, opts PDFTranslatorOptions) error {
 	// 1. 元PDFを読み込み
 	reader, err := Open(inputPath)
 	if err != nil {
@@ -52,47 +158,36 @@ func TranslatePDF(inputPath string, outputPath string, opts PDFTranslatorOptions
 	}
 	defer reader.Close()
 
-	// 2. 新しいPDFドキュメントを作成
-	doc := New()
-
-	// 3. 各ページを処理
-	pageCount := reader.PageCount()
-	for i := 0; i < pageCount; i++ {
-		layout, err := reader.ExtractPageLayout(i)
-		if err != nil {
-			return fmt.Errorf("failed to extract layout from page %d: %w", i, err)
-		}
-
-		// 4. テキストを翻訳
-		if opts.Translator != nil {
-			for j := range layout.TextBlocks {
-				translated, err := opts.Translator.Translate(layout.TextBlocks[j].Text)
-				if err != nil {
-					return fmt.Errorf("translation failed on page %d, block %d: %w", i, j, err)
-				}
-				layout.TextBlocks[j].Text = translated
-			}
-		}
-
-		// 5. ページを生成
-		_, err = RenderLayout(doc, layout, opts)
-		if err != nil {
-			return fmt.Errorf("failed to render page %d: %w", i, err)
-		}
+	// 2. 各ページを翻訳
+	doc, skipped, err := translateDocument(ctx, reader, opts)
+	if err != nil {
+		return err
 	}
 
-	// 6. 出力
+	// 3. 出力
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer file.Close()
 
-	return doc.WriteTo(file)
+	if err := doc.WriteTo(file); err != nil {
+		return err
+	}
+	if skipped != nil {
+		return skipped
+	}
+	return nil
 }
 
 // TranslatePDFToWriter はPDFを翻訳してWriterに出力
+// opts.ErrorPolicyがTranslateErrorSkipPageの場合、飛ばしたページがあればPDFを出力した上で*TranslationErrorを返す
 func TranslatePDFToWriter(input io.ReadSeeker, output io.Writer, opts PDFTranslatorOptions) error {
+	return TranslatePDFToWriterContext(context.Background(), input, output, opts)
+}
+
+// TranslatePDFToWriterContext はctxを使ってTranslatePDFToWriterを行う
+func TranslatePDFToWriterContext(ctx context.Context, input io.ReadSeeker, output io.Writer, opts PDFTranslatorOptions) error {
 	// 1. 元PDFを読み込み
 	reader, err := OpenReader(input)
 	if err != nil {
@@ -100,37 +195,193 @@ func TranslatePDFToWriter(input io.ReadSeeker, output io.Writer, opts PDFTransla
 	}
 	defer reader.Close()
 
-	// 2. 新しいPDFドキュメントを作成
-	doc := New()
+	// 2. 各ページを翻訳
+	doc, skipped, err := translateDocument(ctx, reader, opts)
+	if err != nil {
+		return err
+	}
 
-	// 3. 各ページを処理
-	pageCount := reader.PageCount()
-	for i := 0; i < pageCount; i++ {
-		layout, err := reader.ExtractPageLayout(i)
+	// 3. 出力
+	if err := doc.WriteTo(output); err != nil {
+		return err
+	}
+	if skipped != nil {
+		return skipped
+	}
+	return nil
+}
+
+// translatedPage は抽出・翻訳したページ
+type translatedPage struct {
+	page   int
+	layout *PageLayout
+	err    error // 抽出・翻訳のエラー
+}
+
+// translateDocument は各ページを翻訳した新しいドキュメントを返す
+// 抽出は1つのゴルーチンで順に行い（PDFReaderは並行に使えない）、翻訳はopts.Workersのゴルーチンで並行に行い、描画はページ順に行う
+// TranslateErrorAbortでは最初に失敗したページの*PageErrorを返す
+// TranslateErrorSkipPageでは失敗したページを除いたドキュメントと、飛ばしたページのエラー（なければnil）を返す
+// ctxが取り消された場合はctxのエラーを返す
+func translateDocument(ctx context.Context, reader *PDFReader, opts PDFTranslatorOptions) (*Document, *TranslationError, error) {
+	if opts.TargetFont == nil && opts.TargetLanguage != "" {
+		font, err := opts.FontResolver.FontForLanguage(opts.TargetLanguage)
 		if err != nil {
-			return fmt.Errorf("failed to extract layout from page %d: %w", i, err)
+			return nil, nil, fmt.Errorf("failed to select target font: %w", err)
+		}
+		opts.TargetFont, opts.TargetFontName = font, font.Name()
+	}
+
+	pageCount := reader.PageCount()
+	for _, page := range opts.Pages {
+		if page < 0 || page >= pageCount {
+			return nil, nil, fmt.Errorf("page number %d out of range [0, %d)", page, pageCount)
 		}
+	}
+	results := make([]chan translatedPage, pageCount)
+	for i := range results {
+		results[i] = make(chan translatedPage, 1)
+	}
 
-		// 4. テキストを翻訳
-		if opts.Translator != nil {
-			for j := range layout.TextBlocks {
-				translated, err := opts.Translator.Translate(layout.TextBlocks[j].Text)
-				if err != nil {
-					return fmt.Errorf("translation failed on page %d, block %d: %w", i, j, err)
-				}
-				layout.TextBlocks[j].Text = translated
+	// 戻る前にゴルーチンを止めて待つ（呼び出し元がreaderを閉じるため）
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan translatedPage)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i := 0; i < pageCount; i++ {
+			layout, err := reader.ExtractPageLayout(i)
+			if err != nil {
+				err = fmt.Errorf("failed to extract layout: %w", err)
+			}
+			select {
+			case jobs <- translatedPage{page: i, layout: layout, err: err}:
+			case <-ctx.Done():
+				return
 			}
 		}
+	}()
+	var translator Translator
+	if opts.Translator != nil {
+		translator = WithGlossary(opts.Translator, opts.Glossary, opts.ProtectedPatterns)
+	}
+	for w := 0; w < max(opts.Workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if job.err == nil && translator != nil && opts.translatesPage(job.page) {
+					job.err = translateLayoutText(ctx, job.layout, translator, opts)
+					if job.err == nil && opts.MirrorLayout {
+						job.layout.MirrorHorizontally()
+					}
+				}
+				results[job.page] <- job
+			}
+		}()
+	}
 
-		// 5. ページを生成
-		_, err = RenderLayout(doc, layout, opts)
+	doc := New()
+	var skipped []*PageError
+	for i := 0; i < pageCount; i++ {
+		var job translatedPage
+		select {
+		case job = <-results[i]:
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("translation canceled: %w", ctx.Err())
+		}
+		err := job.err
+		if err == nil {
+			pageOpts := opts
+			if opts.MirrorLayout && translator != nil && opts.translatesPage(i) {
+				pageOpts.FittingOptions.Alignment = AlignRight
+			}
+			err = renderTranslatedPage(doc, job.layout, pageOpts)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to render page %d: %w", i, err)
+			if ctx.Err() != nil {
+				return nil, nil, fmt.Errorf("translation canceled: %w", ctx.Err())
+			}
+			pageErr := &PageError{Page: i, Err: err}
+			if opts.ErrorPolicy != TranslateErrorSkipPage {
+				return nil, nil, pageErr
+			}
+			skipped = append(skipped, pageErr)
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(i, pageCount)
+		}
+	}
+
+	if len(skipped) > 0 {
+		return doc, &TranslationError{Total: pageCount, Pages: skipped}, nil
+	}
+	return doc, nil, nil
+}
+
+// translateLayoutText はレイアウトのテキストブロックを翻訳する（BatchTranslatorの場合はページで1回の呼び出し）
+// opts.Regionsの範囲と重ならないブロックは翻訳しない
+func translateLayoutText(ctx context.Context, layout *PageLayout, translator Translator, opts PDFTranslatorOptions) error {
+	var texts []string
+	var indices []int
+	for i, tb := range layout.TextBlocks {
+		if !opts.translatesBlock(tb) {
+			continue
+		}
+		text := tb.Text
+		if runs := tb.StyleRuns(); opts.PreserveStyles && runs != nil {
+			text = markStyleRuns(runs)
+		}
+		texts = append(texts, text)
+		indices = append(indices, i)
+	}
+	translated, err := translateBatch(ctx, translator, texts)
+	if err != nil {
+		return fmt.Errorf("failed to translate text blocks: %w", err)
+	}
+	for j, i := range indices {
+		tb := &layout.TextBlocks[i]
+		if runs := tb.StyleRuns(); opts.PreserveStyles && runs != nil {
+			tb.Text, tb.Runs = unmarkStyleRuns(translated[j], runs)
+			continue
+		}
+		tb.Text, tb.Runs = translated[j], nil
+	}
+	return nil
+}
+
+// translatesPage はページ（0始まり）を翻訳するかを返す（Pagesが空の場合はすべて）
+func (opts PDFTranslatorOptions) translatesPage(page int) bool {
+	return len(opts.Pages) == 0 || slices.Contains(opts.Pages, page)
+}
+
+// translatesBlock はテキストブロックを翻訳するか（Regionsのいずれかと重なるか）を返す
+func (opts PDFTranslatorOptions) translatesBlock(tb TextBlock) bool {
+	if len(opts.Regions) == 0 {
+		return true
+	}
+	for _, region := range opts.Regions {
+		if overlap := region.Intersect(tb.Rect); overlap.Width > 0 && overlap.Height > 0 {
+			return true
 		}
 	}
+	return false
+}
 
-	// 6. 出力
-	return doc.WriteTo(output)
+// renderTranslatedPage は翻訳したレイアウトをdocに描く
+// 描画に失敗した場合は、描きかけのページをdocから取り除く
+func renderTranslatedPage(doc *Document, layout *PageLayout, opts PDFTranslatorOptions) error {
+	pages := len(doc.pages)
+	if _, err := RenderLayout(doc, layout, opts); err != nil {
+		doc.pages = doc.pages[:pages]
+		return fmt.Errorf("failed to render: %w", err)
+	}
+	return nil
 }
 
 // RenderLayout はPageLayoutからPageを生成
@@ -159,7 +410,7 @@ func RenderLayout(doc *Document, layout *PageLayout, opts PDFTranslatorOptions)
 					continue
 				}
 
-				if err := page.DrawImage(pdfImage, img.X, img.Y, img.PlacedWidth, img.PlacedHeight); err != nil {
+				if err := page.drawLayoutImage(pdfImage, img); err != nil {
 					// 画像の描画に失敗しても続行
 					continue
 				}
@@ -176,6 +427,14 @@ func RenderLayout(doc *Document, layout *PageLayout, opts PDFTranslatorOptions)
 					continue
 				}
 
+				// スタイルの違う部分があるブロックは、部分ごとのスタイルで描く
+				if opts.PreserveStyles && textBlock.StyleRuns() != nil {
+					if err := page.drawLayoutText(textBlock, opts.layoutRenderOptions()); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
 				// テキストをフィッティング
 				fitted, err := FitText(textBlock.Text, textBlock.Rect, opts.TargetFontName, opts.FittingOptions)
 				if err != nil {
@@ -217,6 +476,22 @@ func RenderLayout(doc *Document, layout *PageLayout, opts PDFTranslatorOptions)
 	return page, nil
 }
 
+// layoutRenderOptions はスタイルの違う部分があるブロックを描くときの、drawLayoutTextの設定を返す
+func (opts PDFTranslatorOptions) layoutRenderOptions() LayoutRenderOptions {
+	renderOpts := LayoutRenderOptions{
+		LineSpacing: opts.FittingOptions.LineSpacing,
+		MinFontSize: opts.FittingOptions.MinFontSize,
+		Alignment:   opts.FittingOptions.Alignment,
+	}
+	switch f := opts.TargetFont.(type) {
+	case StandardFont:
+		renderOpts.Font = f
+	case *TTFFont:
+		renderOpts.TTFFont = f
+	}
+	return renderOpts
+}
+
 // setPageFont はページにフォントを設定する（型アサーション対応）
 func setPageFont(page *Page, fontInterface interface{}, size float64) error {
 	// gopdf.StandardFontの場合
@@ -236,32 +511,55 @@ func drawPageText(page *Page, fontInterface interface{}, text string, x, y float
 	return page.DrawText(text, x, y)
 }
 
-// loadImageFromImageInfo はImageInfoからImageを作成
-func loadImageFromImageInfo(info ImageInfo) (*Image, error) {
-	switch info.Format {
-	case ImageFormatJPEG:
-		return LoadJPEG(bytes.NewReader(info.Data))
-	case ImageFormatPNG:
-		// PNGの場合、FlateDecode済みのrawデータの可能性がある
-		// とりあえずそのまま読み込みを試みる
-		return LoadPNG(bytes.NewReader(info.Data))
-	default:
-		return nil, fmt.Errorf("unsupported image format: %s", info.Format)
+// TranslateTextBlocks はTextBlocksのテキストを翻訳（BatchTranslatorの場合はまとめて1回で翻訳する）
+func TranslateTextBlocks(blocks []TextBlock, translator Translator) error {
+	if translator == nil {
+		return fmt.Errorf("translator is nil")
+	}
+
+	texts := make([]string, len(blocks))
+	for i := range blocks {
+		texts[i] = blocks[i].Text
+	}
+	translated, err := translateBatch(context.Background(), translator, texts)
+	if err != nil {
+		return fmt.Errorf("failed to translate text blocks: %w", err)
+	}
+	for i := range blocks {
+		blocks[i].Text = translated[i]
 	}
+
+	return nil
 }
 
-// TranslateTextBlocks はTextBlocksのテキストを翻訳
-func TranslateTextBlocks(blocks []TextBlock, translator Translator) error {
+// TranslateTables は表（PageLayout.Tables）のセルのテキストを翻訳する（空のセルは翻訳しない。BatchTranslatorの場合はまとめて1回で翻訳する）
+// DrawLayoutのDrawTablesで表を描く場合は、TextBlocksと合わせて翻訳する
+func TranslateTables(tables []TableBlock, translator Translator) error {
 	if translator == nil {
 		return fmt.Errorf("translator is nil")
 	}
 
-	for i := range blocks {
-		translated, err := translator.Translate(blocks[i].Text)
-		if err != nil {
-			return fmt.Errorf("translation failed for block %d: %w", i, err)
+	// 空でないセルをまとめて翻訳する
+	var cells []*TableCell
+	var texts []string
+	for i := range tables {
+		for j := range tables[i].Rows {
+			row := tables[i].Rows[j].Cells
+			for k := range row {
+				if strings.TrimSpace(row[k].Text) == "" {
+					continue
+				}
+				cells = append(cells, &row[k])
+				texts = append(texts, row[k].Text)
+			}
 		}
-		blocks[i].Text = translated
+	}
+	translated, err := translateBatch(context.Background(), translator, texts)
+	if err != nil {
+		return fmt.Errorf("failed to translate table cells: %w", err)
+	}
+	for i, cell := range cells {
+		cell.Text = translated[i]
 	}
 
 	return nil
@@ -0,0 +1,331 @@
+package gopdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// translatorTestPDF は1ページに1行ずつtextsを書いたPDFを作成する
+func translatorTestPDF(t *testing.T, texts ...string) []byte {
+	t.Helper()
+	doc := New()
+	for _, text := range texts {
+		page := doc.AddPage(PageSize{Width: 300, Height: 200}, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatalf("SetFont failed: %v", err)
+		}
+		if err := page.DrawText(text, 20, 150); err != nil {
+			t.Fatalf("DrawText failed: %v", err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTranslatePDFToWriter_ErrorPolicy(t *testing.T) {
+	errBroken := errors.New("translation service failed")
+	translator := TranslateFunc(func(text string) (string, error) {
+		if strings.Contains(text, "Broken") {
+			return "", errBroken
+		}
+		return strings.ToUpper(text), nil
+	})
+	input := translatorTestPDF(t, "First page", "Broken page", "Third page")
+
+	tests := []struct {
+		name      string
+		policy    TranslateErrorPolicy
+		wantPages []string // 出力したページのテキスト（nilの場合は出力しない）
+		wantProg  []int    // OnProgressに渡されたページ番号
+	}{
+		{"abort", TranslateErrorAbort, nil, []int{0}},
+		{"skip page", TranslateErrorSkipPage, []string{"FIRST PAGE", "THIRD PAGE"}, []int{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+			opts.Translator = translator
+			opts.ErrorPolicy = tt.policy
+			var progress []int
+			opts.OnProgress = func(page, total int) {
+				if total != 3 {
+					t.Errorf("OnProgress total = %d, want 3", total)
+				}
+				progress = append(progress, page)
+			}
+
+			var out bytes.Buffer
+			err := TranslatePDFToWriter(bytes.NewReader(input), &out, opts)
+			if !errors.Is(err, errBroken) {
+				t.Fatalf("TranslatePDFToWriter error = %v, want it to wrap the translation error", err)
+			}
+			var pageErr *PageError
+			if !errors.As(err, &pageErr) || pageErr.Page != 1 {
+				t.Errorf("error %v does not report page 1", err)
+			}
+			if fmt.Sprint(progress) != fmt.Sprint(tt.wantProg) {
+				t.Errorf("OnProgress pages = %v, want %v", progress, tt.wantProg)
+			}
+
+			if tt.wantPages == nil {
+				if out.Len() != 0 {
+					t.Errorf("aborted translation wrote %d bytes", out.Len())
+				}
+				return
+			}
+			var skipped *TranslationError
+			if !errors.As(err, &skipped) || skipped.Total != 3 || len(skipped.Pages) != 1 {
+				t.Errorf("error = %#v, want a TranslationError with 1 of 3 pages", err)
+			}
+			reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader failed: %v", err)
+			}
+			defer reader.Close()
+			if reader.PageCount() != len(tt.wantPages) {
+				t.Fatalf("output has %d pages, want %d", reader.PageCount(), len(tt.wantPages))
+			}
+			for i, want := range tt.wantPages {
+				layout, err := reader.ExtractPageLayout(i)
+				if err != nil {
+					t.Fatalf("ExtractPageLayout(%d) failed: %v", i, err)
+				}
+				var texts []string
+				for _, tb := range layout.TextBlocks {
+					texts = append(texts, tb.Text)
+				}
+				if got := strings.Join(texts, " "); got != want {
+					t.Errorf("page %d text = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslatePDFToWriter_RenderErrorRemovesPage(t *testing.T) {
+	// 描画に失敗したページ（フォントの指定なし）は、描きかけのまま出力しない
+	input := translatorTestPDF(t, "Only page")
+	opts := DefaultPDFTranslatorOptions(nil, "")
+	opts.ErrorPolicy = TranslateErrorSkipPage
+
+	reader, err := OpenReader(bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	doc, skipped, err := translateDocument(context.Background(), reader, opts)
+	if err != nil {
+		t.Fatalf("translateDocument failed: %v", err)
+	}
+	if skipped == nil || len(skipped.Pages) != 1 {
+		t.Fatalf("skipped = %v, want 1 page", skipped)
+	}
+	if len(doc.pages) != 0 {
+		t.Errorf("document has %d pages, want 0", len(doc.pages))
+	}
+}
+
+// contextTranslatorFunc は関数型ContextTranslator
+type contextTranslatorFunc func(ctx context.Context, text string) (string, error)
+
+func (f contextTranslatorFunc) Translate(text string) (string, error) {
+	return f(context.Background(), text)
+}
+
+func (f contextTranslatorFunc) TranslateContext(ctx context.Context, text string) (string, error) {
+	return f(ctx, text)
+}
+
+func TestTranslatePDFToWriterContext_Workers(t *testing.T) {
+	// 3ページの翻訳が同時に呼ばれるまで、どの翻訳も返さない
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	translator := TranslateFunc(func(text string) (string, error) {
+		mu.Lock()
+		if calls++; calls == 3 {
+			close(release)
+		}
+		mu.Unlock()
+		select {
+		case <-release:
+			return strings.ToUpper(text), nil
+		case <-time.After(5 * time.Second):
+			return "", errors.New("pages were not translated concurrently")
+		}
+	})
+
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	opts.Translator = translator
+	opts.Workers = 3
+	var progress []int
+	opts.OnProgress = func(page, total int) { progress = append(progress, page) }
+
+	var out bytes.Buffer
+	input := translatorTestPDF(t, "First page", "Second page", "Third page")
+	if err := TranslatePDFToWriterContext(context.Background(), bytes.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriterContext failed: %v", err)
+	}
+	if fmt.Sprint(progress) != "[0 1 2]" {
+		t.Errorf("OnProgress pages = %v, want [0 1 2]", progress)
+	}
+
+	// ページの順序は元のPDFのまま
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	for i, want := range []string{"FIRST PAGE", "SECOND PAGE", "THIRD PAGE"} {
+		layout, err := reader.ExtractPageLayout(i)
+		if err != nil {
+			t.Fatalf("ExtractPageLayout(%d) failed: %v", i, err)
+		}
+		if len(layout.TextBlocks) != 1 || layout.TextBlocks[0].Text != want {
+			t.Errorf("page %d = %+v, want %q", i, layout.TextBlocks, want)
+		}
+	}
+}
+
+func TestTranslatePDFToWriterContext_Cancel(t *testing.T) {
+	// 2ページ目の翻訳中に取り消すと、翻訳は止まり何も出力しない
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	translator := contextTranslatorFunc(func(ctx context.Context, text string) (string, error) {
+		if strings.Contains(text, "Second") {
+			cancel()
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return strings.ToUpper(text), nil
+	})
+
+	for _, policy := range []TranslateErrorPolicy{TranslateErrorAbort, TranslateErrorSkipPage} {
+		opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+		opts.Translator = translator
+		opts.ErrorPolicy = policy
+		var out bytes.Buffer
+		input := translatorTestPDF(t, "First page", "Second page", "Third page")
+		err := TranslatePDFToWriterContext(ctx, bytes.NewReader(input), &out, opts)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("policy %d: error = %v, want context.Canceled", policy, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("policy %d: canceled translation wrote %d bytes", policy, out.Len())
+		}
+	}
+}
+
+func TestTranslatePDFToWriter_Selection(t *testing.T) {
+	// 1ページ目はヘッダー（y=170）と本文（y=100）、2ページ目は本文だけ
+	doc := New()
+	for _, lines := range [][]string{{"Header", "Body one"}, {"Body two"}} {
+		page := doc.AddPage(PageSize{Width: 300, Height: 200}, Portrait)
+		if err := page.SetFont(FontHelvetica, 12); err != nil {
+			t.Fatal(err)
+		}
+		for i, text := range lines {
+			if err := page.DrawText(text, 20, 170-float64(i)*70); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var input bytes.Buffer
+	if err := doc.WriteTo(&input); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pages   []int
+		regions []Rectangle
+		want    string
+	}{
+		{"all", nil, nil, "[HEADER BODY ONE] [BODY TWO]"},
+		{"pages", []int{1}, nil, "[Header Body one] [BODY TWO]"},
+		{"regions", nil, []Rectangle{{X: 0, Y: 0, Width: 300, Height: 150}}, "[Header BODY ONE] [Body two]"},
+		{"pages and regions", []int{1}, []Rectangle{{X: 0, Y: 150, Width: 300, Height: 50}}, "[Header Body one] [BODY TWO]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+			opts.Translator = TranslateFunc(func(text string) (string, error) {
+				return strings.ToUpper(text), nil
+			})
+			opts.Pages = tt.pages
+			opts.Regions = tt.regions
+
+			var out bytes.Buffer
+			if err := TranslatePDFToWriter(bytes.NewReader(input.Bytes()), &out, opts); err != nil {
+				t.Fatalf("TranslatePDFToWriter failed: %v", err)
+			}
+			reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader failed: %v", err)
+			}
+			defer reader.Close()
+			var pages []string
+			for i := 0; i < reader.PageCount(); i++ {
+				pl, err := reader.ExtractPageLayout(i)
+				if err != nil {
+					t.Fatalf("ExtractPageLayout failed: %v", err)
+				}
+				var texts []string
+				for _, tb := range pl.SortedContentBlocks() {
+					if tb, ok := tb.(TextBlock); ok {
+						texts = append(texts, tb.Text)
+					}
+				}
+				pages = append(pages, fmt.Sprint(texts))
+			}
+			if got := strings.Join(pages, " "); got != tt.want {
+				t.Errorf("pages = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	opts.Translator = TranslateFunc(func(text string) (string, error) { return text, nil })
+	opts.Pages = []int{2}
+	if err := TranslatePDFToWriter(bytes.NewReader(input.Bytes()), io.Discard, opts); err == nil {
+		t.Error("TranslatePDFToWriter should fail for a page out of range")
+	}
+}
+
+func TestTranslatePDFToWriter_MirrorLayout(t *testing.T) {
+	opts := DefaultPDFTranslatorOptions(FontHelvetica, "Helvetica")
+	opts.Translator = TranslateFunc(func(text string) (string, error) { return "RTL", nil })
+	opts.MirrorLayout = true
+
+	// 元の行は左端（X=20）から始まる。反転したブロックの中で右揃えにするので、訳文はページの右端に寄る
+	var out bytes.Buffer
+	if err := TranslatePDFToWriter(bytes.NewReader(translatorTestPDF(t, "Left aligned line")), &out, opts); err != nil {
+		t.Fatalf("TranslatePDFToWriter failed: %v", err)
+	}
+	reader, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer reader.Close()
+	pl, err := reader.ExtractPageLayout(0)
+	if err != nil {
+		t.Fatalf("ExtractPageLayout failed: %v", err)
+	}
+	if len(pl.TextBlocks) != 1 {
+		t.Fatalf("got %d text blocks, want 1", len(pl.TextBlocks))
+	}
+	if r := pl.TextBlocks[0].Rect; r.X+r.Width < 270 || r.X+r.Width > 285 {
+		t.Errorf("translated block = %+v, want its right edge near x=280", r)
+	}
+}
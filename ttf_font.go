@@ -1,6 +1,8 @@
 package gopdf
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/ryomak/gopdf/internal/font"
@@ -55,6 +57,41 @@ func (f *TTFFont) TextWidth(text string, fontSize float64) (float64, error) {
 	return f.internal.TextWidth(text, fontSize)
 }
 
+// encodeGlyphs converts UTF-8 text to a hex string of glyph indices and records the used glyphs
+// for subsetting and the ToUnicode CMap.
+func (f *TTFFont) encodeGlyphs(text string) (string, error) {
+	var result strings.Builder
+
+	for _, r := range text {
+		// Get the glyph index for this character
+		glyphIndex, err := f.internal.GetGlyphIndex(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to get glyph index for character %c (U+%04X): %w", r, r, err)
+		}
+
+		// Record glyph usage for ToUnicode CMap generation
+		f.glyphsMutex.Lock()
+		f.usedGlyphs[uint16(glyphIndex)] = r
+		f.glyphsMutex.Unlock()
+
+		// Convert glyph index to 4-digit hex string
+		fmt.Fprintf(&result, "%04X", glyphIndex)
+	}
+
+	return result.String(), nil
+}
+
+// usedGlyphsSnapshot returns a copy of the glyphs used so far (glyph index → Unicode rune).
+func (f *TTFFont) usedGlyphsSnapshot() map[uint16]rune {
+	f.glyphsMutex.Lock()
+	defer f.glyphsMutex.Unlock()
+	usedGlyphs := make(map[uint16]rune, len(f.usedGlyphs))
+	for k, v := range f.usedGlyphs {
+		usedGlyphs[k] = v
+	}
+	return usedGlyphs
+}
+
 // DefaultJapaneseFont は埋め込まれた日本語フォント（Koruri）を返す
 //
 // 初回呼び出し時にフォントを読み込み、以降はキャッシュされた結果を返します。
@@ -5,6 +5,8 @@ import (
 	"os"
 	"runtime"
 	"testing"
+
+	"github.com/ryomak/gopdf/internal/core"
 )
 
 // getTestTTFPath returns a path to a system TTF font for testing
@@ -311,3 +313,85 @@ func TestTTFFont_UnicodeText(t *testing.T) {
 func contains(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))
 }
+
+func TestPage_SetFontAfterTTFFont(t *testing.T) {
+	font, err := DefaultJapaneseFont()
+	if err != nil {
+		t.Fatalf("DefaultJapaneseFont failed: %v", err)
+	}
+
+	page := New().AddPage(PageSizeA4, Portrait)
+	if err := page.SetTTFFont(font, 12); err != nil {
+		t.Fatalf("SetTTFFont failed: %v", err)
+	}
+	if err := page.SetFont(FontHelvetica, 10); err != nil {
+		t.Fatalf("SetFont failed: %v", err)
+	}
+
+	// Text after switching back is drawn with the standard font
+	if page.currentTTFFont != nil {
+		t.Error("currentTTFFont should be cleared")
+	}
+	if err := page.DrawText("Hello", 100, 700); err != nil {
+		t.Fatalf("DrawText failed: %v", err)
+	}
+	if content := page.content.String(); !contains(content, "(Hello) Tj") {
+		t.Errorf("text is not drawn with the standard font:\n%s", content)
+	}
+}
+
+func TestDocument_TTFGlyphWidths(t *testing.T) {
+	font, err := DefaultJapaneseFont()
+	if err != nil {
+		t.Fatalf("DefaultJapaneseFont failed: %v", err)
+	}
+	doc := New()
+	page := doc.AddPage(PageSizeA4, Portrait)
+	if err := page.SetTTFFont(font, 12); err != nil {
+		t.Fatalf("SetTTFFont failed: %v", err)
+	}
+	if err := page.DrawText("iW日本", 100, 700); err != nil {
+		t.Fatalf("DrawText failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// The CIDFont carries the widths of the used glyphs
+	pageDict, err := r.r.GetPage(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := r.r.Resolve(pageDict[core.Name("Resources")]).(core.Dictionary)
+	fonts := r.r.Resolve(resources[core.Name("Font")]).(core.Dictionary)
+	var widths core.Array
+	for _, ref := range fonts {
+		type0 := r.r.Resolve(ref).(core.Dictionary)
+		descendants := r.r.Resolve(type0[core.Name("DescendantFonts")]).(core.Array)
+		cidFont := r.r.Resolve(descendants[0]).(core.Dictionary)
+		widths, _ = r.r.Resolve(cidFont[core.Name("W")]).(core.Array)
+	}
+	byGlyph := make(map[rune]int)
+	for i := 0; i+1 < len(widths); i += 2 {
+		gid := int(widths[i].(core.Integer))
+		width := int(widths[i+1].(core.Array)[0].(core.Integer))
+		for _, ch := range "iW日本" {
+			if idx, err := font.internal.GetGlyphIndex(ch); err == nil && int(idx) == gid {
+				byGlyph[ch] = width
+			}
+		}
+	}
+	if len(byGlyph) != 4 {
+		t.Fatalf("W = %v, want the widths of 4 glyphs", widths)
+	}
+	if byGlyph['i'] >= byGlyph['W'] || byGlyph['日'] != 1000 {
+		t.Errorf("widths = %v, want proportional Latin glyphs and full-width CJK glyphs", byGlyph)
+	}
+}
@@ -0,0 +1,106 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PDFVersion は出力するPDFのバージョン（ヘッダーの %PDF-x.y）
+type PDFVersion int
+
+const (
+	PDFVersion14 PDFVersion = 14
+	PDFVersion15 PDFVersion = 15
+	PDFVersion16 PDFVersion = 16
+	PDFVersion17 PDFVersion = 17 // デフォルト
+	PDFVersion20 PDFVersion = 20
+)
+
+// String はバージョンを "1.7" の形式で返す
+func (v PDFVersion) String() string {
+	return fmt.Sprintf("%d.%d", int(v)/10, int(v)%10)
+}
+
+// SetPDFVersion は出力するPDFのバージョンを設定する（1.4〜2.0、デフォルトは1.7）
+// WriteTo時に、使用している機能がバージョンで使えるかを検査し、使えない機能があれば *VersionError を返す
+// 検査する機能はAES-128暗号化（1.6〜1.7）、AES-256暗号化（1.7以上）、RC4暗号化（1.4〜1.7）
+// 設計書: docs/pdf_version_design.md
+func (d *Document) SetPDFVersion(v PDFVersion) error {
+	switch v {
+	case PDFVersion14, PDFVersion15, PDFVersion16, PDFVersion17, PDFVersion20:
+	default:
+		return fmt.Errorf("unsupported PDF version: %s", v)
+	}
+	d.version = v
+	return nil
+}
+
+// PDFVersion は出力するPDFのバージョンを返す
+func (d *Document) PDFVersion() PDFVersion {
+	if d.version == 0 {
+		return PDFVersion17
+	}
+	return d.version
+}
+
+// VersionFeature はバージョンによって使えるかが決まる機能
+type VersionFeature struct {
+	Feature    string     // 機能の説明
+	Required   PDFVersion // 必要な最小バージョン
+	Deprecated PDFVersion // 非推奨になり使えなくなるバージョン（0の場合は制限なし）
+}
+
+// supportedBy は機能がバージョンvで使えるかを返す
+func (f VersionFeature) supportedBy(v PDFVersion) bool {
+	return f.Required <= v && (f.Deprecated == 0 || v < f.Deprecated)
+}
+
+// VersionError は設定されたバージョンでは使えない機能の一覧
+type VersionError struct {
+	Version  PDFVersion
+	Features []VersionFeature
+}
+
+func (e *VersionError) Error() string {
+	features := make([]string, len(e.Features))
+	for i, f := range e.Features {
+		if f.Required > e.Version {
+			features[i] = fmt.Sprintf("%s (requires PDF %s)", f.Feature, f.Required)
+		} else {
+			features[i] = fmt.Sprintf("%s (deprecated in PDF %s)", f.Feature, f.Deprecated)
+		}
+	}
+	return fmt.Sprintf("PDF %s does not support: %s", e.Version, strings.Join(features, ", "))
+}
+
+// versionFeatures は文書が使用している、バージョンに依存する機能を返す
+func (d *Document) versionFeatures() []VersionFeature {
+	var features []VersionFeature
+	if d.encryption != nil {
+		switch d.encryption.GetRevision() {
+		case 6:
+			// PDF 2.0の機能だが、1.7ではAdobe拡張レベル8として出力できる
+			features = append(features, VersionFeature{Feature: "AES-256 encryption", Required: PDFVersion17})
+		case 4:
+			features = append(features, VersionFeature{Feature: "AES-128 encryption", Required: PDFVersion16, Deprecated: PDFVersion20})
+		default:
+			features = append(features, VersionFeature{Feature: "RC4 encryption", Required: PDFVersion14, Deprecated: PDFVersion20})
+		}
+	}
+	return features
+}
+
+// checkVersion は設定されたバージョンで使えない機能を報告する
+func (d *Document) checkVersion() error {
+	version := d.PDFVersion()
+	var unsupported []VersionFeature
+	for _, f := range d.versionFeatures() {
+		if !f.supportedBy(version) {
+			unsupported = append(unsupported, f)
+		}
+	}
+	if len(unsupported) > 0 {
+		return &VersionError{Version: version, Features: unsupported}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocumentSetPDFVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version PDFVersion
+		wantErr bool
+	}{
+		{"1.4", PDFVersion14, false},
+		{"1.7", PDFVersion17, false},
+		{"2.0", PDFVersion20, false},
+		{"1.3", PDFVersion(13), true},
+		{"1.8", PDFVersion(18), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			err := doc.SetPDFVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetPDFVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			want := tt.version
+			if tt.wantErr {
+				want = PDFVersion17
+			}
+			if got := doc.PDFVersion(); got != want {
+				t.Errorf("PDFVersion() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDocumentWriteTo_PDFVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    PDFVersion
+		encryption *EncryptionOptions
+		wantHeader string
+		wantErr    string // 空の場合は成功
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "default",
+			wantHeader: "%PDF-1.7",
+			notWant:    []string{"/ID"},
+		},
+		{
+			name:       "1.4 with 128-bit RC4",
+			version:    PDFVersion14,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 128},
+			wantHeader: "%PDF-1.4",
+		},
+		{
+			name:       "1.5 rejects AES-128",
+			version:    PDFVersion15,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 128, Algorithm: EncryptionAES},
+			wantErr:    "AES-128 encryption (requires PDF 1.6)",
+		},
+		{
+			name:       "1.6 rejects AES-256",
+			version:    PDFVersion16,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 256},
+			wantErr:    "AES-256 encryption (requires PDF 1.7)",
+		},
+		{
+			name:       "1.7 declares the AES-256 extension",
+			version:    PDFVersion17,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 256},
+			wantHeader: "%PDF-1.7",
+			want:       []string{"/ADBE"},
+		},
+		{
+			name:       "2.0 uses AES-256 natively",
+			version:    PDFVersion20,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 256},
+			wantHeader: "%PDF-2.0",
+			notWant:    []string{"/ADBE"},
+		},
+		{
+			name:       "2.0 rejects RC4",
+			version:    PDFVersion20,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 128},
+			wantErr:    "RC4 encryption (deprecated in PDF 2.0)",
+		},
+		{
+			name:       "2.0 rejects 40-bit RC4",
+			version:    PDFVersion20,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 40},
+			wantErr:    "RC4 encryption (deprecated in PDF 2.0)",
+		},
+		{
+			name:       "2.0 rejects AES-128",
+			version:    PDFVersion20,
+			encryption: &EncryptionOptions{UserPassword: "u", KeyLength: 128, Algorithm: EncryptionAES},
+			wantErr:    "AES-128 encryption (deprecated in PDF 2.0)",
+		},
+		{
+			name:       "2.0 requires a file identifier",
+			version:    PDFVersion20,
+			wantHeader: "%PDF-2.0",
+			want:       []string{"/ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			if tt.version != 0 {
+				if err := doc.SetPDFVersion(tt.version); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.encryption != nil {
+				if err := doc.SetEncryption(*tt.encryption); err != nil {
+					t.Fatal(err)
+				}
+			}
+			doc.AddPage(PageSizeA4, Portrait)
+
+			var buf bytes.Buffer
+			err := doc.WriteTo(&buf)
+			if tt.wantErr != "" {
+				var versionErr *VersionError
+				if !errors.As(err, &versionErr) {
+					t.Fatalf("WriteTo() error = %v, want *VersionError", err)
+				}
+				if versionErr.Version != tt.version || len(versionErr.Features) != 1 {
+					t.Errorf("VersionError = %+v, want one feature unsupported in PDF %s", versionErr, tt.version)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteTo() failed: %v", err)
+			}
+
+			pdf := buf.String()
+			if !strings.HasPrefix(pdf, tt.wantHeader+"\n") {
+				t.Errorf("header = %q, want %q", strings.SplitN(pdf, "\n", 2)[0], tt.wantHeader)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(pdf, want) {
+					t.Errorf("output does not contain %s", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(pdf, notWant) {
+					t.Errorf("output contains %s", notWant)
+				}
+			}
+
+			r, err := OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("OpenReader() failed: %v", err)
+			}
+			defer r.Close()
+			if tt.encryption != nil {
+				if err := r.AuthenticateWithPassword(tt.encryption.UserPassword); err != nil {
+					t.Fatalf("AuthenticateWithPassword() failed: %v", err)
+				}
+			}
+			if r.PageCount() != 1 {
+				t.Errorf("PageCount() = %d, want 1", r.PageCount())
+			}
+		})
+	}
+}
+
+func TestDocumentWriteTo_PDFVersionConformance(t *testing.T) {
+	doc := New()
+	doc.SetConformance(PDFA2B)
+	doc.SetPDFVersion(PDFVersion20)
+	doc.AddPage(PageSizeA4, Portrait)
+
+	err := doc.WriteTo(&bytes.Buffer{})
+	var confErr *ConformanceError
+	if !errors.As(err, &confErr) {
+		t.Fatalf("WriteTo() error = %v, want *ConformanceError", err)
+	}
+	if len(confErr.Violations) != 1 || !strings.Contains(confErr.Violations[0], "PDF 2.0 is not allowed") {
+		t.Errorf("Violations = %q", confErr.Violations)
+	}
+}
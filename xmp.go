@@ -0,0 +1,556 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryomak/gopdf/internal/core"
+)
+
+// XMP namespace URIs
+const (
+	nsRDF     = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	nsXMLLang = "http://www.w3.org/XML/1998/namespace"
+	nsDC      = "http://purl.org/dc/elements/1.1/"
+	nsXMP     = "http://ns.adobe.com/xap/1.0/"
+	nsPDF     = "http://ns.adobe.com/pdf/1.3/"
+	nsPDFX    = "http://ns.adobe.com/pdfx/1.3/"
+
+	nsPDFAID        = "http://www.aiim.org/pdfa/ns/id/"
+	nsPDFAExtension = "http://www.aiim.org/pdfa/ns/extension/"
+	nsPDFASchema    = "http://www.aiim.org/pdfa/ns/schema#"
+	nsPDFAProperty  = "http://www.aiim.org/pdfa/ns/property#"
+	nsPDFUAID       = "http://www.aiim.org/pdfua/ns/id/"
+)
+
+// reservedXMPPrefixes are the namespace prefixes used by the generated packet
+var reservedXMPPrefixes = map[string]bool{
+	"x": true, "rdf": true, "xml": true, "xmlns": true,
+	"dc": true, "xmp": true, "pdf": true, "pdfx": true,
+	"pdfaid": true, "pdfaExtension": true, "pdfaSchema": true, "pdfaProperty": true,
+	"pdfuaid": true,
+}
+
+// xmpPadding is the whitespace reserved after the packet so it can be edited in place
+const xmpPadding = 2048
+
+// XMPProperty is a simple (text) property in a custom XMP namespace.
+type XMPProperty struct {
+	Namespace string // Namespace URI (e.g. "http://example.com/ns/invoice/1.0/")
+	Prefix    string // Namespace prefix (e.g. "inv")
+	Name      string // Property name
+	Value     string // Property value
+}
+
+// withDefaults returns a copy of the metadata with the values that
+// createInfoDict fills in (Producer, CreationDate) resolved, so the Info
+// dictionary and the XMP packet carry the same values.
+func (m *Metadata) withDefaults() *Metadata {
+	if m == nil {
+		return nil
+	}
+	out := *m
+	if out.Producer == "" {
+		out.Producer = "gopdf"
+	}
+	if out.CreationDate.IsZero() {
+		out.CreationDate = time.Now()
+	}
+	return &out
+}
+
+// createXMPStream creates the catalog /Metadata stream (uncompressed XML)
+func createXMPStream(metadata *Metadata, conformance Conformance) (*core.Stream, error) {
+	packet, err := buildXMP(metadata, conformance)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Stream{
+		Dict: core.Dictionary{
+			core.Name("Type"):    core.Name("Metadata"),
+			core.Name("Subtype"): core.Name("XML"),
+			core.Name("Length"):  core.Integer(len(packet)),
+		},
+		Data: packet,
+	}, nil
+}
+
+// buildXMP serializes metadata as an XMP packet.
+// Info dictionary fields map to dc, xmp and pdf properties, Custom fields to
+// the pdfx namespace, and XMP properties to their own namespaces.
+// For PDF/A conformance the packet also carries the pdfaid identification and
+// extension schemas describing the pdfx and custom properties; for PDF/UA the
+// pdfuaid identification.
+func buildXMP(metadata *Metadata, conformance Conformance) ([]byte, error) {
+	part, level, pdfa := conformance.pdfaID()
+	uaPart, pdfua := conformance.pdfuaID()
+
+	namespaces := map[string]string{} // prefix -> URI for custom namespaces
+	for _, p := range metadata.XMP {
+		if err := validateXMPProperty(p); err != nil {
+			return nil, err
+		}
+		if uri, ok := namespaces[p.Prefix]; ok && uri != p.Namespace {
+			return nil, fmt.Errorf("XMP prefix %q is bound to both %q and %q", p.Prefix, uri, p.Namespace)
+		}
+		namespaces[p.Prefix] = p.Namespace
+	}
+
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	fmt.Fprintf(&b, " <rdf:RDF xmlns:rdf=\"%s\">\n", nsRDF)
+	fmt.Fprintf(&b, "  <rdf:Description rdf:about=\"\"\n    xmlns:dc=\"%s\"\n    xmlns:xmp=\"%s\"\n    xmlns:pdf=\"%s\"", nsDC, nsXMP, nsPDF)
+	if len(metadata.Custom) > 0 {
+		fmt.Fprintf(&b, "\n    xmlns:pdfx=\"%s\"", nsPDFX)
+	}
+	if pdfa {
+		fmt.Fprintf(&b, "\n    xmlns:pdfaid=\"%s\"", nsPDFAID)
+	}
+	if pdfua {
+		fmt.Fprintf(&b, "\n    xmlns:pdfuaid=\"%s\"", nsPDFUAID)
+	}
+	for _, prefix := range sortedKeys(namespaces) {
+		fmt.Fprintf(&b, "\n    xmlns:%s=\"%s\"", prefix, xmlEscape(namespaces[prefix]))
+	}
+	b.WriteString(">\n")
+
+	if pdfa {
+		writeXMPSimple(&b, "pdfaid:part", strconv.Itoa(part))
+		writeXMPSimple(&b, "pdfaid:conformance", level)
+	}
+	if pdfua {
+		writeXMPSimple(&b, "pdfuaid:part", strconv.Itoa(uaPart))
+	}
+
+	b.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	if metadata.Title != "" {
+		writeXMPAlt(&b, "dc:title", metadata.Title)
+	}
+	if metadata.Author != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(metadata.Author))
+	}
+	if metadata.Subject != "" {
+		writeXMPAlt(&b, "dc:description", metadata.Subject)
+	}
+	if metadata.Keywords != "" {
+		writeXMPSimple(&b, "pdf:Keywords", metadata.Keywords)
+	}
+	if metadata.Producer != "" {
+		writeXMPSimple(&b, "pdf:Producer", metadata.Producer)
+	}
+	if metadata.Creator != "" {
+		writeXMPSimple(&b, "xmp:CreatorTool", metadata.Creator)
+	}
+	if !metadata.CreationDate.IsZero() {
+		writeXMPSimple(&b, "xmp:CreateDate", formatXMPDate(metadata.CreationDate))
+	}
+	if !metadata.ModDate.IsZero() {
+		writeXMPSimple(&b, "xmp:ModifyDate", formatXMPDate(metadata.ModDate))
+	}
+	writeXMPSimple(&b, "xmp:MetadataDate", formatXMPDate(time.Now()))
+
+	for _, key := range sortedKeys(metadata.Custom) {
+		if isXMLName(key) && metadata.Custom[key] != "" {
+			writeXMPSimple(&b, "pdfx:"+key, metadata.Custom[key])
+		}
+	}
+	for _, p := range metadata.XMP {
+		writeXMPSimple(&b, p.Prefix+":"+p.Name, p.Value)
+	}
+
+	b.WriteString("  </rdf:Description>\n")
+	if pdfa {
+		writeXMPExtensionSchemas(&b, metadata)
+	}
+	b.WriteString(" </rdf:RDF>\n</x:xmpmeta>\n")
+	for i := 0; i < xmpPadding/64; i++ {
+		b.WriteString(strings.Repeat(" ", 63) + "\n")
+	}
+	b.WriteString("<?xpacket end=\"w\"?>")
+
+	return b.Bytes(), nil
+}
+
+// xmpSchema is a custom namespace described by a PDF/A extension schema
+type xmpSchema struct {
+	namespace  string
+	prefix     string
+	properties []string
+}
+
+// writeXMPExtensionSchemas describes the pdfx and custom namespaces used in the
+// packet, as PDF/A requires for properties outside the predefined schemas.
+func writeXMPExtensionSchemas(b *bytes.Buffer, metadata *Metadata) {
+	var schemas []*xmpSchema
+	pdfx := &xmpSchema{namespace: nsPDFX, prefix: "pdfx"}
+	for _, key := range sortedKeys(metadata.Custom) {
+		if isXMLName(key) && metadata.Custom[key] != "" {
+			pdfx.properties = append(pdfx.properties, key)
+		}
+	}
+	if len(pdfx.properties) > 0 {
+		schemas = append(schemas, pdfx)
+	}
+	byPrefix := map[string]*xmpSchema{}
+	for _, p := range metadata.XMP {
+		s, ok := byPrefix[p.Prefix]
+		if !ok {
+			s = &xmpSchema{namespace: p.Namespace, prefix: p.Prefix}
+			byPrefix[p.Prefix] = s
+			schemas = append(schemas, s)
+		}
+		if !slices.Contains(s.properties, p.Name) {
+			s.properties = append(s.properties, p.Name)
+		}
+	}
+	if len(schemas) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "  <rdf:Description rdf:about=\"\"\n    xmlns:pdfaExtension=\"%s\"\n    xmlns:pdfaSchema=\"%s\"\n    xmlns:pdfaProperty=\"%s\">\n", nsPDFAExtension, nsPDFASchema, nsPDFAProperty)
+	b.WriteString("   <pdfaExtension:schemas>\n    <rdf:Bag>\n")
+	for _, s := range schemas {
+		b.WriteString("     <rdf:li rdf:parseType=\"Resource\">\n")
+		fmt.Fprintf(b, "      <pdfaSchema:schema>%s</pdfaSchema:schema>\n", xmlEscape(s.prefix+" properties"))
+		fmt.Fprintf(b, "      <pdfaSchema:namespaceURI>%s</pdfaSchema:namespaceURI>\n", xmlEscape(s.namespace))
+		fmt.Fprintf(b, "      <pdfaSchema:prefix>%s</pdfaSchema:prefix>\n", s.prefix)
+		b.WriteString("      <pdfaSchema:property>\n       <rdf:Seq>\n")
+		for _, name := range s.properties {
+			b.WriteString("        <rdf:li rdf:parseType=\"Resource\">\n")
+			fmt.Fprintf(b, "         <pdfaProperty:name>%s</pdfaProperty:name>\n", name)
+			b.WriteString("         <pdfaProperty:valueType>Text</pdfaProperty:valueType>\n")
+			b.WriteString("         <pdfaProperty:category>external</pdfaProperty:category>\n")
+			fmt.Fprintf(b, "         <pdfaProperty:description>%s</pdfaProperty:description>\n", name)
+			b.WriteString("        </rdf:li>\n")
+		}
+		b.WriteString("       </rdf:Seq>\n      </pdfaSchema:property>\n     </rdf:li>\n")
+	}
+	b.WriteString("    </rdf:Bag>\n   </pdfaExtension:schemas>\n  </rdf:Description>\n")
+}
+
+// validateXMPProperty checks that a custom property can be serialized
+func validateXMPProperty(p XMPProperty) error {
+	if p.Namespace == "" {
+		return fmt.Errorf("XMP property %q has no namespace", p.Name)
+	}
+	if !isXMLName(p.Prefix) || strings.HasPrefix(strings.ToLower(p.Prefix), "xml") || reservedXMPPrefixes[p.Prefix] {
+		return fmt.Errorf("invalid or reserved XMP prefix: %q", p.Prefix)
+	}
+	if !isXMLName(p.Name) {
+		return fmt.Errorf("invalid XMP property name: %q", p.Name)
+	}
+	return nil
+}
+
+// isXMLName reports whether s is a valid XML name without a colon
+func isXMLName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r > 0x7F:
+		case i > 0 && (r == '-' || r == '.' || (r >= '0' && r <= '9')):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func writeXMPSimple(b *bytes.Buffer, name, value string) {
+	fmt.Fprintf(b, "   <%s>%s</%s>\n", name, xmlEscape(value), name)
+}
+
+func writeXMPAlt(b *bytes.Buffer, name, value string) {
+	fmt.Fprintf(b, "   <%s><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></%s>\n", name, xmlEscape(value), name)
+}
+
+// xmlEscape escapes text for use in XML content and attribute values
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// formatXMPDate formats a time as an XMP (ISO 8601) date
+func formatXMPDate(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05-07:00")
+}
+
+// parseXMPDate parses the ISO 8601 date forms allowed in XMP
+func parseXMPDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04",
+		"2006-01-02",
+		"2006-01",
+		"2006",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid XMP date: %q", s)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergeMetadata fills the fields of dst that are empty with the values from src
+func mergeMetadata(dst, src *Metadata) {
+	for _, f := range []struct{ dst, src *string }{
+		{&dst.Title, &src.Title},
+		{&dst.Author, &src.Author},
+		{&dst.Subject, &src.Subject},
+		{&dst.Keywords, &src.Keywords},
+		{&dst.Creator, &src.Creator},
+		{&dst.Producer, &src.Producer},
+	} {
+		if *f.dst == "" {
+			*f.dst = *f.src
+		}
+	}
+	if dst.CreationDate.IsZero() {
+		dst.CreationDate = src.CreationDate
+	}
+	if dst.ModDate.IsZero() {
+		dst.ModDate = src.ModDate
+	}
+	for key, value := range src.Custom {
+		if dst.Custom == nil {
+			dst.Custom = make(map[string]string)
+		}
+		if _, ok := dst.Custom[key]; !ok {
+			dst.Custom[key] = value
+		}
+	}
+	dst.XMP = append(dst.XMP, src.XMP...)
+}
+
+// xmpValue is a property value read from an XMP packet
+type xmpValue struct {
+	text  string   // simple value
+	items []string // rdf:Seq / rdf:Bag items
+	alt   string   // x-default (or first) rdf:Alt item
+}
+
+// String returns the value as a single string (array items are joined with ", ")
+func (v xmpValue) String() string {
+	switch {
+	case v.alt != "":
+		return v.alt
+	case len(v.items) > 0:
+		return strings.Join(v.items, ", ")
+	default:
+		return strings.TrimSpace(v.text)
+	}
+}
+
+// parseXMP extracts metadata from an XMP packet.
+// Properties outside the dc, xmp, pdf and pdfx namespaces are returned in XMP.
+func parseXMP(data []byte) (Metadata, error) {
+	metadata := Metadata{Custom: make(map[string]string)}
+	prefixes := map[string]string{} // URI -> prefix declared in the packet
+
+	set := func(name xml.Name, value xmpValue) {
+		s := value.String()
+		switch name.Space {
+		case nsDC:
+			switch name.Local {
+			case "title":
+				metadata.Title = s
+			case "creator":
+				metadata.Author = s
+			case "description":
+				metadata.Subject = s
+			case "subject":
+				if metadata.Keywords == "" {
+					metadata.Keywords = s
+				}
+			}
+		case nsPDF:
+			switch name.Local {
+			case "Keywords":
+				metadata.Keywords = s
+			case "Producer":
+				metadata.Producer = s
+			}
+		case nsXMP:
+			switch name.Local {
+			case "CreatorTool":
+				metadata.Creator = s
+			case "CreateDate":
+				if t, err := parseXMPDate(s); err == nil {
+					metadata.CreationDate = t
+				}
+			case "ModifyDate":
+				if t, err := parseXMPDate(s); err == nil {
+					metadata.ModDate = t
+				}
+			}
+		case nsPDFX:
+			if s != "" {
+				metadata.Custom[name.Local] = s
+			}
+		case nsRDF, nsXMLLang, "":
+		default:
+			if prefix, ok := prefixes[name.Space]; ok && !isWellKnownXMPNamespace(name.Space) {
+				metadata.XMP = append(metadata.XMP, XMPProperty{Namespace: name.Space, Prefix: prefix, Name: name.Local, Value: s})
+			}
+		}
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return metadata, fmt.Errorf("failed to parse XMP: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Space == "xmlns" {
+				prefixes[attr.Value] = attr.Name.Local
+			}
+		}
+		if start.Name.Space != nsRDF || start.Name.Local != "Description" {
+			continue
+		}
+
+		// Attributes of rdf:Description are properties in abbreviated form
+		for _, attr := range start.Attr {
+			if attr.Name.Space != "xmlns" {
+				set(attr.Name, xmpValue{text: attr.Value})
+			}
+		}
+		if err := parseXMPDescription(dec, prefixes, set); err != nil {
+			return metadata, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// parseXMPDescription reads the property elements of an rdf:Description
+func parseXMPDescription(dec *xml.Decoder, prefixes map[string]string, set func(xml.Name, xmpValue)) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse XMP: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					prefixes[attr.Value] = attr.Name.Local
+				}
+			}
+			value, err := readXMPValue(dec)
+			if err != nil {
+				return err
+			}
+			set(t.Name, value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readXMPValue reads a property value up to the end of the property element
+func readXMPValue(dec *xml.Decoder) (xmpValue, error) {
+	var value xmpValue
+	var text strings.Builder
+	var item *strings.Builder
+	var itemLang string
+	var alt bool
+	depth := 0
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return value, fmt.Errorf("failed to parse XMP: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if t.Name.Space == nsRDF {
+				switch t.Name.Local {
+				case "Alt":
+					alt = true
+				case "li":
+					item = &strings.Builder{}
+					itemLang = ""
+					for _, attr := range t.Attr {
+						if attr.Name.Space == nsXMLLang && attr.Name.Local == "lang" {
+							itemLang = attr.Value
+						}
+					}
+				}
+			}
+		case xml.CharData:
+			if item != nil {
+				item.Write(t)
+			} else if depth == 0 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				value.text = text.String()
+				return value, nil
+			}
+			depth--
+			if t.Name.Space == nsRDF && t.Name.Local == "li" && item != nil {
+				s := strings.TrimSpace(item.String())
+				if alt {
+					if value.alt == "" || itemLang == "x-default" {
+						value.alt = s
+					}
+				} else {
+					value.items = append(value.items, s)
+				}
+				item = nil
+			}
+		}
+	}
+}
+
+// isWellKnownXMPNamespace reports namespaces managed by PDF tools rather than users
+func isWellKnownXMPNamespace(uri string) bool {
+	switch uri {
+	case "adobe:ns:meta/",
+		"http://ns.adobe.com/xap/1.0/mm/",
+		"http://ns.adobe.com/xap/1.0/sType/ResourceRef#",
+		"http://ns.adobe.com/xap/1.0/sType/ResourceEvent#",
+		nsPDFAID,
+		nsPDFAExtension,
+		nsPDFASchema,
+		nsPDFAProperty,
+		nsPDFUAID:
+		return true
+	}
+	return false
+}
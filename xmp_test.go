@@ -0,0 +1,207 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXMPMetadataRoundtrip(t *testing.T) {
+	created := time.Date(2025, 1, 29, 12, 30, 45, 0, time.FixedZone("JST", 9*3600))
+	metadata := Metadata{
+		Title:        "請求書 <2025> & Co",
+		Author:       "山田 太郎",
+		Subject:      "Invoice",
+		Keywords:     "invoice, 2025",
+		Creator:      "gopdf example",
+		CreationDate: created,
+		ModDate:      created.Add(time.Hour),
+		Custom:       map[string]string{"Department": "Sales"},
+		XMP: []XMPProperty{
+			{Namespace: "http://example.com/ns/invoice/1.0/", Prefix: "inv", Name: "Number", Value: "INV-001"},
+		},
+	}
+
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	doc.SetMetadata(metadata)
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	packet, err := reader.RawXMP()
+	if err != nil {
+		t.Fatalf("RawXMP() failed: %v", err)
+	}
+	if !bytes.HasPrefix(packet, []byte("<?xpacket begin=")) || !bytes.HasSuffix(packet, []byte(`<?xpacket end="w"?>`)) {
+		t.Error("XMP is not wrapped in an xpacket")
+	}
+	if err := checkWellFormed(packet); err != nil {
+		t.Fatalf("XMP is not well-formed: %v", err)
+	}
+
+	got, err := reader.XMPMetadata()
+	if err != nil {
+		t.Fatalf("XMPMetadata() failed: %v", err)
+	}
+	if got == nil {
+		t.Fatal("XMPMetadata() returned nil")
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Title", got.Title, metadata.Title},
+		{"Author", got.Author, metadata.Author},
+		{"Subject", got.Subject, metadata.Subject},
+		{"Keywords", got.Keywords, metadata.Keywords},
+		{"Creator", got.Creator, metadata.Creator},
+		{"Producer", got.Producer, "gopdf"},
+		{"Custom", got.Custom["Department"], "Sales"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+
+	if !got.CreationDate.Equal(created) || !got.ModDate.Equal(metadata.ModDate) {
+		t.Errorf("CreationDate/ModDate = %v/%v, want %v/%v", got.CreationDate, got.ModDate, created, metadata.ModDate)
+	}
+	if len(got.XMP) != 1 || got.XMP[0] != metadata.XMP[0] {
+		t.Errorf("XMP = %+v, want %+v", got.XMP, metadata.XMP)
+	}
+
+	// Info辞書とXMPの作成日時は一致する
+	info, err := reader.r.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if infoDate := parseInfoDict(info).CreationDate; !infoDate.Equal(got.CreationDate) {
+		t.Errorf("Info CreationDate %v differs from XMP %v", infoDate, got.CreationDate)
+	}
+}
+
+func TestXMPMetadata_NoMetadata(t *testing.T) {
+	doc := New()
+	doc.AddPage(PageSizeA4, Portrait)
+	var buf bytes.Buffer
+	if err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	got, err := reader.XMPMetadata()
+	if err != nil || got != nil {
+		t.Errorf("XMPMetadata() = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestParseXMP(t *testing.T) {
+	// 他のツールが出力する形式（属性による簡易表記、複数言語のAlt、Bag）
+	packet := `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:pdf="http://ns.adobe.com/pdf/1.3/"
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:xmpMM="http://ns.adobe.com/xap/1.0/mm/"
+    pdf:Producer="Other Tool"
+    xmp:CreateDate="2024-03-01T10:00:00Z"
+    xmpMM:DocumentID="uuid:1234"/>
+  <rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+   <dc:title>
+    <rdf:Alt>
+     <rdf:li xml:lang="ja">日本語のタイトル</rdf:li>
+     <rdf:li xml:lang="x-default">Default Title</rdf:li>
+    </rdf:Alt>
+   </dc:title>
+   <dc:creator><rdf:Seq><rdf:li>Alice</rdf:li><rdf:li>Bob</rdf:li></rdf:Seq></dc:creator>
+   <dc:subject><rdf:Bag><rdf:li>pdf</rdf:li><rdf:li>xmp</rdf:li></rdf:Bag></dc:subject>
+  </rdf:Description>
+  <rdf:Description rdf:about="" xmlns:my="http://example.com/my/">
+   <my:Status>Final</my:Status>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="r"?>`
+
+	got, err := parseXMP([]byte(packet))
+	if err != nil {
+		t.Fatalf("parseXMP() failed: %v", err)
+	}
+
+	if got.Title != "Default Title" {
+		t.Errorf("Title = %q, want %q", got.Title, "Default Title")
+	}
+	if got.Author != "Alice, Bob" {
+		t.Errorf("Author = %q, want %q", got.Author, "Alice, Bob")
+	}
+	if got.Keywords != "pdf, xmp" {
+		t.Errorf("Keywords = %q, want %q", got.Keywords, "pdf, xmp")
+	}
+	if got.Producer != "Other Tool" {
+		t.Errorf("Producer = %q, want %q", got.Producer, "Other Tool")
+	}
+	if want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC); !got.CreationDate.Equal(want) {
+		t.Errorf("CreationDate = %v, want %v", got.CreationDate, want)
+	}
+	want := []XMPProperty{{Namespace: "http://example.com/my/", Prefix: "my", Name: "Status", Value: "Final"}}
+	if len(got.XMP) != 1 || got.XMP[0] != want[0] {
+		t.Errorf("XMP = %+v, want %+v", got.XMP, want)
+	}
+}
+
+func TestBuildXMP_InvalidProperty(t *testing.T) {
+	tests := []struct {
+		name string
+		prop XMPProperty
+	}{
+		{name: "missing namespace", prop: XMPProperty{Prefix: "inv", Name: "Number"}},
+		{name: "reserved prefix", prop: XMPProperty{Namespace: "http://example.com/", Prefix: "dc", Name: "Number"}},
+		{name: "invalid prefix", prop: XMPProperty{Namespace: "http://example.com/", Prefix: "1inv", Name: "Number"}},
+		{name: "invalid name", prop: XMPProperty{Namespace: "http://example.com/", Prefix: "inv", Name: "a b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			doc.AddPage(PageSizeA4, Portrait)
+			doc.SetMetadata(Metadata{Title: "x", XMP: []XMPProperty{tt.prop}})
+			if err := doc.WriteTo(io.Discard); err == nil {
+				t.Error("WriteTo() should fail for an invalid XMP property")
+			}
+		})
+	}
+}
+
+// checkWellFormed はXMLとして最後まで読めるかを確認する
+func checkWellFormed(data []byte) error {
+	dec := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}